@@ -27,15 +27,22 @@ import (
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/cluster"
 	packagemgmt "github.com/karmada-io/dashboard/cmd/api/app/routes/mgmt/package"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/notification"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/orphan"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/reports"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/search"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/setting/monitoring"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/users"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/options"
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/aggregated"               // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/apitoken"                 // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/auth"                     // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/backup"                   // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/cloudcredentials"         // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/cluster"                  // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/clusteroverridepolicy"    // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/clusterpropagationpolicy" // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/config"                   // Importing route packages forces route registration
@@ -43,23 +50,30 @@ import (
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/cronjob"                  // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/daemonset"                // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/deployment"               // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/drift"                    // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/federatedresourcequota"   // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/health"                   // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/ingress"                  // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/job"                      // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/jobs"                     // Importing route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/karmadaconfig"
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member"             // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/mgmt"               // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/namespace"          // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/overridepolicy"     // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/overview"           // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/propagationpolicy"  // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/secret"             // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/service"            // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/setting/monitoring" // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/setting/user"       // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/statefulset"        // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/terminal"           // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/unstructured"       // Importing route packages forces route registration
-	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/users"              // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member"              // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/mgmt"                // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/mlplatform"          // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/namespace"           // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/overridepolicy"      // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/overview"            // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/projects"            // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/propagationpolicy"   // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/secret"              // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/service"             // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/setting/declarative" // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/setting/user"        // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/statefulset"         // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/terminal"            // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/unstructured"        // Importing route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/webhooks"            // Importing route packages forces route registration
+	"github.com/karmada-io/dashboard/pkg/audit"
 	"github.com/karmada-io/dashboard/pkg/auth"
 	"github.com/karmada-io/dashboard/pkg/auth/fga"
 	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
@@ -67,6 +81,9 @@ import (
 	"github.com/karmada-io/dashboard/pkg/config"
 	"github.com/karmada-io/dashboard/pkg/environment"
 	"github.com/karmada-io/dashboard/pkg/etcd"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+	"github.com/karmada-io/dashboard/pkg/leader"
+	"github.com/karmada-io/dashboard/pkg/tracing"
 )
 
 // NewAPICommand creates a *cobra.Command object with default parameters
@@ -111,6 +128,17 @@ func NewAPICommand(ctx context.Context) *cobra.Command {
 func run(ctx context.Context, opts *options.Options) error {
 	klog.InfoS("Starting Karmada Dashboard API", "version", environment.Version)
 
+	// The client configs are instrumented as they are built, so tracing starts first
+	shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+		Endpoint:    opts.TracingEndpoint,
+		SampleRatio: opts.TracingSampleRatio,
+		Version:     environment.Version,
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to initialize tracing")
+		return err
+	}
+
 	client.InitKarmadaConfig(
 		client.WithUserAgent(environment.UserAgent()),
 		client.WithKubeconfig(opts.KarmadaKubeConfig),
@@ -146,6 +174,14 @@ func run(ctx context.Context, opts *options.Options) error {
 			return err
 		}
 		klog.InfoS("Keycloak client initialized successfully")
+
+		// Role mappings grant OpenFGA relations from Keycloak realm roles, so OpenFGA
+		// authorizes cluster access alongside Keycloak when they are enabled
+		if opts.RoleMappingSyncInterval > 0 {
+			if err := fga.InitFGAService(opts.OpenFGAAPIURL); err != nil {
+				klog.ErrorS(err, "Failed to initialize OpenFGA service for role mappings")
+			}
+		}
 	} else {
 		klog.InfoS("Using self-generated JWT and OpenFGA for authentication and authorization")
 		
@@ -166,10 +202,32 @@ func run(ctx context.Context, opts *options.Options) error {
 		return err
 	}
 
+	if err := router.ConfigureRateLimits(opts.RateLimits); err != nil {
+		klog.ErrorS(err, "Invalid rate limits")
+		return err
+	}
+
 	ensureAPIServerConnectionOrDie()
-	serve(opts)
+	migrateMonitoringTokens(ctx)
+	// Every replica serves reads from its own cache and claims jobs, the other workers run on the leader only
+	client.ConfigureMemberClientCache(opts.MemberClientCacheTTL)
+	client.StartMemberClientInvalidation(ctx)
+	backup.StartMigrationCache(ctx, opts.MigrationCacheSyncInterval)
+	search.StartIndexer(ctx, opts.SearchIndexInterval)
+	audit.Start(ctx, client.InClusterClient(), config.GetNamespace(), opts.AuditFlushInterval)
+	jobs.StartWorker(ctx, opts.JobWorkerInterval)
+	if err := startLeaderElection(ctx, opts); err != nil {
+		klog.ErrorS(err, "Failed to start leader election")
+		return err
+	}
+	serve(ctx, opts)
 	config.InitDashboardConfig(client.InClusterClient(), ctx.Done())
 	<-ctx.Done()
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		klog.ErrorS(err, "Failed to flush spans")
+	}
+	cancel()
 	os.Exit(0)
 	return nil
 }
@@ -188,6 +246,39 @@ func initPorchAPI(opts *options.Options) error {
 	return nil
 }
 
+// startLeaderElection starts the background workers that must run once across the replicas
+// whenever this replica becomes the leader
+func startLeaderElection(ctx context.Context, opts *options.Options) error {
+	cfg := leader.Config{
+		Enabled:       opts.LeaderElect,
+		Namespace:     config.GetNamespace(),
+		LeaseName:     opts.LeaderElectResourceName,
+		LeaseDuration: opts.LeaderElectLeaseDuration,
+		RenewDeadline: opts.LeaderElectRenewDeadline,
+		RetryPeriod:   opts.LeaderElectRetryPeriod,
+	}
+	return leader.Start(ctx, cfg, client.InClusterClient(), func(ctx context.Context) {
+		backup.StartRetentionWorker(ctx, opts.BackupGCInterval)
+		backup.StartAttestationWorker(ctx, opts.AttestationInterval)
+		backup.StartTrashPurger(ctx, opts.BackupTrashPurgeInterval)
+		backup.StartRetryWorker(ctx, opts.BackupRetryInterval)
+		backup.StartControllerReconciler(ctx, opts.ControllerReconcileInterval, opts.ControllerAutoRemediation)
+		notification.StartWatcher(ctx, opts.NotificationPollInterval, opts.NotificationSuppressionWindow)
+		users.StartRoleMappingSync(ctx, opts.RoleMappingSyncInterval)
+		reports.StartReportScheduler(ctx, opts.ReportSchedulerInterval)
+		orphan.StartCollector(ctx, opts.OrphanGCInterval, opts.OrphanGCDelete)
+		cluster.StartUsageSampler(ctx, opts.UsageSampleInterval)
+	})
+}
+
+// migrateMonitoringTokens rewrites monitoring token secrets that were stored
+// base64 encoded twice by earlier versions.
+func migrateMonitoringTokens(ctx context.Context) {
+	if err := monitoring.MigrateTokenSecrets(ctx, client.InClusterClient()); err != nil {
+		klog.ErrorS(err, "Failed to migrate monitoring token secrets")
+	}
+}
+
 func initEtcdClient(ctx context.Context, opts *options.Options) {
 	// Get admin password for etcd setup
 	adminPassword := os.Getenv("KARMADA_DASHBOARD_ADMIN_PASSWORD")
@@ -322,10 +413,24 @@ func ensureAPIServerConnectionOrDie() {
 	klog.InfoS("Successful initial request to the Karmada apiserver", "version", karmadaVersionInfo.String())
 }
 
-func serve(opts *options.Options) {
+func serve(ctx context.Context, opts *options.Options) {
 	insecureAddress := fmt.Sprintf("%s:%d", opts.InsecureBindAddress, opts.InsecurePort)
 	klog.V(1).InfoS("Listening and serving on", "address", insecureAddress)
 	go func() {
 		klog.Fatal(router.Router().Run(insecureAddress))
 	}()
+
+	if opts.GRPCPort == 0 {
+		return
+	}
+	grpcAddress := fmt.Sprintf("%s:%d", opts.InsecureBindAddress, opts.GRPCPort)
+	go func() {
+		klog.Fatal(router.ServeGRPC(grpcAddress))
+	}()
+	if opts.GRPCGatewayPort != 0 {
+		gatewayAddress := fmt.Sprintf("%s:%d", opts.InsecureBindAddress, opts.GRPCGatewayPort)
+		go func() {
+			klog.Fatal(router.ServeGRPCGateway(ctx, gatewayAddress, grpcAddress))
+		}()
+	}
 }
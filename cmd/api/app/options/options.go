@@ -18,6 +18,7 @@ package options
 
 import (
 	"net"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -28,6 +29,8 @@ type Options struct {
 	Port                          int
 	InsecureBindAddress           net.IP
 	InsecurePort                  int
+	GRPCPort                      int
+	GRPCGatewayPort               int
 	KubeConfig                    string
 	KubeContext                   string
 	SkipKubeApiserverTLSVerify    bool
@@ -42,6 +45,32 @@ type Options struct {
 	OpenFGAAPIURL                 string
 	PorchAPIURL                   string
 	SkipPorchTLSVerify            bool
+	BackupGCInterval              time.Duration
+	AttestationInterval           time.Duration
+	BackupTrashPurgeInterval      time.Duration
+	BackupRetryInterval           time.Duration
+	NotificationPollInterval      time.Duration
+	NotificationSuppressionWindow time.Duration
+	ControllerReconcileInterval   time.Duration
+	ControllerAutoRemediation     bool
+	MigrationCacheSyncInterval    time.Duration
+	MemberClientCacheTTL          time.Duration
+	RoleMappingSyncInterval       time.Duration
+	ReportSchedulerInterval       time.Duration
+	JobWorkerInterval             time.Duration
+	OrphanGCInterval              time.Duration
+	OrphanGCDelete                bool
+	UsageSampleInterval           time.Duration
+	SearchIndexInterval           time.Duration
+	AuditFlushInterval            time.Duration
+	LeaderElect                   bool
+	LeaderElectResourceName       string
+	LeaderElectLeaseDuration      time.Duration
+	LeaderElectRenewDeadline      time.Duration
+	LeaderElectRetryPeriod        time.Duration
+	RateLimits                    []string
+	TracingEndpoint               string
+	TracingSampleRatio            float64
 	// Keycloak authentication options
 	UseKeycloak      bool   // Enable Keycloak authentication
 	KeycloakURL      string // Keycloak server URL
@@ -63,6 +92,8 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&o.Port, "port", 8001, "secure port to listen to for incoming HTTPS requests")
 	fs.IPVar(&o.InsecureBindAddress, "insecure-bind-address", net.IPv4(127, 0, 0, 1), "IP address on which to serve the --insecure-port, set to 0.0.0.0 for all interfaces")
 	fs.IntVar(&o.InsecurePort, "insecure-port", 8000, "port to listen to for incoming HTTP requests")
+	fs.IntVar(&o.GRPCPort, "grpc-port", 8002, "port on --insecure-bind-address to serve the gRPC API on, 0 disables it")
+	fs.IntVar(&o.GRPCGatewayPort, "grpc-gateway-port", 8003, "port on --insecure-bind-address to serve the REST routes of the gRPC API on through the gRPC-gateway, 0 disables the gateway; requires --grpc-port")
 	fs.StringVar(&o.KubeConfig, "kubeconfig", "", "Path to the host cluster kubeconfig file.")
 	fs.StringVar(&o.KubeContext, "context", "", "The name of the kubeconfig context to use.")
 	fs.BoolVar(&o.SkipKubeApiserverTLSVerify, "skip-kube-apiserver-tls-verify", false, "enable if connection with remote Kubernetes API server should skip TLS verify")
@@ -77,6 +108,32 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.OpenFGAAPIURL, "openfga-api-url", "http://openfga.ml-platform-system.svc:8080", "The URL for the OpenFGA API server")
 	fs.StringVar(&o.PorchAPIURL, "porch-api", "", "The URL for the Porch API server")
 	fs.BoolVar(&o.SkipPorchTLSVerify, "skip-porch-tls-verify", false, "Skip TLS certificate verification when connecting to the Porch API")
+	fs.DurationVar(&o.BackupGCInterval, "backup-gc-interval", time.Hour, "Interval between checkpoint garbage collection runs for backups with a retention policy, 0 disables the worker")
+	fs.DurationVar(&o.AttestationInterval, "checkpoint-attestation-interval", 5*time.Minute, "Interval at which the digests of new checkpoints are recorded and signed, 0 disables the worker")
+	fs.DurationVar(&o.BackupTrashPurgeInterval, "backup-trash-purge-interval", time.Hour, "Interval at which deleted backup configurations and recovery records older than the trash retention are purged, 0 disables the purger")
+	fs.DurationVar(&o.BackupRetryInterval, "backup-retry-interval", 30*time.Second, "Interval at which failed backup executions are looked for and re-triggered according to the retry policy of their configuration, 0 disables retries")
+	fs.DurationVar(&o.NotificationPollInterval, "notification-poll-interval", 30*time.Second, "Interval at which clusters and migration resources are checked for notification events, 0 disables notifications")
+	fs.DurationVar(&o.NotificationSuppressionWindow, "notification-suppression-window", 15*time.Minute, "Minimum time between two notifications of the same ArgoCD application, so a flapping application does not flood the channels")
+	fs.DurationVar(&o.ControllerReconcileInterval, "controller-reconcile-interval", 5*time.Minute, "Interval between health checks of the installed migration controllers, 0 disables the reconciler")
+	fs.BoolVar(&o.ControllerAutoRemediation, "controller-auto-remediation", true, "Repair drift of the installed migration controllers, e.g. deleted propagation policies; when false drift is only recorded")
+	fs.DurationVar(&o.MigrationCacheSyncInterval, "migration-cache-sync-interval", 30*time.Second, "Interval at which the watch cache of checkpoint resources picks up added and removed clusters, 0 disables the cache")
+	fs.DurationVar(&o.MemberClientCacheTTL, "member-client-cache-ttl", 5*time.Minute, "Duration that the client of a member cluster is reused before its access path is probed again; clients are also dropped when the cluster changes, 0 disables the cache")
+	fs.DurationVar(&o.RoleMappingSyncInterval, "role-mapping-sync-interval", 0, "Interval at which Keycloak realm roles are mapped to OpenFGA relations; with --use-keycloak a non-zero value also enables OpenFGA authorization, 0 disables the sync")
+	fs.DurationVar(&o.ReportSchedulerInterval, "report-scheduler-interval", time.Minute, "Interval at which scheduled reports are checked and the due ones generated and delivered, 0 disables scheduled reports")
+	fs.DurationVar(&o.JobWorkerInterval, "job-worker-interval", 5*time.Second, "Interval at which pending jobs, such as migrations and controller installs, are picked up by this replica, 0 disables the worker")
+	fs.DurationVar(&o.OrphanGCInterval, "orphan-gc-interval", time.Hour, "Interval at which Karmada resources created by the dashboard for clusters that no longer exist are looked for, 0 disables the collector")
+	fs.BoolVar(&o.OrphanGCDelete, "orphan-gc-delete", false, "Delete the orphaned resources found by the periodic collector; when false they are only logged")
+	fs.DurationVar(&o.UsageSampleInterval, "usage-sample-interval", 5*time.Minute, "Interval at which the usage of the member clusters is sampled from metrics-server for the usage timelines of clusters without Prometheus, 0 disables the sampler")
+	fs.DurationVar(&o.SearchIndexInterval, "search-index-interval", 2*time.Minute, "Interval at which this replica rebuilds the index of the global search from the clusters, backups, recoveries, ArgoCD applications and users, 0 disables the search")
+	fs.DurationVar(&o.AuditFlushInterval, "audit-flush-interval", 10*time.Second, "Interval at which this replica appends the changes made through the API to the audit log behind the cluster and user activity feeds, 0 disables the audit log")
+	fs.BoolVar(&o.LeaderElect, "leader-elect", true, "Elect a leader among the API replicas to run the background workers, e.g. backup retention, notifications and report scheduling; all replicas serve requests. Disable only when running a single replica")
+	fs.StringVar(&o.LeaderElectResourceName, "leader-elect-resource-name", "ml-platform-admin-api", "Name of the Lease in --namespace used for leader election")
+	fs.DurationVar(&o.LeaderElectLeaseDuration, "leader-elect-lease-duration", 15*time.Second, "Duration that replicas wait before taking over the leadership from a leader that stopped renewing it")
+	fs.DurationVar(&o.LeaderElectRenewDeadline, "leader-elect-renew-deadline", 10*time.Second, "Duration that the leader retries renewing its leadership before giving it up, must be less than the lease duration")
+	fs.DurationVar(&o.LeaderElectRetryPeriod, "leader-elect-retry-period", 2*time.Second, "Duration between attempts of the replicas to acquire or renew the leadership")
+	fs.StringSliceVar(&o.RateLimits, "rate-limits", nil, "Per-user request rate limits as '[METHOD] /api/path/prefix=RPS[:BURST]', e.g. 'POST /api/v1/cluster/capi=0.1:2'; 'default=RPS:BURST' limits all other routes and a rate of 0 disables a limit. Fan-out, CAPI and controller install routes are limited by default")
+	fs.StringVar(&o.TracingEndpoint, "tracing-endpoint", "", "URL of an OTLP/HTTP collector, e.g. 'http://tempo.monitoring.svc:4318', to which spans of the API requests and of their Kubernetes API calls per cluster are exported; empty disables tracing")
+	fs.Float64Var(&o.TracingSampleRatio, "tracing-sample-ratio", 0.1, "Share of the API requests that are traced when --tracing-endpoint is set, between 0 and 1; requests of a traced caller are always traced")
 	// Keycloak options
 	fs.BoolVar(&o.UseKeycloak, "use-keycloak", false, "Enable Keycloak for authentication and authorization (replaces self-signed JWT and OpenFGA)")
 	fs.StringVar(&o.KeycloakURL, "keycloak-url", "http://keycloak.ml-platform-system.svc:8080", "Keycloak server URL")
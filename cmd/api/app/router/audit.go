@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// maxAuditedResponseSize bounds the part of a response kept to tell whether the change succeeded
+const maxAuditedResponseSize = 64 << 10
+
+// auditRecorder keeps the beginning of the response body
+type auditRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditRecorder) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditRecorder) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditRecorder) keep(data []byte) {
+	if room := maxAuditedResponseSize - w.body.Len(); room > 0 {
+		if len(data) > room {
+			data = data[:room]
+		}
+		w.body.Write(data)
+	}
+}
+
+// succeeded reports whether a response reports success, failures are answered with 200 and an error code too
+func (w *auditRecorder) succeeded() bool {
+	if status := w.Status(); status < 200 || status >= 300 {
+		return false
+	}
+	var response common.BaseResponse
+	if err := json.Unmarshal(w.body.Bytes(), &response); err == nil && response.Code != 0 && response.Code != 200 {
+		return false
+	}
+	return true
+}
+
+// AuditMiddleware records the requests that change something in the audit log, with the user who made them,
+// their outcome and the clusters and users they affected. The clusters and the user of the path are recorded,
+// handlers add the ones of the request body with audit.SetClusters and audit.SetSubject.
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Requests that matched no route changed nothing
+		route := c.FullPath()
+		if !audit.Enabled() || route == "" || !audit.Recorded(c.Request.Method, route) {
+			c.Next()
+			return
+		}
+		recorder := &auditRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		entry := audit.Entry{
+			Time:         time.Now().UTC().Format(time.RFC3339),
+			User:         utilauth.GetAuthenticatedUser(c),
+			Impersonator: Impersonator(c),
+			Action:       audit.RequestAction(c),
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.Path,
+			Success:      recorder.succeeded(),
+			Clusters:     auditedClusters(c, route),
+			Subject:      audit.RequestSubject(c),
+		}
+		if entry.Action == "" {
+			entry.Action = audit.Classify(c.Request.Method, route)
+		}
+		if entry.Subject == "" && strings.HasPrefix(route, "/api/v1/users/:id") {
+			entry.Subject = c.Param("id")
+		}
+		audit.Record(entry)
+	}
+}
+
+// auditedClusters returns the clusters of the path of a request and the ones its handler set, without duplicates
+func auditedClusters(c *gin.Context, route string) []string {
+	var clusters []string
+	if name := c.Param("clustername"); name != "" {
+		clusters = append(clusters, name)
+	}
+	if strings.HasPrefix(route, "/api/v1/cluster/:name") || strings.HasPrefix(route, "/api/v1/backup/settings/clusters/:name") {
+		clusters = append(clusters, c.Param("name"))
+	}
+	clusters = append(clusters, audit.RequestClusters(c)...)
+
+	seen := map[string]bool{}
+	unique := clusters[:0]
+	for _, name := range clusters {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
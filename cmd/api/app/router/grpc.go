@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	apiv1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/auth/apitoken"
+	"github.com/karmada-io/dashboard/pkg/client"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// GatewayHandler registers the REST routes of a gRPC service on the gateway, forwarding them to the endpoint
+type GatewayHandler func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+
+var (
+	grpcServer      *grpc.Server
+	gatewayHandlers []GatewayHandler
+)
+
+func init() {
+	// Calls are authenticated with the bearer tokens of the REST API, rate limited and their changes audited
+	// the same way
+	grpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(grpcAuthInterceptor, grpcRateLimitInterceptor, grpcAuditInterceptor))
+}
+
+// GRPC returns the gRPC server the services of the API are registered on.
+func GRPC() *grpc.Server {
+	return grpcServer
+}
+
+// RegisterGateway adds the REST routes of a gRPC service to the gateway.
+func RegisterGateway(handler GatewayHandler) {
+	gatewayHandlers = append(gatewayHandlers, handler)
+}
+
+// ServeGRPC serves the gRPC services on the address until the server stops.
+func ServeGRPC(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	klog.InfoS("Serving gRPC API", "address", address)
+	return grpcServer.Serve(listener)
+}
+
+// ServeGRPCGateway serves the REST routes of the gRPC services on the address, translating the requests to
+// calls of the gRPC server at grpcAddress so they go through the same authentication.
+func ServeGRPCGateway(ctx context.Context, address, grpcAddress string) error {
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	for _, register := range gatewayHandlers {
+		if err := register(ctx, mux, grpcAddress, opts); err != nil {
+			return err
+		}
+	}
+	klog.InfoS("Serving gRPC gateway", "address", address, "grpcAddress", grpcAddress)
+	server := &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 30 * time.Second}
+	return server.ListenAndServe()
+}
+
+// grpcPrincipal is the caller of a gRPC call
+type grpcPrincipal struct {
+	name string
+	// token is the bearer token of a user, empty for API tokens
+	token string
+}
+
+type grpcPrincipalKey struct{}
+
+// GRPCUser returns the name of the caller of a gRPC call.
+func GRPCUser(ctx context.Context) string {
+	principal, _ := ctx.Value(grpcPrincipalKey{}).(grpcPrincipal)
+	return principal.name
+}
+
+// GRPCBearerToken returns the bearer token of the user calling a gRPC method, empty for API tokens.
+func GRPCBearerToken(ctx context.Context) string {
+	principal, _ := ctx.Value(grpcPrincipalKey{}).(grpcPrincipal)
+	return principal.token
+}
+
+// GRPCGinContext returns a gin context carrying the caller of a gRPC call like the REST requests, for the functions
+// shared with the REST API that look up the user of a gin context, e.g. to check their access to member clusters.
+func GRPCGinContext(ctx context.Context) *gin.Context {
+	c := &gin.Context{Request: (&http.Request{Header: http.Header{}}).WithContext(ctx)}
+	name := GRPCUser(ctx)
+	c.Set("user", &apiv1.User{Name: name, Authenticated: true})
+	c.Set("claims", map[string]interface{}{"username": name})
+	return c
+}
+
+// grpcRouteParam matches the path parameters of the google.api.http option of a method
+var grpcRouteParam = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// grpcRoute returns the method and path of the REST route a gRPC call maps to with the google.api.http option
+// of its method, with the path parameters filled from the request, and the route as gin names it
+func grpcRoute(fullMethod string, req interface{}) (method, path, route string, err error) {
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return "", "", "", err
+	}
+	methodDesc, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return "", "", "", fmt.Errorf("%s is not a method", name)
+	}
+	rule, _ := proto.GetExtension(methodDesc.Options(), annotations.E_Http).(*annotations.HttpRule)
+	var pattern string
+	switch {
+	case rule.GetGet() != "":
+		method, pattern = http.MethodGet, rule.GetGet()
+	case rule.GetPost() != "":
+		method, pattern = http.MethodPost, rule.GetPost()
+	case rule.GetPut() != "":
+		method, pattern = http.MethodPut, rule.GetPut()
+	case rule.GetPatch() != "":
+		method, pattern = http.MethodPatch, rule.GetPatch()
+	case rule.GetDelete() != "":
+		method, pattern = http.MethodDelete, rule.GetDelete()
+	default:
+		return "", "", "", fmt.Errorf("method %s has no REST route", name)
+	}
+
+	message, _ := req.(proto.Message)
+	path = grpcRouteParam.ReplaceAllStringFunc(pattern, func(param string) string {
+		if message == nil {
+			return ""
+		}
+		fields := message.ProtoReflect().Descriptor().Fields()
+		field := fields.ByName(protoreflect.Name(grpcRouteParam.FindStringSubmatch(param)[1]))
+		if field == nil {
+			return ""
+		}
+		return message.ProtoReflect().Get(field).String()
+	})
+	return method, path, grpcRouteParam.ReplaceAllString(pattern, ":$1"), nil
+}
+
+// grpcBearerToken returns the bearer token of the authorization metadata of a call
+func grpcBearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token := strings.TrimPrefix(value, "Bearer "); token != value {
+			return token
+		}
+	}
+	return ""
+}
+
+// grpcAuthInterceptor authenticates gRPC calls with the bearer token of their authorization metadata. API tokens
+// are authorized for the REST route the method maps to, like the requests of the REST API.
+func grpcAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	bearer := grpcBearerToken(ctx)
+	if bearer == "" {
+		return nil, status.Error(codes.Unauthenticated, "a bearer token is required")
+	}
+
+	var principal grpcPrincipal
+	if apitoken.IsAPIToken(bearer) {
+		token, err := apitoken.Validate(ctx, bearer)
+		if err != nil {
+			klog.V(4).InfoS("Rejected API token", "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		method, path, route, err := grpcRoute(info.FullMethod, req)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		var cluster string
+		if named, ok := req.(interface{ GetName() string }); ok && strings.HasPrefix(route, "/api/v1/cluster/:name") {
+			cluster = named.GetName()
+		}
+		if err := token.Authorize(method, path, cluster); err != nil {
+			klog.InfoS("API token not authorized", "token", token.ID, "method", info.FullMethod, "error", err)
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		principal = grpcPrincipal{name: token.Principal}
+	} else {
+		name, _ := utilauth.AuthenticateToken(ctx, bearer)
+		if name == "" {
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+		principal = grpcPrincipal{name: name, token: bearer}
+	}
+
+	client.SetCurrentUser(principal.name)
+	return handler(context.WithValue(ctx, grpcPrincipalKey{}, principal), req)
+}
+
+// grpcAuditInterceptor records the gRPC calls that change something in the audit log, like the requests of the
+// REST routes their methods map to
+func grpcAuditInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method, path, route, err := grpcRoute(info.FullMethod, req)
+	if !audit.Enabled() || err != nil || !audit.Recorded(method, route) {
+		return handler(ctx, req)
+	}
+
+	resp, err := handler(ctx, req)
+	entry := audit.Entry{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		User:    GRPCUser(ctx),
+		Action:  audit.Classify(method, route),
+		Method:  method,
+		Path:    path,
+		Success: err == nil,
+	}
+	if r, ok := req.(interface{ GetCluster() string }); ok && r.GetCluster() != "" {
+		entry.Clusters = []string{r.GetCluster()}
+	}
+	if r, ok := req.(interface{ GetTargetCluster() string }); ok && r.GetTargetCluster() != "" {
+		entry.Clusters = []string{r.GetTargetCluster()}
+	}
+	if r, ok := req.(interface{ GetId() string }); ok && strings.HasPrefix(route, "/api/v1/users/:id") {
+		entry.Subject = r.GetId()
+	}
+	audit.Record(entry)
+	return resp, err
+}
+
+// GRPCError returns the gRPC status of an error, with the code of the status of a Kubernetes API error.
+func GRPCError(err error) error {
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		return GRPCErrorWithStatus(err, int(apiStatus.Status().Code))
+	}
+	return GRPCErrorWithStatus(err, http.StatusInternalServerError)
+}
+
+// GRPCErrorWithStatus returns the gRPC status of an error the REST API answers with the HTTP status.
+func GRPCErrorWithStatus(err error, httpStatus int) error {
+	code := codes.Internal
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	platformv1 "github.com/karmada-io/dashboard/pkg/apis/platform/v1"
+)
+
+func TestGRPCRoute(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		req        interface{}
+		method     string
+		path       string
+		route      string
+	}{
+		{
+			fullMethod: "/platform.v1.ClusterService/ListClusters",
+			req:        &platformv1.ListClustersRequest{},
+			method:     "GET",
+			path:       "/api/v1/cluster",
+			route:      "/api/v1/cluster",
+		},
+		{
+			fullMethod: "/platform.v1.ClusterService/GetCluster",
+			req:        &platformv1.GetClusterRequest{Name: "member1"},
+			method:     "GET",
+			path:       "/api/v1/cluster/member1",
+			route:      "/api/v1/cluster/:name",
+		},
+		{
+			fullMethod: "/platform.v1.BackupService/ExecuteBackup",
+			req:        &platformv1.ExecuteBackupRequest{Id: "nightly"},
+			method:     "POST",
+			path:       "/api/v1/backup/nightly/execute",
+			route:      "/api/v1/backup/:id/execute",
+		},
+		{
+			fullMethod: "/platform.v1.RecoveryService/CancelRecovery",
+			req:        &platformv1.CancelRecoveryRequest{Id: "recovery-1"},
+			method:     "POST",
+			path:       "/api/v1/backup/recovery/recovery-1/cancel",
+			route:      "/api/v1/backup/recovery/:id/cancel",
+		},
+		{
+			fullMethod: "/platform.v1.UserService/UpdateUser",
+			req:        &platformv1.UpdateUserRequest{Id: "u1"},
+			method:     "PUT",
+			path:       "/api/v1/users/u1",
+			route:      "/api/v1/users/:id",
+		},
+		{
+			fullMethod: "/platform.v1.UserService/DeleteUser",
+			req:        &platformv1.DeleteUserRequest{Id: "u1"},
+			method:     "DELETE",
+			path:       "/api/v1/users/u1",
+			route:      "/api/v1/users/:id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fullMethod, func(t *testing.T) {
+			method, path, route, err := grpcRoute(tt.fullMethod, tt.req)
+			if err != nil {
+				t.Fatalf("grpcRoute() unexpected error: %v", err)
+			}
+			if method != tt.method || path != tt.path || route != tt.route {
+				t.Errorf("grpcRoute() = %s %s %s, want %s %s %s", method, path, route, tt.method, tt.path, tt.route)
+			}
+		})
+	}
+
+	if _, _, _, err := grpcRoute("/platform.v1.ClusterService/DeleteCluster", nil); err == nil {
+		t.Error("grpcRoute() of an unknown method, want an error")
+	}
+}
+
+func TestGRPCAuthInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/platform.v1.ClusterService/ListClusters"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("handler called for an unauthenticated call")
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+	}{
+		{name: "no metadata", md: nil},
+		{name: "no bearer", md: metadata.Pairs("authorization", "Basic dXNlcjpwYXNz")},
+		{name: "invalid token", md: metadata.Pairs("authorization", "Bearer not-a-token")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			_, err := grpcAuthInterceptor(ctx, &platformv1.ListClustersRequest{}, info, handler)
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("grpcAuthInterceptor() error = %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestGRPCError(t *testing.T) {
+	tests := []struct {
+		status int
+		code   codes.Code
+	}{
+		{status: 400, code: codes.InvalidArgument},
+		{status: 403, code: codes.PermissionDenied},
+		{status: 404, code: codes.NotFound},
+		{status: 409, code: codes.FailedPrecondition},
+		{status: 500, code: codes.Internal},
+	}
+	for _, tt := range tests {
+		if code := status.Code(GRPCErrorWithStatus(context.Canceled, tt.status)); code != tt.code {
+			t.Errorf("GRPCErrorWithStatus(%d) code = %s, want %s", tt.status, code, tt.code)
+		}
+	}
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "statefulmigrations"}, "nightly")
+	if code := status.Code(GRPCError(notFound)); code != codes.NotFound {
+		t.Errorf("GRPCError() of a not found API error code = %s, want NotFound", code)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	apiv1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// ImpersonateUserHeader is the header a dashboard admin sets to issue a request as another user
+	ImpersonateUserHeader = "Impersonate-User"
+	// impersonatorKey is the context key of the admin who issued an impersonated request
+	impersonatorKey = "impersonator"
+)
+
+// impersonatorIsAdmin reports whether the user asking to impersonate is a dashboard admin
+var impersonatorIsAdmin = isDashboardAdmin
+
+// ImpersonationMiddleware lets dashboard admins issue requests as another user with the Impersonate-User
+// header, so permission issues a user reports can be reproduced without their credentials. The impersonated
+// user replaces the admin for the rest of the request: cluster access and admin checks apply to them.
+// Every impersonated request is logged with the admin who issued it. The header is ignored when the
+// impersonation feature is disabled.
+func ImpersonationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		target := strings.TrimSpace(c.GetHeader(ImpersonateUserHeader))
+		if target == "" {
+			c.Next()
+			return
+		}
+		// Deployments without impersonation handle the request as the authenticated user
+		if !config.FeatureEnabled(config.FeatureImpersonation) {
+			klog.V(4).InfoS("Ignoring impersonation header, impersonation is disabled", "impersonate", target)
+			c.Next()
+			return
+		}
+		deny := func(code int, message string) {
+			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
+				Code: code,
+				Msg:  message,
+			})
+		}
+
+		username := utilauth.GetAuthenticatedUser(c)
+		if username == "" {
+			deny(401, "Authentication required to impersonate users")
+			return
+		}
+		if _, ok := c.Get("apiToken"); ok {
+			klog.InfoS("API token is not allowed to impersonate users", "username", username, "impersonate", target)
+			deny(403, "API tokens cannot impersonate users")
+			return
+		}
+		isAdmin, err := impersonatorIsAdmin(c, username)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check if user is admin", "username", username)
+			deny(500, "Failed to verify administrator permissions")
+			return
+		}
+		if !isAdmin {
+			klog.InfoS("Impersonation denied to a user who is not admin", "username", username, "impersonate", target)
+			deny(403, "Administrator permissions required to impersonate users")
+			return
+		}
+
+		c.Set("user", &apiv1.User{Name: target, Authenticated: true})
+		c.Set("claims", map[string]interface{}{"username": target})
+		// The roles in the context are the roles of the admin
+		c.Set("user_roles", []string{})
+		c.Set(impersonatorKey, username)
+		client.SetCurrentUser(target)
+		c.Next()
+
+		klog.InfoS("Impersonated request", "impersonator", username, "user", target,
+			"method", c.Request.Method, "path", c.Request.URL.Path, "status", c.Writer.Status())
+	}
+}
+
+// Impersonator returns the admin who issued an impersonated request, or "" for other requests
+func Impersonator(c *gin.Context) string {
+	impersonator, _ := c.Get(impersonatorKey)
+	name, _ := impersonator.(string)
+	return name
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	apiv1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// setImpersonationFeature loads a dashboard config that switches the impersonation feature on or off
+func setImpersonationFeature(t *testing.T, enabled bool) {
+	path := filepath.Join(t.TempDir(), "dashboard.yaml")
+	content := "runtime:\n  features:\n    " + config.FeatureImpersonation + ": false\n"
+	if enabled {
+		content = "runtime:\n  features:\n    " + config.FeatureImpersonation + ": true\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.InitDashboardConfigFromMountFile(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newImpersonationEngine returns an engine whose requests are authenticated as the user in the X-Test-User
+// header, alice being the only admin. The handler replies with the user it sees.
+func newImpersonationEngine(t *testing.T) *gin.Engine {
+	original := impersonatorIsAdmin
+	impersonatorIsAdmin = func(_ *gin.Context, username string) (bool, error) {
+		return username == "alice", nil
+	}
+	t.Cleanup(func() { impersonatorIsAdmin = original })
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("user", &apiv1.User{Name: c.GetHeader("X-Test-User"), Authenticated: true})
+	}, ImpersonationMiddleware(), AuditMiddleware())
+	engine.POST("/api/v1/test/:name", func(c *gin.Context) {
+		common.Success(c, gin.H{"user": c.MustGet("user").(*apiv1.User).Name, "impersonator": Impersonator(c)})
+	})
+	return engine
+}
+
+type impersonationResponse struct {
+	Code int `json:"code"`
+	Data struct {
+		User         string `json:"user"`
+		Impersonator string `json:"impersonator"`
+	} `json:"data"`
+}
+
+func impersonatedRequest(t *testing.T, engine *gin.Engine, path, user, impersonate string) impersonationResponse {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("X-Test-User", user)
+	if impersonate != "" {
+		req.Header.Set(ImpersonateUserHeader, impersonate)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	var resp impersonationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return resp
+}
+
+// auditEntry returns the audit entry of the request to path, nil when none was recorded
+func auditEntry(t *testing.T, path string) *audit.Entry {
+	entries, err := audit.Entries(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range entries {
+		if entries[i].Path == path {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	audit.Start(ctx, kubefake.NewSimpleClientset(), "karmada-system", time.Hour)
+	setImpersonationFeature(t, true)
+	engine := newImpersonationEngine(t)
+
+	t.Run("admin impersonates a user", func(t *testing.T) {
+		path := "/api/v1/test/admin"
+		resp := impersonatedRequest(t, engine, path, "alice", "bob")
+		if resp.Code != http.StatusOK || resp.Data.User != "bob" || resp.Data.Impersonator != "alice" {
+			t.Fatalf("response = %+v, want the request handled as bob impersonated by alice", resp)
+		}
+		// The audit log keeps the admin who actually issued the request
+		entry := auditEntry(t, path)
+		if entry == nil || entry.User != "bob" || entry.Impersonator != "alice" {
+			t.Errorf("audit entry = %+v, want user bob and impersonator alice", entry)
+		}
+	})
+
+	t.Run("non-admin is rejected", func(t *testing.T) {
+		resp := impersonatedRequest(t, engine, "/api/v1/test/non-admin", "bob", "alice")
+		if resp.Code != http.StatusForbidden || resp.Data.User != "" {
+			t.Fatalf("response = %+v, want 403 without reaching the handler", resp)
+		}
+	})
+
+	t.Run("request without header", func(t *testing.T) {
+		resp := impersonatedRequest(t, engine, "/api/v1/test/plain", "bob", "")
+		if resp.Code != http.StatusOK || resp.Data.User != "bob" || resp.Data.Impersonator != "" {
+			t.Errorf("response = %+v, want the request handled as bob", resp)
+		}
+	})
+
+	t.Run("header is ignored when the feature is disabled", func(t *testing.T) {
+		setImpersonationFeature(t, false)
+		t.Cleanup(func() { setImpersonationFeature(t, true) })
+		path := "/api/v1/test/disabled"
+		resp := impersonatedRequest(t, engine, path, "alice", "bob")
+		if resp.Code != http.StatusOK || resp.Data.User != "alice" || resp.Data.Impersonator != "" {
+			t.Fatalf("response = %+v, want the request handled as alice", resp)
+		}
+		if entry := auditEntry(t, path); entry == nil || entry.User != "alice" || entry.Impersonator != "" {
+			t.Errorf("audit entry = %+v, want user alice without impersonator", entry)
+		}
+	})
+}
@@ -18,15 +18,20 @@ package router
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	apiv1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/apitoken"
 	"github.com/karmada-io/dashboard/pkg/auth/fga"
 	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
 	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -62,54 +67,25 @@ func EnsureMgmtAdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		var isAdmin bool
-		var err error
-
-		// Check if Keycloak is available
-		if kc := keycloak.GetClient(); kc != nil {
-			klog.V(4).InfoS("Using Keycloak for admin authorization", "username", username)
-			
-			// Get user roles from context (set by GetAuthenticatedUser)
-			rolesInterface, exists := c.Get("user_roles")
-			if exists {
-				if roles, ok := rolesInterface.([]string); ok {
-					// Check for admin or dashboard-admin role
-					for _, role := range roles {
-						if strings.EqualFold(role, "admin") || strings.EqualFold(role, "dashboard-admin") {
-							isAdmin = true
-							break
-						}
-					}
-				}
-			}
-			
-			if !isAdmin {
-				// Fallback: check token directly
-				token := client.GetBearerToken(c.Request)
-				if token != "" {
-					isAdmin, err = kc.HasRole(context.TODO(), token, "admin")
-					if err == nil && !isAdmin {
-						isAdmin, err = kc.HasRole(context.TODO(), token, "dashboard-admin")
-					}
-				}
-			}
-		} else {
-			// Use OpenFGA if Keycloak is not available
-			klog.V(4).InfoS("Using OpenFGA for admin authorization", "username", username)
-			
-			if fga.FGAService == nil || fga.FGAService.GetClient() == nil {
-				klog.ErrorS(nil, "Authorization service not available")
-				c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
-					Code: 500,
-					Msg:  "Authorization service unavailable",
-				})
-				return
-			}
-
-			// Check if user is dashboard admin in OpenFGA
-			isAdmin, err = fga.FGAService.GetClient().Check(context.TODO(), username, "admin", "dashboard", "dashboard")
+		// API tokens are scoped to cluster relations and never act as admins
+		if _, ok := c.Get("apiToken"); ok {
+			klog.InfoS("API token is not allowed administrator access", "username", username)
+			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
+				Code: 403,
+				Msg:  "Administrator permissions required for management cluster access",
+			})
+			return
 		}
 
+		isAdmin, err := isDashboardAdmin(c, username)
+		if errors.Is(err, errAuthorizationUnavailable) {
+			klog.ErrorS(nil, "Authorization service not available")
+			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
+				Code: 500,
+				Msg:  "Authorization service unavailable",
+			})
+			return
+		}
 		if err != nil {
 			klog.ErrorS(err, "Failed to check if user is admin", "username", username)
 			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
@@ -133,3 +109,120 @@ func EnsureMgmtAdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// errAuthorizationUnavailable is returned by isDashboardAdmin when neither Keycloak nor OpenFGA is configured
+var errAuthorizationUnavailable = errors.New("authorization service unavailable")
+
+// isDashboardAdmin checks whether the user of the request is a dashboard admin, with the Keycloak roles
+// of the request when Keycloak is configured and with OpenFGA otherwise
+func isDashboardAdmin(c *gin.Context, username string) (bool, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		klog.V(4).InfoS("Using OpenFGA for admin authorization", "username", username)
+		if fga.FGAService == nil || fga.FGAService.GetClient() == nil {
+			return false, errAuthorizationUnavailable
+		}
+		return fga.FGAService.GetClient().Check(context.TODO(), username, "admin", "dashboard", "dashboard")
+	}
+
+	klog.V(4).InfoS("Using Keycloak for admin authorization", "username", username)
+	// Get user roles from context (set by GetAuthenticatedUser)
+	if roles, ok := c.Get("user_roles"); ok {
+		if roles, ok := roles.([]string); ok {
+			for _, role := range roles {
+				if strings.EqualFold(role, "admin") || strings.EqualFold(role, "dashboard-admin") {
+					return true, nil
+				}
+			}
+		}
+	}
+	// The bearer token belongs to the impersonator, so it says nothing about the roles of an impersonated user
+	if Impersonator(c) != "" {
+		return false, nil
+	}
+	// Fallback: check token directly
+	token := client.GetBearerToken(c.Request)
+	if token == "" {
+		return false, nil
+	}
+	isAdmin, err := kc.HasRole(context.TODO(), token, "admin")
+	if err == nil && !isAdmin {
+		isAdmin, err = kc.HasRole(context.TODO(), token, "dashboard-admin")
+	}
+	return isAdmin, err
+}
+
+// IsDashboardAdmin reports whether the user of the request is a dashboard admin. API tokens never are, and
+// users whose admin permissions cannot be verified are not.
+func IsDashboardAdmin(c *gin.Context) bool {
+	username := utilauth.GetAuthenticatedUser(c)
+	if username == "" {
+		return false
+	}
+	if _, ok := c.Get("apiToken"); ok {
+		return false
+	}
+	isAdmin, err := isDashboardAdmin(c, username)
+	if err != nil {
+		klog.V(4).InfoS("Failed to check if user is admin", "username", username, "error", err)
+		return false
+	}
+	return isAdmin
+}
+
+// APITokenMiddleware authenticates requests that carry an API token instead of a user token.
+// The token's principal is set as the current user, so cluster access is checked against the
+// relations bound to the token. Other requests pass through unchanged.
+func APITokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearer := client.GetBearerToken(c.Request)
+		if !apitoken.IsAPIToken(bearer) {
+			c.Next()
+			return
+		}
+
+		token, err := apitoken.Validate(c.Request.Context(), bearer)
+		if err != nil {
+			klog.V(4).InfoS("Rejected API token", "path", c.Request.URL.Path, "error", err)
+			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
+				Code: 401,
+				Msg:  err.Error(),
+			})
+			return
+		}
+
+		cluster := c.Param("clustername")
+		if cluster == "" {
+			cluster = c.Param("cluster")
+		}
+		if err := token.Authorize(c.Request.Method, c.Request.URL.Path, cluster); err != nil {
+			klog.InfoS("API token not authorized", "token", token.ID, "path", c.Request.URL.Path, "error", err)
+			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
+				Code: 403,
+				Msg:  err.Error(),
+			})
+			return
+		}
+
+		c.Set("user", &apiv1.User{Name: token.Principal, Authenticated: true, Role: "apitoken"})
+		c.Set("claims", map[string]interface{}{"username": token.Principal})
+		c.Set("apiToken", token)
+		client.SetCurrentUser(token.Principal)
+		c.Next()
+	}
+}
+
+// RequireFeature rejects the requests of routes that belong to a feature disabled in the runtime config.
+// The feature is checked on each request, so switching it takes effect without a restart.
+func RequireFeature(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.FeatureEnabled(name) {
+			c.AbortWithStatusJSON(http.StatusOK, common.BaseResponse{
+				Code: 404,
+				Msg:  fmt.Sprintf("Feature %s is disabled on this deployment", name),
+			})
+			return
+		}
+		c.Next()
+	}
+}
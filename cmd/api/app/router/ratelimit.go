@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// limiterIdleTimeout is how long the limiter of a user is kept after its last request
+const limiterIdleTimeout = 10 * time.Minute
+
+// DefaultRateLimits protect the endpoints that fan out to all member clusters or install
+// components. They can be overridden with the same syntax as ConfigureRateLimits.
+var DefaultRateLimits = []string{
+	"GET /api/v1/aggregated/=5:20",
+	"POST /api/v1/cluster/capi=0.1:2",
+	"POST /api/v1/backup/settings/clusters/=0.2:3",
+}
+
+// rateLimitRule limits the requests of each user to the routes matching a method and path prefix
+type rateLimitRule struct {
+	method string // "*" matches any method
+	prefix string
+	limit  rate.Limit
+	burst  int
+}
+
+func (r *rateLimitRule) key() string {
+	return r.method + " " + r.prefix
+}
+
+type userLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	rules     []*rateLimitRule // longest prefix first
+	limiters  map[string]*userLimiter
+	lastSweep time.Time
+}
+
+var limiter = &rateLimiter{limiters: map[string]*userLimiter{}}
+
+// parseRateLimitRule parses "METHOD /path/prefix=rps:burst". The method is optional, "default" stands
+// for all routes without a more specific rule, and a rate of 0 disables limiting for the matching routes.
+func parseRateLimitRule(spec string) (*rateLimitRule, error) {
+	route, value, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	if !ok {
+		return nil, fmt.Errorf("invalid rate limit %q, expected [METHOD] PATH=RPS[:BURST]", spec)
+	}
+	rule := &rateLimitRule{method: "*"}
+	fields := strings.Fields(route)
+	switch {
+	case len(fields) == 1 && fields[0] == "default":
+		rule.prefix = "/"
+	case len(fields) == 1:
+		rule.prefix = fields[0]
+	case len(fields) == 2:
+		rule.method, rule.prefix = strings.ToUpper(fields[0]), fields[1]
+	default:
+		return nil, fmt.Errorf("invalid rate limit route %q", route)
+	}
+	if !strings.HasPrefix(rule.prefix, "/") {
+		return nil, fmt.Errorf("rate limit path %q must start with /", rule.prefix)
+	}
+
+	rps, burst, _ := strings.Cut(value, ":")
+	perSecond, err := strconv.ParseFloat(rps, 64)
+	if err != nil || perSecond < 0 {
+		return nil, fmt.Errorf("invalid rate %q in rate limit %q", rps, spec)
+	}
+	rule.limit = rate.Limit(perSecond)
+	rule.burst = int(math.Ceil(perSecond))
+	if burst != "" {
+		if rule.burst, err = strconv.Atoi(burst); err != nil || rule.burst < 1 {
+			return nil, fmt.Errorf("invalid burst %q in rate limit %q", burst, spec)
+		}
+	}
+	if rule.burst < 1 {
+		rule.burst = 1
+	}
+	return rule, nil
+}
+
+// ConfigureRateLimits replaces the rate limit rules with DefaultRateLimits and the given overrides.
+// A rule for the same method and path as a default replaces the default.
+func ConfigureRateLimits(specs []string) error {
+	byKey := map[string]*rateLimitRule{}
+	for _, spec := range append(append([]string{}, DefaultRateLimits...), specs...) {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		rule, err := parseRateLimitRule(spec)
+		if err != nil {
+			return err
+		}
+		byKey[rule.key()] = rule
+	}
+
+	rules := make([]*rateLimitRule, 0, len(byKey))
+	for _, rule := range byKey {
+		rules = append(rules, rule)
+	}
+	// The most specific rule wins: longer prefixes first, then rules with a method
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].prefix) != len(rules[j].prefix) {
+			return len(rules[i].prefix) > len(rules[j].prefix)
+		}
+		return rules[i].method != "*" && rules[j].method == "*"
+	})
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.rules = rules
+	limiter.limiters = map[string]*userLimiter{}
+	for _, rule := range rules {
+		klog.V(4).InfoS("Configured rate limit", "method", rule.method, "prefix", rule.prefix, "rps", float64(rule.limit), "burst", rule.burst)
+	}
+	return nil
+}
+
+func (l *rateLimiter) match(method, path string) *rateLimitRule {
+	for _, rule := range l.rules {
+		if (rule.method == "*" || rule.method == method) && strings.HasPrefix(path, rule.prefix) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// allow reports whether a user may make a request under the rule, and otherwise when to retry
+func (l *rateLimiter) allow(rule *rateLimitRule, user string) (bool, time.Duration) {
+	now := time.Now()
+	key := rule.key() + "|" + user
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > limiterIdleTimeout {
+		for k, entry := range l.limiters {
+			if now.Sub(entry.lastSeen) > limiterIdleTimeout {
+				delete(l.limiters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	entry, ok := l.limiters[key]
+	if !ok {
+		entry = &userLimiter{limiter: rate.NewLimiter(rule.limit, rule.burst)}
+		l.limiters[key] = entry
+	}
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// RateLimitMiddleware limits the request rate of each user per rule configured with ConfigureRateLimits.
+// Anonymous requests are limited by client IP.
+func RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter.mu.Lock()
+		rule := limiter.match(c.Request.Method, c.Request.URL.Path)
+		limiter.mu.Unlock()
+		if rule == nil || rule.limit == 0 {
+			c.Next()
+			return
+		}
+
+		user := utilauth.GetAuthenticatedUser(c)
+		if user == "" {
+			user = "ip:" + c.ClientIP()
+		}
+		if ok, retryAfter := limiter.allow(rule, user); !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			klog.V(4).InfoS("Rate limited request", "user", user, "method", c.Request.Method, "path", c.Request.URL.Path, "retryAfter", seconds)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, common.BaseResponse{
+				Code: http.StatusTooManyRequests,
+				Msg:  fmt.Sprintf("Too many requests, retry in %d seconds", seconds),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// grpcRateLimitInterceptor limits the gRPC calls of each user with the rule of the REST route their method maps to.
+// Calls and REST requests of a user share the same limiters, so the gRPC API does not add to the allowed rate.
+func grpcRateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method, path, _, err := grpcRoute(info.FullMethod, req)
+	if err != nil {
+		return handler(ctx, req)
+	}
+	limiter.mu.Lock()
+	rule := limiter.match(method, path)
+	limiter.mu.Unlock()
+	if rule == nil || rule.limit == 0 {
+		return handler(ctx, req)
+	}
+
+	user := GRPCUser(ctx)
+	if ok, retryAfter := limiter.allow(rule, user); !ok {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		klog.V(4).InfoS("Rate limited gRPC call", "user", user, "method", info.FullMethod, "retryAfter", seconds)
+		// The gateway forwards the header as Grpc-Metadata-Retry-After
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+		return nil, status.Errorf(codes.ResourceExhausted, "Too many requests, retry in %d seconds", seconds)
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apiv1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	platformv1 "github.com/karmada-io/dashboard/pkg/apis/platform/v1"
+)
+
+func TestParseRateLimitRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    rateLimitRule
+		wantErr bool
+	}{
+		{spec: "GET /api/v1/aggregated/=5:20", want: rateLimitRule{method: "GET", prefix: "/api/v1/aggregated/", limit: 5, burst: 20}},
+		{spec: "post /api/v1/cluster=0.5", want: rateLimitRule{method: "POST", prefix: "/api/v1/cluster", limit: 0.5, burst: 1}},
+		{spec: "/api/v1/backup=2.5", want: rateLimitRule{method: "*", prefix: "/api/v1/backup", limit: 2.5, burst: 3}},
+		{spec: " default=10:50 ", want: rateLimitRule{method: "*", prefix: "/", limit: 10, burst: 50}},
+		{spec: "GET /api/v1/cluster=0", want: rateLimitRule{method: "GET", prefix: "/api/v1/cluster", limit: 0, burst: 1}},
+		{spec: "GET /api/v1/cluster", wantErr: true},
+		{spec: "GET api/v1/cluster=1", wantErr: true},
+		{spec: "GET PUT /api/v1/cluster=1", wantErr: true},
+		{spec: "=1", wantErr: true},
+		{spec: "/api=fast", wantErr: true},
+		{spec: "/api=-1", wantErr: true},
+		{spec: "/api=1:0", wantErr: true},
+		{spec: "/api=1:many", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			rule, err := parseRateLimitRule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRateLimitRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *rule != tt.want {
+				t.Errorf("parseRateLimitRule() = %+v, want %+v", *rule, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigureRateLimits(t *testing.T) {
+	t.Cleanup(func() {
+		_ = ConfigureRateLimits(nil)
+	})
+
+	if err := ConfigureRateLimits([]string{
+		"GET /api/v1/aggregated/=1:2",
+		"default=50",
+		"POST /api/v1/cluster=3",
+		"",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method string
+		path   string
+		prefix string
+		limit  rate.Limit
+	}{
+		// The override replaces the default for the same method and path
+		{method: "GET", path: "/api/v1/aggregated/pods", prefix: "/api/v1/aggregated/", limit: 1},
+		// A longer prefix wins over a shorter one
+		{method: "POST", path: "/api/v1/cluster/capi", prefix: "/api/v1/cluster/capi", limit: 0.1},
+		{method: "POST", path: "/api/v1/cluster/member1", prefix: "/api/v1/cluster", limit: 3},
+		// Routes without a more specific rule fall back to the default
+		{method: "GET", path: "/api/v1/cluster", prefix: "/", limit: 50},
+		{method: "DELETE", path: "/api/v1/backup/settings/clusters/member1", prefix: "/", limit: 50},
+	}
+	for _, tt := range tests {
+		rule := limiter.match(tt.method, tt.path)
+		if rule == nil || rule.prefix != tt.prefix || rule.limit != tt.limit {
+			t.Errorf("match(%s %s) = %+v, want prefix %s with rate %v", tt.method, tt.path, rule, tt.prefix, tt.limit)
+		}
+	}
+
+	// An invalid rule is rejected and keeps the current rules
+	if err := ConfigureRateLimits([]string{"GET /api/v1/cluster=x"}); err == nil {
+		t.Error("ConfigureRateLimits() accepted an invalid rule")
+	}
+	if rule := limiter.match("GET", "/api/v1/aggregated/pods"); rule == nil || rule.limit != 1 {
+		t.Errorf("the rules changed after an invalid configuration: %+v", rule)
+	}
+
+	// Without overrides only the defaults apply
+	if err := ConfigureRateLimits(nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(limiter.rules) != len(DefaultRateLimits) {
+		t.Errorf("got %d rules, want the %d defaults", len(limiter.rules), len(DefaultRateLimits))
+	}
+	if rule := limiter.match("GET", "/api/v1/cluster"); rule != nil {
+		t.Errorf("match() without a default rule = %+v, want nil", rule)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Cleanup(func() {
+		_ = ConfigureRateLimits(nil)
+	})
+	if err := ConfigureRateLimits([]string{"GET /limited=0.001:1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RateLimitMiddleware())
+	engine.GET("/limited", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/free", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	if w := request("/limited"); w.Code != http.StatusOK {
+		t.Fatalf("first request returned %d", w.Code)
+	}
+	w := request("/limited")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("second request returned %d with Retry-After %q, want 429 with Retry-After", w.Code, w.Header().Get("Retry-After"))
+	}
+	for i := 0; i < 3; i++ {
+		if w := request("/free"); w.Code != http.StatusOK {
+			t.Errorf("unlimited request returned %d", w.Code)
+		}
+	}
+}
+
+func TestGRPCRateLimitInterceptor(t *testing.T) {
+	t.Cleanup(func() {
+		_ = ConfigureRateLimits(nil)
+	})
+	if err := ConfigureRateLimits([]string{"GET /api/v1/cluster=0.001:1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	call := func(user, fullMethod string, req interface{}) error {
+		ctx := context.WithValue(context.Background(), grpcPrincipalKey{}, grpcPrincipal{name: user})
+		_, err := grpcRateLimitInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+		return err
+	}
+	listClusters := "/platform.v1.ClusterService/ListClusters"
+	if err := call("alice", listClusters, &platformv1.ListClustersRequest{}); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if err := call("alice", listClusters, &platformv1.ListClustersRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second call error = %v, want ResourceExhausted", err)
+	}
+	// Other users and routes keep their own limits
+	if err := call("bob", listClusters, &platformv1.ListClustersRequest{}); err != nil {
+		t.Errorf("call of another user failed: %v", err)
+	}
+	if err := call("alice", "/platform.v1.UserService/GetUser", &platformv1.GetUserRequest{Id: "u1"}); err != nil {
+		t.Errorf("call of an unlimited route failed: %v", err)
+	}
+
+	// The REST requests of a user share the limiter of their gRPC calls
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("user", &apiv1.User{Name: "bob", Authenticated: true})
+	}, RateLimitMiddleware())
+	engine.GET("/api/v1/cluster", func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/cluster", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("REST request after the gRPC call returned %d, want 429", w.Code)
+	}
+}
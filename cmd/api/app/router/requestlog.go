@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/karmada-io/dashboard/pkg/util/requestid"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// RequestLogMiddleware gives each request an ID and logs it with its route, user, cluster, status and
+// duration once it was handled. A valid ID sent by the client in the X-Request-ID header is kept, so the
+// requests of a proxy or the UI can be followed. The ID is returned in the X-Request-ID header, carried by
+// the request context for handlers and the Kubernetes clients, and set as the requestID key of the gin
+// context, so requestid.FromContext and requestid.Logger work with the gin context as well.
+func RequestLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Set(requestid.Key, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+
+		start := time.Now()
+		c.Next()
+
+		// Probes would flood the log
+		if c.Request.URL.Path == "/livez" {
+			return
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = "<unmatched>"
+		}
+		status := c.Writer.Status()
+		keysAndValues := []interface{}{
+			"method", c.Request.Method,
+			"route", route,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration", time.Since(start).String(),
+			"size", c.Writer.Size(),
+			"clientIP", c.ClientIP(),
+		}
+		if route != "<unmatched>" {
+			keysAndValues = append(keysAndValues, "user", utilauth.GetAuthenticatedUser(c))
+		}
+		if impersonator := Impersonator(c); impersonator != "" {
+			keysAndValues = append(keysAndValues, "impersonator", impersonator)
+		}
+		if cluster := requestCluster(c, route); cluster != "" {
+			keysAndValues = append(keysAndValues, "cluster", cluster)
+		}
+		if spanContext := trace.SpanContextFromContext(c); spanContext.IsValid() {
+			keysAndValues = append(keysAndValues, "traceID", spanContext.TraceID().String())
+		}
+
+		// Failures are answered with 200 and an error code too, the response helpers keep their error
+		if len(c.Errors) > 0 {
+			keysAndValues = append(keysAndValues, "error", strings.Join(c.Errors.Errors(), "; "))
+		}
+		requestid.Logger(c).Info("HTTP request", keysAndValues...)
+	}
+}
+
+// requestCluster returns the cluster of the path of a request
+func requestCluster(c *gin.Context, route string) string {
+	if name := c.Param("clustername"); name != "" {
+		return name
+	}
+	if strings.HasPrefix(route, "/api/v1/cluster/:name") {
+		return c.Param("name")
+	}
+	return ""
+}
@@ -34,9 +34,20 @@ func init() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router = gin.Default()
+	// The request log replaces the access log of gin.Default, it is structured and carries the request ID
+	router = gin.New()
+	// Handlers pass the gin context to the Kubernetes clients, it resolves the request ID and the trace
+	// span from the request context
+	router.ContextWithFallback = true
+	router.Use(gin.Recovery(), TracingMiddleware(), RequestLogMiddleware())
 	_ = router.SetTrustedProxies(nil)
+	registerValidations()
 	v1 = router.Group("/api/v1")
+	// API tokens are validated before any route, so the groups below inherit the middleware.
+	// Rate limits apply after it, so requests with an API token are limited per token. Impersonation
+	// comes after them, so an admin's impersonated requests count against the admin, and audit last, so
+	// changes are recorded with the impersonated user and their impersonator.
+	v1.Use(APITokenMiddleware(), RateLimitMiddleware(), ImpersonationMiddleware(), AuditMiddleware())
 	
 	// Member cluster routes with middleware to ensure cluster exists
 	member = v1.Group("/member/:clustername")
@@ -49,9 +60,6 @@ func init() {
 	router.GET("/livez", func(c *gin.Context) {
 		c.String(200, "livez")
 	})
-	router.GET("/readyz", func(c *gin.Context) {
-		c.String(200, "readyz")
-	})
 }
 
 // V1 returns the router group for /api/v1 which for resources in control plane endpoints.
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/karmada-io/dashboard/pkg/tracing"
+	"github.com/karmada-io/dashboard/pkg/util/requestid"
+)
+
+// TracingMiddleware records a server span for each request, continuing the trace of the caller. The span
+// is carried by the request context, so the calls of the Kubernetes clients made with it are its children.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracing.Enabled() || c.Request.URL.Path == "/livez" {
+			c.Next()
+			return
+		}
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracing.Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+			),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if id := requestid.FromContext(c); id != "" {
+			span.SetAttributes(attribute.String("request.id", id))
+		}
+		if cluster := requestCluster(c, route); cluster != "" {
+			span.SetAttributes(tracing.ClusterKey.String(cluster))
+		}
+		// Failures are answered with 200 and an error code too, the response helpers keep their error
+		switch {
+		case len(c.Errors) > 0:
+			span.SetStatus(codes.Error, c.Errors.Last().Error())
+		case status >= http.StatusInternalServerError:
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
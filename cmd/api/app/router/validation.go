@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/util/validation"
+)
+
+// Validator returns the validator gin binds requests with, to register validations of the request DTOs of a module
+func Validator() *validator.Validate {
+	return binding.Validator.Engine().(*validator.Validate)
+}
+
+// registerValidations registers the custom validation rules of the request DTOs
+func registerValidations() {
+	if err := validation.Register(Validator(), listClusterNames); err != nil {
+		klog.ErrorS(err, "Failed to register request validations")
+	}
+}
+
+// managementClusterNames are the names requests address the management cluster with
+var managementClusterNames = []string{"mgmt-cluster", "management"}
+
+// listClusterNames returns the names of the clusters joined to Karmada and of the management cluster
+func listClusterNames() ([]string, error) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := append([]string{}, managementClusterNames...)
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.Name)
+	}
+	return names, nil
+}
@@ -1,17 +1,23 @@
 package argocd
 
 import (
+	"context"
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/projects"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 )
 
@@ -20,230 +26,169 @@ func init() {
 	r.GET("/aggregated/argocd/project", handleGetAggregatedArgoProjects)
 	r.GET("/aggregated/argocd/application", handleGetAggregatedArgoApplications)
 	r.GET("/aggregated/argocd/applicationset", handleGetAggregatedArgoApplicationSets)
+	r.GET("/argocd/instances", handleGetArgoInstances)
 }
 
-// handleGetAggregatedArgoProjects handles GET requests for ArgoCD Projects across all member clusters
-func handleGetAggregatedArgoProjects(c *gin.Context) {
-	karmadaClient := client.InClusterKarmadaClient()
-	dataSelect := common.ParseDataSelectPathParameter(c)
-
-	// Get all clusters
-	clusters, err := cluster.GetClusterList(karmadaClient, dataSelect)
+// listAcrossClusters lists the resources of a type in the ready member clusters, sorted by name, with the
+// errors of the clusters that could not be listed. include filters the clusters and the resources, it is
+// called with an empty name for a cluster.
+func listAcrossClusters(c *gin.Context, resource argocd.Resource, include func(clusterName, name string) bool) ([]unstructured.Unstructured, []multicluster.ClusterError, error) {
+	clusters, err := cluster.GetClusterList(client.InClusterKarmadaClient(), common.ParseDataSelectPathParameter(c))
 	if err != nil {
-		common.Fail(c, err)
-		return
+		return nil, nil, err
 	}
 
-	// For each cluster, get its ArgoCD Projects
-	var allProjects []unstructured.Unstructured
-
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	var included []cluster.Cluster
+	for _, member := range clusters.Clusters {
+		if include(member.ObjectMeta.Name, "") {
+			included = append(included, member)
 		}
-
-		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.ObjectMeta.Name)
-		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client", "cluster", cluster.ObjectMeta.Name)
-			continue
+	}
+	readyClusters, clusterErrors := multicluster.ReadyClusters(included)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]unstructured.Unstructured, error) {
+		// Clusters that do not run ArgoCD have nothing to list
+		if !argocd.Installed(c, clusterName) {
+			return nil, nil
 		}
-
-		projectGVR := schema.GroupVersionResource{
-			Group:    "argoproj.io",
-			Version:  "v1alpha1",
-			Resource: "appprojects",
+		dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+		if err != nil {
+			return nil, err
 		}
-
-		projectList, err := dynamicClient.Resource(projectGVR).List(c, metav1.ListOptions{})
+		items, err := argocd.NewService(dynamicClient, clusterName).List(c, resource, "")
 		if err != nil {
-			klog.ErrorS(err, "Failed to list ArgoCD Projects", "cluster", cluster.ObjectMeta.Name)
-			continue // Skip this cluster if we can't get projects
+			return nil, err
 		}
-
-		// Add cluster information to each project
-		for _, project := range projectList.Items {
-			// Clean up metadata
-			metadata := project.Object["metadata"].(map[string]interface{})
-			
-			// Initialize labels if not present
-			if metadata["labels"] == nil {
-				metadata["labels"] = make(map[string]interface{})
+		var kept []unstructured.Unstructured
+		for _, item := range items {
+			if include(clusterName, item.GetName()) {
+				kept = append(kept, item)
 			}
-			
-			// Add cluster information
-			metadata["labels"].(map[string]interface{})["cluster"] = cluster.ObjectMeta.Name
-
-			// Remove managedFields
-			delete(metadata, "managedFields")
-
-			// Add to our result list
-			allProjects = append(allProjects, project)
 		}
-	}
+		return kept, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
 
+	var all []unstructured.Unstructured
+	for _, items := range results {
+		all = append(all, items...)
+	}
 	// Sort by name for consistent ordering
-	sort.Slice(allProjects, func(i, j int) bool {
-		iName := allProjects[i].Object["metadata"].(map[string]interface{})["name"].(string)
-		jName := allProjects[j].Object["metadata"].(map[string]interface{})["name"].(string)
-		return iName < jName
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].GetName() < all[j].GetName()
 	})
+	return all, clusterErrors, nil
+}
 
-	common.Success(c, gin.H{
+func includeAll(string, string) bool { return true }
+
+// handleGetAggregatedArgoProjects handles GET requests for ArgoCD Projects across all member clusters
+func handleGetAggregatedArgoProjects(c *gin.Context) {
+	allProjects, clusterErrors, err := listAcrossClusters(c, argocd.Project, includeAll)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.SuccessWithClusterErrors(c, gin.H{
 		"items":      allProjects,
 		"totalItems": len(allProjects),
-	})
+	}, clusterErrors)
 }
 
-// handleGetAggregatedArgoApplications handles GET requests for ArgoCD Applications across all member clusters
+// handleGetAggregatedArgoApplications handles GET requests for ArgoCD Applications across all member clusters.
+// The project query parameter restricts them to the applications of a project.
 func handleGetAggregatedArgoApplications(c *gin.Context) {
-	karmadaClient := client.InClusterKarmadaClient()
-	dataSelect := common.ParseDataSelectPathParameter(c)
-
-	// Get all clusters
-	clusters, err := cluster.GetClusterList(karmadaClient, dataSelect)
+	p, err := projects.FromQuery(c)
 	if err != nil {
 		common.Fail(c, err)
 		return
 	}
-
-	// For each cluster, get its ArgoCD Applications
-	var allApplications []unstructured.Unstructured
-
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
-		}
-
-		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.ObjectMeta.Name)
-		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client", "cluster", cluster.ObjectMeta.Name)
-			continue
-		}
-
-		applicationGVR := schema.GroupVersionResource{
-			Group:    "argoproj.io",
-			Version:  "v1alpha1",
-			Resource: "applications",
-		}
-
-		applicationList, err := dynamicClient.Resource(applicationGVR).List(c, metav1.ListOptions{})
-		if err != nil {
-			klog.ErrorS(err, "Failed to list ArgoCD Applications", "cluster", cluster.ObjectMeta.Name)
-			continue // Skip this cluster if we can't get applications
-		}
-
-		// Add cluster information to each application
-		for _, application := range applicationList.Items {
-			// Clean up metadata
-			metadata := application.Object["metadata"].(map[string]interface{})
-			
-			// Initialize labels if not present
-			if metadata["labels"] == nil {
-				metadata["labels"] = make(map[string]interface{})
-			}
-			
-			// Add cluster information
-			metadata["labels"].(map[string]interface{})["cluster"] = cluster.ObjectMeta.Name
-
-			// Remove managedFields
-			delete(metadata, "managedFields")
-
-			// Add to our result list
-			allApplications = append(allApplications, application)
+	allApplications, clusterErrors, err := listAcrossClusters(c, argocd.Application, func(clusterName, name string) bool {
+		switch {
+		case p == nil:
+			return true
+		case name == "":
+			return p.HasCluster(clusterName)
 		}
-	}
-
-	// Sort by name for consistent ordering
-	sort.Slice(allApplications, func(i, j int) bool {
-		iName := allApplications[i].Object["metadata"].(map[string]interface{})["name"].(string)
-		jName := allApplications[j].Object["metadata"].(map[string]interface{})["name"].(string)
-		return iName < jName
+		return p.HasApplication(clusterName, name)
 	})
-
-	common.Success(c, gin.H{
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.SuccessWithClusterErrors(c, gin.H{
 		"items":      allApplications,
 		"totalItems": len(allApplications),
-	})
+	}, clusterErrors)
 }
 
 // handleGetAggregatedArgoApplicationSets handles GET requests for ArgoCD ApplicationSets across all member clusters
 func handleGetAggregatedArgoApplicationSets(c *gin.Context) {
-	karmadaClient := client.InClusterKarmadaClient()
-	dataSelect := common.ParseDataSelectPathParameter(c)
-
-	// Get all clusters
-	clusters, err := cluster.GetClusterList(karmadaClient, dataSelect)
+	allApplicationSets, clusterErrors, err := listAcrossClusters(c, argocd.ApplicationSet, includeAll)
 	if err != nil {
 		common.Fail(c, err)
 		return
 	}
+	common.SuccessWithClusterErrors(c, gin.H{
+		"items":      allApplicationSets,
+		"totalItems": len(allApplicationSets),
+	}, clusterErrors)
+}
 
-	// For each cluster, get its ArgoCD ApplicationSets
-	var allApplicationSets []unstructured.Unstructured
-
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
-		}
+// inspectCluster returns the ArgoCD instance of a member cluster, detecting whether it is installed
+// from the resources the cluster serves
+func inspectCluster(c *gin.Context, clusterName string, now time.Time) argocd.Instance {
+	unknown := argocd.Instance{Cluster: clusterName, Health: argocd.HealthUnknown, DetectedAt: now.Format(time.RFC3339)}
+	capabilities, err := capability.For(c, clusterName)
+	if err != nil {
+		unknown.Message = fmt.Sprintf("failed to discover the resources of the cluster: %v", err)
+		return unknown
+	}
+	installed := capabilities.Supports(capability.KindApplication)
+	if !installed && capabilities.Partial {
+		unknown.Message = "the ArgoCD resources may be in an API group that could not be discovered"
+		return unknown
+	}
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create dynamic client", "cluster", clusterName)
+		unknown.Installed = installed
+		unknown.Message = err.Error()
+		return unknown
+	}
+	return argocd.NewService(dynamicClient, clusterName).Inspect(c, installed, now)
+}
 
-		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.ObjectMeta.Name)
-		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client", "cluster", cluster.ObjectMeta.Name)
+// handleGetArgoInstances handles GET requests for the ArgoCD instance of every member cluster, with whether
+// it is installed, its version and its health. The refresh query parameter detects the installations again
+// instead of using the cached discovery of the clusters.
+func handleGetArgoInstances(c *gin.Context) {
+	clusters, err := cluster.GetClusterList(client.InClusterKarmadaClient(), common.ParseDataSelectPathParameter(c))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	refresh := c.Query("refresh") == "true"
+	now := time.Now()
+
+	instances := make([]argocd.Instance, 0, len(clusters.Clusters))
+	for _, member := range clusters.Clusters {
+		clusterName := member.ObjectMeta.Name
+		if member.Ready != metav1.ConditionTrue {
+			instances = append(instances, argocd.Instance{
+				Cluster:    clusterName,
+				Health:     argocd.HealthUnknown,
+				Message:    "cluster is not ready",
+				DetectedAt: now.Format(time.RFC3339),
+			})
 			continue
 		}
-
-		applicationSetGVR := schema.GroupVersionResource{
-			Group:    "argoproj.io",
-			Version:  "v1alpha1",
-			Resource: "applicationsets",
-		}
-
-		applicationSetList, err := dynamicClient.Resource(applicationSetGVR).List(c, metav1.ListOptions{})
-		if err != nil {
-			klog.ErrorS(err, "Failed to list ArgoCD ApplicationSets", "cluster", cluster.ObjectMeta.Name)
-			continue // Skip this cluster if we can't get applicationsets
-		}
-
-		// Add cluster info to each ApplicationSet
-		for i := range applicationSetList.Items {
-			appSet := &applicationSetList.Items[i]
-			
-			// Add cluster info to the labels
-			if appSet.Object["metadata"] == nil {
-				appSet.Object["metadata"] = map[string]interface{}{}
-			}
-			
-			metadata := appSet.Object["metadata"].(map[string]interface{})
-			if metadata["labels"] == nil {
-				metadata["labels"] = map[string]interface{}{}
-			}
-			
-			labels := metadata["labels"].(map[string]interface{})
-			labels["cluster"] = cluster.ObjectMeta.Name
-			
-			allApplicationSets = append(allApplicationSets, *appSet)
+		if refresh {
+			capability.Invalidate(clusterName)
 		}
+		instances = append(instances, inspectCluster(c, clusterName, now))
 	}
-
-	// Sort the ApplicationSets by name
-	sort.Slice(allApplicationSets, func(i, j int) bool {
-		nameI, _, _ := unstructured.NestedString(allApplicationSets[i].Object, "metadata", "name")
-		nameJ, _, _ := unstructured.NestedString(allApplicationSets[j].Object, "metadata", "name")
-		return nameI < nameJ
-	})
-
-	// Return the ApplicationSets
-	c.JSON(200, gin.H{
-		"items":      allApplicationSets,
-		"totalItems": len(allApplicationSets),
+	common.Success(c, gin.H{
+		"items":      instances,
+		"totalItems": len(instances),
 	})
 }
@@ -1,12 +1,14 @@
 package configmap
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/configmap"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedConfigMaps(c *gin.Context) {
 		return
 	}
 
-	var aggregatedConfigMaps configmap.ConfigMapList
-
-	// Fetch configmaps from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch configmaps from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]configmap.ConfigMap, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := configmap.GetConfigMapList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each configmap
-		for _, cm := range result.Items {
+		for i := range result.Items {
+			cm := &result.Items[i]
 			if cm.ObjectMeta.Labels == nil {
 				cm.ObjectMeta.Labels = make(map[string]string)
 			}
-			cm.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedConfigMaps.Items = append(aggregatedConfigMaps.Items, cm)
+			cm.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedConfigMaps configmap.ConfigMapList
+	for _, items := range results {
+		aggregatedConfigMaps.Items = append(aggregatedConfigMaps.Items, items...)
 	}
 
 	aggregatedConfigMaps.ListMeta.TotalItems = len(aggregatedConfigMaps.Items)
@@ -56,7 +60,7 @@ func handleGetAggregatedConfigMaps(c *gin.Context) {
 		aggregatedConfigMaps.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedConfigMaps)
+	common.SuccessWithClusterErrors(c, aggregatedConfigMaps, clusterErrors)
 }
 
 func init() {
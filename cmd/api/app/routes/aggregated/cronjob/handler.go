@@ -1,12 +1,14 @@
 package cronjob
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cronjob"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedCronJobs(c *gin.Context) {
 		return
 	}
 
-	var aggregatedCronJobs cronjob.CronJobList
-
-	// Fetch cronjobs from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch cronjobs from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]cronjob.CronJob, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := cronjob.GetCronJobList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each cronjob
-		for _, j := range result.Items {
+		for i := range result.Items {
+			j := &result.Items[i]
 			if j.ObjectMeta.Labels == nil {
 				j.ObjectMeta.Labels = make(map[string]string)
 			}
-			j.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedCronJobs.Items = append(aggregatedCronJobs.Items, j)
+			j.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedCronJobs cronjob.CronJobList
+	for _, items := range results {
+		aggregatedCronJobs.Items = append(aggregatedCronJobs.Items, items...)
 	}
 
 	aggregatedCronJobs.ListMeta.TotalItems = len(aggregatedCronJobs.Items)
@@ -56,7 +60,7 @@ func handleGetAggregatedCronJobs(c *gin.Context) {
 		aggregatedCronJobs.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedCronJobs)
+	common.SuccessWithClusterErrors(c, aggregatedCronJobs, clusterErrors)
 }
 
 func init() {
@@ -1,6 +1,7 @@
 package customresource
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 )
 
@@ -43,20 +45,12 @@ func handleGetAggregatedAPIVersions(c *gin.Context) {
 		Cluster  string   `json:"cluster"`
 	}
 
-	var result []APIVersionInfo
-
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
-		}
-
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]APIVersionInfo, error) {
 		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.ObjectMeta.Name)
+		dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
 		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client", "cluster", cluster.ObjectMeta.Name)
-			continue
+			return nil, err
 		}
 
 		crdGVR := schema.GroupVersionResource{
@@ -67,10 +61,10 @@ func handleGetAggregatedAPIVersions(c *gin.Context) {
 
 		crdList, err := dynamicClient.Resource(crdGVR).List(c, metav1.ListOptions{})
 		if err != nil {
-			klog.ErrorS(err, "Failed to list CRDs", "cluster", cluster.ObjectMeta.Name)
-			continue
+			return nil, err
 		}
 
+		var infos []APIVersionInfo
 		// Track unique groups for this cluster
 		clusterGroups := make(map[string]bool)
 
@@ -89,7 +83,7 @@ func handleGetAggregatedAPIVersions(c *gin.Context) {
 			info := APIVersionInfo{
 				Group:    group,
 				Versions: make([]string, 0),
-				Cluster:  cluster.ObjectMeta.Name,
+				Cluster:  clusterName,
 			}
 
 			// Add versions
@@ -108,8 +102,15 @@ func handleGetAggregatedAPIVersions(c *gin.Context) {
 			}
 
 			sort.Strings(info.Versions)
-			result = append(result, info)
+			infos = append(infos, info)
 		}
+		return infos, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var result []APIVersionInfo
+	for _, infos := range results {
+		result = append(result, infos...)
 	}
 
 	// Sort results by group name
@@ -122,7 +123,7 @@ func handleGetAggregatedAPIVersions(c *gin.Context) {
 		"totalItems": len(result),
 	}
 
-	common.Success(c, response)
+	common.SuccessWithClusterErrors(c, response, clusterErrors)
 }
 
 // handleGetAggregatedCustomResources handles GET requests for custom resources across all member clusters
@@ -138,20 +139,12 @@ func handleGetAggregatedCustomResources(c *gin.Context) {
 	}
 
 	// For each cluster, get its CRDs and resources
-	var allResources []unstructured.Unstructured
-
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
-		}
-
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]unstructured.Unstructured, error) {
 		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.ObjectMeta.Name)
+		dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
 		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client", "cluster", cluster.ObjectMeta.Name)
-			continue
+			return nil, err
 		}
 
 		// Get CRDs for this cluster
@@ -163,11 +156,11 @@ func handleGetAggregatedCustomResources(c *gin.Context) {
 
 		crdList, err := dynamicClient.Resource(crdGVR).List(c, metav1.ListOptions{})
 		if err != nil {
-			klog.ErrorS(err, "Failed to list CRDs", "cluster", cluster.ObjectMeta.Name)
-			continue // Skip this cluster if we can't get CRDs
+			return nil, err
 		}
 
 		// For each CRD, get its resources
+		var clusterResources []unstructured.Unstructured
 		for _, crd := range crdList.Items {
 			group := crd.Object["spec"].(map[string]interface{})["group"].(string)
 			version := crd.Object["spec"].(map[string]interface{})["versions"].([]interface{})[0].(map[string]interface{})["name"].(string)
@@ -181,7 +174,7 @@ func handleGetAggregatedCustomResources(c *gin.Context) {
 
 			resources, err := dynamicClient.Resource(gvr).List(c, metav1.ListOptions{})
 			if err != nil {
-				klog.V(4).InfoS("Failed to list resources", "gvr", gvr, "cluster", cluster.ObjectMeta.Name)
+				klog.V(4).InfoS("Failed to list resources", "gvr", gvr, "cluster", clusterName)
 				continue // Skip if we can't access this resource type
 			}
 
@@ -190,10 +183,17 @@ func handleGetAggregatedCustomResources(c *gin.Context) {
 				if resource.Object["metadata"].(map[string]interface{})["labels"] == nil {
 					resource.Object["metadata"].(map[string]interface{})["labels"] = make(map[string]interface{})
 				}
-				resource.Object["metadata"].(map[string]interface{})["labels"].(map[string]interface{})["cluster"] = cluster.ObjectMeta.Name
-				allResources = append(allResources, resource)
+				resource.Object["metadata"].(map[string]interface{})["labels"].(map[string]interface{})["cluster"] = clusterName
+				clusterResources = append(clusterResources, resource)
 			}
 		}
+		return clusterResources, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var allResources []unstructured.Unstructured
+	for _, resources := range results {
+		allResources = append(allResources, resources...)
 	}
 
 	response := gin.H{
@@ -201,7 +201,7 @@ func handleGetAggregatedCustomResources(c *gin.Context) {
 		"totalItems": len(allResources),
 	}
 
-	common.Success(c, response)
+	common.SuccessWithClusterErrors(c, response, clusterErrors)
 }
 
 // handleGetAggregatedCustomResourceDefinitions handles GET requests for CRDs across all member clusters
@@ -220,21 +220,16 @@ func handleGetAggregatedCustomResourceDefinitions(c *gin.Context) {
 	}
 
 	// For each cluster, get its CRDs
-	var allCRDs []unstructured.Unstructured
-	groupedCRDs := make(map[string][]unstructured.Unstructured)
-
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
-		}
-
+	type clusterCRDs struct {
+		cluster string
+		crds    []unstructured.Unstructured
+	}
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) (clusterCRDs, error) {
 		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.ObjectMeta.Name)
+		dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
 		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client", "cluster", cluster.ObjectMeta.Name)
-			continue
+			return clusterCRDs{}, err
 		}
 
 		crdGVR := schema.GroupVersionResource{
@@ -245,8 +240,7 @@ func handleGetAggregatedCustomResourceDefinitions(c *gin.Context) {
 
 		crdList, err := dynamicClient.Resource(crdGVR).List(c, metav1.ListOptions{})
 		if err != nil {
-			klog.ErrorS(err, "Failed to list CRDs", "cluster", cluster.ObjectMeta.Name)
-			continue // Skip this cluster if we can't get CRDs
+			return clusterCRDs{}, err
 		}
 
 		// Add cluster information to each CRD's metadata and extract necessary info from spec
@@ -260,7 +254,7 @@ func handleGetAggregatedCustomResourceDefinitions(c *gin.Context) {
 			}
 			
 			// Add cluster information
-			metadata["labels"].(map[string]interface{})["cluster"] = cluster.ObjectMeta.Name
+			metadata["labels"].(map[string]interface{})["cluster"] = clusterName
 
 			// Remove managedFields
 			delete(metadata, "managedFields")
@@ -294,10 +288,20 @@ func handleGetAggregatedCustomResourceDefinitions(c *gin.Context) {
 				// Remove entire status field
 				delete(crd.Object, "status")
 			}
+		}
+		// The CRDs share their objects with the list, so the changes above are kept
+		return clusterCRDs{cluster: clusterName, crds: crdList.Items}, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
 
+	var allCRDs []unstructured.Unstructured
+	groupedCRDs := make(map[string][]unstructured.Unstructured)
+	for _, result := range results {
+		for _, crd := range result.crds {
 			if groupBy == "group" {
 				// Create unique group key combining group name and cluster
-				groupKey := fmt.Sprintf("%s-%s", group, cluster.ObjectMeta.Name)
+				group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+				groupKey := fmt.Sprintf("%s-%s", group, result.cluster)
 				groupedCRDs[groupKey] = append(groupedCRDs[groupKey], crd)
 			} else {
 				allCRDs = append(allCRDs, crd)
@@ -333,14 +337,14 @@ func handleGetAggregatedCustomResourceDefinitions(c *gin.Context) {
 			})
 		}
 
-		common.Success(c, gin.H{
+		common.SuccessWithClusterErrors(c, gin.H{
 			"groups":     groupedResponse,
 			"totalItems": totalItems,
-		})
+		}, clusterErrors)
 	} else {
-		common.Success(c, gin.H{
+		common.SuccessWithClusterErrors(c, gin.H{
 			"items":      allCRDs,
 			"totalItems": len(allCRDs),
-		})
+		}, clusterErrors)
 	}
 }
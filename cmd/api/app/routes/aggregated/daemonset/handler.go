@@ -1,12 +1,14 @@
 package daemonset
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/daemonset"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedDaemonsets(c *gin.Context) {
 		return
 	}
 
-	var aggregatedDaemonsets daemonset.DaemonSetList
-
-	// Fetch daemonsets from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch daemonsets from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]daemonset.DaemonSet, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := daemonset.GetDaemonSetList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each daemonset
-		for _, d := range result.DaemonSets {
+		for i := range result.DaemonSets {
+			d := &result.DaemonSets[i]
 			if d.ObjectMeta.Labels == nil {
 				d.ObjectMeta.Labels = make(map[string]string)
 			}
-			d.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedDaemonsets.DaemonSets = append(aggregatedDaemonsets.DaemonSets, d)
+			d.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.DaemonSets, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedDaemonsets daemonset.DaemonSetList
+	for _, items := range results {
+		aggregatedDaemonsets.DaemonSets = append(aggregatedDaemonsets.DaemonSets, items...)
 	}
 
 	aggregatedDaemonsets.ListMeta.TotalItems = len(aggregatedDaemonsets.DaemonSets)
@@ -56,7 +60,7 @@ func handleGetAggregatedDaemonsets(c *gin.Context) {
 		aggregatedDaemonsets.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedDaemonsets)
+	common.SuccessWithClusterErrors(c, aggregatedDaemonsets, clusterErrors)
 }
 
 func init() {
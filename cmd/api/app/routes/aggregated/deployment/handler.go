@@ -17,12 +17,15 @@ limitations under the License.
 package deployment
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/projects"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/deployment"
 )
@@ -39,32 +42,50 @@ func handleGetAggregatedDeployments(c *gin.Context) {
 		common.Fail(c, err)
 		return
 	}
+	p, err := projects.FromQuery(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
 
-	var aggregatedDeployments deployment.DeploymentList
-
-	// Fetch deployments from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch deployments from each ready cluster of the project, the clusters that fail are listed in the errors of
+	// the response
+	var projectClusters []cluster.Cluster
+	for _, member := range clusters.Clusters {
+		if p == nil || p.HasCluster(member.ObjectMeta.Name) {
+			projectClusters = append(projectClusters, member)
+		}
+	}
+	readyClusters, clusterErrors := multicluster.ReadyClusters(projectClusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]deployment.Deployment, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := deployment.GetDeploymentList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster information to each deployment's metadata
+		items := make([]deployment.Deployment, 0, len(result.Deployments))
 		for _, d := range result.Deployments {
+			if p != nil && !p.HasNamespace(clusterName, d.ObjectMeta.Namespace) {
+				continue
+			}
 			if d.ObjectMeta.Labels == nil {
 				d.ObjectMeta.Labels = make(map[string]string)
 			}
-			d.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedDeployments.Deployments = append(aggregatedDeployments.Deployments, d)
+			d.ObjectMeta.Labels["cluster"] = clusterName
+			items = append(items, d)
 		}
+		return items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedDeployments deployment.DeploymentList
+	for _, items := range results {
+		aggregatedDeployments.Deployments = append(aggregatedDeployments.Deployments, items...)
 	}
 
 	aggregatedDeployments.ListMeta.TotalItems = len(aggregatedDeployments.Deployments)
@@ -72,7 +93,7 @@ func handleGetAggregatedDeployments(c *gin.Context) {
 		aggregatedDeployments.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedDeployments)
+	common.SuccessWithClusterErrors(c, aggregatedDeployments, clusterErrors)
 }
 
 func init() {
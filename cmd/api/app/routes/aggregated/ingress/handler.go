@@ -1,12 +1,14 @@
 package ingress
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/ingress"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedIngresses(c *gin.Context) {
 		return
 	}
 
-	var aggregatedIngresses ingress.IngressList
-
-	// Fetch ingresses from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch ingresses from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]ingress.Ingress, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := ingress.GetIngressList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each ingress
-		for _, i := range result.Items {
+		for i := range result.Items {
+			i := &result.Items[i]
 			if i.ObjectMeta.Labels == nil {
 				i.ObjectMeta.Labels = make(map[string]string)
 			}
-			i.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedIngresses.Items = append(aggregatedIngresses.Items, i)
+			i.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedIngresses ingress.IngressList
+	for _, items := range results {
+		aggregatedIngresses.Items = append(aggregatedIngresses.Items, items...)
 	}
 
 	aggregatedIngresses.ListMeta.TotalItems = len(aggregatedIngresses.Items)
@@ -56,7 +60,7 @@ func handleGetAggregatedIngresses(c *gin.Context) {
 		aggregatedIngresses.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedIngresses)
+	common.SuccessWithClusterErrors(c, aggregatedIngresses, clusterErrors)
 }
 
 func init() {
@@ -1,12 +1,14 @@
 package job
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/job"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedJobs(c *gin.Context) {
 		return
 	}
 
-	var aggregatedJobs job.JobList
-
-	// Fetch jobs from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch jobs from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]job.Job, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := job.GetJobList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each job
-		for _, j := range result.Jobs {
+		for i := range result.Jobs {
+			j := &result.Jobs[i]
 			if j.ObjectMeta.Labels == nil {
 				j.ObjectMeta.Labels = make(map[string]string)
 			}
-			j.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedJobs.Jobs = append(aggregatedJobs.Jobs, j)
+			j.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Jobs, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedJobs job.JobList
+	for _, items := range results {
+		aggregatedJobs.Jobs = append(aggregatedJobs.Jobs, items...)
 	}
 
 	aggregatedJobs.ListMeta.TotalItems = len(aggregatedJobs.Jobs)
@@ -56,7 +60,7 @@ func handleGetAggregatedJobs(c *gin.Context) {
 		aggregatedJobs.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedJobs)
+	common.SuccessWithClusterErrors(c, aggregatedJobs, clusterErrors)
 }
 
 func init() {
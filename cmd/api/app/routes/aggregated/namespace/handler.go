@@ -17,12 +17,15 @@ limitations under the License.
 package namespace
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/projects"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	ns "github.com/karmada-io/dashboard/pkg/resource/namespace"
 )
@@ -37,29 +40,50 @@ func handleGetAggregatedNamespaces(c *gin.Context) {
 		common.Fail(c, err)
 		return
 	}
+	p, err := projects.FromQuery(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
 
-	var aggregatedNamespaces ns.NamespaceList
-
-	// Fetch namespaces from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch namespaces from each ready cluster of the project, the clusters that fail are listed in the errors of
+	// the response
+	var projectClusters []cluster.Cluster
+	for _, member := range clusters.Clusters {
+		if p == nil || p.HasCluster(member.ObjectMeta.Name) {
+			projectClusters = append(projectClusters, member)
+		}
+	}
+	readyClusters, clusterErrors := multicluster.ReadyClusters(projectClusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]ns.Namespace, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := ns.GetNamespaceList(memberClient, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster information to each namespace's metadata
+		items := make([]ns.Namespace, 0, len(result.Namespaces))
 		for _, n := range result.Namespaces {
-			n.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedNamespaces.Namespaces = append(aggregatedNamespaces.Namespaces, n)
+			if p != nil && !p.HasNamespace(clusterName, n.ObjectMeta.Name) {
+				continue
+			}
+			if n.ObjectMeta.Labels == nil {
+				n.ObjectMeta.Labels = make(map[string]string)
+			}
+			n.ObjectMeta.Labels["cluster"] = clusterName
+			items = append(items, n)
 		}
+		return items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedNamespaces ns.NamespaceList
+	for _, items := range results {
+		aggregatedNamespaces.Namespaces = append(aggregatedNamespaces.Namespaces, items...)
 	}
 
 	aggregatedNamespaces.ListMeta.TotalItems = len(aggregatedNamespaces.Namespaces)
@@ -67,7 +91,7 @@ func handleGetAggregatedNamespaces(c *gin.Context) {
 		aggregatedNamespaces.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedNamespaces)
+	common.SuccessWithClusterErrors(c, aggregatedNamespaces, clusterErrors)
 }
 
 func init() {
@@ -17,16 +17,17 @@ limitations under the License.
 package node
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/node"
 	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/klog/v2"
 )
 
 func handleGetAggregatedNodes(c *gin.Context) {
@@ -41,30 +42,33 @@ func handleGetAggregatedNodes(c *gin.Context) {
 		return
 	}
 
-	var aggregatedNodes node.NodeList
-
-	// Fetch nodes from each cluster
-	for _, cluster := range clusters.Clusters {
-		klog.InfoS("Fetching nodes from cluster", "cluster", cluster)
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-
-		if !isReady {
-			continue
+	// Fetch nodes from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]node.Node, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := node.GetNodeList(memberClient, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster information to each node's metadata
-		for _, n := range result.Items {
-			n.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedNodes.Items = append(aggregatedNodes.Items, n)
+		for i := range result.Items {
+			n := &result.Items[i]
+			if n.ObjectMeta.Labels == nil {
+				n.ObjectMeta.Labels = make(map[string]string)
+			}
+			n.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedNodes node.NodeList
+	for _, items := range results {
+		aggregatedNodes.Items = append(aggregatedNodes.Items, items...)
 	}
 
 	aggregatedNodes.ListMeta.TotalItems = len(aggregatedNodes.Items)
@@ -75,7 +79,7 @@ func handleGetAggregatedNodes(c *gin.Context) {
 	// Create response with aggregated nodes
 	response := aggregatedNodes
 
-	common.Success(c, response)
+	common.SuccessWithClusterErrors(c, response, clusterErrors)
 }
 
 func init() {
@@ -17,12 +17,14 @@ limitations under the License.
 package persistentvolume
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/persistentvolume"
 )
@@ -39,31 +41,33 @@ func handleGetAggregatedPersistentVolumes(c *gin.Context) {
 		return
 	}
 
-	var aggregatedPersistentVolumes persistentvolume.PersistentVolumeList
-
-	// Fetch persistentvolumes from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch persistentvolumes from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]persistentvolume.PersistentVolume, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := persistentvolume.GetPersistentVolumeList(memberClient, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster information to each PV's metadata
-		for _, pv := range result.PersistentVolumes {
+		for i := range result.PersistentVolumes {
+			pv := &result.PersistentVolumes[i]
 			if pv.ObjectMeta.Labels == nil {
 				pv.ObjectMeta.Labels = make(map[string]string)
 			}
-			pv.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedPersistentVolumes.PersistentVolumes = append(aggregatedPersistentVolumes.PersistentVolumes, pv)
+			pv.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.PersistentVolumes, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedPersistentVolumes persistentvolume.PersistentVolumeList
+	for _, items := range results {
+		aggregatedPersistentVolumes.PersistentVolumes = append(aggregatedPersistentVolumes.PersistentVolumes, items...)
 	}
 
 	aggregatedPersistentVolumes.ListMeta.TotalItems = len(aggregatedPersistentVolumes.PersistentVolumes)
@@ -71,7 +75,7 @@ func handleGetAggregatedPersistentVolumes(c *gin.Context) {
 		aggregatedPersistentVolumes.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedPersistentVolumes)
+	common.SuccessWithClusterErrors(c, aggregatedPersistentVolumes, clusterErrors)
 }
 
 func init() {
@@ -17,12 +17,14 @@ limitations under the License.
 package pod
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/pod"
 )
@@ -40,31 +42,33 @@ func handleGetAggregatedPods(c *gin.Context) {
 		return
 	}
 
-	var aggregatedPods pod.PodList
-
-	// Fetch pods from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch pods from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]pod.Pod, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := pod.GetPodList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster information to each pod's metadata
-		for _, p := range result.Items {
+		for i := range result.Items {
+			p := &result.Items[i]
 			if p.ObjectMeta.Labels == nil {
 				p.ObjectMeta.Labels = make(map[string]string)
 			}
-			p.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedPods.Items = append(aggregatedPods.Items, p)
+			p.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedPods pod.PodList
+	for _, items := range results {
+		aggregatedPods.Items = append(aggregatedPods.Items, items...)
 	}
 
 	aggregatedPods.ListMeta.TotalItems = len(aggregatedPods.Items)
@@ -72,7 +76,7 @@ func handleGetAggregatedPods(c *gin.Context) {
 		aggregatedPods.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedPods)
+	common.SuccessWithClusterErrors(c, aggregatedPods, clusterErrors)
 }
 
 func init() {
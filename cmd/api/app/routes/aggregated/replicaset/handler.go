@@ -1,12 +1,14 @@
 package replicaset
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/replicaset"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedReplicaSets(c *gin.Context) {
 		return
 	}
 
-	var aggregatedReplicaSets replicaset.ReplicaSetList
-
-	// Fetch replicasets from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch replicasets from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]replicaset.ReplicaSet, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := replicaset.GetReplicaSetList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each replicaset
-		for _, rs := range result.Items {
+		for i := range result.Items {
+			rs := &result.Items[i]
 			if rs.ObjectMeta.Labels == nil {
 				rs.ObjectMeta.Labels = make(map[string]string)
 			}
-			rs.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedReplicaSets.Items = append(aggregatedReplicaSets.Items, rs)
+			rs.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedReplicaSets replicaset.ReplicaSetList
+	for _, items := range results {
+		aggregatedReplicaSets.Items = append(aggregatedReplicaSets.Items, items...)
 	}
 
 	aggregatedReplicaSets.ListMeta.TotalItems = len(aggregatedReplicaSets.Items)
@@ -56,7 +60,7 @@ func handleGetAggregatedReplicaSets(c *gin.Context) {
 		aggregatedReplicaSets.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedReplicaSets)
+	common.SuccessWithClusterErrors(c, aggregatedReplicaSets, clusterErrors)
 }
 
 func init() {
@@ -1,12 +1,14 @@
 package secret
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/secret"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedSecrets(c *gin.Context) {
 		return
 	}
 
-	var aggregatedSecrets secret.SecretList
-
-	// Fetch secrets from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch secrets from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]secret.Secret, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := secret.GetSecretList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each secret
-		for _, s := range result.Secrets {
+		for i := range result.Secrets {
+			s := &result.Secrets[i]
 			if s.ObjectMeta.Labels == nil {
 				s.ObjectMeta.Labels = make(map[string]string)
 			}
-			s.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedSecrets.Secrets = append(aggregatedSecrets.Secrets, s)
+			s.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Secrets, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedSecrets secret.SecretList
+	for _, items := range results {
+		aggregatedSecrets.Secrets = append(aggregatedSecrets.Secrets, items...)
 	}
 
 	aggregatedSecrets.ListMeta.TotalItems = len(aggregatedSecrets.Secrets)
@@ -56,7 +60,7 @@ func handleGetAggregatedSecrets(c *gin.Context) {
 		aggregatedSecrets.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedSecrets)
+	common.SuccessWithClusterErrors(c, aggregatedSecrets, clusterErrors)
 }
 
 func init() {
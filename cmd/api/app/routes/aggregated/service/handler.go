@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/service"
 )
@@ -24,31 +26,33 @@ func handleGetAggregatedServices(c *gin.Context) {
 		return
 	}
 
-	var aggregatedServices service.ServiceList
-
-	// Fetch services from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch services from each ready cluster, the clusters that fail are listed in the errors of the response
+	readyClusters, clusterErrors := multicluster.ReadyClusters(clusters.Clusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]service.Service, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := service.GetServiceList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster name to each service
-		for _, s := range result.Services {
+		for i := range result.Services {
+			s := &result.Services[i]
 			if s.ObjectMeta.Labels == nil {
 				s.ObjectMeta.Labels = make(map[string]string)
 			}
-			s.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedServices.Services = append(aggregatedServices.Services, s)
+			s.ObjectMeta.Labels["cluster"] = clusterName
 		}
+		return result.Services, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedServices service.ServiceList
+	for _, items := range results {
+		aggregatedServices.Services = append(aggregatedServices.Services, items...)
 	}
 
 	aggregatedServices.ListMeta.TotalItems = len(aggregatedServices.Services)
@@ -56,7 +60,7 @@ func handleGetAggregatedServices(c *gin.Context) {
 		aggregatedServices.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedServices)
+	common.SuccessWithClusterErrors(c, aggregatedServices, clusterErrors)
 }
 
 func init() {
@@ -17,12 +17,15 @@ limitations under the License.
 package statefulset
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/projects"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	"github.com/karmada-io/dashboard/pkg/resource/statefulset"
 )
@@ -39,32 +42,50 @@ func handleGetAggregatedStatefulSets(c *gin.Context) {
 		common.Fail(c, err)
 		return
 	}
+	p, err := projects.FromQuery(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
 
-	var aggregatedStatefulSets statefulset.StatefulSetList
-
-	// Fetch statefulsets from each cluster
-	for _, cluster := range clusters.Clusters {
-		// Skip clusters that are not ready
-		isReady := cluster.Ready == metav1.ConditionTrue
-		if !isReady {
-			continue
+	// Fetch statefulsets from each ready cluster of the project, the clusters that fail are listed in the errors of
+	// the response
+	var projectClusters []cluster.Cluster
+	for _, member := range clusters.Clusters {
+		if p == nil || p.HasCluster(member.ObjectMeta.Name) {
+			projectClusters = append(projectClusters, member)
+		}
+	}
+	readyClusters, clusterErrors := multicluster.ReadyClusters(projectClusters)
+	results, errs := multicluster.Gather(c, readyClusters, func(_ context.Context, clusterName string) ([]statefulset.StatefulSet, error) {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			return nil, multicluster.ErrNoClient
 		}
-
-		memberClient := client.InClusterClientForMemberCluster(cluster.ObjectMeta.Name)
 		result, err := statefulset.GetStatefulSetList(memberClient, namespace, dataSelect)
 		if err != nil {
-			// Log error but continue with other clusters
-			continue
+			return nil, err
 		}
 
 		// Add cluster information to each statefulset's metadata
+		items := make([]statefulset.StatefulSet, 0, len(result.StatefulSets))
 		for _, s := range result.StatefulSets {
+			if p != nil && !p.HasNamespace(clusterName, s.ObjectMeta.Namespace) {
+				continue
+			}
 			if s.ObjectMeta.Labels == nil {
 				s.ObjectMeta.Labels = make(map[string]string)
 			}
-			s.ObjectMeta.Labels["cluster"] = cluster.ObjectMeta.Name
-			aggregatedStatefulSets.StatefulSets = append(aggregatedStatefulSets.StatefulSets, s)
+			s.ObjectMeta.Labels["cluster"] = clusterName
+			items = append(items, s)
 		}
+		return items, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	var aggregatedStatefulSets statefulset.StatefulSetList
+	for _, items := range results {
+		aggregatedStatefulSets.StatefulSets = append(aggregatedStatefulSets.StatefulSets, items...)
 	}
 
 	aggregatedStatefulSets.ListMeta.TotalItems = len(aggregatedStatefulSets.StatefulSets)
@@ -72,7 +93,7 @@ func handleGetAggregatedStatefulSets(c *gin.Context) {
 		aggregatedStatefulSets.ListMeta.TotalItems = 0
 	}
 
-	common.Success(c, aggregatedStatefulSets)
+	common.SuccessWithClusterErrors(c, aggregatedStatefulSets, clusterErrors)
 }
 
 func init() {
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apitoken
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/apitoken"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// handleGetAPITokens lists the API tokens
+func handleGetAPITokens(c *gin.Context) {
+	tokens, err := apitoken.List(c.Request.Context())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list API tokens")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"tokens":     tokens,
+		"totalItems": len(tokens),
+	})
+}
+
+// handleGetAPIToken returns an API token without its secret
+func handleGetAPIToken(c *gin.Context) {
+	token, err := apitoken.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	common.Success(c, token)
+}
+
+// handleCreateAPIToken mints an API token. The bearer token is only returned in this response.
+func handleCreateAPIToken(c *gin.Context) {
+	var req apitoken.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	createdBy := utilauth.GetAuthenticatedUser(c)
+	token, bearer, err := apitoken.Create(c.Request.Context(), req, createdBy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create API token", "name", req.Name)
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Created API token", "id", token.ID, "name", token.Name, "createdBy", createdBy)
+	common.Success(c, gin.H{
+		"token":  token,
+		"secret": bearer,
+	})
+}
+
+// handleRevokeAPIToken deletes an API token, requests with it are rejected afterwards
+func handleRevokeAPIToken(c *gin.Context) {
+	token, err := apitoken.Revoke(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to revoke API token", "id", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Revoked API token", "id", token.ID, "name", token.Name, "revokedBy", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, token)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/apitokens", router.EnsureMgmtAdminMiddleware(), handleGetAPITokens)
+	r.GET("/apitokens/:id", router.EnsureMgmtAdminMiddleware(), handleGetAPIToken)
+	r.POST("/apitokens", router.EnsureMgmtAdminMiddleware(), handleCreateAPIToken)
+	r.DELETE("/apitokens/:id", router.EnsureMgmtAdminMiddleware(), handleRevokeAPIToken)
+}
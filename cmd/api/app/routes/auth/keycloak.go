@@ -139,11 +139,7 @@ func handleKeycloakValidate(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.BaseResponse{
-			Code: http.StatusBadRequest,
-			Msg:  "Invalid request: " + err.Error(),
-			Data: nil,
-		})
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -160,13 +156,7 @@ func handleKeycloakValidate(c *gin.Context) {
 	}
 
 	// Check if user has admin role
-	isAdmin := false
-	for _, role := range claims.Roles {
-		if role == "admin" || role == "dashboard-admin" {
-			isAdmin = true
-			break
-		}
-	}
+	isAdmin := isAdminRoles(claims.Roles)
 
 	c.JSON(http.StatusOK, common.BaseResponse{
 		Code: http.StatusOK,
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// isAdminRoles reports whether the realm roles include a dashboard admin role
+func isAdminRoles(roles []string) bool {
+	for _, role := range roles {
+		if role == "admin" || role == "dashboard-admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionAdminToken returns the service account token for the Keycloak admin API,
+// or the user's token when no client secret is configured
+func sessionAdminToken(ctx context.Context, kc *keycloak.KeycloakClient, userToken string) string {
+	adminToken, err := kc.GetAdminToken(ctx)
+	if err != nil || adminToken == "" {
+		return userToken
+	}
+	return adminToken
+}
+
+// currentKeycloakUser validates the bearer token of the request
+func currentKeycloakUser(c *gin.Context) (*keycloak.KeycloakClient, *keycloak.TokenClaims, string, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		return nil, nil, "", fmt.Errorf("Keycloak authentication not configured")
+	}
+	token := client.GetBearerToken(c.Request)
+	if token == "" {
+		return nil, nil, "", fmt.Errorf("missing authentication token")
+	}
+	claims, err := kc.ValidateToken(c, token)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return kc, claims, token, nil
+}
+
+// handleGetSessions lists the active sessions of the current user.
+// Admins can list the sessions of all users with all=true.
+func handleGetSessions(c *gin.Context) {
+	kc, claims, token, err := currentKeycloakUser(c)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusUnauthorized)
+		return
+	}
+	all := c.Query("all") == "true"
+	if all && !isAdminRoles(claims.Roles) {
+		common.FailWithStatus(c, fmt.Errorf("administrator permissions required to list all sessions"), http.StatusForbidden)
+		return
+	}
+
+	adminToken := sessionAdminToken(c, kc, token)
+	var sessions []keycloak.Session
+	if all {
+		sessions, err = kc.GetClientSessions(c, adminToken)
+	} else {
+		sessions, err = kc.GetUserSessions(c, adminToken, claims.Sub)
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Keycloak sessions", "user", claims.GetUsername(), "all", all)
+		common.Fail(c, err)
+		return
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastAccess > sessions[j].LastAccess
+	})
+
+	common.Success(c, gin.H{
+		"sessions": sessions,
+		"total":    len(sessions),
+	})
+}
+
+// handleRevokeSession logs out a session. Users can revoke their own sessions, admins any session.
+func handleRevokeSession(c *gin.Context) {
+	kc, claims, token, err := currentKeycloakUser(c)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusUnauthorized)
+		return
+	}
+	sessionID := c.Param("id")
+	adminToken := sessionAdminToken(c, kc, token)
+
+	if !isAdminRoles(claims.Roles) {
+		sessions, err := kc.GetUserSessions(c, adminToken, claims.Sub)
+		if err != nil {
+			common.Fail(c, err)
+			return
+		}
+		owned := false
+		for _, session := range sessions {
+			if session.ID == sessionID {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			common.FailWithStatus(c, fmt.Errorf("session %s not found", sessionID), http.StatusNotFound)
+			return
+		}
+	}
+
+	if err := kc.RevokeSession(c, adminToken, sessionID); err != nil {
+		klog.ErrorS(err, "Failed to revoke Keycloak session", "session", sessionID, "user", claims.GetUsername())
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Revoked Keycloak session", "session", sessionID, "user", claims.GetUsername())
+	common.Success(c, gin.H{"id": sessionID})
+}
+
+// handleIntrospectToken reports whether a token belongs to an active session, together with its claims
+func handleIntrospectToken(c *gin.Context) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		common.Fail(c, fmt.Errorf("Keycloak authentication not configured"))
+		return
+	}
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	introspection, err := kc.IntrospectToken(c, req.Token)
+	if err != nil {
+		klog.ErrorS(err, "Failed to introspect token")
+		common.Fail(c, err)
+		return
+	}
+	result := gin.H{
+		"active":    introspection.Active,
+		"expiresAt": introspection.ExpiresAt,
+		"source":    introspection.Source,
+	}
+	if introspection.Claims != nil {
+		result["username"] = introspection.Claims.GetUsername()
+		result["email"] = introspection.Claims.Email
+		result["roles"] = introspection.Claims.Roles
+		result["isAdmin"] = isAdminRoles(introspection.Claims.Roles)
+	}
+	common.Success(c, result)
+}
+
+// handleRefreshToken exchanges a refresh token for new tokens
+func handleRefreshToken(c *gin.Context) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		common.Fail(c, fmt.Errorf("Keycloak authentication not configured"))
+		return
+	}
+	var req struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	token, err := kc.RefreshToken(c, req.RefreshToken)
+	if err != nil {
+		klog.V(4).InfoS("Failed to refresh token", "error", err)
+		common.FailWithStatus(c, err, http.StatusUnauthorized)
+		return
+	}
+	common.Success(c, gin.H{
+		"accessToken":      token.AccessToken,
+		"refreshToken":     token.RefreshToken,
+		"idToken":          token.IDToken,
+		"expiresIn":        token.ExpiresIn,
+		"refreshExpiresIn": token.RefreshExpiresIn,
+		"tokenType":        token.TokenType,
+	})
+}
+
+func init() {
+	v1 := router.V1()
+	v1.GET("/auth/sessions", handleGetSessions)
+	v1.DELETE("/auth/sessions/:id", handleRevokeSession)
+	v1.POST("/auth/token/introspect", handleIntrospectToken)
+	v1.POST("/auth/token/refresh", handleRefreshToken)
+}
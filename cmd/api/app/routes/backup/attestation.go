@@ -0,0 +1,400 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	// attestationLabel marks the immutable ConfigMaps that record the provenance of checkpoints
+	attestationLabel   = "app=backup-attestation"
+	attestationDataKey = "attestation"
+	// signingKeySecretName is the secret with the key checkpoint attestations are signed with
+	signingKeySecretName = "backup-signing-key"
+	signingKeyDataKey    = "private-key"
+	// signCheckpointsAnnotation is set on the StatefulMigrations whose checkpoints are signed
+	signCheckpointsAnnotation = "backup.dcnlab.com/sign-checkpoints"
+)
+
+// CheckpointAttestation is an attestation with the result of checking its signature
+type CheckpointAttestation struct {
+	migration.Attestation
+	Verified          bool   `json:"verified"`
+	VerificationError string `json:"verificationError,omitempty"`
+}
+
+// setSignCheckpointsAnnotation records whether the checkpoints of a backup are signed
+func setSignCheckpointsAnnotation(sm *unstructured.Unstructured, sign bool) {
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if sign {
+		annotations[signCheckpointsAnnotation] = "true"
+	} else {
+		delete(annotations, signCheckpointsAnnotation)
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// signsCheckpoints reports whether the checkpoints of a backup are signed
+func signsCheckpoints(sm *unstructured.Unstructured) bool {
+	return sm.GetAnnotations()[signCheckpointsAnnotation] == "true"
+}
+
+// attestationConfigMapName returns the name of the ConfigMap that records a checkpoint of a backup
+func attestationConfigMapName(backupID, artifact, digest string) string {
+	sum := sha256.Sum256([]byte(backupID + "/" + artifact + "@" + digest))
+	return fmt.Sprintf("backup-attestation-%s", hex.EncodeToString(sum[:10]))
+}
+
+// signingKey returns the PEM encoded key checkpoints are signed with, generating it on first use when create is set
+func signingKey(ctx context.Context, create bool) ([]byte, error) {
+	secrets := client.InClusterClient().CoreV1().Secrets(config.GetNamespace())
+	secret, err := secrets.Get(ctx, signingKeySecretName, metav1.GetOptions{})
+	if err == nil {
+		return secret.Data[signingKeyDataKey], nil
+	}
+	if !apierrors.IsNotFound(err) || !create {
+		return nil, err
+	}
+
+	key, err := migration.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	_, err = secrets.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      signingKeySecretName,
+			Namespace: config.GetNamespace(),
+			Labels:    map[string]string{"app": "backup-signing-key"},
+		},
+		Data: map[string][]byte{signingKeyDataKey: key},
+		Type: corev1.SecretTypeOpaque,
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// Another replica generated the key first
+		return signingKey(ctx, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store signing key: %v", err)
+	}
+	klog.InfoS("Generated checkpoint signing key")
+	return key, nil
+}
+
+// listAttestations returns the attestations of a backup, oldest first
+func listAttestations(ctx context.Context, backupID string) ([]migration.Attestation, error) {
+	list, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s,backup-id=%s", attestationLabel, backupID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	attestations := make([]migration.Attestation, 0, len(list.Items))
+	for i := range list.Items {
+		attestation := migration.Attestation{}
+		if err := json.Unmarshal([]byte(list.Items[i].Data[attestationDataKey]), &attestation); err != nil {
+			klog.ErrorS(err, "Failed to decode checkpoint attestation", "name", list.Items[i].Name)
+			continue
+		}
+		attestations = append(attestations, attestation)
+	}
+	sort.SliceStable(attestations, func(i, j int) bool {
+		return attestations[i].RecordedAt < attestations[j].RecordedAt
+	})
+	return attestations, nil
+}
+
+// recordAttestations records the digest of the checkpoints of a backup that have no attestation yet, and
+// signs them when the backup signs its checkpoints. It returns the number of recorded checkpoints.
+func recordAttestations(ctx context.Context, sm *unstructured.Unstructured) (int, error) {
+	if storageType, _, _ := unstructured.NestedString(sm.Object, "spec", "storage", "type"); storageType == StorageTypePVC {
+		// Checkpoints on a claim of the member cluster cannot be read from here
+		return 0, nil
+	}
+	backupID := sm.GetLabels()["backup-id"]
+	store, err := checkpointStoreForBackup(sm)
+	if err != nil {
+		return 0, err
+	}
+	artifacts, err := store.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list checkpoints: %v", err)
+	}
+	existing, err := listAttestations(ctx, backupID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list attestations: %v", err)
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, attestation := range existing {
+		recorded[attestation.Artifact+"@"+attestation.Digest] = true
+	}
+
+	var privateKey []byte
+	keyID := ""
+	if signsCheckpoints(sm) {
+		if privateKey, err = signingKey(ctx, true); err != nil {
+			return 0, fmt.Errorf("failed to get signing key: %v", err)
+		}
+		if _, keyID, err = migration.SigningPublicKey(privateKey); err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+	configMaps := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace())
+	for _, artifact := range artifacts {
+		if artifact.Digest == "" || recorded[artifact.Name+"@"+artifact.Digest] {
+			continue
+		}
+		attestation := migration.Attestation{
+			BackupID:   backupID,
+			Artifact:   artifact.Name,
+			Digest:     artifact.Digest,
+			Size:       artifact.Size,
+			RecordedAt: time.Now().Format(time.RFC3339),
+		}
+		if !artifact.CreatedAt.IsZero() {
+			attestation.CreatedAt = artifact.CreatedAt.Format(time.RFC3339)
+		}
+		if privateKey != nil {
+			signature, err := migration.Sign(privateKey, migration.SignaturePayload(artifact.Name, artifact.Digest))
+			if err != nil {
+				return count, err
+			}
+			attestation.Signature, attestation.KeyID = signature, keyID
+		}
+		data, err := json.Marshal(attestation)
+		if err != nil {
+			return count, err
+		}
+		immutable := true
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      attestationConfigMapName(backupID, artifact.Name, artifact.Digest),
+				Namespace: config.GetNamespace(),
+				Labels:    map[string]string{"app": "backup-attestation", "backup-id": backupID},
+			},
+			Data:      map[string]string{attestationDataKey: string(data)},
+			Immutable: &immutable,
+		}, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return count, fmt.Errorf("failed to record attestation of %s: %v", artifact.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// recordAllAttestations records the new checkpoints of all backups
+func recordAllAttestations(ctx context.Context) {
+	service, err := backupService()
+	if err != nil {
+		klog.ErrorS(err, "Failed to record checkpoint attestations")
+		return
+	}
+	backups, err := service.List(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list backups for checkpoint attestations")
+		return
+	}
+	for i := range backups {
+		count, err := recordAttestations(ctx, &backups[i])
+		if err != nil {
+			klog.ErrorS(err, "Failed to record checkpoint attestations", "backup", backups[i].GetName())
+		}
+		if count > 0 {
+			klog.InfoS("Recorded checkpoint attestations", "backup", backups[i].GetName(), "count", count)
+			// Scheduled executions are only seen through their new checkpoints, the manifests are exported with them
+			if _, err := exportBackupManifests(ctx, statefulMigrationToBackup(&backups[i])); err != nil {
+				klog.ErrorS(err, "Failed to export backup manifests", "backup", backups[i].GetName())
+			}
+		}
+	}
+}
+
+// StartAttestationWorker periodically records the provenance of new checkpoints until ctx is done.
+// A non-positive interval disables the worker.
+func StartAttestationWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Checkpoint attestation worker is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				recordAllAttestations(ctx)
+			}
+		}
+	}()
+	klog.InfoS("Checkpoint attestation worker started", "interval", interval)
+}
+
+// verifyRecoveryCheckpoint returns a conflict when the latest checkpoint of a backup, the one a recovery
+// restores, is not trusted: its digest changed since it was recorded, or its signature is missing or invalid
+// while the backup signs its checkpoints
+func verifyRecoveryCheckpoint(ctx context.Context, backup BackupConfiguration) *migration.Conflict {
+	service, err := backupService()
+	if err != nil {
+		return nil
+	}
+	sm, err := service.Get(ctx, backup.ID)
+	if err != nil {
+		klog.V(4).InfoS("Skipping checkpoint verification", "backupID", backup.ID, "error", err)
+		return nil
+	}
+	requireSignature := signsCheckpoints(sm)
+	if storageType, _, _ := unstructured.NestedString(sm.Object, "spec", "storage", "type"); storageType == StorageTypePVC {
+		return nil
+	}
+
+	var artifacts []checkpointArtifact
+	store, err := checkpointStoreForBackup(sm)
+	if err == nil {
+		artifacts, err = store.List(ctx)
+	}
+	if err != nil {
+		if requireSignature {
+			return &migration.Conflict{Kind: "Checkpoint", Name: backup.Name, Reason: fmt.Sprintf("cannot be verified: %v", err)}
+		}
+		klog.V(4).InfoS("Skipping checkpoint verification", "backupID", backup.ID, "error", err)
+		return nil
+	}
+	if len(artifacts) == 0 {
+		return nil
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].CreatedAt.After(artifacts[j].CreatedAt) })
+	latest := artifacts[0]
+
+	attestations, err := listAttestations(ctx, backup.ID)
+	if err != nil {
+		return &migration.Conflict{Kind: "Checkpoint", Name: latest.Name, Reason: fmt.Sprintf("cannot be verified: %v", err)}
+	}
+	var publicKey []byte
+	if privateKey, err := signingKey(ctx, false); err == nil {
+		publicKey, _, _ = migration.SigningPublicKey(privateKey)
+	}
+	if err := migration.VerifyCheckpoint(latest.Name, latest.Digest, attestations, publicKey, requireSignature); err != nil {
+		return &migration.Conflict{Kind: "Checkpoint", Name: latest.Name, Reason: fmt.Sprintf("is not trusted: %v", err)}
+	}
+	return nil
+}
+
+// handleGetBackupAttestations lists the recorded digests and signatures of the checkpoints of a backup,
+// recording the checkpoints pushed since the last run of the attestation worker first
+func handleGetBackupAttestations(c *gin.Context) {
+	backupID := c.Param("id")
+	service, err := backupService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	sm, err := service.Get(c, backupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", backupID)
+		common.Fail(c, err)
+		return
+	}
+	if _, err := recordAttestations(c, sm); err != nil {
+		klog.ErrorS(err, "Failed to record checkpoint attestations", "backupID", backupID)
+	}
+
+	attestations, err := listAttestations(c, backupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list checkpoint attestations", "backupID", backupID)
+		common.Fail(c, err)
+		return
+	}
+	var publicKey []byte
+	keyID := ""
+	if privateKey, err := signingKey(c, false); err == nil {
+		publicKey, keyID, _ = migration.SigningPublicKey(privateKey)
+	}
+	result := make([]CheckpointAttestation, 0, len(attestations))
+	for _, attestation := range attestations {
+		checked := CheckpointAttestation{Attestation: attestation}
+		if attestation.Signature != "" {
+			if err := attestation.Verify(publicKey); err != nil {
+				checked.VerificationError = err.Error()
+			} else {
+				checked.Verified = true
+			}
+		}
+		result = append(result, checked)
+	}
+	common.Success(c, map[string]interface{}{
+		"attestations": result,
+		"total":        len(result),
+		"signed":       signsCheckpoints(sm),
+		"keyId":        keyID,
+	})
+}
+
+// handleGetSigningKey returns the public key checkpoints are signed with, to verify them outside the dashboard
+func handleGetSigningKey(c *gin.Context) {
+	privateKey, err := signingKey(c, false)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	publicKey, keyID, err := migration.SigningPublicKey(privateKey)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, map[string]interface{}{
+		"publicKey": string(publicKey),
+		"keyId":     keyID,
+	})
+}
+
+// Register checkpoint attestation routes
+func init() {
+	r := router.V1()
+
+	backupGroup := r.Group("/backup")
+	{
+		backupGroup.GET("/:id/attestations", handleGetBackupAttestations)
+		backupGroup.GET("/signing-key", handleGetSigningKey)
+	}
+}
@@ -18,40 +18,67 @@ package backup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/projects"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+	"github.com/karmada-io/dashboard/pkg/util/validation"
 )
 
 // BackupConfiguration represents a backup configuration
 type BackupConfiguration struct {
-	ID           string         `json:"id"`
-	Name         string         `json:"name"`
-	Cluster      string         `json:"cluster"`
-	ResourceType string         `json:"resourceType"` // "pod" or "statefulset"
-	ResourceName string         `json:"resourceName"`
-	Namespace    string         `json:"namespace"`
-	Registry     RegistryInfo   `json:"registry"`
-	Repository   string         `json:"repository"`
-	Schedule     ScheduleConfig `json:"schedule"`
-	Status       string         `json:"status"`
-	LastBackup   string         `json:"lastBackup,omitempty"`
-	NextBackup   string         `json:"nextBackup,omitempty"`
-	CreatedAt    string         `json:"createdAt"`
-	UpdatedAt    string         `json:"updatedAt"`
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Cluster      string              `json:"cluster"`
+	ResourceType string              `json:"resourceType"` // "pod" or "statefulset"
+	ResourceName string              `json:"resourceName"`
+	Namespace    string              `json:"namespace"`
+	Registry     RegistryInfo        `json:"registry"`
+	Repository   string              `json:"repository"`
+	Storage      *StorageBackendInfo `json:"storage,omitempty"`
+	Encryption   *EncryptionKeyInfo  `json:"encryption,omitempty"`
+	Schedule     ScheduleConfig      `json:"schedule"`
+	Retention    *RetentionPolicy    `json:"retention,omitempty"`
+	// VolumeSnapshots is set when the persistent volumes of the workload are snapshotted with each backup
+	VolumeSnapshots *VolumeSnapshotPolicy `json:"volumeSnapshots,omitempty"`
+	// Containers are the containers of the pods that are checkpointed, all of them when empty
+	Containers []string `json:"containers,omitempty"`
+	// SignCheckpoints is set when the checkpoints are signed and only signed checkpoints are recovered
+	SignCheckpoints bool `json:"signCheckpoints,omitempty"`
+	// Throttle limits the bandwidth and concurrency of the checkpoints of the backup, the controller settings apply when unset
+	Throttle *migration.Throttle `json:"throttle,omitempty"`
+	// Retry re-triggers the failed executions of the backup, failed executions are not retried when unset
+	Retry  *migration.RetryPolicy `json:"retry,omitempty"`
+	LastGC string                 `json:"lastGC,omitempty"`
+	// TemplateID is the backup template the configuration was created from
+	TemplateID string `json:"templateId,omitempty"`
+	Status     string `json:"status"`
+	LastBackup string `json:"lastBackup,omitempty"`
+	NextBackup string `json:"nextBackup,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+	// ManagedBy is "gitops" for configurations deployed from Git, which are read-only, and "dashboard" otherwise
+	ManagedBy string `json:"managedBy"`
+	// GitOpsApplication is the ArgoCD application deploying a configuration managed by GitOps
+	GitOpsApplication string `json:"gitOpsApplication,omitempty"`
 }
 
 // RegistryInfo represents registry information for backup
@@ -66,30 +93,55 @@ type ScheduleConfig struct {
 	Type    string `json:"type"`  // "selection" or "cron"
 	Value   string `json:"value"` // For selection: "5m", "15m", "30m", "1h". For cron: cron expression
 	Enabled bool   `json:"enabled"`
+	ExecutionWindows
+}
+
+// validateScheduleConfig checks the cron expression of a cron schedule when a request is bound
+func validateScheduleConfig(sl validator.StructLevel) {
+	schedule := sl.Current().Interface().(ScheduleConfig)
+	if schedule.Type == "cron" && !validation.IsCron(schedule.Value) {
+		sl.ReportError(schedule.Value, "value", "Value", validation.TagCron, "")
+	}
 }
 
 // CreateBackupRequest represents the request to create a new backup
 type CreateBackupRequest struct {
-	Name         string         `json:"name" binding:"required"`
-	Cluster      string         `json:"cluster" binding:"required"`
-	ResourceType string         `json:"resourceType" binding:"required,oneof=pod statefulset"`
-	ResourceName string         `json:"resourceName" binding:"required"`
-	Namespace    string         `json:"namespace" binding:"required"`
-	RegistryID   string         `json:"registryId" binding:"required"`
-	Repository   string         `json:"repository" binding:"required"`
-	Schedule     ScheduleConfig `json:"schedule" binding:"required"`
+	Name             string                 `json:"name" binding:"required"`
+	Cluster          string                 `json:"cluster" binding:"required,cluster"`
+	ResourceType     string                 `json:"resourceType" binding:"required,oneof=pod statefulset"`
+	ResourceName     string                 `json:"resourceName" binding:"required,dns1123subdomain"`
+	Namespace        string                 `json:"namespace" binding:"required,dns1123label"`
+	RegistryID       string                 `json:"registryId"`       // Defaults to the registry provisioned for the namespace
+	Repository       string                 `json:"repository"`       // Defaults to a repository of the provisioned registry
+	StorageBackendID string                 `json:"storageBackendId"` // Replaces the registry when set
+	Schedule         ScheduleConfig         `json:"schedule" binding:"required"`
+	Retention        *RetentionPolicy       `json:"retention"`
+	VolumeSnapshots  *VolumeSnapshotPolicy  `json:"volumeSnapshots"`
+	Containers       []string               `json:"containers"` // Checkpoints only these containers, such as the app without its istio-proxy
+	EncryptionKeyID  string                 `json:"encryptionKeyId"`
+	SignCheckpoints  bool                   `json:"signCheckpoints"`
+	Throttle         *migration.Throttle    `json:"throttle"`
+	Retry            *migration.RetryPolicy `json:"retry"`
 }
 
 // UpdateBackupRequest represents the request to update a backup
 type UpdateBackupRequest struct {
-	Name         string         `json:"name"`
-	Cluster      string         `json:"cluster"`
-	ResourceType string         `json:"resourceType"`
-	ResourceName string         `json:"resourceName"`
-	Namespace    string         `json:"namespace"`
-	RegistryID   string         `json:"registryId"`
-	Repository   string         `json:"repository"`
-	Schedule     ScheduleConfig `json:"schedule"`
+	Name             string                 `json:"name"`
+	Cluster          string                 `json:"cluster" binding:"omitempty,cluster"`
+	ResourceType     string                 `json:"resourceType" binding:"omitempty,oneof=pod statefulset"`
+	ResourceName     string                 `json:"resourceName" binding:"omitempty,dns1123subdomain"`
+	Namespace        string                 `json:"namespace" binding:"omitempty,dns1123label"`
+	RegistryID       string                 `json:"registryId"`
+	Repository       string                 `json:"repository"`
+	StorageBackendID string                 `json:"storageBackendId"`
+	Schedule         ScheduleConfig         `json:"schedule"`
+	Retention        *RetentionPolicy       `json:"retention"`
+	VolumeSnapshots  *VolumeSnapshotPolicy  `json:"volumeSnapshots"` // Enabled false turns snapshots off
+	Containers       []string               `json:"containers"`      // An empty list checkpoints all containers again
+	EncryptionKeyID  *string                `json:"encryptionKeyId"` // An empty ID turns encryption off
+	SignCheckpoints  *bool                  `json:"signCheckpoints"`
+	Throttle         *migration.Throttle    `json:"throttle"` // A throttle without limits falls back to the controller settings
+	Retry            *migration.RetryPolicy `json:"retry"`    // A policy without attempts turns retries off
 }
 
 // BackupExecutionRequest represents a request to execute a backup immediately
@@ -97,37 +149,60 @@ type BackupExecutionRequest struct {
 	BackupID string `json:"backupId" binding:"required"`
 }
 
-// StatefulMigrationCR represents the StatefulMigration custom resource
-var statefulMigrationGVR = schema.GroupVersionResource{
-	Group:    "migration.dcnlab.com",
-	Version:  "v1",
-	Resource: "statefulmigrations",
-}
+// statefulMigrationGVR is the resource of the StatefulMigration CRs of backup configurations
+var statefulMigrationGVR = migration.Backup.Resource
 
-var defaultNamespace = "stateful-migration"
+var defaultNamespace = migration.Namespace
 
-// handleGetBackups retrieves all backup configurations
-func handleGetBackups(c *gin.Context) {
+// backupService returns the service managing the StatefulMigration CRs of backup configurations
+func backupService() (*migration.Service, error) {
 	dynamicClient, err := client.GetDynamicClient()
 	if err != nil {
 		klog.ErrorS(err, "Failed to get dynamic client")
+		return nil, err
+	}
+	return migration.NewService(dynamicClient, migration.Backup, defaultNamespace), nil
+}
+
+// listBackups returns all backup configurations
+func listBackups(ctx context.Context) ([]BackupConfiguration, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := service.List(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list StatefulMigration CRs")
+		return nil, err
+	}
+
+	backups := make([]BackupConfiguration, 0, len(items))
+	for _, item := range items {
+		backups = append(backups, statefulMigrationToBackup(&item))
+	}
+	return backups, nil
+}
+
+// handleGetBackups retrieves all backup configurations, or those of the project query parameter
+func handleGetBackups(c *gin.Context) {
+	p, err := projects.FromQuery(c)
+	if err != nil {
 		common.Fail(c, err)
 		return
 	}
 
-	// List all StatefulMigration CRs
-	unstructuredList, err := dynamicClient.Resource(statefulMigrationGVR).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: "app=backup-migration",
-	})
+	all, err := listBackups(c)
 	if err != nil {
-		klog.ErrorS(err, "Failed to list StatefulMigration CRs")
 		common.Fail(c, err)
 		return
 	}
 
-	backups := make([]BackupConfiguration, 0, len(unstructuredList.Items))
-	for _, item := range unstructuredList.Items {
-		backup := statefulMigrationToBackup(&item)
+	backups := make([]BackupConfiguration, 0, len(all))
+	for _, backup := range all {
+		if p != nil && !p.HasBackup(backup.ID, backup.Cluster, backup.Namespace) {
+			continue
+		}
 		backups = append(backups, backup)
 	}
 
@@ -137,27 +212,50 @@ func handleGetBackups(c *gin.Context) {
 	})
 }
 
+// getBackup returns a backup configuration
+func getBackup(ctx context.Context, backupID string) (BackupConfiguration, error) {
+	service, err := backupService()
+	if err != nil {
+		return BackupConfiguration{}, err
+	}
+
+	unstructuredObj, err := service.Get(ctx, backupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get StatefulMigration CR", "backupID", backupID)
+		return BackupConfiguration{}, err
+	}
+	return statefulMigrationToBackup(unstructuredObj), nil
+}
+
 // handleGetBackup retrieves a specific backup configuration
 func handleGetBackup(c *gin.Context) {
-	backupID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
+	backup, err := getBackup(c, c.Param("id"))
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
 		common.Fail(c, err)
 		return
 	}
+	common.Success(c, backup)
+}
 
-	// Get the StatefulMigration CR
-	unstructuredObj, err := dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Get(context.TODO(),
-		fmt.Sprintf("backup-%s", backupID), metav1.GetOptions{})
+// createBackup validates a backup request and creates the StatefulMigration CR of the new configuration
+func createBackup(ctx context.Context, req CreateBackupRequest) (BackupConfiguration, error) {
+	statefulMigration, err := newStatefulMigrationCR(ctx, req)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get StatefulMigration CR", "backupID", backupID)
-		common.Fail(c, err)
-		return
+		return BackupConfiguration{}, err
+	}
+	if err := validateBackupContainers(ctx, statefulMigrationToBackup(statefulMigration)); err != nil {
+		return BackupConfiguration{}, &statusError{err: err, status: http.StatusBadRequest}
 	}
 
-	backup := statefulMigrationToBackup(unstructuredObj)
-	common.Success(c, backup)
+	service, err := backupService()
+	if err != nil {
+		return BackupConfiguration{}, err
+	}
+	if _, err := service.Create(ctx, statefulMigration); err != nil {
+		klog.ErrorS(err, "Failed to create StatefulMigration CR")
+		return BackupConfiguration{}, err
+	}
+	return statefulMigrationToBackup(statefulMigration), nil
 }
 
 // handleCreateBackup creates a new backup configuration
@@ -165,49 +263,154 @@ func handleCreateBackup(c *gin.Context) {
 	var req CreateBackupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind backup request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
+	audit.SetClusters(c, req.Cluster)
 
-	// Validate cron expression if schedule type is cron
-	if req.Schedule.Type == "cron" {
-		if err := validateCronExpression(req.Schedule.Value); err != nil {
-			klog.ErrorS(err, "Invalid cron expression", "cron", req.Schedule.Value)
-			common.Fail(c, fmt.Errorf("invalid cron expression: %v", err))
-			return
+	backup, err := createBackup(c, req)
+	if err != nil {
+		failBackupChange(c, err)
+		return
+	}
+	common.Success(c, backup)
+}
+
+// newStatefulMigrationCR validates a backup request and returns the StatefulMigration CR of the new configuration
+func newStatefulMigrationCR(ctx context.Context, req CreateBackupRequest) (*unstructured.Unstructured, error) {
+	if err := checkNotCordoned(ctx, req.Cluster); err != nil {
+		return nil, err
+	}
+	if err := validateBackupSchedule(req.Schedule, req.Retention); err != nil {
+		return nil, err
+	}
+	if err := migration.ValidateContainers(req.Containers, nil); err != nil {
+		return nil, err
+	}
+	if req.Throttle != nil {
+		if err := req.Throttle.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if req.Retry != nil {
+		if err := req.Retry.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := defaultProvisionedRegistry(&req); err != nil {
+		return nil, err
+	}
+	registry, storage, err := resolveCheckpointStorage(req.RegistryID, req.StorageBackendID)
+	if err != nil {
+		return nil, err
+	}
+	if storage != nil {
+		if err := validateRetentionStorage(req.Retention, storage.Type); err != nil {
+			return nil, err
 		}
 	}
 
-	// Get registry information
-	registry, err := getRegistryByID(req.RegistryID)
+	encryption, err := resolveEncryptionKey(req.EncryptionKeyID)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get registry", "registryID", req.RegistryID)
-		common.Fail(c, err)
-		return
+		return nil, err
 	}
 
 	// Generate unique ID for the backup
-	backupID := generateBackupID(req.Name)
+	backupID := migration.GenerateID(req.Name)
+	sm := createStatefulMigrationCR(backupID, req, registry, storage)
+	if encryption != nil {
+		setBackupEncryption(sm, encryption)
+	}
+	return sm, nil
+}
 
-	// Create StatefulMigration CR
-	statefulMigration := createStatefulMigrationCR(backupID, req, registry)
+// defaultProvisionedRegistry stores the checkpoints of a backup that names neither a registry nor a storage
+// backend in the registry provisioned for its namespace, in a repository named after the backup
+func defaultProvisionedRegistry(req *CreateBackupRequest) error {
+	if req.StorageBackendID != "" {
+		return nil
+	}
+	if req.RegistryID == "" {
+		registry, found, err := provisionedRegistryFor(context.TODO(), req.Cluster, req.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to look up the registry of namespace %s: %v", req.Namespace, err)
+		}
+		if !found {
+			return fmt.Errorf("registryId or storageBackendId is required, no registry is provisioned for namespace %s of cluster %s", req.Namespace, req.Cluster)
+		}
+		req.RegistryID = registry.ID
+		if req.Repository == "" {
+			req.Repository = registry.Name + "/" + strings.ToLower(strings.ReplaceAll(req.Name, " ", "-"))
+		}
+	}
+	if req.Repository == "" {
+		return fmt.Errorf("repository is required with registry %s", req.RegistryID)
+	}
+	return nil
+}
 
-	dynamicClient, err := client.GetDynamicClient()
+// resolveEncryptionKey returns the encryption key of a backup, nil when its checkpoints are not encrypted
+func resolveEncryptionKey(keyID string) (*EncryptionKey, error) {
+	if keyID == "" {
+		return nil, nil
+	}
+	key, err := getEncryptionKeyByID(keyID)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
-		return
+		return nil, fmt.Errorf("encryption key %s: %v", keyID, err)
 	}
-	_, err = dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Create(context.TODO(),
-		statefulMigration, metav1.CreateOptions{})
+	return &key, nil
+}
+
+// validateBackupContainers checks the container selection of a backup against the pod spec of its workload.
+// The workload is looked up best effort: a backup may be configured before its workload is deployed.
+func validateBackupContainers(ctx context.Context, backup BackupConfiguration) error {
+	if len(backup.Containers) == 0 {
+		return nil
+	}
+	memberClient := client.InClusterClientForMemberCluster(backup.Cluster)
+	if memberClient == nil {
+		return nil
+	}
+	spec, _, _, err := sourceWorkloadPodSpec(ctx, memberClient, backup)
 	if err != nil {
-		klog.ErrorS(err, "Failed to create StatefulMigration CR")
-		common.Fail(c, err)
-		return
+		klog.V(4).InfoS("Skipping container selection check", "backup", backup.Name, "cluster", backup.Cluster, "error", err)
+		return nil
 	}
+	return migration.ValidateContainers(backup.Containers, spec)
+}
 
-	backup := statefulMigrationToBackup(statefulMigration)
-	common.Success(c, backup)
+// validateBackupSchedule validates the schedule, execution windows and retention of a backup
+func validateBackupSchedule(schedule ScheduleConfig, retention *RetentionPolicy) error {
+	// Validate cron expression if schedule type is cron
+	if schedule.Type == "cron" {
+		if err := migration.ValidateCronExpression(schedule.Value); err != nil {
+			klog.ErrorS(err, "Invalid cron expression", "cron", schedule.Value)
+			return fmt.Errorf("invalid cron expression: %v", err)
+		}
+	}
+
+	if err := validateRetentionPolicy(retention); err != nil {
+		return err
+	}
+	return schedule.ExecutionWindows.validate()
+}
+
+// resolveCheckpointStorage returns where checkpoints are stored, either a storage backend or the image registry
+func resolveCheckpointStorage(registryID, storageBackendID string) (RegistryCredentials, *StorageBackend, error) {
+	if storageBackendID != "" {
+		backend, err := getStorageBackendByID(storageBackendID)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get storage backend", "storageID", storageBackendID)
+			return RegistryCredentials{}, nil, err
+		}
+		return RegistryCredentials{}, &backend, nil
+	}
+	registry, err := getRegistryByID(registryID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get registry", "registryID", registryID)
+		return RegistryCredentials{}, nil, err
+	}
+	return registry, nil, nil
 }
 
 // handleUpdateBackup updates an existing backup configuration
@@ -216,268 +419,386 @@ func handleUpdateBackup(c *gin.Context) {
 	var req UpdateBackupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind backup update request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
-	dynamicClient, err := client.GetDynamicClient()
-	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
+	if err := validateRetentionPolicy(req.Retention); err != nil {
 		common.Fail(c, err)
 		return
 	}
-	smName := fmt.Sprintf("backup-%s", backupID)
+	if err := req.Schedule.ExecutionWindows.validate(); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := migration.ValidateContainers(req.Containers, nil); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if req.Throttle != nil {
+		if err := req.Throttle.Validate(); err != nil {
+			common.FailWithStatus(c, err, http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Retry != nil {
+		if err := req.Retry.Validate(); err != nil {
+			common.FailWithStatus(c, err, http.StatusBadRequest)
+			return
+		}
+	}
+	var encryption *EncryptionKey
+	if req.EncryptionKeyID != nil {
+		key, err := resolveEncryptionKey(*req.EncryptionKeyID)
+		if err != nil {
+			common.FailWithStatus(c, err, http.StatusBadRequest)
+			return
+		}
+		encryption = key
+	}
 
-	// Get existing StatefulMigration CR
-	unstructuredObj, err := dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Get(context.TODO(),
-		smName, metav1.GetOptions{})
+	service, err := backupService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get StatefulMigration CR for update", "backupID", backupID)
 		common.Fail(c, err)
 		return
 	}
 
-	// Update the CR with new values
-	updated := updateStatefulMigrationCR(unstructuredObj, req)
-
-	_, err = dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Update(context.TODO(),
-		updated, metav1.UpdateOptions{})
+	// The new values are applied to the latest CR again when the update conflicts with another change
+	var errInvalidContainers error
+	updated, err := service.Mutate(c, backupID, func(sm *unstructured.Unstructured) error {
+		if err := checkNotCordoned(c, strings.Split(statefulMigrationToBackup(sm).Cluster, ",")...); err != nil {
+			return err
+		}
+		storageType, _, _ := unstructured.NestedString(sm.Object, "spec", "storage", "type")
+		if err := validateRetentionStorage(req.Retention, storageType); err != nil {
+			return &statusError{err: err, status: http.StatusBadRequest}
+		}
+		updateStatefulMigrationCR(sm, req)
+		if req.EncryptionKeyID != nil {
+			setBackupEncryption(sm, encryption)
+		}
+		if len(req.Containers) > 0 {
+			errInvalidContainers = validateBackupContainers(c, statefulMigrationToBackup(sm))
+			return errInvalidContainers
+		}
+		return nil
+	})
 	if err != nil {
-		klog.ErrorS(err, "Failed to update StatefulMigration CR")
-		common.Fail(c, err)
+		klog.ErrorS(err, "Failed to update StatefulMigration CR", "backupID", backupID)
+		if errInvalidContainers != nil {
+			common.FailWithStatus(c, err, http.StatusBadRequest)
+			return
+		}
+		failBackupChange(c, err)
 		return
 	}
 
 	backup := statefulMigrationToBackup(updated)
+	audit.SetClusters(c, strings.Split(backup.Cluster, ",")...)
 	common.Success(c, backup)
 }
 
-// handleDeleteBackup deletes a backup configuration
+// handleDeleteBackup moves a backup configuration to the trash, or deletes it for good with ?permanent=true
 func handleDeleteBackup(c *gin.Context) {
 	backupID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
+	service, err := backupService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
 		common.Fail(c, err)
 		return
 	}
 
-	smName := fmt.Sprintf("backup-%s", backupID)
-	err = dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Delete(context.TODO(),
-		smName, metav1.DeleteOptions{})
-	if err != nil {
+	permanent := c.Query("permanent") == "true"
+	if err := deleteRecord(c, service, backupID, utilauth.GetAuthenticatedUser(c), permanent); err != nil {
 		klog.ErrorS(err, "Failed to delete StatefulMigration CR", "backupID", backupID)
-		common.Fail(c, err)
+		failBackupChange(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Backup configuration deleted successfully",
-	})
-}
-
-// handleExecuteBackup executes a backup immediately
-func handleExecuteBackup(c *gin.Context) {
-	backupID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
-	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
+	if permanent {
+		common.Success(c, ActionResult{Message: "Backup configuration deleted successfully"})
 		return
 	}
+	common.Success(c, ActionResult{Message: fmt.Sprintf("Backup configuration moved to the trash, it is purged after %s", config.TrashRetention())})
+}
 
-	// Get the StatefulMigration CR
-	smName := fmt.Sprintf("backup-%s", backupID)
-	unstructuredObj, err := dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Get(context.TODO(),
-		smName, metav1.GetOptions{})
+// executeBackup triggers an execution of a backup requested by a user and snapshots the volumes of its workload
+func executeBackup(c *gin.Context, backupID string) (BackupConfiguration, []VolumeSnapshotInfo, error) {
+	unstructuredObj, err := triggerBackup(c, backupID, nil)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get StatefulMigration CR", "backupID", backupID)
-		common.Fail(c, err)
-		return
+		return BackupConfiguration{}, nil, err
 	}
 
-	// Trigger immediate backup by updating the CR with a new execution timestamp
-	spec, found, err := unstructured.NestedMap(unstructuredObj.Object, "spec")
-	if err != nil || !found {
-		common.Fail(c, fmt.Errorf("failed to get spec from StatefulMigration CR"))
-		return
+	// Volume snapshots are best effort, the checkpoint is taken either way
+	backup := statefulMigrationToBackup(unstructuredObj)
+	var volumeSnapshots []VolumeSnapshotInfo
+	if backup.VolumeSnapshots != nil {
+		volumeSnapshots, err = snapshotBackupVolumes(c, backup, *backup.VolumeSnapshots)
+		if err != nil {
+			klog.ErrorS(err, "Failed to snapshot backup volumes", "backupID", backupID)
+		}
 	}
+	return backup, volumeSnapshots, nil
+}
 
-	// Add immediate execution trigger
-	spec["executeNow"] = time.Now().Unix()
-	unstructured.SetNestedMap(unstructuredObj.Object, spec, "spec")
-
-	_, err = dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Update(context.TODO(),
-		unstructuredObj, metav1.UpdateOptions{})
+// handleExecuteBackup executes a backup immediately
+func handleExecuteBackup(c *gin.Context) {
+	backup, volumeSnapshots, err := executeBackup(c, c.Param("id"))
 	if err != nil {
-		klog.ErrorS(err, "Failed to trigger backup execution")
-		common.Fail(c, err)
+		failBackupChange(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Backup execution triggered successfully",
+	audit.SetClusters(c, strings.Split(backup.Cluster, ",")...)
+	common.Success(c, BackupExecutionResult{
+		Message:         "Backup execution triggered successfully",
+		VolumeSnapshots: volumeSnapshots,
 	})
 }
 
-// handleGetResourcesInCluster gets available resources (pods/statefulsets) in a specific cluster
-func handleGetResourcesInCluster(c *gin.Context) {
-	clusterName := c.Param("cluster")
-	resourceType := c.Query("type") // "pod" or "statefulset"
-	namespace := c.Query("namespace")
-
-	if resourceType == "" {
-		common.Fail(c, fmt.Errorf("resource type is required"))
+// failBackupChange fails a request changing a backup configuration or recovery record, with a conflict for the
+// CRs managed by GitOps and for the ones that kept changing concurrently so that clients can tell them apart.
+// Concurrent changes report the latest resource version, and workloads locked by another operation the holder
+// of the lock. Operations on clusters in maintenance mode or cordoned are conflicts as well.
+func failBackupChange(c *gin.Context, err error) {
+	var conflict *migration.ConflictError
+	if errors.As(err, &conflict) {
+		common.FailWithData(c, err, http.StatusConflict, ConflictResult{ResourceVersion: conflict.ResourceVersion})
 		return
 	}
-
-	// Get member cluster client
-	memberClient, err := getMemberClusterClient(c, clusterName)
-	if err != nil {
-		klog.ErrorS(err, "Failed to get member cluster client", "cluster", clusterName)
-		common.Fail(c, err)
+	var locked *migration.LockedError
+	if errors.As(err, &locked) {
+		common.FailWithData(c, err, http.StatusConflict, locked.Holder)
 		return
 	}
-
-	var resources []map[string]interface{}
-
-	switch resourceType {
-	case "pod":
-		resources, err = getPodsInCluster(memberClient, namespace)
-	case "statefulset":
-		resources, err = getStatefulSetsInCluster(memberClient, namespace)
-	default:
-		common.Fail(c, fmt.Errorf("unsupported resource type: %s", resourceType))
+	if status := backupChangeStatus(err); status != 0 {
+		common.FailWithStatus(c, err, status)
 		return
 	}
+	common.Fail(c, err)
+}
+
+// statusError is an error of a change that is answered with its HTTP status
+type statusError struct {
+	err    error
+	status int
+}
 
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// backupChangeStatus returns the HTTP status of an error changing a backup configuration or recovery record,
+// 0 for errors without a specific status
+func backupChangeStatus(err error) int {
+	var withStatus *statusError
+	var conflict *migration.ConflictError
+	var locked *migration.LockedError
+	switch {
+	case errors.As(err, &withStatus):
+		return withStatus.status
+	case errors.As(err, &conflict), errors.As(err, &locked), errors.Is(err, migration.ErrGitOpsManaged),
+		errors.Is(err, errClusterInMaintenance), errors.Is(err, errClusterCordoned):
+		return http.StatusConflict
+	}
+	return 0
+}
+
+// ExecuteBackup triggers an immediate execution of a backup configuration. Volume snapshots are
+// only taken by executions requested by a user, since they need access to the member cluster.
+func ExecuteBackup(ctx context.Context, backupID string) error {
+	_, err := triggerBackup(ctx, backupID, nil)
+	return err
+}
+
+// triggerBackup sets the execution trigger on the StatefulMigration CR of a backup and returns the updated CR.
+// record, when set, changes the CR in the same update as the trigger.
+func triggerBackup(ctx context.Context, backupID string, record func(sm *unstructured.Unstructured)) (*unstructured.Unstructured, error) {
+	service, err := backupService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get resources", "cluster", clusterName, "type", resourceType)
-		common.Fail(c, err)
-		return
+		return nil, err
 	}
 
-	common.Success(c, map[string]interface{}{
-		"resources": resources,
-		"total":     len(resources),
+	updated, err := service.Execute(ctx, backupID, func(sm *unstructured.Unstructured) error {
+		// Maintenance windows and blackout periods also apply to manual executions
+		if err := executionWindowsFromAnnotations(sm).checkAllowed(time.Now()); err != nil {
+			klog.InfoS("Backup execution rejected", "backupID", backupID, "reason", err.Error())
+			return err
+		}
+		if err := checkNotInMaintenance(ctx, sourceClusters(sm)...); err != nil {
+			klog.InfoS("Backup execution rejected", "backupID", backupID, "reason", err.Error())
+			return err
+		}
+		if record != nil {
+			record(sm)
+		}
+		return nil
 	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to trigger backup execution", "backupID", backupID)
+		return nil, err
+	}
+
+	// The manifests of the workload are exported with the checkpoint. Exporting is best effort, recoveries
+	// fall back to the manifests of an earlier execution or to the source cluster.
+	if _, err := exportBackupManifests(ctx, statefulMigrationToBackup(updated)); err != nil {
+		klog.ErrorS(err, "Failed to export backup manifests", "backupID", backupID)
+	}
+	return updated, nil
 }
 
 // Helper functions
 
 func statefulMigrationToBackup(sm *unstructured.Unstructured) BackupConfiguration {
 	// Extract information from StatefulMigration CR and convert to BackupConfiguration
-	// Use direct field access instead of NestedMap to avoid deep copy issues with string slices
 	backup := BackupConfiguration{
 		ID:        sm.GetLabels()["backup-id"],
 		Name:      sm.GetName(),
 		Status:    "Active", // Default status
 		CreatedAt: sm.GetCreationTimestamp().Format(time.RFC3339),
 		UpdatedAt: sm.GetCreationTimestamp().Format(time.RFC3339),
+		ManagedBy: migration.ManagedBy(sm),
 	}
-
-	// Extract other fields from spec using direct field access
-	if clusters, found, _ := unstructured.NestedStringSlice(sm.Object, "spec", "sourceClusters"); found {
-		backup.Cluster = strings.Join(clusters, ",")
-	}
-	if resourceType, found, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "kind"); found {
-		backup.ResourceType = resourceType
-	}
-	if resourceName, found, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "name"); found {
-		backup.ResourceName = resourceName
+	if backup.ManagedBy == migration.ManagedByGitOps {
+		backup.GitOpsApplication = migration.GitOpsApplication(sm)
 	}
-	if namespace, found, _ := unstructured.NestedString(sm.Object, "spec", "resourceRef", "namespace"); found {
-		backup.Namespace = namespace
+	if backup.ID == "" {
+		// CRs written in Git may leave out the label, their name is the one the dashboard would give
+		backup.ID = migration.Backup.ID(sm.GetName())
 	}
-	if repository, found, _ := unstructured.NestedString(sm.Object, "spec", "registry", "repository"); found {
-		backup.Repository = repository
+
+	spec := StatefulMigrationSpec{}
+	if decoded, err := decodeStatefulMigration(sm); err != nil {
+		klog.V(4).InfoS("Failed to decode StatefulMigration", "name", sm.GetName(), "error", err)
+	} else {
+		spec = decoded.Spec
 	}
 
+	backup.Cluster = strings.Join(spec.SourceClusters, ",")
+	backup.ResourceType = spec.ResourceRef.Kind
+	backup.ResourceName = spec.ResourceRef.Name
+	backup.Namespace = spec.ResourceRef.Namespace
+
 	// Extract registry info
-	if registrySecretName, found, _ := unstructured.NestedString(sm.Object, "spec", "registry", "secretRef", "name"); found {
-		registry, _ := getRegistryByName(registrySecretName)
-		backup.Registry = RegistryInfo{
-			ID:       registry.ID,
-			Name:     registry.Name,
-			Registry: registry.Registry,
+	if spec.Registry != nil {
+		backup.Repository = spec.Registry.Repository
+		if spec.Registry.SecretRef != nil {
+			registry, _ := getRegistryByName(spec.Registry.SecretRef.Name)
+			backup.Registry = RegistryInfo{
+				ID:       registry.ID,
+				Name:     registry.Name,
+				Registry: registry.Registry,
+			}
 		}
 	}
 
-	// Extract schedule info
-	if scheduleValue, found, _ := unstructured.NestedString(sm.Object, "spec", "schedule"); found {
+	// Extract storage backend info
+	if spec.Storage != nil {
+		backup.Storage = &StorageBackendInfo{
+			ID:   sm.GetAnnotations()[storageBackendAnnotation],
+			Type: spec.Storage.Type,
+		}
+		if backend, err := getStorageBackendByID(backup.Storage.ID); err == nil {
+			backup.Storage.Name = backend.Name
+		}
+		if path := spec.Storage.Path(); path != "" {
+			backup.Repository = path
+		}
+	}
+
+	backup.Retention = retentionFromAnnotations(sm)
+	backup.VolumeSnapshots = volumeSnapshotPolicyFromAnnotations(sm)
+	backup.LastGC = sm.GetAnnotations()[lastGCAnnotation]
+	backup.TemplateID = backupTemplateID(sm)
+	backup.Containers = spec.Containers
+	backup.Encryption = backupEncryption(sm, spec)
+	backup.SignCheckpoints = signsCheckpoints(sm)
+	backup.Throttle = spec.Throttle
+	backup.Retry = retryPolicyFromAnnotations(sm)
+
+	// Extract schedule info, schedules suspended while a cluster is in maintenance are disabled
+	if spec.Schedule != "" {
 		backup.Schedule = ScheduleConfig{
-			Type:    "cron",
-			Value:   scheduleValue,
-			Enabled: true,
+			Type:             "cron",
+			Value:            spec.Schedule,
+			Enabled:          true,
+			ExecutionWindows: executionWindowsFromAnnotations(sm),
+		}
+	} else if suspended := sm.GetAnnotations()[suspendedScheduleAnnotation]; suspended != "" {
+		backup.Schedule = ScheduleConfig{
+			Type:             "cron",
+			Value:            suspended,
+			ExecutionWindows: executionWindowsFromAnnotations(sm),
 		}
 	}
 
 	return backup
 }
 
-func createStatefulMigrationCR(backupID string, req CreateBackupRequest, registry RegistryCredentials) *unstructured.Unstructured {
+func createStatefulMigrationCR(backupID string, req CreateBackupRequest, registry RegistryCredentials, storage *StorageBackend) *unstructured.Unstructured {
 	sm := &unstructured.Unstructured{}
-	sm.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "migration.dcnlab.com",
-		Version: "v1",
-		Kind:    "StatefulMigration",
-	})
+	sm.SetGroupVersionKind(migration.Backup.Resource.GroupVersion().WithKind("StatefulMigration"))
 
-	sm.SetName(fmt.Sprintf("backup-%s", backupID))
+	sm.SetName(migration.Backup.Name(backupID))
 	sm.SetNamespace(defaultNamespace)
 
 	// Set labels
 	sm.SetLabels(map[string]string{
-		"app":       "backup-migration",
+		"app":       migration.Backup.App,
 		"backup-id": backupID,
 		"type":      "backup",
 	})
 
 	// Set annotations
-	sm.SetAnnotations(map[string]string{
+	annotations := map[string]string{
 		"backup.dcnlab.com/created-at": time.Now().Format(time.RFC3339),
-	})
-
-	// Convert schedule selection to cron if needed
-	cronExpression := req.Schedule.Value
-	if req.Schedule.Type == "selection" {
-		cronExpression = selectionToCron(req.Schedule.Value)
+		migration.ManagedByAnnotation:  migration.ManagedByDashboard,
 	}
-
-	// Determine API version based on resource type
-	var apiVersion string
-	switch strings.ToLower(req.ResourceType) {
-	case "pod":
-		apiVersion = "v1"
-	case "statefulset":
-		apiVersion = "apps/v1"
-	default:
-		apiVersion = "v1" // Default fallback
+	if storage != nil {
+		annotations[storageBackendAnnotation] = storage.ID
 	}
+	sm.SetAnnotations(annotations)
+	setRetentionAnnotations(sm, req.Retention)
+	setRetryPolicyAnnotation(sm, req.Retry)
+	setExecutionWindowsAnnotation(sm, req.Schedule.ExecutionWindows)
+	setVolumeSnapshotAnnotation(sm, req.VolumeSnapshots)
+	setSignCheckpointsAnnotation(sm, req.SignCheckpoints)
 
 	// Create spec according to StatefulMigration CRD format
 	spec := map[string]interface{}{
 		"sourceClusters": []string{req.Cluster},
 		"resourceRef": map[string]interface{}{
-			"apiVersion": apiVersion,
+			"apiVersion": migration.ResourceAPIVersion(req.ResourceType),
 			"kind":       req.ResourceType,
 			"name":       req.ResourceName,
 			"namespace":  req.Namespace,
 		},
-		"registry": map[string]interface{}{
+		"schedule": migration.CronExpression(req.Schedule.Type, req.Schedule.Value),
+	}
+	if len(req.Containers) > 0 {
+		spec["containers"] = req.Containers
+	}
+	if req.Throttle != nil && !req.Throttle.IsZero() {
+		spec["throttle"] = req.Throttle.Spec()
+	}
+
+	if storage != nil {
+		spec["storage"] = storageBackendToSpec(*storage, req.Repository)
+	} else {
+		spec["registry"] = map[string]interface{}{
 			"url":        registry.Registry, // Registry URL is required
 			"repository": req.Repository,
 			"secretRef": map[string]interface{}{
 				"name": fmt.Sprintf("%s-%s", registrySecretPrefix, req.RegistryID),
 			},
-		},
-		"schedule": cronExpression, // Should be a string (cron expression)
+		}
 	}
 
 	sm.Object = map[string]interface{}{
-		"apiVersion": "migration.dcnlab.com/v1",
+		"apiVersion": migration.Backup.Resource.GroupVersion().String(),
 		"kind":       "StatefulMigration",
 		"metadata":   sm.Object["metadata"],
 		"spec":       spec,
@@ -496,6 +817,8 @@ func updateStatefulMigrationCR(sm *unstructured.Unstructured, req UpdateBackupRe
 	if req.Cluster != "" {
 		spec["sourceClusters"] = []string{req.Cluster}
 	}
+	setRetentionAnnotations(sm, req.Retention)
+	setRetryPolicyAnnotation(sm, req.Retry)
 
 	// Update resourceRef
 	if req.ResourceType != "" || req.ResourceName != "" || req.Namespace != "" {
@@ -504,17 +827,7 @@ func updateStatefulMigrationCR(sm *unstructured.Unstructured, req UpdateBackupRe
 			resourceRef = make(map[string]interface{})
 		}
 		if req.ResourceType != "" {
-			// Determine API version based on resource type
-			var apiVersion string
-			switch strings.ToLower(req.ResourceType) {
-			case "pod":
-				apiVersion = "v1"
-			case "statefulset":
-				apiVersion = "apps/v1"
-			default:
-				apiVersion = "v1" // Default fallback
-			}
-			resourceRef["apiVersion"] = apiVersion
+			resourceRef["apiVersion"] = migration.ResourceAPIVersion(req.ResourceType)
 			resourceRef["kind"] = req.ResourceType
 		}
 		if req.ResourceName != "" {
@@ -526,8 +839,24 @@ func updateStatefulMigrationCR(sm *unstructured.Unstructured, req UpdateBackupRe
 		spec["resourceRef"] = resourceRef
 	}
 
-	// Update registry
-	if req.RegistryID != "" || req.Repository != "" {
+	// Switch to a storage backend, replacing the registry
+	storageBackendID := req.StorageBackendID
+	if storageBackendID == "" && req.RegistryID == "" && spec["storage"] != nil {
+		storageBackendID = sm.GetAnnotations()[storageBackendAnnotation]
+	}
+	if storageBackendID != "" && (req.StorageBackendID != "" || req.Repository != "") {
+		backend, err := getStorageBackendByID(storageBackendID)
+		if err == nil {
+			spec["storage"] = storageBackendToSpec(backend, req.Repository)
+			delete(spec, "registry")
+			annotations := sm.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[storageBackendAnnotation] = backend.ID
+			sm.SetAnnotations(annotations)
+		}
+	} else if req.RegistryID != "" || (req.Repository != "" && storageBackendID == "") {
 		registry, _, _ := unstructured.NestedMap(spec, "registry")
 		if registry == nil {
 			registry = make(map[string]interface{})
@@ -545,18 +874,44 @@ func updateStatefulMigrationCR(sm *unstructured.Unstructured, req UpdateBackupRe
 				"name": fmt.Sprintf("%s-%s", registrySecretPrefix, req.RegistryID),
 			}
 			registry["secretRef"] = secretRef
+			delete(spec, "storage")
+			annotations := sm.GetAnnotations()
+			delete(annotations, storageBackendAnnotation)
+			sm.SetAnnotations(annotations)
 		}
 		spec["registry"] = registry
 	}
 
 	if req.Schedule.Type != "" {
-		var cronExpression string
-		if req.Schedule.Type == "selection" {
-			cronExpression = selectionToCron(req.Schedule.Value)
+		schedule := migration.CronExpression(req.Schedule.Type, req.Schedule.Value)
+		// A suspended schedule is changed where it is kept, it is resumed when the maintenance ends
+		if annotations := sm.GetAnnotations(); annotations[suspendedScheduleAnnotation] != "" {
+			annotations[suspendedScheduleAnnotation] = schedule
+			sm.SetAnnotations(annotations)
+		} else {
+			spec["schedule"] = schedule
+		}
+	}
+	if req.Schedule.MaintenanceWindows != nil || req.Schedule.Blackouts != nil {
+		setExecutionWindowsAnnotation(sm, req.Schedule.ExecutionWindows)
+	}
+	setVolumeSnapshotAnnotation(sm, req.VolumeSnapshots)
+	if req.SignCheckpoints != nil {
+		setSignCheckpointsAnnotation(sm, *req.SignCheckpoints)
+	}
+	if req.Containers != nil {
+		if len(req.Containers) > 0 {
+			spec["containers"] = req.Containers
 		} else {
-			cronExpression = req.Schedule.Value
+			delete(spec, "containers")
+		}
+	}
+	if req.Throttle != nil {
+		if req.Throttle.IsZero() {
+			delete(spec, "throttle")
+		} else {
+			spec["throttle"] = req.Throttle.Spec()
 		}
-		spec["schedule"] = cronExpression // Should be a string, not an object
 	}
 
 	// Update timestamp
@@ -571,34 +926,6 @@ func updateStatefulMigrationCR(sm *unstructured.Unstructured, req UpdateBackupRe
 	return sm
 }
 
-func generateBackupID(name string) string {
-	return fmt.Sprintf("%s-%d", strings.ToLower(strings.ReplaceAll(name, " ", "-")), time.Now().Unix())
-}
-
-func selectionToCron(selection string) string {
-	switch selection {
-	case "5m":
-		return "*/5 * * * *"
-	case "15m":
-		return "*/15 * * * *"
-	case "30m":
-		return "*/30 * * * *"
-	case "1h":
-		return "0 * * * *"
-	default:
-		return "0 0 * * *" // Daily by default
-	}
-}
-
-func validateCronExpression(cron string) error {
-	// Basic cron validation - you might want to use a proper cron library
-	parts := strings.Fields(cron)
-	if len(parts) != 5 {
-		return fmt.Errorf("cron expression must have 5 fields")
-	}
-	return nil
-}
-
 func getRegistryByName(secretName string) (RegistryCredentials, error) {
 	karmadaDynamicClient, err := getKarmadaDynamicClient()
 	if err != nil {
@@ -663,118 +990,15 @@ func getRegistryByID(registryID string) (RegistryCredentials, error) {
 	return secretToRegistry(secret), nil
 }
 
-func getMemberClusterClient(c *gin.Context, clusterName string) (interface{}, error) {
-	// Get dynamic client for member cluster
-	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get dynamic client for member cluster %s: %v", clusterName, err)
-	}
-	return dynamicClient, nil
-}
-
-func getPodsInCluster(client interface{}, namespace string) ([]map[string]interface{}, error) {
-	dynamicClient, ok := client.(dynamic.Interface)
-	if !ok {
-		return nil, fmt.Errorf("invalid client type for pods")
-	}
-
-	// Define Pod GVR
-	podGVR := schema.GroupVersionResource{
-		Group:    "",
-		Version:  "v1",
-		Resource: "pods",
-	}
-
-	// List pods in the specified namespace (or all namespaces if empty)
-	var unstructuredList *unstructured.UnstructuredList
-	var err error
-
-	if namespace != "" {
-		unstructuredList, err = dynamicClient.Resource(podGVR).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
-	} else {
-		unstructuredList, err = dynamicClient.Resource(podGVR).List(context.TODO(), metav1.ListOptions{})
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %v", err)
-	}
-
-	resources := make([]map[string]interface{}, 0, len(unstructuredList.Items))
-	for _, item := range unstructuredList.Items {
-		pod := map[string]interface{}{
-			"name":       item.GetName(),
-			"namespace":  item.GetNamespace(),
-			"kind":       "Pod",
-			"apiVersion": "v1",
-		}
-
-		// Get pod status
-		if status, found, _ := unstructured.NestedString(item.Object, "status", "phase"); found {
-			pod["status"] = status
-		}
-
-		resources = append(resources, pod)
-	}
-
-	return resources, nil
-}
-
-func getStatefulSetsInCluster(client interface{}, namespace string) ([]map[string]interface{}, error) {
-	dynamicClient, ok := client.(dynamic.Interface)
-	if !ok {
-		return nil, fmt.Errorf("invalid client type for statefulsets")
-	}
-
-	// Define StatefulSet GVR
-	statefulSetGVR := schema.GroupVersionResource{
-		Group:    "apps",
-		Version:  "v1",
-		Resource: "statefulsets",
-	}
-
-	// List statefulsets in the specified namespace (or all namespaces if empty)
-	var unstructuredList *unstructured.UnstructuredList
-	var err error
-
-	if namespace != "" {
-		unstructuredList, err = dynamicClient.Resource(statefulSetGVR).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
-	} else {
-		unstructuredList, err = dynamicClient.Resource(statefulSetGVR).List(context.TODO(), metav1.ListOptions{})
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to list statefulsets: %v", err)
-	}
-
-	resources := make([]map[string]interface{}, 0, len(unstructuredList.Items))
-	for _, item := range unstructuredList.Items {
-		statefulSet := map[string]interface{}{
-			"name":       item.GetName(),
-			"namespace":  item.GetNamespace(),
-			"kind":       "StatefulSet",
-			"apiVersion": "apps/v1",
-		}
-
-		// Get statefulset status
-		if replicas, found, _ := unstructured.NestedInt64(item.Object, "status", "replicas"); found {
-			statefulSet["replicas"] = replicas
-		}
-		if readyReplicas, found, _ := unstructured.NestedInt64(item.Object, "status", "readyReplicas"); found {
-			statefulSet["readyReplicas"] = readyReplicas
-		}
-
-		resources = append(resources, statefulSet)
-	}
-
-	return resources, nil
-}
-
 // Register backup routes
 func init() {
+	router.Validator().RegisterStructValidation(validateScheduleConfig, ScheduleConfig{})
+
 	r := router.V1()
 
 	// Backup management routes
 	backupGroup := r.Group("/backup")
+	backupGroup.Use(idempotencyMiddleware())
 	{
 		backupGroup.GET("", handleGetBackups)
 		backupGroup.POST("", handleCreateBackup)
@@ -782,6 +1006,14 @@ func init() {
 		backupGroup.PUT("/:id", handleUpdateBackup)
 		backupGroup.DELETE("/:id", handleDeleteBackup)
 		backupGroup.POST("/:id/execute", handleExecuteBackup)
+		backupGroup.GET("/:id/events", handleGetBackupEvents)
+		backupGroup.GET("/:id/snapshots", handleGetBackupVolumeSnapshots)
+		backupGroup.POST("/:id/snapshots", handleCreateBackupVolumeSnapshots)
+		backupGroup.GET("/:id/manifests", handleGetBackupManifests)
+		backupGroup.POST("/:id/manifests", handleExportBackupManifests)
+		backupGroup.GET("/:id/manifests/:name", handleDownloadBackupManifests)
 		backupGroup.GET("/clusters/:cluster/resources", handleGetResourcesInCluster)
+		backupGroup.GET("/clusters/:cluster/namespaces", handleGetNamespacesInCluster)
+		backupGroup.POST("/compatibility-check", handleCompatibilityCheck)
 	}
 }
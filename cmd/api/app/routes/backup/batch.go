@@ -0,0 +1,349 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// batchExecutionJobType is the job that executes several backup configurations at once
+const batchExecutionJobType = "backup-batch-execution"
+
+const (
+	// defaultBatchConcurrency is how many backups of a batch are triggered at the same time unless requested otherwise
+	defaultBatchConcurrency = 5
+	// maxBatchConcurrency bounds the requested concurrency
+	maxBatchConcurrency = 20
+)
+
+// Results of a backup of a batch execution
+const (
+	batchResultTriggered = "Triggered"
+	batchResultFailed    = "Failed"
+)
+
+// BatchExecutionRequest executes the listed backup configurations, or those matching the selector, immediately
+type BatchExecutionRequest struct {
+	BackupIDs []string                `json:"backupIds" binding:"required_without=Selector,omitempty,dive,required"`
+	Selector  *BatchExecutionSelector `json:"selector" binding:"required_without=BackupIDs,omitempty"`
+	// Concurrency is how many backups are triggered at the same time
+	Concurrency int `json:"concurrency" binding:"omitempty,min=1"`
+}
+
+// BatchExecutionSelector selects backup configurations by the cluster and namespace of their workload and by
+// the labels of their StatefulMigration CR. Unset fields match every configuration.
+type BatchExecutionSelector struct {
+	Cluster       string            `json:"cluster" binding:"omitempty,cluster"`
+	Namespace     string            `json:"namespace" binding:"omitempty,dns1123label"`
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+}
+
+// matches reports whether a backup configuration is selected
+func (s BatchExecutionSelector) matches(backup BackupConfiguration, crLabels map[string]string) bool {
+	if s.Cluster != "" && !containsString(strings.Split(backup.Cluster, ","), s.Cluster) {
+		return false
+	}
+	if s.Namespace != "" && backup.Namespace != s.Namespace {
+		return false
+	}
+	return labels.SelectorFromSet(s.LabelSelector).Matches(labels.Set(crLabels))
+}
+
+// BatchExecutionResult is the result of starting a batch execution
+type BatchExecutionResult struct {
+	Message   string   `json:"message"`
+	JobID     string   `json:"jobId"`
+	BackupIDs []string `json:"backupIds"`
+}
+
+// BatchExecutionProgress is the progress of a batch execution
+type BatchExecutionProgress struct {
+	Job       *jobs.Job `json:"job"`
+	Total     int       `json:"total"`
+	Triggered int       `json:"triggered"`
+	Failed    int       `json:"failed"`
+	Pending   int       `json:"pending"`
+	// Results are the outcomes of the backups that were triggered or failed, by backup ID
+	Results map[string]BatchBackupResult `json:"results"`
+}
+
+// BatchBackupResult is the outcome of a backup of a batch execution
+type BatchBackupResult struct {
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// batchExecutionParams are the params of a batch execution job
+type batchExecutionParams struct {
+	BackupIDs   []string `json:"backupIds"`
+	Concurrency int      `json:"concurrency"`
+}
+
+// batchExecutionState records the outcome of each backup. A retry only triggers the backups that failed.
+type batchExecutionState struct {
+	Results map[string]BatchBackupResult `json:"results"`
+}
+
+// handleExecuteBackupBatch starts a job that triggers an immediate checkpoint of several backup configurations.
+// Its progress is returned by GET /backup/execute-batch/:id.
+func handleExecuteBackupBatch(c *gin.Context) {
+	var req BatchExecutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	concurrency := req.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	if concurrency > maxBatchConcurrency {
+		concurrency = maxBatchConcurrency
+	}
+
+	backups, err := selectBatchBackups(c, req)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if len(backups) == 0 {
+		common.FailWithStatus(c, errors.New("no backup configuration matches the request"), http.StatusBadRequest)
+		return
+	}
+
+	backupIDs := make([]string, 0, len(backups))
+	var clusters []string
+	for _, backup := range backups {
+		backupIDs = append(backupIDs, backup.ID)
+		clusters = append(clusters, strings.Split(backup.Cluster, ",")...)
+	}
+	audit.SetClusters(c, clusters...)
+
+	job, err := jobs.Enqueue(c, batchExecutionJobType, batchExecutionParams{
+		BackupIDs:   backupIDs,
+		Concurrency: concurrency,
+	}, jobs.EnqueueOptions{
+		Keys:      backupIDs,
+		CreatedBy: utilauth.GetAuthenticatedUser(c),
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to enqueue batch backup execution", "backups", backupIDs)
+		common.Fail(c, err)
+		return
+	}
+
+	common.Success(c, BatchExecutionResult{
+		Message:   fmt.Sprintf("Execution of %d backups started", len(backupIDs)),
+		JobID:     job.ID,
+		BackupIDs: backupIDs,
+	})
+}
+
+// selectBatchBackups returns the backup configurations of a batch execution. Listed IDs must all exist.
+func selectBatchBackups(ctx context.Context, req BatchExecutionRequest) ([]BackupConfiguration, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, err
+	}
+	items, err := service.List(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list StatefulMigration CRs")
+		return nil, err
+	}
+
+	byID := map[string]BackupConfiguration{}
+	var selected []BackupConfiguration
+	for i := range items {
+		backup := statefulMigrationToBackup(&items[i])
+		byID[backup.ID] = backup
+		if req.Selector != nil && req.Selector.matches(backup, items[i].GetLabels()) {
+			selected = append(selected, backup)
+		}
+	}
+	if req.Selector != nil {
+		sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+		return selected, nil
+	}
+
+	seen := map[string]bool{}
+	for _, id := range req.BackupIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		backup, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("backup configuration %s not found", id)
+		}
+		selected = append(selected, backup)
+	}
+	return selected, nil
+}
+
+// runBatchExecutionJob triggers the backups of a batch, concurrency at a time. Backups that fail do not stop the
+// others; the job fails once all were attempted so that a retry triggers the failed ones again.
+func runBatchExecutionJob(ctx context.Context, run *jobs.Run) error {
+	var params batchExecutionParams
+	if err := run.DecodeParams(&params); err != nil {
+		return err
+	}
+	if params.Concurrency <= 0 {
+		params.Concurrency = defaultBatchConcurrency
+	}
+	var state batchExecutionState
+	if _, err := run.DecodeState(&state); err != nil {
+		return err
+	}
+	if state.Results == nil {
+		state.Results = map[string]BatchBackupResult{}
+	}
+
+	var mu sync.Mutex
+	var saveErr error
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, params.Concurrency)
+	for _, backupID := range params.BackupIDs {
+		if state.Results[backupID].Result == batchResultTriggered {
+			continue
+		}
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(backupID string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+			result := BatchBackupResult{Result: batchResultTriggered}
+			if err := ExecuteBackup(ctx, backupID); err != nil {
+				result = BatchBackupResult{Result: batchResultFailed, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			state.Results[backupID] = result
+			if err := run.SaveState(ctx, state, batchExecutionMessage(params, state)); err != nil && saveErr == nil {
+				saveErr = err
+			}
+		}(backupID)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if saveErr != nil {
+		return saveErr
+	}
+	var failed []string
+	for _, backupID := range params.BackupIDs {
+		if state.Results[backupID].Result != batchResultTriggered {
+			failed = append(failed, backupID)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to trigger backups %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// batchExecutionMessage describes the progress of a batch execution
+func batchExecutionMessage(params batchExecutionParams, state batchExecutionState) string {
+	progress := batchProgress(params, state)
+	return fmt.Sprintf("%d of %d backups triggered, %d failed", progress.Triggered, progress.Total, progress.Failed)
+}
+
+// batchProgress counts the outcomes of the backups of a batch execution
+func batchProgress(params batchExecutionParams, state batchExecutionState) BatchExecutionProgress {
+	progress := BatchExecutionProgress{Total: len(params.BackupIDs), Results: state.Results}
+	if progress.Results == nil {
+		progress.Results = map[string]BatchBackupResult{}
+	}
+	for _, backupID := range params.BackupIDs {
+		switch state.Results[backupID].Result {
+		case batchResultTriggered:
+			progress.Triggered++
+		case batchResultFailed:
+			progress.Failed++
+		default:
+			progress.Pending++
+		}
+	}
+	return progress
+}
+
+// handleGetBackupBatch returns the progress of a batch execution
+func handleGetBackupBatch(c *gin.Context) {
+	job, err := jobs.Get(c, c.Param("id"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if job.Type != batchExecutionJobType {
+		common.FailWithStatus(c, fmt.Errorf("job %s is not a batch backup execution", job.ID), http.StatusNotFound)
+		return
+	}
+
+	var params batchExecutionParams
+	if err := json.Unmarshal(job.Params, &params); err != nil {
+		common.Fail(c, fmt.Errorf("invalid params of job %s: %v", job.ID, err))
+		return
+	}
+	var state batchExecutionState
+	if len(job.State) > 0 {
+		if err := json.Unmarshal(job.State, &state); err != nil {
+			common.Fail(c, fmt.Errorf("invalid state of job %s: %v", job.ID, err))
+			return
+		}
+	}
+
+	progress := batchProgress(params, state)
+	progress.Job = job
+	common.Success(c, progress)
+}
+
+func init() {
+	jobs.Register(batchExecutionJobType, runBatchExecutionJob)
+
+	r := router.V1()
+
+	batchGroup := r.Group("/backup/execute-batch")
+	batchGroup.Use(idempotencyMiddleware())
+	{
+		batchGroup.POST("", handleExecuteBackupBatch)
+		batchGroup.GET("/:id", handleGetBackupBatch)
+	}
+}
@@ -0,0 +1,496 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	bundleAPIVersion = "ml-platform.io/v1"
+	bundleKind       = "BackupBundle"
+	maxBundleSize    = 2 << 20
+)
+
+// Import outcomes of a backup configuration
+const (
+	importStatusCreated     = "created"
+	importStatusWouldCreate = "would-create"
+	importStatusSkipped     = "skipped"
+	importStatusInvalid     = "invalid"
+	importStatusFailed      = "failed"
+)
+
+// backupIDSuffix is the creation timestamp migration.GenerateID appends to the name
+var backupIDSuffix = regexp.MustCompile(`-[0-9]+$`)
+
+// BackupBundle is a portable set of backup configurations. Registries and storage backends are referenced by
+// name and exported without credentials, they must exist in the target environment before importing.
+type BackupBundle struct {
+	APIVersion      string                 `json:"apiVersion"`
+	Kind            string                 `json:"kind"`
+	ExportedAt      string                 `json:"exportedAt,omitempty"`
+	Registries      []BundleRegistry       `json:"registries,omitempty"`
+	StorageBackends []BundleStorageBackend `json:"storageBackends,omitempty"`
+	Backups         []BundleBackup         `json:"backups"`
+}
+
+// BundleRegistry is a registry referenced by the configurations of a bundle
+type BundleRegistry struct {
+	Name     string `json:"name"`
+	Registry string `json:"registry"`
+}
+
+// BundleStorageBackend is a storage backend referenced by the configurations of a bundle
+type BundleStorageBackend struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Endpoint   string `json:"endpoint,omitempty"`
+	Bucket     string `json:"bucket,omitempty"`
+	Region     string `json:"region,omitempty"`
+	ClaimName  string `json:"claimName,omitempty"`
+	PathPrefix string `json:"pathPrefix,omitempty"`
+}
+
+// BundleBackup is a backup configuration of a bundle
+type BundleBackup struct {
+	Name         string `json:"name"`
+	Cluster      string `json:"cluster"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	Namespace    string `json:"namespace"`
+	// Registry is the name of the registry, or StorageBackend the name of the storage backend
+	Registry       string `json:"registry,omitempty"`
+	StorageBackend string `json:"storageBackend,omitempty"`
+	// Repository is the registry repository, or the path below the prefix of the storage backend
+	Repository      string                `json:"repository,omitempty"`
+	Schedule        ScheduleConfig        `json:"schedule"`
+	Retention       *RetentionPolicy      `json:"retention,omitempty"`
+	VolumeSnapshots *VolumeSnapshotPolicy `json:"volumeSnapshots,omitempty"`
+	Containers      []string              `json:"containers,omitempty"`
+	// EncryptionKey is the name of the encryption key, the key itself is registered in each environment
+	EncryptionKey   string `json:"encryptionKey,omitempty"`
+	SignCheckpoints bool   `json:"signCheckpoints,omitempty"`
+}
+
+// ImportResult is the outcome of importing a backup configuration of a bundle
+type ImportResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	BackupID string `json:"backupId,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// listRegistries returns the registries with their credentials
+func listRegistries(ctx context.Context) ([]RegistryCredentials, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+	secretsUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=backup-registry",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registries: %v", err)
+	}
+	registries := make([]RegistryCredentials, 0, len(secretsUnstructured.Items))
+	for i := range secretsUnstructured.Items {
+		secret := &corev1.Secret{}
+		if err := convertUnstructuredToTyped(&secretsUnstructured.Items[i], secret); err != nil {
+			klog.ErrorS(err, "Failed to convert secret", "secretName", secretsUnstructured.Items[i].GetName())
+			continue
+		}
+		registries = append(registries, secretToRegistry(secret))
+	}
+	return registries, nil
+}
+
+// listStorageBackends returns the storage backends with their credentials
+func listStorageBackends(ctx context.Context) ([]StorageBackend, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+	secretsUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=backup-storage",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage backends: %v", err)
+	}
+	backends := make([]StorageBackend, 0, len(secretsUnstructured.Items))
+	for i := range secretsUnstructured.Items {
+		secret := &corev1.Secret{}
+		if err := convertUnstructuredToTyped(&secretsUnstructured.Items[i], secret); err != nil {
+			klog.ErrorS(err, "Failed to convert secret", "secretName", secretsUnstructured.Items[i].GetName())
+			continue
+		}
+		backends = append(backends, secretToStorageBackend(secret))
+	}
+	return backends, nil
+}
+
+// backupToBundle converts a backup configuration, dropping the environment specific IDs
+func backupToBundle(backup BackupConfiguration, backends map[string]StorageBackend) BundleBackup {
+	entry := BundleBackup{
+		Name:            backupIDSuffix.ReplaceAllString(backup.ID, ""),
+		Cluster:         backup.Cluster,
+		ResourceType:    backup.ResourceType,
+		ResourceName:    backup.ResourceName,
+		Namespace:       backup.Namespace,
+		Repository:      backup.Repository,
+		Schedule:        backup.Schedule,
+		Retention:       backup.Retention,
+		VolumeSnapshots: backup.VolumeSnapshots,
+		Containers:      backup.Containers,
+		SignCheckpoints: backup.SignCheckpoints,
+	}
+	if entry.Name == "" {
+		entry.Name = strings.TrimPrefix(backup.Name, "backup-")
+	}
+	if backup.Storage != nil {
+		backend := backends[backup.Storage.ID]
+		entry.StorageBackend = backend.Name
+		// The storage path includes the prefix of the backend, which is added again on import
+		if prefix := strings.Trim(backend.PathPrefix, "/"); prefix != "" {
+			entry.Repository = strings.TrimPrefix(strings.TrimPrefix(entry.Repository, prefix), "/")
+		}
+	} else {
+		entry.Registry = backup.Registry.Name
+	}
+	if backup.Encryption != nil {
+		entry.EncryptionKey = backup.Encryption.Name
+	}
+	return entry
+}
+
+// handleExportBackups returns the backup configurations as a bundle, all of them or those given by the
+// comma separated ids query parameter. format=json returns JSON instead of YAML.
+func handleExportBackups(c *gin.Context) {
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get dynamic client")
+		common.Fail(c, err)
+		return
+	}
+	unstructuredList, err := dynamicClient.Resource(statefulMigrationGVR).List(c, metav1.ListOptions{
+		LabelSelector: migration.Backup.ActiveLabelSelector(),
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list StatefulMigration CRs")
+		common.Fail(c, err)
+		return
+	}
+
+	selected := map[string]bool{}
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			selected[id] = true
+		}
+	}
+
+	backends, err := listStorageBackends(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	backendsByID := make(map[string]StorageBackend, len(backends))
+	for _, backend := range backends {
+		backendsByID[backend.ID] = backend
+	}
+
+	bundle := BackupBundle{
+		APIVersion: bundleAPIVersion,
+		Kind:       bundleKind,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Backups:    []BundleBackup{},
+	}
+	registries := map[string]bool{}
+	storageBackends := map[string]bool{}
+	for i := range unstructuredList.Items {
+		backup := statefulMigrationToBackup(&unstructuredList.Items[i])
+		if len(selected) > 0 && !selected[backup.ID] {
+			continue
+		}
+		delete(selected, backup.ID)
+		entry := backupToBundle(backup, backendsByID)
+		bundle.Backups = append(bundle.Backups, entry)
+
+		if entry.Registry != "" && !registries[entry.Registry] {
+			registries[entry.Registry] = true
+			bundle.Registries = append(bundle.Registries, BundleRegistry{Name: backup.Registry.Name, Registry: backup.Registry.Registry})
+		}
+		if entry.StorageBackend != "" && !storageBackends[entry.StorageBackend] {
+			storageBackends[entry.StorageBackend] = true
+			backend := backendsByID[backup.Storage.ID]
+			bundle.StorageBackends = append(bundle.StorageBackends, BundleStorageBackend{
+				Name:       backend.Name,
+				Type:       backend.Type,
+				Endpoint:   backend.Endpoint,
+				Bucket:     backend.Bucket,
+				Region:     backend.Region,
+				ClaimName:  backend.ClaimName,
+				PathPrefix: backend.PathPrefix,
+			})
+		}
+	}
+	for id := range selected {
+		common.Fail(c, fmt.Errorf("backup configuration %s not found", id))
+		return
+	}
+
+	filename := fmt.Sprintf("backup-bundle-%s", time.Now().Format("20060102"))
+	if c.Query("format") == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.JSON(http.StatusOK, bundle)
+		return
+	}
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.yaml"`, filename))
+	c.Data(http.StatusOK, "application/yaml", data)
+}
+
+// bundleEntryToRequest checks a bundle entry and resolves its registry or storage backend and its encryption key by name
+func bundleEntryToRequest(entry BundleBackup, bundle *BackupBundle, registries []RegistryCredentials, backends []StorageBackend, keys []EncryptionKey) (CreateBackupRequest, error) {
+	req := CreateBackupRequest{
+		Name:            entry.Name,
+		Cluster:         entry.Cluster,
+		ResourceType:    entry.ResourceType,
+		ResourceName:    entry.ResourceName,
+		Namespace:       entry.Namespace,
+		Repository:      entry.Repository,
+		Schedule:        entry.Schedule,
+		Retention:       entry.Retention,
+		VolumeSnapshots: entry.VolumeSnapshots,
+		Containers:      entry.Containers,
+		SignCheckpoints: entry.SignCheckpoints,
+	}
+	if req.Name == "" || req.Cluster == "" || req.ResourceName == "" || req.Namespace == "" {
+		return req, fmt.Errorf("name, cluster, resourceName and namespace are required")
+	}
+	if req.ResourceType != "pod" && req.ResourceType != "statefulset" {
+		return req, fmt.Errorf("resourceType must be pod or statefulset")
+	}
+	if req.Schedule.Type == "" || req.Schedule.Value == "" {
+		return req, fmt.Errorf("a schedule is required")
+	}
+	if entry.EncryptionKey != "" {
+		for _, key := range keys {
+			if key.Name == entry.EncryptionKey {
+				req.EncryptionKeyID = key.ID
+			}
+		}
+		if req.EncryptionKeyID == "" {
+			return req, fmt.Errorf("encryption key %s does not exist", entry.EncryptionKey)
+		}
+	}
+
+	switch {
+	case entry.StorageBackend != "":
+		for _, backend := range backends {
+			if backend.Name == entry.StorageBackend {
+				req.StorageBackendID = backend.ID
+				return req, nil
+			}
+		}
+		return req, fmt.Errorf("storage backend %s does not exist", entry.StorageBackend)
+	case entry.Registry != "":
+		if req.Repository == "" {
+			return req, fmt.Errorf("a repository is required with a registry")
+		}
+		// Registries are matched by name, then by the URL the bundle gives for the name
+		url := ""
+		for _, registry := range bundle.Registries {
+			if registry.Name == entry.Registry {
+				url = registry.Registry
+			}
+		}
+		for _, registry := range registries {
+			if registry.Name == entry.Registry {
+				req.RegistryID = registry.ID
+				return req, nil
+			}
+		}
+		for _, registry := range registries {
+			if url != "" && registry.Registry == url {
+				req.RegistryID = registry.ID
+				return req, nil
+			}
+		}
+		return req, fmt.Errorf("registry %s does not exist", entry.Registry)
+	}
+	return req, fmt.Errorf("a registry or storage backend is required")
+}
+
+// handleImportBackups creates the backup configurations of a YAML or JSON bundle. All configurations are
+// validated first and none is created when one is invalid. Configurations of a workload that already has one
+// are skipped. dryRun=true only validates.
+func handleImportBackups(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBundleSize+1))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if len(body) > maxBundleSize {
+		common.FailWithStatus(c, fmt.Errorf("bundle exceeds %d bytes", maxBundleSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	bundle := &BackupBundle{}
+	// YAML is a superset of JSON, so both are accepted
+	if err := yaml.UnmarshalStrict(body, bundle); err != nil {
+		common.FailWithStatus(c, fmt.Errorf("invalid bundle: %v", err), http.StatusBadRequest)
+		return
+	}
+	if bundle.Kind != bundleKind || bundle.APIVersion != bundleAPIVersion {
+		common.FailWithStatus(c, fmt.Errorf("expected a %s %s", bundleAPIVersion, bundleKind), http.StatusBadRequest)
+		return
+	}
+	dryRun := c.Query("dryRun") == "true"
+
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get dynamic client")
+		common.Fail(c, err)
+		return
+	}
+	existing, err := dynamicClient.Resource(statefulMigrationGVR).List(c, metav1.ListOptions{
+		LabelSelector: "app=backup-migration",
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list StatefulMigration CRs")
+		common.Fail(c, err)
+		return
+	}
+	workloads := map[string]string{}
+	for i := range existing.Items {
+		backup := statefulMigrationToBackup(&existing.Items[i])
+		workloads[strings.Join([]string{backup.Cluster, strings.ToLower(backup.ResourceType), backup.Namespace, backup.ResourceName}, "/")] = backup.ID
+	}
+	registries, err := listRegistries(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	backends, err := listStorageBackends(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	keys, err := listEncryptionKeys(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	karmadaClient := client.InClusterKarmadaClient()
+
+	results := make([]ImportResult, len(bundle.Backups))
+	requests := make([]*CreateBackupRequest, len(bundle.Backups))
+	invalid := 0
+	for i, entry := range bundle.Backups {
+		results[i] = ImportResult{Name: entry.Name}
+		workload := strings.Join([]string{entry.Cluster, strings.ToLower(entry.ResourceType), entry.Namespace, entry.ResourceName}, "/")
+		if id, ok := workloads[workload]; ok {
+			results[i].Status = importStatusSkipped
+			results[i].BackupID = id
+			results[i].Error = "the workload already has a backup configuration"
+			continue
+		}
+		req, err := bundleEntryToRequest(entry, bundle, registries, backends, keys)
+		if err == nil {
+			if _, err = karmadaClient.ClusterV1alpha1().Clusters().Get(c, entry.Cluster, metav1.GetOptions{}); err != nil {
+				err = fmt.Errorf("cluster %s: %v", entry.Cluster, err)
+			}
+		}
+		if err == nil {
+			// Builds the CR to run the validation of a create request
+			_, err = newStatefulMigrationCR(c, req)
+		}
+		if err != nil {
+			results[i].Status = importStatusInvalid
+			results[i].Error = err.Error()
+			invalid++
+			continue
+		}
+		workloads[workload] = ""
+		requests[i] = &req
+		results[i].Status = importStatusWouldCreate
+	}
+
+	if dryRun || invalid > 0 {
+		common.Success(c, gin.H{
+			"dryRun":  dryRun,
+			"results": results,
+			"invalid": invalid,
+		})
+		return
+	}
+
+	klog.InfoS("Importing backup configurations", "count", len(bundle.Backups), "user", utilauth.GetAuthenticatedUser(c))
+	for i, req := range requests {
+		if req == nil {
+			continue
+		}
+		statefulMigration, err := newStatefulMigrationCR(c, *req)
+		if err == nil {
+			_, err = dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Create(c, statefulMigration, metav1.CreateOptions{})
+		}
+		if err != nil {
+			klog.ErrorS(err, "Failed to import backup configuration", "name", req.Name)
+			results[i].Status = importStatusFailed
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Status = importStatusCreated
+		results[i].BackupID = statefulMigration.GetLabels()["backup-id"]
+	}
+	common.Success(c, gin.H{
+		"dryRun":  false,
+		"results": results,
+	})
+}
+
+func init() {
+	r := router.V1()
+
+	backupGroup := r.Group("/backup")
+	backupGroup.Use(idempotencyMiddleware())
+	{
+		backupGroup.GET("/export", handleExportBackups)
+		backupGroup.POST("/import", handleImportBackups)
+	}
+}
@@ -0,0 +1,480 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const checkpointStoreTimeout = 30 * time.Second
+
+// checkpointArtifact is a single checkpoint stored in a registry or storage backend
+type checkpointArtifact struct {
+	Name      string    `json:"name"`
+	Digest    string    `json:"digest,omitempty"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// checkpointStore lists and deletes the checkpoints of one backup configuration
+type checkpointStore interface {
+	List(ctx context.Context) ([]checkpointArtifact, error)
+	Delete(ctx context.Context, artifact checkpointArtifact) error
+}
+
+// registryCheckpointStore manages checkpoint images through the registry HTTP API v2
+type registryCheckpointStore struct {
+	baseURL    string
+	repository string
+	username   string
+	password   string
+	httpClient *http.Client
+	token      string
+}
+
+func newRegistryCheckpointStore(registry RegistryCredentials, password, repository string) *registryCheckpointStore {
+	baseURL := strings.TrimRight(registry.Registry, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "https://" + baseURL
+	}
+	// Docker Hub serves the registry API from a different host
+	if baseURL == "https://docker.io" || baseURL == "https://index.docker.io" {
+		baseURL = "https://registry-1.docker.io"
+	}
+	return &registryCheckpointStore{
+		baseURL:    baseURL,
+		repository: strings.Trim(repository, "/"),
+		username:   registry.Username,
+		password:   password,
+		httpClient: &http.Client{Timeout: checkpointStoreTimeout},
+	}
+}
+
+// do sends a registry request, negotiating a bearer token when the registry asks for one
+func (s *registryCheckpointStore) do(ctx context.Context, method, path string, header http.Header) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if s.token != "" {
+			req.Header.Set("Authorization", "Bearer "+s.token)
+		} else if s.username != "" {
+			req.SetBasicAuth(s.username, s.password)
+		}
+		return s.httpClient.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || s.token != "" {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("registry rejected the credentials for %s", path)
+	}
+	if err := s.fetchToken(ctx, challenge); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+// fetchToken requests a bearer token from the realm announced in the WWW-Authenticate challenge
+func (s *registryCheckpointStore) fetchToken(ctx context.Context, challenge string) error {
+	params := map[string]string{}
+	for _, part := range strings.Split(challenge[len("bearer "):], ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return fmt.Errorf("registry auth challenge has no realm")
+	}
+
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull,delete", s.repository))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request registry token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request returned %s", resp.Status)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return fmt.Errorf("failed to parse registry token: %v", err)
+	}
+	s.token = tokenResp.Token
+	if s.token == "" {
+		s.token = tokenResp.AccessToken
+	}
+	return nil
+}
+
+// List returns one artifact per tag, with its manifest digest, total size and creation time
+func (s *registryCheckpointStore) List(ctx context.Context) ([]checkpointArtifact, error) {
+	resp, err := s.do(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/tags/list", s.repository), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing tags of %s returned %s", s.repository, resp.Status)
+	}
+
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to parse tag list: %v", err)
+	}
+
+	artifacts := make([]checkpointArtifact, 0, len(tags.Tags))
+	for _, tag := range tags.Tags {
+		artifact, err := s.inspect(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+func (s *registryCheckpointStore) inspect(ctx context.Context, tag string) (checkpointArtifact, error) {
+	header := http.Header{}
+	header.Set("Accept", strings.Join([]string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}, ", "))
+	resp, err := s.do(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/manifests/%s", s.repository, tag), header)
+	if err != nil {
+		return checkpointArtifact{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return checkpointArtifact{}, fmt.Errorf("getting manifest %s:%s returned %s", s.repository, tag, resp.Status)
+	}
+
+	var manifest struct {
+		Config struct {
+			Digest string `json:"digest"`
+			Size   int64  `json:"size"`
+		} `json:"config"`
+		Layers []struct {
+			Size int64 `json:"size"`
+		} `json:"layers"`
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return checkpointArtifact{}, fmt.Errorf("failed to parse manifest %s:%s: %v", s.repository, tag, err)
+	}
+
+	artifact := checkpointArtifact{
+		Name:   fmt.Sprintf("%s:%s", s.repository, tag),
+		Digest: resp.Header.Get("Docker-Content-Digest"),
+		Size:   manifest.Config.Size,
+	}
+	for _, layer := range manifest.Layers {
+		artifact.Size += layer.Size
+	}
+	if created, err := time.Parse(time.RFC3339, manifest.Annotations["org.opencontainers.image.created"]); err == nil {
+		artifact.CreatedAt = created
+		return artifact, nil
+	}
+
+	// Fall back to the creation time recorded in the image config
+	configResp, err := s.do(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/blobs/%s", s.repository, manifest.Config.Digest), nil)
+	if err != nil {
+		return checkpointArtifact{}, err
+	}
+	defer configResp.Body.Close()
+	var imageConfig struct {
+		Created time.Time `json:"created"`
+	}
+	if configResp.StatusCode == http.StatusOK {
+		_ = json.NewDecoder(configResp.Body).Decode(&imageConfig)
+	}
+	artifact.CreatedAt = imageConfig.Created
+	return artifact, nil
+}
+
+// Delete removes the manifest of the checkpoint image; blobs are reclaimed by the registry's own GC
+func (s *registryCheckpointStore) Delete(ctx context.Context, artifact checkpointArtifact) error {
+	if artifact.Digest == "" {
+		return fmt.Errorf("checkpoint %s has no digest", artifact.Name)
+	}
+	resp, err := s.do(ctx, http.MethodDelete, fmt.Sprintf("/v2/%s/manifests/%s", s.repository, artifact.Digest), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deleting %s returned %s", artifact.Name, resp.Status)
+	}
+	return nil
+}
+
+// s3CheckpointStore manages checkpoint objects in an S3 compatible bucket
+type s3CheckpointStore struct {
+	endpoint        *url.URL
+	bucket          string
+	region          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	httpClient      *http.Client
+}
+
+func newS3CheckpointStore(backend StorageBackend, secretAccessKey, prefix string) (*s3CheckpointStore, error) {
+	region := backend.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimRight(backend.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	} else if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage endpoint %s: %v", endpoint, err)
+	}
+
+	httpClient := &http.Client{Timeout: checkpointStoreTimeout}
+	if backend.Insecure {
+		httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} // #nosec G402
+	}
+
+	return &s3CheckpointStore{
+		endpoint:        endpointURL,
+		bucket:          backend.Bucket,
+		region:          region,
+		prefix:          strings.Trim(prefix, "/"),
+		accessKeyID:     backend.AccessKeyID,
+		secretAccessKey: secretAccessKey,
+		httpClient:      httpClient,
+	}, nil
+}
+
+// List returns the objects stored under the checkpoint prefix
+func (s *s3CheckpointStore) List(ctx context.Context) ([]checkpointArtifact, error) {
+	var artifacts []checkpointArtifact
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if s.prefix != "" {
+			query.Set("prefix", s.prefix+"/")
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+				Size         int64     `xml:"Size"`
+				ETag         string    `xml:"ETag"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bucket listing: %v", err)
+		}
+
+		for _, object := range result.Contents {
+			artifact := checkpointArtifact{
+				Name:      object.Key,
+				Size:      object.Size,
+				CreatedAt: object.LastModified,
+			}
+			// The ETag identifies the content of the object, it changes when the object is replaced
+			if etag := strings.Trim(object.ETag, `"`); etag != "" {
+				artifact.Digest = "etag:" + etag
+			}
+			artifacts = append(artifacts, artifact)
+		}
+		if !result.IsTruncated {
+			return artifacts, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// Delete removes the checkpoint object from the bucket
+func (s *s3CheckpointStore) Delete(ctx context.Context, artifact checkpointArtifact) error {
+	resp, err := s.do(ctx, http.MethodDelete, artifact.Name, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Put uploads an object below the store prefix
+func (s *s3CheckpointStore) Put(ctx context.Context, name, contentType string, content []byte) (string, error) {
+	key := strings.Trim(name, "/")
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+	resp, err := s.doWithBody(ctx, http.MethodPut, key, nil, content, contentType)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return key, nil
+}
+
+// do sends a path-style request signed with AWS Signature Version 4
+func (s *s3CheckpointStore) do(ctx context.Context, method, key string, query url.Values) (*http.Response, error) {
+	return s.doWithBody(ctx, method, key, query, nil, "")
+}
+
+// doWithBody sends a signed request with a payload
+func (s *s3CheckpointStore) doWithBody(ctx context.Context, method, key string, query url.Values, body []byte, contentType string) (*http.Response, error) {
+	canonicalURI := "/" + s.bucket
+	if key != "" {
+		canonicalURI += "/" + (&url.URL{Path: key}).EscapedPath()
+	}
+	rawQuery := ""
+	if query != nil {
+		rawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	}
+	target := fmt.Sprintf("%s://%s%s", s.endpoint.Scheme, s.endpoint.Host, canonicalURI)
+	if rawQuery != "" {
+		target += "?" + rawQuery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(req, canonicalURI, query, hex.EncodeToString(sha256Sum(body)), time.Now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("storage request %s %s returned %s: %s", method, canonicalURI, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 headers for a path-style request to the bucket
+func (s *s3CheckpointStore) sign(req *http.Request, canonicalURI string, query url.Values, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", s.endpoint.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{req.Method, canonicalURI, canonicalQueryString(query), canonicalHeaders, signedHeaders, payloadHash}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", shortDate, s.region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(sha256Sum([]byte(canonicalRequest)))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQueryString(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", url.QueryEscape(k), strings.ReplaceAll(url.QueryEscape(query.Get(k)), "+", "%20")))
+	}
+	return strings.Join(parts, "&")
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/dataselect"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	"github.com/karmada-io/dashboard/pkg/resource/pod"
+)
+
+// browsableResource is a resource type the backup wizard can list in a member cluster
+type browsableResource struct {
+	gvr  schema.GroupVersionResource
+	kind string
+	// status adds the type specific status fields to the listed resource
+	status func(item *unstructured.Unstructured, resource map[string]interface{})
+	// podSpec is the path of the pod spec of workloads, whose containers can be selected for a backup
+	podSpec []string
+}
+
+// workloadPodSpec is the path of the pod template spec of workload controllers
+var workloadPodSpec = []string{"spec", "template", "spec"}
+
+// listedContainers returns the containers of the pod spec at path, marking the sidecars
+func listedContainers(item *unstructured.Unstructured, path []string) []migration.ContainerInfo {
+	fields, found, _ := unstructured.NestedMap(item.Object, path...)
+	if !found {
+		return nil
+	}
+	spec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(fields, spec); err != nil {
+		klog.V(4).InfoS("Failed to decode pod spec", "name", item.GetName(), "error", err)
+		return nil
+	}
+	return migration.PodContainers(spec)
+}
+
+// replicaStatus copies the replica counts reported by workload controllers
+func replicaStatus(fields ...string) func(*unstructured.Unstructured, map[string]interface{}) {
+	return func(item *unstructured.Unstructured, resource map[string]interface{}) {
+		for _, field := range fields {
+			if value, found, _ := unstructured.NestedInt64(item.Object, "status", field); found {
+				resource[field] = value
+			}
+		}
+	}
+}
+
+// phaseStatus copies status.phase as the resource status
+func phaseStatus(item *unstructured.Unstructured, resource map[string]interface{}) {
+	if phase, found, _ := unstructured.NestedString(item.Object, "status", "phase"); found {
+		resource["status"] = phase
+	}
+}
+
+// browsableResources are the supported values of the type query parameter
+var browsableResources = map[string]browsableResource{
+	"pod": {
+		gvr:     schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+		kind:    "Pod",
+		status:  phaseStatus,
+		podSpec: []string{"spec"},
+	},
+	"statefulset": {
+		gvr:     schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		kind:    "StatefulSet",
+		status:  replicaStatus("replicas", "readyReplicas"),
+		podSpec: workloadPodSpec,
+	},
+	"deployment": {
+		gvr:     schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		kind:    "Deployment",
+		status:  replicaStatus("replicas", "readyReplicas", "availableReplicas"),
+		podSpec: workloadPodSpec,
+	},
+	"daemonset": {
+		gvr:     schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		kind:    "DaemonSet",
+		status:  replicaStatus("desiredNumberScheduled", "numberReady"),
+		podSpec: workloadPodSpec,
+	},
+	"job": {
+		gvr:     schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"},
+		kind:    "Job",
+		status:  replicaStatus("active", "succeeded", "failed"),
+		podSpec: workloadPodSpec,
+	},
+	"pvc": {
+		gvr:  schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"},
+		kind: "PersistentVolumeClaim",
+		status: func(item *unstructured.Unstructured, resource map[string]interface{}) {
+			phaseStatus(item, resource)
+			if capacity, found, _ := unstructured.NestedString(item.Object, "status", "capacity", "storage"); found {
+				resource["capacity"] = capacity
+			}
+			if storageClass, found, _ := unstructured.NestedString(item.Object, "spec", "storageClassName"); found {
+				resource["storageClass"] = storageClass
+			}
+		},
+	},
+	"service": {
+		gvr:  schema.GroupVersionResource{Version: "v1", Resource: "services"},
+		kind: "Service",
+		status: func(item *unstructured.Unstructured, resource map[string]interface{}) {
+			if serviceType, found, _ := unstructured.NestedString(item.Object, "spec", "type"); found {
+				resource["serviceType"] = serviceType
+			}
+			if clusterIP, found, _ := unstructured.NestedString(item.Object, "spec", "clusterIP"); found {
+				resource["clusterIP"] = clusterIP
+			}
+		},
+	},
+}
+
+// browsableResourceTypes returns the supported resource types for error messages
+func browsableResourceTypes() string {
+	types := make([]string, 0, len(browsableResources))
+	for resourceType := range browsableResources {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	return strings.Join(types, ", ")
+}
+
+// clusterResourceCell is a cell representation of a listed resource for sorting, filtering and pagination
+type clusterResourceCell map[string]interface{}
+
+// GetProperty returns value of a given property.
+func (r clusterResourceCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(fmt.Sprint(r["name"]))
+	case dataselect.NamespaceProperty:
+		return dataselect.StdComparableString(fmt.Sprint(r["namespace"]))
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableRFC3339Timestamp(fmt.Sprint(r["creationTimestamp"]))
+	default:
+		return nil
+	}
+}
+
+// handleGetResourcesInCluster lists resources of a type in a member cluster.
+// Supports namespace, labelSelector and fieldSelector, which are passed to the member cluster,
+// and page, itemsPerPage, sortBy and filterBy.
+func handleGetResourcesInCluster(c *gin.Context) {
+	clusterName := c.Param("cluster")
+	resourceType := strings.ToLower(c.Query("type"))
+	namespace := c.Query("namespace")
+
+	if resourceType == "" {
+		common.Fail(c, fmt.Errorf("resource type is required"))
+		return
+	}
+	resource, ok := browsableResources[resourceType]
+	if !ok {
+		common.Fail(c, fmt.Errorf("unsupported resource type: %s, supported types are %s", resourceType, browsableResourceTypes()))
+		return
+	}
+
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get member cluster client", "cluster", clusterName)
+		common.Fail(c, fmt.Errorf("failed to get dynamic client for member cluster %s: %v", clusterName, err))
+		return
+	}
+
+	list, err := dynamicClient.Resource(resource.gvr).Namespace(namespace).List(c, metav1.ListOptions{
+		LabelSelector: c.Query("labelSelector"),
+		FieldSelector: c.Query("fieldSelector"),
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to get resources", "cluster", clusterName, "type", resourceType)
+		common.Fail(c, fmt.Errorf("failed to list %s: %v", resource.gvr.Resource, err))
+		return
+	}
+
+	// Pods are listed with their usage when metrics-server runs in the cluster
+	var metrics map[string]pod.PodMetrics
+	if resourceType == "pod" {
+		if metrics, err = pod.ListPodMetrics(c, dynamicClient, namespace); err != nil {
+			klog.V(4).InfoS("Pod metrics are not available", "cluster", clusterName, "error", err)
+		}
+	}
+
+	cells := make([]dataselect.DataCell, 0, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		listed := map[string]interface{}{
+			"name":              item.GetName(),
+			"namespace":         item.GetNamespace(),
+			"kind":              resource.kind,
+			"apiVersion":        resource.gvr.GroupVersion().String(),
+			"labels":            item.GetLabels(),
+			"creationTimestamp": item.GetCreationTimestamp().Format(time.RFC3339),
+		}
+		resource.status(item, listed)
+		if len(resource.podSpec) > 0 {
+			listed["containers"] = listedContainers(item, resource.podSpec)
+		}
+		if m, ok := metrics[item.GetNamespace()+"/"+item.GetName()]; ok {
+			listed["usage"] = m.Usage
+		}
+		cells = append(cells, clusterResourceCell(listed))
+	}
+
+	selected, total := dataselect.GenericDataSelectWithFilter(cells, common.ParseDataSelectPathParameter(c))
+	resources := make([]map[string]interface{}, 0, len(selected))
+	for _, cell := range selected {
+		resources = append(resources, cell.(clusterResourceCell))
+	}
+
+	common.Success(c, map[string]interface{}{
+		"resources": resources,
+		"total":     total,
+	})
+}
+
+// handleGetNamespacesInCluster lists the namespaces of a member cluster for the backup wizard
+func handleGetNamespacesInCluster(c *gin.Context) {
+	clusterName := c.Param("cluster")
+
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	memberClient := client.InClusterClientForMemberCluster(clusterName)
+	if memberClient == nil {
+		common.Fail(c, fmt.Errorf("failed to get client for cluster %s", clusterName))
+		return
+	}
+
+	list, err := memberClient.CoreV1().Namespaces().List(c, metav1.ListOptions{
+		LabelSelector: c.Query("labelSelector"),
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list namespaces", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	namespaces := make([]map[string]interface{}, 0, len(list.Items))
+	for _, namespace := range list.Items {
+		namespaces = append(namespaces, map[string]interface{}{
+			"name":              namespace.Name,
+			"status":            string(namespace.Status.Phase),
+			"labels":            namespace.Labels,
+			"creationTimestamp": namespace.CreationTimestamp.Format(time.RFC3339),
+		})
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i]["name"].(string) < namespaces[j]["name"].(string)
+	})
+
+	common.Success(c, map[string]interface{}{
+		"namespaces": namespaces,
+		"total":      len(namespaces),
+	})
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// Severities of compatibility findings; an error means checkpointing will most likely fail
+const (
+	FindingInfo    = "info"
+	FindingWarning = "warning"
+	FindingError   = "error"
+)
+
+// Verdicts of a compatibility check
+const (
+	VerdictCompatible             = "compatible"
+	VerdictCompatibleWithWarnings = "compatible-with-warnings"
+	VerdictIncompatible           = "incompatible"
+)
+
+var (
+	// minCheckpointKubeletVersion is the first release with the kubelet checkpoint API
+	minCheckpointKubeletVersion = version.MajorMinor(1, 25)
+	// betaCheckpointKubeletVersion enables the ContainerCheckpoint feature gate by default
+	betaCheckpointKubeletVersion = version.MajorMinor(1, 30)
+	// minContainerdVersion is the first containerd release implementing CRI checkpointing
+	minContainerdVersion = version.MajorMinor(2, 0)
+	// minCRIOVersion is the first CRI-O release implementing CRI checkpointing
+	minCRIOVersion = version.MajorMinor(1, 25)
+)
+
+// CompatibilityCheckRequest selects the workload to check
+type CompatibilityCheckRequest struct {
+	Cluster      string `json:"cluster" binding:"required,cluster"`
+	ResourceType string `json:"resourceType" binding:"required,oneof=pod statefulset"`
+	ResourceName string `json:"resourceName" binding:"required,dns1123subdomain"`
+	Namespace    string `json:"namespace" binding:"required,dns1123label"`
+}
+
+// CompatibilityFinding is one observation about the workload
+type CompatibilityFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+}
+
+// ContainerCompatibility are the findings of one container
+type ContainerCompatibility struct {
+	Name       string                 `json:"name"`
+	Image      string                 `json:"image"`
+	Compatible bool                   `json:"compatible"`
+	Findings   []CompatibilityFinding `json:"findings"`
+}
+
+// CompatibilityReport reports whether CRIU checkpointing of a workload is likely to succeed
+type CompatibilityReport struct {
+	Cluster          string                   `json:"cluster"`
+	Namespace        string                   `json:"namespace"`
+	ResourceType     string                   `json:"resourceType"`
+	ResourceName     string                   `json:"resourceName"`
+	PodName          string                   `json:"podName,omitempty"`
+	NodeName         string                   `json:"nodeName,omitempty"`
+	ContainerRuntime string                   `json:"containerRuntime,omitempty"`
+	OperatingSystem  string                   `json:"operatingSystem,omitempty"`
+	KubeletVersion   string                   `json:"kubeletVersion,omitempty"`
+	Verdict          string                   `json:"verdict"`
+	Compatible       bool                     `json:"compatible"`
+	Findings         []CompatibilityFinding   `json:"findings"`
+	Containers       []ContainerCompatibility `json:"containers"`
+}
+
+func (r *CompatibilityReport) add(severity, check, message string) {
+	r.Findings = append(r.Findings, CompatibilityFinding{Severity: severity, Check: check, Message: message})
+}
+
+// finish sets the verdict from the findings of the pod and its containers
+func (r *CompatibilityReport) finish() {
+	worst := worstSeverity(r.Findings)
+	for i := range r.Containers {
+		container := &r.Containers[i]
+		severity := worstSeverity(container.Findings)
+		container.Compatible = severity != FindingError
+		if severity == FindingError || (severity == FindingWarning && worst != FindingError) {
+			worst = severity
+		}
+	}
+	switch worst {
+	case FindingError:
+		r.Verdict = VerdictIncompatible
+	case FindingWarning:
+		r.Verdict = VerdictCompatibleWithWarnings
+	default:
+		r.Verdict = VerdictCompatible
+	}
+	r.Compatible = worst != FindingError
+}
+
+func worstSeverity(findings []CompatibilityFinding) string {
+	worst := FindingInfo
+	for _, finding := range findings {
+		switch finding.Severity {
+		case FindingError:
+			return FindingError
+		case FindingWarning:
+			worst = FindingWarning
+		}
+	}
+	return worst
+}
+
+// getWorkloadPod returns the pod to inspect, for a StatefulSet its first running pod.
+// A StatefulSet without pods is checked from its pod template only.
+func getWorkloadPod(c *gin.Context, memberClient kubeclient.Interface, req CompatibilityCheckRequest) (*corev1.Pod, error) {
+	if req.ResourceType == "pod" {
+		return memberClient.CoreV1().Pods(req.Namespace).Get(c, req.ResourceName, metav1.GetOptions{})
+	}
+
+	sts, err := memberClient.AppsV1().StatefulSets(req.Namespace).Get(c, req.ResourceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of statefulset %s: %v", sts.Name, err)
+	}
+	pods, err := memberClient.CoreV1().Pods(req.Namespace).List(c, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Labels: sts.Spec.Template.Labels},
+		Spec:       sts.Spec.Template.Spec,
+	}, nil
+}
+
+// checkNode checks the container runtime, OS and kubelet of the node running the pod
+func checkNode(c *gin.Context, memberClient kubeclient.Interface, report *CompatibilityReport) {
+	if report.NodeName == "" {
+		report.add(FindingWarning, "node", "The workload has no running pod, the node runtime could not be checked")
+		return
+	}
+	node, err := memberClient.CoreV1().Nodes().Get(c, report.NodeName, metav1.GetOptions{})
+	if err != nil {
+		report.add(FindingWarning, "node", fmt.Sprintf("Failed to get node %s: %v", report.NodeName, err))
+		return
+	}
+	info := node.Status.NodeInfo
+	report.ContainerRuntime = info.ContainerRuntimeVersion
+	report.OperatingSystem = info.OperatingSystem
+	report.KubeletVersion = info.KubeletVersion
+
+	if info.OperatingSystem != "linux" {
+		report.add(FindingError, "operating-system", fmt.Sprintf("CRIU only supports Linux, node runs %s", info.OperatingSystem))
+	}
+
+	if kubelet, err := version.ParseGeneric(info.KubeletVersion); err != nil {
+		report.add(FindingWarning, "kubelet", fmt.Sprintf("Unknown kubelet version %q", info.KubeletVersion))
+	} else if kubelet.LessThan(minCheckpointKubeletVersion) {
+		report.add(FindingError, "kubelet", fmt.Sprintf("Kubelet %s has no checkpoint API, 1.25 or newer is required", info.KubeletVersion))
+	} else if kubelet.LessThan(betaCheckpointKubeletVersion) {
+		report.add(FindingWarning, "kubelet", fmt.Sprintf("Kubelet %s requires the ContainerCheckpoint feature gate to be enabled", info.KubeletVersion))
+	}
+
+	// The runtime version is reported as <runtime>://<version>
+	runtime, runtimeVersion, _ := strings.Cut(info.ContainerRuntimeVersion, "://")
+	var minVersion *version.Version
+	switch runtime {
+	case "containerd":
+		minVersion = minContainerdVersion
+	case "cri-o":
+		minVersion = minCRIOVersion
+	default:
+		report.add(FindingError, "container-runtime", fmt.Sprintf("Container runtime %s does not support CRI checkpointing", info.ContainerRuntimeVersion))
+		return
+	}
+	if parsed, err := version.ParseGeneric(runtimeVersion); err != nil {
+		report.add(FindingWarning, "container-runtime", fmt.Sprintf("Unknown %s version %q", runtime, runtimeVersion))
+	} else if parsed.LessThan(minVersion) {
+		report.add(FindingError, "container-runtime", fmt.Sprintf("%s %s does not support CRI checkpointing, %s or newer is required", runtime, runtimeVersion, minVersion))
+	}
+}
+
+// checkPod checks the pod level settings that CRIU cannot restore on another node
+func checkPod(pod *corev1.Pod, report *CompatibilityReport) {
+	if pod.Spec.HostNetwork {
+		report.add(FindingError, "host-network", "Pods using the host network cannot be restored on another node")
+	}
+	if pod.Spec.HostPID {
+		report.add(FindingError, "host-pid", "Pods sharing the host PID namespace cannot be checkpointed")
+	}
+	if pod.Spec.HostIPC {
+		report.add(FindingWarning, "host-ipc", "Host IPC resources are not part of the checkpoint")
+	}
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.HostPath != nil:
+			report.add(FindingWarning, "host-path", fmt.Sprintf("Volume %s mounts host path %s, which is not part of the checkpoint", volume.Name, volume.HostPath.Path))
+		case volume.EmptyDir != nil:
+			report.add(FindingInfo, "ephemeral-storage", fmt.Sprintf("Volume %s is an emptyDir, its content is not part of the checkpoint", volume.Name))
+		case volume.PersistentVolumeClaim != nil:
+			report.add(FindingInfo, "persistent-volume", fmt.Sprintf("Volume %s uses claim %s, which must be available on the target cluster", volume.Name, volume.PersistentVolumeClaim.ClaimName))
+		}
+	}
+}
+
+// checkContainer checks the container settings that break or weaken checkpointing
+func checkContainer(container corev1.Container) ContainerCompatibility {
+	result := ContainerCompatibility{Name: container.Name, Image: container.Image, Findings: []CompatibilityFinding{}}
+	add := func(severity, check, message string) {
+		result.Findings = append(result.Findings, CompatibilityFinding{Severity: severity, Check: check, Message: message})
+	}
+
+	if sc := container.SecurityContext; sc != nil {
+		if sc.Privileged != nil && *sc.Privileged {
+			add(FindingWarning, "privileged", "Privileged containers may hold host resources that cannot be restored")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if capability == "SYS_ADMIN" || capability == "NET_ADMIN" {
+					add(FindingWarning, "capabilities", fmt.Sprintf("Capability %s may configure host state that is not part of the checkpoint", capability))
+				}
+			}
+		}
+	}
+	for name := range container.Resources.Limits {
+		if strings.Contains(string(name), "gpu") {
+			add(FindingError, "gpu", fmt.Sprintf("Container requests %s, CRIU cannot checkpoint device memory", name))
+		}
+	}
+	if _, ok := container.Resources.Limits[corev1.ResourceEphemeralStorage]; ok {
+		add(FindingInfo, "ephemeral-storage", "Writes to the container filesystem are included in the checkpoint and count against its size")
+	}
+	for _, mount := range container.VolumeMounts {
+		if mount.MountPropagation != nil && *mount.MountPropagation == corev1.MountPropagationBidirectional {
+			add(FindingWarning, "mount-propagation", fmt.Sprintf("Mount %s uses bidirectional propagation", mount.MountPath))
+		}
+	}
+	if container.Stdin || container.TTY {
+		add(FindingWarning, "terminal", "Attached terminals and stdin are not restored")
+	}
+	return result
+}
+
+// handleCompatibilityCheck reports whether a pod or statefulset can likely be checkpointed with CRIU
+func handleCompatibilityCheck(c *gin.Context) {
+	var req CompatibilityCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind compatibility check request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	if err := client.CheckMemberClusterAccess(c, req.Cluster); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	memberClient := client.InClusterClientForMemberCluster(req.Cluster)
+	if memberClient == nil {
+		common.Fail(c, fmt.Errorf("failed to get client for cluster %s", req.Cluster))
+		return
+	}
+
+	pod, err := getWorkloadPod(c, memberClient, req)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get workload", "cluster", req.Cluster, "type", req.ResourceType, "name", req.ResourceName)
+		common.Fail(c, fmt.Errorf("failed to get %s %s/%s: %v", req.ResourceType, req.Namespace, req.ResourceName, err))
+		return
+	}
+
+	report := &CompatibilityReport{
+		Cluster:      req.Cluster,
+		Namespace:    req.Namespace,
+		ResourceType: req.ResourceType,
+		ResourceName: req.ResourceName,
+		PodName:      pod.Name,
+		NodeName:     pod.Spec.NodeName,
+		Findings:     []CompatibilityFinding{},
+	}
+	checkNode(c, memberClient, report)
+	checkPod(pod, report)
+	for _, container := range pod.Spec.InitContainers {
+		// Restartable init containers are sidecars and run next to the workload
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			report.Containers = append(report.Containers, checkContainer(container))
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		report.Containers = append(report.Containers, checkContainer(container))
+	}
+	report.finish()
+
+	common.Success(c, report)
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
+)
+
+// controllerConfigTarget is where the settings of the migration controller of a cluster live: the workload
+// running it and its ConfigMap. Member cluster controllers are configured in Karmada and propagated.
+type controllerConfigTarget struct {
+	clusterName   string
+	workloads     dynamic.ResourceInterface
+	workloadName  string
+	container     string
+	configMaps    kubernetes.Interface
+	configMapName string
+	labels        map[string]string
+}
+
+func getControllerConfigTarget(clusterName string) (*controllerConfigTarget, error) {
+	if clusterName == "mgmt-cluster" || clusterName == "management" {
+		dynamicClient, err := client.GetDynamicClient()
+		if err != nil {
+			return nil, err
+		}
+		deploymentGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+		return &controllerConfigTarget{
+			clusterName:   clusterName,
+			workloads:     dynamicClient.Resource(deploymentGVR).Namespace("stateful-migration"),
+			workloadName:  "migration-backup-controller",
+			container:     "manager",
+			configMaps:    client.InClusterClient(),
+			configMapName: "migration-backup-controller-config",
+		}, nil
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	daemonSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	return &controllerConfigTarget{
+		clusterName:   clusterName,
+		workloads:     karmadaDynamicClient.Resource(daemonSetGVR).Namespace("stateful-migration"),
+		workloadName:  clusterResourceName("checkpoint-backup-controller", clusterName),
+		container:     checkpointBackupBundle.ImageContainer,
+		configMaps:    client.InClusterClientForKarmadaAPIServer(),
+		configMapName: checkpointBackupConfigMapName(clusterName),
+		labels:        orphan.Labels(clusterName),
+	}, nil
+}
+
+// checkpointBackupConfigMapName returns the ConfigMap of the checkpoint backup controller of a cluster
+func checkpointBackupConfigMapName(suffix string) string {
+	return clusterResourceName("checkpoint-backup-config", suffix)
+}
+
+// errControllerNotInstalled is returned when a cluster has no migration controller to configure
+var errControllerNotInstalled = errors.New("migration controller is not installed")
+
+// get returns the current settings of the controller
+func (t *controllerConfigTarget) get(ctx context.Context) (migration.ControllerConfig, error) {
+	workload, err := t.workloads.Get(ctx, t.workloadName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return migration.ControllerConfig{}, fmt.Errorf("cluster %s: %w", t.clusterName, errControllerNotInstalled)
+	}
+	if err != nil {
+		return migration.ControllerConfig{}, err
+	}
+	var data map[string]string
+	cm, err := t.configMaps.CoreV1().ConfigMaps("stateful-migration").Get(ctx, t.configMapName, metav1.GetOptions{})
+	if err == nil {
+		data = migration.ClusterSettings(cm.Data, cm.Annotations)
+	} else if !apierrors.IsNotFound(err) {
+		return migration.ControllerConfig{}, err
+	}
+	return migration.ReadControllerConfig(workload, t.container, data)
+}
+
+// apply writes the settings to the ConfigMap and the workload of the controller. Limits the settings leave
+// unset are taken from the global throttle, and the ConfigMap records which ones, so get returns the
+// settings of the cluster only.
+func (t *controllerConfigTarget) apply(ctx context.Context, cfg migration.ControllerConfig, defaults migration.Throttle) error {
+	workload, err := t.workloads.Get(ctx, t.workloadName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("cluster %s: %w", t.clusterName, errControllerNotInstalled)
+	}
+	if err != nil {
+		return err
+	}
+
+	effective, inherited := cfg.WithDefaults(defaults)
+	configMaps := t.configMaps.CoreV1().ConfigMaps("stateful-migration")
+	cm, err := configMaps.Get(ctx, t.configMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      t.configMapName,
+			Namespace: "stateful-migration",
+			Labels:    t.labels,
+		}, Data: effective.ConfigMapData()}
+		setInheritedSettings(cm, inherited)
+		if _, err := configMaps.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create controller ConfigMap: %v", err)
+		}
+	} else if err != nil {
+		return err
+	} else {
+		cm.Data = effective.ConfigMapData()
+		setInheritedSettings(cm, inherited)
+		if _, err := configMaps.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update controller ConfigMap: %v", err)
+		}
+	}
+
+	if t.labels != nil {
+		if err := ensurePolicySelectsConfigMap(ctx, t.clusterName); err != nil {
+			return err
+		}
+	}
+
+	if err := migration.ApplyControllerConfig(workload, t.container, t.configMapName, effective); err != nil {
+		return err
+	}
+	if _, err := t.workloads.Update(ctx, workload, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update %s %s: %v", workload.GetKind(), t.workloadName, err)
+	}
+	return nil
+}
+
+// setInheritedSettings records the settings of a controller ConfigMap that come from the global throttle
+func setInheritedSettings(cm *corev1.ConfigMap, inherited []string) {
+	if len(inherited) == 0 {
+		delete(cm.Annotations, migration.InheritedSettingsAnnotation)
+		return
+	}
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[migration.InheritedSettingsAnnotation] = strings.Join(inherited, ",")
+}
+
+// ensurePolicySelectsConfigMap adds the ConfigMap to the propagation policy of a checkpoint backup controller
+// installed before the policies selected it
+func ensurePolicySelectsConfigMap(ctx context.Context, clusterName string) error {
+	policies := client.InClusterKarmadaClient().PolicyV1alpha1().PropagationPolicies("stateful-migration")
+	policy, err := policies.Get(ctx, fmt.Sprintf("checkpoint-backup-%s", clusterName), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get checkpoint-backup PropagationPolicy: %v", err)
+	}
+	configMapName := checkpointBackupConfigMapName(clusterName)
+	for _, selector := range policy.Spec.ResourceSelectors {
+		if selector.Kind == "ConfigMap" && selector.Name == configMapName {
+			return nil
+		}
+	}
+	policy.Spec.ResourceSelectors = append(policy.Spec.ResourceSelectors, policyv1alpha1.ResourceSelector{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       configMapName,
+	})
+	if _, err := policies.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update checkpoint-backup PropagationPolicy: %v", err)
+	}
+	return nil
+}
+
+// handleGetControllerConfig returns the runtime settings of the migration controller of a cluster
+func handleGetControllerConfig(c *gin.Context) {
+	clusterName := c.Param("name")
+	target, err := getControllerConfigTarget(clusterName)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	cfg, err := target.get(c)
+	if err != nil {
+		failControllerConfig(c, clusterName, err)
+		return
+	}
+	common.Success(c, cfg)
+}
+
+// handlePutControllerConfig replaces the runtime settings of the migration controller of a cluster. The pods of
+// the controller restart with the new settings.
+func handlePutControllerConfig(c *gin.Context) {
+	clusterName := c.Param("name")
+	var cfg migration.ControllerConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	release, err := clusterOperations.begin(c, clusterName, "configure", false)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusConflict)
+		return
+	}
+	defer release()
+
+	target, err := getControllerConfigTarget(clusterName)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := target.apply(c, cfg, migration.GlobalThrottle()); err != nil {
+		failControllerConfig(c, clusterName, err)
+		return
+	}
+	klog.InfoS("Updated migration controller settings", "cluster", clusterName, "config", cfg)
+	common.Success(c, cfg)
+}
+
+func failControllerConfig(c *gin.Context, clusterName string, err error) {
+	if errors.Is(err, errControllerNotInstalled) {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	klog.ErrorS(err, "Failed to configure migration controller", "cluster", clusterName)
+	common.Fail(c, err)
+}
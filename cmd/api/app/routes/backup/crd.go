@@ -0,0 +1,296 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// Typed views of the migration.dcnlab.com CRDs of the stateful migration operator.
+// Only the fields the dashboard reads or writes are declared, unknown fields are ignored on decode.
+
+const migrationGroup = "migration.dcnlab.com"
+
+// ResourceRef references the workload a migration resource applies to
+type ResourceRef struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// LocalObjectRef references a Secret in the namespace of the resource
+type LocalObjectRef struct {
+	Name string `json:"name"`
+}
+
+// CheckpointContainer is a container checkpoint and the image it was stored in
+type CheckpointContainer struct {
+	Name            string `json:"name,omitempty"`
+	Image           string `json:"image,omitempty"`
+	CheckpointImage string `json:"checkpointImage,omitempty"`
+}
+
+// MigrationCondition is a status condition reported by the operator
+type MigrationCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// StatefulMigration is the migration.dcnlab.com/v1 StatefulMigration used for backup configurations
+type StatefulMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StatefulMigrationSpec `json:"spec"`
+}
+
+// StatefulMigrationSpec is the desired checkpoint schedule of a workload
+type StatefulMigrationSpec struct {
+	SourceClusters []string      `json:"sourceClusters,omitempty"`
+	ResourceRef    ResourceRef   `json:"resourceRef"`
+	Schedule       string        `json:"schedule,omitempty"`
+	ExecuteNow     int64         `json:"executeNow,omitempty"`
+	Registry       *RegistrySpec `json:"registry,omitempty"`
+	Storage        *StorageSpec  `json:"storage,omitempty"`
+	// Containers limits the checkpoint to these containers of the pods, all containers when empty
+	Containers []string        `json:"containers,omitempty"`
+	Encryption *EncryptionSpec `json:"encryption,omitempty"`
+	// Throttle limits the upload bandwidth and the parallel checkpoints of the migration per node
+	Throttle *migration.Throttle `json:"throttle,omitempty"`
+}
+
+// EncryptionSpec is the key the checkpoint artifacts are encrypted with
+type EncryptionSpec struct {
+	Provider  string          `json:"provider"`
+	Algorithm string          `json:"algorithm,omitempty"`
+	SecretRef *LocalObjectRef `json:"secretRef,omitempty"`
+	KMSKeyURI string          `json:"kmsKeyURI,omitempty"`
+}
+
+// RegistrySpec is the container registry the checkpoint images are pushed to
+type RegistrySpec struct {
+	URL        string          `json:"url,omitempty"`
+	Repository string          `json:"repository,omitempty"`
+	SecretRef  *LocalObjectRef `json:"secretRef,omitempty"`
+}
+
+// StorageSpec is the storage backend checkpoints are written to instead of a registry
+type StorageSpec struct {
+	Type string          `json:"type"`
+	S3   *S3StorageSpec  `json:"s3,omitempty"`
+	PVC  *PVCStorageSpec `json:"pvc,omitempty"`
+}
+
+// S3StorageSpec is an S3 compatible bucket, used for both S3 and MinIO backends
+type S3StorageSpec struct {
+	Endpoint  string          `json:"endpoint,omitempty"`
+	Bucket    string          `json:"bucket,omitempty"`
+	Region    string          `json:"region,omitempty"`
+	Insecure  bool            `json:"insecure,omitempty"`
+	Prefix    string          `json:"prefix,omitempty"`
+	SecretRef *LocalObjectRef `json:"secretRef,omitempty"`
+}
+
+// PVCStorageSpec is a persistent volume claim in the member cluster
+type PVCStorageSpec struct {
+	ClaimName string `json:"claimName,omitempty"`
+	Path      string `json:"path,omitempty"`
+}
+
+// Path returns the path checkpoints are stored under in the backend
+func (s *StorageSpec) Path() string {
+	switch {
+	case s.PVC != nil:
+		return s.PVC.Path
+	case s.S3 != nil:
+		return s.S3.Prefix
+	}
+	return ""
+}
+
+// RecoveryMigration is the migration.dcnlab.com/v1alpha1 StatefulMigration the dashboard uses as a recovery record
+type RecoveryMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecoveryMigrationSpec   `json:"spec"`
+	Status RecoveryMigrationStatus `json:"status,omitempty"`
+}
+
+// RecoveryMigrationSpec is the backup to recover and where to recover it
+type RecoveryMigrationSpec struct {
+	BackupID        string `json:"backupID,omitempty"`
+	BackupName      string `json:"backupName,omitempty"`
+	SourceCluster   string `json:"sourceCluster,omitempty"`
+	TargetCluster   string `json:"targetCluster,omitempty"`
+	ResourceType    string `json:"resourceType,omitempty"`
+	ResourceName    string `json:"resourceName,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	TargetName      string `json:"targetName,omitempty"`
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	RecoveryType    string `json:"recoveryType,omitempty"`
+	ImageRepository string `json:"imageRepository,omitempty"`
+	RegistryID      string `json:"registryID,omitempty"`
+	Phase           string `json:"phase,omitempty"`
+	ExecuteNow      int64  `json:"executeNow,omitempty"`
+	// Renames are the dependencies of the workload recreated under another name on the target cluster
+	Renames []RecoveryRename `json:"renames,omitempty"`
+}
+
+// RecoveryMigrationStatus is the progress of a recovery
+type RecoveryMigrationStatus struct {
+	Phase       string `json:"phase,omitempty"`
+	Progress    int64  `json:"progress,omitempty"`
+	Error       string `json:"error,omitempty"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"`
+}
+
+// CheckpointBackup is the migration.dcnlab.com/v1 CheckpointBackup created by the operator in member clusters
+type CheckpointBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CheckpointBackupSpec   `json:"spec"`
+	Status CheckpointBackupStatus `json:"status,omitempty"`
+}
+
+// CheckpointBackupSpec is the workload to checkpoint
+type CheckpointBackupSpec struct {
+	ResourceRef *ResourceRef          `json:"resourceRef,omitempty"`
+	PodName     string                `json:"podName,omitempty"`
+	Containers  []CheckpointContainer `json:"containers,omitempty"`
+}
+
+// CheckpointBackupStatus is the result of a checkpoint
+type CheckpointBackupStatus struct {
+	Phase            string                `json:"phase,omitempty"`
+	Message          string                `json:"message,omitempty"`
+	Containers       []CheckpointContainer `json:"containers,omitempty"`
+	CheckpointImages []CheckpointContainer `json:"checkpointImages,omitempty"`
+}
+
+// CheckpointedContainers returns the checkpoint image of each container, preferring what the operator reported
+func (cb *CheckpointBackup) CheckpointedContainers() []CheckpointContainer {
+	for _, containers := range [][]CheckpointContainer{cb.Status.Containers, cb.Status.CheckpointImages, cb.Spec.Containers} {
+		if len(containers) == 0 {
+			continue
+		}
+		result := make([]CheckpointContainer, 0, len(containers))
+		for _, container := range containers {
+			image := container.CheckpointImage
+			if image == "" {
+				image = container.Image
+			}
+			result = append(result, CheckpointContainer{Name: container.Name, Image: image})
+		}
+		return result
+	}
+	return nil
+}
+
+// CheckpointRestore is the migration.dcnlab.com/v1 CheckpointRestore that restores a checkpoint in a member cluster
+type CheckpointRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CheckpointRestoreSpec   `json:"spec"`
+	Status CheckpointRestoreStatus `json:"status,omitempty"`
+}
+
+// CheckpointRestoreSpec is the checkpoint to restore and the pod to restore it into
+type CheckpointRestoreSpec struct {
+	BackupRef     *CheckpointBackupRef  `json:"backupRef,omitempty"`
+	TargetCluster string                `json:"targetCluster,omitempty"`
+	PodName       string                `json:"podName,omitempty"`
+	PodNamespace  string                `json:"podNamespace,omitempty"`
+	Containers    []CheckpointContainer `json:"containers,omitempty"`
+}
+
+// CheckpointBackupRef references the CheckpointBackup a restore is made from
+type CheckpointBackupRef struct {
+	Name        string       `json:"name"`
+	Namespace   string       `json:"namespace,omitempty"`
+	Cluster     string       `json:"cluster,omitempty"`
+	ResourceRef *ResourceRef `json:"resourceRef,omitempty"`
+}
+
+// CheckpointRestoreStatus is the progress of a restore
+type CheckpointRestoreStatus struct {
+	Phase          string               `json:"phase,omitempty"`
+	Message        string               `json:"message,omitempty"`
+	Progress       int64                `json:"progress,omitempty"`
+	StartTime      string               `json:"startTime,omitempty"`
+	CompletionTime string               `json:"completionTime,omitempty"`
+	RestoredImages []string             `json:"restoredImages,omitempty"`
+	Conditions     []MigrationCondition `json:"conditions,omitempty"`
+}
+
+// decodeMigrationObject decodes obj into out after checking it is a supported version of kind
+func decodeMigrationObject(obj *unstructured.Unstructured, kind string, versions []string, out interface{}) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != migrationGroup || gvk.Kind != kind {
+		return fmt.Errorf("expected %s.%s, got %s", kind, migrationGroup, gvk.String())
+	}
+	if !containsString(versions, gvk.Version) {
+		return fmt.Errorf("unsupported %s version %q, supported versions are %s", kind, gvk.Version, strings.Join(versions, ", "))
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, out); err != nil {
+		return fmt.Errorf("failed to decode %s %s/%s: %v", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+func decodeStatefulMigration(obj *unstructured.Unstructured) (*StatefulMigration, error) {
+	sm := &StatefulMigration{}
+	return sm, decodeMigrationObject(obj, "StatefulMigration", []string{"v1"}, sm)
+}
+
+func decodeRecoveryMigration(obj *unstructured.Unstructured) (*RecoveryMigration, error) {
+	rm := &RecoveryMigration{}
+	return rm, decodeMigrationObject(obj, "StatefulMigration", []string{"v1alpha1"}, rm)
+}
+
+func decodeCheckpointBackup(obj *unstructured.Unstructured) (*CheckpointBackup, error) {
+	cb := &CheckpointBackup{}
+	return cb, decodeMigrationObject(obj, "CheckpointBackup", []string{"v1"}, cb)
+}
+
+func decodeCheckpointRestore(obj *unstructured.Unstructured) (*CheckpointRestore, error) {
+	cr := &CheckpointRestore{}
+	return cr, decodeMigrationObject(obj, "CheckpointRestore", []string{"v1"}, cr)
+}
+
+// toUnstructured encodes a typed migration resource for the dynamic client
+func toUnstructured(obj interface{}) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	settingsKindRegistry   = "registry"
+	settingsKindController = "controller"
+)
+
+// declaredRegistries returns the registries by name together with their secrets, which hold the passwords
+func declaredRegistries(ctx context.Context) (map[string]*corev1.Secret, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+	secretsUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=backup-registry",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registries: %v", err)
+	}
+	secrets := make(map[string]*corev1.Secret, len(secretsUnstructured.Items))
+	for i := range secretsUnstructured.Items {
+		secret := &corev1.Secret{}
+		if err := convertUnstructuredToTyped(&secretsUnstructured.Items[i], secret); err != nil {
+			klog.ErrorS(err, "Failed to convert secret", "secretName", secretsUnstructured.Items[i].GetName())
+			continue
+		}
+		secrets[string(secret.Data["name"])] = secret
+	}
+	return secrets, nil
+}
+
+// CurrentRegistries returns the registries as declared settings, without passwords
+func CurrentRegistries(ctx context.Context) ([]v1.DeclaredRegistry, error) {
+	secrets, err := declaredRegistries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registries := make([]v1.DeclaredRegistry, 0, len(secrets))
+	for name, secret := range secrets {
+		registries = append(registries, v1.DeclaredRegistry{
+			Name:        name,
+			Registry:    string(secret.Data["registry"]),
+			Username:    string(secret.Data["username"]),
+			Description: string(secret.Data["description"]),
+		})
+	}
+	sort.Slice(registries, func(i, j int) bool { return registries[i].Name < registries[j].Name })
+	return registries, nil
+}
+
+// ReconcileRegistries brings the registries to the declared state, or only reports the changes with dryRun.
+// Registries that are not declared are deleted with prune.
+func ReconcileRegistries(ctx context.Context, desired []v1.DeclaredRegistry, prune, dryRun bool) ([]v1.SettingsChange, error) {
+	secrets, err := declaredRegistries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []v1.SettingsChange
+	declared := map[string]bool{}
+	for _, registry := range desired {
+		var err error
+		change := v1.SettingsChange{Kind: settingsKindRegistry, Name: registry.Name}
+		if registry.Name == "" || registry.Registry == "" || registry.Username == "" {
+			change.Action = v1.SettingsActionCreate
+			change.Error = "name, registry and username are required"
+			changes = append(changes, change)
+			continue
+		}
+		declared[registry.Name] = true
+
+		secret, ok := secrets[registry.Name]
+		if !ok {
+			change.Action = v1.SettingsActionCreate
+			if registry.Password == "" {
+				change.Error = "a password is required to create a registry"
+			} else if !dryRun {
+				_, err = createRegistry(ctx, CreateRegistryRequest{
+					Name:        registry.Name,
+					Registry:    registry.Registry,
+					Username:    registry.Username,
+					Password:    registry.Password,
+					Description: registry.Description,
+				})
+			}
+			changes = append(changes, withError(change, err))
+			continue
+		}
+
+		req := UpdateRegistryRequest{}
+		if string(secret.Data["registry"]) != registry.Registry {
+			req.Registry = registry.Registry
+			change.Fields = append(change.Fields, "registry")
+		}
+		if string(secret.Data["username"]) != registry.Username {
+			req.Username = registry.Username
+			change.Fields = append(change.Fields, "username")
+		}
+		if registry.Password != "" && string(secret.Data["password"]) != registry.Password {
+			req.Password = registry.Password
+			change.Fields = append(change.Fields, "password")
+		}
+		// The update API ignores empty fields, so a description can be changed but not cleared
+		if registry.Description != "" && string(secret.Data["description"]) != registry.Description {
+			req.Description = registry.Description
+			change.Fields = append(change.Fields, "description")
+		}
+		change.Action = v1.SettingsActionUnchanged
+		if len(change.Fields) > 0 {
+			change.Action = v1.SettingsActionUpdate
+			if !dryRun {
+				_, err = updateRegistry(ctx, secret.Labels["registry-id"], req)
+			}
+		}
+		changes = append(changes, withError(change, err))
+	}
+
+	if prune {
+		for _, name := range sortedSecretNames(secrets) {
+			if declared[name] {
+				continue
+			}
+			var err error
+			change := v1.SettingsChange{Kind: settingsKindRegistry, Name: name, Action: v1.SettingsActionDelete}
+			if !dryRun {
+				err = deleteRegistry(ctx, secrets[name].Labels["registry-id"])
+			}
+			changes = append(changes, withError(change, err))
+		}
+	}
+	return changes, nil
+}
+
+func sortedSecretNames(secrets map[string]*corev1.Secret) []string {
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func withError(change v1.SettingsChange, err error) v1.SettingsChange {
+	if err != nil {
+		change.Error = err.Error()
+	}
+	return change
+}
+
+// installedControllerVersions returns the version of the migration controller of each cluster it is installed on,
+// read from the manifests the dashboard applied
+func installedControllerVersions(ctx context.Context) (map[string]string, error) {
+	clusters, err := installedClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	daemonSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+
+	versions := make(map[string]string, len(clusters))
+	for _, clusterName := range clusters {
+		if clusterName == "mgmt-cluster" {
+			deployment, err := client.InClusterClient().AppsV1().Deployments("stateful-migration").Get(ctx, "migration-backup-controller", metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			versions[clusterName] = migration.DeploymentVersion(deployment, componentMigrationBackup)
+			continue
+		}
+		daemonSet, err := karmadaDynamicClient.Resource(daemonSetGVR).Namespace("stateful-migration").Get(ctx,
+			fmt.Sprintf("checkpoint-backup-controller-%s", clusterName), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		versions[clusterName] = migration.DaemonSetVersion(daemonSet.Object, componentCheckpointBackup)
+	}
+	return versions, nil
+}
+
+// CurrentControllers returns the migration controller installs as declared settings
+func CurrentControllers(ctx context.Context) ([]v1.DeclaredController, error) {
+	versions, err := installedControllerVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	controllers := make([]v1.DeclaredController, 0, len(versions))
+	for clusterName, controllerVersion := range versions {
+		controllers = append(controllers, v1.DeclaredController{Cluster: clusterName, Version: controllerVersion})
+	}
+	sort.Slice(controllers, func(i, j int) bool { return controllers[i].Cluster < controllers[j].Cluster })
+	return controllers, nil
+}
+
+// ReconcileControllers installs, upgrades and, with prune, uninstalls migration controllers so that the
+// declared clusters run the declared versions. Clusters with another controller operation running are
+// reported as failed rather than waited for.
+func ReconcileControllers(ctx context.Context, desired []v1.DeclaredController, prune, dryRun bool) ([]v1.SettingsChange, error) {
+	versions, err := installedControllerVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Both names are accepted for the management cluster
+	normalize := func(clusterName string) string {
+		if clusterName == "management" {
+			return "mgmt-cluster"
+		}
+		return clusterName
+	}
+
+	var changes []v1.SettingsChange
+	declared := map[string]bool{}
+	for _, controller := range desired {
+		clusterName := normalize(controller.Cluster)
+		change := v1.SettingsChange{Kind: settingsKindController, Name: clusterName}
+		declared[clusterName] = true
+
+		installed, ok := versions[clusterName]
+		var apply func(version string) error
+		switch {
+		case !ok:
+			change.Action = v1.SettingsActionCreate
+			apply = func(version string) error { return installMigrationController(clusterName, version) }
+		case controller.Version != "" && installed != controller.Version:
+			change.Action = v1.SettingsActionUpdate
+			change.Fields = []string{"version"}
+			apply = func(version string) error { return upgradeMigrationController(ctx, clusterName, version) }
+		default:
+			change.Action = v1.SettingsActionUnchanged
+			changes = append(changes, change)
+			continue
+		}
+
+		controllerVersion := controller.Version
+		if controllerVersion == "" {
+			controllerVersion = getControllerCatalog(ctx).Latest
+		}
+		err := validateControllerVersion(ctx, clusterName, controllerVersion)
+		if err == nil && !dryRun {
+			err = runControllerOperation(ctx, clusterName, "declarative "+change.Action, func() error {
+				return apply(controllerVersion)
+			})
+		}
+		changes = append(changes, withError(change, err))
+	}
+
+	if prune {
+		clusterNames := make([]string, 0, len(versions))
+		for clusterName := range versions {
+			clusterNames = append(clusterNames, clusterName)
+		}
+		sort.Strings(clusterNames)
+		for _, clusterName := range clusterNames {
+			if declared[clusterName] {
+				continue
+			}
+			var err error
+			change := v1.SettingsChange{Kind: settingsKindController, Name: clusterName, Action: v1.SettingsActionDelete}
+			if !dryRun {
+				err = runControllerOperation(ctx, clusterName, "declarative delete", func() error {
+					return uninstallMigrationController(clusterName)
+				})
+			}
+			changes = append(changes, withError(change, err))
+		}
+	}
+	return changes, nil
+}
+
+// runControllerOperation runs a controller operation unless the cluster has another one running
+func runControllerOperation(ctx context.Context, clusterName, operation string, run func() error) error {
+	release, err := clusterOperations.begin(ctx, clusterName, operation, false)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := run(); err != nil {
+		klog.ErrorS(err, "Migration controller operation failed", "cluster", clusterName, "operation", operation)
+		return err
+	}
+	return nil
+}
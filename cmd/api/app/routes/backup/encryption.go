@@ -0,0 +1,398 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	encryptionSecretPrefix = "backup-encryption"
+	// encryptionKeyAnnotation records the encryption key selected for a StatefulMigration
+	encryptionKeyAnnotation = "backup.dcnlab.com/encryption-key-id"
+)
+
+// EncryptionKey is a key checkpoint artifacts are encrypted with. The key material of the secret
+// provider is never returned.
+type EncryptionKey struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Provider        string `json:"provider"` // "secret" or "kms"
+	Algorithm       string `json:"algorithm"`
+	KMSKeyURI       string `json:"kmsKeyURI,omitempty"`
+	Fingerprint     string `json:"fingerprint,omitempty"`
+	Description     string `json:"description"`
+	CreatedAt       string `json:"createdAt"`
+	UpdatedAt       string `json:"updatedAt"`
+	SecretName      string `json:"secretName"`
+	SecretNamespace string `json:"secretNamespace"`
+}
+
+// EncryptionKeyInfo represents encryption key information for backup
+type EncryptionKeyInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Provider    string `json:"provider"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// CreateEncryptionKeyRequest represents the request to register an encryption key
+type CreateEncryptionKeyRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Provider string `json:"provider" binding:"required,oneof=secret kms"`
+	// Key is the base64 encoded AES-256 key of the secret provider, a key is generated when empty
+	Key         string `json:"key"`
+	KMSKeyURI   string `json:"kmsKeyURI"`
+	Description string `json:"description"`
+}
+
+// encryptionKeySecretName returns the name of the secret that stores an encryption key
+func encryptionKeySecretName(keyID string) string {
+	return fmt.Sprintf("%s-%s", encryptionSecretPrefix, keyID)
+}
+
+// encryptionKeySecretData validates a request and returns the secret data of the key
+func encryptionKeySecretData(req CreateEncryptionKeyRequest) (map[string][]byte, error) {
+	data := map[string][]byte{
+		"name":        []byte(req.Name),
+		"provider":    []byte(req.Provider),
+		"algorithm":   []byte(migration.EncryptionAlgorithm),
+		"description": []byte(req.Description),
+	}
+	switch req.Provider {
+	case migration.EncryptionProviderSecret:
+		encoded := req.Key
+		if encoded == "" {
+			generated, err := migration.GenerateEncryptionKey()
+			if err != nil {
+				return nil, err
+			}
+			encoded = generated
+		}
+		key, err := migration.ParseEncryptionKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		data["key"] = key
+		data["fingerprint"] = []byte(migration.KeyFingerprint(key))
+	case migration.EncryptionProviderKMS:
+		if err := migration.ValidateKMSKeyURI(req.KMSKeyURI); err != nil {
+			return nil, err
+		}
+		data["kmsKeyURI"] = []byte(req.KMSKeyURI)
+	default:
+		return nil, fmt.Errorf("unsupported encryption key provider: %s", req.Provider)
+	}
+	return data, nil
+}
+
+// secretToEncryptionKey converts a Kubernetes secret to an EncryptionKey struct
+func secretToEncryptionKey(secret *corev1.Secret) EncryptionKey {
+	key := EncryptionKey{
+		ID:        secret.Labels["encryption-key-id"],
+		Name:      string(secret.Data["name"]),
+		Provider:  string(secret.Data["provider"]),
+		Algorithm: string(secret.Data["algorithm"]),
+		KMSKeyURI: string(secret.Data["kmsKeyURI"]),
+		// The key itself is only read by the checkpoint controllers
+		Fingerprint:     string(secret.Data["fingerprint"]),
+		Description:     string(secret.Data["description"]),
+		CreatedAt:       secret.Annotations["backup.dcnlab.com/created-at"],
+		UpdatedAt:       secret.Annotations["backup.dcnlab.com/updated-at"],
+		SecretName:      secret.Name,
+		SecretNamespace: secret.Namespace,
+	}
+	if key.CreatedAt == "" {
+		key.CreatedAt = secret.CreationTimestamp.Format(time.RFC3339)
+	}
+	if key.UpdatedAt == "" {
+		key.UpdatedAt = key.CreatedAt
+	}
+	return key
+}
+
+// listEncryptionKeys returns the registered encryption keys
+func listEncryptionKeys(ctx context.Context) ([]EncryptionKey, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+	secretsUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=backup-encryption",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list encryption keys: %v", err)
+	}
+	keys := make([]EncryptionKey, 0, len(secretsUnstructured.Items))
+	for i := range secretsUnstructured.Items {
+		secret := &corev1.Secret{}
+		if err := convertUnstructuredToTyped(&secretsUnstructured.Items[i], secret); err != nil {
+			klog.ErrorS(err, "Failed to convert secret", "secretName", secretsUnstructured.Items[i].GetName())
+			continue
+		}
+		keys = append(keys, secretToEncryptionKey(secret))
+	}
+	return keys, nil
+}
+
+// getEncryptionKeyByID returns the encryption key with the given ID
+func getEncryptionKeyByID(keyID string) (EncryptionKey, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return EncryptionKey{}, fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+	secretUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Get(context.TODO(), encryptionKeySecretName(keyID), metav1.GetOptions{})
+	if err != nil {
+		return EncryptionKey{}, err
+	}
+	secret := &corev1.Secret{}
+	if err := convertUnstructuredToTyped(secretUnstructured, secret); err != nil {
+		return EncryptionKey{}, fmt.Errorf("failed to convert secret: %v", err)
+	}
+	return secretToEncryptionKey(secret), nil
+}
+
+// encryptionKeyToSpec builds the encryption section of the StatefulMigration spec
+func encryptionKeyToSpec(key EncryptionKey) map[string]interface{} {
+	encryption := map[string]interface{}{
+		"provider":  key.Provider,
+		"algorithm": key.Algorithm,
+		"secretRef": map[string]interface{}{
+			"name": encryptionKeySecretName(key.ID),
+		},
+	}
+	if key.KMSKeyURI != "" {
+		encryption["kmsKeyURI"] = key.KMSKeyURI
+	}
+	return encryption
+}
+
+// setBackupEncryption sets the encryption key of a StatefulMigration, a nil key turns encryption off
+func setBackupEncryption(sm *unstructured.Unstructured, key *EncryptionKey) {
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if key == nil {
+		delete(annotations, encryptionKeyAnnotation)
+		unstructured.RemoveNestedField(sm.Object, "spec", "encryption")
+	} else {
+		annotations[encryptionKeyAnnotation] = key.ID
+		unstructured.SetNestedMap(sm.Object, encryptionKeyToSpec(*key), "spec", "encryption")
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// backupEncryption returns the encryption key of a StatefulMigration, nil when its checkpoints are not encrypted
+func backupEncryption(sm *unstructured.Unstructured, spec StatefulMigrationSpec) *EncryptionKeyInfo {
+	if spec.Encryption == nil {
+		return nil
+	}
+	info := &EncryptionKeyInfo{
+		ID:       sm.GetAnnotations()[encryptionKeyAnnotation],
+		Provider: spec.Encryption.Provider,
+	}
+	if key, err := getEncryptionKeyByID(info.ID); err == nil {
+		info.Name = key.Name
+		info.Fingerprint = key.Fingerprint
+	}
+	return info
+}
+
+// checkRecoveryEncryption returns a conflict when the key of an encrypted backup is not available on the
+// target cluster, where the checkpoint controller decrypts the checkpoint
+func checkRecoveryEncryption(ctx context.Context, backup BackupConfiguration, targetCluster string) *migration.Conflict {
+	if backup.Encryption == nil {
+		return nil
+	}
+	secretName := encryptionKeySecretName(backup.Encryption.ID)
+	conflict := &migration.Conflict{Kind: "EncryptionKey", Namespace: registryNamespace, Name: secretName}
+	targetClient := client.InClusterClientForMemberCluster(targetCluster)
+	if targetClient == nil {
+		conflict.Reason = "cannot be checked, the target cluster is not reachable"
+		return conflict
+	}
+	_, err := targetClient.CoreV1().Secrets(registryNamespace).Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		conflict.Reason = "is not available on the target cluster"
+		return conflict
+	}
+	if err != nil {
+		conflict.Reason = fmt.Sprintf("could not be checked on the target cluster: %v", err)
+		return conflict
+	}
+	return nil
+}
+
+// handleGetEncryptionKeys lists the registered encryption keys
+func handleGetEncryptionKeys(c *gin.Context) {
+	keys, err := listEncryptionKeys(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list encryption keys")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, map[string]interface{}{
+		"encryptionKeys": keys,
+		"total":          len(keys),
+	})
+}
+
+// handleGetEncryptionKey retrieves an encryption key
+func handleGetEncryptionKey(c *gin.Context) {
+	key, err := getEncryptionKeyByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get encryption key", "keyID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, key)
+}
+
+// handleCreateEncryptionKey registers an encryption key and propagates it to the member clusters
+func handleCreateEncryptionKey(c *gin.Context) {
+	var req CreateEncryptionKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind encryption key request")
+		common.FailWithBindError(c, err)
+		return
+	}
+	data, err := encryptionKeySecretData(req)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		common.Fail(c, err)
+		return
+	}
+
+	keyID := generateRegistryID(strings.ToLower(strings.ReplaceAll(req.Name, " ", "-")))
+	secretName := encryptionKeySecretName(keyID)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: registryNamespace,
+			Labels: map[string]string{
+				"app":               "backup-encryption",
+				"encryption-key-id": keyID,
+				"key-provider":      req.Provider,
+			},
+			Annotations: map[string]string{
+				"backup.dcnlab.com/created-at": metav1.Now().Format(time.RFC3339),
+			},
+		},
+		Data: data,
+		Type: corev1.SecretTypeOpaque,
+	}
+	secretUnstructured, err := convertSecretToUnstructured(secret)
+	if err != nil {
+		klog.ErrorS(err, "Failed to convert secret to unstructured")
+		common.Fail(c, err)
+		return
+	}
+	if _, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Create(context.TODO(), secretUnstructured, metav1.CreateOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to create encryption key secret in Karmada")
+		common.Fail(c, err)
+		return
+	}
+
+	// The checkpoint controllers of the member clusters encrypt and decrypt with the key
+	if err := propagateBackupSecret(secretName, registryNamespace, map[string]string{
+		"app":               "backup-encryption",
+		"encryption-key-id": keyID,
+	}); err != nil {
+		klog.ErrorS(err, "Failed to propagate encryption key secret", "secretName", secretName)
+	}
+
+	klog.InfoS("Registered backup encryption key", "keyID", keyID, "provider", req.Provider)
+	common.Success(c, secretToEncryptionKey(secret))
+}
+
+// handleDeleteEncryptionKey deletes an encryption key that no backup uses anymore
+func handleDeleteEncryptionKey(c *gin.Context) {
+	keyID := c.Param("id")
+	service, err := backupService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	backups, err := service.List(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list StatefulMigration CRs")
+		common.Fail(c, err)
+		return
+	}
+	for i := range backups {
+		if backups[i].GetAnnotations()[encryptionKeyAnnotation] == keyID {
+			// The checkpoints of the backup could not be decrypted anymore
+			common.FailWithStatus(c, fmt.Errorf("encryption key %s is used by backup %s", keyID, backups[i].GetName()), http.StatusConflict)
+			return
+		}
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		common.Fail(c, err)
+		return
+	}
+	secretName := encryptionKeySecretName(keyID)
+	if err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to delete encryption key secret from Karmada", "keyID", keyID)
+		common.Fail(c, err)
+		return
+	}
+	err = client.InClusterKarmadaClient().PolicyV1alpha1().PropagationPolicies(registryNamespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to delete PropagationPolicy for encryption key", "keyID", keyID)
+	}
+
+	common.Success(c, ActionResult{Message: "Encryption key deleted successfully"})
+}
+
+// Register encryption key routes
+func init() {
+	r := router.V1()
+
+	encryptionGroup := r.Group("/backup/encryption-keys")
+	{
+		encryptionGroup.GET("", handleGetEncryptionKeys)
+		encryptionGroup.POST("", handleCreateEncryptionKey)
+		encryptionGroup.GET("/:id", handleGetEncryptionKey)
+		encryptionGroup.DELETE("/:id", handleDeleteEncryptionKey)
+	}
+}
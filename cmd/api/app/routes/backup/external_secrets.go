@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/externalsecret"
+)
+
+// externalSecretSelector selects the ExternalSecret of a backup secret, it is part of the PropagationPolicy of
+// every backup secret so that credentials can be moved to an external store after the secret is created
+func externalSecretSelector(secretName string) policyv1alpha1.ResourceSelector {
+	return policyv1alpha1.ResourceSelector{
+		APIVersion: externalsecret.APIVersion,
+		Kind:       externalsecret.Kind,
+		Name:       secretName,
+	}
+}
+
+// applyBackupExternalSecret makes the keys of a backup secret filled from the external store. The ExternalSecret
+// is created in Karmada and propagated with the secret, so the operator of each member cluster merges the
+// credentials into its copy and the checkpoint controllers read them as before. The keys are removed from the
+// secret, which is not saved here.
+func applyBackupExternalSecret(ctx context.Context, secret *corev1.Secret, refs map[string]externalsecret.RemoteRef) error {
+	for key, ref := range refs {
+		if err := ref.Validate(); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	settings, err := externalsecret.Settings()
+	if err != nil {
+		return err
+	}
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return err
+	}
+	obj := externalsecret.New(secret.Name, secret.Namespace, settings, refs, secret.Labels)
+	if err := externalsecret.Apply(ctx, karmadaDynamicClient, obj); err != nil {
+		return fmt.Errorf("failed to apply ExternalSecret %s: %v", secret.Name, err)
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[externalsecret.ReferenceAnnotation] = secret.Name
+	for key := range refs {
+		delete(secret.Data, key)
+	}
+	return nil
+}
+
+// removeBackupExternalSecret deletes the ExternalSecret of a backup secret when its credentials are given in
+// plain text again
+func removeBackupExternalSecret(ctx context.Context, secret *corev1.Secret) error {
+	if externalsecret.Reference(secret) == "" {
+		return nil
+	}
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return err
+	}
+	if err := externalsecret.Delete(ctx, karmadaDynamicClient, secret.Namespace, externalsecret.Reference(secret)); err != nil {
+		return fmt.Errorf("failed to delete ExternalSecret %s: %v", externalsecret.Reference(secret), err)
+	}
+	delete(secret.Annotations, externalsecret.ReferenceAnnotation)
+	return nil
+}
+
+// deleteBackupExternalSecret deletes the ExternalSecret of a deleted backup secret, if it had one
+func deleteBackupExternalSecret(ctx context.Context, secretName string) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		return
+	}
+	if err := externalsecret.Delete(ctx, karmadaDynamicClient, registryNamespace, secretName); err != nil {
+		klog.ErrorS(err, "Failed to delete ExternalSecret of backup secret", "secretName", secretName)
+	}
+}
+
+// backupSecretValue returns a key of a backup secret. Credentials filled from the external store are not in the
+// Karmada secret, they are read from the copy of a member cluster the operator has synced them to.
+func backupSecretValue(ctx context.Context, secret *corev1.Secret, key string) (string, error) {
+	value, err := externalsecret.Value(secret, key)
+	if !errors.Is(err, externalsecret.ErrNotSynced) {
+		return value, err
+	}
+
+	memberClusters, listErr := getMemberClusters()
+	if listErr != nil {
+		return "", fmt.Errorf("%v: %v", err, listErr)
+	}
+	for _, cluster := range memberClusters {
+		kubeClient := client.InClusterClientForMemberCluster(cluster)
+		if kubeClient == nil {
+			continue
+		}
+		synced, getErr := kubeClient.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+		if getErr != nil {
+			klog.V(4).InfoS("Failed to read synced backup secret", "cluster", cluster, "secretName", secret.Name, "err", getErr)
+			continue
+		}
+		if value := string(synced.Data[key]); value != "" {
+			return value, nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	platformv1 "github.com/karmada-io/dashboard/pkg/apis/platform/v1"
+)
+
+// backupServer serves the backup configurations over gRPC with the functions of the REST handlers
+type backupServer struct {
+	platformv1.UnimplementedBackupServiceServer
+}
+
+// recoveryServer serves the recoveries over gRPC with the functions of the REST handlers
+type recoveryServer struct {
+	platformv1.UnimplementedRecoveryServiceServer
+}
+
+// grpcBackupError returns the gRPC status of an error changing a backup configuration or recovery record, with
+// the code of the HTTP status of failBackupChange
+func grpcBackupError(err error) error {
+	if status := backupChangeStatus(err); status != 0 {
+		return router.GRPCErrorWithStatus(err, status)
+	}
+	return router.GRPCError(err)
+}
+
+// validateGRPCRequest checks a request converted from a gRPC message against its binding tags, like the REST
+// handlers bind it
+func validateGRPCRequest(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return router.GRPCErrorWithStatus(err, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// ListBackups lists the backup configurations
+func (s *backupServer) ListBackups(ctx context.Context, _ *platformv1.ListBackupsRequest) (*platformv1.ListBackupsResponse, error) {
+	backups, err := listBackups(ctx)
+	if err != nil {
+		return nil, router.GRPCError(err)
+	}
+	resp := &platformv1.ListBackupsResponse{}
+	for _, backup := range backups {
+		resp.Backups = append(resp.Backups, backupMessage(backup))
+	}
+	return resp, nil
+}
+
+// GetBackup returns a backup configuration
+func (s *backupServer) GetBackup(ctx context.Context, req *platformv1.GetBackupRequest) (*platformv1.Backup, error) {
+	backup, err := getBackup(ctx, req.GetId())
+	if err != nil {
+		return nil, router.GRPCError(err)
+	}
+	return backupMessage(backup), nil
+}
+
+// CreateBackup creates a backup configuration
+func (s *backupServer) CreateBackup(ctx context.Context, req *platformv1.CreateBackupRequest) (*platformv1.Backup, error) {
+	createReq := CreateBackupRequest{
+		Name:             req.GetName(),
+		Cluster:          req.GetCluster(),
+		ResourceType:     req.GetResourceType(),
+		ResourceName:     req.GetResourceName(),
+		Namespace:        req.GetNamespace(),
+		RegistryID:       req.GetRegistryId(),
+		Repository:       req.GetRepository(),
+		StorageBackendID: req.GetStorageBackendId(),
+		Schedule: ScheduleConfig{
+			Type:    req.GetSchedule().GetType(),
+			Value:   req.GetSchedule().GetValue(),
+			Enabled: req.GetSchedule().GetEnabled(),
+		},
+	}
+	if err := validateGRPCRequest(&createReq); err != nil {
+		return nil, err
+	}
+
+	backup, err := createBackup(ctx, createReq)
+	if err != nil {
+		return nil, grpcBackupError(err)
+	}
+	return backupMessage(backup), nil
+}
+
+// DeleteBackup moves a backup configuration to the trash
+func (s *backupServer) DeleteBackup(ctx context.Context, req *platformv1.DeleteBackupRequest) (*platformv1.DeleteBackupResponse, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, router.GRPCError(err)
+	}
+	if err := deleteRecord(ctx, service, req.GetId(), router.GRPCUser(ctx), false); err != nil {
+		return nil, grpcBackupError(err)
+	}
+	return &platformv1.DeleteBackupResponse{}, nil
+}
+
+// ExecuteBackup executes a backup immediately
+func (s *backupServer) ExecuteBackup(ctx context.Context, req *platformv1.ExecuteBackupRequest) (*platformv1.ExecuteBackupResponse, error) {
+	if _, _, err := executeBackup(router.GRPCGinContext(ctx), req.GetId()); err != nil {
+		return nil, grpcBackupError(err)
+	}
+	return &platformv1.ExecuteBackupResponse{Message: "Backup execution triggered successfully"}, nil
+}
+
+// backupMessage returns the gRPC message of a backup configuration
+func backupMessage(backup BackupConfiguration) *platformv1.Backup {
+	message := &platformv1.Backup{
+		Id:           backup.ID,
+		Name:         backup.Name,
+		Cluster:      backup.Cluster,
+		ResourceType: backup.ResourceType,
+		ResourceName: backup.ResourceName,
+		Namespace:    backup.Namespace,
+		RegistryId:   backup.Registry.ID,
+		Repository:   backup.Repository,
+		Schedule: &platformv1.Schedule{
+			Type:    backup.Schedule.Type,
+			Value:   backup.Schedule.Value,
+			Enabled: backup.Schedule.Enabled,
+		},
+		Status:     backup.Status,
+		LastBackup: backup.LastBackup,
+		NextBackup: backup.NextBackup,
+		CreatedAt:  backup.CreatedAt,
+		UpdatedAt:  backup.UpdatedAt,
+	}
+	if backup.Storage != nil {
+		message.StorageBackendId = backup.Storage.ID
+	}
+	return message
+}
+
+// ListRecoveries lists the recovery records
+func (s *recoveryServer) ListRecoveries(ctx context.Context, _ *platformv1.ListRecoveriesRequest) (*platformv1.ListRecoveriesResponse, error) {
+	recoveries, err := listRecoveries(ctx)
+	if err != nil {
+		return nil, router.GRPCError(err)
+	}
+	resp := &platformv1.ListRecoveriesResponse{}
+	for _, recovery := range recoveries {
+		resp.Recoveries = append(resp.Recoveries, recoveryMessage(recovery))
+	}
+	return resp, nil
+}
+
+// GetRecovery returns a recovery record
+func (s *recoveryServer) GetRecovery(ctx context.Context, req *platformv1.GetRecoveryRequest) (*platformv1.Recovery, error) {
+	recovery, err := getRecovery(ctx, req.GetId())
+	if err != nil {
+		return nil, router.GRPCError(err)
+	}
+	return recoveryMessage(recovery), nil
+}
+
+// CreateRecovery creates a recovery operation
+func (s *recoveryServer) CreateRecovery(ctx context.Context, req *platformv1.CreateRecoveryRequest) (*platformv1.Recovery, error) {
+	createReq := CreateRecoveryRequest{
+		Name:            req.GetName(),
+		BackupID:        req.GetBackupId(),
+		TargetCluster:   req.GetTargetCluster(),
+		RecoveryType:    req.GetRecoveryType(),
+		TargetName:      req.GetTargetName(),
+		TargetNamespace: req.GetTargetNamespace(),
+	}
+	if err := validateGRPCRequest(&createReq); err != nil {
+		return nil, err
+	}
+
+	recovery, _, err := createRecovery(ctx, createReq)
+	if err != nil {
+		return nil, grpcBackupError(err)
+	}
+	return recoveryMessage(recovery), nil
+}
+
+// ExecuteRecovery starts the execution of a recovery operation
+func (s *recoveryServer) ExecuteRecovery(ctx context.Context, req *platformv1.ExecuteRecoveryRequest) (*platformv1.Recovery, error) {
+	recovery, err := executeRecovery(router.GRPCGinContext(ctx), req.GetId(), router.GRPCUser(ctx))
+	if err != nil {
+		return nil, grpcBackupError(err)
+	}
+	return recoveryMessage(recovery), nil
+}
+
+// CancelRecovery cancels a running recovery operation
+func (s *recoveryServer) CancelRecovery(ctx context.Context, req *platformv1.CancelRecoveryRequest) (*platformv1.Recovery, error) {
+	recovery, err := cancelRecovery(ctx, req.GetId(), router.GRPCUser(ctx))
+	if err != nil {
+		return nil, grpcBackupError(err)
+	}
+	return recoveryMessage(recovery), nil
+}
+
+// recoveryMessage returns the gRPC message of a recovery record
+func recoveryMessage(recovery RecoveryRecord) *platformv1.Recovery {
+	return &platformv1.Recovery{
+		Id:            recovery.ID,
+		Name:          recovery.Name,
+		BackupId:      recovery.BackupID,
+		BackupName:    recovery.BackupName,
+		SourceCluster: recovery.SourceCluster,
+		TargetCluster: recovery.TargetCluster,
+		ResourceType:  recovery.ResourceType,
+		ResourceName:  recovery.ResourceName,
+		Namespace:     recovery.Namespace,
+		RecoveryType:  recovery.RecoveryType,
+		Status:        recovery.Status,
+		Progress:      int32(recovery.Progress),
+		Error:         recovery.Error,
+		StartedAt:     recovery.StartedAt,
+		CompletedAt:   recovery.CompletedAt,
+		CreatedAt:     recovery.CreatedAt,
+		UpdatedAt:     recovery.UpdatedAt,
+	}
+}
+
+func init() {
+	platformv1.RegisterBackupServiceServer(router.GRPC(), &backupServer{})
+	platformv1.RegisterRecoveryServiceServer(router.GRPC(), &recoveryServer{})
+	router.RegisterGateway(platformv1.RegisterBackupServiceHandlerFromEndpoint)
+	router.RegisterGateway(platformv1.RegisterRecoveryServiceHandlerFromEndpoint)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a POST without repeating its effect
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotencyConfigMapName stores the responses of completed requests, one entry per user and key
+	idempotencyConfigMapName = "backup-idempotency-keys"
+	idempotencyKeyTTL        = 24 * time.Hour
+	maxIdempotencyRecords    = 500
+	// maxIdempotentResponseSize keeps the records well below the ConfigMap size limit
+	maxIdempotentResponseSize = 64 * 1024
+	maxIdempotencyKeyLength   = 255
+)
+
+// IdempotencyRecord is the stored outcome of a request made with an idempotency key
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+var (
+	// idempotencyMu serializes updates of the ConfigMap and guards inFlightKeys
+	idempotencyMu sync.Mutex
+	inFlightKeys  = map[string]bool{}
+)
+
+// responseRecorder keeps a copy of the response body for the idempotency record
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyRecordKey scopes keys to the user, so users cannot read each other's responses
+func idempotencyRecordKey(user, key string) string {
+	sum := sha256.Sum256([]byte(user + "|" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestFingerprint identifies a request by method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func getIdempotencyRecord(ctx context.Context, recordKey string) (*IdempotencyRecord, error) {
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, idempotencyConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[recordKey]
+	if !ok {
+		return nil, nil
+	}
+	record := &IdempotencyRecord{}
+	if err := json.Unmarshal([]byte(data), record); err != nil {
+		return nil, err
+	}
+	if createdAt, err := time.Parse(time.RFC3339, record.CreatedAt); err == nil && time.Since(createdAt) > idempotencyKeyTTL {
+		return nil, nil
+	}
+	return record, nil
+}
+
+// saveIdempotencyRecord stores a record and drops expired ones, keeping at most maxIdempotencyRecords
+func saveIdempotencyRecord(ctx context.Context, recordKey string, record *IdempotencyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	configMaps := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace())
+	cm, err := configMaps.Get(ctx, idempotencyConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      idempotencyConfigMapName,
+				Namespace: config.GetNamespace(),
+			},
+			Data: map[string]string{recordKey: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[recordKey] = string(data)
+
+	type entry struct {
+		key       string
+		createdAt time.Time
+	}
+	var entries []entry
+	for key, value := range cm.Data {
+		var r IdempotencyRecord
+		createdAt := time.Time{}
+		if json.Unmarshal([]byte(value), &r) == nil {
+			createdAt, _ = time.Parse(time.RFC3339, r.CreatedAt)
+		}
+		if time.Since(createdAt) > idempotencyKeyTTL {
+			delete(cm.Data, key)
+			continue
+		}
+		entries = append(entries, entry{key: key, createdAt: createdAt})
+	}
+	if len(entries) > maxIdempotencyRecords {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].createdAt.Before(entries[j].createdAt)
+		})
+		for _, e := range entries[:len(entries)-maxIdempotencyRecords] {
+			delete(cm.Data, e.key)
+		}
+	}
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// replayable reports whether a response should be replayed. Failed requests are not recorded,
+// so they can be retried with the same key.
+func replayable(status int, body []byte) bool {
+	if status < 200 || status >= 300 {
+		return false
+	}
+	var response common.BaseResponse
+	if err := json.Unmarshal(body, &response); err == nil && response.Code != 0 && response.Code != 200 {
+		return false
+	}
+	return true
+}
+
+// idempotencyMiddleware replays the stored response of a POST retried with the same Idempotency-Key
+// header instead of running it again. Requests without the header are not affected.
+func idempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			common.FailWithStatus(c, fmt.Errorf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength), http.StatusBadRequest)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			common.FailWithStatus(c, err, http.StatusBadRequest)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		recordKey := idempotencyRecordKey(utilauth.GetAuthenticatedUser(c), key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+		ctx := c.Request.Context()
+
+		idempotencyMu.Lock()
+		if inFlightKeys[recordKey] {
+			idempotencyMu.Unlock()
+			common.FailWithStatus(c, fmt.Errorf("a request with this %s is still in progress", IdempotencyKeyHeader), http.StatusConflict)
+			c.Abort()
+			return
+		}
+		record, err := getIdempotencyRecord(ctx, recordKey)
+		if err != nil {
+			idempotencyMu.Unlock()
+			klog.ErrorS(err, "Failed to read idempotency record")
+			common.Fail(c, err)
+			c.Abort()
+			return
+		}
+		if record != nil {
+			idempotencyMu.Unlock()
+			if record.Fingerprint != fingerprint {
+				common.FailWithStatus(c, fmt.Errorf("%s was already used for a different request", IdempotencyKeyHeader), http.StatusUnprocessableEntity)
+				c.Abort()
+				return
+			}
+			klog.V(4).InfoS("Replaying idempotent request", "path", record.Path, "createdAt", record.CreatedAt)
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.Status, record.ContentType, []byte(record.Body))
+			c.Abort()
+			return
+		}
+		inFlightKeys[recordKey] = true
+		idempotencyMu.Unlock()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			idempotencyMu.Lock()
+			defer idempotencyMu.Unlock()
+			delete(inFlightKeys, recordKey)
+
+			status := recorder.Status()
+			if !replayable(status, recorder.body.Bytes()) || recorder.body.Len() > maxIdempotentResponseSize {
+				return
+			}
+			err := saveIdempotencyRecord(context.TODO(), recordKey, &IdempotencyRecord{
+				Fingerprint: fingerprint,
+				Method:      c.Request.Method,
+				Path:        c.Request.URL.Path,
+				Status:      status,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.String(),
+				CreatedAt:   time.Now().Format(time.RFC3339),
+			})
+			if err != nil {
+				klog.ErrorS(err, "Failed to store idempotency record", "path", c.Request.URL.Path)
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// handleGetImageRewrites returns the image rewrite rules of restores by target cluster
+func handleGetImageRewrites(c *gin.Context) {
+	common.Success(c, migration.ImageRewrites())
+}
+
+// handlePutImageRewrites replaces the image rewrite rules of restores by target cluster in the runtime config.
+// The rules apply to the restores created afterwards, running migrations and recoveries keep their images.
+func handlePutImageRewrites(c *gin.Context) {
+	var rewrites map[string][]migration.ImageRewriteRule
+	if err := c.ShouldBindJSON(&rewrites); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := migration.ValidateImageRewrites(rewrites); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	oldConfig := config.GetDashboardConfig()
+	newConfig := oldConfig
+	newConfig.Runtime.ImageRewrites = migration.ImageRewritesConfig(rewrites)
+	k8sClient := client.InClusterClient()
+	if changed := config.ChangedFields(oldConfig, newConfig); len(changed) > 0 {
+		if err := config.UpdateDashboardConfig(k8sClient, newConfig); err != nil {
+			klog.ErrorS(err, "Failed to update image rewrite rules")
+			common.Fail(c, err)
+			return
+		}
+		user := utilauth.GetAuthenticatedUser(c)
+		change := config.ConfigChange{Time: time.Now().Format(time.RFC3339), User: user, Fields: changed}
+		if err := config.RecordConfigChange(c, k8sClient, change); err != nil {
+			klog.ErrorS(err, "Failed to record dashboard config change", "changed", changed)
+		}
+		klog.InfoS("Updated image rewrite rules", "user", user, "clusters", len(newConfig.Runtime.ImageRewrites))
+	}
+	common.Success(c, migration.ImageRewrites())
+}
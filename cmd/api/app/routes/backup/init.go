@@ -21,9 +21,29 @@ package backup
 //
 // Features include:
 // - Registry management for container image storage
+// - Harbor projects provisioned for users and projects, with pull secrets in their namespaces
+// - Storage backend management (S3, MinIO, PVC) for checkpoint storage
+// - Registry passwords and storage keys read from an external store through ExternalSecrets
+// - Encryption keys for checkpoint artifacts, stored as secrets or referenced in a KMS
 // - Backup configuration and scheduling for pods and statefulsets
+// - Export and import of backup configurations as YAML bundles across environments
+// - Batch execution of backup configurations selected by ID, cluster, namespace or labels
+// - Checkpoint retention policies and garbage collection
+// - Signed, immutable attestations of checkpoint digests, verified before recovery
+// - CSI volume snapshots of workload claims, restored during recovery
 // - Recovery operations for cross-cluster migration
+// - Network connectivity tests between the clusters of a migration and its registry
+// - Workload locks serializing the recoveries and migrations of a workload across API replicas
+// - Backup schedules suspended, and checkpoints and restores onto a cluster rejected, while it is in maintenance
+// - Checkpoint upload bandwidth and concurrency throttling, globally and per backup
+// - Trash for deleted backup configurations and recovery records, purged after a retention window
+// - One-step migration that checkpoints a workload and restores it on another cluster
 // - Settings for cluster management and controller deployment
+// - One install, upgrade or uninstall of the controller per cluster at a time
+// - Idempotency-Key header on POST requests, so retries replay the original response
+// - Controller version catalog with a Kubernetes compatibility matrix
+// - Health-check reconciliation of installed controllers with remediation history
+// - Watch based cache of checkpoint resources across member clusters
 //
 // The package integrates with Karmada for multi-cluster deployment
 // and uses StatefulMigration CRDs for backup/recovery operations.
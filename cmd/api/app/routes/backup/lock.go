@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// Operations that lock the workload they checkpoint or restore
+const (
+	lockOperationRecovery  = "recovery"
+	lockOperationMigration = "migration"
+)
+
+// workloadLockTTL bounds how long a lock outlives an operation that ended without releasing it and could not
+// be found to be done, such as after the API lost access to its record
+const workloadLockTTL = 6 * time.Hour
+
+// workloadLocks returns the locks of the workloads, stored as Leases in the dashboard namespace
+func workloadLocks() *migration.Locks {
+	return migration.NewLocks(client.InClusterClient(), config.GetNamespace(), lockHolderDone)
+}
+
+// lockHolderDone reports whether the recovery or migration holding a lock has ended, so its lock is taken
+// over when it could not release it. A holder whose record is gone is done.
+func lockHolderDone(ctx context.Context, holder migration.LockHolder) bool {
+	switch holder.Operation {
+	case lockOperationRecovery:
+		service, err := recoveryService()
+		if err != nil {
+			return false
+		}
+		sm, err := service.Get(ctx, holder.ID)
+		if apierrors.IsNotFound(err) {
+			return true
+		}
+		if err != nil {
+			return false
+		}
+		switch statefulMigrationToRecovery(sm).Status {
+		case "completed", "failed", "cancelled":
+			return true
+		}
+		return false
+	case lockOperationMigration:
+		status, err := getMigrationStatus(ctx, holder.ID)
+		if apierrors.IsNotFound(err) {
+			return true
+		}
+		if err != nil {
+			return false
+		}
+		return status.Phase == MigrationPhaseCompleted || status.Phase == MigrationPhaseFailed
+	}
+	return false
+}
+
+// recoveryWorkload returns the workload a recovery restores, identified by its source so that recoveries of
+// the same workload to different clusters are serialized
+func recoveryWorkload(recovery RecoveryRecord) migration.Workload {
+	return migration.Workload{
+		Cluster:   recovery.SourceCluster,
+		Namespace: recovery.Namespace,
+		Kind:      recovery.ResourceType,
+		Name:      recovery.ResourceName,
+	}
+}
+
+// migrationWorkload returns the workload a migration moves
+func migrationWorkload(status *MigrationStatus) migration.Workload {
+	return migration.Workload{
+		Cluster:   status.SourceCluster,
+		Namespace: status.Namespace,
+		Kind:      status.ResourceType,
+		Name:      status.ResourceName,
+	}
+}
+
+// migrationLockHolder returns the holder of the lock of a migration
+func migrationLockHolder(status *MigrationStatus) migration.LockHolder {
+	return migration.LockHolder{Operation: lockOperationMigration, ID: status.ID}
+}
+
+// releaseWorkloadLock releases the lock of an operation, a lock that cannot be released is left to expire
+func releaseWorkloadLock(ctx context.Context, workload migration.Workload, holder migration.LockHolder) {
+	if err := workloadLocks().Release(ctx, workload, holder); err != nil {
+		klog.ErrorS(err, "Failed to release workload lock", "workload", workload.String(), "operation", holder.Operation, "id", holder.ID)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	clusterresource "github.com/karmada-io/dashboard/pkg/resource/cluster"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// suspendedScheduleAnnotation keeps the schedule of a backup configuration whose cluster is in maintenance.
+// The schedule is removed from the spec meanwhile so the controller does not take checkpoints.
+const suspendedScheduleAnnotation = "backup.dcnlab.com/suspended-schedule"
+
+// karmadaClient returns the client the maintenance and cordon checks read clusters with
+var karmadaClient = client.InClusterKarmadaClient
+
+// errClusterInMaintenance is returned when an operation targets a cluster in maintenance mode
+var errClusterInMaintenance = errors.New("cluster is in maintenance mode")
+
+// checkNotInMaintenance returns an error when one of the clusters is in maintenance mode
+func checkNotInMaintenance(ctx context.Context, clusterNames ...string) error {
+	for _, clusterName := range clusterNames {
+		if clusterName == "" {
+			continue
+		}
+		maintenance, err := clusterresource.GetMaintenance(ctx, karmadaClient(), clusterName)
+		if err != nil {
+			// A missing or unreachable cluster is reported by the operation itself
+			klog.V(4).InfoS("Failed to check cluster maintenance", "cluster", clusterName, "error", err)
+			continue
+		}
+		if maintenance != nil {
+			if maintenance.Reason != "" {
+				return fmt.Errorf("%w: %s since %s (%s)", errClusterInMaintenance, clusterName, maintenance.Since, maintenance.Reason)
+			}
+			return fmt.Errorf("%w: %s since %s", errClusterInMaintenance, clusterName, maintenance.Since)
+		}
+	}
+	return nil
+}
+
+// errClusterCordoned is returned when a new backup configuration or migration targets a cordoned cluster
+var errClusterCordoned = errors.New("cluster is cordoned from new backups and migrations")
+
+// checkNotCordoned returns an error when one of the clusters is cordoned
+func checkNotCordoned(ctx context.Context, clusterNames ...string) error {
+	for _, clusterName := range clusterNames {
+		if clusterName == "" {
+			continue
+		}
+		cordon, err := clusterresource.GetCordon(ctx, karmadaClient(), clusterName)
+		if err != nil {
+			// A missing or unreachable cluster is reported by the operation itself
+			klog.V(4).InfoS("Failed to check cluster cordon", "cluster", clusterName, "error", err)
+			continue
+		}
+		if cordon != nil {
+			if cordon.Reason != "" {
+				return fmt.Errorf("%w: %s since %s (%s)", errClusterCordoned, clusterName, cordon.Since, cordon.Reason)
+			}
+			return fmt.Errorf("%w: %s since %s", errClusterCordoned, clusterName, cordon.Since)
+		}
+	}
+	return nil
+}
+
+// sourceClusters returns the source clusters of a StatefulMigration
+func sourceClusters(sm *unstructured.Unstructured) []string {
+	clusters, _, _ := unstructured.NestedStringSlice(sm.Object, "spec", "sourceClusters")
+	return clusters
+}
+
+// SuspendClusterSchedules suspends the schedules of the backup configurations of a cluster entering maintenance
+// and returns their IDs. Configurations managed by GitOps are left alone, their checkpoints are rejected instead.
+func SuspendClusterSchedules(ctx context.Context, clusterName string) ([]string, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, err
+	}
+	items, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var suspended []string
+	for i := range items {
+		sm := &items[i]
+		schedule, _, _ := unstructured.NestedString(sm.Object, "spec", "schedule")
+		if schedule == "" || !containsString(sourceClusters(sm), clusterName) || migration.ManagedBy(sm) == migration.ManagedByGitOps {
+			continue
+		}
+		backupID := statefulMigrationID(sm)
+		_, err := service.Mutate(ctx, backupID, func(obj *unstructured.Unstructured) error {
+			schedule, _, _ := unstructured.NestedString(obj.Object, "spec", "schedule")
+			if schedule == "" {
+				return nil
+			}
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[suspendedScheduleAnnotation] = schedule
+			obj.SetAnnotations(annotations)
+			unstructured.RemoveNestedField(obj.Object, "spec", "schedule")
+			return nil
+		})
+		if err != nil {
+			return suspended, fmt.Errorf("failed to suspend the schedule of backup %s: %w", backupID, err)
+		}
+		suspended = append(suspended, backupID)
+	}
+	return suspended, nil
+}
+
+// ResumeClusterSchedules restores the schedules suspended for a cluster leaving maintenance and returns the IDs
+// of their backup configurations. Schedules of configurations with another source cluster still in maintenance
+// stay suspended.
+func ResumeClusterSchedules(ctx context.Context, clusterName string) ([]string, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, err
+	}
+	items, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resumed []string
+	for i := range items {
+		sm := &items[i]
+		clusters := sourceClusters(sm)
+		if _, ok := sm.GetAnnotations()[suspendedScheduleAnnotation]; !ok || !containsString(clusters, clusterName) {
+			continue
+		}
+		others := make([]string, 0, len(clusters))
+		for _, other := range clusters {
+			if other != clusterName {
+				others = append(others, other)
+			}
+		}
+		if checkNotInMaintenance(ctx, others...) != nil {
+			continue
+		}
+
+		backupID := statefulMigrationID(sm)
+		_, err := service.Mutate(ctx, backupID, func(obj *unstructured.Unstructured) error {
+			annotations := obj.GetAnnotations()
+			schedule, ok := annotations[suspendedScheduleAnnotation]
+			if !ok {
+				return nil
+			}
+			delete(annotations, suspendedScheduleAnnotation)
+			obj.SetAnnotations(annotations)
+			return unstructured.SetNestedField(obj.Object, schedule, "spec", "schedule")
+		})
+		if err != nil {
+			return resumed, fmt.Errorf("failed to resume the schedule of backup %s: %w", backupID, err)
+		}
+		resumed = append(resumed, backupID)
+	}
+	return resumed, nil
+}
+
+// statefulMigrationID returns the backup ID of a StatefulMigration
+func statefulMigrationID(sm *unstructured.Unstructured) string {
+	if id := sm.GetLabels()["backup-id"]; id != "" {
+		return id
+	}
+	return migration.Backup.ID(sm.GetName())
+}
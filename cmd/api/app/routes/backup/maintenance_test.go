@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	karmadafake "github.com/karmada-io/karmada/pkg/generated/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterresource "github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// useFakeKarmadaClient makes the cluster checks read the clusters from a fake client with member1 and member2,
+// member2 being cordoned
+func useFakeKarmadaClient(t *testing.T) {
+	fakeClient := karmadafake.NewSimpleClientset(
+		&v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}},
+		&v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member2"}},
+	)
+	if _, err := clusterresource.CordonCluster(context.TODO(), fakeClient, "member2", clusterresource.Cordon{
+		Since: "2024-05-01T10:00:00Z", By: "alice", Reason: "decommission",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	original := karmadaClient
+	karmadaClient = func() karmadaclientset.Interface { return fakeClient }
+	t.Cleanup(func() { karmadaClient = original })
+}
+
+func TestCheckNotCordoned(t *testing.T) {
+	useFakeKarmadaClient(t)
+	tests := []struct {
+		name     string
+		clusters []string
+		wantErr  bool
+	}{
+		{name: "no clusters"},
+		{name: "uncordoned cluster", clusters: []string{"member1"}},
+		{name: "cordoned cluster", clusters: []string{"member2"}, wantErr: true},
+		{name: "one of several clusters cordoned", clusters: []string{"member1", "", "member2"}, wantErr: true},
+		{name: "missing cluster is left to the operation", clusters: []string{"member3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNotCordoned(context.TODO(), tt.clusters...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkNotCordoned() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errClusterCordoned) {
+				t.Errorf("checkNotCordoned() error = %v, want errClusterCordoned", err)
+			}
+		})
+	}
+}
+
+func TestCreateRecoveryOnCordonedCluster(t *testing.T) {
+	useFakeKarmadaClient(t)
+	_, _, err := createRecovery(context.TODO(), CreateRecoveryRequest{
+		Name:          "restore-db",
+		BackupID:      "db-backup",
+		TargetCluster: "member2",
+		RecoveryType:  "restore",
+	})
+	if !errors.Is(err, errClusterCordoned) {
+		t.Fatalf("createRecovery() error = %v, want errClusterCordoned", err)
+	}
+	if status := backupChangeStatus(err); status != http.StatusConflict {
+		t.Errorf("backupChangeStatus() = %d, want %d", status, http.StatusConflict)
+	}
+}
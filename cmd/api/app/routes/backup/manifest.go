@@ -0,0 +1,386 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
+)
+
+// manifestBundle describes the manifests installed together for a controller
+type manifestBundle struct {
+	Name      string
+	Namespace string
+	// Files are the manifest paths in the operator repository, applied in order
+	Files []string
+	// ImageContainer is the container whose image is set to the requested version
+	ImageContainer string
+	// ImageComponent is the operator tag prefix of the container image
+	ImageComponent string
+}
+
+// checkpointBackupBundle is the checkpoint backup controller installed on member clusters
+var checkpointBackupBundle = manifestBundle{
+	Name:      "checkpoint-backup",
+	Namespace: "stateful-migration",
+	Files: []string{
+		"config/rbac/checkpoint_backup_rbac.yaml",
+		"deploy/checkpoint-backup-daemonset.yaml",
+	},
+	ImageContainer: "controller",
+	ImageComponent: componentCheckpointBackup,
+}
+
+// kinds that are shared between clusters and keep their names
+var unsuffixedKinds = map[string]bool{
+	"Namespace":                true,
+	"CustomResourceDefinition": true,
+}
+
+// podSpecPaths locates the pod spec of the workload kinds
+var podSpecPaths = map[string][]string{
+	"Pod":         {"spec"},
+	"Deployment":  {"spec", "template", "spec"},
+	"DaemonSet":   {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"ReplicaSet":  {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// clusterResourceName returns the name of a bundle resource for a cluster. It is idempotent.
+func clusterResourceName(name, clusterName string) string {
+	if strings.HasSuffix(name, "-"+clusterName) {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, clusterName)
+}
+
+// loadManifestBundle fetches and decodes the manifests of a bundle
+func loadManifestBundle(bundle manifestBundle) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	for _, file := range bundle.Files {
+		url := config.ManifestURL(file)
+		content, err := fetchYAMLFromURL(url)
+		if err != nil {
+			return nil, err
+		}
+		decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(content)), 4096)
+		for {
+			var rawObj map[string]interface{}
+			err := decoder.Decode(&rawObj)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode YAML from %s: %v", url, err)
+			}
+			if rawObj == nil {
+				continue
+			}
+			obj := &unstructured.Unstructured{Object: rawObj}
+			if obj.GetNamespace() == "" && bundle.Namespace != "" && obj.GetKind() != "ClusterRole" &&
+				obj.GetKind() != "ClusterRoleBinding" && !unsuffixedKinds[obj.GetKind()] {
+				obj.SetNamespace(bundle.Namespace)
+			}
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// suffixBundleForCluster renames every resource of the bundle for the cluster and rewrites
+// the references between them, so several clusters can be served from one Karmada control plane
+func suffixBundleForCluster(objects []*unstructured.Unstructured, clusterName string) error {
+	// the label lets the orphan collector delete the resources once the cluster is gone
+	return suffixBundle(objects, clusterName, orphan.Labels(clusterName))
+}
+
+// suffixBundleForSelector renames every resource of the bundle for a cluster label selector. The resources
+// are not tied to a cluster, so the orphan collector leaves them alone.
+func suffixBundleForSelector(objects []*unstructured.Unstructured, selectorName string) error {
+	return suffixBundle(objects, selectorName, selectorResourceLabels(selectorName))
+}
+
+// selectorResourceLabels returns the labels of the resources installed for a cluster label selector
+func selectorResourceLabels(selectorName string) map[string]string {
+	labels := orphan.Labels("")
+	labels[migration.ControllerSelectorLabel] = selectorName
+	return labels
+}
+
+// suffixBundle adds the suffix to the name of every resource of the bundle, sets the labels on them
+// and rewrites the references between them
+func suffixBundle(objects []*unstructured.Unstructured, suffix string, labels map[string]string) error {
+	// kind/name of every renamed resource
+	renamed := make(map[string]string)
+	for _, obj := range objects {
+		if unsuffixedKinds[obj.GetKind()] {
+			continue
+		}
+		newName := clusterResourceName(obj.GetName(), suffix)
+		renamed[obj.GetKind()+"/"+obj.GetName()] = newName
+		renamed[obj.GetKind()+"/"+newName] = newName
+		obj.SetName(newName)
+		objLabels := obj.GetLabels()
+		if objLabels == nil {
+			objLabels = map[string]string{}
+		}
+		for key, value := range labels {
+			objLabels[key] = value
+		}
+		obj.SetLabels(objLabels)
+	}
+	lookup := func(kind, name string) (string, bool) {
+		newName, ok := renamed[kind+"/"+name]
+		return newName, ok
+	}
+
+	for _, obj := range objects {
+		if err := rewriteRoleRef(obj, lookup); err != nil {
+			return err
+		}
+		if err := rewriteSubjects(obj, lookup); err != nil {
+			return err
+		}
+		if path, ok := podSpecPaths[obj.GetKind()]; ok {
+			if err := rewritePodSpec(obj, path, lookup); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type renameLookup func(kind, name string) (string, bool)
+
+func rewriteRoleRef(obj *unstructured.Unstructured, lookup renameLookup) error {
+	roleRef, found, err := unstructured.NestedMap(obj.Object, "roleRef")
+	if err != nil || !found {
+		return err
+	}
+	kind, _ := roleRef["kind"].(string)
+	name, _ := roleRef["name"].(string)
+	if newName, ok := lookup(kind, name); ok {
+		roleRef["name"] = newName
+		return unstructured.SetNestedMap(obj.Object, roleRef, "roleRef")
+	}
+	return nil
+}
+
+func rewriteSubjects(obj *unstructured.Unstructured, lookup renameLookup) error {
+	subjects, found, err := unstructured.NestedSlice(obj.Object, "subjects")
+	if err != nil || !found {
+		return err
+	}
+	for i, subject := range subjects {
+		subjectMap, ok := subject.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := subjectMap["kind"].(string)
+		name, _ := subjectMap["name"].(string)
+		if newName, ok := lookup(kind, name); ok {
+			subjectMap["name"] = newName
+			subjects[i] = subjectMap
+		}
+	}
+	return unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+}
+
+// rewritePodSpec rewrites the ServiceAccount, Secret and ConfigMap references of a pod spec
+func rewritePodSpec(obj *unstructured.Unstructured, path []string, lookup renameLookup) error {
+	podSpec, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return err
+	}
+
+	for _, field := range []string{"serviceAccountName", "serviceAccount"} {
+		if name, ok := podSpec[field].(string); ok {
+			if newName, ok := lookup("ServiceAccount", name); ok {
+				podSpec[field] = newName
+			}
+		}
+	}
+
+	if volumes, ok := podSpec["volumes"].([]interface{}); ok {
+		for _, volume := range volumes {
+			volumeMap, ok := volume.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rewriteField(volumeMap, lookup, "Secret", "secret", "secretName")
+			rewriteField(volumeMap, lookup, "ConfigMap", "configMap", "name")
+			rewriteField(volumeMap, lookup, "PersistentVolumeClaim", "persistentVolumeClaim", "claimName")
+		}
+	}
+
+	if pullSecrets, ok := podSpec["imagePullSecrets"].([]interface{}); ok {
+		for _, pullSecret := range pullSecrets {
+			if pullSecretMap, ok := pullSecret.(map[string]interface{}); ok {
+				rewriteField(pullSecretMap, lookup, "Secret", "name")
+			}
+		}
+	}
+
+	for _, containersField := range []string{"initContainers", "containers"} {
+		containers, ok := podSpec[containersField].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if env, ok := containerMap["env"].([]interface{}); ok {
+				for _, envVar := range env {
+					if envMap, ok := envVar.(map[string]interface{}); ok {
+						rewriteField(envMap, lookup, "Secret", "valueFrom", "secretKeyRef", "name")
+						rewriteField(envMap, lookup, "ConfigMap", "valueFrom", "configMapKeyRef", "name")
+					}
+				}
+			}
+			if envFrom, ok := containerMap["envFrom"].([]interface{}); ok {
+				for _, source := range envFrom {
+					if sourceMap, ok := source.(map[string]interface{}); ok {
+						rewriteField(sourceMap, lookup, "Secret", "secretRef", "name")
+						rewriteField(sourceMap, lookup, "ConfigMap", "configMapRef", "name")
+					}
+				}
+			}
+		}
+	}
+
+	return unstructured.SetNestedMap(obj.Object, podSpec, path...)
+}
+
+// rewriteField renames the string at path in m when it references a renamed resource of kind
+func rewriteField(m map[string]interface{}, lookup renameLookup, kind string, path ...string) {
+	name, found, err := unstructured.NestedString(m, path...)
+	if err != nil || !found {
+		return
+	}
+	if newName, ok := lookup(kind, name); ok {
+		_ = unstructured.SetNestedField(m, newName, path...)
+	}
+}
+
+// setBundleImage sets the image of the bundle container to the operator image of the version
+func setBundleImage(objects []*unstructured.Unstructured, bundle manifestBundle, controllerVersion string) error {
+	image := fmt.Sprintf("%s:%s_%s", operatorImageRepository, bundle.ImageComponent, controllerVersion)
+	for _, obj := range objects {
+		path, ok := podSpecPaths[obj.GetKind()]
+		if !ok {
+			continue
+		}
+		containersPath := append(append([]string{}, path...), "containers")
+		containers, found, err := unstructured.NestedSlice(obj.Object, containersPath...)
+		if err != nil || !found {
+			continue
+		}
+		for i, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if ok && containerMap["name"] == bundle.ImageContainer {
+				containerMap["image"] = image
+				containers[i] = containerMap
+			}
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, containers, containersPath...); err != nil {
+			return fmt.Errorf("failed to set containers: %v", err)
+		}
+	}
+	return nil
+}
+
+// applyObjectsToKarmada creates the objects in Karmada, updating the ones that already exist
+func applyObjectsToKarmada(ctx context.Context, objects []*unstructured.Unstructured) error {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		gvr, err := getGVRFromGVK(obj.GroupVersionKind())
+		if err != nil {
+			return fmt.Errorf("failed to get GVR for %s: %v", obj.GroupVersionKind(), err)
+		}
+		var resourceClient dynamic.ResourceInterface
+		if obj.GetNamespace() != "" {
+			resourceClient = karmadaDynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+		} else {
+			resourceClient = karmadaDynamicClient.Resource(gvr)
+		}
+
+		existing, err := resourceClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			if !strings.Contains(err.Error(), "not found") {
+				return fmt.Errorf("failed to get %s %s in Karmada: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			if _, err := resourceClient.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create %s %s in Karmada: %v", obj.GetKind(), obj.GetName(), err)
+			}
+			continue
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		if _, err := resourceClient.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update %s %s in Karmada: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		klog.V(4).InfoS("Updated bundle resource in Karmada", "kind", obj.GetKind(), "name", obj.GetName())
+	}
+	return nil
+}
+
+// installBundleForCluster renders a bundle for a member cluster and applies it to Karmada
+func installBundleForCluster(ctx context.Context, bundle manifestBundle, clusterName, controllerVersion string) error {
+	return installBundle(ctx, bundle, controllerVersion, func(objects []*unstructured.Unstructured) error {
+		return suffixBundleForCluster(objects, clusterName)
+	})
+}
+
+// installBundleForSelector renders a bundle for a cluster label selector and applies it to Karmada
+func installBundleForSelector(ctx context.Context, bundle manifestBundle, selectorName, controllerVersion string) error {
+	return installBundle(ctx, bundle, controllerVersion, func(objects []*unstructured.Unstructured) error {
+		return suffixBundleForSelector(objects, selectorName)
+	})
+}
+
+func installBundle(ctx context.Context, bundle manifestBundle, controllerVersion string, render func([]*unstructured.Unstructured) error) error {
+	objects, err := loadManifestBundle(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to load %s manifests: %v", bundle.Name, err)
+	}
+	if err := render(objects); err != nil {
+		return fmt.Errorf("failed to render %s manifests: %v", bundle.Name, err)
+	}
+	if err := setBundleImage(objects, bundle, controllerVersion); err != nil {
+		return err
+	}
+	return applyObjectsToKarmada(ctx, objects)
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	manifestsApp     = "backup-manifests"
+	manifestsDataKey = "manifests.json"
+	// maxExportedManifests is the number of exported manifests kept per backup, the oldest are deleted
+	maxExportedManifests = 5
+)
+
+// ExportedManifests describes the manifests of a backed up workload exported with its checkpoints
+type ExportedManifests struct {
+	Name       string `json:"name"`
+	Cluster    string `json:"cluster"`
+	CapturedAt string `json:"capturedAt"`
+	Objects    int    `json:"objects"`
+}
+
+// getWorkload gets the backed up statefulset or pod
+func getWorkload(ctx context.Context, memberClient kubeclient.Interface, backup BackupConfiguration) (metav1.Object, error) {
+	if strings.EqualFold(backup.ResourceType, "statefulset") {
+		return memberClient.AppsV1().StatefulSets(backup.Namespace).Get(ctx, backup.ResourceName, metav1.GetOptions{})
+	}
+	return memberClient.CoreV1().Pods(backup.Namespace).Get(ctx, backup.ResourceName, metav1.GetOptions{})
+}
+
+// podDeployment returns the Deployment owning a pod through its ReplicaSet, nil for other pods
+func podDeployment(ctx context.Context, memberClient kubeclient.Interface, pod *corev1.Pod) (metav1.Object, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "ReplicaSet" {
+		return nil, nil
+	}
+	replicaSet, err := memberClient.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner = metav1.GetControllerOf(replicaSet); owner == nil || owner.Kind != "Deployment" {
+		return nil, nil
+	}
+	deployment, err := memberClient.AppsV1().Deployments(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return deployment, err
+}
+
+// captureWorkloadManifests exports the backed up workload and the ConfigMaps, Secrets, Services and claims
+// it depends on from its cluster
+func captureWorkloadManifests(ctx context.Context, backup BackupConfiguration) (*migration.WorkloadManifests, error) {
+	memberClient := client.InClusterClientForMemberCluster(backup.Cluster)
+	if memberClient == nil {
+		return nil, fmt.Errorf("failed to get client for cluster %s", backup.Cluster)
+	}
+	manifests := &migration.WorkloadManifests{Cluster: backup.Cluster, CapturedAt: time.Now().Format(time.RFC3339)}
+	workload, err := getWorkload(ctx, memberClient, backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %v", backup.ResourceType, backup.Namespace, backup.ResourceName, err)
+	}
+	if err := manifests.Add(workload); err != nil {
+		return nil, err
+	}
+	if pod, ok := workload.(*corev1.Pod); ok {
+		deployment, err := podDeployment(ctx, memberClient, pod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the deployment of pod %s: %v", pod.Name, err)
+		}
+		if deployment != nil {
+			if err := manifests.Add(deployment); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	source := clusterSource{client: memberClient, backup: backup}
+	podSpec, podLabels, governing := manifests.PodSpec()
+	dependencies, err := workloadDependencies(ctx, source, podSpec, podLabels, governing)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependency := range dependencies {
+		obj, err := source.dependency(ctx, dependency.Kind, dependency.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %v", dependency.Kind, dependency.Name, err)
+		}
+		if obj == nil {
+			continue
+		}
+		if err := manifests.Add(obj); err != nil {
+			return nil, err
+		}
+	}
+	return manifests, nil
+}
+
+// listExportedManifests returns the Secrets holding the exported manifests of a backup, newest first
+func listExportedManifests(ctx context.Context, backupID string) ([]corev1.Secret, error) {
+	list, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s,backup-id=%s", manifestsApp, backupID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	secrets := list.Items
+	sort.Slice(secrets, func(i, j int) bool {
+		if !secrets[i].CreationTimestamp.Equal(&secrets[j].CreationTimestamp) {
+			return secrets[j].CreationTimestamp.Before(&secrets[i].CreationTimestamp)
+		}
+		return secrets[i].Name > secrets[j].Name
+	})
+	return secrets, nil
+}
+
+// decodeExportedManifests reads the manifests held by a Secret
+func decodeExportedManifests(secret *corev1.Secret) (*migration.WorkloadManifests, error) {
+	manifests := &migration.WorkloadManifests{}
+	if err := json.Unmarshal(secret.Data[manifestsDataKey], manifests); err != nil {
+		return nil, fmt.Errorf("failed to parse exported manifests %s: %v", secret.Name, err)
+	}
+	return manifests, nil
+}
+
+// latestWorkloadManifests returns the latest manifests exported for a backup, nil when it has none
+func latestWorkloadManifests(ctx context.Context, backupID string) (*migration.WorkloadManifests, error) {
+	secrets, err := listExportedManifests(ctx, backupID)
+	if err != nil || len(secrets) == 0 {
+		return nil, err
+	}
+	return decodeExportedManifests(&secrets[0])
+}
+
+// saveWorkloadManifests stores exported manifests in a Secret of the system namespace, since they hold the
+// Secrets of the workload, and deletes the ones older than the last maxExportedManifests
+func saveWorkloadManifests(ctx context.Context, backupID string, manifests *migration.WorkloadManifests) (*ExportedManifests, error) {
+	data, err := json.Marshal(manifests)
+	if err != nil {
+		return nil, err
+	}
+	immutable := true
+	secrets := client.InClusterClient().CoreV1().Secrets(config.GetNamespace())
+	created, err := secrets.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: backupID + "-manifests-",
+			Namespace:    config.GetNamespace(),
+			Labels:       map[string]string{"app": manifestsApp, "backup-id": backupID},
+		},
+		Data:      map[string][]byte{manifestsDataKey: data},
+		Immutable: &immutable,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store exported manifests: %v", err)
+	}
+
+	existing, err := listExportedManifests(ctx, backupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list exported manifests", "backupID", backupID)
+	}
+	for i := maxExportedManifests; i < len(existing); i++ {
+		if err := secrets.Delete(ctx, existing[i].Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete exported manifests", "backupID", backupID, "name", existing[i].Name)
+		}
+	}
+	return &ExportedManifests{Name: created.Name, Cluster: manifests.Cluster, CapturedAt: manifests.CapturedAt, Objects: manifests.Count()}, nil
+}
+
+// exportBackupManifests exports the manifests of the workload of a backup along with a checkpoint
+func exportBackupManifests(ctx context.Context, backup BackupConfiguration) (*ExportedManifests, error) {
+	manifests, err := captureWorkloadManifests(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+	return saveWorkloadManifests(ctx, backup.ID, manifests)
+}
+
+// handleGetBackupManifests lists the manifests exported for a backup, newest first
+func handleGetBackupManifests(c *gin.Context) {
+	backup, err := getBackupByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	if err := client.CheckMemberClusterAccess(c, backup.Cluster); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	secrets, err := listExportedManifests(c, backup.ID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list exported manifests", "backupID", backup.ID)
+		common.Fail(c, err)
+		return
+	}
+	exported := make([]ExportedManifests, 0, len(secrets))
+	for i := range secrets {
+		manifests, err := decodeExportedManifests(&secrets[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping exported manifests", "backupID", backup.ID)
+			continue
+		}
+		exported = append(exported, ExportedManifests{Name: secrets[i].Name, Cluster: manifests.Cluster, CapturedAt: manifests.CapturedAt, Objects: manifests.Count()})
+	}
+	common.Success(c, map[string]interface{}{
+		"manifests": exported,
+		"total":     len(exported),
+	})
+}
+
+// handleExportBackupManifests exports the manifests of the workload of a backup now
+func handleExportBackupManifests(c *gin.Context) {
+	backup, err := getBackupByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	if err := client.CheckMemberClusterAccess(c, backup.Cluster); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	exported, err := exportBackupManifests(c, backup)
+	if err != nil {
+		klog.ErrorS(err, "Failed to export backup manifests", "backupID", backup.ID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, exported)
+}
+
+// handleDownloadBackupManifests returns exported manifests as a YAML file. The values of the Secrets are
+// left out, recoveries read them from the stored manifests.
+func handleDownloadBackupManifests(c *gin.Context) {
+	backup, err := getBackupByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	if err := client.CheckMemberClusterAccess(c, backup.Cluster); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	secret, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Get(c, c.Param("name"), metav1.GetOptions{})
+	if err == nil && (secret.Labels["app"] != manifestsApp || secret.Labels["backup-id"] != backup.ID) {
+		err = apierrors.NewNotFound(corev1.Resource("secrets"), c.Param("name"))
+	}
+	if apierrors.IsNotFound(err) {
+		common.FailWithStatus(c, fmt.Errorf("exported manifests %s of backup %s not found", c.Param("name"), backup.ID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	manifests, err := decodeExportedManifests(secret)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	data, err := manifests.YAML(true)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.yaml"`, secret.Name))
+	c.Data(http.StatusOK, "application/yaml", data)
+}
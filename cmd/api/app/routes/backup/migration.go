@@ -0,0 +1,680 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// Migration phases, in the order they are reached
+const (
+	MigrationPhasePending       = "Pending"
+	MigrationPhaseCheckpointing = "Checkpointing"
+	MigrationPhaseRestoring     = "Restoring"
+	MigrationPhaseCompleted     = "Completed"
+	MigrationPhaseFailed        = "Failed"
+)
+
+const (
+	defaultMigrationTimeout = 30 * time.Minute
+	migrationPollInterval   = 10 * time.Second
+)
+
+var checkpointBackupGVR = schema.GroupVersionResource{
+	Group:    "migration.dcnlab.com",
+	Version:  "v1",
+	Resource: "checkpointbackups",
+}
+
+var checkpointRestoreGVR = schema.GroupVersionResource{
+	Group:    "migration.dcnlab.com",
+	Version:  "v1",
+	Resource: "checkpointrestores",
+}
+
+// CreateMigrationRequest represents the request to migrate a workload to another cluster
+type CreateMigrationRequest struct {
+	Name             string `json:"name" binding:"required"`
+	SourceCluster    string `json:"sourceCluster" binding:"required,cluster"`
+	TargetCluster    string `json:"targetCluster" binding:"required,cluster"`
+	ResourceType     string `json:"resourceType" binding:"required,oneof=pod statefulset"`
+	ResourceName     string `json:"resourceName" binding:"required,dns1123subdomain"`
+	Namespace        string `json:"namespace" binding:"required,dns1123label"`
+	RegistryID       string `json:"registryId" binding:"required_without=StorageBackendID"`
+	Repository       string `json:"repository" binding:"required_without=StorageBackendID"`
+	StorageBackendID string `json:"storageBackendId"`
+	TargetName       string `json:"targetName,omitempty" binding:"omitempty,dns1123subdomain"`  // Optional: different name for migrated resource
+	TargetNamespace  string `json:"targetNamespace,omitempty" binding:"omitempty,dns1123label"` // Optional: different namespace
+	TimeoutSeconds   int    `json:"timeoutSeconds,omitempty"`
+	ExecutionWindows
+}
+
+// MigrationPhaseTransition records when a migration entered a phase
+type MigrationPhaseTransition struct {
+	Phase     string `json:"phase"`
+	Message   string `json:"message,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// MigrationStatus is the single status object of a migration
+type MigrationStatus struct {
+	ID              string                     `json:"id"`
+	Name            string                     `json:"name"`
+	SourceCluster   string                     `json:"sourceCluster"`
+	TargetCluster   string                     `json:"targetCluster"`
+	ResourceType    string                     `json:"resourceType"`
+	ResourceName    string                     `json:"resourceName"`
+	Namespace       string                     `json:"namespace"`
+	TargetName      string                     `json:"targetName"`
+	TargetNamespace string                     `json:"targetNamespace"`
+	BackupID        string                     `json:"backupId,omitempty"`
+	CheckpointName  string                     `json:"checkpointName,omitempty"`
+	RestoreName     string                     `json:"restoreName,omitempty"`
+	Phase           string                     `json:"phase"`
+	Message         string                     `json:"message,omitempty"`
+	Phases          []MigrationPhaseTransition `json:"phases"`
+	StartedAt       string                     `json:"startedAt"`
+	CompletedAt     string                     `json:"completedAt,omitempty"`
+	// JobID is the job running the migration
+	JobID string `json:"jobId,omitempty"`
+	// Ordinals are the pods of a migrated StatefulSet, restored in ordinal order
+	Ordinals []MigrationOrdinal `json:"ordinals,omitempty"`
+}
+
+// migrationConfigMapName returns the name of the ConfigMap that stores a migration's status
+func migrationConfigMapName(migrationID string) string {
+	return fmt.Sprintf("migration-%s", migrationID)
+}
+
+// saveMigrationStatus creates or updates the ConfigMap holding the migration status
+func saveMigrationStatus(ctx context.Context, status *MigrationStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal migration status: %v", err)
+	}
+
+	kubeClient := client.InClusterClient()
+	configMaps := kubeClient.CoreV1().ConfigMaps(config.GetNamespace())
+	name := migrationConfigMapName(status.ID)
+
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: config.GetNamespace(),
+				Labels: map[string]string{
+					"app":          "migration-wizard",
+					"migration-id": status.ID,
+				},
+			},
+			Data: map[string]string{"status": string(data)},
+		}
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data["status"] = string(data)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// configMapToMigrationStatus decodes the migration status stored in a ConfigMap
+func configMapToMigrationStatus(cm *corev1.ConfigMap) (MigrationStatus, error) {
+	var status MigrationStatus
+	if err := json.Unmarshal([]byte(cm.Data["status"]), &status); err != nil {
+		return MigrationStatus{}, fmt.Errorf("failed to parse migration status %s: %v", cm.Name, err)
+	}
+	return status, nil
+}
+
+// setMigrationPhase moves the migration to a new phase and persists it
+func setMigrationPhase(ctx context.Context, status *MigrationStatus, phase, message string) {
+	now := time.Now().Format(time.RFC3339)
+	status.Phase = phase
+	status.Message = message
+	status.Phases = append(status.Phases, MigrationPhaseTransition{
+		Phase:     phase,
+		Message:   message,
+		Timestamp: now,
+	})
+	if phase == MigrationPhaseCompleted || phase == MigrationPhaseFailed {
+		status.CompletedAt = now
+	}
+	if err := saveMigrationStatus(ctx, status); err != nil {
+		klog.ErrorS(err, "Failed to save migration status", "migrationID", status.ID, "phase", phase)
+	}
+}
+
+// triggerCheckpoint creates a one-off backup configuration for the migration and executes it immediately
+func triggerCheckpoint(ctx context.Context, status *MigrationStatus, req CreateMigrationRequest) error {
+	backupReq := CreateBackupRequest{
+		Name:             fmt.Sprintf("migration-%s", status.ID),
+		Cluster:          req.SourceCluster,
+		ResourceType:     req.ResourceType,
+		ResourceName:     req.ResourceName,
+		Namespace:        req.Namespace,
+		RegistryID:       req.RegistryID,
+		Repository:       req.Repository,
+		StorageBackendID: req.StorageBackendID,
+		Schedule:         ScheduleConfig{ExecutionWindows: req.ExecutionWindows},
+	}
+
+	var registry RegistryCredentials
+	var storage *StorageBackend
+	if req.StorageBackendID != "" {
+		backend, err := getStorageBackendByID(req.StorageBackendID)
+		if err != nil {
+			return fmt.Errorf("failed to get storage backend: %v", err)
+		}
+		storage = &backend
+	} else {
+		var err error
+		registry, err = getRegistryByID(req.RegistryID)
+		if err != nil {
+			return fmt.Errorf("failed to get registry: %v", err)
+		}
+	}
+
+	backupID := migration.GenerateID(backupReq.Name)
+	sm := createStatefulMigrationCR(backupID, backupReq, registry, storage)
+
+	// A migration checkpoint runs once, so it has no schedule
+	spec, _, _ := unstructured.NestedMap(sm.Object, "spec")
+	delete(spec, "schedule")
+	spec["executeNow"] = time.Now().Unix()
+	unstructured.SetNestedMap(sm.Object, spec, "spec")
+
+	labels := sm.GetLabels()
+	labels["migration-id"] = status.ID
+	sm.SetLabels(labels)
+
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		return fmt.Errorf("failed to get dynamic client: %v", err)
+	}
+	if _, err := dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).Create(ctx, sm, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create StatefulMigration CR: %v", err)
+	}
+
+	status.BackupID = backupID
+	return nil
+}
+
+// checkpointMatchesResource reports whether a CheckpointBackup belongs to the resource
+func checkpointMatchesResource(cb *CheckpointBackup, resourceName string) bool {
+	if cb.Spec.ResourceRef != nil && cb.Spec.ResourceRef.Name != "" {
+		return cb.Spec.ResourceRef.Name == resourceName
+	}
+	if podName := cb.Spec.PodName; podName != "" {
+		// StatefulSet pods are named <statefulset>-<ordinal>
+		return podName == resourceName || strings.HasPrefix(podName, resourceName+"-")
+	}
+	return strings.Contains(cb.Name, resourceName)
+}
+
+// waitForCheckpoint polls the source cluster until a CheckpointBackup created after startedAt completes
+func waitForCheckpoint(ctx context.Context, c *gin.Context, status *MigrationStatus, req CreateMigrationRequest, startedAt time.Time) (*CheckpointBackup, error) {
+	dynamicClient, err := client.GetDynamicClientForMember(c, req.SourceCluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for source cluster: %v", err)
+	}
+
+	ticker := time.NewTicker(migrationPollInterval)
+	defer ticker.Stop()
+	for {
+		list, err := dynamicClient.Resource(checkpointBackupGVR).Namespace(req.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.V(4).InfoS("Failed to list CheckpointBackup CRs", "cluster", req.SourceCluster, "error", err)
+		} else {
+			for i := range list.Items {
+				cb, err := decodeCheckpointBackup(&list.Items[i])
+				if err != nil {
+					klog.V(4).InfoS("Skipping CheckpointBackup", "cluster", req.SourceCluster, "error", err)
+					continue
+				}
+				if cb.CreationTimestamp.Time.Before(startedAt.Truncate(time.Second)) || !checkpointMatchesResource(cb, req.ResourceName) {
+					continue
+				}
+				status.CheckpointName = cb.Name
+				switch strings.ToLower(cb.Status.Phase) {
+				case "completed", "succeeded", "ready":
+					return cb, nil
+				case "failed", "error":
+					return nil, fmt.Errorf("checkpoint %s failed: %s", cb.Name, cb.Status.Message)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for checkpoint of %s/%s", req.Namespace, req.ResourceName)
+		case <-ticker.C:
+		}
+	}
+}
+
+// createCheckpointRestore creates the CheckpointRestore CR on the target cluster
+func createCheckpointRestore(ctx context.Context, c *gin.Context, status *MigrationStatus, cb *CheckpointBackup) error {
+	restore := newMigrationRestore(status, cb, fmt.Sprintf("migration-%s", status.ID), status.TargetName)
+	if err := applyMigrationRestore(ctx, c, status, restore); err != nil {
+		return err
+	}
+	status.RestoreName = restore.Name
+	return nil
+}
+
+// newMigrationRestore returns the CheckpointRestore that restores the checkpoint as podName on the target cluster,
+// with the checkpoint images rewritten by the image rewrite rules of the target cluster
+func newMigrationRestore(status *MigrationStatus, cb *CheckpointBackup, name, podName string) *CheckpointRestore {
+	containers := cb.CheckpointedContainers()
+	rules := migration.ClusterImageRewrites(status.TargetCluster)
+	for i := range containers {
+		containers[i].Image = migration.RewriteImage(containers[i].Image, rules)
+	}
+	return &CheckpointRestore{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: checkpointRestoreGVR.GroupVersion().String(),
+			Kind:       "CheckpointRestore",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: status.TargetNamespace,
+			Labels: map[string]string{
+				"app":          "migration-wizard",
+				"migration-id": status.ID,
+			},
+		},
+		Spec: CheckpointRestoreSpec{
+			BackupRef: &CheckpointBackupRef{
+				Name:      cb.Name,
+				Namespace: cb.Namespace,
+				Cluster:   status.SourceCluster,
+				ResourceRef: &ResourceRef{
+					Kind:      status.ResourceType,
+					Name:      status.ResourceName,
+					Namespace: status.Namespace,
+				},
+			},
+			TargetCluster: status.TargetCluster,
+			PodName:       podName,
+			PodNamespace:  status.TargetNamespace,
+			Containers:    containers,
+		},
+	}
+}
+
+// applyMigrationRestore creates a CheckpointRestore of the migration on the target cluster
+func applyMigrationRestore(ctx context.Context, c *gin.Context, status *MigrationStatus, restore *CheckpointRestore) error {
+	dynamicClient, err := client.GetDynamicClientForMember(c, status.TargetCluster)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client for target cluster: %v", err)
+	}
+	obj, err := toUnstructured(restore)
+	if err != nil {
+		return fmt.Errorf("failed to encode CheckpointRestore: %v", err)
+	}
+
+	// A retried attempt may find the restore created by the previous one
+	if _, err := dynamicClient.Resource(checkpointRestoreGVR).Namespace(status.TargetNamespace).Create(ctx, obj, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create CheckpointRestore: %v", err)
+	}
+	return nil
+}
+
+// waitForRestore polls the target cluster until the CheckpointRestore completes or fails
+func waitForRestore(ctx context.Context, c *gin.Context, status *MigrationStatus) error {
+	return waitForNamedRestore(ctx, c, status, status.RestoreName)
+}
+
+// waitForNamedRestore polls the target cluster until a CheckpointRestore of the migration completes or fails
+func waitForNamedRestore(ctx context.Context, c *gin.Context, status *MigrationStatus, restoreName string) error {
+	dynamicClient, err := client.GetDynamicClientForMember(c, status.TargetCluster)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client for target cluster: %v", err)
+	}
+
+	ticker := time.NewTicker(migrationPollInterval)
+	defer ticker.Stop()
+	for {
+		obj, err := dynamicClient.Resource(checkpointRestoreGVR).Namespace(status.TargetNamespace).Get(ctx, restoreName, metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).InfoS("Failed to get CheckpointRestore", "cluster", status.TargetCluster, "error", err)
+		} else if restore, err := decodeCheckpointRestore(obj); err != nil {
+			klog.V(4).InfoS("Failed to decode CheckpointRestore", "cluster", status.TargetCluster, "error", err)
+		} else {
+			switch strings.ToLower(restore.Status.Phase) {
+			case "completed", "succeeded", "restored":
+				return nil
+			case "failed", "error":
+				return fmt.Errorf("restore %s failed: %s", restoreName, restore.Status.Message)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for restore %s", restoreName)
+		case <-ticker.C:
+		}
+	}
+}
+
+// migrationJobType is the job that drives a migration through its phases
+const migrationJobType = "migration"
+
+// migrationJobParams are the params of a migration job
+type migrationJobParams struct {
+	MigrationID string                 `json:"migrationId"`
+	Request     CreateMigrationRequest `json:"request"`
+}
+
+// getMigrationStatus reads the status of a migration
+func getMigrationStatus(ctx context.Context, migrationID string) (*MigrationStatus, error) {
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, migrationConfigMapName(migrationID), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	status, err := configMapToMigrationStatus(cm)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// runMigration drives a migration through its phases as a job. The status of the migration records the
+// checkpoint and restore it created, so an attempt resumed after a restart continues with the next step.
+func runMigration(jobCtx context.Context, run *jobs.Run) error {
+	var params migrationJobParams
+	if err := run.DecodeParams(&params); err != nil {
+		return err
+	}
+	req := params.Request
+	status, err := getMigrationStatus(jobCtx, params.MigrationID)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %v", err)
+	}
+	if status.Phase == MigrationPhaseCompleted {
+		return nil
+	}
+	// The workload is unlocked once the migration completed or failed for good
+	defer func() {
+		if status.Phase == MigrationPhaseCompleted || status.Phase == MigrationPhaseFailed {
+			releaseWorkloadLock(context.Background(), migrationWorkload(status), migrationLockHolder(status))
+		}
+	}()
+
+	timeout := defaultMigrationTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(jobCtx, timeout)
+	defer cancel()
+
+	// Status updates must outlive the timeout so failures are still recorded
+	saveCtx := context.Background()
+	fail := func(err error) error {
+		if run.Final(jobCtx, err) {
+			setMigrationPhase(saveCtx, status, MigrationPhaseFailed, err.Error())
+		} else {
+			status.Message = fmt.Sprintf("Attempt %d failed, retrying: %v", run.Attempt(), err)
+			if saveErr := saveMigrationStatus(saveCtx, status); saveErr != nil {
+				klog.ErrorS(saveErr, "Failed to save migration status", "migrationID", status.ID)
+			}
+		}
+		return err
+	}
+	// Member clusters are accessed without the user, whose access was checked when the migration was created
+	memberCtx := &gin.Context{}
+
+	startedAt, err := time.Parse(time.RFC3339, status.StartedAt)
+	if err != nil {
+		startedAt = time.Now()
+	}
+	if status.BackupID == "" {
+		setMigrationPhase(saveCtx, status, MigrationPhaseCheckpointing, "Creating checkpoint on source cluster")
+		if err := triggerCheckpoint(ctx, status, req); err != nil {
+			klog.ErrorS(err, "Failed to trigger migration checkpoint", "migrationID", status.ID)
+			return fail(err)
+		}
+		if err := saveMigrationStatus(saveCtx, status); err != nil {
+			klog.ErrorS(err, "Failed to save migration status", "migrationID", status.ID)
+		}
+	}
+
+	if strings.EqualFold(req.ResourceType, "statefulset") {
+		if err := migrateStatefulSet(ctx, saveCtx, memberCtx, status, req, startedAt); err != nil {
+			klog.ErrorS(err, "StatefulSet migration did not complete", "migrationID", status.ID)
+			return fail(err)
+		}
+		setMigrationPhase(saveCtx, status, MigrationPhaseCompleted, "Migration completed successfully")
+		return nil
+	}
+
+	if status.RestoreName == "" {
+		cb, err := waitForCheckpoint(ctx, memberCtx, status, req, startedAt)
+		if err != nil {
+			klog.ErrorS(err, "Migration checkpoint did not complete", "migrationID", status.ID)
+			return fail(err)
+		}
+
+		setMigrationPhase(saveCtx, status, MigrationPhaseRestoring, fmt.Sprintf("Restoring checkpoint %s on target cluster", cb.GetName()))
+		if err := createCheckpointRestore(ctx, memberCtx, status, cb); err != nil {
+			klog.ErrorS(err, "Failed to create migration restore", "migrationID", status.ID)
+			return fail(err)
+		}
+		if err := saveMigrationStatus(saveCtx, status); err != nil {
+			klog.ErrorS(err, "Failed to save migration status", "migrationID", status.ID)
+		}
+	}
+	if err := waitForRestore(ctx, memberCtx, status); err != nil {
+		klog.ErrorS(err, "Migration restore did not complete", "migrationID", status.ID)
+		return fail(err)
+	}
+
+	setMigrationPhase(saveCtx, status, MigrationPhaseCompleted, "Migration completed successfully")
+	return nil
+}
+
+// handleCreateMigration starts a migration that checkpoints the source workload and restores it on the target cluster
+func handleCreateMigration(c *gin.Context) {
+	var req CreateMigrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind migration request")
+		common.FailWithBindError(c, err)
+		return
+	}
+	audit.SetClusters(c, req.SourceCluster, req.TargetCluster)
+	if req.SourceCluster == req.TargetCluster {
+		common.Fail(c, fmt.Errorf("source and target cluster must be different"))
+		return
+	}
+	if err := req.ExecutionWindows.validate(); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := req.ExecutionWindows.checkAllowed(time.Now()); err != nil {
+		klog.InfoS("Migration rejected", "name", req.Name, "reason", err.Error())
+		common.Fail(c, err)
+		return
+	}
+	// Migrating workloads away from a cluster in maintenance or cordoned is allowed, migrating onto one is not
+	if err := checkNotInMaintenance(c, req.TargetCluster); err != nil {
+		klog.InfoS("Migration rejected", "name", req.Name, "reason", err.Error())
+		failBackupChange(c, err)
+		return
+	}
+	if err := checkNotCordoned(c, req.TargetCluster); err != nil {
+		klog.InfoS("Migration rejected", "name", req.Name, "reason", err.Error())
+		failBackupChange(c, err)
+		return
+	}
+	// The migration runs without the user, so their access to both clusters is checked now
+	for _, clusterName := range []string{req.SourceCluster, req.TargetCluster} {
+		if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	targetName := req.ResourceName
+	if req.TargetName != "" {
+		targetName = req.TargetName
+	}
+	targetNamespace := req.Namespace
+	if req.TargetNamespace != "" {
+		targetNamespace = req.TargetNamespace
+	}
+
+	status := &MigrationStatus{
+		ID:              fmt.Sprintf("%s-%d", strings.ToLower(strings.ReplaceAll(req.Name, " ", "-")), time.Now().Unix()),
+		Name:            req.Name,
+		SourceCluster:   req.SourceCluster,
+		TargetCluster:   req.TargetCluster,
+		ResourceType:    req.ResourceType,
+		ResourceName:    req.ResourceName,
+		Namespace:       req.Namespace,
+		TargetName:      targetName,
+		TargetNamespace: targetNamespace,
+		Phase:           MigrationPhasePending,
+		Phases: []MigrationPhaseTransition{
+			{Phase: MigrationPhasePending, Timestamp: time.Now().Format(time.RFC3339)},
+		},
+		StartedAt: time.Now().Format(time.RFC3339),
+	}
+	// The source workload stays locked until the migration ends
+	holder := migrationLockHolder(status)
+	holder.User = utilauth.GetAuthenticatedUser(c)
+	if err := workloadLocks().Acquire(c, migrationWorkload(status), holder, workloadLockTTL); err != nil {
+		klog.InfoS("Migration rejected", "name", req.Name, "reason", err.Error())
+		failBackupChange(c, err)
+		return
+	}
+	if err := saveMigrationStatus(c, status); err != nil {
+		klog.ErrorS(err, "Failed to save migration status", "migrationID", status.ID)
+		releaseWorkloadLock(c, migrationWorkload(status), holder)
+		common.Fail(c, err)
+		return
+	}
+
+	job, err := jobs.Enqueue(c, migrationJobType, migrationJobParams{MigrationID: status.ID, Request: req}, jobs.EnqueueOptions{
+		ID:        migrationConfigMapName(status.ID),
+		Keys:      []string{req.SourceCluster, req.TargetCluster},
+		CreatedBy: utilauth.GetAuthenticatedUser(c),
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to enqueue migration", "migrationID", status.ID)
+		setMigrationPhase(c, status, MigrationPhaseFailed, fmt.Sprintf("failed to start the migration: %v", err))
+		releaseWorkloadLock(c, migrationWorkload(status), holder)
+		common.Fail(c, err)
+		return
+	}
+	status.JobID = job.ID
+	if err := saveMigrationStatus(c, status); err != nil {
+		klog.ErrorS(err, "Failed to save migration status", "migrationID", status.ID)
+	}
+
+	common.Success(c, status)
+}
+
+// handleGetMigrations lists all migrations started through the wizard
+func handleGetMigrations(c *gin.Context) {
+	kubeClient := client.InClusterClient()
+	list, err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).List(c, metav1.ListOptions{
+		LabelSelector: "app=migration-wizard",
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list migrations")
+		common.Fail(c, err)
+		return
+	}
+
+	migrations := make([]MigrationStatus, 0, len(list.Items))
+	for i := range list.Items {
+		status, err := configMapToMigrationStatus(&list.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid migration status", "configMap", list.Items[i].Name)
+			continue
+		}
+		migrations = append(migrations, status)
+	}
+
+	common.Success(c, map[string]interface{}{
+		"migrations": migrations,
+		"total":      len(migrations),
+	})
+}
+
+// handleGetMigration returns the status of a single migration
+func handleGetMigration(c *gin.Context) {
+	migrationID := c.Param("id")
+	kubeClient := client.InClusterClient()
+	cm, err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Get(c, migrationConfigMapName(migrationID), metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to get migration", "migrationID", migrationID)
+		common.Fail(c, err)
+		return
+	}
+
+	status, err := configMapToMigrationStatus(cm)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, status)
+}
+
+// Register migration routes
+func init() {
+	jobs.Register(migrationJobType, runMigration)
+
+	r := router.V1()
+
+	migrationGroup := r.Group("/migration")
+	migrationGroup.Use(router.RequireFeature(config.FeatureMigration), idempotencyMiddleware())
+	{
+		migrationGroup.GET("", handleGetMigrations)
+		migrationGroup.POST("", handleCreateMigration)
+		migrationGroup.GET("/:id", handleGetMigration)
+	}
+}
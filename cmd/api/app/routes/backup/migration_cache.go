@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicache"
+)
+
+// migrationCache watches the migration CRs of the member clusters, nil when disabled
+var migrationCache *multicache.Manager
+
+// StartMigrationCache starts watching CheckpointBackup and CheckpointRestore CRs in the ready member clusters,
+// checking for added and removed clusters every interval. A non-positive interval disables the cache
+// and the handlers read the member clusters on each request.
+func StartMigrationCache(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Migration resource cache is disabled")
+		return
+	}
+	migrationCache = multicache.NewManager([]schema.GroupVersionResource{checkpointBackupGVR, checkpointRestoreGVR}, 0)
+	migrationCache.Start(ctx, interval)
+	klog.InfoS("Migration resource cache started", "clusterSyncInterval", interval)
+}
+
+// MigrationCacheSynced returns an error until the migration resource cache has synced its cluster list.
+// It returns nil when the cache is disabled.
+func MigrationCacheSynced() error {
+	if migrationCache == nil || migrationCache.Started() {
+		return nil
+	}
+	return fmt.Errorf("migration resource cache has not synced the member clusters")
+}
+
+// RefreshMigrationCache rebuilds the cached migration CRs of a cluster, or of all clusters when
+// clusterName is empty
+func RefreshMigrationCache(ctx context.Context, clusterName string) error {
+	if migrationCache == nil {
+		return fmt.Errorf("migration resource cache is disabled")
+	}
+	migrationCache.Refresh(ctx, clusterName)
+	klog.InfoS("Migration resource cache refreshed", "cluster", clusterName)
+	return nil
+}
+
+// cachedCheckpointRestoreEvents returns the CheckpointRestore events of the clusters the user can access
+// from the cache. It returns false until the cache has synced its cluster list.
+func cachedCheckpointRestoreEvents(c *gin.Context) ([]CheckpointRestoreEvent, bool) {
+	if migrationCache == nil || !migrationCache.Started() {
+		return nil, false
+	}
+
+	events := []CheckpointRestoreEvent{}
+	for _, clusterName := range migrationCache.Clusters() {
+		if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+			klog.V(4).InfoS("Skipping cluster", "cluster", clusterName, "error", err)
+			continue
+		}
+		restores, ok := migrationCache.List(clusterName, checkpointRestoreGVR)
+		if !ok {
+			continue
+		}
+		for _, restore := range restores {
+			events = append(events, convertCheckpointRestoreToEvent(restore, clusterName))
+		}
+	}
+	return events, true
+}
+
+// cachedMigrationObject returns a migration CR of a member cluster from the cache, used when the
+// cluster cannot be reached. It returns false when the cache is disabled or does not hold the object.
+func cachedMigrationObject(clusterName string, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, bool) {
+	if migrationCache == nil {
+		return nil, false
+	}
+	return migrationCache.Get(clusterName, gvr, namespace, name)
+}
+
+// getMigrationObject reads a migration CR from a member cluster. When the cluster is unreachable the
+// cached copy is returned and the request reports the cache as the access path.
+func getMigrationObject(c *gin.Context, clusterName string, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		if errors.Is(err, client.ErrMemberClusterUnreachable) {
+			if obj, ok := cachedMigrationObject(clusterName, gvr, namespace, name); ok {
+				klog.V(4).InfoS("Serving migration resource from cache", "cluster", clusterName, "resource", gvr.Resource, "name", name)
+				return obj.DeepCopy(), nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create dynamic client for cluster %s: %w", clusterName, err)
+	}
+	return dynamicClient.Resource(gvr).Namespace(namespace).Get(c, name, metav1.GetOptions{})
+}
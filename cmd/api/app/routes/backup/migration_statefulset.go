@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// Phases of a StatefulSet ordinal during a migration
+const (
+	OrdinalPhasePending   = "Pending"
+	OrdinalPhaseRestoring = "Restoring"
+	OrdinalPhaseReady     = "Ready"
+)
+
+// MigrationOrdinal is the restore of one pod of a migrated StatefulSet
+type MigrationOrdinal struct {
+	Ordinal        int    `json:"ordinal"`
+	PodName        string `json:"podName"`
+	CheckpointName string `json:"checkpointName,omitempty"`
+	RestoreName    string `json:"restoreName,omitempty"`
+	Phase          string `json:"phase"`
+}
+
+// migrateStatefulSet restores a StatefulSet on the target cluster in ordinal order. The StatefulSet and its
+// services are recreated scaled to zero, then each pod is restored from its checkpoint, waited for until ready
+// and adopted by scaling the StatefulSet up by one, so pod N only starts once pod N-1 is ready.
+// The ordinals are recorded in the status, so a resumed attempt continues with the first one not ready.
+func migrateStatefulSet(ctx, saveCtx context.Context, memberCtx *gin.Context, status *MigrationStatus, req CreateMigrationRequest, startedAt time.Time) error {
+	sourceClient := client.InClusterClientForMemberCluster(status.SourceCluster)
+	targetClient := client.InClusterClientForMemberCluster(status.TargetCluster)
+	if sourceClient == nil || targetClient == nil {
+		return fmt.Errorf("failed to get clients for clusters %s and %s", status.SourceCluster, status.TargetCluster)
+	}
+	source, err := sourceClient.AppsV1().StatefulSets(status.Namespace).Get(ctx, status.ResourceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get statefulset %s/%s: %v", status.Namespace, status.ResourceName, err)
+	}
+
+	if len(status.Ordinals) == 0 {
+		replicas := migration.StatefulSetReplicas(source)
+		for ordinal := 0; ordinal < replicas; ordinal++ {
+			status.Ordinals = append(status.Ordinals, MigrationOrdinal{
+				Ordinal: ordinal,
+				PodName: migration.OrdinalPodName(status.TargetName, ordinal),
+				Phase:   OrdinalPhasePending,
+			})
+		}
+	}
+
+	checkpoints, err := waitForStatefulSetCheckpoints(ctx, memberCtx, status, req, startedAt)
+	if err != nil {
+		return err
+	}
+	for i := range status.Ordinals {
+		status.Ordinals[i].CheckpointName = checkpoints[status.Ordinals[i].Ordinal].Name
+	}
+
+	setMigrationPhase(saveCtx, status, MigrationPhaseRestoring, fmt.Sprintf("Restoring %d pods of statefulset %s on target cluster", len(status.Ordinals), status.TargetName))
+	target, err := ensureTargetStatefulSet(ctx, sourceClient, targetClient, source, status)
+	if err != nil {
+		return err
+	}
+
+	for i := range status.Ordinals {
+		ordinal := &status.Ordinals[i]
+		if ordinal.Phase == OrdinalPhaseReady {
+			continue
+		}
+		if err := restoreStatefulSetOrdinal(ctx, memberCtx, targetClient, target, status, ordinal, checkpoints[ordinal.Ordinal]); err != nil {
+			return err
+		}
+		ordinal.Phase = OrdinalPhaseReady
+		status.Message = fmt.Sprintf("Pod %s is ready (%d/%d)", ordinal.PodName, i+1, len(status.Ordinals))
+		if err := saveMigrationStatus(saveCtx, status); err != nil {
+			klog.ErrorS(err, "Failed to save migration status", "migrationID", status.ID)
+		}
+	}
+	return nil
+}
+
+// waitForStatefulSetCheckpoints polls the source cluster until every ordinal of the StatefulSet has a completed
+// CheckpointBackup created after startedAt, and returns them by ordinal
+func waitForStatefulSetCheckpoints(ctx context.Context, c *gin.Context, status *MigrationStatus, req CreateMigrationRequest, startedAt time.Time) (map[int]*CheckpointBackup, error) {
+	dynamicClient, err := client.GetDynamicClientForMember(c, req.SourceCluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for source cluster: %v", err)
+	}
+
+	ticker := time.NewTicker(migrationPollInterval)
+	defer ticker.Stop()
+	for {
+		completed := map[int]*CheckpointBackup{}
+		list, err := dynamicClient.Resource(checkpointBackupGVR).Namespace(req.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.V(4).InfoS("Failed to list CheckpointBackup CRs", "cluster", req.SourceCluster, "error", err)
+		} else {
+			for i := range list.Items {
+				cb, err := decodeCheckpointBackup(&list.Items[i])
+				if err != nil {
+					klog.V(4).InfoS("Skipping CheckpointBackup", "cluster", req.SourceCluster, "error", err)
+					continue
+				}
+				ordinal, ok := migration.PodOrdinal(req.ResourceName, cb.Spec.PodName)
+				if !ok || cb.CreationTimestamp.Time.Before(startedAt.Truncate(time.Second)) {
+					continue
+				}
+				switch strings.ToLower(cb.Status.Phase) {
+				case "completed", "succeeded", "ready":
+					completed[ordinal] = cb
+				case "failed", "error":
+					return nil, fmt.Errorf("checkpoint %s of pod %s failed: %s", cb.Name, cb.Spec.PodName, cb.Status.Message)
+				}
+			}
+		}
+
+		missing := 0
+		for _, ordinal := range status.Ordinals {
+			if completed[ordinal.Ordinal] == nil {
+				missing++
+			}
+		}
+		if missing == 0 {
+			return completed, nil
+		}
+		status.Message = fmt.Sprintf("Waiting for the checkpoints of %d of %d pods", missing, len(status.Ordinals))
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the checkpoints of statefulset %s/%s, %d missing", req.Namespace, req.ResourceName, missing)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ensureTargetStatefulSet creates the services and the StatefulSet, scaled to zero, on the target cluster
+// unless a previous attempt did, and returns the StatefulSet
+func ensureTargetStatefulSet(ctx context.Context, sourceClient, targetClient kubeclient.Interface, source *appsv1.StatefulSet, status *MigrationStatus) (*appsv1.StatefulSet, error) {
+	// The governing service gives the pods their stable network identity, so it must exist before them
+	if source.Spec.ServiceName != "" {
+		service, err := sourceClient.CoreV1().Services(status.Namespace).Get(ctx, source.Spec.ServiceName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			klog.InfoS("Governing service of statefulset not found on source cluster", "namespace", status.Namespace, "service", source.Spec.ServiceName)
+		case err != nil:
+			return nil, fmt.Errorf("failed to get service %s: %v", source.Spec.ServiceName, err)
+		default:
+			target := migration.TargetService(service, status.TargetNamespace)
+			if _, err := targetClient.CoreV1().Services(status.TargetNamespace).Create(ctx, target, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+				return nil, fmt.Errorf("failed to create service %s on target cluster: %v", target.Name, err)
+			}
+		}
+	}
+
+	statefulSets := targetClient.AppsV1().StatefulSets(status.TargetNamespace)
+	target, err := statefulSets.Get(ctx, status.TargetName, metav1.GetOptions{})
+	if err == nil {
+		return target, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get statefulset %s on target cluster: %v", status.TargetName, err)
+	}
+	target, err = statefulSets.Create(ctx, migration.TargetStatefulSet(source, status.TargetName, status.TargetNamespace), metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create statefulset %s on target cluster: %v", status.TargetName, err)
+	}
+	return target, nil
+}
+
+// restoreStatefulSetOrdinal creates the claims of an ordinal, restores its pod from the checkpoint, waits for
+// the pod to be ready and scales the StatefulSet up so it adopts the pod
+func restoreStatefulSetOrdinal(ctx context.Context, memberCtx *gin.Context, targetClient kubeclient.Interface, target *appsv1.StatefulSet, status *MigrationStatus, ordinal *MigrationOrdinal, cb *CheckpointBackup) error {
+	// Claims restored from volume snapshots already exist and are kept
+	for _, claim := range migration.OrdinalClaims(target, ordinal.Ordinal) {
+		if _, err := targetClient.CoreV1().PersistentVolumeClaims(status.TargetNamespace).Create(ctx, claim, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create claim %s on target cluster: %v", claim.Name, err)
+		}
+	}
+
+	if ordinal.RestoreName == "" {
+		restore := newMigrationRestore(status, cb, fmt.Sprintf("migration-%s-%d", status.ID, ordinal.Ordinal), ordinal.PodName)
+		if err := applyMigrationRestore(ctx, memberCtx, status, restore); err != nil {
+			return err
+		}
+		ordinal.RestoreName = restore.Name
+		ordinal.Phase = OrdinalPhaseRestoring
+	}
+	if err := waitForNamedRestore(ctx, memberCtx, status, ordinal.RestoreName); err != nil {
+		return err
+	}
+	if err := waitForPodReady(ctx, targetClient, status.TargetNamespace, ordinal.PodName); err != nil {
+		return err
+	}
+
+	statefulSets := targetClient.AppsV1().StatefulSets(status.TargetNamespace)
+	current, err := statefulSets.Get(ctx, target.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get statefulset %s on target cluster: %v", target.Name, err)
+	}
+	replicas := int32(ordinal.Ordinal + 1)
+	if current.Spec.Replicas == nil || *current.Spec.Replicas < replicas {
+		current.Spec.Replicas = &replicas
+		if _, err := statefulSets.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to scale statefulset %s to %d: %v", target.Name, replicas, err)
+		}
+	}
+	return nil
+}
+
+// waitForPodReady polls the target cluster until the pod is ready
+func waitForPodReady(ctx context.Context, kubeClient kubeclient.Interface, namespace, name string) error {
+	ticker := time.NewTicker(migrationPollInterval)
+	defer ticker.Stop()
+	for {
+		pod, err := kubeClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).InfoS("Failed to get restored pod", "namespace", namespace, "pod", name, "error", err)
+		} else if migration.PodReady(pod) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pod %s/%s to be ready", namespace, name)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
+)
+
+const (
+	// networkTestAppLabel labels the probe pods of network connectivity tests
+	networkTestAppLabel = "migration-network-test"
+	// bandwidthServerPort is the port the payload server of the bandwidth check listens on
+	bandwidthServerPort = 8080
+)
+
+var (
+	// networkProbeImage runs the probe script, it needs curl and a shell
+	networkProbeImage = "curlimages/curl:8.10.1"
+	// bandwidthServerImage serves the payload of the bandwidth check with the busybox httpd
+	bandwidthServerImage = "busybox:1.36"
+	// networkTestTimeout bounds each wait for a probe pod, networkTestPollInterval is how often it is checked
+	networkTestTimeout      = 2 * time.Minute
+	networkTestPollInterval = 2 * time.Second
+)
+
+// NetworkTestRequest selects the clusters and registry of a migration to test
+type NetworkTestRequest struct {
+	// BackupID fills the source cluster and registry from a backup configuration
+	BackupID      string `json:"backupId,omitempty"`
+	SourceCluster string `json:"sourceCluster,omitempty" binding:"required_without=BackupID,omitempty,cluster"`
+	TargetCluster string `json:"targetCluster" binding:"required,cluster"`
+	RegistryID    string `json:"registryId,omitempty" binding:"required_without=BackupID"`
+	// Image is pulled on the target cluster with the pull secret of the registry, the pull check is skipped when empty
+	Image string `json:"image,omitempty"`
+}
+
+// networkTest are the resolved clusters and registry of a network connectivity test
+type networkTest struct {
+	id             string
+	sourceClient   kubeclient.Interface
+	targetClient   kubeclient.Interface
+	sourceEndpoint string
+	registryURL    string
+	pullSecret     string
+	image          string
+}
+
+// handleNetworkConnectivityTest checks that the target cluster of a migration can reach the registry,
+// pull from it and, where the network allows it, reach the source cluster. Short-lived probe pods
+// measure DNS resolution, latency and the bandwidth between the clusters; they are deleted afterwards.
+// Failing checks are reported, not returned as an error.
+func handleNetworkConnectivityTest(c *gin.Context) {
+	var req NetworkTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind network connectivity test request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	sourceCluster, registryID := req.SourceCluster, req.RegistryID
+	if req.BackupID != "" {
+		backup, err := getBackupByID(req.BackupID)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get backup configuration", "backupID", req.BackupID)
+			common.Fail(c, err)
+			return
+		}
+		if sourceCluster == "" {
+			sourceCluster = backup.Cluster
+		}
+		if registryID == "" {
+			registryID = backup.Registry.ID
+		}
+	}
+	for _, clusterName := range []string{sourceCluster, req.TargetCluster} {
+		if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	registry, err := getRegistryByID(registryID)
+	if err != nil {
+		common.Fail(c, fmt.Errorf("failed to get registry %s: %v", registryID, err))
+		return
+	}
+	registryURL, err := migration.RegistryProbeURL(registry.Registry)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	test := networkTest{
+		id:          rand.String(5),
+		registryURL: registryURL,
+		pullSecret:  registry.SecretName,
+		image:       req.Image,
+	}
+	if test.sourceClient = client.InClusterClientForMemberCluster(sourceCluster); test.sourceClient == nil {
+		common.Fail(c, fmt.Errorf("failed to get client for cluster %s", sourceCluster))
+		return
+	}
+	if test.targetClient = client.InClusterClientForMemberCluster(req.TargetCluster); test.targetClient == nil {
+		common.Fail(c, fmt.Errorf("failed to get client for cluster %s", req.TargetCluster))
+		return
+	}
+	source, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(c, sourceCluster, metav1.GetOptions{})
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	test.sourceEndpoint = source.Spec.APIEndpoint
+
+	report := &migration.NetworkReport{
+		SourceCluster: sourceCluster,
+		TargetCluster: req.TargetCluster,
+		Registry:      registry.Registry,
+		Success:       true,
+	}
+	runNetworkTest(c, test, report)
+	if !report.Success {
+		klog.InfoS("Network connectivity test failed", "source", sourceCluster, "target", req.TargetCluster, "registry", registry.Registry)
+	}
+	common.Success(c, report)
+}
+
+// runNetworkTest starts the probe pods of a test, waits for their results and records them in the report
+func runNetworkTest(ctx context.Context, test networkTest, report *migration.NetworkReport) {
+	var cleanups []func()
+	defer func() {
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
+	}()
+	start := func(memberClient kubeclient.Interface, pod *corev1.Pod) error {
+		if _, err := memberClient.CoreV1().Pods(registryNamespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		cleanups = append(cleanups, func() { deleteProbePod(memberClient, pod.Name) })
+		return nil
+	}
+
+	targets := []migration.ProbeTarget{
+		{Check: migration.NetworkCheckRegistry, URL: test.registryURL, Codes: []int{http.StatusOK, http.StatusUnauthorized}, ResolveCheck: true},
+	}
+	if test.sourceEndpoint != "" {
+		targets = append(targets, migration.ProbeTarget{
+			Check:    migration.NetworkCheckSourceAPI,
+			URL:      strings.TrimSuffix(test.sourceEndpoint, "/") + "/healthz",
+			Optional: true,
+		})
+	}
+
+	// Pods of the source cluster are only reachable from the target on a flat network, so bandwidth is optional
+	server := probePod("network-server-"+test.id, bandwidthServerImage, "mkdir -p /www && "+
+		fmt.Sprintf("head -c %d /dev/urandom > /www/payload && exec httpd -f -p %d -h /www", migration.BandwidthPayloadBytes, bandwidthServerPort))
+	bandwidthErr := start(test.sourceClient, server)
+	if bandwidthErr == nil {
+		var serverIP string
+		serverIP, bandwidthErr = waitForPodIP(ctx, test.sourceClient, server.Name)
+		if bandwidthErr == nil {
+			targets = append(targets, migration.ProbeTarget{
+				Check:    migration.NetworkCheckBandwidth,
+				URL:      fmt.Sprintf("http://%s:%d/payload", serverIP, bandwidthServerPort),
+				Codes:    []int{http.StatusOK},
+				Optional: true,
+			})
+		}
+	}
+
+	var pull *corev1.Pod
+	var pullStarted time.Time
+	if test.image != "" {
+		pull = probePod("image-pull-"+test.id, test.image, "")
+		pull.Spec.Containers[0].ImagePullPolicy = corev1.PullAlways
+		pull.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: test.pullSecret}}
+		pullStarted = time.Now()
+		if err := start(test.targetClient, pull); err != nil {
+			report.Record(migration.NetworkCheck{Name: migration.NetworkCheckImagePull, Target: test.image, Message: err.Error()})
+			pull = nil
+		}
+	}
+
+	probe := probePod("network-probe-"+test.id, networkProbeImage, migration.ProbeScript(targets))
+	if err := start(test.targetClient, probe); err != nil {
+		report.Record(migration.NetworkCheck{Name: migration.NetworkCheckRegistry, Target: test.registryURL, Message: err.Error()})
+	} else {
+		output, err := waitForProbeOutput(ctx, test.targetClient, probe.Name)
+		if err != nil {
+			klog.ErrorS(err, "Network probe did not complete", "pod", probe.Name)
+		}
+		for _, check := range migration.ParseProbeOutput(output, targets) {
+			report.Record(check)
+		}
+	}
+	if bandwidthErr != nil {
+		report.Record(migration.NetworkCheck{Name: migration.NetworkCheckBandwidth, Optional: true, Message: bandwidthErr.Error()})
+	}
+
+	if pull != nil {
+		report.Record(waitForImagePull(ctx, test.targetClient, pull, pullStarted))
+	}
+}
+
+// probePod returns a pod of a network connectivity test running a shell command, or the entrypoint of the image
+func probePod(name, image, command string) *corev1.Pod {
+	container := corev1.Container{Name: "probe", Image: image}
+	if command != "" {
+		container.Command = []string{"sh", "-c", command}
+	}
+	gracePeriod := int64(0)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: registryNamespace,
+			Labels:    map[string]string{"app": networkTestAppLabel, orphan.ManagedLabel: orphan.ManagedLabelValue},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &gracePeriod,
+			Containers:                    []corev1.Container{container},
+		},
+	}
+}
+
+// deleteProbePod removes a probe pod once a test is done, also when the request was cancelled
+func deleteProbePod(memberClient kubeclient.Interface, name string) {
+	err := memberClient.CoreV1().Pods(registryNamespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to delete network probe pod", "pod", name)
+	}
+}
+
+// waitForPodIP waits for the payload server of the bandwidth check to run and returns its pod IP
+func waitForPodIP(ctx context.Context, memberClient kubeclient.Interface, name string) (string, error) {
+	var podIP string
+	err := wait.PollUntilContextTimeout(ctx, networkTestPollInterval, networkTestTimeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := memberClient.CoreV1().Pods(registryNamespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			return false, fmt.Errorf("payload server exited")
+		}
+		podIP = pod.Status.PodIP
+		return pod.Status.Phase == corev1.PodRunning && podIP != "", nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("payload server on the source cluster did not start: %v", err)
+	}
+	return podIP, nil
+}
+
+// waitForProbeOutput waits for the probe pod to finish and returns its logs
+func waitForProbeOutput(ctx context.Context, memberClient kubeclient.Interface, name string) (string, error) {
+	pods := memberClient.CoreV1().Pods(registryNamespace)
+	err := wait.PollUntilContextTimeout(ctx, networkTestPollInterval, networkTestTimeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	logs, err := pods.GetLogs(name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	return string(logs), err
+}
+
+// waitForImagePull waits until the kubelet pulled the image of the pull test pod or gave up
+func waitForImagePull(ctx context.Context, memberClient kubeclient.Interface, pod *corev1.Pod, started time.Time) migration.NetworkCheck {
+	check := migration.NetworkCheck{Name: migration.NetworkCheckImagePull, Target: pod.Spec.Containers[0].Image}
+	err := wait.PollUntilContextTimeout(ctx, networkTestPollInterval, networkTestTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := memberClient.CoreV1().Pods(registryNamespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		var done bool
+		check, done = migration.ImagePullCheck(current)
+		return done, nil
+	})
+	if err != nil {
+		check.Message = fmt.Sprintf("image was not pulled: %v", err)
+		return check
+	}
+	check.LatencyMs = time.Since(started).Milliseconds()
+	return check
+}
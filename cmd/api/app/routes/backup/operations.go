@@ -0,0 +1,392 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+	clusterresource "github.com/karmada-io/dashboard/pkg/resource/cluster"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// ControllerOperation is an install, upgrade or uninstall of the migration controller that is running on a cluster
+type ControllerOperation struct {
+	ClusterName string `json:"clusterName"`
+	Operation   string `json:"operation"`
+	StartedAt   string `json:"startedAt"`
+}
+
+// controllerLockKind is the kind of the lock workload of a cluster's migration controller
+const controllerLockKind = "migration-controller"
+
+// controllerOperations allows one controller operation per cluster at a time, so concurrent
+// requests cannot apply and delete the same manifests. The operations are locked with Leases
+// so that they are serialized across all API replicas.
+type controllerOperations struct {
+	locks func() *migration.Locks
+	// ttl bounds how long the lock of an API replica that stopped without releasing it blocks the cluster,
+	// a running operation renews its lock well before
+	ttl time.Duration
+	// pollInterval is how often an operation that waits checks whether the cluster is free
+	pollInterval time.Duration
+}
+
+var clusterOperations = &controllerOperations{
+	locks: func() *migration.Locks {
+		return migration.NewLocks(client.InClusterClient(), config.GetNamespace(), nil)
+	},
+	ttl:          2 * time.Minute,
+	pollInterval: 2 * time.Second,
+}
+
+// errOperationInProgress is returned when a cluster already has a controller operation running
+type errOperationInProgress struct {
+	running *ControllerOperation
+}
+
+func (e *errOperationInProgress) Error() string {
+	return fmt.Sprintf("migration controller %s is already running on cluster %s since %s",
+		e.running.Operation, e.running.ClusterName, e.running.StartedAt)
+}
+
+// controllerLockWorkload returns the lock workload of the controller operations on a cluster
+func controllerLockWorkload(clusterName string) migration.Workload {
+	return migration.Workload{Cluster: clusterName, Kind: controllerLockKind}
+}
+
+// controllerLockID returns an ID that is unique to one operation on one API replica
+func controllerLockID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, time.Now().UnixNano())
+}
+
+// begin starts an operation on a cluster and returns the function that ends it. When another operation is
+// running, begin fails unless wait is set, in which case it queues until the cluster is free or ctx is done.
+func (o *controllerOperations) begin(ctx context.Context, clusterName, operation string, wait bool) (func(), error) {
+	locks := o.locks()
+	workload := controllerLockWorkload(clusterName)
+	holder := migration.LockHolder{Operation: operation, ID: controllerLockID()}
+	for {
+		err := locks.Acquire(ctx, workload, holder, o.ttl)
+		if err == nil {
+			break
+		}
+		var locked *migration.LockedError
+		if !errors.As(err, &locked) {
+			return nil, fmt.Errorf("failed to lock cluster %s: %v", clusterName, err)
+		}
+		running := &ControllerOperation{ClusterName: clusterName, Operation: locked.Holder.Operation, StartedAt: locked.Holder.AcquiredAt}
+		if !wait {
+			return nil, &errOperationInProgress{running: running}
+		}
+		klog.V(4).InfoS("Waiting for migration controller operation", "cluster", clusterName, "operation", operation, "running", running.Operation)
+		select {
+		case <-time.After(o.pollInterval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting for %s on cluster %s: %v", running.Operation, clusterName, ctx.Err())
+		}
+	}
+
+	// The lock is renewed until the operation ends, it is not tied to ctx which may end before
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(o.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := locks.Acquire(context.TODO(), workload, holder, o.ttl); err != nil {
+					klog.ErrorS(err, "Failed to renew migration controller operation lock", "cluster", clusterName, "operation", operation)
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			if err := locks.Release(context.TODO(), workload, holder); err != nil {
+				klog.ErrorS(err, "Failed to release migration controller operation lock", "cluster", clusterName, "operation", operation)
+			}
+		})
+	}, nil
+}
+
+// beginAll starts an operation on several clusters, either on all of them or on none
+func (o *controllerOperations) beginAll(ctx context.Context, clusterNames []string, operation string, wait bool) (func(), error) {
+	var releases []func()
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+	// Clusters are locked in a fixed order so two queued multi-cluster operations cannot deadlock
+	sorted := append([]string{}, clusterNames...)
+	sort.Strings(sorted)
+	for _, clusterName := range sorted {
+		r, err := o.begin(ctx, clusterName, operation, wait)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	return release, nil
+}
+
+// list returns the controller operations running on any API replica
+func (o *controllerOperations) list(ctx context.Context) ([]ControllerOperation, error) {
+	locks, err := o.locks().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	operations := make([]ControllerOperation, 0, len(locks))
+	for _, lock := range locks {
+		if lock.Workload.Kind != controllerLockKind {
+			continue
+		}
+		operations = append(operations, ControllerOperation{
+			ClusterName: lock.Workload.Cluster,
+			Operation:   lock.Holder.Operation,
+			StartedAt:   lock.Holder.AcquiredAt,
+		})
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].ClusterName < operations[j].ClusterName
+	})
+	return operations, nil
+}
+
+// controllerJobType is the job that installs, upgrades or uninstalls the migration controller
+const controllerJobType = "controller-operation"
+
+// controllerJobParams are the params of a controller operation job
+type controllerJobParams struct {
+	Operation string   `json:"operation"`
+	Clusters  []string `json:"clusters"`
+	Version   string   `json:"version,omitempty"`
+	// Selector installs or uninstalls the controller once for every cluster with these labels.
+	// Clusters are then the ones matching when the job was created.
+	Selector map[string]string `json:"selector,omitempty"`
+	// Force uninstalls a controller that is still in use, deleting the backup configurations of its clusters
+	Force bool `json:"force,omitempty"`
+}
+
+// keys are the names the operation locks: its clusters and, for a label selector, the selector
+func (p controllerJobParams) keys() []string {
+	if len(p.Selector) == 0 {
+		return p.Clusters
+	}
+	return append(append([]string{}, p.Clusters...), clusterresource.SelectorName(p.Selector))
+}
+
+// controllerJobState lists the clusters the operation completed on, which a retry skips
+type controllerJobState struct {
+	Completed []string `json:"completed"`
+}
+
+// runControllerJob runs a controller operation on its clusters one after the other
+func runControllerJob(ctx context.Context, run *jobs.Run) error {
+	var params controllerJobParams
+	if err := run.DecodeParams(&params); err != nil {
+		return err
+	}
+	var state controllerJobState
+	if _, err := run.DecodeState(&state); err != nil {
+		return err
+	}
+	completed := map[string]bool{}
+	for _, clusterName := range state.Completed {
+		completed[clusterName] = true
+	}
+
+	// Operations started outside of jobs, like remediations of the reconciler, are waited for
+	release, err := clusterOperations.beginAll(ctx, params.keys(), params.Operation, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if len(params.Selector) > 0 {
+		return runSelectorControllerJob(ctx, run, params)
+	}
+
+	for _, clusterName := range params.Clusters {
+		if completed[clusterName] {
+			continue
+		}
+		var err error
+		switch params.Operation {
+		case "install":
+			err = installMigrationController(clusterName, params.Version)
+		case "upgrade":
+			err = upgradeMigrationController(ctx, clusterName, params.Version)
+		case "uninstall":
+			if err = prepareUninstall(ctx, clusterName, params.Force); err == nil {
+				err = uninstallMigrationController(clusterName)
+			}
+		default:
+			return jobs.Permanent(fmt.Errorf("unknown migration controller operation %q", params.Operation))
+		}
+		if err != nil {
+			klog.ErrorS(err, "Migration controller operation failed", "cluster", clusterName, "operation", params.Operation)
+			return controllerJobError(fmt.Errorf("cluster %s: %w", clusterName, err))
+		}
+		state.Completed = append(state.Completed, clusterName)
+		message := fmt.Sprintf("Migration controller %s completed on cluster %s", params.Operation, strings.Join(state.Completed, ", "))
+		if err := run.SaveState(ctx, state, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runSelectorControllerJob installs or uninstalls the controller of a label selector. The policies select the
+// clusters by label, so it is done once rather than per cluster.
+func runSelectorControllerJob(ctx context.Context, run *jobs.Run, params controllerJobParams) error {
+	var err error
+	switch params.Operation {
+	case "install":
+		err = installMigrationControllerForSelector(params.Selector, params.Version)
+	case "uninstall":
+		for _, clusterName := range params.Clusters {
+			if err = prepareUninstall(ctx, clusterName, params.Force); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = uninstallMigrationControllerForSelector(params.Selector)
+		}
+	default:
+		return jobs.Permanent(fmt.Errorf("migration controller %s is not supported for a label selector", params.Operation))
+	}
+	if err != nil {
+		klog.ErrorS(err, "Migration controller operation failed", "selector", params.Selector, "operation", params.Operation)
+		return controllerJobError(err)
+	}
+	message := fmt.Sprintf("Migration controller %s completed on clusters matching %s",
+		params.Operation, labels.SelectorFromSet(params.Selector).String())
+	return run.SaveState(ctx, controllerJobState{Completed: params.Clusters}, message)
+}
+
+// controllerJobError returns the error of a failed operation. An uninstall refused because the controller is
+// in use is not retried, it has to be requested again with force.
+func controllerJobError(err error) error {
+	var blocked *migration.UninstallBlockedError
+	if errors.As(err, &blocked) {
+		return jobs.Permanent(err)
+	}
+	return err
+}
+
+// enqueueControllerOperation starts a controller operation requested through the API as a job. The request is
+// rejected with 409 while one of the clusters is busy, or the job waits until they are free with ?wait=true.
+func enqueueControllerOperation(c *gin.Context, params controllerJobParams) (*jobs.Job, bool) {
+	clusterNames, operation := params.keys(), params.Operation
+	audit.SetClusters(c, params.Clusters...)
+	if c.Query("wait") != "true" {
+		if err := controllerOperationConflict(c, clusterNames); err != nil {
+			klog.InfoS("Rejected migration controller operation", "clusters", clusterNames, "operation", operation, "error", err)
+			common.FailWithStatus(c, err, http.StatusConflict)
+			return nil, false
+		}
+	}
+	job, err := jobs.Enqueue(c, controllerJobType, params, jobs.EnqueueOptions{
+		Keys:      clusterNames,
+		CreatedBy: utilauth.GetAuthenticatedUser(c),
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to enqueue migration controller operation", "clusters", clusterNames, "operation", operation)
+		common.Fail(c, err)
+		return nil, false
+	}
+	return job, true
+}
+
+// controllerOperationConflict returns an error when a controller operation is running or queued on one of the clusters
+func controllerOperationConflict(ctx context.Context, clusterNames []string) error {
+	operations, err := clusterOperations.list(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check running operations: %v", err)
+	}
+	for _, op := range operations {
+		for _, clusterName := range clusterNames {
+			if op.ClusterName == clusterName {
+				running := op
+				return &errOperationInProgress{running: &running}
+			}
+		}
+	}
+	active, err := jobs.Active(ctx, controllerJobType, clusterNames)
+	if err != nil {
+		return fmt.Errorf("failed to check queued operations: %v", err)
+	}
+	if len(active) > 0 {
+		return fmt.Errorf("migration controller job %s is already %s on cluster %s",
+			active[0].ID, strings.ToLower(active[0].Phase), strings.Join(active[0].Keys, ", "))
+	}
+	return nil
+}
+
+// handleGetControllerOperations lists the controller operations that are running and the jobs that are not done
+func handleGetControllerOperations(c *gin.Context) {
+	operations, err := clusterOperations.list(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	list, err := jobs.List(c, controllerJobType)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	active := make([]*jobs.Job, 0, len(list))
+	for _, job := range list {
+		if !job.Done() {
+			active = append(active, job)
+		}
+	}
+	common.Success(c, gin.H{
+		"operations": operations,
+		"total":      len(operations),
+		"jobs":       active,
+	})
+}
+
+func init() {
+	jobs.Register(controllerJobType, runControllerJob)
+}
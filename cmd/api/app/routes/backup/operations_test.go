@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// newTestControllerOperations returns controller operations whose locks are shared like those of two API replicas
+func newTestControllerOperations() (*controllerOperations, *controllerOperations) {
+	kubeClient := kubefake.NewSimpleClientset()
+	newOperations := func() *controllerOperations {
+		return &controllerOperations{
+			locks: func() *migration.Locks {
+				return migration.NewLocks(kubeClient, "karmada-system", nil)
+			},
+			ttl:          time.Minute,
+			pollInterval: 10 * time.Millisecond,
+		}
+	}
+	return newOperations(), newOperations()
+}
+
+func TestControllerOperationsAcrossReplicas(t *testing.T) {
+	ctx := context.TODO()
+	replica1, replica2 := newTestControllerOperations()
+
+	release, err := replica1.begin(ctx, "member1", "install", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = replica2.begin(ctx, "member1", "uninstall", false)
+	var inProgress *errOperationInProgress
+	if !errors.As(err, &inProgress) || inProgress.running.Operation != "install" || inProgress.running.ClusterName != "member1" {
+		t.Fatalf("begin() on a busy cluster returned %v, expected the running install", err)
+	}
+	if releaseOther, err := replica2.begin(ctx, "member2", "uninstall", false); err != nil {
+		t.Errorf("begin() on another cluster failed: %v", err)
+	} else {
+		releaseOther()
+	}
+
+	operations, err := replica2.list(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(operations) != 1 || operations[0].ClusterName != "member1" || operations[0].Operation != "install" {
+		t.Errorf("list() = %+v, expected the install on member1", operations)
+	}
+
+	release()
+	release()
+	if operations, _ := replica2.list(ctx); len(operations) != 0 {
+		t.Errorf("list() after release = %+v", operations)
+	}
+	releaseAgain, err := replica2.begin(ctx, "member1", "uninstall", false)
+	if err != nil {
+		t.Fatalf("begin() after release failed: %v", err)
+	}
+	releaseAgain()
+}
+
+func TestControllerOperationsWait(t *testing.T) {
+	replica1, replica2 := newTestControllerOperations()
+	release, err := replica1.begin(context.TODO(), "member1", "upgrade", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := replica2.begin(ctx, "member1", "uninstall", true); err == nil {
+		t.Fatal("begin() returned before the running operation ended")
+	}
+
+	time.AfterFunc(20*time.Millisecond, release)
+	waited, err := replica2.begin(context.TODO(), "member1", "uninstall", true)
+	if err != nil {
+		t.Fatalf("begin() failed after waiting: %v", err)
+	}
+	waited()
+}
+
+func TestControllerOperationsBeginAll(t *testing.T) {
+	ctx := context.TODO()
+	replica1, replica2 := newTestControllerOperations()
+	release, err := replica1.begin(ctx, "member2", "configure", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := replica2.beginAll(ctx, []string{"member3", "member1", "member2"}, "install", false); err == nil {
+		t.Fatal("beginAll() succeeded with a busy cluster")
+	}
+	// The clusters locked before the busy one are released again
+	if operations, _ := replica2.list(ctx); len(operations) != 1 || operations[0].ClusterName != "member2" {
+		t.Errorf("list() after a failed beginAll() = %+v", operations)
+	}
+
+	release()
+	releaseAll, err := replica2.beginAll(ctx, []string{"member3", "member1", "member2"}, "install", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operations, _ := replica1.list(ctx); len(operations) != 3 {
+		t.Errorf("list() = %+v, expected all three clusters", operations)
+	}
+	releaseAll()
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	// statefulMigrationCRDPath is the StatefulMigration CRD manifest in the operator repository
+	statefulMigrationCRDPath = "config/crd/bases/migration.dcnlab.com_statefulmigrations.yaml"
+
+	// remediationConfigMapName stores the remediation history, one JSON list per cluster
+	remediationConfigMapName = "migration-controller-remediation"
+	maxRemediationHistory    = 50
+
+	remediationDetected     = "detected"
+	remediationRepaired     = "repaired"
+	remediationRepairFailed = "repair-failed"
+	remediationRecovered    = "recovered"
+)
+
+// RemediationEntry is a drift found by the controller reconciler and what was done about it
+type RemediationEntry struct {
+	Time    string `json:"time"`
+	Issue   string `json:"issue"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+// ControllerHealth is the result of the last reconciliation of a cluster
+type ControllerHealth struct {
+	CheckedAt string   `json:"checkedAt"`
+	Status    string   `json:"status"`
+	Healthy   bool     `json:"healthy"`
+	Issues    []string `json:"issues"`
+}
+
+// controllerReconciler verifies installed migration controllers and repairs drift
+type controllerReconciler struct {
+	autoRemediate bool
+
+	mu sync.Mutex
+	// health holds the last check per cluster, so detected issues are recorded once
+	health map[string]*ControllerHealth
+}
+
+var reconciler = &controllerReconciler{health: make(map[string]*ControllerHealth)}
+
+// StartControllerReconciler periodically checks the migration controllers until ctx is done.
+// A non-positive interval disables the reconciler; without autoRemediate drift is only recorded.
+func StartControllerReconciler(ctx context.Context, interval time.Duration, autoRemediate bool) {
+	if interval <= 0 {
+		klog.InfoS("Migration controller reconciler is disabled")
+		return
+	}
+	reconciler.autoRemediate = autoRemediate
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reconciler.reconcileAll(ctx)
+			}
+		}
+	}()
+	klog.InfoS("Migration controller reconciler started", "interval", interval, "autoRemediate", autoRemediate)
+}
+
+// installedClusters returns the clusters with a migration controller installed by the dashboard
+func installedClusters(ctx context.Context) ([]string, error) {
+	var clusters []string
+	_, err := client.InClusterClient().AppsV1().Deployments("stateful-migration").Get(ctx, "migration-backup-controller", metav1.GetOptions{})
+	if err == nil {
+		clusters = append(clusters, "mgmt-cluster")
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	daemonSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	daemonSets, err := karmadaDynamicClient.Resource(daemonSetGVR).Namespace("stateful-migration").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list controller DaemonSets: %v", err)
+	}
+	for _, ds := range daemonSets.Items {
+		// Controllers installed for a label selector are not tied to a cluster
+		if _, ok := ds.GetLabels()[migration.ControllerSelectorLabel]; ok {
+			continue
+		}
+		if name := strings.TrimPrefix(ds.GetName(), "checkpoint-backup-controller-"); name != ds.GetName() {
+			clusters = append(clusters, name)
+		}
+	}
+	return clusters, nil
+}
+
+func (r *controllerReconciler) reconcileAll(ctx context.Context) {
+	clusters, err := installedClusters(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Migration controller reconciler failed to list installed controllers")
+		return
+	}
+	for _, clusterName := range clusters {
+		// A cluster with an install, upgrade or uninstall in progress is checked on the next run
+		release, err := clusterOperations.begin(ctx, clusterName, "reconcile", false)
+		if err != nil {
+			klog.V(4).InfoS("Skipping migration controller reconcile", "cluster", clusterName, "reason", err)
+			continue
+		}
+		r.reconcileCluster(ctx, clusterName)
+		release()
+	}
+}
+
+// reconcileCluster checks one cluster, repairs what the dashboard owns and records the outcome
+func (r *controllerReconciler) reconcileCluster(ctx context.Context, clusterName string) {
+	var entries []RemediationEntry
+	now := time.Now().Format(time.RFC3339)
+	var issues []string
+
+	// repair fixes an issue when remediation is enabled; unresolved issues mark the cluster unhealthy
+	repair := func(issue string, fix func() error) {
+		if !r.autoRemediate {
+			issues = append(issues, issue)
+			return
+		}
+		entry := RemediationEntry{Time: now, Issue: issue, Action: remediationRepaired}
+		if err := fix(); err != nil {
+			entry.Action = remediationRepairFailed
+			entry.Message = err.Error()
+			issues = append(issues, issue)
+		}
+		entries = append(entries, entry)
+	}
+
+	if clusterName == "mgmt-cluster" {
+		if !statefulMigrationCRDExists(ctx) {
+			repair("StatefulMigration CRD is missing", func() error {
+				crdYAML, err := fetchYAMLFromURL(config.ManifestURL(statefulMigrationCRDPath))
+				if err != nil {
+					return err
+				}
+				return applyYAMLManifest(crdYAML, "")
+			})
+		}
+	} else {
+		missing, err := missingCheckpointBackupPolicies(ctx, clusterName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check propagation policies", "cluster", clusterName)
+		}
+		if len(missing) > 0 {
+			repair(fmt.Sprintf("%s is missing", strings.Join(missing, ", ")), func() error {
+				_, err := ensureCheckpointBackupPolicies(clusterName)
+				return err
+			})
+		}
+	}
+
+	// Readiness and CRDs in member clusters are not owned by the dashboard, so they are only recorded
+	status, _, statusErr := MigrationControllerStatus(&gin.Context{}, clusterName)
+	if status != "installed" {
+		issue := fmt.Sprintf("controller status is %s", status)
+		if statusErr != nil {
+			issue = fmt.Sprintf("%s: %v", issue, statusErr)
+		}
+		issues = append(issues, issue)
+	}
+
+	health := &ControllerHealth{CheckedAt: now, Status: status, Healthy: len(issues) == 0, Issues: issues}
+	r.mu.Lock()
+	previous := r.health[clusterName]
+	r.health[clusterName] = health
+	r.mu.Unlock()
+
+	// Repairs are always recorded, other issues only when they first appear
+	for _, issue := range issues {
+		if (previous == nil || !containsString(previous.Issues, issue)) && !hasEntry(entries, issue) {
+			entries = append(entries, RemediationEntry{Time: now, Issue: issue, Action: remediationDetected})
+		}
+	}
+	if previous != nil && !previous.Healthy && health.Healthy {
+		entries = append(entries, RemediationEntry{Time: now, Issue: strings.Join(previous.Issues, "; "), Action: remediationRecovered})
+	}
+
+	if len(entries) > 0 {
+		if err := appendRemediationHistory(ctx, clusterName, entries); err != nil {
+			klog.ErrorS(err, "Failed to record remediation history", "cluster", clusterName)
+		}
+	}
+}
+
+func hasEntry(entries []RemediationEntry, issue string) bool {
+	for _, entry := range entries {
+		if entry.Issue == issue {
+			return true
+		}
+	}
+	return false
+}
+
+func statefulMigrationCRDExists(ctx context.Context) bool {
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		return true
+	}
+	crdGVR := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	_, err = dynamicClient.Resource(crdGVR).Get(ctx, "statefulmigrations.migration.dcnlab.com", metav1.GetOptions{})
+	return !apierrors.IsNotFound(err)
+}
+
+// missingCheckpointBackupPolicies returns the propagation policies of a member cluster controller that are gone
+func missingCheckpointBackupPolicies(ctx context.Context, clusterName string) ([]string, error) {
+	karmadaClient := client.InClusterKarmadaClient()
+	var missing []string
+
+	policyName := fmt.Sprintf("checkpoint-backup-%s", clusterName)
+	_, err := karmadaClient.PolicyV1alpha1().PropagationPolicies("stateful-migration").Get(ctx, policyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		missing = append(missing, "PropagationPolicy/"+policyName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	clusterPolicyName := fmt.Sprintf("checkpoint-backup-cluster-rbac-%s", clusterName)
+	_, err = karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().Get(ctx, clusterPolicyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		missing = append(missing, "ClusterPropagationPolicy/"+clusterPolicyName)
+	} else if err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// getRemediationHistory returns the remediation history of a cluster, newest last
+func getRemediationHistory(ctx context.Context, clusterName string) ([]RemediationEntry, error) {
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, remediationConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []RemediationEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	history := []RemediationEntry{}
+	if data, ok := cm.Data[clusterName]; ok {
+		if err := json.Unmarshal([]byte(data), &history); err != nil {
+			return nil, fmt.Errorf("failed to parse remediation history: %v", err)
+		}
+	}
+	return history, nil
+}
+
+func appendRemediationHistory(ctx context.Context, clusterName string, entries []RemediationEntry) error {
+	k8sClient := client.InClusterClient()
+	configMaps := k8sClient.CoreV1().ConfigMaps(config.GetNamespace())
+
+	history, err := getRemediationHistory(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	history = append(history, entries...)
+	if len(history) > maxRemediationHistory {
+		history = history[len(history)-maxRemediationHistory:]
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	cm, err := configMaps.Get(ctx, remediationConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      remediationConfigMapName,
+				Namespace: config.GetNamespace(),
+			},
+			Data: map[string]string{clusterName: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[clusterName] = string(data)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// handleGetRemediationHistory returns the last health check and remediation history of a cluster
+func handleGetRemediationHistory(c *gin.Context) {
+	clusterName := c.Param("name")
+	if clusterName == "management" {
+		clusterName = "mgmt-cluster"
+	}
+
+	history, err := getRemediationHistory(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get remediation history", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	reconciler.mu.Lock()
+	health := reconciler.health[clusterName]
+	reconciler.mu.Unlock()
+
+	common.Success(c, gin.H{
+		"clusterName":   clusterName,
+		"autoRemediate": reconciler.autoRemediate,
+		"lastCheck":     health,
+		"history":       history,
+	})
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,8 +33,12 @@ import (
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
 	"github.com/karmada-io/dashboard/pkg/client"
 	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
 )
 
 // RecoveryRecord represents a recovery operation record
@@ -55,6 +60,8 @@ type RecoveryRecord struct {
 	CompletedAt   string `json:"completedAt,omitempty"`
 	CreatedAt     string `json:"createdAt"`
 	UpdatedAt     string `json:"updatedAt"`
+	// RestoredVolumes are the claims created from the volume snapshots of the backup
+	RestoredVolumes []RestoredVolume `json:"restoredVolumes,omitempty"`
 }
 
 // CheckpointRestoreEvent represents a recovery event from CheckpointRestore CR
@@ -87,10 +94,10 @@ type CheckpointRestoreEvent struct {
 type CreateRecoveryRequest struct {
 	Name            string `json:"name" binding:"required"`
 	BackupID        string `json:"backupId" binding:"required"`
-	TargetCluster   string `json:"targetCluster" binding:"required"`
+	TargetCluster   string `json:"targetCluster" binding:"required,cluster"`
 	RecoveryType    string `json:"recoveryType" binding:"required,oneof=restore migrate"`
-	TargetName      string `json:"targetName,omitempty"`      // Optional: different name for recovered resource
-	TargetNamespace string `json:"targetNamespace,omitempty"` // Optional: different namespace
+	TargetName      string `json:"targetName,omitempty" binding:"omitempty,dns1123subdomain"`  // Optional: different name for recovered resource
+	TargetNamespace string `json:"targetNamespace,omitempty" binding:"omitempty,dns1123label"` // Optional: different namespace
 }
 
 // RecoveryExecutionRequest represents a request to start recovery execution
@@ -98,15 +105,26 @@ type RecoveryExecutionRequest struct {
 	RecoveryID string `json:"recoveryId" binding:"required"`
 }
 
-// StatefulMigrationCR for recovery operations
-var recoveryStatefulMigrationGVR = schema.GroupVersionResource{
-	Group:    "migration.dcnlab.com",
-	Version:  "v1alpha1",
-	Resource: "statefulmigrations",
+// recoveryService returns the service managing the StatefulMigration CRs of recovery operations
+func recoveryService() (*migration.Service, error) {
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get dynamic client")
+		return nil, err
+	}
+	return migration.NewService(dynamicClient, migration.Recovery, config.GetNamespace()), nil
 }
 
 // handleGetCheckpointRestoreEvents handles GET requests for CheckpointRestore CRs from all member clusters
 func handleGetCheckpointRestoreEvents(c *gin.Context) {
+	if events, ok := cachedCheckpointRestoreEvents(c); ok {
+		common.SuccessWithClusterErrors(c, map[string]interface{}{
+			"events": events,
+			"total":  len(events),
+		}, nil)
+		return
+	}
+
 	karmadaClient := client.InClusterKarmadaClient()
 
 	// Get all member clusters
@@ -117,18 +135,10 @@ func handleGetCheckpointRestoreEvents(c *gin.Context) {
 		return
 	}
 
-	var allEvents []CheckpointRestoreEvent
-
-	// CheckpointRestore GVR
-	checkpointRestoreGVR := schema.GroupVersionResource{
-		Group:    "migration.dcnlab.com",
-		Version:  "v1",
-		Resource: "checkpointrestores",
-	}
-
-	// Iterate through each cluster and fetch CheckpointRestore CRs
+	// The clusters that are not ready or fail are listed in the errors of the response
+	var readyClusters []string
+	var clusterErrors []multicluster.ClusterError
 	for _, cluster := range clusterList.Items {
-		// Skip clusters that are not ready
 		isReady := false
 		for _, condition := range cluster.Status.Conditions {
 			if condition.Type == clusterv1alpha1.ClusterConditionReady && condition.Status == metav1.ConditionTrue {
@@ -136,41 +146,136 @@ func handleGetCheckpointRestoreEvents(c *gin.Context) {
 				break
 			}
 		}
-
 		if !isReady {
-			klog.V(4).InfoS("Skipping cluster that is not ready", "cluster", cluster.Name)
+			clusterErrors = append(clusterErrors, multicluster.ClusterError{Cluster: cluster.Name, Reason: multicluster.ReasonNotReady})
 			continue
 		}
+		readyClusters = append(readyClusters, cluster.Name)
+	}
 
+	results, errs := multicluster.Gather(c, readyClusters, func(ctx context.Context, clusterName string) ([]CheckpointRestoreEvent, error) {
 		// Create dynamic client for the member cluster
-		dynamicClient, err := client.GetDynamicClientForMember(c, cluster.Name)
+		dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
 		if err != nil {
-			klog.ErrorS(err, "Failed to create dynamic client for member cluster", "cluster", cluster.Name)
-			continue // Skip this cluster but continue with others
+			return nil, err
 		}
 
 		// List CheckpointRestore CRs in all namespaces
-		checkpointRestoreList, err := dynamicClient.Resource(checkpointRestoreGVR).List(context.TODO(), metav1.ListOptions{})
+		checkpointRestoreList, err := dynamicClient.Resource(checkpointRestoreGVR).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			// The CheckpointRestore CRD is not installed in this cluster
+			return nil, nil
+		}
 		if err != nil {
-			klog.V(4).InfoS("Failed to list CheckpointRestore CRs or CRD not available", "cluster", cluster.Name, "error", err)
-			continue // Skip this cluster if CheckpointRestore CRD is not available
+			return nil, err
 		}
 
 		// Convert each CheckpointRestore CR to CheckpointRestoreEvent
-		for _, checkpointRestore := range checkpointRestoreList.Items {
-			event := convertCheckpointRestoreToEvent(&checkpointRestore, cluster.Name)
-			allEvents = append(allEvents, event)
+		events := make([]CheckpointRestoreEvent, 0, len(checkpointRestoreList.Items))
+		for i := range checkpointRestoreList.Items {
+			events = append(events, convertCheckpointRestoreToEvent(&checkpointRestoreList.Items[i], clusterName))
 		}
+		return events, nil
+	})
+	clusterErrors = append(clusterErrors, errs...)
+
+	allEvents := []CheckpointRestoreEvent{}
+	for _, events := range results {
+		allEvents = append(allEvents, events...)
 	}
 
-	common.Success(c, map[string]interface{}{
+	common.SuccessWithClusterErrors(c, map[string]interface{}{
 		"events": allEvents,
 		"total":  len(allEvents),
-	})
+	}, clusterErrors)
 }
 
-// convertCheckpointRestoreToEvent converts a CheckpointRestore CR to CheckpointRestoreEvent
+// convertCheckpointRestoreToEvent converts a CheckpointRestore CR to CheckpointRestoreEvent.
+// CRs that do not match the typed schema go through the legacy field guessing.
 func convertCheckpointRestoreToEvent(cr *unstructured.Unstructured, clusterName string) CheckpointRestoreEvent {
+	restore, err := decodeCheckpointRestore(cr)
+	if err != nil || restore.Spec.BackupRef == nil {
+		klog.V(4).InfoS("Using legacy CheckpointRestore parsing", "cluster", clusterName, "name", cr.GetName(), "error", err)
+		return legacyCheckpointRestoreToEvent(cr, clusterName)
+	}
+
+	event := CheckpointRestoreEvent{
+		ID:              fmt.Sprintf("%s-%s-%s", clusterName, restore.Namespace, restore.Name),
+		Name:            restore.Name,
+		Namespace:       restore.Namespace,
+		Cluster:         clusterName,
+		TargetCluster:   restore.Spec.TargetCluster,
+		SourceCluster:   restore.Spec.BackupRef.Cluster,
+		Status:          restore.Status.Phase,
+		Phase:           restore.Status.Phase,
+		Progress:        int(restore.Status.Progress),
+		Message:         restore.Status.Message,
+		StartTime:       restore.Status.StartTime,
+		CompletionTime:  restore.Status.CompletionTime,
+		CreatedAt:       cr.GetCreationTimestamp().Format(time.RFC3339),
+		Spec:            make(map[string]interface{}),
+		Conditions:      make([]map[string]interface{}, 0, len(restore.Status.Conditions)),
+		ContainerImages: make([]string, 0),
+		BackupRef:       make(map[string]interface{}),
+	}
+	if spec, found, _ := unstructured.NestedMap(cr.Object, "spec"); found {
+		event.Spec = spec
+	}
+	if backupRef, found, _ := unstructured.NestedMap(cr.Object, "spec", "backupRef"); found {
+		event.BackupRef = backupRef
+	}
+
+	if ref := restore.Spec.BackupRef.ResourceRef; ref != nil {
+		event.ResourceType = ref.Kind
+		event.ResourceName = ref.Name
+		event.SourceNamespace = ref.Namespace
+	} else if restore.Spec.PodName != "" {
+		event.ResourceType = "Pod"
+		event.ResourceName = restore.Spec.PodName
+		event.SourceNamespace = restore.Spec.PodNamespace
+	}
+	event.SourceResource = event.ResourceName
+
+	for _, container := range restore.Spec.Containers {
+		if container.Image != "" {
+			event.ContainerImages = append(event.ContainerImages, container.Image)
+		}
+	}
+	event.ContainerImages = append(event.ContainerImages, restore.Status.RestoredImages...)
+
+	event.UpdatedAt = event.CreatedAt
+	for _, condition := range restore.Status.Conditions {
+		event.Conditions = append(event.Conditions, map[string]interface{}{
+			"type":               condition.Type,
+			"status":             condition.Status,
+			"reason":             condition.Reason,
+			"message":            condition.Message,
+			"lastTransitionTime": condition.LastTransitionTime,
+		})
+		if condition.LastTransitionTime != "" {
+			event.UpdatedAt = condition.LastTransitionTime
+		}
+	}
+
+	if event.SourceCluster == "" {
+		event.SourceCluster = "unknown-source"
+	}
+	if event.TargetCluster == "" {
+		event.TargetCluster = clusterName
+	}
+	if event.ResourceType == "" {
+		event.ResourceType = "Unknown"
+	}
+	if event.ResourceName == "" {
+		event.ResourceName = restore.Name
+		event.SourceResource = restore.Name
+	}
+	return event
+}
+
+// legacyCheckpointRestoreToEvent guesses the event fields of CheckpointRestore CRs created
+// by older operator versions, which used different field names
+func legacyCheckpointRestoreToEvent(cr *unstructured.Unstructured, clusterName string) CheckpointRestoreEvent {
 	event := CheckpointRestoreEvent{
 		ID:              fmt.Sprintf("%s-%s-%s", clusterName, cr.GetNamespace(), cr.GetName()),
 		Name:            cr.GetName(),
@@ -519,228 +624,310 @@ func convertCheckpointRestoreToEvent(cr *unstructured.Unstructured, clusterName
 	return event
 }
 
-// handleGetRecoveryHistory retrieves all recovery records
-func handleGetRecoveryHistory(c *gin.Context) {
-	dynamicClient, err := client.GetDynamicClient()
+// listRecoveries returns all recovery records
+func listRecoveries(ctx context.Context) ([]RecoveryRecord, error) {
+	service, err := recoveryService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
-		return
+		return nil, err
 	}
 
-	// List all StatefulMigration CRs for recovery operations
-	unstructuredList, err := dynamicClient.Resource(recoveryStatefulMigrationGVR).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: "app=recovery-migration",
-	})
+	items, err := service.List(ctx)
 	if err != nil {
 		klog.ErrorS(err, "Failed to list recovery StatefulMigration CRs")
+		return nil, err
+	}
+
+	recoveries := make([]RecoveryRecord, 0, len(items))
+	for _, item := range items {
+		recoveries = append(recoveries, statefulMigrationToRecovery(&item))
+	}
+	return recoveries, nil
+}
+
+// handleGetRecoveryHistory retrieves recovery records.
+// Supports filtering by status, targetCluster and a from/to time range, pagination
+// with page and itemsPerPage, and sortBy (defaults to newest startedAt first).
+func handleGetRecoveryHistory(c *gin.Context) {
+	filter, err := parseRecoveryFilter(c)
+	if err != nil {
 		common.Fail(c, err)
 		return
 	}
 
-	recoveries := make([]RecoveryRecord, 0, len(unstructuredList.Items))
-	for _, item := range unstructuredList.Items {
-		recovery := statefulMigrationToRecovery(&item)
-		recoveries = append(recoveries, recovery)
+	recoveries, err := listRecoveries(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
 	}
 
+	selected, total, summary := selectRecoveries(c, recoveries, filter)
 	common.Success(c, map[string]interface{}{
-		"recoveries": recoveries,
-		"total":      len(recoveries),
+		"recoveries": selected,
+		"total":      total,
+		"summary":    summary,
 	})
 }
 
-// handleGetRecoveryRecord retrieves a specific recovery record
-func handleGetRecoveryRecord(c *gin.Context) {
-	recoveryID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
+// getRecovery returns a recovery record
+func getRecovery(ctx context.Context, recoveryID string) (RecoveryRecord, error) {
+	service, err := recoveryService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, err
 	}
 
-	// Get the StatefulMigration CR for recovery
-	unstructuredObj, err := dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Get(context.TODO(),
-		fmt.Sprintf("recovery-%s", recoveryID), metav1.GetOptions{})
+	unstructuredObj, err := service.Get(ctx, recoveryID)
 	if err != nil {
 		klog.ErrorS(err, "Failed to get recovery StatefulMigration CR", "recoveryID", recoveryID)
+		return RecoveryRecord{}, err
+	}
+	return statefulMigrationToRecovery(unstructuredObj), nil
+}
+
+// handleGetRecoveryRecord retrieves a specific recovery record
+func handleGetRecoveryRecord(c *gin.Context) {
+	recovery, err := getRecovery(c, c.Param("id"))
+	if err != nil {
 		common.Fail(c, err)
 		return
 	}
-
-	recovery := statefulMigrationToRecovery(unstructuredObj)
 	common.Success(c, recovery)
 }
 
-// handleCreateRecovery creates a new recovery operation
-func handleCreateRecovery(c *gin.Context) {
-	var req CreateRecoveryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		klog.ErrorS(err, "Failed to bind recovery request")
-		common.Fail(c, err)
-		return
+// createRecovery validates a recovery request against the target cluster and creates the StatefulMigration CR
+// of the recovery. It returns the backup of the recovery as well, which is set before the request is checked.
+func createRecovery(ctx context.Context, req CreateRecoveryRequest) (RecoveryRecord, BackupConfiguration, error) {
+	if err := validateRecoveryTarget(req.TargetName, req.TargetNamespace); err != nil {
+		return RecoveryRecord{}, BackupConfiguration{}, &statusError{err: err, status: http.StatusBadRequest}
+	}
+	if err := checkNotCordoned(ctx, req.TargetCluster); err != nil {
+		return RecoveryRecord{}, BackupConfiguration{}, err
 	}
 
 	// Get backup configuration to extract source information
 	backup, err := getBackupByID(req.BackupID)
 	if err != nil {
 		klog.ErrorS(err, "Failed to get backup configuration", "backupID", req.BackupID)
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, BackupConfiguration{}, err
+	}
+
+	// Collisions on the target cluster are rejected now; the source cluster may be gone when recovering
+	// from a disaster, so a recovery without a plan is still created
+	var renames []RecoveryRename
+	plan, err := buildRecoveryPlan(ctx, backup, req.TargetCluster, recoveryRename(backup, req.TargetName, req.TargetNamespace))
+	if err != nil {
+		klog.InfoS("Creating recovery without pre-flight check", "backupID", req.BackupID, "reason", err.Error())
+		// The checkpoint must still be trusted and its encryption key available on the target cluster
+		plan := &RecoveryPlan{TargetCluster: req.TargetCluster, Conflicts: recoveryTrustConflicts(ctx, backup, req.TargetCluster)}
+		if err := plan.conflictError(); err != nil {
+			return RecoveryRecord{}, backup, &statusError{err: err, status: http.StatusConflict}
+		}
+	} else {
+		if err := plan.conflictError(); err != nil {
+			return RecoveryRecord{}, backup, &statusError{err: err, status: http.StatusConflict}
+		}
+		renames = plan.Renames()
 	}
 
 	// Generate unique ID for the recovery
-	recoveryID := generateRecoveryID(req.Name)
+	recoveryID := "recovery-" + migration.GenerateID(req.Name)
 
 	// Create StatefulMigration CR for recovery
 	statefulMigration := createRecoveryStatefulMigrationCR(recoveryID, req, backup)
+	if err := setRecoveryRenames(statefulMigration, renames); err != nil {
+		return RecoveryRecord{}, backup, err
+	}
 
-	dynamicClient, err := client.GetDynamicClient()
+	service, err := recoveryService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, backup, err
 	}
-	_, err = dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Create(context.TODO(),
-		statefulMigration, metav1.CreateOptions{})
-	if err != nil {
+	if _, err := service.Create(ctx, statefulMigration); err != nil {
 		klog.ErrorS(err, "Failed to create recovery StatefulMigration CR")
-		common.Fail(c, err)
+		return RecoveryRecord{}, backup, err
+	}
+	return statefulMigrationToRecovery(statefulMigration), backup, nil
+}
+
+// handleCreateRecovery creates a new recovery operation
+func handleCreateRecovery(c *gin.Context) {
+	var req CreateRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind recovery request")
+		common.FailWithBindError(c, err)
 		return
 	}
 
-	recovery := statefulMigrationToRecovery(statefulMigration)
+	recovery, backup, err := createRecovery(c, req)
+	audit.SetClusters(c, req.TargetCluster, backup.Cluster)
+	if err != nil {
+		failBackupChange(c, err)
+		return
+	}
 	common.Success(c, recovery)
 }
 
-// handleExecuteRecovery starts the execution of a recovery operation
-func handleExecuteRecovery(c *gin.Context) {
-	recoveryID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
+// executeRecovery starts the execution of a recovery operation on behalf of the user. The workload of the
+// recovery stays locked until the recovery ends, so that other recoveries and migrations of it are rejected
+// meanwhile. The recovery is returned once it is found, even when it could not be started.
+func executeRecovery(c *gin.Context, recoveryID, user string) (RecoveryRecord, error) {
+	service, err := recoveryService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, err
 	}
 
-	// Get the StatefulMigration CR
-	smName := fmt.Sprintf("recovery-%s", recoveryID)
-	unstructuredObj, err := dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Get(context.TODO(),
-		smName, metav1.GetOptions{})
+	sm, err := service.Get(c, recoveryID)
 	if err != nil {
 		klog.ErrorS(err, "Failed to get recovery StatefulMigration CR", "recoveryID", recoveryID)
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, err
 	}
-
-	// Update the CR to trigger recovery execution
-	spec, found, err := unstructured.NestedMap(unstructuredObj.Object, "spec")
-	if err != nil || !found {
-		common.Fail(c, fmt.Errorf("failed to get spec from recovery StatefulMigration CR"))
-		return
+	recovery := statefulMigrationToRecovery(sm)
+	// Workloads can still be recovered from a cluster in maintenance, but not onto one
+	if err := checkNotInMaintenance(c, recovery.TargetCluster); err != nil {
+		klog.InfoS("Recovery execution rejected", "recoveryID", recoveryID, "reason", err.Error())
+		return recovery, err
+	}
+	workload := recoveryWorkload(recovery)
+	holder := migration.LockHolder{Operation: lockOperationRecovery, ID: recoveryID, User: user}
+	if err := workloadLocks().Acquire(c, workload, holder, workloadLockTTL); err != nil {
+		klog.InfoS("Recovery execution rejected", "recoveryID", recoveryID, "reason", err.Error())
+		return recovery, err
 	}
 
-	// Add execution trigger
-	spec["executeNow"] = time.Now().Unix()
-	spec["phase"] = "running"
-	unstructured.SetNestedMap(unstructuredObj.Object, spec, "spec")
+	updated, err := service.Execute(c, recoveryID, func(sm *unstructured.Unstructured) error {
+		if err := unstructured.SetNestedField(sm.Object, "running", "spec", "phase"); err != nil {
+			return err
+		}
+		status := map[string]interface{}{
+			"phase":     "running",
+			"startedAt": time.Now().Format(time.RFC3339),
+			"progress":  int64(0),
+		}
+		if err := unstructured.SetNestedMap(sm.Object, status, "status"); err != nil {
+			return err
+		}
 
-	// Update status
-	status := map[string]interface{}{
-		"phase":     "running",
-		"startedAt": time.Now().Format(time.RFC3339),
-		"progress":  0,
+		// Dependencies and volumes are restored before the checkpoint so the restored workload finds them
+		if err := relocateRecoveryDependencies(c, sm); err != nil {
+			klog.ErrorS(err, "Failed to relocate recovery dependencies", "recoveryID", recoveryID)
+			return err
+		}
+		if err := restoreRecoveryVolumes(c, sm); err != nil {
+			klog.ErrorS(err, "Failed to restore volume snapshots", "recoveryID", recoveryID)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to trigger recovery execution", "recoveryID", recoveryID)
+		releaseWorkloadLock(c, workload, holder)
+		return recovery, err
 	}
-	unstructured.SetNestedMap(unstructuredObj.Object, status, "status")
+	return statefulMigrationToRecovery(updated), nil
+}
 
-	_, err = dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Update(context.TODO(),
-		unstructuredObj, metav1.UpdateOptions{})
+// handleExecuteRecovery starts the execution of a recovery operation
+func handleExecuteRecovery(c *gin.Context) {
+	recoveryID := c.Param("id")
+	recovery, err := executeRecovery(c, recoveryID, utilauth.GetAuthenticatedUser(c))
+	audit.SetClusters(c, recovery.TargetCluster, recovery.SourceCluster)
 	if err != nil {
-		klog.ErrorS(err, "Failed to trigger recovery execution")
-		common.Fail(c, err)
+		failBackupChange(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Recovery execution started successfully",
+	common.Success(c, RecoveryActionResult{
+		ID:      recoveryID,
+		Message: "Recovery execution started successfully",
+		Phase:   "running",
 	})
 }
 
-// handleDeleteRecoveryRecord deletes a recovery record
+// handleDeleteRecoveryRecord moves a recovery record to the trash, or deletes it for good with ?permanent=true
 func handleDeleteRecoveryRecord(c *gin.Context) {
 	recoveryID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
+	service, err := recoveryService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
 		common.Fail(c, err)
 		return
 	}
 
-	smName := fmt.Sprintf("recovery-%s", recoveryID)
-	err = dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Delete(context.TODO(),
-		smName, metav1.DeleteOptions{})
-	if err != nil {
+	permanent := c.Query("permanent") == "true"
+	if err := deleteRecord(c, service, recoveryID, utilauth.GetAuthenticatedUser(c), permanent); err != nil {
 		klog.ErrorS(err, "Failed to delete recovery StatefulMigration CR", "recoveryID", recoveryID)
 		common.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Recovery record deleted successfully",
-	})
+	message := "Recovery record deleted successfully"
+	if !permanent {
+		message = fmt.Sprintf("Recovery record moved to the trash, it is purged after %s", config.TrashRetention())
+	}
+	common.Success(c, RecoveryActionResult{ID: recoveryID, Message: message})
 }
 
-// handleCancelRecovery cancels a running recovery operation
-func handleCancelRecovery(c *gin.Context) {
-	recoveryID := c.Param("id")
-	dynamicClient, err := client.GetDynamicClient()
+// cancelRecovery cancels a running recovery operation on behalf of the user. It is rejected while another
+// operation holds the lock of the workload, and releases the lock of the recovery. The recovery is returned
+// once it is found, even when it could not be cancelled.
+func cancelRecovery(ctx context.Context, recoveryID, user string) (RecoveryRecord, error) {
+	service, err := recoveryService()
 	if err != nil {
-		klog.ErrorS(err, "Failed to get dynamic client")
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, err
 	}
 
-	// Get the StatefulMigration CR
-	smName := fmt.Sprintf("recovery-%s", recoveryID)
-	unstructuredObj, err := dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Get(context.TODO(),
-		smName, metav1.GetOptions{})
+	sm, err := service.Get(ctx, recoveryID)
 	if err != nil {
 		klog.ErrorS(err, "Failed to get recovery StatefulMigration CR", "recoveryID", recoveryID)
-		common.Fail(c, err)
-		return
+		return RecoveryRecord{}, err
 	}
-
-	// Update the CR to cancel recovery
-	spec, found, err := unstructured.NestedMap(unstructuredObj.Object, "spec")
-	if err != nil || !found {
-		common.Fail(c, fmt.Errorf("failed to get spec from recovery StatefulMigration CR"))
-		return
+	recovery := statefulMigrationToRecovery(sm)
+	workload := recoveryWorkload(recovery)
+	holder := migration.LockHolder{Operation: lockOperationRecovery, ID: recoveryID, User: user}
+	if err := workloadLocks().Acquire(ctx, workload, holder, workloadLockTTL); err != nil {
+		klog.InfoS("Recovery cancellation rejected", "recoveryID", recoveryID, "reason", err.Error())
+		return recovery, err
 	}
 
-	spec["phase"] = "cancelled"
-	unstructured.SetNestedMap(unstructuredObj.Object, spec, "spec")
+	updated, err := service.Mutate(ctx, recoveryID, func(sm *unstructured.Unstructured) error {
+		spec, found, err := unstructured.NestedMap(sm.Object, "spec")
+		if err != nil || !found {
+			return fmt.Errorf("failed to get spec from recovery StatefulMigration CR")
+		}
+		spec["phase"] = "cancelled"
+		if err := unstructured.SetNestedMap(sm.Object, spec, "spec"); err != nil {
+			return err
+		}
 
-	// Update status
-	status := map[string]interface{}{
-		"phase":       "cancelled",
-		"completedAt": time.Now().Format(time.RFC3339),
+		status := map[string]interface{}{
+			"phase":       "cancelled",
+			"completedAt": time.Now().Format(time.RFC3339),
+		}
+		return unstructured.SetNestedMap(sm.Object, status, "status")
+	})
+	// A running recovery that could not be cancelled keeps its lock
+	if err == nil || recovery.Status != "running" {
+		releaseWorkloadLock(ctx, workload, holder)
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to cancel recovery", "recoveryID", recoveryID)
+		return recovery, err
 	}
-	unstructured.SetNestedMap(unstructuredObj.Object, status, "status")
+	return statefulMigrationToRecovery(updated), nil
+}
 
-	_, err = dynamicClient.Resource(recoveryStatefulMigrationGVR).Namespace(config.GetNamespace()).Update(context.TODO(),
-		unstructuredObj, metav1.UpdateOptions{})
+// handleCancelRecovery cancels a running recovery operation
+func handleCancelRecovery(c *gin.Context) {
+	recoveryID := c.Param("id")
+	recovery, err := cancelRecovery(c, recoveryID, utilauth.GetAuthenticatedUser(c))
+	audit.SetClusters(c, recovery.TargetCluster, recovery.SourceCluster)
 	if err != nil {
-		klog.ErrorS(err, "Failed to cancel recovery")
-		common.Fail(c, err)
+		failBackupChange(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Recovery operation cancelled successfully",
+	common.Success(c, RecoveryActionResult{
+		ID:      recoveryID,
+		Message: "Recovery operation cancelled successfully",
+		Phase:   "cancelled",
 	})
 }
 
@@ -784,9 +971,6 @@ func handleGetBackupHistory(c *gin.Context) {
 // Helper functions
 
 func statefulMigrationToRecovery(sm *unstructured.Unstructured) RecoveryRecord {
-	spec, _, _ := unstructured.NestedMap(sm.Object, "spec")
-	status, _, _ := unstructured.NestedMap(sm.Object, "status")
-
 	recovery := RecoveryRecord{
 		ID:        sm.GetLabels()["recovery-id"],
 		Name:      sm.GetName(),
@@ -796,66 +980,43 @@ func statefulMigrationToRecovery(sm *unstructured.Unstructured) RecoveryRecord {
 		Progress:  0,
 	}
 
-	// Extract fields from spec
-	if backupID, found, _ := unstructured.NestedString(spec, "backupID"); found {
-		recovery.BackupID = backupID
-	}
-	if backupName, found, _ := unstructured.NestedString(spec, "backupName"); found {
-		recovery.BackupName = backupName
-	}
-	if sourceCluster, found, _ := unstructured.NestedString(spec, "sourceCluster"); found {
-		recovery.SourceCluster = sourceCluster
-	}
-	if targetCluster, found, _ := unstructured.NestedString(spec, "targetCluster"); found {
-		recovery.TargetCluster = targetCluster
-	}
-	if resourceType, found, _ := unstructured.NestedString(spec, "resourceType"); found {
-		recovery.ResourceType = resourceType
-	}
-	if resourceName, found, _ := unstructured.NestedString(spec, "resourceName"); found {
-		recovery.ResourceName = resourceName
-	}
-	if namespace, found, _ := unstructured.NestedString(spec, "namespace"); found {
-		recovery.Namespace = namespace
-	}
-	if recoveryType, found, _ := unstructured.NestedString(spec, "recoveryType"); found {
-		recovery.RecoveryType = recoveryType
+	rm, err := decodeRecoveryMigration(sm)
+	if err != nil {
+		klog.V(4).InfoS("Failed to decode recovery StatefulMigration", "name", sm.GetName(), "error", err)
+		return recovery
 	}
 
-	// Extract fields from status
-	if phase, found, _ := unstructured.NestedString(status, "phase"); found {
-		recovery.Status = phase
-	}
-	if progress, found, _ := unstructured.NestedInt64(status, "progress"); found {
-		recovery.Progress = int(progress)
-	}
-	if errorMsg, found, _ := unstructured.NestedString(status, "error"); found {
-		recovery.Error = errorMsg
-	}
-	if startedAt, found, _ := unstructured.NestedString(status, "startedAt"); found {
-		recovery.StartedAt = startedAt
-	}
-	if completedAt, found, _ := unstructured.NestedString(status, "completedAt"); found {
-		recovery.CompletedAt = completedAt
+	recovery.BackupID = rm.Spec.BackupID
+	recovery.BackupName = rm.Spec.BackupName
+	recovery.SourceCluster = rm.Spec.SourceCluster
+	recovery.TargetCluster = rm.Spec.TargetCluster
+	recovery.ResourceType = rm.Spec.ResourceType
+	recovery.ResourceName = rm.Spec.ResourceName
+	recovery.Namespace = rm.Spec.Namespace
+	recovery.RecoveryType = rm.Spec.RecoveryType
+
+	if rm.Status.Phase != "" {
+		recovery.Status = rm.Status.Phase
 	}
+	recovery.Progress = int(rm.Status.Progress)
+	recovery.Error = rm.Status.Error
+	recovery.StartedAt = rm.Status.StartedAt
+	recovery.CompletedAt = rm.Status.CompletedAt
+	recovery.RestoredVolumes = restoredVolumesFromAnnotations(sm)
 
 	return recovery
 }
 
 func createRecoveryStatefulMigrationCR(recoveryID string, req CreateRecoveryRequest, backup BackupConfiguration) *unstructured.Unstructured {
 	sm := &unstructured.Unstructured{}
-	sm.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "migration.dcnlab.com",
-		Version: "v1alpha1",
-		Kind:    "StatefulMigration",
-	})
+	sm.SetGroupVersionKind(migration.Recovery.Resource.GroupVersion().WithKind("StatefulMigration"))
 
-	sm.SetName(fmt.Sprintf("recovery-%s", recoveryID))
+	sm.SetName(migration.Recovery.Name(recoveryID))
 	sm.SetNamespace(config.GetNamespace())
 
 	// Set labels
 	sm.SetLabels(map[string]string{
-		"app":         "recovery-migration",
+		"app":         migration.Recovery.App,
 		"recovery-id": recoveryID,
 		"backup-id":   req.BackupID,
 		"type":        "recovery",
@@ -889,7 +1050,7 @@ func createRecoveryStatefulMigrationCR(recoveryID string, req CreateRecoveryRequ
 		"targetName":      targetName,
 		"targetNamespace": targetNamespace,
 		"recoveryType":    req.RecoveryType,
-		"imageRepository": fmt.Sprintf("%s/%s", backup.Registry.Registry, backup.Repository),
+		"imageRepository": migration.RewriteImage(fmt.Sprintf("%s/%s", backup.Registry.Registry, backup.Repository), migration.ClusterImageRewrites(req.TargetCluster)),
 		"registryID":      backup.Registry.ID,
 		"phase":           "pending",
 	}
@@ -901,7 +1062,7 @@ func createRecoveryStatefulMigrationCR(recoveryID string, req CreateRecoveryRequ
 	}
 
 	sm.Object = map[string]interface{}{
-		"apiVersion": "migration.dcnlab.com/v1alpha1",
+		"apiVersion": migration.Recovery.Resource.GroupVersion().String(),
 		"kind":       "StatefulMigration",
 		"metadata":   sm.Object["metadata"],
 		"spec":       spec,
@@ -911,19 +1072,13 @@ func createRecoveryStatefulMigrationCR(recoveryID string, req CreateRecoveryRequ
 	return sm
 }
 
-func generateRecoveryID(name string) string {
-	return fmt.Sprintf("recovery-%s-%d", strings.ToLower(strings.ReplaceAll(name, " ", "-")), time.Now().Unix())
-}
-
 func getBackupByID(backupID string) (BackupConfiguration, error) {
 	dynamicClient, err := client.GetDynamicClient()
 	if err != nil {
 		return BackupConfiguration{}, err
 	}
-	smName := fmt.Sprintf("backup-%s", backupID)
 
-	unstructuredObj, err := dynamicClient.Resource(statefulMigrationGVR).Namespace(config.GetNamespace()).Get(context.TODO(),
-		smName, metav1.GetOptions{})
+	unstructuredObj, err := migration.NewService(dynamicClient, migration.Backup, config.GetNamespace()).Get(context.TODO(), backupID)
 	if err != nil {
 		return BackupConfiguration{}, err
 	}
@@ -933,6 +1088,11 @@ func getBackupByID(backupID string) (BackupConfiguration, error) {
 
 func configMapToBackupHistory(cm *unstructured.Unstructured) map[string]interface{} {
 	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	// The containers that were checkpointed, recorded when the backup selects containers
+	var containers []string
+	if data["containers"] != "" {
+		containers = strings.Split(data["containers"], ",")
+	}
 
 	return map[string]interface{}{
 		"id":             cm.GetName(),
@@ -941,7 +1101,9 @@ func configMapToBackupHistory(cm *unstructured.Unstructured) map[string]interfac
 		"duration":       data["duration"],
 		"size":           data["size"],
 		"error":          data["error"],
+		"attempt":        data["attempt"],
 		"checkpointPath": data["checkpointPath"],
+		"containers":     containers,
 	}
 }
 
@@ -951,9 +1113,13 @@ func init() {
 
 	// Recovery management routes
 	recoveryGroup := r.Group("/backup/recovery")
+	recoveryGroup.Use(idempotencyMiddleware())
 	{
 		recoveryGroup.GET("", handleGetRecoveryHistory)
 		recoveryGroup.POST("", handleCreateRecovery)
+		recoveryGroup.POST("/preflight", handleRecoveryPreflight)
+		recoveryGroup.POST("/simulate", handleSimulateRecovery)
+		recoveryGroup.POST("/connectivity-test", handleNetworkConnectivityTest)
 		recoveryGroup.GET("/:id", handleGetRecoveryRecord)
 		recoveryGroup.POST("/:id/execute", handleExecuteRecovery)
 		recoveryGroup.POST("/:id/cancel", handleCancelRecovery)
@@ -961,6 +1127,8 @@ func init() {
 
 		// CheckpointRestore events endpoint
 		recoveryGroup.GET("/checkpoint-restore-events", handleGetCheckpointRestoreEvents)
+		recoveryGroup.GET("/checkpoint-restore-events/:cluster/:namespace/:name", handleGetCheckpointRestoreEvent)
+		recoveryGroup.POST("/checkpoint-restore-events/:cluster/:namespace/:name/retry", handleRetryCheckpointRestore)
 
 		// Backup history endpoint
 		recoveryGroup.GET("/backup/:backupId/history", handleGetBackupHistory)
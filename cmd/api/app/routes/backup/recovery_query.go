@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/dataselect"
+)
+
+// startedAtProperty sorts recoveries by start time, falling back to creation time
+const startedAtProperty dataselect.PropertyName = "startedAt"
+
+// recoveryCell is a cell representation of RecoveryRecord for sorting and pagination
+type recoveryCell RecoveryRecord
+
+func (r recoveryCell) startedAt() string {
+	if r.StartedAt != "" {
+		return r.StartedAt
+	}
+	return r.CreatedAt
+}
+
+// GetProperty returns value of a given property.
+func (r recoveryCell) GetProperty(name dataselect.PropertyName) dataselect.ComparableValue {
+	switch name {
+	case dataselect.NameProperty:
+		return dataselect.StdComparableString(r.Name)
+	case dataselect.CreationTimestampProperty:
+		return dataselect.StdComparableRFC3339Timestamp(r.CreatedAt)
+	case startedAtProperty:
+		return dataselect.StdComparableRFC3339Timestamp(r.startedAt())
+	default:
+		return nil
+	}
+}
+
+// RecoveryFilter holds the recovery history query parameters
+type RecoveryFilter struct {
+	// Statuses matches any of the phases, case insensitive
+	Statuses      []string
+	TargetCluster string
+	From          time.Time
+	To            time.Time
+}
+
+// parseRecoveryFilter reads status (comma separated), targetCluster, and the RFC3339 from/to range
+func parseRecoveryFilter(c *gin.Context) (*RecoveryFilter, error) {
+	filter := &RecoveryFilter{TargetCluster: c.Query("targetCluster")}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			filter.Statuses = append(filter.Statuses, strings.ToLower(status))
+		}
+	}
+	for param, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC3339 timestamp: %v", param, err)
+		}
+		*target = parsed
+	}
+	return filter, nil
+}
+
+// matches reports whether the recovery matches the filter, optionally ignoring the status
+func (f *RecoveryFilter) matches(recovery RecoveryRecord, ignoreStatus bool) bool {
+	if !ignoreStatus && len(f.Statuses) > 0 && !containsString(f.Statuses, strings.ToLower(recovery.Status)) {
+		return false
+	}
+	if f.TargetCluster != "" && recovery.TargetCluster != f.TargetCluster {
+		return false
+	}
+	if !f.From.IsZero() || !f.To.IsZero() {
+		startedAt, err := time.Parse(time.RFC3339, recoveryCell(recovery).startedAt())
+		if err != nil {
+			return false
+		}
+		if !f.From.IsZero() && startedAt.Before(f.From) {
+			return false
+		}
+		if !f.To.IsZero() && startedAt.After(f.To) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectRecoveries filters, sorts and paginates the recoveries. The summary counts recoveries
+// by phase with every filter but the status applied, so it can drive status tabs.
+func selectRecoveries(c *gin.Context, recoveries []RecoveryRecord, filter *RecoveryFilter) ([]RecoveryRecord, int, map[string]int) {
+	summary := map[string]int{}
+	cells := make([]dataselect.DataCell, 0, len(recoveries))
+	for _, recovery := range recoveries {
+		if !filter.matches(recovery, true) {
+			continue
+		}
+		summary[strings.ToLower(recovery.Status)]++
+		if filter.matches(recovery, false) {
+			cells = append(cells, recoveryCell(recovery))
+		}
+	}
+
+	dsQuery := common.ParseDataSelectPathParameter(c)
+	if c.Query("sortBy") == "" {
+		dsQuery.SortQuery = dataselect.NewSortQuery([]string{"d", string(startedAtProperty)})
+	}
+	// Status and cluster filters are handled above
+	dsQuery.FilterQuery = dataselect.NoFilter
+
+	selected, total := dataselect.GenericDataSelectWithFilter(cells, dsQuery)
+	result := make([]RecoveryRecord, 0, len(selected))
+	for _, cell := range selected {
+		result = append(result, RecoveryRecord(cell.(recoveryCell)))
+	}
+	return result, total, summary
+}
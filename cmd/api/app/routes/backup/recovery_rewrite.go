@@ -0,0 +1,595 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// Actions taken for the objects of a recovery on the target cluster
+const (
+	// RecoveryActionCreate copies the object from the source cluster
+	RecoveryActionCreate = "create"
+	// RecoveryActionReuse keeps the object that already exists on the target cluster
+	RecoveryActionReuse = "reuse"
+	// RecoveryActionRestore creates the claim from the volume snapshots of the backup
+	RecoveryActionRestore = "restore"
+	// RecoveryActionSkip ignores an object missing on the source cluster
+	RecoveryActionSkip = "skip"
+)
+
+// Where a recovery reads the backed up workload and its dependencies from
+const (
+	// RecoverySourceManifests are the manifests exported with the checkpoints of the backup
+	RecoverySourceManifests = "manifests"
+	// RecoverySourceCluster is the source cluster, for backups without exported manifests
+	RecoverySourceCluster = "cluster"
+)
+
+// RecoveryResource is an object the recovered workload depends on and where it goes on the target cluster
+type RecoveryResource struct {
+	Kind       string `json:"kind"`
+	SourceName string `json:"sourceName"`
+	TargetName string `json:"targetName"`
+	Action     string `json:"action"`
+}
+
+// RecoveryRename is a dependency renamed by a recovery, which the restored workload must reference by its new name
+type RecoveryRename struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	TargetName string `json:"targetName"`
+}
+
+// RecoveryPlan is the pre-flight report of a recovery: where the workload and its dependencies go
+// and which of them collide with objects on the target cluster
+type RecoveryPlan struct {
+	SourceCluster   string               `json:"sourceCluster"`
+	SourceName      string               `json:"sourceName"`
+	SourceNamespace string               `json:"sourceNamespace"`
+	TargetCluster   string               `json:"targetCluster"`
+	TargetName      string               `json:"targetName"`
+	TargetNamespace string               `json:"targetNamespace"`
+	CreateNamespace bool                 `json:"createNamespace"`
+	Resources       []RecoveryResource   `json:"resources"`
+	Conflicts       []migration.Conflict `json:"conflicts"`
+	// Source is where the workload and its dependencies are read from, RecoverySourceManifests or RecoverySourceCluster
+	Source string `json:"source"`
+	// ManifestsCapturedAt is when the manifests the recovery reads were exported
+	ManifestsCapturedAt string `json:"manifestsCapturedAt,omitempty"`
+
+	source workloadSource
+}
+
+// RecoveryPreflightRequest is a recovery to check before creating it
+type RecoveryPreflightRequest struct {
+	BackupID        string `json:"backupId" binding:"required"`
+	TargetCluster   string `json:"targetCluster" binding:"required,cluster"`
+	TargetName      string `json:"targetName,omitempty" binding:"omitempty,dns1123subdomain"`
+	TargetNamespace string `json:"targetNamespace,omitempty" binding:"omitempty,dns1123label"`
+}
+
+// recoveryRename returns where a recovery relocates the backed up workload
+func recoveryRename(backup BackupConfiguration, targetName, targetNamespace string) migration.Rename {
+	rename := migration.Rename{
+		SourceName:      backup.ResourceName,
+		SourceNamespace: backup.Namespace,
+		TargetName:      backup.ResourceName,
+		TargetNamespace: backup.Namespace,
+	}
+	if targetName != "" {
+		rename.TargetName = targetName
+	}
+	if targetNamespace != "" {
+		rename.TargetNamespace = targetNamespace
+	}
+	return rename
+}
+
+// validateRecoveryTarget checks that the target name and namespace are valid object names
+func validateRecoveryTarget(targetName, targetNamespace string) error {
+	if targetName != "" {
+		if errs := validation.IsDNS1123Subdomain(targetName); len(errs) > 0 {
+			return fmt.Errorf("invalid target name %q: %s", targetName, strings.Join(errs, ", "))
+		}
+	}
+	if targetNamespace != "" {
+		if errs := validation.IsDNS1123Label(targetNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid target namespace %q: %s", targetNamespace, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// sourceWorkloadPodSpec returns the pod spec of the backed up pod or the pod template of the backed up statefulset
+func sourceWorkloadPodSpec(ctx context.Context, memberClient kubeclient.Interface, backup BackupConfiguration) (*corev1.PodSpec, map[string]string, string, error) {
+	if strings.EqualFold(backup.ResourceType, "statefulset") {
+		sts, err := memberClient.AppsV1().StatefulSets(backup.Namespace).Get(ctx, backup.ResourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return &sts.Spec.Template.Spec, sts.Spec.Template.Labels, sts.Spec.ServiceName, nil
+	}
+	pod, err := memberClient.CoreV1().Pods(backup.Namespace).Get(ctx, backup.ResourceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return &pod.Spec, pod.Labels, pod.Spec.Subdomain, nil
+}
+
+// workloadSource resolves the backed up workload and the objects it depends on
+type workloadSource interface {
+	// podSpec returns the pod spec of the workload, the labels of its pods and their subdomain or governing service
+	podSpec(ctx context.Context) (*corev1.PodSpec, map[string]string, string, error)
+	// claims returns the claims mounted by the pods of the workload
+	claims(ctx context.Context) ([]string, error)
+	// services returns the services selecting the pods of the workload
+	services(ctx context.Context, podLabels map[string]string, governing string) ([]string, error)
+	// dependency returns a ConfigMap, Secret, Service or claim, nil when it does not exist
+	dependency(ctx context.Context, kind, name string) (metav1.Object, error)
+}
+
+// clusterSource reads the backed up workload from its source cluster
+type clusterSource struct {
+	client kubeclient.Interface
+	backup BackupConfiguration
+}
+
+func (s clusterSource) podSpec(ctx context.Context) (*corev1.PodSpec, map[string]string, string, error) {
+	return sourceWorkloadPodSpec(ctx, s.client, s.backup)
+}
+
+func (s clusterSource) claims(ctx context.Context) ([]string, error) {
+	return workloadClaims(ctx, s.client, s.backup)
+}
+
+func (s clusterSource) services(ctx context.Context, podLabels map[string]string, governing string) ([]string, error) {
+	return workloadServices(ctx, s.client, s.backup.Namespace, podLabels, governing)
+}
+
+func (s clusterSource) dependency(ctx context.Context, kind, name string) (metav1.Object, error) {
+	return getDependency(ctx, s.client, kind, s.backup.Namespace, name)
+}
+
+// manifestSource reads the backed up workload from the manifests exported with its checkpoints
+type manifestSource struct {
+	manifests *migration.WorkloadManifests
+}
+
+func (s manifestSource) podSpec(context.Context) (*corev1.PodSpec, map[string]string, string, error) {
+	spec, podLabels, governing := s.manifests.PodSpec()
+	if spec == nil {
+		return nil, nil, "", fmt.Errorf("the exported manifests have no workload")
+	}
+	return spec, podLabels, governing, nil
+}
+
+func (s manifestSource) claims(context.Context) ([]string, error) {
+	return s.manifests.Names(migration.KindPersistentVolumeClaim), nil
+}
+
+func (s manifestSource) services(context.Context, map[string]string, string) ([]string, error) {
+	// Only the services of the workload were exported
+	return s.manifests.Names(migration.KindService), nil
+}
+
+func (s manifestSource) dependency(_ context.Context, kind, name string) (metav1.Object, error) {
+	return s.manifests.Object(kind, name), nil
+}
+
+// recoverySource returns where a recovery reads the backed up workload from: the latest manifests exported
+// with its checkpoints, which stay available when the source cluster is gone, or else the source cluster
+func recoverySource(ctx context.Context, backup BackupConfiguration) (workloadSource, string, error) {
+	manifests, err := latestWorkloadManifests(ctx, backup.ID)
+	if err != nil {
+		klog.V(4).InfoS("Failed to read exported manifests", "backupID", backup.ID, "error", err)
+	} else if manifests != nil {
+		return manifestSource{manifests: manifests}, manifests.CapturedAt, nil
+	}
+	sourceClient := client.InClusterClientForMemberCluster(backup.Cluster)
+	if sourceClient == nil {
+		return nil, "", fmt.Errorf("failed to get client for cluster %s", backup.Cluster)
+	}
+	return clusterSource{client: sourceClient, backup: backup}, "", nil
+}
+
+// workloadDependencies returns the ConfigMaps, Secrets, claims and services of the backed up workload
+func workloadDependencies(ctx context.Context, source workloadSource, podSpec *corev1.PodSpec, podLabels map[string]string, governing string) ([]migration.Dependency, error) {
+	var dependencies []migration.Dependency
+	for _, dependency := range migration.PodDependencies(podSpec) {
+		// Claims are taken from the pods, which also covers the claims of statefulset volume templates
+		if dependency.Kind != migration.KindPersistentVolumeClaim {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	claims, err := source.claims(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the claims of the workload: %v", err)
+	}
+	for _, claim := range claims {
+		dependencies = append(dependencies, migration.Dependency{Kind: migration.KindPersistentVolumeClaim, Name: claim})
+	}
+	services, err := source.services(ctx, podLabels, governing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the services of the workload: %v", err)
+	}
+	for _, service := range services {
+		dependencies = append(dependencies, migration.Dependency{Kind: migration.KindService, Name: service})
+	}
+	return dependencies, nil
+}
+
+// targetWorkloadExists reports whether the recovered workload already exists on the target cluster
+func targetWorkloadExists(ctx context.Context, memberClient kubeclient.Interface, backup BackupConfiguration, rename migration.Rename) (bool, error) {
+	var err error
+	if strings.EqualFold(backup.ResourceType, "statefulset") {
+		_, err = memberClient.AppsV1().StatefulSets(rename.TargetNamespace).Get(ctx, rename.TargetName, metav1.GetOptions{})
+	} else {
+		_, err = memberClient.CoreV1().Pods(rename.TargetNamespace).Get(ctx, rename.TargetName, metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// workloadServices returns the services of the source namespace that select the workload pods,
+// including the governing service of a statefulset
+func workloadServices(ctx context.Context, memberClient kubeclient.Interface, namespace string, podLabels map[string]string, governing string) ([]string, error) {
+	list, err := memberClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, service := range list.Items {
+		if service.Name == governing ||
+			(len(service.Spec.Selector) > 0 && labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(podLabels))) {
+			services = append(services, service.Name)
+		}
+	}
+	return services, nil
+}
+
+// getDependency gets a ConfigMap, Secret, Service or claim, returning nil when it does not exist
+func getDependency(ctx context.Context, memberClient kubeclient.Interface, kind, namespace, name string) (metav1.Object, error) {
+	var obj metav1.Object
+	var err error
+	switch kind {
+	case migration.KindConfigMap:
+		obj, err = memberClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	case migration.KindSecret:
+		obj, err = memberClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	case migration.KindService:
+		obj, err = memberClient.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	case migration.KindPersistentVolumeClaim:
+		obj, err = memberClient.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("unsupported dependency kind %s", kind)
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// recoveryTrustConflicts checks what a recovery needs regardless of the source cluster: the checkpoint to
+// restore is trusted and its encryption key is available on the target cluster
+func recoveryTrustConflicts(ctx context.Context, backup BackupConfiguration, targetCluster string) []migration.Conflict {
+	var conflicts []migration.Conflict
+	if conflict := verifyRecoveryCheckpoint(ctx, backup); conflict != nil {
+		conflicts = append(conflicts, *conflict)
+	}
+	if conflict := checkRecoveryEncryption(ctx, backup, targetCluster); conflict != nil {
+		conflicts = append(conflicts, *conflict)
+	}
+	return conflicts
+}
+
+// buildRecoveryPlan resolves the dependencies of the backed up workload from its exported manifests, or on
+// the source cluster without them, and checks where they go on the target cluster. Objects named after the
+// workload follow its new name; an object that already exists under its target name is a conflict, unless it
+// keeps its name and is shared.
+func buildRecoveryPlan(ctx context.Context, backup BackupConfiguration, targetCluster string, rename migration.Rename) (*RecoveryPlan, error) {
+	targetClient := client.InClusterClientForMemberCluster(targetCluster)
+	if targetClient == nil {
+		return nil, fmt.Errorf("failed to get client for cluster %s", targetCluster)
+	}
+	source, capturedAt, err := recoverySource(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &RecoveryPlan{
+		SourceCluster:   backup.Cluster,
+		SourceName:      rename.SourceName,
+		SourceNamespace: rename.SourceNamespace,
+		TargetCluster:   targetCluster,
+		TargetName:      rename.TargetName,
+		TargetNamespace: rename.TargetNamespace,
+		Resources:       []RecoveryResource{},
+		Conflicts:       []migration.Conflict{},
+		Source:          RecoverySourceCluster,
+		source:          source,
+	}
+	if capturedAt != "" {
+		plan.Source = RecoverySourceManifests
+		plan.ManifestsCapturedAt = capturedAt
+	}
+
+	if _, err := targetClient.CoreV1().Namespaces().Get(ctx, rename.TargetNamespace, metav1.GetOptions{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get namespace %s on cluster %s: %v", rename.TargetNamespace, targetCluster, err)
+		}
+		plan.CreateNamespace = true
+	}
+	plan.Conflicts = append(plan.Conflicts, recoveryTrustConflicts(ctx, backup, targetCluster)...)
+	if !plan.CreateNamespace {
+		exists, err := targetWorkloadExists(ctx, targetClient, backup, rename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s %s on cluster %s: %v", backup.ResourceType, rename.TargetName, targetCluster, err)
+		}
+		if exists {
+			plan.Conflicts = append(plan.Conflicts, migration.Conflict{
+				Kind:      backup.ResourceType,
+				Namespace: rename.TargetNamespace,
+				Name:      rename.TargetName,
+				Reason:    "already exists on the target cluster",
+			})
+		}
+	}
+
+	podSpec, podLabels, governing, err := source.podSpec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s of cluster %s: %v", backup.ResourceType, backup.Namespace, backup.ResourceName, backup.Cluster, err)
+	}
+	dependencies, err := workloadDependencies(ctx, source, podSpec, podLabels, governing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the dependencies of %s %s: %v", backup.ResourceType, backup.ResourceName, err)
+	}
+
+	inPlace := targetCluster == backup.Cluster && rename.TargetNamespace == rename.SourceNamespace
+	for _, dependency := range dependencies {
+		resource := RecoveryResource{
+			Kind:       dependency.Kind,
+			SourceName: dependency.Name,
+			TargetName: rename.Name(dependency.Name),
+		}
+		if inPlace && resource.TargetName == resource.SourceName {
+			resource.Action = RecoveryActionReuse
+			plan.Resources = append(plan.Resources, resource)
+			continue
+		}
+
+		var existing metav1.Object
+		if !plan.CreateNamespace {
+			existing, err = getDependency(ctx, targetClient, dependency.Kind, rename.TargetNamespace, resource.TargetName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s %s on cluster %s: %v", dependency.Kind, resource.TargetName, targetCluster, err)
+			}
+		}
+		switch {
+		case existing != nil && (dependency.Kind == migration.KindPersistentVolumeClaim || resource.TargetName != resource.SourceName):
+			// Claims hold the data of the workload and renamed objects belong to it, so neither is shared
+			plan.Conflicts = append(plan.Conflicts, migration.Conflict{
+				Kind:      dependency.Kind,
+				Namespace: rename.TargetNamespace,
+				Name:      resource.TargetName,
+				Reason:    "already exists on the target cluster",
+			})
+			resource.Action = RecoveryActionReuse
+		case existing != nil:
+			resource.Action = RecoveryActionReuse
+		case dependency.Kind == migration.KindPersistentVolumeClaim && backup.VolumeSnapshots != nil:
+			resource.Action = RecoveryActionRestore
+		default:
+			existing, err := source.dependency(ctx, dependency.Kind, dependency.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s %s of cluster %s: %v", dependency.Kind, dependency.Name, backup.Cluster, err)
+			}
+			resource.Action = RecoveryActionCreate
+			if existing == nil {
+				resource.Action = RecoveryActionSkip
+			}
+		}
+		plan.Resources = append(plan.Resources, resource)
+	}
+	return plan, nil
+}
+
+// Renames returns the dependencies the plan gives another name
+func (p *RecoveryPlan) Renames() []RecoveryRename {
+	var renames []RecoveryRename
+	for _, resource := range p.Resources {
+		if resource.TargetName != resource.SourceName {
+			renames = append(renames, RecoveryRename{Kind: resource.Kind, Name: resource.SourceName, TargetName: resource.TargetName})
+		}
+	}
+	return renames
+}
+
+// conflictError summarizes the conflicts of a plan, or returns nil without conflicts
+func (p *RecoveryPlan) conflictError() error {
+	if len(p.Conflicts) == 0 {
+		return nil
+	}
+	conflicts := make([]string, 0, len(p.Conflicts))
+	for _, conflict := range p.Conflicts {
+		name := conflict.Name
+		if conflict.Namespace != "" {
+			name = conflict.Namespace + "/" + name
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s %s %s", conflict.Kind, name, conflict.Reason))
+	}
+	return fmt.Errorf("recovery conflicts on cluster %s: %s", p.TargetCluster, strings.Join(conflicts, "; "))
+}
+
+// relocateDependency copies a dependency from the source of the plan under its target name and namespace
+func relocateDependency(ctx context.Context, targetClient kubeclient.Interface, plan *RecoveryPlan, resource RecoveryResource) error {
+	source, err := plan.source.dependency(ctx, resource.Kind, resource.SourceName)
+	if err != nil || source == nil {
+		return err
+	}
+	meta := metav1.ObjectMeta{
+		Name:        resource.TargetName,
+		Namespace:   plan.TargetNamespace,
+		Labels:      source.GetLabels(),
+		Annotations: source.GetAnnotations(),
+	}
+
+	switch obj := source.(type) {
+	case *corev1.ConfigMap:
+		_, err = targetClient.CoreV1().ConfigMaps(plan.TargetNamespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: meta, Data: obj.Data, BinaryData: obj.BinaryData,
+		}, metav1.CreateOptions{})
+	case *corev1.Secret:
+		if obj.Type == corev1.SecretTypeServiceAccountToken {
+			// Tokens are issued by the target cluster for its own service accounts
+			return nil
+		}
+		_, err = targetClient.CoreV1().Secrets(plan.TargetNamespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: meta, Data: obj.Data, Type: obj.Type,
+		}, metav1.CreateOptions{})
+	case *corev1.Service:
+		service := migration.TargetService(obj, plan.TargetNamespace)
+		service.ObjectMeta = meta
+		_, err = targetClient.CoreV1().Services(plan.TargetNamespace).Create(ctx, service, metav1.CreateOptions{})
+	case *corev1.PersistentVolumeClaim:
+		// Without volume snapshots the claim is recreated empty
+		spec := obj.Spec.DeepCopy()
+		spec.VolumeName = ""
+		spec.DataSource = nil
+		spec.DataSourceRef = nil
+		_, err = targetClient.CoreV1().PersistentVolumeClaims(plan.TargetNamespace).Create(ctx, &corev1.PersistentVolumeClaim{
+			ObjectMeta: meta, Spec: *spec,
+		}, metav1.CreateOptions{})
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s %s on cluster %s: %v", resource.Kind, resource.TargetName, plan.TargetCluster, err)
+	}
+	return nil
+}
+
+// relocateRecoveryDependencies creates the target namespace and copies the dependencies of the recovered
+// workload under their target names before it is restored, from the manifests exported with the checkpoints
+// when the backup has them. Otherwise the source cluster may be gone when recovering from a disaster, so the
+// recovery continues without the dependencies when they cannot be resolved.
+func relocateRecoveryDependencies(c *gin.Context, sm *unstructured.Unstructured) error {
+	rm, err := decodeRecoveryMigration(sm)
+	if err != nil {
+		return err
+	}
+	backup, err := getBackupByID(rm.Spec.BackupID)
+	if err != nil {
+		return fmt.Errorf("failed to get backup %s: %v", rm.Spec.BackupID, err)
+	}
+
+	plan, err := buildRecoveryPlan(c, backup, rm.Spec.TargetCluster, recoveryRename(backup, rm.Spec.TargetName, rm.Spec.TargetNamespace))
+	if err != nil {
+		klog.InfoS("Recovering without relocating dependencies", "recovery", sm.GetName(), "reason", err.Error())
+		return nil
+	}
+	targetClient := client.InClusterClientForMemberCluster(plan.TargetCluster)
+
+	if plan.CreateNamespace {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: plan.TargetNamespace}}
+		if _, err := targetClient.CoreV1().Namespaces().Create(c, namespace, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create namespace %s on cluster %s: %v", plan.TargetNamespace, plan.TargetCluster, err)
+		}
+	}
+	for _, resource := range plan.Resources {
+		if resource.Action != RecoveryActionCreate {
+			continue
+		}
+		if err := relocateDependency(c, targetClient, plan, resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRecoveryRenames records the renamed dependencies on the recovery StatefulMigration, so the restored
+// workload references them by their new names
+func setRecoveryRenames(sm *unstructured.Unstructured, renames []RecoveryRename) error {
+	if len(renames) == 0 {
+		return nil
+	}
+	items := make([]interface{}, 0, len(renames))
+	for _, rename := range renames {
+		items = append(items, map[string]interface{}{
+			"kind":       rename.Kind,
+			"name":       rename.Name,
+			"targetName": rename.TargetName,
+		})
+	}
+	return unstructured.SetNestedSlice(sm.Object, items, "spec", "renames")
+}
+
+// handleRecoveryPreflight reports where a recovery would put the workload and its dependencies
+// and the objects it would collide with on the target cluster
+func handleRecoveryPreflight(c *gin.Context) {
+	var req RecoveryPreflightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind recovery preflight request")
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := validateRecoveryTarget(req.TargetName, req.TargetNamespace); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	backup, err := getBackupByID(req.BackupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup configuration", "backupID", req.BackupID)
+		common.Fail(c, err)
+		return
+	}
+	for _, clusterName := range []string{backup.Cluster, req.TargetCluster} {
+		if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	plan, err := buildRecoveryPlan(c, backup, req.TargetCluster, recoveryRename(backup, req.TargetName, req.TargetNamespace))
+	if err != nil {
+		klog.ErrorS(err, "Failed to build recovery plan", "backupID", req.BackupID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, plan)
+}
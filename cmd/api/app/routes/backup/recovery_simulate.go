@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// RecoverySimulation is what a recovery of a backup would do on a target cluster: the objects and namespaces
+// it would create, the checkpoint the target cluster would pull and the conflicts that would reject it
+type RecoverySimulation struct {
+	RecoveryPlan
+	// Feasible is true when the recovery would be accepted on the target cluster
+	Feasible bool `json:"feasible"`
+	// Namespaces are the namespaces the recovery would create
+	Namespaces []string `json:"namespaces"`
+	// Creates are the workload and the dependencies the recovery would create
+	Creates []RecoveryResource `json:"creates"`
+	// ImageRepository is the repository the target cluster would pull the checkpoint from,
+	// after the image rewrite rules of the cluster
+	ImageRepository string `json:"imageRepository,omitempty"`
+	// Checkpoint is the latest checkpoint of the backup, the one the recovery restores
+	Checkpoint string `json:"checkpoint,omitempty"`
+	// EstimatedPullBytes is the size of the checkpoint in the registry, unset when it cannot be read
+	EstimatedPullBytes *int64 `json:"estimatedPullBytes,omitempty"`
+	// Warnings are the parts of the simulation that could not be checked
+	Warnings []string `json:"warnings"`
+}
+
+// latestCheckpoint returns the latest checkpoint of a backup, nil when the backup has none yet.
+// Checkpoints kept on a volume are not listed.
+func latestCheckpoint(ctx context.Context, backup BackupConfiguration) (*checkpointArtifact, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, err
+	}
+	sm, err := service.Get(ctx, backup.ID)
+	if err != nil {
+		return nil, err
+	}
+	if storageType, _, _ := unstructured.NestedString(sm.Object, "spec", "storage", "type"); storageType == StorageTypePVC {
+		return nil, fmt.Errorf("checkpoints on pvc storage cannot be listed")
+	}
+	store, err := checkpointStoreForBackup(sm)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := store.List(ctx)
+	if err != nil || len(artifacts) == 0 {
+		return nil, err
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].CreatedAt.After(artifacts[j].CreatedAt) })
+	return &artifacts[0], nil
+}
+
+// simulateRecovery reports what a recovery would do on the target cluster without creating anything.
+// Like the creation of a recovery, it falls back to the checks of the checkpoint when the dependencies
+// cannot be resolved, e.g. because the source cluster is gone.
+func simulateRecovery(ctx context.Context, backup BackupConfiguration, targetCluster string, rename migration.Rename) *RecoverySimulation {
+	simulation := &RecoverySimulation{Namespaces: []string{}, Creates: []RecoveryResource{}, Warnings: []string{}}
+	plan, err := buildRecoveryPlan(ctx, backup, targetCluster, rename)
+	if err != nil {
+		plan = &RecoveryPlan{
+			SourceCluster:   backup.Cluster,
+			SourceName:      rename.SourceName,
+			SourceNamespace: rename.SourceNamespace,
+			TargetCluster:   targetCluster,
+			TargetName:      rename.TargetName,
+			TargetNamespace: rename.TargetNamespace,
+			Resources:       []RecoveryResource{},
+			Conflicts:       recoveryTrustConflicts(ctx, backup, targetCluster),
+		}
+		simulation.Warnings = append(simulation.Warnings, fmt.Sprintf("dependencies cannot be resolved: %v", err))
+	}
+	if plan.Conflicts == nil {
+		plan.Conflicts = []migration.Conflict{}
+	}
+	simulation.RecoveryPlan = *plan
+	simulation.Feasible = len(plan.Conflicts) == 0
+
+	if plan.CreateNamespace {
+		simulation.Namespaces = append(simulation.Namespaces, rename.TargetNamespace)
+	}
+	simulation.Creates = append(simulation.Creates, RecoveryResource{
+		Kind:       backup.ResourceType,
+		SourceName: rename.SourceName,
+		TargetName: rename.TargetName,
+		Action:     RecoveryActionRestore,
+	})
+	for _, resource := range plan.Resources {
+		if resource.Action == RecoveryActionCreate || resource.Action == RecoveryActionRestore {
+			simulation.Creates = append(simulation.Creates, resource)
+		}
+	}
+
+	if backup.Registry.Registry != "" {
+		repository := fmt.Sprintf("%s/%s", backup.Registry.Registry, backup.Repository)
+		simulation.ImageRepository = migration.RewriteImage(repository, migration.ClusterImageRewrites(targetCluster))
+	}
+	checkpoint, err := latestCheckpoint(ctx, backup)
+	switch {
+	case err != nil:
+		simulation.Warnings = append(simulation.Warnings, fmt.Sprintf("checkpoint size cannot be estimated: %v", err))
+	case checkpoint == nil:
+		simulation.Warnings = append(simulation.Warnings, "backup has no checkpoint yet")
+	default:
+		simulation.Checkpoint = checkpoint.Name
+		simulation.EstimatedPullBytes = &checkpoint.Size
+	}
+	return simulation
+}
+
+// handleSimulateRecovery reports what a recovery of a backup would do on a target cluster without creating
+// any CR, so that candidate target clusters can be compared before recovering
+func handleSimulateRecovery(c *gin.Context) {
+	var req RecoveryPreflightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind recovery simulation request")
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := validateRecoveryTarget(req.TargetName, req.TargetNamespace); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	backup, err := getBackupByID(req.BackupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup configuration", "backupID", req.BackupID)
+		common.Fail(c, err)
+		return
+	}
+	for _, clusterName := range []string{backup.Cluster, req.TargetCluster} {
+		if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+			common.Fail(c, err)
+			return
+		}
+	}
+	common.Success(c, simulateRecovery(c, backup, req.TargetCluster, recoveryRename(backup, req.TargetName, req.TargetNamespace)))
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +33,8 @@ import (
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/externalsecret"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
 )
 
 // RegistryCredentials represents registry authentication information
@@ -48,24 +49,30 @@ type RegistryCredentials struct {
 	UpdatedAt       string `json:"updatedAt"`
 	SecretName      string `json:"secretName"`
 	SecretNamespace string `json:"secretNamespace"`
+	// ExternalSecret names the ExternalSecret filling the password from an external store
+	ExternalSecret string `json:"externalSecret,omitempty"`
 }
 
 // CreateRegistryRequest represents the request to create a new registry
 type CreateRegistryRequest struct {
 	Name        string `json:"name" binding:"required"`
-	Registry    string `json:"registry" binding:"required"`
+	Registry    string `json:"registry" binding:"required,registryurl"`
 	Username    string `json:"username" binding:"required"`
-	Password    string `json:"password" binding:"required"`
+	Password    string `json:"password" binding:"required_without=PasswordRef"`
 	Description string `json:"description"`
+	// PasswordRef reads the password from the external store instead
+	PasswordRef *externalsecret.RemoteRef `json:"passwordRef,omitempty"`
 }
 
 // UpdateRegistryRequest represents the request to update a registry
 type UpdateRegistryRequest struct {
 	Name        string `json:"name"`
-	Registry    string `json:"registry"`
+	Registry    string `json:"registry" binding:"omitempty,registryurl"`
 	Username    string `json:"username"`
 	Password    string `json:"password"`
 	Description string `json:"description"`
+	// PasswordRef reads the password from the external store instead
+	PasswordRef *externalsecret.RemoteRef `json:"passwordRef,omitempty"`
 }
 
 const (
@@ -182,19 +189,32 @@ func handleCreateRegistry(c *gin.Context) {
 	var req CreateRegistryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind registry request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
-	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	registry, err := createRegistry(c, req)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get Karmada dynamic client")
 		common.Fail(c, err)
 		return
 	}
+	common.Success(c, registry)
+}
 
+// createRegistry stores a registry in a Karmada secret propagated to the member clusters
+func createRegistry(ctx context.Context, req CreateRegistryRequest) (RegistryCredentials, error) {
 	// Generate unique ID for the registry
-	registryID := generateRegistryID(req.Name)
+	return createRegistryWithID(ctx, generateRegistryID(req.Name), req, nil)
+}
+
+// createRegistryWithID stores a registry under an ID, with additional labels on its secret
+func createRegistryWithID(ctx context.Context, registryID string, req CreateRegistryRequest, labels map[string]string) (RegistryCredentials, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		return RegistryCredentials{}, err
+	}
+
 	secretName := fmt.Sprintf("%s-%s", registrySecretPrefix, registryID)
 
 	// Create secret data
@@ -223,13 +243,21 @@ func handleCreateRegistry(c *gin.Context) {
 		Data: secretData,
 		Type: corev1.SecretTypeOpaque,
 	}
+	for key, value := range labels {
+		secret.Labels[key] = value
+	}
+	if req.PasswordRef != nil {
+		if err := applyBackupExternalSecret(ctx, secret, map[string]externalsecret.RemoteRef{"password": *req.PasswordRef}); err != nil {
+			klog.ErrorS(err, "Failed to create ExternalSecret for registry", "secretName", secretName)
+			return RegistryCredentials{}, err
+		}
+	}
 
 	// Convert secret to unstructured and create in Karmada
 	secretUnstructured, err := convertSecretToUnstructured(secret)
 	if err != nil {
 		klog.ErrorS(err, "Failed to convert secret to unstructured")
-		common.Fail(c, err)
-		return
+		return RegistryCredentials{}, err
 	}
 
 	secretGVR := schema.GroupVersionResource{
@@ -238,11 +266,13 @@ func handleCreateRegistry(c *gin.Context) {
 		Resource: "secrets",
 	}
 
-	_, err = karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Create(context.TODO(), secretUnstructured, metav1.CreateOptions{})
+	_, err = karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Create(ctx, secretUnstructured, metav1.CreateOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to create registry secret in Karmada")
-		common.Fail(c, err)
-		return
+		if req.PasswordRef != nil {
+			deleteBackupExternalSecret(ctx, secretName)
+		}
+		return RegistryCredentials{}, err
 	}
 
 	// Propagate secret to member clusters using PropagationPolicy
@@ -251,8 +281,7 @@ func handleCreateRegistry(c *gin.Context) {
 		// Continue even if propagation fails - we can retry later
 	}
 
-	registry := secretToRegistry(secret)
-	common.Success(c, registry)
+	return secretToRegistry(secret), nil
 }
 
 // handleUpdateRegistry updates an existing registry configuration
@@ -261,15 +290,24 @@ func handleUpdateRegistry(c *gin.Context) {
 	var req UpdateRegistryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind registry update request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	registry, err := updateRegistry(c, registryID, req)
+	if err != nil {
 		common.Fail(c, err)
 		return
 	}
+	common.Success(c, registry)
+}
 
+// updateRegistry sets the non-empty fields of the request on a registry
+func updateRegistry(ctx context.Context, registryID string, req UpdateRegistryRequest) (RegistryCredentials, error) {
 	karmadaDynamicClient, err := getKarmadaDynamicClient()
 	if err != nil {
 		klog.ErrorS(err, "Failed to get Karmada dynamic client")
-		common.Fail(c, err)
-		return
+		return RegistryCredentials{}, err
 	}
 
 	secretName := fmt.Sprintf("%s-%s", registrySecretPrefix, registryID)
@@ -280,19 +318,17 @@ func handleUpdateRegistry(c *gin.Context) {
 	}
 
 	// Get existing secret from Karmada
-	secretUnstructured, err := karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	secretUnstructured, err := karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Get(ctx, secretName, metav1.GetOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to get registry secret for update from Karmada", "registryID", registryID)
-		common.Fail(c, err)
-		return
+		return RegistryCredentials{}, err
 	}
 
 	secret := &corev1.Secret{}
 	err = convertUnstructuredToTyped(secretUnstructured, secret)
 	if err != nil {
 		klog.ErrorS(err, "Failed to convert secret", "secretName", secretName)
-		common.Fail(c, err)
-		return
+		return RegistryCredentials{}, err
 	}
 
 	// Update secret data
@@ -306,7 +342,16 @@ func handleUpdateRegistry(c *gin.Context) {
 	if req.Username != "" {
 		secret.Data["username"] = []byte(req.Username)
 	}
-	if req.Password != "" {
+	if req.PasswordRef != nil {
+		if err := applyBackupExternalSecret(ctx, secret, map[string]externalsecret.RemoteRef{"password": *req.PasswordRef}); err != nil {
+			klog.ErrorS(err, "Failed to apply ExternalSecret for registry", "registryID", registryID)
+			return RegistryCredentials{}, err
+		}
+	} else if req.Password != "" {
+		if err := removeBackupExternalSecret(ctx, secret); err != nil {
+			klog.ErrorS(err, "Failed to remove ExternalSecret of registry", "registryID", registryID)
+			return RegistryCredentials{}, err
+		}
 		secret.Data["password"] = []byte(req.Password)
 	}
 	if req.Description != "" {
@@ -319,29 +364,34 @@ func handleUpdateRegistry(c *gin.Context) {
 	updatedSecretUnstructured, err := convertSecretToUnstructured(secret)
 	if err != nil {
 		klog.ErrorS(err, "Failed to convert updated secret to unstructured")
-		common.Fail(c, err)
-		return
+		return RegistryCredentials{}, err
 	}
 
-	_, err = karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Update(context.TODO(), updatedSecretUnstructured, metav1.UpdateOptions{})
+	_, err = karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Update(ctx, updatedSecretUnstructured, metav1.UpdateOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to update registry secret in Karmada")
-		common.Fail(c, err)
-		return
+		return RegistryCredentials{}, err
 	}
 
-	registry := secretToRegistry(secret)
-	common.Success(c, registry)
+	return secretToRegistry(secret), nil
 }
 
 // handleDeleteRegistry deletes a registry configuration
 func handleDeleteRegistry(c *gin.Context) {
-	registryID := c.Param("id")
+	if err := deleteRegistry(c, c.Param("id")); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	common.Success(c, ActionResult{Message: "Registry deleted successfully"})
+}
+
+// deleteRegistry deletes the secret of a registry and its PropagationPolicy
+func deleteRegistry(ctx context.Context, registryID string) error {
 	karmadaDynamicClient, err := getKarmadaDynamicClient()
 	if err != nil {
 		klog.ErrorS(err, "Failed to get Karmada dynamic client")
-		common.Fail(c, err)
-		return
+		return err
 	}
 
 	secretName := fmt.Sprintf("%s-%s", registrySecretPrefix, registryID)
@@ -352,26 +402,23 @@ func handleDeleteRegistry(c *gin.Context) {
 	}
 
 	// Delete secret from Karmada
-	err = karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{})
+	err = karmadaDynamicClient.Resource(secretGVR).Namespace(registryNamespace).Delete(ctx, secretName, metav1.DeleteOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to delete registry secret from Karmada", "registryID", registryID)
-		common.Fail(c, err)
-		return
+		return err
 	}
 
+	deleteBackupExternalSecret(ctx, secretName)
+
 	// Also delete the PropagationPolicy
 	karmadaClient := client.InClusterKarmadaClient()
 	propagationPolicyName := fmt.Sprintf("backup-registry-%s", registryID)
-	err = karmadaClient.PolicyV1alpha1().PropagationPolicies(registryNamespace).Delete(context.TODO(), propagationPolicyName, metav1.DeleteOptions{})
+	err = karmadaClient.PolicyV1alpha1().PropagationPolicies(registryNamespace).Delete(ctx, propagationPolicyName, metav1.DeleteOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to delete PropagationPolicy for registry", "registryID", registryID)
 		// Continue even if PropagationPolicy deletion fails
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Registry deleted successfully",
-	})
+	return nil
 }
 
 // secretToRegistry converts a Kubernetes secret to a RegistryCredentials struct
@@ -387,6 +434,7 @@ func secretToRegistry(secret *corev1.Secret) RegistryCredentials {
 		UpdatedAt:       secret.Annotations["backup.dcnlab.com/updated-at"],
 		SecretName:      secret.Name,
 		SecretNamespace: secret.Namespace,
+		ExternalSecret:  externalsecret.Reference(secret),
 	}
 
 	if registry.CreatedAt == "" {
@@ -424,8 +472,9 @@ func propagateRegistrySecret(registryID, secretName, namespace string) error {
 			Name:      fmt.Sprintf("backup-registry-%s", registryID),
 			Namespace: namespace,
 			Labels: map[string]string{
-				"app":         "backup-registry",
-				"registry-id": registryID,
+				"app":               "backup-registry",
+				"registry-id":       registryID,
+				orphan.ManagedLabel: orphan.ManagedLabelValue,
 			},
 		},
 		Spec: policyv1alpha1.PropagationSpec{
@@ -435,6 +484,7 @@ func propagateRegistrySecret(registryID, secretName, namespace string) error {
 					Kind:       "Secret",
 					Name:       secretName,
 				},
+				externalSecretSelector(secretName),
 			},
 			Placement: policyv1alpha1.Placement{
 				ClusterAffinity: &policyv1alpha1.ClusterAffinity{
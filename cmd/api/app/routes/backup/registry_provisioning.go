@@ -0,0 +1,353 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/harbor"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
+	"github.com/karmada-io/dashboard/pkg/resource/project"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// provisionedLabel marks the registries provisioned in Harbor for a user or a project
+	provisionedLabel   = "registry-provisioned"
+	ownerKindLabel     = "registry-owner-kind"
+	ownerAnnotation    = "backup.dcnlab.com/registry-owner"
+	targetsAnnotation  = "backup.dcnlab.com/registry-namespaces"
+	pullSecretAppLabel = "registry-pull-secret"
+	// skipAutoPropagationLabel keeps Karmada from propagating the namespaces created for pull secrets to every cluster
+	skipAutoPropagationLabel = "namespace.karmada.io/skip-auto-propagation"
+)
+
+// ProvisionRegistryRequest is the request to provision the registry project of an existing user or project
+type ProvisionRegistryRequest struct {
+	OwnerKind string `json:"ownerKind" binding:"required,oneof=user project"`
+	// Owner is the name of the project, or the name of the Kubeflow Profile of the user
+	Owner string `json:"owner" binding:"required"`
+}
+
+// provisionedRegistryID returns the ID of the registry of a Harbor project
+func provisionedRegistryID(harborProject string) string {
+	return "harbor-" + harborProject
+}
+
+// registryTargets returns the namespaces that get the pull secret of an owner: the profile namespace of a
+// user in every cluster, and the namespaces of a project
+func registryTargets(ctx context.Context, params harbor.ProvisionParams) ([]harbor.Target, error) {
+	if params.OwnerKind == harbor.OwnerUser {
+		return []harbor.Target{{Namespace: params.Owner}}, nil
+	}
+	p, err := project.Get(ctx, client.InClusterClient(), params.Owner)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]harbor.Target, 0, len(p.Namespaces))
+	for _, namespace := range p.Namespaces {
+		targets = append(targets, harbor.Target{Cluster: namespace.Cluster, Namespace: namespace.Namespace})
+	}
+	return targets, nil
+}
+
+// harborClient returns a client of the Harbor instance of the runtime config, authenticated with the
+// administrator credentials of its Secret
+func harborClient(ctx context.Context) (*harbor.Client, error) {
+	settings, err := harbor.Settings()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Get(ctx, settings.CredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Harbor credentials: %v", err)
+	}
+	return harbor.NewClient(settings.URL, string(secret.Data["username"]), string(secret.Data["password"]), settings.InsecureSkipTLSVerify), nil
+}
+
+// runRegistryProvisioning creates the Harbor project of a user or a project and its robot account, stores
+// the robot credentials as a backup registry and propagates them as a pull secret to the namespaces of the
+// owner. The registry of an earlier attempt is reused, so its robot is only created once.
+func runRegistryProvisioning(ctx context.Context, run *jobs.Run) error {
+	var params harbor.ProvisionParams
+	if err := run.DecodeParams(&params); err != nil {
+		return err
+	}
+	if err := params.Validate(); err != nil {
+		return jobs.Permanent(err)
+	}
+	targets, err := registryTargets(ctx, params)
+	if apierrors.IsNotFound(err) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+
+	harborProject := harbor.ProjectName(params.OwnerKind, params.Owner)
+	registryID := provisionedRegistryID(harborProject)
+	secret, err := registrySecret(ctx, registryID)
+	if apierrors.IsNotFound(err) {
+		if secret, err = createProvisionedRegistry(ctx, params, harborProject); err != nil {
+			return err
+		}
+		if err := run.SaveState(ctx, nil, fmt.Sprintf("Created Harbor project %s", harborProject)); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if err := setRegistryTargets(ctx, secret, targets); err != nil {
+		return err
+	}
+	dockerConfig, err := harbor.DockerConfigJSON(string(secret.Data["registry"]), string(secret.Data["username"]), string(secret.Data["password"]))
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	for namespace, clusters := range targetClusters(targets) {
+		if err := propagatePullSecret(ctx, registryID, namespace, clusters, dockerConfig); err != nil {
+			return fmt.Errorf("namespace %s: %v", namespace, err)
+		}
+	}
+	klog.InfoS("Provisioned registry", "ownerKind", params.OwnerKind, "owner", params.Owner, "harborProject", harborProject, "namespaces", len(targets))
+	return nil
+}
+
+// registrySecret returns the Karmada secret of a registry with its credentials
+func registrySecret(ctx context.Context, registryID string) (*corev1.Secret, error) {
+	secretName := fmt.Sprintf("%s-%s", registrySecretPrefix, registryID)
+	return client.InClusterClientForKarmadaAPIServer().CoreV1().Secrets(registryNamespace).Get(ctx, secretName, metav1.GetOptions{})
+}
+
+// createProvisionedRegistry creates the Harbor project and robot of an owner and stores the robot as a registry
+func createProvisionedRegistry(ctx context.Context, params harbor.ProvisionParams, harborProject string) (*corev1.Secret, error) {
+	harborAPI, err := harborClient(ctx)
+	if errors.Is(err, harbor.ErrNotConfigured) {
+		return nil, jobs.Permanent(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	projectID, err := harborAPI.EnsureProject(ctx, harborProject)
+	if err != nil {
+		return nil, err
+	}
+	robot, err := harborAPI.CreateRobot(ctx, harborProject, projectID)
+	if err != nil {
+		return nil, err
+	}
+	req := CreateRegistryRequest{
+		Name:        harborProject,
+		Registry:    harborAPI.Host(),
+		Username:    robot.Name,
+		Password:    robot.Secret,
+		Description: fmt.Sprintf("Harbor project of %s %s", params.OwnerKind, params.Owner),
+	}
+	labels := map[string]string{
+		provisionedLabel: "true",
+		ownerKindLabel:   params.OwnerKind,
+	}
+	if _, err := createRegistryWithID(ctx, provisionedRegistryID(harborProject), req, labels); err != nil {
+		return nil, err
+	}
+	secret, err := registrySecret(ctx, provisionedRegistryID(harborProject))
+	if err != nil {
+		return nil, err
+	}
+	secret.Annotations[ownerAnnotation] = params.Owner
+	return client.InClusterClientForKarmadaAPIServer().CoreV1().Secrets(registryNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+}
+
+// setRegistryTargets records the namespaces a provisioned registry serves, which backups of these
+// namespaces use unless they name another registry
+func setRegistryTargets(ctx context.Context, secret *corev1.Secret, targets []harbor.Target) error {
+	data, err := json.Marshal(targets)
+	if err != nil {
+		return err
+	}
+	if secret.Annotations[targetsAnnotation] == string(data) {
+		return nil
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[targetsAnnotation] = string(data)
+	_, err = client.InClusterClientForKarmadaAPIServer().CoreV1().Secrets(registryNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// targetClusters groups the targets by namespace, a nil list of clusters means every member cluster
+func targetClusters(targets []harbor.Target) map[string][]string {
+	byNamespace := map[string][]string{}
+	allClusters := map[string]bool{}
+	for _, target := range targets {
+		if target.Cluster == "" {
+			allClusters[target.Namespace] = true
+			continue
+		}
+		byNamespace[target.Namespace] = append(byNamespace[target.Namespace], target.Cluster)
+	}
+	for namespace := range allClusters {
+		byNamespace[namespace] = nil
+	}
+	return byNamespace
+}
+
+// propagatePullSecret stores a pull secret in a namespace of Karmada and propagates it to the clusters,
+// or to every member cluster when none are given. The namespace is created in Karmada when it is missing,
+// without propagating it.
+func propagatePullSecret(ctx context.Context, name, namespace string, clusters []string, dockerConfig []byte) error {
+	karmadaKubeClient := client.InClusterClientForKarmadaAPIServer()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   namespace,
+		Labels: map[string]string{skipAutoPropagationLabel: "true"},
+	}}
+	if _, err := karmadaKubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": pullSecretAppLabel, orphan.ManagedLabel: orphan.ManagedLabelValue},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	}
+	secrets := karmadaKubeClient.CoreV1().Secrets(namespace)
+	if current, err := secrets.Get(ctx, name, metav1.GetOptions{}); err == nil {
+		current.Data = secret.Data
+		if _, err := secrets.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update pull secret: %v", err)
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	} else if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create pull secret: %v", err)
+	}
+
+	if len(clusters) == 0 {
+		memberClusters, err := getMemberClusters()
+		if err != nil {
+			return err
+		}
+		clusters = memberClusters
+	}
+	sort.Strings(clusters)
+	policy := &policyv1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": pullSecretAppLabel, orphan.ManagedLabel: orphan.ManagedLabelValue},
+		},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: []policyv1alpha1.ResourceSelector{
+				{APIVersion: "v1", Kind: "Secret", Name: name},
+			},
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: &policyv1alpha1.ClusterAffinity{ClusterNames: clusters},
+			},
+		},
+	}
+	policies := client.InClusterKarmadaClient().PolicyV1alpha1().PropagationPolicies(namespace)
+	current, err := policies.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = policies.Create(ctx, policy, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	current.Spec = policy.Spec
+	_, err = policies.Update(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+// provisionedRegistryFor returns the provisioned registry serving a namespace of a cluster and the Harbor
+// project its repositories belong to, false when there is none
+func provisionedRegistryFor(ctx context.Context, cluster, namespace string) (RegistryCredentials, bool, error) {
+	list, err := client.InClusterClientForKarmadaAPIServer().CoreV1().Secrets(registryNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=backup-registry,%s=true", provisionedLabel),
+	})
+	if err != nil {
+		return RegistryCredentials{}, false, err
+	}
+	for i := range list.Items {
+		var targets []harbor.Target
+		if err := json.Unmarshal([]byte(list.Items[i].Annotations[targetsAnnotation]), &targets); err != nil {
+			continue
+		}
+		if harbor.Serves(targets, cluster, namespace) {
+			return secretToRegistry(&list.Items[i]), true, nil
+		}
+	}
+	return RegistryCredentials{}, false, nil
+}
+
+// handleProvisionRegistry queues the provisioning of the registry project of an existing user or project,
+// which also updates the pull secrets of a project after its namespaces changed
+func handleProvisionRegistry(c *gin.Context) {
+	var req ProvisionRegistryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	job, err := harbor.EnqueueProvisioning(c, harbor.ProvisionParams{OwnerKind: req.OwnerKind, Owner: req.Owner}, utilauth.GetAuthenticatedUser(c))
+	if errors.Is(err, harbor.ErrNotConfigured) {
+		common.FailWithStatus(c, err, http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to enqueue registry provisioning", "ownerKind", req.OwnerKind, "owner", req.Owner)
+		common.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, common.BaseResponse{
+		Code: http.StatusAccepted,
+		Msg:  "Registry provisioning started",
+		Data: gin.H{
+			"jobId":      job.ID,
+			"registryId": provisionedRegistryID(harbor.ProjectName(req.OwnerKind, req.Owner)),
+		},
+	})
+}
+
+func init() {
+	jobs.Register(harbor.ProvisionJobType, runRegistryProvisioning)
+
+	r := router.V1()
+	r.POST("/backup/registry/provision", router.EnsureMgmtAdminMiddleware(), handleProvisionRegistry)
+}
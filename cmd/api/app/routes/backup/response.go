@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import "github.com/karmada-io/dashboard/pkg/resource/migration"
+
+// ActionResult is the result of a change to a backup resource, like deleting it
+type ActionResult struct {
+	Message string `json:"message"`
+}
+
+// BackupExecutionResult is the result of running a backup configuration now
+type BackupExecutionResult struct {
+	Message         string               `json:"message"`
+	VolumeSnapshots []VolumeSnapshotInfo `json:"volumeSnapshots"`
+}
+
+// ConflictResult is the data of a change rejected because the CR kept changing concurrently
+type ConflictResult struct {
+	// ResourceVersion is the latest version of the CR
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// RecoveryActionResult is the result of executing, cancelling or deleting a recovery
+type RecoveryActionResult struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Phase   string `json:"phase,omitempty"`
+}
+
+// InstallResult is the result of starting an install, upgrade or uninstall of the migration controller
+type InstallResult struct {
+	Message  string   `json:"message"`
+	JobID    string   `json:"jobId"`
+	Clusters []string `json:"clusters"`
+	// Selector is the cluster label selector the controller is installed for, if any
+	Selector string `json:"selector,omitempty"`
+	// Blockers are the resources a forced uninstall overrides
+	Blockers []migration.UninstallBlocker `json:"blockers,omitempty"`
+}
+
+// ControllerStatusResult is the state of the migration controller of a cluster
+type ControllerStatusResult struct {
+	ClusterName string `json:"clusterName"`
+	Status      string `json:"status"`
+	Version     string `json:"version"`
+	CheckedAt   string `json:"checkedAt"`
+}
+
+// ControllerLogsResult holds the latest log lines of the migration controller of a cluster
+type ControllerLogsResult struct {
+	ClusterName string   `json:"clusterName"`
+	Logs        []string `json:"logs"`
+	RetrievedAt string   `json:"retrievedAt"`
+}
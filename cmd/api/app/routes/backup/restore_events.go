@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+const (
+	// restoreRetryCountAnnotation counts the retries requested from the dashboard
+	restoreRetryCountAnnotation = "migration.dcnlab.com/retry-count"
+	// restoreRetryRequestedAnnotation asks the operator to retry the restore in place
+	restoreRetryRequestedAnnotation = "migration.dcnlab.com/retry-requested-at"
+
+	// RestoreRetryRecreate deletes the CheckpointRestore and creates it again with the same spec
+	RestoreRetryRecreate = "recreate"
+	// RestoreRetryAnnotate keeps the CheckpointRestore and annotates it for the operator to pick up
+	RestoreRetryAnnotate = "annotate"
+)
+
+// RetryCheckpointRestoreRequest represents the request to retry a failed restore
+type RetryCheckpointRestoreRequest struct {
+	Mode string `json:"mode" binding:"omitempty,oneof=recreate annotate"`
+}
+
+// isRestoreFailed reports whether a CheckpointRestore phase is terminal and failed
+func isRestoreFailed(phase string) bool {
+	switch strings.ToLower(phase) {
+	case "failed", "error":
+		return true
+	}
+	return false
+}
+
+// getReferencedCheckpointBackup returns the CheckpointBackup referenced by the restore.
+// The backup lives in the source cluster when the reference names one, else next to the restore.
+func getReferencedCheckpointBackup(c *gin.Context, event CheckpointRestoreEvent) (*unstructured.Unstructured, error) {
+	name, _, _ := unstructured.NestedString(event.BackupRef, "name")
+	if name == "" {
+		return nil, nil
+	}
+	namespace, _, _ := unstructured.NestedString(event.BackupRef, "namespace")
+	if namespace == "" {
+		namespace = event.Namespace
+	}
+	clusterName, _, _ := unstructured.NestedString(event.BackupRef, "cluster")
+	if clusterName == "" {
+		clusterName = event.Cluster
+	}
+
+	return getMigrationObject(c, clusterName, checkpointBackupGVR, namespace, name)
+}
+
+// handleGetCheckpointRestoreEvent returns a CheckpointRestore with its conditions and referenced CheckpointBackup
+func handleGetCheckpointRestoreEvent(c *gin.Context) {
+	clusterName := c.Param("cluster")
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	restore, err := getMigrationObject(c, clusterName, checkpointRestoreGVR, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			common.FailWithStatus(c, fmt.Errorf("checkpoint restore %s/%s not found in cluster %s", namespace, name, clusterName), http.StatusNotFound)
+			return
+		}
+		klog.ErrorS(err, "Failed to get CheckpointRestore", "cluster", clusterName, "namespace", namespace, "name", name)
+		common.Fail(c, err)
+		return
+	}
+
+	event := convertCheckpointRestoreToEvent(restore, clusterName)
+
+	// A missing backup is reported rather than failing the request, it is often why the restore failed
+	var backupError string
+	checkpointBackup, err := getReferencedCheckpointBackup(c, event)
+	if err != nil {
+		klog.V(4).InfoS("Failed to get referenced CheckpointBackup", "cluster", clusterName, "restore", name, "error", err)
+		backupError = err.Error()
+		checkpointBackup = nil
+	}
+
+	var backupObject map[string]interface{}
+	if checkpointBackup != nil {
+		backupObject = checkpointBackup.Object
+	}
+	common.Success(c, gin.H{
+		"event":            event,
+		"resource":         restore.Object,
+		"checkpointBackup": backupObject,
+		"backupError":      backupError,
+		"retryable":        isRestoreFailed(event.Phase),
+	})
+}
+
+// handleRetryCheckpointRestore retries a failed CheckpointRestore, either by creating it again
+// or by annotating it so the operator restarts the restore
+func handleRetryCheckpointRestore(c *gin.Context) {
+	clusterName := c.Param("cluster")
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var req RetryCheckpointRestoreRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.ErrorS(err, "Failed to bind retry request")
+			common.FailWithBindError(c, err)
+			return
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = RestoreRetryRecreate
+	}
+
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create dynamic client for member cluster", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	restores := dynamicClient.Resource(checkpointRestoreGVR).Namespace(namespace)
+
+	restore, err := restores.Get(c, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			common.FailWithStatus(c, fmt.Errorf("checkpoint restore %s/%s not found in cluster %s", namespace, name, clusterName), http.StatusNotFound)
+			return
+		}
+		common.Fail(c, err)
+		return
+	}
+
+	phase, _, _ := unstructured.NestedString(restore.Object, "status", "phase")
+	if !isRestoreFailed(phase) {
+		common.FailWithStatus(c, fmt.Errorf("only failed restores can be retried, current phase is %q", phase), http.StatusConflict)
+		return
+	}
+
+	annotations := restore.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	retryCount, _ := strconv.Atoi(annotations[restoreRetryCountAnnotation])
+	annotations[restoreRetryCountAnnotation] = strconv.Itoa(retryCount + 1)
+	annotations[restoreRetryRequestedAnnotation] = time.Now().Format(time.RFC3339)
+
+	if req.Mode == RestoreRetryAnnotate {
+		restore.SetAnnotations(annotations)
+		if _, err := restores.Update(c, restore, metav1.UpdateOptions{}); err != nil {
+			klog.ErrorS(err, "Failed to annotate CheckpointRestore for retry", "cluster", clusterName, "name", name)
+			common.Fail(c, err)
+			return
+		}
+	} else {
+		// Only the spec, labels and annotations are carried over, the status starts fresh
+		retry := &unstructured.Unstructured{}
+		retry.SetGroupVersionKind(restore.GroupVersionKind())
+		retry.SetName(restore.GetName())
+		retry.SetNamespace(restore.GetNamespace())
+		retry.SetLabels(restore.GetLabels())
+		retry.SetAnnotations(annotations)
+		retry.Object["spec"] = restore.Object["spec"]
+
+		if err := restores.Delete(c, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete CheckpointRestore for retry", "cluster", clusterName, "name", name)
+			common.Fail(c, err)
+			return
+		}
+		if err := waitForRestoreDeletion(c, restores, name); err != nil {
+			common.Fail(c, err)
+			return
+		}
+		if _, err := restores.Create(c, retry, metav1.CreateOptions{}); err != nil {
+			klog.ErrorS(err, "Failed to recreate CheckpointRestore", "cluster", clusterName, "name", name)
+			common.Fail(c, fmt.Errorf("failed to recreate checkpoint restore: %v", err))
+			return
+		}
+	}
+
+	klog.InfoS("Retried CheckpointRestore", "cluster", clusterName, "namespace", namespace, "name", name, "mode", req.Mode)
+	common.Success(c, gin.H{
+		"message":    fmt.Sprintf("Retry of checkpoint restore %s requested", name),
+		"mode":       req.Mode,
+		"retryCount": retryCount + 1,
+	})
+}
+
+// waitForRestoreDeletion waits for finalizers to release the old CheckpointRestore before it is recreated
+func waitForRestoreDeletion(ctx context.Context, restores dynamic.ResourceInterface, name string) error {
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := restores.Get(ctx, name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("timed out waiting for checkpoint restore %s to be deleted", name)
+}
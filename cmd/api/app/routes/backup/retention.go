@@ -0,0 +1,391 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	retentionKeepLastAnnotation = "backup.dcnlab.com/retention-keep-last"
+	retentionMaxAgeAnnotation   = "backup.dcnlab.com/retention-max-age"
+	lastGCAnnotation            = "backup.dcnlab.com/last-gc"
+)
+
+// RetentionPolicy controls how many checkpoints of a backup are kept. It is only supported for registry and
+// s3 storage, checkpoints on pvc storage are not pruned.
+type RetentionPolicy struct {
+	KeepLast int    `json:"keepLast,omitempty"` // Number of most recent checkpoints to keep, 0 keeps all
+	MaxAge   string `json:"maxAge,omitempty"`   // Maximum checkpoint age, e.g. "72h" or "30d"
+}
+
+// GCResult reports the checkpoints removed for one backup configuration
+type GCResult struct {
+	BackupID       string               `json:"backupId"`
+	BackupName     string               `json:"backupName"`
+	Deleted        []checkpointArtifact `json:"deleted"`
+	Kept           int                  `json:"kept"`
+	ReclaimedBytes int64                `json:"reclaimedBytes"`
+	Errors         []string             `json:"errors,omitempty"`
+}
+
+// gcMutex prevents the background worker and on-demand runs from deleting concurrently
+var gcMutex sync.Mutex
+
+// parseRetentionAge parses a Go duration, additionally accepting a day suffix ("30d")
+func parseRetentionAge(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("invalid max age %q", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid max age %q", value)
+	}
+	return age, nil
+}
+
+// validateRetentionPolicy checks the retention settings of a backup request
+func validateRetentionPolicy(policy *RetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.KeepLast < 0 {
+		return fmt.Errorf("keepLast must not be negative")
+	}
+	if policy.MaxAge != "" {
+		if _, err := parseRetentionAge(policy.MaxAge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRetentionStorage rejects retention policies on storage whose checkpoints cannot be garbage collected.
+// Checkpoints on a PVC live inside the member cluster and are not reachable from the dashboard.
+func validateRetentionStorage(policy *RetentionPolicy, storageType string) error {
+	if policy == nil || (policy.KeepLast == 0 && policy.MaxAge == "") {
+		return nil
+	}
+	if storageType == StorageTypePVC {
+		return fmt.Errorf("retention policies are not supported for pvc storage: checkpoints on a pvc live inside " +
+			"the member cluster and cannot be pruned by the dashboard, delete old checkpoints from the volume " +
+			"manually or use registry or s3 storage to keep a limited number")
+	}
+	return nil
+}
+
+// setRetentionAnnotations stores the retention policy on the StatefulMigration
+func setRetentionAnnotations(sm *unstructured.Unstructured, policy *RetentionPolicy) {
+	if policy == nil {
+		return
+	}
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	delete(annotations, retentionKeepLastAnnotation)
+	delete(annotations, retentionMaxAgeAnnotation)
+	if policy.KeepLast > 0 {
+		annotations[retentionKeepLastAnnotation] = strconv.Itoa(policy.KeepLast)
+	}
+	if policy.MaxAge != "" {
+		annotations[retentionMaxAgeAnnotation] = policy.MaxAge
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// retentionFromAnnotations reads the retention policy of a StatefulMigration, nil if none is set
+func retentionFromAnnotations(sm *unstructured.Unstructured) *RetentionPolicy {
+	annotations := sm.GetAnnotations()
+	policy := &RetentionPolicy{MaxAge: annotations[retentionMaxAgeAnnotation]}
+	if keepLast, err := strconv.Atoi(annotations[retentionKeepLastAnnotation]); err == nil {
+		policy.KeepLast = keepLast
+	}
+	if policy.KeepLast == 0 && policy.MaxAge == "" {
+		return nil
+	}
+	return policy
+}
+
+// selectExpiredCheckpoints returns the checkpoints that fall outside the retention policy.
+// The newest checkpoint is always kept so the workload can still be restored.
+func selectExpiredCheckpoints(artifacts []checkpointArtifact, policy RetentionPolicy, now time.Time) []checkpointArtifact {
+	if len(artifacts) <= 1 {
+		return nil
+	}
+	sorted := make([]checkpointArtifact, len(artifacts))
+	copy(sorted, artifacts)
+	// Checkpoints created in the same second are ordered by name so the same one is kept on every run
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+		}
+		return sorted[i].Name > sorted[j].Name
+	})
+
+	var maxAge time.Duration
+	if policy.MaxAge != "" {
+		maxAge, _ = parseRetentionAge(policy.MaxAge)
+	}
+
+	var expired []checkpointArtifact
+	for i, artifact := range sorted[1:] {
+		index := i + 1
+		if policy.KeepLast > 0 && index >= policy.KeepLast {
+			expired = append(expired, artifact)
+			continue
+		}
+		if maxAge > 0 && !artifact.CreatedAt.IsZero() && now.Sub(artifact.CreatedAt) > maxAge {
+			expired = append(expired, artifact)
+		}
+	}
+	return expired
+}
+
+// readBackupSecretValue returns a raw value from a registry or storage backend secret
+func readBackupSecretValue(secretName, key string) (string, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+	secretUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{}
+	if err := convertUnstructuredToTyped(secretUnstructured, secret); err != nil {
+		return "", fmt.Errorf("failed to convert secret: %v", err)
+	}
+	return backupSecretValue(context.TODO(), secret, key)
+}
+
+// checkpointStoreForBackup builds the checkpoint store that holds the checkpoints of a StatefulMigration
+func checkpointStoreForBackup(sm *unstructured.Unstructured) (checkpointStore, error) {
+	if storageType, found, _ := unstructured.NestedString(sm.Object, "spec", "storage", "type"); found {
+		if storageType == StorageTypePVC {
+			return nil, fmt.Errorf("garbage collection is not supported for pvc storage")
+		}
+		backend, err := getStorageBackendByID(sm.GetAnnotations()[storageBackendAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage backend: %v", err)
+		}
+		secretAccessKey, err := readBackupSecretValue(backend.SecretName, "secretAccessKey")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read storage credentials: %v", err)
+		}
+		prefix, _, _ := unstructured.NestedString(sm.Object, "spec", "storage", "s3", "prefix")
+		return newS3CheckpointStore(backend, secretAccessKey, prefix)
+	}
+
+	secretName, found, _ := unstructured.NestedString(sm.Object, "spec", "registry", "secretRef", "name")
+	if !found {
+		return nil, fmt.Errorf("backup has no registry or storage backend")
+	}
+	registry, err := getRegistryByName(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registry: %v", err)
+	}
+	password, err := readBackupSecretValue(secretName, "password")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry credentials: %v", err)
+	}
+	repository, _, _ := unstructured.NestedString(sm.Object, "spec", "registry", "repository")
+	return newRegistryCheckpointStore(registry, password, repository), nil
+}
+
+// runBackupGC deletes the expired checkpoints of one backup configuration
+func runBackupGC(ctx context.Context, sm *unstructured.Unstructured) GCResult {
+	result := GCResult{
+		BackupID:   sm.GetLabels()["backup-id"],
+		BackupName: sm.GetName(),
+		Deleted:    []checkpointArtifact{},
+	}
+
+	policy := retentionFromAnnotations(sm)
+	if policy == nil {
+		return result
+	}
+
+	store, err := checkpointStoreForBackup(sm)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	artifacts, err := store.List(ctx)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to list checkpoints: %v", err))
+		return result
+	}
+
+	expired := selectExpiredCheckpoints(artifacts, *policy, time.Now())
+	for _, artifact := range expired {
+		if err := store.Delete(ctx, artifact); err != nil {
+			klog.ErrorS(err, "Failed to delete expired checkpoint", "backup", sm.GetName(), "checkpoint", artifact.Name)
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to delete %s: %v", artifact.Name, err))
+			continue
+		}
+		result.Deleted = append(result.Deleted, artifact)
+		result.ReclaimedBytes += artifact.Size
+	}
+	result.Kept = len(artifacts) - len(result.Deleted)
+
+	klog.InfoS("Checkpoint garbage collection finished", "backup", sm.GetName(),
+		"deleted", len(result.Deleted), "kept", result.Kept, "reclaimedBytes", result.ReclaimedBytes)
+	return result
+}
+
+// runGC runs garbage collection for all backups, or only for backupID when it is set
+func runGC(ctx context.Context, backupID string) ([]GCResult, error) {
+	gcMutex.Lock()
+	defer gcMutex.Unlock()
+
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %v", err)
+	}
+
+	// Backups in the trash keep their checkpoints until they are purged, in case they are restored
+	selector := migration.Backup.ActiveLabelSelector()
+	if backupID != "" {
+		selector = fmt.Sprintf("%s,backup-id=%s", selector, backupID)
+	}
+	list, err := dynamicClient.Resource(statefulMigrationGVR).Namespace(defaultNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %v", err)
+	}
+	if backupID != "" && len(list.Items) == 0 {
+		return nil, fmt.Errorf("backup %s not found", backupID)
+	}
+	service := migration.NewService(dynamicClient, migration.Backup, defaultNamespace)
+
+	results := make([]GCResult, 0, len(list.Items))
+	for i := range list.Items {
+		sm := &list.Items[i]
+		result := runBackupGC(ctx, sm)
+		results = append(results, result)
+
+		// CRs managed by GitOps are read-only, their garbage collection time is not recorded
+		if len(result.Deleted) > 0 && migration.ManagedBy(sm) == migration.ManagedByDashboard {
+			gcTime := time.Now().Format(time.RFC3339)
+			_, err := service.Mutate(ctx, migration.Backup.ID(sm.GetName()), func(obj *unstructured.Unstructured) error {
+				annotations := obj.GetAnnotations()
+				if annotations == nil {
+					annotations = map[string]string{}
+				}
+				annotations[lastGCAnnotation] = gcTime
+				obj.SetAnnotations(annotations)
+				return nil
+			})
+			if err != nil {
+				klog.ErrorS(err, "Failed to record garbage collection time", "backup", sm.GetName())
+			}
+		}
+	}
+	return results, nil
+}
+
+// StartRetentionWorker periodically garbage collects expired checkpoints until ctx is done.
+// A non-positive interval disables the worker.
+func StartRetentionWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Checkpoint garbage collection worker is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := runGC(ctx, ""); err != nil {
+					klog.ErrorS(err, "Checkpoint garbage collection failed")
+				}
+			}
+		}
+	}()
+	klog.InfoS("Checkpoint garbage collection worker started", "interval", interval)
+}
+
+// handleRunGC runs checkpoint garbage collection for all backups on demand
+func handleRunGC(c *gin.Context) {
+	results, err := runGC(c, "")
+	if err != nil {
+		klog.ErrorS(err, "Failed to run checkpoint garbage collection")
+		common.Fail(c, err)
+		return
+	}
+
+	var reclaimed int64
+	deleted := 0
+	for _, result := range results {
+		reclaimed += result.ReclaimedBytes
+		deleted += len(result.Deleted)
+	}
+	common.Success(c, map[string]interface{}{
+		"results":        results,
+		"deleted":        deleted,
+		"reclaimedBytes": reclaimed,
+	})
+}
+
+// handleRunBackupGC runs checkpoint garbage collection for a single backup on demand
+func handleRunBackupGC(c *gin.Context) {
+	backupID := c.Param("id")
+	results, err := runGC(c, backupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to run checkpoint garbage collection", "backupID", backupID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, results[0])
+}
+
+func init() {
+	r := router.V1()
+
+	backupGroup := r.Group("/backup")
+	backupGroup.Use(idempotencyMiddleware())
+	{
+		backupGroup.POST("/gc", handleRunGC)
+		backupGroup.POST("/:id/gc", handleRunBackupGC)
+	}
+}
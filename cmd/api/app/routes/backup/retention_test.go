@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSelectExpiredCheckpoints(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	checkpoint := func(name string, age time.Duration) checkpointArtifact {
+		return checkpointArtifact{Name: name, CreatedAt: now.Add(-age)}
+	}
+	tests := []struct {
+		name      string
+		artifacts []checkpointArtifact
+		policy    RetentionPolicy
+		expired   []string
+	}{
+		{
+			name: "keep last n",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 4*time.Hour),
+				checkpoint("c4", time.Hour),
+				checkpoint("c2", 3*time.Hour),
+				checkpoint("c3", 2*time.Hour),
+			},
+			policy:  RetentionPolicy{KeepLast: 2},
+			expired: []string{"c2", "c1"},
+		},
+		{
+			name: "keep last n larger than the number of checkpoints",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 2*time.Hour),
+				checkpoint("c2", time.Hour),
+			},
+			policy: RetentionPolicy{KeepLast: 5},
+		},
+		{
+			name: "max age",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 72*time.Hour),
+				checkpoint("c2", 25*time.Hour),
+				checkpoint("c3", 23*time.Hour),
+				checkpoint("c4", time.Hour),
+			},
+			policy:  RetentionPolicy{MaxAge: "1d"},
+			expired: []string{"c2", "c1"},
+		},
+		{
+			name: "max age ignores checkpoints without a creation time",
+			artifacts: []checkpointArtifact{
+				{Name: "c1"},
+				checkpoint("c2", time.Hour),
+			},
+			policy: RetentionPolicy{MaxAge: "30m"},
+		},
+		{
+			name: "both limits, keep last n is stricter",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 3*time.Hour),
+				checkpoint("c2", 2*time.Hour),
+				checkpoint("c3", time.Hour),
+			},
+			policy:  RetentionPolicy{KeepLast: 1, MaxAge: "72h"},
+			expired: []string{"c2", "c1"},
+		},
+		{
+			name: "both limits, max age is stricter",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 50*time.Hour),
+				checkpoint("c2", 26*time.Hour),
+				checkpoint("c3", time.Hour),
+			},
+			policy:  RetentionPolicy{KeepLast: 10, MaxAge: "24h"},
+			expired: []string{"c2", "c1"},
+		},
+		{
+			name: "equal timestamps are ordered by name",
+			artifacts: []checkpointArtifact{
+				checkpoint("c-a", time.Hour),
+				checkpoint("c-c", time.Hour),
+				checkpoint("c-b", time.Hour),
+			},
+			policy:  RetentionPolicy{KeepLast: 1},
+			expired: []string{"c-b", "c-a"},
+		},
+		{
+			name: "newest checkpoint is kept when all are too old",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 96*time.Hour),
+				checkpoint("c2", 48*time.Hour),
+			},
+			policy:  RetentionPolicy{MaxAge: "1h"},
+			expired: []string{"c1"},
+		},
+		{
+			name:      "single checkpoint is never deleted",
+			artifacts: []checkpointArtifact{checkpoint("c1", 96*time.Hour)},
+			policy:    RetentionPolicy{KeepLast: 1, MaxAge: "1h"},
+		},
+		{
+			name: "empty policy keeps everything",
+			artifacts: []checkpointArtifact{
+				checkpoint("c1", 96*time.Hour),
+				checkpoint("c2", time.Hour),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expired []string
+			for _, artifact := range selectExpiredCheckpoints(tt.artifacts, tt.policy, now) {
+				expired = append(expired, artifact.Name)
+			}
+			if !reflect.DeepEqual(expired, tt.expired) {
+				t.Errorf("selectExpiredCheckpoints() = %v, want %v", expired, tt.expired)
+			}
+		})
+	}
+}
+
+func TestValidateRetentionStorage(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *RetentionPolicy
+		storageType string
+		wantErr     bool
+	}{
+		{name: "no policy on pvc", storageType: StorageTypePVC},
+		{name: "empty policy on pvc", policy: &RetentionPolicy{}, storageType: StorageTypePVC},
+		{name: "keep last on pvc", policy: &RetentionPolicy{KeepLast: 3}, storageType: StorageTypePVC, wantErr: true},
+		{name: "max age on pvc", policy: &RetentionPolicy{MaxAge: "7d"}, storageType: StorageTypePVC, wantErr: true},
+		{name: "policy on s3", policy: &RetentionPolicy{KeepLast: 3}, storageType: StorageTypeS3},
+		{name: "policy on registry", policy: &RetentionPolicy{MaxAge: "7d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRetentionStorage(tt.policy, tt.storageType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRetentionStorage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
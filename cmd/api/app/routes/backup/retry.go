@@ -0,0 +1,282 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/notification"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	retryPolicyAnnotation = "backup.dcnlab.com/retry-policy"
+	retryStateAnnotation  = "backup.dcnlab.com/retry-state"
+)
+
+// Statuses of the backup history entries recorded by the retry worker
+const (
+	historyStatusRetrying         = "retrying"
+	historyStatusRetriesExhausted = "retries-exhausted"
+)
+
+// setRetryPolicyAnnotation stores the retry policy on the StatefulMigration, a policy without attempts
+// turns retries off
+func setRetryPolicyAnnotation(sm *unstructured.Unstructured, policy *migration.RetryPolicy) {
+	if policy == nil {
+		return
+	}
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if policy.MaxAttempts == 0 {
+		delete(annotations, retryPolicyAnnotation)
+		delete(annotations, retryStateAnnotation)
+	} else if data, err := json.Marshal(policy); err == nil {
+		annotations[retryPolicyAnnotation] = string(data)
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// retryPolicyFromAnnotations reads the retry policy of a StatefulMigration, nil if retries are off
+func retryPolicyFromAnnotations(sm *unstructured.Unstructured) *migration.RetryPolicy {
+	data, ok := sm.GetAnnotations()[retryPolicyAnnotation]
+	if !ok {
+		return nil
+	}
+	policy := &migration.RetryPolicy{}
+	if err := json.Unmarshal([]byte(data), policy); err != nil || policy.MaxAttempts <= 0 {
+		return nil
+	}
+	return policy
+}
+
+// retryStateFromAnnotations reads the progress of the retries of a StatefulMigration
+func retryStateFromAnnotations(sm *unstructured.Unstructured) migration.RetryState {
+	var state migration.RetryState
+	if data, ok := sm.GetAnnotations()[retryStateAnnotation]; ok {
+		_ = json.Unmarshal([]byte(data), &state)
+	}
+	return state
+}
+
+// setRetryStateAnnotation stores the progress of the retries on the StatefulMigration
+func setRetryStateAnnotation(sm *unstructured.Unstructured, state migration.RetryState) {
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if state == (migration.RetryState{}) {
+		delete(annotations, retryStateAnnotation)
+	} else if data, err := json.Marshal(state); err == nil {
+		annotations[retryStateAnnotation] = string(data)
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// backupExecution is the latest execution of a backup, made of the newest CheckpointBackup of each pod
+type backupExecution struct {
+	// ID identifies a failed execution by the newest of its failed CheckpointBackups
+	ID      string
+	Outcome string
+	Cluster string
+	Message string
+}
+
+// latestExecution returns the latest execution of a backup in its source clusters. The execution runs while
+// one of its CheckpointBackups runs and failed when one of them failed.
+func latestExecution(ctx context.Context, backup BackupConfiguration) (*backupExecution, error) {
+	type checkpoint struct {
+		cluster string
+		obj     *unstructured.Unstructured
+		cb      *CheckpointBackup
+	}
+	newest := map[string]checkpoint{}
+	for _, clusterName := range strings.Split(backup.Cluster, ",") {
+		dynamicClient, _, err := client.MemberDynamicClient(ctx, clusterName)
+		if err != nil {
+			return nil, err
+		}
+		list, err := dynamicClient.Resource(checkpointBackupGVR).Namespace(backup.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list checkpoint backups of cluster %s: %v", clusterName, err)
+		}
+		for i := range list.Items {
+			cb, err := decodeCheckpointBackup(&list.Items[i])
+			if err != nil || !checkpointMatchesResource(cb, backup.ResourceName) {
+				continue
+			}
+			key := clusterName + "/" + cb.Spec.PodName
+			if cb.Spec.PodName == "" {
+				key = clusterName + "/" + cb.Name
+			}
+			if current, ok := newest[key]; !ok || current.obj.GetCreationTimestamp().Time.Before(list.Items[i].GetCreationTimestamp().Time) {
+				newest[key] = checkpoint{cluster: clusterName, obj: &list.Items[i], cb: cb}
+			}
+		}
+	}
+	if len(newest) == 0 {
+		return nil, nil
+	}
+
+	execution := &backupExecution{Outcome: migration.ExecutionSucceeded}
+	var failed *checkpoint
+	for _, current := range newest {
+		switch migration.ExecutionOutcome(current.cb.Status.Phase) {
+		case migration.ExecutionRunning:
+			execution.Outcome = migration.ExecutionRunning
+		case migration.ExecutionFailed:
+			if failed == nil || failed.obj.GetCreationTimestamp().Time.Before(current.obj.GetCreationTimestamp().Time) {
+				current := current
+				failed = &current
+			}
+		}
+	}
+	if execution.Outcome == migration.ExecutionRunning || failed == nil {
+		return execution, nil
+	}
+	execution.Outcome = migration.ExecutionFailed
+	execution.ID = string(failed.obj.GetUID())
+	execution.Cluster = failed.cluster
+	execution.Message = fmt.Sprintf("CheckpointBackup %s/%s failed: %s", failed.cb.Namespace, failed.cb.Name, failed.cb.Status.Message)
+	return execution, nil
+}
+
+// recordRetryHistory adds an entry for a retry or for exhausted retries to the execution history of a backup
+func recordRetryHistory(ctx context.Context, backup BackupConfiguration, status string, attempt int, message string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: backup.ID + "-retry-",
+			Namespace:    config.GetNamespace(),
+			Labels: map[string]string{
+				"app":       "backup-history",
+				"backup-id": backup.ID,
+			},
+		},
+		Data: map[string]string{
+			"timestamp": time.Now().Format(time.RFC3339),
+			"status":    status,
+			"attempt":   strconv.Itoa(attempt),
+			"error":     message,
+		},
+	}
+	_, err := client.InClusterClient().CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	return err
+}
+
+// retryBackup applies the retry policy of a backup to its latest execution: a new failure is scheduled for a
+// retry, a retry is triggered once its backoff elapsed and a failure after the last attempt is notified
+func retryBackup(ctx context.Context, service *migration.Service, sm *unstructured.Unstructured, policy migration.RetryPolicy) error {
+	backup := statefulMigrationToBackup(sm)
+	execution, err := latestExecution(ctx, backup)
+	if err != nil || execution == nil {
+		return err
+	}
+	state, action := policy.Next(retryStateFromAnnotations(sm), execution.ID, execution.Outcome, time.Now())
+	switch action {
+	case migration.RetryNone:
+		return nil
+	case migration.RetryNow:
+		// The state is recorded with the trigger, so a retry is triggered once even when the update is retried
+		if _, err := triggerBackup(ctx, backup.ID, func(obj *unstructured.Unstructured) {
+			setRetryStateAnnotation(obj, state)
+		}); err != nil {
+			return fmt.Errorf("failed to retry backup: %v", err)
+		}
+		klog.InfoS("Retried failed backup", "backupID", backup.ID, "attempt", state.Attempts, "maxAttempts", policy.MaxAttempts)
+		return recordRetryHistory(ctx, backup, historyStatusRetrying, state.Attempts, execution.Message)
+	}
+
+	if _, err := service.Mutate(ctx, backup.ID, func(obj *unstructured.Unstructured) error {
+		setRetryStateAnnotation(obj, state)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to record retry state: %v", err)
+	}
+	if action != migration.RetryExhausted {
+		return nil
+	}
+	klog.InfoS("Retries of failed backup exhausted", "backupID", backup.ID, "attempts", state.Attempts)
+	notification.Dispatch(ctx, notification.Event{
+		Type:      notification.EventBackupRetriesExhausted,
+		Title:     fmt.Sprintf("Backup %s failed after %d retries", backup.Name, state.Attempts),
+		Message:   fmt.Sprintf("Backup %s of %s %s/%s still fails after %d retries. %s", backup.Name, backup.ResourceType, backup.Namespace, backup.ResourceName, state.Attempts, execution.Message),
+		Cluster:   execution.Cluster,
+		Resource:  fmt.Sprintf("%s/%s", backup.Namespace, backup.ResourceName),
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+	return recordRetryHistory(ctx, backup, historyStatusRetriesExhausted, state.Attempts, execution.Message)
+}
+
+// runBackupRetries applies the retry policies of the backup configurations. Configurations managed by
+// GitOps are read-only and are not retried.
+func runBackupRetries(ctx context.Context) {
+	service, err := backupService()
+	if err != nil {
+		return
+	}
+	items, err := service.List(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list backup configurations for retries")
+		return
+	}
+	for i := range items {
+		sm := &items[i]
+		policy := retryPolicyFromAnnotations(sm)
+		if policy == nil || migration.ManagedBy(sm) != migration.ManagedByDashboard {
+			continue
+		}
+		if err := retryBackup(ctx, service, sm, *policy); err != nil {
+			klog.ErrorS(err, "Failed to apply backup retry policy", "backup", sm.GetName())
+		}
+	}
+}
+
+// StartRetryWorker periodically re-triggers failed backup executions according to the retry policy of their
+// configuration until ctx is done. A non-positive interval disables the worker.
+func StartRetryWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Backup retry worker is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runBackupRetries(ctx)
+			}
+		}
+	}()
+	klog.InfoS("Backup retry worker started", "interval", interval)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/karmada-io/dashboard/pkg/search"
+)
+
+// BackupSearchDocuments returns the backup configurations as documents of the global search
+func BackupSearchDocuments(ctx context.Context) ([]search.Document, error) {
+	service, err := backupService()
+	if err != nil {
+		return nil, err
+	}
+	items, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]search.Document, 0, len(items))
+	for i := range items {
+		backup := statefulMigrationToBackup(&items[i])
+		docs = append(docs, search.Document{
+			Type:        search.TypeBackup,
+			ID:          backup.ID,
+			Name:        backup.Name,
+			Cluster:     backup.Cluster,
+			Namespace:   backup.Namespace,
+			Description: fmt.Sprintf("Backup of %s %s", backup.ResourceType, backup.ResourceName),
+			Keywords:    []string{backup.ResourceName, backup.ResourceType, backup.Repository, backup.TemplateID},
+			Link: search.Link{
+				Module: "backups",
+				API:    "/api/v1/backup/" + backup.ID,
+				Params: map[string]string{"id": backup.ID},
+			},
+		})
+	}
+	return docs, nil
+}
+
+// RecoverySearchDocuments returns the recovery records as documents of the global search. A recovery belongs
+// to its target cluster.
+func RecoverySearchDocuments(ctx context.Context) ([]search.Document, error) {
+	service, err := recoveryService()
+	if err != nil {
+		return nil, err
+	}
+	items, err := service.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]search.Document, 0, len(items))
+	for i := range items {
+		recovery := statefulMigrationToRecovery(&items[i])
+		docs = append(docs, search.Document{
+			Type:        search.TypeRecovery,
+			ID:          recovery.ID,
+			Name:        recovery.Name,
+			Cluster:     recovery.TargetCluster,
+			Namespace:   recovery.Namespace,
+			Description: fmt.Sprintf("Recovery of %s %s from %s, %s", recovery.ResourceType, recovery.ResourceName, recovery.SourceCluster, recovery.Status),
+			Keywords:    []string{recovery.ResourceName, recovery.BackupName, recovery.SourceCluster},
+			Link: search.Link{
+				Module: "recoveries",
+				API:    "/api/v1/backup/recovery/" + recovery.ID,
+				Params: map[string]string{"id": recovery.ID},
+			},
+		})
+	}
+	return docs, nil
+}
@@ -31,8 +31,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -44,6 +46,9 @@ import (
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
 	"github.com/karmada-io/dashboard/pkg/config"
+	clusterresource "github.com/karmada-io/dashboard/pkg/resource/cluster"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
 )
 
 // ClusterInfo represents cluster information with migration controller status
@@ -59,15 +64,21 @@ type ClusterInfo struct {
 	Error                      string `json:"error,omitempty"`
 }
 
-// InstallControllerRequest represents the request to install migration controller
+// InstallControllerRequest represents the request to install migration controller. A cluster group or label
+// selector installs the controller on every matching member cluster, including the ones that match later.
 type InstallControllerRequest struct {
-	ClusterName string `json:"clusterName" binding:"required"`
-	Version     string `json:"version,omitempty"` // defaults to v2.0
+	ClusterName   string            `json:"clusterName" binding:"required_without_all=ClusterGroup LabelSelector,omitempty,cluster"`
+	ClusterGroup  string            `json:"clusterGroup,omitempty"`
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+	Version       string            `json:"version,omitempty"` // defaults to the latest published version
 }
 
-// UninstallControllerRequest represents the request to uninstall migration controller
+// UninstallControllerRequest represents the request to uninstall migration controller. The cluster group or
+// label selector must be the one the controller was installed with.
 type UninstallControllerRequest struct {
-	ClusterName string `json:"clusterName" binding:"required"`
+	ClusterName   string            `json:"clusterName" binding:"required_without_all=ClusterGroup LabelSelector,omitempty,cluster"`
+	ClusterGroup  string            `json:"clusterGroup,omitempty"`
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
 }
 
 // handleGetClusters retrieves all clusters with migration controller status
@@ -121,54 +132,162 @@ func handleGetClusterDetail(c *gin.Context) {
 	common.Success(c, clusterInfo)
 }
 
-// handleInstallController installs the migration controller on a cluster
+// handleInstallController starts a job that installs the migration controller on a cluster
 func handleInstallController(c *gin.Context) {
 	var req InstallControllerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind install controller request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
-	// Default to v2.0 if version not specified
+	// Default to the latest published version if not specified
 	if req.Version == "" {
-		req.Version = "v2.0"
+		req.Version = getControllerCatalog(c).Latest
 	}
 
-	// Install controller using deployment script
-	err := installMigrationController(req.ClusterName, req.Version)
-	if err != nil {
-		klog.ErrorS(err, "Failed to install migration controller", "cluster", req.ClusterName)
-		common.Fail(c, err)
+	matchLabels := clusterresource.SelectorLabels(req.ClusterGroup, req.LabelSelector)
+	if matchLabels != nil {
+		handleInstallControllerForSelector(c, req.ClusterName, matchLabels, req.Version)
+		return
+	}
+
+	if err := validateControllerVersion(c, req.ClusterName, req.Version); err != nil {
+		common.Fail(c, fmt.Errorf("cluster %s: %w", req.ClusterName, err))
+		return
+	}
+
+	job, ok := enqueueControllerOperation(c, controllerJobParams{
+		Operation: "install",
+		Clusters:  []string{req.ClusterName},
+		Version:   req.Version,
+	})
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": fmt.Sprintf("Migration controller installation started on cluster %s", req.ClusterName),
+	common.Success(c, InstallResult{
+		Message:  fmt.Sprintf("Migration controller installation started on cluster %s", req.ClusterName),
+		JobID:    job.ID,
+		Clusters: []string{req.ClusterName},
 	})
 }
 
-// handleUninstallController uninstalls the migration controller from a cluster
+// handleInstallControllerForSelector starts a job that installs the migration controller for a cluster label
+// selector. The version is checked against the clusters matching now; clusters that match later get the same version.
+func handleInstallControllerForSelector(c *gin.Context, clusterName string, matchLabels map[string]string, version string) {
+	clusterNames, ok := resolveControllerSelector(c, clusterName, matchLabels)
+	if !ok {
+		return
+	}
+	for _, name := range clusterNames {
+		if err := validateControllerVersion(c, name, version); err != nil {
+			common.Fail(c, fmt.Errorf("cluster %s: %w", name, err))
+			return
+		}
+	}
+	if len(clusterNames) == 0 {
+		// Without a cluster only the catalog is checked
+		if err := validateControllerVersion(c, "", version); err != nil {
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	job, ok := enqueueControllerOperation(c, controllerJobParams{
+		Operation: "install",
+		Clusters:  clusterNames,
+		Version:   version,
+		Selector:  matchLabels,
+	})
+	if !ok {
+		return
+	}
+
+	selector := labels.SelectorFromSet(matchLabels).String()
+	common.Success(c, InstallResult{
+		Message:  fmt.Sprintf("Migration controller installation started on clusters matching %s", selector),
+		JobID:    job.ID,
+		Clusters: clusterNames,
+		Selector: selector,
+	})
+}
+
+// resolveControllerSelector returns the clusters matching a label selector of a controller operation
+func resolveControllerSelector(c *gin.Context, clusterName string, matchLabels map[string]string) ([]string, bool) {
+	if clusterName != "" {
+		common.FailWithStatus(c, fmt.Errorf("clusterName cannot be combined with clusterGroup or labelSelector"), http.StatusBadRequest)
+		return nil, false
+	}
+	if err := clusterresource.ValidateLabels(matchLabels, matchLabels); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return nil, false
+	}
+	clusterNames, err := clusterresource.GetSelectedClusters(client.InClusterKarmadaClient(), matchLabels)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list clusters matching the selector", "selector", matchLabels)
+		common.Fail(c, err)
+		return nil, false
+	}
+	return clusterNames, true
+}
+
+// handleUninstallController starts a job that uninstalls the migration controller from a cluster. An uninstall
+// is refused with 409 and the list of blockers while backup configurations or operations in flight use the
+// controller, unless ?force=true is set, which deletes the backup configurations of the cluster as well.
 func handleUninstallController(c *gin.Context) {
 	var req UninstallControllerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind uninstall controller request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
-	// Uninstall controller using deployment script
-	err := uninstallMigrationController(req.ClusterName)
-	if err != nil {
-		klog.ErrorS(err, "Failed to uninstall migration controller", "cluster", req.ClusterName)
-		common.Fail(c, err)
+	params := controllerJobParams{Operation: "uninstall", Clusters: []string{req.ClusterName}}
+	target, selector := "cluster "+req.ClusterName, ""
+	if matchLabels := clusterresource.SelectorLabels(req.ClusterGroup, req.LabelSelector); matchLabels != nil {
+		clusterNames, ok := resolveControllerSelector(c, req.ClusterName, matchLabels)
+		if !ok {
+			return
+		}
+		params.Clusters, params.Selector = clusterNames, matchLabels
+		selector = labels.SelectorFromSet(matchLabels).String()
+		target = "clusters matching " + selector
+	}
+
+	// The job checks again when it runs, this reports the blockers to the caller right away
+	params.Force = c.Query("force") == "true"
+	blockers := []migration.UninstallBlocker{}
+	for _, clusterName := range params.Clusters {
+		clusterBlockers, err := controllerUninstallBlockers(c, clusterName)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check migration controller uninstall", "cluster", clusterName)
+			common.Fail(c, err)
+			return
+		}
+		if len(clusterBlockers) > 0 && !params.Force {
+			err := &migration.UninstallBlockedError{Cluster: clusterName, Blockers: clusterBlockers}
+			common.FailWithData(c, err, http.StatusConflict, InstallResult{
+				Message:  err.Error(),
+				Clusters: []string{clusterName},
+				Blockers: clusterBlockers,
+			})
+			return
+		}
+		blockers = append(blockers, clusterBlockers...)
+	}
+
+	job, ok := enqueueControllerOperation(c, params)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": fmt.Sprintf("Migration controller uninstallation started on cluster %s", req.ClusterName),
+	common.Success(c, InstallResult{
+		Message:  fmt.Sprintf("Migration controller uninstallation started on %s", target),
+		JobID:    job.ID,
+		Clusters: params.Clusters,
+		Selector: selector,
+		Blockers: blockers,
 	})
 }
 
@@ -176,18 +295,18 @@ func handleUninstallController(c *gin.Context) {
 func handleCheckControllerStatus(c *gin.Context) {
 	clusterName := c.Param("name")
 
-	status, version, err := checkMigrationControllerStatus(c, clusterName)
+	status, version, err := MigrationControllerStatus(c, clusterName)
 	if err != nil {
 		klog.ErrorS(err, "Failed to check migration controller status", "cluster", clusterName)
 		common.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"clusterName": clusterName,
-		"status":      status,
-		"version":     version,
-		"checkedAt":   time.Now().Format(time.RFC3339),
+	common.Success(c, ControllerStatusResult{
+		ClusterName: clusterName,
+		Status:      status,
+		Version:     version,
+		CheckedAt:   time.Now().Format(time.RFC3339),
 	})
 }
 
@@ -203,10 +322,10 @@ func handleGetControllerLogs(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"clusterName": clusterName,
-		"logs":        logs,
-		"retrievedAt": time.Now().Format(time.RFC3339),
+	common.Success(c, ControllerLogsResult{
+		ClusterName: clusterName,
+		Logs:        logs,
+		RetrievedAt: time.Now().Format(time.RFC3339),
 	})
 }
 
@@ -254,7 +373,7 @@ func memberClusterToClusterInfo(ctx *gin.Context, cluster *clusterv1alpha1.Clust
 	}
 
 	// Check migration controller status
-	status, version, err := checkMigrationControllerStatus(ctx, cluster.Name)
+	status, version, err := MigrationControllerStatus(ctx, cluster.Name)
 	if err != nil {
 		clusterInfo.MigrationControllerStatus = "error"
 		clusterInfo.Error = err.Error()
@@ -278,7 +397,8 @@ func getClusterReadyStatus(cluster *clusterv1alpha1.Cluster) string {
 	return "Unknown"
 }
 
-func checkMigrationControllerStatus(ctx *gin.Context, clusterName string) (status, version string, err error) {
+// MigrationControllerStatus returns the state and version of the migration controller of a cluster
+func MigrationControllerStatus(ctx *gin.Context, clusterName string) (status, version string, err error) {
 	// For management cluster, check local deployments
 	if clusterName == "mgmt-cluster" || clusterName == "management" {
 		return checkManagementMigrationController()
@@ -288,263 +408,38 @@ func checkMigrationControllerStatus(ctx *gin.Context, clusterName string) (statu
 	return checkMemberMigrationController(ctx, clusterName)
 }
 
-// extractVersionFromDeployment extracts version from deployment container image
-func extractVersionFromDeployment(deployment *appsv1.Deployment, controllerType string) string {
-	for _, container := range deployment.Spec.Template.Spec.Containers {
-		// Extract version from image name like: docker.io/lehuannhatrang/stateful-migration-operator:migrationBackup_v1.21
-		if strings.Contains(container.Image, ":"+controllerType+"_") {
-			parts := strings.Split(container.Image, ":"+controllerType+"_")
-			if len(parts) > 1 {
-				return parts[1]
-			}
-		}
-	}
-	return "unknown"
-}
-
-// extractVersionFromDaemonSetUnstructured extracts version from DaemonSet container image (unstructured)
-func extractVersionFromDaemonSetUnstructured(daemonSet map[string]interface{}, controllerType string) string {
-	spec, exists := daemonSet["spec"]
-	if !exists {
-		return "unknown"
-	}
-
-	specMap := spec.(map[string]interface{})
-	template, exists := specMap["template"]
-	if !exists {
-		return "unknown"
-	}
-
-	templateMap := template.(map[string]interface{})
-	templateSpec, exists := templateMap["spec"]
-	if !exists {
-		return "unknown"
-	}
-
-	templateSpecMap := templateSpec.(map[string]interface{})
-	containers, exists := templateSpecMap["containers"]
-	if !exists {
-		return "unknown"
-	}
-
-	containersSlice := containers.([]interface{})
-	for _, container := range containersSlice {
-		containerMap := container.(map[string]interface{})
-		if image, exists := containerMap["image"]; exists {
-			imageStr := image.(string)
-			// Extract version from image name like: docker.io/lehuannhatrang/stateful-migration-operator:checkpointBackup_v1.21
-			if strings.Contains(imageStr, ":"+controllerType+"_") {
-				parts := strings.Split(imageStr, ":"+controllerType+"_")
-				if len(parts) > 1 {
-					return parts[1]
-				}
-			}
-		}
-	}
-	return "unknown"
-}
-
 func checkManagementMigrationController() (status, versionResult string, err error) {
-	// For management cluster, check:
-	// - migrationBackup controller (Deployment)
-	// - migrationRestore controller (Deployment)
-	// - statefulMigration CRD
-
-	k8sClient := client.InClusterClient()
-
-	// Check migrationBackup controller deployment
-	migrationBackupDeployments, err := k8sClient.AppsV1().Deployments("stateful-migration").List(context.TODO(), metav1.ListOptions{
-		LabelSelector: "app.kubernetes.io/name=migration-backup-controller",
-	})
-	if err != nil {
-		return "error", "", fmt.Errorf("failed to check migrationBackup controller: %v", err)
-	}
-
-	// Check migrationRestore controller deployment
-	migrationRestoreDeployments, err := k8sClient.AppsV1().Deployments("stateful-migration").List(context.TODO(), metav1.ListOptions{
-		LabelSelector: "app.kubernetes.io/name=migration-restore-controller",
-	})
-	if err != nil {
-		return "error", "", fmt.Errorf("failed to check migrationRestore controller: %v", err)
-	}
-
-	// Check statefulMigration CRD using dynamic client
 	dynamicClient, err := client.GetDynamicClient()
 	if err != nil {
-		return "error", "", fmt.Errorf("failed to get dynamic client: %v", err)
-	}
-
-	// Try to list StatefulMigration resources to verify CRD exists
-	statefulMigrationGVR := schema.GroupVersionResource{
-		Group:    "migration.dcnlab.com",
-		Version:  "v1",
-		Resource: "statefulmigrations",
-	}
-
-	// Try to list resources - if CRD doesn't exist, this will fail
-	_, err = dynamicClient.Resource(statefulMigrationGVR).Namespace(config.GetNamespace()).List(context.TODO(), metav1.ListOptions{Limit: 1})
-	if err != nil {
-		return "error", "", fmt.Errorf("statefulMigration CRD not found or not accessible: %v", err)
-	}
-
-	// Check if all components are present and ready
-	var detectedVersion string
-	componentsReady := 0
-	totalComponents := 2 // migrationBackup + migrationRestore controllers
-
-	// Check migrationBackup controller
-	if len(migrationBackupDeployments.Items) > 0 {
-		deployment := migrationBackupDeployments.Items[0]
-		if deployment.Status.ReadyReplicas > 0 {
-			componentsReady++
-			if detectedVersion == "" {
-				detectedVersion = extractVersionFromDeployment(&deployment, "migrationBackup")
-			}
-		}
-	}
-
-	// Check migrationRestore controller
-	if len(migrationRestoreDeployments.Items) > 0 {
-		deployment := migrationRestoreDeployments.Items[0]
-		if deployment.Status.ReadyReplicas > 0 {
-			componentsReady++
-			if detectedVersion == "" {
-				detectedVersion = extractVersionFromDeployment(&deployment, "migrationRestore")
-			}
-		}
-	}
-
-	// Determine overall status
-	if componentsReady == 0 {
-		return "not-installed", "", nil
-	} else if componentsReady == totalComponents {
-		return "installed", detectedVersion, nil
-	} else {
-		return "partial", detectedVersion, fmt.Errorf("only %d of %d controllers are ready", componentsReady, totalComponents)
+		return migration.StatusError, "", fmt.Errorf("failed to get dynamic client: %v", err)
 	}
+	return migration.ManagementControllerStatus(context.TODO(), client.InClusterClient(), dynamicClient, config.GetNamespace())
 }
 
 func checkMemberMigrationController(ctx *gin.Context, clusterName string) (status, versionResult string, err error) {
-	// For member cluster, check:
-	// - checkpointBackup controller (DaemonSet)
-	// - checkpointBackup CRD
-	// - checkpointRestore CRD
-
 	karmadaClient := client.InClusterKarmadaClient()
 
 	// Check if the cluster exists and is ready in Karmada
 	cluster, err := karmadaClient.ClusterV1alpha1().Clusters().Get(context.TODO(), clusterName, metav1.GetOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to get cluster from Karmada", "cluster", clusterName)
-		return "error", "", fmt.Errorf("cluster not found in Karmada: %v", err)
+		return migration.StatusError, "", fmt.Errorf("cluster not found in Karmada: %v", err)
 	}
-
-	// Check cluster readiness
-	isReady := false
-	for _, condition := range cluster.Status.Conditions {
-		if condition.Type == clusterv1alpha1.ClusterConditionReady && condition.Status == metav1.ConditionTrue {
-			isReady = true
-			break
-		}
-	}
-
-	if !isReady {
+	if getClusterReadyStatus(cluster) != "Ready" {
 		klog.InfoS("Cluster is not ready, migration controller status unknown", "cluster", clusterName)
-		return "unknown", "", fmt.Errorf("cluster %s is not ready", clusterName)
+		return migration.StatusUnknown, "", fmt.Errorf("cluster %s is not ready", clusterName)
 	}
 
-	// Create dynamic client for the member cluster
 	dynamicClient, err := client.GetDynamicClientForMember(ctx, clusterName)
 	if err != nil {
 		klog.ErrorS(err, "Failed to create dynamic client for member cluster", "cluster", clusterName)
-		return "error", "", fmt.Errorf("failed to create dynamic client for member cluster: %v", err)
-	}
-
-	// Check checkpointBackup CRD
-	checkpointBackupGVR := schema.GroupVersionResource{
-		Group:    "migration.dcnlab.com",
-		Version:  "v1",
-		Resource: "checkpointbackups",
+		return migration.StatusError, "", fmt.Errorf("failed to create dynamic client for member cluster: %v", err)
 	}
-
-	_, err = dynamicClient.Resource(checkpointBackupGVR).List(context.TODO(), metav1.ListOptions{Limit: 1})
+	status, versionResult, err = migration.MemberControllerStatus(ctx, dynamicClient, clusterName)
 	if err != nil {
-		return "error", "", fmt.Errorf("checkpointBackup CRD not found: %v", err)
+		klog.ErrorS(err, "Failed to check migration controller", "cluster", clusterName)
 	}
-
-	// Check checkpointRestore CRD
-	checkpointRestoreGVR := schema.GroupVersionResource{
-		Group:    "migration.dcnlab.com",
-		Version:  "v1",
-		Resource: "checkpointrestores",
-	}
-
-	_, err = dynamicClient.Resource(checkpointRestoreGVR).List(context.TODO(), metav1.ListOptions{Limit: 1})
-	if err != nil {
-		return "error", "", fmt.Errorf("checkpointRestore CRD not found: %v", err)
-	}
-
-	// Check checkpointBackup controller DaemonSet
-	daemonSetGVR := schema.GroupVersionResource{
-		Group:    "apps",
-		Version:  "v1",
-		Resource: "daemonsets",
-	}
-
-	// Look for the cluster-specific DaemonSet name first
-	clusterSpecificDaemonSetName := fmt.Sprintf("checkpoint-backup-controller-%s", clusterName)
-	daemonSet, err := dynamicClient.Resource(daemonSetGVR).Namespace("stateful-migration").Get(context.TODO(), clusterSpecificDaemonSetName, metav1.GetOptions{})
-
-	// If cluster-specific DaemonSet not found, try the generic name (for manual deployments)
-	if err != nil && strings.Contains(err.Error(), "not found") {
-		genericDaemonSetName := "checkpoint-backup-controller"
-		daemonSet, err = dynamicClient.Resource(daemonSetGVR).Namespace("stateful-migration").Get(context.TODO(), genericDaemonSetName, metav1.GetOptions{})
-		if err != nil {
-			if strings.Contains(err.Error(), "not found") {
-				return "not-installed", "", nil
-			}
-			klog.ErrorS(err, "Failed to check checkpointBackup controller DaemonSet", "cluster", clusterName)
-			return "error", "", fmt.Errorf("failed to check checkpointBackup controller DaemonSet: %v", err)
-		}
-	} else if err != nil {
-		klog.ErrorS(err, "Failed to check checkpointBackup controller DaemonSet", "cluster", clusterName)
-		return "error", "", fmt.Errorf("failed to check checkpointBackup controller DaemonSet: %v", err)
-	}
-
-	// If we got here, the DaemonSet exists
-	// Check DaemonSet status
-	status_obj, exists := daemonSet.Object["status"]
-	if !exists {
-		return "error", "", fmt.Errorf("DaemonSet status not available")
-	}
-
-	statusMap := status_obj.(map[string]interface{})
-
-	// Get numberReady from status
-	numberReady, exists := statusMap["numberReady"]
-	if !exists {
-		return "error", "", fmt.Errorf("DaemonSet numberReady not available")
-	}
-
-	// Extract version from container image
-	detectedVersion := extractVersionFromDaemonSetUnstructured(daemonSet.Object, "checkpointBackup")
-
-	// Check if DaemonSet is ready
-	ready := int64(0)
-	switch v := numberReady.(type) {
-	case int64:
-		ready = v
-	case float64:
-		ready = int64(v)
-	default:
-		return "error", detectedVersion, fmt.Errorf("unexpected numberReady type: %T", numberReady)
-	}
-
-	if ready == 0 {
-		return "partial", detectedVersion, fmt.Errorf("DaemonSet not ready")
-	}
-
-	return "installed", detectedVersion, nil
+	return status, versionResult, err
 }
 
 // fetchYAMLFromURL fetches YAML content from a URL
@@ -583,115 +478,6 @@ func getKarmadaDynamicClient() (dynamic.Interface, error) {
 	return karmadaDynamicClient, nil
 }
 
-func applyYAMLManifestToKarmadaWithCluster(yamlContent []byte, namespace, clusterName string) error {
-	// Decode YAML into unstructured objects
-	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(yamlContent)), 4096)
-
-	// Get Karmada dynamic client
-	karmadaDynamicClient, err := getKarmadaDynamicClient()
-	if err != nil {
-		return err
-	}
-
-	for {
-		var rawObj map[string]interface{}
-		err := decoder.Decode(&rawObj)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to decode YAML: %v", err)
-		}
-
-		if rawObj == nil {
-			continue
-		}
-
-		obj := &unstructured.Unstructured{Object: rawObj}
-
-		// Set namespace if not specified and resource is namespaced
-		if obj.GetNamespace() == "" && namespace != "" {
-			// Check if this is a namespaced resource
-			gvk := obj.GroupVersionKind()
-			if gvk.Kind != "ClusterRole" && gvk.Kind != "ClusterRoleBinding" {
-				obj.SetNamespace(namespace)
-			}
-		}
-
-		// Make resource names cluster-specific if clusterName is provided
-		if clusterName != "" {
-			originalName := obj.GetName()
-			gvk := obj.GroupVersionKind()
-
-			switch gvk.Kind {
-			case "ServiceAccount":
-				if originalName == "checkpoint-backup-sa" {
-					newName := fmt.Sprintf("checkpoint-backup-sa-%s", clusterName)
-					obj.SetName(newName)
-				}
-			case "ClusterRole":
-				if originalName == "checkpoint-backup-role" {
-					newName := fmt.Sprintf("checkpoint-backup-role-%s", clusterName)
-					obj.SetName(newName)
-				}
-			case "ClusterRoleBinding":
-				if originalName == "checkpoint-backup-rolebinding" {
-					newName := fmt.Sprintf("checkpoint-backup-rolebinding-%s", clusterName)
-					obj.SetName(newName)
-
-					// Update the roleRef to point to the cluster-specific ClusterRole
-					roleRef, found, err := unstructured.NestedMap(obj.Object, "roleRef")
-					if err == nil && found {
-						if roleName, exists := roleRef["name"]; exists && roleName == "checkpoint-backup-role" {
-							roleRef["name"] = fmt.Sprintf("checkpoint-backup-role-%s", clusterName)
-							err = unstructured.SetNestedMap(obj.Object, roleRef, "roleRef")
-							if err != nil {
-								return fmt.Errorf("failed to update roleRef: %v", err)
-							}
-						}
-					}
-
-					// Update the subjects to point to the cluster-specific ServiceAccount
-					subjects, found, err := unstructured.NestedSlice(obj.Object, "subjects")
-					if err == nil && found {
-						for i, subject := range subjects {
-							subjectMap := subject.(map[string]interface{})
-							if name, exists := subjectMap["name"]; exists && name == "checkpoint-backup-sa" {
-								subjectMap["name"] = fmt.Sprintf("checkpoint-backup-sa-%s", clusterName)
-								subjects[i] = subjectMap
-							}
-						}
-						err = unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
-						if err != nil {
-							return fmt.Errorf("failed to update subjects: %v", err)
-						}
-					}
-				}
-			}
-		}
-
-		// Use Karmada dynamic client to create resources
-		gvr, err := getGVRFromGVK(obj.GroupVersionKind())
-		if err != nil {
-			return fmt.Errorf("failed to get GVR for %s: %v", obj.GroupVersionKind(), err)
-		}
-
-		var resourceClient dynamic.ResourceInterface
-		if obj.GetNamespace() != "" {
-			resourceClient = karmadaDynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
-		} else {
-			resourceClient = karmadaDynamicClient.Resource(gvr)
-		}
-
-		_, err = resourceClient.Create(context.TODO(), obj, metav1.CreateOptions{})
-		if err != nil && !strings.Contains(err.Error(), "already exists") {
-			return fmt.Errorf("failed to create %s %s in Karmada: %v", obj.GetKind(), obj.GetName(), err)
-		}
-	}
-
-	return nil
-}
-
 func getGVRFromGVK(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
 	// Map common resources
 	resourceMap := map[schema.GroupVersionKind]schema.GroupVersionResource{
@@ -714,100 +500,6 @@ func getGVRFromGVK(gvk schema.GroupVersionKind) (schema.GroupVersionResource, er
 	}, nil
 }
 
-func applyModifiedDaemonSetToKarmada(yamlContent []byte, clusterName, version string) error {
-	// Parse the YAML to modify the DaemonSet name and image
-	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(yamlContent)), 4096)
-
-	// Get Karmada dynamic client
-	karmadaDynamicClient, err := getKarmadaDynamicClient()
-	if err != nil {
-		return err
-	}
-
-	for {
-		var rawObj map[string]interface{}
-		err := decoder.Decode(&rawObj)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to decode YAML: %v", err)
-		}
-
-		if rawObj == nil {
-			continue
-		}
-
-		obj := &unstructured.Unstructured{Object: rawObj}
-
-		// Only process DaemonSet objects
-		if obj.GetKind() == "DaemonSet" {
-			// Set namespace if not specified
-			if obj.GetNamespace() == "" {
-				obj.SetNamespace("stateful-migration")
-			}
-
-			// Modify DaemonSet name to be cluster-specific
-			originalName := obj.GetName()
-			newName := fmt.Sprintf("%s-%s", originalName, clusterName)
-			obj.SetName(newName)
-
-			// Update container image - replace $image_name placeholder
-			containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
-			if err != nil {
-				return fmt.Errorf("failed to get containers: %v", err)
-			}
-			if found {
-				for i, container := range containers {
-					containerMap := container.(map[string]interface{})
-					if name, exists := containerMap["name"]; exists && name == "controller" {
-						// Replace $image_name placeholder with actual image
-						containerMap["image"] = fmt.Sprintf("docker.io/lehuannhatrang/stateful-migration-operator:checkpointBackup_%s", version)
-						containers[i] = containerMap
-						break
-					}
-				}
-				err = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
-				if err != nil {
-					return fmt.Errorf("failed to set containers: %v", err)
-				}
-			}
-
-			// Update serviceAccountName to be cluster-specific
-			serviceAccountName, found, err := unstructured.NestedString(obj.Object, "spec", "template", "spec", "serviceAccountName")
-			if err != nil {
-				return fmt.Errorf("failed to get serviceAccountName: %v", err)
-			}
-			if found && serviceAccountName == "checkpoint-backup-sa" {
-				newServiceAccountName := fmt.Sprintf("checkpoint-backup-sa-%s", clusterName)
-				err = unstructured.SetNestedField(obj.Object, newServiceAccountName, "spec", "template", "spec", "serviceAccountName")
-				if err != nil {
-					return fmt.Errorf("failed to set serviceAccountName: %v", err)
-				}
-				klog.InfoS("Updated DaemonSet serviceAccountName", "original", serviceAccountName, "new", newServiceAccountName, "cluster", clusterName)
-			} else if found {
-				klog.InfoS("ServiceAccountName found but not matching expected", "serviceAccountName", serviceAccountName, "expected", "checkpoint-backup-sa", "cluster", clusterName)
-			} else {
-				klog.InfoS("ServiceAccountName not found in DaemonSet", "cluster", clusterName)
-			}
-
-			// Create the DaemonSet in Karmada
-			daemonSetGVR := schema.GroupVersionResource{
-				Group:    "apps",
-				Version:  "v1",
-				Resource: "daemonsets",
-			}
-
-			_, err = karmadaDynamicClient.Resource(daemonSetGVR).Namespace(obj.GetNamespace()).Create(context.TODO(), obj, metav1.CreateOptions{})
-			if err != nil && !strings.Contains(err.Error(), "already exists") {
-				return fmt.Errorf("failed to create DaemonSet %s in Karmada: %v", newName, err)
-			}
-		}
-	}
-
-	return nil
-}
-
 func applyYAMLManifest(yamlContent []byte, namespace string) error {
 	// Decode YAML into unstructured objects
 	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(string(yamlContent)), 4096)
@@ -1035,7 +727,7 @@ func installMigrationController(clusterName, version string) error {
 		// Install MigrationBackup controller on management cluster
 
 		// 1. Apply StatefulMigration CRD
-		crdYAML, err := fetchYAMLFromURL("https://raw.githubusercontent.com/lehuannhatrang/stateful-migration-operator/main/config/crd/bases/migration.dcnlab.com_statefulmigrations.yaml")
+		crdYAML, err := fetchYAMLFromURL(config.ManifestURL(statefulMigrationCRDPath))
 		if err != nil {
 			return fmt.Errorf("failed to fetch StatefulMigration CRD: %v", err)
 		}
@@ -1045,7 +737,7 @@ func installMigrationController(clusterName, version string) error {
 		}
 
 		// 2. Apply RBAC
-		rbacYAML, err := fetchYAMLFromURL("https://raw.githubusercontent.com/lehuannhatrang/stateful-migration-operator/main/config/rbac/migration_backup_rbac.yaml")
+		rbacYAML, err := fetchYAMLFromURL(config.ManifestURL("config/rbac/migration_backup_rbac.yaml"))
 		if err != nil {
 			return fmt.Errorf("failed to fetch migration backup RBAC: %v", err)
 		}
@@ -1055,7 +747,7 @@ func installMigrationController(clusterName, version string) error {
 		}
 
 		// 3. Apply deployment
-		deploymentYAML, err := fetchYAMLFromURL("https://raw.githubusercontent.com/lehuannhatrang/stateful-migration-operator/main/deploy/migration-backup-controller.yaml")
+		deploymentYAML, err := fetchYAMLFromURL(config.ManifestURL("deploy/migration-backup-controller.yaml"))
 		if err != nil {
 			return fmt.Errorf("failed to fetch migration backup deployment: %v", err)
 		}
@@ -1086,100 +778,194 @@ func installMigrationController(clusterName, version string) error {
 	} else {
 		// Install CheckpointBackup controller on member cluster using Karmada propagation
 
-		// 1. Apply checkpoint backup RBAC to Karmada with cluster-specific names
-		rbacYAML, err := fetchYAMLFromURL("https://raw.githubusercontent.com/lehuannhatrang/stateful-migration-operator/main/config/rbac/checkpoint_backup_rbac.yaml")
-		if err != nil {
-			return fmt.Errorf("failed to fetch checkpoint backup RBAC: %v", err)
+		// 1. Apply the checkpoint backup bundle to Karmada with cluster-specific names
+		if err := installBundleForCluster(context.TODO(), checkpointBackupBundle, clusterName, version); err != nil {
+			return fmt.Errorf("failed to apply checkpoint backup controller to Karmada: %v", err)
 		}
-		err = applyYAMLManifestToKarmadaWithCluster(rbacYAML, "stateful-migration", clusterName)
-		if err != nil {
-			return fmt.Errorf("failed to apply checkpoint backup RBAC to Karmada: %v", err)
+
+		// 2. Create the propagation policies for the namespaced and cluster-scoped resources
+		if _, err := ensureCheckpointBackupPolicies(clusterName); err != nil {
+			return err
 		}
+	}
 
-		// 2. Fetch checkpoint backup DaemonSet YAML and modify it for cluster-specific naming
-		daemonsetYAML, err := fetchYAMLFromURL("https://raw.githubusercontent.com/lehuannhatrang/stateful-migration-operator/main/deploy/checkpoint-backup-daemonset.yaml")
-		if err != nil {
-			return fmt.Errorf("failed to fetch checkpoint backup DaemonSet: %v", err)
+	// The controller starts with the global throttle, keeping the settings a previous installation set for the cluster
+	if throttle := migration.GlobalThrottle(); !throttle.IsZero() {
+		if err := applyGlobalThrottle(context.TODO(), clusterName, throttle); err != nil {
+			return fmt.Errorf("failed to apply checkpoint throttle: %v", err)
 		}
+	}
 
-		// 3. Parse and modify the DaemonSet YAML to be cluster-specific
-		err = applyModifiedDaemonSetToKarmada(daemonsetYAML, clusterName, version)
-		if err != nil {
-			return fmt.Errorf("failed to apply checkpoint backup DaemonSet to Karmada: %v", err)
+	klog.InfoS("Migration controller installation completed", "cluster", clusterName)
+	return nil
+}
+
+// installMigrationControllerForSelector installs the checkpoint backup controller once for all the member
+// clusters with the labels, so clusters that get the labels later receive it as well
+func installMigrationControllerForSelector(matchLabels map[string]string, version string) error {
+	selectorName := clusterresource.SelectorName(matchLabels)
+	if err := installBundleForSelector(context.TODO(), checkpointBackupBundle, selectorName, version); err != nil {
+		return fmt.Errorf("failed to apply checkpoint backup controller to Karmada: %v", err)
+	}
+	if _, err := ensureCheckpointBackupSelectorPolicies(matchLabels); err != nil {
+		return err
+	}
+	klog.InfoS("Migration controller installation completed", "selector", matchLabels)
+	return nil
+}
+
+// uninstallMigrationControllerForSelector removes the checkpoint backup controller of a cluster label selector.
+// Karmada removes it from the member clusters along with the resources.
+func uninstallMigrationControllerForSelector(matchLabels map[string]string) error {
+	ctx := context.TODO()
+	selectorName := clusterresource.SelectorName(matchLabels)
+	karmadaClient := client.InClusterKarmadaClient()
+
+	err := karmadaClient.PolicyV1alpha1().PropagationPolicies("stateful-migration").Delete(ctx, fmt.Sprintf("checkpoint-backup-%s", selectorName), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete checkpoint-backup PropagationPolicy: %v", err)
+	}
+	err = karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().Delete(ctx, fmt.Sprintf("checkpoint-backup-cluster-rbac-%s", selectorName), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete checkpoint-backup-cluster-rbac ClusterPropagationPolicy: %v", err)
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return err
+	}
+	resources := []struct {
+		gvr       schema.GroupVersionResource
+		namespace string
+	}{
+		{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, "stateful-migration"},
+		{schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, "stateful-migration"},
+		{schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, "stateful-migration"},
+		{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, ""},
+		{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, ""},
+	}
+	listOptions := metav1.ListOptions{LabelSelector: migration.ControllerSelectorLabel + "=" + selectorName}
+	for _, resource := range resources {
+		err := karmadaDynamicClient.Resource(resource.gvr).Namespace(resource.namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, listOptions)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete checkpoint backup %s from Karmada: %v", resource.gvr.Resource, err)
 		}
+	}
 
-		// 4. Create PropagationPolicy for namespaced resources (DaemonSet, ServiceAccount)
-		clusterSpecificDaemonSetName := fmt.Sprintf("checkpoint-backup-controller-%s", clusterName)
-		clusterSpecificServiceAccountName := fmt.Sprintf("checkpoint-backup-sa-%s", clusterName)
-		propagationPolicy := &policyv1alpha1.PropagationPolicy{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("checkpoint-backup-%s", clusterName),
-				Namespace: "stateful-migration",
-			},
-			Spec: policyv1alpha1.PropagationSpec{
-				ResourceSelectors: []policyv1alpha1.ResourceSelector{
-					{
-						APIVersion: "apps/v1",
-						Kind:       "DaemonSet",
-						Name:       clusterSpecificDaemonSetName,
-					},
-					{
-						APIVersion: "v1",
-						Kind:       "ServiceAccount",
-						Name:       clusterSpecificServiceAccountName,
-					},
+	klog.InfoS("Migration controller uninstallation completed", "selector", matchLabels)
+	return nil
+}
+
+// ensureCheckpointBackupPolicies creates the propagation policies of the checkpoint backup controller
+// of a member cluster and returns the ones that were missing
+func ensureCheckpointBackupPolicies(clusterName string) ([]string, error) {
+	affinity := &policyv1alpha1.ClusterAffinity{ClusterNames: []string{clusterName}}
+	return createCheckpointBackupPolicies(clusterName, orphan.Labels(clusterName), affinity)
+}
+
+// ensureCheckpointBackupSelectorPolicies creates the propagation policies of the checkpoint backup controller
+// of a cluster label selector. Karmada propagates the controller to the clusters that get the labels later.
+func ensureCheckpointBackupSelectorPolicies(matchLabels map[string]string) ([]string, error) {
+	selectorName := clusterresource.SelectorName(matchLabels)
+	affinity := &policyv1alpha1.ClusterAffinity{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: matchLabels},
+	}
+	return createCheckpointBackupPolicies(selectorName, selectorResourceLabels(selectorName), affinity)
+}
+
+// createCheckpointBackupPolicies creates the propagation policies of the checkpoint backup resources
+// with the suffix and returns the ones that were missing
+func createCheckpointBackupPolicies(suffix string, policyLabels map[string]string, affinity *policyv1alpha1.ClusterAffinity) ([]string, error) {
+	// PropagationPolicy for namespaced resources (DaemonSet, ServiceAccount)
+	clusterSpecificDaemonSetName := clusterResourceName("checkpoint-backup-controller", suffix)
+	clusterSpecificServiceAccountName := clusterResourceName("checkpoint-backup-sa", suffix)
+	propagationPolicy := &policyv1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("checkpoint-backup-%s", suffix),
+			Namespace: "stateful-migration",
+			Labels:    policyLabels,
+		},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: []policyv1alpha1.ResourceSelector{
+				{
+					APIVersion: "apps/v1",
+					Kind:       "DaemonSet",
+					Name:       clusterSpecificDaemonSetName,
+				},
+				{
+					APIVersion: "v1",
+					Kind:       "ServiceAccount",
+					Name:       clusterSpecificServiceAccountName,
 				},
-				Placement: policyv1alpha1.Placement{
-					ClusterAffinity: &policyv1alpha1.ClusterAffinity{
-						ClusterNames: []string{clusterName},
-					},
+				{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       checkpointBackupConfigMapName(suffix),
 				},
 			},
-		}
-
-		// 5. Create ClusterPropagationPolicy for cluster-scoped resources (ClusterRole, ClusterRoleBinding)
-		clusterPropagationPolicy := &policyv1alpha1.ClusterPropagationPolicy{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: fmt.Sprintf("checkpoint-backup-cluster-rbac-%s", clusterName),
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: affinity,
 			},
-			Spec: policyv1alpha1.PropagationSpec{
-				ResourceSelectors: []policyv1alpha1.ResourceSelector{
-					{
-						APIVersion: "rbac.authorization.k8s.io/v1",
-						Kind:       "ClusterRole",
-						Name:       "checkpoint-backup-role",
-					},
-					{
-						APIVersion: "rbac.authorization.k8s.io/v1",
-						Kind:       "ClusterRoleBinding",
-						Name:       "checkpoint-backup-rolebinding",
-					},
+		},
+	}
+
+	// ClusterPropagationPolicy for cluster-scoped resources (ClusterRole, ClusterRoleBinding)
+	clusterPropagationPolicy := &policyv1alpha1.ClusterPropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("checkpoint-backup-cluster-rbac-%s", suffix),
+			Labels: policyLabels,
+		},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: []policyv1alpha1.ResourceSelector{
+				{
+					APIVersion: "rbac.authorization.k8s.io/v1",
+					Kind:       "ClusterRole",
+					Name:       clusterResourceName("checkpoint-backup-role", suffix),
 				},
-				Placement: policyv1alpha1.Placement{
-					ClusterAffinity: &policyv1alpha1.ClusterAffinity{
-						ClusterNames: []string{clusterName},
-					},
+				{
+					APIVersion: "rbac.authorization.k8s.io/v1",
+					Kind:       "ClusterRoleBinding",
+					Name:       clusterResourceName("checkpoint-backup-rolebinding", suffix),
 				},
 			},
-		}
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: affinity,
+			},
+		},
+	}
 
-		karmadaClient := client.InClusterKarmadaClient()
+	karmadaClient := client.InClusterKarmadaClient()
+	var created []string
 
-		// Create PropagationPolicy for namespaced resources
-		_, err = karmadaClient.PolicyV1alpha1().PropagationPolicies("stateful-migration").Create(context.TODO(), propagationPolicy, metav1.CreateOptions{})
-		if err != nil && !strings.Contains(err.Error(), "already exists") {
-			return fmt.Errorf("failed to create propagation policy: %v", err)
-		}
+	// Create PropagationPolicy for namespaced resources
+	_, err := karmadaClient.PolicyV1alpha1().PropagationPolicies("stateful-migration").Create(context.TODO(), propagationPolicy, metav1.CreateOptions{})
+	if err != nil && !strings.Contains(err.Error(), "already exists") {
+		return created, fmt.Errorf("failed to create propagation policy: %v", err)
+	}
+	if err == nil {
+		created = append(created, "PropagationPolicy/"+propagationPolicy.Name)
+	}
 
-		// Create ClusterPropagationPolicy for cluster-scoped resources
-		_, err = karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().Create(context.TODO(), clusterPropagationPolicy, metav1.CreateOptions{})
-		if err != nil && !strings.Contains(err.Error(), "already exists") {
-			return fmt.Errorf("failed to create cluster propagation policy: %v", err)
-		}
+	// Create ClusterPropagationPolicy for cluster-scoped resources
+	_, err = karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().Create(context.TODO(), clusterPropagationPolicy, metav1.CreateOptions{})
+	if err != nil && !strings.Contains(err.Error(), "already exists") {
+		return created, fmt.Errorf("failed to create cluster propagation policy: %v", err)
 	}
+	if err == nil {
+		created = append(created, "ClusterPropagationPolicy/"+clusterPropagationPolicy.Name)
+	}
+	return created, nil
+}
 
-	klog.InfoS("Migration controller installation completed", "cluster", clusterName)
-	return nil
+// UninstallMigrationController removes the migration controller and its propagation policies from a cluster.
+// It waits for a running install or upgrade on the cluster to finish first.
+func UninstallMigrationController(clusterName string) error {
+	release, err := clusterOperations.begin(context.TODO(), clusterName, "uninstall", true)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return uninstallMigrationController(clusterName)
 }
 
 func uninstallMigrationController(clusterName string) error {
@@ -1222,6 +1008,11 @@ func uninstallMigrationController(clusterName string) error {
 			klog.ErrorS(err, "Failed to delete migration-backup-controller ServiceAccount")
 		}
 
+		err = k8sClient.CoreV1().ConfigMaps("stateful-migration").Delete(context.TODO(), "migration-backup-controller-config", metav1.DeleteOptions{})
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			klog.ErrorS(err, "Failed to delete migration-backup-controller-config ConfigMap")
+		}
+
 		// Delete StatefulMigration CRD (optional, as it might be used by other components)
 		dynamicClient, err := client.GetDynamicClient()
 		if err == nil {
@@ -1330,6 +1121,16 @@ func uninstallMigrationController(clusterName string) error {
 		if err != nil && !strings.Contains(err.Error(), "not found") {
 			klog.ErrorS(err, "Failed to delete cluster-specific ServiceAccount from Karmada", "cluster", clusterName)
 		}
+
+		configMapGVR := schema.GroupVersionResource{
+			Group:    "",
+			Version:  "v1",
+			Resource: "configmaps",
+		}
+		err = karmadaDynamicClient.Resource(configMapGVR).Namespace("stateful-migration").Delete(context.TODO(), checkpointBackupConfigMapName(clusterName), metav1.DeleteOptions{})
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			klog.ErrorS(err, "Failed to delete cluster-specific ConfigMap from Karmada", "cluster", clusterName)
+		}
 	}
 
 	klog.InfoS("Migration controller uninstallation completed", "cluster", clusterName)
@@ -1398,12 +1199,23 @@ func init() {
 
 	// Settings/cluster management routes
 	settingsGroup := r.Group("/backup/settings")
+	settingsGroup.Use(idempotencyMiddleware())
 	{
 		settingsGroup.GET("/clusters", handleGetClusters)
+		settingsGroup.GET("/controller-versions", handleGetControllerVersions)
+		settingsGroup.GET("/controller-operations", handleGetControllerOperations)
 		settingsGroup.GET("/clusters/:name", handleGetClusterDetail)
 		settingsGroup.POST("/clusters/install-controller", handleInstallController)
+		settingsGroup.POST("/clusters/upgrade-controller", handleUpgradeController)
 		settingsGroup.POST("/clusters/uninstall-controller", handleUninstallController)
 		settingsGroup.GET("/clusters/:name/controller-status", handleCheckControllerStatus)
 		settingsGroup.GET("/clusters/:name/controller-logs", handleGetControllerLogs)
+		settingsGroup.GET("/clusters/:name/remediation-history", handleGetRemediationHistory)
+		settingsGroup.GET("/clusters/:name/controller-config", handleGetControllerConfig)
+		settingsGroup.PUT("/clusters/:name/controller-config", handlePutControllerConfig)
+		settingsGroup.GET("/throttle", handleGetThrottle)
+		settingsGroup.PUT("/throttle", router.EnsureMgmtAdminMiddleware(), handlePutThrottle)
+		settingsGroup.GET("/image-rewrites", handleGetImageRewrites)
+		settingsGroup.PUT("/image-rewrites", router.EnsureMgmtAdminMiddleware(), handlePutImageRewrites)
 	}
 }
@@ -0,0 +1,543 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+)
+
+const (
+	// volumeSnapshotsAnnotation stores the VolumeSnapshotPolicy of a backup
+	volumeSnapshotsAnnotation = "backup.dcnlab.com/volume-snapshots"
+	// snapshotPVCAnnotation records the claim a VolumeSnapshot was taken of
+	snapshotPVCAnnotation = "backup.dcnlab.com/pvc"
+	// restoredVolumesAnnotation stores the volumes restored by a recovery
+	restoredVolumesAnnotation = "recovery.dcnlab.com/restored-volumes"
+)
+
+// snapshotResources are the VolumeSnapshot resources served by a member cluster
+type snapshotResources struct {
+	snapshot schema.GroupVersionResource
+	content  schema.GroupVersionResource
+}
+
+// volumeSnapshotResources returns the version of the VolumeSnapshot resources a member cluster serves,
+// and an error when the cluster does not have the snapshot CRDs installed
+func volumeSnapshotResources(ctx context.Context, clusterName string) (snapshotResources, error) {
+	snapshot, err := capability.ResourceFor(ctx, clusterName, capability.KindVolumeSnapshot)
+	if err != nil {
+		return snapshotResources{}, err
+	}
+	content, err := capability.ResourceFor(ctx, clusterName, capability.KindVolumeSnapshotContent)
+	if err != nil {
+		return snapshotResources{}, err
+	}
+	return snapshotResources{snapshot: snapshot, content: content}, nil
+}
+
+// VolumeSnapshotPolicy enables CSI snapshots of the persistent volumes of the backed up workload
+type VolumeSnapshotPolicy struct {
+	Enabled bool `json:"enabled"`
+	// VolumeSnapshotClass is the snapshot class to use, empty for the cluster default
+	VolumeSnapshotClass string `json:"volumeSnapshotClass,omitempty"`
+}
+
+// VolumeSnapshotInfo is a VolumeSnapshot taken for a backup
+type VolumeSnapshotInfo struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	PVCName       string `json:"pvcName"`
+	SnapshotClass string `json:"snapshotClass,omitempty"`
+	ContentName   string `json:"contentName,omitempty"`
+	RestoreSize   string `json:"restoreSize,omitempty"`
+	ReadyToUse    bool   `json:"readyToUse"`
+	Error         string `json:"error,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// RestoredVolume is a claim created from a volume snapshot during a recovery
+type RestoredVolume struct {
+	PVCName        string `json:"pvcName"`
+	Namespace      string `json:"namespace"`
+	SourcePVC      string `json:"sourcePvc"`
+	SourceSnapshot string `json:"sourceSnapshot"`
+	Error          string `json:"error,omitempty"`
+}
+
+// setVolumeSnapshotAnnotation stores the volume snapshot policy on the StatefulMigration
+func setVolumeSnapshotAnnotation(sm *unstructured.Unstructured, policy *VolumeSnapshotPolicy) {
+	if policy == nil {
+		return
+	}
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if !policy.Enabled {
+		delete(annotations, volumeSnapshotsAnnotation)
+	} else if data, err := json.Marshal(policy); err == nil {
+		annotations[volumeSnapshotsAnnotation] = string(data)
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// volumeSnapshotPolicyFromAnnotations reads the volume snapshot policy of a StatefulMigration, nil if disabled
+func volumeSnapshotPolicyFromAnnotations(sm *unstructured.Unstructured) *VolumeSnapshotPolicy {
+	data, ok := sm.GetAnnotations()[volumeSnapshotsAnnotation]
+	if !ok {
+		return nil
+	}
+	policy := &VolumeSnapshotPolicy{}
+	if err := json.Unmarshal([]byte(data), policy); err != nil || !policy.Enabled {
+		return nil
+	}
+	return policy
+}
+
+// workloadClaims returns the persistent volume claims mounted by the backed up pod or statefulset pods
+func workloadClaims(ctx context.Context, memberClient kubeclient.Interface, backup BackupConfiguration) ([]string, error) {
+	var pods []corev1.Pod
+	if strings.EqualFold(backup.ResourceType, "statefulset") {
+		sts, err := memberClient.AppsV1().StatefulSets(backup.Namespace).Get(ctx, backup.ResourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector of statefulset %s: %v", sts.Name, err)
+		}
+		list, err := memberClient.CoreV1().Pods(backup.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		pods = list.Items
+	} else {
+		pod, err := memberClient.CoreV1().Pods(backup.Namespace).Get(ctx, backup.ResourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		pods = []corev1.Pod{*pod}
+	}
+
+	seen := map[string]bool{}
+	var claims []string
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil || seen[volume.PersistentVolumeClaim.ClaimName] {
+				continue
+			}
+			seen[volume.PersistentVolumeClaim.ClaimName] = true
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	sort.Strings(claims)
+	return claims, nil
+}
+
+// snapshotBackupVolumes creates a VolumeSnapshot of every claim of the backed up workload in its cluster
+func snapshotBackupVolumes(c *gin.Context, backup BackupConfiguration, policy VolumeSnapshotPolicy) ([]VolumeSnapshotInfo, error) {
+	if err := client.CheckMemberClusterAccess(c, backup.Cluster); err != nil {
+		return nil, err
+	}
+	memberClient := client.InClusterClientForMemberCluster(backup.Cluster)
+	if memberClient == nil {
+		return nil, fmt.Errorf("failed to get client for cluster %s", backup.Cluster)
+	}
+	dynamicClient, err := client.GetDynamicClientForMember(c, backup.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client for cluster %s: %v", backup.Cluster, err)
+	}
+
+	resources, err := volumeSnapshotResources(c, backup.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := workloadClaims(c, memberClient, backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find volumes of %s %s/%s: %v", backup.ResourceType, backup.Namespace, backup.ResourceName, err)
+	}
+
+	now := time.Now()
+	snapshots := make([]VolumeSnapshotInfo, 0, len(claims))
+	for _, claim := range claims {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(resources.snapshot.GroupVersion().WithKind("VolumeSnapshot"))
+		snapshot.SetName(fmt.Sprintf("%s-%s-%d", claim, backup.ID, now.Unix()))
+		snapshot.SetNamespace(backup.Namespace)
+		snapshot.SetLabels(map[string]string{
+			"app":       "backup-volume-snapshot",
+			"backup-id": backup.ID,
+		})
+		snapshot.SetAnnotations(map[string]string{snapshotPVCAnnotation: claim})
+		spec := map[string]interface{}{
+			"source": map[string]interface{}{"persistentVolumeClaimName": claim},
+		}
+		if policy.VolumeSnapshotClass != "" {
+			spec["volumeSnapshotClassName"] = policy.VolumeSnapshotClass
+		}
+		snapshot.Object["spec"] = spec
+
+		created, err := dynamicClient.Resource(resources.snapshot).Namespace(backup.Namespace).Create(c, snapshot, metav1.CreateOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to create volume snapshot", "cluster", backup.Cluster, "pvc", claim)
+			snapshots = append(snapshots, VolumeSnapshotInfo{
+				Name:      snapshot.GetName(),
+				Namespace: backup.Namespace,
+				PVCName:   claim,
+				Error:     err.Error(),
+				CreatedAt: now.Format(time.RFC3339),
+			})
+			continue
+		}
+		snapshots = append(snapshots, volumeSnapshotToInfo(created))
+	}
+	return snapshots, nil
+}
+
+func volumeSnapshotToInfo(snapshot *unstructured.Unstructured) VolumeSnapshotInfo {
+	info := VolumeSnapshotInfo{
+		Name:      snapshot.GetName(),
+		Namespace: snapshot.GetNamespace(),
+		PVCName:   snapshot.GetAnnotations()[snapshotPVCAnnotation],
+		CreatedAt: snapshot.GetCreationTimestamp().Format(time.RFC3339),
+	}
+	if info.PVCName == "" {
+		info.PVCName, _, _ = unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	}
+	info.SnapshotClass, _, _ = unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	info.ContentName, _, _ = unstructured.NestedString(snapshot.Object, "status", "boundVolumeSnapshotContentName")
+	info.RestoreSize, _, _ = unstructured.NestedString(snapshot.Object, "status", "restoreSize")
+	info.ReadyToUse, _, _ = unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	info.Error, _, _ = unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	return info
+}
+
+// listBackupVolumeSnapshots returns the volume snapshots of a backup, newest first
+func listBackupVolumeSnapshots(ctx context.Context, dynamicClient dynamic.Interface, backup BackupConfiguration) ([]VolumeSnapshotInfo, error) {
+	resources, err := volumeSnapshotResources(ctx, backup.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(resources.snapshot).Namespace(backup.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("backup-id=%s", backup.ID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshots: %v", err)
+	}
+	snapshots := make([]VolumeSnapshotInfo, 0, len(list.Items))
+	for i := range list.Items {
+		snapshots = append(snapshots, volumeSnapshotToInfo(&list.Items[i]))
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt > snapshots[j].CreatedAt
+	})
+	return snapshots, nil
+}
+
+// latestReadySnapshots returns the newest ready snapshot of each claim
+func latestReadySnapshots(snapshots []VolumeSnapshotInfo) []VolumeSnapshotInfo {
+	seen := map[string]bool{}
+	var latest []VolumeSnapshotInfo
+	for _, snapshot := range snapshots {
+		if !snapshot.ReadyToUse || seen[snapshot.PVCName] {
+			continue
+		}
+		seen[snapshot.PVCName] = true
+		latest = append(latest, snapshot)
+	}
+	return latest
+}
+
+// restoreVolumeSnapshots creates the claims of the backed up workload from its latest snapshots in the target
+// namespace. Within the source namespace the snapshot is used directly; elsewhere the snapshot is imported
+// as a pre-provisioned VolumeSnapshotContent, which requires the target cluster to reach the same storage.
+func restoreVolumeSnapshots(c *gin.Context, backup BackupConfiguration, targetCluster, targetName, targetNamespace string) ([]RestoredVolume, error) {
+	sourceDynamic, err := client.GetDynamicClientForMember(c, backup.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client for cluster %s: %v", backup.Cluster, err)
+	}
+	targetDynamic, err := client.GetDynamicClientForMember(c, targetCluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client for cluster %s: %v", targetCluster, err)
+	}
+	sourceClient := client.InClusterClientForMemberCluster(backup.Cluster)
+	targetClient := client.InClusterClientForMemberCluster(targetCluster)
+	if sourceClient == nil || targetClient == nil {
+		return nil, fmt.Errorf("failed to get clients for clusters %s and %s", backup.Cluster, targetCluster)
+	}
+
+	snapshots, err := listBackupVolumeSnapshots(c, sourceDynamic, backup)
+	if err != nil {
+		return nil, err
+	}
+	sourceResources, err := volumeSnapshotResources(c, backup.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	targetResources, err := volumeSnapshotResources(c, targetCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	sameNamespace := targetCluster == backup.Cluster && targetNamespace == backup.Namespace
+	rename := recoveryRename(backup, targetName, targetNamespace)
+	var restored []RestoredVolume
+	for _, snapshot := range latestReadySnapshots(snapshots) {
+		volume := RestoredVolume{
+			// StatefulSet claims are named <template>-<statefulset>-<ordinal>, so they follow a renamed target
+			PVCName:        rename.Name(snapshot.PVCName),
+			Namespace:      targetNamespace,
+			SourcePVC:      snapshot.PVCName,
+			SourceSnapshot: snapshot.Name,
+		}
+		if sameNamespace && volume.PVCName == snapshot.PVCName {
+			volume.Error = "claim already exists in the target namespace"
+			restored = append(restored, volume)
+			continue
+		}
+
+		snapshotName := snapshot.Name
+		if !sameNamespace {
+			snapshotName, err = importVolumeSnapshot(c, sourceDynamic, targetDynamic, sourceResources, targetResources, snapshot, targetNamespace)
+			if err != nil {
+				volume.Error = err.Error()
+				restored = append(restored, volume)
+				continue
+			}
+		}
+		if err := createClaimFromSnapshot(c, sourceClient, targetClient, targetResources, snapshot, snapshotName, volume); err != nil {
+			volume.Error = err.Error()
+		}
+		restored = append(restored, volume)
+	}
+	return restored, nil
+}
+
+// importVolumeSnapshot makes a snapshot available in the target namespace through a pre-provisioned
+// VolumeSnapshotContent that points at the same storage snapshot
+func importVolumeSnapshot(ctx context.Context, sourceDynamic, targetDynamic dynamic.Interface, sourceResources, targetResources snapshotResources, snapshot VolumeSnapshotInfo, targetNamespace string) (string, error) {
+	content, err := sourceDynamic.Resource(sourceResources.content).Get(ctx, snapshot.ContentName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get snapshot content %s: %v", snapshot.ContentName, err)
+	}
+	snapshotHandle, _, _ := unstructured.NestedString(content.Object, "status", "snapshotHandle")
+	driver, _, _ := unstructured.NestedString(content.Object, "spec", "driver")
+	if snapshotHandle == "" || driver == "" {
+		return "", fmt.Errorf("snapshot content %s has no snapshot handle", snapshot.ContentName)
+	}
+
+	name := fmt.Sprintf("restored-%s", snapshot.Name)
+	contentName := fmt.Sprintf("restored-%s-%s", targetNamespace, snapshot.Name)
+
+	imported := &unstructured.Unstructured{}
+	imported.SetGroupVersionKind(targetResources.content.GroupVersion().WithKind("VolumeSnapshotContent"))
+	imported.SetName(contentName)
+	imported.SetLabels(map[string]string{"app": "backup-volume-snapshot"})
+	imported.Object["spec"] = map[string]interface{}{
+		// The storage snapshot belongs to the source backup and must outlive the restore
+		"deletionPolicy": "Retain",
+		"driver":         driver,
+		"source":         map[string]interface{}{"snapshotHandle": snapshotHandle},
+		"volumeSnapshotRef": map[string]interface{}{
+			"name":      name,
+			"namespace": targetNamespace,
+		},
+	}
+	if snapshot.SnapshotClass != "" {
+		unstructured.SetNestedField(imported.Object, snapshot.SnapshotClass, "spec", "volumeSnapshotClassName")
+	}
+	if _, err := targetDynamic.Resource(targetResources.content).Create(ctx, imported, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create snapshot content %s: %v", contentName, err)
+	}
+
+	volumeSnapshot := &unstructured.Unstructured{}
+	volumeSnapshot.SetGroupVersionKind(targetResources.snapshot.GroupVersion().WithKind("VolumeSnapshot"))
+	volumeSnapshot.SetName(name)
+	volumeSnapshot.SetNamespace(targetNamespace)
+	volumeSnapshot.SetLabels(map[string]string{"app": "backup-volume-snapshot"})
+	volumeSnapshot.SetAnnotations(map[string]string{snapshotPVCAnnotation: snapshot.PVCName})
+	volumeSnapshot.Object["spec"] = map[string]interface{}{
+		"source": map[string]interface{}{"volumeSnapshotContentName": contentName},
+	}
+	if _, err := targetDynamic.Resource(targetResources.snapshot).Namespace(targetNamespace).Create(ctx, volumeSnapshot, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create volume snapshot %s: %v", name, err)
+	}
+	return name, nil
+}
+
+// createClaimFromSnapshot creates the restored claim with the access modes, class and size of the source claim
+func createClaimFromSnapshot(ctx context.Context, sourceClient, targetClient kubeclient.Interface, targetResources snapshotResources, snapshot VolumeSnapshotInfo, snapshotName string, volume RestoredVolume) error {
+	source, err := sourceClient.CoreV1().PersistentVolumeClaims(snapshot.Namespace).Get(ctx, snapshot.PVCName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get source claim %s: %v", snapshot.PVCName, err)
+	}
+	requests := source.Spec.Resources.Requests.DeepCopy()
+	if size, err := resource.ParseQuantity(snapshot.RestoreSize); err == nil && size.Cmp(requests[corev1.ResourceStorage]) > 0 {
+		requests[corev1.ResourceStorage] = size
+	}
+
+	apiGroup := targetResources.snapshot.Group
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      volume.PVCName,
+			Namespace: volume.Namespace,
+			Labels:    source.Labels,
+			Annotations: map[string]string{
+				snapshotPVCAnnotation: snapshot.PVCName,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      source.Spec.AccessModes,
+			StorageClassName: source.Spec.StorageClassName,
+			VolumeMode:       source.Spec.VolumeMode,
+			Resources:        corev1.VolumeResourceRequirements{Requests: requests},
+			DataSource: &corev1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VolumeSnapshot",
+				Name:     snapshotName,
+			},
+		},
+	}
+	if _, err := targetClient.CoreV1().PersistentVolumeClaims(volume.Namespace).Create(ctx, claim, metav1.CreateOptions{}); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("claim already exists in the target namespace")
+		}
+		return fmt.Errorf("failed to create claim %s: %v", volume.PVCName, err)
+	}
+	return nil
+}
+
+// restoredVolumesFromAnnotations reads the volumes restored by a recovery
+func restoredVolumesFromAnnotations(sm *unstructured.Unstructured) []RestoredVolume {
+	var volumes []RestoredVolume
+	if data, ok := sm.GetAnnotations()[restoredVolumesAnnotation]; ok {
+		_ = json.Unmarshal([]byte(data), &volumes)
+	}
+	return volumes
+}
+
+// handleGetBackupVolumeSnapshots lists the volume snapshots taken for a backup
+func handleGetBackupVolumeSnapshots(c *gin.Context) {
+	backup, err := getBackupByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	dynamicClient, err := client.GetDynamicClientForMember(c, backup.Cluster)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	snapshots, err := listBackupVolumeSnapshots(c, dynamicClient, backup)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list volume snapshots", "backupID", backup.ID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, map[string]interface{}{
+		"enabled":   backup.VolumeSnapshots != nil,
+		"snapshots": snapshots,
+		"total":     len(snapshots),
+	})
+}
+
+// handleCreateBackupVolumeSnapshots snapshots the volumes of a backup now, even if snapshots are not enabled
+func handleCreateBackupVolumeSnapshots(c *gin.Context) {
+	backup, err := getBackupByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	policy := VolumeSnapshotPolicy{Enabled: true}
+	if backup.VolumeSnapshots != nil {
+		policy = *backup.VolumeSnapshots
+	}
+	snapshots, err := snapshotBackupVolumes(c, backup, policy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to snapshot backup volumes", "backupID", backup.ID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, map[string]interface{}{
+		"snapshots": snapshots,
+		"total":     len(snapshots),
+	})
+}
+
+// restoreRecoveryVolumes restores the volume snapshots of the recovered backup on the target cluster
+// and records the restored claims on the recovery StatefulMigration
+func restoreRecoveryVolumes(c *gin.Context, sm *unstructured.Unstructured) error {
+	rm, err := decodeRecoveryMigration(sm)
+	if err != nil {
+		return err
+	}
+	backup, err := getBackupByID(rm.Spec.BackupID)
+	if err != nil {
+		return fmt.Errorf("failed to get backup %s: %v", rm.Spec.BackupID, err)
+	}
+	if backup.VolumeSnapshots == nil {
+		return nil
+	}
+
+	targetName := rm.Spec.TargetName
+	if targetName == "" {
+		targetName = backup.ResourceName
+	}
+	targetNamespace := rm.Spec.TargetNamespace
+	if targetNamespace == "" {
+		targetNamespace = backup.Namespace
+	}
+	volumes, err := restoreVolumeSnapshots(c, backup, rm.Spec.TargetCluster, targetName, targetNamespace)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(volumes)
+	if err != nil {
+		return err
+	}
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[restoredVolumesAnnotation] = string(data)
+	sm.SetAnnotations(annotations)
+	return nil
+}
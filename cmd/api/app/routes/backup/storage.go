@@ -0,0 +1,620 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/externalsecret"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
+)
+
+// Storage backend types supported in addition to the image registry
+const (
+	StorageTypeS3    = "s3"
+	StorageTypeMinIO = "minio"
+	StorageTypePVC   = "pvc"
+)
+
+const (
+	storageSecretPrefix = "backup-storage"
+	// storageBackendAnnotation records the storage backend selected for a StatefulMigration
+	storageBackendAnnotation = "backup.dcnlab.com/storage-backend-id"
+)
+
+// StorageBackend represents a checkpoint storage backend configuration
+type StorageBackend struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Type            string `json:"type"` // "s3", "minio" or "pvc"
+	Endpoint        string `json:"endpoint,omitempty"`
+	Bucket          string `json:"bucket,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Insecure        bool   `json:"insecure,omitempty"`
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	ClaimName       string `json:"claimName,omitempty"`
+	PathPrefix      string `json:"pathPrefix,omitempty"`
+	Description     string `json:"description"`
+	CreatedAt       string `json:"createdAt"`
+	UpdatedAt       string `json:"updatedAt"`
+	SecretName      string `json:"secretName"`
+	SecretNamespace string `json:"secretNamespace"`
+	// ExternalSecret names the ExternalSecret filling the secret access key from an external store
+	ExternalSecret string `json:"externalSecret,omitempty"`
+}
+
+// StorageBackendInfo represents storage backend information for backup
+type StorageBackendInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// CreateStorageBackendRequest represents the request to create a new storage backend
+type CreateStorageBackendRequest struct {
+	Name            string `json:"name" binding:"required"`
+	Type            string `json:"type" binding:"required,oneof=s3 minio pvc"`
+	Endpoint        string `json:"endpoint"`
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	Insecure        bool   `json:"insecure"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	ClaimName       string `json:"claimName"`
+	PathPrefix      string `json:"pathPrefix"`
+	Description     string `json:"description"`
+	// SecretAccessKeyRef reads the secret access key from the external store instead
+	SecretAccessKeyRef *externalsecret.RemoteRef `json:"secretAccessKeyRef,omitempty"`
+}
+
+// UpdateStorageBackendRequest represents the request to update a storage backend
+type UpdateStorageBackendRequest struct {
+	Name            string `json:"name"`
+	Endpoint        string `json:"endpoint"`
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	Insecure        *bool  `json:"insecure"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	ClaimName       string `json:"claimName"`
+	PathPrefix      string `json:"pathPrefix"`
+	Description     string `json:"description"`
+	// SecretAccessKeyRef reads the secret access key from the external store instead
+	SecretAccessKeyRef *externalsecret.RemoteRef `json:"secretAccessKeyRef,omitempty"`
+}
+
+var storageSecretGVR = schema.GroupVersionResource{
+	Group:    "",
+	Version:  "v1",
+	Resource: "secrets",
+}
+
+// validateStorageBackend checks that the fields required by the backend type are set
+func validateStorageBackend(backend StorageBackend) error {
+	switch backend.Type {
+	case StorageTypeS3, StorageTypeMinIO:
+		if backend.Bucket == "" {
+			return fmt.Errorf("bucket is required for %s storage", backend.Type)
+		}
+		if backend.Type == StorageTypeMinIO && backend.Endpoint == "" {
+			return fmt.Errorf("endpoint is required for minio storage")
+		}
+		if backend.AccessKeyID == "" || (backend.SecretAccessKey == "" && backend.ExternalSecret == "") {
+			return fmt.Errorf("accessKeyId and secretAccessKey are required for %s storage", backend.Type)
+		}
+	case StorageTypePVC:
+		if backend.ClaimName == "" {
+			return fmt.Errorf("claimName is required for pvc storage")
+		}
+	default:
+		return fmt.Errorf("unsupported storage type: %s", backend.Type)
+	}
+	return nil
+}
+
+// handleGetStorageBackends retrieves all storage backend configurations
+func handleGetStorageBackends(c *gin.Context) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		common.Fail(c, err)
+		return
+	}
+
+	secretsUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: "app=backup-storage",
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list storage backend secrets from Karmada")
+		common.Fail(c, err)
+		return
+	}
+
+	backends := make([]StorageBackend, 0, len(secretsUnstructured.Items))
+	for _, secretUnstructured := range secretsUnstructured.Items {
+		secret := &corev1.Secret{}
+		if err := convertUnstructuredToTyped(&secretUnstructured, secret); err != nil {
+			klog.ErrorS(err, "Failed to convert secret", "secretName", secretUnstructured.GetName())
+			continue
+		}
+		backends = append(backends, secretToStorageBackend(secret))
+	}
+
+	common.Success(c, map[string]interface{}{
+		"storageBackends": backends,
+		"total":           len(backends),
+	})
+}
+
+// handleGetStorageBackend retrieves a specific storage backend configuration
+func handleGetStorageBackend(c *gin.Context) {
+	backend, err := getStorageBackendByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get storage backend", "storageID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, backend)
+}
+
+// handleCreateStorageBackend creates a new storage backend configuration
+func handleCreateStorageBackend(c *gin.Context) {
+	var req CreateStorageBackendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind storage backend request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	backend := StorageBackend{
+		Name:            req.Name,
+		Type:            req.Type,
+		Endpoint:        strings.TrimRight(req.Endpoint, "/"),
+		Bucket:          req.Bucket,
+		Region:          req.Region,
+		Insecure:        req.Insecure,
+		AccessKeyID:     req.AccessKeyID,
+		SecretAccessKey: req.SecretAccessKey,
+		ClaimName:       req.ClaimName,
+		PathPrefix:      req.PathPrefix,
+		Description:     req.Description,
+	}
+	storageID := generateRegistryID(strings.ToLower(strings.ReplaceAll(req.Name, " ", "-")))
+	secretName := fmt.Sprintf("%s-%s", storageSecretPrefix, storageID)
+	if req.SecretAccessKeyRef != nil {
+		if backend.Type == StorageTypePVC {
+			common.Fail(c, fmt.Errorf("secretAccessKeyRef cannot be set for pvc storage"))
+			return
+		}
+		backend.ExternalSecret = secretName
+	}
+	if err := validateStorageBackend(backend); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		common.Fail(c, err)
+		return
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: registryNamespace,
+			Labels: map[string]string{
+				"app":          "backup-storage",
+				"storage-id":   storageID,
+				"storage-type": req.Type,
+			},
+			Annotations: map[string]string{
+				"backup.dcnlab.com/created-at": metav1.Now().Format(time.RFC3339),
+			},
+		},
+		Data: storageBackendToSecretData(backend),
+		Type: corev1.SecretTypeOpaque,
+	}
+	if req.SecretAccessKeyRef != nil {
+		refs := map[string]externalsecret.RemoteRef{"secretAccessKey": *req.SecretAccessKeyRef}
+		if err := applyBackupExternalSecret(c, secret, refs); err != nil {
+			klog.ErrorS(err, "Failed to create ExternalSecret for storage backend", "secretName", secretName)
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	secretUnstructured, err := convertSecretToUnstructured(secret)
+	if err != nil {
+		klog.ErrorS(err, "Failed to convert secret to unstructured")
+		common.Fail(c, err)
+		return
+	}
+
+	_, err = karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Create(context.TODO(), secretUnstructured, metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to create storage backend secret in Karmada")
+		if req.SecretAccessKeyRef != nil {
+			deleteBackupExternalSecret(context.TODO(), secretName)
+		}
+		common.Fail(c, err)
+		return
+	}
+
+	// Object storage credentials are needed by the checkpoint controllers on member clusters
+	if backend.Type != StorageTypePVC {
+		if err := propagateStorageSecret(storageID, secretName, registryNamespace); err != nil {
+			klog.ErrorS(err, "Failed to propagate storage backend secret", "secretName", secretName)
+		}
+	}
+
+	common.Success(c, secretToStorageBackend(secret))
+}
+
+// handleUpdateStorageBackend updates an existing storage backend configuration
+func handleUpdateStorageBackend(c *gin.Context) {
+	storageID := c.Param("id")
+	var req UpdateStorageBackendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind storage backend update request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		common.Fail(c, err)
+		return
+	}
+
+	secretName := fmt.Sprintf("%s-%s", storageSecretPrefix, storageID)
+	secretUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to get storage backend secret for update from Karmada", "storageID", storageID)
+		common.Fail(c, err)
+		return
+	}
+
+	secret := &corev1.Secret{}
+	if err := convertUnstructuredToTyped(secretUnstructured, secret); err != nil {
+		klog.ErrorS(err, "Failed to convert secret", "secretName", secretName)
+		common.Fail(c, err)
+		return
+	}
+
+	backend := secretToStorageBackend(secret)
+	backend.SecretAccessKey = string(secret.Data["secretAccessKey"])
+	if req.Name != "" {
+		backend.Name = req.Name
+	}
+	if req.Endpoint != "" {
+		backend.Endpoint = strings.TrimRight(req.Endpoint, "/")
+	}
+	if req.Bucket != "" {
+		backend.Bucket = req.Bucket
+	}
+	if req.Region != "" {
+		backend.Region = req.Region
+	}
+	if req.Insecure != nil {
+		backend.Insecure = *req.Insecure
+	}
+	if req.AccessKeyID != "" {
+		backend.AccessKeyID = req.AccessKeyID
+	}
+	if req.SecretAccessKeyRef != nil {
+		backend.ExternalSecret = secret.Name
+	} else if req.SecretAccessKey != "" {
+		backend.SecretAccessKey = req.SecretAccessKey
+		backend.ExternalSecret = ""
+	}
+	if req.ClaimName != "" {
+		backend.ClaimName = req.ClaimName
+	}
+	if req.PathPrefix != "" {
+		backend.PathPrefix = req.PathPrefix
+	}
+	if req.Description != "" {
+		backend.Description = req.Description
+	}
+	if err := validateStorageBackend(backend); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	secret.Data = storageBackendToSecretData(backend)
+	if req.SecretAccessKeyRef != nil {
+		refs := map[string]externalsecret.RemoteRef{"secretAccessKey": *req.SecretAccessKeyRef}
+		if err := applyBackupExternalSecret(c, secret, refs); err != nil {
+			klog.ErrorS(err, "Failed to apply ExternalSecret for storage backend", "secretName", secretName)
+			common.Fail(c, err)
+			return
+		}
+	} else if req.SecretAccessKey != "" {
+		if err := removeBackupExternalSecret(c, secret); err != nil {
+			klog.ErrorS(err, "Failed to remove ExternalSecret of storage backend", "secretName", secretName)
+			common.Fail(c, err)
+			return
+		}
+	} else if backend.ExternalSecret != "" {
+		delete(secret.Data, "secretAccessKey")
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations["backup.dcnlab.com/updated-at"] = metav1.Now().Format(time.RFC3339)
+
+	updatedSecretUnstructured, err := convertSecretToUnstructured(secret)
+	if err != nil {
+		klog.ErrorS(err, "Failed to convert updated secret to unstructured")
+		common.Fail(c, err)
+		return
+	}
+	updatedSecretUnstructured.SetResourceVersion(secretUnstructured.GetResourceVersion())
+
+	_, err = karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Update(context.TODO(), updatedSecretUnstructured, metav1.UpdateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to update storage backend secret in Karmada")
+		common.Fail(c, err)
+		return
+	}
+
+	common.Success(c, secretToStorageBackend(secret))
+}
+
+// handleDeleteStorageBackend deletes a storage backend configuration
+func handleDeleteStorageBackend(c *gin.Context) {
+	storageID := c.Param("id")
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get Karmada dynamic client")
+		common.Fail(c, err)
+		return
+	}
+
+	secretName := fmt.Sprintf("%s-%s", storageSecretPrefix, storageID)
+	err = karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to delete storage backend secret from Karmada", "storageID", storageID)
+		common.Fail(c, err)
+		return
+	}
+
+	deleteBackupExternalSecret(context.TODO(), secretName)
+
+	karmadaClient := client.InClusterKarmadaClient()
+	err = karmadaClient.PolicyV1alpha1().PropagationPolicies(registryNamespace).Delete(context.TODO(), secretName, metav1.DeleteOptions{})
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		klog.ErrorS(err, "Failed to delete PropagationPolicy for storage backend", "storageID", storageID)
+	}
+
+	common.Success(c, ActionResult{Message: "Storage backend deleted successfully"})
+}
+
+// storageBackendToSecretData converts a StorageBackend into secret data
+func storageBackendToSecretData(backend StorageBackend) map[string][]byte {
+	insecure := "false"
+	if backend.Insecure {
+		insecure = "true"
+	}
+	return map[string][]byte{
+		"name":            []byte(backend.Name),
+		"type":            []byte(backend.Type),
+		"endpoint":        []byte(backend.Endpoint),
+		"bucket":          []byte(backend.Bucket),
+		"region":          []byte(backend.Region),
+		"insecure":        []byte(insecure),
+		"accessKeyId":     []byte(backend.AccessKeyID),
+		"secretAccessKey": []byte(backend.SecretAccessKey),
+		"claimName":       []byte(backend.ClaimName),
+		"pathPrefix":      []byte(backend.PathPrefix),
+		"description":     []byte(backend.Description),
+	}
+}
+
+// secretToStorageBackend converts a Kubernetes secret to a StorageBackend struct
+func secretToStorageBackend(secret *corev1.Secret) StorageBackend {
+	backend := StorageBackend{
+		ID:          secret.Labels["storage-id"],
+		Name:        string(secret.Data["name"]),
+		Type:        string(secret.Data["type"]),
+		Endpoint:    string(secret.Data["endpoint"]),
+		Bucket:      string(secret.Data["bucket"]),
+		Region:      string(secret.Data["region"]),
+		Insecure:    string(secret.Data["insecure"]) == "true",
+		AccessKeyID: string(secret.Data["accessKeyId"]),
+		// Don't expose secret access key in responses
+		ClaimName:       string(secret.Data["claimName"]),
+		PathPrefix:      string(secret.Data["pathPrefix"]),
+		Description:     string(secret.Data["description"]),
+		CreatedAt:       secret.Annotations["backup.dcnlab.com/created-at"],
+		UpdatedAt:       secret.Annotations["backup.dcnlab.com/updated-at"],
+		SecretName:      secret.Name,
+		SecretNamespace: secret.Namespace,
+		ExternalSecret:  externalsecret.Reference(secret),
+	}
+
+	if backend.CreatedAt == "" {
+		backend.CreatedAt = secret.CreationTimestamp.Format(time.RFC3339)
+	}
+	if backend.UpdatedAt == "" {
+		backend.UpdatedAt = backend.CreatedAt
+	}
+
+	return backend
+}
+
+// getStorageBackendByID returns the storage backend with the given ID
+func getStorageBackendByID(storageID string) (StorageBackend, error) {
+	return getStorageBackendBySecretName(fmt.Sprintf("%s-%s", storageSecretPrefix, storageID))
+}
+
+// UploadToStorageBackend stores a file in an S3 or MinIO storage backend below its path prefix and
+// returns the object key. It is used to export generated files such as reports.
+func UploadToStorageBackend(ctx context.Context, storageID, name, contentType string, content []byte) (string, error) {
+	backend, err := getStorageBackendByID(storageID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get storage backend %s: %v", storageID, err)
+	}
+	if backend.Type == StorageTypePVC {
+		return "", fmt.Errorf("storage backend %s is a pvc, files can only be uploaded to s3 or minio", backend.Name)
+	}
+	secretAccessKey, err := readBackupSecretValue(backend.SecretName, "secretAccessKey")
+	if err != nil {
+		return "", fmt.Errorf("failed to read storage credentials: %v", err)
+	}
+	store, err := newS3CheckpointStore(backend, secretAccessKey, backend.PathPrefix)
+	if err != nil {
+		return "", err
+	}
+	return store.Put(ctx, name, contentType, content)
+}
+
+// getStorageBackendBySecretName returns the storage backend stored in the given secret
+func getStorageBackendBySecretName(secretName string) (StorageBackend, error) {
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return StorageBackend{}, fmt.Errorf("failed to get Karmada dynamic client: %v", err)
+	}
+
+	secretUnstructured, err := karmadaDynamicClient.Resource(storageSecretGVR).Namespace(registryNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return StorageBackend{}, err
+	}
+
+	secret := &corev1.Secret{}
+	if err := convertUnstructuredToTyped(secretUnstructured, secret); err != nil {
+		return StorageBackend{}, fmt.Errorf("failed to convert secret: %v", err)
+	}
+
+	return secretToStorageBackend(secret), nil
+}
+
+// storageBackendToSpec builds the storage section of the StatefulMigration spec
+func storageBackendToSpec(backend StorageBackend, repository string) map[string]interface{} {
+	storage := map[string]interface{}{
+		"type": backend.Type,
+	}
+	path := strings.Trim(backend.PathPrefix, "/")
+	if repository != "" {
+		path = strings.Trim(fmt.Sprintf("%s/%s", path, repository), "/")
+	}
+
+	switch backend.Type {
+	case StorageTypePVC:
+		storage["pvc"] = map[string]interface{}{
+			"claimName": backend.ClaimName,
+			"path":      path,
+		}
+	default:
+		storage["s3"] = map[string]interface{}{
+			"endpoint": backend.Endpoint,
+			"bucket":   backend.Bucket,
+			"region":   backend.Region,
+			"insecure": backend.Insecure,
+			"prefix":   path,
+			"secretRef": map[string]interface{}{
+				"name": fmt.Sprintf("%s-%s", storageSecretPrefix, backend.ID),
+			},
+		}
+	}
+	return storage
+}
+
+// propagateStorageSecret creates a PropagationPolicy to propagate the storage backend secret to member clusters
+func propagateStorageSecret(storageID, secretName, namespace string) error {
+	return propagateBackupSecret(secretName, namespace, map[string]string{
+		"app":        "backup-storage",
+		"storage-id": storageID,
+	})
+}
+
+// propagateBackupSecret creates a PropagationPolicy with the given labels to propagate a secret read by the
+// checkpoint controllers to member clusters
+func propagateBackupSecret(secretName, namespace string, labels map[string]string) error {
+	karmadaClient := client.InClusterKarmadaClient()
+
+	memberClusters, err := getMemberClusters()
+	if err != nil {
+		return fmt.Errorf("failed to get member clusters: %v", err)
+	}
+
+	policyLabels := map[string]string{orphan.ManagedLabel: orphan.ManagedLabelValue}
+	for key, value := range labels {
+		policyLabels[key] = value
+	}
+	propagationPolicy := &policyv1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+			Labels:    policyLabels,
+		},
+		Spec: policyv1alpha1.PropagationSpec{
+			ResourceSelectors: []policyv1alpha1.ResourceSelector{
+				{
+					APIVersion: "v1",
+					Kind:       "Secret",
+					Name:       secretName,
+				},
+				externalSecretSelector(secretName),
+			},
+			Placement: policyv1alpha1.Placement{
+				ClusterAffinity: &policyv1alpha1.ClusterAffinity{
+					ClusterNames: memberClusters,
+				},
+			},
+		},
+	}
+
+	_, err = karmadaClient.PolicyV1alpha1().PropagationPolicies(namespace).Create(context.TODO(), propagationPolicy, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create PropagationPolicy: %v", err)
+	}
+
+	klog.InfoS("Successfully created PropagationPolicy for backup secret", "propagationPolicy", propagationPolicy.Name, "clusters", memberClusters)
+	return nil
+}
+
+// Register storage backend routes
+func init() {
+	r := router.V1()
+
+	storageGroup := r.Group("/backup/storage")
+	{
+		storageGroup.GET("", handleGetStorageBackends)
+		storageGroup.POST("", handleCreateStorageBackend)
+		storageGroup.GET("/:id", handleGetStorageBackend)
+		storageGroup.PUT("/:id", handleUpdateStorageBackend)
+		storageGroup.DELETE("/:id", handleDeleteStorageBackend)
+	}
+}
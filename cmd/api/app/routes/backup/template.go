@@ -0,0 +1,456 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// backupTemplateLabel marks the ConfigMaps that store backup templates
+	backupTemplateLabel   = "app=backup-template"
+	backupTemplateDataKey = "template"
+	// backupTemplateAnnotation records the template a backup was created from
+	backupTemplateAnnotation = "backup.dcnlab.com/template"
+)
+
+// BackupTemplate is a reusable set of backup settings defined by admins, so users only choose the workload
+type BackupTemplate struct {
+	ID               string                `json:"id"`
+	Name             string                `json:"name"`
+	Description      string                `json:"description,omitempty"`
+	RegistryID       string                `json:"registryId,omitempty"`
+	Repository       string                `json:"repository,omitempty"`
+	StorageBackendID string                `json:"storageBackendId,omitempty"`
+	Schedule         ScheduleConfig        `json:"schedule"`
+	Retention        *RetentionPolicy      `json:"retention,omitempty"`
+	VolumeSnapshots  *VolumeSnapshotPolicy `json:"volumeSnapshots,omitempty"`
+	EncryptionKeyID  string                `json:"encryptionKeyId,omitempty"`
+	SignCheckpoints  bool                  `json:"signCheckpoints,omitempty"`
+	CreatedBy        string                `json:"createdBy,omitempty"`
+	CreatedAt        string                `json:"createdAt"`
+	UpdatedAt        string                `json:"updatedAt"`
+}
+
+// BackupTemplateRequest is the request to create or replace a backup template
+type BackupTemplateRequest struct {
+	Name             string                `json:"name" binding:"required"`
+	Description      string                `json:"description"`
+	RegistryID       string                `json:"registryId" binding:"required_without=StorageBackendID"`
+	Repository       string                `json:"repository" binding:"required_without=StorageBackendID"`
+	StorageBackendID string                `json:"storageBackendId"`
+	Schedule         ScheduleConfig        `json:"schedule" binding:"required"`
+	Retention        *RetentionPolicy      `json:"retention"`
+	VolumeSnapshots  *VolumeSnapshotPolicy `json:"volumeSnapshots"`
+	EncryptionKeyID  string                `json:"encryptionKeyId"`
+	SignCheckpoints  bool                  `json:"signCheckpoints"`
+}
+
+// InstantiateBackupTemplateRequest is the workload a backup template is applied to
+type InstantiateBackupTemplateRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Cluster      string `json:"cluster" binding:"required,cluster"`
+	ResourceType string `json:"resourceType" binding:"required,oneof=pod statefulset"`
+	ResourceName string `json:"resourceName" binding:"required,dns1123subdomain"`
+	Namespace    string `json:"namespace" binding:"required,dns1123label"`
+	// Repository overrides the repository of the template, for instance to keep a workload apart
+	Repository string `json:"repository"`
+	// Containers limits the backup to these containers of the workload
+	Containers []string `json:"containers"`
+}
+
+// CloneBackupRequest is the workload a backup is cloned for; empty fields keep the value of the cloned backup
+type CloneBackupRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Cluster      string `json:"cluster" binding:"omitempty,cluster"`
+	ResourceType string `json:"resourceType" binding:"omitempty,oneof=pod statefulset"`
+	ResourceName string `json:"resourceName" binding:"omitempty,dns1123subdomain"`
+	Namespace    string `json:"namespace" binding:"omitempty,dns1123label"`
+	// Containers replaces the container selection of the cloned backup, an empty list selects all containers
+	Containers []string `json:"containers"`
+}
+
+// backupTemplateConfigMapName returns the name of the ConfigMap that stores a backup template
+func backupTemplateConfigMapName(id string) string {
+	return fmt.Sprintf("backup-template-%s", id)
+}
+
+// validateBackupTemplate checks the settings of a template and that its registry or storage backend exists
+func validateBackupTemplate(template *BackupTemplate) error {
+	if err := validateBackupSchedule(template.Schedule, template.Retention); err != nil {
+		return err
+	}
+	_, storage, err := resolveCheckpointStorage(template.RegistryID, template.StorageBackendID)
+	if err != nil {
+		return err
+	}
+	if storage != nil {
+		if err := validateRetentionStorage(template.Retention, storage.Type); err != nil {
+			return err
+		}
+	}
+	_, err = resolveEncryptionKey(template.EncryptionKeyID)
+	return err
+}
+
+func configMapToBackupTemplate(cm *corev1.ConfigMap) (*BackupTemplate, error) {
+	template := &BackupTemplate{}
+	if err := json.Unmarshal([]byte(cm.Data[backupTemplateDataKey]), template); err != nil {
+		return nil, fmt.Errorf("failed to decode backup template %s: %w", cm.Name, err)
+	}
+	return template, nil
+}
+
+// listBackupTemplates returns the backup templates sorted by name
+func listBackupTemplates(ctx context.Context) ([]*BackupTemplate, error) {
+	list, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: backupTemplateLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]*BackupTemplate, 0, len(list.Items))
+	for i := range list.Items {
+		template, err := configMapToBackupTemplate(&list.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid backup template", "configMap", list.Items[i].Name)
+			continue
+		}
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// getBackupTemplate returns the backup template with the given ID
+func getBackupTemplate(ctx context.Context, id string) (*BackupTemplate, error) {
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, backupTemplateConfigMapName(id), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return configMapToBackupTemplate(cm)
+}
+
+// saveBackupTemplate creates or replaces the ConfigMap of a backup template
+func saveBackupTemplate(ctx context.Context, template *BackupTemplate) error {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	configMaps := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace())
+	cm, err := configMaps.Get(ctx, backupTemplateConfigMapName(template.ID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      backupTemplateConfigMapName(template.ID),
+				Namespace: config.GetNamespace(),
+				Labels:    map[string]string{"app": "backup-template", "template-id": template.ID},
+			},
+			Data: map[string]string{backupTemplateDataKey: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[backupTemplateDataKey] = string(data)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// applyBackupTemplateRequest copies the settings of a request to a template
+func applyBackupTemplateRequest(template *BackupTemplate, req BackupTemplateRequest) {
+	template.Name = req.Name
+	template.Description = req.Description
+	template.RegistryID = req.RegistryID
+	template.Repository = req.Repository
+	template.StorageBackendID = req.StorageBackendID
+	template.Schedule = req.Schedule
+	template.Retention = req.Retention
+	template.VolumeSnapshots = req.VolumeSnapshots
+	template.EncryptionKeyID = req.EncryptionKeyID
+	template.SignCheckpoints = req.SignCheckpoints
+	template.UpdatedAt = time.Now().Format(time.RFC3339)
+}
+
+// handleGetBackupTemplates lists the backup templates
+func handleGetBackupTemplates(c *gin.Context) {
+	templates, err := listBackupTemplates(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list backup templates")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, map[string]interface{}{
+		"templates": templates,
+		"total":     len(templates),
+	})
+}
+
+// handleGetBackupTemplate returns a backup template
+func handleGetBackupTemplate(c *gin.Context) {
+	template, err := getBackupTemplate(c, c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup template", "templateID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, template)
+}
+
+// handleCreateBackupTemplate creates a backup template
+func handleCreateBackupTemplate(c *gin.Context) {
+	var req BackupTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind backup template request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	template := &BackupTemplate{
+		ID:        migration.GenerateID(req.Name),
+		CreatedBy: utilauth.GetAuthenticatedUser(c),
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	applyBackupTemplateRequest(template, req)
+	if err := validateBackupTemplate(template); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	if err := saveBackupTemplate(c, template); err != nil {
+		klog.ErrorS(err, "Failed to create backup template", "name", req.Name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, template)
+}
+
+// handleUpdateBackupTemplate replaces the settings of a backup template. Backups created from it keep theirs.
+func handleUpdateBackupTemplate(c *gin.Context) {
+	var req BackupTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind backup template request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	template, err := getBackupTemplate(c, c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup template", "templateID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	applyBackupTemplateRequest(template, req)
+	if err := validateBackupTemplate(template); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	if err := saveBackupTemplate(c, template); err != nil {
+		klog.ErrorS(err, "Failed to update backup template", "templateID", template.ID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, template)
+}
+
+// handleDeleteBackupTemplate deletes a backup template
+func handleDeleteBackupTemplate(c *gin.Context) {
+	id := c.Param("id")
+	err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Delete(c, backupTemplateConfigMapName(id), metav1.DeleteOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to delete backup template", "templateID", id)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, ActionResult{Message: "Backup template deleted successfully"})
+}
+
+// handleInstantiateBackupTemplate creates a backup of a workload with the settings of a template
+func handleInstantiateBackupTemplate(c *gin.Context) {
+	var req InstantiateBackupTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind backup template instantiation request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	template, err := getBackupTemplate(c, c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup template", "templateID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	repository := template.Repository
+	if req.Repository != "" {
+		repository = req.Repository
+	}
+	createBackupFromRequest(c, CreateBackupRequest{
+		Name:             req.Name,
+		Cluster:          req.Cluster,
+		ResourceType:     req.ResourceType,
+		ResourceName:     req.ResourceName,
+		Namespace:        req.Namespace,
+		RegistryID:       template.RegistryID,
+		Repository:       repository,
+		StorageBackendID: template.StorageBackendID,
+		Schedule:         template.Schedule,
+		Retention:        template.Retention,
+		VolumeSnapshots:  template.VolumeSnapshots,
+		Containers:       req.Containers,
+		EncryptionKeyID:  template.EncryptionKeyID,
+		SignCheckpoints:  template.SignCheckpoints,
+	}, template.ID)
+}
+
+// handleCloneBackup creates a backup with the settings of an existing backup, for the same or another workload
+func handleCloneBackup(c *gin.Context) {
+	var req CloneBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind backup clone request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	source, err := getBackupByID(c.Param("id"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to get backup", "backupID", c.Param("id"))
+		common.Fail(c, err)
+		return
+	}
+	clone := CreateBackupRequest{
+		Name:            req.Name,
+		Cluster:         source.Cluster,
+		ResourceType:    source.ResourceType,
+		ResourceName:    source.ResourceName,
+		Namespace:       source.Namespace,
+		RegistryID:      source.Registry.ID,
+		Repository:      source.Repository,
+		Schedule:        source.Schedule,
+		Retention:       source.Retention,
+		VolumeSnapshots: source.VolumeSnapshots,
+		Containers:      source.Containers,
+		SignCheckpoints: source.SignCheckpoints,
+	}
+	if req.Containers != nil {
+		clone.Containers = req.Containers
+	}
+	if source.Storage != nil {
+		clone.StorageBackendID = source.Storage.ID
+	}
+	if source.Encryption != nil {
+		clone.EncryptionKeyID = source.Encryption.ID
+	}
+	if req.Cluster != "" {
+		clone.Cluster = req.Cluster
+	}
+	if req.ResourceType != "" {
+		clone.ResourceType = req.ResourceType
+	}
+	if req.ResourceName != "" {
+		clone.ResourceName = req.ResourceName
+	}
+	if req.Namespace != "" {
+		clone.Namespace = req.Namespace
+	}
+	createBackupFromRequest(c, clone, "")
+}
+
+// createBackupFromRequest validates and creates a backup built from a template or another backup
+func createBackupFromRequest(c *gin.Context, req CreateBackupRequest, templateID string) {
+	statefulMigration, err := newStatefulMigrationCR(c, req)
+	if errors.Is(err, errClusterCordoned) {
+		failBackupChange(c, err)
+		return
+	}
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	if err := validateBackupContainers(c, statefulMigrationToBackup(statefulMigration)); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	if templateID != "" {
+		annotations := statefulMigration.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[backupTemplateAnnotation] = templateID
+		statefulMigration.SetAnnotations(annotations)
+	}
+
+	service, err := backupService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if _, err := service.Create(c, statefulMigration); err != nil {
+		klog.ErrorS(err, "Failed to create StatefulMigration CR")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, statefulMigrationToBackup(statefulMigration))
+}
+
+// backupTemplateID returns the template a backup was created from
+func backupTemplateID(sm *unstructured.Unstructured) string {
+	return sm.GetAnnotations()[backupTemplateAnnotation]
+}
+
+func init() {
+	r := router.V1()
+
+	templateGroup := r.Group("/backup/templates")
+	templateGroup.Use(idempotencyMiddleware())
+	{
+		templateGroup.GET("", handleGetBackupTemplates)
+		templateGroup.GET("/:id", handleGetBackupTemplate)
+		templateGroup.POST("", router.EnsureMgmtAdminMiddleware(), handleCreateBackupTemplate)
+		templateGroup.PUT("/:id", router.EnsureMgmtAdminMiddleware(), handleUpdateBackupTemplate)
+		templateGroup.DELETE("/:id", router.EnsureMgmtAdminMiddleware(), handleDeleteBackupTemplate)
+		templateGroup.POST("/:id/instantiate", handleInstantiateBackupTemplate)
+	}
+
+	backupGroup := r.Group("/backup")
+	backupGroup.Use(idempotencyMiddleware())
+	{
+		backupGroup.POST("/:id/clone", handleCloneBackup)
+	}
+}
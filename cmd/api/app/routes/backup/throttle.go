@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// ThrottleApplyResult is the outcome of applying the global throttle to the controller of a cluster
+type ThrottleApplyResult struct {
+	Cluster string `json:"cluster"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// ThrottleUpdateResult is the global throttle after an update and the controllers it was applied to
+type ThrottleUpdateResult struct {
+	Throttle migration.Throttle    `json:"throttle"`
+	Clusters []ThrottleApplyResult `json:"clusters"`
+}
+
+// applyGlobalThrottle rewrites the settings of the controller of a cluster with a new global throttle,
+// keeping the settings of the cluster
+func applyGlobalThrottle(ctx context.Context, clusterName string, throttle migration.Throttle) error {
+	target, err := getControllerConfigTarget(clusterName)
+	if err != nil {
+		return err
+	}
+	cfg, err := target.get(ctx)
+	if err != nil {
+		return err
+	}
+	return target.apply(ctx, cfg, throttle)
+}
+
+// handleGetThrottle returns the global throttle of the migration controllers
+func handleGetThrottle(c *gin.Context) {
+	common.Success(c, migration.GlobalThrottle())
+}
+
+// handlePutThrottle replaces the global throttle in the runtime config and applies it to the installed
+// controllers. Clusters whose settings set a limit keep it. A controller that cannot be updated is reported,
+// it picks the throttle up the next time its settings are written.
+func handlePutThrottle(c *gin.Context) {
+	var throttle migration.Throttle
+	if err := c.ShouldBindJSON(&throttle); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := throttle.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	oldConfig := config.GetDashboardConfig()
+	newConfig := oldConfig
+	newConfig.Runtime.CheckpointThrottle = nil
+	if !throttle.IsZero() {
+		newConfig.Runtime.CheckpointThrottle = &config.CheckpointThrottleConfig{
+			UploadBandwidthLimit:  throttle.UploadBandwidthLimit,
+			CheckpointConcurrency: throttle.CheckpointConcurrency,
+		}
+	}
+	k8sClient := client.InClusterClient()
+	if changed := config.ChangedFields(oldConfig, newConfig); len(changed) > 0 {
+		if err := config.UpdateDashboardConfig(k8sClient, newConfig); err != nil {
+			klog.ErrorS(err, "Failed to update checkpoint throttle")
+			common.Fail(c, err)
+			return
+		}
+		user := utilauth.GetAuthenticatedUser(c)
+		change := config.ConfigChange{Time: time.Now().Format(time.RFC3339), User: user, Fields: changed}
+		if err := config.RecordConfigChange(c, k8sClient, change); err != nil {
+			klog.ErrorS(err, "Failed to record dashboard config change", "changed", changed)
+		}
+		klog.InfoS("Updated checkpoint throttle", "user", user, "throttle", throttle)
+	}
+
+	memberClusters, err := getMemberClusters()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	result := ThrottleUpdateResult{Throttle: throttle, Clusters: []ThrottleApplyResult{}}
+	for _, clusterName := range append([]string{"mgmt-cluster"}, memberClusters...) {
+		release, err := clusterOperations.begin(c, clusterName, "configure", false)
+		if err == nil {
+			err = applyGlobalThrottle(c, clusterName, throttle)
+			release()
+		}
+		if errors.Is(err, errControllerNotInstalled) {
+			continue
+		}
+		applied := ThrottleApplyResult{Cluster: clusterName, Success: err == nil}
+		if err != nil {
+			klog.ErrorS(err, "Failed to apply checkpoint throttle", "cluster", clusterName)
+			applied.Message = err.Error()
+		}
+		result.Clusters = append(result.Clusters, applied)
+	}
+	common.Success(c, result)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	// defaultTimelineRange is how far back the timeline of a backup goes without a from parameter
+	defaultTimelineRange = 24 * time.Hour
+	// timelineLogLimitBytes bounds the controller logs read per pod
+	timelineLogLimitBytes = 1 << 20
+)
+
+// backupTimeline collects the timeline of a backup configuration. Sources that cannot be read are
+// reported as warnings rather than failing the whole timeline.
+type backupTimeline struct {
+	from, to time.Time
+	entries  []migration.TimelineEntry
+	warnings []string
+}
+
+func (t *backupTimeline) warn(source string, err error) {
+	klog.V(4).InfoS("Skipping backup timeline source", "source", source, "error", err)
+	t.warnings = append(t.warnings, fmt.Sprintf("%s: %v", source, err))
+}
+
+// handleGetBackupEvents returns the Kubernetes Events, CheckpointBackup status transitions and controller log
+// lines of a backup configuration in chronological order, between the from and to RFC3339 parameters.
+// The timeline covers the last 24 hours by default.
+func handleGetBackupEvents(c *gin.Context) {
+	backupID := c.Param("id")
+	timeline := &backupTimeline{to: time.Now(), warnings: []string{}}
+	for param, target := range map[string]*time.Time{"from": &timeline.from, "to": &timeline.to} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				common.FailWithStatus(c, fmt.Errorf("%s must be an RFC3339 timestamp: %v", param, err), http.StatusBadRequest)
+				return
+			}
+			*target = parsed
+		}
+	}
+	if timeline.from.IsZero() {
+		timeline.from = timeline.to.Add(-defaultTimelineRange)
+	}
+
+	service, err := backupService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	sm, err := service.Get(c, backupID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get StatefulMigration CR", "backupID", backupID)
+		common.Fail(c, err)
+		return
+	}
+
+	timeline.addManagement(c, sm)
+	backup := statefulMigrationToBackup(sm)
+	for _, clusterName := range migration.SourceClusters(sm) {
+		timeline.addMember(c, clusterName, backup)
+	}
+
+	entries := migration.SortTimeline(timeline.entries, timeline.from, timeline.to)
+	common.Success(c, gin.H{
+		"backupId": backupID,
+		"from":     timeline.from.Format(time.RFC3339),
+		"to":       timeline.to.Format(time.RFC3339),
+		"entries":  entries,
+		"total":    len(entries),
+		"warnings": timeline.warnings,
+	})
+}
+
+// addManagement adds the events of the StatefulMigration CR and the lines of the migration backup controller
+// that mention it
+func (t *backupTimeline) addManagement(ctx context.Context, sm *unstructured.Unstructured) {
+	k8sClient := client.InClusterClient()
+	events, err := k8sClient.CoreV1().Events(sm.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.warn("management cluster events", err)
+	} else {
+		t.entries = append(t.entries, migration.EventTimeline(events.Items, "mgmt-cluster", func(kind, name string) bool {
+			return kind == "StatefulMigration" && name == sm.GetName()
+		})...)
+	}
+
+	pods, err := k8sClient.CoreV1().Pods("stateful-migration").List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/name=migration-backup-controller",
+	})
+	if err != nil {
+		t.warn("migration backup controller logs", err)
+		return
+	}
+	for _, pod := range pods.Items {
+		lines, err := timelineLogLines(ctx, k8sClient, &pod, t.from)
+		if err != nil {
+			t.warn("migration backup controller logs", err)
+			continue
+		}
+		object := "Pod/stateful-migration/" + pod.Name
+		t.entries = append(t.entries, migration.LogTimeline(lines, "mgmt-cluster", object, []string{sm.GetName()})...)
+	}
+}
+
+// addMember adds the CheckpointBackups of the backed up resource in a source cluster, the events of them and of
+// the resource and its pods, and the lines of the checkpoint backup controller that mention them
+func (t *backupTimeline) addMember(c *gin.Context, clusterName string, backup BackupConfiguration) {
+	keywords := []string{backup.ResourceName}
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		t.warn("checkpoint backups of "+clusterName, err)
+	} else {
+		list, err := dynamicClient.Resource(checkpointBackupGVR).Namespace(backup.Namespace).List(c, metav1.ListOptions{})
+		if err != nil {
+			t.warn("checkpoint backups of "+clusterName, err)
+		} else {
+			for i := range list.Items {
+				cb, err := decodeCheckpointBackup(&list.Items[i])
+				if err != nil || !checkpointMatchesResource(cb, backup.ResourceName) {
+					continue
+				}
+				keywords = append(keywords, cb.Name)
+				t.entries = append(t.entries, migration.CheckpointTimeline(&list.Items[i], clusterName)...)
+			}
+		}
+	}
+
+	memberClient := client.InClusterClientForMemberCluster(clusterName)
+	if memberClient == nil {
+		t.warn("events of "+clusterName, fmt.Errorf("cluster client is not available"))
+		return
+	}
+	events, err := memberClient.CoreV1().Events(backup.Namespace).List(c, metav1.ListOptions{})
+	if err != nil {
+		t.warn("events of "+clusterName, err)
+	} else {
+		t.entries = append(t.entries, migration.EventTimeline(events.Items, clusterName, func(kind, name string) bool {
+			switch kind {
+			case "CheckpointBackup":
+				return containsString(keywords, name)
+			case "Pod":
+				// StatefulSet pods are named <statefulset>-<ordinal>
+				return name == backup.ResourceName || strings.HasPrefix(name, backup.ResourceName+"-")
+			}
+			return name == backup.ResourceName
+		})...)
+	}
+
+	pods, err := memberClient.CoreV1().Pods("stateful-migration").List(c, metav1.ListOptions{})
+	if err != nil {
+		t.warn("checkpoint backup controller logs of "+clusterName, err)
+		return
+	}
+	for _, pod := range pods.Items {
+		if !strings.HasPrefix(pod.Name, "checkpoint-backup-controller") {
+			continue
+		}
+		// Every node runs a controller pod and only the ones on the nodes of the resource log about it, so all are read
+		lines, err := timelineLogLines(c, memberClient, &pod, t.from)
+		if err != nil {
+			t.warn("checkpoint backup controller logs of "+clusterName, err)
+			continue
+		}
+		object := "Pod/stateful-migration/" + pod.Name
+		t.entries = append(t.entries, migration.LogTimeline(lines, clusterName, object, keywords)...)
+	}
+}
+
+// timelineLogLines reads the timestamped log lines of a controller pod since a time
+func timelineLogLines(ctx context.Context, k8sClient kubernetes.Interface, pod *corev1.Pod, since time.Time) ([]string, error) {
+	limitBytes := int64(timelineLogLimitBytes)
+	stream, err := k8sClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Timestamps: true,
+		SinceTime:  &metav1.Time{Time: since},
+		LimitBytes: &limitBytes,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of pod %s: %v", pod.Name, err)
+	}
+	defer stream.Close()
+	content, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs of pod %s: %v", pod.Name, err)
+	}
+	return strings.Split(strings.TrimSpace(string(content)), "\n"), nil
+}
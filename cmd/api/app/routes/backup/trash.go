@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// Kinds of records in the trash, as they appear in the trash routes
+const (
+	trashKindBackups    = "backups"
+	trashKindRecoveries = "recoveries"
+)
+
+// TrashedRecord is a backup configuration or recovery record in the trash
+type TrashedRecord struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	DeletedAt string `json:"deletedAt"`
+	DeletedBy string `json:"deletedBy,omitempty"`
+	// PurgeAt is when the record is deleted for good unless it is restored before
+	PurgeAt  string               `json:"purgeAt"`
+	Backup   *BackupConfiguration `json:"backup,omitempty"`
+	Recovery *RecoveryRecord      `json:"recovery,omitempty"`
+}
+
+// TrashListing lists the records in the trash
+type TrashListing struct {
+	Backups    []TrashedRecord `json:"backups"`
+	Recoveries []TrashedRecord `json:"recoveries"`
+	// Retention is how long records stay in the trash
+	Retention string `json:"retention"`
+}
+
+// trashService returns the service managing the CRs of a kind of record in the trash
+func trashService(kind string) (*migration.Service, error) {
+	switch kind {
+	case trashKindBackups:
+		return backupService()
+	case trashKindRecoveries:
+		return recoveryService()
+	}
+	return nil, fmt.Errorf("unknown kind %q, expected %s or %s", kind, trashKindBackups, trashKindRecoveries)
+}
+
+// deleteRecord moves the CR of a record to the trash on behalf of the user, or deletes it for good when permanent
+func deleteRecord(ctx context.Context, service *migration.Service, id, user string, permanent bool) error {
+	if permanent {
+		return service.Delete(ctx, id)
+	}
+	_, err := service.Trash(ctx, id, user, time.Now())
+	return err
+}
+
+// trashedRecord describes a CR in the trash
+func trashedRecord(obj *unstructured.Unstructured, retention time.Duration) TrashedRecord {
+	deletedAt := migration.DeletedAt(obj)
+	return TrashedRecord{
+		Name:      obj.GetName(),
+		DeletedAt: deletedAt.Format(time.RFC3339),
+		DeletedBy: migration.DeletedBy(obj),
+		PurgeAt:   deletedAt.Add(retention).Format(time.RFC3339),
+	}
+}
+
+// handleGetTrash lists the backup configurations and recovery records in the trash
+func handleGetTrash(c *gin.Context) {
+	retention := config.TrashRetention()
+	listing := TrashListing{Backups: []TrashedRecord{}, Recoveries: []TrashedRecord{}, Retention: retention.String()}
+
+	for _, kind := range []string{trashKindBackups, trashKindRecoveries} {
+		service, err := trashService(kind)
+		if err != nil {
+			common.Fail(c, err)
+			return
+		}
+		items, err := service.ListTrash(c)
+		if err != nil {
+			klog.ErrorS(err, "Failed to list StatefulMigration CRs in the trash", "kind", kind)
+			common.Fail(c, err)
+			return
+		}
+		for i := range items {
+			record := trashedRecord(&items[i], retention)
+			if kind == trashKindBackups {
+				backup := statefulMigrationToBackup(&items[i])
+				record.ID, record.Backup = backup.ID, &backup
+				listing.Backups = append(listing.Backups, record)
+			} else {
+				recovery := statefulMigrationToRecovery(&items[i])
+				record.ID, record.Recovery = recovery.ID, &recovery
+				listing.Recoveries = append(listing.Recoveries, record)
+			}
+		}
+	}
+	common.Success(c, listing)
+}
+
+// handleRestoreFromTrash takes a backup configuration or recovery record out of the trash
+func handleRestoreFromTrash(c *gin.Context) {
+	kind, id := c.Param("kind"), c.Param("id")
+	service, err := trashService(kind)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	if _, err := service.Restore(c, id); err != nil {
+		klog.ErrorS(err, "Failed to restore StatefulMigration CR from the trash", "kind", kind, "id", id)
+		if errors.Is(err, migration.ErrNotInTrash) {
+			common.FailWithStatus(c, fmt.Errorf("%s %s is %w", kind, id, err), http.StatusConflict)
+			return
+		}
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Restored StatefulMigration CR from the trash", "kind", kind, "id", id, "user", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, ActionResult{Message: fmt.Sprintf("Restored %s %s from the trash", kind, id)})
+}
+
+// handlePurgeFromTrash deletes a backup configuration or recovery record in the trash for good
+func handlePurgeFromTrash(c *gin.Context) {
+	kind, id := c.Param("kind"), c.Param("id")
+	service, err := trashService(kind)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	// Only records in the trash are purged here, active ones are deleted with ?permanent=true
+	if err := service.Purge(c, id); err != nil {
+		klog.ErrorS(err, "Failed to purge StatefulMigration CR from the trash", "kind", kind, "id", id)
+		if errors.Is(err, migration.ErrNotInTrash) {
+			common.FailWithStatus(c, fmt.Errorf("%s %s is %w", kind, id, err), http.StatusNotFound)
+			return
+		}
+		failBackupChange(c, err)
+		return
+	}
+	common.Success(c, ActionResult{Message: fmt.Sprintf("Purged %s %s from the trash", kind, id)})
+}
+
+// purgeTrash deletes the backup configurations and recovery records that have been in the trash for longer
+// than the trash retention
+func purgeTrash(ctx context.Context) error {
+	retention := config.TrashRetention()
+	var errs []error
+	for _, kind := range []string{trashKindBackups, trashKindRecoveries} {
+		service, err := trashService(kind)
+		if err != nil {
+			return err
+		}
+		purged, err := service.PurgeTrash(ctx, retention, time.Now())
+		if len(purged) > 0 {
+			klog.InfoS("Purged StatefulMigration CRs from the trash", "kind", kind, "ids", purged, "retention", retention)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to purge %s: %w", kind, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartTrashPurger periodically purges the trash of backup configurations and recovery records until ctx is
+// done. A non-positive interval disables the purger.
+func StartTrashPurger(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Backup trash purger is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := purgeTrash(ctx); err != nil {
+					klog.ErrorS(err, "Backup trash purge failed")
+				}
+			}
+		}
+	}()
+	klog.InfoS("Backup trash purger started", "interval", interval)
+}
+
+func init() {
+	r := router.V1()
+
+	trashGroup := r.Group("/backup/trash")
+	trashGroup.Use(idempotencyMiddleware())
+	{
+		trashGroup.GET("", handleGetTrash)
+		trashGroup.POST("/:kind/:id/restore", handleRestoreFromTrash)
+		trashGroup.DELETE("/:kind/:id", handlePurgeFromTrash)
+	}
+}
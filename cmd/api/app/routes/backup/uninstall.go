@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// controllerUninstallBlockers returns the resources that use the migration controller of a cluster.
+// The checkpoint backups of a member cluster that cannot be reached are not checked.
+func controllerUninstallBlockers(ctx context.Context, clusterName string) ([]migration.UninstallBlocker, error) {
+	backups, err := listStatefulMigrations(ctx, backupService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup configurations: %v", err)
+	}
+	recoveries, err := listStatefulMigrations(ctx, recoveryService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recoveries: %v", err)
+	}
+
+	management := clusterName == "mgmt-cluster" || clusterName == "management"
+	var checkpoints []unstructured.Unstructured
+	if !management {
+		dynamicClient, err := client.GetDynamicClientForMember(&gin.Context{}, clusterName)
+		if err == nil {
+			var list *unstructured.UnstructuredList
+			list, err = dynamicClient.Resource(checkpointBackupGVR).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				checkpoints = list.Items
+			}
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.V(4).InfoS("Skipping checkpoint backups in uninstall check", "cluster", clusterName, "error", err)
+		}
+	}
+	return migration.UninstallBlockers(clusterName, management, backups, recoveries, checkpoints), nil
+}
+
+// listStatefulMigrations lists the CRs of a service, none when the StatefulMigration CRD is not installed
+func listStatefulMigrations(ctx context.Context, newService func() (*migration.Service, error)) ([]unstructured.Unstructured, error) {
+	service, err := newService()
+	if err != nil {
+		return nil, err
+	}
+	items, err := service.List(ctx)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return items, err
+}
+
+// prepareUninstall refuses to uninstall a migration controller that is in use with an UninstallBlockedError.
+// A forced uninstall deletes the backup configurations of the cluster first; operations in flight are left to fail.
+func prepareUninstall(ctx context.Context, clusterName string, force bool) error {
+	blockers, err := controllerUninstallBlockers(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+	if !force {
+		return &migration.UninstallBlockedError{Cluster: clusterName, Blockers: blockers}
+	}
+
+	service, err := backupService()
+	if err != nil {
+		return err
+	}
+	for _, blocker := range blockers {
+		if !blocker.Cascade {
+			klog.InfoS("Forcing migration controller uninstall", "cluster", clusterName, "kind", blocker.Kind, "name", blocker.Name, "phase", blocker.Phase)
+			continue
+		}
+		if err := service.Delete(ctx, migration.Backup.ID(blocker.Name)); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete backup configuration %s: %w", blocker.Name, err)
+		}
+		klog.InfoS("Deleted backup configuration of uninstalled migration controller", "cluster", clusterName, "name", blocker.Name)
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+const (
+	operatorImageRepository = "docker.io/lehuannhatrang/stateful-migration-operator"
+	operatorTagsURL         = "https://hub.docker.com/v2/repositories/lehuannhatrang/stateful-migration-operator/tags?page_size=100"
+
+	// defaultControllerVersion is used when the registry cannot be queried
+	defaultControllerVersion = "v2.0"
+	// compatibilityConfigMapName overrides the built-in compatibility matrix with its "matrix" JSON key
+	compatibilityConfigMapName = "controller-compatibility"
+
+	componentMigrationBackup  = migration.ComponentMigrationBackup
+	componentCheckpointBackup = migration.ComponentCheckpointBackup
+
+	versionCatalogTTL = 10 * time.Minute
+)
+
+// ControllerVersion is an operator version published in the registry
+type ControllerVersion struct {
+	Version     string   `json:"version"`
+	Components  []string `json:"components"`
+	LastUpdated string   `json:"lastUpdated,omitempty"`
+}
+
+// CompatibilityEntry gives the Kubernetes versions supported by the controller versions with a prefix.
+// An empty MaxKubeVersion means no upper bound.
+type CompatibilityEntry struct {
+	VersionPrefix  string `json:"versionPrefix"`
+	MinKubeVersion string `json:"minKubeVersion"`
+	MaxKubeVersion string `json:"maxKubeVersion,omitempty"`
+}
+
+// ControllerCatalog lists the available controller versions and their compatibility
+type ControllerCatalog struct {
+	Versions      []ControllerVersion  `json:"versions"`
+	Latest        string               `json:"latest"`
+	Compatibility []CompatibilityEntry `json:"compatibility"`
+	// Error is set when the registry could not be queried and the catalog only holds the default version
+	Error string `json:"error,omitempty"`
+}
+
+// UpgradeControllerRequest represents the request to change the version of an installed migration controller
+type UpgradeControllerRequest struct {
+	ClusterName string `json:"clusterName" binding:"required,cluster"`
+	Version     string `json:"version" binding:"required"`
+}
+
+// defaultCompatibility requires the kubelet checkpoint API, which was added in Kubernetes 1.25
+var defaultCompatibility = []CompatibilityEntry{
+	{VersionPrefix: "v1", MinKubeVersion: "1.25"},
+	{VersionPrefix: "v2", MinKubeVersion: "1.25"},
+}
+
+var versionCatalogCache = struct {
+	sync.Mutex
+	versions  []ControllerVersion
+	fetchedAt time.Time
+}{}
+
+// fetchControllerVersions lists the operator image tags, grouped by version.
+// Tags are named <component>_<version>, e.g. checkpointBackup_v2.0.
+func fetchControllerVersions(ctx context.Context) ([]ControllerVersion, error) {
+	versionCatalogCache.Lock()
+	defer versionCatalogCache.Unlock()
+	if versionCatalogCache.versions != nil && time.Since(versionCatalogCache.fetchedAt) < versionCatalogTTL {
+		return versionCatalogCache.versions, nil
+	}
+
+	byVersion := make(map[string]*ControllerVersion)
+	url := operatorTagsURL
+	for url != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list operator tags: %v", err)
+		}
+		var page struct {
+			Next    string `json:"next"`
+			Results []struct {
+				Name        string `json:"name"`
+				LastUpdated string `json:"last_updated"`
+			} `json:"results"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to list operator tags: HTTP %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode operator tags: %v", err)
+		}
+
+		for _, tag := range page.Results {
+			component, ver, found := strings.Cut(tag.Name, "_")
+			if !found || (component != componentMigrationBackup && component != componentCheckpointBackup) {
+				continue
+			}
+			if _, err := version.ParseGeneric(ver); err != nil {
+				continue
+			}
+			entry, ok := byVersion[ver]
+			if !ok {
+				entry = &ControllerVersion{Version: ver}
+				byVersion[ver] = entry
+			}
+			if !containsString(entry.Components, component) {
+				entry.Components = append(entry.Components, component)
+			}
+			if tag.LastUpdated > entry.LastUpdated {
+				entry.LastUpdated = tag.LastUpdated
+			}
+		}
+		url = page.Next
+	}
+
+	versions := make([]ControllerVersion, 0, len(byVersion))
+	for _, entry := range byVersion {
+		sort.Strings(entry.Components)
+		versions = append(versions, *entry)
+	}
+	// Newest first
+	sort.Slice(versions, func(i, j int) bool {
+		return version.MustParseGeneric(versions[j].Version).LessThan(version.MustParseGeneric(versions[i].Version))
+	})
+
+	versionCatalogCache.versions = versions
+	versionCatalogCache.fetchedAt = time.Now()
+	return versions, nil
+}
+
+// getCompatibilityMatrix returns the matrix from the override ConfigMap, or the built-in one
+func getCompatibilityMatrix(ctx context.Context) []CompatibilityEntry {
+	k8sClient := client.InClusterClient()
+	cm, err := k8sClient.CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, compatibilityConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return defaultCompatibility
+	}
+	var matrix []CompatibilityEntry
+	if err := json.Unmarshal([]byte(cm.Data["matrix"]), &matrix); err != nil {
+		klog.ErrorS(err, "Invalid controller compatibility matrix, using defaults", "configMap", compatibilityConfigMapName)
+		return defaultCompatibility
+	}
+	return matrix
+}
+
+// getControllerCatalog builds the catalog. When the registry is unreachable it falls back to the default version.
+func getControllerCatalog(ctx context.Context) *ControllerCatalog {
+	catalog := &ControllerCatalog{Compatibility: getCompatibilityMatrix(ctx)}
+	versions, err := fetchControllerVersions(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch controller versions")
+		catalog.Error = err.Error()
+		catalog.Versions = []ControllerVersion{{
+			Version:    defaultControllerVersion,
+			Components: []string{componentCheckpointBackup, componentMigrationBackup},
+		}}
+		catalog.Latest = defaultControllerVersion
+		return catalog
+	}
+
+	catalog.Versions = versions
+	for _, v := range versions {
+		// The latest version is the newest one published for both controllers
+		if len(v.Components) == 2 {
+			catalog.Latest = v.Version
+			break
+		}
+	}
+	if catalog.Latest == "" {
+		catalog.Latest = defaultControllerVersion
+	}
+	return catalog
+}
+
+// checkKubeCompatibility reports whether the controller version supports the Kubernetes version
+func checkKubeCompatibility(matrix []CompatibilityEntry, controllerVersion, kubeVersion string) error {
+	parsed, err := version.ParseGeneric(kubeVersion)
+	if err != nil {
+		// Unknown Kubernetes versions are not blocked
+		return nil
+	}
+	// The matrix is expressed in minor versions, so patch releases are ignored
+	kube := version.MajorMinor(parsed.Major(), parsed.Minor())
+	for _, entry := range matrix {
+		if !strings.HasPrefix(controllerVersion, entry.VersionPrefix) {
+			continue
+		}
+		if minVersion, err := version.ParseGeneric(entry.MinKubeVersion); err == nil && kube.LessThan(minVersion) {
+			return fmt.Errorf("controller %s requires Kubernetes %s or newer, cluster runs %s", controllerVersion, entry.MinKubeVersion, kubeVersion)
+		}
+		if maxVersion, err := version.ParseGeneric(entry.MaxKubeVersion); err == nil && maxVersion.LessThan(kube) {
+			return fmt.Errorf("controller %s supports Kubernetes up to %s, cluster runs %s", controllerVersion, entry.MaxKubeVersion, kubeVersion)
+		}
+		return nil
+	}
+	return nil
+}
+
+// getClusterKubeVersion returns the Kubernetes version of the management or a member cluster
+func getClusterKubeVersion(ctx context.Context, clusterName string) string {
+	if clusterName == "mgmt-cluster" || clusterName == "management" {
+		info, err := client.InClusterClient().Discovery().ServerVersion()
+		if err != nil {
+			return ""
+		}
+		return info.GitVersion
+	}
+	cluster, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(ctx, clusterName, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return cluster.Status.KubernetesVersion
+}
+
+// validateControllerVersion checks that the version is published for the controller of the cluster
+// and compatible with its Kubernetes version
+func validateControllerVersion(ctx context.Context, clusterName, controllerVersion string) error {
+	if _, err := version.ParseGeneric(controllerVersion); err != nil {
+		return fmt.Errorf("invalid controller version %q", controllerVersion)
+	}
+	component := componentCheckpointBackup
+	if clusterName == "mgmt-cluster" || clusterName == "management" {
+		component = componentMigrationBackup
+	}
+
+	catalog := getControllerCatalog(ctx)
+	if catalog.Error == "" {
+		published := false
+		for _, v := range catalog.Versions {
+			if v.Version != controllerVersion {
+				continue
+			}
+			published = containsString(v.Components, component)
+		}
+		if !published {
+			return fmt.Errorf("controller version %s is not published for %s", controllerVersion, component)
+		}
+	}
+
+	return checkKubeCompatibility(catalog.Compatibility, controllerVersion, getClusterKubeVersion(ctx, clusterName))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// upgradeMigrationController switches the image of an installed controller to another version
+func upgradeMigrationController(ctx context.Context, clusterName, controllerVersion string) error {
+	if clusterName == "mgmt-cluster" || clusterName == "management" {
+		k8sClient := client.InClusterClient()
+		deployment, err := k8sClient.AppsV1().Deployments("stateful-migration").Get(ctx, "migration-backup-controller", metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("migration controller is not installed: %v", err)
+		}
+		for i := range deployment.Spec.Template.Spec.Containers {
+			if deployment.Spec.Template.Spec.Containers[i].Name == "manager" {
+				deployment.Spec.Template.Spec.Containers[i].Image = fmt.Sprintf("%s:%s_%s", operatorImageRepository, componentMigrationBackup, controllerVersion)
+			}
+		}
+		_, err = k8sClient.AppsV1().Deployments("stateful-migration").Update(ctx, deployment, metav1.UpdateOptions{})
+		return err
+	}
+
+	karmadaDynamicClient, err := getKarmadaDynamicClient()
+	if err != nil {
+		return err
+	}
+	daemonSetGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	daemonSets := karmadaDynamicClient.Resource(daemonSetGVR).Namespace("stateful-migration")
+	daemonSet, err := daemonSets.Get(ctx, fmt.Sprintf("checkpoint-backup-controller-%s", clusterName), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("migration controller is not installed: %v", err)
+	}
+	containers, _, err := unstructured.NestedSlice(daemonSet.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return fmt.Errorf("failed to get containers: %v", err)
+	}
+	for i, container := range containers {
+		containerMap := container.(map[string]interface{})
+		if containerMap["name"] == "controller" {
+			containerMap["image"] = fmt.Sprintf("%s:%s_%s", operatorImageRepository, componentCheckpointBackup, controllerVersion)
+			containers[i] = containerMap
+		}
+	}
+	if err := unstructured.SetNestedSlice(daemonSet.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return fmt.Errorf("failed to set containers: %v", err)
+	}
+	_, err = daemonSets.Update(ctx, daemonSet, metav1.UpdateOptions{})
+	return err
+}
+
+// handleGetControllerVersions returns the controller version catalog and compatibility matrix
+func handleGetControllerVersions(c *gin.Context) {
+	common.Success(c, getControllerCatalog(c))
+}
+
+// handleUpgradeController starts a job that changes the version of the migration controller on a cluster
+func handleUpgradeController(c *gin.Context) {
+	var req UpgradeControllerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		klog.ErrorS(err, "Failed to bind upgrade controller request")
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := validateControllerVersion(c, req.ClusterName, req.Version); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	job, ok := enqueueControllerOperation(c, controllerJobParams{
+		Operation: "upgrade",
+		Clusters:  []string{req.ClusterName},
+		Version:   req.Version,
+	})
+	if !ok {
+		return
+	}
+
+	common.Success(c, InstallResult{
+		Message:  fmt.Sprintf("Migration controller on cluster %s is being upgraded to %s", req.ClusterName, req.Version),
+		JobID:    job.ID,
+		Clusters: []string{req.ClusterName},
+	})
+}
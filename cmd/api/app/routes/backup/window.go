@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const executionWindowsAnnotation = "backup.dcnlab.com/execution-windows"
+
+// MaintenanceWindow is a recurring period in which checkpoints may be taken
+type MaintenanceWindow struct {
+	Days     []string `json:"days,omitempty"` // "mon".."sun", empty means every day
+	Start    string   `json:"start"`          // "HH:MM"
+	End      string   `json:"end"`            // "HH:MM", earlier than start when the window crosses midnight
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+// BlackoutPeriod is a one-off period in which no checkpoints may be taken
+type BlackoutPeriod struct {
+	Start  string `json:"start"` // RFC3339
+	End    string `json:"end"`   // RFC3339
+	Reason string `json:"reason,omitempty"`
+}
+
+// ExecutionWindows restricts when backups and migrations may be executed
+type ExecutionWindows struct {
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	Blackouts          []BlackoutPeriod    `json:"blackouts,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w MaintenanceWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+// contains reports whether the window is open at the given time
+func (w MaintenanceWindow) contains(now time.Time) bool {
+	loc, err := w.location()
+	if err != nil {
+		return false
+	}
+	local := now.In(loc)
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+	minute := local.Hour()*60 + local.Minute()
+
+	onDay := func(day time.Weekday) bool {
+		if len(w.Days) == 0 {
+			return true
+		}
+		for _, d := range w.Days {
+			if weekdays[strings.ToLower(d)[:3]] == day {
+				return true
+			}
+		}
+		return false
+	}
+
+	if start < end {
+		return onDay(local.Weekday()) && minute >= start && minute < end
+	}
+	// The window crosses midnight, so the early part belongs to the previous day's window
+	if minute >= start {
+		return onDay(local.Weekday())
+	}
+	return minute < end && onDay(local.AddDate(0, 0, -1).Weekday())
+}
+
+// validate checks that all windows and blackout periods are well formed
+func (e ExecutionWindows) validate() error {
+	for i, w := range e.MaintenanceWindows {
+		if _, err := parseClock(w.Start); err != nil {
+			return fmt.Errorf("maintenance window %d: %v", i, err)
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return fmt.Errorf("maintenance window %d: %v", i, err)
+		}
+		if w.Start == w.End {
+			return fmt.Errorf("maintenance window %d: start and end must differ", i)
+		}
+		if _, err := w.location(); err != nil {
+			return fmt.Errorf("maintenance window %d: invalid timezone %q", i, w.Timezone)
+		}
+		for _, d := range w.Days {
+			if len(d) < 3 {
+				return fmt.Errorf("maintenance window %d: invalid day %q", i, d)
+			}
+			if _, ok := weekdays[strings.ToLower(d)[:3]]; !ok {
+				return fmt.Errorf("maintenance window %d: invalid day %q", i, d)
+			}
+		}
+	}
+	for i, b := range e.Blackouts {
+		start, err := time.Parse(time.RFC3339, b.Start)
+		if err != nil {
+			return fmt.Errorf("blackout %d: invalid start %q", i, b.Start)
+		}
+		end, err := time.Parse(time.RFC3339, b.End)
+		if err != nil {
+			return fmt.Errorf("blackout %d: invalid end %q", i, b.End)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("blackout %d: end must be after start", i)
+		}
+	}
+	return nil
+}
+
+// checkAllowed returns an error when execution is not allowed at the given time
+func (e ExecutionWindows) checkAllowed(now time.Time) error {
+	for _, b := range e.Blackouts {
+		start, _ := time.Parse(time.RFC3339, b.Start)
+		end, _ := time.Parse(time.RFC3339, b.End)
+		if !now.Before(start) && now.Before(end) {
+			if b.Reason != "" {
+				return fmt.Errorf("execution is blocked by a blackout period until %s: %s", b.End, b.Reason)
+			}
+			return fmt.Errorf("execution is blocked by a blackout period until %s", b.End)
+		}
+	}
+
+	if len(e.MaintenanceWindows) == 0 {
+		return nil
+	}
+	for _, w := range e.MaintenanceWindows {
+		if w.contains(now) {
+			return nil
+		}
+	}
+	return fmt.Errorf("execution is only allowed during the configured maintenance windows")
+}
+
+// isEmpty reports whether no windows or blackouts are configured
+func (e ExecutionWindows) isEmpty() bool {
+	return len(e.MaintenanceWindows) == 0 && len(e.Blackouts) == 0
+}
+
+// setExecutionWindowsAnnotation stores the execution windows on the StatefulMigration
+func setExecutionWindowsAnnotation(sm *unstructured.Unstructured, windows ExecutionWindows) {
+	annotations := sm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if windows.isEmpty() {
+		delete(annotations, executionWindowsAnnotation)
+	} else if data, err := json.Marshal(windows); err == nil {
+		annotations[executionWindowsAnnotation] = string(data)
+	}
+	sm.SetAnnotations(annotations)
+}
+
+// executionWindowsFromAnnotations reads the execution windows of a StatefulMigration
+func executionWindowsFromAnnotations(sm *unstructured.Unstructured) ExecutionWindows {
+	var windows ExecutionWindows
+	if data, ok := sm.GetAnnotations()[executionWindowsAnnotation]; ok {
+		_ = json.Unmarshal([]byte(data), &windows)
+	}
+	return windows
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	// 2024-06-07 is a Friday
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	officeHours := MaintenanceWindow{Start: "09:00", End: "17:00"}
+	fridayNight := MaintenanceWindow{Days: []string{"fri"}, Start: "22:00", End: "02:00"}
+	tokyoMorning := MaintenanceWindow{Days: []string{"Monday"}, Start: "01:00", End: "03:00", Timezone: "Asia/Tokyo"}
+	newYork := MaintenanceWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"}
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		now    string
+		want   bool
+	}{
+		{name: "before start", window: officeHours, now: "2024-06-07T08:59:59Z", want: false},
+		{name: "exact start minute", window: officeHours, now: "2024-06-07T09:00:00Z", want: true},
+		{name: "last minute", window: officeHours, now: "2024-06-07T16:59:59Z", want: true},
+		{name: "exact end minute", window: officeHours, now: "2024-06-07T17:00:00Z", want: false},
+		{name: "no days is every day", window: officeHours, now: "2024-06-09T12:00:00Z", want: true},
+
+		{name: "crossing midnight, exact start", window: fridayNight, now: "2024-06-07T22:00:00Z", want: true},
+		{name: "crossing midnight, before midnight", window: fridayNight, now: "2024-06-07T23:30:00Z", want: true},
+		{name: "crossing midnight, after midnight of the next day", window: fridayNight, now: "2024-06-08T01:59:00Z", want: true},
+		{name: "crossing midnight, exact end", window: fridayNight, now: "2024-06-08T02:00:00Z", want: false},
+		{name: "crossing midnight, early part belongs to the previous day", window: fridayNight, now: "2024-06-07T01:00:00Z", want: false},
+		{name: "crossing midnight, other day", window: fridayNight, now: "2024-06-08T22:30:00Z", want: false},
+		{name: "crossing midnight, between end and start", window: fridayNight, now: "2024-06-07T12:00:00Z", want: false},
+
+		{name: "time zone shifts the day", window: tokyoMorning, now: "2024-06-09T16:30:00Z", want: true},
+		{name: "time zone, exact start", window: tokyoMorning, now: "2024-06-09T16:00:00Z", want: true},
+		{name: "time zone, exact end", window: tokyoMorning, now: "2024-06-09T18:00:00Z", want: false},
+		{name: "time zone, same utc time on the window day", window: tokyoMorning, now: "2024-06-10T16:30:00Z", want: false},
+		{name: "time zone with daylight saving, summer", window: newYork, now: "2024-06-07T13:00:00Z", want: true},
+		{name: "time zone with daylight saving, winter", window: newYork, now: "2024-01-10T13:00:00Z", want: false},
+		{name: "time zone with daylight saving, winter start", window: newYork, now: "2024-01-10T14:00:00Z", want: true},
+
+		{name: "day names are case insensitive", window: MaintenanceWindow{Days: []string{"FRI"}, Start: "09:00", End: "17:00"}, now: "2024-06-07T10:00:00Z", want: true},
+		{name: "invalid time zone never opens", window: MaintenanceWindow{Start: "00:00", End: "23:59", Timezone: "Mars/Olympus"}, now: "2024-06-07T10:00:00Z", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.contains(at(tt.now)); got != tt.want {
+				t.Errorf("contains(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutionWindowsCheckAllowed(t *testing.T) {
+	blackout := BlackoutPeriod{Start: "2024-06-07T10:00:00Z", End: "2024-06-07T12:00:00Z", Reason: "release freeze"}
+	tests := []struct {
+		name    string
+		windows ExecutionWindows
+		now     string
+		wantErr string
+	}{
+		{name: "no windows configured", now: "2024-06-07T03:00:00Z"},
+		{
+			name:    "inside a blackout",
+			windows: ExecutionWindows{Blackouts: []BlackoutPeriod{blackout}},
+			now:     "2024-06-07T11:00:00Z",
+			wantErr: "blackout period until 2024-06-07T12:00:00Z: release freeze",
+		},
+		{
+			name:    "exact blackout start",
+			windows: ExecutionWindows{Blackouts: []BlackoutPeriod{blackout}},
+			now:     "2024-06-07T10:00:00Z",
+			wantErr: "blackout period",
+		},
+		{
+			name:    "exact blackout end",
+			windows: ExecutionWindows{Blackouts: []BlackoutPeriod{blackout}},
+			now:     "2024-06-07T12:00:00Z",
+		},
+		{
+			name:    "blackout without reason",
+			windows: ExecutionWindows{Blackouts: []BlackoutPeriod{{Start: blackout.Start, End: blackout.End}}},
+			now:     "2024-06-07T11:00:00Z",
+			wantErr: "blocked by a blackout period until 2024-06-07T12:00:00Z",
+		},
+		{
+			name:    "inside a maintenance window",
+			windows: ExecutionWindows{MaintenanceWindows: []MaintenanceWindow{{Start: "22:00", End: "02:00"}}},
+			now:     "2024-06-07T23:00:00Z",
+		},
+		{
+			name:    "outside all maintenance windows",
+			windows: ExecutionWindows{MaintenanceWindows: []MaintenanceWindow{{Start: "22:00", End: "02:00"}, {Start: "12:00", End: "13:00"}}},
+			now:     "2024-06-07T13:00:00Z",
+			wantErr: "only allowed during the configured maintenance windows",
+		},
+		{
+			name:    "any open window allows",
+			windows: ExecutionWindows{MaintenanceWindows: []MaintenanceWindow{{Start: "22:00", End: "02:00"}, {Start: "12:00", End: "13:00"}}},
+			now:     "2024-06-07T12:00:00Z",
+		},
+		{
+			name: "blackout wins over an open window",
+			windows: ExecutionWindows{
+				MaintenanceWindows: []MaintenanceWindow{{Start: "09:00", End: "17:00"}},
+				Blackouts:          []BlackoutPeriod{blackout},
+			},
+			now:     "2024-06-07T10:30:00Z",
+			wantErr: "release freeze",
+		},
+		{
+			name:    "maintenance window in another time zone",
+			windows: ExecutionWindows{MaintenanceWindows: []MaintenanceWindow{{Start: "09:00", End: "10:00", Timezone: "Asia/Tokyo"}}},
+			now:     "2024-06-07T00:15:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.windows.checkAllowed(now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkAllowed() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkAllowed() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/client"
 	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/externalsecret"
 )
 
 const (
@@ -41,11 +42,13 @@ const (
 )
 
 type CloudCredential struct {
-	Name         string            `json:"name"`
-	Provider     string            `json:"provider"`
-	Description  string            `json:"description,omitempty"`
-	CreatedAt    string            `json:"createdAt"`
-	Labels       map[string]string `json:"labels,omitempty"`
+	Name        string            `json:"name"`
+	Provider    string            `json:"provider"`
+	Description string            `json:"description,omitempty"`
+	CreatedAt   string            `json:"createdAt"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// ExternalSecret names the ExternalSecret filling the credentials from an external store
+	ExternalSecret string `json:"externalSecret,omitempty"`
 }
 
 type CloudCredentialList struct {
@@ -53,16 +56,53 @@ type CloudCredentialList struct {
 	TotalItems  int               `json:"totalItems"`
 }
 
+// CreateCredentialRequest holds either the credentials or, when external secrets are enabled, where
+// they are read from in the external store
 type CreateCredentialRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Provider    string `json:"provider" binding:"required"`
-	Credentials string `json:"credentials" binding:"required"`
-	Description string `json:"description"`
+	Name           string                    `json:"name" binding:"required"`
+	Provider       string                    `json:"provider" binding:"required"`
+	Credentials    string                    `json:"credentials" binding:"required_without=CredentialsRef"`
+	CredentialsRef *externalsecret.RemoteRef `json:"credentialsRef,omitempty"`
+	Description    string                    `json:"description"`
 }
 
 type UpdateCredentialRequest struct {
-	Credentials string `json:"credentials"`
-	Description string `json:"description"`
+	Credentials    string                    `json:"credentials"`
+	CredentialsRef *externalsecret.RemoteRef `json:"credentialsRef,omitempty"`
+	Description    string                    `json:"description"`
+}
+
+const credentialsKey = "credentials"
+
+// applyCredentialsRef makes an ExternalSecret fill the credentials of a secret from the external store
+func applyCredentialsRef(ctx context.Context, secret *corev1.Secret, ref externalsecret.RemoteRef) error {
+	if err := ref.Validate(); err != nil {
+		return pkgerrors.NewBadRequest(err.Error())
+	}
+	settings, err := externalsecret.Settings()
+	if err != nil {
+		return pkgerrors.NewBadRequest(err.Error())
+	}
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+	obj := externalsecret.New(secret.Name, secret.Namespace, settings, map[string]externalsecret.RemoteRef{credentialsKey: ref},
+		map[string]string{CredentialLabelKey: "cloud-credential"})
+	return externalsecret.Apply(ctx, dynamicClient, obj)
+}
+
+// deleteCredentialsRef deletes the ExternalSecret filling the credentials of a secret, if any
+func deleteCredentialsRef(ctx context.Context, secret *corev1.Secret) error {
+	name := externalsecret.Reference(secret)
+	if name == "" {
+		return nil
+	}
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+	return externalsecret.Delete(ctx, dynamicClient, secret.Namespace, name)
 }
 
 // handleGetCloudCredentials returns a list of cloud credentials
@@ -87,11 +127,12 @@ func handleGetCloudCredentials(c *gin.Context) {
 	credentials := make([]CloudCredential, 0, len(secrets.Items))
 	for _, secret := range secrets.Items {
 		cred := CloudCredential{
-			Name:        secret.Name,
-			Provider:    secret.Labels[ProviderLabelKey],
-			Description: secret.Annotations["description"],
-			CreatedAt:   secret.CreationTimestamp.Format("2006-01-02 15:04:05"),
-			Labels:      secret.Labels,
+			Name:           secret.Name,
+			Provider:       secret.Labels[ProviderLabelKey],
+			Description:    secret.Annotations["description"],
+			CreatedAt:      secret.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			Labels:         secret.Labels,
+			ExternalSecret: externalsecret.Reference(&secret),
 		}
 		credentials = append(credentials, cred)
 	}
@@ -132,11 +173,12 @@ func handleGetCloudCredential(c *gin.Context) {
 	}
 
 	cred := CloudCredential{
-		Name:        secret.Name,
-		Provider:    secret.Labels[ProviderLabelKey],
-		Description: secret.Annotations["description"],
-		CreatedAt:   secret.CreationTimestamp.Format("2006-01-02 15:04:05"),
-		Labels:      secret.Labels,
+		Name:           secret.Name,
+		Provider:       secret.Labels[ProviderLabelKey],
+		Description:    secret.Annotations["description"],
+		CreatedAt:      secret.CreationTimestamp.Format("2006-01-02 15:04:05"),
+		Labels:         secret.Labels,
+		ExternalSecret: externalsecret.Reference(secret),
 	}
 
 	common.Success(c, cred)
@@ -154,7 +196,7 @@ func handleCreateCloudCredential(c *gin.Context) {
 	var req CreateCredentialRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON for credential creation")
-		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -186,9 +228,17 @@ func handleCreateCloudCredential(c *gin.Context) {
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"credentials": []byte(req.Credentials),
-		},
+		Data: map[string][]byte{},
+	}
+	if req.CredentialsRef != nil {
+		// The credentials are merged into the secret by the External Secrets Operator
+		if _, err := externalsecret.Settings(); err != nil {
+			common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+			return
+		}
+		secret.Annotations[externalsecret.ReferenceAnnotation] = req.Name
+	} else {
+		secret.Data[credentialsKey] = []byte(req.Credentials)
 	}
 
 	createdSecret, err := k8sClient.CoreV1().Secrets(CloudCredentialsNamespace).Create(context.TODO(), secret, metav1.CreateOptions{})
@@ -201,13 +251,24 @@ func handleCreateCloudCredential(c *gin.Context) {
 		common.Fail(c, err)
 		return
 	}
+	if req.CredentialsRef != nil {
+		if err := applyCredentialsRef(c, createdSecret, *req.CredentialsRef); err != nil {
+			klog.ErrorS(err, "Failed to create ExternalSecret for cloud credential", "name", req.Name)
+			if err := k8sClient.CoreV1().Secrets(CloudCredentialsNamespace).Delete(context.TODO(), req.Name, metav1.DeleteOptions{}); err != nil {
+				klog.ErrorS(err, "Failed to delete cloud credential after ExternalSecret failure", "name", req.Name)
+			}
+			common.Fail(c, err)
+			return
+		}
+	}
 
 	cred := CloudCredential{
-		Name:        createdSecret.Name,
-		Provider:    createdSecret.Labels[ProviderLabelKey],
-		Description: createdSecret.Annotations["description"],
-		CreatedAt:   createdSecret.CreationTimestamp.Format("2006-01-02 15:04:05"),
-		Labels:      createdSecret.Labels,
+		Name:           createdSecret.Name,
+		Provider:       createdSecret.Labels[ProviderLabelKey],
+		Description:    createdSecret.Annotations["description"],
+		CreatedAt:      createdSecret.CreationTimestamp.Format("2006-01-02 15:04:05"),
+		Labels:         createdSecret.Labels,
+		ExternalSecret: externalsecret.Reference(createdSecret),
 	}
 
 	common.Success(c, cred)
@@ -226,7 +287,7 @@ func handleUpdateCloudCredential(c *gin.Context) {
 	var req UpdateCredentialRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON for credential update")
-		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -248,14 +309,31 @@ func handleUpdateCloudCredential(c *gin.Context) {
 		return
 	}
 
-	// Update secret data
-	if req.Credentials != "" {
-		secret.Data["credentials"] = []byte(req.Credentials)
+	// Update secret data, plain credentials replace an external reference and the other way around
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
 	}
-	if req.Description != "" {
-		if secret.Annotations == nil {
-			secret.Annotations = make(map[string]string)
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	if req.CredentialsRef != nil {
+		if err := applyCredentialsRef(c, secret, *req.CredentialsRef); err != nil {
+			klog.ErrorS(err, "Failed to apply ExternalSecret for cloud credential", "name", name)
+			common.Fail(c, err)
+			return
+		}
+		secret.Annotations[externalsecret.ReferenceAnnotation] = secret.Name
+		delete(secret.Data, credentialsKey)
+	} else if req.Credentials != "" {
+		if err := deleteCredentialsRef(c, secret); err != nil {
+			klog.ErrorS(err, "Failed to delete ExternalSecret of cloud credential", "name", name)
+			common.Fail(c, err)
+			return
 		}
+		delete(secret.Annotations, externalsecret.ReferenceAnnotation)
+		secret.Data[credentialsKey] = []byte(req.Credentials)
+	}
+	if req.Description != "" {
 		secret.Annotations["description"] = req.Description
 	}
 
@@ -267,11 +345,12 @@ func handleUpdateCloudCredential(c *gin.Context) {
 	}
 
 	cred := CloudCredential{
-		Name:        updatedSecret.Name,
-		Provider:    updatedSecret.Labels[ProviderLabelKey],
-		Description: updatedSecret.Annotations["description"],
-		CreatedAt:   updatedSecret.CreationTimestamp.Format("2006-01-02 15:04:05"),
-		Labels:      updatedSecret.Labels,
+		Name:           updatedSecret.Name,
+		Provider:       updatedSecret.Labels[ProviderLabelKey],
+		Description:    updatedSecret.Annotations["description"],
+		CreatedAt:      updatedSecret.CreationTimestamp.Format("2006-01-02 15:04:05"),
+		Labels:         updatedSecret.Labels,
+		ExternalSecret: externalsecret.Reference(updatedSecret),
 	}
 
 	common.Success(c, cred)
@@ -306,6 +385,12 @@ func handleDeleteCloudCredential(c *gin.Context) {
 		return
 	}
 
+	if err := deleteCredentialsRef(c, secret); err != nil {
+		klog.ErrorS(err, "Failed to delete ExternalSecret of cloud credential", "name", name)
+		common.Fail(c, err)
+		return
+	}
+
 	err = k8sClient.CoreV1().Secrets(CloudCredentialsNamespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
 	if err != nil {
 		klog.ErrorS(err, "Failed to delete cloud credential", "name", name)
@@ -343,6 +428,24 @@ func handleGetCredentialContent(c *gin.Context) {
 		return
 	}
 
+	// Credentials read from an external store are not returned, only where they are read from
+	if reference := externalsecret.Reference(secret); reference != "" {
+		result := gin.H{
+			"name":           secret.Name,
+			"provider":       secret.Labels[ProviderLabelKey],
+			"externalSecret": reference,
+			"synced":         len(secret.Data[credentialsKey]) > 0,
+			"description":    secret.Annotations["description"],
+		}
+		if dynamicClient, err := client.GetDynamicClient(); err == nil {
+			if obj, err := dynamicClient.Resource(externalsecret.Resource).Namespace(secret.Namespace).Get(c, reference, metav1.GetOptions{}); err == nil {
+				result["credentialsRef"] = externalsecret.Refs(obj)[credentialsKey]
+			}
+		}
+		common.Success(c, result)
+		return
+	}
+
 	// Return credential content (base64 encoded for security)
 	credContent := base64.StdEncoding.EncodeToString(secret.Data["credentials"])
 
@@ -363,7 +466,3 @@ func init() {
 	r.PUT("/cloudcredentials/:name", handleUpdateCloudCredential)
 	r.DELETE("/cloudcredentials/:name", handleDeleteCloudCredential)
 }
-
-
-
-
@@ -64,14 +64,14 @@ type pullModeOption struct {
 	memberClusterEndpoint  string
 }
 
-// createSecretAndRBACInMemberCluster create required secrets and rbac in member cluster
-func (o pullModeOption) createSecretAndRBACInMemberCluster() error {
+// makeKarmadaKubeconfigSecret generate the Secret holding the kubeconfig karmada-agent connects to Karmada with
+func (o pullModeOption) makeKarmadaKubeconfigSecret() (*corev1.Secret, error) {
 	configBytes, err := clientcmd.Write(*o.karmadaAgentCfg)
 	if err != nil {
-		return fmt.Errorf("failure while serializing karmada-agent kubeConfig. %w", err)
+		return nil, fmt.Errorf("failure while serializing karmada-agent kubeConfig. %w", err)
 	}
 
-	kubeConfigSecret := &corev1.Secret{
+	return &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "Secret",
@@ -82,14 +82,16 @@ func (o pullModeOption) createSecretAndRBACInMemberCluster() error {
 		},
 		Type:       corev1.SecretTypeOpaque,
 		StringData: map[string]string{KarmadaKubeconfigName: string(configBytes)},
-	}
-
-	// create karmada-kubeconfig secret to be used by karmada-agent component.
-	if err := cmdutil.CreateOrUpdateSecret(o.memberClusterClient, kubeConfigSecret); err != nil {
-		return fmt.Errorf("create secret %s failed: %v", kubeConfigSecret.Name, err)
-	}
+	}, nil
+}
 
+// makeKarmadaAgentRBAC generate the ServiceAccount of karmada-agent and the ClusterRole granted to it
+func (o pullModeOption) makeKarmadaAgentRBAC() (*rbacv1.ClusterRole, *corev1.ServiceAccount, *rbacv1.ClusterRoleBinding) {
 	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: KarmadaAgentName,
 		},
@@ -106,25 +108,22 @@ func (o pullModeOption) createSecretAndRBACInMemberCluster() error {
 		},
 	}
 
-	// create a karmada-agent ClusterRole in member cluster.
-	if err := cmdutil.CreateOrUpdateClusterRole(o.memberClusterClient, clusterRole); err != nil {
-		return err
-	}
-
 	sa := &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ServiceAccount",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      KarmadaAgentServiceAccountName,
 			Namespace: o.memberClusterNamespace,
 		},
 	}
 
-	// create service account for karmada-agent
-	_, err = karmadautil.EnsureServiceAccountExist(o.memberClusterClient, sa, false)
-	if err != nil {
-		return err
-	}
-
 	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRoleBinding",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: KarmadaAgentName,
 		},
@@ -141,6 +140,33 @@ func (o pullModeOption) createSecretAndRBACInMemberCluster() error {
 			},
 		},
 	}
+	return clusterRole, sa, clusterRoleBinding
+}
+
+// createSecretAndRBACInMemberCluster create required secrets and rbac in member cluster
+func (o pullModeOption) createSecretAndRBACInMemberCluster() error {
+	kubeConfigSecret, err := o.makeKarmadaKubeconfigSecret()
+	if err != nil {
+		return err
+	}
+
+	// create karmada-kubeconfig secret to be used by karmada-agent component.
+	if err := cmdutil.CreateOrUpdateSecret(o.memberClusterClient, kubeConfigSecret); err != nil {
+		return fmt.Errorf("create secret %s failed: %v", kubeConfigSecret.Name, err)
+	}
+
+	clusterRole, sa, clusterRoleBinding := o.makeKarmadaAgentRBAC()
+
+	// create a karmada-agent ClusterRole in member cluster.
+	if err := cmdutil.CreateOrUpdateClusterRole(o.memberClusterClient, clusterRole); err != nil {
+		return err
+	}
+
+	// create service account for karmada-agent
+	_, err = karmadautil.EnsureServiceAccountExist(o.memberClusterClient, sa, false)
+	if err != nil {
+		return err
+	}
 
 	// grant karmada-agent clusterrole to karmada-agent service account
 	if err := cmdutil.CreateOrUpdateClusterRoleBinding(o.memberClusterClient, clusterRoleBinding); err != nil {
@@ -180,7 +206,6 @@ func (o pullModeOption) makeKarmadaAgentDeployment() *appsv1.Deployment {
 					"/bin/karmada-agent",
 					"--karmada-kubeconfig=/etc/kubeconfig/karmada-kubeconfig",
 					fmt.Sprintf("--cluster-name=%s", o.memberClusterName),
-					fmt.Sprintf("--cluster-api-endpoint=%s", o.memberClusterEndpoint),
 					//fmt.Sprintf("--cluster-provider=%s", clusterProvider),
 					//fmt.Sprintf("--cluster-region=%s", clusterRegion),
 					//fmt.Sprintf("--cluster-zones=%s", strings.Join(clusterZones, ",")),
@@ -218,6 +243,9 @@ func (o pullModeOption) makeKarmadaAgentDeployment() *appsv1.Deployment {
 			},
 		},
 	}
+	if o.memberClusterEndpoint != "" {
+		podSpec.Containers[0].Command = append(podSpec.Containers[0].Command, fmt.Sprintf("--cluster-api-endpoint=%s", o.memberClusterEndpoint))
+	}
 	// PodTemplateSpec
 	podTemplateSpec := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
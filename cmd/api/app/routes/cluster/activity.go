@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// handleGetClusterActivity returns the recent changes made through the API that affected a cluster, such as
+// controller installs, backups, recoveries and ArgoCD syncs, newest first
+func handleGetClusterActivity(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	limit, err := audit.ParseFeedLimit(c.Query("limit"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	entries, err := audit.Entries(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, audit.Filter(entries, func(e audit.Entry) bool { return e.HasCluster(clusterName) }, limit))
+}
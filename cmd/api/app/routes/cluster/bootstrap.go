@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// defaultAgentNamespace is the namespace karmada-agent runs in when none is given
+const defaultAgentNamespace = "karmada-system"
+
+// renderManifests returns objects as a multi-document YAML manifest
+func renderManifests(objects ...interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// handleGetClusterBootstrapManifest renders the manifests that join a cluster in Pull mode when applied
+// to it: the namespace, the Secret with the Karmada kubeconfig, the RBAC and the Deployment of
+// karmada-agent. Query parameters: name of the cluster, mode (only pull), namespace of the agent
+// (default karmada-system) and endpoint, the API server address of the cluster reported to Karmada.
+// The Secret holds the Karmada credentials of the dashboard, so the manifest must be handled as a secret.
+func handleGetClusterBootstrapManifest(c *gin.Context) {
+	name := c.Query("name")
+	namespace := c.DefaultQuery("namespace", defaultAgentNamespace)
+	mode := c.DefaultQuery("mode", strings.ToLower(string(clusterv1alpha1.Pull)))
+	if !strings.EqualFold(mode, string(clusterv1alpha1.Pull)) {
+		common.FailWithStatus(c, fmt.Errorf("only pull mode clusters are joined with a bootstrap manifest, join push mode clusters with POST /cluster/join"), http.StatusBadRequest)
+		return
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		common.FailWithStatus(c, fmt.Errorf("invalid cluster name %q: %s", name, strings.Join(errs, ", ")), http.StatusBadRequest)
+		return
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		common.FailWithStatus(c, fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	_, exist, err := karmadautil.GetClusterWithKarmadaClient(client.InClusterKarmadaClient(), name)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if exist {
+		common.FailWithStatus(c, fmt.Errorf("cluster %s already exists", name), http.StatusConflict)
+		return
+	}
+	_, apiConfig, err := client.GetKarmadaConfig()
+	if err != nil {
+		klog.ErrorS(err, "Get apiConfig for karmada failed")
+		common.Fail(c, err)
+		return
+	}
+
+	opts := pullModeOption{
+		karmadaAgentCfg:        apiConfig,
+		memberClusterNamespace: namespace,
+		memberClusterName:      name,
+		memberClusterEndpoint:  c.Query("endpoint"),
+	}
+	agentNamespace := &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{karmadautil.ManagedByKarmadaLabel: karmadautil.ManagedByKarmadaLabelValue},
+		},
+	}
+	secret, err := opts.makeKarmadaKubeconfigSecret()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	clusterRole, serviceAccount, clusterRoleBinding := opts.makeKarmadaAgentRBAC()
+	manifest, err := renderManifests(agentNamespace, secret, clusterRole, serviceAccount, clusterRoleBinding, opts.makeKarmadaAgentDeployment())
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	klog.InfoS("Rendered pull mode bootstrap manifest", "cluster", name, "namespace", namespace)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-karmada-agent.yaml"`, name))
+	c.Data(http.StatusOK, "application/yaml", manifest)
+}
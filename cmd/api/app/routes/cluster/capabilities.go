@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+)
+
+// handleGetClusterCapabilities returns the Kubernetes version of a member cluster and the versions of the
+// optional resources it serves. The capabilities are cached, refresh=true detects them again.
+func handleGetClusterCapabilities(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if c.Query("refresh") == "true" {
+		capability.Invalidate(clusterName)
+	}
+	capabilities, err := capability.For(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to detect cluster capabilities", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, capabilities)
+}
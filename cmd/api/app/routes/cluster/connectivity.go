@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// connectivityTestTimeout bounds each request of a connectivity test
+const connectivityTestTimeout = 10 * time.Second
+
+// handleTestClusterConnectivity probes a member cluster and returns a report of what works: reachability
+// and latency of its API server, its version and the permissions of the dashboard. A failing cluster is
+// reported, not returned as an error.
+func handleTestClusterConnectivity(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	_, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		common.Fail(c, pkgerrors.NewNotFound("cluster "+clusterName+" not found"))
+		return
+	}
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	// A test resolves the access path again, so a cluster that recovered is not reported from the cache
+	client.ResetMemberAccess(clusterName)
+	capability.Invalidate(clusterName)
+	config, path, err := client.MemberConfig(c, clusterName)
+	if err != nil {
+		klog.InfoS("Member cluster connectivity test failed", "cluster", clusterName, "error", err)
+		common.Success(c, cluster.FailConnectivity(clusterName, err))
+		return
+	}
+	client.SetMemberAccessPath(c, clusterName, path)
+
+	testConfig := rest.CopyConfig(config)
+	testConfig.Timeout = connectivityTestTimeout
+	kubeClient, err := kubernetes.NewForConfig(testConfig)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	report := cluster.TestConnectivity(c, clusterName, kubeClient)
+	report.AccessPath = string(path)
+	if !report.Success {
+		klog.InfoS("Member cluster connectivity test failed", "cluster", clusterName, "accessPath", path)
+	}
+	common.Success(c, report)
+}
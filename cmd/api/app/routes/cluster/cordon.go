@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// Cordon actions
+const (
+	cordonCordon   = "cordon"
+	cordonUncordon = "uncordon"
+)
+
+// CordonRequest cordons a cluster from new backup configurations and migrations, or uncordons it
+type CordonRequest struct {
+	Action string `json:"action" binding:"required,oneof=cordon uncordon"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CordonResult is the cordon of a cluster after a request
+type CordonResult struct {
+	Cluster string          `json:"cluster"`
+	Cordon  *cluster.Cordon `json:"cordon,omitempty"`
+}
+
+// handleClusterCordon cordons or uncordons a cluster. Backup configurations cannot be created or updated on
+// a cordoned cluster and workloads cannot be migrated onto it, while its existing backup configurations
+// keep running, e.g. while the cluster is decommissioned. Only admins and owners of the cluster can change
+// its cordon.
+func handleClusterCordon(c *gin.Context) {
+	clusterName := c.Param("name")
+	var req CordonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := checkClusterOwnerAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	karmadaClient := client.InClusterKarmadaClient()
+	result := CordonResult{Cluster: clusterName}
+	switch req.Action {
+	case cordonCordon:
+		clusterObj, err := cluster.CordonCluster(c, karmadaClient, clusterName, cluster.Cordon{
+			Since:  time.Now().UTC().Format(time.RFC3339),
+			By:     utilauth.GetAuthenticatedUser(c),
+			Reason: req.Reason,
+		})
+		if err != nil {
+			failClusterUpdate(c, clusterName, err)
+			return
+		}
+		result.Cordon = cluster.CordonOf(clusterObj)
+		klog.InfoS("Cluster cordoned", "cluster", clusterName, "user", result.Cordon.By, "reason", result.Cordon.Reason)
+	case cordonUncordon:
+		if _, err := cluster.UncordonCluster(c, karmadaClient, clusterName); err != nil {
+			failClusterUpdate(c, clusterName, err)
+			return
+		}
+		klog.InfoS("Cluster uncordoned", "cluster", clusterName, "user", utilauth.GetAuthenticatedUser(c))
+	}
+	common.Success(c, result)
+}
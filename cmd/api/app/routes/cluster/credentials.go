@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// CredentialRotationResult is the outcome of a credential rotation of a member cluster
+type CredentialRotationResult struct {
+	cluster.CredentialRotation
+	// Revoked lists the old token secrets deleted from the member cluster
+	Revoked      []string                    `json:"revoked,omitempty"`
+	Connectivity *cluster.ConnectivityReport `json:"connectivity,omitempty"`
+}
+
+// checkClusterOwnerAccess ensures the user of the request is an admin or an owner of the cluster. Members
+// can use the cluster but not change how it is managed. Without OpenFGA only dashboard admins pass.
+func checkClusterOwnerAccess(c *gin.Context, clusterName string) error {
+	username := utilauth.GetAuthenticatedUser(c)
+	if username == "" {
+		return pkgerrors.NewUnauthorized("authentication required")
+	}
+	if fga.FGAService == nil || fga.FGAService.GetClient() == nil {
+		if router.IsDashboardAdmin(c) {
+			return nil
+		}
+		return pkgerrors.NewForbidden(clusterName, fmt.Errorf("user %s is not an administrator", username))
+	}
+	allowed, err := fga.HasClusterOwnerAccess(c, fga.FGAService.GetClient(), username, clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to check cluster access: %w", err)
+	}
+	if !allowed {
+		return pkgerrors.NewForbidden(clusterName, fmt.Errorf("user %s is not an owner of cluster %s", username, clusterName))
+	}
+	return nil
+}
+
+// handleRotateClusterCredentials replaces the ServiceAccount token the control plane uses to access a push
+// mode cluster. The new token is verified against the cluster before the stored secret is updated and the
+// old tokens are revoked, so a failed rotation leaves the current credentials in place.
+func handleRotateClusterCredentials(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := checkClusterOwnerAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	clusterObj, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		common.Fail(c, pkgerrors.NewNotFound("cluster "+clusterName+" not found"))
+		return
+	}
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if clusterObj.Spec.SyncMode != clusterv1alpha1.Push || clusterObj.Spec.SecretRef == nil {
+		common.FailWithStatus(c, fmt.Errorf("cluster %s is not in push mode with stored credentials", clusterName), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	result := &CredentialRotationResult{CredentialRotation: cluster.CredentialRotation{
+		Time:    now.UTC().Format(time.RFC3339),
+		User:    utilauth.GetAuthenticatedUser(c),
+		Cluster: clusterName,
+	}}
+	revoked, report, err := rotateClusterCredentials(c, clusterObj, result, now)
+	result.Revoked = revoked
+	result.Connectivity = report
+	result.Success = err == nil
+	if err != nil {
+		result.Message = err.Error()
+	}
+	if recordErr := cluster.RecordCredentialRotation(c, client.InClusterClient(), config.GetNamespace(), result.CredentialRotation); recordErr != nil {
+		klog.ErrorS(recordErr, "Failed to record credential rotation", "cluster", clusterName)
+	}
+	if err != nil {
+		klog.ErrorS(err, "Cluster credential rotation failed", "cluster", clusterName, "user", result.User)
+		common.FailWithData(c, err, http.StatusOK, result)
+		return
+	}
+	klog.InfoS("Rotated cluster credentials", "cluster", clusterName, "user", result.User, "secret", result.Secret, "revoked", len(revoked))
+	common.Success(c, result)
+}
+
+// rotateClusterCredentials creates a new token in the member cluster, verifies it and stores it in the
+// secret of the cluster. The new token is deleted again when it cannot be verified.
+func rotateClusterCredentials(c *gin.Context, clusterObj *clusterv1alpha1.Cluster, result *CredentialRotationResult, now time.Time) ([]string, *cluster.ConnectivityReport, error) {
+	clusterName := clusterObj.Name
+	memberConfig, _, err := client.MemberConfig(c, clusterName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to access cluster %s: %w", clusterName, err)
+	}
+	memberClient, err := kubernetes.NewForConfig(memberConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serviceAccount := names.GenerateServiceAccountName(clusterName)
+	token, err := cluster.NewServiceAccountToken(c, memberClient, ClusterNamespace, serviceAccount, now)
+	if token != nil {
+		result.Secret = token.Name
+	}
+	if err != nil {
+		deleteTokenSecret(c, memberClient, token)
+		return nil, nil, err
+	}
+
+	secrets := client.InClusterClientForKarmadaAPIServer().CoreV1().Secrets(clusterObj.Spec.SecretRef.Namespace)
+	stored, err := secrets.Get(c, clusterObj.Spec.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		deleteTokenSecret(c, memberClient, token)
+		return nil, nil, fmt.Errorf("failed to get the credentials of cluster %s: %w", clusterName, err)
+	}
+	rotated := stored.DeepCopy()
+	if rotated.Data == nil {
+		rotated.Data = map[string][]byte{}
+	}
+	rotated.Data[clusterv1alpha1.SecretTokenKey] = token.Data[corev1.ServiceAccountTokenKey]
+	if ca := token.Data[corev1.ServiceAccountRootCAKey]; len(ca) > 0 {
+		rotated.Data[clusterv1alpha1.SecretCADataKey] = ca
+	}
+
+	// The new token is tested the way the control plane uses it: directly against the API endpoint of the cluster
+	testConfig, err := karmadautil.BuildClusterConfig(clusterName,
+		func(string) (*clusterv1alpha1.Cluster, error) { return clusterObj, nil },
+		func(string, string) (*corev1.Secret, error) { return rotated, nil })
+	if err != nil {
+		deleteTokenSecret(c, memberClient, token)
+		return nil, nil, fmt.Errorf("failed to build a config with the new credentials: %w", err)
+	}
+	testConfig.Timeout = connectivityTestTimeout
+	testClient, err := kubernetes.NewForConfig(testConfig)
+	if err != nil {
+		deleteTokenSecret(c, memberClient, token)
+		return nil, nil, err
+	}
+	report := cluster.TestConnectivity(c, clusterName, testClient)
+	if !report.Success {
+		deleteTokenSecret(c, memberClient, token)
+		return nil, report, fmt.Errorf("cluster %s is not accessible with the new credentials", clusterName)
+	}
+
+	if _, err := secrets.Update(c, rotated, metav1.UpdateOptions{}); err != nil {
+		deleteTokenSecret(c, memberClient, token)
+		return nil, report, fmt.Errorf("failed to store the new credentials of cluster %s: %w", clusterName, err)
+	}
+	client.ResetMemberAccess(clusterName)
+	capability.Invalidate(clusterName)
+
+	// The new credentials are in use, so a failure to revoke the old tokens does not fail the rotation
+	revoked, err := cluster.DeleteServiceAccountTokens(c, memberClient, ClusterNamespace, serviceAccount, token.Name)
+	if err != nil {
+		klog.ErrorS(err, "Failed to revoke old cluster tokens", "cluster", clusterName)
+		result.Message = err.Error()
+	}
+	return revoked, report, nil
+}
+
+// deleteTokenSecret removes a token secret of a failed rotation from the member cluster
+func deleteTokenSecret(c *gin.Context, memberClient kubernetes.Interface, token *corev1.Secret) {
+	if token == nil {
+		return
+	}
+	if err := memberClient.CoreV1().Secrets(token.Namespace).Delete(c, token.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to delete token secret of a failed rotation", "namespace", token.Namespace, "name", token.Name)
+	}
+}
+
+// handleGetClusterCredentialRotations returns the credential rotations of a cluster, newest last
+func handleGetClusterCredentialRotations(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	rotations, err := cluster.GetCredentialRotations(c, client.InClusterClient(), config.GetNamespace(), clusterName)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, rotations)
+}
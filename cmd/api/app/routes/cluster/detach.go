@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// dashboardNamespaces are the namespaces the dashboard creates in member clusters
+var dashboardNamespaces = []string{UserAccessNamespace, JoinNamespace, "stateful-migration"}
+
+// DetachStep is the outcome of one cleanup step of a cluster detach
+type DetachStep struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// DetachReport lists the cleanup steps run before the cluster was removed from Karmada
+type DetachReport struct {
+	Cluster string       `json:"cluster"`
+	Steps   []DetachStep `json:"steps"`
+}
+
+func (r *DetachReport) record(name string, err error, message string) {
+	step := DetachStep{Name: name, Success: err == nil, Message: message}
+	if err != nil {
+		klog.ErrorS(err, "Cluster detach step failed", "cluster", r.Cluster, "step", name)
+		step.Message = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+// detachCluster removes the platform components of a cluster before its Cluster object is deleted.
+// Steps are best effort, so an unreachable cluster can still be removed.
+func detachCluster(ctx context.Context, karmadaClient karmadaclientset.Interface, clusterName string, cleanNamespaces bool) *DetachReport {
+	report := &DetachReport{Cluster: clusterName, Steps: []DetachStep{}}
+
+	err := backup.UninstallMigrationController(clusterName)
+	report.record("uninstall-migration-controller", err, "")
+
+	deleted, updated, err := removeClusterFromPropagationPolicies(ctx, karmadaClient, clusterName)
+	report.record("propagation-policies", err, fmt.Sprintf("deleted %d, updated %d", deleted, updated))
+
+	tuples, err := cluster.DeleteClusterUserTuples(ctx, clusterName)
+	report.record("user-permissions", err, fmt.Sprintf("deleted %d", tuples))
+
+	if cleanNamespaces {
+		err = cleanupDashboardNamespaces(ctx, clusterName)
+		report.record("dashboard-namespaces", err, "")
+	}
+	return report
+}
+
+// targetsCluster returns the cluster names of the placement without clusterName, and whether it was listed
+func targetsCluster(placement *policyv1alpha1.Placement, clusterName string) ([]string, bool) {
+	if placement.ClusterAffinity == nil {
+		return nil, false
+	}
+	found := false
+	remaining := make([]string, 0, len(placement.ClusterAffinity.ClusterNames))
+	for _, name := range placement.ClusterAffinity.ClusterNames {
+		if name == clusterName {
+			found = true
+			continue
+		}
+		remaining = append(remaining, name)
+	}
+	return remaining, found
+}
+
+// removeClusterFromPropagationPolicies deletes the policies that only target the cluster
+// and drops the cluster from the ones that target several clusters, like user profiles
+func removeClusterFromPropagationPolicies(ctx context.Context, karmadaClient karmadaclientset.Interface, clusterName string) (int, int, error) {
+	deleted, updated := 0, 0
+
+	policies, err := karmadaClient.PolicyV1alpha1().PropagationPolicies(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return deleted, updated, fmt.Errorf("failed to list propagation policies: %w", err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		remaining, found := targetsCluster(&policy.Spec.Placement, clusterName)
+		if !found {
+			continue
+		}
+		policyClient := karmadaClient.PolicyV1alpha1().PropagationPolicies(policy.Namespace)
+		if len(remaining) == 0 {
+			if err := policyClient.Delete(ctx, policy.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return deleted, updated, fmt.Errorf("failed to delete propagation policy %s/%s: %w", policy.Namespace, policy.Name, err)
+			}
+			deleted++
+			continue
+		}
+		policy.Spec.Placement.ClusterAffinity.ClusterNames = remaining
+		if _, err := policyClient.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+			return deleted, updated, fmt.Errorf("failed to update propagation policy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		updated++
+	}
+
+	clusterPolicies, err := karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return deleted, updated, fmt.Errorf("failed to list cluster propagation policies: %w", err)
+	}
+	for i := range clusterPolicies.Items {
+		policy := &clusterPolicies.Items[i]
+		remaining, found := targetsCluster(&policy.Spec.Placement, clusterName)
+		if !found {
+			continue
+		}
+		policyClient := karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies()
+		if len(remaining) == 0 {
+			if err := policyClient.Delete(ctx, policy.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return deleted, updated, fmt.Errorf("failed to delete cluster propagation policy %s: %w", policy.Name, err)
+			}
+			deleted++
+			continue
+		}
+		policy.Spec.Placement.ClusterAffinity.ClusterNames = remaining
+		if _, err := policyClient.Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+			return deleted, updated, fmt.Errorf("failed to update cluster propagation policy %s: %w", policy.Name, err)
+		}
+		updated++
+	}
+	return deleted, updated, nil
+}
+
+// cleanupDashboardNamespaces deletes the namespaces and bindings the dashboard created in the member cluster
+func cleanupDashboardNamespaces(ctx context.Context, clusterName string) error {
+	memberClient := client.InClusterClientForMemberCluster(clusterName)
+	if memberClient == nil {
+		return fmt.Errorf("failed to get client for cluster %s", clusterName)
+	}
+
+	bindings, err := memberClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{
+		LabelSelector: userAccessLabelKey + "=true",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list user access bindings: %w", err)
+	}
+	names := []string{JoinServiceAccountName}
+	for _, binding := range bindings.Items {
+		names = append(names, binding.Name)
+	}
+	for _, name := range names {
+		if err := memberClient.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete cluster role binding %s: %w", name, err)
+		}
+	}
+
+	for _, namespace := range dashboardNamespaces {
+		if err := memberClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete namespace %s: %w", namespace, err)
+		}
+	}
+	return nil
+}
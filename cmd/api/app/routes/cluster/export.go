@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+	"github.com/karmada-io/dashboard/pkg/resource/migration"
+)
+
+// handleGetClusterExport returns the inventory of all member clusters as a downloadable file: Kubernetes
+// version, node counts, readiness, migration controller status and version, ArgoCD presence and owners.
+// format=csv exports CSV, JSON otherwise. Details that cannot be read are listed in the errors of the entry.
+func handleGetClusterExport(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		common.FailWithStatus(c, fmt.Errorf("format must be json or csv"), http.StatusBadRequest)
+		return
+	}
+
+	clusterList, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(c, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list member clusters")
+		common.Fail(c, err)
+		return
+	}
+	names := make([]string, 0, len(clusterList.Items))
+	byName := make(map[string]*clusterv1alpha1.Cluster, len(clusterList.Items))
+	for i := range clusterList.Items {
+		names = append(names, clusterList.Items[i].Name)
+		byName[clusterList.Items[i].Name] = &clusterList.Items[i]
+	}
+	owners, ownersErr := cluster.OwnersByCluster(c, names)
+	if ownersErr != nil {
+		klog.ErrorS(ownersErr, "Failed to list cluster owners for the inventory export")
+	}
+
+	entries, _ := multicluster.Gather(c, names, func(ctx context.Context, name string) (cluster.InventoryEntry, error) {
+		entry := cluster.NewInventoryEntry(byName[name])
+		if ownersErr != nil {
+			entry.Errors = append(entry.Errors, fmt.Sprintf("owners: %v", ownersErr))
+		} else if len(owners[name]) > 0 {
+			entry.Owners = owners[name]
+		}
+		if !entry.Ready {
+			entry.MigrationControllerStatus = migration.StatusUnknown
+			return entry, nil
+		}
+		status, version, err := backup.MigrationControllerStatus(c, name)
+		entry.MigrationControllerStatus = status
+		entry.MigrationControllerVersion = version
+		if err != nil {
+			entry.Errors = append(entry.Errors, fmt.Sprintf("migration controller: %v", err))
+		}
+		entry.ArgoCD = argocd.Installed(ctx, name)
+		return entry, nil
+	})
+	if entries == nil {
+		entries = []cluster.InventoryEntry{}
+	}
+
+	filename := fmt.Sprintf("cluster-inventory-%s", time.Now().Format("20060102"))
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	c.Status(http.StatusOK)
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(cluster.InventoryColumns)
+	for _, entry := range entries {
+		_ = writer.Write(entry.CSVRecord())
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		klog.ErrorS(err, "Failed to write cluster inventory CSV")
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	platformv1 "github.com/karmada-io/dashboard/pkg/apis/platform/v1"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/dataselect"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// clusterServer serves the clusters over gRPC with the same permission filter as GET /cluster
+type clusterServer struct {
+	platformv1.UnimplementedClusterServiceServer
+}
+
+// ListClusters lists the clusters the caller has access to
+func (s *clusterServer) ListClusters(ctx context.Context, req *platformv1.ListClustersRequest) (*platformv1.ListClustersResponse, error) {
+	// Like the REST query parameters, pages start from 1
+	pagination := dataselect.NoPagination
+	if req.GetItemsPerPage() > 0 && req.GetPage() > 0 {
+		pagination = dataselect.NewPaginationQuery(int(req.GetItemsPerPage()), int(req.GetPage()-1))
+	}
+	dataSelect := dataselect.NewDataSelectQuery(pagination,
+		dataselect.NewSortQuery(strings.Split(req.GetSortBy(), ",")),
+		dataselect.NewFilterQuery(strings.Split(req.GetFilterBy(), ",")))
+
+	result, err := cluster.GetClusterList(client.InClusterKarmadaClient(), dataSelect, router.GRPCUser(ctx))
+	if err != nil {
+		klog.ErrorS(err, "GetClusterList failed")
+		return nil, router.GRPCError(err)
+	}
+	resp := &platformv1.ListClustersResponse{TotalItems: int32(result.ListMeta.TotalItems)}
+	for i := range result.Clusters {
+		resp.Clusters = append(resp.Clusters, clusterMessage(&result.Clusters[i]))
+	}
+	return resp, nil
+}
+
+// GetCluster returns a cluster the caller has access to
+func (s *clusterServer) GetCluster(ctx context.Context, req *platformv1.GetClusterRequest) (*platformv1.Cluster, error) {
+	result, err := cluster.GetClusterList(client.InClusterKarmadaClient(), dataselect.NoDataSelect, router.GRPCUser(ctx))
+	if err != nil {
+		klog.ErrorS(err, "GetClusterList failed")
+		return nil, router.GRPCError(err)
+	}
+	for i := range result.Clusters {
+		if result.Clusters[i].ObjectMeta.Name == req.GetName() {
+			return clusterMessage(&result.Clusters[i]), nil
+		}
+	}
+	return nil, router.GRPCErrorWithStatus(fmt.Errorf("cluster %s not found", req.GetName()), http.StatusNotFound)
+}
+
+// clusterMessage returns the gRPC message of a cluster
+func clusterMessage(c *cluster.Cluster) *platformv1.Cluster {
+	message := &platformv1.Cluster{
+		Name:              c.ObjectMeta.Name,
+		Labels:            c.ObjectMeta.Labels,
+		Ready:             string(c.Ready),
+		KubernetesVersion: c.KubernetesVersion,
+		SyncMode:          string(c.SyncMode),
+		AllocatedResources: &platformv1.AllocatedResources{
+			CpuCapacity:    c.AllocatedResources.CPUCapacity,
+			CpuFraction:    c.AllocatedResources.CPUFraction,
+			MemoryCapacity: c.AllocatedResources.MemoryCapacity,
+			MemoryFraction: c.AllocatedResources.MemoryFraction,
+			AllocatedPods:  c.AllocatedResources.AllocatedPods,
+			PodCapacity:    c.AllocatedResources.PodCapacity,
+			PodFraction:    c.AllocatedResources.PodFraction,
+		},
+	}
+	if !c.ObjectMeta.CreationTimestamp.IsZero() {
+		message.CreationTimestamp = c.ObjectMeta.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	if c.NodeSummary != nil {
+		message.TotalNodes = c.NodeSummary.TotalNum
+		message.ReadyNodes = c.NodeSummary.ReadyNum
+	}
+	return message
+}
+
+func init() {
+	platformv1.RegisterClusterServiceServer(router.GRPC(), &clusterServer{})
+	router.RegisterGateway(platformv1.RegisterClusterServiceHandlerFromEndpoint)
+}
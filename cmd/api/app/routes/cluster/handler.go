@@ -30,10 +30,14 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/projects"
 	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
 	"github.com/karmada-io/dashboard/pkg/auth/fga"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/externalsecret"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
 )
@@ -45,8 +49,19 @@ func handleGetClusterList(c *gin.Context) {
 	// Get the authenticated username
 	username := utilauth.GetAuthenticatedUser(c)
 
+	p, err := projects.FromQuery(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
 	// Call GetClusterList with the username to filter by permissions
-	result, err := cluster.GetClusterList(karmadaClient, dataSelect, username)
+	var result *cluster.ClusterList
+	if p != nil {
+		result, err = cluster.GetClusterListInProject(karmadaClient, dataSelect, username, p.ClusterNames())
+	} else {
+		result, err = cluster.GetClusterList(karmadaClient, dataSelect, username)
+	}
 	if err != nil {
 		klog.ErrorS(err, "GetClusterList failed")
 		common.Fail(c, err)
@@ -71,7 +86,7 @@ func handlePostCluster(c *gin.Context) {
 	clusterRequest := new(v1.PostClusterRequest)
 	if err := c.ShouldBind(clusterRequest); err != nil {
 		klog.ErrorS(err, "Could not read cluster request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	memberClusterEndpoint, err := parseEndpointFromKubeconfig(clusterRequest.MemberClusterKubeConfig)
@@ -148,7 +163,7 @@ func handlePutCluster(c *gin.Context) {
 	name := c.Param("name")
 	if err := c.ShouldBind(clusterRequest); err != nil {
 		klog.ErrorS(err, "Could not read handlePutCluster request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	karmadaClient := client.InClusterKarmadaClient()
@@ -159,25 +174,53 @@ func handlePutCluster(c *gin.Context) {
 		return
 	}
 
-	// assume that the frontend can fetch the whole labels and taints
 	labels := make(map[string]string)
 	if clusterRequest.Labels != nil {
 		for _, labelItem := range *clusterRequest.Labels {
 			labels[labelItem.Key] = labelItem.Value
 		}
-		memberCluster.Labels = labels
 	}
-
 	taints := make([]corev1.Taint, 0)
 	if clusterRequest.Taints != nil {
-		for _, taintItem := range *clusterRequest.Taints {
-			taints = append(taints, corev1.Taint{
-				Key:    taintItem.Key,
-				Value:  taintItem.Value,
-				Effect: taintItem.Effect,
-			})
+		taints = toTaints(*clusterRequest.Taints)
+	}
+	if err := cluster.ValidateLabels(labels, memberCluster.Labels); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := cluster.ValidateTaints(taints, memberCluster.Spec.Taints); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	switch clusterRequest.Mode {
+	case "", v1.PutClusterModeReplace:
+		// the frontend sends the whole labels and taints, keep the ones managed by karmada
+		if clusterRequest.Labels != nil {
+			memberCluster.Labels = cluster.PreserveReservedLabels(labels, memberCluster.Labels)
 		}
-		memberCluster.Spec.Taints = taints
+		if clusterRequest.Taints != nil {
+			memberCluster.Spec.Taints = cluster.PreserveReservedTaints(taints, memberCluster.Spec.Taints)
+		}
+	case v1.PutClusterModeMerge:
+		for _, key := range clusterRequest.RemoveLabels {
+			if cluster.IsReservedKey(key) {
+				common.Fail(c, fmt.Errorf("label key %q uses a reserved prefix", key))
+				return
+			}
+		}
+		removeTaints := toTaints(clusterRequest.RemoveTaints)
+		for _, taint := range removeTaints {
+			if cluster.IsReservedKey(taint.Key) {
+				common.Fail(c, fmt.Errorf("taint key %q uses a reserved prefix", taint.Key))
+				return
+			}
+		}
+		memberCluster.Labels = cluster.MergeLabels(memberCluster.Labels, labels, clusterRequest.RemoveLabels)
+		memberCluster.Spec.Taints = cluster.MergeTaints(memberCluster.Spec.Taints, taints, removeTaints)
+	default:
+		common.Fail(c, fmt.Errorf("unsupported update mode: %s", clusterRequest.Mode))
+		return
 	}
 
 	_, err = karmadaClient.ClusterV1alpha1().Clusters().Update(context.TODO(), memberCluster, metav1.UpdateOptions{})
@@ -189,18 +232,56 @@ func handlePutCluster(c *gin.Context) {
 	common.Success(c, "ok")
 }
 
+func toTaints(items []v1.TaintRequest) []corev1.Taint {
+	taints := make([]corev1.Taint, 0, len(items))
+	for _, taintItem := range items {
+		taints = append(taints, corev1.Taint{
+			Key:    taintItem.Key,
+			Value:  taintItem.Value,
+			Effect: taintItem.Effect,
+		})
+	}
+	return taints
+}
+
+func handleGetClusterGroups(c *gin.Context) {
+	karmadaClient := client.InClusterKarmadaClient()
+	groups, err := cluster.GetClusterGroups(karmadaClient)
+	if err != nil {
+		klog.ErrorS(err, "GetClusterGroups failed")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"groupLabel": cluster.ClusterGroupLabel,
+		"groups":     groups,
+	})
+}
+
 func handleDeleteCluster(c *gin.Context) {
 	ctx := context.Context(c)
 	clusterRequest := new(v1.DeleteClusterRequest)
 	if err := c.ShouldBindUri(&clusterRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	clusterName := clusterRequest.MemberClusterName
 	karmadaClient := client.InClusterKarmadaClient()
 	waitDuration := time.Second * 60
 
-	err := karmadaClient.ClusterV1alpha1().Clusters().Delete(ctx, clusterName, metav1.DeleteOptions{})
+	_, err := karmadaClient.ClusterV1alpha1().Clusters().Get(ctx, clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		common.Fail(c, fmt.Errorf("no cluster object %s found in karmada control Plane", clusterName))
+		return
+	}
+
+	// uninstall platform components while the cluster is still reachable through karmada
+	report := &DetachReport{Cluster: clusterName, Steps: []DetachStep{}}
+	if c.Query("skipCleanup") != "true" {
+		report = detachCluster(ctx, karmadaClient, clusterName, c.Query("cleanNamespaces") == "true")
+	}
+
+	err = karmadaClient.ClusterV1alpha1().Clusters().Delete(ctx, clusterName, metav1.DeleteOptions{})
 	if apierrors.IsNotFound(err) {
 		common.Fail(c, fmt.Errorf("no cluster object %s found in karmada control Plane", clusterName))
 		return
@@ -229,7 +310,9 @@ func handleDeleteCluster(c *gin.Context) {
 		common.Fail(c, err)
 		return
 	}
-	common.Success(c, "ok")
+	client.ResetMemberAccess(clusterName)
+	capability.Invalidate(clusterName)
+	common.Success(c, report)
 }
 
 func handleGetClusterUsers(c *gin.Context) {
@@ -328,7 +411,7 @@ func handleUpdateClusterUsers(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		common.FailWithStatus(c, fmt.Errorf("invalid request body: %v", err), 400)
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -385,12 +468,7 @@ func handleUpdateClusterUsers(c *gin.Context) {
 			// Add new roles based on the request
 			for _, role := range userUpdate.Roles {
 				// Map UI role names to OpenFGA relation names
-				relation := role
-				if role == "owner" || role == "admin" {
-					relation = "owner"
-				} else if role == "member" || role == "read" || role == "write" {
-					relation = "member"
-				}
+				relation := fga.ClusterRelationForRole(role)
 
 				err := fgaService.GetClient().WriteTuple(context.TODO(), userUpdate.Username, relation, "cluster", clusterName)
 				if err != nil {
@@ -458,7 +536,7 @@ func handlePostCAPICluster(c *gin.Context) {
 	var req CAPIClusterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		klog.ErrorS(err, "Could not read CAPI cluster request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -491,6 +569,13 @@ func handlePostCAPICluster(c *gin.Context) {
 		return
 	}
 
+	// Credentials read from an external store can only be used by the providers once they are synced
+	if _, err := externalsecret.Value(secret, "credentials"); err != nil {
+		klog.ErrorS(err, "Cloud credential is not ready", "name", secretName)
+		common.Fail(c, err)
+		return
+	}
+
 	// Create the ClusterAPI Cluster resource
 	capiCluster := map[string]interface{}{
 		"apiVersion": "cluster.x-k8s.io/v1beta1",
@@ -682,9 +767,25 @@ func init() {
 	r.GET("/cluster", handleGetClusterList)
 	r.GET("/cluster/:name", handleGetClusterDetail)
 	r.GET("/cluster/:name/users", handleGetClusterUsers)
+	r.POST("/cluster/:name/kubeconfig", handleCreateClusterKubeconfig)
+	r.POST("/cluster/:name/test", handleTestClusterConnectivity)
+	r.POST("/cluster/:name/rotate-credentials", handleRotateClusterCredentials)
+	r.POST("/cluster/:name/maintenance", handleClusterMaintenance)
+	r.POST("/cluster/:name/cordon", handleClusterCordon)
+	r.GET("/cluster/:name/credential-rotations", handleGetClusterCredentialRotations)
+	r.GET("/cluster/:name/activity", handleGetClusterActivity)
+	r.GET("/cluster/:name/capabilities", handleGetClusterCapabilities)
+	r.GET("/cluster/:name/usage", handleGetClusterUsage)
+	r.GET("/cluster/:name/onboarding-status", handleGetClusterOnboardingStatus)
 	r.PUT("/cluster/:name/users", handleUpdateClusterUsers)
 	r.POST("/cluster", handlePostCluster)
-	r.POST("/cluster/capi", handlePostCAPICluster)
+	r.POST("/cluster/capi", router.RequireFeature(config.FeatureCAPIProvisioning), handlePostCAPICluster)
+	r.GET("/cluster/export", router.EnsureMgmtAdminMiddleware(), handleGetClusterExport)
+	r.GET("/cluster/join/manifest", handleGetClusterJoinManifest)
+	r.GET("/cluster/bootstrap-manifest", router.EnsureMgmtAdminMiddleware(), handleGetClusterBootstrapManifest)
+	r.POST("/cluster/join", handlePostClusterJoin)
 	r.PUT("/cluster/:name", handlePutCluster)
 	r.DELETE("/cluster/:name", handleDeleteCluster)
+	r.GET("/cluster-groups", handleGetClusterGroups)
+	r.POST("/clusters/simulate-placement", handleSimulatePlacement)
 }
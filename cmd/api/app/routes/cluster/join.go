@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+const (
+	// JoinNamespace is the namespace in member clusters that holds the join ServiceAccount
+	JoinNamespace = "ml-platform-join"
+	// JoinServiceAccountName is the ServiceAccount used by the dashboard to register a Push mode cluster
+	JoinServiceAccountName = "ml-platform-join"
+	// JoinTokenSecretName is the long lived token Secret of the join ServiceAccount
+	JoinTokenSecretName = "ml-platform-join-token"
+)
+
+// joinManifest creates the join ServiceAccount with a token Secret. Registration creates the
+// Karmada credentials in the member cluster, which needs cluster-admin to grant their permissions.
+const joinManifest = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{namespace}}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{serviceAccount}}
+  namespace: {{namespace}}
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{serviceAccount}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cluster-admin
+subjects:
+- kind: ServiceAccount
+  name: {{serviceAccount}}
+  namespace: {{namespace}}
+---
+apiVersion: v1
+kind: Secret
+type: kubernetes.io/service-account-token
+metadata:
+  name: {{secret}}
+  namespace: {{namespace}}
+  annotations:
+    kubernetes.io/service-account.name: {{serviceAccount}}
+`
+
+// joinCommand applies the manifest and prints the token, CA and endpoint to paste into the dashboard
+const joinCommand = `kubectl apply -f ml-platform-join.yaml && \
+kubectl -n {{namespace}} wait --for=jsonpath='{.data.token}' secret/{{secret}} --timeout=60s && \
+echo "endpoint: $(kubectl config view --minify -o jsonpath='{.clusters[0].cluster.server}')" && \
+echo "token: $(kubectl -n {{namespace}} get secret {{secret}} -o jsonpath='{.data.token}' | base64 -d)" && \
+echo "caData: $(kubectl -n {{namespace}} get secret {{secret}} -o jsonpath='{.data.ca\.crt}')"`
+
+func renderJoinTemplate(tmpl string) string {
+	return strings.NewReplacer(
+		"{{namespace}}", JoinNamespace,
+		"{{serviceAccount}}", JoinServiceAccountName,
+		"{{secret}}", JoinTokenSecretName,
+	).Replace(tmpl)
+}
+
+// handleGetClusterJoinManifest returns the manifest and command that prepare a member cluster
+// for token based registration, so no admin kubeconfig has to be uploaded
+func handleGetClusterJoinManifest(c *gin.Context) {
+	common.Success(c, gin.H{
+		"manifest":       renderJoinTemplate(joinManifest),
+		"command":        renderJoinTemplate(joinCommand),
+		"namespace":      JoinNamespace,
+		"serviceAccount": JoinServiceAccountName,
+		"secret":         JoinTokenSecretName,
+	})
+}
+
+// handlePostClusterJoin registers a Push mode cluster with the token and CA of the join ServiceAccount
+func handlePostClusterJoin(c *gin.Context) {
+	joinRequest := new(v1.PostClusterJoinRequest)
+	if err := c.ShouldBind(joinRequest); err != nil {
+		klog.ErrorS(err, "Could not read cluster join request")
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	memberClusterRestConfig := &rest.Config{
+		Host:        joinRequest.MemberClusterEndpoint,
+		BearerToken: strings.TrimSpace(joinRequest.Token),
+	}
+	if joinRequest.CAData != "" {
+		caData, err := base64.StdEncoding.DecodeString(strings.TrimSpace(joinRequest.CAData))
+		if err != nil {
+			common.Fail(c, fmt.Errorf("caData must be base64 encoded: %w", err))
+			return
+		}
+		memberClusterRestConfig.TLSClientConfig.CAData = caData
+	} else if joinRequest.InsecureSkipTLSVerify {
+		memberClusterRestConfig.TLSClientConfig.Insecure = true
+	} else {
+		common.Fail(c, fmt.Errorf("caData is required unless insecureSkipTLSVerify is set"))
+		return
+	}
+
+	// Fail early with a readable error when the token or endpoint is wrong
+	memberClusterClient, err := kubeclient.NewForConfig(memberClusterRestConfig)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if _, err := memberClusterClient.Discovery().ServerVersion(); err != nil {
+		klog.ErrorS(err, "Failed to connect to member cluster", "cluster", joinRequest.MemberClusterName)
+		common.Fail(c, fmt.Errorf("failed to connect to member cluster with the join token: %w", err))
+		return
+	}
+
+	restConfig, _, err := client.GetKarmadaConfig()
+	if err != nil {
+		klog.ErrorS(err, "Get restConfig failed")
+		common.Fail(c, err)
+		return
+	}
+	opts := &pushModeOption{
+		karmadaClient:           client.InClusterKarmadaClient(),
+		clusterName:             joinRequest.MemberClusterName,
+		karmadaRestConfig:       restConfig,
+		memberClusterRestConfig: memberClusterRestConfig,
+	}
+	if err := accessClusterInPushMode(opts); err != nil {
+		klog.ErrorS(err, "accessClusterInPushMode failed", "cluster", joinRequest.MemberClusterName)
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Cluster joined with service account token", "cluster", joinRequest.MemberClusterName)
+	common.Success(c, "ok")
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/client"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// UserAccessNamespace is the namespace in member clusters that holds the per-user ServiceAccounts
+	UserAccessNamespace = "ml-platform-user-access"
+	// userAccessLabelKey marks the ServiceAccounts and bindings created for kubeconfig export
+	userAccessLabelKey = "ml-platform.io/user-access"
+	// userAccessUsernameAnnotation records the exact dashboard user a ServiceAccount and binding belong to
+	userAccessUsernameAnnotation = "ml-platform.io/username"
+
+	defaultKubeconfigExpiration = 8 * time.Hour
+	maxKubeconfigExpiration     = 7 * 24 * time.Hour
+)
+
+// clusterRoleForFGARole maps dashboard roles to the ClusterRole bound in the member cluster.
+// Members only get read access, since a cluster wide binding to edit would include every namespace.
+var clusterRoleForFGARole = map[string]string{
+	"admin":  "cluster-admin",
+	"owner":  "cluster-admin",
+	"member": "view",
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// userAccessName returns the ServiceAccount and ClusterRoleBinding name for a user.
+// The readable part is lossy, so a hash of the exact username keeps the names of different users apart.
+func userAccessName(username string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(username), "-"), "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	sum := sha256.Sum256([]byte(username))
+	if name == "" {
+		return "mlp-user-" + hex.EncodeToString(sum[:])[:10]
+	}
+	return "mlp-user-" + name + "-" + hex.EncodeToString(sum[:])[:10]
+}
+
+// checkUserAccessOwner fails if an object created for kubeconfig export belongs to another user
+func checkUserAccessOwner(kind string, obj metav1.Object, username string) error {
+	if owner := obj.GetAnnotations()[userAccessUsernameAnnotation]; owner != username {
+		return fmt.Errorf("%s %s belongs to user %q, refusing to rebind it for %q", kind, obj.GetName(), owner, username)
+	}
+	return nil
+}
+
+// resolveClusterRole returns the dashboard role of the user on the cluster, or an empty string without access
+func resolveClusterRole(c *gin.Context, username, clusterName string) (string, error) {
+	if fga.FGAService == nil {
+		// Keycloak deployments only carry platform wide roles. The bearer token of an impersonated request
+		// belongs to the admin, so its roles are not the ones of the impersonated user.
+		if router.Impersonator(c) == "" && utilauth.IsKeycloakAdmin(c) {
+			return "admin", nil
+		}
+		return "", nil
+	}
+	fgaClient := fga.FGAService.GetClient()
+	isAdmin, err := fgaClient.Check(context.TODO(), username, "admin", "dashboard", "dashboard")
+	if err != nil {
+		return "", err
+	}
+	if isAdmin {
+		return "admin", nil
+	}
+	for _, role := range []string{"owner", "member"} {
+		allowed, err := fgaClient.Check(context.TODO(), username, role, "cluster", clusterName)
+		if err != nil {
+			return "", err
+		}
+		if allowed {
+			return role, nil
+		}
+	}
+	return "", nil
+}
+
+// ensureUserServiceAccount creates the user's ServiceAccount and binds it to the ClusterRole of their role
+func ensureUserServiceAccount(ctx context.Context, memberClient kubeclient.Interface, username, clusterRole string) (string, error) {
+	name := userAccessName(username)
+	labels := map[string]string{userAccessLabelKey: "true"}
+	annotations := map[string]string{userAccessUsernameAnnotation: username}
+
+	_, err := memberClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: UserAccessNamespace, Labels: labels},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create namespace %s: %w", UserAccessNamespace, err)
+	}
+
+	_, err = memberClient.CoreV1().ServiceAccounts(UserAccessNamespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   UserAccessNamespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := memberClient.CoreV1().ServiceAccounts(UserAccessNamespace).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return "", fmt.Errorf("failed to get service account %s: %w", name, getErr)
+		}
+		if err := checkUserAccessOwner("service account", existing, username); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", fmt.Errorf("failed to create service account %s: %w", name, err)
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      name,
+			Namespace: UserAccessNamespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole,
+		},
+	}
+	existing, err := memberClient.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return "", fmt.Errorf("failed to get cluster role binding %s: %w", name, err)
+	default:
+		if err := checkUserAccessOwner("cluster role binding", existing, username); err != nil {
+			return "", err
+		}
+		if existing.RoleRef.Name == clusterRole {
+			return name, nil
+		}
+		// The role reference is immutable, so a changed dashboard role needs a new binding
+		if err := memberClient.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			return "", fmt.Errorf("failed to replace cluster role binding %s: %w", name, err)
+		}
+	}
+	if _, err := memberClient.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+		return "", fmt.Errorf("failed to create cluster role binding %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// clusterEndpointAndCA returns the API endpoint of a member cluster and the CA stored in its Karmada secret
+func clusterEndpointAndCA(ctx context.Context, memberCluster *clusterv1alpha1.Cluster) (string, []byte, error) {
+	if memberCluster.Spec.APIEndpoint == "" {
+		return "", nil, fmt.Errorf("cluster %s has no API endpoint", memberCluster.Name)
+	}
+	if memberCluster.Spec.SecretRef == nil {
+		return memberCluster.Spec.APIEndpoint, nil, nil
+	}
+	karmadaKubeClient := client.InClusterClientForKarmadaAPIServer()
+	secret, err := karmadaKubeClient.CoreV1().Secrets(memberCluster.Spec.SecretRef.Namespace).Get(ctx, memberCluster.Spec.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get cluster secret: %w", err)
+	}
+	return memberCluster.Spec.APIEndpoint, secret.Data[clusterv1alpha1.SecretCADataKey], nil
+}
+
+// handleCreateClusterKubeconfig generates a kubeconfig for the requesting user with a short lived
+// ServiceAccount token bound to the ClusterRole matching their role on the cluster. It is a POST since
+// it creates the ServiceAccount and its binding, so read-only API tokens cannot mint cluster credentials.
+func handleCreateClusterKubeconfig(c *gin.Context) {
+	clusterName := c.Param("name")
+	username := utilauth.GetAuthenticatedUser(c)
+	if username == "" {
+		common.FailWithStatus(c, fmt.Errorf("unauthorized"), 401)
+		return
+	}
+
+	role, err := resolveClusterRole(c, username, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to check access permission", "username", username, "cluster", clusterName)
+		common.FailWithStatus(c, fmt.Errorf("failed to check permissions"), 500)
+		return
+	}
+	clusterRole, ok := clusterRoleForFGARole[role]
+	if !ok {
+		common.FailWithStatus(c, fmt.Errorf("forbidden: no access to cluster %s", clusterName), 403)
+		return
+	}
+
+	expiration := defaultKubeconfigExpiration
+	if value := c.Query("expirationSeconds"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 600 {
+			common.Fail(c, fmt.Errorf("expirationSeconds must be an integer of at least 600"))
+			return
+		}
+		expiration = time.Duration(seconds) * time.Second
+		if expiration > maxKubeconfigExpiration {
+			expiration = maxKubeconfigExpiration
+		}
+	}
+
+	karmadaClient := client.InClusterKarmadaClient()
+	memberCluster, err := karmadaClient.ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Get cluster failed", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	endpoint, caData, err := clusterEndpointAndCA(c, memberCluster)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get cluster endpoint", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	memberClient := client.InClusterClientForMemberCluster(clusterName)
+	if memberClient == nil {
+		common.Fail(c, fmt.Errorf("failed to get client for cluster %s", clusterName))
+		return
+	}
+	saName, err := ensureUserServiceAccount(c, memberClient, username, clusterRole)
+	if err != nil {
+		klog.ErrorS(err, "Failed to prepare user service account", "username", username, "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	expirationSeconds := int64(expiration.Seconds())
+	tokenRequest, err := memberClient.CoreV1().ServiceAccounts(UserAccessNamespace).CreateToken(c, saName, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to create service account token", "serviceAccount", saName, "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	contextName := fmt.Sprintf("%s@%s", username, clusterName)
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[clusterName] = &clientcmdapi.Cluster{
+		Server:                   endpoint,
+		CertificateAuthorityData: caData,
+		InsecureSkipTLSVerify:    len(caData) == 0 && memberCluster.Spec.InsecureSkipTLSVerification,
+	}
+	kubeconfig.AuthInfos[contextName] = &clientcmdapi.AuthInfo{Token: tokenRequest.Status.Token}
+	kubeconfig.Contexts[contextName] = &clientcmdapi.Context{Cluster: clusterName, AuthInfo: contextName}
+	kubeconfig.CurrentContext = contextName
+
+	content, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	klog.InfoS("Generated kubeconfig", "username", username, "cluster", clusterName, "role", role, "expiresAt", tokenRequest.Status.ExpirationTimestamp.Time)
+	common.Success(c, gin.H{
+		"kubeconfig":  string(content),
+		"role":        role,
+		"clusterRole": clusterRole,
+		"expiresAt":   tokenRequest.Status.ExpirationTimestamp.Format(time.RFC3339),
+	})
+}
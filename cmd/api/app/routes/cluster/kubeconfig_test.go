@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUserAccessName(t *testing.T) {
+	collisions := [][2]string{
+		{"a.b", "a_b"},
+		{"Alice", "alice"},
+		{"alice@example.com", "alice-example-com"},
+	}
+	for _, pair := range collisions {
+		if userAccessName(pair[0]) == userAccessName(pair[1]) {
+			t.Errorf("userAccessName(%q) and userAccessName(%q) collide on %s", pair[0], pair[1], userAccessName(pair[0]))
+		}
+	}
+
+	long := userAccessName(strings.Repeat("x", 200))
+	if len(long) > 63 {
+		t.Errorf("userAccessName() of a long username has %d characters, want at most 63", len(long))
+	}
+	if name := userAccessName("@@@"); !strings.HasPrefix(name, "mlp-user-") || strings.Contains(name, "--") {
+		t.Errorf("userAccessName(\"@@@\") = %s, want a valid name", name)
+	}
+}
+
+func TestEnsureUserServiceAccount(t *testing.T) {
+	ctx := context.TODO()
+	memberClient := kubefake.NewSimpleClientset()
+
+	name, err := ensureUserServiceAccount(ctx, memberClient, "alice", "view")
+	if err != nil {
+		t.Fatalf("ensureUserServiceAccount() unexpected error: %v", err)
+	}
+	binding, err := memberClient.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.RoleRef.Name != "view" || binding.Annotations[userAccessUsernameAnnotation] != "alice" {
+		t.Errorf("binding = %s/%v, want view bound for alice", binding.RoleRef.Name, binding.Annotations)
+	}
+
+	// A changed role replaces the binding of the same user
+	if _, err := ensureUserServiceAccount(ctx, memberClient, "alice", "cluster-admin"); err != nil {
+		t.Fatalf("ensureUserServiceAccount() unexpected error: %v", err)
+	}
+	binding, err = memberClient.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.RoleRef.Name != "cluster-admin" {
+		t.Errorf("binding role = %s, want cluster-admin", binding.RoleRef.Name)
+	}
+
+	// Objects carrying another user's name are never rebound
+	sa, err := memberClient.CoreV1().ServiceAccounts(UserAccessNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa.Annotations[userAccessUsernameAnnotation] = "mallory"
+	if _, err := memberClient.CoreV1().ServiceAccounts(UserAccessNamespace).Update(ctx, sa, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ensureUserServiceAccount(ctx, memberClient, "alice", "view"); err == nil {
+		t.Fatal("ensureUserServiceAccount() succeeded on a service account of another user")
+	}
+	binding, err = memberClient.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.RoleRef.Name != "cluster-admin" {
+		t.Errorf("binding role = %s after refused rebind, want cluster-admin", binding.RoleRef.Name)
+	}
+}
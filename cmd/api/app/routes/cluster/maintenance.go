@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// Maintenance actions
+const (
+	maintenanceEnter = "enter"
+	maintenanceExit  = "exit"
+)
+
+// MaintenanceRequest puts a cluster in or takes it out of maintenance mode
+type MaintenanceRequest struct {
+	Action string `json:"action" binding:"required,oneof=enter exit"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// MaintenanceResult is the maintenance mode of a cluster after a request and the backup schedules it changed
+type MaintenanceResult struct {
+	Cluster     string               `json:"cluster"`
+	Maintenance *cluster.Maintenance `json:"maintenance,omitempty"`
+	// SuspendedBackups are the backup configurations whose schedules were suspended on entering maintenance
+	SuspendedBackups []string `json:"suspendedBackups,omitempty"`
+	// ResumedBackups are the backup configurations whose schedules were resumed on exiting maintenance
+	ResumedBackups []string `json:"resumedBackups,omitempty"`
+}
+
+// handleClusterMaintenance enters or exits the maintenance mode of a cluster. In maintenance the cluster is
+// tainted so Karmada propagates nothing new to it, the backup schedules of its workloads are suspended and
+// checkpoints, recoveries and migrations onto it are rejected until it exits maintenance.
+func handleClusterMaintenance(c *gin.Context) {
+	clusterName := c.Param("name")
+	var req MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	karmadaClient := client.InClusterKarmadaClient()
+	result := MaintenanceResult{Cluster: clusterName}
+	var err error
+	switch req.Action {
+	case maintenanceEnter:
+		clusterObj, enterErr := cluster.EnterMaintenance(c, karmadaClient, clusterName, cluster.Maintenance{
+			Since:  time.Now().UTC().Format(time.RFC3339),
+			By:     utilauth.GetAuthenticatedUser(c),
+			Reason: req.Reason,
+		})
+		if enterErr != nil {
+			failClusterUpdate(c, clusterName, enterErr)
+			return
+		}
+		result.Maintenance = cluster.MaintenanceOf(clusterObj)
+		klog.InfoS("Cluster entered maintenance", "cluster", clusterName, "user", result.Maintenance.By, "reason", result.Maintenance.Reason)
+		result.SuspendedBackups, err = backup.SuspendClusterSchedules(c, clusterName)
+	case maintenanceExit:
+		if _, exitErr := cluster.ExitMaintenance(c, karmadaClient, clusterName); exitErr != nil {
+			failClusterUpdate(c, clusterName, exitErr)
+			return
+		}
+		klog.InfoS("Cluster exited maintenance", "cluster", clusterName, "user", utilauth.GetAuthenticatedUser(c))
+		result.ResumedBackups, err = backup.ResumeClusterSchedules(c, clusterName)
+	}
+	if err != nil {
+		// The mode changed, the request can be repeated to change the remaining schedules
+		klog.ErrorS(err, "Failed to update backup schedules for maintenance", "cluster", clusterName, "action", req.Action)
+		common.FailWithData(c, err, http.StatusInternalServerError, result)
+		return
+	}
+	common.Success(c, result)
+}
+
+// failClusterUpdate fails a maintenance or cordon request whose cluster could not be updated
+func failClusterUpdate(c *gin.Context, clusterName string, err error) {
+	if apierrors.IsNotFound(err) {
+		common.Fail(c, pkgerrors.NewNotFound("cluster "+clusterName+" not found"))
+		return
+	}
+	klog.ErrorS(err, "Failed to update cluster", "cluster", clusterName)
+	common.Fail(c, err)
+}
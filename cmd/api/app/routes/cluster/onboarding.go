@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/setting/monitoring"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// onboardingNamespaces are the namespaces the platform components of a member cluster run in
+var onboardingNamespaces = []string{"stateful-migration", UserAccessNamespace}
+
+// handleGetClusterOnboardingStatus evaluates the onboarding checklist of a member cluster, so the UI can
+// guide the setup of a newly added cluster. Steps that cannot be checked yet are reported as not done.
+func handleGetClusterOnboardingStatus(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	status := cluster.NewOnboardingStatus(clusterName)
+
+	memberCluster, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		common.Fail(c, err)
+		return
+	}
+	joined, ready := err == nil, false
+	status.Record(cluster.OnboardingJoined, "Joined to Karmada", joined, "", nil)
+	if joined {
+		var message string
+		ready, message = cluster.ReadyMessage(memberCluster)
+		status.Record(cluster.OnboardingReady, "Cluster ready", ready, message, nil)
+	} else {
+		status.Skip(cluster.OnboardingReady, "Cluster ready", "cluster is not joined")
+	}
+
+	controllers, err := backup.CurrentControllers(c)
+	installed := false
+	for _, controller := range controllers {
+		if controller.Cluster == clusterName {
+			installed = true
+		}
+	}
+	status.Record(cluster.OnboardingMigrationController, "Migration controller installed", installed, "", err)
+
+	monitorings, err := monitoring.CurrentMonitoring(c)
+	var sources []string
+	for _, m := range monitorings {
+		if m.Cluster == clusterName {
+			sources = append(sources, m.Name)
+		}
+	}
+	monitoringMessage := ""
+	if len(sources) > 0 {
+		monitoringMessage = fmt.Sprintf("%d monitoring sources", len(sources))
+	}
+	status.Record(cluster.OnboardingMonitoring, "Monitoring configured", len(sources) > 0, monitoringMessage, err)
+
+	owners, err := cluster.ClusterOwners(c, clusterName)
+	ownerMessage := ""
+	if len(owners) > 0 {
+		ownerMessage = fmt.Sprintf("owned by %d users", len(owners))
+	}
+	status.Record(cluster.OnboardingOwner, "Owner assigned", len(owners) > 0, ownerMessage, err)
+
+	if ready {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			status.Record(cluster.OnboardingNamespaces, "Required namespaces present", false, "", fmt.Errorf("failed to get client for member cluster %s", clusterName))
+		} else {
+			namespaces, err := memberClient.CoreV1().Namespaces().List(c, metav1.ListOptions{})
+			var missing []string
+			if err == nil {
+				missing = cluster.MissingNamespaces(onboardingNamespaces, namespaces.Items)
+			}
+			status.Record(cluster.OnboardingNamespaces, "Required namespaces present", len(missing) == 0, cluster.NamespacesMessage(missing), err)
+		}
+	} else {
+		status.Skip(cluster.OnboardingNamespaces, "Required namespaces present", "cluster is not ready")
+	}
+
+	klog.V(4).InfoS("Evaluated cluster onboarding", "cluster", clusterName, "completed", status.Completed, "total", status.Total)
+	common.Success(c, status)
+}
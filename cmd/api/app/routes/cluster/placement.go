@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/dataselect"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const defaultPlacementCandidates = 3
+
+// toWorkloadSpec parses the quantities of a placement request
+func toWorkloadSpec(req v1.SimulatePlacementRequest) (cluster.WorkloadSpec, error) {
+	spec := cluster.WorkloadSpec{
+		Replicas:     req.Replicas,
+		Requests:     corev1.ResourceList{},
+		NodeSelector: req.NodeSelector,
+		Tolerations:  req.Tolerations,
+	}
+	if spec.Replicas == 0 {
+		spec.Replicas = 1
+	}
+	if spec.Replicas < 0 {
+		return spec, fmt.Errorf("replicas must not be negative")
+	}
+	for name, value := range map[corev1.ResourceName]string{
+		corev1.ResourceCPU:      req.CPU,
+		corev1.ResourceMemory:   req.Memory,
+		cluster.GPUResourceName: req.GPU,
+	} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return spec, fmt.Errorf("invalid %s quantity %q: %v", name, value, err)
+		}
+		if quantity.Sign() < 0 {
+			return spec, fmt.Errorf("%s must not be negative", name)
+		}
+		spec.Requests[name] = quantity
+	}
+	return spec, nil
+}
+
+// simulateClusterPlacement evaluates the workload against the nodes and pods of a member cluster
+func simulateClusterPlacement(ctx context.Context, clusterName string, spec cluster.WorkloadSpec) (cluster.PlacementResult, error) {
+	memberClient := client.InClusterClientForMemberCluster(clusterName)
+	if memberClient == nil {
+		return cluster.PlacementResult{}, fmt.Errorf("failed to get client for member cluster %s", clusterName)
+	}
+	nodes, err := memberClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return cluster.PlacementResult{}, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	pods, err := memberClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return cluster.PlacementResult{}, fmt.Errorf("failed to list pods: %v", err)
+	}
+	return cluster.EvaluatePlacement(clusterName, nodes.Items, pods.Items, spec), nil
+}
+
+// handleSimulatePlacement evaluates whether a workload fits in each member cluster the user can access on
+// the current free resources of its nodes, and returns the best candidate clusters. Nothing is created.
+func handleSimulatePlacement(c *gin.Context) {
+	var req v1.SimulatePlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	spec, err := toWorkloadSpec(req)
+	if err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultPlacementCandidates
+	}
+	only := map[string]bool{}
+	for _, name := range req.Clusters {
+		only[name] = true
+	}
+
+	clusters, err := cluster.GetClusterList(client.InClusterKarmadaClient(), dataselect.NoDataSelect, utilauth.GetAuthenticatedUser(c))
+	if err != nil {
+		klog.ErrorS(err, "GetClusterList failed")
+		common.Fail(c, err)
+		return
+	}
+
+	results := make([]cluster.PlacementResult, 0, len(clusters.Clusters))
+	for _, memberCluster := range clusters.Clusters {
+		name := memberCluster.ObjectMeta.Name
+		if name == "mgmt-cluster" || (len(only) > 0 && !only[name]) {
+			continue
+		}
+		if memberCluster.Ready != metav1.ConditionTrue {
+			results = append(results, cluster.PlacementResult{Cluster: name, Reasons: []string{"cluster is not ready"}})
+			continue
+		}
+		result, err := simulateClusterPlacement(c, name, spec)
+		if err != nil {
+			klog.ErrorS(err, "Failed to simulate placement", "cluster", name)
+			result = cluster.PlacementResult{Cluster: name, Reasons: []string{err.Error()}}
+		}
+		results = append(results, result)
+	}
+	cluster.RankPlacements(results)
+
+	candidates := make([]cluster.PlacementResult, 0, limit)
+	for _, result := range results {
+		if result.Fits && len(candidates) < limit {
+			candidates = append(candidates, result)
+		}
+	}
+	common.Success(c, v1.SimulatePlacementResponse{
+		Candidates: candidates,
+		Clusters:   results,
+	})
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/setting/monitoring"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// Sources of a usage timeline
+const (
+	usageSourcePrometheus = "prometheus"
+	usageSourceSnapshots  = "snapshots"
+)
+
+// usagePrometheusQueries are the Prometheus queries of the metrics of a usage timeline
+var usagePrometheusQueries = map[string]string{
+	cluster.UsageCPU:    `sum(rate(container_cpu_usage_seconds_total{container!="",pod!=""}[5m]))`,
+	cluster.UsageMemory: `sum(container_memory_working_set_bytes{container!="",pod!=""})`,
+	cluster.UsageGPU:    `sum(kube_pod_container_resource_requests{resource="nvidia_com_gpu"})`,
+	cluster.UsagePods:   `sum(kube_pod_status_phase{phase="Running"})`,
+}
+
+// UsageTimeline is the resource usage of a cluster over a time range
+type UsageTimeline struct {
+	Cluster string `json:"cluster"`
+	// Source is "prometheus" when the timeline is queried from the Prometheus registered for the cluster,
+	// "snapshots" when it is built from the metrics-server samples taken by the dashboard
+	Source string               `json:"source"`
+	Range  string               `json:"range"`
+	Step   string               `json:"step"`
+	Start  time.Time            `json:"start"`
+	End    time.Time            `json:"end"`
+	Points []cluster.UsagePoint `json:"points"`
+}
+
+// handleGetClusterUsage returns the CPU, memory, GPU and pod count timeline of a cluster over the range query
+// parameter, 24h by default. The Prometheus registered for the cluster is used when there is one, unless the
+// source query parameter asks for the snapshots.
+func handleGetClusterUsage(c *gin.Context) {
+	clusterName := c.Param("name")
+	if err := client.CheckMemberClusterAccess(c, clusterName); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	usageRange, err := cluster.ParseUsageRange(c.Query("range"))
+	if err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+	source := c.Query("source")
+	if source != "" && source != usageSourcePrometheus && source != usageSourceSnapshots {
+		common.Fail(c, pkgerrors.NewBadRequest(fmt.Sprintf("invalid source %q, expected %s or %s", source, usageSourcePrometheus, usageSourceSnapshots)))
+		return
+	}
+	_, err = client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		common.Fail(c, pkgerrors.NewNotFound("cluster "+clusterName+" not found"))
+		return
+	}
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	step := cluster.UsageStep(usageRange)
+	end := time.Now().Truncate(step)
+	timeline := UsageTimeline{
+		Cluster: clusterName,
+		Range:   usageRange.String(),
+		Step:    step.String(),
+		Start:   end.Add(-usageRange),
+		End:     end,
+	}
+
+	if source != usageSourceSnapshots {
+		points, err := prometheusUsage(c, clusterName, timeline.Start, end, step)
+		switch {
+		case err == nil:
+			timeline.Source = usageSourcePrometheus
+			timeline.Points = points
+			common.Success(c, timeline)
+			return
+		case errors.Is(err, monitoring.ErrNoPrometheus) && source == "":
+			// Fall back to the snapshots
+		case errors.Is(err, monitoring.ErrNoPrometheus):
+			common.FailWithStatus(c, err, http.StatusPreconditionFailed)
+			return
+		default:
+			klog.ErrorS(err, "Failed to query cluster usage from Prometheus", "cluster", clusterName)
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	samples, err := cluster.LoadUsage(c, client.InClusterClient(), config.GetNamespace(), clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to load cluster usage samples", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	timeline.Source = usageSourceSnapshots
+	timeline.Points = cluster.DownsampleUsage(samples, timeline.Start, end, step)
+	common.Success(c, timeline)
+}
+
+// prometheusUsage queries the usage timeline of a cluster from its Prometheus
+func prometheusUsage(ctx context.Context, clusterName string, start, end time.Time, step time.Duration) ([]cluster.UsagePoint, error) {
+	metrics := map[string]map[time.Time]float64{}
+	for metric, query := range usagePrometheusQueries {
+		values, err := monitoring.QueryClusterPrometheusRange(ctx, clusterName, query, start, end, step)
+		if err != nil {
+			return nil, err
+		}
+		metrics[metric] = values
+	}
+	return cluster.MergeUsage(metrics), nil
+}
+
+// StartUsageSampler samples the usage of the ready member clusters from metrics-server at the given interval
+// until ctx is done, keeping the samples of the longest timeline. A non-positive interval disables the sampler.
+func StartUsageSampler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Cluster usage sampler is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			sampleClusterUsage(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	klog.InfoS("Cluster usage sampler started", "interval", interval)
+}
+
+func sampleClusterUsage(ctx context.Context) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Usage sampler failed to list clusters")
+		return
+	}
+	kubeClient := client.InClusterClient()
+	namespace := config.GetNamespace()
+	now := time.Now()
+	members := map[string]bool{}
+	for i := range clusters.Items {
+		name := clusters.Items[i].Name
+		members[name] = true
+		if ready, _ := cluster.ReadyMessage(&clusters.Items[i]); !ready {
+			continue
+		}
+		memberClient := client.InClusterClientForMemberCluster(name)
+		// An empty context carries no user, so the sampler uses the dashboard's own member access
+		dynamicClient, err := client.GetDynamicClientForMember(&gin.Context{}, name)
+		if memberClient == nil || err != nil {
+			klog.V(4).InfoS("Usage sampler failed to create member client", "cluster", name, "error", err)
+			continue
+		}
+		point, err := cluster.SampleUsage(ctx, memberClient, dynamicClient, now)
+		if err != nil {
+			klog.V(2).InfoS("Usage sampler failed to sample cluster", "cluster", name, "error", err)
+			continue
+		}
+		samples, err := cluster.LoadUsage(ctx, kubeClient, namespace, name)
+		if err != nil {
+			klog.ErrorS(err, "Usage sampler failed to load samples", "cluster", name)
+			continue
+		}
+		if err := cluster.SaveUsage(ctx, kubeClient, namespace, name, cluster.AppendUsage(samples, point, cluster.MaxUsageRange)); err != nil {
+			klog.ErrorS(err, "Usage sampler failed to save samples", "cluster", name)
+		}
+	}
+
+	// The samples of clusters that were removed are not kept
+	stored, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: cluster.UsageLabelKey})
+	if err != nil {
+		return
+	}
+	for _, cm := range stored.Items {
+		if !members[cm.Labels[cluster.UsageLabelKey]] {
+			if err := kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "Usage sampler failed to delete samples of removed cluster", "configMap", cm.Name)
+			}
+		}
+	}
+}
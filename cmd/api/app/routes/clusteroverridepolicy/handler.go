@@ -60,7 +60,7 @@ func handlePostClusterOverridePolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	overridepolicyRequest := new(v1.PostOverridePolicyRequest)
 	if err := c.ShouldBind(&overridepolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
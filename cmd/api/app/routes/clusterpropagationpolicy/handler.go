@@ -60,7 +60,7 @@ func handlePostClusterPropagationPolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	propagationpolicyRequest := new(v1.PostPropagationPolicyRequest)
 	if err := c.ShouldBind(&propagationpolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// handleGetFeatures returns the switchable features and whether they are enabled, so the frontend
+// can hide the navigation of the disabled ones
+func handleGetFeatures(c *gin.Context) {
+	features := config.FeatureStatuses()
+	common.Success(c, gin.H{
+		"features": features,
+		"total":    len(features),
+	})
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/features", handleGetFeatures)
+}
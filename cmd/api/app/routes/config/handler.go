@@ -38,7 +38,7 @@ func SetDashboardConfig(c *gin.Context) {
 	setDashboardConfigRequest := new(v1.SetDashboardConfigRequest)
 	if err := c.ShouldBind(setDashboardConfigRequest); err != nil {
 		klog.ErrorS(err, "Could not read SetDashboardConfigRequest")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// handleGetRuntimeConfig returns the whole dashboard configuration the API server currently applies
+func handleGetRuntimeConfig(c *gin.Context) {
+	common.Success(c, gin.H{
+		"config":          config.GetDashboardConfig(),
+		"namespace":       config.GetNamespace(),
+		"manifestBaseURL": config.ManifestBaseURL(),
+	})
+}
+
+// handlePutRuntimeConfig replaces the dashboard configuration. The replicas apply it when they see the
+// ConfigMap change, without a restart, and the changed settings are recorded in the history.
+func handlePutRuntimeConfig(c *gin.Context) {
+	newConfig := config.DashboardConfig{}
+	if err := c.ShouldBindJSON(&newConfig); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := newConfig.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	changed := config.ChangedFields(config.GetDashboardConfig(), newConfig)
+	if len(changed) == 0 {
+		common.Success(c, gin.H{"changed": changed})
+		return
+	}
+	k8sClient := client.InClusterClient()
+	if err := config.UpdateDashboardConfig(k8sClient, newConfig); err != nil {
+		klog.ErrorS(err, "Failed to update dashboard config")
+		common.Fail(c, err)
+		return
+	}
+
+	user := utilauth.GetAuthenticatedUser(c)
+	klog.InfoS("Dashboard configuration updated", "user", user, "changed", changed)
+	change := config.ConfigChange{Time: time.Now().Format(time.RFC3339), User: user, Fields: changed}
+	if err := config.RecordConfigChange(c, k8sClient, change); err != nil {
+		klog.ErrorS(err, "Failed to record dashboard config change", "changed", changed)
+	}
+	common.Success(c, gin.H{"changed": changed})
+}
+
+// handleGetRuntimeConfigHistory returns the changes made through the API, newest last
+func handleGetRuntimeConfigHistory(c *gin.Context) {
+	history, err := config.GetConfigHistory(c, client.InClusterClient())
+	if err != nil {
+		klog.ErrorS(err, "Failed to get dashboard config history")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"history": history,
+		"total":   len(history),
+	})
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/settings/config", router.EnsureMgmtAdminMiddleware(), handleGetRuntimeConfig)
+	r.PUT("/settings/config", router.EnsureMgmtAdminMiddleware(), handlePutRuntimeConfig)
+	r.GET("/settings/config/history", router.EnsureMgmtAdminMiddleware(), handleGetRuntimeConfigHistory)
+}
@@ -37,7 +37,7 @@ func handlerCreateDeployment(c *gin.Context) {
 	ctx := context.Context(c)
 	createDeploymentRequest := new(v1.CreateDeploymentRequest)
 	if err := c.ShouldBind(&createDeploymentRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	if createDeploymentRequest.Namespace == "" {
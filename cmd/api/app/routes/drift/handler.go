@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/drift"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// handleGetDrift compares the resources Karmada declares for the member clusters with the clusters.
+// Query parameters: cluster, kind, namespace and extra=true to also report objects no longer declared.
+func handleGetDrift(c *gin.Context) {
+	report, err := drift.Detect(c, client.InClusterKarmadaClient(), drift.Options{
+		Cluster:      c.Query("cluster"),
+		Kind:         c.Query("kind"),
+		Namespace:    c.Query("namespace"),
+		IncludeExtra: c.Query("extra") == "true",
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to detect drift")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, report)
+}
+
+// handlePostDriftRepair re-applies the declared state of the drifted objects of a member cluster
+func handlePostDriftRepair(c *gin.Context) {
+	req := new(v1.PostDriftRepairRequest)
+	if err := c.ShouldBindJSON(req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	klog.InfoS("Repairing drift", "cluster", req.Cluster, "kind", req.Kind, "namespace", req.Namespace,
+		"objects", len(req.Objects), "deleteExtra", req.DeleteExtra, "user", utilauth.GetAuthenticatedUser(c))
+
+	results, err := drift.Repair(c, client.InClusterKarmadaClient(), drift.Options{
+		Cluster:   req.Cluster,
+		Kind:      req.Kind,
+		Namespace: req.Namespace,
+	}, req.Objects, req.DeleteExtra)
+	if err != nil {
+		klog.ErrorS(err, "Failed to repair drift", "cluster", req.Cluster)
+		common.Fail(c, err)
+		return
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	common.Success(c, gin.H{
+		"results":  results,
+		"repaired": len(results) - failed,
+		"failed":   failed,
+	})
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/karmada/drift", router.EnsureMgmtAdminMiddleware(), handleGetDrift)
+	r.POST("/karmada/drift/repair", router.EnsureMgmtAdminMiddleware(), handlePostDriftRepair)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedresourcequota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/federatedresourcequota"
+)
+
+// toResourceList parses resource quantities by resource name
+func toResourceList(limits map[string]string) (corev1.ResourceList, error) {
+	list := corev1.ResourceList{}
+	for name, value := range limits {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q for %s: %v", value, name, err)
+		}
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("quantity for %s must not be negative", name)
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
+
+// toQuotaSpec builds the quota spec and checks that the static assignments fit in the overall limits
+func toQuotaSpec(overall map[string]string, assignments []v1.StaticQuotaAssignment) (v1alpha1.FederatedResourceQuotaSpec, error) {
+	spec := v1alpha1.FederatedResourceQuotaSpec{}
+	if len(overall) == 0 {
+		return spec, fmt.Errorf("at least one overall limit is required")
+	}
+	var err error
+	if spec.Overall, err = toResourceList(overall); err != nil {
+		return spec, err
+	}
+
+	assigned := corev1.ResourceList{}
+	seen := map[string]bool{}
+	for _, assignment := range assignments {
+		if seen[assignment.ClusterName] {
+			return spec, fmt.Errorf("cluster %s is assigned more than once", assignment.ClusterName)
+		}
+		seen[assignment.ClusterName] = true
+		hard, err := toResourceList(assignment.Hard)
+		if err != nil {
+			return spec, fmt.Errorf("cluster %s: %v", assignment.ClusterName, err)
+		}
+		for name, quantity := range hard {
+			if _, ok := spec.Overall[name]; !ok {
+				return spec, fmt.Errorf("cluster %s limits %s, which has no overall limit", assignment.ClusterName, name)
+			}
+			total := assigned[name]
+			total.Add(quantity)
+			assigned[name] = total
+		}
+		spec.StaticAssignments = append(spec.StaticAssignments, v1alpha1.StaticClusterAssignment{
+			ClusterName: assignment.ClusterName,
+			Hard:        hard,
+		})
+	}
+	for name, total := range assigned {
+		if limit := spec.Overall[name]; total.Cmp(limit) > 0 {
+			return spec, fmt.Errorf("static assignments of %s add up to %s, more than the overall limit %s", name, total.String(), limit.String())
+		}
+	}
+	return spec, nil
+}
+
+func handleGetFederatedResourceQuotaList(c *gin.Context) {
+	karmadaClient := client.InClusterKarmadaClient()
+	dataSelect := common.ParseDataSelectPathParameter(c)
+	namespace := common.ParseNamespacePathParameter(c)
+	result, err := federatedresourcequota.GetFederatedResourceQuotaList(karmadaClient, namespace, dataSelect)
+	if err != nil {
+		klog.ErrorS(err, "GetFederatedResourceQuotaList failed")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, result)
+}
+
+func handleGetFederatedResourceQuotaDetail(c *gin.Context) {
+	karmadaClient := client.InClusterKarmadaClient()
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	result, err := federatedresourcequota.GetFederatedResourceQuotaDetail(karmadaClient, namespace, name)
+	if err != nil {
+		klog.ErrorS(err, "GetFederatedResourceQuotaDetail failed", "namespace", namespace, "name", name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, result)
+}
+
+func handlePostFederatedResourceQuota(c *gin.Context) {
+	ctx := context.Context(c)
+	quotaRequest := new(v1.PostFederatedResourceQuotaRequest)
+	if err := c.ShouldBind(quotaRequest); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	spec, err := toQuotaSpec(quotaRequest.Overall, quotaRequest.StaticAssignments)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	quota := &v1alpha1.FederatedResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      quotaRequest.Name,
+			Namespace: quotaRequest.Namespace,
+		},
+		Spec: spec,
+	}
+	karmadaClient := client.InClusterKarmadaClient()
+	if _, err := karmadaClient.PolicyV1alpha1().FederatedResourceQuotas(quotaRequest.Namespace).Create(ctx, quota, metav1.CreateOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to create FederatedResourceQuota", "namespace", quotaRequest.Namespace, "name", quotaRequest.Name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, "ok")
+}
+
+func handlePutFederatedResourceQuota(c *gin.Context) {
+	ctx := context.Context(c)
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	quotaRequest := new(v1.PutFederatedResourceQuotaRequest)
+	if err := c.ShouldBind(quotaRequest); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	spec, err := toQuotaSpec(quotaRequest.Overall, quotaRequest.StaticAssignments)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	karmadaClient := client.InClusterKarmadaClient()
+	quota, err := karmadaClient.PolicyV1alpha1().FederatedResourceQuotas(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		// only spec can be updated
+		quota.Spec = spec
+		_, err = karmadaClient.PolicyV1alpha1().FederatedResourceQuotas(namespace).Update(ctx, quota, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to update FederatedResourceQuota", "namespace", namespace, "name", name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, "ok")
+}
+
+func handleDeleteFederatedResourceQuota(c *gin.Context) {
+	ctx := context.Context(c)
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	karmadaClient := client.InClusterKarmadaClient()
+	if err := karmadaClient.PolicyV1alpha1().FederatedResourceQuotas(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to delete FederatedResourceQuota", "namespace", namespace, "name", name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, "ok")
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/federatedresourcequota", handleGetFederatedResourceQuotaList)
+	r.GET("/federatedresourcequota/:namespace", handleGetFederatedResourceQuotaList)
+	r.GET("/federatedresourcequota/namespace/:namespace/:name", handleGetFederatedResourceQuotaDetail)
+	// Quotas cap the resources of a team across the fleet, so only admins can change them
+	r.POST("/federatedresourcequota", router.EnsureMgmtAdminMiddleware(), handlePostFederatedResourceQuota)
+	r.PUT("/federatedresourcequota/namespace/:namespace/:name", router.EnsureMgmtAdminMiddleware(), handlePutFederatedResourceQuota)
+	r.DELETE("/federatedresourcequota/namespace/:namespace/:name", router.EnsureMgmtAdminMiddleware(), handleDeleteFederatedResourceQuota)
+}
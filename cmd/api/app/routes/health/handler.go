@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/pkg/leader"
+)
+
+// check is a named condition of the replica, nil when it holds
+type check struct {
+	name string
+	run  func(req *http.Request) error
+}
+
+// respond runs the checks and answers 200 when they all pass, 503 otherwise
+func respond(c *gin.Context, checks []check) {
+	healthy := true
+	results := make(map[string]string, len(checks))
+	for _, ch := range checks {
+		if err := ch.run(c.Request); err != nil {
+			healthy = false
+			results[ch.name] = err.Error()
+			continue
+		}
+		results[ch.name] = "ok"
+	}
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{
+		"healthy": healthy,
+		"checks":  results,
+		"leader":  leader.GetStatus(),
+	})
+}
+
+// handleHealthz fails when this replica leads but could not renew its lease, so it gets restarted
+// and another replica takes over the background workers
+func handleHealthz(c *gin.Context) {
+	respond(c, []check{
+		{name: "leader-election", run: leader.Check},
+	})
+}
+
+// handleReadyz fails until the caches the handlers read from have synced. Replicas that do not lead
+// are ready too, since every replica serves requests.
+func handleReadyz(c *gin.Context) {
+	respond(c, []check{
+		{name: "migration-cache", run: func(*http.Request) error { return backup.MigrationCacheSynced() }},
+	})
+}
+
+func init() {
+	r := router.Router()
+	r.GET("/healthz", handleHealthz)
+	r.GET("/readyz", handleReadyz)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// handleGetJobs lists the jobs, optionally filtered by type and phase
+func handleGetJobs(c *gin.Context) {
+	list, err := jobs.List(c, c.Query("type"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to list jobs")
+		common.Fail(c, err)
+		return
+	}
+	phase := c.Query("phase")
+	result := make([]*jobs.Job, 0, len(list))
+	for _, job := range list {
+		if phase == "" || job.Phase == phase {
+			result = append(result, job)
+		}
+	}
+	common.Success(c, gin.H{
+		"jobs":       result,
+		"totalItems": len(result),
+	})
+}
+
+// handleGetJob returns a job with its status and attempts
+func handleGetJob(c *gin.Context) {
+	job, err := jobs.Get(c, c.Param("id"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, job)
+}
+
+// handleCancelJob cancels a pending job, or asks the worker running it to stop
+func handleCancelJob(c *gin.Context) {
+	job, err := jobs.Cancel(c, c.Param("id"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Cancel requested for job", "id", job.ID, "user", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, job)
+}
+
+// handleRetryJob queues a failed or cancelled job again
+func handleRetryJob(c *gin.Context) {
+	job, err := jobs.Retry(c, c.Param("id"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Retry requested for job", "id", job.ID, "user", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, job)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/jobs", handleGetJobs)
+	r.GET("/jobs/:id", handleGetJob)
+	r.POST("/jobs/:id/cancel", router.EnsureMgmtAdminMiddleware(), handleCancelJob)
+	r.POST("/jobs/:id/retry", router.EnsureMgmtAdminMiddleware(), handleRetryJob)
+}
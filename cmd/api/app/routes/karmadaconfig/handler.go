@@ -85,7 +85,7 @@ func hadlleputpodinfo(c *gin.Context) {
         YamlContent string `json:"yamlContent"`
     }
     if err := c.ShouldBindJSON(&requestBody); err != nil {
-        common.Fail(c, err)
+        common.FailWithBindError(c, err)
         return
     }
 
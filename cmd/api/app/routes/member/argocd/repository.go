@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+)
+
+// failRepository fails a request on a repository secret with the status matching the error
+func failRepository(c *gin.Context, err error) {
+	switch {
+	case apierrors.IsNotFound(err):
+		common.FailWithStatus(c, err, http.StatusNotFound)
+	case apierrors.IsAlreadyExists(err):
+		common.FailWithStatus(c, err, http.StatusConflict)
+	default:
+		common.Fail(c, err)
+	}
+}
+
+// handleListRepositories lists the repository secrets of a type in a member cluster
+func handleListRepositories(c *gin.Context, secretType string) {
+	service, err := memberService(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	repositories, err := service.ListRepositories(c, secretType)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list ArgoCD repository secrets", "cluster", c.Param("clustername"), "secretType", secretType)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"items":      repositories,
+		"totalItems": len(repositories),
+	})
+}
+
+// handleCreateRepository stores a repository secret of a type in a member cluster
+func handleCreateRepository(c *gin.Context, secretType string) {
+	var request argocd.RepositoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := request.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	service, err := memberService(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	repository, err := service.CreateRepository(c, secretType, request)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create ArgoCD repository secret", "cluster", c.Param("clustername"), "secretType", secretType, "url", request.URL)
+		failRepository(c, err)
+		return
+	}
+	common.Success(c, repository)
+}
+
+// handleDeleteRepository deletes a repository secret of a type from a member cluster
+func handleDeleteRepository(c *gin.Context, secretType string) {
+	name := c.Param("repositoryName")
+	service, err := memberService(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := service.DeleteRepository(c, secretType, name); err != nil {
+		klog.ErrorS(err, "Failed to delete ArgoCD repository secret", "cluster", c.Param("clustername"), "secretType", secretType, "name", name)
+		failRepository(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"message": fmt.Sprintf("Repository secret %s deleted successfully", name),
+	})
+}
+
+// testRepository tests the connection to a repository with the credentials ArgoCD would use for it
+func testRepository(c *gin.Context, service *argocd.Service, request argocd.RepositoryRequest) {
+	request, err := service.MatchingCredentials(c, request)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read ArgoCD repository credentials", "cluster", c.Param("clustername"))
+	}
+	knownHosts, err := service.KnownHosts(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read ArgoCD known hosts", "cluster", c.Param("clustername"))
+	}
+	common.Success(c, argocd.TestConnection(c, request, knownHosts))
+}
+
+// handleGetMemberArgoRepositories handles GET requests for the ArgoCD repositories of a member cluster
+func handleGetMemberArgoRepositories(c *gin.Context) {
+	handleListRepositories(c, argocd.SecretTypeRepository)
+}
+
+// handleCreateMemberArgoRepository handles POST requests to add an ArgoCD repository to a member cluster
+func handleCreateMemberArgoRepository(c *gin.Context) {
+	handleCreateRepository(c, argocd.SecretTypeRepository)
+}
+
+// handleDeleteMemberArgoRepository handles DELETE requests to remove an ArgoCD repository from a member cluster
+func handleDeleteMemberArgoRepository(c *gin.Context) {
+	handleDeleteRepository(c, argocd.SecretTypeRepository)
+}
+
+// handleTestMemberArgoRepository handles POST requests to test the connection to a repository from the
+// request body before it is added to a member cluster
+func handleTestMemberArgoRepository(c *gin.Context) {
+	var request argocd.RepositoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	service, err := memberService(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	testRepository(c, service, request)
+}
+
+// handleTestMemberArgoStoredRepository handles POST requests to test the connection to a repository of a member cluster
+func handleTestMemberArgoStoredRepository(c *gin.Context) {
+	service, err := memberService(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	request, err := service.StoredRepository(c, c.Param("repositoryName"))
+	if err != nil {
+		failRepository(c, err)
+		return
+	}
+	testRepository(c, service, request)
+}
+
+// handleGetMemberArgoRepositoryCredentials handles GET requests for the ArgoCD repository credentials of a member cluster
+func handleGetMemberArgoRepositoryCredentials(c *gin.Context) {
+	handleListRepositories(c, argocd.SecretTypeRepoCreds)
+}
+
+// handleCreateMemberArgoRepositoryCredentials handles POST requests to add ArgoCD repository credentials to a member cluster
+func handleCreateMemberArgoRepositoryCredentials(c *gin.Context) {
+	handleCreateRepository(c, argocd.SecretTypeRepoCreds)
+}
+
+// handleDeleteMemberArgoRepositoryCredentials handles DELETE requests to remove ArgoCD repository credentials from a member cluster
+func handleDeleteMemberArgoRepositoryCredentials(c *gin.Context) {
+	handleDeleteRepository(c, argocd.SecretTypeRepoCreds)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argoworkflow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/argoworkflow"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+)
+
+func init() {
+	r := router.MemberV1()
+	r.GET("/argoworkflow/workflow", handleGetMemberWorkflows)
+	r.GET("/argoworkflow/workflow/:namespace/:name", handleGetMemberWorkflow)
+	r.POST("/argoworkflow/workflow/:namespace/:name/retry", handleRetryMemberWorkflow)
+	r.POST("/argoworkflow/workflow/:namespace/:name/stop", handleStopMemberWorkflow)
+	r.DELETE("/argoworkflow/workflow/:namespace/:name", handleDeleteMemberWorkflow)
+}
+
+// memberService returns the Argo Workflows service of the member cluster of the request, failing the
+// request when Argo Workflows is not installed in the cluster
+func memberService(c *gin.Context) (*argoworkflow.Service, bool) {
+	clusterName := c.Param("clustername")
+	if clusterName == "" {
+		common.Fail(c, fmt.Errorf("cluster name cannot be empty"))
+		return nil, false
+	}
+	if _, err := capability.ResourceFor(c, clusterName, capability.KindWorkflow); err != nil {
+		common.FailWithStatus(c, fmt.Errorf("argo workflows is not installed in cluster %s", clusterName), http.StatusNotFound)
+		return nil, false
+	}
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create dynamic client", "cluster", clusterName)
+		common.Fail(c, err)
+		return nil, false
+	}
+	return argoworkflow.NewService(dynamicClient, clusterName), true
+}
+
+// failWorkflow fails a request on a workflow with the status matching the error
+func failWorkflow(c *gin.Context, err error) {
+	switch {
+	case apierrors.IsNotFound(err):
+		common.FailWithStatus(c, err, http.StatusNotFound)
+	case errors.Is(err, argoworkflow.ErrActionNotAllowed):
+		common.FailWithStatus(c, err, http.StatusConflict)
+	default:
+		common.Fail(c, err)
+	}
+}
+
+// handleGetMemberWorkflows handles GET requests for the Argo Workflows of a member cluster, in the
+// namespace of the namespace query parameter or in all namespaces
+func handleGetMemberWorkflows(c *gin.Context) {
+	service, ok := memberService(c)
+	if !ok {
+		return
+	}
+	workflows, err := service.List(c, c.Query("namespace"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to list workflows", "cluster", c.Param("clustername"))
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"items":      workflows,
+		"totalItems": len(workflows),
+	})
+}
+
+// handleGetMemberWorkflow handles GET requests for a workflow of a member cluster with its summary
+func handleGetMemberWorkflow(c *gin.Context) {
+	service, ok := memberService(c)
+	if !ok {
+		return
+	}
+	workflow, err := service.Get(c, c.Param("namespace"), c.Param("name"))
+	if err != nil {
+		failWorkflow(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"workflow": workflow,
+		"summary":  argoworkflow.Summarize(workflow, c.Param("clustername"), time.Now()),
+	})
+}
+
+// handleRetryMemberWorkflow handles POST requests to submit a new run of a failed workflow
+func handleRetryMemberWorkflow(c *gin.Context) {
+	service, ok := memberService(c)
+	if !ok {
+		return
+	}
+	namespace, name := c.Param("namespace"), c.Param("name")
+	retry, err := service.Retry(c, namespace, name)
+	if err != nil {
+		klog.ErrorS(err, "Failed to retry workflow", "cluster", c.Param("clustername"), "namespace", namespace, "name", name)
+		failWorkflow(c, err)
+		return
+	}
+	klog.InfoS("Retried workflow", "cluster", c.Param("clustername"), "namespace", namespace, "name", name, "retry", retry.GetName())
+	common.Success(c, argoworkflow.Summarize(retry, c.Param("clustername"), time.Now()))
+}
+
+// handleStopMemberWorkflow handles POST requests to stop a running workflow
+func handleStopMemberWorkflow(c *gin.Context) {
+	service, ok := memberService(c)
+	if !ok {
+		return
+	}
+	namespace, name := c.Param("namespace"), c.Param("name")
+	if err := service.Stop(c, namespace, name); err != nil {
+		klog.ErrorS(err, "Failed to stop workflow", "cluster", c.Param("clustername"), "namespace", namespace, "name", name)
+		failWorkflow(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"message": fmt.Sprintf("Workflow %s is stopping", name),
+	})
+}
+
+// handleDeleteMemberWorkflow handles DELETE requests to remove a workflow and its pods
+func handleDeleteMemberWorkflow(c *gin.Context) {
+	service, ok := memberService(c)
+	if !ok {
+		return
+	}
+	namespace, name := c.Param("namespace"), c.Param("name")
+	if err := service.Delete(c, namespace, name); err != nil {
+		klog.ErrorS(err, "Failed to delete workflow", "cluster", c.Param("clustername"), "namespace", namespace, "name", name)
+		failWorkflow(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"message": fmt.Sprintf("Workflow %s deleted successfully", name),
+	})
+}
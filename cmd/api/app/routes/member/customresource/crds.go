@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresource
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/crd"
+)
+
+// handleListCRDs lists the CRDs installed in a member cluster with their versions, scope and instance
+// counts. ?group= limits the list to an API group and ?counts=false skips counting instances.
+func handleListCRDs(c *gin.Context) {
+	clusterName := c.Param("clustername")
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create dynamic client", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	definitions, err := crd.List(c, dynamicClient, c.Query("group"), c.Query("counts") != "false")
+	if err != nil {
+		klog.ErrorS(err, "Failed to list CRDs", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"cluster":    clusterName,
+		"items":      definitions,
+		"totalItems": len(definitions),
+	})
+}
+
+// handleGetCRDSchema returns the OpenAPI v3 schema of a CRD version, the storage version unless
+// ?version= is given
+func handleGetCRDSchema(c *gin.Context) {
+	clusterName := c.Param("clustername")
+	name := c.Param("name")
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create dynamic client", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+
+	def, openAPISchema, err := crd.Schema(c, dynamicClient, name, c.Query("version"))
+	switch {
+	case apierrors.IsNotFound(err), errors.Is(err, crd.ErrVersionNotFound):
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	case err != nil:
+		klog.ErrorS(err, "Failed to get CRD schema", "cluster", clusterName, "crd", name)
+		common.Fail(c, err)
+		return
+	}
+
+	version := c.Query("version")
+	if version == "" {
+		version = def.StorageVersion
+	}
+	common.Success(c, gin.H{
+		"cluster":    clusterName,
+		"definition": def,
+		"version":    version,
+		"hasSchema":  openAPISchema != nil,
+		"schema":     openAPISchema,
+	})
+}
+
+func init() {
+	r := router.MemberV1()
+	r.GET("/crds", handleListCRDs)
+	r.GET("/crds/:name/schema", handleGetCRDSchema)
+}
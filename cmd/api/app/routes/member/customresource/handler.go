@@ -309,7 +309,7 @@ func handleUpdateCRD(c *gin.Context) {
 	var crdData map[string]interface{}
 	if err := c.ShouldBindJSON(&crdData); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON")
-		common.Fail(c, fmt.Errorf("failed to parse request body: %w", err))
+		common.FailWithBindError(c, err)
 		return
 	}
 
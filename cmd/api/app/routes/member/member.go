@@ -18,6 +18,7 @@ package member
 
 import (
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member/argocd"           // Importing member route packages forces route registration
+	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member/argoworkflow"     // Importing member route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member/configmap"        // Importing member route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member/cronjob"          // Importing member route packages forces route registration
 	_ "github.com/karmada-io/dashboard/cmd/api/app/routes/member/customresource"   // Importing member route packages forces route registration
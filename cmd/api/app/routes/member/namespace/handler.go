@@ -71,7 +71,7 @@ func handleCreateNamespace(c *gin.Context) {
 	createNamespaceRequest := new(v1.CreateNamesapceRequest)
 
 	if err := c.ShouldBind(&createNamespaceRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	spec := &ns.NamespaceSpec{
@@ -17,9 +17,12 @@ limitations under the License.
 package pod
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
@@ -100,10 +103,33 @@ func handleGetPodContainerLogs(c *gin.Context) {
 	})
 }
 
+// handleGetPodMetrics returns the live CPU and memory usage of a pod and its containers from the metrics API
+// of the member cluster
+func handleGetPodMetrics(c *gin.Context) {
+	clusterName := c.Param("clustername")
+	dynamicClient, err := client.GetDynamicClientForMember(c, clusterName)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	metrics, err := pod.GetPodMetrics(c, dynamicClient, c.Param("namespace"), c.Param("name"))
+	if errors.Is(err, pod.ErrMetricsUnavailable) {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to get pod metrics", "cluster", clusterName, "namespace", c.Param("namespace"), "pod", c.Param("name"))
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, metrics)
+}
+
 func init() {
 	r := router.MemberV1()
 	r.GET("/pod", handleGetMemberPod)
 	r.GET("/pod/:namespace", handleGetMemberPod)
 	r.GET("/pod/:namespace/:name", handleGetMemberPodDetail)
 	r.GET("/pod/:namespace/:name/logs", handleGetPodContainerLogs)
+	r.GET("/pods/:namespace/:name/metrics", handleGetPodMetrics)
 }
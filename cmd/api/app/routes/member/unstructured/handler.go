@@ -253,7 +253,7 @@ func handlePutResource(c *gin.Context) {
 	var obj *unstructured.Unstructured
 	if err := c.ShouldBindJSON(&obj); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -290,7 +290,7 @@ func handleCreateResource(c *gin.Context) {
 	var obj *unstructured.Unstructured
 	if err := c.ShouldBindJSON(&obj); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
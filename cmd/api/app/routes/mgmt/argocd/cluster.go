@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+)
+
+// MemberDestination is a Karmada member cluster with its registration as an ArgoCD destination cluster
+type MemberDestination struct {
+	Cluster     string                     `json:"cluster"`
+	Ready       bool                       `json:"ready"`
+	Registered  bool                       `json:"registered"`
+	Destination *argocd.DestinationCluster `json:"destination,omitempty"`
+}
+
+// handleGetMgmtArgoClusters handles GET requests for the Karmada member clusters with whether they are
+// destination clusters of the ArgoCD of the management cluster. Destination clusters that are not member
+// clusters are listed apart.
+func handleGetMgmtArgoClusters(c *gin.Context) {
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	destinations, err := service.ListDestinationClusters(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list ArgoCD destination clusters")
+		common.Fail(c, err)
+		return
+	}
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(c, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list member clusters")
+		common.Fail(c, err)
+		return
+	}
+
+	byMember := map[string]argocd.DestinationCluster{}
+	others := make([]argocd.DestinationCluster, 0)
+	for _, destination := range destinations {
+		if destination.MemberCluster != "" {
+			byMember[destination.MemberCluster] = destination
+		} else {
+			others = append(others, destination)
+		}
+	}
+	members := make([]MemberDestination, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		member := MemberDestination{
+			Cluster: clusters.Items[i].Name,
+			Ready:   clusterReady(&clusters.Items[i]),
+		}
+		if destination, ok := byMember[member.Cluster]; ok {
+			member.Registered = true
+			member.Destination = &destination
+		}
+		members = append(members, member)
+	}
+	common.Success(c, gin.H{
+		"items":      members,
+		"totalItems": len(members),
+		"others":     others,
+	})
+}
+
+// clusterReady reports whether the Ready condition of a member cluster is true
+func clusterReady(cluster *clusterv1alpha1.Cluster) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == clusterv1alpha1.ClusterConditionReady {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// handleRegisterMgmtArgoCluster handles POST requests to register a member cluster as a destination cluster of
+// the ArgoCD of the management cluster. A ServiceAccount bound to cluster-admin is created in the member
+// cluster and its token is stored in the cluster secret; registering again replaces the secret.
+func handleRegisterMgmtArgoCluster(c *gin.Context) {
+	clusterName := c.Param("clusterName")
+	memberCluster, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			common.FailWithStatus(c, err, http.StatusNotFound)
+			return
+		}
+		common.Fail(c, err)
+		return
+	}
+	if memberCluster.Spec.APIEndpoint == "" {
+		common.FailWithStatus(c, fmt.Errorf("cluster %s has no API endpoint ArgoCD could connect to", clusterName), http.StatusBadRequest)
+		return
+	}
+	memberClient := client.InClusterClientForMemberCluster(clusterName)
+	if memberClient == nil {
+		common.Fail(c, fmt.Errorf("failed to get client for cluster %s", clusterName))
+		return
+	}
+
+	token, caData, err := argocd.EnsureManagerToken(c, memberClient)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create the ArgoCD manager in member cluster", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	if len(caData) == 0 && memberCluster.Spec.SecretRef != nil {
+		// The CA the cluster was joined with
+		secret, err := client.InClusterClientForKarmadaAPIServer().CoreV1().Secrets(memberCluster.Spec.SecretRef.Namespace).Get(c, memberCluster.Spec.SecretRef.Name, metav1.GetOptions{})
+		if err == nil {
+			caData = secret.Data[clusterv1alpha1.SecretCADataKey]
+		}
+	}
+
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	destination, err := service.RegisterMemberCluster(c, clusterName, argocd.ClusterCredentials{
+		Server:      memberCluster.Spec.APIEndpoint,
+		BearerToken: token,
+		CAData:      caData,
+		Insecure:    memberCluster.Spec.InsecureSkipTLSVerification,
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to register member cluster in ArgoCD", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Registered member cluster as ArgoCD destination cluster", "cluster", clusterName, "server", destination.Server)
+	common.Success(c, destination)
+}
+
+// handleUnregisterMgmtArgoCluster handles DELETE requests to remove a member cluster from the destination
+// clusters of the ArgoCD of the management cluster, and its ServiceAccount from the member cluster
+func handleUnregisterMgmtArgoCluster(c *gin.Context) {
+	clusterName := c.Param("clusterName")
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := service.UnregisterMemberCluster(c, clusterName); err != nil {
+		if apierrors.IsNotFound(err) {
+			common.FailWithStatus(c, fmt.Errorf("cluster %s is not registered in ArgoCD", clusterName), http.StatusNotFound)
+			return
+		}
+		klog.ErrorS(err, "Failed to unregister member cluster from ArgoCD", "cluster", clusterName)
+		common.Fail(c, err)
+		return
+	}
+	// The registration is gone even if the member cluster cannot be reached
+	if memberClient := client.InClusterClientForMemberCluster(clusterName); memberClient != nil {
+		if err := argocd.RevokeManagerToken(c, memberClient); err != nil {
+			klog.ErrorS(err, "Failed to remove the ArgoCD manager from member cluster", "cluster", clusterName)
+		}
+	}
+	common.Success(c, gin.H{
+		"message": fmt.Sprintf("Cluster %s unregistered from ArgoCD", clusterName),
+	})
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+)
+
+// failRepository fails a request on a repository secret with the status matching the error
+func failRepository(c *gin.Context, err error) {
+	switch {
+	case apierrors.IsNotFound(err):
+		common.FailWithStatus(c, err, http.StatusNotFound)
+	case apierrors.IsAlreadyExists(err):
+		common.FailWithStatus(c, err, http.StatusConflict)
+	default:
+		common.Fail(c, err)
+	}
+}
+
+// handleListRepositories lists the repository secrets of a type in the management cluster
+func handleListRepositories(c *gin.Context, secretType string) {
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	repositories, err := service.ListRepositories(c, secretType)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list ArgoCD repository secrets", "secretType", secretType)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"items":      repositories,
+		"totalItems": len(repositories),
+	})
+}
+
+// handleCreateRepository stores a repository secret of a type in the management cluster
+func handleCreateRepository(c *gin.Context, secretType string) {
+	var request argocd.RepositoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := request.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	repository, err := service.CreateRepository(c, secretType, request)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create ArgoCD repository secret", "secretType", secretType, "url", request.URL)
+		failRepository(c, err)
+		return
+	}
+	common.Success(c, repository)
+}
+
+// handleDeleteRepository deletes a repository secret of a type from the management cluster
+func handleDeleteRepository(c *gin.Context, secretType string) {
+	name := c.Param("repositoryName")
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := service.DeleteRepository(c, secretType, name); err != nil {
+		klog.ErrorS(err, "Failed to delete ArgoCD repository secret", "secretType", secretType, "name", name)
+		failRepository(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"message": fmt.Sprintf("Repository secret %s deleted successfully", name),
+	})
+}
+
+// testRepository tests the connection to a repository with the credentials ArgoCD would use for it
+func testRepository(c *gin.Context, service *argocd.Service, request argocd.RepositoryRequest) {
+	request, err := service.MatchingCredentials(c, request)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read ArgoCD repository credentials")
+	}
+	knownHosts, err := service.KnownHosts(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read ArgoCD known hosts")
+	}
+	common.Success(c, argocd.TestConnection(c, request, knownHosts))
+}
+
+// handleGetMgmtArgoRepositories handles GET requests for the ArgoCD repositories of the management cluster
+func handleGetMgmtArgoRepositories(c *gin.Context) {
+	handleListRepositories(c, argocd.SecretTypeRepository)
+}
+
+// handleCreateMgmtArgoRepository handles POST requests to add an ArgoCD repository to the management cluster
+func handleCreateMgmtArgoRepository(c *gin.Context) {
+	handleCreateRepository(c, argocd.SecretTypeRepository)
+}
+
+// handleDeleteMgmtArgoRepository handles DELETE requests to remove an ArgoCD repository from the management cluster
+func handleDeleteMgmtArgoRepository(c *gin.Context) {
+	handleDeleteRepository(c, argocd.SecretTypeRepository)
+}
+
+// handleTestMgmtArgoRepository handles POST requests to test the connection to a repository from the
+// request body before it is added to the management cluster
+func handleTestMgmtArgoRepository(c *gin.Context) {
+	var request argocd.RepositoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	testRepository(c, service, request)
+}
+
+// handleTestMgmtArgoStoredRepository handles POST requests to test the connection to a repository of the management cluster
+func handleTestMgmtArgoStoredRepository(c *gin.Context) {
+	service, err := mgmtService()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	request, err := service.StoredRepository(c, c.Param("repositoryName"))
+	if err != nil {
+		failRepository(c, err)
+		return
+	}
+	testRepository(c, service, request)
+}
+
+// handleGetMgmtArgoRepositoryCredentials handles GET requests for the ArgoCD repository credentials of the management cluster
+func handleGetMgmtArgoRepositoryCredentials(c *gin.Context) {
+	handleListRepositories(c, argocd.SecretTypeRepoCreds)
+}
+
+// handleCreateMgmtArgoRepositoryCredentials handles POST requests to add ArgoCD repository credentials to the management cluster
+func handleCreateMgmtArgoRepositoryCredentials(c *gin.Context) {
+	handleCreateRepository(c, argocd.SecretTypeRepoCreds)
+}
+
+// handleDeleteMgmtArgoRepositoryCredentials handles DELETE requests to remove ArgoCD repository credentials from the management cluster
+func handleDeleteMgmtArgoRepositoryCredentials(c *gin.Context) {
+	handleDeleteRepository(c, argocd.SecretTypeRepoCreds)
+}
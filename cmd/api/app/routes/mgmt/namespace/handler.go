@@ -113,7 +113,7 @@ func HandleCreateMgmtNamespace(c *gin.Context) {
 	var createRequest apiv1.CreateNamesapceRequest
 	if err := c.ShouldBindJSON(&createRequest); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON for namespace creation")
-		common.Fail(c, errors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
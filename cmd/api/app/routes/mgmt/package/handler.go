@@ -151,7 +151,7 @@ func HandleCreateRepository(c *gin.Context) {
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		klog.ErrorS(err, "Failed to parse request body")
-		common.Fail(c, errors.NewBadRequest(fmt.Sprintf("Failed to parse request body: %v", err)))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -212,7 +212,7 @@ func HandleUpdateRepository(c *gin.Context) {
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		klog.ErrorS(err, "Failed to parse request body")
-		common.Fail(c, errors.NewBadRequest(fmt.Sprintf("Failed to parse request body: %v", err)))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -394,7 +394,7 @@ func HandleCreatePackageRev(c *gin.Context) {
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		klog.ErrorS(err, "Failed to parse request body")
-		common.Fail(c, errors.NewBadRequest(fmt.Sprintf("Failed to parse request body: %v", err)))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -452,7 +452,7 @@ func HandleUpdatePackageRev(c *gin.Context) {
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		klog.ErrorS(err, "Failed to parse request body")
-		common.Fail(c, errors.NewBadRequest(fmt.Sprintf("Failed to parse request body: %v", err)))
+		common.FailWithBindError(c, err)
 		return
 	}
 
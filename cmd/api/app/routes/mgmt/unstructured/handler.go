@@ -221,7 +221,7 @@ func HandleGetMgmtResource(c *gin.Context) {
 
 	if err := validateResourceParams(kind, namespace, name); err != nil {
 		klog.ErrorS(err, "Invalid resource parameters")
-		common.Fail(c, errors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -260,7 +260,7 @@ func HandleDeleteMgmtResource(c *gin.Context) {
 
 	if err := validateResourceParams(kind, namespace, name); err != nil {
 		klog.ErrorS(err, "Invalid resource parameters")
-		common.Fail(c, errors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -300,14 +300,14 @@ func HandlePutMgmtResource(c *gin.Context) {
 
 	if err := validateResourceParams(kind, namespace, name); err != nil {
 		klog.ErrorS(err, "Invalid resource parameters")
-		common.Fail(c, errors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON")
-		common.Fail(c, errors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -354,7 +354,7 @@ func HandleCreateMgmtResource(c *gin.Context) {
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		klog.ErrorS(err, "Failed to bind JSON")
-		common.Fail(c, errors.NewBadRequest(err.Error()))
+		common.FailWithBindError(c, err)
 		return
 	}
 
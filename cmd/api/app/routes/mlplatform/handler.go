@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mlplatform serves views over the ML workloads of all member clusters, such as the Kubeflow
+// Pipelines runs of every cluster.
+package mlplatform
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/dataselect"
+	"github.com/karmada-io/dashboard/pkg/multicluster"
+	"github.com/karmada-io/dashboard/pkg/resource/argoworkflow"
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// clusterTimeout bounds the time spent listing the runs of one cluster, so an unreachable cluster
+// does not hold up the others
+const clusterTimeout = 10 * time.Second
+
+var profileGVR = schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "profiles"}
+
+// PipelineRuns are the Kubeflow Pipelines runs of the member clusters and the load of each cluster
+type PipelineRuns struct {
+	Runs       []argoworkflow.PipelineRun  `json:"runs"`
+	Clusters   []argoworkflow.PipelineLoad `json:"clusters"`
+	TotalItems int                         `json:"totalItems"`
+	// Errors lists the clusters whose runs could not be listed
+	Errors []multicluster.ClusterError `json:"errors"`
+}
+
+// profileOwners returns the owners of the Kubeflow Profiles of the control plane by profile name, which is
+// also the name of the profile namespace
+func profileOwners(ctx context.Context) (map[string]string, error) {
+	karmadaConfig, _, err := client.GetKarmadaConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get karmada config: %v", err)
+	}
+	karmadaDynamicClient, err := dynamic.NewForConfig(karmadaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create karmada dynamic client: %v", err)
+	}
+	list, err := karmadaDynamicClient.Resource(profileGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Kubeflow Profiles: %v", err)
+	}
+	owners := make(map[string]string, len(list.Items))
+	for _, item := range list.Items {
+		owner, _, _ := unstructured.NestedString(item.Object, "spec", "owner", "name")
+		owners[item.GetName()] = owner
+	}
+	return owners, nil
+}
+
+// clusterPipelineRuns lists the pipeline runs of a cluster, none when Argo Workflows is not installed
+func clusterPipelineRuns(ctx context.Context, clusterName, namespace string) ([]argoworkflow.PipelineRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, clusterTimeout)
+	defer cancel()
+	if _, err := capability.ResourceFor(ctx, clusterName, capability.KindWorkflow); err != nil {
+		return nil, nil
+	}
+	dynamicClient, err := client.DynamicClientForMemberCluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return argoworkflow.NewService(dynamicClient, clusterName).ListPipelineRuns(ctx, namespace)
+}
+
+// handleGetPipelineRuns aggregates the Kubeflow Pipelines runs of the ready member clusters, read from the
+// Argo Workflows executing them, and attributes them to the profile owning their namespace. The runs can
+// be filtered by cluster, namespace, profile and phase; the load of each cluster ignores the phase filter.
+func handleGetPipelineRuns(c *gin.Context) {
+	clusterFilter := c.Query("cluster")
+	namespace := c.Query("namespace")
+	profileFilter := c.Query("profile")
+	phaseFilter := c.Query("phase")
+
+	clusters, err := cluster.GetClusterList(client.InClusterKarmadaClient(), dataselect.NoDataSelect)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	owners, err := profileOwners(c)
+	if err != nil {
+		// The runs are still listed, without attribution
+		klog.ErrorS(err, "Failed to list Kubeflow Profiles for pipeline run attribution")
+	}
+
+	var members []cluster.Cluster
+	for _, member := range clusters.Clusters {
+		if clusterFilter == "" || member.ObjectMeta.Name == clusterFilter {
+			members = append(members, member)
+		}
+	}
+	names, notReady := multicluster.ReadyClusters(members)
+	type clusterRuns struct {
+		name string
+		runs []argoworkflow.PipelineRun
+	}
+	gathered, failed := multicluster.Gather(c, names, func(ctx context.Context, name string) (clusterRuns, error) {
+		runs, err := clusterPipelineRuns(ctx, name, namespace)
+		return clusterRuns{name: name, runs: runs}, err
+	})
+
+	result := PipelineRuns{
+		Runs:     []argoworkflow.PipelineRun{},
+		Clusters: []argoworkflow.PipelineLoad{},
+		Errors:   append(append([]multicluster.ClusterError{}, notReady...), failed...),
+	}
+	for _, g := range gathered {
+		runs := make([]argoworkflow.PipelineRun, 0, len(g.runs))
+		for _, run := range g.runs {
+			if owner, ok := owners[run.Namespace]; ok {
+				run.Profile = run.Namespace
+				run.Owner = owner
+			}
+			if profileFilter != "" && run.Profile != profileFilter {
+				continue
+			}
+			runs = append(runs, run)
+		}
+		result.Clusters = append(result.Clusters, argoworkflow.CountPipelineLoad(g.name, runs))
+		for _, run := range runs {
+			if phaseFilter == "" || run.Phase == phaseFilter {
+				result.Runs = append(result.Runs, run)
+			}
+		}
+	}
+	argoworkflow.SortPipelineRuns(result.Runs)
+	result.TotalItems = len(result.Runs)
+	common.Success(c, result)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/workloads/pipelines", router.EnsureMgmtAdminMiddleware(), handleGetPipelineRuns)
+}
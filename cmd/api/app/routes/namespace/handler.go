@@ -31,7 +31,7 @@ func handleCreateNamespace(c *gin.Context) {
 	k8sClient := client.InClusterClientForKarmadaAPIServer()
 	createNamespaceRequest := new(v1.CreateNamesapceRequest)
 	if err := c.ShouldBind(&createNamespaceRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	spec := &ns.NamespaceSpec{
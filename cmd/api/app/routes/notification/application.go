@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+)
+
+// EventLabelProject is the event label holding the ArgoCD project of an application event
+const EventLabelProject = "project"
+
+// mgmtClusterName is the cluster name of the events of the ArgoCD of the management cluster
+const mgmtClusterName = "mgmt-cluster"
+
+// Application states tracked by the watcher
+const (
+	applicationHealthy   = "healthy"
+	applicationDegraded  = "degraded"
+	applicationOther     = "other"
+	applicationSynced    = "synced"
+	applicationOutOfSync = "outofsync"
+)
+
+// applicationHealth maps the ArgoCD health of an application to a tracked state. Missing resources count as
+// degraded, while progressing or suspended applications are neither healthy nor degraded.
+func applicationHealth(app *unstructured.Unstructured) string {
+	status, _, _ := unstructured.NestedString(app.Object, "status", "health", "status")
+	switch status {
+	case "Healthy":
+		return applicationHealthy
+	case "Degraded", "Missing":
+		return applicationDegraded
+	default:
+		return applicationOther
+	}
+}
+
+func applicationSync(app *unstructured.Unstructured) string {
+	status, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	if status == "OutOfSync" {
+		return applicationOutOfSync
+	}
+	return applicationSynced
+}
+
+// pollMgmtApplications checks the ArgoCD applications of the management cluster
+func (w *watcher) pollMgmtApplications(ctx context.Context) {
+	dynamicClient, err := client.GetDynamicClient()
+	if err != nil {
+		klog.V(4).InfoS("Notification watcher failed to create management cluster client", "error", err)
+		return
+	}
+	w.pollApplications(ctx, dynamicClient, mgmtClusterName)
+}
+
+// pollApplications checks the health and sync status of the ArgoCD applications of a cluster. Clusters
+// without ArgoCD fail the list and are skipped.
+func (w *watcher) pollApplications(ctx context.Context, dynamicClient dynamic.Interface, clusterName string) {
+	applications, err := argocd.NewService(dynamicClient, clusterName).List(ctx, argocd.Application, argocd.Namespace)
+	if err != nil {
+		klog.V(4).InfoS("Notification watcher failed to list ArgoCD applications", "cluster", clusterName, "error", err)
+		return
+	}
+	now := time.Now()
+	for i := range applications {
+		app := &applications[i]
+		project, _, _ := unstructured.NestedString(app.Object, "spec", "project")
+		resource := fmt.Sprintf("%s/%s", app.GetNamespace(), app.GetName())
+		key := fmt.Sprintf("application/%s/%s", clusterName, resource)
+		event := Event{
+			Cluster:  clusterName,
+			Resource: resource,
+			Labels:   map[string]string{EventLabelProject: project},
+		}
+
+		healthKey := key + "/health"
+		previous := w.seen[healthKey]
+		health := applicationHealth(app)
+		if w.transition(healthKey, health) {
+			message, _, _ := unstructured.NestedString(app.Object, "status", "health", "message")
+			switch {
+			case health == applicationDegraded:
+				event.Type = EventApplicationDegraded
+				event.Title = fmt.Sprintf("Application %s is degraded", app.GetName())
+				event.Message = fmt.Sprintf("ArgoCD application %s of project %s on cluster %s is degraded: %s", resource, project, clusterName, message)
+				w.emitApplication(ctx, key, now, event)
+			case health == applicationHealthy && previous == applicationDegraded:
+				event.Type = EventApplicationRecovered
+				event.Title = fmt.Sprintf("Application %s recovered", app.GetName())
+				event.Message = fmt.Sprintf("ArgoCD application %s of project %s on cluster %s is healthy again", resource, project, clusterName)
+				w.emitApplication(ctx, key, now, event)
+			}
+		}
+
+		sync := applicationSync(app)
+		if w.transition(key+"/sync", sync) && sync == applicationOutOfSync {
+			revision, _, _ := unstructured.NestedString(app.Object, "status", "sync", "revision")
+			event.Type = EventApplicationOutOfSync
+			event.Title = fmt.Sprintf("Application %s is out of sync", app.GetName())
+			event.Message = fmt.Sprintf("ArgoCD application %s of project %s on cluster %s is out of sync with its source, last synced revision %s", resource, project, clusterName, revision)
+			w.emitApplication(ctx, key, now, event)
+		}
+	}
+}
+
+// emitApplication dispatches an application event unless one was dispatched for the same application within
+// the suppression window, so that a flapping application does not flood the channels
+func (w *watcher) emitApplication(ctx context.Context, key string, now time.Time, event Event) {
+	if last, ok := w.notified[key]; ok && now.Sub(last) < w.suppressionWindow {
+		klog.V(2).InfoS("Suppressed application notification", "event", event.Type, "cluster", event.Cluster, "application", event.Resource)
+		return
+	}
+	w.notified[key] = now
+	event.Timestamp = timeNow()
+	Dispatch(ctx, event)
+}
+
+// forgetNotified drops the applications whose suppression window has passed
+func (w *watcher) forgetNotified(now time.Time) {
+	for key, last := range w.notified {
+		if now.Sub(last) >= w.suppressionWindow {
+			delete(w.notified, key)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	ChannelLabelKey     = "ml-platform.io/notification-channel"
+	ChannelTypeLabelKey = "ml-platform.io/notification-channel-type"
+)
+
+// Channel types
+const (
+	ChannelTypeWebhook = "webhook"
+	ChannelTypeSlack   = "slack"
+	ChannelTypeEmail   = "email"
+)
+
+// Event types that channels can subscribe to
+const (
+	EventBackupFailed        = "backup.failed"
+	EventRecoveryCompleted   = "recovery.completed"
+	EventControllerUnhealthy = "controller.unhealthy"
+	EventClusterNotReady     = "cluster.notready"
+	// ArgoCD applications of the member clusters and of the management cluster
+	EventApplicationDegraded  = "application.degraded"
+	EventApplicationRecovered = "application.recovered"
+	EventApplicationOutOfSync = "application.outofsync"
+	// Backups that still fail after the retries of their retry policy
+	EventBackupRetriesExhausted = "backup.retriesexhausted"
+)
+
+var supportedEvents = []string{
+	EventBackupFailed,
+	EventRecoveryCompleted,
+	EventControllerUnhealthy,
+	EventClusterNotReady,
+	EventApplicationDegraded,
+	EventApplicationRecovered,
+	EventApplicationOutOfSync,
+	EventBackupRetriesExhausted,
+}
+
+// ChannelConfig holds the type specific settings of a channel
+type ChannelConfig struct {
+	// Webhook and Slack
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Email
+	SMTPHost string   `json:"smtpHost,omitempty"`
+	SMTPPort int      `json:"smtpPort,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+}
+
+// Channel is a notification destination and the events it is subscribed to
+type Channel struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Enabled     bool          `json:"enabled"`
+	Events      []string      `json:"events"`
+	Projects    []string      `json:"projects,omitempty"` // ArgoCD projects of the application events, all when empty
+	Config      ChannelConfig `json:"config"`
+	CreatedAt   string        `json:"createdAt"`
+}
+
+type CreateChannelRequest struct {
+	Name        string        `json:"name" binding:"required"`
+	Type        string        `json:"type" binding:"required,oneof=webhook slack email"`
+	Description string        `json:"description"`
+	Events      []string      `json:"events"`
+	Projects    []string      `json:"projects"`
+	Config      ChannelConfig `json:"config" binding:"required"`
+}
+
+type UpdateChannelRequest struct {
+	Description *string        `json:"description"`
+	Enabled     *bool          `json:"enabled"`
+	Config      *ChannelConfig `json:"config"`
+}
+
+type UpdateSubscriptionsRequest struct {
+	Events   []string `json:"events"`
+	Projects []string `json:"projects"`
+}
+
+// validateChannel checks the settings required by the channel type
+func validateChannel(channel *Channel) error {
+	switch channel.Type {
+	case ChannelTypeWebhook, ChannelTypeSlack:
+		if !strings.HasPrefix(channel.Config.URL, "http://") && !strings.HasPrefix(channel.Config.URL, "https://") {
+			return fmt.Errorf("a valid url is required for %s channels", channel.Type)
+		}
+	case ChannelTypeEmail:
+		if channel.Config.SMTPHost == "" || channel.Config.From == "" || len(channel.Config.To) == 0 {
+			return fmt.Errorf("smtpHost, from and to are required for email channels")
+		}
+		if channel.Config.SMTPPort == 0 {
+			channel.Config.SMTPPort = 587
+		}
+	default:
+		return fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+	return validateEvents(channel.Events)
+}
+
+func validateEvents(events []string) error {
+	for _, event := range events {
+		supported := false
+		for _, e := range supportedEvents {
+			if e == event {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("unsupported event %q, supported events are %s", event, strings.Join(supportedEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// channelSecretName returns the name of the secret that stores a channel
+func channelSecretName(name string) string {
+	return fmt.Sprintf("notification-channel-%s", name)
+}
+
+// secretToChannel decodes a channel from its secret
+func secretToChannel(secret *corev1.Secret) (*Channel, error) {
+	channel := &Channel{}
+	if err := json.Unmarshal(secret.Data["channel"], channel); err != nil {
+		return nil, fmt.Errorf("failed to decode notification channel %s: %w", secret.Name, err)
+	}
+	channel.CreatedAt = secret.CreationTimestamp.Format("2006-01-02 15:04:05")
+	return channel, nil
+}
+
+// redacted returns a copy of the channel without credentials, for API responses
+func (ch Channel) redacted() Channel {
+	if ch.Config.Password != "" {
+		ch.Config.Password = "******"
+	}
+	return ch
+}
+
+// listChannels returns all notification channels
+func listChannels(ctx context.Context) ([]*Channel, error) {
+	k8sClient := client.InClusterClient()
+	secrets, err := k8sClient.CoreV1().Secrets(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: ChannelLabelKey + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	channels := make([]*Channel, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		channel, err := secretToChannel(&secrets.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid notification channel", "secret", secrets.Items[i].Name)
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// saveChannel writes the channel into its secret
+func saveChannel(ctx context.Context, secret *corev1.Secret, channel *Channel) error {
+	data, err := json.Marshal(channel)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data["channel"] = data
+	_, err = client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// createChannel stores a new channel in its own secret
+func createChannel(ctx context.Context, channel *Channel) error {
+	data, err := json.Marshal(channel)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      channelSecretName(channel.Name),
+			Namespace: config.GetNamespace(),
+			Labels: map[string]string{
+				ChannelLabelKey:     "true",
+				ChannelTypeLabelKey: channel.Type,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"channel": data},
+	}
+	created, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	channel.CreatedAt = created.CreationTimestamp.Format("2006-01-02 15:04:05")
+	return nil
+}
+
+// getChannelSecret returns the secret and decoded channel for the given name
+func getChannelSecret(ctx context.Context, name string) (*corev1.Secret, *Channel, error) {
+	secret, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Get(ctx, channelSecretName(name), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, pkgerrors.NewNotFound("Notification channel not found")
+		}
+		return nil, nil, err
+	}
+	channel, err := secretToChannel(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return secret, channel, nil
+}
+
+// handleGetChannels returns all notification channels
+func handleGetChannels(c *gin.Context) {
+	channels, err := listChannels(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list notification channels")
+		common.Fail(c, err)
+		return
+	}
+	result := make([]Channel, 0, len(channels))
+	for _, channel := range channels {
+		result = append(result, channel.redacted())
+	}
+	common.Success(c, gin.H{
+		"channels":   result,
+		"totalItems": len(result),
+	})
+}
+
+// handleGetChannel returns a single notification channel
+func handleGetChannel(c *gin.Context) {
+	_, channel, err := getChannelSecret(c, c.Param("name"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, channel.redacted())
+}
+
+// handleCreateChannel registers a new notification channel
+func handleCreateChannel(c *gin.Context) {
+	var req CreateChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	channel := &Channel{
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+		Enabled:     true,
+		Events:      req.Events,
+		Projects:    req.Projects,
+		Config:      req.Config,
+	}
+	if channel.Events == nil {
+		channel.Events = []string{}
+	}
+	if err := validateChannel(channel); err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	if err := createChannel(c, channel); err != nil {
+		klog.ErrorS(err, "Failed to create notification channel", "name", req.Name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, channel.redacted())
+}
+
+// handleUpdateChannel updates the settings of a notification channel
+func handleUpdateChannel(c *gin.Context) {
+	var req UpdateChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	secret, channel, err := getChannelSecret(c, c.Param("name"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if req.Description != nil {
+		channel.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	if req.Config != nil {
+		password := channel.Config.Password
+		channel.Config = *req.Config
+		// Keep the stored password when the client sends back the redacted value
+		if channel.Config.Password == "" || channel.Config.Password == "******" {
+			channel.Config.Password = password
+		}
+	}
+	if err := validateChannel(channel); err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	if err := saveChannel(c, secret, channel); err != nil {
+		klog.ErrorS(err, "Failed to update notification channel", "name", channel.Name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, channel.redacted())
+}
+
+// handleUpdateSubscriptions replaces the events a channel is subscribed to and the ArgoCD projects it receives
+// application events of
+func handleUpdateSubscriptions(c *gin.Context) {
+	var req UpdateSubscriptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := validateEvents(req.Events); err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	secret, channel, err := getChannelSecret(c, c.Param("name"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	channel.Events = req.Events
+	if channel.Events == nil {
+		channel.Events = []string{}
+	}
+	channel.Projects = req.Projects
+	if err := saveChannel(c, secret, channel); err != nil {
+		klog.ErrorS(err, "Failed to update notification subscriptions", "name", channel.Name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, channel.redacted())
+}
+
+// handleDeleteChannel removes a notification channel
+func handleDeleteChannel(c *gin.Context) {
+	name := c.Param("name")
+	err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Delete(c, channelSecretName(name), metav1.DeleteOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			common.Fail(c, pkgerrors.NewNotFound("Notification channel not found"))
+			return
+		}
+		klog.ErrorS(err, "Failed to delete notification channel", "name", name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "Notification channel deleted successfully"})
+}
+
+// handleTestChannel sends a test notification through the channel
+func handleTestChannel(c *gin.Context) {
+	_, channel, err := getChannelSecret(c, c.Param("name"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	record := deliver(c, channel, Event{
+		Type:      "test",
+		Title:     "Test notification",
+		Message:   fmt.Sprintf("This is a test notification for channel %s", channel.Name),
+		Timestamp: timeNow(),
+	}, nil)
+	common.Success(c, record)
+}
+
+// handleGetEventTypes returns the events channels can subscribe to
+func handleGetEventTypes(c *gin.Context) {
+	common.Success(c, supportedEvents)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/notification/events", handleGetEventTypes)
+	r.GET("/notification/channels", handleGetChannels)
+	r.POST("/notification/channels", handleCreateChannel)
+	r.GET("/notification/channels/:name", handleGetChannel)
+	r.PUT("/notification/channels/:name", handleUpdateChannel)
+	r.DELETE("/notification/channels/:name", handleDeleteChannel)
+	r.PUT("/notification/channels/:name/subscriptions", handleUpdateSubscriptions)
+	r.POST("/notification/channels/:name/test", handleTestChannel)
+	r.GET("/notification/deliveries", handleGetDeliveries)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const settingsKindChannel = "notificationChannel"
+
+// declaredChannel converts a declared channel into a channel, keeping the password of the current one when
+// the declaration has none
+func declaredChannel(declared v1.DeclaredNotificationChannel, current *Channel) (*Channel, error) {
+	channel := &Channel{
+		Name:        declared.Name,
+		Type:        declared.Type,
+		Description: declared.Description,
+		Enabled:     declared.Enabled == nil || *declared.Enabled,
+		Events:      declared.Events,
+		Projects:    declared.Projects,
+	}
+	if channel.Events == nil {
+		channel.Events = []string{}
+	}
+	data, err := json.Marshal(declared.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &channel.Config); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	if current != nil && channel.Config.Password == "" {
+		channel.Config.Password = current.Config.Password
+	}
+	if err := validateChannel(channel); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+// CurrentChannels returns the notification channels as declared settings, without passwords
+func CurrentChannels(ctx context.Context) ([]v1.DeclaredNotificationChannel, error) {
+	channels, err := listChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	declared := make([]v1.DeclaredNotificationChannel, 0, len(channels))
+	for _, channel := range channels {
+		cfg := channel.Config
+		cfg.Password = ""
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return nil, err
+		}
+		var configMap map[string]interface{}
+		if err := json.Unmarshal(data, &configMap); err != nil {
+			return nil, err
+		}
+		enabled := channel.Enabled
+		declared = append(declared, v1.DeclaredNotificationChannel{
+			Name:        channel.Name,
+			Type:        channel.Type,
+			Description: channel.Description,
+			Enabled:     &enabled,
+			Events:      channel.Events,
+			Projects:    channel.Projects,
+			Config:      configMap,
+		})
+	}
+	return declared, nil
+}
+
+// ReconcileChannels brings the notification channels to the declared state, or only reports the changes with dryRun.
+// Channels that are not declared are deleted with prune.
+func ReconcileChannels(ctx context.Context, desired []v1.DeclaredNotificationChannel, prune, dryRun bool) ([]v1.SettingsChange, error) {
+	channels, err := listChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*Channel, len(channels))
+	for _, channel := range channels {
+		existing[channel.Name] = channel
+	}
+
+	var changes []v1.SettingsChange
+	declared := map[string]bool{}
+	for _, d := range desired {
+		change := v1.SettingsChange{Kind: settingsKindChannel, Name: d.Name}
+		declared[d.Name] = true
+		current := existing[d.Name]
+
+		channel, err := declaredChannel(d, current)
+		if current == nil {
+			change.Action = v1.SettingsActionCreate
+			if err == nil && !dryRun {
+				err = createChannel(ctx, channel)
+			}
+			changes = append(changes, withError(change, err))
+			continue
+		}
+		if err != nil {
+			change.Action = v1.SettingsActionUpdate
+			changes = append(changes, withError(change, err))
+			continue
+		}
+
+		if channel.Type != current.Type {
+			// The type is part of the secret labels, so a channel cannot change it in place
+			change.Action = v1.SettingsActionUpdate
+			change.Error = fmt.Sprintf("the type of channel %s cannot be changed from %s to %s", d.Name, current.Type, channel.Type)
+			changes = append(changes, change)
+			continue
+		}
+		if channel.Description != current.Description {
+			change.Fields = append(change.Fields, "description")
+		}
+		if channel.Enabled != current.Enabled {
+			change.Fields = append(change.Fields, "enabled")
+		}
+		if !reflect.DeepEqual(channel.Events, current.Events) && (len(channel.Events) > 0 || len(current.Events) > 0) {
+			change.Fields = append(change.Fields, "events")
+		}
+		if !reflect.DeepEqual(channel.Projects, current.Projects) && (len(channel.Projects) > 0 || len(current.Projects) > 0) {
+			change.Fields = append(change.Fields, "projects")
+		}
+		if !reflect.DeepEqual(channel.Config, current.Config) {
+			change.Fields = append(change.Fields, "config")
+		}
+		change.Action = v1.SettingsActionUnchanged
+		if len(change.Fields) > 0 {
+			change.Action = v1.SettingsActionUpdate
+			if !dryRun {
+				var secret *corev1.Secret
+				if secret, _, err = getChannelSecret(ctx, d.Name); err == nil {
+					err = saveChannel(ctx, secret, channel)
+				}
+			}
+		}
+		changes = append(changes, withError(change, err))
+	}
+
+	if prune {
+		for _, channel := range channels {
+			if declared[channel.Name] {
+				continue
+			}
+			var err error
+			change := v1.SettingsChange{Kind: settingsKindChannel, Name: channel.Name, Action: v1.SettingsActionDelete}
+			if !dryRun {
+				err = client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Delete(ctx, channelSecretName(channel.Name), metav1.DeleteOptions{})
+				if apierrors.IsNotFound(err) {
+					err = nil
+				}
+			}
+			changes = append(changes, withError(change, err))
+		}
+	}
+	return changes, nil
+}
+
+func withError(change v1.SettingsChange, err error) v1.SettingsChange {
+	if err != nil {
+		change.Error = err.Error()
+	}
+	return change
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+)
+
+const (
+	maxDeliveryAttempts = 3
+	initialRetryBackoff = 2 * time.Second
+	deliveryTimeout     = 10 * time.Second
+	deliveryLogSize     = 500
+)
+
+// Event is a notification about something that happened in the platform
+type Event struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Message   string            `json:"message"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Resource  string            `json:"resource,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp string            `json:"timestamp"`
+}
+
+// Attachment is a file delivered with an event, such as a generated report
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Content     []byte `json:"content"`
+}
+
+// DeliveryRecord is an entry of the delivery log
+type DeliveryRecord struct {
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channelType"`
+	Event       Event  `json:"event"`
+	Success     bool   `json:"success"`
+	Attempts    int    `json:"attempts"`
+	Error       string `json:"error,omitempty"`
+	DeliveredAt string `json:"deliveredAt"`
+}
+
+// deliveryLog keeps the most recent deliveries in memory
+var deliveryLog = struct {
+	sync.Mutex
+	records []DeliveryRecord
+}{}
+
+func timeNow() string {
+	return time.Now().Format(time.RFC3339)
+}
+
+func recordDelivery(record DeliveryRecord) {
+	deliveryLog.Lock()
+	defer deliveryLog.Unlock()
+	deliveryLog.records = append(deliveryLog.records, record)
+	if len(deliveryLog.records) > deliveryLogSize {
+		deliveryLog.records = deliveryLog.records[len(deliveryLog.records)-deliveryLogSize:]
+	}
+}
+
+// Dispatch sends the event to every enabled channel subscribed to its type and, for application events, to
+// its project
+func Dispatch(ctx context.Context, event Event) {
+	channels, err := listChannels(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list notification channels", "event", event.Type)
+		return
+	}
+	for _, channel := range channels {
+		if !channel.Enabled || !subscribed(channel, event.Type) || !subscribedProject(channel, event) {
+			continue
+		}
+		deliver(ctx, channel, event, nil)
+	}
+}
+
+// SendToChannel delivers the event and an optional attachment through the named channel, whatever events it
+// subscribes to. Email channels attach the file, webhooks receive it base64 encoded and Slack only the event.
+func SendToChannel(ctx context.Context, name string, event Event, attachment *Attachment) error {
+	_, channel, err := getChannelSecret(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !channel.Enabled {
+		return fmt.Errorf("notification channel %s is disabled", name)
+	}
+	if record := deliver(ctx, channel, event, attachment); !record.Success {
+		return fmt.Errorf("delivery through channel %s failed: %s", name, record.Error)
+	}
+	return nil
+}
+
+func subscribed(channel *Channel, eventType string) bool {
+	for _, e := range channel.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribedProject reports whether the channel receives the events of the ArgoCD project of the event.
+// Events without a project are not filtered.
+func subscribedProject(channel *Channel, event Event) bool {
+	project, ok := event.Labels[EventLabelProject]
+	if !ok || len(channel.Projects) == 0 {
+		return true
+	}
+	for _, p := range channel.Projects {
+		if p == project {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver sends the event through the channel, retrying with exponential backoff
+func deliver(ctx context.Context, channel *Channel, event Event, attachment *Attachment) DeliveryRecord {
+	record := DeliveryRecord{
+		Channel:     channel.Name,
+		ChannelType: channel.Type,
+		Event:       event,
+	}
+
+	backoff := initialRetryBackoff
+	var err error
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		record.Attempts = attempt
+		if err = send(ctx, channel, event, attachment); err == nil {
+			break
+		}
+		klog.V(2).InfoS("Notification delivery failed", "channel", channel.Name, "event", event.Type, "attempt", attempt, "error", err)
+		if attempt == maxDeliveryAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			attempt = maxDeliveryAttempts
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+
+	record.Success = err == nil
+	if err != nil {
+		record.Error = err.Error()
+		klog.ErrorS(err, "Failed to deliver notification", "channel", channel.Name, "event", event.Type)
+	}
+	record.DeliveredAt = timeNow()
+	recordDelivery(record)
+	return record
+}
+
+func send(ctx context.Context, channel *Channel, event Event, attachment *Attachment) error {
+	switch channel.Type {
+	case ChannelTypeWebhook:
+		body, err := json.Marshal(struct {
+			Event
+			Attachment *Attachment `json:"attachment,omitempty"`
+		}{event, attachment})
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, channel.Config.URL, channel.Config.Headers, body)
+	case ChannelTypeSlack:
+		body, err := json.Marshal(map[string]string{
+			"text": fmt.Sprintf("*%s*\n%s", event.Title, event.Message),
+		})
+		if err != nil {
+			return err
+		}
+		return postJSON(ctx, channel.Config.URL, nil, body)
+	case ChannelTypeEmail:
+		return sendEmail(channel.Config, event, attachment)
+	default:
+		return fmt.Errorf("unsupported channel type: %s", channel.Type)
+	}
+}
+
+func postJSON(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func sendEmail(cfg ChannelConfig, event Event, attachment *Attachment) error {
+	addr := net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort))
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.SMTPHost)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [ML Platform] %s\r\n", event.Title)
+	if attachment == nil {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(event.Message)
+		msg.WriteString("\r\n")
+		return smtp.SendMail(addr, auth, cfg.From, cfg.To, []byte(msg.String()))
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%s\r\n\r\n", parts.Boundary())
+	text, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=UTF-8"}})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(text, "%s\r\n", event.Message)
+	file, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {attachment.ContentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf(`attachment; filename="%s"`, attachment.Filename)},
+	})
+	if err != nil {
+		return err
+	}
+	// Base64 lines must not exceed 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		fmt.Fprintf(file, "%s\r\n", encoded[:76])
+		encoded = encoded[76:]
+	}
+	fmt.Fprintf(file, "%s\r\n", encoded)
+	if err := parts.Close(); err != nil {
+		return err
+	}
+	msg.Write(body.Bytes())
+
+	return smtp.SendMail(addr, auth, cfg.From, cfg.To, []byte(msg.String()))
+}
+
+// handleGetDeliveries returns the delivery log, newest first.
+// Supports filtering by channel and by failed deliveries.
+func handleGetDeliveries(c *gin.Context) {
+	channelFilter := c.Query("channel")
+	failedOnly := c.Query("failed") == "true"
+
+	deliveryLog.Lock()
+	records := make([]DeliveryRecord, 0, len(deliveryLog.records))
+	for i := len(deliveryLog.records) - 1; i >= 0; i-- {
+		record := deliveryLog.records[i]
+		if channelFilter != "" && record.Channel != channelFilter {
+			continue
+		}
+		if failedOnly && record.Success {
+			continue
+		}
+		records = append(records, record)
+	}
+	deliveryLog.Unlock()
+
+	common.Success(c, gin.H{
+		"deliveries": records,
+		"totalItems": len(records),
+	})
+}
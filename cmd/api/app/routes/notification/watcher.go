@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+)
+
+const migrationNamespace = "stateful-migration"
+
+var (
+	checkpointBackupGVR = schema.GroupVersionResource{
+		Group:    "migration.dcnlab.com",
+		Version:  "v1",
+		Resource: "checkpointbackups",
+	}
+	checkpointRestoreGVR = schema.GroupVersionResource{
+		Group:    "migration.dcnlab.com",
+		Version:  "v1",
+		Resource: "checkpointrestores",
+	}
+	daemonSetGVR = schema.GroupVersionResource{
+		Group:    "apps",
+		Version:  "v1",
+		Resource: "daemonsets",
+	}
+)
+
+// watcher polls clusters and migration CRs and dispatches events on state changes
+type watcher struct {
+	// seen holds the last observed state per object, so events fire once per transition
+	seen map[string]string
+	// primed is false until the first poll, which only records the current state
+	primed bool
+	// notified holds when an event was last dispatched per application
+	notified map[string]time.Time
+	// suppressionWindow is the minimum time between two events of the same application
+	suppressionWindow time.Duration
+}
+
+// StartWatcher starts the notification watcher, polling at the given interval until ctx is done.
+// A non-positive interval disables the watcher. Events of the same ArgoCD application are dispatched at most
+// once per suppression window.
+func StartWatcher(ctx context.Context, interval, suppressionWindow time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Notification watcher is disabled")
+		return
+	}
+	w := &watcher{
+		seen:              make(map[string]string),
+		notified:          make(map[string]time.Time),
+		suppressionWindow: suppressionWindow,
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			w.poll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	klog.InfoS("Notification watcher started", "interval", interval, "suppressionWindow", suppressionWindow)
+}
+
+// transition records the state of key and reports whether it changed to state since the last poll
+func (w *watcher) transition(key, state string) bool {
+	previous, ok := w.seen[key]
+	w.seen[key] = state
+	return w.primed && (!ok || previous != state)
+}
+
+func (w *watcher) emit(ctx context.Context, key, state string, event Event) {
+	if w.transition(key, state) {
+		event.Timestamp = timeNow()
+		Dispatch(ctx, event)
+	}
+}
+
+func (w *watcher) poll(ctx context.Context) {
+	karmadaClient := client.InClusterKarmadaClient()
+	clusters, err := karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Notification watcher failed to list clusters")
+		return
+	}
+
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		ready := isClusterReady(cluster)
+		state := "ready"
+		if !ready {
+			state = "notready"
+		}
+		if state == "notready" {
+			w.emit(ctx, "cluster/"+cluster.Name, state, Event{
+				Type:    EventClusterNotReady,
+				Title:   fmt.Sprintf("Cluster %s is not ready", cluster.Name),
+				Message: fmt.Sprintf("Cluster %s reported a NotReady condition", cluster.Name),
+				Cluster: cluster.Name,
+			})
+		} else {
+			w.transition("cluster/"+cluster.Name, state)
+		}
+		if !ready {
+			continue
+		}
+
+		// An empty context carries no user, so the watcher uses the dashboard's own member access
+		dynamicClient, err := client.GetDynamicClientForMember(&gin.Context{}, cluster.Name)
+		if err != nil {
+			klog.V(4).InfoS("Notification watcher failed to create member client", "cluster", cluster.Name, "error", err)
+			continue
+		}
+		w.pollController(ctx, dynamicClient, cluster.Name)
+		w.pollCheckpoints(ctx, dynamicClient, cluster.Name)
+		if argocd.Installed(ctx, cluster.Name) {
+			w.pollApplications(ctx, dynamicClient, cluster.Name)
+		}
+	}
+	w.pollMgmtApplications(ctx)
+	w.forgetNotified(time.Now())
+	w.primed = true
+}
+
+func isClusterReady(cluster *clusterv1alpha1.Cluster) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == clusterv1alpha1.ClusterConditionReady {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// pollController checks the checkpoint backup controller DaemonSet of a member cluster
+func (w *watcher) pollController(ctx context.Context, dynamicClient dynamic.Interface, clusterName string) {
+	ds, err := dynamicClient.Resource(daemonSetGVR).Namespace(migrationNamespace).Get(ctx,
+		fmt.Sprintf("checkpoint-backup-controller-%s", clusterName), metav1.GetOptions{})
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		ds, err = dynamicClient.Resource(daemonSetGVR).Namespace(migrationNamespace).Get(ctx, "checkpoint-backup-controller", metav1.GetOptions{})
+	}
+	if err != nil {
+		// The controller is not installed on this cluster
+		return
+	}
+
+	desired, _, _ := unstructured.NestedInt64(ds.Object, "status", "desiredNumberScheduled")
+	ready, _, _ := unstructured.NestedInt64(ds.Object, "status", "numberReady")
+	key := "controller/" + clusterName
+	if ready < desired || desired == 0 {
+		w.emit(ctx, key, "unhealthy", Event{
+			Type:     EventControllerUnhealthy,
+			Title:    fmt.Sprintf("Checkpoint controller unhealthy on %s", clusterName),
+			Message:  fmt.Sprintf("DaemonSet %s has %d of %d pods ready", ds.GetName(), ready, desired),
+			Cluster:  clusterName,
+			Resource: ds.GetName(),
+		})
+		return
+	}
+	w.transition(key, "healthy")
+}
+
+// pollCheckpoints checks CheckpointBackup and CheckpointRestore CRs of a member cluster
+func (w *watcher) pollCheckpoints(ctx context.Context, dynamicClient dynamic.Interface, clusterName string) {
+	if backups, err := dynamicClient.Resource(checkpointBackupGVR).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range backups.Items {
+			cb := &backups.Items[i]
+			phase := strings.ToLower(objectPhase(cb))
+			key := fmt.Sprintf("backup/%s/%s/%s", clusterName, cb.GetNamespace(), cb.GetName())
+			if phase != "failed" && phase != "error" {
+				w.transition(key, phase)
+				continue
+			}
+			message, _, _ := unstructured.NestedString(cb.Object, "status", "message")
+			w.emit(ctx, key, phase, Event{
+				Type:     EventBackupFailed,
+				Title:    fmt.Sprintf("Backup %s failed", cb.GetName()),
+				Message:  fmt.Sprintf("CheckpointBackup %s/%s on cluster %s failed: %s", cb.GetNamespace(), cb.GetName(), clusterName, message),
+				Cluster:  clusterName,
+				Resource: fmt.Sprintf("%s/%s", cb.GetNamespace(), cb.GetName()),
+			})
+		}
+	}
+
+	if restores, err := dynamicClient.Resource(checkpointRestoreGVR).List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range restores.Items {
+			cr := &restores.Items[i]
+			phase := strings.ToLower(objectPhase(cr))
+			key := fmt.Sprintf("restore/%s/%s/%s", clusterName, cr.GetNamespace(), cr.GetName())
+			if phase != "completed" && phase != "succeeded" {
+				w.transition(key, phase)
+				continue
+			}
+			w.emit(ctx, key, phase, Event{
+				Type:     EventRecoveryCompleted,
+				Title:    fmt.Sprintf("Recovery %s completed", cr.GetName()),
+				Message:  fmt.Sprintf("CheckpointRestore %s/%s on cluster %s completed", cr.GetNamespace(), cr.GetName(), clusterName),
+				Cluster:  clusterName,
+				Resource: fmt.Sprintf("%s/%s", cr.GetNamespace(), cr.GetName()),
+			})
+		}
+	}
+}
+
+func objectPhase(obj *unstructured.Unstructured) string {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	return phase
+}
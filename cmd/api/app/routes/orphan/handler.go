@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// collect finds the orphaned dashboard resources in Karmada and deletes them unless dryRun
+func collect(ctx context.Context, dryRun bool) (*orphan.Report, error) {
+	karmadaConfig, _, err := client.GetKarmadaConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get karmada config: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(karmadaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create karmada dynamic client: %w", err)
+	}
+	return orphan.Collect(ctx, client.InClusterKarmadaClient(), dynamicClient, dryRun)
+}
+
+// handleGetOrphans lists the dashboard resources whose target clusters no longer exist without deleting them
+func handleGetOrphans(c *gin.Context) {
+	report, err := collect(c, true)
+	if err != nil {
+		klog.ErrorS(err, "Failed to find orphaned resources")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, report)
+}
+
+// handlePostOrphansCollect deletes the orphaned dashboard resources and prunes the missing clusters
+// from the policies that still target existing ones
+func handlePostOrphansCollect(c *gin.Context) {
+	klog.InfoS("Collecting orphaned resources", "user", utilauth.GetAuthenticatedUser(c))
+	report, err := collect(c, false)
+	if err != nil {
+		klog.ErrorS(err, "Failed to collect orphaned resources")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, report)
+}
+
+// StartCollector looks for orphaned dashboard resources every interval. They are deleted when
+// deleteOrphans is set and only logged otherwise. A non-positive interval disables the collector.
+func StartCollector(ctx context.Context, interval time.Duration, deleteOrphans bool) {
+	if interval <= 0 {
+		klog.InfoS("Orphaned resource collector is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report, err := collect(ctx, !deleteOrphans)
+				if err != nil {
+					klog.ErrorS(err, "Orphaned resource collection failed")
+					continue
+				}
+				if len(report.Items) > 0 {
+					klog.InfoS("Orphaned resource collection finished", "found", len(report.Items),
+						"deleted", report.Deleted, "pruned", report.Pruned, "failed", report.Failed, "dryRun", report.DryRun)
+				}
+			}
+		}
+	}()
+	klog.InfoS("Orphaned resource collector started", "interval", interval, "delete", deleteOrphans)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/karmada/orphans", router.EnsureMgmtAdminMiddleware(), handleGetOrphans)
+	r.POST("/karmada/orphans/collect", router.EnsureMgmtAdminMiddleware(), handlePostOrphansCollect)
+}
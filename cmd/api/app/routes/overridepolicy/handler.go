@@ -64,7 +64,7 @@ func handlePostOverridePolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	overridepolicyRequest := new(v1.PostOverridePolicyRequest)
 	if err := c.ShouldBind(&overridepolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	if overridepolicyRequest.Namespace == "" {
@@ -101,7 +101,7 @@ func handlePutOverridePolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	overridepolicyRequest := new(v1.PutOverridePolicyRequest)
 	if err := c.ShouldBind(&overridepolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	var err error
@@ -142,7 +142,7 @@ func handleDeleteOverridePolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	overridepolicyRequest := new(v1.DeleteOverridePolicyRequest)
 	if err := c.ShouldBind(&overridepolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	var err error
@@ -96,7 +96,7 @@ type DashboardConfig struct {
 func handleSaveDashboard(c *gin.Context) {
 	var dashboardConfig DashboardConfig
 	if err := c.ShouldBindJSON(&dashboardConfig); err != nil {
-		common.Fail(c, fmt.Errorf("invalid request body: %w", err))
+		common.FailWithBindError(c, err)
 		return
 	}
 
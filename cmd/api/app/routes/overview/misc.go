@@ -29,6 +29,7 @@ import (
 	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
 	"github.com/karmada-io/dashboard/pkg/client"
 	"github.com/karmada-io/dashboard/pkg/dataselect"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
 	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
 	"github.com/karmada-io/karmada/pkg/version"
@@ -328,8 +329,8 @@ func GetArgoMetrics(c *gin.Context) (*v1.ArgoMetrics, error) {
 	var applicationCount, projectCount int
 
 	for _, cluster := range clusterList.Items {
-		// Skip clusters that aren't ready
-		if !isClusterReady(&cluster) {
+		// Skip clusters that aren't ready or don't run ArgoCD
+		if !isClusterReady(&cluster) || !argocd.Installed(ctx, cluster.ObjectMeta.Name) {
 			continue
 		}
 
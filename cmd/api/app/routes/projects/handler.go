@@ -0,0 +1,276 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projects
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/harbor"
+	"github.com/karmada-io/dashboard/pkg/resource/project"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// ProjectRequest is the request body for creating or replacing a project
+type ProjectRequest struct {
+	Name         string                `json:"name"`
+	DisplayName  string                `json:"displayName"`
+	Description  string                `json:"description"`
+	Clusters     []string              `json:"clusters"`
+	Namespaces   []project.Namespace   `json:"namespaces"`
+	Members      []project.Member      `json:"members"`
+	Backups      []string              `json:"backups"`
+	Applications []project.Application `json:"applications"`
+	// ProvisionRegistry queues the provisioning of a Harbor project with pull secrets in the namespaces of
+	// the project when it is created
+	ProvisionRegistry bool `json:"provisionRegistry,omitempty"`
+}
+
+func (r ProjectRequest) toProject() *project.Project {
+	return &project.Project{
+		Name:         r.Name,
+		DisplayName:  r.DisplayName,
+		Description:  r.Description,
+		Clusters:     r.Clusters,
+		Namespaces:   r.Namespaces,
+		Members:      r.Members,
+		Backups:      r.Backups,
+		Applications: r.Applications,
+	}
+}
+
+// FromQuery returns the project named by the project query parameter, or nil when it is not set.
+// List endpoints use it to only return what belongs to the project. Users who are neither admins
+// nor members of the project get an error.
+func FromQuery(c *gin.Context) (*project.Project, error) {
+	name := c.Query("project")
+	if name == "" {
+		return nil, nil
+	}
+	p, err := project.Get(c, client.InClusterClient(), name)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := project.CanView(c, utilauth.GetAuthenticatedUser(c), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check project permissions: %v", err)
+	}
+	if !allowed {
+		return nil, pkgerrors.NewForbidden(name, fmt.Errorf("not a member of project %s", name))
+	}
+	return p, nil
+}
+
+// validateClusters checks that the clusters of a project are member clusters
+func validateClusters(c *gin.Context, p *project.Project) error {
+	karmadaClient := client.InClusterKarmadaClient()
+	for _, clusterName := range p.ClusterNames() {
+		if _, err := karmadaClient.ClusterV1alpha1().Clusters().Get(c, clusterName, metav1.GetOptions{}); err != nil {
+			return pkgerrors.NewBadRequest(fmt.Sprintf("cluster %s: %v", clusterName, err))
+		}
+	}
+	return nil
+}
+
+// handleGetProjects returns the projects the user is a member of, or all projects for admins
+func handleGetProjects(c *gin.Context) {
+	projects, err := project.List(c, client.InClusterClient())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list projects")
+		common.Fail(c, err)
+		return
+	}
+	username := utilauth.GetAuthenticatedUser(c)
+	result := make([]*project.Project, 0, len(projects))
+	for _, p := range projects {
+		allowed, err := project.CanView(c, username, p.Name)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check project permissions", "username", username, "project", p.Name)
+			continue
+		}
+		if allowed {
+			result = append(result, p)
+		}
+	}
+	common.Success(c, gin.H{
+		"projects":   result,
+		"totalItems": len(result),
+	})
+}
+
+// handleGetProject returns a single project
+func handleGetProject(c *gin.Context) {
+	name := c.Param("name")
+	p, err := project.Get(c, client.InClusterClient(), name)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	allowed, err := project.CanView(c, utilauth.GetAuthenticatedUser(c), name)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if !allowed {
+		common.FailWithStatus(c, fmt.Errorf("forbidden: not a member of project %s", name), 403)
+		return
+	}
+	common.Success(c, p)
+}
+
+// handleCreateProject creates a project and grants its members their roles
+func handleCreateProject(c *gin.Context) {
+	var req ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	p := req.toProject()
+	if err := project.Validate(p); err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+	if err := validateClusters(c, p); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	if err := project.Create(c, client.InClusterClient(), p); err != nil {
+		klog.ErrorS(err, "Failed to create project", "project", p.Name)
+		common.Fail(c, err)
+		return
+	}
+	if err := project.SyncTuples(c, nil, p); err != nil {
+		klog.ErrorS(err, "Failed to grant project roles", "project", p.Name)
+		common.Fail(c, fmt.Errorf("project created, but %v", err))
+		return
+	}
+	if req.ProvisionRegistry {
+		params := harbor.ProvisionParams{OwnerKind: harbor.OwnerProject, Owner: p.Name}
+		job, err := harbor.EnqueueProvisioning(c, params, utilauth.GetAuthenticatedUser(c))
+		if err != nil {
+			klog.ErrorS(err, "Failed to enqueue registry provisioning", "project", p.Name)
+			common.Fail(c, fmt.Errorf("project created, but failed to provision its registry: %v", err))
+			return
+		}
+		klog.InfoS("Enqueued registry provisioning", "project", p.Name, "job", job.ID)
+	}
+	klog.InfoS("Created project", "project", p.Name, "user", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, p)
+}
+
+// handleUpdateProject replaces a project. Project owners may change its members, backups and
+// applications; changing the clusters and namespaces, which grants access to them, needs an admin.
+func handleUpdateProject(c *gin.Context) {
+	name := c.Param("name")
+	var req ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	req.Name = name
+
+	current, err := project.Get(c, client.InClusterClient(), name)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	username := utilauth.GetAuthenticatedUser(c)
+	allowed, err := project.CanManage(c, username, name)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if !allowed {
+		common.FailWithStatus(c, fmt.Errorf("forbidden: only owners of project %s can change it", name), 403)
+		return
+	}
+
+	p := req.toProject()
+	if err := project.Validate(p); err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+	if !reflect.DeepEqual(p.Clusters, current.Clusters) || !reflect.DeepEqual(p.Namespaces, current.Namespaces) {
+		isAdmin, err := project.IsAdmin(c, username)
+		if err != nil {
+			common.Fail(c, err)
+			return
+		}
+		if !isAdmin {
+			common.FailWithStatus(c, fmt.Errorf("forbidden: only admins can change the clusters and namespaces of a project"), 403)
+			return
+		}
+		if err := validateClusters(c, p); err != nil {
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	if err := project.Update(c, client.InClusterClient(), p); err != nil {
+		klog.ErrorS(err, "Failed to update project", "project", name)
+		common.Fail(c, err)
+		return
+	}
+	if err := project.SyncTuples(c, current, p); err != nil {
+		klog.ErrorS(err, "Failed to update project roles", "project", name)
+		common.Fail(c, fmt.Errorf("project updated, but %v", err))
+		return
+	}
+	p.CreatedAt = current.CreatedAt
+	common.Success(c, p)
+}
+
+// handleDeleteProject deletes a project and revokes the roles it granted.
+// The clusters, backups and applications of the project are kept.
+func handleDeleteProject(c *gin.Context) {
+	name := c.Param("name")
+	current, err := project.Get(c, client.InClusterClient(), name)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := project.SyncTuples(c, current, nil); err != nil {
+		klog.ErrorS(err, "Failed to revoke project roles", "project", name)
+		common.Fail(c, err)
+		return
+	}
+	if err := project.Delete(c, client.InClusterClient(), name); err != nil {
+		klog.ErrorS(err, "Failed to delete project", "project", name)
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Deleted project", "project", name, "user", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, gin.H{"message": "Project deleted successfully"})
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/projects", handleGetProjects)
+	r.GET("/projects/:name", handleGetProject)
+	r.POST("/projects", router.EnsureMgmtAdminMiddleware(), handleCreateProject)
+	r.PUT("/projects/:name", handleUpdateProject)
+	r.DELETE("/projects/:name", router.EnsureMgmtAdminMiddleware(), handleDeleteProject)
+}
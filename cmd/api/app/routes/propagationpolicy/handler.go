@@ -64,7 +64,7 @@ func handlePostPropagationPolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	propagationpolicyRequest := new(v1.PostPropagationPolicyRequest)
 	if err := c.ShouldBind(&propagationpolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	if propagationpolicyRequest.Namespace == "" {
@@ -101,7 +101,7 @@ func handlePutPropagationPolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	propagationpolicyRequest := new(v1.PutPropagationPolicyRequest)
 	if err := c.ShouldBind(&propagationpolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	var err error
@@ -142,7 +142,7 @@ func handleDeletePropagationPolicy(c *gin.Context) {
 	ctx := context.Context(c)
 	propagationpolicyRequest := new(v1.DeletePropagationPolicyRequest)
 	if err := c.ShouldBind(&propagationpolicyRequest); err != nil {
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 	var err error
@@ -0,0 +1,479 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// Report types that can be generated on demand or on a schedule
+const (
+	ReportTypeClusterInventory  = "cluster-inventory"
+	ReportTypeBackupSuccessRate = "backup-success-rate"
+	ReportTypeUserActivity      = "user-activity"
+	ReportTypeUsage             = "usage"
+
+	formatJSON = "json"
+	formatCSV  = "csv"
+
+	// maxUserEvents bounds the Keycloak events read for a user activity report
+	maxUserEvents = 10000
+)
+
+var checkpointBackupGVR = schema.GroupVersionResource{Group: "migration.dcnlab.com", Version: "v1", Resource: "checkpointbackups"}
+
+// Table is a generated report. Every report is a table, so it renders the same way as JSON and CSV.
+type Table struct {
+	Type        string            `json:"type"`
+	Title       string            `json:"title"`
+	Start       string            `json:"start,omitempty"`
+	End         string            `json:"end,omitempty"`
+	GeneratedAt string            `json:"generatedAt"`
+	Columns     []string          `json:"columns"`
+	Rows        [][]string        `json:"rows"`
+	Summary     map[string]string `json:"summary"`
+	// Errors lists the clusters or sources that could not be included
+	Errors []string `json:"errors"`
+}
+
+// generator builds a report over the window, with type specific parameters such as cluster
+type generator func(ctx context.Context, start, end time.Time, params map[string]string) (*Table, error)
+
+var generators = map[string]generator{
+	ReportTypeClusterInventory:  clusterInventoryReport,
+	ReportTypeBackupSuccessRate: backupSuccessRateReport,
+	ReportTypeUserActivity:      userActivityReport,
+	ReportTypeUsage:             usageTableReport,
+}
+
+// reportTypes returns the supported report types in a stable order
+func reportTypes() []string {
+	types := make([]string, 0, len(generators))
+	for t := range generators {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func validateReportType(reportType string) error {
+	if _, ok := generators[reportType]; !ok {
+		return fmt.Errorf("unsupported report type %q, expected one of %s", reportType, strings.Join(reportTypes(), ", "))
+	}
+	return nil
+}
+
+func validateReportFormat(format string) error {
+	if format != formatJSON && format != formatCSV {
+		return fmt.Errorf("unsupported report format %q, expected %s or %s", format, formatJSON, formatCSV)
+	}
+	return nil
+}
+
+// generateReport builds a report of the given type
+func generateReport(ctx context.Context, reportType string, start, end time.Time, params map[string]string) (*Table, error) {
+	if err := validateReportType(reportType); err != nil {
+		return nil, err
+	}
+	table, err := generators[reportType](ctx, start, end, params)
+	if err != nil {
+		return nil, err
+	}
+	table.Type = reportType
+	table.GeneratedAt = time.Now().Format(time.RFC3339)
+	if table.Summary == nil {
+		table.Summary = map[string]string{}
+	}
+	if table.Errors == nil {
+		table.Errors = []string{}
+	}
+	return table, nil
+}
+
+// render encodes the report and returns its content type and file extension
+func (t *Table) render(format string) ([]byte, string, string, error) {
+	if format == formatCSV {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write(t.Columns)
+		_ = writer.WriteAll(t.Rows)
+		if err := writer.Error(); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "text/csv", "csv", nil
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, "", "", err
+	}
+	return data, "application/json", "json", nil
+}
+
+// summaryText lists the summary of the report as lines of text, e.g. for the body of an email
+func (t *Table) summaryText() string {
+	keys := make([]string, 0, len(t.Summary))
+	for key := range t.Summary {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n", t.Title)
+	if t.Start != "" {
+		fmt.Fprintf(&text, "Period: %s - %s\n", t.Start, t.End)
+	}
+	for _, key := range keys {
+		fmt.Fprintf(&text, "%s: %s\n", key, t.Summary[key])
+	}
+	for _, e := range t.Errors {
+		fmt.Fprintf(&text, "Not included: %s\n", e)
+	}
+	return text.String()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
+
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	if quantity, ok := list[name]; ok {
+		return quantity.String()
+	}
+	return ""
+}
+
+// clusterInventoryReport lists the member clusters with their version, nodes and resources
+func clusterInventoryReport(ctx context.Context, _, _ time.Time, _ map[string]string) (*Table, error) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %v", err)
+	}
+	sort.Slice(clusters.Items, func(i, j int) bool {
+		return clusters.Items[i].Name < clusters.Items[j].Name
+	})
+
+	table := &Table{
+		Title: "Cluster inventory",
+		Columns: []string{"cluster", "ready", "kubernetes_version", "sync_mode", "provider", "region",
+			"nodes_ready", "nodes_total", "cpu_allocatable", "cpu_allocated", "memory_allocatable", "memory_allocated", "gpu_allocatable"},
+		Rows: [][]string{},
+	}
+	ready, nodes := 0, int32(0)
+	for _, cluster := range clusters.Items {
+		isReady := meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1alpha1.ClusterConditionReady)
+		if isReady {
+			ready++
+		}
+		var nodesReady, nodesTotal int32
+		if cluster.Status.NodeSummary != nil {
+			nodesReady, nodesTotal = cluster.Status.NodeSummary.ReadyNum, cluster.Status.NodeSummary.TotalNum
+		}
+		nodes += nodesTotal
+		var allocatable, allocated corev1.ResourceList
+		if cluster.Status.ResourceSummary != nil {
+			allocatable, allocated = cluster.Status.ResourceSummary.Allocatable, cluster.Status.ResourceSummary.Allocated
+		}
+		table.Rows = append(table.Rows, []string{
+			cluster.Name, strconv.FormatBool(isReady), cluster.Status.KubernetesVersion, string(cluster.Spec.SyncMode),
+			cluster.Spec.Provider, cluster.Spec.Region,
+			strconv.Itoa(int(nodesReady)), strconv.Itoa(int(nodesTotal)),
+			quantityString(allocatable, corev1.ResourceCPU), quantityString(allocated, corev1.ResourceCPU),
+			quantityString(allocatable, corev1.ResourceMemory), quantityString(allocated, corev1.ResourceMemory),
+			quantityString(allocatable, gpuResourceName),
+		})
+	}
+	table.Summary = map[string]string{
+		"clusters":       strconv.Itoa(len(clusters.Items)),
+		"ready clusters": strconv.Itoa(ready),
+		"nodes":          strconv.Itoa(int(nodes)),
+	}
+	return table, nil
+}
+
+// backupOutcome classifies the phase of a CheckpointBackup
+func backupOutcome(phase string) string {
+	switch strings.ToLower(phase) {
+	case "completed", "succeeded":
+		return "succeeded"
+	case "failed", "error":
+		return "failed"
+	default:
+		return "in-progress"
+	}
+}
+
+// backupSuccessRateReport counts the CheckpointBackups created in the window per cluster by outcome
+func backupSuccessRateReport(ctx context.Context, start, end time.Time, params map[string]string) (*Table, error) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %v", err)
+	}
+	sort.Slice(clusters.Items, func(i, j int) bool {
+		return clusters.Items[i].Name < clusters.Items[j].Name
+	})
+
+	table := &Table{
+		Title:   "Backup success rate",
+		Start:   start.Format(time.RFC3339),
+		End:     end.Format(time.RFC3339),
+		Columns: []string{"cluster", "total", "succeeded", "failed", "in_progress", "success_rate"},
+		Rows:    [][]string{},
+		Errors:  []string{},
+	}
+	var total, succeeded, failed int
+	for _, cluster := range clusters.Items {
+		if params["cluster"] != "" && cluster.Name != params["cluster"] {
+			continue
+		}
+		dynamicClient, err := client.DynamicClientForMemberCluster(cluster.Name)
+		if err != nil {
+			table.Errors = append(table.Errors, fmt.Sprintf("%s: %v", cluster.Name, err))
+			continue
+		}
+		backups, err := dynamicClient.Resource(checkpointBackupGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.V(4).InfoS("Failed to list CheckpointBackups", "cluster", cluster.Name, "error", err)
+			table.Errors = append(table.Errors, fmt.Sprintf("%s: %v", cluster.Name, err))
+			continue
+		}
+
+		counts := map[string]int{}
+		for _, cb := range backups.Items {
+			created := cb.GetCreationTimestamp().Time
+			if created.Before(start) || created.After(end) {
+				continue
+			}
+			phase, _, _ := unstructured.NestedString(cb.Object, "status", "phase")
+			counts[backupOutcome(phase)]++
+		}
+		clusterTotal := counts["succeeded"] + counts["failed"] + counts["in-progress"]
+		total += clusterTotal
+		succeeded += counts["succeeded"]
+		failed += counts["failed"]
+		table.Rows = append(table.Rows, []string{
+			cluster.Name, strconv.Itoa(clusterTotal), strconv.Itoa(counts["succeeded"]), strconv.Itoa(counts["failed"]),
+			strconv.Itoa(counts["in-progress"]), successRate(counts["succeeded"], counts["failed"]),
+		})
+	}
+	table.Summary = map[string]string{
+		"backups":      strconv.Itoa(total),
+		"succeeded":    strconv.Itoa(succeeded),
+		"failed":       strconv.Itoa(failed),
+		"success rate": successRate(succeeded, failed),
+	}
+	return table, nil
+}
+
+// successRate is the percentage of finished backups that succeeded, empty when none finished
+func successRate(succeeded, failed int) string {
+	if succeeded+failed == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(succeeded)*100/float64(succeeded+failed), 'f', 1, 64)
+}
+
+// userActivityReport summarizes the logins of each user recorded by Keycloak in the window
+func userActivityReport(ctx context.Context, start, end time.Time, _ map[string]string) (*Table, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		return nil, fmt.Errorf("keycloak is not configured")
+	}
+	adminToken, err := kc.GetAdminToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if adminToken == "" {
+		return nil, fmt.Errorf("the user activity report requires KEYCLOAK_CLIENT_SECRET and a service account with the view-events role")
+	}
+	events, err := kc.GetUserEvents(ctx, adminToken, start, end, []string{"LOGIN", "LOGIN_ERROR"}, maxUserEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	type activity struct {
+		username, userID string
+		logins, failures int
+		lastLogin        string
+		ipAddresses      map[string]bool
+	}
+	byUser := map[string]*activity{}
+	for _, event := range events {
+		key := event.UserID
+		if key == "" {
+			key = "username:" + event.Username
+		}
+		a, ok := byUser[key]
+		if !ok {
+			a = &activity{userID: event.UserID, ipAddresses: map[string]bool{}}
+			byUser[key] = a
+		}
+		if event.Username != "" {
+			a.username = event.Username
+		}
+		if event.Type == "LOGIN_ERROR" {
+			a.failures++
+			continue
+		}
+		a.logins++
+		a.ipAddresses[event.IPAddress] = true
+		// Times are RFC3339 in the same zone, so they compare as strings
+		if event.Time > a.lastLogin {
+			a.lastLogin = event.Time
+		}
+	}
+
+	table := &Table{
+		Title:   "User activity",
+		Start:   start.Format(time.RFC3339),
+		End:     end.Format(time.RFC3339),
+		Columns: []string{"username", "user_id", "logins", "failed_logins", "last_login", "ip_addresses"},
+		Rows:    [][]string{},
+	}
+	logins, failures, active := 0, 0, 0
+	for _, a := range byUser {
+		ips := make([]string, 0, len(a.ipAddresses))
+		for ip := range a.ipAddresses {
+			ips = append(ips, ip)
+		}
+		sort.Strings(ips)
+		table.Rows = append(table.Rows, []string{
+			a.username, a.userID, strconv.Itoa(a.logins), strconv.Itoa(a.failures), a.lastLogin, strings.Join(ips, " "),
+		})
+		logins += a.logins
+		failures += a.failures
+		if a.logins > 0 {
+			active++
+		}
+	}
+	sort.Slice(table.Rows, func(i, j int) bool {
+		return table.Rows[i][0] < table.Rows[j][0]
+	})
+	table.Summary = map[string]string{
+		"active users":  strconv.Itoa(active),
+		"logins":        strconv.Itoa(logins),
+		"failed logins": strconv.Itoa(failures),
+	}
+	if len(events) == maxUserEvents {
+		table.Errors = []string{fmt.Sprintf("only the first %d events were included", maxUserEvents)}
+	}
+	return table, nil
+}
+
+// usageTableReport is the usage report of the Kubeflow Profile namespaces as a table
+func usageTableReport(ctx context.Context, start, end time.Time, params map[string]string) (*Table, error) {
+	report, err := buildUsageReport(ctx, start, end, params["source"], params["cluster"], params["namespace"])
+	if err != nil {
+		return nil, err
+	}
+	table := &Table{
+		Title:   "Resource usage by profile",
+		Start:   report.Start,
+		End:     report.End,
+		Columns: []string{"cluster", "namespace", "profile", "owner", "cpu_core_hours", "memory_gib_hours", "gpu_hours", "source", "estimated"},
+		Rows:    [][]string{},
+		Errors:  report.Errors,
+	}
+	var cpu, memory, gpu float64
+	for _, row := range report.Rows {
+		table.Rows = append(table.Rows, []string{
+			row.Cluster, row.Namespace, row.Profile, row.Owner,
+			formatFloat(row.CPUCoreHours), formatFloat(row.MemoryGiBHours), formatFloat(row.GPUHours),
+			row.Source, strconv.FormatBool(row.Estimated),
+		})
+		cpu += row.CPUCoreHours
+		memory += row.MemoryGiBHours
+		gpu += row.GPUHours
+	}
+	table.Summary = map[string]string{
+		"namespaces":       strconv.Itoa(len(report.Totals)),
+		"cpu core hours":   formatFloat(cpu),
+		"memory GiB hours": formatFloat(memory),
+		"gpu hours":        formatFloat(gpu),
+	}
+	return table, nil
+}
+
+// reportParams returns the type specific parameters given as query parameters
+func reportParams(c *gin.Context) map[string]string {
+	params := map[string]string{}
+	for _, key := range []string{"cluster", "namespace", "source"} {
+		if value := c.Query(key); value != "" {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+// handleGenerateReport generates a report on demand. Query parameters: type, start and end (RFC3339,
+// default the last 7 days), format (json or csv) and the type specific cluster, namespace and source.
+func handleGenerateReport(c *gin.Context) {
+	reportType := c.Query("type")
+	if err := validateReportType(reportType); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	format := c.DefaultQuery("format", formatJSON)
+	if err := validateReportFormat(format); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	start, end, err := parseReportWindow(c)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	table, err := generateReport(c, reportType, start, end, reportParams(c))
+	if err != nil {
+		klog.ErrorS(err, "Failed to generate report", "type", reportType)
+		common.Fail(c, err)
+		return
+	}
+	if format == formatJSON {
+		common.Success(c, table)
+		return
+	}
+	content, contentType, ext, err := table.render(format)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, reportType, end.Format("20060102"), ext))
+	c.Data(http.StatusOK, contentType, content)
+}
@@ -0,0 +1,700 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/notification"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/util/cron"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// scheduledReportsConfigMapName stores the report definitions, one entry per report ID
+	scheduledReportsConfigMapName = "scheduled-reports"
+	// reportRunsConfigMapName stores the recent runs of each report, one entry per report ID
+	reportRunsConfigMapName = "scheduled-report-runs"
+	maxRunsPerReport        = 20
+	reportRunTimeout        = 10 * time.Minute
+
+	targetTypeEmail         = "email"
+	targetTypeWebhook       = "webhook"
+	targetTypeObjectStorage = "object-storage"
+
+	triggerSchedule = "schedule"
+	triggerManual   = "manual"
+
+	runStatusSucceeded = "succeeded"
+	// runStatusPartial means the report was generated but could not be delivered to every target
+	runStatusPartial = "partial"
+	runStatusFailed  = "failed"
+)
+
+var fileNameUnsafe = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// DeliveryTarget is where a generated report is sent
+type DeliveryTarget struct {
+	Type string `json:"type"`
+	// Channel is the email or webhook notification channel for email and webhook targets
+	Channel string `json:"channel,omitempty"`
+	// StorageID is the S3 or MinIO backup storage backend for object storage targets
+	StorageID string `json:"storageId,omitempty"`
+	// Path is the folder below the path prefix of the storage backend
+	Path string `json:"path,omitempty"`
+}
+
+// ScheduledReport is a report generated on a cron schedule and delivered to its targets
+type ScheduledReport struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	// Schedule is a five field cron expression evaluated in Timezone
+	Schedule string `json:"schedule"`
+	Timezone string `json:"timezone"`
+	Format   string `json:"format"`
+	// Window is how far back the report looks from the time it runs, e.g. 24h
+	Window     string            `json:"window"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Targets    []DeliveryTarget  `json:"targets"`
+	Enabled    bool              `json:"enabled"`
+	CreatedBy  string            `json:"createdBy,omitempty"`
+	CreatedAt  string            `json:"createdAt"`
+	UpdatedAt  string            `json:"updatedAt"`
+	LastRunAt  string            `json:"lastRunAt,omitempty"`
+	NextRunAt  string            `json:"nextRunAt,omitempty"`
+}
+
+// ScheduledReportRequest represents the request to create or update a scheduled report
+type ScheduledReportRequest struct {
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	Type        string            `json:"type" binding:"required"`
+	Schedule    string            `json:"schedule" binding:"required,cron"`
+	Timezone    string            `json:"timezone"`
+	Format      string            `json:"format"`
+	Window      string            `json:"window"`
+	Parameters  map[string]string `json:"parameters"`
+	Targets     []DeliveryTarget  `json:"targets"`
+	Enabled     *bool             `json:"enabled"`
+}
+
+// DeliveryResult is the outcome of delivering a report to one target
+type DeliveryResult struct {
+	Target  DeliveryTarget `json:"target"`
+	Success bool           `json:"success"`
+	// Location is the object key for object storage targets
+	Location string `json:"location,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReportRun is one generation of a scheduled report
+type ReportRun struct {
+	ID          string            `json:"id"`
+	ReportID    string            `json:"reportId"`
+	ReportName  string            `json:"reportName"`
+	Type        string            `json:"type"`
+	Trigger     string            `json:"trigger"`
+	TriggeredBy string            `json:"triggeredBy,omitempty"`
+	Status      string            `json:"status"`
+	Start       string            `json:"start"`
+	End         string            `json:"end"`
+	StartedAt   string            `json:"startedAt"`
+	CompletedAt string            `json:"completedAt"`
+	Rows        int               `json:"rows"`
+	Summary     map[string]string `json:"summary,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Deliveries  []DeliveryResult  `json:"deliveries"`
+}
+
+var (
+	// reportsMu serializes updates of the definition and run ConfigMaps and guards runningReports
+	reportsMu      sync.Mutex
+	runningReports = map[string]bool{}
+)
+
+func newID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// validateScheduledReport checks the definition and computes its next run
+func validateScheduledReport(report *ScheduledReport, now time.Time) error {
+	if err := validateReportType(report.Type); err != nil {
+		return err
+	}
+	if err := validateReportFormat(report.Format); err != nil {
+		return err
+	}
+	schedule, err := cron.Parse(report.Schedule)
+	if err != nil {
+		return err
+	}
+	location, err := time.LoadLocation(report.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %v", report.Timezone, err)
+	}
+	window, err := time.ParseDuration(report.Window)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid window %q, expected a duration such as 24h", report.Window)
+	}
+	if window > maxReportWindow {
+		return fmt.Errorf("the report window must not exceed %d days", int(maxReportWindow.Hours()/24))
+	}
+	if len(report.Targets) == 0 {
+		return fmt.Errorf("at least one delivery target is required")
+	}
+	for _, target := range report.Targets {
+		switch target.Type {
+		case targetTypeEmail, targetTypeWebhook:
+			if target.Channel == "" {
+				return fmt.Errorf("%s targets require a notification channel", target.Type)
+			}
+		case targetTypeObjectStorage:
+			if target.StorageID == "" {
+				return fmt.Errorf("object storage targets require a storageId")
+			}
+		default:
+			return fmt.Errorf("unsupported delivery target %q, expected %s, %s or %s", target.Type, targetTypeEmail, targetTypeWebhook, targetTypeObjectStorage)
+		}
+	}
+
+	report.NextRunAt = ""
+	if report.Enabled {
+		next := schedule.Next(now.In(location))
+		if next.IsZero() {
+			return fmt.Errorf("schedule %q never runs", report.Schedule)
+		}
+		report.NextRunAt = next.Format(time.RFC3339)
+	}
+	return nil
+}
+
+// applyRequest copies the request into the report, filling in defaults
+func applyRequest(report *ScheduledReport, req *ScheduledReportRequest) {
+	report.Name = req.Name
+	report.Description = req.Description
+	report.Type = req.Type
+	report.Schedule = req.Schedule
+	report.Timezone = req.Timezone
+	if report.Timezone == "" {
+		report.Timezone = "UTC"
+	}
+	report.Format = req.Format
+	if report.Format == "" {
+		report.Format = formatCSV
+	}
+	report.Window = req.Window
+	if report.Window == "" {
+		report.Window = defaultReportWindow.String()
+	}
+	report.Parameters = req.Parameters
+	report.Targets = req.Targets
+	if req.Enabled != nil {
+		report.Enabled = *req.Enabled
+	}
+}
+
+// readConfigMapEntries returns the data of a ConfigMap of this package, empty when it does not exist
+func readConfigMapEntries(ctx context.Context, name string) (map[string]string, error) {
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cm.Data == nil {
+		return map[string]string{}, nil
+	}
+	return cm.Data, nil
+}
+
+// updateConfigMapEntry sets an entry of a ConfigMap, or deletes it when value is empty
+func updateConfigMapEntry(ctx context.Context, name, key, value string) error {
+	configMaps := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace())
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if value == "" {
+			return nil
+		}
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: config.GetNamespace(),
+			},
+			Data: map[string]string{key: value},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	if value == "" {
+		delete(cm.Data, key)
+	} else {
+		cm.Data[key] = value
+	}
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// listScheduledReports returns all scheduled reports sorted by name
+func listScheduledReports(ctx context.Context) ([]*ScheduledReport, error) {
+	data, err := readConfigMapEntries(ctx, scheduledReportsConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduled reports: %v", err)
+	}
+	reports := make([]*ScheduledReport, 0, len(data))
+	for id, value := range data {
+		report := &ScheduledReport{}
+		if err := json.Unmarshal([]byte(value), report); err != nil {
+			klog.ErrorS(err, "Skipping invalid scheduled report", "id", id)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Name < reports[j].Name
+	})
+	return reports, nil
+}
+
+func getScheduledReport(ctx context.Context, id string) (*ScheduledReport, error) {
+	data, err := readConfigMapEntries(ctx, scheduledReportsConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduled reports: %v", err)
+	}
+	value, ok := data[id]
+	if !ok {
+		return nil, fmt.Errorf("scheduled report %s not found", id)
+	}
+	report := &ScheduledReport{}
+	if err := json.Unmarshal([]byte(value), report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func saveScheduledReport(ctx context.Context, report *ScheduledReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return updateConfigMapEntry(ctx, scheduledReportsConfigMapName, report.ID, string(data))
+}
+
+// listReportRuns returns the recent runs of a report, newest first
+func listReportRuns(ctx context.Context, reportID string) ([]ReportRun, error) {
+	data, err := readConfigMapEntries(ctx, reportRunsConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report runs: %v", err)
+	}
+	runs := []ReportRun{}
+	if value, ok := data[reportID]; ok {
+		if err := json.Unmarshal([]byte(value), &runs); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+// recordReportRun prepends a run to the history of its report, keeping maxRunsPerReport runs
+func recordReportRun(ctx context.Context, run ReportRun) error {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	runs, err := listReportRuns(ctx, run.ReportID)
+	if err != nil {
+		return err
+	}
+	runs = append([]ReportRun{run}, runs...)
+	if len(runs) > maxRunsPerReport {
+		runs = runs[:maxRunsPerReport]
+	}
+	data, err := json.Marshal(runs)
+	if err != nil {
+		return err
+	}
+	return updateConfigMapEntry(ctx, reportRunsConfigMapName, run.ReportID, string(data))
+}
+
+// deliverReport sends the rendered report to a target
+func deliverReport(ctx context.Context, report *ScheduledReport, table *Table, target DeliveryTarget, fileName, contentType string, content []byte) DeliveryResult {
+	result := DeliveryResult{Target: target}
+	var err error
+	switch target.Type {
+	case targetTypeEmail, targetTypeWebhook:
+		err = notification.SendToChannel(ctx, target.Channel, notification.Event{
+			Type:      "report.generated",
+			Title:     fmt.Sprintf("Report %s", report.Name),
+			Message:   table.summaryText(),
+			Resource:  report.ID,
+			Timestamp: time.Now().Format(time.RFC3339),
+		}, &notification.Attachment{Filename: fileName, ContentType: contentType, Content: content})
+	case targetTypeObjectStorage:
+		result.Location, err = backup.UploadToStorageBackend(ctx, target.StorageID, path.Join(target.Path, fileName), contentType, content)
+	default:
+		err = fmt.Errorf("unsupported delivery target %q", target.Type)
+	}
+	result.Success = err == nil
+	if err != nil {
+		result.Error = err.Error()
+		klog.ErrorS(err, "Failed to deliver report", "report", report.Name, "target", target.Type)
+	}
+	return result
+}
+
+// runScheduledReport generates a report over the window ending now and delivers it to every target
+func runScheduledReport(ctx context.Context, report *ScheduledReport, trigger, triggeredBy string) ReportRun {
+	id, _ := newID()
+	now := time.Now()
+	window, _ := time.ParseDuration(report.Window)
+	if window <= 0 {
+		window = defaultReportWindow
+	}
+	run := ReportRun{
+		ID:          id,
+		ReportID:    report.ID,
+		ReportName:  report.Name,
+		Type:        report.Type,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Start:       now.Add(-window).Format(time.RFC3339),
+		End:         now.Format(time.RFC3339),
+		StartedAt:   now.Format(time.RFC3339),
+		Deliveries:  []DeliveryResult{},
+	}
+	klog.InfoS("Running scheduled report", "report", report.Name, "type", report.Type, "trigger", trigger)
+
+	ctx, cancel := context.WithTimeout(ctx, reportRunTimeout)
+	defer cancel()
+	table, err := generateReport(ctx, report.Type, now.Add(-window), now, report.Parameters)
+	var content []byte
+	var contentType, ext string
+	if err == nil {
+		content, contentType, ext, err = table.render(report.Format)
+	}
+	if err != nil {
+		run.Status = runStatusFailed
+		run.Error = err.Error()
+		klog.ErrorS(err, "Failed to generate scheduled report", "report", report.Name)
+	} else {
+		run.Rows = len(table.Rows)
+		run.Summary = table.Summary
+		fileName := fmt.Sprintf("%s-%s.%s", strings.Trim(fileNameUnsafe.ReplaceAllString(report.Name, "-"), "-"), now.Format("20060102-1504"), ext)
+		run.Status = runStatusSucceeded
+		failed := 0
+		for _, target := range report.Targets {
+			result := deliverReport(ctx, report, table, target, fileName, contentType, content)
+			if !result.Success {
+				failed++
+			}
+			run.Deliveries = append(run.Deliveries, result)
+		}
+		if failed == len(report.Targets) && failed > 0 {
+			run.Status = runStatusFailed
+			run.Error = "the report could not be delivered to any target"
+		} else if failed > 0 {
+			run.Status = runStatusPartial
+		}
+	}
+	run.CompletedAt = time.Now().Format(time.RFC3339)
+
+	if err := recordReportRun(context.TODO(), run); err != nil {
+		klog.ErrorS(err, "Failed to record report run", "report", report.Name)
+	}
+	return run
+}
+
+// startRun marks a report as running, so a report never runs twice at the same time
+func startRun(reportID string) bool {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	if runningReports[reportID] {
+		return false
+	}
+	runningReports[reportID] = true
+	return true
+}
+
+func endRun(reportID string) {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	delete(runningReports, reportID)
+}
+
+// scheduleNextRun records that a report runs now and stores its next run before it runs,
+// so a failing report is not retried on every tick
+func scheduleNextRun(ctx context.Context, id string, now time.Time) (*ScheduledReport, error) {
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	report, err := getScheduledReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	report.LastRunAt = now.Format(time.RFC3339)
+	if err := validateScheduledReport(report, now); err != nil {
+		klog.ErrorS(err, "Disabling invalid scheduled report", "report", report.Name)
+		report.Enabled = false
+		report.NextRunAt = ""
+	}
+	return report, saveScheduledReport(ctx, report)
+}
+
+// runDueReports starts the enabled reports whose next run has passed and schedules their following run
+func runDueReports(ctx context.Context) {
+	reports, err := listScheduledReports(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list scheduled reports")
+		return
+	}
+	now := time.Now()
+	for _, report := range reports {
+		if !report.Enabled || report.NextRunAt == "" {
+			continue
+		}
+		nextRun, err := time.Parse(time.RFC3339, report.NextRunAt)
+		if err != nil || nextRun.After(now) {
+			continue
+		}
+		if !startRun(report.ID) {
+			continue
+		}
+
+		id := report.ID
+		report, err = scheduleNextRun(ctx, id, now)
+		if err != nil {
+			klog.ErrorS(err, "Failed to update scheduled report", "id", id)
+		}
+		if err != nil || !report.Enabled {
+			endRun(id)
+			continue
+		}
+		go func(report *ScheduledReport) {
+			defer endRun(report.ID)
+			runScheduledReport(ctx, report, triggerSchedule, "")
+		}(report)
+	}
+}
+
+// StartReportScheduler checks every interval for scheduled reports that are due and runs them.
+// A non-positive interval disables scheduled reports, they can still be run on demand.
+func StartReportScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Report scheduler is disabled")
+		return
+	}
+	klog.InfoS("Starting report scheduler", "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runDueReports(ctx)
+			}
+		}
+	}()
+}
+
+// handleListScheduledReports returns all scheduled reports
+func handleListScheduledReports(c *gin.Context) {
+	reports, err := listScheduledReports(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"reports":    reports,
+		"totalItems": len(reports),
+		"types":      reportTypes(),
+	})
+}
+
+// handleGetScheduledReport returns a scheduled report
+func handleGetScheduledReport(c *gin.Context) {
+	report, err := getScheduledReport(c, c.Param("id"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	common.Success(c, report)
+}
+
+// handleCreateScheduledReport creates a scheduled report, enabled unless requested otherwise
+func handleCreateScheduledReport(c *gin.Context) {
+	var req ScheduledReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	now := time.Now()
+	report := &ScheduledReport{
+		ID:        id,
+		Enabled:   true,
+		CreatedBy: utilauth.GetAuthenticatedUser(c),
+		CreatedAt: now.Format(time.RFC3339),
+		UpdatedAt: now.Format(time.RFC3339),
+	}
+	applyRequest(report, &req)
+	if err := validateScheduledReport(report, now); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+
+	reportsMu.Lock()
+	err = saveScheduledReport(c, report)
+	reportsMu.Unlock()
+	if err != nil {
+		klog.ErrorS(err, "Failed to save scheduled report", "name", report.Name)
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Created scheduled report", "id", report.ID, "name", report.Name, "type", report.Type, "schedule", report.Schedule)
+	common.Success(c, report)
+}
+
+// handleUpdateScheduledReport replaces the definition of a scheduled report
+func handleUpdateScheduledReport(c *gin.Context) {
+	var req ScheduledReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	report, err := getScheduledReport(c, c.Param("id"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	now := time.Now()
+	applyRequest(report, &req)
+	report.UpdatedAt = now.Format(time.RFC3339)
+	if err := validateScheduledReport(report, now); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	if err := saveScheduledReport(c, report); err != nil {
+		klog.ErrorS(err, "Failed to save scheduled report", "id", report.ID)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, report)
+}
+
+// handleDeleteScheduledReport deletes a scheduled report and its run history
+func handleDeleteScheduledReport(c *gin.Context) {
+	id := c.Param("id")
+	reportsMu.Lock()
+	defer reportsMu.Unlock()
+	if _, err := getScheduledReport(c, id); err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	if err := updateConfigMapEntry(c, scheduledReportsConfigMapName, id, ""); err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if err := updateConfigMapEntry(c, reportRunsConfigMapName, id, ""); err != nil {
+		klog.ErrorS(err, "Failed to delete report runs", "id", id)
+	}
+	common.Success(c, gin.H{"id": id})
+}
+
+// handleRunScheduledReport generates and delivers a scheduled report now and returns the run
+func handleRunScheduledReport(c *gin.Context) {
+	report, err := getScheduledReport(c, c.Param("id"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	if !startRun(report.ID) {
+		common.FailWithStatus(c, fmt.Errorf("report %s is already running", report.Name), http.StatusConflict)
+		return
+	}
+	defer endRun(report.ID)
+	common.Success(c, runScheduledReport(c.Request.Context(), report, triggerManual, utilauth.GetAuthenticatedUser(c)))
+}
+
+// handleGetReportRuns returns the recent runs of a scheduled report, newest first
+func handleGetReportRuns(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := getScheduledReport(c, id); err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	runs, err := listReportRuns(c, id)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"runs":       runs,
+		"totalItems": len(runs),
+	})
+}
+
+func init() {
+	r := router.V1()
+	group := r.Group("/reports", router.EnsureMgmtAdminMiddleware())
+	group.GET("/generate", handleGenerateReport)
+	group.GET("/scheduled", handleListScheduledReports)
+	group.POST("/scheduled", handleCreateScheduledReport)
+	group.GET("/scheduled/:id", handleGetScheduledReport)
+	group.PUT("/scheduled/:id", handleUpdateScheduledReport)
+	group.DELETE("/scheduled/:id", handleDeleteScheduledReport)
+	group.POST("/scheduled/:id/run", handleRunScheduledReport)
+	group.GET("/scheduled/:id/runs", handleGetReportRuns)
+}
@@ -0,0 +1,401 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reports generates platform reports, such as the usage of cluster resources by Kubeflow Profiles.
+// Reports are generated on demand or on a cron schedule and delivered by email, webhook or object storage.
+package reports
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/setting/monitoring"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+const (
+	defaultReportWindow = 7 * 24 * time.Hour
+	maxReportWindow     = 90 * 24 * time.Hour
+
+	sourcePrometheus    = "prometheus"
+	sourceMetricsServer = "metrics-server"
+
+	gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+)
+
+var (
+	profileGVR    = schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "profiles"}
+	podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+)
+
+// Prometheus queries summing the usage of each namespace over a window, which is substituted in seconds.
+// CPU is the CPU time used, memory and GPUs are averaged over the window and multiplied by its length.
+const (
+	cpuSecondsQuery  = `sum by (namespace) (increase(container_cpu_usage_seconds_total{container!="",pod!=""}[%ds]))`
+	memoryBytesQuery = `sum by (namespace) (avg_over_time(container_memory_working_set_bytes{container!="",pod!=""}[%ds]))`
+	gpuRequestsQuery = `sum by (namespace) (avg_over_time(kube_pod_container_resource_requests{resource="nvidia_com_gpu"}[%ds]))`
+)
+
+// UsageRow is the resource usage of a profile namespace in one cluster
+type UsageRow struct {
+	Cluster        string  `json:"cluster"`
+	Namespace      string  `json:"namespace"`
+	Profile        string  `json:"profile"`
+	Owner          string  `json:"owner"`
+	CPUCoreHours   float64 `json:"cpuCoreHours"`
+	MemoryGiBHours float64 `json:"memoryGiBHours"`
+	GPUHours       float64 `json:"gpuHours"`
+	Source         string  `json:"source"`
+	// Estimated is set when the usage is extrapolated from the current usage reported by metrics-server
+	Estimated bool `json:"estimated"`
+}
+
+// NamespaceUsage is the usage of a profile namespace summed over all clusters
+type NamespaceUsage struct {
+	Namespace      string   `json:"namespace"`
+	Owner          string   `json:"owner"`
+	Clusters       []string `json:"clusters"`
+	CPUCoreHours   float64  `json:"cpuCoreHours"`
+	MemoryGiBHours float64  `json:"memoryGiBHours"`
+	GPUHours       float64  `json:"gpuHours"`
+}
+
+// UsageReport attributes CPU, memory and GPU hours to profile namespaces over a time window
+type UsageReport struct {
+	Start  string           `json:"start"`
+	End    string           `json:"end"`
+	Hours  float64          `json:"hours"`
+	Rows   []UsageRow       `json:"rows"`
+	Totals []NamespaceUsage `json:"totals"`
+	// Errors lists the clusters whose usage could not be collected
+	Errors []string `json:"errors"`
+}
+
+// profileInfo is a Kubeflow Profile, whose namespace has the name of the profile
+type profileInfo struct {
+	Name  string
+	Owner string
+}
+
+// namespaceUsage is the usage of a namespace in the units of the report
+type namespaceUsage struct {
+	cpuCoreHours, memoryGiBHours, gpuHours float64
+}
+
+// parseReportWindow reads start and end as RFC3339, defaulting to the last seven days
+func parseReportWindow(c *gin.Context) (time.Time, time.Time, error) {
+	end := time.Now()
+	if value := c.Query("end"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end %q, expected RFC3339", value)
+		}
+		end = parsed
+	}
+	start := end.Add(-defaultReportWindow)
+	if value := c.Query("start"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start %q, expected RFC3339", value)
+		}
+		start = parsed
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start must be before end")
+	}
+	if end.Sub(start) > maxReportWindow {
+		return time.Time{}, time.Time{}, fmt.Errorf("the report window must not exceed %d days", int(maxReportWindow.Hours()/24))
+	}
+	return start, end, nil
+}
+
+// listProfiles returns the Kubeflow Profiles of the control plane by namespace
+func listProfiles(ctx context.Context) (map[string]profileInfo, error) {
+	karmadaConfig, _, err := client.GetKarmadaConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get karmada config: %v", err)
+	}
+	karmadaDynamicClient, err := dynamic.NewForConfig(karmadaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create karmada dynamic client: %v", err)
+	}
+	list, err := karmadaDynamicClient.Resource(profileGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Kubeflow Profiles: %v", err)
+	}
+	profiles := make(map[string]profileInfo, len(list.Items))
+	for _, item := range list.Items {
+		owner, _, _ := unstructured.NestedString(item.Object, "spec", "owner", "name")
+		profiles[item.GetName()] = profileInfo{Name: item.GetName(), Owner: owner}
+	}
+	return profiles, nil
+}
+
+// prometheusUsage queries the usage of each namespace over the window from the Prometheus of a cluster
+func prometheusUsage(ctx context.Context, cluster string, start, end time.Time) (map[string]*namespaceUsage, error) {
+	window := int64(end.Sub(start).Seconds())
+	hours := end.Sub(start).Hours()
+	usage := map[string]*namespaceUsage{}
+	get := func(namespace string) *namespaceUsage {
+		if usage[namespace] == nil {
+			usage[namespace] = &namespaceUsage{}
+		}
+		return usage[namespace]
+	}
+
+	queries := []struct {
+		query string
+		add   func(u *namespaceUsage, value float64)
+	}{
+		{cpuSecondsQuery, func(u *namespaceUsage, value float64) { u.cpuCoreHours += value / 3600 }},
+		{memoryBytesQuery, func(u *namespaceUsage, value float64) { u.memoryGiBHours += value / (1 << 30) * hours }},
+		{gpuRequestsQuery, func(u *namespaceUsage, value float64) { u.gpuHours += value * hours }},
+	}
+	for _, q := range queries {
+		samples, err := monitoring.QueryClusterPrometheus(ctx, cluster, fmt.Sprintf(q.query, window), end)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range samples {
+			if namespace := sample.Labels["namespace"]; namespace != "" {
+				q.add(get(namespace), sample.Value)
+			}
+		}
+	}
+	return usage, nil
+}
+
+// metricsServerUsage extrapolates the current usage reported by metrics-server over the window.
+// GPUs are not reported by metrics-server, so the GPU requests of running pods are used instead.
+func metricsServerUsage(ctx context.Context, cluster string, namespaces []string, hours float64) (map[string]*namespaceUsage, error) {
+	dynamicClient, err := client.DynamicClientForMemberCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient := client.InClusterClientForMemberCluster(cluster)
+	if kubeClient == nil {
+		return nil, fmt.Errorf("failed to get client for cluster %s", cluster)
+	}
+
+	usage := map[string]*namespaceUsage{}
+	for _, namespace := range namespaces {
+		u := &namespaceUsage{}
+		podMetrics, err := dynamicClient.Resource(podMetricsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod metrics: %v", err)
+		}
+		for _, item := range podMetrics.Items {
+			containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+			for _, container := range containers {
+				fields, ok := container.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				containerUsage, _, _ := unstructured.NestedStringMap(fields, "usage")
+				if cpu, err := resource.ParseQuantity(containerUsage["cpu"]); err == nil {
+					u.cpuCoreHours += float64(cpu.MilliValue()) / 1000 * hours
+				}
+				if memory, err := resource.ParseQuantity(containerUsage["memory"]); err == nil {
+					u.memoryGiBHours += float64(memory.Value()) / (1 << 30) * hours
+				}
+			}
+		}
+
+		pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Running"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %v", err)
+		}
+		for _, pod := range pods.Items {
+			for _, container := range pod.Spec.Containers {
+				if gpus, ok := container.Resources.Requests[gpuResourceName]; ok {
+					u.gpuHours += float64(gpus.Value()) * hours
+				}
+			}
+		}
+		if u.cpuCoreHours > 0 || u.memoryGiBHours > 0 || u.gpuHours > 0 {
+			usage[namespace] = u
+		}
+	}
+	return usage, nil
+}
+
+// buildUsageReport collects the usage of the profile namespaces in every cluster
+func buildUsageReport(ctx context.Context, start, end time.Time, source, clusterFilter, namespaceFilter string) (*UsageReport, error) {
+	profiles, err := listProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for namespace := range profiles {
+		if namespaceFilter == "" || namespace == namespaceFilter {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %v", err)
+	}
+
+	hours := end.Sub(start).Hours()
+	report := &UsageReport{
+		Start:  start.Format(time.RFC3339),
+		End:    end.Format(time.RFC3339),
+		Hours:  hours,
+		Rows:   []UsageRow{},
+		Totals: []NamespaceUsage{},
+		Errors: []string{},
+	}
+	for _, cluster := range clusters.Items {
+		name := cluster.Name
+		if clusterFilter != "" && name != clusterFilter {
+			continue
+		}
+
+		var usage map[string]*namespaceUsage
+		usedSource := source
+		if source != sourceMetricsServer {
+			usage, err = prometheusUsage(ctx, name, start, end)
+			usedSource = sourcePrometheus
+			if errors.Is(err, monitoring.ErrNoPrometheus) && source == "" {
+				usage, err = nil, nil
+				usedSource = sourceMetricsServer
+			}
+		}
+		if usedSource == sourceMetricsServer {
+			usage, err = metricsServerUsage(ctx, name, namespaces, hours)
+		}
+		if err != nil {
+			klog.ErrorS(err, "Failed to collect usage", "cluster", name, "source", usedSource)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		for _, namespace := range namespaces {
+			u, ok := usage[namespace]
+			if !ok {
+				continue
+			}
+			report.Rows = append(report.Rows, UsageRow{
+				Cluster:        name,
+				Namespace:      namespace,
+				Profile:        profiles[namespace].Name,
+				Owner:          profiles[namespace].Owner,
+				CPUCoreHours:   u.cpuCoreHours,
+				MemoryGiBHours: u.memoryGiBHours,
+				GPUHours:       u.gpuHours,
+				Source:         usedSource,
+				Estimated:      usedSource == sourceMetricsServer,
+			})
+		}
+	}
+
+	totals := map[string]*NamespaceUsage{}
+	for _, row := range report.Rows {
+		total, ok := totals[row.Namespace]
+		if !ok {
+			total = &NamespaceUsage{Namespace: row.Namespace, Owner: row.Owner, Clusters: []string{}}
+			totals[row.Namespace] = total
+		}
+		total.Clusters = append(total.Clusters, row.Cluster)
+		total.CPUCoreHours += row.CPUCoreHours
+		total.MemoryGiBHours += row.MemoryGiBHours
+		total.GPUHours += row.GPUHours
+	}
+	for _, namespace := range namespaces {
+		if total, ok := totals[namespace]; ok {
+			report.Totals = append(report.Totals, *total)
+		}
+	}
+	return report, nil
+}
+
+// writeUsageCSV writes the rows of a report as a CSV attachment
+func writeUsageCSV(c *gin.Context, report *UsageReport) {
+	start, _ := time.Parse(time.RFC3339, report.Start)
+	end, _ := time.Parse(time.RFC3339, report.End)
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s-%s.csv"`, start.Format("20060102"), end.Format("20060102")))
+	c.Status(http.StatusOK)
+
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 3, 64)
+	}
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"cluster", "namespace", "profile", "owner", "cpu_core_hours", "memory_gib_hours", "gpu_hours", "source", "estimated", "start", "end"})
+	for _, row := range report.Rows {
+		_ = writer.Write([]string{
+			row.Cluster, row.Namespace, row.Profile, row.Owner,
+			formatFloat(row.CPUCoreHours), formatFloat(row.MemoryGiBHours), formatFloat(row.GPUHours),
+			row.Source, strconv.FormatBool(row.Estimated), report.Start, report.End,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		klog.ErrorS(err, "Failed to write usage report CSV")
+	}
+}
+
+// handleGetUsageReport returns the CPU, memory and GPU hours of each Kubeflow Profile namespace per cluster.
+// Query parameters: start and end (RFC3339, default the last 7 days), cluster, namespace, source
+// (prometheus or metrics-server, by default Prometheus where registered) and format=csv for a CSV export.
+func handleGetUsageReport(c *gin.Context) {
+	start, end, err := parseReportWindow(c)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	source := c.Query("source")
+	if source != "" && source != sourcePrometheus && source != sourceMetricsServer {
+		common.FailWithStatus(c, fmt.Errorf("source must be %s or %s", sourcePrometheus, sourceMetricsServer), http.StatusBadRequest)
+		return
+	}
+
+	report, err := buildUsageReport(c, start, end, source, c.Query("cluster"), c.Query("namespace"))
+	if err != nil {
+		klog.ErrorS(err, "Failed to build usage report")
+		common.Fail(c, err)
+		return
+	}
+	if c.Query("format") == "csv" {
+		writeUsageCSV(c, report)
+		return
+	}
+	common.Success(c, report)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/reports/usage", router.EnsureMgmtAdminMiddleware(), handleGetUsageReport)
+}
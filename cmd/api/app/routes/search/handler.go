@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/search"
+)
+
+// maxLimit caps the number of results of a search
+const maxLimit = 200
+
+var searchTypes = map[search.Type]bool{
+	search.TypeCluster:     true,
+	search.TypeNamespace:   true,
+	search.TypeBackup:      true,
+	search.TypeRecovery:    true,
+	search.TypeApplication: true,
+	search.TypeUser:        true,
+}
+
+// Response is the result of a global search
+type Response struct {
+	Query   string                `json:"query"`
+	Results []search.Result       `json:"results"`
+	Total   int                   `json:"total"`
+	Sources []search.SourceStatus `json:"sources"`
+}
+
+// parseTypes parses the comma separated types of the type query parameter
+func parseTypes(value string) ([]search.Type, error) {
+	var types []search.Type
+	for _, t := range strings.Split(value, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !searchTypes[search.Type(t)] {
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+		types = append(types, search.Type(t))
+	}
+	return types, nil
+}
+
+// allowDocuments returns the filter of the documents the user of the request may see: the ones of the clusters
+// they can access, and the users for dashboard admins only
+func allowDocuments(c *gin.Context) func(search.Document) bool {
+	clusters := map[string]bool{}
+	var isAdmin *bool
+	return func(doc search.Document) bool {
+		if doc.Type == search.TypeUser {
+			if isAdmin == nil {
+				admin := router.IsDashboardAdmin(c)
+				isAdmin = &admin
+			}
+			return *isAdmin
+		}
+		if doc.Cluster == "" {
+			return true
+		}
+		// Backups of several clusters are visible with access to any of them
+		for _, clusterName := range strings.Split(doc.Cluster, ",") {
+			allowed, checked := clusters[clusterName]
+			if !checked {
+				allowed = client.CheckMemberClusterAccess(c, clusterName) == nil
+				clusters[clusterName] = allowed
+			}
+			if allowed {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// handleSearch searches the clusters, namespaces, backups, recoveries, ArgoCD applications and users for the
+// words of the q query parameter. The type parameter restricts the types of the results.
+func handleSearch(c *gin.Context) {
+	if index == nil {
+		common.FailWithStatus(c, fmt.Errorf("global search is disabled"), http.StatusServiceUnavailable)
+		return
+	}
+	if !index.Ready() {
+		common.FailWithStatus(c, fmt.Errorf("search index is being built, retry shortly"), http.StatusServiceUnavailable)
+		return
+	}
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		common.FailWithStatus(c, fmt.Errorf("query parameter q is required"), http.StatusBadRequest)
+		return
+	}
+	types, err := parseTypes(c.Query("type"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	limit := search.DefaultLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			common.FailWithStatus(c, fmt.Errorf("limit must be between 1 and %d", maxLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	results := index.Search(search.Query{Text: query, Types: types, Limit: limit, Allow: allowDocuments(c)})
+	common.Success(c, Response{
+		Query:   query,
+		Results: results,
+		Total:   len(results),
+		Sources: index.Status(),
+	})
+}
+
+func init() {
+	router.V1().GET("/search", handleSearch)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/users"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/argocd"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+	"github.com/karmada-io/dashboard/pkg/search"
+)
+
+// index is the index of the global search, nil when the indexer is disabled
+var index *search.Index
+
+// indexSource builds the documents of a source of the index
+type indexSource struct {
+	name  string
+	build func(ctx context.Context) ([]search.Document, error)
+}
+
+var indexSources = []indexSource{
+	{name: "clusters", build: clusterDocuments},
+	{name: "namespaces", build: namespaceDocuments},
+	{name: "backups", build: backup.BackupSearchDocuments},
+	{name: "recoveries", build: backup.RecoverySearchDocuments},
+	{name: "applications", build: applicationDocuments},
+	{name: "users", build: users.SearchDocuments},
+}
+
+// StartIndexer builds the index of the global search and rebuilds it every interval. Every replica keeps its
+// own index. A non-positive interval disables the search.
+func StartIndexer(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Global search indexer is disabled")
+		return
+	}
+	index = search.NewIndex()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			rebuildIndex(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	klog.InfoS("Global search indexer started", "interval", interval)
+}
+
+// rebuildIndex replaces the documents of each source. A source that fails keeps the documents it had.
+func rebuildIndex(ctx context.Context) {
+	for _, source := range indexSources {
+		docs, err := source.build(ctx)
+		if err != nil {
+			klog.V(2).InfoS("Failed to index search source", "source", source.name, "error", err)
+			continue
+		}
+		index.Replace(source.name, docs, time.Now())
+	}
+}
+
+// readyClusters returns the names of the ready member clusters, sorted
+func readyClusters(ctx context.Context) ([]string, error) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i := range clusters.Items {
+		if ready, _ := cluster.ReadyMessage(&clusters.Items[i]); ready {
+			names = append(names, clusters.Items[i].Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func clusterDocuments(ctx context.Context) ([]search.Document, error) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]search.Document, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		docs = append(docs, clusterDocument(&clusters.Items[i]))
+	}
+	return docs, nil
+}
+
+func clusterDocument(c *clusterv1alpha1.Cluster) search.Document {
+	state := "not ready"
+	if ready, _ := cluster.ReadyMessage(c); ready {
+		state = "ready"
+	}
+	keywords := []string{c.Spec.Provider, c.Spec.Region, c.Spec.Zone, c.Status.KubernetesVersion}
+	for key, value := range c.Labels {
+		keywords = append(keywords, key+"="+value)
+	}
+	return search.Document{
+		Type:        search.TypeCluster,
+		ID:          c.Name,
+		Name:        c.Name,
+		Cluster:     c.Name,
+		Description: fmt.Sprintf("%s cluster, %s, %s", c.Spec.SyncMode, state, c.Status.KubernetesVersion),
+		Keywords:    keywords,
+		Link: search.Link{
+			Module: "clusters",
+			API:    "/api/v1/cluster/" + c.Name,
+			Params: map[string]string{"cluster": c.Name},
+		},
+	}
+}
+
+// namespaceDocuments returns the namespaces of the ready member clusters. An unreachable cluster is left out.
+func namespaceDocuments(ctx context.Context) ([]search.Document, error) {
+	clusterNames, err := readyClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var docs []search.Document
+	for _, clusterName := range clusterNames {
+		memberClient := client.InClusterClientForMemberCluster(clusterName)
+		if memberClient == nil {
+			continue
+		}
+		namespaces, err := memberClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.V(2).InfoS("Failed to index namespaces", "cluster", clusterName, "error", err)
+			continue
+		}
+		for _, ns := range namespaces.Items {
+			docs = append(docs, search.Document{
+				Type:        search.TypeNamespace,
+				ID:          clusterName + "/" + ns.Name,
+				Name:        ns.Name,
+				Cluster:     clusterName,
+				Description: fmt.Sprintf("Namespace in %s, %s", clusterName, ns.Status.Phase),
+				Link: search.Link{
+					Module: "namespaces",
+					API:    fmt.Sprintf("/api/v1/member/%s/namespace/%s", clusterName, ns.Name),
+					Params: map[string]string{"cluster": clusterName, "name": ns.Name},
+				},
+			})
+		}
+	}
+	return docs, nil
+}
+
+// applicationDocuments returns the ArgoCD applications of the ready member clusters running ArgoCD
+func applicationDocuments(ctx context.Context) ([]search.Document, error) {
+	clusterNames, err := readyClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var docs []search.Document
+	for _, clusterName := range clusterNames {
+		if !argocd.Installed(ctx, clusterName) {
+			continue
+		}
+		dynamicClient, _, err := client.MemberDynamicClient(ctx, clusterName)
+		if err != nil {
+			klog.V(2).InfoS("Failed to create dynamic client for the search index", "cluster", clusterName, "error", err)
+			continue
+		}
+		items, err := argocd.NewService(dynamicClient, clusterName).List(ctx, argocd.Application, "")
+		if err != nil {
+			klog.V(2).InfoS("Failed to index ArgoCD applications", "cluster", clusterName, "error", err)
+			continue
+		}
+		for i := range items {
+			docs = append(docs, applicationDocument(clusterName, &items[i]))
+		}
+	}
+	return docs, nil
+}
+
+func applicationDocument(clusterName string, app *unstructured.Unstructured) search.Document {
+	project, _, _ := unstructured.NestedString(app.Object, "spec", "project")
+	repoURL, _, _ := unstructured.NestedString(app.Object, "spec", "source", "repoURL")
+	destination, _, _ := unstructured.NestedString(app.Object, "spec", "destination", "namespace")
+	syncStatus, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	health, _, _ := unstructured.NestedString(app.Object, "status", "health", "status")
+	return search.Document{
+		Type:        search.TypeApplication,
+		ID:          clusterName + "/" + app.GetNamespace() + "/" + app.GetName(),
+		Name:        app.GetName(),
+		Cluster:     clusterName,
+		Namespace:   app.GetNamespace(),
+		Description: fmt.Sprintf("ArgoCD application of project %s, %s, %s", project, syncStatus, health),
+		Keywords:    []string{project, repoURL, destination},
+		Link: search.Link{
+			Module: "argocd",
+			API:    fmt.Sprintf("/api/v1/member/%s/argocd/application/%s", clusterName, app.GetName()),
+			Params: map[string]string{"cluster": clusterName, "name": app.GetName()},
+		},
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/notification"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/setting/monitoring"
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	settingsAPIVersion = "ml-platform.io/v1"
+	settingsKind       = "PlatformSettings"
+	maxDocumentSize    = 1 << 20
+)
+
+// reconcileFunc compares one section of the document with the current state and applies the differences
+type reconcileFunc func(ctx context.Context, settings *v1.DeclarativeSettings, dryRun bool) ([]v1.SettingsChange, error)
+
+// sections are reconciled in order, controllers last as their installs take the longest
+var sections = []struct {
+	name      string
+	present   func(settings *v1.DeclarativeSettings) bool
+	reconcile reconcileFunc
+}{
+	{
+		name:    "registries",
+		present: func(s *v1.DeclarativeSettings) bool { return s.Registries != nil },
+		reconcile: func(ctx context.Context, s *v1.DeclarativeSettings, dryRun bool) ([]v1.SettingsChange, error) {
+			return backup.ReconcileRegistries(ctx, s.Registries, s.Prune, dryRun)
+		},
+	},
+	{
+		name:    "monitoring",
+		present: func(s *v1.DeclarativeSettings) bool { return s.Monitoring != nil },
+		reconcile: func(ctx context.Context, s *v1.DeclarativeSettings, dryRun bool) ([]v1.SettingsChange, error) {
+			return monitoring.ReconcileMonitoring(ctx, s.Monitoring, s.Prune, dryRun)
+		},
+	},
+	{
+		name:    "notificationChannels",
+		present: func(s *v1.DeclarativeSettings) bool { return s.NotificationChannels != nil },
+		reconcile: func(ctx context.Context, s *v1.DeclarativeSettings, dryRun bool) ([]v1.SettingsChange, error) {
+			return notification.ReconcileChannels(ctx, s.NotificationChannels, s.Prune, dryRun)
+		},
+	},
+	{
+		name:    "controllers",
+		present: func(s *v1.DeclarativeSettings) bool { return s.Controllers != nil },
+		reconcile: func(ctx context.Context, s *v1.DeclarativeSettings, dryRun bool) ([]v1.SettingsChange, error) {
+			return backup.ReconcileControllers(ctx, s.Controllers, s.Prune, dryRun)
+		},
+	},
+}
+
+// plan reconciles the sections present in the document and returns all changes
+func plan(ctx context.Context, settings *v1.DeclarativeSettings, dryRun bool) ([]v1.SettingsChange, error) {
+	changes := []v1.SettingsChange{}
+	for _, section := range sections {
+		if !section.present(settings) {
+			continue
+		}
+		sectionChanges, err := section.reconcile(ctx, settings, dryRun)
+		if err != nil {
+			return changes, fmt.Errorf("failed to reconcile %s: %v", section.name, err)
+		}
+		changes = append(changes, sectionChanges...)
+	}
+	return changes, nil
+}
+
+// summarize counts the changes by action and the failed ones
+func summarize(changes []v1.SettingsChange) gin.H {
+	summary := map[string]int{
+		v1.SettingsActionCreate:    0,
+		v1.SettingsActionUpdate:    0,
+		v1.SettingsActionDelete:    0,
+		v1.SettingsActionUnchanged: 0,
+	}
+	failed := 0
+	for _, change := range changes {
+		summary[change.Action]++
+		if change.Error != "" {
+			failed++
+		}
+	}
+	return gin.H{
+		"create":    summary[v1.SettingsActionCreate],
+		"update":    summary[v1.SettingsActionUpdate],
+		"delete":    summary[v1.SettingsActionDelete],
+		"unchanged": summary[v1.SettingsActionUnchanged],
+		"failed":    failed,
+	}
+}
+
+func hasErrors(changes []v1.SettingsChange) bool {
+	for _, change := range changes {
+		if change.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// handleGetDeclarativeSettings returns the current settings as a document that can be applied as is.
+// Secrets are left out, so applying it keeps them. format=json returns JSON instead of YAML.
+func handleGetDeclarativeSettings(c *gin.Context) {
+	settings := &v1.DeclarativeSettings{APIVersion: settingsAPIVersion, Kind: settingsKind}
+	var err error
+	if settings.Registries, err = backup.CurrentRegistries(c); err != nil {
+		klog.ErrorS(err, "Failed to get registries")
+		common.Fail(c, err)
+		return
+	}
+	if settings.Monitoring, err = monitoring.CurrentMonitoring(c); err != nil {
+		klog.ErrorS(err, "Failed to get monitoring endpoints")
+		common.Fail(c, err)
+		return
+	}
+	if settings.NotificationChannels, err = notification.CurrentChannels(c); err != nil {
+		klog.ErrorS(err, "Failed to get notification channels")
+		common.Fail(c, err)
+		return
+	}
+	if settings.Controllers, err = backup.CurrentControllers(c); err != nil {
+		klog.ErrorS(err, "Failed to get migration controllers")
+		common.Fail(c, err)
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, settings)
+		return
+	}
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/yaml", data)
+}
+
+// handleApplyDeclarativeSettings brings the platform settings to the state of a YAML or JSON document.
+// The whole document is checked first and nothing is applied when an entry is invalid, so applying the
+// same document twice changes nothing the second time. dryRun=true only reports the changes.
+func handleApplyDeclarativeSettings(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxDocumentSize+1))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if len(body) > maxDocumentSize {
+		common.FailWithStatus(c, fmt.Errorf("document exceeds %d bytes", maxDocumentSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	settings := &v1.DeclarativeSettings{}
+	// YAML is a superset of JSON, so both are accepted
+	if err := yaml.UnmarshalStrict(body, settings); err != nil {
+		common.FailWithStatus(c, fmt.Errorf("invalid document: %v", err), http.StatusBadRequest)
+		return
+	}
+	if settings.Kind != settingsKind || settings.APIVersion != settingsAPIVersion {
+		common.FailWithStatus(c, fmt.Errorf("expected a %s %s", settingsAPIVersion, settingsKind), http.StatusBadRequest)
+		return
+	}
+	dryRun := c.Query("dryRun") == "true"
+
+	changes, err := plan(c, settings, true)
+	if err != nil {
+		klog.ErrorS(err, "Failed to compare declarative settings")
+		common.Fail(c, err)
+		return
+	}
+	if dryRun || hasErrors(changes) {
+		common.Success(c, gin.H{
+			"dryRun":  dryRun,
+			"applied": false,
+			"changes": changes,
+			"summary": summarize(changes),
+		})
+		return
+	}
+
+	klog.InfoS("Applying declarative settings", "prune", settings.Prune, "user", utilauth.GetAuthenticatedUser(c))
+	changes, err = plan(c, settings, false)
+	if err != nil {
+		klog.ErrorS(err, "Failed to apply declarative settings")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{
+		"dryRun":  false,
+		"applied": true,
+		"changes": changes,
+		"summary": summarize(changes),
+	})
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/settings/declarative", router.EnsureMgmtAdminMiddleware(), handleGetDeclarativeSettings)
+	r.PUT("/settings/declarative", router.EnsureMgmtAdminMiddleware(), handleApplyDeclarativeSettings)
+}
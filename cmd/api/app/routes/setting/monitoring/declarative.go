@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	v1 "github.com/karmada-io/dashboard/cmd/api/app/types/api/v1"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const settingsKindMonitoring = "monitoring"
+
+// loadOrCreateMonitoringConfig is loadMonitoringConfig that creates the dashboard configmap when it is missing.
+// With dryRun a missing configmap is reported as an empty configuration instead.
+func loadOrCreateMonitoringConfig(ctx context.Context, dryRun bool) (*corev1.ConfigMap, *MonitoringConfig, error) {
+	configMap, monitoringConfig, err := loadMonitoringConfig(ctx)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return configMap, monitoringConfig, err
+	}
+	configMap = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monitoringConfigMapName,
+			Namespace: config.GetNamespace(),
+		},
+		Data: make(map[string]string),
+	}
+	if !dryRun {
+		configMap, err = client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Create(ctx, configMap, metav1.CreateOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return configMap, &MonitoringConfig{}, nil
+}
+
+// CurrentMonitoring returns the monitoring endpoints as declared settings, without tokens
+func CurrentMonitoring(ctx context.Context) ([]v1.DeclaredMonitoring, error) {
+	_, monitoringConfig, err := loadOrCreateMonitoringConfig(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	monitorings := make([]v1.DeclaredMonitoring, 0, len(monitoringConfig.Monitorings))
+	for _, m := range monitoringConfig.Monitorings {
+		monitorings = append(monitorings, v1.DeclaredMonitoring{
+			Name:     m.Name,
+			Type:     m.Type,
+			Endpoint: m.Endpoint,
+			Cluster:  m.Cluster,
+		})
+	}
+	return monitorings, nil
+}
+
+// validateDeclaredMonitoring checks a declared endpoint the way the add APIs do
+func validateDeclaredMonitoring(ctx context.Context, monitoring v1.DeclaredMonitoring) error {
+	if monitoring.Name == "" || monitoring.Endpoint == "" {
+		return fmt.Errorf("name and endpoint are required")
+	}
+	switch monitoring.Type {
+	case "grafana":
+	case "prometheus":
+		if monitoring.Cluster == "" {
+			return fmt.Errorf("a cluster is required for prometheus")
+		}
+		if _, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().Get(ctx, monitoring.Cluster, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("cluster '%s' not found: %w", monitoring.Cluster, err)
+		}
+	default:
+		return fmt.Errorf("unsupported monitoring type %q", monitoring.Type)
+	}
+	return nil
+}
+
+// ReconcileMonitoring brings the monitoring endpoints to the declared state, or only reports the changes with dryRun.
+// Endpoints that are not declared are deleted with prune, together with their token secrets.
+func ReconcileMonitoring(ctx context.Context, desired []v1.DeclaredMonitoring, prune, dryRun bool) ([]v1.SettingsChange, error) {
+	configMap, monitoringConfig, err := loadOrCreateMonitoringConfig(ctx, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load monitoring config: %w", err)
+	}
+
+	var changes []v1.SettingsChange
+	declared := map[string]bool{}
+	for _, monitoring := range desired {
+		monitoring.Name = strings.TrimSpace(monitoring.Name)
+		monitoring.Endpoint = strings.TrimRight(monitoring.Endpoint, "/")
+		change := v1.SettingsChange{Kind: settingsKindMonitoring, Name: monitoring.Name}
+		declared[monitoring.Name] = true
+
+		idx := findMonitoringSource(monitoringConfig, monitoring.Name)
+		if err := validateDeclaredMonitoring(ctx, monitoring); err != nil {
+			change.Action = v1.SettingsActionCreate
+			if idx >= 0 {
+				change.Action = v1.SettingsActionUpdate
+			}
+			change.Error = err.Error()
+			changes = append(changes, change)
+			continue
+		}
+
+		if idx < 0 {
+			change.Action = v1.SettingsActionCreate
+			secretName := ""
+			if monitoring.Token != "" && !dryRun {
+				secretName, err = createMonitoringTokenSecret(ctx, monitoring.Type, monitoring.Name, monitoring.Token)
+				if err != nil {
+					change.Error = err.Error()
+					changes = append(changes, change)
+					continue
+				}
+			}
+			monitoringConfig.Monitorings = append(monitoringConfig.Monitorings, MonitoringSource{
+				Name:     monitoring.Name,
+				Type:     monitoring.Type,
+				Endpoint: monitoring.Endpoint,
+				Token:    secretName,
+				Cluster:  monitoring.Cluster,
+			})
+			changes = append(changes, change)
+			continue
+		}
+
+		current := &monitoringConfig.Monitorings[idx]
+		if current.Type != monitoring.Type {
+			current.Type = monitoring.Type
+			change.Fields = append(change.Fields, "type")
+		}
+		if strings.TrimRight(current.Endpoint, "/") != monitoring.Endpoint {
+			current.Endpoint = monitoring.Endpoint
+			change.Fields = append(change.Fields, "endpoint")
+		}
+		if current.Cluster != monitoring.Cluster {
+			current.Cluster = monitoring.Cluster
+			change.Fields = append(change.Fields, "cluster")
+		}
+		if monitoring.Token != "" {
+			token, err := GetMonitoringToken(ctx, current.Token)
+			if err != nil || token != monitoring.Token {
+				change.Fields = append(change.Fields, "token")
+				if !dryRun {
+					err = setMonitoringToken(ctx, current, monitoring.Token)
+				}
+			}
+			if err != nil {
+				change.Error = err.Error()
+			}
+		}
+		change.Action = v1.SettingsActionUnchanged
+		if len(change.Fields) > 0 {
+			change.Action = v1.SettingsActionUpdate
+		}
+		changes = append(changes, change)
+	}
+
+	var deletedSecrets []string
+	if prune {
+		kept := make([]MonitoringSource, 0, len(monitoringConfig.Monitorings))
+		for _, m := range monitoringConfig.Monitorings {
+			if declared[m.Name] {
+				kept = append(kept, m)
+				continue
+			}
+			changes = append(changes, v1.SettingsChange{Kind: settingsKindMonitoring, Name: m.Name, Action: v1.SettingsActionDelete})
+			if m.Token != "" {
+				deletedSecrets = append(deletedSecrets, m.Token)
+			}
+		}
+		monitoringConfig.Monitorings = kept
+	}
+
+	if dryRun || !hasChanges(changes) {
+		return changes, nil
+	}
+	if err := saveMonitoringConfig(ctx, configMap, monitoringConfig); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", monitoringConfigMapName, err)
+	}
+	kubeClient := client.InClusterClient()
+	for _, secretName := range deletedSecrets {
+		err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Delete(ctx, secretName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete monitoring token secret", "secretName", secretName)
+		}
+	}
+	return changes, nil
+}
+
+// setMonitoringToken replaces the token of an endpoint, creating its secret if it has none yet
+func setMonitoringToken(ctx context.Context, monitoring *MonitoringSource, token string) error {
+	if monitoring.Token != "" {
+		err := writeMonitoringToken(ctx, monitoring.Token, token)
+		if err == nil || !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	secretName, err := createMonitoringTokenSecret(ctx, monitoring.Type, monitoring.Name, token)
+	if err != nil {
+		return err
+	}
+	monitoring.Token = secretName
+	return nil
+}
+
+func hasChanges(changes []v1.SettingsChange) bool {
+	for _, change := range changes {
+		if change.Action != v1.SettingsActionUnchanged && change.Error == "" {
+			return true
+		}
+	}
+	return false
+}
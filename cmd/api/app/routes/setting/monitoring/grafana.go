@@ -0,0 +1,366 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	monitoringConfigMapName = "ml-platform-admin-configmap"
+	monitoringConfigKey     = "monitoring"
+	grafanaRequestTimeout   = 10 * time.Second
+)
+
+// UpdateGrafanaRequest is the request body for updating a Grafana configuration.
+// Empty fields keep their current value.
+type UpdateGrafanaRequest struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint" binding:"omitempty,url"`
+	Token    string `json:"token"`
+}
+
+// TestGrafanaRequest optionally overrides the stored type, endpoint and token, which
+// allows a configuration to be verified before it is saved. Another endpoint needs its
+// own token, the stored one is only sent to the stored endpoint.
+type TestGrafanaRequest struct {
+	Type     string `json:"type" binding:"omitempty,oneof=grafana prometheus"`
+	Endpoint string `json:"endpoint" binding:"omitempty,url"`
+	Token    string `json:"token"`
+}
+
+// GrafanaTestResult describes the outcome of a Grafana connectivity test.
+type GrafanaTestResult struct {
+	Reachable       bool   `json:"reachable"`
+	Authenticated   bool   `json:"authenticated"`
+	Version         string `json:"version,omitempty"`
+	Database        string `json:"database,omitempty"`
+	DatasourceCount int    `json:"datasourceCount"`
+	Message         string `json:"message,omitempty"`
+}
+
+// loadMonitoringConfig returns the dashboard configmap together with its parsed monitoring section.
+func loadMonitoringConfig(ctx context.Context) (*corev1.ConfigMap, *MonitoringConfig, error) {
+	kubeClient := client.InClusterClient()
+	configMap, err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, monitoringConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	monitoringConfig := &MonitoringConfig{}
+	if data, ok := configMap.Data[monitoringConfigKey]; ok && data != "" {
+		if err := yaml.Unmarshal([]byte(data), monitoringConfig); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse monitoring config: %w", err)
+		}
+	}
+	return configMap, monitoringConfig, nil
+}
+
+// saveMonitoringConfig writes the monitoring section back into the dashboard configmap.
+func saveMonitoringConfig(ctx context.Context, configMap *corev1.ConfigMap, monitoringConfig *MonitoringConfig) error {
+	yamlBytes, err := yaml.Marshal(monitoringConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal monitoring config: %w", err)
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[monitoringConfigKey] = string(yamlBytes)
+
+	kubeClient := client.InClusterClient()
+	_, err = kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// findMonitoringSource returns the index of the monitoring entry with the given name, or -1.
+func findMonitoringSource(monitoringConfig *MonitoringConfig, name string) int {
+	for i := range monitoringConfig.Monitorings {
+		if monitoringConfig.Monitorings[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// relabelMonitoringTokenSecret updates the name label of a monitoring token secret.
+func relabelMonitoringTokenSecret(ctx context.Context, secretName, name string) {
+	kubeClient := client.InClusterClient()
+	secret, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to get monitoring token secret", "secret", secretName)
+		return
+	}
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+	secret.Labels["grafana.karmada.io/name"] = formatLabelValue(name)
+	if _, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to relabel monitoring token secret", "secret", secretName)
+	}
+}
+
+// checkGrafanaConnectivity calls the Grafana health endpoint and the datasource API
+// to verify that the endpoint is reachable and that the token is accepted.
+func checkGrafanaConnectivity(ctx context.Context, endpoint, token string) GrafanaTestResult {
+	result := GrafanaTestResult{}
+	endpoint = strings.TrimRight(endpoint, "/")
+	httpClient := &http.Client{Timeout: grafanaRequestTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/health", endpoint), nil)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		result.Message = fmt.Sprintf("grafana is not reachable: %v", err)
+		return result
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = fmt.Sprintf("grafana health check returned %s", resp.Status)
+		return result
+	}
+	result.Reachable = true
+
+	var health struct {
+		Database string `json:"database"`
+		Version  string `json:"version"`
+	}
+	if err := json.Unmarshal(body, &health); err == nil {
+		result.Version = health.Version
+		result.Database = health.Database
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/datasources", endpoint), nil)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	resp, err = httpClient.Do(req)
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to query grafana datasources: %v", err)
+		return result
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = fmt.Sprintf("grafana rejected the token: %s", resp.Status)
+		return result
+	}
+	result.Authenticated = true
+
+	var datasources []map[string]interface{}
+	if err := json.Unmarshal(body, &datasources); err == nil {
+		result.DatasourceCount = len(datasources)
+	}
+	return result
+}
+
+func handleUpdateMonitoring(c *gin.Context) {
+	name := c.Param("name")
+	var req UpdateGrafanaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	configMap, monitoringConfig, err := loadMonitoringConfig(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to load monitoring config")
+		common.Fail(c, err)
+		return
+	}
+
+	idx := findMonitoringSource(monitoringConfig, name)
+	if idx < 0 {
+		common.Fail(c, fmt.Errorf("monitoring '%s' not found", name))
+		return
+	}
+	monitoring := &monitoringConfig.Monitorings[idx]
+
+	newName := strings.TrimSpace(req.Name)
+	newEndpoint := strings.TrimRight(req.Endpoint, "/")
+	for i, m := range monitoringConfig.Monitorings {
+		if i == idx {
+			continue
+		}
+		if newName != "" && m.Name == newName {
+			common.Fail(c, fmt.Errorf("monitoring configuration with name '%s' already exists", newName))
+			return
+		}
+		if newEndpoint != "" && strings.TrimRight(m.Endpoint, "/") == newEndpoint {
+			common.Fail(c, fmt.Errorf("monitoring configuration with endpoint '%s' already exists", newEndpoint))
+			return
+		}
+	}
+
+	if strings.TrimSpace(req.Token) != "" && monitoring.Token == "" {
+		secretName, err := createMonitoringTokenSecret(c, monitoring.Type, monitoring.Name, req.Token)
+		if err != nil {
+			klog.ErrorS(err, "Failed to create monitoring token secret", "name", name)
+			common.Fail(c, err)
+			return
+		}
+		monitoring.Token = secretName
+	} else if strings.TrimSpace(req.Token) != "" {
+		if err := writeMonitoringToken(c, monitoring.Token, req.Token); err != nil {
+			klog.ErrorS(err, "Failed to update monitoring token secret", "name", name, "secret", monitoring.Token)
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	if newName != "" && newName != monitoring.Name {
+		if monitoring.Token != "" {
+			// Keep the secret label in sync so label based cleanup keeps working
+			relabelMonitoringTokenSecret(c, monitoring.Token, newName)
+		}
+		monitoring.Name = newName
+	}
+	if newEndpoint != "" {
+		monitoring.Endpoint = newEndpoint
+	}
+
+	if err := saveMonitoringConfig(c, configMap, monitoringConfig); err != nil {
+		klog.ErrorS(err, "Failed to update ml-platform-admin-configmap")
+		common.Fail(c, err)
+		return
+	}
+
+	common.Success(c, MonitoringResponse{
+		Name:     monitoring.Name,
+		Type:     monitoring.Type,
+		Endpoint: monitoring.Endpoint,
+		Cluster:  monitoring.Cluster,
+		HasToken: monitoring.Token != "",
+	})
+}
+
+func handleDeleteMonitoringByName(c *gin.Context) {
+	name := c.Param("name")
+
+	configMap, monitoringConfig, err := loadMonitoringConfig(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to load monitoring config")
+		common.Fail(c, err)
+		return
+	}
+
+	idx := findMonitoringSource(monitoringConfig, name)
+	if idx < 0 {
+		common.Fail(c, fmt.Errorf("monitoring '%s' not found", name))
+		return
+	}
+	secretName := monitoringConfig.Monitorings[idx].Token
+	monitoringConfig.Monitorings = append(monitoringConfig.Monitorings[:idx], monitoringConfig.Monitorings[idx+1:]...)
+
+	if err := saveMonitoringConfig(c, configMap, monitoringConfig); err != nil {
+		klog.ErrorS(err, "Failed to update ml-platform-admin-configmap")
+		common.Fail(c, err)
+		return
+	}
+
+	if secretName != "" {
+		kubeClient := client.InClusterClient()
+		err = kubeClient.CoreV1().Secrets(config.GetNamespace()).Delete(c, secretName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete monitoring token secret", "secretName", secretName)
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	common.Success(c, gin.H{"message": "Monitoring configuration deleted successfully"})
+}
+
+func handleTestMonitoring(c *gin.Context) {
+	name := c.Param("name")
+	var req TestGrafanaRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			common.FailWithBindError(c, err)
+			return
+		}
+	}
+
+	monitoringType := req.Type
+	if monitoringType == "" {
+		monitoringType = "grafana"
+	}
+	endpoint := req.Endpoint
+	token := req.Token
+	if endpoint == "" || (token == "" && monitoringType == "grafana") {
+		_, monitoringConfig, err := loadMonitoringConfig(c)
+		if err != nil {
+			klog.ErrorS(err, "Failed to load monitoring config")
+			common.Fail(c, err)
+			return
+		}
+		idx := findMonitoringSource(monitoringConfig, name)
+		if idx < 0 {
+			common.Fail(c, fmt.Errorf("monitoring '%s' not found", name))
+			return
+		}
+		monitoring := monitoringConfig.Monitorings[idx]
+		monitoringType = monitoring.Type
+		if endpoint == "" {
+			endpoint = monitoring.Endpoint
+		}
+		if token == "" && monitoring.Token != "" {
+			// The stored token is only sent to the configured endpoint, never to one given by the caller
+			if endpoint != monitoring.Endpoint {
+				common.FailWithStatus(c, fmt.Errorf("a token is required to test an endpoint other than the configured one"), http.StatusBadRequest)
+				return
+			}
+			token, err = GetMonitoringToken(c, monitoring.Token)
+			if err != nil {
+				klog.ErrorS(err, "Failed to read monitoring token", "name", name)
+				common.Fail(c, err)
+				return
+			}
+		}
+	}
+
+	switch monitoringType {
+	case "grafana":
+		common.Success(c, checkGrafanaConnectivity(c, endpoint, token))
+	case "prometheus":
+		common.Success(c, checkPrometheusConnectivity(c, endpoint, token))
+	default:
+		common.Fail(c, fmt.Errorf("monitoring type '%s' does not support connectivity tests", monitoringType))
+	}
+}
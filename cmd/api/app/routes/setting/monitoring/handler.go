@@ -18,7 +18,6 @@ package monitoring
 
 import (
 	"crypto/rand"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -77,13 +76,18 @@ type GrafanaConfig struct {
 	Token    string `json:"token" binding:"required"`
 }
 
+// MonitoringSource is a single monitoring entry stored in the dashboard configmap.
+// Token holds the name of the secret that contains the API token, not the token itself.
+type MonitoringSource struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Endpoint string `yaml:"endpoint"`
+	Token    string `yaml:"token"`
+	Cluster  string `yaml:"cluster,omitempty"`
+}
+
 type MonitoringConfig struct {
-	Monitorings []struct {
-		Name     string `yaml:"name"`
-		Type     string `yaml:"type"`
-		Endpoint string `yaml:"endpoint"`
-		Token    string `yaml:"token"`
-	} `yaml:"monitorings"`
+	Monitorings []MonitoringSource `yaml:"monitorings"`
 }
 
 // formatLabelValue formats a string to be valid as a Kubernetes label value
@@ -114,7 +118,7 @@ func isAlphanumeric(r rune) bool {
 func handleAddGrafana(c *gin.Context) {
 	var grafanaConfig GrafanaConfig
 	if err := c.ShouldBindJSON(&grafanaConfig); err != nil {
-		common.Fail(c, fmt.Errorf("invalid request body: %w", err))
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -164,10 +168,8 @@ func handleAddGrafana(c *gin.Context) {
 			},
 		},
 		Type: corev1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"token": []byte(base64.StdEncoding.EncodeToString([]byte(grafanaConfig.Token))),
-		},
 	}
+	setSecretToken(secret, grafanaConfig.Token)
 
 	_, err = kubeClient.CoreV1().Secrets(config.GetNamespace()).Create(c, secret, metav1.CreateOptions{})
 	if err != nil {
@@ -229,12 +231,7 @@ func handleAddGrafana(c *gin.Context) {
 		}
 
 		// Add new monitoring entry
-		monitoringConfig.Monitorings = append(monitoringConfig.Monitorings, struct {
-			Name     string `yaml:"name"`
-			Type     string `yaml:"type"`
-			Endpoint string `yaml:"endpoint"`
-			Token    string `yaml:"token"`
-		}{
+		monitoringConfig.Monitorings = append(monitoringConfig.Monitorings, MonitoringSource{
 			Name:     grafanaConfig.Name,
 			Type:     "grafana",
 			Endpoint: grafanaConfig.Endpoint,
@@ -242,12 +239,7 @@ func handleAddGrafana(c *gin.Context) {
 		})
 	} else {
 		// Create new monitoring config
-		monitoringConfig.Monitorings = []struct {
-			Name     string `yaml:"name"`
-			Type     string `yaml:"type"`
-			Endpoint string `yaml:"endpoint"`
-			Token    string `yaml:"token"`
-		}{
+		monitoringConfig.Monitorings = []MonitoringSource{
 			{
 				Name:     grafanaConfig.Name,
 				Type:     "grafana",
@@ -278,12 +270,14 @@ func handleAddGrafana(c *gin.Context) {
 	})
 }
 
-// MonitoringResponse represents a Grafana monitoring configuration
+// MonitoringResponse represents a monitoring configuration
 type MonitoringResponse struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`
 	Endpoint string `json:"endpoint"`
-	Token    string `json:"token,omitempty"` // omitempty to avoid exposing token in logs
+	Cluster  string `json:"cluster,omitempty"`
+	// HasToken tells whether a token is stored, the token itself is never returned
+	HasToken bool `json:"hasToken"`
 }
 
 func handleGetMonitoring(c *gin.Context) {
@@ -315,45 +309,16 @@ func handleGetMonitoring(c *gin.Context) {
 		return
 	}
 
-	// Get tokens from secrets
 	response := make([]MonitoringResponse, 0, len(monitoringConfig.Monitorings))
 	for _, monitoring := range monitoringConfig.Monitorings {
-		// First add basic info without token
-		monitoringResponse := MonitoringResponse{
+		response = append(response, MonitoringResponse{
 			Name:     monitoring.Name,
 			Type:     monitoring.Type,
 			Endpoint: monitoring.Endpoint,
-		}
-
-		// Try to get token from secret
-		secret, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Get(c, monitoring.Token, metav1.GetOptions{})
-		if err != nil {
-			klog.ErrorS(err, "Failed to get monitoring secret", "name", monitoring.Name, "secret", monitoring.Token)
-			// Still include the monitoring entry but without token
-			response = append(response, monitoringResponse)
-			continue
-		}
-
-		// Decode token
-		tokenBytes, ok := secret.Data["token"]
-		if !ok {
-			klog.ErrorS(nil, "Token not found in secret", "name", monitoring.Name, "secret", monitoring.Token)
-			// Still include the monitoring entry but without token
-			response = append(response, monitoringResponse)
-			continue
-		}
-
-		tokenStr, err := base64.StdEncoding.DecodeString(string(tokenBytes))
-		if err != nil {
-			klog.ErrorS(err, "Failed to decode token", "name", monitoring.Name, "secret", monitoring.Token)
-			// Still include the monitoring entry but without token
-			response = append(response, monitoringResponse)
-			continue
-		}
-
-		// Add token to response
-		monitoringResponse.Token = string(tokenStr)
-		response = append(response, monitoringResponse)
+			Cluster:  monitoring.Cluster,
+			// Prometheus sources may be registered without a token
+			HasToken: monitoring.Token != "",
+		})
 	}
 
 	common.Success(c, gin.H{"monitorings": response})
@@ -403,12 +368,7 @@ func handleGetDashboards(c *gin.Context) {
 	}
 
 	// Find the monitoring entry
-	var monitoring *struct {
-		Name     string `yaml:"name"`
-		Type     string `yaml:"type"`
-		Endpoint string `yaml:"endpoint"`
-		Token    string `yaml:"token"`
-	}
+	var monitoring *MonitoringSource
 	for i := range monitoringConfig.Monitorings {
 		if monitoringConfig.Monitorings[i].Name == name {
 			monitoring = &monitoringConfig.Monitorings[i]
@@ -427,23 +387,9 @@ func handleGetDashboards(c *gin.Context) {
 	}
 
 	// Get token from secret
-	secret, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Get(c, monitoring.Token, metav1.GetOptions{})
-	if err != nil {
-		klog.ErrorS(err, "Failed to get monitoring secret", "name", name)
-		common.Fail(c, err)
-		return
-	}
-
-	// Decode token
-	tokenBytes, ok := secret.Data["token"]
-	if !ok {
-		klog.ErrorS(nil, "Token not found in secret", "name", name)
-		common.Fail(c, fmt.Errorf("token not found in secret"))
-		return
-	}
-	tokenStr, err := base64.StdEncoding.DecodeString(string(tokenBytes))
+	token, err := GetMonitoringToken(c, monitoring.Token)
 	if err != nil {
-		klog.ErrorS(err, "Failed to decode token", "name", name)
+		klog.ErrorS(err, "Failed to get monitoring token", "name", name)
 		common.Fail(c, err)
 		return
 	}
@@ -461,7 +407,7 @@ func handleGetDashboards(c *gin.Context) {
 	}
 
 	// Add headers
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tokenStr))
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 
 	// Send request
 	resp, err := client.Do(req)
@@ -533,12 +479,7 @@ func handleDeleteMonitoring(c *gin.Context) {
 
 	// Find and remove the monitoring config
 	found := false
-	updatedMonitorings := make([]struct {
-		Name     string `yaml:"name"`
-		Type     string `yaml:"type"`
-		Endpoint string `yaml:"endpoint"`
-		Token    string `yaml:"token"`
-	}, 0, len(monitoringConfig.Monitorings))
+	updatedMonitorings := make([]MonitoringSource, 0, len(monitoringConfig.Monitorings))
 
 	for _, m := range monitoringConfig.Monitorings {
 		if m.Name == name && strings.TrimRight(m.Endpoint, "/") == strings.TrimRight(endpoint, "/") {
@@ -599,4 +540,16 @@ func init() {
 	r.GET("/setting/monitoring", handleGetMonitoring)
 	r.GET("/setting/monitoring/:name/dashboards", handleGetDashboards)
 	r.DELETE("/setting/monitoring/source/:name", handleDeleteMonitoring)
+	r.PUT("/setting/monitoring/:name", router.EnsureMgmtAdminMiddleware(), handleUpdateMonitoring)
+	r.DELETE("/setting/monitoring/:name", router.EnsureMgmtAdminMiddleware(), handleDeleteMonitoringByName)
+	r.POST("/setting/monitoring/:name/test", router.EnsureMgmtAdminMiddleware(), handleTestMonitoring)
+	// Reads through the proxy, including the query requests Grafana sends as POST, are open to every user
+	r.GET("/setting/monitoring/:name/proxy/*path", ensureGrafanaProxyAccess(), handleGrafanaProxy)
+	r.HEAD("/setting/monitoring/:name/proxy/*path", ensureGrafanaProxyAccess(), handleGrafanaProxy)
+	r.POST("/setting/monitoring/:name/proxy/*path", ensureGrafanaProxyAccess(), handleGrafanaProxy)
+	r.PUT("/setting/monitoring/:name/proxy/*path", ensureGrafanaProxyAccess(), handleGrafanaProxy)
+	r.PATCH("/setting/monitoring/:name/proxy/*path", ensureGrafanaProxyAccess(), handleGrafanaProxy)
+	r.DELETE("/setting/monitoring/:name/proxy/*path", ensureGrafanaProxyAccess(), handleGrafanaProxy)
+	r.POST("/setting/monitoring/prometheus", handleAddPrometheus)
+	r.GET("/monitoring/:name/query", handlePrometheusQuery)
 }
@@ -0,0 +1,409 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// PrometheusConfig is the request body for registering a Prometheus endpoint.
+// Cluster names the member cluster whose metrics the endpoint serves.
+type PrometheusConfig struct {
+	Name     string `json:"name" binding:"required"`
+	Endpoint string `json:"endpoint" binding:"required,url"`
+	Cluster  string `json:"cluster" binding:"required"`
+	Token    string `json:"token"`
+}
+
+// createMonitoringTokenSecret stores the token in a new secret labeled with the
+// monitoring type and name, and returns the secret name.
+func createMonitoringTokenSecret(ctx context.Context, monitoringType, name, token string) (string, error) {
+	randomStr, err := generateRandomString(16)
+	if err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-token-%s", monitoringType, randomStr),
+			Namespace: config.GetNamespace(),
+			Labels: map[string]string{
+				"app.kubernetes.io/name":  monitoringType,
+				"grafana.karmada.io/name": formatLabelValue(name),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	setSecretToken(secret, token)
+	kubeClient := client.InClusterClient()
+	if _, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return "", err
+	}
+	return secret.Name, nil
+}
+
+func handleAddPrometheus(c *gin.Context) {
+	var prometheusConfig PrometheusConfig
+	if err := c.ShouldBindJSON(&prometheusConfig); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	prometheusConfig.Name = strings.TrimSpace(prometheusConfig.Name)
+	prometheusConfig.Endpoint = strings.TrimRight(prometheusConfig.Endpoint, "/")
+	if prometheusConfig.Name == "" {
+		common.Fail(c, fmt.Errorf("name cannot be empty"))
+		return
+	}
+
+	karmadaClient := client.InClusterKarmadaClient()
+	if _, err := karmadaClient.ClusterV1alpha1().Clusters().Get(c, prometheusConfig.Cluster, metav1.GetOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to get cluster for Prometheus registration", "cluster", prometheusConfig.Cluster)
+		common.Fail(c, fmt.Errorf("cluster '%s' not found: %w", prometheusConfig.Cluster, err))
+		return
+	}
+
+	configMap, monitoringConfig, err := loadMonitoringConfig(c)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to load monitoring config")
+			common.Fail(c, err)
+			return
+		}
+		kubeClient := client.InClusterClient()
+		configMap, err = kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Create(c, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      monitoringConfigMapName,
+				Namespace: config.GetNamespace(),
+			},
+			Data: make(map[string]string),
+		}, metav1.CreateOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to create ml-platform-admin-configmap")
+			common.Fail(c, err)
+			return
+		}
+		monitoringConfig = &MonitoringConfig{}
+	}
+
+	for _, m := range monitoringConfig.Monitorings {
+		if m.Name == prometheusConfig.Name {
+			common.Fail(c, fmt.Errorf("monitoring configuration with name '%s' already exists", prometheusConfig.Name))
+			return
+		}
+		if strings.TrimRight(m.Endpoint, "/") == prometheusConfig.Endpoint {
+			common.Fail(c, fmt.Errorf("monitoring configuration with endpoint '%s' already exists", prometheusConfig.Endpoint))
+			return
+		}
+	}
+
+	secretName := ""
+	if strings.TrimSpace(prometheusConfig.Token) != "" {
+		secretName, err = createMonitoringTokenSecret(c, "prometheus", prometheusConfig.Name, prometheusConfig.Token)
+		if err != nil {
+			klog.ErrorS(err, "Failed to create Prometheus token secret")
+			common.Fail(c, err)
+			return
+		}
+	}
+
+	monitoringConfig.Monitorings = append(monitoringConfig.Monitorings, MonitoringSource{
+		Name:     prometheusConfig.Name,
+		Type:     "prometheus",
+		Endpoint: prometheusConfig.Endpoint,
+		Token:    secretName,
+		Cluster:  prometheusConfig.Cluster,
+	})
+
+	if err := saveMonitoringConfig(c, configMap, monitoringConfig); err != nil {
+		klog.ErrorS(err, "Failed to update ml-platform-admin-configmap")
+		common.Fail(c, err)
+		return
+	}
+
+	common.Success(c, gin.H{
+		"message": "Prometheus configuration added successfully",
+	})
+}
+
+// checkPrometheusConnectivity calls the Prometheus build info API to verify that
+// the endpoint is reachable and that the optional token is accepted.
+func checkPrometheusConnectivity(ctx context.Context, endpoint, token string) GrafanaTestResult {
+	result := GrafanaTestResult{}
+	httpClient := &http.Client{Timeout: grafanaRequestTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/api/v1/status/buildinfo", strings.TrimRight(endpoint, "/")), nil)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	if token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		result.Message = fmt.Sprintf("prometheus is not reachable: %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	result.Reachable = true
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		result.Message = fmt.Sprintf("prometheus rejected the token: %s", resp.Status)
+		return result
+	}
+	if resp.StatusCode != http.StatusOK {
+		result.Message = fmt.Sprintf("prometheus build info returned %s", resp.Status)
+		return result
+	}
+	result.Authenticated = true
+
+	var buildInfo struct {
+		Data struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	if body, err := io.ReadAll(resp.Body); err == nil && json.Unmarshal(body, &buildInfo) == nil {
+		result.Version = buildInfo.Data.Version
+	}
+	return result
+}
+
+// handlePrometheusQuery passes an instant or range PromQL query through to the
+// named Prometheus endpoint. A range query is issued when start and end are set.
+func handlePrometheusQuery(c *gin.Context) {
+	name := c.Param("name")
+	query := c.Query("query")
+	if query == "" {
+		common.Fail(c, fmt.Errorf("query parameter is required"))
+		return
+	}
+
+	_, monitoringConfig, err := loadMonitoringConfig(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to load monitoring config")
+		common.Fail(c, err)
+		return
+	}
+	idx := findMonitoringSource(monitoringConfig, name)
+	if idx < 0 {
+		common.Fail(c, fmt.Errorf("monitoring '%s' not found", name))
+		return
+	}
+	monitoring := monitoringConfig.Monitorings[idx]
+	if monitoring.Type != "prometheus" {
+		common.Fail(c, fmt.Errorf("monitoring type '%s' does not support queries", monitoring.Type))
+		return
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	apiPath := "/api/v1/query"
+	if start, end := c.Query("start"), c.Query("end"); start != "" && end != "" {
+		apiPath = "/api/v1/query_range"
+		params.Set("start", start)
+		params.Set("end", end)
+		params.Set("step", c.DefaultQuery("step", "60"))
+	} else if ts := c.Query("time"); ts != "" {
+		params.Set("time", ts)
+	}
+
+	data, err := queryPrometheus(c, monitoring, apiPath, params)
+	if err != nil {
+		klog.ErrorS(err, "Failed to query Prometheus", "name", name)
+		common.Fail(c, err)
+		return
+	}
+
+	common.Success(c, gin.H{
+		"cluster": monitoring.Cluster,
+		"result":  data,
+	})
+}
+
+// queryPrometheus calls a query API of a Prometheus monitoring source and returns the data of the response
+func queryPrometheus(ctx context.Context, monitoring MonitoringSource, apiPath string, params url.Values) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s%s?%s", strings.TrimRight(monitoring.Endpoint, "/"), apiPath, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	if monitoring.Token != "" {
+		token, err := GetMonitoringToken(ctx, monitoring.Token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read monitoring token: %w", err)
+		}
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	httpClient := &http.Client{Timeout: grafanaRequestTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType string          `json:"errorType,omitempty"`
+		Error     string          `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		klog.ErrorS(err, "Failed to parse Prometheus response", "name", monitoring.Name, "status", resp.Status)
+		return nil, fmt.Errorf("prometheus returned %s", resp.Status)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", result.Error)
+	}
+	return result.Data, nil
+}
+
+// PrometheusSample is a sample of an instant query result
+type PrometheusSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// ErrNoPrometheus is returned when no Prometheus endpoint is registered for a cluster
+var ErrNoPrometheus = errors.New("no Prometheus endpoint registered for the cluster")
+
+// QueryClusterPrometheus runs an instant query at the given time against the Prometheus endpoint
+// registered for a cluster and returns the samples of the resulting vector.
+func QueryClusterPrometheus(ctx context.Context, cluster, query string, at time.Time) ([]PrometheusSample, error) {
+	source, err := clusterPrometheus(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("time", strconv.FormatInt(at.Unix(), 10))
+	data, err := queryPrometheus(ctx, *source, "/api/v1/query", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var vector struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &vector); err != nil {
+		return nil, fmt.Errorf("failed to parse Prometheus result: %w", err)
+	}
+	if vector.ResultType != "vector" {
+		return nil, fmt.Errorf("expected a vector result, got %s", vector.ResultType)
+	}
+	samples := make([]PrometheusSample, 0, len(vector.Result))
+	for _, r := range vector.Result {
+		// Prometheus encodes sample values as strings
+		raw, _ := r.Value[1].(string)
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, PrometheusSample{Labels: r.Metric, Value: value})
+	}
+	return samples, nil
+}
+
+// clusterPrometheus returns the Prometheus endpoint registered for a cluster
+func clusterPrometheus(ctx context.Context, cluster string) (*MonitoringSource, error) {
+	_, monitoringConfig, err := loadMonitoringConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range monitoringConfig.Monitorings {
+		if m := &monitoringConfig.Monitorings[i]; m.Type == "prometheus" && m.Cluster == cluster {
+			return m, nil
+		}
+	}
+	return nil, ErrNoPrometheus
+}
+
+// QueryClusterPrometheusRange runs a range query against the Prometheus endpoint registered for a cluster and
+// returns the values of the resulting matrix by time, summed over its series
+func QueryClusterPrometheusRange(ctx context.Context, cluster, query string, start, end time.Time, step time.Duration) (map[time.Time]float64, error) {
+	source, err := clusterPrometheus(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", strconv.FormatInt(int64(step.Seconds()), 10))
+	data, err := queryPrometheus(ctx, *source, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var matrix struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &matrix); err != nil {
+		return nil, fmt.Errorf("failed to parse Prometheus result: %w", err)
+	}
+	if matrix.ResultType != "matrix" {
+		return nil, fmt.Errorf("expected a matrix result, got %s", matrix.ResultType)
+	}
+	values := map[time.Time]float64{}
+	for _, series := range matrix.Result {
+		for _, v := range series.Values {
+			// Timestamps are seconds as numbers, values strings
+			seconds, _ := v[0].(float64)
+			raw, _ := v[1].(string)
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			values[time.Unix(int64(seconds), 0)] += value
+		}
+	}
+	return values, nil
+}
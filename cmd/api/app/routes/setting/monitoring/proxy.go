@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+)
+
+// proxyStrippedHeaders are request headers that belong to the dashboard session
+// and must not be forwarded to Grafana.
+var proxyStrippedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"X-Grafana-Org-Id",
+}
+
+// proxyReadRequests are the Grafana API paths that only read data although their method is not GET,
+// such as the queries of dashboard panels. Paths ending in a slash match every path below them.
+var proxyReadRequests = map[string][]string{
+	http.MethodPost: {
+		"/api/ds/query",
+		"/api/datasources/proxy/",
+	},
+}
+
+// grafanaProxyPath returns the cleaned Grafana path of a proxied request
+func grafanaProxyPath(c *gin.Context) string {
+	return path.Clean("/" + c.Param("path"))
+}
+
+// isGrafanaProxyRead reports whether the request only reads from Grafana
+func isGrafanaProxyRead(method, proxyPath string) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return true
+	}
+	for _, allowed := range proxyReadRequests[method] {
+		if proxyPath == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(proxyPath, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureGrafanaProxyAccess lets every user read through the proxy and limits anything that can change
+// Grafana with the stored service token to administrators.
+func ensureGrafanaProxyAccess() gin.HandlerFunc {
+	ensureAdmin := router.EnsureMgmtAdminMiddleware()
+	return func(c *gin.Context) {
+		if isGrafanaProxyRead(c.Request.Method, grafanaProxyPath(c)) {
+			c.Next()
+			return
+		}
+		ensureAdmin(c)
+	}
+}
+
+// handleGrafanaProxy forwards the request to the configured Grafana endpoint and
+// injects the stored API token, so the token is never exposed to the browser.
+func handleGrafanaProxy(c *gin.Context) {
+	name := c.Param("name")
+
+	_, monitoringConfig, err := loadMonitoringConfig(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to load monitoring config")
+		common.Fail(c, err)
+		return
+	}
+	idx := findMonitoringSource(monitoringConfig, name)
+	if idx < 0 {
+		common.Fail(c, fmt.Errorf("monitoring '%s' not found", name))
+		return
+	}
+	monitoring := monitoringConfig.Monitorings[idx]
+	if monitoring.Type != "grafana" {
+		common.Fail(c, fmt.Errorf("monitoring type '%s' does not support proxying", monitoring.Type))
+		return
+	}
+
+	target, err := url.Parse(strings.TrimRight(monitoring.Endpoint, "/"))
+	if err != nil {
+		klog.ErrorS(err, "Invalid Grafana endpoint", "name", name, "endpoint", monitoring.Endpoint)
+		common.Fail(c, err)
+		return
+	}
+
+	token, err := GetMonitoringToken(c, monitoring.Token)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read monitoring token", "name", name)
+		common.Fail(c, err)
+		return
+	}
+
+	proxyPath := grafanaProxyPath(c)
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = target.Path + proxyPath
+			req.URL.RawPath = ""
+			req.Host = target.Host
+			for _, header := range proxyStrippedHeaders {
+				req.Header.Del(header)
+			}
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			// Grafana session cookies are scoped to the Grafana host and must not leak to the dashboard origin
+			resp.Header.Del("Set-Cookie")
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			klog.ErrorS(err, "Grafana proxy request failed", "name", name, "path", proxyPath)
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(fmt.Sprintf("grafana proxy error: %v", err)))
+		},
+	}
+
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
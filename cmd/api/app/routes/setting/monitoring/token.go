@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	// tokenEncodingAnnotation marks secrets whose token is stored as raw bytes.
+	// Secrets without it were written by older versions, which base64-encoded
+	// the token before Kubernetes encoded it again.
+	tokenEncodingAnnotation = "monitoring.karmada.io/token-encoding"
+	tokenEncodingRaw        = "raw"
+)
+
+// GetMonitoringToken returns the API token stored in the given monitoring secret.
+// Secrets that have not been migrated yet are decoded transparently.
+func GetMonitoringToken(ctx context.Context, secretName string) (string, error) {
+	if secretName == "" {
+		return "", nil
+	}
+	kubeClient := client.InClusterClient()
+	secret, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	tokenBytes, ok := secret.Data["token"]
+	if !ok {
+		return "", fmt.Errorf("token not found in secret %s", secretName)
+	}
+	if secret.Annotations[tokenEncodingAnnotation] == tokenEncodingRaw {
+		return string(tokenBytes), nil
+	}
+	token, err := base64.StdEncoding.DecodeString(string(tokenBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token: %w", err)
+	}
+	return string(token), nil
+}
+
+// setSecretToken stores the raw token in the secret and marks it as migrated.
+func setSecretToken(secret *corev1.Secret, token string) {
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Data["token"] = []byte(token)
+	secret.Annotations[tokenEncodingAnnotation] = tokenEncodingRaw
+}
+
+// writeMonitoringToken replaces the API token stored in the given secret.
+func writeMonitoringToken(ctx context.Context, secretName, token string) error {
+	kubeClient := client.InClusterClient()
+	secret, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	setSecretToken(secret, token)
+	_, err = kubeClient.CoreV1().Secrets(config.GetNamespace()).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// MigrateTokenSecrets rewrites monitoring token secrets created by older versions
+// so that they hold the raw token instead of a base64 encoded copy of it.
+func MigrateTokenSecrets(ctx context.Context, kubeClient kubernetes.Interface) error {
+	secrets, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/name in (grafana,prometheus)",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list monitoring token secrets: %w", err)
+	}
+
+	migrated := 0
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Annotations[tokenEncodingAnnotation] == tokenEncodingRaw {
+			continue
+		}
+		tokenBytes, ok := secret.Data["token"]
+		if !ok {
+			continue
+		}
+		token, err := base64.StdEncoding.DecodeString(string(tokenBytes))
+		if err != nil {
+			klog.ErrorS(err, "Skipping monitoring token secret that is not base64 encoded", "secret", secret.Name)
+			continue
+		}
+		setSecretToken(secret, string(token))
+		if _, err := kubeClient.CoreV1().Secrets(config.GetNamespace()).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			klog.ErrorS(err, "Failed to migrate monitoring token secret", "secret", secret.Name)
+			continue
+		}
+		migrated++
+	}
+
+	klog.InfoS("Monitoring token secret migration finished", "migrated", migrated, "total", len(secrets.Items))
+	return nil
+}
@@ -113,7 +113,7 @@ func handlePostUserSetting(c *gin.Context) {
 	userSettingRequest := new(v1.UserSettingRequest)
 	if err := c.ShouldBind(userSettingRequest); err != nil {
 		klog.ErrorS(err, "Could not read user setting request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -137,7 +137,7 @@ func handlePutUserSetting(c *gin.Context) {
 	userSettingRequest := new(v1.UserSettingRequest)
 	if err := c.ShouldBind(userSettingRequest); err != nil {
 		klog.ErrorS(err, "Could not read user setting request")
-		common.Fail(c, err)
+		common.FailWithBindError(c, err)
 		return
 	}
 
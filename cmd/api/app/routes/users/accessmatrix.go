@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// handleGetAccessMatrix returns the effective relation of every user on every member cluster, owner, member
+// or none, with how it was derived: a direct tuple, a project the cluster belongs to, or the admin role.
+// The matrix is evaluated from the tuples of the store rather than a check per user and cluster. Users are
+// the ones of Keycloak, or of etcd without it, and the ones related to anything in the store. Query parameters user and cluster restrict
+// the matrix to one user or cluster, relation and source to the cells with that relation or source.
+func handleGetAccessMatrix(c *gin.Context) {
+	filter := fga.AccessMatrixFilter{
+		User:     c.Query("user"),
+		Cluster:  c.Query("cluster"),
+		Relation: c.Query("relation"),
+		Source:   c.Query("source"),
+	}
+	switch filter.Relation {
+	case "", "owner", "member", fga.RelationNone:
+	default:
+		common.FailWithStatus(c, fmt.Errorf("relation must be owner, member or %s", fga.RelationNone), http.StatusBadRequest)
+		return
+	}
+	switch filter.Source {
+	case "", fga.SourceDirect, fga.SourceProject, fga.SourceAdmin:
+	default:
+		common.FailWithStatus(c, fmt.Errorf("source must be %s, %s or %s", fga.SourceDirect, fga.SourceProject, fga.SourceAdmin), http.StatusBadRequest)
+		return
+	}
+	fgaService := fga.FGAService
+	if fgaService == nil {
+		common.FailWithStatus(c, fmt.Errorf("OpenFGA is not configured"), http.StatusServiceUnavailable)
+		return
+	}
+
+	tuples, err := fgaService.GetClient().ReadTuples(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read OpenFGA tuples for the access matrix")
+		common.Fail(c, err)
+		return
+	}
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(c, metav1.ListOptions{})
+	if err != nil {
+		common.Fail(c, fmt.Errorf("failed to list clusters: %v", err))
+		return
+	}
+	clusterNames := make([]string, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	usernames, err := listUsernames(c)
+	if err != nil {
+		// The users related to anything in the store are still listed
+		klog.ErrorS(err, "Failed to list users for the access matrix")
+	}
+
+	common.Success(c, fga.BuildAccessMatrix(tuples, usernames, clusterNames, filter))
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/access-matrix", router.EnsureMgmtAdminMiddleware(), handleGetAccessMatrix)
+}
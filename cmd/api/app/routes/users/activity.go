@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// resolveUsername returns the username of a Keycloak user ID. Without Keycloak users are identified by their
+// username.
+func resolveUsername(ctx context.Context, c *gin.Context, userID string) (string, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		return userID, nil
+	}
+	adminToken, err := getAdminToken(ctx, kc, client.GetBearerToken(c.Request))
+	if err != nil {
+		return "", err
+	}
+	config := kc.GetConfig()
+	u, err := gocloak.NewClient(config.URL).GetUserByID(ctx, adminToken, config.Realm, userID)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %v", err)
+	}
+	return getStringValue(u.Username), nil
+}
+
+// handleGetUserActivity returns the recent changes made through the API by a user, as themselves or
+// impersonating another user, and the changes that affected them, such as role changes, newest first.
+// Users can read their own activity, admins the activity of every user.
+func handleGetUserActivity(c *gin.Context) {
+	userID := c.Param("id")
+	limit, err := audit.ParseFeedLimit(c.Query("limit"))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	username, err := resolveUsername(c.Request.Context(), c, userID)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusNotFound)
+		return
+	}
+	if username != utilauth.GetAuthenticatedUser(c) && !router.IsDashboardAdmin(c) {
+		common.FailWithStatus(c, fmt.Errorf("administrator permissions required to read the activity of other users"), http.StatusForbidden)
+		return
+	}
+
+	entries, err := audit.Entries(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, audit.Filter(entries, func(e audit.Entry) bool {
+		return e.User == username || e.Impersonator == username || e.Subject == userID || e.Subject == username
+	}, limit))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth"
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/resource/project"
+)
+
+// ConsistencyReport lists the tuples of the store referencing users, clusters or projects that do not exist
+type ConsistencyReport struct {
+	CheckedAt string              `json:"checkedAt"`
+	Tuples    int                 `json:"tuples"`
+	Orphaned  []fga.OrphanedTuple `json:"orphaned"`
+	// Skipped lists the kinds of references that could not be checked
+	Skipped []string `json:"skipped"`
+}
+
+// usernamesPageSize is the number of Keycloak users read per request
+const usernamesPageSize = 100
+
+// listUsernames returns the usernames of the Keycloak realm, or of etcd without Keycloak. Keycloak users
+// are only listed with a service account token, since there may be no user token outside of a request.
+// All the users are listed, so that a missing user is really missing.
+func listUsernames(ctx context.Context) ([]string, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		userManager := auth.GetUserManager()
+		if userManager == nil {
+			return nil, fmt.Errorf("user manager is not initialized")
+		}
+		users, err := userManager.ListUsers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(users))
+		for _, u := range users {
+			names = append(names, u.Username)
+		}
+		return names, nil
+	}
+	adminToken, err := kc.GetAdminToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if adminToken == "" {
+		return nil, fmt.Errorf("no Keycloak service account token, KEYCLOAK_CLIENT_SECRET is not set")
+	}
+	config := kc.GetConfig()
+	gocloakClient := gocloak.NewClient(config.URL)
+	var names []string
+	for first := 0; ; first += usernamesPageSize {
+		users, err := gocloakClient.GetUsers(ctx, adminToken, config.Realm, gocloak.GetUsersParams{
+			First: gocloak.IntP(first),
+			Max:   gocloak.IntP(usernamesPageSize),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			names = append(names, getStringValue(u.Username))
+		}
+		if len(users) < usernamesPageSize {
+			return names, nil
+		}
+	}
+}
+
+// requireFGAClient returns the OpenFGA client, or fails the request when OpenFGA is not configured
+func requireFGAClient(c *gin.Context) (fga.Client, bool) {
+	if fga.FGAService == nil {
+		common.FailWithStatus(c, fmt.Errorf("OpenFGA is not configured"), http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return fga.FGAService.GetClient(), true
+}
+
+// handleExportFGA returns the authorization model and tuples of the OpenFGA store as a downloadable file
+func handleExportFGA(c *gin.Context) {
+	fgaClient, ok := requireFGAClient(c)
+	if !ok {
+		return
+	}
+	export, err := fga.Export(c, fgaClient)
+	if err != nil {
+		klog.ErrorS(err, "Failed to export the OpenFGA store")
+		common.Fail(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="fga-store-%s.json"`, time.Now().Format("20060102")))
+	c.JSON(http.StatusOK, export)
+}
+
+// handleImportFGA writes the authorization model and the missing tuples of an export into the OpenFGA
+// store, e.g. a fresh store after losing OpenFGA. dryRun=true reports what would be written.
+func handleImportFGA(c *gin.Context) {
+	var export fga.StoreExport
+	if err := c.ShouldBindJSON(&export); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	for _, tuple := range export.Tuples {
+		if tuple.User == "" || tuple.ObjectType == "" || tuple.ObjectID == "" || tuple.Relation == "" {
+			common.FailWithStatus(c, fmt.Errorf("tuples must have a user, relation, objectType and objectId"), http.StatusBadRequest)
+			return
+		}
+	}
+	fgaClient, ok := requireFGAClient(c)
+	if !ok {
+		return
+	}
+	dryRun := c.Query("dryRun") == "true"
+	result, err := fga.Import(c, fgaClient, export, dryRun)
+	if err != nil {
+		klog.ErrorS(err, "Failed to import the OpenFGA store")
+		common.Fail(c, err)
+		return
+	}
+	klog.InfoS("Imported OpenFGA store", "dryRun", dryRun, "modelWritten", result.ModelWritten,
+		"written", len(result.Written), "existing", result.Existing, "errors", len(result.Errors))
+	common.Success(c, result)
+}
+
+// handleCheckFGAConsistency reports the tuples of the OpenFGA store referencing deleted users, clusters or
+// projects. References that cannot be checked, e.g. because the users cannot be listed, are skipped.
+func handleCheckFGAConsistency(c *gin.Context) {
+	fgaClient, ok := requireFGAClient(c)
+	if !ok {
+		return
+	}
+	tuples, err := fgaClient.ReadTuples(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read OpenFGA tuples")
+		common.Fail(c, err)
+		return
+	}
+
+	report := ConsistencyReport{CheckedAt: time.Now().Format(time.RFC3339), Tuples: len(tuples), Skipped: []string{}}
+	usernames, err := listUsernames(c)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list users for the OpenFGA consistency check")
+		report.Skipped = append(report.Skipped, fmt.Sprintf("users: %v", err))
+	}
+	var clusterNames []string
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(c, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list clusters for the OpenFGA consistency check")
+		report.Skipped = append(report.Skipped, fmt.Sprintf("clusters: %v", err))
+	} else {
+		clusterNames = make([]string, 0, len(clusters.Items))
+		for _, cluster := range clusters.Items {
+			clusterNames = append(clusterNames, cluster.Name)
+		}
+	}
+	var projectNames []string
+	projects, err := project.List(c, client.InClusterClient())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list projects for the OpenFGA consistency check")
+		report.Skipped = append(report.Skipped, fmt.Sprintf("projects: %v", err))
+	} else {
+		projectNames = make([]string, 0, len(projects))
+		for _, p := range projects {
+			projectNames = append(projectNames, p.Name)
+		}
+	}
+
+	report.Orphaned = fga.FindOrphanedTuples(tuples, usernames, clusterNames, projectNames)
+	common.Success(c, report)
+}
+
+func init() {
+	r := router.V1()
+	fgaStore := r.Group("/fga", router.EnsureMgmtAdminMiddleware())
+	{
+		fgaStore.GET("/export", handleExportFGA)
+		fgaStore.POST("/import", handleImportFGA)
+		fgaStore.GET("/consistency", handleCheckFGAConsistency)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	platformv1 "github.com/karmada-io/dashboard/pkg/apis/platform/v1"
+)
+
+// userServer serves the users of the realm over gRPC with the Keycloak admin client of the REST handlers
+type userServer struct {
+	platformv1.UnimplementedUserServiceServer
+}
+
+// grpcUserError returns the gRPC status of an error managing a user
+func grpcUserError(err error) error {
+	var notFound *userNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		return router.GRPCErrorWithStatus(err, http.StatusNotFound)
+	case errors.Is(err, errKeycloakNotConfigured):
+		return router.GRPCErrorWithStatus(err, http.StatusServiceUnavailable)
+	}
+	return router.GRPCError(err)
+}
+
+// grpcKeycloakAdmin returns the Keycloak admin client acting on behalf of the caller of a gRPC call
+func grpcKeycloakAdmin(ctx context.Context) (*keycloakAdmin, error) {
+	admin, err := keycloakAdminFor(ctx, router.GRPCBearerToken(ctx))
+	if err != nil {
+		return nil, grpcUserError(err)
+	}
+	return admin, nil
+}
+
+// ListUsers lists the users of the realm
+func (s *userServer) ListUsers(ctx context.Context, _ *platformv1.ListUsersRequest) (*platformv1.ListUsersResponse, error) {
+	admin, err := grpcKeycloakAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := admin.listUsers(ctx)
+	if err != nil {
+		return nil, grpcUserError(err)
+	}
+	resp := &platformv1.ListUsersResponse{}
+	for _, user := range users {
+		resp.Users = append(resp.Users, userMessage(user))
+	}
+	return resp, nil
+}
+
+// GetUser returns a user of the realm
+func (s *userServer) GetUser(ctx context.Context, req *platformv1.GetUserRequest) (*platformv1.User, error) {
+	admin, err := grpcKeycloakAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := admin.getUser(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcUserError(err)
+	}
+	return userMessage(user), nil
+}
+
+// CreateUser creates a user in the realm and provisions their Kubeflow Profile
+func (s *userServer) CreateUser(ctx context.Context, req *platformv1.CreateUserRequest) (*platformv1.User, error) {
+	createReq := CreateUserRequest{
+		Username:      req.GetUsername(),
+		Email:         req.GetEmail(),
+		FirstName:     req.GetFirstName(),
+		LastName:      req.GetLastName(),
+		Password:      req.GetPassword(),
+		Enabled:       req.GetEnabled(),
+		EmailVerified: req.GetEmailVerified(),
+		Roles:         req.GetRoles(),
+	}
+	if err := binding.Validator.ValidateStruct(&createReq); err != nil {
+		return nil, router.GRPCErrorWithStatus(err, http.StatusBadRequest)
+	}
+
+	admin, err := grpcKeycloakAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, _, err := provisionUser(ctx, admin.client, admin.token, admin.realm, createReq, false)
+	if err != nil {
+		return nil, grpcUserError(err)
+	}
+	user, err := admin.getUser(ctx, userID)
+	if err != nil {
+		return nil, grpcUserError(err)
+	}
+	return userMessage(user), nil
+}
+
+// UpdateUser updates a user of the realm. Since an empty list of roles cannot be told apart from a missing one,
+// the realm roles are only replaced when the request lists some.
+func (s *userServer) UpdateUser(ctx context.Context, req *platformv1.UpdateUserRequest) (*platformv1.User, error) {
+	updateReq := UpdateUserRequest{
+		Email:         req.GetEmail(),
+		FirstName:     req.GetFirstName(),
+		LastName:      req.GetLastName(),
+		Enabled:       req.Enabled,
+		EmailVerified: req.EmailVerified,
+	}
+	if len(req.GetRoles()) > 0 {
+		updateReq.Roles = req.GetRoles()
+	}
+
+	admin, err := grpcKeycloakAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.updateUser(ctx, req.GetId(), updateReq); err != nil {
+		return nil, grpcUserError(err)
+	}
+	user, err := admin.getUser(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcUserError(err)
+	}
+	return userMessage(user), nil
+}
+
+// DeleteUser deletes a user from the realm with their Kubeflow Profile
+func (s *userServer) DeleteUser(ctx context.Context, req *platformv1.DeleteUserRequest) (*platformv1.DeleteUserResponse, error) {
+	admin, err := grpcKeycloakAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.deleteUser(ctx, req.GetId()); err != nil {
+		return nil, grpcUserError(err)
+	}
+	return &platformv1.DeleteUserResponse{}, nil
+}
+
+// userMessage returns the gRPC message of a user
+func userMessage(user User) *platformv1.User {
+	return &platformv1.User{
+		Id:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		Enabled:          user.Enabled,
+		EmailVerified:    user.EmailVerified,
+		Roles:            user.Roles,
+		CreatedTimestamp: user.CreatedAt,
+	}
+}
+
+func init() {
+	platformv1.RegisterUserServiceServer(router.GRPC(), &userServer{})
+	router.RegisterGateway(platformv1.RegisterUserServiceHandlerFromEndpoint)
+}
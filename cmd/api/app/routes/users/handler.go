@@ -18,6 +18,7 @@ package users
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -33,8 +34,11 @@ import (
 
 	"github.com/karmada-io/dashboard/cmd/api/app/router"
 	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/audit"
 	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
 	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/harbor"
+	"github.com/karmada-io/dashboard/pkg/resource/orphan"
 )
 
 // User represents a Keycloak user with relevant fields
@@ -60,6 +64,8 @@ type CreateUserRequest struct {
 	Enabled       bool   `json:"enabled"`
 	EmailVerified bool   `json:"emailVerified"`
 	Roles         []string `json:"roles"`
+	// ProvisionRegistry queues the provisioning of a Harbor project for the Kubeflow Profile of the user
+	ProvisionRegistry bool `json:"provisionRegistry"`
 }
 
 // UpdateUserRequest represents the request to update a user
@@ -139,16 +145,9 @@ func handleListUsers(c *gin.Context) {
 	}
 
 	// Get users from Keycloak
-	gocloakClient := gocloak.NewClient(config.URL)
-	users, err := gocloakClient.GetUsers(
-		ctx,
-		adminToken,
-		config.Realm,
-		gocloak.GetUsersParams{},
-	)
-
+	admin := &keycloakAdmin{client: gocloak.NewClient(config.URL), token: adminToken, realm: config.Realm}
+	result, err := admin.listUsers(ctx)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get users from Keycloak")
 		c.JSON(http.StatusInternalServerError, common.BaseResponse{
 			Code: http.StatusInternalServerError,
 			Msg:  "Failed to retrieve users: " + err.Error(),
@@ -157,40 +156,6 @@ func handleListUsers(c *gin.Context) {
 		return
 	}
 
-	// Convert to our User type
-	result := make([]User, 0, len(users))
-	for _, u := range users {
-		// Get user roles
-		userRoles, err := gocloakClient.GetRealmRolesByUserID(
-			ctx,
-			adminToken,
-			config.Realm,
-			*u.ID,
-		)
-		
-		roles := make([]string, 0)
-		if err == nil {
-			for _, role := range userRoles {
-				if role.Name != nil {
-					roles = append(roles, *role.Name)
-				}
-			}
-		}
-
-		user := User{
-			ID:            getStringValue(u.ID),
-			Username:      getStringValue(u.Username),
-			Email:         getStringValue(u.Email),
-			FirstName:     getStringValue(u.FirstName),
-			LastName:      getStringValue(u.LastName),
-			Enabled:       getBoolValue(u.Enabled),
-			EmailVerified: getBoolValue(u.EmailVerified),
-			Roles:         roles,
-			CreatedAt:     getInt64Value(u.CreatedTimestamp),
-		}
-		result = append(result, user)
-	}
-
 	c.JSON(http.StatusOK, common.BaseResponse{
 		Code: http.StatusOK,
 		Msg:  "success",
@@ -198,6 +163,68 @@ func handleListUsers(c *gin.Context) {
 	})
 }
 
+// userNotFoundError is returned for users that cannot be looked up in Keycloak
+type userNotFoundError struct {
+	err error
+}
+
+func (e *userNotFoundError) Error() string {
+	return "User not found: " + e.err.Error()
+}
+
+func (e *userNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// keycloakUser converts a Keycloak user with its realm roles
+func keycloakUser(u *gocloak.User, userRoles []*gocloak.Role) User {
+	roles := make([]string, 0)
+	for _, role := range userRoles {
+		if role.Name != nil {
+			roles = append(roles, *role.Name)
+		}
+	}
+	return User{
+		ID:            getStringValue(u.ID),
+		Username:      getStringValue(u.Username),
+		Email:         getStringValue(u.Email),
+		FirstName:     getStringValue(u.FirstName),
+		LastName:      getStringValue(u.LastName),
+		Enabled:       getBoolValue(u.Enabled),
+		EmailVerified: getBoolValue(u.EmailVerified),
+		Roles:         roles,
+		CreatedAt:     getInt64Value(u.CreatedTimestamp),
+	}
+}
+
+// listUsers returns the users of the realm with their realm roles
+func (a *keycloakAdmin) listUsers(ctx context.Context) ([]User, error) {
+	users, err := a.client.GetUsers(ctx, a.token, a.realm, gocloak.GetUsersParams{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to get users from Keycloak")
+		return nil, err
+	}
+
+	result := make([]User, 0, len(users))
+	for _, u := range users {
+		// Users are listed without their roles when these cannot be read
+		userRoles, _ := a.client.GetRealmRolesByUserID(ctx, a.token, a.realm, getStringValue(u.ID))
+		result = append(result, keycloakUser(u, userRoles))
+	}
+	return result, nil
+}
+
+// getUser returns a user of the realm with their realm roles
+func (a *keycloakAdmin) getUser(ctx context.Context, userID string) (User, error) {
+	u, err := a.client.GetUserByID(ctx, a.token, a.realm, userID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get user from Keycloak", "userID", userID)
+		return User{}, &userNotFoundError{err: err}
+	}
+	userRoles, _ := a.client.GetRealmRolesByUserID(ctx, a.token, a.realm, userID)
+	return keycloakUser(u, userRoles), nil
+}
+
 // handleGetUser gets a specific user by ID
 func handleGetUser(c *gin.Context) {
 	userID := c.Param("id")
@@ -246,41 +273,17 @@ func handleGetUser(c *gin.Context) {
 		return
 	}
 
-	gocloakClient := gocloak.NewClient(config.URL)
-	u, err := gocloakClient.GetUserByID(ctx, adminToken, config.Realm, userID)
+	admin := &keycloakAdmin{client: gocloak.NewClient(config.URL), token: adminToken, realm: config.Realm}
+	user, err := admin.getUser(ctx, userID)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get user from Keycloak", "userID", userID)
 		c.JSON(http.StatusNotFound, common.BaseResponse{
 			Code: http.StatusNotFound,
-			Msg:  "User not found: " + err.Error(),
+			Msg:  err.Error(),
 			Data: nil,
 		})
 		return
 	}
 
-	// Get user roles
-	userRoles, err := gocloakClient.GetRealmRolesByUserID(ctx, adminToken, config.Realm, userID)
-	roles := make([]string, 0)
-	if err == nil {
-		for _, role := range userRoles {
-			if role.Name != nil {
-				roles = append(roles, *role.Name)
-			}
-		}
-	}
-
-	user := User{
-		ID:            getStringValue(u.ID),
-		Username:      getStringValue(u.Username),
-		Email:         getStringValue(u.Email),
-		FirstName:     getStringValue(u.FirstName),
-		LastName:      getStringValue(u.LastName),
-		Enabled:       getBoolValue(u.Enabled),
-		EmailVerified: getBoolValue(u.EmailVerified),
-		Roles:         roles,
-		CreatedAt:     getInt64Value(u.CreatedTimestamp),
-	}
-
 	c.JSON(http.StatusOK, common.BaseResponse{
 		Code: http.StatusOK,
 		Msg:  "success",
@@ -326,7 +329,8 @@ func createKubeflowProfile(ctx context.Context, userEmail string) error {
 			"apiVersion": "kubeflow.org/v1",
 			"kind":       "Profile",
 			"metadata": map[string]interface{}{
-				"name": profileName,
+				"name":   profileName,
+				"labels": map[string]interface{}{orphan.ManagedLabel: orphan.ManagedLabelValue},
 			},
 			"spec": map[string]interface{}{
 				"owner": map[string]interface{}{
@@ -417,7 +421,8 @@ func createProfilePropagationPolicy(ctx context.Context, userEmail string) error
 	// Create ClusterPropagationPolicy
 	propagationPolicy := &policyv1alpha1.ClusterPropagationPolicy{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: policyName,
+			Name:   policyName,
+			Labels: orphan.Labels(""),
 		},
 		Spec: policyv1alpha1.PropagationSpec{
 			ResourceSelectors: []policyv1alpha1.ResourceSelector{
@@ -445,59 +450,14 @@ func createProfilePropagationPolicy(ctx context.Context, userEmail string) error
 	return nil
 }
 
-// handleCreateUser creates a new user in Keycloak
-func handleCreateUser(c *gin.Context) {
-	var req CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.BaseResponse{
-			Code: http.StatusBadRequest,
-			Msg:  "Invalid request: " + err.Error(),
-			Data: nil,
-		})
-		return
-	}
-
-	kc := keycloak.GetClient()
-	if kc == nil {
-		klog.ErrorS(nil, "Keycloak client not initialized")
-		c.JSON(http.StatusInternalServerError, common.BaseResponse{
-			Code: http.StatusInternalServerError,
-			Msg:  "Keycloak not configured",
-			Data: nil,
-		})
-		return
-	}
-
-	token := client.GetBearerToken(c.Request)
-	if token == "" {
-		c.JSON(http.StatusUnauthorized, common.BaseResponse{
-			Code: http.StatusUnauthorized,
-			Msg:  "Missing authentication token",
-			Data: nil,
-		})
-		return
-	}
-
-	config := kc.GetConfig()
-	ctx := c.Request.Context()
-
-	// Get admin token for Keycloak operations
-	adminToken, err := getAdminToken(ctx, kc, token)
-	if err != nil {
-		klog.ErrorS(err, "Failed to get admin token")
-		c.JSON(http.StatusInternalServerError, common.BaseResponse{
-			Code: http.StatusInternalServerError,
-			Msg:  "Failed to authenticate with Keycloak",
-			Data: nil,
-		})
-		return
-	}
-
-	// Create user
-	gocloakClient := gocloak.NewClient(config.URL)
+// provisionUser creates a user in Keycloak, sets the password and realm roles, and provisions the
+// Kubeflow Profile of the user. Only a failure to create the user is returned as an error; the steps
+// after it are best effort and their failures are returned as warnings.
+func provisionUser(ctx context.Context, gocloakClient *gocloak.GoCloak, adminToken, realm string, req CreateUserRequest, temporaryPassword bool) (string, []string, error) {
+	var warnings []string
 	enabled := req.Enabled
 	emailVerified := req.EmailVerified
-	
+
 	user := gocloak.User{
 		Username:      &req.Username,
 		Email:         &req.Email,
@@ -507,15 +467,10 @@ func handleCreateUser(c *gin.Context) {
 		EmailVerified: &emailVerified,
 	}
 
-	userID, err := gocloakClient.CreateUser(ctx, adminToken, config.Realm, user)
+	userID, err := gocloakClient.CreateUser(ctx, adminToken, realm, user)
 	if err != nil {
 		klog.ErrorS(err, "Failed to create user in Keycloak")
-		c.JSON(http.StatusInternalServerError, common.BaseResponse{
-			Code: http.StatusInternalServerError,
-			Msg:  "Failed to create user: " + err.Error(),
-			Data: nil,
-		})
-		return
+		return "", nil, err
 	}
 
 	// Set password
@@ -523,80 +478,82 @@ func handleCreateUser(c *gin.Context) {
 		ctx,
 		adminToken,
 		userID,
-		config.Realm,
+		realm,
 		req.Password,
-		false, // temporary password
+		temporaryPassword,
 	)
 	if err != nil {
 		klog.ErrorS(err, "Failed to set user password", "userID", userID)
 		// Don't fail the request, user is created but password needs to be set manually
+		warnings = append(warnings, "failed to set password: "+err.Error())
 	}
 
 	// Assign roles if provided
 	if len(req.Roles) > 0 {
 		// Get all available roles
-		allRoles, err := gocloakClient.GetRealmRoles(ctx, adminToken, config.Realm, gocloak.GetRoleParams{})
-		if err == nil {
+		allRoles, err := gocloakClient.GetRealmRoles(ctx, adminToken, realm, gocloak.GetRoleParams{})
+		if err != nil {
+			warnings = append(warnings, "failed to get realm roles: "+err.Error())
+		} else {
 			rolesToAssign := make([]gocloak.Role, 0)
 			for _, roleName := range req.Roles {
+				found := false
 				for _, role := range allRoles {
 					if role.Name != nil && *role.Name == roleName {
 						rolesToAssign = append(rolesToAssign, *role)
+						found = true
 						break
 					}
 				}
+				if !found {
+					warnings = append(warnings, fmt.Sprintf("realm role %s does not exist", roleName))
+				}
 			}
-			
+
 			if len(rolesToAssign) > 0 {
-				err = gocloakClient.AddRealmRoleToUser(ctx, adminToken, config.Realm, userID, rolesToAssign)
+				err = gocloakClient.AddRealmRoleToUser(ctx, adminToken, realm, userID, rolesToAssign)
 				if err != nil {
 					klog.ErrorS(err, "Failed to assign roles to user", "userID", userID)
+					warnings = append(warnings, "failed to assign roles: "+err.Error())
 				}
 			}
 		}
 	}
+	syncUserRoleMappings(ctx, req.Username, req.Roles)
+	enforceUserMFAPolicy(ctx, &keycloakAdmin{client: gocloakClient, token: adminToken, realm: realm}, userID, req.Roles)
 
 	// Create Kubeflow Profile for the user
 	if err := createKubeflowProfile(ctx, req.Email); err != nil {
 		klog.ErrorS(err, "Failed to create Kubeflow Profile", "userEmail", req.Email)
 		// Don't fail the request, user is created but profile needs to be created manually
-		// Continue to create propagation policy anyway
+		warnings = append(warnings, "failed to create Kubeflow Profile: "+err.Error())
 	} else {
 		// Create propagation policy to propagate the profile to all member clusters
 		if err := createProfilePropagationPolicy(ctx, req.Email); err != nil {
 			klog.ErrorS(err, "Failed to create propagation policy", "userEmail", req.Email)
 			// Don't fail the request, profile is created but policy needs to be created manually
+			warnings = append(warnings, "failed to create propagation policy: "+err.Error())
 		}
 	}
 
-	c.JSON(http.StatusCreated, common.BaseResponse{
-		Code: http.StatusCreated,
-		Msg:  "User created successfully",
-		Data: gin.H{
-			"id": userID,
-		},
-	})
-}
-
-// handleUpdateUser updates an existing user in Keycloak
-func handleUpdateUser(c *gin.Context) {
-	userID := c.Param("id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, common.BaseResponse{
-			Code: http.StatusBadRequest,
-			Msg:  "Missing user ID",
-			Data: nil,
-		})
-		return
+	if req.ProvisionRegistry {
+		params := harbor.ProvisionParams{OwnerKind: harbor.OwnerUser, Owner: sanitizeEmailForK8sName(req.Email)}
+		if job, err := harbor.EnqueueProvisioning(ctx, params, ""); err != nil {
+			klog.ErrorS(err, "Failed to enqueue registry provisioning", "userEmail", req.Email)
+			warnings = append(warnings, "failed to provision registry: "+err.Error())
+		} else {
+			klog.InfoS("Enqueued registry provisioning", "userEmail", req.Email, "job", job.ID)
+		}
 	}
 
-	var req UpdateUserRequest
+	return userID, warnings, nil
+}
+
+// handleCreateUser creates a new user in Keycloak
+func handleCreateUser(c *gin.Context) {
+	var req CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.BaseResponse{
-			Code: http.StatusBadRequest,
-			Msg:  "Invalid request: " + err.Error(),
-			Data: nil,
-		})
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -636,19 +593,37 @@ func handleUpdateUser(c *gin.Context) {
 		return
 	}
 
-	// Get existing user
+	// Create user
 	gocloakClient := gocloak.NewClient(config.URL)
-	existingUser, err := gocloakClient.GetUserByID(ctx, adminToken, config.Realm, userID)
+	userID, _, err := provisionUser(ctx, gocloakClient, adminToken, config.Realm, req, false)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get user from Keycloak", "userID", userID)
-		c.JSON(http.StatusNotFound, common.BaseResponse{
-			Code: http.StatusNotFound,
-			Msg:  "User not found: " + err.Error(),
+		c.JSON(http.StatusInternalServerError, common.BaseResponse{
+			Code: http.StatusInternalServerError,
+			Msg:  "Failed to create user: " + err.Error(),
 			Data: nil,
 		})
 		return
 	}
 
+	c.JSON(http.StatusCreated, common.BaseResponse{
+		Code: http.StatusCreated,
+		Msg:  "User created successfully",
+		Data: gin.H{
+			"id": userID,
+		},
+	})
+}
+
+// updateUser changes the fields of a user that are set in the request, and replaces their realm roles when
+// the request lists roles
+func (a *keycloakAdmin) updateUser(ctx context.Context, userID string, req UpdateUserRequest) error {
+	// Get existing user
+	existingUser, err := a.client.GetUserByID(ctx, a.token, a.realm, userID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get user from Keycloak", "userID", userID)
+		return &userNotFoundError{err: err}
+	}
+
 	// Update user fields
 	if req.Email != "" {
 		existingUser.Email = &req.Email
@@ -666,21 +641,16 @@ func handleUpdateUser(c *gin.Context) {
 		existingUser.EmailVerified = req.EmailVerified
 	}
 
-	err = gocloakClient.UpdateUser(ctx, adminToken, config.Realm, *existingUser)
+	err = a.client.UpdateUser(ctx, a.token, a.realm, *existingUser)
 	if err != nil {
 		klog.ErrorS(err, "Failed to update user in Keycloak", "userID", userID)
-		c.JSON(http.StatusInternalServerError, common.BaseResponse{
-			Code: http.StatusInternalServerError,
-			Msg:  "Failed to update user: " + err.Error(),
-			Data: nil,
-		})
-		return
+		return err
 	}
 
 	// Update roles if provided
 	if req.Roles != nil {
 		// Get current roles
-		currentRoles, err := gocloakClient.GetRealmRolesByUserID(ctx, adminToken, config.Realm, userID)
+		currentRoles, err := a.client.GetRealmRolesByUserID(ctx, a.token, a.realm, userID)
 		if err == nil {
 			// Remove all current roles
 			if len(currentRoles) > 0 {
@@ -691,7 +661,7 @@ func handleUpdateUser(c *gin.Context) {
 						rolesToRemove[i] = *role
 					}
 				}
-				err = gocloakClient.DeleteRealmRoleFromUser(ctx, adminToken, config.Realm, userID, rolesToRemove)
+				err = a.client.DeleteRealmRoleFromUser(ctx, a.token, a.realm, userID, rolesToRemove)
 				if err != nil {
 					klog.ErrorS(err, "Failed to remove current roles", "userID", userID)
 				}
@@ -699,7 +669,7 @@ func handleUpdateUser(c *gin.Context) {
 
 			// Add new roles
 			if len(req.Roles) > 0 {
-				allRoles, err := gocloakClient.GetRealmRoles(ctx, adminToken, config.Realm, gocloak.GetRoleParams{})
+				allRoles, err := a.client.GetRealmRoles(ctx, a.token, a.realm, gocloak.GetRoleParams{})
 				if err == nil {
 					rolesToAssign := make([]gocloak.Role, 0)
 					for _, roleName := range req.Roles {
@@ -712,7 +682,7 @@ func handleUpdateUser(c *gin.Context) {
 					}
 					
 					if len(rolesToAssign) > 0 {
-						err = gocloakClient.AddRealmRoleToUser(ctx, adminToken, config.Realm, userID, rolesToAssign)
+						err = a.client.AddRealmRoleToUser(ctx, a.token, a.realm, userID, rolesToAssign)
 						if err != nil {
 							klog.ErrorS(err, "Failed to assign new roles", "userID", userID)
 						}
@@ -722,6 +692,90 @@ func handleUpdateUser(c *gin.Context) {
 		}
 	}
 
+	// Apply the role mappings to the new realm roles
+	if req.Roles != nil {
+		syncUserRoleMappings(ctx, getStringValue(existingUser.Username), req.Roles)
+		enforceUserMFAPolicy(ctx, a, userID, req.Roles)
+	}
+	return nil
+}
+
+// handleUpdateUser updates an existing user in Keycloak
+func handleUpdateUser(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, common.BaseResponse{
+			Code: http.StatusBadRequest,
+			Msg:  "Missing user ID",
+			Data: nil,
+		})
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if req.Roles != nil {
+		audit.SetAction(c, audit.ActionRoleChange)
+	}
+
+	kc := keycloak.GetClient()
+	if kc == nil {
+		klog.ErrorS(nil, "Keycloak client not initialized")
+		c.JSON(http.StatusInternalServerError, common.BaseResponse{
+			Code: http.StatusInternalServerError,
+			Msg:  "Keycloak not configured",
+			Data: nil,
+		})
+		return
+	}
+
+	token := client.GetBearerToken(c.Request)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, common.BaseResponse{
+			Code: http.StatusUnauthorized,
+			Msg:  "Missing authentication token",
+			Data: nil,
+		})
+		return
+	}
+
+	config := kc.GetConfig()
+	ctx := c.Request.Context()
+
+	// Get admin token for Keycloak operations
+	adminToken, err := getAdminToken(ctx, kc, token)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get admin token")
+		c.JSON(http.StatusInternalServerError, common.BaseResponse{
+			Code: http.StatusInternalServerError,
+			Msg:  "Failed to authenticate with Keycloak",
+			Data: nil,
+		})
+		return
+	}
+
+	admin := &keycloakAdmin{client: gocloak.NewClient(config.URL), token: adminToken, realm: config.Realm}
+	if err := admin.updateUser(ctx, userID, req); err != nil {
+		var notFound *userNotFoundError
+		if errors.As(err, &notFound) {
+			c.JSON(http.StatusNotFound, common.BaseResponse{
+				Code: http.StatusNotFound,
+				Msg:  err.Error(),
+				Data: nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, common.BaseResponse{
+			Code: http.StatusInternalServerError,
+			Msg:  "Failed to update user: " + err.Error(),
+			Data: nil,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, common.BaseResponse{
 		Code: http.StatusOK,
 		Msg:  "User updated successfully",
@@ -743,11 +797,7 @@ func handleUpdatePassword(c *gin.Context) {
 
 	var req UpdatePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, common.BaseResponse{
-			Code: http.StatusBadRequest,
-			Msg:  "Invalid request: " + err.Error(),
-			Data: nil,
-		})
+		common.FailWithBindError(c, err)
 		return
 	}
 
@@ -902,6 +952,42 @@ func deleteProfilePropagationPolicy(ctx context.Context, userEmail string) error
 	return nil
 }
 
+// deleteUser deletes a user from the realm, and their Kubeflow Profile with its propagation policy
+func (a *keycloakAdmin) deleteUser(ctx context.Context, userID string) error {
+	// Get user details before deletion to retrieve the email
+	user, err := a.client.GetUserByID(ctx, a.token, a.realm, userID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get user from Keycloak", "userID", userID)
+		return &userNotFoundError{err: err}
+	}
+
+	userEmail := getStringValue(user.Email)
+	
+	// Delete user from Keycloak
+	err = a.client.DeleteUser(ctx, a.token, a.realm, userID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to delete user from Keycloak", "userID", userID)
+		return err
+	}
+
+	// Delete the propagation policy first (to stop propagation to member clusters)
+	if userEmail != "" {
+		if err := deleteProfilePropagationPolicy(ctx, userEmail); err != nil {
+			klog.ErrorS(err, "Failed to delete propagation policy", "userEmail", userEmail)
+			// Don't fail the request, continue to delete the profile
+		}
+
+		// Delete the Kubeflow Profile
+		if err := deleteKubeflowProfile(ctx, userEmail); err != nil {
+			klog.ErrorS(err, "Failed to delete Kubeflow Profile", "userEmail", userEmail)
+			// Don't fail the request, profile deletion can be done manually if needed
+		}
+	} else {
+		klog.InfoS("User email is empty, skipping Kubeflow Profile cleanup", "userID", userID)
+	}
+	return nil
+}
+
 // handleDeleteUser deletes a user from Keycloak
 func handleDeleteUser(c *gin.Context) {
 	userID := c.Param("id")
@@ -950,26 +1036,17 @@ func handleDeleteUser(c *gin.Context) {
 		return
 	}
 
-	gocloakClient := gocloak.NewClient(config.URL)
-	
-	// Get user details before deletion to retrieve the email
-	user, err := gocloakClient.GetUserByID(ctx, adminToken, config.Realm, userID)
-	if err != nil {
-		klog.ErrorS(err, "Failed to get user from Keycloak", "userID", userID)
-		c.JSON(http.StatusNotFound, common.BaseResponse{
-			Code: http.StatusNotFound,
-			Msg:  "User not found: " + err.Error(),
-			Data: nil,
-		})
-		return
-	}
-
-	userEmail := getStringValue(user.Email)
-	
-	// Delete user from Keycloak
-	err = gocloakClient.DeleteUser(ctx, adminToken, config.Realm, userID)
-	if err != nil {
-		klog.ErrorS(err, "Failed to delete user from Keycloak", "userID", userID)
+	admin := &keycloakAdmin{client: gocloak.NewClient(config.URL), token: adminToken, realm: config.Realm}
+	if err := admin.deleteUser(ctx, userID); err != nil {
+		var notFound *userNotFoundError
+		if errors.As(err, &notFound) {
+			c.JSON(http.StatusNotFound, common.BaseResponse{
+				Code: http.StatusNotFound,
+				Msg:  err.Error(),
+				Data: nil,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, common.BaseResponse{
 			Code: http.StatusInternalServerError,
 			Msg:  "Failed to delete user: " + err.Error(),
@@ -978,22 +1055,6 @@ func handleDeleteUser(c *gin.Context) {
 		return
 	}
 
-	// Delete the propagation policy first (to stop propagation to member clusters)
-	if userEmail != "" {
-		if err := deleteProfilePropagationPolicy(ctx, userEmail); err != nil {
-			klog.ErrorS(err, "Failed to delete propagation policy", "userEmail", userEmail)
-			// Don't fail the request, continue to delete the profile
-		}
-
-		// Delete the Kubeflow Profile
-		if err := deleteKubeflowProfile(ctx, userEmail); err != nil {
-			klog.ErrorS(err, "Failed to delete Kubeflow Profile", "userEmail", userEmail)
-			// Don't fail the request, profile deletion can be done manually if needed
-		}
-	} else {
-		klog.InfoS("User email is empty, skipping Kubeflow Profile cleanup", "userID", userID)
-	}
-
 	c.JSON(http.StatusOK, common.BaseResponse{
 		Code: http.StatusOK,
 		Msg:  "User deleted successfully",
@@ -1093,7 +1154,9 @@ func init() {
 	// User management routes
 	v1.GET("/users", handleListUsers)
 	v1.GET("/users/:id", handleGetUser)
+	v1.GET("/users/:id/activity", handleGetUserActivity)
 	v1.POST("/users", handleCreateUser)
+	v1.POST("/users/import", router.EnsureMgmtAdminMiddleware(), handleImportUsers)
 	v1.PUT("/users/:id", handleUpdateUser)
 	v1.PUT("/users/:id/password", handleUpdatePassword)
 	v1.DELETE("/users/:id", handleDeleteUser)
@@ -0,0 +1,358 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/util/requestid"
+)
+
+// IdentityProviderRequest creates or updates a SAML or OIDC identity provider. On update the alias and type
+// cannot change, and a masked client secret keeps the stored one.
+type IdentityProviderRequest struct {
+	Alias                     string            `json:"alias"`
+	DisplayName               string            `json:"displayName"`
+	ProviderID                string            `json:"providerId" binding:"required,oneof=oidc keycloak-oidc saml"`
+	Enabled                   *bool             `json:"enabled"`
+	TrustEmail                bool              `json:"trustEmail"`
+	LinkOnly                  bool              `json:"linkOnly"`
+	FirstBrokerLoginFlowAlias string            `json:"firstBrokerLoginFlowAlias"`
+	Config                    map[string]string `json:"config" binding:"required"`
+}
+
+func (r IdentityProviderRequest) provider(alias string) keycloak.IdentityProvider {
+	enabled := r.Enabled == nil || *r.Enabled
+	return keycloak.IdentityProvider{
+		Alias:                     alias,
+		DisplayName:               r.DisplayName,
+		ProviderID:                r.ProviderID,
+		Enabled:                   enabled,
+		TrustEmail:                r.TrustEmail,
+		LinkOnly:                  r.LinkOnly,
+		FirstBrokerLoginFlowAlias: r.FirstBrokerLoginFlowAlias,
+		Config:                    r.Config,
+	}
+}
+
+// GroupMappingRequest maps a group of an identity provider to a realm role and, optionally, the role to a
+// relation of the dashboard through a role mapping
+type GroupMappingRequest struct {
+	Group string `json:"group" binding:"required"`
+	Role  string `json:"role" binding:"required"`
+	// Claim is the OIDC claim or SAML attribute listing the groups, "groups" or "memberOf" by default
+	Claim string `json:"claim"`
+	// Relation, ObjectType and ObjectID add a role mapping granting the relation to users with the role
+	Relation   string `json:"relation"`
+	ObjectType string `json:"objectType" binding:"required_with=Relation,omitempty,oneof=dashboard cluster"`
+	ObjectID   string `json:"objectId"`
+}
+
+// GroupMappingResult is a created group mapping and the role mapping it was added with
+type GroupMappingResult struct {
+	keycloak.GroupMapping
+	RoleMapping *RoleMapping `json:"roleMapping,omitempty"`
+}
+
+// keycloakAdmin is the Keycloak client and token used to manage the realm on behalf of a request
+type keycloakAdmin struct {
+	client *gocloak.GoCloak
+	token  string
+	realm  string
+}
+
+// errKeycloakNotConfigured is returned when the realm is managed without Keycloak
+var errKeycloakNotConfigured = errors.New("Keycloak not configured")
+
+// keycloakAdminFor returns the Keycloak admin client acting on behalf of the user of the bearer token
+func keycloakAdminFor(ctx context.Context, bearer string) (*keycloakAdmin, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		return nil, errKeycloakNotConfigured
+	}
+	token, err := getAdminToken(ctx, kc, bearer)
+	if err != nil {
+		return nil, err
+	}
+	kcConfig := kc.GetConfig()
+	return &keycloakAdmin{client: gocloak.NewClient(kcConfig.URL), token: token, realm: kcConfig.Realm}, nil
+}
+
+// newKeycloakAdmin returns the Keycloak admin client of a request, or fails the request
+func newKeycloakAdmin(c *gin.Context) (*keycloakAdmin, bool) {
+	admin, err := keycloakAdminFor(c, client.GetBearerToken(c.Request))
+	if errors.Is(err, errKeycloakNotConfigured) {
+		common.FailWithStatus(c, err, http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if err != nil {
+		common.Fail(c, err)
+		return nil, false
+	}
+	return admin, true
+}
+
+// failKeycloak fails a request with the status of a Keycloak error when it is a client error
+func failKeycloak(c *gin.Context, err error, msg string, keysAndValues ...interface{}) {
+	requestid.Logger(c).Error(err, msg, keysAndValues...)
+	var apiErr *gocloak.APIError
+	if errors.As(err, &apiErr) && apiErr.Code >= 400 && apiErr.Code < 500 {
+		common.FailWithStatus(c, err, apiErr.Code)
+		return
+	}
+	common.Fail(c, err)
+}
+
+// handleGetIdentityProviders lists the identity providers of the realm with their secrets masked
+func handleGetIdentityProviders(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	reps, err := admin.client.GetIdentityProviders(c, admin.token, admin.realm)
+	if err != nil {
+		failKeycloak(c, err, "Failed to list identity providers")
+		return
+	}
+	providers := make([]keycloak.IdentityProvider, 0, len(reps))
+	for _, rep := range reps {
+		providers = append(providers, keycloak.IdentityProviderFromRepresentation(rep))
+	}
+	common.Success(c, gin.H{
+		"identityProviders": providers,
+		"total":             len(providers),
+	})
+}
+
+// handleGetIdentityProvider returns an identity provider with its secrets masked and its group mappings
+func handleGetIdentityProvider(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	alias := c.Param("alias")
+	rep, err := admin.client.GetIdentityProvider(c, admin.token, admin.realm, alias)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get identity provider", "alias", alias)
+		return
+	}
+	mappers, err := admin.client.GetIdentityProviderMappers(c, admin.token, admin.realm, alias)
+	if err != nil {
+		failKeycloak(c, err, "Failed to list identity provider mappers", "alias", alias)
+		return
+	}
+	common.Success(c, gin.H{
+		"identityProvider": keycloak.IdentityProviderFromRepresentation(rep),
+		"groupMappings":    keycloak.GroupMappingsFromMappers(mappers),
+	})
+}
+
+// handleCreateIdentityProvider federates a SAML or OIDC identity provider into the realm
+func handleCreateIdentityProvider(c *gin.Context) {
+	var req IdentityProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	provider := req.provider(req.Alias)
+	if err := provider.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	rep := provider.Representation()
+	if _, err := admin.client.CreateIdentityProvider(c, admin.token, admin.realm, rep); err != nil {
+		failKeycloak(c, err, "Failed to create identity provider", "alias", provider.Alias)
+		return
+	}
+	klog.InfoS("Created identity provider", "alias", provider.Alias, "type", provider.ProviderID)
+	common.Success(c, keycloak.IdentityProviderFromRepresentation(&rep))
+}
+
+// handleUpdateIdentityProvider replaces the settings of an identity provider
+func handleUpdateIdentityProvider(c *gin.Context) {
+	var req IdentityProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	alias := c.Param("alias")
+	if req.Alias != "" && req.Alias != alias {
+		common.FailWithStatus(c, fmt.Errorf("the alias of identity provider %s cannot be changed", alias), http.StatusBadRequest)
+		return
+	}
+	provider := req.provider(alias)
+	if err := provider.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	existing, err := admin.client.GetIdentityProvider(c, admin.token, admin.realm, alias)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get identity provider", "alias", alias)
+		return
+	}
+	if current := gocloak.PString(existing.ProviderID); current != provider.ProviderID {
+		common.FailWithStatus(c, fmt.Errorf("identity provider %s is a %s provider, its type cannot be changed", alias, current), http.StatusBadRequest)
+		return
+	}
+
+	rep := provider.Representation()
+	rep.InternalID = existing.InternalID
+	if err := admin.client.UpdateIdentityProvider(c, admin.token, admin.realm, alias, rep); err != nil {
+		failKeycloak(c, err, "Failed to update identity provider", "alias", alias)
+		return
+	}
+	klog.InfoS("Updated identity provider", "alias", alias)
+	common.Success(c, keycloak.IdentityProviderFromRepresentation(&rep))
+}
+
+// handleDeleteIdentityProvider removes an identity provider and its mappers. Users it created stay in the realm.
+func handleDeleteIdentityProvider(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	alias := c.Param("alias")
+	if err := admin.client.DeleteIdentityProvider(c, admin.token, admin.realm, alias); err != nil {
+		failKeycloak(c, err, "Failed to delete identity provider", "alias", alias)
+		return
+	}
+	klog.InfoS("Deleted identity provider", "alias", alias)
+	common.Success(c, gin.H{"alias": alias})
+}
+
+// handleGetGroupMappings lists the group to realm role mappings of an identity provider
+func handleGetGroupMappings(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	alias := c.Param("alias")
+	mappers, err := admin.client.GetIdentityProviderMappers(c, admin.token, admin.realm, alias)
+	if err != nil {
+		failKeycloak(c, err, "Failed to list identity provider mappers", "alias", alias)
+		return
+	}
+	mappings := keycloak.GroupMappingsFromMappers(mappers)
+	common.Success(c, gin.H{
+		"groupMappings": mappings,
+		"total":         len(mappings),
+	})
+}
+
+// handleCreateGroupMapping maps a group of an identity provider to a realm role. With a relation the role is
+// also mapped to it, so federated users of the group get the relation at the next role mapping sync.
+func handleCreateGroupMapping(c *gin.Context) {
+	var req GroupMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	alias := c.Param("alias")
+	rep, err := admin.client.GetIdentityProvider(c, admin.token, admin.realm, alias)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get identity provider", "alias", alias)
+		return
+	}
+	if _, err := admin.client.GetRealmRole(c, admin.token, admin.realm, req.Role); err != nil {
+		failKeycloak(c, err, "Failed to get realm role", "role", req.Role)
+		return
+	}
+
+	mapper, err := keycloak.GroupMapper(alias, gocloak.PString(rep.ProviderID), keycloak.GroupMapping{Group: req.Group, Role: req.Role, Claim: req.Claim})
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	result := GroupMappingResult{}
+	if req.Relation != "" {
+		roleMapping, err := ensureRoleMapping(c, RoleMappingRequest{
+			Role:       req.Role,
+			ObjectType: req.ObjectType,
+			ObjectID:   req.ObjectID,
+			Relation:   req.Relation,
+		})
+		if err != nil {
+			common.FailWithStatus(c, err, http.StatusBadRequest)
+			return
+		}
+		result.RoleMapping = &roleMapping
+	}
+
+	id, err := admin.client.CreateIdentityProviderMapper(c, admin.token, admin.realm, alias, mapper)
+	if err != nil {
+		failKeycloak(c, err, "Failed to create identity provider mapper", "alias", alias, "group", req.Group)
+		return
+	}
+	klog.InfoS("Created identity provider group mapping", "alias", alias, "group", req.Group, "role", req.Role)
+	mapper.ID = gocloak.StringP(id)
+	result.GroupMapping = keycloak.GroupMappingsFromMappers([]*gocloak.IdentityProviderMapper{&mapper})[0]
+	common.Success(c, result)
+}
+
+// handleDeleteGroupMapping removes a group mapping of an identity provider. Role mappings of its role are kept,
+// since the role may be granted in other ways.
+func handleDeleteGroupMapping(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	alias, id := c.Param("alias"), c.Param("id")
+	if err := admin.client.DeleteIdentityProviderMapper(c, admin.token, admin.realm, alias, id); err != nil {
+		failKeycloak(c, err, "Failed to delete identity provider mapper", "alias", alias, "id", id)
+		return
+	}
+	klog.InfoS("Deleted identity provider group mapping", "alias", alias, "id", id)
+	common.Success(c, gin.H{"id": id})
+}
+
+func init() {
+	r := router.V1()
+	identityProviders := r.Group("/identity-providers", router.EnsureMgmtAdminMiddleware())
+	{
+		identityProviders.GET("", handleGetIdentityProviders)
+		identityProviders.POST("", handleCreateIdentityProvider)
+		identityProviders.GET("/:alias", handleGetIdentityProvider)
+		identityProviders.PUT("/:alias", handleUpdateIdentityProvider)
+		identityProviders.DELETE("/:alias", handleDeleteIdentityProvider)
+		identityProviders.GET("/:alias/group-mappings", handleGetGroupMappings)
+		identityProviders.POST("/:alias/group-mappings", handleCreateGroupMapping)
+		identityProviders.DELETE("/:alias/group-mappings/:id", handleDeleteGroupMapping)
+	}
+}
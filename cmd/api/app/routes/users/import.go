@@ -0,0 +1,291 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// maxImportUsers limits the size of an import batch
+const maxImportUsers = 500
+
+// ImportUser is a row of a user import
+type ImportUser struct {
+	Username  string   `json:"username"`
+	Email     string   `json:"email"`
+	FirstName string   `json:"firstName"`
+	LastName  string   `json:"lastName"`
+	Password  string   `json:"password"` // A temporary password is generated when empty
+	Roles     []string `json:"roles"`
+	// Clusters are cluster names with an optional role, e.g. "gpu-cluster:owner". The role defaults to member.
+	Clusters []string `json:"clusters"`
+}
+
+// ImportUsersRequest represents the JSON body of a user import
+type ImportUsersRequest struct {
+	Users []ImportUser `json:"users"`
+}
+
+// ImportUserResult is the outcome of one row of a user import
+type ImportUserResult struct {
+	Row      int    `json:"row"`
+	Username string `json:"username"`
+	Status   string `json:"status"` // "created" or "failed"
+	UserID   string `json:"userId,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// TemporaryPassword is the generated password the user must change at first login
+	TemporaryPassword string   `json:"temporaryPassword,omitempty"`
+	Warnings          []string `json:"warnings,omitempty"`
+}
+
+// parseImportUsers reads the users of an import from a multipart "file" field, a CSV body or a JSON body
+func parseImportUsers(c *gin.Context) ([]ImportUser, error) {
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open uploaded file: %v", err)
+		}
+		defer f.Close()
+		if strings.HasSuffix(strings.ToLower(file.Filename), ".json") {
+			return parseImportJSON(f)
+		}
+		return parseImportCSV(f)
+	}
+
+	if strings.Contains(c.ContentType(), "csv") {
+		return parseImportCSV(c.Request.Body)
+	}
+	return parseImportJSON(c.Request.Body)
+}
+
+// parseImportJSON accepts either {"users": [...]} or a plain array of users
+func parseImportJSON(r io.Reader) ([]ImportUser, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var users []ImportUser
+	if err := json.Unmarshal(data, &users); err == nil {
+		return users, nil
+	}
+	var req ImportUsersRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return req.Users, nil
+}
+
+// parseImportCSV reads a CSV with a header row. Username and email columns are required; roles and
+// clusters hold values separated by semicolons.
+func parseImportCSV(r io.Reader) ([]ImportUser, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV is empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := columns[strings.ToLower(required)]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[strings.ToLower(name)]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	list := func(record []string, name string) []string {
+		var values []string
+		for _, value := range strings.Split(field(record, name), ";") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	users := make([]ImportUser, 0, len(records)-1)
+	for _, record := range records[1:] {
+		users = append(users, ImportUser{
+			Username:  field(record, "username"),
+			Email:     field(record, "email"),
+			FirstName: field(record, "firstName"),
+			LastName:  field(record, "lastName"),
+			Password:  field(record, "password"),
+			Roles:     list(record, "roles"),
+			Clusters:  list(record, "clusters"),
+		})
+	}
+	return users, nil
+}
+
+// generateTemporaryPassword returns a random password for imported users without one
+func generateTemporaryPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// assignClusterRelations grants the cluster relations of an imported user
+func assignClusterRelations(ctx context.Context, username string, clusters []string) []string {
+	if len(clusters) == 0 {
+		return nil
+	}
+	if fga.FGAService == nil {
+		return []string{"OpenFGA is not configured, cluster access was not assigned"}
+	}
+	var warnings []string
+	for _, entry := range clusters {
+		clusterName, role, _ := strings.Cut(entry, ":")
+		if role == "" {
+			role = "member"
+		}
+		relation := fga.ClusterRelationForRole(role)
+		if !fga.IsValidRelation("cluster", relation) {
+			warnings = append(warnings, fmt.Sprintf("unknown role %s for cluster %s", role, clusterName))
+			continue
+		}
+		if err := fga.FGAService.GetClient().WriteTuple(ctx, username, relation, "cluster", clusterName); err != nil {
+			klog.ErrorS(err, "Failed to assign cluster role", "username", username, "cluster", clusterName, "relation", relation)
+			warnings = append(warnings, fmt.Sprintf("failed to assign %s on cluster %s: %v", relation, clusterName, err))
+		}
+	}
+	return warnings
+}
+
+// handleImportUsers creates a batch of users from CSV or JSON and reports the outcome of each row.
+// Rows are independent, a failed row does not stop the import.
+func handleImportUsers(c *gin.Context) {
+	users, err := parseImportUsers(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if len(users) == 0 {
+		common.Fail(c, fmt.Errorf("no users to import"))
+		return
+	}
+	if len(users) > maxImportUsers {
+		common.Fail(c, fmt.Errorf("at most %d users can be imported at once, got %d", maxImportUsers, len(users)))
+		return
+	}
+
+	kc := keycloak.GetClient()
+	if kc == nil {
+		common.Fail(c, fmt.Errorf("Keycloak not configured"))
+		return
+	}
+	ctx := c.Request.Context()
+	adminToken, err := getAdminToken(ctx, kc, client.GetBearerToken(c.Request))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	kcConfig := kc.GetConfig()
+	gocloakClient := gocloak.NewClient(kcConfig.URL)
+	provisionRegistry := c.Query("provisionRegistry") == "true"
+
+	results := make([]ImportUserResult, 0, len(users))
+	seen := map[string]bool{}
+	succeeded := 0
+	for i, user := range users {
+		result := ImportUserResult{Row: i + 1, Username: user.Username, Status: "failed"}
+		switch {
+		case user.Username == "" || user.Email == "":
+			result.Error = "username and email are required"
+		case seen[strings.ToLower(user.Username)]:
+			result.Error = "duplicate username in import"
+		}
+		seen[strings.ToLower(user.Username)] = true
+		if result.Error != "" {
+			results = append(results, result)
+			continue
+		}
+
+		req := CreateUserRequest{
+			Username:          user.Username,
+			Email:             user.Email,
+			FirstName:         user.FirstName,
+			LastName:          user.LastName,
+			Password:          user.Password,
+			Enabled:           true,
+			EmailVerified:     false,
+			Roles:             user.Roles,
+			ProvisionRegistry: provisionRegistry,
+		}
+		temporary := req.Password == ""
+		if temporary {
+			if req.Password, err = generateTemporaryPassword(); err != nil {
+				result.Error = "failed to generate password: " + err.Error()
+				results = append(results, result)
+				continue
+			}
+		}
+
+		userID, warnings, err := provisionUser(ctx, gocloakClient, adminToken, kcConfig.Realm, req, temporary)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Status = "created"
+		result.UserID = userID
+		if temporary {
+			result.TemporaryPassword = req.Password
+		}
+		result.Warnings = append(warnings, assignClusterRelations(ctx, user.Username, user.Clusters)...)
+		results = append(results, result)
+		succeeded++
+	}
+
+	klog.InfoS("Imported users", "total", len(users), "succeeded", succeeded)
+	common.Success(c, map[string]interface{}{
+		"total":     len(users),
+		"succeeded": succeeded,
+		"failed":    len(users) - succeeded,
+		"results":   results,
+	})
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+const (
+	// mfaPolicyConfigMap stores the realm roles whose users must enroll a second factor
+	mfaPolicyConfigMap = "mfa-policy"
+	mfaPolicyKey       = "policy"
+	// maxRoleUsers bounds how many users of a role are read when the policy is enforced
+	maxRoleUsers = 10000
+)
+
+// MFAPolicy requires the users with one of the realm roles to enroll a second factor. Users without one get
+// the required action of the method and enroll at their next login.
+type MFAPolicy struct {
+	Roles []string `json:"roles"`
+	// Method is "otp" or "webauthn", otp by default
+	Method    string `json:"method" binding:"omitempty,oneof=otp webauthn"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+	UpdatedBy string `json:"updatedBy,omitempty"`
+}
+
+// requires reports whether a user with the realm roles must have a second factor
+func (p MFAPolicy) requires(roles []string) bool {
+	for _, role := range roles {
+		if containsRole(p.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// MFAEnforcementResult lists the users of the policy roles and those asked to enroll a second factor
+type MFAEnforcementResult struct {
+	DryRun bool `json:"dryRun"`
+	Users  int  `json:"users"`
+	// Enrolled users already have a second factor
+	Enrolled int `json:"enrolled"`
+	// Required are the usernames that have to enroll a second factor at their next login
+	Required []string `json:"required"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// UserMFA is the second factors of a user and whether the MFA policy applies to them
+type UserMFA struct {
+	UserID          string               `json:"userId"`
+	Username        string               `json:"username"`
+	Factors         []keycloak.MFAFactor `json:"factors"`
+	RequiredActions []string             `json:"requiredActions"`
+	// Required is set when one of the realm roles of the user is in the MFA policy
+	Required bool `json:"required"`
+}
+
+func loadMFAPolicy(ctx context.Context) (MFAPolicy, error) {
+	var policy MFAPolicy
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, mfaPolicyConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return policy, nil
+	}
+	if err != nil {
+		return policy, fmt.Errorf("failed to get MFA policy: %v", err)
+	}
+	if data := cm.Data[mfaPolicyKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &policy); err != nil {
+			return policy, fmt.Errorf("failed to decode MFA policy: %v", err)
+		}
+	}
+	return policy, nil
+}
+
+func saveMFAPolicy(ctx context.Context, policy MFAPolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	configMaps := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace())
+	cm, err := configMaps.Get(ctx, mfaPolicyConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: mfaPolicyConfigMap, Namespace: config.GetNamespace()},
+			Data:       map[string]string{mfaPolicyKey: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{mfaPolicyKey: string(data)}
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// requireMFAEnrollment adds the enrollment action of the policy to a user without a second factor.
+// It reports whether the user still has to enroll.
+func requireMFAEnrollment(ctx context.Context, admin *keycloakAdmin, user *gocloak.User, action string, dryRun bool) (bool, error) {
+	userID := gocloak.PString(user.ID)
+	credentials, err := admin.client.GetCredentials(ctx, admin.token, admin.realm, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get credentials: %v", err)
+	}
+	if len(keycloak.MFAFactors(credentials)) > 0 {
+		return false, nil
+	}
+
+	actions := []string{}
+	if user.RequiredActions != nil {
+		actions = append(actions, *user.RequiredActions...)
+	}
+	if containsAction(actions, action) || dryRun {
+		return true, nil
+	}
+	actions = append(actions, action)
+	user.RequiredActions = &actions
+	if err := admin.client.UpdateUser(ctx, admin.token, admin.realm, *user); err != nil {
+		return false, fmt.Errorf("failed to set required action: %v", err)
+	}
+	return true, nil
+}
+
+// enforceMFAPolicy asks the users of the policy roles that have no second factor to enroll one
+func enforceMFAPolicy(ctx context.Context, admin *keycloakAdmin, policy MFAPolicy, dryRun bool) (*MFAEnforcementResult, error) {
+	action, err := keycloak.MFAEnrollmentAction(policy.Method)
+	if err != nil {
+		return nil, err
+	}
+	result := &MFAEnforcementResult{DryRun: dryRun, Required: []string{}}
+	seen := map[string]bool{}
+	for _, role := range policy.Roles {
+		users, err := admin.client.GetUsersByRoleName(ctx, admin.token, admin.realm, role, gocloak.GetUsersByRoleParams{Max: gocloak.IntP(maxRoleUsers)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users of role %s: %v", role, err)
+		}
+		for _, user := range users {
+			userID := gocloak.PString(user.ID)
+			if user == nil || userID == "" || seen[userID] {
+				continue
+			}
+			seen[userID] = true
+			result.Users++
+			required, err := requireMFAEnrollment(ctx, admin, user, action, dryRun)
+			switch {
+			case err != nil:
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", gocloak.PString(user.Username), err))
+			case required:
+				result.Required = append(result.Required, gocloak.PString(user.Username))
+			default:
+				result.Enrolled++
+			}
+		}
+	}
+	sort.Strings(result.Required)
+	return result, nil
+}
+
+// enforceUserMFAPolicy applies the MFA policy to a user after their realm roles were changed
+func enforceUserMFAPolicy(ctx context.Context, admin *keycloakAdmin, userID string, roles []string) {
+	policy, err := loadMFAPolicy(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to load MFA policy", "userID", userID)
+		return
+	}
+	if !policy.requires(roles) {
+		return
+	}
+	action, err := keycloak.MFAEnrollmentAction(policy.Method)
+	if err != nil {
+		klog.ErrorS(err, "Invalid MFA policy")
+		return
+	}
+	user, err := admin.client.GetUserByID(ctx, admin.token, admin.realm, userID)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get user for MFA policy", "userID", userID)
+		return
+	}
+	if _, err := requireMFAEnrollment(ctx, admin, user, action, false); err != nil {
+		klog.ErrorS(err, "Failed to apply MFA policy", "userID", userID)
+	}
+}
+
+// userRealmRoles returns the names of the realm roles of a user
+func userRealmRoles(ctx context.Context, admin *keycloakAdmin, userID string) ([]string, error) {
+	roles, err := admin.client.GetRealmRolesByUserID(ctx, admin.token, admin.realm, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.Name != nil {
+			names = append(names, *role.Name)
+		}
+	}
+	return names, nil
+}
+
+// handleGetUserMFA returns the second factors of a user
+func handleGetUserMFA(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	userID := c.Param("id")
+	user, err := admin.client.GetUserByID(c, admin.token, admin.realm, userID)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get user", "userID", userID)
+		return
+	}
+	credentials, err := admin.client.GetCredentials(c, admin.token, admin.realm, userID)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get user credentials", "userID", userID)
+		return
+	}
+	roles, err := userRealmRoles(c, admin, userID)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get user roles", "userID", userID)
+		return
+	}
+	policy, err := loadMFAPolicy(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	result := UserMFA{
+		UserID:          userID,
+		Username:        gocloak.PString(user.Username),
+		Factors:         keycloak.MFAFactors(credentials),
+		RequiredActions: []string{},
+		Required:        policy.requires(roles),
+	}
+	if user.RequiredActions != nil {
+		result.RequiredActions = *user.RequiredActions
+	}
+	common.Success(c, result)
+}
+
+// handleDeleteUserMFA removes a lost second factor of a user. When the MFA policy still applies to the user
+// and no second factor is left, they have to enroll a new one at their next login.
+func handleDeleteUserMFA(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	userID, credentialID := c.Param("id"), c.Param("credentialId")
+	credentials, err := admin.client.GetCredentials(c, admin.token, admin.realm, userID)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get user credentials", "userID", userID)
+		return
+	}
+	var factor *keycloak.MFAFactor
+	for _, f := range keycloak.MFAFactors(credentials) {
+		if f.ID == credentialID {
+			found := f
+			factor = &found
+		}
+	}
+	if factor == nil {
+		common.FailWithStatus(c, fmt.Errorf("user %s has no second factor %s", userID, credentialID), http.StatusNotFound)
+		return
+	}
+
+	if err := admin.client.DeleteCredentials(c, admin.token, admin.realm, userID, credentialID); err != nil {
+		failKeycloak(c, err, "Failed to delete user credential", "userID", userID, "credentialID", credentialID)
+		return
+	}
+	klog.InfoS("Removed second factor", "userID", userID, "type", factor.Type, "label", factor.Label, "by", utilauth.GetAuthenticatedUser(c))
+
+	if roles, err := userRealmRoles(c, admin, userID); err != nil {
+		klog.ErrorS(err, "Failed to get user roles for MFA policy", "userID", userID)
+	} else {
+		enforceUserMFAPolicy(c, admin, userID, roles)
+	}
+	common.Success(c, factor)
+}
+
+// handleGetMFAPolicy returns the MFA policy
+func handleGetMFAPolicy(c *gin.Context) {
+	policy, err := loadMFAPolicy(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if policy.Roles == nil {
+		policy.Roles = []string{}
+	}
+	common.Success(c, policy)
+}
+
+// handleUpdateMFAPolicy replaces the MFA policy and applies it to the users of its roles.
+// With dryRun=true the users that would have to enroll are returned without saving the policy.
+func handleUpdateMFAPolicy(c *gin.Context) {
+	var policy MFAPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	for _, role := range policy.Roles {
+		if _, err := admin.client.GetRealmRole(c, admin.token, admin.realm, role); err != nil {
+			failKeycloak(c, err, "Failed to get realm role", "role", role)
+			return
+		}
+	}
+
+	dryRun := c.Query("dryRun") == "true"
+	if !dryRun {
+		policy.UpdatedAt = time.Now().Format(time.RFC3339)
+		policy.UpdatedBy = utilauth.GetAuthenticatedUser(c)
+		if err := saveMFAPolicy(c, policy); err != nil {
+			klog.ErrorS(err, "Failed to save MFA policy")
+			common.Fail(c, err)
+			return
+		}
+		klog.InfoS("Updated MFA policy", "roles", policy.Roles, "method", policy.Method, "user", policy.UpdatedBy)
+	}
+	result, err := enforceMFAPolicy(c, admin, policy, dryRun)
+	if err != nil {
+		klog.ErrorS(err, "Failed to enforce MFA policy")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{"policy": policy, "enforcement": result})
+}
+
+// handleEnforceMFAPolicy applies the MFA policy again, e.g. after users were given a role outside of the dashboard
+func handleEnforceMFAPolicy(c *gin.Context) {
+	policy, err := loadMFAPolicy(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	result, err := enforceMFAPolicy(c, admin, policy, c.Query("dryRun") == "true")
+	if err != nil {
+		klog.ErrorS(err, "Failed to enforce MFA policy")
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, result)
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/users/:id/mfa", router.EnsureMgmtAdminMiddleware(), handleGetUserMFA)
+	r.DELETE("/users/:id/mfa/:credentialId", router.EnsureMgmtAdminMiddleware(), handleDeleteUserMFA)
+
+	mfaPolicy := r.Group("/mfa-policy", router.EnsureMgmtAdminMiddleware())
+	{
+		mfaPolicy.GET("", handleGetMFAPolicy)
+		mfaPolicy.PUT("", handleUpdateMFAPolicy)
+		mfaPolicy.POST("/enforce", handleEnforceMFAPolicy)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	utilauth "github.com/karmada-io/dashboard/pkg/util/utilauth"
+)
+
+// RequirePasswordResetRequest makes a user choose a new password at their next login
+type RequirePasswordResetRequest struct {
+	// SendEmail sends the user an email with a link to reset their password
+	SendEmail bool `json:"sendEmail"`
+	// LifespanSeconds is how long the link of the email is valid, 12 hours by default
+	LifespanSeconds int `json:"lifespanSeconds" binding:"omitempty,min=60"`
+	// RedirectURI is where users land after resetting their password from the email
+	RedirectURI string `json:"redirectUri" binding:"omitempty,url"`
+	// LogoutSessions ends the sessions of the user so the reset applies right away
+	LogoutSessions bool `json:"logoutSessions"`
+}
+
+// RequirePasswordResetResult is the outcome of a forced password reset
+type RequirePasswordResetResult struct {
+	UserID          string   `json:"userId"`
+	RequiredActions []string `json:"requiredActions"`
+	EmailSent       bool     `json:"emailSent"`
+	SessionsEnded   bool     `json:"sessionsEnded"`
+}
+
+// handleGetPasswordPolicy returns the password policy of the realm
+func handleGetPasswordPolicy(c *gin.Context) {
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+	realm, err := admin.client.GetRealm(c, admin.token, admin.realm)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get realm")
+		return
+	}
+	policy, err := keycloak.ParsePasswordPolicy(gocloak.PString(realm.PasswordPolicy))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, policy)
+}
+
+// handleUpdatePasswordPolicy replaces the password policy of the realm. Policies the dashboard does not manage
+// are kept unless they are listed in other. New rules apply to the next password change of each user.
+func handleUpdatePasswordPolicy(c *gin.Context) {
+	var policy keycloak.PasswordPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+
+	// Only the password policy is sent, Keycloak leaves the other settings of the realm unchanged
+	value := policy.String()
+	if err := admin.client.UpdateRealm(c, admin.token, gocloak.RealmRepresentation{
+		Realm:          gocloak.StringP(admin.realm),
+		PasswordPolicy: gocloak.StringP(value),
+	}); err != nil {
+		failKeycloak(c, err, "Failed to update password policy")
+		return
+	}
+	klog.InfoS("Updated password policy", "policy", value, "user", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, policy)
+}
+
+// handleRequirePasswordReset sets the update password required action of a user, optionally emailing them
+// a reset link and ending their sessions
+func handleRequirePasswordReset(c *gin.Context) {
+	userID := c.Param("id")
+	var req RequirePasswordResetRequest
+	// The body is optional, without it only the required action is set
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		common.FailWithBindError(c, err)
+		return
+	}
+	admin, ok := newKeycloakAdmin(c)
+	if !ok {
+		return
+	}
+
+	user, err := admin.client.GetUserByID(c, admin.token, admin.realm, userID)
+	if err != nil {
+		failKeycloak(c, err, "Failed to get user", "userID", userID)
+		return
+	}
+	actions := []string{}
+	if user.RequiredActions != nil {
+		actions = append(actions, *user.RequiredActions...)
+	}
+	if !containsAction(actions, keycloak.UpdatePasswordAction) {
+		actions = append(actions, keycloak.UpdatePasswordAction)
+		user.RequiredActions = &actions
+		if err := admin.client.UpdateUser(c, admin.token, admin.realm, *user); err != nil {
+			failKeycloak(c, err, "Failed to require password reset", "userID", userID)
+			return
+		}
+	}
+	result := RequirePasswordResetResult{UserID: userID, RequiredActions: actions}
+
+	if req.SendEmail {
+		if gocloak.PString(user.Email) == "" {
+			common.FailWithData(c, fmt.Errorf("user %s has no email address", gocloak.PString(user.Username)), http.StatusBadRequest, result)
+			return
+		}
+		params := gocloak.ExecuteActionsEmail{
+			UserID:  gocloak.StringP(userID),
+			Actions: &[]string{keycloak.UpdatePasswordAction},
+		}
+		if req.LifespanSeconds > 0 {
+			params.Lifespan = gocloak.IntP(req.LifespanSeconds)
+		}
+		if req.RedirectURI != "" {
+			params.RedirectURI = gocloak.StringP(req.RedirectURI)
+			params.ClientID = gocloak.StringP(keycloak.GetClient().GetConfig().ClientID)
+		}
+		if err := admin.client.ExecuteActionsEmail(c, admin.token, admin.realm, params); err != nil {
+			klog.ErrorS(err, "Failed to send password reset email", "userID", userID)
+			common.FailWithData(c, fmt.Errorf("password reset is required but the email could not be sent: %v", err), http.StatusBadGateway, result)
+			return
+		}
+		result.EmailSent = true
+	}
+	if req.LogoutSessions {
+		if err := admin.client.LogoutAllSessions(c, admin.token, admin.realm, userID); err != nil {
+			klog.ErrorS(err, "Failed to end user sessions", "userID", userID)
+			common.FailWithData(c, fmt.Errorf("password reset is required but the sessions could not be ended: %v", err), http.StatusBadGateway, result)
+			return
+		}
+		result.SessionsEnded = true
+	}
+
+	klog.InfoS("Required password reset", "userID", userID, "username", gocloak.PString(user.Username),
+		"emailSent", result.EmailSent, "sessionsEnded", result.SessionsEnded, "by", utilauth.GetAuthenticatedUser(c))
+	common.Success(c, result)
+}
+
+func containsAction(actions []string, action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	r := router.V1()
+	r.GET("/password-policy", router.EnsureMgmtAdminMiddleware(), handleGetPasswordPolicy)
+	r.PUT("/password-policy", router.EnsureMgmtAdminMiddleware(), handleUpdatePasswordPolicy)
+	r.POST("/users/:id/require-password-reset", router.EnsureMgmtAdminMiddleware(), handleRequirePasswordReset)
+}
@@ -0,0 +1,615 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	// roleMappingConfigMap stores the role mappings and the tuples granted by them
+	roleMappingConfigMap = "role-permission-mappings"
+	roleMappingsKey      = "mappings"
+	roleGrantsKey        = "grants"
+
+	// allObjects maps a role to the relation on every cluster
+	allObjects = "*"
+)
+
+// RoleMapping grants an OpenFGA relation on an object to every user with a Keycloak realm role
+type RoleMapping struct {
+	ID         string `json:"id"`
+	Role       string `json:"role"`
+	ObjectType string `json:"objectType"` // "dashboard" or "cluster"
+	ObjectID   string `json:"objectId"`   // A cluster name or "*" for all clusters, always "dashboard" for the dashboard
+	Relation   string `json:"relation"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// RoleMappingRequest represents the request to create or update a role mapping
+type RoleMappingRequest struct {
+	Role       string `json:"role" binding:"required"`
+	ObjectType string `json:"objectType" binding:"required,oneof=dashboard cluster"`
+	ObjectID   string `json:"objectId"`
+	Relation   string `json:"relation" binding:"required"`
+}
+
+// PermissionTuple is a relation of a user on an object
+type PermissionTuple struct {
+	User       string `json:"user"`
+	Relation   string `json:"relation"`
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+	// Roles are the realm roles the tuple is granted by
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (t PermissionTuple) key() string {
+	return fmt.Sprintf("%s|%s|%s:%s", t.User, t.Relation, t.ObjectType, t.ObjectID)
+}
+
+// RoleMappingSyncResult is the plan of a sync, and its outcome when it is not a dry run
+type RoleMappingSyncResult struct {
+	DryRun   bool              `json:"dryRun"`
+	Users    int               `json:"users"`
+	Grants   []PermissionTuple `json:"grants"`
+	Revokes  []PermissionTuple `json:"revokes"`
+	Errors   []string          `json:"errors,omitempty"`
+	SyncedAt string            `json:"syncedAt"`
+}
+
+// roleMappingMu serializes changes to the mappings and syncs, which both rewrite the ConfigMap
+var roleMappingMu sync.Mutex
+
+// roleMappingState is the content of the role mapping ConfigMap
+type roleMappingState struct {
+	configMap *corev1.ConfigMap
+	mappings  []RoleMapping
+	// grants are the tuples written by syncs, keyed by user. Only these are revoked when roles change,
+	// so relations assigned by hand are never removed.
+	grants map[string][]PermissionTuple
+}
+
+func loadRoleMappingState(ctx context.Context) (*roleMappingState, error) {
+	state := &roleMappingState{grants: map[string][]PermissionTuple{}}
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, roleMappingConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role mappings: %v", err)
+	}
+	state.configMap = cm
+	if data := cm.Data[roleMappingsKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &state.mappings); err != nil {
+			return nil, fmt.Errorf("failed to decode role mappings: %v", err)
+		}
+	}
+	if data := cm.Data[roleGrantsKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &state.grants); err != nil {
+			return nil, fmt.Errorf("failed to decode role mapping grants: %v", err)
+		}
+	}
+	return state, nil
+}
+
+func (s *roleMappingState) save(ctx context.Context) error {
+	mappings, err := json.Marshal(s.mappings)
+	if err != nil {
+		return err
+	}
+	grants, err := json.Marshal(s.grants)
+	if err != nil {
+		return err
+	}
+	configMaps := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace())
+	if s.configMap == nil {
+		s.configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      roleMappingConfigMap,
+				Namespace: config.GetNamespace(),
+			},
+		}
+		s.configMap.Data = map[string]string{roleMappingsKey: string(mappings), roleGrantsKey: string(grants)}
+		s.configMap, err = configMaps.Create(ctx, s.configMap, metav1.CreateOptions{})
+		return err
+	}
+	s.configMap.Data = map[string]string{roleMappingsKey: string(mappings), roleGrantsKey: string(grants)}
+	s.configMap, err = configMaps.Update(ctx, s.configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// validateRoleMapping checks the relation against the authorization model and normalizes the object
+func validateRoleMapping(req *RoleMappingRequest) error {
+	if !fga.IsValidRelation(req.ObjectType, req.Relation) {
+		return fmt.Errorf("relation %q is not defined on %s, supported relations are %s",
+			req.Relation, req.ObjectType, strings.Join(fga.ObjectRelations[req.ObjectType], ", "))
+	}
+	switch req.ObjectType {
+	case "dashboard":
+		req.ObjectID = "dashboard"
+	case "cluster":
+		if req.ObjectID == "" {
+			return fmt.Errorf("objectId is required for cluster mappings, use %q for all clusters", allObjects)
+		}
+	}
+	return nil
+}
+
+// ensureRoleMapping returns the role mapping matching the request, adding it when there is none.
+// Tuples of a new mapping are granted by the next sync.
+func ensureRoleMapping(ctx context.Context, req RoleMappingRequest) (RoleMapping, error) {
+	if err := validateRoleMapping(&req); err != nil {
+		return RoleMapping{}, err
+	}
+
+	roleMappingMu.Lock()
+	defer roleMappingMu.Unlock()
+	state, err := loadRoleMappingState(ctx)
+	if err != nil {
+		return RoleMapping{}, err
+	}
+	for _, mapping := range state.mappings {
+		if mapping.Role == req.Role && mapping.ObjectType == req.ObjectType && mapping.ObjectID == req.ObjectID && mapping.Relation == req.Relation {
+			return mapping, nil
+		}
+	}
+	mapping := RoleMapping{
+		ID:         strconv.FormatInt(time.Now().UnixNano(), 36),
+		Role:       req.Role,
+		ObjectType: req.ObjectType,
+		ObjectID:   req.ObjectID,
+		Relation:   req.Relation,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	state.mappings = append(state.mappings, mapping)
+	if err := state.save(ctx); err != nil {
+		return RoleMapping{}, fmt.Errorf("failed to save role mapping: %v", err)
+	}
+	return mapping, nil
+}
+
+// desiredTuples returns the tuples the mappings grant to a user with the given realm roles
+func desiredTuples(mappings []RoleMapping, username string, roles []string, clusters []string) map[string]PermissionTuple {
+	hasRole := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		hasRole[role] = true
+	}
+
+	desired := map[string]PermissionTuple{}
+	for _, mapping := range mappings {
+		if !hasRole[mapping.Role] {
+			continue
+		}
+		objectIDs := []string{mapping.ObjectID}
+		if mapping.ObjectType == "cluster" && mapping.ObjectID == allObjects {
+			objectIDs = clusters
+		}
+		for _, objectID := range objectIDs {
+			tuple := PermissionTuple{User: username, Relation: mapping.Relation, ObjectType: mapping.ObjectType, ObjectID: objectID}
+			if existing, ok := desired[tuple.key()]; ok {
+				tuple.Roles = existing.Roles
+			}
+			if !containsRole(tuple.Roles, mapping.Role) {
+				tuple.Roles = append(tuple.Roles, mapping.Role)
+			}
+			desired[tuple.key()] = tuple
+		}
+	}
+	return desired
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// syncRoleMappings applies the mappings to the given users and their realm roles. Unless allUsers is set,
+// users that are not passed keep their tuples, so a single user can be synced after their roles were changed.
+// With allUsers the tuples of users that no longer exist in the realm are revoked.
+func syncRoleMappings(ctx context.Context, userRoles map[string][]string, allUsers, dryRun bool) (*RoleMappingSyncResult, error) {
+	fgaService := fga.FGAService
+	if fgaService == nil {
+		return nil, fmt.Errorf("OpenFGA is not configured")
+	}
+
+	roleMappingMu.Lock()
+	defer roleMappingMu.Unlock()
+
+	state, err := loadRoleMappingState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %v", err)
+	}
+	clusterNames := make([]string, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+
+	result := &RoleMappingSyncResult{
+		DryRun:   dryRun,
+		Users:    len(userRoles),
+		Grants:   []PermissionTuple{},
+		Revokes:  []PermissionTuple{},
+		SyncedAt: time.Now().Format(time.RFC3339),
+	}
+	usernames := make([]string, 0, len(userRoles))
+	for username := range userRoles {
+		usernames = append(usernames, username)
+	}
+	if allUsers {
+		for username := range state.grants {
+			if _, ok := userRoles[username]; !ok {
+				usernames = append(usernames, username)
+			}
+		}
+	}
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		desired := desiredTuples(state.mappings, username, userRoles[username], clusterNames)
+		granted := map[string]PermissionTuple{}
+		for _, tuple := range state.grants[username] {
+			granted[tuple.key()] = tuple
+		}
+
+		var kept []PermissionTuple
+		for key, tuple := range granted {
+			if _, ok := desired[key]; ok {
+				kept = append(kept, desired[key])
+				continue
+			}
+			result.Revokes = append(result.Revokes, tuple)
+			if dryRun {
+				continue
+			}
+			if err := fgaService.GetClient().DeleteTuple(ctx, tuple.User, tuple.Relation, tuple.ObjectType, tuple.ObjectID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("revoke %s: %v", key, err))
+				kept = append(kept, tuple)
+			}
+		}
+
+		for key, tuple := range desired {
+			if _, ok := granted[key]; ok {
+				continue
+			}
+			// Relations the user already has were assigned by hand and are left unmanaged
+			exists, err := fgaService.Check(ctx, tuple.User, tuple.Relation, tuple.ObjectType, tuple.ObjectID)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("check %s: %v", key, err))
+				continue
+			}
+			if exists {
+				continue
+			}
+			result.Grants = append(result.Grants, tuple)
+			if dryRun {
+				continue
+			}
+			if err := fgaService.GetClient().WriteTuple(ctx, tuple.User, tuple.Relation, tuple.ObjectType, tuple.ObjectID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("grant %s: %v", key, err))
+				continue
+			}
+			kept = append(kept, tuple)
+		}
+
+		if len(kept) == 0 {
+			delete(state.grants, username)
+		} else {
+			sort.Slice(kept, func(i, j int) bool { return kept[i].key() < kept[j].key() })
+			state.grants[username] = kept
+		}
+	}
+
+	sortTuples(result.Grants)
+	sortTuples(result.Revokes)
+	if dryRun || len(result.Grants)+len(result.Revokes) == 0 {
+		return result, nil
+	}
+	if err := state.save(ctx); err != nil {
+		return result, fmt.Errorf("failed to save role mapping grants: %v", err)
+	}
+	return result, nil
+}
+
+func sortTuples(tuples []PermissionTuple) {
+	sort.Slice(tuples, func(i, j int) bool { return tuples[i].key() < tuples[j].key() })
+}
+
+// listRealmUserRoles returns the realm roles of every user in the Keycloak realm
+func listRealmUserRoles(ctx context.Context, kc *keycloak.KeycloakClient, adminToken string) (map[string][]string, error) {
+	kcConfig := kc.GetConfig()
+	gocloakClient := gocloak.NewClient(kcConfig.URL)
+	users, err := gocloakClient.GetUsers(ctx, adminToken, kcConfig.Realm, gocloak.GetUsersParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %v", err)
+	}
+
+	userRoles := make(map[string][]string, len(users))
+	for _, u := range users {
+		if u.ID == nil || u.Username == nil {
+			continue
+		}
+		realmRoles, err := gocloakClient.GetRealmRolesByUserID(ctx, adminToken, kcConfig.Realm, *u.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get realm roles of %s: %v", *u.Username, err)
+		}
+		roles := make([]string, 0, len(realmRoles))
+		for _, role := range realmRoles {
+			if role.Name != nil {
+				roles = append(roles, *role.Name)
+			}
+		}
+		userRoles[*u.Username] = roles
+	}
+	return userRoles, nil
+}
+
+// syncUserRoleMappings applies the mappings to one user after their realm roles were changed
+func syncUserRoleMappings(ctx context.Context, username string, roles []string) {
+	if fga.FGAService == nil {
+		return
+	}
+	result, err := syncRoleMappings(ctx, map[string][]string{username: roles}, false, false)
+	if err != nil {
+		klog.ErrorS(err, "Failed to sync role mappings", "username", username)
+		return
+	}
+	klog.V(4).InfoS("Synced role mappings", "username", username, "grants", len(result.Grants), "revokes", len(result.Revokes))
+}
+
+// StartRoleMappingSync periodically applies the role mappings to the realm roles of all Keycloak users
+// until ctx is done. An interval of 0 disables the sync.
+func StartRoleMappingSync(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Role mapping sync disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runRoleMappingSync(ctx)
+			}
+		}
+	}()
+}
+
+func runRoleMappingSync(ctx context.Context) {
+	kc := keycloak.GetClient()
+	if kc == nil || fga.FGAService == nil {
+		return
+	}
+	// Without a client secret there is no token to read the realm outside of a request
+	adminToken, err := kc.GetAdminToken(ctx)
+	if err != nil || adminToken == "" {
+		klog.V(4).InfoS("Skipping role mapping sync, no Keycloak service account token", "error", err)
+		return
+	}
+	userRoles, err := listRealmUserRoles(ctx, kc, adminToken)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list Keycloak users for role mapping sync")
+		return
+	}
+	result, err := syncRoleMappings(ctx, userRoles, true, false)
+	if err != nil {
+		klog.ErrorS(err, "Role mapping sync failed")
+		return
+	}
+	if len(result.Grants)+len(result.Revokes) > 0 || len(result.Errors) > 0 {
+		klog.InfoS("Role mapping sync completed", "users", result.Users, "grants", len(result.Grants),
+			"revokes", len(result.Revokes), "errors", len(result.Errors))
+	}
+}
+
+// handleGetRoleMappings lists the role mappings
+func handleGetRoleMappings(c *gin.Context) {
+	state, err := loadRoleMappingState(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	mappings := state.mappings
+	if mappings == nil {
+		mappings = []RoleMapping{}
+	}
+	common.Success(c, map[string]interface{}{
+		"mappings":  mappings,
+		"relations": fga.ObjectRelations,
+		"total":     len(mappings),
+	})
+}
+
+// handleCreateRoleMapping adds a role mapping. Tuples are granted by the next sync.
+func handleCreateRoleMapping(c *gin.Context) {
+	var req RoleMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := validateRoleMapping(&req); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	roleMappingMu.Lock()
+	defer roleMappingMu.Unlock()
+	state, err := loadRoleMappingState(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	for _, mapping := range state.mappings {
+		if mapping.Role == req.Role && mapping.ObjectType == req.ObjectType && mapping.ObjectID == req.ObjectID && mapping.Relation == req.Relation {
+			common.Fail(c, fmt.Errorf("role %s is already mapped to %s on %s:%s", req.Role, req.Relation, req.ObjectType, req.ObjectID))
+			return
+		}
+	}
+
+	mapping := RoleMapping{
+		ID:         strconv.FormatInt(time.Now().UnixNano(), 36),
+		Role:       req.Role,
+		ObjectType: req.ObjectType,
+		ObjectID:   req.ObjectID,
+		Relation:   req.Relation,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	state.mappings = append(state.mappings, mapping)
+	if err := state.save(c); err != nil {
+		klog.ErrorS(err, "Failed to save role mapping", "role", req.Role)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, mapping)
+}
+
+// handleUpdateRoleMapping replaces a role mapping. Tuples are updated by the next sync.
+func handleUpdateRoleMapping(c *gin.Context) {
+	var req RoleMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if err := validateRoleMapping(&req); err != nil {
+		common.Fail(c, err)
+		return
+	}
+
+	roleMappingMu.Lock()
+	defer roleMappingMu.Unlock()
+	state, err := loadRoleMappingState(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	for i := range state.mappings {
+		if state.mappings[i].ID != c.Param("id") {
+			continue
+		}
+		state.mappings[i].Role = req.Role
+		state.mappings[i].ObjectType = req.ObjectType
+		state.mappings[i].ObjectID = req.ObjectID
+		state.mappings[i].Relation = req.Relation
+		if err := state.save(c); err != nil {
+			klog.ErrorS(err, "Failed to save role mapping", "id", c.Param("id"))
+			common.Fail(c, err)
+			return
+		}
+		common.Success(c, state.mappings[i])
+		return
+	}
+	common.Fail(c, fmt.Errorf("role mapping %s not found", c.Param("id")))
+}
+
+// handleDeleteRoleMapping removes a role mapping. The tuples it granted are revoked by the next sync.
+func handleDeleteRoleMapping(c *gin.Context) {
+	roleMappingMu.Lock()
+	defer roleMappingMu.Unlock()
+	state, err := loadRoleMappingState(c)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	for i, mapping := range state.mappings {
+		if mapping.ID != c.Param("id") {
+			continue
+		}
+		state.mappings = append(state.mappings[:i], state.mappings[i+1:]...)
+		if err := state.save(c); err != nil {
+			klog.ErrorS(err, "Failed to delete role mapping", "id", mapping.ID)
+			common.Fail(c, err)
+			return
+		}
+		common.Success(c, mapping)
+		return
+	}
+	common.Fail(c, fmt.Errorf("role mapping %s not found", c.Param("id")))
+}
+
+// handleSyncRoleMappings applies the role mappings to all Keycloak users now.
+// With dryRun=true the tuples that would be granted and revoked are returned without changing anything.
+func handleSyncRoleMappings(c *gin.Context) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		common.Fail(c, fmt.Errorf("Keycloak not configured"))
+		return
+	}
+	adminToken, err := getAdminToken(c, kc, client.GetBearerToken(c.Request))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	userRoles, err := listRealmUserRoles(c, kc, adminToken)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list Keycloak users for role mapping sync")
+		common.Fail(c, err)
+		return
+	}
+
+	dryRun := c.Query("dryRun") == "true"
+	result, err := syncRoleMappings(c, userRoles, true, dryRun)
+	if err != nil {
+		klog.ErrorS(err, "Role mapping sync failed", "dryRun", dryRun)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, result)
+}
+
+func init() {
+	r := router.V1()
+	roleMappings := r.Group("/role-mappings", router.EnsureMgmtAdminMiddleware())
+	{
+		roleMappings.GET("", handleGetRoleMappings)
+		roleMappings.POST("", handleCreateRoleMapping)
+		roleMappings.PUT("/:id", handleUpdateRoleMapping)
+		roleMappings.DELETE("/:id", handleDeleteRoleMapping)
+		roleMappings.POST("/sync", handleSyncRoleMappings)
+	}
+}
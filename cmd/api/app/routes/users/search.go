@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Nerzal/gocloak/v13"
+
+	"github.com/karmada-io/dashboard/pkg/auth"
+	"github.com/karmada-io/dashboard/pkg/auth/keycloak"
+	"github.com/karmada-io/dashboard/pkg/search"
+)
+
+// SearchDocuments returns the users of the Keycloak realm, or of etcd without Keycloak, as documents of the
+// global search. Keycloak users are only listed with a service account token, since there is no user token
+// outside of a request.
+func SearchDocuments(ctx context.Context) ([]search.Document, error) {
+	kc := keycloak.GetClient()
+	if kc == nil {
+		return etcdSearchDocuments(ctx)
+	}
+	adminToken, err := kc.GetAdminToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if adminToken == "" {
+		return nil, fmt.Errorf("no Keycloak service account token, KEYCLOAK_CLIENT_SECRET is not set")
+	}
+	config := kc.GetConfig()
+	users, err := gocloak.NewClient(config.URL).GetUsers(ctx, adminToken, config.Realm, gocloak.GetUsersParams{})
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]search.Document, 0, len(users))
+	for _, u := range users {
+		name := strings.TrimSpace(getStringValue(u.FirstName) + " " + getStringValue(u.LastName))
+		docs = append(docs, userSearchDocument(getStringValue(u.ID), getStringValue(u.Username), getStringValue(u.Email), name))
+	}
+	return docs, nil
+}
+
+func etcdSearchDocuments(ctx context.Context) ([]search.Document, error) {
+	userManager := auth.GetUserManager()
+	if userManager == nil {
+		return nil, fmt.Errorf("user manager is not initialized")
+	}
+	users, err := userManager.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]search.Document, 0, len(users))
+	for _, u := range users {
+		docs = append(docs, userSearchDocument(u.Username, u.Username, u.Email, ""))
+	}
+	return docs, nil
+}
+
+func userSearchDocument(id, username, email, fullName string) search.Document {
+	return search.Document{
+		Type:        search.TypeUser,
+		ID:          id,
+		Name:        username,
+		Description: strings.TrimSpace(fullName + " " + email),
+		Keywords:    []string{email, fullName},
+		Link: search.Link{
+			Module: "users",
+			API:    "/api/v1/users/" + id,
+			Params: map[string]string{"id": id},
+		},
+	}
+}
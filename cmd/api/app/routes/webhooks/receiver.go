@@ -0,0 +1,374 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooks receives events from external CI/CD systems and turns them into dashboard actions,
+// such as ArgoCD syncs or backup executions, according to the rules of the receiver.
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/router"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+	"github.com/karmada-io/dashboard/pkg/client"
+	pkgerrors "github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	// ReceiverLabelKey marks the secrets that store a webhook receiver
+	ReceiverLabelKey = "ml-platform.io/webhook-receiver"
+	// ReceiverTypeLabelKey is the label on a receiver secret that holds the type of the receiver
+	ReceiverTypeLabelKey = "ml-platform.io/webhook-receiver-type"
+)
+
+// Receiver types, which decide how requests are verified and parsed
+const (
+	ReceiverTypeGitHub = "github"
+	ReceiverTypeGitLab = "gitlab"
+	ReceiverTypeHarbor = "harbor"
+)
+
+// Actions a rule can trigger
+const (
+	ActionArgoCDSync   = "argocd-sync"
+	ActionBackup       = "backup"
+	ActionCacheRefresh = "cache-refresh"
+)
+
+var receiverNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Action is what a rule does when an event matches
+type Action struct {
+	Type string `json:"type"`
+	// Cluster is the member cluster of the ArgoCD Application, or the cluster whose cache is refreshed.
+	// An empty cluster refreshes the cache of all clusters.
+	Cluster     string `json:"cluster,omitempty"`
+	Application string `json:"application,omitempty"`
+	BackupID    string `json:"backupId,omitempty"`
+}
+
+// Rule selects the events that trigger an action. Empty fields match any event.
+type Rule struct {
+	Name   string   `json:"name"`
+	Events []string `json:"events,omitempty"`
+	// Repository is a glob on the repository, e.g. org/* for GitHub or project/image for Harbor
+	Repository string `json:"repository,omitempty"`
+	// Ref is a glob on the branch or tag of the event, e.g. main or v*. Harbor events match on the pushed tags.
+	Ref    string `json:"ref,omitempty"`
+	Action Action `json:"action"`
+}
+
+// Receiver is an endpoint for the events of an external system and the rules applied to them
+type Receiver struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	// Secret verifies the requests: the HMAC key of GitHub, the token of GitLab or the auth header of Harbor
+	Secret    string `json:"secret"`
+	Rules     []Rule `json:"rules"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// CreateReceiverRequest is the request to create a webhook receiver
+type CreateReceiverRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Type        string `json:"type" binding:"required,oneof=github gitlab harbor"`
+	Description string `json:"description"`
+	// Secret is generated when empty
+	Secret string `json:"secret"`
+	Rules  []Rule `json:"rules"`
+}
+
+// UpdateReceiverRequest is the request to update a webhook receiver, fields left out keep their value
+type UpdateReceiverRequest struct {
+	Description *string `json:"description"`
+	Enabled     *bool   `json:"enabled"`
+	Secret      *string `json:"secret"`
+	Rules       []Rule  `json:"rules"`
+}
+
+// validateRules checks the patterns of the rules and the settings required by their actions
+func validateRules(rules []Rule) error {
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		for _, pattern := range []string{rule.Repository, rule.Ref} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %s: invalid pattern %q", name, pattern)
+			}
+		}
+		switch rule.Action.Type {
+		case ActionArgoCDSync:
+			if rule.Action.Cluster == "" || rule.Action.Application == "" {
+				return fmt.Errorf("rule %s: cluster and application are required for %s actions", name, ActionArgoCDSync)
+			}
+		case ActionBackup:
+			if rule.Action.BackupID == "" {
+				return fmt.Errorf("rule %s: backupId is required for %s actions", name, ActionBackup)
+			}
+		case ActionCacheRefresh:
+		default:
+			return fmt.Errorf("rule %s: unsupported action %q", name, rule.Action.Type)
+		}
+	}
+	return nil
+}
+
+// randomHex returns size random bytes hex encoded, for generated secrets and delivery IDs
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// receiverSecretName returns the name of the secret that stores a receiver
+func receiverSecretName(name string) string {
+	return fmt.Sprintf("webhook-receiver-%s", name)
+}
+
+// secretToReceiver decodes a receiver from its secret
+func secretToReceiver(secret *corev1.Secret) (*Receiver, error) {
+	receiver := &Receiver{}
+	if err := json.Unmarshal(secret.Data["receiver"], receiver); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook receiver %s: %w", secret.Name, err)
+	}
+	receiver.CreatedAt = secret.CreationTimestamp.Format("2006-01-02 15:04:05")
+	return receiver, nil
+}
+
+// redacted returns a copy of the receiver without its secret, for API responses
+func (r Receiver) redacted() Receiver {
+	if r.Secret != "" {
+		r.Secret = "******"
+	}
+	return r
+}
+
+// getReceiverSecret returns the secret and decoded receiver for the given name
+func getReceiverSecret(ctx context.Context, name string) (*corev1.Secret, *Receiver, error) {
+	secret, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Get(ctx, receiverSecretName(name), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, pkgerrors.NewNotFound("Webhook receiver not found")
+		}
+		return nil, nil, err
+	}
+	receiver, err := secretToReceiver(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return secret, receiver, nil
+}
+
+// saveReceiver writes the receiver into its secret
+func saveReceiver(ctx context.Context, secret *corev1.Secret, receiver *Receiver) error {
+	data, err := json.Marshal(receiver)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data["receiver"] = data
+	_, err = client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// handleGetReceivers returns all webhook receivers
+func handleGetReceivers(c *gin.Context) {
+	secrets, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).List(c, metav1.ListOptions{
+		LabelSelector: ReceiverLabelKey + "=true",
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list webhook receivers")
+		common.Fail(c, err)
+		return
+	}
+	receivers := make([]Receiver, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		receiver, err := secretToReceiver(&secrets.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid webhook receiver", "secret", secrets.Items[i].Name)
+			continue
+		}
+		receivers = append(receivers, receiver.redacted())
+	}
+	common.Success(c, gin.H{
+		"receivers":  receivers,
+		"totalItems": len(receivers),
+	})
+}
+
+// handleGetReceiver returns a single webhook receiver
+func handleGetReceiver(c *gin.Context) {
+	_, receiver, err := getReceiverSecret(c, c.Param("name"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, receiver.redacted())
+}
+
+// handleCreateReceiver registers a new webhook receiver. The secret is returned once in the response,
+// so that a generated one can be configured in the external system.
+func handleCreateReceiver(c *gin.Context) {
+	var req CreateReceiverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+	if !receiverNamePattern.MatchString(req.Name) {
+		common.Fail(c, pkgerrors.NewBadRequest("receiver name must consist of lower case alphanumeric characters or '-'"))
+		return
+	}
+	if err := validateRules(req.Rules); err != nil {
+		common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	receiver := &Receiver{
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+		Enabled:     true,
+		Secret:      req.Secret,
+		Rules:       req.Rules,
+	}
+	if receiver.Rules == nil {
+		receiver.Rules = []Rule{}
+	}
+	if receiver.Secret == "" {
+		secret, err := randomHex(32)
+		if err != nil {
+			common.Fail(c, err)
+			return
+		}
+		receiver.Secret = secret
+	}
+
+	data, err := json.Marshal(receiver)
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      receiverSecretName(req.Name),
+			Namespace: config.GetNamespace(),
+			Labels: map[string]string{
+				ReceiverLabelKey:     "true",
+				ReceiverTypeLabelKey: req.Type,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"receiver": data},
+	}
+	created, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Create(c, secret, metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Failed to create webhook receiver", "name", req.Name)
+		common.Fail(c, err)
+		return
+	}
+	receiver.CreatedAt = created.CreationTimestamp.Format("2006-01-02 15:04:05")
+	common.Success(c, receiver)
+}
+
+// handleUpdateReceiver updates the settings and rules of a webhook receiver
+func handleUpdateReceiver(c *gin.Context) {
+	var req UpdateReceiverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.FailWithBindError(c, err)
+		return
+	}
+
+	secret, receiver, err := getReceiverSecret(c, c.Param("name"))
+	if err != nil {
+		common.Fail(c, err)
+		return
+	}
+	if req.Description != nil {
+		receiver.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		receiver.Enabled = *req.Enabled
+	}
+	// Keep the stored secret when the client sends back the redacted value
+	if req.Secret != nil && *req.Secret != "" && *req.Secret != "******" {
+		receiver.Secret = *req.Secret
+	}
+	if req.Rules != nil {
+		if err := validateRules(req.Rules); err != nil {
+			common.Fail(c, pkgerrors.NewBadRequest(err.Error()))
+			return
+		}
+		receiver.Rules = req.Rules
+	}
+
+	if err := saveReceiver(c, secret, receiver); err != nil {
+		klog.ErrorS(err, "Failed to update webhook receiver", "name", receiver.Name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, receiver.redacted())
+}
+
+// handleDeleteReceiver removes a webhook receiver
+func handleDeleteReceiver(c *gin.Context) {
+	name := c.Param("name")
+	err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Delete(c, receiverSecretName(name), metav1.DeleteOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			common.Fail(c, pkgerrors.NewNotFound("Webhook receiver not found"))
+			return
+		}
+		klog.ErrorS(err, "Failed to delete webhook receiver", "name", name)
+		common.Fail(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "Webhook receiver deleted successfully"})
+}
+
+func init() {
+	r := router.V1()
+	// The receiving endpoint is called by external systems and authenticated by the receiver secret
+	r.POST("/webhooks/:source", handleReceiveWebhook)
+
+	admin := r.Group("/webhook-receivers", router.EnsureMgmtAdminMiddleware())
+	admin.GET("", handleGetReceivers)
+	admin.POST("", handleCreateReceiver)
+	admin.GET("/:name", handleGetReceiver)
+	admin.PUT("/:name", handleUpdateReceiver)
+	admin.DELETE("/:name", handleDeleteReceiver)
+	r.GET("/webhook-deliveries", router.EnsureMgmtAdminMiddleware(), handleGetDeliveries)
+}
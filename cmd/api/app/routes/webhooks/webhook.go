@@ -0,0 +1,361 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/backup"
+	"github.com/karmada-io/dashboard/cmd/api/app/routes/member/argocd"
+	"github.com/karmada-io/dashboard/cmd/api/app/types/common"
+)
+
+const (
+	maxPayloadSize  = 5 << 20
+	actionTimeout   = 2 * time.Minute
+	deliveryLogSize = 200
+)
+
+// Event is the part of an external event the rules match on
+type Event struct {
+	Type       string `json:"type"`
+	Repository string `json:"repository,omitempty"`
+	// Ref is the branch or tag of GitHub and GitLab events
+	Ref string `json:"ref,omitempty"`
+	// Tags are the pushed tags of Harbor events
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ActionResult is the outcome of the action of a matched rule
+type ActionResult struct {
+	Rule   string `json:"rule"`
+	Action Action `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DeliveryRecord is an entry of the log of received webhooks
+type DeliveryRecord struct {
+	ID         string         `json:"id"`
+	Receiver   string         `json:"receiver"`
+	Event      Event          `json:"event"`
+	Results    []ActionResult `json:"results"`
+	ReceivedAt string         `json:"receivedAt"`
+}
+
+// deliveryLog keeps the most recent webhooks that matched a rule in memory
+var deliveryLog = struct {
+	sync.Mutex
+	records []DeliveryRecord
+}{}
+
+func recordDelivery(record DeliveryRecord) {
+	deliveryLog.Lock()
+	defer deliveryLog.Unlock()
+	deliveryLog.records = append(deliveryLog.records, record)
+	if len(deliveryLog.records) > deliveryLogSize {
+		deliveryLog.records = deliveryLog.records[len(deliveryLog.records)-deliveryLogSize:]
+	}
+}
+
+// verifyRequest checks the signature or token the external system sends with the receiver secret
+func verifyRequest(receiver *Receiver, header http.Header, body []byte) error {
+	switch receiver.Type {
+	case ReceiverTypeGitHub:
+		signature := strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		expected, err := hex.DecodeString(signature)
+		if err != nil || signature == "" {
+			return fmt.Errorf("missing or malformed X-Hub-Signature-256 header")
+		}
+		mac := hmac.New(sha256.New, []byte(receiver.Secret))
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), expected) {
+			return fmt.Errorf("invalid signature")
+		}
+	case ReceiverTypeGitLab:
+		if !secretEqual(header.Get("X-Gitlab-Token"), receiver.Secret) {
+			return fmt.Errorf("invalid X-Gitlab-Token header")
+		}
+	case ReceiverTypeHarbor:
+		// Harbor sends the auth header of the webhook policy as is, with or without a scheme
+		authorization := header.Get("Authorization")
+		if !secretEqual(authorization, receiver.Secret) && !secretEqual(strings.TrimPrefix(authorization, "Bearer "), receiver.Secret) {
+			return fmt.Errorf("invalid Authorization header")
+		}
+	default:
+		return fmt.Errorf("unsupported receiver type %s", receiver.Type)
+	}
+	return nil
+}
+
+func secretEqual(value, secret string) bool {
+	return value != "" && subtle.ConstantTimeCompare([]byte(value), []byte(secret)) == 1
+}
+
+// parseEvent extracts the event type, repository and refs from the payload
+func parseEvent(receiverType string, header http.Header, body []byte) (Event, error) {
+	switch receiverType {
+	case ReceiverTypeGitHub:
+		var payload struct {
+			Ref        string `json:"ref"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Release struct {
+				TagName string `json:"tag_name"`
+			} `json:"release"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return Event{}, err
+		}
+		event := Event{Type: header.Get("X-GitHub-Event"), Repository: payload.Repository.FullName, Ref: shortRef(payload.Ref)}
+		if event.Ref == "" {
+			event.Ref = payload.Release.TagName
+		}
+		return event, nil
+	case ReceiverTypeGitLab:
+		var payload struct {
+			ObjectKind string `json:"object_kind"`
+			Ref        string `json:"ref"`
+			Project    struct {
+				PathWithNamespace string `json:"path_with_namespace"`
+			} `json:"project"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return Event{}, err
+		}
+		return Event{Type: payload.ObjectKind, Repository: payload.Project.PathWithNamespace, Ref: shortRef(payload.Ref)}, nil
+	case ReceiverTypeHarbor:
+		var payload struct {
+			Type      string `json:"type"`
+			EventData struct {
+				Resources []struct {
+					Tag string `json:"tag"`
+				} `json:"resources"`
+				Repository struct {
+					RepoFullName string `json:"repo_full_name"`
+				} `json:"repository"`
+			} `json:"event_data"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return Event{}, err
+		}
+		event := Event{Type: payload.Type, Repository: payload.EventData.Repository.RepoFullName}
+		for _, resource := range payload.EventData.Resources {
+			if resource.Tag != "" {
+				event.Tags = append(event.Tags, resource.Tag)
+			}
+		}
+		return event, nil
+	}
+	return Event{}, fmt.Errorf("unsupported receiver type %s", receiverType)
+}
+
+// shortRef strips refs/heads/ and refs/tags/ from git refs
+func shortRef(ref string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+}
+
+// matches reports whether the rule applies to the event
+func (r Rule) matches(event Event) bool {
+	if len(r.Events) > 0 {
+		found := false
+		for _, eventType := range r.Events {
+			if strings.EqualFold(eventType, event.Type) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Repository != "" {
+		if ok, _ := path.Match(r.Repository, event.Repository); !ok {
+			return false
+		}
+	}
+	if r.Ref != "" {
+		refs := event.Tags
+		if event.Ref != "" {
+			refs = append([]string{event.Ref}, refs...)
+		}
+		for _, ref := range refs {
+			if ok, _ := path.Match(r.Ref, ref); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// runAction performs the action of a matched rule
+func runAction(ctx context.Context, action Action) error {
+	switch action.Type {
+	case ActionArgoCDSync:
+		return argocd.SyncApplication(ctx, action.Cluster, action.Application)
+	case ActionBackup:
+		return backup.ExecuteBackup(ctx, action.BackupID)
+	case ActionCacheRefresh:
+		return backup.RefreshMigrationCache(ctx, action.Cluster)
+	}
+	return fmt.Errorf("unsupported action %q", action.Type)
+}
+
+// runRules performs the actions of the rules that match the event and records the outcome
+func runRules(ctx context.Context, record DeliveryRecord, rules []Rule) {
+	for _, rule := range rules {
+		result := ActionResult{Rule: rule.Name, Action: rule.Action}
+		if err := runAction(ctx, rule.Action); err != nil {
+			result.Error = err.Error()
+			klog.ErrorS(err, "Webhook action failed", "receiver", record.Receiver, "rule", rule.Name, "action", rule.Action.Type)
+		} else {
+			klog.InfoS("Webhook action completed", "receiver", record.Receiver, "rule", rule.Name, "action", rule.Action.Type)
+		}
+		record.Results = append(record.Results, result)
+	}
+	recordDelivery(record)
+}
+
+// handleReceiveWebhook verifies an event of an external system and runs the actions of the matching rules.
+// The actions run in the background so that the sender does not time out, their outcome is in the delivery log.
+func handleReceiveWebhook(c *gin.Context) {
+	_, receiver, err := getReceiverSecret(c, c.Param("source"))
+	if err != nil {
+		common.FailWithStatus(c, fmt.Errorf("webhook receiver not found"), http.StatusNotFound)
+		return
+	}
+	if !receiver.Enabled {
+		common.FailWithStatus(c, fmt.Errorf("webhook receiver %s is disabled", receiver.Name), http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPayloadSize+1))
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPayloadSize {
+		common.FailWithStatus(c, fmt.Errorf("payload exceeds %d bytes", maxPayloadSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := verifyRequest(receiver, c.Request.Header, body); err != nil {
+		klog.InfoS("Rejected webhook", "receiver", receiver.Name, "reason", err.Error(), "remoteAddr", c.ClientIP())
+		common.FailWithStatus(c, err, http.StatusUnauthorized)
+		return
+	}
+	event, err := parseEvent(receiver.Type, c.Request.Header, body)
+	if err != nil {
+		common.FailWithStatus(c, fmt.Errorf("failed to parse payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// GitHub pings new webhooks to check the endpoint, which must not trigger rules matching any event
+	if receiver.Type == ReceiverTypeGitHub && event.Type == "ping" {
+		common.Success(c, gin.H{"matchedRules": 0})
+		return
+	}
+
+	var matched []Rule
+	for _, rule := range receiver.Rules {
+		if rule.matches(event) {
+			matched = append(matched, rule)
+		}
+	}
+	klog.InfoS("Received webhook", "receiver", receiver.Name, "event", event.Type, "repository", event.Repository,
+		"ref", event.Ref, "tags", event.Tags, "matchedRules", len(matched))
+	if len(matched) == 0 {
+		common.Success(c, gin.H{"matchedRules": 0})
+		return
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		common.FailWithStatus(c, err, http.StatusInternalServerError)
+		return
+	}
+	record := DeliveryRecord{
+		ID:         id,
+		Receiver:   receiver.Name,
+		Event:      event,
+		Results:    []ActionResult{},
+		ReceivedAt: time.Now().Format(time.RFC3339),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+		runRules(ctx, record, matched)
+	}()
+	c.JSON(http.StatusAccepted, common.BaseResponse{
+		Code: 200,
+		Msg:  "success",
+		Data: gin.H{
+			"deliveryId":   record.ID,
+			"matchedRules": len(matched),
+		},
+	})
+}
+
+// handleGetDeliveries returns the log of received webhooks that matched a rule, newest first.
+// Supports filtering by receiver and by deliveries with a failed action.
+func handleGetDeliveries(c *gin.Context) {
+	receiverFilter := c.Query("receiver")
+	failedOnly := c.Query("failed") == "true"
+
+	deliveryLog.Lock()
+	records := make([]DeliveryRecord, 0, len(deliveryLog.records))
+	for i := len(deliveryLog.records) - 1; i >= 0; i-- {
+		record := deliveryLog.records[i]
+		if receiverFilter != "" && record.Receiver != receiverFilter {
+			continue
+		}
+		if failedOnly && !hasFailedAction(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+	deliveryLog.Unlock()
+
+	common.Success(c, gin.H{
+		"deliveries": records,
+		"totalItems": len(records),
+	})
+}
+
+func hasFailedAction(record DeliveryRecord) bool {
+	for _, result := range record.Results {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
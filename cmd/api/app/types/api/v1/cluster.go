@@ -19,6 +19,8 @@ package v1
 import (
 	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
 )
 
 // PostClusterRequest is the request body for creating a cluster.
@@ -37,6 +39,17 @@ type PostClusterRequest struct {
 type PostClusterResponse struct {
 }
 
+// PostClusterJoinRequest is the request body for registering a Push mode cluster
+// with the ServiceAccount token created by the join manifest.
+type PostClusterJoinRequest struct {
+	MemberClusterName     string `json:"memberClusterName" binding:"required"`
+	MemberClusterEndpoint string `json:"memberClusterEndpoint" binding:"required"`
+	Token                 string `json:"token" binding:"required"`
+	// CAData is the base64 encoded CA bundle of the member cluster API server
+	CAData                string `json:"caData"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify"`
+}
+
 // LabelRequest is the request body for labeling a cluster.
 type LabelRequest struct {
 	Key   string `json:"key"`
@@ -50,10 +63,25 @@ type TaintRequest struct {
 	Value  string             `json:"value"`
 }
 
+// PutClusterMode selects how PutClusterRequest is applied to the cluster.
+type PutClusterMode string
+
+const (
+	// PutClusterModeReplace replaces the labels and taints with the ones in the request.
+	PutClusterModeReplace PutClusterMode = "replace"
+	// PutClusterModeMerge adds or updates the labels and taints in the request and removes the listed ones.
+	PutClusterModeMerge PutClusterMode = "merge"
+)
+
 // PutClusterRequest is the request body for updating a cluster.
 type PutClusterRequest struct {
+	// Mode defaults to replace
+	Mode   PutClusterMode  `json:"mode"`
 	Labels *[]LabelRequest `json:"labels"`
 	Taints *[]TaintRequest `json:"taints"`
+	// RemoveLabels and RemoveTaints are only used in merge mode
+	RemoveLabels []string       `json:"removeLabels"`
+	RemoveTaints []TaintRequest `json:"removeTaints"`
 }
 
 // PutClusterResponse is the response body for updating a cluster.
@@ -67,3 +95,27 @@ type DeleteClusterRequest struct {
 // DeleteClusterResponse is the response body for deleting a cluster.
 type DeleteClusterResponse struct {
 }
+
+// SimulatePlacementRequest is the request body for simulating the placement of a workload on the member clusters.
+// CPU, memory and GPU are the requests of each replica as Kubernetes quantities.
+type SimulatePlacementRequest struct {
+	// Replicas defaults to 1
+	Replicas     int32               `json:"replicas"`
+	CPU          string              `json:"cpu"`
+	Memory       string              `json:"memory"`
+	GPU          string              `json:"gpu"`
+	NodeSelector map[string]string   `json:"nodeSelector"`
+	Tolerations  []corev1.Toleration `json:"tolerations"`
+	// Clusters restricts the simulation to these clusters, all accessible clusters are evaluated when empty
+	Clusters []string `json:"clusters"`
+	// Limit is the maximum number of candidates returned, defaults to 3
+	Limit int `json:"limit"`
+}
+
+// SimulatePlacementResponse is the response body for simulating the placement of a workload.
+type SimulatePlacementResponse struct {
+	// Candidates are the clusters the workload fits in, best first
+	Candidates []cluster.PlacementResult `json:"candidates"`
+	// Clusters are the results of all evaluated clusters
+	Clusters []cluster.PlacementResult `json:"clusters"`
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// Actions of a declarative settings change
+const (
+	SettingsActionCreate    = "create"
+	SettingsActionUpdate    = "update"
+	SettingsActionDelete    = "delete"
+	SettingsActionUnchanged = "unchanged"
+)
+
+// DeclarativeSettings describes the desired configuration of the dashboard. A nil section is left as it is;
+// a present section, even empty, is managed and its undeclared entries are deleted when Prune is set.
+// Secrets that are empty keep their current value, so a document can be applied without them.
+type DeclarativeSettings struct {
+	APIVersion           string                        `json:"apiVersion,omitempty"`
+	Kind                 string                        `json:"kind,omitempty"`
+	Prune                bool                          `json:"prune,omitempty"`
+	Registries           []DeclaredRegistry            `json:"registries,omitempty"`
+	Monitoring           []DeclaredMonitoring          `json:"monitoring,omitempty"`
+	Controllers          []DeclaredController          `json:"controllers,omitempty"`
+	NotificationChannels []DeclaredNotificationChannel `json:"notificationChannels,omitempty"`
+}
+
+// DeclaredRegistry is a container registry for backups, identified by name
+type DeclaredRegistry struct {
+	Name        string `json:"name"`
+	Registry    string `json:"registry"`
+	Username    string `json:"username"`
+	Password    string `json:"password,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// DeclaredMonitoring is a Grafana or Prometheus endpoint, identified by name
+type DeclaredMonitoring struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // "grafana" or "prometheus"
+	Endpoint string `json:"endpoint"`
+	// Cluster is the member cluster a Prometheus endpoint monitors
+	Cluster string `json:"cluster,omitempty"`
+	Token   string `json:"token,omitempty"`
+}
+
+// DeclaredController is a migration controller install on a cluster
+type DeclaredController struct {
+	Cluster string `json:"cluster"`
+	// Version is the controller version, an empty version accepts any installed version and installs the latest
+	Version string `json:"version,omitempty"`
+}
+
+// DeclaredNotificationChannel is a notification channel, identified by name
+type DeclaredNotificationChannel struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // "webhook", "slack" or "email"
+	Description string   `json:"description,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"` // defaults to true
+	Events      []string `json:"events,omitempty"`
+	Projects    []string `json:"projects,omitempty"` // ArgoCD projects of the application events, all when empty
+	// Config holds the type specific settings, as in the notification channel API
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+// SettingsChange is a difference between the declared and the current settings and the outcome of applying it
+type SettingsChange struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import "github.com/karmada-io/dashboard/pkg/resource/drift"
+
+// PostDriftRepairRequest is the request to repair the drift of a member cluster
+type PostDriftRepairRequest struct {
+	Cluster   string `json:"cluster" binding:"required"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	// Objects limits the repair to the listed objects, all drifted objects are repaired when empty
+	Objects []drift.ObjectRef `json:"objects"`
+	// DeleteExtra deletes objects that are managed by Karmada but no longer declared
+	DeleteExtra bool `json:"deleteExtra"`
+}
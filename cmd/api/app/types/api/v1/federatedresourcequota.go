@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// StaticQuotaAssignment is the part of a federated quota enforced in one member cluster.
+type StaticQuotaAssignment struct {
+	ClusterName string            `json:"clusterName" binding:"required"`
+	Hard        map[string]string `json:"hard" binding:"required"`
+}
+
+// PostFederatedResourceQuotaRequest is the request body for creating a federated resource quota.
+// Limits are resource quantities by resource name, e.g. {"requests.cpu": "64", "requests.nvidia.com/gpu": "8"}.
+type PostFederatedResourceQuotaRequest struct {
+	Namespace         string                  `json:"namespace" binding:"required"`
+	Name              string                  `json:"name" binding:"required"`
+	Overall           map[string]string       `json:"overall" binding:"required"`
+	StaticAssignments []StaticQuotaAssignment `json:"staticAssignments"`
+}
+
+// PutFederatedResourceQuotaRequest is the request body for updating the limits of a federated resource quota.
+type PutFederatedResourceQuotaRequest struct {
+	Overall           map[string]string       `json:"overall" binding:"required"`
+	StaticAssignments []StaticQuotaAssignment `json:"staticAssignments"`
+}
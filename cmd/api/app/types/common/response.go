@@ -17,9 +17,15 @@ limitations under the License.
 package common
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/karmada-io/dashboard/pkg/multicluster"
+	"github.com/karmada-io/dashboard/pkg/util/validation"
 )
 
 // BaseResponse is the base response
@@ -41,18 +47,64 @@ func Fail(c *gin.Context, err error) {
 
 // FailWithStatus generates a fail response with a custom HTTP status code
 func FailWithStatus(c *gin.Context, err error, httpStatus int) {
-	code := 500          // biz status code
-	message := "error"   // biz status message
+	FailWithData(c, err, httpStatus, nil)
+}
+
+// FailWithData generates a fail response with a custom HTTP status code and details of the failure as data
+func FailWithData(c *gin.Context, err error, httpStatus int, data interface{}) {
+	code := 500        // biz status code
+	message := "error" // biz status message
 	if err != nil {
 		message = err.Error()
+		// Keep the error for the request log
+		_ = c.Error(err)
 	}
 	c.JSON(httpStatus, BaseResponse{
 		Code: code,
 		Msg:  message,
-		Data: nil,
+		Data: data,
 	})
 }
 
+// ValidationFailure is the data of a response to a request that failed validation
+type ValidationFailure struct {
+	Errors []validation.FieldError `json:"errors"`
+}
+
+// FailWithBindError generates a 400 fail response for a request body or query that failed to bind. Validation
+// failures list the errors of each field so that the UI can show them next to the fields.
+func FailWithBindError(c *gin.Context, err error) {
+	if fields := validation.FieldErrors(err); len(fields) > 0 {
+		FailWithData(c, errors.New(validation.Summary(fields)), http.StatusBadRequest, ValidationFailure{Errors: fields})
+		return
+	}
+	FailWithStatus(c, fmt.Errorf("invalid request: %w", err), http.StatusBadRequest)
+}
+
+// SuccessWithClusterErrors generates a success response for data gathered from several member clusters.
+// The errors field added to the data lists the clusters whose data is missing and why, so the UI can tell
+// them from clusters without data. The data must encode as a JSON object, its items keep their field.
+func SuccessWithClusterErrors(c *gin.Context, data interface{}, clusterErrors []multicluster.ClusterError) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		Fail(c, err)
+		return
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		Fail(c, fmt.Errorf("multi-cluster response is not an object: %w", err))
+		return
+	}
+	if clusterErrors == nil {
+		clusterErrors = []multicluster.ClusterError{}
+	}
+	if fields["errors"], err = json.Marshal(clusterErrors); err != nil {
+		Fail(c, err)
+		return
+	}
+	Success(c, fields)
+}
+
 // Response generate response
 func Response(c *gin.Context, err error, data interface{}) {
 	code := 200          // biz status code
@@ -60,6 +112,7 @@ func Response(c *gin.Context, err error, data interface{}) {
 	if err != nil {
 		code = 500
 		message = err.Error()
+		_ = c.Error(err)
 	}
 	c.JSON(http.StatusOK, BaseResponse{
 		Code: code,
@@ -0,0 +1,39 @@
+#!/usr/bin/env bash
+# copyright 2024 the karmada authors.
+#
+# licensed under the apache license, version 2.0 (the "license");
+# you may not use this file except in compliance with the license.
+# you may obtain a copy of the license at
+#
+#     http://www.apache.org/licenses/license-2.0
+#
+# unless required by applicable law or agreed to in writing, software
+# distributed under the license is distributed on an "as is" basis,
+# without warranties or conditions of any kind, either express or implied.
+# see the license for the specific language governing permissions and
+# limitations under the license.
+
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Generates the protobuf messages, the gRPC services and the gRPC-gateway handlers of proto/
+# into pkg/apis with buf.
+
+REPO_ROOT=$(dirname "${BASH_SOURCE[0]}")/..
+BUF_VER="v1.45.0"
+
+cd "${REPO_ROOT}/proto"
+
+if [ -x "$(command -v buf)" ]; then
+  echo "Using buf version: $(buf --version)"
+else
+  echo "Installing buf ${BUF_VER}"
+  go install "github.com/bufbuild/buf/cmd/buf@${BUF_VER}"
+fi
+
+buf dep update
+buf lint
+buf generate
+echo 'Generated the gRPC API into pkg/apis.'
@@ -0,0 +1,381 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the changes made through the API: who made them, what they did and the clusters and
+// users they affected, for the activity feeds of the clusters and users. Entries are buffered by each replica
+// and appended in batches to a ConfigMap that keeps the most recent ones.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// logConfigName stores the audit log in the dashboard namespace
+	logConfigName = "ml-platform-admin-audit-log"
+	logKey        = "entries"
+	// MaxEntries is the number of entries the audit log keeps
+	MaxEntries = 1000
+)
+
+// Actions of the entries
+const (
+	ActionInstall     = "install"
+	ActionUpgrade     = "upgrade"
+	ActionUninstall   = "uninstall"
+	ActionBackup      = "backup"
+	ActionRecovery    = "recovery"
+	ActionMigration   = "migration"
+	ActionSettings    = "settings"
+	ActionArgoCDSync  = "argocd-sync"
+	ActionArgoCD      = "argocd"
+	ActionCredentials = "credentials"
+	ActionRoleChange  = "role-change"
+	ActionUser        = "user"
+	ActionCluster     = "cluster"
+	ActionChange      = "change"
+)
+
+// Keys of the request context that handlers set to describe their change, see SetClusters, SetSubject and
+// SetAction
+const (
+	clustersKey = "auditClusters"
+	subjectKey  = "auditSubject"
+	actionKey   = "auditAction"
+)
+
+// Entry is a change made through the API
+type Entry struct {
+	Time string `json:"time"`
+	User string `json:"user"`
+	// Impersonator is the admin who made the change as User
+	Impersonator string `json:"impersonator,omitempty"`
+	Action       string `json:"action"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Success      bool   `json:"success"`
+	// Clusters are the clusters the change affected
+	Clusters []string `json:"clusters,omitempty"`
+	// Subject is the user the change affected, like the user whose roles changed
+	Subject string `json:"subject,omitempty"`
+}
+
+// HasCluster reports whether the change affected a cluster
+func (e Entry) HasCluster(clusterName string) bool {
+	for _, name := range e.Clusters {
+		if name == clusterName {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify returns the action of a request from its method and the route it matched
+func Classify(method, route string) string {
+	switch {
+	case strings.HasSuffix(route, "/install-controller"):
+		return ActionInstall
+	case strings.HasSuffix(route, "/upgrade-controller"):
+		return ActionUpgrade
+	case strings.HasSuffix(route, "/uninstall-controller"):
+		return ActionUninstall
+	case strings.Contains(route, "/argocd/") && strings.HasSuffix(route, "/sync"):
+		return ActionArgoCDSync
+	case strings.Contains(route, "/argocd/"):
+		return ActionArgoCD
+	case strings.HasSuffix(route, "/rotate-credentials"):
+		return ActionCredentials
+	case strings.HasPrefix(route, "/api/v1/backup/recovery"):
+		return ActionRecovery
+	case strings.HasPrefix(route, "/api/v1/backup/settings"):
+		return ActionSettings
+	case strings.HasPrefix(route, "/api/v1/backup"):
+		return ActionBackup
+	case strings.HasPrefix(route, "/api/v1/migration"):
+		return ActionMigration
+	case strings.HasPrefix(route, "/api/v1/role-mappings"):
+		return ActionRoleChange
+	case strings.HasPrefix(route, "/api/v1/users"):
+		return ActionUser
+	case strings.HasPrefix(route, "/api/v1/cluster"), strings.HasPrefix(route, "/api/v1/member/"):
+		return ActionCluster
+	}
+	return ActionChange
+}
+
+// SetClusters records the clusters the change of a request affects, besides the cluster of its path
+func SetClusters(c *gin.Context, clusters ...string) {
+	existing := c.GetStringSlice(clustersKey)
+	c.Set(clustersKey, append(existing, clusters...))
+}
+
+// RequestClusters returns the clusters set with SetClusters
+func RequestClusters(c *gin.Context) []string {
+	return c.GetStringSlice(clustersKey)
+}
+
+// SetSubject records the user the change of a request affects
+func SetSubject(c *gin.Context, user string) {
+	c.Set(subjectKey, user)
+}
+
+// RequestSubject returns the user set with SetSubject
+func RequestSubject(c *gin.Context) string {
+	return c.GetString(subjectKey)
+}
+
+// SetAction overrides the action of a request, when the route alone does not tell it
+func SetAction(c *gin.Context, action string) {
+	c.Set(actionKey, action)
+}
+
+// RequestAction returns the action set with SetAction
+func RequestAction(c *gin.Context) string {
+	return c.GetString(actionKey)
+}
+
+// Log buffers the entries of a replica and appends them to the ConfigMap of the audit log
+type Log struct {
+	client    kubernetes.Interface
+	namespace string
+	mu        sync.Mutex
+	pending   []Entry
+}
+
+// NewLog returns the audit log stored in the namespace
+func NewLog(client kubernetes.Interface, namespace string) *Log {
+	return &Log{client: client, namespace: namespace}
+}
+
+// Record buffers an entry until the next flush. The oldest entries are dropped when the ConfigMap cannot be
+// written for long.
+func (l *Log) Record(entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, entry)
+	if len(l.pending) > MaxEntries {
+		l.pending = l.pending[len(l.pending)-MaxEntries:]
+	}
+}
+
+// Flush appends the buffered entries to the ConfigMap. They stay buffered when it fails.
+func (l *Log) Flush(ctx context.Context) error {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	configMaps := l.client.CoreV1().ConfigMaps(l.namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, logConfigName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			data, err := encode(pending)
+			if err != nil {
+				return err
+			}
+			_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: logConfigName, Namespace: l.namespace},
+				Data:       map[string]string{logKey: data},
+			}, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica created it first, append to theirs
+				return apierrors.NewConflict(corev1.Resource("configmaps"), logConfigName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		entries, err := decode(cm)
+		if err != nil {
+			return err
+		}
+		data, err := encode(append(entries, pending...))
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[logKey] = data
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		l.mu.Lock()
+		l.pending = append(pending, l.pending...)
+		l.mu.Unlock()
+	}
+	return err
+}
+
+// Entries returns the entries of the audit log, including the ones this replica did not flush yet, newest first
+func (l *Log) Entries(ctx context.Context) ([]Entry, error) {
+	cm, err := l.client.CoreV1().ConfigMaps(l.namespace).Get(ctx, logConfigName, metav1.GetOptions{})
+	entries := []Entry{}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		if entries, err = decode(cm); err != nil {
+			return nil, err
+		}
+	}
+	l.mu.Lock()
+	entries = append(entries, l.pending...)
+	l.mu.Unlock()
+
+	// Replicas flush their batches independently, so the entries are sorted by time
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time > entries[j].Time })
+	return entries, nil
+}
+
+// encode returns the last MaxEntries entries as the data of the ConfigMap
+func encode(entries []Entry) (string, error) {
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	data, err := json.Marshal(entries)
+	return string(data), err
+}
+
+func decode(cm *corev1.ConfigMap) ([]Entry, error) {
+	entries := []Entry{}
+	if data, ok := cm.Data[logKey]; ok {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log: %v", err)
+		}
+	}
+	return entries, nil
+}
+
+// DefaultFeedLimit is the number of entries of an activity feed when the request sets no limit
+const DefaultFeedLimit = 100
+
+// ParseFeedLimit parses the limit query parameter of an activity feed
+func ParseFeedLimit(value string) (int, error) {
+	if value == "" {
+		return DefaultFeedLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 || limit > MaxEntries {
+		return 0, fmt.Errorf("limit must be between 1 and %d", MaxEntries)
+	}
+	return limit, nil
+}
+
+// Filter returns the first entries matching a filter, at most limit of them when limit is positive
+func Filter(entries []Entry, match func(Entry) bool, limit int) []Entry {
+	filtered := []Entry{}
+	for _, entry := range entries {
+		if limit > 0 && len(filtered) == limit {
+			break
+		}
+		if match(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// checkRoutes are the last segments of the routes that only check something, like a connectivity test
+var checkRoutes = map[string]bool{
+	"test":               true,
+	"preflight":          true,
+	"connectivity-test":  true,
+	"simulate-placement": true,
+}
+
+// Recorded reports whether requests with the method to the route change something and are recorded
+func Recorded(method, route string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !checkRoutes[route[strings.LastIndex(route, "/")+1:]]
+}
+
+// defaultLog is the audit log of the API, nil until Start is called
+var defaultLog *Log
+
+// Start records the changes made through the API in the audit log of the namespace, flushing the entries of
+// this replica every interval. A non-positive interval disables the audit log.
+func Start(ctx context.Context, client kubernetes.Interface, namespace string, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Audit log is disabled")
+		return
+	}
+	log := NewLog(client, namespace)
+	defaultLog = log
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// Entries of the last interval are written before the replica stops
+				if err := log.Flush(context.Background()); err != nil {
+					klog.ErrorS(err, "Failed to flush audit log")
+				}
+				return
+			case <-ticker.C:
+				if err := log.Flush(ctx); err != nil {
+					klog.ErrorS(err, "Failed to flush audit log")
+				}
+			}
+		}
+	}()
+	klog.InfoS("Audit log started", "flushInterval", interval)
+}
+
+// Enabled reports whether the audit log was started
+func Enabled() bool {
+	return defaultLog != nil
+}
+
+// Record adds an entry to the audit log, it is dropped when the audit log is disabled
+func Record(entry Entry) {
+	if defaultLog != nil {
+		defaultLog.Record(entry)
+	}
+}
+
+// Entries returns the entries of the audit log, newest first
+func Entries(ctx context.Context) ([]Entry, error) {
+	if defaultLog == nil {
+		return nil, fmt.Errorf("audit log is disabled")
+	}
+	return defaultLog.Entries(ctx)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		method   string
+		route    string
+		expected string
+	}{
+		{"POST", "/api/v1/backup/settings/clusters/install-controller", ActionInstall},
+		{"POST", "/api/v1/backup/settings/clusters/uninstall-controller", ActionUninstall},
+		{"PUT", "/api/v1/backup/settings/throttle", ActionSettings},
+		{"POST", "/api/v1/member/:clustername/argocd/application/:applicationName/sync", ActionArgoCDSync},
+		{"DELETE", "/api/v1/member/:clustername/argocd/application/:applicationName", ActionArgoCD},
+		{"POST", "/api/v1/backup/recovery/:id/execute", ActionRecovery},
+		{"POST", "/api/v1/backup/:id/execute", ActionBackup},
+		{"POST", "/api/v1/migration", ActionMigration},
+		{"POST", "/api/v1/cluster/:name/rotate-credentials", ActionCredentials},
+		{"PUT", "/api/v1/role-mappings/:id", ActionRoleChange},
+		{"DELETE", "/api/v1/users/:id", ActionUser},
+		{"DELETE", "/api/v1/cluster/:name", ActionCluster},
+		{"POST", "/api/v1/apitokens", ActionChange},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.method, tt.route); got != tt.expected {
+			t.Errorf("Classify(%s, %s) = %s, expected %s", tt.method, tt.route, got, tt.expected)
+		}
+	}
+}
+
+func TestRecorded(t *testing.T) {
+	if Recorded("GET", "/api/v1/cluster/:name") {
+		t.Error("GET requests are recorded")
+	}
+	if Recorded("POST", "/api/v1/cluster/:name/test") {
+		t.Error("connectivity tests are recorded")
+	}
+	if !Recorded("POST", "/api/v1/backup/:id/execute") {
+		t.Error("backup executions are not recorded")
+	}
+}
+
+func TestLog(t *testing.T) {
+	ctx := context.TODO()
+	client := kubefake.NewSimpleClientset()
+	first := NewLog(client, "dashboard")
+	second := NewLog(client, "dashboard")
+
+	first.Record(Entry{Time: "2024-05-01T12:00:00Z", User: "alice", Action: ActionInstall, Clusters: []string{"member1"}})
+	second.Record(Entry{Time: "2024-05-01T12:01:00Z", User: "bob", Action: ActionBackup, Clusters: []string{"member2"}})
+	if err := first.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries not flushed yet are returned by their replica
+	entries, err := second.Entries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].User != "bob" || entries[1].User != "alice" {
+		t.Fatalf("Entries() = %+v, expected bob then alice", entries)
+	}
+	if err := second.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := first.Entries(ctx); len(entries) != 2 {
+		t.Errorf("Entries() after both flushes = %d entries, expected 2", len(entries))
+	}
+
+	member1 := Filter(entries, func(e Entry) bool { return e.HasCluster("member1") }, 0)
+	if len(member1) != 1 || member1[0].User != "alice" {
+		t.Errorf("Filter() = %+v", member1)
+	}
+}
+
+func TestLogKeepsMaxEntries(t *testing.T) {
+	ctx := context.TODO()
+	log := NewLog(kubefake.NewSimpleClientset(), "dashboard")
+	for i := 0; i < MaxEntries+10; i++ {
+		log.Record(Entry{Time: fmt.Sprintf("2024-05-01T12:%02d:%02dZ", i/60%60, i%60), User: "alice"})
+		if i%100 == 0 {
+			if err := log.Flush(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := log.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := log.Entries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Errorf("Entries() = %d entries, expected %d", len(entries), MaxEntries)
+	}
+}
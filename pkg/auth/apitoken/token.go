@@ -0,0 +1,335 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apitoken manages long-lived API tokens for automation clients.
+// A token authenticates as its own principal, which is bound to OpenFGA cluster relations
+// like a user, and can additionally be limited to read-only requests and API path prefixes.
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	// Prefix identifies API tokens among bearer tokens
+	Prefix = "mlp_"
+
+	tokenLabelKey = "ml-platform.io/api-token"
+	tokenDataKey  = "token"
+	hashDataKey   = "hash"
+
+	// lastUsedInterval limits how often the last use of a token is written back
+	lastUsedInterval = 5 * time.Minute
+)
+
+// Scope grants the token a relation on a cluster
+type Scope struct {
+	Cluster  string `json:"cluster"`
+	Relation string `json:"relation"` // "owner" or "member"
+}
+
+// Token is an API token without its secret
+type Token struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Principal is the user name the token is authorized as
+	Principal string  `json:"principal"`
+	Scopes    []Scope `json:"scopes"`
+	// ReadOnly restricts the token to GET and HEAD requests
+	ReadOnly bool `json:"readOnly"`
+	// PathPrefixes restricts the token to API paths with one of the prefixes, e.g. /api/v1/backup
+	PathPrefixes []string `json:"pathPrefixes,omitempty"`
+	CreatedBy    string   `json:"createdBy,omitempty"`
+	CreatedAt    string   `json:"createdAt"`
+	ExpiresAt    string   `json:"expiresAt,omitempty"`
+	LastUsedAt   string   `json:"lastUsedAt,omitempty"`
+}
+
+// CreateRequest represents the request to create an API token
+type CreateRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Description  string   `json:"description"`
+	Scopes       []Scope  `json:"scopes"`
+	ReadOnly     bool     `json:"readOnly"`
+	PathPrefixes []string `json:"pathPrefixes"`
+	// ExpiresIn is a Go duration such as 720h, empty for tokens that do not expire
+	ExpiresIn string `json:"expiresIn"`
+}
+
+// IsAPIToken reports whether a bearer token is an API token
+func IsAPIToken(bearer string) bool {
+	return strings.HasPrefix(bearer, Prefix)
+}
+
+func secretName(id string) string {
+	return fmt.Sprintf("apitoken-%s", id)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
+
+func secretToToken(secret *corev1.Secret) (*Token, error) {
+	token := &Token{}
+	if err := json.Unmarshal(secret.Data[tokenDataKey], token); err != nil {
+		return nil, fmt.Errorf("failed to decode API token %s: %v", secret.Name, err)
+	}
+	return token, nil
+}
+
+// Create mints a token and binds its principal to the scoped cluster relations.
+// The returned bearer token is only available at creation.
+func Create(ctx context.Context, req CreateRequest, createdBy string) (*Token, string, error) {
+	for _, scope := range req.Scopes {
+		if scope.Cluster == "" || !fga.IsValidRelation("cluster", scope.Relation) {
+			return nil, "", fmt.Errorf("invalid scope %s:%s, scopes need a cluster and one of the relations %s",
+				scope.Cluster, scope.Relation, strings.Join(fga.ObjectRelations["cluster"], ", "))
+		}
+	}
+	for _, prefix := range req.PathPrefixes {
+		if !strings.HasPrefix(prefix, "/api/") {
+			return nil, "", fmt.Errorf("path prefix %q must start with /api/", prefix)
+		}
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	token := &Token{
+		ID:           id,
+		Name:         req.Name,
+		Description:  req.Description,
+		Principal:    fmt.Sprintf("apitoken-%s", id),
+		Scopes:       req.Scopes,
+		ReadOnly:     req.ReadOnly,
+		PathPrefixes: req.PathPrefixes,
+		CreatedBy:    createdBy,
+		CreatedAt:    now.Format(time.RFC3339),
+	}
+	if token.Scopes == nil {
+		token.Scopes = []Scope{}
+	}
+	if req.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || expiresIn <= 0 {
+			return nil, "", fmt.Errorf("invalid expiresIn %q", req.ExpiresIn)
+		}
+		token.ExpiresAt = now.Add(expiresIn).Format(time.RFC3339)
+	}
+
+	if len(token.Scopes) > 0 && fga.FGAService == nil {
+		return nil, "", fmt.Errorf("OpenFGA is not configured, scoped tokens cannot be created")
+	}
+	for _, scope := range token.Scopes {
+		if err := fga.FGAService.GetClient().WriteTuple(ctx, token.Principal, scope.Relation, "cluster", scope.Cluster); err != nil {
+			deleteTuples(ctx, token)
+			return nil, "", fmt.Errorf("failed to bind scope %s:%s: %v", scope.Cluster, scope.Relation, err)
+		}
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName(id),
+			Namespace: config.GetNamespace(),
+			Labels:    map[string]string{tokenLabelKey: "true"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			tokenDataKey: data,
+			hashDataKey:  []byte(hashSecret(secret)),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		deleteTuples(ctx, token)
+		return nil, "", fmt.Errorf("failed to store API token: %v", err)
+	}
+	return token, fmt.Sprintf("%s%s_%s", Prefix, id, secret), nil
+}
+
+// List returns all API tokens, newest first
+func List(ctx context.Context) ([]*Token, error) {
+	secrets, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", tokenLabelKey),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API tokens: %v", err)
+	}
+	tokens := make([]*Token, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		token, err := secretToToken(&secrets.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid API token", "secret", secrets.Items[i].Name)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt > tokens[j].CreatedAt
+	})
+	return tokens, nil
+}
+
+// Get returns the API token with the given ID
+func Get(ctx context.Context, id string) (*Token, error) {
+	secret, err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Get(ctx, secretName(id), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("API token %s not found", id)
+		}
+		return nil, err
+	}
+	return secretToToken(secret)
+}
+
+// Revoke deletes a token and the relations of its principal
+func Revoke(ctx context.Context, id string) (*Token, error) {
+	token, err := Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.InClusterClient().CoreV1().Secrets(config.GetNamespace()).Delete(ctx, secretName(id), metav1.DeleteOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to revoke API token: %v", err)
+	}
+	deleteTuples(ctx, token)
+	return token, nil
+}
+
+func deleteTuples(ctx context.Context, token *Token) {
+	if fga.FGAService == nil {
+		return
+	}
+	for _, scope := range token.Scopes {
+		if err := fga.FGAService.GetClient().DeleteTuple(ctx, token.Principal, scope.Relation, "cluster", scope.Cluster); err != nil {
+			klog.V(4).InfoS("Failed to delete API token tuple", "token", token.ID, "cluster", scope.Cluster, "relation", scope.Relation, "error", err)
+		}
+	}
+}
+
+// Validate checks a bearer API token and returns the token it belongs to
+func Validate(ctx context.Context, bearer string) (*Token, error) {
+	return validate(ctx, client.InClusterClient().CoreV1().Secrets(config.GetNamespace()), bearer, time.Now())
+}
+
+func validate(ctx context.Context, secrets corev1client.SecretInterface, bearer string, now time.Time) (*Token, error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(bearer, Prefix), "_")
+	if !IsAPIToken(bearer) || !ok || id == "" || secret == "" {
+		return nil, fmt.Errorf("malformed API token")
+	}
+
+	stored, err := secrets.Get(ctx, secretName(id), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("invalid API token")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(stored.Data[hashDataKey], []byte(hashSecret(secret))) != 1 {
+		return nil, fmt.Errorf("invalid API token")
+	}
+	token, err := secretToToken(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ExpiresAt != "" {
+		if expiresAt, err := time.Parse(time.RFC3339, token.ExpiresAt); err == nil && now.After(expiresAt) {
+			return nil, fmt.Errorf("API token has expired")
+		}
+	}
+
+	lastUsed, err := time.Parse(time.RFC3339, token.LastUsedAt)
+	if err != nil || now.Sub(lastUsed) > lastUsedInterval {
+		token.LastUsedAt = now.Format(time.RFC3339)
+		if data, err := json.Marshal(token); err == nil {
+			stored.Data[tokenDataKey] = data
+			if _, err := secrets.Update(ctx, stored, metav1.UpdateOptions{}); err != nil {
+				klog.V(4).InfoS("Failed to record API token use", "token", token.ID, "error", err)
+			}
+		}
+	}
+	return token, nil
+}
+
+// Authorize checks a request against the read-only flag, path prefixes and cluster scopes of the token.
+// cluster is the member cluster the request targets, empty if none.
+func (t *Token) Authorize(method, path, cluster string) error {
+	if t.ReadOnly && method != http.MethodGet && method != http.MethodHead {
+		return fmt.Errorf("API token %s is read-only", t.Name)
+	}
+	if len(t.PathPrefixes) > 0 {
+		allowed := false
+		for _, prefix := range t.PathPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("API token %s is not allowed to access %s", t.Name, path)
+		}
+	}
+	if cluster != "" && cluster != "mgmt-cluster" {
+		for _, scope := range t.Scopes {
+			if scope.Cluster == cluster {
+				return nil
+			}
+		}
+		return fmt.Errorf("API token %s has no access to cluster %s", t.Name, cluster)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apitoken
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func storedToken(t *testing.T, token *Token, secret string) *corev1.Secret {
+	t.Helper()
+	data, err := json.Marshal(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName(token.ID), Namespace: "default"},
+		Data: map[string][]byte{
+			tokenDataKey: data,
+			hashDataKey:  []byte(hashSecret(secret)),
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	active := &Token{ID: "abc123", Name: "ci", Principal: "apitoken-abc123", LastUsedAt: now.Format(time.RFC3339)}
+	expired := &Token{ID: "def456", Name: "old", Principal: "apitoken-def456", ExpiresAt: now.Add(-time.Hour).Format(time.RFC3339)}
+
+	tests := []struct {
+		name    string
+		bearer  string
+		wantID  string
+		wantErr string
+	}{
+		{name: "valid", bearer: "mlp_abc123_s3cret", wantID: "abc123"},
+		{name: "missing prefix", bearer: "abc123_s3cret", wantErr: "malformed API token"},
+		{name: "missing separator", bearer: "mlp_abc123s3cret", wantErr: "malformed API token"},
+		{name: "empty id", bearer: "mlp__s3cret", wantErr: "malformed API token"},
+		{name: "empty secret", bearer: "mlp_abc123_", wantErr: "malformed API token"},
+		{name: "wrong secret", bearer: "mlp_abc123_guess", wantErr: "invalid API token"},
+		{name: "unknown id", bearer: "mlp_ffffff_s3cret", wantErr: "invalid API token"},
+		{name: "expired", bearer: "mlp_def456_0ld", wantErr: "API token has expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := kubefake.NewSimpleClientset(storedToken(t, active, "s3cret"), storedToken(t, expired, "0ld"))
+			token, err := validate(context.TODO(), clientset.CoreV1().Secrets("default"), tt.bearer, now)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("validate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate() unexpected error: %v", err)
+			}
+			if token.ID != tt.wantID {
+				t.Errorf("validate() token = %s, want %s", token.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestValidateRecordsLastUse(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	token := &Token{ID: "abc123", Name: "ci", LastUsedAt: now.Add(-time.Hour).Format(time.RFC3339)}
+	clientset := kubefake.NewSimpleClientset(storedToken(t, token, "s3cret"))
+	secrets := clientset.CoreV1().Secrets("default")
+
+	if _, err := validate(context.TODO(), secrets, "mlp_abc123_s3cret", now); err != nil {
+		t.Fatalf("validate() unexpected error: %v", err)
+	}
+	stored, err := secrets.Get(context.TODO(), secretName("abc123"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := secretToToken(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastUsedAt != now.Format(time.RFC3339) {
+		t.Errorf("LastUsedAt = %s, want %s", got.LastUsedAt, now.Format(time.RFC3339))
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   Token
+		method  string
+		path    string
+		cluster string
+		wantErr bool
+	}{
+		{
+			name:   "unrestricted token",
+			token:  Token{Name: "all"},
+			method: http.MethodDelete,
+			path:   "/api/v1/backup/x",
+		},
+		{
+			name:   "read-only token reads",
+			token:  Token{Name: "ro", ReadOnly: true},
+			method: http.MethodGet,
+			path:   "/api/v1/backup",
+		},
+		{
+			name:   "read-only token heads",
+			token:  Token{Name: "ro", ReadOnly: true},
+			method: http.MethodHead,
+			path:   "/api/v1/backup",
+		},
+		{
+			name:    "read-only token writes",
+			token:   Token{Name: "ro", ReadOnly: true},
+			method:  http.MethodPost,
+			path:    "/api/v1/backup",
+			wantErr: true,
+		},
+		{
+			name:    "read-only token deletes",
+			token:   Token{Name: "ro", ReadOnly: true},
+			method:  http.MethodDelete,
+			path:    "/api/v1/backup/x",
+			wantErr: true,
+		},
+		{
+			name:   "path prefix matches",
+			token:  Token{Name: "backup", PathPrefixes: []string{"/api/v1/migration", "/api/v1/backup"}},
+			method: http.MethodPost,
+			path:   "/api/v1/backup/x/trigger",
+		},
+		{
+			name:    "path prefix mismatch",
+			token:   Token{Name: "backup", PathPrefixes: []string{"/api/v1/backup"}},
+			method:  http.MethodGet,
+			path:    "/api/v1/setting/apitokens",
+			wantErr: true,
+		},
+		{
+			name:    "scoped cluster",
+			token:   Token{Name: "scoped", Scopes: []Scope{{Cluster: "member1", Relation: "member"}}},
+			method:  http.MethodGet,
+			path:    "/api/v1/member/member1/pods",
+			cluster: "member1",
+		},
+		{
+			name:    "cluster out of scope",
+			token:   Token{Name: "scoped", Scopes: []Scope{{Cluster: "member1", Relation: "member"}}},
+			method:  http.MethodGet,
+			path:    "/api/v1/member/member2/pods",
+			cluster: "member2",
+			wantErr: true,
+		},
+		{
+			name:    "unscoped token on member cluster",
+			token:   Token{Name: "unscoped"},
+			method:  http.MethodGet,
+			path:    "/api/v1/member/member1/pods",
+			cluster: "member1",
+			wantErr: true,
+		},
+		{
+			name:    "management cluster needs no scope",
+			token:   Token{Name: "unscoped"},
+			method:  http.MethodGet,
+			path:    "/api/v1/overview",
+			cluster: "mgmt-cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.token.Authorize(tt.method, tt.path, tt.cluster)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Authorize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
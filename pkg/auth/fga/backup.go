@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StoreExport is the authorization model and tuples of the store, to restore it after losing OpenFGA
+type StoreExport struct {
+	ExportedAt string          `json:"exportedAt"`
+	Model      json.RawMessage `json:"model"`
+	Tuples     []Tuple         `json:"tuples"`
+}
+
+// ImportResult is the outcome of importing a store export
+type ImportResult struct {
+	DryRun       bool     `json:"dryRun"`
+	ModelWritten bool     `json:"modelWritten"`
+	Written      []Tuple  `json:"written"`
+	Existing     int      `json:"existing"`
+	Errors       []string `json:"errors"`
+}
+
+// Reasons a tuple is orphaned
+const (
+	OrphanUnknownUser    = "user does not exist"
+	OrphanUnknownCluster = "cluster does not exist"
+	OrphanUnknownProject = "project does not exist"
+)
+
+// OrphanedTuple is a tuple referencing a user, cluster or project that does not exist anymore
+type OrphanedTuple struct {
+	Tuple
+	Reason string `json:"reason"`
+}
+
+func (t Tuple) key() string {
+	return fmt.Sprintf("%s#%s@%s:%s", t.User, t.Relation, t.ObjectType, t.ObjectID)
+}
+
+// Export returns the latest authorization model and all the tuples of the store
+func Export(ctx context.Context, c Client) (*StoreExport, error) {
+	model, err := c.ReadAuthorizationModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tuples, err := c.ReadTuples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tuples == nil {
+		tuples = []Tuple{}
+	}
+	sort.Slice(tuples, func(i, j int) bool { return tuples[i].key() < tuples[j].key() })
+	return &StoreExport{ExportedAt: time.Now().Format(time.RFC3339), Model: model, Tuples: tuples}, nil
+}
+
+// Import writes the authorization model of an export, when it has one, and the tuples of the export the
+// store does not have yet. Tuples of the store missing from the export are kept, so importing is
+// idempotent and can complete an earlier import that failed half way.
+func Import(ctx context.Context, c Client, export StoreExport, dryRun bool) (*ImportResult, error) {
+	existing, err := c.ReadTuples(ctx)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(existing))
+	for _, tuple := range existing {
+		present[tuple.key()] = true
+	}
+
+	result := &ImportResult{DryRun: dryRun, Written: []Tuple{}, Errors: []string{}}
+	if len(export.Model) > 0 && string(export.Model) != "null" {
+		if !dryRun {
+			if err := c.WriteAuthorizationModel(ctx, export.Model); err != nil {
+				return nil, err
+			}
+		}
+		result.ModelWritten = true
+	}
+	for _, tuple := range export.Tuples {
+		if present[tuple.key()] {
+			result.Existing++
+			continue
+		}
+		present[tuple.key()] = true
+		if !dryRun {
+			if err := c.WriteTuple(ctx, tuple.User, tuple.Relation, tuple.ObjectType, tuple.ObjectID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", tuple.key(), err))
+				continue
+			}
+		}
+		result.Written = append(result.Written, tuple)
+	}
+	return result, nil
+}
+
+// FindOrphanedTuples returns the tuples referencing users, clusters or projects missing from the given
+// ones. A nil list is not checked, e.g. when the users could not be listed.
+func FindOrphanedTuples(tuples []Tuple, users, clusters, projects []string) []OrphanedTuple {
+	toSet := func(names []string) map[string]bool {
+		if names == nil {
+			return nil
+		}
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			set[name] = true
+		}
+		return set
+	}
+	knownUsers, knownClusters, knownProjects := toSet(users), toSet(clusters), toSet(projects)
+	missing := func(set map[string]bool, name string) bool {
+		return set != nil && !set[name]
+	}
+
+	orphans := []OrphanedTuple{}
+	for _, tuple := range tuples {
+		var reason string
+		switch {
+		case strings.HasPrefix(tuple.User, projectSubjectPrefix) &&
+			missing(knownProjects, strings.TrimPrefix(tuple.User, projectSubjectPrefix)):
+			reason = OrphanUnknownProject
+		case !strings.HasPrefix(tuple.User, projectSubjectPrefix) && missing(knownUsers, tuple.User):
+			reason = OrphanUnknownUser
+		case tuple.ObjectType == "cluster" && missing(knownClusters, tuple.ObjectID):
+			reason = OrphanUnknownCluster
+		case tuple.ObjectType == "project" && missing(knownProjects, tuple.ObjectID):
+			reason = OrphanUnknownProject
+		default:
+			continue
+		}
+		orphans = append(orphans, OrphanedTuple{Tuple: tuple, Reason: reason})
+	}
+	return orphans
+}
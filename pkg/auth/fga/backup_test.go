@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fga
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// fakeClient is an in-memory store
+type fakeClient struct {
+	tuples []Tuple
+	model  json.RawMessage
+}
+
+func (f *fakeClient) Check(_ context.Context, user, relation, objectType, objectID string) (bool, error) {
+	for _, t := range f.tuples {
+		if t == (Tuple{User: user, Relation: relation, ObjectType: objectType, ObjectID: objectID}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *fakeClient) GetStoreID() string     { return "store" }
+func (f *fakeClient) GetAuthModelID() string { return "" }
+func (f *fakeClient) WriteTuple(_ context.Context, user, relation, objectType, objectID string) error {
+	f.tuples = append(f.tuples, Tuple{User: user, Relation: relation, ObjectType: objectType, ObjectID: objectID})
+	return nil
+}
+func (f *fakeClient) DeleteTuple(context.Context, string, string, string, string) error { return nil }
+func (f *fakeClient) ReadTuples(context.Context) ([]Tuple, error)                       { return f.tuples, nil }
+func (f *fakeClient) ReadAuthorizationModel(context.Context) (json.RawMessage, error) {
+	return f.model, nil
+}
+func (f *fakeClient) WriteAuthorizationModel(_ context.Context, model json.RawMessage) error {
+	f.model = model
+	return nil
+}
+
+func TestExportImport(t *testing.T) {
+	source := &fakeClient{
+		model: json.RawMessage(`{"schema_version":"1.1"}`),
+		tuples: []Tuple{
+			{User: "bob", Relation: "member", ObjectType: "cluster", ObjectID: "member1"},
+			{User: "alice", Relation: "admin", ObjectType: "dashboard", ObjectID: "dashboard"},
+		},
+	}
+	export, err := Export(context.TODO(), source)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if export.Tuples[0].User != "alice" {
+		t.Errorf("Export() tuples = %v, want sorted", export.Tuples)
+	}
+
+	target := &fakeClient{tuples: []Tuple{{User: "alice", Relation: "admin", ObjectType: "dashboard", ObjectID: "dashboard"}}}
+	result, err := Import(context.TODO(), target, *export, true)
+	if err != nil {
+		t.Fatalf("Import() dry run error = %v", err)
+	}
+	if len(result.Written) != 1 || result.Existing != 1 || !result.ModelWritten || len(target.tuples) != 1 || target.model != nil {
+		t.Errorf("Import() dry run = %+v, store = %v, want one tuple to write and nothing written", result, target.tuples)
+	}
+
+	if _, err := Import(context.TODO(), target, *export, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if len(target.tuples) != 2 || string(target.model) != `{"schema_version":"1.1"}` {
+		t.Errorf("store after Import() = %v, %s", target.tuples, target.model)
+	}
+	if result, _ := Import(context.TODO(), target, *export, false); len(result.Written) != 0 || result.Existing != 2 {
+		t.Errorf("second Import() = %+v, want nothing written", result)
+	}
+}
+
+func TestFindOrphanedTuples(t *testing.T) {
+	tuples := []Tuple{
+		{User: "alice", Relation: "owner", ObjectType: "cluster", ObjectID: "member1"},
+		{User: "gone", Relation: "member", ObjectType: "cluster", ObjectID: "member1"},
+		{User: "alice", Relation: "member", ObjectType: "cluster", ObjectID: "deleted"},
+		{User: ProjectSubject("old"), Relation: "project", ObjectType: "cluster", ObjectID: "member1"},
+		{User: "alice", Relation: "owner", ObjectType: "project", ObjectID: "ml"},
+	}
+	got := FindOrphanedTuples(tuples, []string{"alice"}, []string{"member1"}, []string{"ml"})
+	want := []OrphanedTuple{
+		{Tuple: tuples[1], Reason: OrphanUnknownUser},
+		{Tuple: tuples[2], Reason: OrphanUnknownCluster},
+		{Tuple: tuples[3], Reason: OrphanUnknownProject},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindOrphanedTuples() = %+v, want %+v", got, want)
+	}
+	if got := FindOrphanedTuples(tuples, nil, []string{"member1"}, []string{"ml", "old"}); len(got) != 1 {
+		t.Errorf("FindOrphanedTuples() without users = %+v, want only the deleted cluster", got)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/openfga/go-sdk/client"
 	"k8s.io/klog/v2"
@@ -38,6 +39,20 @@ type Client interface {
 	WriteTuple(ctx context.Context, user, relation, objectType, objectID string) error
 	// DeleteTuple deletes a tuple from OpenFGA
 	DeleteTuple(ctx context.Context, user, relation, objectType, objectID string) error
+	// ReadTuples returns all the tuples of the store
+	ReadTuples(ctx context.Context) ([]Tuple, error)
+	// ReadAuthorizationModel returns the latest authorization model of the store as JSON
+	ReadAuthorizationModel(ctx context.Context) (json.RawMessage, error)
+	// WriteAuthorizationModel writes an authorization model, which becomes the latest one of the store
+	WriteAuthorizationModel(ctx context.Context, model json.RawMessage) error
+}
+
+// Tuple is a relation tuple of the store, with the user as passed to WriteTuple
+type Tuple struct {
+	User       string `json:"user"`
+	Relation   string `json:"relation"`
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
 }
 
 // OpenFGAClient implements the Client interface using OpenFGA
@@ -155,7 +170,8 @@ func (c *OpenFGAClient) initializeStore(ctx context.Context, apiURL string) erro
 	return nil
 }
 
-// createAuthorizationModel creates a minimal authorization model for Karmada Dashboard
+// createAuthorizationModel creates a minimal authorization model for Karmada Dashboard.
+// Clusters inherit the owners and members of the projects they belong to.
 func (c *OpenFGAClient) createAuthorizationModel(ctx context.Context) error {
 	minimalModel := `{
   "schema_version": "1.1",
@@ -193,17 +209,99 @@ func (c *OpenFGAClient) createAuthorizationModel(ctx context.Context) error {
       }
     },
     {
-      "type": "cluster",
+      "type": "project",
       "relations": {
         "owner": {
           "this": {}
         },
         "member": {
+          "union": {
+            "child": [
+              {
+                "this": {}
+              },
+              {
+                "computedUserset": {
+                  "relation": "owner"
+                }
+              }
+            ]
+          }
+        }
+      },
+      "metadata": {
+        "relations": {
+          "owner": {
+            "directly_related_user_types": [
+              {
+                "type": "user"
+              }
+            ]
+          },
+          "member": {
+            "directly_related_user_types": [
+              {
+                "type": "user"
+              }
+            ]
+          }
+        }
+      }
+    },
+    {
+      "type": "cluster",
+      "relations": {
+        "project": {
           "this": {}
+        },
+        "owner": {
+          "union": {
+            "child": [
+              {
+                "this": {}
+              },
+              {
+                "tupleToUserset": {
+                  "tupleset": {
+                    "relation": "project"
+                  },
+                  "computedUserset": {
+                    "relation": "owner"
+                  }
+                }
+              }
+            ]
+          }
+        },
+        "member": {
+          "union": {
+            "child": [
+              {
+                "this": {}
+              },
+              {
+                "tupleToUserset": {
+                  "tupleset": {
+                    "relation": "project"
+                  },
+                  "computedUserset": {
+                    "relation": "member"
+                  }
+                }
+              }
+            ]
+          }
         }
       },
 	  "metadata": {
         "relations": {
+          "project": {
+            "directly_related_user_types": [
+              {
+                "type": "project"
+              }
+            ]
+          },
           "owner": {
             "directly_related_user_types": [
               {
@@ -266,7 +364,7 @@ func (c *OpenFGAClient) WriteTuple(ctx context.Context, user, relation, objectTy
 	klog.V(4).InfoS("Writing tuple", "user", user, "relation", relation, "objectType", objectType, "objectID", objectID)
 
 	// Format user and object according to OpenFGA requirements
-	formattedUser := fmt.Sprintf("user:%s", user)
+	formattedUser := formatSubject(user)
 	formattedObject := fmt.Sprintf("%s:%s", objectType, objectID)
 
 	// Create the tuple to write
@@ -296,7 +394,7 @@ func (c *OpenFGAClient) DeleteTuple(ctx context.Context, user, relation, objectT
 	klog.V(4).InfoS("Deleting tuple", "user", user, "relation", relation, "objectType", objectType, "objectID", objectID)
 
 	// Format user and object according to OpenFGA requirements
-	formattedUser := fmt.Sprintf("user:%s", user)
+	formattedUser := formatSubject(user)
 	formattedObject := fmt.Sprintf("%s:%s", objectType, objectID)
 
 	// Create the tuple to delete (without condition since we're dealing with a simple tuple)
@@ -321,6 +419,78 @@ func (c *OpenFGAClient) DeleteTuple(ctx context.Context, user, relation, objectT
 	return nil
 }
 
+// ReadTuples returns all the tuples of the store, reading every page
+func (c *OpenFGAClient) ReadTuples(ctx context.Context) ([]Tuple, error) {
+	var tuples []Tuple
+	var continuationToken string
+	for {
+		options := client.ClientReadOptions{}
+		if continuationToken != "" {
+			options.ContinuationToken = &continuationToken
+		}
+		response, err := c.fgaClient.Read(ctx).Body(client.ClientReadRequest{}).Options(options).Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tuples: %w", err)
+		}
+		for _, tuple := range response.GetTuples() {
+			key := tuple.GetKey()
+			objectType, objectID, _ := strings.Cut(key.GetObject(), ":")
+			tuples = append(tuples, Tuple{
+				User:       parseSubject(key.GetUser()),
+				Relation:   key.GetRelation(),
+				ObjectType: objectType,
+				ObjectID:   objectID,
+			})
+		}
+		continuationToken = response.GetContinuationToken()
+		if continuationToken == "" {
+			return tuples, nil
+		}
+	}
+}
+
+// ReadAuthorizationModel returns the latest authorization model of the store as JSON
+func (c *OpenFGAClient) ReadAuthorizationModel(ctx context.Context) (json.RawMessage, error) {
+	response, err := c.fgaClient.ReadLatestAuthorizationModel(ctx).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization model: %w", err)
+	}
+	model, err := json.Marshal(response.GetAuthorizationModel())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode authorization model: %w", err)
+	}
+	return model, nil
+}
+
+// WriteAuthorizationModel writes an authorization model, which becomes the latest one of the store.
+// The id of an exported model is ignored, OpenFGA assigns a new one.
+func (c *OpenFGAClient) WriteAuthorizationModel(ctx context.Context, model json.RawMessage) error {
+	var body client.ClientWriteAuthorizationModelRequest
+	if err := json.Unmarshal(model, &body); err != nil {
+		return fmt.Errorf("invalid authorization model: %w", err)
+	}
+	response, err := c.fgaClient.WriteAuthorizationModel(ctx).Body(body).Execute()
+	if err != nil {
+		return fmt.Errorf("failed to write authorization model: %w", err)
+	}
+	klog.InfoS("Wrote OpenFGA authorization model", "authModelID", response.GetAuthorizationModelId())
+	return nil
+}
+
+// parseSubject returns the subject of a tuple as passed to WriteTuple, the reverse of formatSubject
+func parseSubject(subject string) string {
+	return strings.TrimPrefix(subject, "user:")
+}
+
+// formatSubject returns the OpenFGA subject of a tuple. Subjects are users unless they are
+// another object, such as a project created with ProjectSubject.
+func formatSubject(subject string) string {
+	if strings.HasPrefix(subject, projectSubjectPrefix) {
+		return subject
+	}
+	return fmt.Sprintf("user:%s", subject)
+}
+
 // GetStoreID returns the OpenFGA store ID
 func (c *OpenFGAClient) GetStoreID() string {
 	return c.storeID
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fga
+
+import (
+	"sort"
+	"strings"
+)
+
+// RelationNone is the effective relation of a user without access to a cluster
+const RelationNone = "none"
+
+// Ways an effective cluster relation is derived
+const (
+	// SourceDirect is a tuple relating the user to the cluster
+	SourceDirect = "direct"
+	// SourceProject is the membership of the user in a project the cluster belongs to
+	SourceProject = "project"
+	// SourceAdmin is the dashboard admin role, which owns every cluster
+	SourceAdmin = "admin"
+)
+
+// AccessSource tells how a user got its relation on a cluster
+type AccessSource struct {
+	Type    string `json:"type"`
+	Project string `json:"project,omitempty"`
+}
+
+// ClusterAccess is the effective relation of a user on a cluster, owner, member or none, and its sources
+type ClusterAccess struct {
+	Relation string         `json:"relation"`
+	Sources  []AccessSource `json:"sources"`
+}
+
+// AccessMatrixRow is the access of a user to each cluster of the matrix, by cluster name
+type AccessMatrixRow struct {
+	User     string                   `json:"user"`
+	Admin    bool                     `json:"admin"`
+	Clusters map[string]ClusterAccess `json:"clusters"`
+}
+
+// AccessMatrix is the effective relation of each user on each cluster
+type AccessMatrix struct {
+	Clusters []string          `json:"clusters"`
+	Rows     []AccessMatrixRow `json:"rows"`
+}
+
+// AccessMatrixFilter restricts an access matrix, empty fields match everything. Relation and Source drop
+// the cells that do not match, and the users left without cells.
+type AccessMatrixFilter struct {
+	User     string
+	Cluster  string
+	Relation string
+	Source   string
+}
+
+func (f AccessMatrixFilter) matches(access ClusterAccess) bool {
+	if f.Relation != "" && access.Relation != f.Relation {
+		return false
+	}
+	if f.Source == "" {
+		return true
+	}
+	for _, source := range access.Sources {
+		if source.Type == f.Source {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildAccessMatrix evaluates the authorization model over the tuples of the store for the users and
+// clusters, without a check per pair. Users related to anything in the tuples are added to the users.
+func BuildAccessMatrix(tuples []Tuple, users, clusters []string, filter AccessMatrixFilter) AccessMatrix {
+	admins := map[string]bool{}
+	direct := map[string]map[string]string{}       // user -> cluster -> strongest relation
+	projectRoles := map[string]map[string]string{} // user -> project -> strongest relation
+	clusterProjects := map[string][]string{}       // cluster -> projects
+	known := map[string]bool{}
+	for _, user := range users {
+		known[user] = true
+	}
+	strongest := func(relations map[string]map[string]string, user, object, relation string) {
+		if relations[user] == nil {
+			relations[user] = map[string]string{}
+		}
+		if relations[user][object] != "owner" {
+			relations[user][object] = relation
+		}
+	}
+	for _, tuple := range tuples {
+		if strings.HasPrefix(tuple.User, projectSubjectPrefix) {
+			if tuple.ObjectType == "cluster" && tuple.Relation == "project" {
+				project := strings.TrimPrefix(tuple.User, projectSubjectPrefix)
+				clusterProjects[tuple.ObjectID] = append(clusterProjects[tuple.ObjectID], project)
+			}
+			continue
+		}
+		known[tuple.User] = true
+		switch {
+		case tuple.ObjectType == "dashboard" && tuple.Relation == "admin":
+			admins[tuple.User] = true
+		case tuple.ObjectType == "cluster" && (tuple.Relation == "owner" || tuple.Relation == "member"):
+			strongest(direct, tuple.User, tuple.ObjectID, tuple.Relation)
+		case tuple.ObjectType == "project" && (tuple.Relation == "owner" || tuple.Relation == "member"):
+			strongest(projectRoles, tuple.User, tuple.ObjectID, tuple.Relation)
+		}
+	}
+
+	matrix := AccessMatrix{Clusters: []string{}, Rows: []AccessMatrixRow{}}
+	for _, cluster := range clusters {
+		if filter.Cluster == "" || cluster == filter.Cluster {
+			matrix.Clusters = append(matrix.Clusters, cluster)
+		}
+	}
+	sort.Strings(matrix.Clusters)
+	names := make([]string, 0, len(known))
+	for user := range known {
+		if user != "" && (filter.User == "" || user == filter.User) {
+			names = append(names, user)
+		}
+	}
+	sort.Strings(names)
+
+	for _, user := range names {
+		row := AccessMatrixRow{User: user, Admin: admins[user], Clusters: map[string]ClusterAccess{}}
+		for _, cluster := range matrix.Clusters {
+			sources := map[string][]AccessSource{}
+			if admins[user] {
+				sources["owner"] = append(sources["owner"], AccessSource{Type: SourceAdmin})
+			}
+			if relation := direct[user][cluster]; relation != "" {
+				sources[relation] = append(sources[relation], AccessSource{Type: SourceDirect})
+			}
+			for _, project := range clusterProjects[cluster] {
+				if relation := projectRoles[user][project]; relation != "" {
+					sources[relation] = append(sources[relation], AccessSource{Type: SourceProject, Project: project})
+				}
+			}
+
+			access := ClusterAccess{Relation: RelationNone, Sources: []AccessSource{}}
+			for _, relation := range []string{"owner", "member"} {
+				if len(sources[relation]) > 0 {
+					access = ClusterAccess{Relation: relation, Sources: sources[relation]}
+					break
+				}
+			}
+			if filter.matches(access) {
+				row.Clusters[cluster] = access
+			}
+		}
+		if len(row.Clusters) > 0 || (filter.Relation == "" && filter.Source == "") {
+			matrix.Rows = append(matrix.Rows, row)
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fga
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildAccessMatrix(t *testing.T) {
+	tuples := []Tuple{
+		{User: "root", Relation: "admin", ObjectType: "dashboard", ObjectID: "dashboard"},
+		{User: "alice", Relation: "owner", ObjectType: "cluster", ObjectID: "member1"},
+		{User: "alice", Relation: "member", ObjectType: "cluster", ObjectID: "member1"},
+		{User: "bob", Relation: "member", ObjectType: "project", ObjectID: "ml"},
+		{User: ProjectSubject("ml"), Relation: "project", ObjectType: "cluster", ObjectID: "member2"},
+	}
+	matrix := BuildAccessMatrix(tuples, []string{"carol"}, []string{"member2", "member1"}, AccessMatrixFilter{})
+
+	if want := []string{"member1", "member2"}; !reflect.DeepEqual(matrix.Clusters, want) {
+		t.Errorf("Clusters = %v, want %v", matrix.Clusters, want)
+	}
+	var users []string
+	for _, row := range matrix.Rows {
+		users = append(users, row.User)
+	}
+	if want := []string{"alice", "bob", "carol", "root"}; !reflect.DeepEqual(users, want) {
+		t.Fatalf("users = %v, want %v", users, want)
+	}
+
+	tests := []struct {
+		row, cluster string
+		want         ClusterAccess
+	}{
+		{"alice", "member1", ClusterAccess{Relation: "owner", Sources: []AccessSource{{Type: SourceDirect}}}},
+		{"alice", "member2", ClusterAccess{Relation: RelationNone, Sources: []AccessSource{}}},
+		{"bob", "member2", ClusterAccess{Relation: "member", Sources: []AccessSource{{Type: SourceProject, Project: "ml"}}}},
+		{"carol", "member1", ClusterAccess{Relation: RelationNone, Sources: []AccessSource{}}},
+		{"root", "member2", ClusterAccess{Relation: "owner", Sources: []AccessSource{{Type: SourceAdmin}}}},
+	}
+	rows := map[string]AccessMatrixRow{}
+	for _, row := range matrix.Rows {
+		rows[row.User] = row
+	}
+	for _, tt := range tests {
+		if got := rows[tt.row].Clusters[tt.cluster]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("access of %s to %s = %+v, want %+v", tt.row, tt.cluster, got, tt.want)
+		}
+	}
+	if !rows["root"].Admin || rows["alice"].Admin {
+		t.Error("only root should be an admin")
+	}
+
+	filtered := BuildAccessMatrix(tuples, nil, []string{"member1", "member2"}, AccessMatrixFilter{Cluster: "member2", Source: SourceProject})
+	if len(filtered.Rows) != 1 || filtered.Rows[0].User != "bob" || len(filtered.Rows[0].Clusters) != 1 {
+		t.Errorf("filtered rows = %+v, want bob on member2 only", filtered.Rows)
+	}
+}
@@ -39,3 +39,60 @@ func HasClusterAccess(ctx context.Context, fgaClient Client, username, clusterNa
 
 	return false, nil
 }
+
+// HasClusterOwnerAccess checks if the user is an admin or an owner of the given cluster.
+// Members of the cluster are not enough for operations that change how the cluster is managed.
+func HasClusterOwnerAccess(ctx context.Context, fgaClient Client, username, clusterName string) (bool, error) {
+	isAdmin, err := fgaClient.Check(ctx, username, "admin", "dashboard", "dashboard")
+	if err != nil {
+		klog.ErrorS(err, "Failed to check admin role in OpenFGA", "user", username)
+		return false, err
+	}
+	if isAdmin {
+		return true, nil
+	}
+
+	isOwner, err := fgaClient.Check(ctx, username, "owner", "cluster", clusterName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to check owner role in OpenFGA", "user", username, "cluster", clusterName)
+		return false, err
+	}
+	return isOwner, nil
+}
+
+// ObjectRelations are the relations of each object type in the authorization model
+var ObjectRelations = map[string][]string{
+	"dashboard": {"admin", "basic_user"},
+	"cluster":   {"owner", "member"},
+	"project":   {"owner", "member"},
+}
+
+const projectSubjectPrefix = "project:"
+
+// ProjectSubject returns the subject that relates a project to a cluster in a tuple,
+// which lets the owners and members of the project inherit the same roles on the cluster.
+func ProjectSubject(project string) string {
+	return projectSubjectPrefix + project
+}
+
+// IsValidRelation reports whether relation is defined on objectType in the authorization model
+func IsValidRelation(objectType, relation string) bool {
+	for _, r := range ObjectRelations[objectType] {
+		if r == relation {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterRelationForRole maps a cluster role name used by the UI to the cluster relation it grants.
+// Unknown roles are returned unchanged.
+func ClusterRelationForRole(role string) string {
+	switch role {
+	case "owner", "admin":
+		return "owner"
+	case "member", "read", "write":
+		return "member"
+	}
+	return role
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fga
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHasClusterOwnerAccess(t *testing.T) {
+	client := &fakeClient{tuples: []Tuple{
+		{User: "root", Relation: "admin", ObjectType: "dashboard", ObjectID: "dashboard"},
+		{User: "alice", Relation: "owner", ObjectType: "cluster", ObjectID: "member1"},
+		{User: "bob", Relation: "member", ObjectType: "cluster", ObjectID: "member1"},
+	}}
+
+	tests := []struct {
+		user, cluster string
+		want          bool
+	}{
+		{"root", "member1", true},
+		{"alice", "member1", true},
+		{"alice", "member2", false},
+		{"bob", "member1", false},
+	}
+	for _, tt := range tests {
+		got, err := HasClusterOwnerAccess(context.Background(), client, tt.user, tt.cluster)
+		if err != nil {
+			t.Fatalf("HasClusterOwnerAccess(%s, %s) failed: %v", tt.user, tt.cluster, err)
+		}
+		if got != tt.want {
+			t.Errorf("HasClusterOwnerAccess(%s, %s) = %v, want %v", tt.user, tt.cluster, got, tt.want)
+		}
+	}
+}
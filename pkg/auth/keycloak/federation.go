@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+// Identity provider types the dashboard manages
+const (
+	ProviderOIDC         = "oidc"
+	ProviderKeycloakOIDC = "keycloak-oidc"
+	ProviderSAML         = "saml"
+)
+
+// Identity provider mappers that grant a realm role for a group claim or attribute
+const (
+	oidcRoleMapper = "oidc-role-idp-mapper"
+	samlRoleMapper = "saml-role-idp-mapper"
+)
+
+// Default claim and attribute carrying the groups of a federated user
+const (
+	DefaultOIDCGroupClaim     = "groups"
+	DefaultSAMLGroupAttribute = "memberOf"
+)
+
+// MaskedSecret replaces secrets in identity provider configs returned to clients. Keycloak keeps the stored
+// secret when it is sent back unchanged.
+const MaskedSecret = "**********"
+
+// secretConfigKeys are the config keys of identity providers holding secrets
+var secretConfigKeys = []string{"clientSecret"}
+
+// requiredConfigKeys are the config keys each provider type needs to log users in
+var requiredConfigKeys = map[string][]string{
+	ProviderOIDC:         {"authorizationUrl", "tokenUrl", "clientId"},
+	ProviderKeycloakOIDC: {"authorizationUrl", "tokenUrl", "clientId"},
+	ProviderSAML:         {"singleSignOnServiceUrl"},
+}
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,63}$`)
+
+// IdentityProvider is a SAML or OIDC identity provider federated into the realm
+type IdentityProvider struct {
+	Alias       string `json:"alias"`
+	DisplayName string `json:"displayName,omitempty"`
+	// ProviderID is the type of the provider: oidc, keycloak-oidc or saml
+	ProviderID string `json:"providerId"`
+	Enabled    bool   `json:"enabled"`
+	TrustEmail bool   `json:"trustEmail"`
+	// LinkOnly providers can only be linked to existing accounts, not used to log in
+	LinkOnly                  bool              `json:"linkOnly"`
+	FirstBrokerLoginFlowAlias string            `json:"firstBrokerLoginFlowAlias,omitempty"`
+	Config                    map[string]string `json:"config"`
+}
+
+// Validate checks the alias, type and required config of an identity provider
+func (p IdentityProvider) Validate() error {
+	if !aliasPattern.MatchString(p.Alias) {
+		return fmt.Errorf("invalid alias %q: use letters, digits, '.', '_' or '-'", p.Alias)
+	}
+	required, ok := requiredConfigKeys[p.ProviderID]
+	if !ok {
+		return fmt.Errorf("unsupported identity provider type %q, supported types are %s, %s and %s",
+			p.ProviderID, ProviderOIDC, ProviderKeycloakOIDC, ProviderSAML)
+	}
+	for _, key := range required {
+		if p.Config[key] == "" {
+			return fmt.Errorf("config %q is required for %s identity providers", key, p.ProviderID)
+		}
+	}
+	return nil
+}
+
+// Representation returns the Keycloak representation of the identity provider
+func (p IdentityProvider) Representation() gocloak.IdentityProviderRepresentation {
+	config := make(map[string]string, len(p.Config))
+	for key, value := range p.Config {
+		config[key] = value
+	}
+	rep := gocloak.IdentityProviderRepresentation{
+		Alias:      gocloak.StringP(p.Alias),
+		ProviderID: gocloak.StringP(p.ProviderID),
+		Enabled:    gocloak.BoolP(p.Enabled),
+		TrustEmail: gocloak.BoolP(p.TrustEmail),
+		LinkOnly:   gocloak.BoolP(p.LinkOnly),
+		Config:     &config,
+	}
+	if p.DisplayName != "" {
+		rep.DisplayName = gocloak.StringP(p.DisplayName)
+	}
+	if p.FirstBrokerLoginFlowAlias != "" {
+		rep.FirstBrokerLoginFlowAlias = gocloak.StringP(p.FirstBrokerLoginFlowAlias)
+	}
+	return rep
+}
+
+// IdentityProviderFromRepresentation converts a Keycloak identity provider, masking its secrets
+func IdentityProviderFromRepresentation(rep *gocloak.IdentityProviderRepresentation) IdentityProvider {
+	p := IdentityProvider{
+		Alias:                     gocloak.PString(rep.Alias),
+		DisplayName:               gocloak.PString(rep.DisplayName),
+		ProviderID:                gocloak.PString(rep.ProviderID),
+		Enabled:                   gocloak.PBool(rep.Enabled),
+		TrustEmail:                gocloak.PBool(rep.TrustEmail),
+		LinkOnly:                  gocloak.PBool(rep.LinkOnly),
+		FirstBrokerLoginFlowAlias: gocloak.PString(rep.FirstBrokerLoginFlowAlias),
+		Config:                    map[string]string{},
+	}
+	if rep.Config != nil {
+		for key, value := range *rep.Config {
+			p.Config[key] = value
+		}
+	}
+	for _, key := range secretConfigKeys {
+		if p.Config[key] != "" {
+			p.Config[key] = MaskedSecret
+		}
+	}
+	return p
+}
+
+// GroupMapping grants a realm role to the users of an identity provider that are in a group. The realm role is
+// mapped to dashboard relations by the role mappings.
+type GroupMapping struct {
+	ID    string `json:"id"`
+	Group string `json:"group"`
+	Role  string `json:"role"`
+	// Claim is the OIDC claim or SAML attribute listing the groups of a user
+	Claim string `json:"claim"`
+}
+
+// GroupMapper returns the identity provider mapper of a group mapping for a provider type
+func GroupMapper(alias, providerID string, mapping GroupMapping) (gocloak.IdentityProviderMapper, error) {
+	mapper := gocloak.IdentityProviderMapper{
+		Name:                  gocloak.StringP(fmt.Sprintf("group %s to %s", mapping.Group, mapping.Role)),
+		IdentityProviderAlias: gocloak.StringP(alias),
+	}
+	if mapping.ID != "" {
+		mapper.ID = gocloak.StringP(mapping.ID)
+	}
+	switch providerID {
+	case ProviderOIDC, ProviderKeycloakOIDC:
+		claim := mapping.Claim
+		if claim == "" {
+			claim = DefaultOIDCGroupClaim
+		}
+		mapper.IdentityProviderMapper = gocloak.StringP(oidcRoleMapper)
+		mapper.Config = &map[string]string{
+			"syncMode":    "FORCE",
+			"claim":       claim,
+			"claim.value": mapping.Group,
+			"role":        mapping.Role,
+		}
+	case ProviderSAML:
+		attribute := mapping.Claim
+		if attribute == "" {
+			attribute = DefaultSAMLGroupAttribute
+		}
+		mapper.IdentityProviderMapper = gocloak.StringP(samlRoleMapper)
+		mapper.Config = &map[string]string{
+			"syncMode":        "FORCE",
+			"attribute.name":  attribute,
+			"attribute.value": mapping.Group,
+			"role":            mapping.Role,
+		}
+	default:
+		return mapper, fmt.Errorf("group mappings are not supported for %s identity providers", providerID)
+	}
+	return mapper, nil
+}
+
+// GroupMappingsFromMappers returns the group mappings among the mappers of an identity provider, sorted by group
+func GroupMappingsFromMappers(mappers []*gocloak.IdentityProviderMapper) []GroupMapping {
+	mappings := []GroupMapping{}
+	for _, mapper := range mappers {
+		if mapper == nil || mapper.Config == nil {
+			continue
+		}
+		config := *mapper.Config
+		mapping := GroupMapping{ID: gocloak.PString(mapper.ID), Role: config["role"]}
+		switch gocloak.PString(mapper.IdentityProviderMapper) {
+		case oidcRoleMapper:
+			mapping.Group, mapping.Claim = config["claim.value"], config["claim"]
+		case samlRoleMapper:
+			mapping.Group, mapping.Claim = config["attribute.value"], config["attribute.name"]
+		default:
+			continue
+		}
+		mappings = append(mappings, mapping)
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		if mappings[i].Group != mappings[j].Group {
+			return mappings[i].Group < mappings[j].Group
+		}
+		return mappings[i].Role < mappings[j].Role
+	})
+	return mappings
+}
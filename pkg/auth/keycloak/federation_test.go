@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"testing"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+func TestIdentityProviderValidate(t *testing.T) {
+	oidc := map[string]string{"authorizationUrl": "https://sso.example.com/auth", "tokenUrl": "https://sso.example.com/token", "clientId": "dashboard"}
+	cases := []struct {
+		provider IdentityProvider
+		wantErr  bool
+	}{
+		{IdentityProvider{Alias: "corp-oidc", ProviderID: ProviderOIDC, Config: oidc}, false},
+		{IdentityProvider{Alias: "corp-saml", ProviderID: ProviderSAML, Config: map[string]string{"singleSignOnServiceUrl": "https://idp.example.com/sso"}}, false},
+		{IdentityProvider{Alias: "corp-saml", ProviderID: ProviderSAML, Config: map[string]string{}}, true},
+		{IdentityProvider{Alias: "bad alias", ProviderID: ProviderOIDC, Config: oidc}, true},
+		{IdentityProvider{Alias: "github", ProviderID: "github", Config: oidc}, true},
+	}
+	for _, c := range cases {
+		if err := c.provider.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("Validate(%s/%s) error = %v, wantErr %v", c.provider.Alias, c.provider.ProviderID, err, c.wantErr)
+		}
+	}
+}
+
+func TestIdentityProviderFromRepresentationMasksSecrets(t *testing.T) {
+	config := map[string]string{"clientId": "dashboard", "clientSecret": "s3cret"}
+	rep := &gocloak.IdentityProviderRepresentation{
+		Alias:      gocloak.StringP("corp-oidc"),
+		ProviderID: gocloak.StringP(ProviderOIDC),
+		Enabled:    gocloak.BoolP(true),
+		Config:     &config,
+	}
+	provider := IdentityProviderFromRepresentation(rep)
+	if provider.Config["clientSecret"] != MaskedSecret || provider.Config["clientId"] != "dashboard" || !provider.Enabled {
+		t.Errorf("IdentityProviderFromRepresentation() = %+v", provider)
+	}
+	if config["clientSecret"] != "s3cret" {
+		t.Error("IdentityProviderFromRepresentation() changed the representation")
+	}
+}
+
+func TestGroupMappers(t *testing.T) {
+	oidcMapper, err := GroupMapper("corp-oidc", ProviderOIDC, GroupMapping{Group: "ml-admins", Role: "dashboard-admin"})
+	if err != nil {
+		t.Fatalf("GroupMapper(oidc) error = %v", err)
+	}
+	if (*oidcMapper.Config)["claim"] != DefaultOIDCGroupClaim || (*oidcMapper.Config)["claim.value"] != "ml-admins" {
+		t.Errorf("GroupMapper(oidc) config = %v", *oidcMapper.Config)
+	}
+	samlMapper, err := GroupMapper("corp-saml", ProviderSAML, GroupMapping{Group: "engineers", Role: "developer", Claim: "groups"})
+	if err != nil {
+		t.Fatalf("GroupMapper(saml) error = %v", err)
+	}
+	if _, err := GroupMapper("github", "github", GroupMapping{Group: "x", Role: "y"}); err == nil {
+		t.Error("GroupMapper() accepted an unsupported provider type")
+	}
+
+	other := gocloak.IdentityProviderMapper{
+		IdentityProviderMapper: gocloak.StringP("hardcoded-attribute-idp-mapper"),
+		Config:                 &map[string]string{"attribute": "team"},
+	}
+	oidcMapper.ID, samlMapper.ID = gocloak.StringP("1"), gocloak.StringP("2")
+	mappings := GroupMappingsFromMappers([]*gocloak.IdentityProviderMapper{&oidcMapper, &other, &samlMapper})
+	if len(mappings) != 2 {
+		t.Fatalf("GroupMappingsFromMappers() = %+v, want 2 mappings", mappings)
+	}
+	if mappings[0] != (GroupMapping{ID: "2", Group: "engineers", Role: "developer", Claim: "groups"}) {
+		t.Errorf("mappings[0] = %+v", mappings[0])
+	}
+	if mappings[1] != (GroupMapping{ID: "1", Group: "ml-admins", Role: "dashboard-admin", Claim: DefaultOIDCGroupClaim}) {
+		t.Errorf("mappings[1] = %+v", mappings[1])
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+// Credential types of the second factors of a user
+const (
+	CredentialOTP                  = "otp"
+	CredentialWebAuthn             = "webauthn"
+	CredentialWebAuthnPasswordless = "webauthn-passwordless"
+)
+
+// Required actions enrolling a second factor at the next login
+const (
+	ConfigureOTPAction     = "CONFIGURE_TOTP"
+	RegisterWebAuthnAction = "webauthn-register"
+)
+
+// mfaCredentialTypes are the credential types that are second factors
+var mfaCredentialTypes = map[string]bool{
+	CredentialOTP:                  true,
+	CredentialWebAuthn:             true,
+	CredentialWebAuthnPasswordless: true,
+}
+
+// IsMFACredential reports whether a credential type is a second factor, unlike passwords
+func IsMFACredential(credentialType string) bool {
+	return mfaCredentialTypes[credentialType]
+}
+
+// MFAFactor is a second factor configured by a user
+type MFAFactor struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Label     string `json:"label,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// MFAFactors returns the second factors among the credentials of a user, oldest first
+func MFAFactors(credentials []*gocloak.CredentialRepresentation) []MFAFactor {
+	factors := []MFAFactor{}
+	for _, credential := range credentials {
+		if credential == nil || !IsMFACredential(gocloak.PString(credential.Type)) {
+			continue
+		}
+		factor := MFAFactor{
+			ID:    gocloak.PString(credential.ID),
+			Type:  gocloak.PString(credential.Type),
+			Label: gocloak.PString(credential.UserLabel),
+		}
+		if credential.CreatedDate != nil {
+			factor.CreatedAt = time.UnixMilli(*credential.CreatedDate).UTC().Format(time.RFC3339)
+		}
+		factors = append(factors, factor)
+	}
+	// Timestamps in the same format and zone sort chronologically
+	sort.SliceStable(factors, func(i, j int) bool { return factors[i].CreatedAt < factors[j].CreatedAt })
+	return factors
+}
+
+// MFAEnrollmentAction returns the required action enrolling a second factor of a method, "otp" or "webauthn"
+func MFAEnrollmentAction(method string) (string, error) {
+	switch method {
+	case "", CredentialOTP:
+		return ConfigureOTPAction, nil
+	case CredentialWebAuthn:
+		return RegisterWebAuthnAction, nil
+	default:
+		return "", fmt.Errorf("unsupported MFA method %q, use %s or %s", method, CredentialOTP, CredentialWebAuthn)
+	}
+}
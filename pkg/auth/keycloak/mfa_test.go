@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"testing"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+func credential(id, credentialType, label string, createdDate int64) *gocloak.CredentialRepresentation {
+	return &gocloak.CredentialRepresentation{
+		ID:          gocloak.StringP(id),
+		Type:        gocloak.StringP(credentialType),
+		UserLabel:   gocloak.StringP(label),
+		CreatedDate: gocloak.Int64P(createdDate),
+	}
+}
+
+func TestMFAFactors(t *testing.T) {
+	factors := MFAFactors([]*gocloak.CredentialRepresentation{
+		credential("1", "password", "", 1700000000000),
+		credential("2", CredentialWebAuthn, "YubiKey", 1710000000000),
+		credential("3", CredentialOTP, "Phone", 1705000000000),
+		nil,
+	})
+	if len(factors) != 2 {
+		t.Fatalf("MFAFactors() = %+v, want the OTP and WebAuthn credentials", factors)
+	}
+	if factors[0].ID != "3" || factors[1].ID != "2" {
+		t.Errorf("MFAFactors() = %+v, want oldest first", factors)
+	}
+	if factors[0].CreatedAt != "2024-01-11T19:06:40Z" || factors[1].Label != "YubiKey" {
+		t.Errorf("MFAFactors() = %+v", factors)
+	}
+}
+
+func TestMFAEnrollmentAction(t *testing.T) {
+	cases := map[string]string{"": ConfigureOTPAction, CredentialOTP: ConfigureOTPAction, CredentialWebAuthn: RegisterWebAuthnAction}
+	for method, expected := range cases {
+		if action, err := MFAEnrollmentAction(method); err != nil || action != expected {
+			t.Errorf("MFAEnrollmentAction(%q) = %q, %v; expected %q", method, action, err, expected)
+		}
+	}
+	if _, err := MFAEnrollmentAction("sms"); err == nil {
+		t.Error("MFAEnrollmentAction() accepted an unsupported method")
+	}
+}
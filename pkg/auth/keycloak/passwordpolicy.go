@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UpdatePasswordAction is the required action making a user choose a new password at their next login
+const UpdatePasswordAction = "UPDATE_PASSWORD"
+
+// Keycloak password policy types managed through PasswordPolicy
+const (
+	policyLength          = "length"
+	policyMaxLength       = "maxLength"
+	policyUpperCase       = "upperCase"
+	policyLowerCase       = "lowerCase"
+	policyDigits          = "digits"
+	policySpecialChars    = "specialChars"
+	policyNotUsername     = "notUsername"
+	policyNotEmail        = "notEmail"
+	policyPasswordHistory = "passwordHistory"
+	policyExpiry          = "forceExpiredPasswordChange"
+)
+
+var policyTermPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?$`)
+
+// PasswordPolicy is the password policy of the realm. Counts of zero leave a rule out.
+type PasswordPolicy struct {
+	MinLength    int `json:"minLength"`
+	MaxLength    int `json:"maxLength"`
+	UpperCase    int `json:"upperCase"`
+	LowerCase    int `json:"lowerCase"`
+	Digits       int `json:"digits"`
+	SpecialChars int `json:"specialChars"`
+	// NotUsername and NotEmail reject passwords equal to the username or email of the user
+	NotUsername bool `json:"notUsername"`
+	NotEmail    bool `json:"notEmail"`
+	// History is how many previous passwords cannot be reused
+	History int `json:"history"`
+	// ExpiryDays is after how many days users have to change their password
+	ExpiryDays int `json:"expiryDays"`
+	// Other are the policies of the realm the dashboard does not manage, like hashAlgorithm(pbkdf2-sha256),
+	// kept unchanged on updates
+	Other []string `json:"other,omitempty"`
+}
+
+// Validate checks the counts of the policy
+func (p PasswordPolicy) Validate() error {
+	for name, value := range map[string]int{
+		"minLength": p.MinLength, "maxLength": p.MaxLength, "upperCase": p.UpperCase, "lowerCase": p.LowerCase,
+		"digits": p.Digits, "specialChars": p.SpecialChars, "history": p.History, "expiryDays": p.ExpiryDays,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+	if p.MaxLength > 0 && p.MaxLength < p.MinLength {
+		return fmt.Errorf("maxLength %d is less than minLength %d", p.MaxLength, p.MinLength)
+	}
+	if required := p.UpperCase + p.LowerCase + p.Digits + p.SpecialChars; p.MaxLength > 0 && required > p.MaxLength {
+		return fmt.Errorf("the policy requires %d characters but maxLength is %d", required, p.MaxLength)
+	}
+	for _, term := range p.Other {
+		match := policyTermPattern.FindStringSubmatch(strings.TrimSpace(term))
+		if match == nil {
+			return fmt.Errorf("invalid password policy %q", term)
+		}
+		if _, managed := managedPolicyTypes[match[1]]; managed {
+			return fmt.Errorf("password policy %s is set through its own field", match[1])
+		}
+	}
+	return nil
+}
+
+// managedPolicyTypes are the policy types set through the fields of PasswordPolicy
+var managedPolicyTypes = map[string]struct{}{
+	policyLength: {}, policyMaxLength: {}, policyUpperCase: {}, policyLowerCase: {}, policyDigits: {},
+	policySpecialChars: {}, policyNotUsername: {}, policyNotEmail: {}, policyPasswordHistory: {}, policyExpiry: {},
+}
+
+// String returns the policy in the format of the passwordPolicy of a Keycloak realm
+func (p PasswordPolicy) String() string {
+	var terms []string
+	add := func(policyType string, value int) {
+		if value > 0 {
+			terms = append(terms, fmt.Sprintf("%s(%d)", policyType, value))
+		}
+	}
+	add(policyLength, p.MinLength)
+	add(policyMaxLength, p.MaxLength)
+	add(policyUpperCase, p.UpperCase)
+	add(policyLowerCase, p.LowerCase)
+	add(policyDigits, p.Digits)
+	add(policySpecialChars, p.SpecialChars)
+	if p.NotUsername {
+		terms = append(terms, policyNotUsername+"(undefined)")
+	}
+	if p.NotEmail {
+		terms = append(terms, policyNotEmail+"(undefined)")
+	}
+	add(policyPasswordHistory, p.History)
+	add(policyExpiry, p.ExpiryDays)
+	for _, term := range p.Other {
+		terms = append(terms, strings.TrimSpace(term))
+	}
+	return strings.Join(terms, " and ")
+}
+
+// ParsePasswordPolicy reads the passwordPolicy of a Keycloak realm
+func ParsePasswordPolicy(value string) (PasswordPolicy, error) {
+	var p PasswordPolicy
+	if strings.TrimSpace(value) == "" {
+		return p, nil
+	}
+	for _, term := range strings.Split(value, " and ") {
+		term = strings.TrimSpace(term)
+		match := policyTermPattern.FindStringSubmatch(term)
+		if match == nil {
+			return p, fmt.Errorf("invalid password policy %q", term)
+		}
+		policyType, arg := match[1], match[2]
+		count := func() (int, error) {
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value of password policy %q", term)
+			}
+			return n, nil
+		}
+		var err error
+		switch policyType {
+		case policyLength:
+			p.MinLength, err = count()
+		case policyMaxLength:
+			p.MaxLength, err = count()
+		case policyUpperCase:
+			p.UpperCase, err = count()
+		case policyLowerCase:
+			p.LowerCase, err = count()
+		case policyDigits:
+			p.Digits, err = count()
+		case policySpecialChars:
+			p.SpecialChars, err = count()
+		case policyNotUsername:
+			p.NotUsername = true
+		case policyNotEmail:
+			p.NotEmail = true
+		case policyPasswordHistory:
+			p.History, err = count()
+		case policyExpiry:
+			p.ExpiryDays, err = count()
+		default:
+			p.Other = append(p.Other, term)
+		}
+		if err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
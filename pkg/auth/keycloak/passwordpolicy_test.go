@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePasswordPolicy(t *testing.T) {
+	policy, err := ParsePasswordPolicy("length(12) and upperCase(1) and digits(2) and notUsername(undefined) and hashIterations(27500) and passwordHistory(3) and forceExpiredPasswordChange(90)")
+	if err != nil {
+		t.Fatalf("ParsePasswordPolicy() error = %v", err)
+	}
+	expected := PasswordPolicy{
+		MinLength:   12,
+		UpperCase:   1,
+		Digits:      2,
+		NotUsername: true,
+		History:     3,
+		ExpiryDays:  90,
+		Other:       []string{"hashIterations(27500)"},
+	}
+	if !reflect.DeepEqual(policy, expected) {
+		t.Errorf("ParsePasswordPolicy() = %+v, expected %+v", policy, expected)
+	}
+
+	if _, err := ParsePasswordPolicy("length(twelve)"); err == nil {
+		t.Error("ParsePasswordPolicy() accepted a policy with an invalid count")
+	}
+	if policy, err := ParsePasswordPolicy(""); err != nil || !reflect.DeepEqual(policy, PasswordPolicy{}) {
+		t.Errorf("ParsePasswordPolicy(\"\") = %+v, %v", policy, err)
+	}
+}
+
+func TestPasswordPolicyString(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 10, SpecialChars: 1, NotEmail: true, ExpiryDays: 30, Other: []string{"hashAlgorithm(pbkdf2-sha256)"}}
+	expected := "length(10) and specialChars(1) and notEmail(undefined) and forceExpiredPasswordChange(30) and hashAlgorithm(pbkdf2-sha256)"
+	if actual := policy.String(); actual != expected {
+		t.Errorf("String() = %q, expected %q", actual, expected)
+	}
+	parsed, err := ParsePasswordPolicy(policy.String())
+	if err != nil || !reflect.DeepEqual(parsed, policy) {
+		t.Errorf("ParsePasswordPolicy(String()) = %+v, %v; expected %+v", parsed, err, policy)
+	}
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	cases := []struct {
+		policy  PasswordPolicy
+		wantErr bool
+	}{
+		{PasswordPolicy{MinLength: 12, UpperCase: 1, ExpiryDays: 90}, false},
+		{PasswordPolicy{MinLength: -1}, true},
+		{PasswordPolicy{MinLength: 20, MaxLength: 10}, true},
+		{PasswordPolicy{MaxLength: 3, UpperCase: 2, Digits: 2}, true},
+		{PasswordPolicy{Other: []string{"length(8)"}}, true},
+		{PasswordPolicy{Other: []string{"not a policy"}}, true},
+	}
+	for _, c := range cases {
+		if err := c.policy.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", c.policy, err, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keycloak
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+// Session is an active SSO session of a user
+type Session struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"userId"`
+	Username   string   `json:"username"`
+	IPAddress  string   `json:"ipAddress"`
+	Start      string   `json:"start"`
+	LastAccess string   `json:"lastAccess"`
+	Clients    []string `json:"clients"`
+}
+
+// Introspection is the state of a token as reported by Keycloak
+type Introspection struct {
+	Active    bool         `json:"active"`
+	ExpiresAt string       `json:"expiresAt,omitempty"`
+	Claims    *TokenClaims `json:"claims,omitempty"`
+	// Source is "introspection" when Keycloak's introspection endpoint was used, which requires a client
+	// secret, and "userinfo" when the token was checked against the userinfo endpoint instead
+	Source string `json:"source"`
+}
+
+func toSession(s *gocloak.UserSessionRepresentation) Session {
+	session := Session{
+		ID:        getStringPtr(s.ID),
+		UserID:    getStringPtr(s.UserID),
+		Username:  getStringPtr(s.Username),
+		IPAddress: getStringPtr(s.IPAddress),
+		Clients:   []string{},
+	}
+	// Keycloak reports session times in milliseconds
+	if s.Start != nil {
+		session.Start = time.UnixMilli(*s.Start).Format(time.RFC3339)
+	}
+	if s.LastAccess != nil {
+		session.LastAccess = time.UnixMilli(*s.LastAccess).Format(time.RFC3339)
+	}
+	if s.Clients != nil {
+		for _, clientID := range *s.Clients {
+			session.Clients = append(session.Clients, clientID)
+		}
+	}
+	return session
+}
+
+// GetUserSessions returns the active sessions of a user
+func (kc *KeycloakClient) GetUserSessions(ctx context.Context, adminToken, userID string) ([]Session, error) {
+	sessions, err := kc.client.GetUserSessions(ctx, adminToken, kc.config.Realm, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	result := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, toSession(s))
+	}
+	return result, nil
+}
+
+// GetClientSessions returns the active sessions of all users of the dashboard client
+func (kc *KeycloakClient) GetClientSessions(ctx context.Context, adminToken string) ([]Session, error) {
+	clientID := kc.config.ClientID
+	clients, err := kc.client.GetClients(ctx, adminToken, kc.config.Realm, gocloak.GetClientsParams{ClientID: &clientID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client %s: %w", clientID, err)
+	}
+	if len(clients) == 0 || clients[0].ID == nil {
+		return nil, fmt.Errorf("client %s not found in realm %s", clientID, kc.config.Realm)
+	}
+
+	sessions, err := kc.client.GetClientUserSessions(ctx, adminToken, kc.config.Realm, *clients[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client sessions: %w", err)
+	}
+	result := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, toSession(s))
+	}
+	return result, nil
+}
+
+// RevokeSession logs out a session
+func (kc *KeycloakClient) RevokeSession(ctx context.Context, adminToken, sessionID string) error {
+	if err := kc.client.LogoutUserSession(ctx, adminToken, kc.config.Realm, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// IntrospectToken checks a token with Keycloak, so revoked sessions are detected unlike with ValidateToken
+func (kc *KeycloakClient) IntrospectToken(ctx context.Context, token string) (*Introspection, error) {
+	result := &Introspection{}
+	if kc.config.ClientSecret != "" {
+		result.Source = "introspection"
+		introspection, err := kc.client.RetrospectToken(ctx, token, kc.config.ClientID, kc.config.ClientSecret, kc.config.Realm)
+		if err != nil {
+			return nil, fmt.Errorf("failed to introspect token: %w", err)
+		}
+		result.Active = introspection.Active != nil && *introspection.Active
+		if introspection.Exp != nil {
+			result.ExpiresAt = time.Unix(int64(*introspection.Exp), 0).Format(time.RFC3339)
+		}
+	} else {
+		// Public clients cannot introspect, the userinfo endpoint only accepts tokens of active sessions
+		result.Source = "userinfo"
+		_, err := kc.client.GetUserInfo(ctx, token, kc.config.Realm)
+		result.Active = err == nil
+	}
+	if !result.Active {
+		return result, nil
+	}
+
+	claims, err := kc.ValidateToken(ctx, token)
+	if err != nil {
+		result.Active = false
+		return result, nil
+	}
+	result.Claims = claims
+	return result, nil
+}
+
+// RefreshToken exchanges a refresh token for new tokens
+func (kc *KeycloakClient) RefreshToken(ctx context.Context, refreshToken string) (*gocloak.JWT, error) {
+	token, err := kc.client.RefreshToken(ctx, refreshToken, kc.config.ClientID, kc.config.ClientSecret, kc.config.Realm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return token, nil
+}
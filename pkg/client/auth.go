@@ -24,6 +24,8 @@ import (
 	"time"
 
 	"github.com/karmada-io/dashboard/pkg/etcd"
+	"github.com/karmada-io/dashboard/pkg/tracing"
+	"github.com/karmada-io/dashboard/pkg/util/requestid"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -67,10 +69,16 @@ func buildConfigFromAuthInfo(authInfo *clientcmdapi.AuthInfo) (*rest.Config, err
 
 	cmdCfg.CurrentContext = DefaultCmdConfigName
 
-	return clientcmd.NewDefaultClientConfig(
+	config, err := clientcmd.NewDefaultClientConfig(
 		*cmdCfg,
 		&clientcmd.ConfigOverrides{},
 	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Wrap(requestid.Transport)
+	config.Wrap(tracing.Transport(tracing.KarmadaControlPlane))
+	return config, nil
 }
 
 func buildAuthInfo(request *http.Request) (*clientcmdapi.AuthInfo, error) {
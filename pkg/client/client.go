@@ -154,9 +154,36 @@ func GetDynamicClient() (dynamic.Interface, error) {
 
 // GetDynamicClientForMember returns a dynamic client for a member cluster.
 //
-// If clusterName is provided, it will configure the client to use the Karmada proxy to access the member cluster.
+// If clusterName is provided, the client uses the first working access path of the cluster, the direct
+// endpoint it is registered with or the Karmada proxy, and the path is reported in the MemberAccessHeader
+// response header. The error wraps ErrMemberClusterUnreachable when neither works.
 // If clusterName is empty, it will return a regular dynamic client for the member cluster.
 func GetDynamicClientForMember(ctx *gin.Context, clusterName string) (dynamic.Interface, error) {
+	if err := CheckMemberClusterAccess(ctx, clusterName); err != nil {
+		return nil, err
+	}
+
+	if clusterName == "" {
+		memberConfig, err := GetMemberConfig()
+		if err != nil {
+			klog.ErrorS(err, "Failed to get member config")
+			return nil, fmt.Errorf("failed to get member config: %w", err)
+		}
+		return dynamic.NewForConfig(memberConfig)
+	}
+
+	dynamicClient, path, err := MemberDynamicClient(ctx, clusterName)
+	SetMemberAccessPath(ctx, clusterName, path)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(4).InfoS("Using member client", "cluster", clusterName, "path", path)
+	return dynamicClient, nil
+}
+
+// CheckMemberClusterAccess checks that the user of the request may access the member cluster.
+// Requests without a user, like background workers, and deployments without OpenFGA are allowed.
+func CheckMemberClusterAccess(ctx *gin.Context, clusterName string) error {
 	// Get the authenticated username using a comprehensive approach that checks:
 	// 1. User object in context (set by middleware)
 	// 2. JWT claims in context
@@ -221,31 +248,31 @@ func GetDynamicClientForMember(ctx *gin.Context, clusterName string) (dynamic.In
 			// Only check permissions if we have an FGA client
 			allowed, err := fga.HasClusterAccess(ctx, fgaClient, username, clusterName)
 			if err != nil {
-				return nil, fmt.Errorf("failed to check cluster access: %w", err)
+				return fmt.Errorf("failed to check cluster access: %w", err)
 			}
 			if !allowed {
-				return nil, fmt.Errorf("user %s does not have access to cluster %s", username, clusterName)
+				return fmt.Errorf("user %s does not have access to cluster %s", username, clusterName)
 			}
 		}
 	}
+	return nil
+}
 
-	memberConfig, err := GetMemberConfig()
+// DynamicClientForMemberCluster returns a dynamic client for a member cluster through the Karmada proxy,
+// using the dashboard's own access. Callers serving users must check access with CheckMemberClusterAccess.
+// Clients are cached per cluster until ResetMemberAccess.
+func DynamicClientForMemberCluster(clusterName string) (dynamic.Interface, error) {
+	if value, ok := proxyDynamicClients.Load(clusterName); ok {
+		return value.(dynamic.Interface), nil
+	}
+	config, err := proxyMemberConfig(clusterName)
 	if err != nil {
-		klog.ErrorS(err, "Failed to get member config")
-		return nil, fmt.Errorf("failed to get member config: %w", err)
+		return nil, err
 	}
-
-	// If a cluster name is provided, configure the client to use the Karmada proxy
-	if clusterName != "" {
-		karmadaConfig, _, err := GetKarmadaConfig()
-		if err != nil {
-			klog.ErrorS(err, "Failed to get karmada config")
-			return nil, fmt.Errorf("failed to get karmada config: %w", err)
-		}
-
-		memberConfig.Host = karmadaConfig.Host + fmt.Sprintf("/apis/cluster.karmada.io/v1alpha1/clusters/%s/proxy/", clusterName)
-		klog.V(4).InfoS("Using member config with proxy", "host", memberConfig.Host)
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
 	}
-
-	return dynamic.NewForConfig(memberConfig)
+	value, _ := proxyDynamicClients.LoadOrStore(clusterName, dynamicClient)
+	return value.(dynamic.Interface), nil
 }
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"reflect"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadainformers "github.com/karmada-io/karmada/pkg/generated/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// StartMemberClientInvalidation watches the Karmada clusters and drops the cached clients of a cluster
+// when it is deleted, its connection settings change or it becomes ready or not ready.
+func StartMemberClientInvalidation(ctx context.Context) {
+	karmadaClient := InClusterKarmadaClient()
+	if karmadaClient == nil {
+		klog.Warning("Karmada client is not initialized, member clients are only refreshed by their TTL")
+		return
+	}
+	factory := karmadainformers.NewSharedInformerFactory(karmadaClient, 0)
+	informer := factory.Cluster().V1alpha1().Clusters().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCluster, ok := oldObj.(*clusterv1alpha1.Cluster)
+			if !ok {
+				return
+			}
+			newCluster, ok := newObj.(*clusterv1alpha1.Cluster)
+			if !ok {
+				return
+			}
+			if memberAccessChanged(oldCluster, newCluster) {
+				klog.V(2).InfoS("Member cluster changed, dropping cached clients", "cluster", newCluster.Name)
+				ResetMemberAccess(newCluster.Name)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if cluster, ok := obj.(*clusterv1alpha1.Cluster); ok {
+				klog.V(2).InfoS("Member cluster deleted, dropping cached clients", "cluster", cluster.Name)
+				ResetMemberAccess(cluster.Name)
+			}
+		},
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to watch clusters for member client invalidation")
+		return
+	}
+	factory.Start(ctx.Done())
+	klog.InfoS("Member client invalidation started")
+}
+
+// memberAccessChanged reports whether a cluster update may change how the cluster is reached
+func memberAccessChanged(oldCluster, newCluster *clusterv1alpha1.Cluster) bool {
+	oldSpec, newSpec := oldCluster.Spec, newCluster.Spec
+	if oldSpec.SyncMode != newSpec.SyncMode ||
+		oldSpec.APIEndpoint != newSpec.APIEndpoint ||
+		oldSpec.ProxyURL != newSpec.ProxyURL ||
+		oldSpec.InsecureSkipTLSVerification != newSpec.InsecureSkipTLSVerification ||
+		!reflect.DeepEqual(oldSpec.SecretRef, newSpec.SecretRef) ||
+		!reflect.DeepEqual(oldSpec.ProxyHeader, newSpec.ProxyHeader) {
+		return true
+	}
+	return clusterReady(oldCluster) != clusterReady(newCluster)
+}
+
+func clusterReady(cluster *clusterv1alpha1.Cluster) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == clusterv1alpha1.ClusterConditionReady {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
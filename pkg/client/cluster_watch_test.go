@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMemberAccessChanged(t *testing.T) {
+	base := func() *clusterv1alpha1.Cluster {
+		return &clusterv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "member1"},
+			Spec: clusterv1alpha1.ClusterSpec{
+				SyncMode:    clusterv1alpha1.Push,
+				APIEndpoint: "https://member1:6443",
+				SecretRef:   &clusterv1alpha1.LocalSecretReference{Namespace: "karmada-cluster", Name: "member1"},
+			},
+			Status: clusterv1alpha1.ClusterStatus{
+				Conditions: []metav1.Condition{{Type: clusterv1alpha1.ClusterConditionReady, Status: metav1.ConditionTrue}},
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		update func(cluster *clusterv1alpha1.Cluster)
+		want   bool
+	}{
+		{name: "labels", update: func(c *clusterv1alpha1.Cluster) { c.Labels = map[string]string{"a": "b"} }},
+		{name: "taints", update: func(c *clusterv1alpha1.Cluster) { c.Spec.Taints = []corev1.Taint{{Key: "a"}} }},
+		{name: "endpoint", update: func(c *clusterv1alpha1.Cluster) { c.Spec.APIEndpoint = "https://other:6443" }, want: true},
+		{name: "secret", update: func(c *clusterv1alpha1.Cluster) { c.Spec.SecretRef.Name = "rotated" }, want: true},
+		{name: "sync mode", update: func(c *clusterv1alpha1.Cluster) { c.Spec.SyncMode = clusterv1alpha1.Pull }, want: true},
+		{name: "not ready", update: func(c *clusterv1alpha1.Cluster) { c.Status.Conditions[0].Status = metav1.ConditionFalse }, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := base()
+			tt.update(updated)
+			if got := memberAccessChanged(base(), updated); got != tt.want {
+				t.Errorf("memberAccessChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"sync"
 
 	"github.com/karmada-io/dashboard/pkg/auth/fga"
+	"github.com/karmada-io/dashboard/pkg/tracing"
+	"github.com/karmada-io/dashboard/pkg/util/requestid"
 	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -44,6 +46,7 @@ var (
 	inClusterClientForKarmadaAPIServer kubeclient.Interface
 	inClusterClientForMemberAPIServer  kubeclient.Interface
 	memberClients                      sync.Map
+	proxyDynamicClients                sync.Map
 	// CurrentUser stores the username for permission checks when context isn't available
 	CurrentUser string
 	// CurrentUserMutex protects concurrent access to CurrentUser
@@ -114,6 +117,8 @@ func (in *configBuilder) buildRestConfig() (*rest.Config, error) {
 	// TODO: make clear that why karmada apiserver seems only can use application/json, however kubernetest apiserver can use "application/vnd.kubernetes.protobuf"
 	restConfig.UserAgent = DefaultUserAgent + "/" + in.userAgent
 	restConfig.TLSClientConfig.Insecure = in.insecure
+	// Kubernetes API requests carry the ID of the dashboard request they were made for
+	restConfig.Wrap(requestid.Transport)
 
 	return restConfig, nil
 }
@@ -147,6 +152,7 @@ func InitKubeConfig(options ...Option) {
 		klog.Infof("InitKubeConfig by InClusterConfig method")
 		restConfig.UserAgent = DefaultUserAgent + "/" + builder.userAgent
 		restConfig.TLSClientConfig.Insecure = builder.insecure
+		restConfig.Wrap(requestid.Transport)
 		kubernetesRestConfig = restConfig
 
 		apiConfig := ConvertRestConfigToAPIConfig(restConfig)
@@ -167,6 +173,7 @@ func InitKubeConfig(options ...Option) {
 		}
 		kubernetesAPIConfig = apiConfig
 	}
+	kubernetesRestConfig.Wrap(tracing.Transport(tracing.ManagementCluster))
 }
 
 // InClusterClient returns a kubernetes client.
@@ -214,6 +221,7 @@ func InitKarmadaConfig(options ...Option) {
 		klog.Errorf("Could not init client config: %s", err)
 		os.Exit(1)
 	}
+	restConfig.Wrap(tracing.Transport(tracing.KarmadaControlPlane))
 	karmadaRestConfig = restConfig
 
 	apiConfig, err := builder.buildAPIConfig()
@@ -339,8 +347,10 @@ func InClusterClientForMemberCluster(clusterName string) kubeclient.Interface {
 		klog.ErrorS(err, "Could not get member restConfig")
 		return nil
 	}
-	memberConfig.Host = restConfig.Host + fmt.Sprintf(proxyURL, clusterName)
-	c, err := kubeclient.NewForConfig(memberConfig)
+	config := rest.CopyConfig(memberConfig)
+	config.Host = restConfig.Host + fmt.Sprintf(proxyURL, clusterName)
+	config.Wrap(tracing.Transport(clusterName))
+	c, err := kubeclient.NewForConfig(config)
 	if err != nil {
 		klog.ErrorS(err, "Could not init kubernetes in-cluster client for member apiserver")
 		return nil
@@ -0,0 +1,349 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/tracing"
+	"github.com/karmada-io/dashboard/pkg/util/requestid"
+)
+
+// MemberAccessPath is the way the dashboard reaches the API server of a member cluster
+type MemberAccessPath string
+
+const (
+	// MemberAccessDirect uses the API endpoint and credentials the cluster is registered with in Karmada
+	MemberAccessDirect MemberAccessPath = "direct"
+	// MemberAccessProxy goes through the cluster proxy of the Karmada aggregated API server
+	MemberAccessProxy MemberAccessPath = "karmada-proxy"
+	// MemberAccessCache serves data from the dashboard caches because the cluster is unreachable
+	MemberAccessCache MemberAccessPath = "cache"
+)
+
+// MemberAccessHeader lists the access path used for each member cluster of a request, as cluster=path pairs
+const MemberAccessHeader = "X-Member-Access-Path"
+
+const (
+	// memberAccessContextKey holds the access paths of a request in the gin context
+	memberAccessContextKey = "memberAccessPaths"
+	// DefaultMemberClientTTL is how long the client of a member cluster is reused before its access is resolved again
+	DefaultMemberClientTTL = 5 * time.Minute
+	// memberUnreachableTTL is how long an unreachable cluster is reported before probing again
+	memberUnreachableTTL = 15 * time.Second
+	// memberProbeTimeout bounds the version request used to check an access path
+	memberProbeTimeout = 5 * time.Second
+)
+
+// ErrMemberClusterUnreachable is returned when no access path reaches the member cluster.
+// Callers holding cached data can serve it and report MemberAccessCache.
+var ErrMemberClusterUnreachable = errors.New("member cluster is unreachable")
+
+// memberAccessEntry is the resolved access to a member cluster, config and client are nil when it was unreachable
+type memberAccessEntry struct {
+	config  *rest.Config
+	client  dynamic.Interface
+	path    MemberAccessPath
+	err     error
+	expires time.Time
+}
+
+// memberAccessResolver picks the first working access path of a member cluster and keeps a client
+// for it until the TTL expires or the cluster changes, so requests share connections
+type memberAccessResolver struct {
+	directConfig func(ctx context.Context, clusterName string) (*rest.Config, error)
+	proxyConfig  func(clusterName string) (*rest.Config, error)
+	probe        func(config *rest.Config) error
+	now          func() time.Time
+	// ttl is how long a reachable cluster is cached, 0 resolves the access on every request
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]memberAccessEntry
+}
+
+var defaultMemberAccessResolver = newMemberAccessResolver(directMemberConfig, proxyMemberConfig, probeMemberConfig)
+
+func newMemberAccessResolver(
+	directConfig func(ctx context.Context, clusterName string) (*rest.Config, error),
+	proxyConfig func(clusterName string) (*rest.Config, error),
+	probe func(config *rest.Config) error,
+) *memberAccessResolver {
+	return &memberAccessResolver{
+		directConfig: directConfig,
+		proxyConfig:  proxyConfig,
+		probe:        probe,
+		now:          time.Now,
+		ttl:          DefaultMemberClientTTL,
+		entries:      make(map[string]memberAccessEntry),
+	}
+}
+
+// resolve returns a config for the member cluster and the path it uses, trying the direct endpoint first
+// and the Karmada proxy next. When neither answers, the path is MemberAccessCache and the error wraps
+// ErrMemberClusterUnreachable.
+func (r *memberAccessResolver) resolve(ctx context.Context, clusterName string) (*rest.Config, MemberAccessPath, error) {
+	entry := r.entry(ctx, clusterName)
+	if entry.config == nil {
+		return nil, entry.path, entry.err
+	}
+	return rest.CopyConfig(entry.config), entry.path, nil
+}
+
+// dynamicClient returns the cached dynamic client of the member cluster and the path it uses
+func (r *memberAccessResolver) dynamicClient(ctx context.Context, clusterName string) (dynamic.Interface, MemberAccessPath, error) {
+	entry := r.entry(ctx, clusterName)
+	if entry.client == nil {
+		return nil, entry.path, entry.err
+	}
+	return entry.client, entry.path, nil
+}
+
+func (r *memberAccessResolver) entry(ctx context.Context, clusterName string) memberAccessEntry {
+	r.mu.Lock()
+	entry, ok := r.entries[clusterName]
+	ttl := r.ttl
+	r.mu.Unlock()
+	if ok && r.now().Before(entry.expires) {
+		return entry
+	}
+
+	entry = r.lookup(ctx, clusterName)
+	if entry.config == nil {
+		// A canceled request says nothing about the cluster, so the failure is not remembered
+		if ctx.Err() == nil {
+			entry.expires = r.now().Add(memberUnreachableTTL)
+			r.store(clusterName, entry)
+		}
+		return entry
+	}
+	dynamicClient, err := dynamic.NewForConfig(entry.config)
+	if err != nil {
+		return memberAccessEntry{path: entry.path, err: fmt.Errorf("failed to create dynamic client for cluster %s: %w", clusterName, err)}
+	}
+	entry.client = dynamicClient
+	if ttl > 0 {
+		entry.expires = r.now().Add(ttl)
+		r.store(clusterName, entry)
+	}
+	return entry
+}
+
+func (r *memberAccessResolver) store(clusterName string, entry memberAccessEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[clusterName] = entry
+}
+
+func (r *memberAccessResolver) lookup(ctx context.Context, clusterName string) memberAccessEntry {
+	var failures []string
+
+	config, err := r.directConfig(ctx, clusterName)
+	if err == nil {
+		err = r.probe(config)
+	}
+	if err == nil {
+		return memberAccessEntry{config: config, path: MemberAccessDirect}
+	}
+	klog.V(4).InfoS("Direct access to member cluster failed", "cluster", clusterName, "error", err)
+	failures = append(failures, fmt.Sprintf("%s: %v", MemberAccessDirect, err))
+
+	config, err = r.proxyConfig(clusterName)
+	if err == nil {
+		err = r.probe(config)
+	}
+	if err == nil {
+		return memberAccessEntry{config: config, path: MemberAccessProxy}
+	}
+	klog.V(4).InfoS("Karmada proxy access to member cluster failed", "cluster", clusterName, "error", err)
+	failures = append(failures, fmt.Sprintf("%s: %v", MemberAccessProxy, err))
+
+	klog.InfoS("Member cluster is unreachable", "cluster", clusterName, "attempts", failures)
+	return memberAccessEntry{
+		path: MemberAccessCache,
+		err:  fmt.Errorf("%w: cluster %s (%s)", ErrMemberClusterUnreachable, clusterName, strings.Join(failures, "; ")),
+	}
+}
+
+// forget drops the resolved access of a cluster so the next request probes again
+func (r *memberAccessResolver) forget(clusterName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, clusterName)
+}
+
+func (r *memberAccessResolver) setTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ttl = ttl
+}
+
+// directMemberConfig builds a config for the API endpoint and secret the cluster is registered with.
+// Pull mode clusters have neither, their API server is only reachable through the Karmada agent.
+func directMemberConfig(ctx context.Context, clusterName string) (*rest.Config, error) {
+	karmadaClient := InClusterKarmadaClient()
+	kubeClient := InClusterClientForKarmadaAPIServer()
+	if karmadaClient == nil || kubeClient == nil {
+		return nil, fmt.Errorf("karmada clients are not initialized")
+	}
+	config, err := karmadautil.BuildClusterConfig(clusterName,
+		func(name string) (*clusterv1alpha1.Cluster, error) {
+			cluster, err := karmadaClient.ClusterV1alpha1().Clusters().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			if cluster.Spec.SyncMode == clusterv1alpha1.Pull {
+				return nil, fmt.Errorf("cluster %s is in pull mode", name)
+			}
+			return cluster, nil
+		},
+		func(namespace, name string) (*corev1.Secret, error) {
+			return kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		})
+	if err != nil {
+		return nil, err
+	}
+	config.Wrap(requestid.Transport)
+	config.Wrap(tracing.Transport(clusterName))
+	return config, nil
+}
+
+// proxyMemberConfig builds a config for the cluster proxy of the Karmada aggregated API server
+func proxyMemberConfig(clusterName string) (*rest.Config, error) {
+	karmadaConfig, _, err := GetKarmadaConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get karmada config: %w", err)
+	}
+	memberConfig, err := GetMemberConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member config: %w", err)
+	}
+	config := rest.CopyConfig(memberConfig)
+	config.Host = karmadaConfig.Host + fmt.Sprintf(proxyURL, clusterName)
+	config.Wrap(tracing.Transport(clusterName))
+	return config, nil
+}
+
+// probeMemberConfig checks that the API server answers a version request
+func probeMemberConfig(config *rest.Config) error {
+	probeConfig := rest.CopyConfig(config)
+	probeConfig.Timeout = memberProbeTimeout
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(probeConfig)
+	if err != nil {
+		return err
+	}
+	_, err = discoveryClient.ServerVersion()
+	return err
+}
+
+// MemberConfig returns a rest config for the member cluster over the first working access path.
+// The error wraps ErrMemberClusterUnreachable when the cluster cannot be reached.
+func MemberConfig(ctx context.Context, clusterName string) (*rest.Config, MemberAccessPath, error) {
+	return defaultMemberAccessResolver.resolve(ctx, clusterName)
+}
+
+// MemberDynamicClient returns a dynamic client for the member cluster over the first working access path.
+// Clients are cached per cluster and shared by the requests until the TTL expires or the cluster changes.
+func MemberDynamicClient(ctx context.Context, clusterName string) (dynamic.Interface, MemberAccessPath, error) {
+	return defaultMemberAccessResolver.dynamicClient(ctx, clusterName)
+}
+
+// ConfigureMemberClientCache sets how long member cluster clients are cached, 0 disables the cache
+func ConfigureMemberClientCache(ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+	defaultMemberAccessResolver.setTTL(ttl)
+}
+
+// ResetMemberAccess drops the cached clients and access path of a cluster, e.g. after its registration changed
+func ResetMemberAccess(clusterName string) {
+	defaultMemberAccessResolver.forget(clusterName)
+	proxyDynamicClients.Delete(clusterName)
+	memberClients.Delete(clusterName)
+}
+
+// memberAccessPaths collects the access path of each member cluster used by a request
+type memberAccessPaths struct {
+	mu    sync.Mutex
+	paths map[string]MemberAccessPath
+}
+
+// SetMemberAccessPath records how a member cluster was reached for the request and reports it
+// in the MemberAccessHeader response header
+func SetMemberAccessPath(ctx *gin.Context, clusterName string, path MemberAccessPath) {
+	if ctx == nil {
+		return
+	}
+	var accessPaths *memberAccessPaths
+	if value, ok := ctx.Get(memberAccessContextKey); ok {
+		accessPaths, _ = value.(*memberAccessPaths)
+	}
+	if accessPaths == nil {
+		accessPaths = &memberAccessPaths{paths: make(map[string]MemberAccessPath)}
+		ctx.Set(memberAccessContextKey, accessPaths)
+	}
+
+	accessPaths.mu.Lock()
+	accessPaths.paths[clusterName] = path
+	pairs := make([]string, 0, len(accessPaths.paths))
+	for name, p := range accessPaths.paths {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, p))
+	}
+	accessPaths.mu.Unlock()
+	sort.Strings(pairs)
+
+	// Background workers pass an empty context without a response writer
+	if ctx.Writer != nil {
+		ctx.Writer.Header().Set(MemberAccessHeader, strings.Join(pairs, ","))
+	}
+}
+
+// GetMemberAccessPaths returns the access path of each member cluster used so far by the request
+func GetMemberAccessPaths(ctx *gin.Context) map[string]MemberAccessPath {
+	paths := map[string]MemberAccessPath{}
+	value, ok := ctx.Get(memberAccessContextKey)
+	if !ok {
+		return paths
+	}
+	accessPaths, ok := value.(*memberAccessPaths)
+	if !ok {
+		return paths
+	}
+	accessPaths.mu.Lock()
+	defer accessPaths.mu.Unlock()
+	for name, path := range accessPaths.paths {
+		paths[name] = path
+	}
+	return paths
+}
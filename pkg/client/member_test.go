@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/rest"
+)
+
+func TestMemberAccessResolver(t *testing.T) {
+	unreachable := errors.New("connection refused")
+	tests := []struct {
+		name      string
+		direct    error
+		proxy     error
+		wantPath  MemberAccessPath
+		wantHost  string
+		wantError bool
+	}{
+		{name: "direct", wantPath: MemberAccessDirect, wantHost: "https://direct"},
+		{name: "proxy fallback", direct: unreachable, wantPath: MemberAccessProxy, wantHost: "https://proxy"},
+		{name: "unreachable", direct: unreachable, proxy: unreachable, wantPath: MemberAccessCache, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newMemberAccessResolver(
+				func(_ context.Context, _ string) (*rest.Config, error) {
+					return &rest.Config{Host: "https://direct"}, nil
+				},
+				func(_ string) (*rest.Config, error) { return &rest.Config{Host: "https://proxy"}, nil },
+				func(config *rest.Config) error {
+					if config.Host == "https://direct" {
+						return tt.direct
+					}
+					return tt.proxy
+				},
+			)
+			config, path, err := resolver.resolve(context.Background(), "member1")
+			if path != tt.wantPath {
+				t.Errorf("path = %s, want %s", path, tt.wantPath)
+			}
+			if tt.wantError {
+				if !errors.Is(err, ErrMemberClusterUnreachable) {
+					t.Errorf("error = %v, want ErrMemberClusterUnreachable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Host != tt.wantHost {
+				t.Errorf("host = %s, want %s", config.Host, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestMemberAccessResolverCachesPath(t *testing.T) {
+	probes := 0
+	directUp := false
+	resolver := newMemberAccessResolver(
+		func(_ context.Context, _ string) (*rest.Config, error) {
+			return &rest.Config{Host: "https://direct"}, nil
+		},
+		func(_ string) (*rest.Config, error) { return &rest.Config{Host: "https://proxy"}, nil },
+		func(config *rest.Config) error {
+			probes++
+			if config.Host == "https://direct" && !directUp {
+				return errors.New("timeout")
+			}
+			return nil
+		},
+	)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	if _, path, _ := resolver.resolve(context.Background(), "member1"); path != MemberAccessProxy {
+		t.Fatalf("path = %s, want %s", path, MemberAccessProxy)
+	}
+	directUp = true
+	config, path, _ := resolver.resolve(context.Background(), "member1")
+	if path != MemberAccessProxy || probes != 2 {
+		t.Errorf("path = %s after %d probes, want cached %s after 2", path, probes, MemberAccessProxy)
+	}
+	// The returned config is a copy, changing it does not affect the cached one
+	config.Host = "https://changed"
+
+	now = now.Add(resolver.ttl + time.Second)
+	if _, path, _ := resolver.resolve(context.Background(), "member1"); path != MemberAccessDirect {
+		t.Errorf("path = %s after expiry, want %s", path, MemberAccessDirect)
+	}
+
+	directUp = false
+	resolver.forget("member1")
+	config, path, _ = resolver.resolve(context.Background(), "member1")
+	if path != MemberAccessProxy || config.Host != "https://proxy" {
+		t.Errorf("path = %s host = %s after forget, want %s https://proxy", path, config.Host, MemberAccessProxy)
+	}
+}
+
+func TestMemberAccessResolverSharesClient(t *testing.T) {
+	newResolver := func() *memberAccessResolver {
+		return newMemberAccessResolver(
+			func(_ context.Context, _ string) (*rest.Config, error) {
+				return &rest.Config{Host: "https://direct"}, nil
+			},
+			func(_ string) (*rest.Config, error) { return &rest.Config{Host: "https://proxy"}, nil },
+			func(_ *rest.Config) error { return nil },
+		)
+	}
+
+	resolver := newResolver()
+	first, _, err := resolver.dynamicClient(context.Background(), "member1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _, _ := resolver.dynamicClient(context.Background(), "member1")
+	if first != second {
+		t.Error("expected the cached client to be shared")
+	}
+	resolver.forget("member1")
+	if third, _, _ := resolver.dynamicClient(context.Background(), "member1"); third == first {
+		t.Error("expected a new client after forget")
+	}
+
+	resolver = newResolver()
+	resolver.setTTL(0)
+	first, _, _ = resolver.dynamicClient(context.Background(), "member1")
+	if second, _, _ := resolver.dynamicClient(context.Background(), "member1"); second == first {
+		t.Error("expected a new client per call with the cache disabled")
+	}
+}
+
+func TestSetMemberAccessPath(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	SetMemberAccessPath(ctx, "member2", MemberAccessProxy)
+	SetMemberAccessPath(ctx, "member1", MemberAccessDirect)
+
+	if got, want := recorder.Header().Get(MemberAccessHeader), "member1=direct,member2=karmada-proxy"; got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+	paths := GetMemberAccessPaths(ctx)
+	if len(paths) != 2 || paths["member2"] != MemberAccessProxy {
+		t.Errorf("paths = %v", paths)
+	}
+
+	// Background workers use an empty context without a response writer
+	SetMemberAccessPath(&gin.Context{}, "member1", MemberAccessCache)
+}
@@ -28,6 +28,7 @@ const (
 	ResourceKindClusterPropagationPolicy = "clusterpropagationpolicy"
 	ResourceKindOverridePolicy           = "overridepolicy"
 	ResourceKindClusterOverridePolicy    = "clusteroverridepolicy"
+	ResourceKindFederatedResourceQuota   = "federatedresourcequota"
 	ResourceKindConfigMap                = "configmap"
 	ResourceKindDaemonSet                = "daemonset"
 	ResourceKindDeployment               = "deployment"
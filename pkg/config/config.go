@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/karmada-io/karmada/pkg/util/fedinformer"
 	"gopkg.in/yaml.v3"
@@ -31,11 +32,14 @@ import (
 	"k8s.io/klog/v2"
 )
 
-var dashboardConfig DashboardConfig
+var (
+	dashboardConfig DashboardConfig
+	// configMu guards dashboardConfig, which the informer replaces while requests read it
+	configMu sync.RWMutex
+)
 
 const (
 	configName             = "ml-platform-admin-configmap"
-	configNamespace        = "ml-platform-system"
 	defaultEnvName         = "prod"
 	defaultSystemNamespace = "ml-platform-system"
 )
@@ -57,10 +61,19 @@ func GetConfigKey() string {
 	return fmt.Sprintf("%s.yaml", envName)
 }
 
-// GetNamespace returns the system namespace from environment variable or default.
+// GetNamespace returns the system namespace, the namespace of the runtime config if it sets one, otherwise the
+// one of the environment variable or the default.
 // Environment variable: KARMADA_SYSTEM_NAMESPACE
 // Default: ml-platform-system
 func GetNamespace() string {
+	if namespace := GetDashboardConfig().Runtime.Namespace; namespace != "" {
+		return namespace
+	}
+	return configMapNamespace()
+}
+
+// configMapNamespace returns the namespace of the dashboard ConfigMap, which the runtime config cannot change
+func configMapNamespace() string {
 	namespace := os.Getenv("KARMADA_SYSTEM_NAMESPACE")
 	if namespace == "" {
 		namespace = defaultSystemNamespace
@@ -68,6 +81,17 @@ func GetNamespace() string {
 	return namespace
 }
 
+// setDashboardConfig replaces the dashboard configuration and logs the sections that changed
+func setDashboardConfig(newConfig DashboardConfig) {
+	configMu.Lock()
+	changed := ChangedFields(dashboardConfig, newConfig)
+	dashboardConfig = newConfig
+	configMu.Unlock()
+	if len(changed) > 0 {
+		klog.InfoS("Dashboard configuration reloaded", "changed", changed)
+	}
+}
+
 // InitDashboardConfig initializes the dashboard configuration using a Kubernetes client.
 func InitDashboardConfig(k8sClient kubernetes.Interface, stopper <-chan struct{}) {
 	factory := informers.NewSharedInformerFactory(k8sClient, 0)
@@ -78,28 +102,18 @@ func InitDashboardConfig(k8sClient kubernetes.Interface, stopper <-chan struct{}
 	}
 	filterFunc := func(obj interface{}) bool {
 		configMap, ok := obj.(*v1.ConfigMap)
-		return ok && configMap.Namespace == GetNamespace() && configMap.Name == configName
+		return ok && configMap.Namespace == configMapNamespace() && configMap.Name == configName
 	}
 	onAdd := func(obj interface{}) {
 		configMap := obj.(*v1.ConfigMap)
 		klog.Infof("ConfigMap %s Added", configMap.Name)
 		klog.Infof("ConfigMap Data is \n%+v", configMap.Data[GetConfigKey()])
-		var tmpConfig DashboardConfig
-		if err := yaml.Unmarshal([]byte(configMap.Data[GetConfigKey()]), &tmpConfig); err != nil {
-			klog.Errorf("Failed to unmarshal ConfigMap %s: %v", configMap.Name, err)
-		} else {
-			dashboardConfig = tmpConfig
-		}
+		loadConfigMap(configMap)
 	}
 	onUpdate := func(_, newObj interface{}) {
 		newConfigMap := newObj.(*v1.ConfigMap)
 		klog.V(2).Infof("ConfigMap %s Updated", newConfigMap.Name)
-		var tmpConfig DashboardConfig
-		if err := yaml.Unmarshal([]byte(newConfigMap.Data[GetConfigKey()]), &tmpConfig); err != nil {
-			klog.Errorf("Failed to unmarshal ConfigMap %s: %v", newConfigMap.Name, err)
-		} else {
-			dashboardConfig = tmpConfig
-		}
+		loadConfigMap(newConfigMap)
 	}
 	evtHandler := fedinformer.NewFilteringHandlerOnAllEvents(filterFunc, onAdd, onUpdate, nil)
 	_, err = resource.Informer().AddEventHandler(evtHandler)
@@ -111,8 +125,25 @@ func InitDashboardConfig(k8sClient kubernetes.Interface, stopper <-chan struct{}
 	klog.Infof("ConfigMap informer started, waiting for ConfigMap events...")
 }
 
+// loadConfigMap applies the configuration of the dashboard ConfigMap. An invalid runtime section, e.g. from an
+// edit of the ConfigMap by hand, is ignored and the current one kept.
+func loadConfigMap(configMap *v1.ConfigMap) {
+	var tmpConfig DashboardConfig
+	if err := yaml.Unmarshal([]byte(configMap.Data[GetConfigKey()]), &tmpConfig); err != nil {
+		klog.Errorf("Failed to unmarshal ConfigMap %s: %v", configMap.Name, err)
+		return
+	}
+	if err := tmpConfig.Runtime.Validate(); err != nil {
+		klog.Errorf("Ignoring the invalid runtime configuration of ConfigMap %s: %v", configMap.Name, err)
+		tmpConfig.Runtime = GetDashboardConfig().Runtime
+	}
+	setDashboardConfig(tmpConfig)
+}
+
 // GetDashboardConfig returns a copy of the current dashboard configuration.
 func GetDashboardConfig() DashboardConfig {
+	configMu.RLock()
+	defer configMu.RUnlock()
 	return DashboardConfig{
 		DockerRegistries:   dashboardConfig.DockerRegistries,
 		ChartRegistries:    dashboardConfig.ChartRegistries,
@@ -120,13 +151,14 @@ func GetDashboardConfig() DashboardConfig {
 		PathPrefix:         dashboardConfig.PathPrefix,
 		MetricsDashboards:  dashboardConfig.MetricsDashboards,
 		AIAgentChatWebHook: dashboardConfig.AIAgentChatWebHook,
+		Runtime:            dashboardConfig.Runtime,
 	}
 }
 
 // UpdateDashboardConfig updates the dashboard configuration in the Kubernetes ConfigMap.
 func UpdateDashboardConfig(k8sClient kubernetes.Interface, newDashboardConfig DashboardConfig) error {
 	ctx := context.TODO()
-	oldConfigMap, err := k8sClient.CoreV1().ConfigMaps(GetNamespace()).Get(ctx, configName, metav1.GetOptions{})
+	oldConfigMap, err := k8sClient.CoreV1().ConfigMaps(configMapNamespace()).Get(ctx, configName, metav1.GetOptions{})
 	if err != nil {
 		klog.Errorf("Failed to get ConfigMap %s: %v", configName, err)
 		return err
@@ -137,8 +169,11 @@ func UpdateDashboardConfig(k8sClient kubernetes.Interface, newDashboardConfig Da
 		klog.Errorf("Failed to marshal new dashboard config: %v", err)
 		return err
 	}
+	if oldConfigMap.Data == nil {
+		oldConfigMap.Data = map[string]string{}
+	}
 	oldConfigMap.Data[configKey] = string(buff)
-	_, err = k8sClient.CoreV1().ConfigMaps(GetNamespace()).Update(ctx, oldConfigMap, metav1.UpdateOptions{})
+	_, err = k8sClient.CoreV1().ConfigMaps(configMapNamespace()).Update(ctx, oldConfigMap, metav1.UpdateOptions{})
 	if err != nil {
 		klog.Errorf("Failed to update ConfigMap %s: %v", configName, err)
 		return err
@@ -162,6 +197,6 @@ func InitDashboardConfigFromMountFile(mountPath string) error {
 		klog.Errorf("Failed to unmarshal from content %v", err)
 		return err
 	}
-	dashboardConfig = tmpConfig
+	setDashboardConfig(tmpConfig)
 	return nil
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// Names of the features that can be switched on and off in the runtime config
+const (
+	FeatureCAPIProvisioning = "capi-provisioning"
+	FeatureMigration        = "migration"
+	FeatureImpersonation    = "impersonation"
+)
+
+// Feature is a module that a deployment can switch on or off
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Stage is alpha or beta, features that are generally available are no longer switchable
+	Stage   string `json:"stage"`
+	Default bool   `json:"default"`
+}
+
+// FeatureStatus is a feature and whether it is enabled in the current configuration
+type FeatureStatus struct {
+	Feature
+	Enabled bool `json:"enabled"`
+}
+
+// Features are the switchable features. They are enabled by default so that deployments that do not set
+// them keep every module.
+var Features = []Feature{
+	{
+		Name:        FeatureCAPIProvisioning,
+		Description: "Provisioning of member clusters with Cluster API",
+		Stage:       "alpha",
+		Default:     true,
+	},
+	{
+		Name:        FeatureMigration,
+		Description: "Migration wizard moving stateful workloads between clusters",
+		Stage:       "alpha",
+		Default:     true,
+	},
+	{
+		Name:        FeatureImpersonation,
+		Description: "Dashboard admins issuing requests as another user with the Impersonate-User header",
+		Stage:       "alpha",
+		Default:     true,
+	},
+}
+
+func lookupFeature(name string) (Feature, bool) {
+	for _, feature := range Features {
+		if feature.Name == name {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// FeatureEnabled returns whether a feature is enabled by the runtime config or by default. Unknown features are disabled.
+func FeatureEnabled(name string) bool {
+	feature, ok := lookupFeature(name)
+	if !ok {
+		return false
+	}
+	if enabled, ok := GetDashboardConfig().Runtime.Features[name]; ok {
+		return enabled
+	}
+	return feature.Default
+}
+
+// FeatureStatuses returns every feature and whether it is enabled
+func FeatureStatuses() []FeatureStatus {
+	features := GetDashboardConfig().Runtime.Features
+	statuses := make([]FeatureStatus, 0, len(Features))
+	for _, feature := range Features {
+		enabled, ok := features[feature.Name]
+		if !ok {
+			enabled = feature.Default
+		}
+		statuses = append(statuses, FeatureStatus{Feature: feature, Enabled: enabled})
+	}
+	return statuses
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// historyConfigName stores the changes made to the dashboard configuration through the API
+	historyConfigName = "ml-platform-admin-config-history"
+	historyKey        = "history"
+	maxConfigHistory  = 50
+)
+
+// ConfigChange records an update of the dashboard configuration
+type ConfigChange struct {
+	Time string `json:"time"`
+	User string `json:"user"`
+	// Fields are the changed settings, see ChangedFields
+	Fields []string `json:"fields"`
+}
+
+// GetConfigHistory returns the recorded changes of the dashboard configuration, newest last
+func GetConfigHistory(ctx context.Context, k8sClient kubernetes.Interface) ([]ConfigChange, error) {
+	cm, err := k8sClient.CoreV1().ConfigMaps(configMapNamespace()).Get(ctx, historyConfigName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []ConfigChange{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	history := []ConfigChange{}
+	if data, ok := cm.Data[historyKey]; ok {
+		if err := json.Unmarshal([]byte(data), &history); err != nil {
+			return nil, fmt.Errorf("failed to parse config history: %v", err)
+		}
+	}
+	return history, nil
+}
+
+// RecordConfigChange appends a change to the history, which keeps the last maxConfigHistory changes
+func RecordConfigChange(ctx context.Context, k8sClient kubernetes.Interface, change ConfigChange) error {
+	history, err := GetConfigHistory(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	history = append(history, change)
+	if len(history) > maxConfigHistory {
+		history = history[len(history)-maxConfigHistory:]
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	configMaps := k8sClient.CoreV1().ConfigMaps(configMapNamespace())
+	cm, err := configMaps.Get(ctx, historyConfigName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      historyConfigName,
+				Namespace: configMapNamespace(),
+			},
+			Data: map[string]string{historyKey: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[historyKey] = string(data)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
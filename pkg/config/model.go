@@ -55,4 +55,62 @@ type DashboardConfig struct {
 	PathPrefix         string             `yaml:"path_prefix" json:"path_prefix"`
 	MetricsDashboards  []MetricsDashboard `yaml:"metrics_dashboards" json:"metrics_dashboards"`
 	AIAgentChatWebHook string             `yaml:"ai_agent_chat_webhook" json:"ai_agent_chat_webhook"`
+	Runtime            RuntimeConfig      `yaml:"runtime" json:"runtime"`
+}
+
+// RuntimeConfig holds the settings of the API server that take effect without a restart.
+type RuntimeConfig struct {
+	// Namespace overrides the system namespace of the platform resources, e.g. backups and jobs.
+	// The dashboard ConfigMap itself stays in the KARMADA_SYSTEM_NAMESPACE namespace.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// ManifestBaseURL is where the migration controller manifests are fetched from, e.g. a mirror of the operator repository
+	ManifestBaseURL string `yaml:"manifest_base_url,omitempty" json:"manifest_base_url,omitempty"`
+	// Features enables or disables the modules of Features by name, the ones left out keep their default
+	Features map[string]bool `yaml:"features,omitempty" json:"features,omitempty"`
+	// Harbor is where registry projects are provisioned for users and projects, provisioning is off when it is not set
+	Harbor *HarborConfig `yaml:"harbor,omitempty" json:"harbor,omitempty"`
+	// ExternalSecrets lets credentials reference an external store through the External Secrets Operator instead of
+	// being stored in dashboard-managed secrets, credentials can only be given in plain text when it is not set
+	ExternalSecrets *ExternalSecretsConfig `yaml:"external_secrets,omitempty" json:"external_secrets,omitempty"`
+	// TrashRetention is how long deleted backup and recovery records stay in the trash before they are purged,
+	// e.g. 72h, it defaults to DefaultTrashRetention
+	TrashRetention string `yaml:"trash_retention,omitempty" json:"trash_retention,omitempty"`
+	// CheckpointThrottle are the limits of the migration controllers of every cluster, a cluster or a backup can set its own
+	CheckpointThrottle *CheckpointThrottleConfig `yaml:"checkpoint_throttle,omitempty" json:"checkpoint_throttle,omitempty"`
+	// ImageRewrites are the image rewrite rules of restores by target cluster, so checkpoints restored in
+	// another region are pulled from a mirrored registry
+	ImageRewrites map[string][]ImageRewriteRule `yaml:"image_rewrites,omitempty" json:"image_rewrites,omitempty"`
+}
+
+// ImageRewriteRule replaces the registry or repository prefix of the images restored on a cluster
+type ImageRewriteRule struct {
+	SourcePrefix string `yaml:"source_prefix" json:"source_prefix"`
+	TargetPrefix string `yaml:"target_prefix" json:"target_prefix"`
+}
+
+// CheckpointThrottleConfig limits what checkpoints take from the nodes and the network, so backups do not
+// saturate production links. Unset fields leave the limit to the controller.
+type CheckpointThrottleConfig struct {
+	// UploadBandwidthLimit caps the upload rate of checkpoints per node, as bytes per second like 50Mi
+	UploadBandwidthLimit string `yaml:"upload_bandwidth_limit,omitempty" json:"upload_bandwidth_limit,omitempty"`
+	// CheckpointConcurrency is the number of containers checkpointed in parallel per node
+	CheckpointConcurrency int `yaml:"checkpoint_concurrency,omitempty" json:"checkpoint_concurrency,omitempty"`
+}
+
+// HarborConfig is the Harbor instance registry projects are provisioned in.
+type HarborConfig struct {
+	URL string `yaml:"url" json:"url"`
+	// CredentialsSecret names the Secret of the system namespace holding the username and password of a Harbor administrator
+	CredentialsSecret     string `yaml:"credentials_secret" json:"credentials_secret"`
+	InsecureSkipTLSVerify bool   `yaml:"insecure_skip_tls_verify,omitempty" json:"insecure_skip_tls_verify,omitempty"`
+}
+
+// ExternalSecretsConfig is the External Secrets Operator store credentials are read from, the store must be
+// available on the management cluster and, for backup credentials, on the member clusters.
+type ExternalSecretsConfig struct {
+	StoreName string `yaml:"store_name" json:"store_name"`
+	// StoreKind is SecretStore or ClusterSecretStore, it defaults to ClusterSecretStore
+	StoreKind string `yaml:"store_kind,omitempty" json:"store_kind,omitempty"`
+	// RefreshInterval is how often the operator reads the credentials again, it defaults to 1h
+	RefreshInterval string `yaml:"refresh_interval,omitempty" json:"refresh_interval,omitempty"`
 }
@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DefaultManifestBaseURL is where the migration controller manifests are fetched from unless the runtime config sets another location
+const DefaultManifestBaseURL = "https://raw.githubusercontent.com/lehuannhatrang/stateful-migration-operator/main"
+
+// ManifestBaseURL returns where the migration controller manifests are fetched from
+func ManifestBaseURL() string {
+	if base := GetDashboardConfig().Runtime.ManifestBaseURL; base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return DefaultManifestBaseURL
+}
+
+// ManifestURL returns the URL of a migration controller manifest from its path in the operator repository
+func ManifestURL(path string) string {
+	return ManifestBaseURL() + "/" + strings.TrimLeft(path, "/")
+}
+
+// DefaultTrashRetention is how long deleted backup and recovery records are kept unless the runtime config sets another window
+const DefaultTrashRetention = 7 * 24 * time.Hour
+
+// TrashRetention returns how long deleted backup and recovery records stay in the trash before they are purged
+func TrashRetention() time.Duration {
+	if d, err := time.ParseDuration(GetDashboardConfig().Runtime.TrashRetention); err == nil && d > 0 {
+		return d
+	}
+	return DefaultTrashRetention
+}
+
+// MaxCheckpointConcurrency bounds the containers a migration controller checkpoints in parallel per node
+const MaxCheckpointConcurrency = 32
+
+// CheckpointThrottle returns the limits of the migration controllers of every cluster, unset when the runtime config has none
+func CheckpointThrottle() CheckpointThrottleConfig {
+	if throttle := GetDashboardConfig().Runtime.CheckpointThrottle; throttle != nil {
+		return *throttle
+	}
+	return CheckpointThrottleConfig{}
+}
+
+// ImageRewrites returns the image rewrite rules of the restores on a cluster
+func ImageRewrites(cluster string) []ImageRewriteRule {
+	return GetDashboardConfig().Runtime.ImageRewrites[cluster]
+}
+
+// Validate checks the runtime settings
+func (c RuntimeConfig) Validate() error {
+	var errs []string
+	if c.Namespace != "" {
+		for _, msg := range validation.IsDNS1123Label(c.Namespace) {
+			errs = append(errs, fmt.Sprintf("namespace %q: %s", c.Namespace, msg))
+		}
+	}
+	if c.ManifestBaseURL != "" {
+		if err := validateHTTPURL(c.ManifestBaseURL); err != nil {
+			errs = append(errs, fmt.Sprintf("manifest_base_url: %v", err))
+		}
+	}
+	if c.Harbor != nil {
+		if err := validateHTTPURL(c.Harbor.URL); err != nil {
+			errs = append(errs, fmt.Sprintf("harbor url: %v", err))
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(c.Harbor.CredentialsSecret) {
+			errs = append(errs, fmt.Sprintf("harbor credentials_secret %q: %s", c.Harbor.CredentialsSecret, msg))
+		}
+	}
+	if c.ExternalSecrets != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(c.ExternalSecrets.StoreName) {
+			errs = append(errs, fmt.Sprintf("external_secrets store_name %q: %s", c.ExternalSecrets.StoreName, msg))
+		}
+		switch c.ExternalSecrets.StoreKind {
+		case "", "SecretStore", "ClusterSecretStore":
+		default:
+			errs = append(errs, fmt.Sprintf("external_secrets store_kind %q is not SecretStore or ClusterSecretStore", c.ExternalSecrets.StoreKind))
+		}
+		if c.ExternalSecrets.RefreshInterval != "" {
+			if d, err := time.ParseDuration(c.ExternalSecrets.RefreshInterval); err != nil || d < 0 {
+				errs = append(errs, fmt.Sprintf("external_secrets refresh_interval %q is not a duration", c.ExternalSecrets.RefreshInterval))
+			}
+		}
+	}
+	if c.TrashRetention != "" {
+		if d, err := time.ParseDuration(c.TrashRetention); err != nil || d <= 0 {
+			errs = append(errs, fmt.Sprintf("trash_retention %q is not a positive duration", c.TrashRetention))
+		}
+	}
+	if c.CheckpointThrottle != nil {
+		if limit := c.CheckpointThrottle.UploadBandwidthLimit; limit != "" {
+			if q, err := resource.ParseQuantity(limit); err != nil || q.Sign() <= 0 {
+				errs = append(errs, fmt.Sprintf("checkpoint_throttle upload_bandwidth_limit %q is not a positive quantity", limit))
+			}
+		}
+		if concurrency := c.CheckpointThrottle.CheckpointConcurrency; concurrency < 0 || concurrency > MaxCheckpointConcurrency {
+			errs = append(errs, fmt.Sprintf("checkpoint_throttle checkpoint_concurrency must be between 0 and %d", MaxCheckpointConcurrency))
+		}
+	}
+	for cluster, rules := range c.ImageRewrites {
+		sources := map[string]bool{}
+		for _, rule := range rules {
+			if rule.SourcePrefix == "" || rule.TargetPrefix == "" || sources[rule.SourcePrefix] {
+				errs = append(errs, fmt.Sprintf("image_rewrites of cluster %q: rule %q is incomplete or duplicated", cluster, rule.SourcePrefix))
+			}
+			sources[rule.SourcePrefix] = true
+		}
+	}
+	for name := range c.Features {
+		if _, ok := lookupFeature(name); !ok {
+			errs = append(errs, fmt.Sprintf("unknown feature %q", name))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid runtime config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate checks that the registries, menus and dashboards of the configuration are complete and not duplicated,
+// and that the URLs and runtime settings are valid
+func (c DashboardConfig) Validate() error {
+	var errs []string
+	registryNames := map[string]bool{}
+	for _, registry := range c.DockerRegistries {
+		if registry.Name == "" || registry.URL == "" || registryNames[registry.Name] {
+			errs = append(errs, fmt.Sprintf("docker registry %q is incomplete or duplicated", registry.Name))
+		}
+		registryNames[registry.Name] = true
+	}
+	registryNames = map[string]bool{}
+	for _, registry := range c.ChartRegistries {
+		if registry.Name == "" || registry.URL == "" || registryNames[registry.Name] {
+			errs = append(errs, fmt.Sprintf("chart registry %q is incomplete or duplicated", registry.Name))
+		}
+		registryNames[registry.Name] = true
+	}
+	errs = append(errs, validateMenuConfigs(c.MenuConfigs, "")...)
+	if c.PathPrefix != "" && !strings.HasPrefix(c.PathPrefix, "/") {
+		errs = append(errs, fmt.Sprintf("path_prefix %q must start with /", c.PathPrefix))
+	}
+	for _, dashboard := range c.MetricsDashboards {
+		if dashboard.Name == "" {
+			errs = append(errs, "metrics dashboard without a name")
+		}
+		if err := validateHTTPURL(dashboard.URL); err != nil {
+			errs = append(errs, fmt.Sprintf("metrics dashboard %q: %v", dashboard.Name, err))
+		}
+	}
+	if c.AIAgentChatWebHook != "" {
+		if err := validateHTTPURL(c.AIAgentChatWebHook); err != nil {
+			errs = append(errs, fmt.Sprintf("ai_agent_chat_webhook: %v", err))
+		}
+	}
+	if err := c.Runtime.Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid dashboard config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func validateMenuConfigs(menus []MenuConfig, parent string) []string {
+	var errs []string
+	for _, menu := range menus {
+		if menu.Path == "" {
+			errs = append(errs, fmt.Sprintf("menu %q under %q has no path", menu.SidebarKey, parent))
+		}
+		errs = append(errs, validateMenuConfigs(menu.Children, parent+menu.Path)...)
+	}
+	return errs
+}
+
+func validateHTTPURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// ChangedFields returns the YAML keys of the top-level sections that differ between two configurations,
+// and those of the runtime settings prefixed with "runtime."
+func ChangedFields(oldConfig, newConfig DashboardConfig) []string {
+	return changedFields(reflect.ValueOf(oldConfig), reflect.ValueOf(newConfig), "")
+}
+
+func changedFields(oldValue, newValue reflect.Value, prefix string) []string {
+	var changed []string
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		name := prefix + strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if field.Type.Kind() == reflect.Struct {
+			changed = append(changed, changedFields(oldValue.Field(i), newValue.Field(i), name+".")...)
+			continue
+		}
+		// nil and empty lists or maps are the same setting
+		kind := field.Type.Kind()
+		if (kind == reflect.Slice || kind == reflect.Map) && oldValue.Field(i).Len() == 0 && newValue.Field(i).Len() == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
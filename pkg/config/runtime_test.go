@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDashboardConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  DashboardConfig
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			config: DashboardConfig{
+				DockerRegistries:  []DockerRegistry{{Name: "hub", URL: "docker.io"}},
+				MenuConfigs:       []MenuConfig{{Path: "/overview", Children: []MenuConfig{{Path: "/clusters"}}}},
+				PathPrefix:        "/dashboard",
+				MetricsDashboards: []MetricsDashboard{{Name: "grafana", URL: "https://grafana.example.com"}},
+				Runtime:           RuntimeConfig{Namespace: "platform", ManifestBaseURL: "https://mirror.example.com/operator"},
+			},
+		},
+		{
+			name:   "empty",
+			config: DashboardConfig{},
+		},
+		{
+			name:    "duplicated registry",
+			config:  DashboardConfig{ChartRegistries: []ChartRegistry{{Name: "a", URL: "x"}, {Name: "a", URL: "y"}}},
+			wantErr: true,
+		},
+		{
+			name:    "menu without path",
+			config:  DashboardConfig{MenuConfigs: []MenuConfig{{Path: "/a", Children: []MenuConfig{{SidebarKey: "b"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid namespace",
+			config:  DashboardConfig{Runtime: RuntimeConfig{Namespace: "Platform_NS"}},
+			wantErr: true,
+		},
+		{
+			name:    "manifest base URL without scheme",
+			config:  DashboardConfig{Runtime: RuntimeConfig{ManifestBaseURL: "mirror.example.com"}},
+			wantErr: true,
+		},
+		{
+			name:   "harbor",
+			config: DashboardConfig{Runtime: RuntimeConfig{Harbor: &HarborConfig{URL: "https://harbor.example.com", CredentialsSecret: "harbor-admin"}}},
+		},
+		{
+			name:    "harbor without credentials",
+			config:  DashboardConfig{Runtime: RuntimeConfig{Harbor: &HarborConfig{URL: "https://harbor.example.com"}}},
+			wantErr: true,
+		},
+		{
+			name:   "external secrets",
+			config: DashboardConfig{Runtime: RuntimeConfig{ExternalSecrets: &ExternalSecretsConfig{StoreName: "vault", RefreshInterval: "15m"}}},
+		},
+		{
+			name:    "external secrets with unknown store kind",
+			config:  DashboardConfig{Runtime: RuntimeConfig{ExternalSecrets: &ExternalSecretsConfig{StoreName: "vault", StoreKind: "VaultStore"}}},
+			wantErr: true,
+		},
+		{
+			name:   "trash retention",
+			config: DashboardConfig{Runtime: RuntimeConfig{TrashRetention: "72h"}},
+		},
+		{
+			name:    "negative trash retention",
+			config:  DashboardConfig{Runtime: RuntimeConfig{TrashRetention: "-1h"}},
+			wantErr: true,
+		},
+		{
+			name:   "checkpoint throttle",
+			config: DashboardConfig{Runtime: RuntimeConfig{CheckpointThrottle: &CheckpointThrottleConfig{UploadBandwidthLimit: "50Mi", CheckpointConcurrency: 2}}},
+		},
+		{
+			name:    "invalid upload bandwidth limit",
+			config:  DashboardConfig{Runtime: RuntimeConfig{CheckpointThrottle: &CheckpointThrottleConfig{UploadBandwidthLimit: "fast"}}},
+			wantErr: true,
+		},
+		{
+			name:    "checkpoint concurrency too high",
+			config:  DashboardConfig{Runtime: RuntimeConfig{CheckpointThrottle: &CheckpointThrottleConfig{CheckpointConcurrency: MaxCheckpointConcurrency + 1}}},
+			wantErr: true,
+		},
+		{
+			name:   "image rewrites",
+			config: DashboardConfig{Runtime: RuntimeConfig{ImageRewrites: map[string][]ImageRewriteRule{"eu": {{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"}}}}},
+		},
+		{
+			name: "duplicated image rewrite",
+			config: DashboardConfig{Runtime: RuntimeConfig{ImageRewrites: map[string][]ImageRewriteRule{"eu": {
+				{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"},
+				{SourcePrefix: "harbor.us", TargetPrefix: "mirror.eu"},
+			}}}},
+			wantErr: true,
+		},
+		{
+			name:    "image rewrite without target",
+			config:  DashboardConfig{Runtime: RuntimeConfig{ImageRewrites: map[string][]ImageRewriteRule{"eu": {{SourcePrefix: "harbor.us"}}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	oldConfig := DashboardConfig{PathPrefix: "/", MenuConfigs: []MenuConfig{}}
+	newConfig := DashboardConfig{PathPrefix: "/dashboard", Runtime: RuntimeConfig{Namespace: "platform"}}
+
+	want := []string{"path_prefix", "runtime.namespace"}
+	if got := ChangedFields(oldConfig, newConfig); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFields() = %v, want %v", got, want)
+	}
+	if got := ChangedFields(newConfig, newConfig); len(got) != 0 {
+		t.Errorf("ChangedFields() of the same config = %v, want none", got)
+	}
+}
+
+func TestRuntimeOverrides(t *testing.T) {
+	t.Setenv("KARMADA_SYSTEM_NAMESPACE", "ml-platform-system")
+	defer setDashboardConfig(DashboardConfig{})
+
+	setDashboardConfig(DashboardConfig{})
+	if got := GetNamespace(); got != "ml-platform-system" {
+		t.Errorf("GetNamespace() = %q, want the one of the environment", got)
+	}
+	if got := ManifestURL("deploy/a.yaml"); got != DefaultManifestBaseURL+"/deploy/a.yaml" {
+		t.Errorf("ManifestURL() = %q, want one of the default location", got)
+	}
+
+	setDashboardConfig(DashboardConfig{Runtime: RuntimeConfig{Namespace: "platform", ManifestBaseURL: "https://mirror.example.com/"}})
+	if got := GetNamespace(); got != "platform" {
+		t.Errorf("GetNamespace() = %q, want the runtime namespace", got)
+	}
+	if got := configMapNamespace(); got != "ml-platform-system" {
+		t.Errorf("configMapNamespace() = %q, want the one of the environment", got)
+	}
+	if got := ManifestURL("/deploy/a.yaml"); got != "https://mirror.example.com/deploy/a.yaml" {
+		t.Errorf("ManifestURL() = %q, want one of the mirror", got)
+	}
+}
+
+func TestLoadConfigMapKeepsValidRuntime(t *testing.T) {
+	defer setDashboardConfig(DashboardConfig{})
+	setDashboardConfig(DashboardConfig{Runtime: RuntimeConfig{Namespace: "platform"}})
+
+	loadConfigMap(&v1.ConfigMap{Data: map[string]string{
+		GetConfigKey(): "path_prefix: /new\nruntime:\n  namespace: Not_Valid\n",
+	}})
+	got := GetDashboardConfig()
+	if got.PathPrefix != "/new" || got.Runtime.Namespace != "platform" {
+		t.Errorf("loadConfigMap() applied path prefix %q and namespace %q, want /new and the previous namespace",
+			got.PathPrefix, got.Runtime.Namespace)
+	}
+}
+
+func TestRecordConfigChange(t *testing.T) {
+	k8sClient := kubefake.NewSimpleClientset()
+	ctx := context.TODO()
+
+	history, err := GetConfigHistory(ctx, k8sClient)
+	if err != nil || len(history) != 0 {
+		t.Fatalf("GetConfigHistory() without ConfigMap = %v, %v, want an empty history", history, err)
+	}
+	for i := 0; i < maxConfigHistory+2; i++ {
+		change := ConfigChange{Time: fmt.Sprint(i), User: "admin", Fields: []string{"path_prefix"}}
+		if err := RecordConfigChange(ctx, k8sClient, change); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err = GetConfigHistory(ctx, k8sClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != maxConfigHistory || history[0].Time != "2" || history[len(history)-1].Time != fmt.Sprint(maxConfigHistory+1) {
+		t.Errorf("GetConfigHistory() returned %d changes from %s to %s, want the last %d",
+			len(history), history[0].Time, history[len(history)-1].Time, maxConfigHistory)
+	}
+	if _, err := k8sClient.CoreV1().ConfigMaps(configMapNamespace()).Get(ctx, historyConfigName, metav1.GetOptions{}); err != nil {
+		t.Errorf("history ConfigMap not found in the ConfigMap namespace: %v", err)
+	}
+}
+
+func TestFeatureEnabled(t *testing.T) {
+	defer setDashboardConfig(DashboardConfig{})
+
+	setDashboardConfig(DashboardConfig{})
+	if !FeatureEnabled(FeatureMigration) || !FeatureEnabled(FeatureCAPIProvisioning) {
+		t.Error("FeatureEnabled() = false for a feature enabled by default")
+	}
+	if FeatureEnabled("unknown") {
+		t.Error("FeatureEnabled() = true for an unknown feature")
+	}
+
+	setDashboardConfig(DashboardConfig{Runtime: RuntimeConfig{Features: map[string]bool{FeatureMigration: false}}})
+	if FeatureEnabled(FeatureMigration) || !FeatureEnabled(FeatureCAPIProvisioning) {
+		t.Error("FeatureEnabled() does not follow the runtime config")
+	}
+	for _, status := range FeatureStatuses() {
+		if status.Enabled != (status.Name != FeatureMigration) {
+			t.Errorf("FeatureStatuses() returned %s enabled %v", status.Name, status.Enabled)
+		}
+	}
+
+	if err := (RuntimeConfig{Features: map[string]bool{"unknown": true}}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown feature")
+	}
+}
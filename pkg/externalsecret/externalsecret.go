@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externalsecret fills the credentials of dashboard-managed secrets from an external store, such as
+// Vault, through ExternalSecrets of the External Secrets Operator. An ExternalSecret has the name of the
+// secret it fills and merges the credentials into it, so the readers of the secret do not have to know
+// where the credentials come from.
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// Resource is the resource of ExternalSecrets
+var Resource = schema.GroupVersionResource{Group: "external-secrets.io", Version: "v1beta1", Resource: "externalsecrets"}
+
+const (
+	// APIVersion and Kind of ExternalSecrets
+	APIVersion = "external-secrets.io/v1beta1"
+	Kind       = "ExternalSecret"
+
+	// ReferenceAnnotation is set on a secret whose credentials are filled by the ExternalSecret it names
+	ReferenceAnnotation = "ml-platform.io/external-secret"
+
+	defaultStoreKind       = "ClusterSecretStore"
+	defaultRefreshInterval = "1h"
+)
+
+var (
+	// ErrNotEnabled is returned when credentials reference an external store that is not set in the runtime config
+	ErrNotEnabled = errors.New("external secrets are not enabled for this installation")
+	// ErrNotSynced is returned when the External Secrets Operator has not filled a credential yet
+	ErrNotSynced = errors.New("credential has not been synced from the external store yet")
+)
+
+// RemoteRef is where a credential is read from in the external store
+type RemoteRef struct {
+	Key      string `json:"key"`
+	Property string `json:"property,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// Validate checks that the reference names a key
+func (r RemoteRef) Validate() error {
+	if r.Key == "" {
+		return fmt.Errorf("external secret key cannot be empty")
+	}
+	return nil
+}
+
+// Settings returns the store of the runtime config with its defaults
+func Settings() (config.ExternalSecretsConfig, error) {
+	settings := config.GetDashboardConfig().Runtime.ExternalSecrets
+	if settings == nil || settings.StoreName == "" {
+		return config.ExternalSecretsConfig{}, ErrNotEnabled
+	}
+	result := *settings
+	if result.StoreKind == "" {
+		result.StoreKind = defaultStoreKind
+	}
+	if result.RefreshInterval == "" {
+		result.RefreshInterval = defaultRefreshInterval
+	}
+	return result, nil
+}
+
+// New returns the ExternalSecret filling the keys of the secret of the same name from their references
+func New(name, namespace string, settings config.ExternalSecretsConfig, refs map[string]RemoteRef, labels map[string]string) *unstructured.Unstructured {
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	data := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		ref := refs[key]
+		remoteRef := map[string]interface{}{"key": ref.Key}
+		if ref.Property != "" {
+			remoteRef["property"] = ref.Property
+		}
+		if ref.Version != "" {
+			remoteRef["version"] = ref.Version
+		}
+		data = append(data, map[string]interface{}{
+			"secretKey": key,
+			"remoteRef": remoteRef,
+		})
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": APIVersion,
+		"kind":       Kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"refreshInterval": settings.RefreshInterval,
+			"secretStoreRef": map[string]interface{}{
+				"name": settings.StoreName,
+				"kind": settings.StoreKind,
+			},
+			"target": map[string]interface{}{
+				"name": name,
+				// The secret is created by the dashboard, the operator only adds the credentials to it
+				"creationPolicy": "Merge",
+			},
+			"data": data,
+		},
+	}}
+	if len(labels) > 0 {
+		obj.SetLabels(labels)
+	}
+	return obj
+}
+
+// Refs returns the references of an ExternalSecret by the key of the secret they fill
+func Refs(obj *unstructured.Unstructured) map[string]RemoteRef {
+	refs := map[string]RemoteRef{}
+	data, _, _ := unstructured.NestedSlice(obj.Object, "spec", "data")
+	for _, item := range data {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _, _ := unstructured.NestedString(entry, "secretKey")
+		var ref RemoteRef
+		ref.Key, _, _ = unstructured.NestedString(entry, "remoteRef", "key")
+		ref.Property, _, _ = unstructured.NestedString(entry, "remoteRef", "property")
+		ref.Version, _, _ = unstructured.NestedString(entry, "remoteRef", "version")
+		if key != "" {
+			refs[key] = ref
+		}
+	}
+	return refs
+}
+
+// Apply creates an ExternalSecret, or replaces the spec and labels of the existing one
+func Apply(ctx context.Context, dynamicClient dynamic.Interface, obj *unstructured.Unstructured) error {
+	resource := dynamicClient.Resource(Resource).Namespace(obj.GetNamespace())
+	_, err := resource.Create(ctx, obj, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	existing.Object["spec"] = obj.Object["spec"]
+	if labels := obj.GetLabels(); len(labels) > 0 {
+		existing.SetLabels(labels)
+	}
+	_, err = resource.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// Delete deletes an ExternalSecret, one that does not exist is not an error
+func Delete(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string) error {
+	err := dynamicClient.Resource(Resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Reference returns the ExternalSecret filling the credentials of a secret, it is empty when the
+// credentials are stored in the secret itself
+func Reference(secret *corev1.Secret) string {
+	return secret.Annotations[ReferenceAnnotation]
+}
+
+// Value returns a key of a secret. The error wraps ErrNotSynced when the key is filled by an
+// ExternalSecret that has not synced it yet.
+func Value(secret *corev1.Secret, key string) (string, error) {
+	if value, ok := secret.Data[key]; ok && len(value) > 0 {
+		return string(value), nil
+	}
+	if name := Reference(secret); name != "" {
+		return "", fmt.Errorf("%w: key %s of secret %s/%s is filled by ExternalSecret %s", ErrNotSynced, key, secret.Namespace, secret.Name, name)
+	}
+	return "", nil
+}
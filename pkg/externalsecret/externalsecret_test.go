@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	refs := map[string]RemoteRef{
+		"password":    {Key: "registry/harbor", Property: "password"},
+		"credentials": {Key: "cloud/aws", Version: "2"},
+	}
+	settings := config.ExternalSecretsConfig{StoreName: "vault", StoreKind: "ClusterSecretStore", RefreshInterval: "1h"}
+	obj := New("backup-registry-a", "stateful-migration", settings, refs, map[string]string{"app": "backup-registry"})
+
+	if obj.GetName() != "backup-registry-a" || obj.GetNamespace() != "stateful-migration" || obj.GetKind() != Kind {
+		t.Fatalf("unexpected object %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	if target, _, _ := unstructured.NestedString(obj.Object, "spec", "target", "name"); target != "backup-registry-a" {
+		t.Errorf("target = %q, want the name of the secret", target)
+	}
+	if policy, _, _ := unstructured.NestedString(obj.Object, "spec", "target", "creationPolicy"); policy != "Merge" {
+		t.Errorf("creationPolicy = %q, want Merge", policy)
+	}
+	if store, _, _ := unstructured.NestedString(obj.Object, "spec", "secretStoreRef", "name"); store != "vault" {
+		t.Errorf("store = %q, want vault", store)
+	}
+	data, _, _ := unstructured.NestedSlice(obj.Object, "spec", "data")
+	if first, _, _ := unstructured.NestedString(data[0].(map[string]interface{}), "secretKey"); first != "credentials" {
+		t.Errorf("data is not sorted by key, first key is %q", first)
+	}
+	if got := Refs(obj); !reflect.DeepEqual(got, refs) {
+		t.Errorf("Refs() = %v, want %v", got, refs)
+	}
+}
+
+func TestRemoteRefValidate(t *testing.T) {
+	if err := (RemoteRef{Property: "password"}).Validate(); err == nil {
+		t.Error("expected an error for a reference without a key")
+	}
+	if err := (RemoteRef{Key: "registry/harbor"}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	plain := &corev1.Secret{Data: map[string][]byte{"password": []byte("secret")}}
+	if value, err := Value(plain, "password"); err != nil || value != "secret" {
+		t.Errorf("Value() = %q, %v, want the stored value", value, err)
+	}
+	if value, err := Value(plain, "token"); err != nil || value != "" {
+		t.Errorf("Value() = %q, %v, want an empty value", value, err)
+	}
+
+	referenced := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "backup-registry-a",
+		Annotations: map[string]string{ReferenceAnnotation: "backup-registry-a"},
+	}}
+	if _, err := Value(referenced, "password"); !errors.Is(err, ErrNotSynced) {
+		t.Errorf("Value() error = %v, want ErrNotSynced", err)
+	}
+	referenced.Data = map[string][]byte{"password": []byte("synced")}
+	if value, err := Value(referenced, "password"); err != nil || value != "synced" {
+		t.Errorf("Value() = %q, %v, want the synced value", value, err)
+	}
+}
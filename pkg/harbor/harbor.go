@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package harbor provisions Harbor projects and the robot accounts pulling and pushing their images,
+// so users and projects get a registry of their own.
+package harbor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+// RobotName is the name of the robot account of a provisioned project, Harbor prefixes it with the project
+const RobotName = "dashboard"
+
+// Client calls the v2 API of a Harbor instance as an administrator
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// Robot is a robot account and its secret, which Harbor only returns when the robot is created
+type Robot struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// StatusError is an unexpected response of the Harbor API
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("harbor returned %d: %s", e.StatusCode, e.Message)
+}
+
+// NewClient returns a client of the Harbor instance at the URL
+func NewClient(baseURL, username, password string, insecureSkipTLSVerify bool) *Client {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if insecureSkipTLSVerify {
+		httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} // #nosec G402
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: httpClient,
+	}
+}
+
+// Host returns the registry host images of the instance are pushed to
+func (c *Client) Host() string {
+	if u, err := url.Parse(c.baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return c.baseURL
+}
+
+// do sends a request to the API and decodes the response into out unless it is nil
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/api/v2.0"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func isStatus(err error, statusCode int) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == statusCode
+}
+
+// EnsureProject creates a private project unless it exists and returns its ID
+func (c *Client) EnsureProject(ctx context.Context, name string) (int64, error) {
+	body := map[string]interface{}{
+		"project_name": name,
+		"metadata":     map[string]string{"public": "false"},
+	}
+	if err := c.do(ctx, http.MethodPost, "/projects", body, nil); err != nil && !isStatus(err, http.StatusConflict) {
+		return 0, fmt.Errorf("failed to create Harbor project %s: %w", name, err)
+	}
+	var project struct {
+		ProjectID int64 `json:"project_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(name), nil, &project); err != nil {
+		return 0, fmt.Errorf("failed to get Harbor project %s: %w", name, err)
+	}
+	return project.ProjectID, nil
+}
+
+// CreateRobot creates the robot account pulling and pushing the repositories of a project. A robot of the
+// same name is replaced, since its secret cannot be read back.
+func (c *Client) CreateRobot(ctx context.Context, project string, projectID int64) (*Robot, error) {
+	var existing []Robot
+	query := url.Values{"q": []string{fmt.Sprintf("Level=project,ProjectID=%d", projectID)}}
+	if err := c.do(ctx, http.MethodGet, "/robots?"+query.Encode(), nil, &existing); err != nil {
+		return nil, fmt.Errorf("failed to list the robots of Harbor project %s: %w", project, err)
+	}
+	for _, robot := range existing {
+		if strings.HasSuffix(robot.Name, project+"+"+RobotName) {
+			if err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/robots/%d", robot.ID), nil, nil); err != nil && !isStatus(err, http.StatusNotFound) {
+				return nil, fmt.Errorf("failed to replace robot %s: %w", robot.Name, err)
+			}
+		}
+	}
+
+	access := []map[string]string{
+		{"resource": "repository", "action": "pull"},
+		{"resource": "repository", "action": "push"},
+	}
+	body := map[string]interface{}{
+		"name":        RobotName,
+		"description": "Pulls and pushes images for the ML platform dashboard",
+		"level":       "project",
+		"duration":    -1,
+		"permissions": []map[string]interface{}{
+			{"kind": "project", "namespace": project, "access": access},
+		},
+	}
+	robot := &Robot{}
+	if err := c.do(ctx, http.MethodPost, "/robots", body, robot); err != nil {
+		return nil, fmt.Errorf("failed to create the robot of Harbor project %s: %w", project, err)
+	}
+	return robot, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harbor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeHarbor serves the projects and robots of the API, a project named "taken" already exists
+func fakeHarbor(t *testing.T, deleted *[]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["project_name"] == "taken" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/v2.0/projects/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"project_id": 7}`))
+	})
+	mux.HandleFunc("/api/v2.0/robots", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("q") != "Level=project,ProjectID=7" {
+				t.Errorf("robots listed with q = %q", r.URL.Query().Get("q"))
+			}
+			_, _ = w.Write([]byte(`[{"id": 3, "name": "robot$taken+dashboard"}, {"id": 4, "name": "robot$taken+ci"}]`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 5, "name": "robot$taken+dashboard", "secret": "s3cr3t"}`))
+		}
+	})
+	mux.HandleFunc("/api/v2.0/robots/", func(w http.ResponseWriter, r *http.Request) {
+		*deleted = append(*deleted, r.URL.Path)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestProvision(t *testing.T) {
+	var deleted []string
+	server := fakeHarbor(t, &deleted)
+	defer server.Close()
+	c := NewClient(server.URL+"/", "admin", "secret", false)
+
+	id, err := c.EnsureProject(context.TODO(), "taken")
+	if err != nil || id != 7 {
+		t.Fatalf("EnsureProject() of an existing project = %d, %v", id, err)
+	}
+	robot, err := c.CreateRobot(context.TODO(), "taken", id)
+	if err != nil {
+		t.Fatalf("CreateRobot() error = %v", err)
+	}
+	if robot.Name != "robot$taken+dashboard" || robot.Secret != "s3cr3t" {
+		t.Errorf("robot = %+v", robot)
+	}
+	if len(deleted) != 1 || deleted[0] != "/api/v2.0/robots/3" {
+		t.Errorf("deleted robots %v, want only the previous dashboard robot", deleted)
+	}
+
+	c = NewClient(server.URL, "admin", "wrong", false)
+	if _, err := c.CreateRobot(context.TODO(), "taken", id); !isStatus(err, http.StatusUnauthorized) {
+		t.Errorf("CreateRobot() with wrong credentials error = %v", err)
+	}
+}
+
+func TestDockerConfigJSON(t *testing.T) {
+	data, err := DockerConfigJSON("harbor.example.com", "robot$user-a+dashboard", "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	auth, _ := base64.StdEncoding.DecodeString(config.Auths["harbor.example.com"].Auth)
+	if string(auth) != "robot$user-a+dashboard:pw" {
+		t.Errorf("auth = %q", auth)
+	}
+}
+
+func TestProvisionParams(t *testing.T) {
+	if err := (ProvisionParams{OwnerKind: "team", Owner: "a"}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown owner kind")
+	}
+	if err := (ProvisionParams{OwnerKind: OwnerProject}).Validate(); err == nil {
+		t.Error("Validate() accepted an empty owner")
+	}
+	if got := ProjectName(OwnerUser, "Jane-example-com"); got != "user-jane-example-com" {
+		t.Errorf("ProjectName() = %q", got)
+	}
+	long := ProjectName(OwnerUser, "a-very-long-email-address-of-a-data-scientist-example-com")
+	if len(long) > maxProjectNameLength || long == ProjectName(OwnerUser, "a-very-long-email-address-of-a-data-scientist-example-org") {
+		t.Errorf("ProjectName() of a long owner = %q", long)
+	}
+	targets := []Target{{Namespace: "user-a"}, {Cluster: "member1", Namespace: "team"}}
+	if !Serves(targets, "member2", "user-a") || !Serves(targets, "member1", "team") || Serves(targets, "member2", "team") {
+		t.Errorf("Serves() does not match the targets %v", targets)
+	}
+	if got := (&Client{baseURL: "https://harbor.example.com:8443"}).Host(); got != "harbor.example.com:8443" {
+		t.Errorf("Host() = %q", got)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harbor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+	"github.com/karmada-io/dashboard/pkg/jobs"
+)
+
+// ProvisionJobType is the type of the jobs provisioning the registry project of a user or a project
+const ProvisionJobType = "registry-provision"
+
+// Kinds of the owners of a provisioned registry project
+const (
+	OwnerUser    = "user"
+	OwnerProject = "project"
+)
+
+// ErrNotConfigured is returned when no Harbor instance is set in the runtime config
+var ErrNotConfigured = errors.New("registry provisioning is not configured")
+
+// ProvisionParams are the params of a provisioning job. The owner of a user is the name of their Kubeflow
+// Profile, which is also the name of their namespace.
+type ProvisionParams struct {
+	OwnerKind string `json:"ownerKind"`
+	Owner     string `json:"owner"`
+}
+
+// Validate checks the kind and name of the owner
+func (p ProvisionParams) Validate() error {
+	if p.OwnerKind != OwnerUser && p.OwnerKind != OwnerProject {
+		return fmt.Errorf("owner kind must be %s or %s", OwnerUser, OwnerProject)
+	}
+	if p.Owner == "" {
+		return fmt.Errorf("owner cannot be empty")
+	}
+	return nil
+}
+
+// Target is a namespace the pull secret of a registry project is stored in, in every member cluster when
+// the cluster is empty
+type Target struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// Serves reports whether a registry project is provisioned for a namespace of a cluster
+func Serves(targets []Target, cluster, namespace string) bool {
+	for _, target := range targets {
+		if target.Namespace == namespace && (target.Cluster == "" || target.Cluster == cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxProjectNameLength keeps the names derived from a project, like the registry ID, within the length of a label value
+const maxProjectNameLength = 56
+
+// ProjectName returns the Harbor project of an owner. The kind is part of the name so that a user and a
+// project of the same name do not share a registry. Long names are shortened with a hash of the full name.
+func ProjectName(ownerKind, owner string) string {
+	name := strings.ToLower(ownerKind + "-" + owner)
+	if len(name) <= maxProjectNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	return strings.TrimRight(name[:maxProjectNameLength-9], "-") + "-" + hex.EncodeToString(sum[:4])
+}
+
+// Settings returns the Harbor instance of the runtime config
+func Settings() (config.HarborConfig, error) {
+	harbor := config.GetDashboardConfig().Runtime.Harbor
+	if harbor == nil || harbor.URL == "" {
+		return config.HarborConfig{}, ErrNotConfigured
+	}
+	return *harbor, nil
+}
+
+// EnqueueProvisioning queues the provisioning of the registry project of an owner
+func EnqueueProvisioning(ctx context.Context, params ProvisionParams, createdBy string) (*jobs.Job, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := Settings(); err != nil {
+		return nil, err
+	}
+	return jobs.Enqueue(ctx, ProvisionJobType, params, jobs.EnqueueOptions{
+		Keys:      []string{"registry/" + ProjectName(params.OwnerKind, params.Owner)},
+		CreatedBy: createdBy,
+	})
+}
+
+// DockerConfigJSON returns the content of a kubernetes.io/dockerconfigjson Secret for a registry
+func DockerConfigJSON(registry, username, password string) ([]byte, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{
+				"username": username,
+				"password": password,
+				"auth":     auth,
+			},
+		},
+	})
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jobs runs long operations, such as migrations and controller installs, outside of the request
+// that started them. Jobs are persisted in ConfigMaps and executed by a worker loop in every API replica,
+// so they survive restarts: a job whose worker stopped sending heartbeats is taken over and retried.
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Job phases
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+	PhaseCancelled = "Cancelled"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts of a job unless it is enqueued with another limit
+	DefaultMaxAttempts = 3
+	// LeaseDuration is how long a running job is owned by its worker without a heartbeat
+	LeaseDuration = 2 * time.Minute
+
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = 10 * time.Minute
+)
+
+// Job is a long operation and the status of its execution
+type Job struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Params are the arguments of the job, decoded by the handler of its type
+	Params json.RawMessage `json:"params"`
+	// State is saved by the handler while it runs, so another attempt can continue where the last one stopped
+	State json.RawMessage `json:"state,omitempty"`
+	// Keys are the resources the job works on, such as cluster names, used to find conflicting jobs
+	Keys        []string `json:"keys,omitempty"`
+	Phase       string   `json:"phase"`
+	Message     string   `json:"message,omitempty"`
+	Attempts    int      `json:"attempts"`
+	MaxAttempts int      `json:"maxAttempts"`
+	CreatedBy   string   `json:"createdBy,omitempty"`
+	CreatedAt   string   `json:"createdAt"`
+	StartedAt   string   `json:"startedAt,omitempty"`
+	CompletedAt string   `json:"completedAt,omitempty"`
+	// NextAttemptAt delays the retry of a failed attempt
+	NextAttemptAt string `json:"nextAttemptAt,omitempty"`
+	// Worker and HeartbeatAt identify the replica running the job
+	Worker          string `json:"worker,omitempty"`
+	HeartbeatAt     string `json:"heartbeatAt,omitempty"`
+	CancelRequested bool   `json:"cancelRequested,omitempty"`
+
+	resourceVersion string
+}
+
+// Done reports whether the job reached a final phase
+func (j *Job) Done() bool {
+	return j.Phase == PhaseSucceeded || j.Phase == PhaseFailed || j.Phase == PhaseCancelled
+}
+
+// HasKey reports whether the job works on the resource
+func (j *Job) HasKey(key string) bool {
+	for _, k := range j.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// claimable reports whether a worker may start the job: it is pending and due, or it is running
+// on a worker whose lease expired
+func claimable(j *Job, now time.Time) bool {
+	switch j.Phase {
+	case PhasePending:
+		return j.NextAttemptAt == "" || !parseTime(j.NextAttemptAt).After(now)
+	case PhaseRunning:
+		return now.Sub(parseTime(j.HeartbeatAt)) > LeaseDuration
+	}
+	return false
+}
+
+// claim moves the job to the running phase on the worker. A job taken over from a worker that
+// stopped has used up its attempt and fails when it has no attempts left.
+func claim(j *Job, worker string, now time.Time) {
+	if j.Phase == PhaseRunning && j.Attempts >= j.MaxAttempts {
+		complete(j, PhaseFailed, fmt.Sprintf("worker %s stopped during the last attempt", j.Worker), now)
+		return
+	}
+	j.Phase = PhaseRunning
+	j.Attempts++
+	j.Worker = worker
+	j.HeartbeatAt = now.Format(time.RFC3339)
+	j.NextAttemptAt = ""
+	if j.StartedAt == "" {
+		j.StartedAt = j.HeartbeatAt
+	}
+}
+
+func complete(j *Job, phase, message string, now time.Time) {
+	j.Phase = phase
+	j.Message = message
+	j.Worker = ""
+	j.HeartbeatAt = ""
+	j.NextAttemptAt = ""
+	j.CompletedAt = now.Format(time.RFC3339)
+}
+
+// retryDelay doubles the delay after each failed attempt
+func retryDelay(attempt int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// finish records the result of an attempt. Failed attempts are retried after a delay unless the
+// error is permanent or the job has no attempts left.
+func finish(j *Job, err error, cancelled bool, now time.Time) {
+	switch {
+	case cancelled:
+		complete(j, PhaseCancelled, "Cancelled", now)
+	case err == nil:
+		complete(j, PhaseSucceeded, j.Message, now)
+	case IsPermanent(err) || j.Attempts >= j.MaxAttempts:
+		complete(j, PhaseFailed, err.Error(), now)
+	default:
+		j.Phase = PhasePending
+		j.Message = fmt.Sprintf("attempt %d of %d failed: %v", j.Attempts, j.MaxAttempts, err)
+		j.Worker = ""
+		j.HeartbeatAt = ""
+		j.NextAttemptAt = now.Add(retryDelay(j.Attempts)).Format(time.RFC3339)
+	}
+}
+
+// permanentError is an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error so the job fails without being retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether the error was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClaimable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	format := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	tests := []struct {
+		name string
+		job  Job
+		want bool
+	}{
+		{name: "pending", job: Job{Phase: PhasePending}, want: true},
+		{name: "retry due", job: Job{Phase: PhasePending, NextAttemptAt: format(-time.Second)}, want: true},
+		{name: "retry delayed", job: Job{Phase: PhasePending, NextAttemptAt: format(time.Minute)}},
+		{name: "running", job: Job{Phase: PhaseRunning, HeartbeatAt: format(-time.Minute)}},
+		{name: "lease expired", job: Job{Phase: PhaseRunning, HeartbeatAt: format(-LeaseDuration - time.Second)}, want: true},
+		{name: "succeeded", job: Job{Phase: PhaseSucceeded}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claimable(&tt.job, now); got != tt.want {
+				t.Errorf("claimable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaim(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	job := &Job{Phase: PhasePending, MaxAttempts: 3}
+	claim(job, "api-0", now)
+	if job.Phase != PhaseRunning || job.Attempts != 1 || job.Worker != "api-0" || job.StartedAt == "" {
+		t.Errorf("claim() of a pending job = %+v", job)
+	}
+
+	// A job taken over from a stopped worker on its last attempt fails
+	job = &Job{Phase: PhaseRunning, Attempts: 3, MaxAttempts: 3, Worker: "api-1"}
+	claim(job, "api-0", now)
+	if job.Phase != PhaseFailed || job.Worker != "" {
+		t.Errorf("claim() of a job without attempts left = %+v", job)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	failure := errors.New("cluster unreachable")
+	tests := []struct {
+		name      string
+		attempts  int
+		err       error
+		cancelled bool
+		phase     string
+	}{
+		{name: "success", attempts: 1, phase: PhaseSucceeded},
+		{name: "retried", attempts: 1, err: failure, phase: PhasePending},
+		{name: "no attempts left", attempts: 3, err: failure, phase: PhaseFailed},
+		{name: "permanent", attempts: 1, err: fmt.Errorf("step: %w", Permanent(failure)), phase: PhaseFailed},
+		{name: "cancelled", attempts: 1, err: failure, cancelled: true, phase: PhaseCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{Phase: PhaseRunning, Attempts: tt.attempts, MaxAttempts: 3, Worker: "api-0"}
+			finish(job, tt.err, tt.cancelled, now)
+			if job.Phase != tt.phase || job.Worker != "" {
+				t.Fatalf("finish() = %+v, want phase %s", job, tt.phase)
+			}
+			if tt.phase == PhasePending {
+				if job.NextAttemptAt != now.Add(baseRetryDelay).Format(time.RFC3339) || job.CompletedAt != "" {
+					t.Errorf("retried job = %+v", job)
+				}
+			} else if job.CompletedAt == "" {
+				t.Errorf("finished job has no completion time")
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		10: maxRetryDelay,
+	}
+	for attempt, expected := range cases {
+		if actual := retryDelay(attempt); actual != expected {
+			t.Errorf("retryDelay(%d) == %v, expected %v", attempt, actual, expected)
+		}
+	}
+}
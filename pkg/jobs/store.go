@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+	"github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	// LabelKey marks the ConfigMaps that store jobs
+	LabelKey = "ml-platform.io/job"
+	// TypeLabelKey holds the type of the job stored in a ConfigMap
+	TypeLabelKey = "ml-platform.io/job-type"
+	dataKey      = "job"
+)
+
+// EnqueueOptions are the optional settings of a new job
+type EnqueueOptions struct {
+	// ID defaults to the type and the creation time
+	ID string
+	// Keys are the resources the job works on
+	Keys []string
+	// MaxAttempts defaults to DefaultMaxAttempts
+	MaxAttempts int
+	CreatedBy   string
+}
+
+func configMapName(id string) string {
+	return fmt.Sprintf("job-%s", id)
+}
+
+func configMapToJob(cm *corev1.ConfigMap) (*Job, error) {
+	job := &Job{}
+	if err := json.Unmarshal([]byte(cm.Data[dataKey]), job); err != nil {
+		return nil, fmt.Errorf("failed to decode job %s: %v", cm.Name, err)
+	}
+	job.resourceVersion = cm.ResourceVersion
+	return job, nil
+}
+
+func jobToConfigMap(job *Job) (*corev1.ConfigMap, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job %s: %v", job.ID, err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            configMapName(job.ID),
+			Namespace:       config.GetNamespace(),
+			ResourceVersion: job.resourceVersion,
+			Labels: map[string]string{
+				LabelKey:     "true",
+				TypeLabelKey: job.Type,
+			},
+		},
+		Data: map[string]string{dataKey: string(data)},
+	}, nil
+}
+
+// Enqueue stores a new pending job of a registered type, which a worker picks up on its next run
+func Enqueue(ctx context.Context, jobType string, params interface{}, opts EnqueueOptions) (*Job, error) {
+	if _, ok := handlerFor(jobType); !ok {
+		return nil, fmt.Errorf("unknown job type %q", jobType)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job params: %v", err)
+	}
+	now := time.Now()
+	job := &Job{
+		ID:          opts.ID,
+		Type:        jobType,
+		Params:      data,
+		Keys:        opts.Keys,
+		Phase:       PhasePending,
+		MaxAttempts: opts.MaxAttempts,
+		CreatedBy:   opts.CreatedBy,
+		CreatedAt:   now.Format(time.RFC3339),
+	}
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("%s-%d", jobType, now.UnixNano())
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+
+	cm, err := jobToConfigMap(job)
+	if err != nil {
+		return nil, err
+	}
+	created, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	job.resourceVersion = created.ResourceVersion
+	klog.InfoS("Enqueued job", "id", job.ID, "type", job.Type, "keys", job.Keys)
+	return job, nil
+}
+
+// List returns the jobs, newest first, optionally of a single type
+func List(ctx context.Context, jobType string) ([]*Job, error) {
+	selector := LabelKey + "=true"
+	if jobType != "" {
+		selector += "," + TypeLabelKey + "=" + jobType
+	}
+	list, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(list.Items))
+	for i := range list.Items {
+		job, err := configMapToJob(&list.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid job", "configMap", list.Items[i].Name)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].CreatedAt != jobs[j].CreatedAt {
+			return jobs[i].CreatedAt > jobs[j].CreatedAt
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// Get returns a job
+func Get(ctx context.Context, id string) (*Job, error) {
+	cm, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, configMapName(id), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.NewNotFound(fmt.Sprintf("Job %s not found", id))
+		}
+		return nil, err
+	}
+	return configMapToJob(cm)
+}
+
+// save writes the job if it was not changed since it was read, otherwise it returns a conflict error
+func save(ctx context.Context, job *Job) error {
+	cm, err := jobToConfigMap(job)
+	if err != nil {
+		return err
+	}
+	updated, err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	job.resourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// update reads the job, applies the change and writes it, retrying when the job changed in between
+func update(ctx context.Context, id string, change func(job *Job) error) (*Job, error) {
+	var job *Job
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		if job, err = Get(ctx, id); err != nil {
+			return err
+		}
+		if err := change(job); err != nil {
+			return err
+		}
+		return save(ctx, job)
+	})
+	return job, err
+}
+
+// Cancel asks the worker running the job to stop it, or cancels it right away when it is pending
+func Cancel(ctx context.Context, id string) (*Job, error) {
+	return update(ctx, id, func(job *Job) error {
+		if job.Done() {
+			return errors.NewBadRequest(fmt.Sprintf("job %s is already %s", id, strings.ToLower(job.Phase)))
+		}
+		if job.Phase == PhasePending {
+			complete(job, PhaseCancelled, "Cancelled before it started", time.Now())
+			return nil
+		}
+		job.CancelRequested = true
+		return nil
+	})
+}
+
+// Retry queues a failed or cancelled job again with all its attempts. The saved state is kept,
+// so the job continues after the steps that already completed.
+func Retry(ctx context.Context, id string) (*Job, error) {
+	return update(ctx, id, func(job *Job) error {
+		if job.Phase != PhaseFailed && job.Phase != PhaseCancelled {
+			return errors.NewBadRequest(fmt.Sprintf("only failed or cancelled jobs can be retried, job %s is %s", id, strings.ToLower(job.Phase)))
+		}
+		job.Phase = PhasePending
+		job.Attempts = 0
+		job.CancelRequested = false
+		job.CompletedAt = ""
+		job.Message = "Retry requested"
+		return nil
+	})
+}
+
+// Active returns the jobs that are not done and work on one of the keys
+func Active(ctx context.Context, jobType string, keys []string) ([]*Job, error) {
+	jobs, err := List(ctx, jobType)
+	if err != nil {
+		return nil, err
+	}
+	var active []*Job
+	for _, job := range jobs {
+		if job.Done() {
+			continue
+		}
+		for _, key := range keys {
+			if job.HasKey(key) {
+				active = append(active, job)
+				break
+			}
+		}
+	}
+	return active, nil
+}
+
+// deleteJob removes a stored job
+func deleteJob(ctx context.Context, id string) error {
+	err := client.InClusterClient().CoreV1().ConfigMaps(config.GetNamespace()).Delete(ctx, configMapName(id), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
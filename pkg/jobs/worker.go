@@ -0,0 +1,324 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/environment"
+)
+
+const (
+	// maxConcurrentJobs limits the jobs a replica runs at the same time
+	maxConcurrentJobs = 4
+	// completedJobTTL is how long finished jobs are kept
+	completedJobTTL   = 7 * 24 * time.Hour
+	heartbeatInterval = LeaseDuration / 4
+)
+
+// Handler runs an attempt of a job. It is called again for every attempt, including after a restart
+// of the replica that ran the previous one, so it must be idempotent. It can save its progress with
+// Run.SaveState and read it back to skip the steps that already completed.
+type Handler func(ctx context.Context, run *Run) error
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]Handler{}
+)
+
+// Register sets the handler of a job type. Route packages register their job types in init.
+func Register(jobType string, handler Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[jobType] = handler
+}
+
+func handlerFor(jobType string) (Handler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	handler, ok := handlers[jobType]
+	return handler, ok
+}
+
+// Run is the attempt of a job passed to its handler
+type Run struct {
+	mu              sync.Mutex
+	job             *Job
+	cancelRequested bool
+	lost            bool
+}
+
+// ID returns the ID of the job
+func (r *Run) ID() string {
+	return r.job.ID
+}
+
+// Attempt returns the number of the attempt, starting at 1
+func (r *Run) Attempt() int {
+	return r.job.Attempts
+}
+
+// DecodeParams decodes the params the job was enqueued with
+func (r *Run) DecodeParams(params interface{}) error {
+	if err := json.Unmarshal(r.job.Params, params); err != nil {
+		return Permanent(fmt.Errorf("invalid params of job %s: %v", r.job.ID, err))
+	}
+	return nil
+}
+
+// DecodeState decodes the state saved by a previous attempt. It reports false when nothing was saved.
+func (r *Run) DecodeState(state interface{}) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.job.State) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(r.job.State, state); err != nil {
+		return false, fmt.Errorf("invalid state of job %s: %v", r.job.ID, err)
+	}
+	return true, nil
+}
+
+// SaveState persists the progress of the job and a message describing it
+func (r *Run) SaveState(ctx context.Context, state interface{}, message string) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode job state: %v", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, err := update(ctx, r.job.ID, func(job *Job) error {
+		if job.Worker != r.job.Worker {
+			return errLeaseLost
+		}
+		job.State = data
+		job.Message = message
+		return nil
+	})
+	if errors.Is(err, errLeaseLost) {
+		r.lost = true
+	}
+	if err != nil {
+		return err
+	}
+	r.job = job
+	return nil
+}
+
+// Final reports whether the job ends with the error of this attempt: the error is permanent, the job
+// was cancelled or it has no attempts left. An attempt interrupted by a shutdown is resumed, so it is not final.
+func (r *Run) Final(ctx context.Context, err error) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case r.cancelRequested || IsPermanent(err):
+		return true
+	case r.lost || ctx.Err() != nil:
+		return false
+	}
+	return r.job.Attempts >= r.job.MaxAttempts
+}
+
+// heartbeat renews the lease of the worker on the job. It reports true when the job must stop
+// because it was cancelled or taken over by another worker.
+func (r *Run) heartbeat(ctx context.Context, worker string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, err := update(ctx, r.job.ID, func(current *Job) error {
+		if current.Worker != worker {
+			return errLeaseLost
+		}
+		current.HeartbeatAt = time.Now().Format(time.RFC3339)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errLeaseLost):
+		r.lost = true
+	case err != nil:
+		klog.ErrorS(err, "Failed to send job heartbeat", "id", r.job.ID)
+	default:
+		r.job = job
+		r.cancelRequested = job.CancelRequested
+	}
+	return r.lost || r.cancelRequested
+}
+
+func (r *Run) leaseLost() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lost
+}
+
+var errLeaseLost = errors.New("the job was taken over by another worker")
+
+// worker runs the jobs claimed by this replica
+type worker struct {
+	name string
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// StartWorker checks for runnable jobs every interval and runs them until ctx is done.
+// A non-positive interval disables the worker, jobs are then only enqueued.
+func StartWorker(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		klog.InfoS("Job worker is disabled")
+		return
+	}
+	w := &worker{name: environment.Identity(), running: map[string]bool{}}
+	klog.InfoS("Starting job worker", "worker", w.name, "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			w.runOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runOnce claims the runnable jobs up to the concurrency limit and removes expired finished jobs
+func (w *worker) runOnce(ctx context.Context) {
+	jobs, err := List(ctx, "")
+	if err != nil {
+		klog.ErrorS(err, "Failed to list jobs")
+		return
+	}
+	now := time.Now()
+	// Oldest jobs first
+	for i := len(jobs) - 1; i >= 0; i-- {
+		job := jobs[i]
+		if job.Done() {
+			if now.Sub(parseTime(job.CompletedAt)) > completedJobTTL {
+				if err := deleteJob(ctx, job.ID); err != nil {
+					klog.ErrorS(err, "Failed to delete expired job", "id", job.ID)
+				}
+			}
+			continue
+		}
+		if !claimable(job, now) || !w.reserve(job.ID) {
+			continue
+		}
+		handler, ok := handlerFor(job.Type)
+		if !ok {
+			w.release(job.ID)
+			klog.V(4).InfoS("No handler for job type, leaving it to another replica", "id", job.ID, "type", job.Type)
+			continue
+		}
+		claim(job, w.name, now)
+		if err := save(ctx, job); err != nil {
+			w.release(job.ID)
+			if !apierrors.IsConflict(err) {
+				klog.ErrorS(err, "Failed to claim job", "id", job.ID)
+			}
+			continue
+		}
+		if job.Done() {
+			w.release(job.ID)
+			klog.InfoS("Job failed after its worker stopped", "id", job.ID, "type", job.Type)
+			continue
+		}
+		go w.run(ctx, job, handler)
+	}
+}
+
+// reserve marks the job as running on this replica, unless it already is or the replica is busy
+func (w *worker) reserve(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running[id] || len(w.running) >= maxConcurrentJobs {
+		return false
+	}
+	w.running[id] = true
+	return true
+}
+
+func (w *worker) release(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.running, id)
+}
+
+// run executes an attempt of a claimed job while sending heartbeats, and records its result
+func (w *worker) run(ctx context.Context, job *Job, handler Handler) {
+	defer w.release(job.ID)
+	klog.InfoS("Running job", "id", job.ID, "type", job.Type, "attempt", job.Attempts)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	run := &Run{job: job}
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			if stop := run.heartbeat(ctx, w.name); stop {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err := handler(runCtx, run)
+	cancel()
+	<-heartbeatDone
+
+	if ctx.Err() != nil {
+		// The replica is shutting down, another worker resumes the job once the lease expires
+		klog.InfoS("Job interrupted by shutdown", "id", job.ID)
+		return
+	}
+	if run.leaseLost() {
+		klog.InfoS("Job was taken over by another worker", "id", job.ID)
+		return
+	}
+	if err != nil {
+		klog.ErrorS(err, "Job attempt failed", "id", job.ID, "type", job.Type, "attempt", job.Attempts)
+	}
+	finished, saveErr := update(ctx, job.ID, func(current *Job) error {
+		if current.Worker != w.name {
+			return errLeaseLost
+		}
+		// A handler that completed despite a cancel request still succeeded
+		finish(current, err, err != nil && current.CancelRequested, time.Now())
+		return nil
+	})
+	if saveErr != nil {
+		klog.ErrorS(saveErr, "Failed to record job result", "id", job.ID)
+		return
+	}
+	klog.InfoS("Job attempt finished", "id", job.ID, "type", job.Type, "phase", finished.Phase)
+}
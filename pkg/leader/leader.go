@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leader elects one API replica to run the background workers, such as the backup retention
+// worker and the notification watcher, so they are not run twice when the API is scaled out.
+// Every replica keeps serving HTTP requests.
+package leader
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/environment"
+)
+
+// Config configures the leader election
+type Config struct {
+	// Enabled runs the election, otherwise this replica is always the leader
+	Enabled bool
+	// Namespace and LeaseName locate the Lease used as lock
+	Namespace string
+	LeaseName string
+	// LeaseDuration, RenewDeadline and RetryPeriod have the meaning of the client-go leader election settings
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Status describes the leadership of this replica
+type Status struct {
+	Enabled  bool   `json:"enabled"`
+	Identity string `json:"identity"`
+	// Leader is the identity of the current leader, empty while it is unknown
+	Leader   string `json:"leader"`
+	IsLeader bool   `json:"isLeader"`
+}
+
+var (
+	mu       sync.RWMutex
+	status   = Status{Identity: environment.Identity()}
+	watchdog *leaderelection.HealthzAdaptor
+)
+
+// Start runs the leader election in the background and calls run with a context that is cancelled
+// when this replica loses the leadership. run must start its workers and return. A replica that loses
+// the leadership campaigns again, so run can be called several times. When the election is disabled,
+// run is called right away with ctx.
+func Start(ctx context.Context, cfg Config, kubeClient kubernetes.Interface, run func(ctx context.Context)) error {
+	if !cfg.Enabled {
+		klog.InfoS("Leader election is disabled, running the background workers in this replica")
+		setStatus(func(s *Status) {
+			s.Leader = s.Identity
+			s.IsLeader = true
+		})
+		run(ctx)
+		return nil
+	}
+
+	identity := environment.Identity()
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: cfg.Namespace,
+			Name:      cfg.LeaseName,
+		},
+		Client:     kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+	adaptor := leaderelection.NewLeaderHealthzAdaptor(cfg.LeaseDuration)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   cfg.LeaseDuration,
+		RenewDeadline:   cfg.RenewDeadline,
+		RetryPeriod:     cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		WatchDog:        adaptor,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				klog.InfoS("Became leader, starting the background workers", "identity", identity)
+				setStatus(func(s *Status) { s.IsLeader = true })
+				run(leaderCtx)
+			},
+			OnStoppedLeading: func() {
+				klog.InfoS("Stopped leading, the background workers stop", "identity", identity)
+				setStatus(func(s *Status) { s.IsLeader = false })
+			},
+			OnNewLeader: func(leader string) {
+				klog.InfoS("New leader elected", "leader", leader)
+				setStatus(func(s *Status) { s.Leader = leader })
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	status.Enabled = true
+	watchdog = adaptor
+	mu.Unlock()
+
+	klog.InfoS("Starting leader election", "identity", identity, "lease", cfg.Namespace+"/"+cfg.LeaseName)
+	go func() {
+		// Run returns when the leadership is lost, campaign again until ctx is done
+		for ctx.Err() == nil {
+			elector.Run(ctx)
+		}
+	}()
+	return nil
+}
+
+func setStatus(change func(s *Status)) {
+	mu.Lock()
+	defer mu.Unlock()
+	change(&status)
+}
+
+// GetStatus returns the leadership of this replica
+func GetStatus() Status {
+	mu.RLock()
+	defer mu.RUnlock()
+	return status
+}
+
+// IsLeader reports whether this replica runs the background workers
+func IsLeader() bool {
+	return GetStatus().IsLeader
+}
+
+// Check returns an error when this replica is the leader but failed to renew its lease for too long,
+// in which case it must be restarted. It returns nil when the election is disabled.
+func Check(req *http.Request) error {
+	mu.RLock()
+	adaptor := watchdog
+	mu.RUnlock()
+	if adaptor == nil {
+		return nil
+	}
+	return adaptor.Check(req)
+}
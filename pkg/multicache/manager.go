@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicache keeps watch based caches of resources across the Karmada member clusters.
+package multicache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// Manager maintains informers on a set of resources in every ready member cluster.
+// Clusters are added and removed as they join, leave or change readiness.
+type Manager struct {
+	gvrs   []schema.GroupVersionResource
+	resync time.Duration
+
+	mu       sync.RWMutex
+	clusters map[string]*clusterInformers
+	// started is set once the first cluster sync has completed
+	started bool
+}
+
+// clusterInformers are the informers of one member cluster, stopped together when the cluster goes away
+type clusterInformers struct {
+	client    dynamic.Interface
+	factory   dynamicinformer.DynamicSharedInformerFactory
+	informers map[schema.GroupVersionResource]cache.SharedIndexInformer
+	stop      chan struct{}
+}
+
+// NewManager returns a Manager for the given resources. resync is the informer resync period, 0 disables resyncs.
+func NewManager(gvrs []schema.GroupVersionResource, resync time.Duration) *Manager {
+	return &Manager{
+		gvrs:     gvrs,
+		resync:   resync,
+		clusters: make(map[string]*clusterInformers),
+	}
+}
+
+// Start syncs the watched clusters with the Karmada clusters every interval until ctx is done,
+// then stops all informers.
+func (m *Manager) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			m.syncClusters(ctx)
+			select {
+			case <-ctx.Done():
+				m.stopAll()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Started reports whether the manager has synced its cluster list at least once
+func (m *Manager) Started() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.started
+}
+
+func (m *Manager) syncClusters(ctx context.Context) {
+	clusters, err := client.InClusterKarmadaClient().ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Multi-cluster cache failed to list clusters")
+		return
+	}
+
+	ready := make(map[string]bool, len(clusters.Items))
+	for i := range clusters.Items {
+		if isClusterReady(&clusters.Items[i]) {
+			ready[clusters.Items[i].Name] = true
+		}
+	}
+
+	m.mu.Lock()
+	for name, informers := range m.clusters {
+		if !ready[name] {
+			close(informers.stop)
+			delete(m.clusters, name)
+			klog.InfoS("Stopped multi-cluster cache", "cluster", name)
+		}
+	}
+	watched := make(map[string]*clusterInformers, len(ready))
+	for name := range ready {
+		informers, ok := m.clusters[name]
+		if !ok {
+			dynamicClient, err := client.DynamicClientForMemberCluster(name)
+			if err != nil {
+				klog.ErrorS(err, "Multi-cluster cache failed to create member client", "cluster", name)
+				continue
+			}
+			informers = &clusterInformers{
+				client:    dynamicClient,
+				factory:   dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, m.resync),
+				informers: make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+				stop:      make(chan struct{}),
+			}
+			m.clusters[name] = informers
+			klog.InfoS("Started multi-cluster cache", "cluster", name)
+		}
+		watched[name] = informers
+	}
+	m.mu.Unlock()
+
+	// Probing the member clusters is done without the lock, so reads are not blocked by slow clusters
+	for name, informers := range watched {
+		m.ensureInformers(ctx, name, informers)
+	}
+
+	m.mu.Lock()
+	m.started = true
+	m.mu.Unlock()
+}
+
+// ensureInformers starts the informers of the resources served by the cluster.
+// Resources whose CRD is not installed yet are retried at the next sync.
+func (m *Manager) ensureInformers(ctx context.Context, clusterName string, informers *clusterInformers) {
+	for _, gvr := range m.gvrs {
+		m.mu.RLock()
+		_, ok := informers.informers[gvr]
+		m.mu.RUnlock()
+		if ok {
+			continue
+		}
+		if _, err := informers.client.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			klog.V(4).InfoS("Resource is not available in cluster", "cluster", clusterName, "resource", gvr.String(), "error", err)
+			continue
+		}
+		m.mu.Lock()
+		informers.informers[gvr] = informers.factory.ForResource(gvr).Informer()
+		m.mu.Unlock()
+	}
+	informers.factory.Start(informers.stop)
+}
+
+// Refresh stops the informers of a cluster, or of all clusters when clusterName is empty, and syncs
+// the clusters again so the informers are recreated and relist their resources.
+func (m *Manager) Refresh(ctx context.Context, clusterName string) {
+	m.mu.Lock()
+	for name, informers := range m.clusters {
+		if clusterName == "" || name == clusterName {
+			close(informers.stop)
+			delete(m.clusters, name)
+		}
+	}
+	m.mu.Unlock()
+	m.syncClusters(ctx)
+}
+
+func (m *Manager) stopAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, informers := range m.clusters {
+		close(informers.stop)
+		delete(m.clusters, name)
+	}
+}
+
+// Clusters returns the names of the watched clusters
+func (m *Manager) Clusters() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.clusters))
+	for name := range m.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns the cached objects of a resource in a cluster. The second result is false when the
+// cluster is not watched, the resource is not served by it, or the cache has not synced yet.
+func (m *Manager) List(clusterName string, gvr schema.GroupVersionResource) ([]*unstructured.Unstructured, bool) {
+	m.mu.RLock()
+	informers, ok := m.clusters[clusterName]
+	var informer cache.SharedIndexInformer
+	if ok {
+		informer = informers.informers[gvr]
+	}
+	m.mu.RUnlock()
+	if informer == nil || !informer.HasSynced() {
+		return nil, false
+	}
+
+	items := informer.GetStore().List()
+	objects := make([]*unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(*unstructured.Unstructured); ok {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, true
+}
+
+// Get returns a cached object of a resource in a cluster. The second result is false when the object is not
+// cached, including when the cluster is not watched or the cache has not synced yet.
+func (m *Manager) Get(clusterName string, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, bool) {
+	m.mu.RLock()
+	informers, ok := m.clusters[clusterName]
+	var informer cache.SharedIndexInformer
+	if ok {
+		informer = informers.informers[gvr]
+	}
+	m.mu.RUnlock()
+	if informer == nil || !informer.HasSynced() {
+		return nil, false
+	}
+
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	item, exists, err := informer.GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return nil, false
+	}
+	obj, ok := item.(*unstructured.Unstructured)
+	return obj, ok
+}
+
+func isClusterReady(cluster *clusterv1alpha1.Cluster) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == clusterv1alpha1.ClusterConditionReady {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
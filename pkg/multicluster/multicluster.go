@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicluster gathers data from several member clusters without letting one unreachable cluster
+// fail or silently empty the whole response.
+package multicluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+// workers bounds the clusters queried at the same time
+const workers = 8
+
+// ReasonNotReady is the reason of the clusters left out of a response because they are not ready
+const ReasonNotReady = "cluster is not ready"
+
+// ErrNoClient is returned for a cluster no client could be created for
+var ErrNoClient = errors.New("no client for the cluster, it may be unreachable or not accessible to the user")
+
+// ClusterError tells why the data of a cluster is missing from a multi-cluster response
+type ClusterError struct {
+	Cluster string `json:"cluster"`
+	Reason  string `json:"reason"`
+}
+
+// ReadyClusters returns the names of the ready clusters of a list, and errors for the others
+func ReadyClusters(clusters []cluster.Cluster) ([]string, []ClusterError) {
+	var names []string
+	var errs []ClusterError
+	for _, c := range clusters {
+		if c.Ready != metav1.ConditionTrue {
+			errs = append(errs, ClusterError{Cluster: c.ObjectMeta.Name, Reason: ReasonNotReady})
+			continue
+		}
+		names = append(names, c.ObjectMeta.Name)
+	}
+	return names, errs
+}
+
+// Gather calls fetch for each cluster concurrently and returns the results of the clusters that answered, in
+// the order of the clusters, with the errors of the ones that failed.
+func Gather[T any](ctx context.Context, clusters []string, fetch func(ctx context.Context, cluster string) (T, error)) ([]T, []ClusterError) {
+	type outcome struct {
+		value T
+		err   error
+	}
+	outcomes := make([]outcome, len(clusters))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, name := range clusters {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			value, err := fetch(ctx, name)
+			outcomes[i] = outcome{value: value, err: err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	var results []T
+	var errs []ClusterError
+	for i, o := range outcomes {
+		if o.err != nil {
+			klog.ErrorS(o.err, "Failed to get data of member cluster", "cluster", clusters[i])
+			errs = append(errs, ClusterError{Cluster: clusters[i], Reason: o.err.Error()})
+			continue
+		}
+		results = append(results, o.value)
+	}
+	return results, errs
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/karmada-io/dashboard/pkg/common/types"
+	"github.com/karmada-io/dashboard/pkg/resource/cluster"
+)
+
+func TestReadyClusters(t *testing.T) {
+	clusters := []cluster.Cluster{
+		{ObjectMeta: types.ObjectMeta{Name: "member1"}, Ready: metav1.ConditionTrue},
+		{ObjectMeta: types.ObjectMeta{Name: "member2"}, Ready: metav1.ConditionFalse},
+		{ObjectMeta: types.ObjectMeta{Name: "member3"}, Ready: metav1.ConditionTrue},
+		{ObjectMeta: types.ObjectMeta{Name: "member4"}, Ready: metav1.ConditionUnknown},
+	}
+	names, errs := ReadyClusters(clusters)
+	if want := []string{"member1", "member3"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadyClusters() names = %v, want %v", names, want)
+	}
+	wantErrs := []ClusterError{
+		{Cluster: "member2", Reason: ReasonNotReady},
+		{Cluster: "member4", Reason: ReasonNotReady},
+	}
+	if !reflect.DeepEqual(errs, wantErrs) {
+		t.Errorf("ReadyClusters() errors = %v, want %v", errs, wantErrs)
+	}
+}
+
+func TestGather(t *testing.T) {
+	var clusters []string
+	for i := 0; i < 3*workers; i++ {
+		clusters = append(clusters, fmt.Sprintf("member%02d", i))
+	}
+	results, errs := Gather(context.Background(), clusters, func(_ context.Context, name string) (string, error) {
+		if name == "member03" || name == "member10" {
+			return "", fmt.Errorf("%s is unreachable", name)
+		}
+		return name, nil
+	})
+
+	if len(results) != len(clusters)-2 {
+		t.Fatalf("Gather() returned %d results, want %d", len(results), len(clusters)-2)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1] >= results[i] {
+			t.Fatalf("Gather() results are not in the order of the clusters: %v", results)
+		}
+	}
+	wantErrs := []ClusterError{
+		{Cluster: "member03", Reason: "member03 is unreachable"},
+		{Cluster: "member10", Reason: "member10 is unreachable"},
+	}
+	if !reflect.DeepEqual(errs, wantErrs) {
+		t.Errorf("Gather() errors = %v, want %v", errs, wantErrs)
+	}
+}
+
+func TestGatherNoClusters(t *testing.T) {
+	results, errs := Gather(context.Background(), nil, func(context.Context, string) (int, error) {
+		t.Fatal("fetch called without clusters")
+		return 0, nil
+	})
+	if len(results) != 0 || len(errs) != 0 {
+		t.Errorf("Gather() = %v, %v, want nothing", results, errs)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package argocd reads and changes the ArgoCD projects, applications and application sets of a cluster.
+// The routes of the management cluster, of the member clusters and the aggregated routes share it.
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+)
+
+// Namespace is the namespace ArgoCD is installed in
+const Namespace = "argocd"
+
+// ClusterLabel is added to the returned resources with the name of their cluster
+const ClusterLabel = "cluster"
+
+const apiVersion = "argoproj.io/v1alpha1"
+
+// Resource is an ArgoCD resource type
+type Resource struct {
+	schema.GroupVersionResource
+	Kind string
+}
+
+// ArgoCD resource types
+var (
+	Project = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "appprojects"},
+		Kind:                 "AppProject",
+	}
+	Application = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+		Kind:                 "Application",
+	}
+	ApplicationSet = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applicationsets"},
+		Kind:                 "ApplicationSet",
+	}
+)
+
+// Service manages the ArgoCD resources of a cluster
+type Service struct {
+	client       dynamic.Interface
+	cluster      string
+	capabilities *capability.Capabilities
+}
+
+// NewService returns a service for the ArgoCD resources of the cluster the client connects to.
+// The cluster name labels the returned resources.
+func NewService(client dynamic.Interface, cluster string) *Service {
+	return &Service{client: client, cluster: cluster}
+}
+
+// WithCapabilities makes the service list the versions of the resources the cluster serves
+func (s *Service) WithCapabilities(capabilities *capability.Capabilities) *Service {
+	s.capabilities = capabilities
+	return s
+}
+
+// clean labels the resource with its cluster and removes its managed fields
+func (s *Service) clean(obj *unstructured.Unstructured) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ClusterLabel] = s.cluster
+	obj.SetLabels(labels)
+	obj.SetManagedFields(nil)
+}
+
+// List returns the resources of a type in the namespace, or in all namespaces when it is empty
+func (s *Service) List(ctx context.Context, resource Resource, namespace string) ([]unstructured.Unstructured, error) {
+	list, err := s.client.Resource(resource.GroupVersionResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		s.clean(&list.Items[i])
+	}
+	return list.Items, nil
+}
+
+// Get returns a resource of the ArgoCD namespace
+func (s *Service) Get(ctx context.Context, resource Resource, name string) (*unstructured.Unstructured, error) {
+	obj, err := s.client.Resource(resource.GroupVersionResource).Namespace(Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s.clean(obj)
+	return obj, nil
+}
+
+// GetProject returns a project and the applications that belong to it
+func (s *Service) GetProject(ctx context.Context, name string) (*unstructured.Unstructured, []unstructured.Unstructured, error) {
+	project, err := s.Get(ctx, Project, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	applications, err := s.List(ctx, Application, Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	projectApplications := make([]unstructured.Unstructured, 0)
+	for _, app := range applications {
+		if appProject, _, _ := unstructured.NestedString(app.Object, "spec", "project"); appProject == name {
+			projectApplications = append(projectApplications, app)
+		}
+	}
+	return project, projectApplications, nil
+}
+
+// Create creates a resource labelled with the cluster, in the ArgoCD namespace unless the object sets another one
+func (s *Service) Create(ctx context.Context, resource Resource, object map[string]interface{}) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{Object: object}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(resource.Kind)
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(Namespace)
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ClusterLabel] = s.cluster
+	obj.SetLabels(labels)
+	return s.client.Resource(resource.GroupVersionResource).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+}
+
+// Update replaces a resource of the ArgoCD namespace with the object, keeping the name of the resource
+func (s *Service) Update(ctx context.Context, resource Resource, name string, object map[string]interface{}) (*unstructured.Unstructured, error) {
+	resourceClient := s.client.Resource(resource.GroupVersionResource).Namespace(Namespace)
+	current, err := resourceClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: object}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(resource.Kind)
+	obj.SetNamespace(Namespace)
+	obj.SetName(name)
+	obj.SetResourceVersion(current.GetResourceVersion())
+	updated, err := resourceClient.Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	s.clean(updated)
+	return updated, nil
+}
+
+// Delete removes a resource of the ArgoCD namespace
+func (s *Service) Delete(ctx context.Context, resource Resource, name string) error {
+	return s.client.Resource(resource.GroupVersionResource).Namespace(Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// Sync starts a sync operation of an application
+func (s *Service) Sync(ctx context.Context, name string) error {
+	applications := s.client.Resource(Application.GroupVersionResource).Namespace(Namespace)
+	application, err := applications.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get application: %v", err)
+	}
+	operation := map[string]interface{}{
+		"sync": map[string]interface{}{},
+	}
+	if err := unstructured.SetNestedField(application.Object, operation, "operation"); err != nil {
+		return fmt.Errorf("failed to set sync operation: %v", err)
+	}
+	if _, err := applications.Update(ctx, application, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to sync application: %v", err)
+	}
+	return nil
+}
+
+// Refresh asks ArgoCD to compare an application with its source again
+func (s *Service) Refresh(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	applications := s.client.Resource(Application.GroupVersionResource).Namespace(Namespace)
+	application, err := applications.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	annotations := application.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["argocd.argoproj.io/refresh"] = time.Now().Format(time.RFC3339)
+	application.SetAnnotations(annotations)
+	return applications.Update(ctx, application, metav1.UpdateOptions{})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newObject(resource Resource, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range fields {
+		obj.Object[k] = v
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(resource.Kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func newFakeService(objects ...runtime.Object) *Service {
+	listKinds := map[schema.GroupVersionResource]string{
+		Project.GroupVersionResource:        "AppProjectList",
+		Application.GroupVersionResource:    "ApplicationList",
+		ApplicationSet.GroupVersionResource: "ApplicationSetList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+	return NewService(client, "member1")
+}
+
+func TestList(t *testing.T) {
+	project := newObject(Project, Namespace, "default", nil)
+	project.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "argocd"}})
+	other := newObject(Project, "team-a", "team-a", nil)
+	s := newFakeService(project, other)
+
+	all, err := s.List(context.TODO(), Project, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("List() in all namespaces returned %d projects, want 2", len(all))
+	}
+	for _, p := range all {
+		if p.GetLabels()[ClusterLabel] != "member1" || p.GetManagedFields() != nil {
+			t.Errorf("List() returned %s with labels %v and managed fields %v", p.GetName(), p.GetLabels(), p.GetManagedFields())
+		}
+	}
+
+	inNamespace, err := s.List(context.TODO(), Project, Namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inNamespace) != 1 || inNamespace[0].GetName() != "default" {
+		t.Errorf("List() in %s = %v", Namespace, inNamespace)
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	s := newFakeService(
+		newObject(Project, Namespace, "team-a", nil),
+		newObject(Application, Namespace, "web", map[string]interface{}{"spec": map[string]interface{}{"project": "team-a"}}),
+		newObject(Application, Namespace, "batch", map[string]interface{}{"spec": map[string]interface{}{"project": "team-b"}}),
+	)
+	project, applications, err := s.GetProject(context.TODO(), "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.GetName() != "team-a" {
+		t.Errorf("GetProject() project = %s", project.GetName())
+	}
+	if len(applications) != 1 || applications[0].GetName() != "web" {
+		t.Errorf("GetProject() applications = %v, want only web", applications)
+	}
+}
+
+func TestCreateAndUpdate(t *testing.T) {
+	s := newFakeService()
+	created, err := s.Create(context.TODO(), Application, map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web"},
+		"spec":     map[string]interface{}{"project": "default"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetNamespace() != Namespace || created.GetKind() != "Application" || created.GetLabels()[ClusterLabel] != "member1" {
+		t.Errorf("Create() = %v", created.Object)
+	}
+
+	updated, err := s.Update(context.TODO(), Application, "web", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "renamed"},
+		"spec":     map[string]interface{}{"project": "team-a"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project, _, _ := unstructured.NestedString(updated.Object, "spec", "project"); updated.GetName() != "web" || project != "team-a" {
+		t.Errorf("Update() = %v, want web in project team-a", updated.Object)
+	}
+}
+
+func TestSync(t *testing.T) {
+	s := newFakeService(newObject(Application, Namespace, "web", nil))
+	if err := s.Sync(context.TODO(), "web"); err != nil {
+		t.Fatal(err)
+	}
+	application, err := s.Get(context.TODO(), Application, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := unstructured.NestedMap(application.Object, "operation", "sync"); !found {
+		t.Errorf("Sync() did not set the sync operation: %v", application.Object)
+	}
+
+	if err := s.Sync(context.TODO(), "missing"); err == nil {
+		t.Errorf("Sync() of a missing application succeeded")
+	}
+}
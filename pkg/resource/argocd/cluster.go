@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclient "k8s.io/client-go/kubernetes"
+)
+
+// SecretTypeCluster is the SecretTypeLabel value of the secrets of ArgoCD destination clusters
+const SecretTypeCluster = "cluster"
+
+// MemberClusterLabel marks the cluster secrets registered for a Karmada member cluster with its name
+const MemberClusterLabel = "ml-platform.io/karmada-cluster"
+
+// The ServiceAccount ArgoCD uses in a destination cluster, named as by argocd cluster add
+const (
+	ManagerServiceAccount = "argocd-manager"
+	ManagerNamespace      = "kube-system"
+	managerBinding        = "argocd-manager-role-binding"
+	managerTokenSecret    = "argocd-manager-token"
+)
+
+// tokenTimeout bounds the wait for the token controller to fill the token of the manager
+const tokenTimeout = 30 * time.Second
+
+// DestinationCluster is a cluster ArgoCD deploys to, without its credentials
+type DestinationCluster struct {
+	SecretName    string `json:"secretName"`
+	Name          string `json:"name"`
+	Server        string `json:"server"`
+	MemberCluster string `json:"memberCluster,omitempty"`
+	Insecure      bool   `json:"insecure,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// ClusterCredentials are how ArgoCD connects to a destination cluster
+type ClusterCredentials struct {
+	Server      string
+	BearerToken string
+	CAData      []byte
+	Insecure    bool
+}
+
+// clusterConfig is the config key of a cluster secret
+type clusterConfig struct {
+	BearerToken     string          `json:"bearerToken"`
+	TLSClientConfig tlsClientConfig `json:"tlsClientConfig"`
+}
+
+type tlsClientConfig struct {
+	Insecure bool   `json:"insecure"`
+	CAData   string `json:"caData,omitempty"`
+}
+
+// ClusterSecretName returns the name of the cluster secret of a member cluster
+func ClusterSecretName(memberCluster string) string {
+	return "cluster-" + memberCluster
+}
+
+// ClusterSecret returns the secret registering a member cluster as an ArgoCD destination cluster of the same name
+func ClusterSecret(memberCluster string, credentials ClusterCredentials) (*unstructured.Unstructured, error) {
+	config := clusterConfig{
+		BearerToken:     credentials.BearerToken,
+		TLSClientConfig: tlsClientConfig{Insecure: credentials.Insecure},
+	}
+	if len(credentials.CAData) > 0 {
+		config.TLSClientConfig.CAData = base64.StdEncoding.EncodeToString(credentials.CAData)
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"data": map[string]interface{}{
+			"name":   base64.StdEncoding.EncodeToString([]byte(memberCluster)),
+			"server": base64.StdEncoding.EncodeToString([]byte(credentials.Server)),
+			"config": base64.StdEncoding.EncodeToString(configJSON),
+		},
+	}}
+	obj.SetName(ClusterSecretName(memberCluster))
+	obj.SetNamespace(Namespace)
+	obj.SetLabels(map[string]string{
+		SecretTypeLabel:    SecretTypeCluster,
+		MemberClusterLabel: memberCluster,
+	})
+	obj.SetAnnotations(map[string]string{"managed-by": "argocd.argoproj.io"})
+	return obj, nil
+}
+
+// destinationFromSecret returns the destination cluster of a cluster secret
+func destinationFromSecret(obj *unstructured.Unstructured) DestinationCluster {
+	destination := DestinationCluster{
+		SecretName:    obj.GetName(),
+		Name:          secretString(obj, "name"),
+		Server:        secretString(obj, "server"),
+		MemberCluster: obj.GetLabels()[MemberClusterLabel],
+		CreatedAt:     obj.GetCreationTimestamp().Format(time.RFC3339),
+	}
+	var config clusterConfig
+	if err := json.Unmarshal([]byte(secretString(obj, "config")), &config); err == nil {
+		destination.Insecure = config.TLSClientConfig.Insecure
+	}
+	return destination
+}
+
+// ListDestinationClusters returns the destination clusters registered in the ArgoCD namespace, sorted by name
+func (s *Service) ListDestinationClusters(ctx context.Context) ([]DestinationCluster, error) {
+	list, err := s.client.Resource(secretResource).Namespace(Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: SecretTypeLabel + "=" + SecretTypeCluster,
+	})
+	if err != nil {
+		return nil, err
+	}
+	destinations := make([]DestinationCluster, 0, len(list.Items))
+	for i := range list.Items {
+		destinations = append(destinations, destinationFromSecret(&list.Items[i]))
+	}
+	sort.Slice(destinations, func(i, j int) bool { return destinations[i].Name < destinations[j].Name })
+	return destinations, nil
+}
+
+// RegisterMemberCluster creates or replaces the cluster secret of a member cluster
+func (s *Service) RegisterMemberCluster(ctx context.Context, memberCluster string, credentials ClusterCredentials) (*DestinationCluster, error) {
+	obj, err := ClusterSecret(memberCluster, credentials)
+	if err != nil {
+		return nil, err
+	}
+	secrets := s.client.Resource(secretResource).Namespace(Namespace)
+	result, err := secrets.Create(ctx, obj, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		var existing *unstructured.Unstructured
+		existing, err = secrets.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		result, err = secrets.Update(ctx, obj, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	destination := destinationFromSecret(result)
+	return &destination, nil
+}
+
+// UnregisterMemberCluster deletes the cluster secret of a member cluster, a cluster secret not created for
+// the member cluster is not found
+func (s *Service) UnregisterMemberCluster(ctx context.Context, memberCluster string) error {
+	secrets := s.client.Resource(secretResource).Namespace(Namespace)
+	name := ClusterSecretName(memberCluster)
+	obj, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if obj.GetLabels()[MemberClusterLabel] != memberCluster {
+		return apierrors.NewNotFound(secretResource.GroupResource(), name)
+	}
+	return secrets.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// EnsureManagerToken creates the ServiceAccount ArgoCD uses in a member cluster, bound to cluster-admin, and
+// returns its long lived token with the CA of the cluster
+func EnsureManagerToken(ctx context.Context, memberClient kubeclient.Interface) (string, []byte, error) {
+	_, err := memberClient.CoreV1().ServiceAccounts(ManagerNamespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: ManagerServiceAccount, Namespace: ManagerNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", nil, fmt.Errorf("failed to create service account %s: %w", ManagerServiceAccount, err)
+	}
+
+	_, err = memberClient.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: managerBinding},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ManagerServiceAccount,
+			Namespace: ManagerNamespace,
+		}},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", nil, fmt.Errorf("failed to create cluster role binding %s: %w", managerBinding, err)
+	}
+
+	// ServiceAccounts no longer get a token secret by default, ArgoCD needs one that does not expire
+	_, err = memberClient.CoreV1().Secrets(ManagerNamespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        managerTokenSecret,
+			Namespace:   ManagerNamespace,
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: ManagerServiceAccount},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", nil, fmt.Errorf("failed to create token secret %s: %w", managerTokenSecret, err)
+	}
+
+	var token string
+	var caData []byte
+	err = wait.PollUntilContextTimeout(ctx, time.Second, tokenTimeout, true, func(ctx context.Context) (bool, error) {
+		secret, err := memberClient.CoreV1().Secrets(ManagerNamespace).Get(ctx, managerTokenSecret, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		token, caData = string(secret.Data[corev1.ServiceAccountTokenKey]), secret.Data[corev1.ServiceAccountRootCAKey]
+		return token != "", nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get the token of service account %s: %w", ManagerServiceAccount, err)
+	}
+	return token, caData, nil
+}
+
+// RevokeManagerToken deletes the ServiceAccount ArgoCD uses in a member cluster with its token and binding
+func RevokeManagerToken(ctx context.Context, memberClient kubeclient.Interface) error {
+	if err := memberClient.RbacV1().ClusterRoleBindings().Delete(ctx, managerBinding, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err := memberClient.CoreV1().Secrets(ManagerNamespace).Delete(ctx, managerTokenSecret, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err := memberClient.CoreV1().ServiceAccounts(ManagerNamespace).Delete(ctx, ManagerServiceAccount, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
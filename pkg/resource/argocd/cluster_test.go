@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClusterSecret(t *testing.T) {
+	obj, err := ClusterSecret("member1", ClusterCredentials{Server: "https://member1:6443", BearerToken: "token", CAData: []byte("ca")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetName() != "cluster-member1" || obj.GetNamespace() != Namespace || obj.GetLabels()[SecretTypeLabel] != SecretTypeCluster {
+		t.Errorf("ClusterSecret() metadata = %s/%s %v", obj.GetNamespace(), obj.GetName(), obj.GetLabels())
+	}
+	if secretString(obj, "name") != "member1" || secretString(obj, "server") != "https://member1:6443" {
+		t.Errorf("ClusterSecret() name = %q, server = %q", secretString(obj, "name"), secretString(obj, "server"))
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(secretString(obj, "config")), &config); err != nil {
+		t.Fatal(err)
+	}
+	tls := config["tlsClientConfig"].(map[string]interface{})
+	if config["bearerToken"] != "token" || tls["caData"] != "Y2E=" || tls["insecure"] != false {
+		t.Errorf("ClusterSecret() config = %v", config)
+	}
+}
+
+func TestRegisterMemberCluster(t *testing.T) {
+	ctx := context.TODO()
+	s := newRepositoryService()
+
+	if _, err := s.RegisterMemberCluster(ctx, "member1", ClusterCredentials{Server: "https://member1:6443", BearerToken: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	destination, err := s.RegisterMemberCluster(ctx, "member1", ClusterCredentials{Server: "https://member1:6443", BearerToken: "new", Insecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destination.MemberCluster != "member1" || !destination.Insecure {
+		t.Errorf("RegisterMemberCluster() again = %+v, want the replaced registration", destination)
+	}
+	destinations, err := s.ListDestinationClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destinations) != 1 || destinations[0].Server != "https://member1:6443" {
+		t.Errorf("ListDestinationClusters() = %+v", destinations)
+	}
+
+	if err := s.UnregisterMemberCluster(ctx, "member2"); !apierrors.IsNotFound(err) {
+		t.Errorf("UnregisterMemberCluster() of an unregistered cluster error = %v, want not found", err)
+	}
+	if err := s.UnregisterMemberCluster(ctx, "member1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnsureManagerToken(t *testing.T) {
+	memberClient := kubefake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: managerTokenSecret, Namespace: ManagerNamespace},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data: map[string][]byte{
+			corev1.ServiceAccountTokenKey:  []byte("token"),
+			corev1.ServiceAccountRootCAKey: []byte("ca"),
+		},
+	})
+	token, caData, err := EnsureManagerToken(context.TODO(), memberClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token" || string(caData) != "ca" {
+		t.Errorf("EnsureManagerToken() = %q, %q", token, caData)
+	}
+	if _, err := memberClient.RbacV1().ClusterRoleBindings().Get(context.TODO(), managerBinding, metav1.GetOptions{}); err != nil {
+		t.Errorf("the manager is not bound: %v", err)
+	}
+
+	if err := RevokeManagerToken(context.TODO(), memberClient); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := memberClient.CoreV1().ServiceAccounts(ManagerNamespace).Get(context.TODO(), ManagerServiceAccount, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("the manager was not revoked: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// connectionTimeout bounds a connection test
+const connectionTimeout = 10 * time.Second
+
+// ConnectionResult is the outcome of a connection test of a repository
+type ConnectionResult struct {
+	Successful bool   `json:"successful"`
+	Message    string `json:"message"`
+}
+
+// TestConnection connects to a repository with its credentials the way ArgoCD would, over SSH for SSH URLs
+// and HTTP otherwise. SSH host keys are checked against knownHosts unless the repository is insecure. The
+// test runs from the dashboard, so it does not cover network policies that only apply to ArgoCD.
+func TestConnection(ctx context.Context, request RepositoryRequest, knownHosts string) ConnectionResult {
+	if err := request.Validate(); err != nil {
+		return ConnectionResult{Message: err.Error()}
+	}
+	ctx, cancel := context.WithTimeout(ctx, connectionTimeout)
+	defer cancel()
+
+	var err error
+	if IsSSHURL(request.URL) {
+		err = testSSH(ctx, request, knownHosts)
+	} else {
+		err = testHTTP(ctx, request)
+	}
+	if err != nil {
+		return ConnectionResult{Message: err.Error()}
+	}
+	return ConnectionResult{Successful: true, Message: "successfully connected to " + request.URL}
+}
+
+// testHTTP requests the refs of a git repository, the index of a helm repository or the API of an OCI registry
+func testHTTP(ctx context.Context, request RepositoryRequest) error {
+	target := strings.TrimSuffix(request.URL, "/")
+	switch {
+	case request.EnableOCI:
+		host, err := repositoryHost("https://" + strings.TrimPrefix(target, "oci://"))
+		if err != nil {
+			return err
+		}
+		target = "https://" + host + "/v2/"
+	case request.repositoryType() == RepositoryTypeHelm:
+		target += "/index.yaml"
+	default:
+		target += "/info/refs?service=git-upload-pack"
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: request.Insecure} //nolint:gosec // insecure repositories are explicitly configured
+	if request.TLSClientCertData != "" {
+		certificate, err := tls.X509KeyPair([]byte(request.TLSClientCertData), []byte(request.TLSClientCertKey))
+		if err != nil {
+			return fmt.Errorf("invalid tls client certificate: %v", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+	httpClient := &http.Client{Transport: transport, Timeout: connectionTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid repository url: %v", err)
+	}
+	if request.Username != "" || request.Password != "" {
+		req.SetBasicAuth(request.Username, request.Password)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %v", redactURL(target), err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case request.EnableOCI && resp.StatusCode == http.StatusUnauthorized:
+		// Registries answer the API root with a token challenge, the credentials are checked when pulling
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("authentication to %s failed with status %d", redactURL(target), resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("repository not found at %s", redactURL(target))
+	default:
+		return fmt.Errorf("%s answered with status %d", redactURL(target), resp.StatusCode)
+	}
+}
+
+// redactURL removes the user information of a URL so that it can be returned
+func redactURL(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.User == nil {
+		return target
+	}
+	parsed.User = nil
+	return parsed.String()
+}
+
+// testSSH authenticates to the SSH server of a repository with its private key
+func testSSH(ctx context.Context, request RepositoryRequest, knownHosts string) error {
+	if request.SSHPrivateKey == "" {
+		return fmt.Errorf("an ssh private key is required for ssh repositories")
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(request.SSHPrivateKey))
+	if err != nil {
+		return fmt.Errorf("invalid ssh private key: %v", err)
+	}
+	address, err := repositoryHost(request.URL)
+	if err != nil {
+		return err
+	}
+	hostKeyCallback := ssh.InsecureIgnoreHostKey() //nolint:gosec // insecure repositories are explicitly configured
+	if !request.Insecure {
+		hostKeyCallback = knownHostsCallback(knownHosts)
+	}
+	config := &ssh.ClientConfig{
+		User:            sshUser(request.URL),
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         connectionTimeout,
+	}
+
+	dialer := &net.Dialer{Timeout: connectionTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %v", address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("ssh authentication to %s failed: %v", address, err)
+	}
+	ssh.NewClient(sshConn, channels, requests).Close()
+	return nil
+}
+
+// sshUser returns the user of an SSH repository URL, git when it has none
+func sshUser(repoURL string) string {
+	if strings.HasPrefix(repoURL, "ssh://") {
+		if parsed, err := url.Parse(repoURL); err == nil && parsed.User != nil {
+			return parsed.User.Username()
+		}
+		return "git"
+	}
+	if at := strings.Index(repoURL, "@"); at > 0 {
+		return repoURL[:at]
+	}
+	return "git"
+}
+
+// knownHostsCallback accepts the host keys listed in known_hosts content. Hashed host names are not supported.
+func knownHostsCallback(knownHosts string) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		address := knownhosts.Normalize(hostname)
+		rest := []byte(knownHosts)
+		for len(rest) > 0 {
+			_, hosts, pubKey, _, next, err := ssh.ParseKnownHosts(rest)
+			if err != nil {
+				break
+			}
+			rest = next
+			if !bytes.Equal(pubKey.Marshal(), key.Marshal()) {
+				continue
+			}
+			for _, host := range hosts {
+				if knownhosts.Normalize(host) == address {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("the host key of %s is not in %s", hostname, knownHostsConfigMap)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+)
+
+// Health of the ArgoCD instance of a cluster
+const (
+	HealthHealthy      = "Healthy"
+	HealthDegraded     = "Degraded"
+	HealthUnknown      = "Unknown"
+	HealthNotInstalled = "NotInstalled"
+)
+
+// ErrNotInstalled is returned for a cluster that does not serve the ArgoCD resources
+var ErrNotInstalled = errors.New("argocd is not installed")
+
+// serverComponent is the workload whose image gives the version of an instance
+const serverComponent = "argocd-server"
+
+// componentSelector selects the workloads of an ArgoCD installation
+const componentSelector = "app.kubernetes.io/part-of=argocd"
+
+var (
+	deploymentResource  = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	statefulSetResource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+)
+
+// Component is a workload of an ArgoCD instance with its replicas
+type Component struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Ready   int64  `json:"ready"`
+	Desired int64  `json:"desired"`
+}
+
+// Instance is the ArgoCD installation of a cluster
+type Instance struct {
+	Cluster    string      `json:"cluster"`
+	Installed  bool        `json:"installed"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Version    string      `json:"version,omitempty"`
+	Health     string      `json:"health"`
+	Components []Component `json:"components,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	DetectedAt string      `json:"detectedAt"`
+}
+
+// Installed reports whether a member cluster runs ArgoCD. A cluster whose resources cannot be discovered
+// is assumed to run it, so that requests fail on the cluster itself rather than on the detection.
+func Installed(ctx context.Context, clusterName string) bool {
+	_, err := capability.ResourceFor(ctx, clusterName, capability.KindApplication)
+	return err == nil
+}
+
+// Inspect returns the version and health of the ArgoCD instance of the cluster from its workloads
+func (s *Service) Inspect(ctx context.Context, installed bool, now time.Time) Instance {
+	instance := Instance{
+		Cluster:    s.cluster,
+		Installed:  installed,
+		Health:     HealthNotInstalled,
+		DetectedAt: now.Format(time.RFC3339),
+	}
+	if !installed {
+		return instance
+	}
+	instance.Namespace = Namespace
+
+	var workloads []unstructured.Unstructured
+	for _, resource := range []schema.GroupVersionResource{deploymentResource, statefulSetResource} {
+		list, err := s.client.Resource(resource).Namespace(Namespace).List(ctx, metav1.ListOptions{LabelSelector: componentSelector})
+		if err != nil {
+			instance.Health = HealthUnknown
+			instance.Message = fmt.Sprintf("failed to list %s: %v", resource.Resource, err)
+			return instance
+		}
+		workloads = append(workloads, list.Items...)
+	}
+	instance.Components, instance.Version = components(workloads)
+	instance.Health = health(instance.Components)
+	if len(instance.Components) == 0 {
+		instance.Message = fmt.Sprintf("no workloads labeled %s in namespace %s", componentSelector, Namespace)
+	}
+	return instance
+}
+
+// components returns the components of an instance sorted by name, and the version of its server
+func components(workloads []unstructured.Unstructured) ([]Component, string) {
+	result := make([]Component, 0, len(workloads))
+	version := ""
+	for _, workload := range workloads {
+		desired, found, _ := unstructured.NestedInt64(workload.Object, "spec", "replicas")
+		if !found {
+			desired = 1
+		}
+		ready, _, _ := unstructured.NestedInt64(workload.Object, "status", "readyReplicas")
+		result = append(result, Component{Name: workload.GetName(), Kind: workload.GetKind(), Ready: ready, Desired: desired})
+
+		if workload.GetName() == serverComponent {
+			version = workload.GetLabels()["app.kubernetes.io/version"]
+			containers, _, _ := unstructured.NestedSlice(workload.Object, "spec", "template", "spec", "containers")
+			if len(containers) > 0 {
+				container, _ := containers[0].(map[string]interface{})
+				if image, _, _ := unstructured.NestedString(container, "image"); imageTag(image) != "" {
+					version = imageTag(image)
+				}
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, version
+}
+
+// imageTag returns the tag of an image reference, empty when it has none or is pinned by digest only
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	index := strings.LastIndex(image, ":")
+	if index < 0 || strings.Contains(image[index:], "/") {
+		return ""
+	}
+	return image[index+1:]
+}
+
+// health is Healthy when every component has its desired replicas ready
+func health(components []Component) string {
+	if len(components) == 0 {
+		return HealthUnknown
+	}
+	for _, component := range components {
+		if component.Ready < component.Desired {
+			return HealthDegraded
+		}
+	}
+	return HealthHealthy
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newWorkload(kind, name, image string, replicas, ready int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": name, "image": image}},
+				},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": ready},
+	}}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace(Namespace)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{"app.kubernetes.io/part-of": "argocd"})
+	return obj
+}
+
+func newInstanceService(objects ...runtime.Object) *Service {
+	listKinds := map[schema.GroupVersionResource]string{
+		deploymentResource:  "DeploymentList",
+		statefulSetResource: "StatefulSetList",
+	}
+	return NewService(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...), "member1")
+}
+
+func TestInspect(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	notInstalled := newInstanceService().Inspect(context.TODO(), false, now)
+	if notInstalled.Installed || notInstalled.Health != HealthNotInstalled {
+		t.Errorf("Inspect() of a cluster without ArgoCD = %+v", notInstalled)
+	}
+
+	s := newInstanceService(
+		newWorkload("Deployment", "argocd-server", "quay.io/argoproj/argocd:v2.10.4", 1, 1),
+		newWorkload("Deployment", "argocd-repo-server", "quay.io/argoproj/argocd:v2.10.4", 2, 2),
+		newWorkload("StatefulSet", "argocd-application-controller", "quay.io/argoproj/argocd:v2.10.4", 1, 1),
+	)
+	instance := s.Inspect(context.TODO(), true, now)
+	if instance.Health != HealthHealthy || instance.Version != "v2.10.4" || len(instance.Components) != 3 {
+		t.Errorf("Inspect() = %+v, want a healthy v2.10.4 instance with 3 components", instance)
+	}
+	if instance.Components[0].Name != "argocd-application-controller" {
+		t.Errorf("components are not sorted by name: %+v", instance.Components)
+	}
+
+	degraded := newInstanceService(newWorkload("Deployment", "argocd-server", "argocd:v2.9.0", 2, 1)).Inspect(context.TODO(), true, now)
+	if degraded.Health != HealthDegraded {
+		t.Errorf("Inspect() health = %s, want %s", degraded.Health, HealthDegraded)
+	}
+
+	empty := newInstanceService().Inspect(context.TODO(), true, now)
+	if empty.Health != HealthUnknown || empty.Message == "" {
+		t.Errorf("Inspect() of an instance without workloads = %+v", empty)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"quay.io/argoproj/argocd:v2.10.4":   "v2.10.4",
+		"registry:5000/argoproj/argocd":     "",
+		"registry:5000/argocd:v2.9.0":       "v2.9.0",
+		"argocd:v2.8.1@sha256:0123456789ab": "v2.8.1",
+		"argocd@sha256:0123456789ab":        "",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
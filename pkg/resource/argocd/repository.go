@@ -0,0 +1,362 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SecretTypeLabel marks the secrets ArgoCD reads repositories and repository credentials from
+const SecretTypeLabel = "argocd.argoproj.io/secret-type"
+
+// Types of the secrets of SecretTypeLabel: a repository, or credentials used by every repository whose
+// URL starts with theirs
+const (
+	SecretTypeRepository = "repository"
+	SecretTypeRepoCreds  = "repo-creds"
+)
+
+// Types of repositories
+const (
+	RepositoryTypeGit  = "git"
+	RepositoryTypeHelm = "helm"
+)
+
+var secretResource = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// Repository is a repository or repository credentials secret, without its credentials
+type Repository struct {
+	Name             string `json:"name"`
+	SecretType       string `json:"secretType"`
+	Type             string `json:"type"`
+	URL              string `json:"url"`
+	DisplayName      string `json:"displayName,omitempty"`
+	Project          string `json:"project,omitempty"`
+	Username         string `json:"username,omitempty"`
+	HasPassword      bool   `json:"hasPassword"`
+	HasSSHPrivateKey bool   `json:"hasSshPrivateKey"`
+	HasTLSClientCert bool   `json:"hasTlsClientCert"`
+	Insecure         bool   `json:"insecure,omitempty"`
+	EnableOCI        bool   `json:"enableOCI,omitempty"`
+	Cluster          string `json:"cluster"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+// RepositoryRequest is a repository or repository credentials with their credentials
+type RepositoryRequest struct {
+	// Name of the secret, derived from the URL when it is empty
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	URL               string `json:"url"`
+	DisplayName       string `json:"displayName"`
+	Project           string `json:"project"`
+	Username          string `json:"username"`
+	Password          string `json:"password"`
+	SSHPrivateKey     string `json:"sshPrivateKey"`
+	TLSClientCertData string `json:"tlsClientCertData"`
+	TLSClientCertKey  string `json:"tlsClientCertKey"`
+	Insecure          bool   `json:"insecure"`
+	EnableOCI         bool   `json:"enableOCI"`
+}
+
+// Validate checks the type, URL and credentials of a repository
+func (r RepositoryRequest) Validate() error {
+	switch r.Type {
+	case "", RepositoryTypeGit, RepositoryTypeHelm:
+	default:
+		return fmt.Errorf("repository type must be %s or %s", RepositoryTypeGit, RepositoryTypeHelm)
+	}
+	if r.URL == "" {
+		return fmt.Errorf("repository url cannot be empty")
+	}
+	if r.SSHPrivateKey != "" && !IsSSHURL(r.URL) {
+		return fmt.Errorf("an ssh private key can only be used with an ssh url")
+	}
+	if (r.TLSClientCertData == "") != (r.TLSClientCertKey == "") {
+		return fmt.Errorf("tlsClientCertData and tlsClientCertKey must be set together")
+	}
+	if r.EnableOCI && r.repositoryType() != RepositoryTypeHelm {
+		return fmt.Errorf("oci can only be enabled for helm repositories")
+	}
+	return nil
+}
+
+func (r RepositoryRequest) repositoryType() string {
+	if r.Type == "" {
+		return RepositoryTypeGit
+	}
+	return r.Type
+}
+
+// data returns the keys of the secret ArgoCD reads, the empty ones are left out
+func (r RepositoryRequest) data() map[string]string {
+	data := map[string]string{
+		"type":              r.repositoryType(),
+		"url":               r.URL,
+		"name":              r.DisplayName,
+		"project":           r.Project,
+		"username":          r.Username,
+		"password":          r.Password,
+		"sshPrivateKey":     r.SSHPrivateKey,
+		"tlsClientCertData": r.TLSClientCertData,
+		"tlsClientCertKey":  r.TLSClientCertKey,
+	}
+	if r.Insecure {
+		data["insecure"] = "true"
+	}
+	if r.EnableOCI {
+		data["enableOCI"] = "true"
+	}
+	for key, value := range data {
+		if value == "" {
+			delete(data, key)
+		}
+	}
+	return data
+}
+
+// IsSSHURL reports whether a repository URL is reached over SSH, as ssh://host/path or user@host:path
+func IsSSHURL(repoURL string) bool {
+	if strings.HasPrefix(repoURL, "ssh://") {
+		return true
+	}
+	if strings.Contains(repoURL, "://") {
+		return false
+	}
+	at, colon := strings.Index(repoURL, "@"), strings.Index(repoURL, ":")
+	return at > 0 && colon > at
+}
+
+// RepositorySecretName returns the name of the secret of a repository URL, following the repo-<hash>
+// naming ArgoCD uses for the repositories it creates. Credentials get a creds-<hash> name.
+func RepositorySecretName(secretType, repoURL string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.TrimSuffix(strings.ToLower(repoURL), ".git")))
+	prefix := "repo"
+	if secretType == SecretTypeRepoCreds {
+		prefix = "creds"
+	}
+	return fmt.Sprintf("%s-%v", prefix, h.Sum32())
+}
+
+// secretString returns a key of an unstructured secret
+func secretString(obj *unstructured.Unstructured, key string) string {
+	encoded, _, _ := unstructured.NestedString(obj.Object, "data", key)
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ""
+	}
+	return string(decoded)
+}
+
+// repositoryFromSecret returns the repository of a secret without its credentials
+func (s *Service) repositoryFromSecret(obj *unstructured.Unstructured) Repository {
+	repository := Repository{
+		Name:             obj.GetName(),
+		SecretType:       obj.GetLabels()[SecretTypeLabel],
+		Type:             secretString(obj, "type"),
+		URL:              secretString(obj, "url"),
+		DisplayName:      secretString(obj, "name"),
+		Project:          secretString(obj, "project"),
+		Username:         secretString(obj, "username"),
+		HasPassword:      secretString(obj, "password") != "",
+		HasSSHPrivateKey: secretString(obj, "sshPrivateKey") != "",
+		HasTLSClientCert: secretString(obj, "tlsClientCertData") != "",
+		Insecure:         secretString(obj, "insecure") == "true",
+		EnableOCI:        secretString(obj, "enableOCI") == "true",
+		Cluster:          s.cluster,
+		CreatedAt:        obj.GetCreationTimestamp().Format(time.RFC3339),
+	}
+	if repository.Type == "" {
+		repository.Type = RepositoryTypeGit
+	}
+	return repository
+}
+
+// ListRepositories returns the repositories or repository credentials of the ArgoCD namespace, sorted by URL
+func (s *Service) ListRepositories(ctx context.Context, secretType string) ([]Repository, error) {
+	list, err := s.client.Resource(secretResource).Namespace(Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: SecretTypeLabel + "=" + secretType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	repositories := make([]Repository, 0, len(list.Items))
+	for i := range list.Items {
+		repositories = append(repositories, s.repositoryFromSecret(&list.Items[i]))
+	}
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].URL < repositories[j].URL })
+	return repositories, nil
+}
+
+// getRepositorySecret returns a secret of the ArgoCD namespace, a secret of another type is not found
+func (s *Service) getRepositorySecret(ctx context.Context, secretType, name string) (*unstructured.Unstructured, error) {
+	obj, err := s.client.Resource(secretResource).Namespace(Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetLabels()[SecretTypeLabel] != secretType {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: secretType}, name)
+	}
+	return obj, nil
+}
+
+// CreateRepository stores a repository or repository credentials in a secret of the ArgoCD namespace
+func (s *Service) CreateRepository(ctx context.Context, secretType string, request RepositoryRequest) (*Repository, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	name := request.Name
+	if name == "" {
+		name = RepositorySecretName(secretType, request.URL)
+	}
+	data := map[string]interface{}{}
+	for key, value := range request.data() {
+		data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"data":       data,
+	}}
+	obj.SetName(name)
+	obj.SetNamespace(Namespace)
+	obj.SetLabels(map[string]string{SecretTypeLabel: secretType})
+	obj.SetAnnotations(map[string]string{"managed-by": "argocd.argoproj.io"})
+
+	created, err := s.client.Resource(secretResource).Namespace(Namespace).Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	repository := s.repositoryFromSecret(created)
+	return &repository, nil
+}
+
+// DeleteRepository deletes a repository or repository credentials secret
+func (s *Service) DeleteRepository(ctx context.Context, secretType, name string) error {
+	if _, err := s.getRepositorySecret(ctx, secretType, name); err != nil {
+		return err
+	}
+	return s.client.Resource(secretResource).Namespace(Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// StoredRepository returns a stored repository with its credentials, to test its connection
+func (s *Service) StoredRepository(ctx context.Context, name string) (RepositoryRequest, error) {
+	obj, err := s.getRepositorySecret(ctx, SecretTypeRepository, name)
+	if err != nil {
+		return RepositoryRequest{}, err
+	}
+	return RepositoryRequest{
+		Name:              obj.GetName(),
+		Type:              secretString(obj, "type"),
+		URL:               secretString(obj, "url"),
+		DisplayName:       secretString(obj, "name"),
+		Project:           secretString(obj, "project"),
+		Username:          secretString(obj, "username"),
+		Password:          secretString(obj, "password"),
+		SSHPrivateKey:     secretString(obj, "sshPrivateKey"),
+		TLSClientCertData: secretString(obj, "tlsClientCertData"),
+		TLSClientCertKey:  secretString(obj, "tlsClientCertKey"),
+		Insecure:          secretString(obj, "insecure") == "true",
+		EnableOCI:         secretString(obj, "enableOCI") == "true",
+	}, nil
+}
+
+// MatchingCredentials fills the missing credentials of a repository from the repository credentials with the
+// longest URL prefix, as ArgoCD does when it connects to the repository
+func (s *Service) MatchingCredentials(ctx context.Context, request RepositoryRequest) (RepositoryRequest, error) {
+	if request.Password != "" || request.SSHPrivateKey != "" || request.TLSClientCertData != "" {
+		return request, nil
+	}
+	list, err := s.client.Resource(secretResource).Namespace(Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: SecretTypeLabel + "=" + SecretTypeRepoCreds,
+	})
+	if err != nil {
+		return request, err
+	}
+	var best *unstructured.Unstructured
+	for i := range list.Items {
+		prefix := secretString(&list.Items[i], "url")
+		if prefix != "" && strings.HasPrefix(request.URL, prefix) && (best == nil || len(prefix) > len(secretString(best, "url"))) {
+			best = &list.Items[i]
+		}
+	}
+	if best != nil {
+		request.Username = secretString(best, "username")
+		request.Password = secretString(best, "password")
+		request.SSHPrivateKey = secretString(best, "sshPrivateKey")
+		request.TLSClientCertData = secretString(best, "tlsClientCertData")
+		request.TLSClientCertKey = secretString(best, "tlsClientCertKey")
+	}
+	return request, nil
+}
+
+// knownHostsConfigMap holds the SSH host keys ArgoCD trusts
+const knownHostsConfigMap = "argocd-ssh-known-hosts-cm"
+
+// KnownHosts returns the SSH host keys ArgoCD trusts, in known_hosts format
+func (s *Service) KnownHosts(ctx context.Context) (string, error) {
+	obj, err := s.client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace(Namespace).Get(ctx, knownHostsConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	knownHosts, _, _ := unstructured.NestedString(obj.Object, "data", "ssh_known_hosts")
+	return knownHosts, nil
+}
+
+// repositoryHost returns the host and port of a repository URL, with the default port of its scheme
+func repositoryHost(repoURL string) (string, error) {
+	if IsSSHURL(repoURL) && !strings.HasPrefix(repoURL, "ssh://") {
+		// scp-like syntax, user@host:path
+		host := repoURL[strings.Index(repoURL, "@")+1 : strings.Index(repoURL, ":")]
+		return host + ":22", nil
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository url: %v", err)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("invalid repository url %q", repoURL)
+	}
+	if parsed.Port() != "" {
+		return parsed.Host, nil
+	}
+	switch parsed.Scheme {
+	case "ssh":
+		return parsed.Host + ":22", nil
+	case "http":
+		return parsed.Host + ":80", nil
+	default:
+		return parsed.Host + ":443", nil
+	}
+}
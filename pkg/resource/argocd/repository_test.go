@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newRepositoryService() *Service {
+	listKinds := map[schema.GroupVersionResource]string{secretResource: "SecretList"}
+	return NewService(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds), "member1")
+}
+
+func TestRepositories(t *testing.T) {
+	ctx := context.TODO()
+	s := newRepositoryService()
+
+	created, err := s.CreateRepository(ctx, SecretTypeRepository, RepositoryRequest{
+		URL:      "https://github.com/example/apps.git",
+		Username: "bot",
+		Password: "token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != RepositorySecretName(SecretTypeRepository, "https://github.com/example/apps.git") || !strings.HasPrefix(created.Name, "repo-") {
+		t.Errorf("CreateRepository() name = %s, want a name derived from the url", created.Name)
+	}
+	if created.Type != RepositoryTypeGit || !created.HasPassword || created.Cluster != "member1" {
+		t.Errorf("CreateRepository() = %+v", created)
+	}
+	if _, err := s.CreateRepository(ctx, SecretTypeRepoCreds, RepositoryRequest{
+		Name:          "github-creds",
+		URL:           "git@github.com:example",
+		SSHPrivateKey: "key",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	repositories, err := s.ListRepositories(ctx, SecretTypeRepository)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repositories) != 1 || repositories[0].URL != "https://github.com/example/apps.git" {
+		t.Errorf("ListRepositories() = %+v, want only the repository", repositories)
+	}
+
+	stored, err := s.StoredRepository(ctx, created.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Password != "token" || stored.Username != "bot" {
+		t.Errorf("StoredRepository() = %+v, want the stored credentials", stored)
+	}
+
+	matched, err := s.MatchingCredentials(ctx, RepositoryRequest{URL: "git@github.com:example/infra.git"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched.SSHPrivateKey != "key" {
+		t.Errorf("MatchingCredentials() did not use the credentials of the url prefix: %+v", matched)
+	}
+
+	if err := s.DeleteRepository(ctx, SecretTypeRepository, "github-creds"); !apierrors.IsNotFound(err) {
+		t.Errorf("DeleteRepository() of credentials as a repository error = %v, want not found", err)
+	}
+	if err := s.DeleteRepository(ctx, SecretTypeRepository, created.Name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepositoryRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request RepositoryRequest
+		wantErr bool
+	}{
+		{name: "https", request: RepositoryRequest{URL: "https://github.com/example/apps.git"}},
+		{name: "missing url", request: RepositoryRequest{Type: RepositoryTypeGit}, wantErr: true},
+		{name: "unknown type", request: RepositoryRequest{Type: "svn", URL: "https://example.com/repo"}, wantErr: true},
+		{name: "ssh key with https url", request: RepositoryRequest{URL: "https://github.com/example/apps.git", SSHPrivateKey: "key"}, wantErr: true},
+		{name: "oci git", request: RepositoryRequest{URL: "registry.example.com/charts", EnableOCI: true}, wantErr: true},
+		{name: "client certificate without key", request: RepositoryRequest{URL: "https://example.com/repo", TLSClientCertData: "cert"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRepositoryHost(t *testing.T) {
+	tests := []struct {
+		url, host, user string
+	}{
+		{"git@github.com:example/apps.git", "github.com:22", "git"},
+		{"ssh://deploy@git.example.com:2222/apps.git", "git.example.com:2222", "deploy"},
+		{"https://gitlab.example.com/apps.git", "gitlab.example.com:443", "git"},
+		{"http://charts.example.com", "charts.example.com:80", "git"},
+	}
+	for _, tt := range tests {
+		host, err := repositoryHost(tt.url)
+		if err != nil || host != tt.host {
+			t.Errorf("repositoryHost(%q) = %q, %v, want %q", tt.url, host, err, tt.host)
+		}
+		if IsSSHURL(tt.url) && sshUser(tt.url) != tt.user {
+			t.Errorf("sshUser(%q) = %q, want %q", tt.url, sshUser(tt.url), tt.user)
+		}
+	}
+}
+
+func TestTestConnectionHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/example/apps.git/info/refs" || r.URL.Query().Get("service") != "git-upload-pack" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if username, password, ok := r.BasicAuth(); !ok || username != "bot" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repoURL := server.URL + "/example/apps.git"
+	if result := TestConnection(context.TODO(), RepositoryRequest{URL: repoURL, Username: "bot", Password: "token"}, ""); !result.Successful {
+		t.Errorf("TestConnection() = %+v, want success", result)
+	}
+	if result := TestConnection(context.TODO(), RepositoryRequest{URL: repoURL, Username: "bot", Password: "wrong"}, ""); result.Successful || !strings.Contains(result.Message, "authentication") {
+		t.Errorf("TestConnection() with a wrong password = %+v", result)
+	}
+	if result := TestConnection(context.TODO(), RepositoryRequest{URL: server.URL + "/missing.git"}, ""); result.Successful {
+		t.Errorf("TestConnection() of a missing repository = %+v", result)
+	}
+}
+
+func TestKnownHostsCallback(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, _ := ssh.NewPublicKey(otherPublicKey)
+
+	callback := knownHostsCallback("# trusted hosts\n" + knownhosts.Line([]string{"github.com"}, key) + "\n")
+	if err := callback("github.com:22", nil, key); err != nil {
+		t.Errorf("known host rejected: %v", err)
+	}
+	if err := callback("github.com:22", nil, otherKey); err == nil {
+		t.Error("host with another key accepted")
+	}
+	if err := callback("gitlab.com:22", nil, key); err == nil {
+		t.Error("unknown host accepted")
+	}
+}
@@ -0,0 +1,462 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+	"github.com/karmada-io/dashboard/pkg/resource/pod"
+)
+
+// resourceKinds are the kinds included in the resources of an application
+var resourceKinds = []string{
+	"Deployment",
+	"StatefulSet",
+	"DaemonSet",
+	"ReplicaSet",
+	"Pod",
+	"Job",
+	"CronJob",
+	"Service",
+	"Ingress",
+	"ConfigMap",
+	"Secret",
+	"PersistentVolumeClaim",
+}
+
+func includedKind(kind string) bool {
+	for _, k := range resourceKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// statusResources returns the resources listed in the status of an application
+func statusResources(application *unstructured.Unstructured) []map[string]interface{} {
+	raw, _, _ := unstructured.NestedSlice(application.Object, "status", "resources")
+	resources := make([]map[string]interface{}, 0, len(raw))
+	for _, item := range raw {
+		if resource, ok := item.(map[string]interface{}); ok {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// StatusResources returns the resources of the included kinds listed in the status of an application
+func StatusResources(application *unstructured.Unstructured) []map[string]interface{} {
+	resources := make([]map[string]interface{}, 0)
+	for _, resource := range statusResources(application) {
+		if kind, ok := resource["kind"].(string); ok && includedKind(kind) {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// ApplicationResources returns the resources listed in the status of an application together with
+// the live objects of the included kinds in their namespaces, such as the ReplicaSets and Pods the
+// application does not list, and the containers of the Pods
+func (s *Service) ApplicationResources(ctx context.Context, application *unstructured.Unstructured) ([]map[string]interface{}, error) {
+	if _, found, _ := unstructured.NestedMap(application.Object, "status"); !found {
+		return nil, fmt.Errorf("application status not found or invalid")
+	}
+	listed := statusResources(application)
+	if len(listed) == 0 {
+		return nil, fmt.Errorf("no resources found in application status")
+	}
+
+	// The kinds the application lists in each namespace
+	namespaceKinds := make(map[string]map[string]bool)
+	for _, resource := range listed {
+		kind, ok := resource["kind"].(string)
+		if !ok {
+			continue
+		}
+		namespace, _ := resource["namespace"].(string)
+		if namespace == "" {
+			namespace = "default"
+		}
+		if namespaceKinds[namespace] == nil {
+			namespaceKinds[namespace] = make(map[string]bool)
+		}
+		namespaceKinds[namespace][kind] = true
+	}
+
+	allResources := append(make([]map[string]interface{}, 0, len(listed)), listed...)
+	for namespace, kinds := range namespaceKinds {
+		for _, kind := range resourceKinds {
+			if !kinds[kind] && kind != "ReplicaSet" && kind != "Pod" {
+				continue
+			}
+			gvr, ok := s.resourceGVR(kind)
+			if !ok {
+				continue
+			}
+			list, err := s.client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				klog.ErrorS(err, "Failed to list resources", "kind", kind, "namespace", namespace)
+				continue
+			}
+			var metrics map[string]pod.PodMetrics
+			if kind == "Pod" {
+				// The usage is shown when metrics-server runs in the cluster
+				if metrics, err = pod.ListPodMetrics(ctx, s.client, namespace); err != nil {
+					klog.V(4).InfoS("Pod metrics are not available", "cluster", s.cluster, "namespace", namespace, "error", err)
+				}
+			}
+			for i := range list.Items {
+				item := &list.Items[i]
+				if item.GetUID() == "" || item.GetName() == "" {
+					continue
+				}
+				resource := liveResource(kind, item)
+				allResources = append(allResources, resource)
+				if kind == "Pod" {
+					containers := containerResources(item)
+					if m, ok := metrics[item.GetNamespace()+"/"+item.GetName()]; ok {
+						addPodUsage(resource, containers, m)
+					}
+					allResources = append(allResources, containers...)
+				}
+			}
+		}
+	}
+	return allResources, nil
+}
+
+// liveResource summarizes an object with its status, health and owners
+func liveResource(kind string, item *unstructured.Unstructured) map[string]interface{} {
+	var owners []map[string]interface{}
+	for _, owner := range item.GetOwnerReferences() {
+		if owner.UID == "" {
+			continue
+		}
+		owners = append(owners, map[string]interface{}{
+			"uid":  string(owner.UID),
+			"kind": owner.Kind,
+			"name": owner.Name,
+		})
+	}
+	creationTimestamp, _, _ := unstructured.NestedString(item.Object, "metadata", "creationTimestamp")
+	resource := map[string]interface{}{
+		"kind":              kind,
+		"name":              item.GetName(),
+		"namespace":         item.GetNamespace(),
+		"uid":               string(item.GetUID()),
+		"status":            resourceStatus(kind, item),
+		"creationTimestamp": creationTimestamp,
+		"ownerReferences":   owners,
+	}
+	if health := resourceHealth(kind, item); health != "" {
+		resource["health"] = map[string]interface{}{"status": health}
+	}
+	return resource
+}
+
+// replicasReady reports whether the ready replicas of a workload match its replicas, and false when
+// its status does not report them
+func replicasReady(item *unstructured.Unstructured) (ready, reported bool) {
+	replicas, hasReplicas, _ := unstructured.NestedInt64(item.Object, "status", "replicas")
+	readyReplicas, hasReadyReplicas, _ := unstructured.NestedInt64(item.Object, "status", "readyReplicas")
+	if !hasReplicas || !hasReadyReplicas {
+		return false, false
+	}
+	return replicas == readyReplicas, true
+}
+
+// resourceStatus returns the status of an object shown in the resource tree
+func resourceStatus(kind string, item *unstructured.Unstructured) string {
+	switch kind {
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		return phase
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		ready, reported := replicasReady(item)
+		switch {
+		case !reported:
+			return "Unknown"
+		case ready:
+			return "Ready"
+		}
+		return "Progressing"
+	case "Service":
+		serviceType, _, _ := unstructured.NestedString(item.Object, "spec", "type")
+		ingress, found, _ := unstructured.NestedSlice(item.Object, "status", "loadBalancer", "ingress")
+		if serviceType == "LoadBalancer" && found && len(ingress) == 0 {
+			// Waiting for an external IP
+			return "Pending"
+		}
+		return "Ready"
+	case "Job":
+		if succeeded, _, _ := unstructured.NestedInt64(item.Object, "status", "succeeded"); succeeded > 0 {
+			return "Completed"
+		}
+		if failed, _, _ := unstructured.NestedInt64(item.Object, "status", "failed"); failed > 0 {
+			return "Failed"
+		}
+		return "Running"
+	case "PersistentVolumeClaim":
+		if phase, _, _ := unstructured.NestedString(item.Object, "status", "phase"); phase != "" {
+			return phase
+		}
+		return "Pending"
+	case "Ingress", "CronJob", "ConfigMap", "Secret":
+		// Ready once created
+		return "Ready"
+	}
+	return "Unknown"
+}
+
+// resourceHealth returns the ArgoCD health of Pods and workloads, empty for other objects
+func resourceHealth(kind string, item *unstructured.Unstructured) string {
+	switch kind {
+	case "Pod":
+		if phase, found, _ := unstructured.NestedString(item.Object, "status", "phase"); found {
+			return podPhaseToHealth(phase)
+		}
+	case "Deployment", "StatefulSet", "DaemonSet":
+		ready, reported := replicasReady(item)
+		switch {
+		case !reported:
+			return ""
+		case ready:
+			return "Healthy"
+		}
+		return "Progressing"
+	}
+	return ""
+}
+
+// containerResources returns the containers of a Pod as resources owned by it
+func containerResources(pod *unstructured.Unstructured) []map[string]interface{} {
+	var containers []interface{}
+	for _, field := range []string{"containers", "initContainers", "ephemeralContainers"} {
+		list, _, _ := unstructured.NestedSlice(pod.Object, "spec", field)
+		containers = append(containers, list...)
+	}
+	statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", "containerStatuses")
+	creationTimestamp, _, _ := unstructured.NestedString(pod.Object, "metadata", "creationTimestamp")
+
+	resources := make([]map[string]interface{}, 0, len(containers))
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := container["name"].(string)
+		if !ok {
+			continue
+		}
+		resource := map[string]interface{}{
+			"uid":               fmt.Sprintf("%s-container-%s", pod.GetUID(), name),
+			"kind":              "Container",
+			"name":              name,
+			"namespace":         pod.GetNamespace(),
+			"status":            containerStatus(statuses, name),
+			"creationTimestamp": creationTimestamp,
+			"ownerReferences": []map[string]interface{}{
+				{
+					"uid":  string(pod.GetUID()),
+					"kind": "Pod",
+					"name": pod.GetName(),
+				},
+			},
+			"children": []interface{}{},
+		}
+		if image, ok := container["image"].(string); ok {
+			resource["image"] = image
+		}
+		if ports, ok := container["ports"].([]interface{}); ok && len(ports) > 0 {
+			resource["ports"] = ports
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// addPodUsage adds the usage of a Pod and of its containers to their resources
+func addPodUsage(podResource map[string]interface{}, containers []map[string]interface{}, metrics pod.PodMetrics) {
+	podResource["usage"] = metrics.Usage
+	for _, container := range containers {
+		for _, m := range metrics.Containers {
+			if container["name"] == m.Name {
+				container["usage"] = m.Usage
+			}
+		}
+	}
+}
+
+// containerStatus returns the state of a container from the container statuses of its Pod
+func containerStatus(statuses []interface{}, name string) string {
+	status := "Unknown"
+	for _, s := range statuses {
+		containerStatus, ok := s.(map[string]interface{})
+		if !ok || containerStatus["name"] != name {
+			continue
+		}
+		if ready, ok := containerStatus["ready"].(bool); ok && ready {
+			status = "Ready"
+		}
+		if state, ok := containerStatus["state"].(map[string]interface{}); ok {
+			if _, ok := state["running"]; ok {
+				status = "Running"
+			} else if _, ok := state["waiting"]; ok {
+				status = "Waiting"
+			} else if _, ok := state["terminated"]; ok {
+				status = "Terminated"
+			}
+		}
+	}
+	return status
+}
+
+// BuildResourceTree nests the resources under their owners and returns the resources without an owner
+// among them
+func BuildResourceTree(resources []map[string]interface{}) []map[string]interface{} {
+	// Copy the resources to keep the originals unchanged
+	byUID := make(map[string]map[string]interface{})
+	var order []string
+	for _, resource := range resources {
+		uid, ok := resource["uid"].(string)
+		if !ok || uid == "" {
+			continue
+		}
+		resourceCopy := make(map[string]interface{}, len(resource)+1)
+		for k, v := range resource {
+			resourceCopy[k] = v
+		}
+		if _, seen := byUID[uid]; !seen {
+			order = append(order, uid)
+		}
+		byUID[uid] = resourceCopy
+	}
+
+	hasParent := make(map[string]bool)
+	for _, resource := range resources {
+		uid, ok := resource["uid"].(string)
+		if !ok {
+			continue
+		}
+		owners, _ := resource["ownerReferences"].([]map[string]interface{})
+		for _, owner := range owners {
+			ownerUID, _ := owner["uid"].(string)
+			if ownerUID == "" || ownerUID == uid {
+				continue
+			}
+			parent, found := byUID[ownerUID]
+			if !found {
+				continue
+			}
+			children, _ := parent["children"].([]map[string]interface{})
+			parent["children"] = append(children, byUID[uid])
+			hasParent[uid] = true
+		}
+	}
+
+	roots := make([]map[string]interface{}, 0)
+	for _, uid := range order {
+		if !hasParent[uid] {
+			roots = append(roots, byUID[uid])
+		}
+	}
+	return roots
+}
+
+// BuildResourceGraph returns the resources as nodes and their owner references among them as edges
+// from the owner to the owned resource
+func BuildResourceGraph(resources []map[string]interface{}) map[string]interface{} {
+	known := make(map[string]bool)
+	for _, resource := range resources {
+		if uid, ok := resource["uid"].(string); ok && uid != "" {
+			known[uid] = true
+		}
+	}
+	edges := []map[string]interface{}{}
+	for _, resource := range resources {
+		childUID, _ := resource["uid"].(string)
+		owners, _ := resource["ownerReferences"].([]interface{})
+		for _, o := range owners {
+			owner, ok := o.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ownerUID, _ := owner["uid"].(string)
+			if ownerUID == "" || childUID == "" || !known[ownerUID] {
+				continue
+			}
+			edges = append(edges, map[string]interface{}{
+				"from": ownerUID,
+				"to":   childUID,
+			})
+		}
+	}
+	return map[string]interface{}{
+		"nodes": resources,
+		"edges": edges,
+	}
+}
+
+// kindToGVR maps a Kubernetes resource kind to its GroupVersionResource
+func kindToGVR(kind string) schema.GroupVersionResource {
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: strings.ToLower(kind) + "s"}
+	case "Ingress":
+		return schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	case "Job", "CronJob":
+		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: strings.ToLower(kind) + "s"}
+	}
+	return schema.GroupVersionResource{Version: "v1", Resource: strings.ToLower(kind) + "s"}
+}
+
+// resourceGVR returns the GVR to list a kind with, using the served version when the capabilities of the
+// cluster are known. Kinds the cluster does not serve are skipped.
+func (s *Service) resourceGVR(kind string) (schema.GroupVersionResource, bool) {
+	if s.capabilities != nil {
+		if gvr, ok := s.capabilities.GVR(kind); ok {
+			return gvr, true
+		}
+		if _, versioned := capability.DefaultGVR(kind); versioned && !s.capabilities.Partial {
+			return schema.GroupVersionResource{}, false
+		}
+	}
+	return kindToGVR(kind), true
+}
+
+// podPhaseToHealth converts a Pod phase to an ArgoCD health status
+func podPhaseToHealth(phase string) string {
+	switch phase {
+	case "Running", "Succeeded":
+		return "Healthy"
+	case "Pending":
+		return "Progressing"
+	case "Failed":
+		return "Degraded"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argocd
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/karmada-io/dashboard/pkg/resource/capability"
+	"github.com/karmada-io/dashboard/pkg/resource/pod"
+)
+
+func TestResourceStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		object map[string]interface{}
+		status string
+		health string
+	}{
+		{
+			name:   "running pod",
+			kind:   "Pod",
+			object: map[string]interface{}{"status": map[string]interface{}{"phase": "Running"}},
+			status: "Running",
+			health: "Healthy",
+		},
+		{
+			name:   "ready deployment",
+			kind:   "Deployment",
+			object: map[string]interface{}{"status": map[string]interface{}{"replicas": int64(2), "readyReplicas": int64(2)}},
+			status: "Ready",
+			health: "Healthy",
+		},
+		{
+			name:   "progressing statefulset",
+			kind:   "StatefulSet",
+			object: map[string]interface{}{"status": map[string]interface{}{"replicas": int64(3), "readyReplicas": int64(1)}},
+			status: "Progressing",
+			health: "Progressing",
+		},
+		{
+			name:   "new daemonset",
+			kind:   "DaemonSet",
+			object: map[string]interface{}{},
+			status: "Unknown",
+		},
+		{
+			name: "load balancer without address",
+			kind: "Service",
+			object: map[string]interface{}{
+				"spec":   map[string]interface{}{"type": "LoadBalancer"},
+				"status": map[string]interface{}{"loadBalancer": map[string]interface{}{"ingress": []interface{}{}}},
+			},
+			status: "Pending",
+		},
+		{
+			name:   "succeeded job",
+			kind:   "Job",
+			object: map[string]interface{}{"status": map[string]interface{}{"succeeded": int64(1)}},
+			status: "Completed",
+		},
+		{
+			name:   "bound claim",
+			kind:   "PersistentVolumeClaim",
+			object: map[string]interface{}{"status": map[string]interface{}{"phase": "Bound"}},
+			status: "Bound",
+		},
+		{
+			name:   "config map",
+			kind:   "ConfigMap",
+			object: map[string]interface{}{},
+			status: "Ready",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &unstructured.Unstructured{Object: tt.object}
+			if status := resourceStatus(tt.kind, item); status != tt.status {
+				t.Errorf("resourceStatus() = %q, want %q", status, tt.status)
+			}
+			if health := resourceHealth(tt.kind, item); health != tt.health {
+				t.Errorf("resourceHealth() = %q, want %q", health, tt.health)
+			}
+		})
+	}
+}
+
+func TestContainerResources(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "web-0", "namespace": "default", "uid": "pod-uid"},
+		"spec": map[string]interface{}{
+			"initContainers": []interface{}{map[string]interface{}{"name": "init", "image": "busybox"}},
+			"containers":     []interface{}{map[string]interface{}{"name": "web", "image": "nginx"}},
+		},
+		"status": map[string]interface{}{
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "web", "ready": true, "state": map[string]interface{}{"running": map[string]interface{}{}}},
+			},
+		},
+	}}
+	containers := containerResources(pod)
+	if len(containers) != 2 {
+		t.Fatalf("containerResources() returned %d containers, want 2", len(containers))
+	}
+	web := containers[0]
+	if web["uid"] != "pod-uid-container-web" || web["status"] != "Running" || web["image"] != "nginx" {
+		t.Errorf("web container = %v", web)
+	}
+	if containers[1]["status"] != "Unknown" {
+		t.Errorf("init container status = %v, want Unknown", containers[1]["status"])
+	}
+}
+
+func TestAddPodUsage(t *testing.T) {
+	podResource := map[string]interface{}{"kind": "Pod", "name": "web-0"}
+	containers := []map[string]interface{}{{"name": "web"}, {"name": "init"}}
+	addPodUsage(podResource, containers, pod.PodMetrics{
+		Usage:      pod.Usage{CPUMillicores: 120, MemoryBytes: 1 << 20},
+		Containers: []pod.ContainerMetrics{{Name: "web", Usage: pod.Usage{CPUMillicores: 120, MemoryBytes: 1 << 20}}},
+	})
+	if usage, _ := podResource["usage"].(pod.Usage); usage.CPUMillicores != 120 {
+		t.Errorf("pod usage = %v, want 120m", podResource["usage"])
+	}
+	if usage, _ := containers[0]["usage"].(pod.Usage); usage.MemoryBytes != 1<<20 {
+		t.Errorf("web container usage = %v, want 1Mi", containers[0]["usage"])
+	}
+	if _, ok := containers[1]["usage"]; ok {
+		t.Errorf("init container without metrics has usage %v", containers[1]["usage"])
+	}
+}
+
+func owned(uid, kind, ownerUID string) map[string]interface{} {
+	resource := map[string]interface{}{"uid": uid, "kind": kind}
+	if ownerUID != "" {
+		resource["ownerReferences"] = []map[string]interface{}{{"uid": ownerUID}}
+	}
+	return resource
+}
+
+func TestBuildResourceTree(t *testing.T) {
+	resources := []map[string]interface{}{
+		owned("deploy", "Deployment", ""),
+		owned("rs", "ReplicaSet", "deploy"),
+		owned("pod", "Pod", "rs"),
+		owned("svc", "Service", ""),
+		// The owner is not among the resources
+		owned("orphan", "Pod", "missing"),
+	}
+	roots := BuildResourceTree(resources)
+	if len(roots) != 3 {
+		t.Fatalf("BuildResourceTree() returned %d roots, want 3", len(roots))
+	}
+	if roots[0]["uid"] != "deploy" || roots[1]["uid"] != "svc" || roots[2]["uid"] != "orphan" {
+		t.Errorf("BuildResourceTree() roots = %v", roots)
+	}
+	replicaSets, _ := roots[0]["children"].([]map[string]interface{})
+	if len(replicaSets) != 1 || replicaSets[0]["uid"] != "rs" {
+		t.Fatalf("deployment children = %v", roots[0]["children"])
+	}
+	pods, _ := replicaSets[0]["children"].([]map[string]interface{})
+	if len(pods) != 1 || pods[0]["uid"] != "pod" {
+		t.Errorf("replica set children = %v", replicaSets[0]["children"])
+	}
+	if _, changed := resources[0]["children"]; changed {
+		t.Errorf("BuildResourceTree() changed its input")
+	}
+}
+
+func TestBuildResourceGraph(t *testing.T) {
+	resources := []map[string]interface{}{
+		{"uid": "deploy"},
+		{"uid": "rs", "ownerReferences": []interface{}{map[string]interface{}{"uid": "deploy"}}},
+		{"uid": "pod", "ownerReferences": []interface{}{map[string]interface{}{"uid": "missing"}}},
+	}
+	edges, _ := BuildResourceGraph(resources)["edges"].([]map[string]interface{})
+	if len(edges) != 1 || edges[0]["from"] != "deploy" || edges[0]["to"] != "rs" {
+		t.Errorf("BuildResourceGraph() edges = %v", edges)
+	}
+}
+
+func TestStatusResources(t *testing.T) {
+	application := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"resources": []interface{}{
+				map[string]interface{}{"kind": "Deployment", "name": "web"},
+				map[string]interface{}{"kind": "ServiceAccount", "name": "web"},
+			},
+		},
+	}}
+	resources := StatusResources(application)
+	if len(resources) != 1 || resources[0]["kind"] != "Deployment" {
+		t.Errorf("StatusResources() = %v, want only the deployment", resources)
+	}
+}
+
+func TestResourceGVR(t *testing.T) {
+	capabilities := &capability.Capabilities{Resources: []capability.Resource{
+		{Kind: capability.KindIngress, Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses", Available: true},
+		{Kind: capability.KindCronJob},
+	}}
+	service := NewService(nil, "member1").WithCapabilities(capabilities)
+
+	if gvr, ok := service.resourceGVR("Ingress"); !ok || gvr.Version != "v1beta1" {
+		t.Errorf("resourceGVR(Ingress) = %v, %v, want the served v1beta1", gvr, ok)
+	}
+	if _, ok := service.resourceGVR("CronJob"); ok {
+		t.Errorf("resourceGVR(CronJob) should skip a kind the cluster does not serve")
+	}
+	if gvr, ok := service.resourceGVR("Deployment"); !ok || gvr.Group != "apps" {
+		t.Errorf("resourceGVR(Deployment) = %v, %v, want apps/v1", gvr, ok)
+	}
+
+	capabilities.Partial = true
+	if gvr, ok := service.resourceGVR("CronJob"); !ok || gvr.Version != "v1" {
+		t.Errorf("resourceGVR(CronJob) = %v, %v, want batch/v1 when discovery was partial", gvr, ok)
+	}
+}
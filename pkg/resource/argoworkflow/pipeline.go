@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argoworkflow
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Labels and annotations Kubeflow Pipelines sets on the workflows of its runs
+const (
+	PipelineRunIDLabel        = "pipeline/runid"
+	PipelineRunNameAnnotation = "pipelines.kubeflow.org/run_name"
+	RecurringRunLabel         = "scheduledworkflows.kubeflow.org/scheduledWorkflowName"
+
+	pipelinesPrefix = "pipelines.kubeflow.org/"
+)
+
+// PipelineRun is a Kubeflow Pipelines run executed by an Argo Workflow
+type PipelineRun struct {
+	Workflow
+	RunID   string `json:"runId,omitempty"`
+	RunName string `json:"runName"`
+	// RecurringRun is the recurring run that started the run
+	RecurringRun string `json:"recurringRun,omitempty"`
+	// Profile is the Kubeflow Profile owning the namespace of the run, and Owner the owner of the profile
+	Profile string `json:"profile,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+}
+
+// PipelineLoad counts the pipeline runs of a cluster by phase, runs without a phase yet are pending
+type PipelineLoad struct {
+	Cluster   string `json:"cluster"`
+	Total     int    `json:"total"`
+	Pending   int    `json:"pending"`
+	Running   int    `json:"running"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+}
+
+// IsPipelineRun reports whether a workflow was submitted by Kubeflow Pipelines
+func IsPipelineRun(obj *unstructured.Unstructured) bool {
+	if _, ok := obj.GetLabels()[PipelineRunIDLabel]; ok {
+		return true
+	}
+	for key := range obj.GetLabels() {
+		if strings.HasPrefix(key, pipelinesPrefix) {
+			return true
+		}
+	}
+	for key := range obj.GetAnnotations() {
+		if strings.HasPrefix(key, pipelinesPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SummarizePipelineRun returns the run of a workflow submitted by Kubeflow Pipelines
+func SummarizePipelineRun(obj *unstructured.Unstructured, cluster string, now time.Time) PipelineRun {
+	run := PipelineRun{
+		Workflow:     Summarize(obj, cluster, now),
+		RunID:        obj.GetLabels()[PipelineRunIDLabel],
+		RunName:      obj.GetAnnotations()[PipelineRunNameAnnotation],
+		RecurringRun: obj.GetLabels()[RecurringRunLabel],
+	}
+	if run.RunName == "" {
+		run.RunName = obj.GetName()
+	}
+	return run
+}
+
+// ListPipelineRuns returns the Kubeflow Pipelines runs of a namespace, or of all namespaces when it is
+// empty, newest first
+func (s *Service) ListPipelineRuns(ctx context.Context, namespace string) ([]PipelineRun, error) {
+	list, err := s.client.Resource(Resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	runs := make([]PipelineRun, 0)
+	for i := range list.Items {
+		if IsPipelineRun(&list.Items[i]) {
+			runs = append(runs, SummarizePipelineRun(&list.Items[i], s.cluster, now))
+		}
+	}
+	SortPipelineRuns(runs)
+	return runs, nil
+}
+
+// SortPipelineRuns orders runs newest first
+func SortPipelineRuns(runs []PipelineRun) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].CreatedAt != runs[j].CreatedAt {
+			return runs[i].CreatedAt > runs[j].CreatedAt
+		}
+		if runs[i].Cluster != runs[j].Cluster {
+			return runs[i].Cluster < runs[j].Cluster
+		}
+		return runs[i].Name < runs[j].Name
+	})
+}
+
+// CountPipelineLoad counts the runs of a cluster by phase
+func CountPipelineLoad(cluster string, runs []PipelineRun) PipelineLoad {
+	load := PipelineLoad{Cluster: cluster}
+	for _, run := range runs {
+		load.Total++
+		switch run.Phase {
+		case PhaseRunning:
+			load.Running++
+		case PhaseSucceeded:
+			load.Succeeded++
+		case PhaseFailed, PhaseError:
+			load.Failed++
+		default:
+			load.Pending++
+		}
+	}
+	return load
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package argoworkflow lists the Argo Workflows of a cluster with their phase and duration, and retries,
+// stops and deletes them.
+package argoworkflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Resource is the GVR of Argo Workflows
+var Resource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"}
+
+const (
+	apiVersion = "argoproj.io/v1alpha1"
+	kind       = "Workflow"
+
+	// ResubmittedFromLabel names the workflow a retry was submitted from, as the Argo CLI does
+	ResubmittedFromLabel = "workflows.argoproj.io/resubmitted-from-workflow"
+)
+
+// Phases of a workflow
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+	PhaseError     = "Error"
+)
+
+// Actions that can be taken on a workflow
+const (
+	ActionRetry  = "retry"
+	ActionStop   = "stop"
+	ActionDelete = "delete"
+)
+
+// ErrActionNotAllowed is returned when an action does not apply to the phase of a workflow
+var ErrActionNotAllowed = errors.New("action not allowed")
+
+// Workflow summarizes an Argo Workflow
+type Workflow struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	// Phase is empty until the workflow controller picks the workflow up
+	Phase    string `json:"phase"`
+	Message  string `json:"message,omitempty"`
+	Progress string `json:"progress,omitempty"`
+	// WorkflowTemplate is the template the workflow was submitted from
+	WorkflowTemplate string `json:"workflowTemplate,omitempty"`
+	// Stopping is set when a stop was requested and the workflow has not finished yet
+	Stopping   bool   `json:"stopping,omitempty"`
+	Suspended  bool   `json:"suspended,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	StartedAt  string `json:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+	// Duration is the run time in seconds, up to now for running workflows
+	Duration int64    `json:"duration"`
+	Actions  []string `json:"actions"`
+}
+
+// Completed reports whether a phase is final
+func Completed(phase string) bool {
+	return phase == PhaseSucceeded || phase == PhaseFailed || phase == PhaseError
+}
+
+// Summarize returns the summary of a workflow, with the duration of running workflows measured up to now
+func Summarize(obj *unstructured.Unstructured, cluster string, now time.Time) Workflow {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+	progress, _, _ := unstructured.NestedString(obj.Object, "status", "progress")
+	startedAt, _, _ := unstructured.NestedString(obj.Object, "status", "startedAt")
+	finishedAt, _, _ := unstructured.NestedString(obj.Object, "status", "finishedAt")
+	template, _, _ := unstructured.NestedString(obj.Object, "spec", "workflowTemplateRef", "name")
+	shutdown, _, _ := unstructured.NestedString(obj.Object, "spec", "shutdown")
+	suspend, _, _ := unstructured.NestedBool(obj.Object, "spec", "suspend")
+
+	workflow := Workflow{
+		Name:             obj.GetName(),
+		Namespace:        obj.GetNamespace(),
+		Cluster:          cluster,
+		Phase:            phase,
+		Message:          message,
+		Progress:         progress,
+		WorkflowTemplate: template,
+		Stopping:         shutdown != "" && !Completed(phase),
+		Suspended:        suspend && !Completed(phase),
+		CreatedAt:        obj.GetCreationTimestamp().UTC().Format(time.RFC3339),
+		StartedAt:        startedAt,
+		FinishedAt:       finishedAt,
+		Duration:         duration(startedAt, finishedAt, now),
+		Actions:          []string{},
+	}
+	if phase == PhaseFailed || phase == PhaseError {
+		workflow.Actions = append(workflow.Actions, ActionRetry)
+	}
+	if !Completed(phase) && shutdown == "" {
+		workflow.Actions = append(workflow.Actions, ActionStop)
+	}
+	workflow.Actions = append(workflow.Actions, ActionDelete)
+	return workflow
+}
+
+// duration returns the seconds between the start and the end of a run, or now when it has not finished
+func duration(startedAt, finishedAt string, now time.Time) int64 {
+	start, err := time.Parse(time.RFC3339, startedAt)
+	if err != nil {
+		return 0
+	}
+	end := now
+	if finished, err := time.Parse(time.RFC3339, finishedAt); err == nil {
+		end = finished
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return int64(end.Sub(start).Seconds())
+}
+
+// Service manages the Argo Workflows of a cluster
+type Service struct {
+	client  dynamic.Interface
+	cluster string
+}
+
+// NewService returns a service for the workflows of the cluster the client connects to
+func NewService(client dynamic.Interface, cluster string) *Service {
+	return &Service{client: client, cluster: cluster}
+}
+
+// List returns the workflows of a namespace, or of all namespaces when it is empty, newest first
+func (s *Service) List(ctx context.Context, namespace string) ([]Workflow, error) {
+	list, err := s.client.Resource(Resource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	workflows := make([]Workflow, 0, len(list.Items))
+	for i := range list.Items {
+		workflows = append(workflows, Summarize(&list.Items[i], s.cluster, now))
+	}
+	sort.SliceStable(workflows, func(i, j int) bool {
+		if workflows[i].CreatedAt != workflows[j].CreatedAt {
+			return workflows[i].CreatedAt > workflows[j].CreatedAt
+		}
+		return workflows[i].Name < workflows[j].Name
+	})
+	return workflows, nil
+}
+
+// Get returns a workflow without its managed fields
+func (s *Service) Get(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := s.client.Resource(Resource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	obj.SetManagedFields(nil)
+	return obj, nil
+}
+
+// Retry submits a new run of a failed workflow with its spec, as `argo resubmit` does, and returns it.
+// The failed workflow is kept so its logs and nodes stay available.
+func (s *Service) Retry(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	workflows := s.client.Resource(Resource).Namespace(namespace)
+	current, err := workflows.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if phase, _, _ := unstructured.NestedString(current.Object, "status", "phase"); phase != PhaseFailed && phase != PhaseError {
+		return nil, fmt.Errorf("%w: workflow %s can only be retried once it failed, its phase is %q", ErrActionNotAllowed, name, phase)
+	}
+	spec, found, err := unstructured.NestedMap(current.Object, "spec")
+	if err != nil || !found {
+		return nil, fmt.Errorf("workflow %s has no spec", name)
+	}
+	delete(spec, "shutdown")
+	delete(spec, "suspend")
+
+	labels := map[string]string{}
+	for key, value := range current.GetLabels() {
+		// The controller sets its own labels on the new run
+		if !strings.HasPrefix(key, "workflows.argoproj.io/") {
+			labels[key] = value
+		}
+	}
+	labels[ResubmittedFromLabel] = name
+
+	retry := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	retry.SetAPIVersion(apiVersion)
+	retry.SetKind(kind)
+	retry.SetNamespace(namespace)
+	retry.SetGenerateName(name + "-")
+	retry.SetLabels(labels)
+	retry.SetAnnotations(current.GetAnnotations())
+	return workflows.Create(ctx, retry, metav1.CreateOptions{})
+}
+
+// Stop asks the workflow controller to stop a workflow, running its exit handlers
+func (s *Service) Stop(ctx context.Context, namespace, name string) error {
+	workflows := s.client.Resource(Resource).Namespace(namespace)
+	current, err := workflows.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if phase, _, _ := unstructured.NestedString(current.Object, "status", "phase"); Completed(phase) {
+		return fmt.Errorf("%w: workflow %s already finished with phase %s", ErrActionNotAllowed, name, phase)
+	}
+	patch := []byte(`{"spec":{"shutdown":"Stop"}}`)
+	_, err = workflows.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// Delete removes a workflow and, through their owner references, its pods
+func (s *Service) Delete(ctx context.Context, namespace, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	return s.client.Resource(Resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package argoworkflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newWorkflow(name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("ml")
+	obj.SetName(name)
+	return obj
+}
+
+func newFakeService(objects ...runtime.Object) *Service {
+	listKinds := map[schema.GroupVersionResource]string{Resource: "WorkflowList"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+	return NewService(client, "member1")
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 10, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		spec, status map[string]interface{}
+		wantActions  []string
+		wantDuration int64
+		wantStopping bool
+	}{
+		{
+			name:         "running",
+			status:       map[string]interface{}{"phase": PhaseRunning, "startedAt": "2024-05-01T12:00:00Z"},
+			wantActions:  []string{ActionStop, ActionDelete},
+			wantDuration: 600,
+		},
+		{
+			name:         "stopping",
+			spec:         map[string]interface{}{"shutdown": "Stop"},
+			status:       map[string]interface{}{"phase": PhaseRunning, "startedAt": "2024-05-01T12:00:00Z"},
+			wantActions:  []string{ActionDelete},
+			wantDuration: 600,
+			wantStopping: true,
+		},
+		{
+			name:         "failed",
+			status:       map[string]interface{}{"phase": PhaseFailed, "startedAt": "2024-05-01T11:00:00Z", "finishedAt": "2024-05-01T11:01:30Z"},
+			wantActions:  []string{ActionRetry, ActionDelete},
+			wantDuration: 90,
+		},
+		{
+			name:         "succeeded",
+			spec:         map[string]interface{}{"shutdown": "Stop"},
+			status:       map[string]interface{}{"phase": PhaseSucceeded, "startedAt": "2024-05-01T11:00:00Z", "finishedAt": "2024-05-01T11:00:05Z"},
+			wantActions:  []string{ActionDelete},
+			wantDuration: 5,
+		},
+		{
+			name:        "not started",
+			wantActions: []string{ActionStop, ActionDelete},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Summarize(newWorkflow("train", tt.spec, tt.status), "member1", now)
+			if !reflect.DeepEqual(got.Actions, tt.wantActions) {
+				t.Errorf("actions = %v, want %v", got.Actions, tt.wantActions)
+			}
+			if got.Duration != tt.wantDuration {
+				t.Errorf("duration = %d, want %d", got.Duration, tt.wantDuration)
+			}
+			if got.Stopping != tt.wantStopping {
+				t.Errorf("stopping = %v, want %v", got.Stopping, tt.wantStopping)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	failed := newWorkflow("train", map[string]interface{}{"entrypoint": "main", "shutdown": "Stop"}, map[string]interface{}{"phase": PhaseFailed})
+	failed.SetLabels(map[string]string{"team": "ml", "workflows.argoproj.io/completed": "true"})
+	running := newWorkflow("serve", map[string]interface{}{"entrypoint": "main"}, map[string]interface{}{"phase": PhaseRunning})
+	s := newFakeService(failed, running)
+
+	if _, err := s.Retry(context.TODO(), "ml", "serve"); !errors.Is(err, ErrActionNotAllowed) {
+		t.Errorf("Retry() of a running workflow error = %v, want ErrActionNotAllowed", err)
+	}
+
+	retry, err := s.Retry(context.TODO(), "ml", "train")
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if retry.GetGenerateName() != "train-" {
+		t.Errorf("generateName = %q, want train-", retry.GetGenerateName())
+	}
+	wantLabels := map[string]string{"team": "ml", ResubmittedFromLabel: "train"}
+	if !reflect.DeepEqual(retry.GetLabels(), wantLabels) {
+		t.Errorf("labels = %v, want %v", retry.GetLabels(), wantLabels)
+	}
+	if _, found, _ := unstructured.NestedString(retry.Object, "spec", "shutdown"); found {
+		t.Error("retry kept the shutdown of the failed workflow")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(retry.Object, "status"); found {
+		t.Error("retry kept the status of the failed workflow")
+	}
+}
+
+func TestStop(t *testing.T) {
+	running := newWorkflow("train", map[string]interface{}{"entrypoint": "main"}, map[string]interface{}{"phase": PhaseRunning})
+	succeeded := newWorkflow("done", map[string]interface{}{"entrypoint": "main"}, map[string]interface{}{"phase": PhaseSucceeded})
+	s := newFakeService(running, succeeded)
+
+	if err := s.Stop(context.TODO(), "ml", "done"); !errors.Is(err, ErrActionNotAllowed) {
+		t.Errorf("Stop() of a finished workflow error = %v, want ErrActionNotAllowed", err)
+	}
+	if err := s.Stop(context.TODO(), "ml", "train"); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	stopped, err := s.Get(context.TODO(), "ml", "train")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shutdown, _, _ := unstructured.NestedString(stopped.Object, "spec", "shutdown"); shutdown != "Stop" {
+		t.Errorf("shutdown = %q, want Stop", shutdown)
+	}
+	if entrypoint, _, _ := unstructured.NestedString(stopped.Object, "spec", "entrypoint"); entrypoint != "main" {
+		t.Errorf("Stop() changed the entrypoint to %q", entrypoint)
+	}
+
+	workflows, err := s.List(context.TODO(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workflows) != 2 || workflows[0].Cluster != "member1" {
+		t.Errorf("List() = %v", workflows)
+	}
+}
+
+func TestListPipelineRuns(t *testing.T) {
+	run := newWorkflow("training-pipeline-x7k2p", nil, map[string]interface{}{"phase": PhaseRunning})
+	run.SetLabels(map[string]string{PipelineRunIDLabel: "0b1c", RecurringRunLabel: "nightly"})
+	run.SetAnnotations(map[string]string{PipelineRunNameAnnotation: "nightly training"})
+	v2 := newWorkflow("eval-pipeline-9xq4d", nil, map[string]interface{}{"phase": PhaseError})
+	v2.SetAnnotations(map[string]string{"pipelines.kubeflow.org/v2_pipeline": "true"})
+	plain := newWorkflow("etl", nil, map[string]interface{}{"phase": PhaseRunning})
+	s := newFakeService(run, v2, plain)
+
+	runs, err := s.ListPipelineRuns(context.TODO(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("ListPipelineRuns() returned %d runs, want 2 without the plain workflow", len(runs))
+	}
+	byName := map[string]PipelineRun{}
+	for _, r := range runs {
+		byName[r.Name] = r
+	}
+	if got := byName["training-pipeline-x7k2p"]; got.RunID != "0b1c" || got.RunName != "nightly training" || got.RecurringRun != "nightly" {
+		t.Errorf("run = %+v", got)
+	}
+	if got := byName["eval-pipeline-9xq4d"]; got.RunName != "eval-pipeline-9xq4d" {
+		t.Errorf("run name = %q, want the workflow name", got.RunName)
+	}
+
+	load := CountPipelineLoad("member1", runs)
+	if want := (PipelineLoad{Cluster: "member1", Total: 2, Running: 1, Failed: 1}); load != want {
+		t.Errorf("CountPipelineLoad() = %+v, want %+v", load, want)
+	}
+}
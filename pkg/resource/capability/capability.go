@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capability detects which API versions of the optional and version dependent resources the
+// dashboard uses each member cluster serves, so callers pick a served GVR instead of hard-coding one.
+package capability
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// DefaultTTL is how long the capabilities of a cluster are reused before they are detected again
+const DefaultTTL = 10 * time.Minute
+
+// Kinds whose API version differs between clusters or which are not installed everywhere
+const (
+	KindIngress                 = "Ingress"
+	KindCronJob                 = "CronJob"
+	KindHorizontalPodAutoscaler = "HorizontalPodAutoscaler"
+	KindPodDisruptionBudget     = "PodDisruptionBudget"
+	KindVolumeSnapshot          = "VolumeSnapshot"
+	KindVolumeSnapshotContent   = "VolumeSnapshotContent"
+	KindCheckpointBackup        = "CheckpointBackup"
+	KindCheckpointRestore       = "CheckpointRestore"
+	KindWorkflow                = "Workflow"
+	KindApplication             = "Application"
+)
+
+// candidates are the GVRs of each kind, preferred first
+var candidates = []struct {
+	kind string
+	gvrs []schema.GroupVersionResource
+}{
+	{KindIngress, []schema.GroupVersionResource{
+		{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+		{Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses"},
+		{Group: "extensions", Version: "v1beta1", Resource: "ingresses"},
+	}},
+	{KindCronJob, []schema.GroupVersionResource{
+		{Group: "batch", Version: "v1", Resource: "cronjobs"},
+		{Group: "batch", Version: "v1beta1", Resource: "cronjobs"},
+	}},
+	{KindHorizontalPodAutoscaler, []schema.GroupVersionResource{
+		{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"},
+		{Group: "autoscaling", Version: "v2beta2", Resource: "horizontalpodautoscalers"},
+		{Group: "autoscaling", Version: "v1", Resource: "horizontalpodautoscalers"},
+	}},
+	{KindPodDisruptionBudget, []schema.GroupVersionResource{
+		{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"},
+		{Group: "policy", Version: "v1beta1", Resource: "poddisruptionbudgets"},
+	}},
+	{KindVolumeSnapshot, []schema.GroupVersionResource{
+		{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"},
+		{Group: "snapshot.storage.k8s.io", Version: "v1beta1", Resource: "volumesnapshots"},
+	}},
+	{KindVolumeSnapshotContent, []schema.GroupVersionResource{
+		{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshotcontents"},
+		{Group: "snapshot.storage.k8s.io", Version: "v1beta1", Resource: "volumesnapshotcontents"},
+	}},
+	{KindCheckpointBackup, []schema.GroupVersionResource{
+		{Group: "migration.dcnlab.com", Version: "v1", Resource: "checkpointbackups"},
+	}},
+	{KindCheckpointRestore, []schema.GroupVersionResource{
+		{Group: "migration.dcnlab.com", Version: "v1", Resource: "checkpointrestores"},
+	}},
+	{KindWorkflow, []schema.GroupVersionResource{
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"},
+	}},
+	{KindApplication, []schema.GroupVersionResource{
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+	}},
+}
+
+// Resource is the served version of a kind in a cluster
+type Resource struct {
+	Kind      string `json:"kind"`
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Available bool   `json:"available"`
+}
+
+// Capabilities are the Kubernetes version of a cluster and the versions of the kinds it serves
+type Capabilities struct {
+	Cluster           string     `json:"cluster"`
+	KubernetesVersion string     `json:"kubernetesVersion"`
+	Resources         []Resource `json:"resources"`
+	// Partial is set when some API groups could not be discovered, their kinds are reported unavailable
+	Partial    bool   `json:"partial,omitempty"`
+	DetectedAt string `json:"detectedAt"`
+}
+
+// GVR returns the served GVR of a kind
+func (c *Capabilities) GVR(kind string) (schema.GroupVersionResource, bool) {
+	for _, resource := range c.Resources {
+		if resource.Kind == kind && resource.Available {
+			return schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource}, true
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// Supports reports whether the cluster serves a kind
+func (c *Capabilities) Supports(kind string) bool {
+	_, ok := c.GVR(kind)
+	return ok
+}
+
+// DefaultGVR returns the preferred GVR of a kind, used when the capabilities of a cluster are unknown
+func DefaultGVR(kind string) (schema.GroupVersionResource, bool) {
+	for _, candidate := range candidates {
+		if candidate.kind == kind {
+			return candidate.gvrs[0], true
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// Detect discovers the Kubernetes version and the served resources of a cluster
+func Detect(discoveryClient discovery.DiscoveryInterface) (*Capabilities, error) {
+	version, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	capabilities := &Capabilities{
+		KubernetesVersion: version.GitVersion,
+		DetectedAt:        time.Now().Format(time.RFC3339),
+	}
+
+	_, resourceLists, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, fmt.Errorf("failed to discover resources: %w", err)
+		}
+		// Aggregated APIs that are down do not hide the rest of the cluster
+		capabilities.Partial = true
+	}
+	served := map[schema.GroupVersionResource]bool{}
+	for _, list := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			served[groupVersion.WithResource(resource.Name)] = true
+		}
+	}
+
+	for _, candidate := range candidates {
+		resource := Resource{Kind: candidate.kind}
+		for _, gvr := range candidate.gvrs {
+			if served[gvr] {
+				resource.Group, resource.Version, resource.Resource, resource.Available = gvr.Group, gvr.Version, gvr.Resource, true
+				break
+			}
+		}
+		capabilities.Resources = append(capabilities.Resources, resource)
+	}
+	return capabilities, nil
+}
+
+// DetectFunc detects the capabilities of a member cluster
+type DetectFunc func(ctx context.Context, clusterName string) (*Capabilities, error)
+
+type cacheEntry struct {
+	capabilities *Capabilities
+	expires      time.Time
+}
+
+// Cache keeps the capabilities of each member cluster for a TTL. Failed detections are not cached.
+type Cache struct {
+	ttl     time.Duration
+	detect  DetectFunc
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewCache returns a cache that detects the capabilities of clusters with detect
+func NewCache(ttl time.Duration, detect DetectFunc) *Cache {
+	return &Cache{ttl: ttl, detect: detect, now: time.Now, entries: map[string]cacheEntry{}}
+}
+
+// Get returns the capabilities of a cluster, detecting them when they are not cached or expired
+func (c *Cache) Get(ctx context.Context, clusterName string) (*Capabilities, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[clusterName]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.capabilities, nil
+	}
+
+	capabilities, err := c.detect(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	capabilities.Cluster = clusterName
+	c.mu.Lock()
+	c.entries[clusterName] = cacheEntry{capabilities: capabilities, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return capabilities, nil
+}
+
+// Invalidate drops the capabilities of a cluster, so they are detected again on the next request
+func (c *Cache) Invalidate(clusterName string) {
+	c.mu.Lock()
+	delete(c.entries, clusterName)
+	c.mu.Unlock()
+}
+
+// detectMember detects the capabilities of a member cluster over its first working access path
+func detectMember(ctx context.Context, clusterName string) (*Capabilities, error) {
+	config, _, err := client.MemberConfig(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return Detect(discoveryClient)
+}
+
+var defaultCache = NewCache(DefaultTTL, detectMember)
+
+// For returns the capabilities of a member cluster
+func For(ctx context.Context, clusterName string) (*Capabilities, error) {
+	return defaultCache.Get(ctx, clusterName)
+}
+
+// Invalidate drops the cached capabilities of a member cluster
+func Invalidate(clusterName string) {
+	defaultCache.Invalidate(clusterName)
+}
+
+// ResourceFor returns the GVR of a kind served by a member cluster. When the capabilities cannot be
+// detected, or only partially, the preferred GVR is returned so callers fail on the request itself;
+// the error is only returned when the cluster is known not to serve the kind.
+func ResourceFor(ctx context.Context, clusterName, kind string) (schema.GroupVersionResource, error) {
+	capabilities, err := For(ctx, clusterName)
+	if err != nil {
+		klog.V(4).InfoS("Using the preferred version of a kind", "cluster", clusterName, "kind", kind, "reason", err.Error())
+		gvr, _ := DefaultGVR(kind)
+		return gvr, nil
+	}
+	gvr, ok := capabilities.GVR(kind)
+	if !ok && capabilities.Partial {
+		// The group of the kind may be the one that failed discovery
+		gvr, _ = DefaultGVR(kind)
+		return gvr, nil
+	}
+	if !ok {
+		return schema.GroupVersionResource{}, fmt.Errorf("cluster %s does not serve %s", clusterName, kind)
+	}
+	return gvr, nil
+}
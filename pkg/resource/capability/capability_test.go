@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capability
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newDiscovery(gitVersion string, resources ...*metav1.APIResourceList) *fakediscovery.FakeDiscovery {
+	discoveryClient := kubefake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discoveryClient.FakedServerVersion = &version.Info{GitVersion: gitVersion}
+	discoveryClient.Resources = resources
+	return discoveryClient
+}
+
+func TestDetect(t *testing.T) {
+	discoveryClient := newDiscovery("v1.20.15",
+		&metav1.APIResourceList{GroupVersion: "networking.k8s.io/v1beta1", APIResources: []metav1.APIResource{{Name: "ingresses"}}},
+		&metav1.APIResourceList{GroupVersion: "extensions/v1beta1", APIResources: []metav1.APIResource{{Name: "ingresses"}}},
+		&metav1.APIResourceList{GroupVersion: "batch/v1beta1", APIResources: []metav1.APIResource{{Name: "cronjobs"}}},
+		&metav1.APIResourceList{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{{Name: "jobs"}}},
+	)
+	capabilities, err := Detect(discoveryClient)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if capabilities.KubernetesVersion != "v1.20.15" {
+		t.Errorf("version = %s, want v1.20.15", capabilities.KubernetesVersion)
+	}
+
+	ingress, ok := capabilities.GVR(KindIngress)
+	if want := (schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1beta1", Resource: "ingresses"}); !ok || ingress != want {
+		t.Errorf("ingress = %v, %v, want %v", ingress, ok, want)
+	}
+	if cronJob, _ := capabilities.GVR(KindCronJob); cronJob.Version != "v1beta1" {
+		t.Errorf("cronjob version = %s, want v1beta1", cronJob.Version)
+	}
+	if capabilities.Supports(KindVolumeSnapshot) {
+		t.Error("Supports(VolumeSnapshot) = true without the snapshot CRDs")
+	}
+	if len(capabilities.Resources) != len(candidates) {
+		t.Errorf("detected %d kinds, want %d", len(capabilities.Resources), len(candidates))
+	}
+}
+
+func TestDefaultGVR(t *testing.T) {
+	if gvr, ok := DefaultGVR(KindVolumeSnapshot); !ok || gvr.Version != "v1" {
+		t.Errorf("DefaultGVR(VolumeSnapshot) = %v, %v", gvr, ok)
+	}
+	if _, ok := DefaultGVR("Unknown"); ok {
+		t.Error("DefaultGVR(Unknown) found a GVR")
+	}
+}
+
+func TestCache(t *testing.T) {
+	detections := 0
+	fail := false
+	cache := NewCache(time.Minute, func(ctx context.Context, clusterName string) (*Capabilities, error) {
+		detections++
+		if fail {
+			return nil, errors.New("cluster unreachable")
+		}
+		return &Capabilities{KubernetesVersion: "v1.29.0"}, nil
+	})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	capabilities, err := cache.Get(context.Background(), "member1")
+	if err != nil || capabilities.Cluster != "member1" {
+		t.Fatalf("Get() = %+v, %v", capabilities, err)
+	}
+	if _, err := cache.Get(context.Background(), "member1"); err != nil || detections != 1 {
+		t.Errorf("cached Get() detected again, %d detections", detections)
+	}
+
+	now = now.Add(2 * time.Minute)
+	fail = true
+	if _, err := cache.Get(context.Background(), "member1"); err == nil || detections != 2 {
+		t.Errorf("expired Get() = %v after %d detections, want a new failed detection", err, detections)
+	}
+	fail = false
+	if _, err := cache.Get(context.Background(), "member1"); err != nil || detections != 3 {
+		t.Errorf("Get() after a failure = %v after %d detections, want a new detection", err, detections)
+	}
+	cache.Invalidate("member1")
+	if _, err := cache.Get(context.Background(), "member1"); err != nil || detections != 4 {
+		t.Errorf("Get() after Invalidate() made %d detections, want 4", detections)
+	}
+}
@@ -44,6 +44,10 @@ type Cluster struct {
 	SyncMode           v1alpha1.ClusterSyncMode  `json:"syncMode"`
 	NodeSummary        *v1alpha1.NodeSummary     `json:"nodeSummary,omitempty"`
 	AllocatedResources ClusterAllocatedResources `json:"allocatedResources"`
+	// Maintenance is set while the cluster is in maintenance mode
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+	// Cordon is set while the cluster is cordoned from new backup configurations and migrations
+	Cordon *Cordon `json:"cordon,omitempty"`
 }
 
 // ClusterList contains a list of clusters.
@@ -57,6 +61,25 @@ type ClusterList struct {
 // GetClusterList returns a list of clusters that the user has permission to access.
 // If username is empty, all clusters are returned.
 func GetClusterList(client karmadaclientset.Interface, dsQuery *dataselect.DataSelectQuery, username ...string) (*ClusterList, error) {
+	// Extract username if provided, otherwise use empty string
+	user := ""
+	if len(username) > 0 && username[0] != "" {
+		user = username[0]
+	}
+	return getClusterList(client, dsQuery, user, nil)
+}
+
+// GetClusterListInProject returns the clusters of a project that the user has permission to access.
+func GetClusterListInProject(client karmadaclientset.Interface, dsQuery *dataselect.DataSelectQuery, username string, projectClusters []string) (*ClusterList, error) {
+	inProject := make(map[string]bool, len(projectClusters))
+	for _, name := range projectClusters {
+		inProject[name] = true
+	}
+	return getClusterList(client, dsQuery, username, inProject)
+}
+
+// getClusterList lists the clusters the user may access, restricted to the given cluster names when not nil
+func getClusterList(client karmadaclientset.Interface, dsQuery *dataselect.DataSelectQuery, user string, only map[string]bool) (*ClusterList, error) {
 	// Handle nil client to prevent panic
 	if client == nil {
 		return nil, fmt.Errorf("karmada client is nil")
@@ -68,11 +91,14 @@ func GetClusterList(client karmadaclientset.Interface, dsQuery *dataselect.DataS
 	if criticalError != nil {
 		return nil, criticalError
 	}
-
-	// Extract username if provided, otherwise use empty string
-	user := ""
-	if len(username) > 0 && username[0] != "" {
-		user = username[0]
+	if only != nil {
+		selected := clusters.Items[:0]
+		for _, cluster := range clusters.Items {
+			if only[cluster.Name] {
+				selected = append(selected, cluster)
+			}
+		}
+		clusters.Items = selected
 	}
 
 	// If no username provided or username is empty, return all clusters
@@ -98,6 +124,11 @@ func GetClusterList(client karmadaclientset.Interface, dsQuery *dataselect.DataS
 		klog.ErrorS(err, "Failed to check if user is admin", "username", user)
 		// Continue with cluster-specific checks in case of error
 	} else if isAdmin {
+		// Projects are made of member clusters, so their lists leave the management cluster out
+		if only != nil {
+			return toClusterList(client, clusters.Items, nonCriticalErrors, dsQuery), nil
+		}
+
 		// For admin users, add a management cluster to the list
 		// Set reasonable node count values for the management cluster
 		// These should match what we'd expect in a production environment
@@ -228,6 +259,8 @@ func toCluster(cluster *v1alpha1.Cluster) Cluster {
 		AllocatedResources: allocatedResources,
 		SyncMode:           cluster.Spec.SyncMode,
 		NodeSummary:        cluster.Status.NodeSummary,
+		Maintenance:        MaintenanceOf(cluster),
+		Cordon:             CordonOf(cluster),
 	}
 }
 
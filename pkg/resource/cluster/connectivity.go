@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// connectivityProbes is the number of health requests the latency of a cluster is measured over
+const connectivityProbes = 3
+
+// Names of the checks of a connectivity test
+const (
+	CheckReachability = "reachability"
+	CheckVersion      = "version"
+	CheckPermissions  = "permissions"
+)
+
+// RequiredPermissions are the permissions the dashboard uses in member clusters
+var RequiredPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Resource: "nodes"},
+	{Verb: "list", Resource: "pods"},
+	{Verb: "get", Resource: "pods", Subresource: "log"},
+	{Verb: "create", Resource: "namespaces"},
+	{Verb: "create", Resource: "serviceaccounts"},
+	{Verb: "create", Resource: "secrets"},
+	{Verb: "list", Group: "apps", Resource: "statefulsets"},
+	{Verb: "create", Group: "apps", Resource: "daemonsets"},
+	{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"},
+	{Verb: "create", Group: "migration.dcnlab.com", Resource: "checkpointbackups"},
+}
+
+// ConnectivityCheck is the outcome of one check of a connectivity test
+type ConnectivityCheck struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	// LatencyMs is how long the check took
+	LatencyMs int64 `json:"latencyMs"`
+	// Denied lists the required permissions the dashboard is missing
+	Denied []string `json:"denied,omitempty"`
+}
+
+// ConnectivityReport is the result of a connectivity test of a member cluster
+type ConnectivityReport struct {
+	Cluster    string `json:"cluster"`
+	AccessPath string `json:"accessPath,omitempty"`
+	Success    bool   `json:"success"`
+	// Version is the Kubernetes version of the cluster
+	Version string `json:"version,omitempty"`
+	// LatencyMs is the average latency of the health requests, MaxLatencyMs the slowest of them
+	LatencyMs    int64               `json:"latencyMs"`
+	MaxLatencyMs int64               `json:"maxLatencyMs"`
+	Checks       []ConnectivityCheck `json:"checks"`
+}
+
+func (r *ConnectivityReport) record(check ConnectivityCheck) {
+	r.Checks = append(r.Checks, check)
+	if !check.Success {
+		r.Success = false
+	}
+}
+
+// FailConnectivity returns the report of a cluster whose API server could not be reached at all
+func FailConnectivity(clusterName string, err error) *ConnectivityReport {
+	report := &ConnectivityReport{Cluster: clusterName}
+	report.record(ConnectivityCheck{Name: CheckReachability, Message: err.Error()})
+	return report
+}
+
+// TestConnectivity checks that the API server of a member cluster answers, reports its version and
+// latency, and checks with SelfSubjectAccessReviews that the dashboard has the permissions it needs.
+// Checks after a failed reachability check are skipped.
+func TestConnectivity(ctx context.Context, clusterName string, kubeClient kubernetes.Interface) *ConnectivityReport {
+	report := &ConnectivityReport{Cluster: clusterName, Success: true}
+
+	reachability := ConnectivityCheck{Name: CheckReachability, Success: true}
+	var total time.Duration
+	for i := 0; i < connectivityProbes; i++ {
+		latency, err := probeHealth(ctx, kubeClient)
+		total += latency
+		if ms := latency.Milliseconds(); ms > report.MaxLatencyMs {
+			report.MaxLatencyMs = ms
+		}
+		if err != nil {
+			reachability.Success = false
+			reachability.Message = err.Error()
+			break
+		}
+	}
+	reachability.LatencyMs = total.Milliseconds()
+	report.LatencyMs = (total / connectivityProbes).Milliseconds()
+	report.record(reachability)
+	if !reachability.Success {
+		return report
+	}
+
+	start := time.Now()
+	version, err := kubeClient.Discovery().ServerVersion()
+	check := ConnectivityCheck{Name: CheckVersion, Success: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Message = err.Error()
+	} else {
+		report.Version = version.GitVersion
+		check.Message = version.GitVersion
+	}
+	report.record(check)
+
+	report.record(checkPermissions(ctx, kubeClient))
+	return report
+}
+
+// probeHealth requests the health endpoint of the API server, or its version when the client has no REST client
+func probeHealth(ctx context.Context, kubeClient kubernetes.Interface) (time.Duration, error) {
+	start := time.Now()
+	restClient := kubeClient.Discovery().RESTClient()
+	if restClient == nil {
+		_, err := kubeClient.Discovery().ServerVersion()
+		return time.Since(start), err
+	}
+	err := restClient.Get().AbsPath("/healthz").Do(ctx).Error()
+	return time.Since(start), err
+}
+
+// checkPermissions reviews each required permission of the dashboard in the cluster
+func checkPermissions(ctx context.Context, kubeClient kubernetes.Interface) ConnectivityCheck {
+	check := ConnectivityCheck{Name: CheckPermissions, Success: true}
+	start := time.Now()
+	for _, attributes := range RequiredPermissions {
+		attributes := attributes
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+		}
+		result, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			check.Success = false
+			check.Message = fmt.Sprintf("failed to review permissions: %v", err)
+			break
+		}
+		if !result.Status.Allowed {
+			check.Success = false
+			check.Denied = append(check.Denied, permissionString(attributes))
+		}
+	}
+	if len(check.Denied) > 0 {
+		check.Message = fmt.Sprintf("missing %d of %d required permissions", len(check.Denied), len(RequiredPermissions))
+	}
+	check.LatencyMs = time.Since(start).Milliseconds()
+	return check
+}
+
+// permissionString formats a permission as "<verb> <resource>[/<subresource>][.<group>]"
+func permissionString(attributes authorizationv1.ResourceAttributes) string {
+	resource := attributes.Resource
+	if attributes.Subresource != "" {
+		resource += "/" + attributes.Subresource
+	}
+	if attributes.Group != "" {
+		resource += "." + attributes.Group
+	}
+	return attributes.Verb + " " + resource
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newConnectivityClient(denied map[string]bool) *kubefake.Clientset {
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.29.2"}
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = !denied[permissionString(*review.Spec.ResourceAttributes)]
+		return true, review, nil
+	})
+	return kubeClient
+}
+
+func TestTestConnectivity(t *testing.T) {
+	report := TestConnectivity(context.Background(), "member1", newConnectivityClient(nil))
+	if !report.Success {
+		t.Fatalf("TestConnectivity() failed: %+v", report)
+	}
+	if report.Version != "v1.29.2" {
+		t.Errorf("version = %s, want v1.29.2", report.Version)
+	}
+	names := []string{}
+	for _, check := range report.Checks {
+		names = append(names, check.Name)
+	}
+	if len(names) != 3 || names[0] != CheckReachability || names[1] != CheckVersion || names[2] != CheckPermissions {
+		t.Errorf("checks = %v, want reachability, version and permissions", names)
+	}
+}
+
+func TestTestConnectivityDeniedPermissions(t *testing.T) {
+	denied := map[string]bool{"get pods/log": true, "create checkpointbackups.migration.dcnlab.com": true}
+	report := TestConnectivity(context.Background(), "member1", newConnectivityClient(denied))
+	if report.Success {
+		t.Fatal("TestConnectivity() succeeded with missing permissions")
+	}
+	permissions := report.Checks[len(report.Checks)-1]
+	if permissions.Name != CheckPermissions || permissions.Success || len(permissions.Denied) != 2 {
+		t.Errorf("permissions check = %+v, want the 2 denied permissions", permissions)
+	}
+}
+
+func TestFailConnectivity(t *testing.T) {
+	report := FailConnectivity("member1", context.DeadlineExceeded)
+	if report.Success || len(report.Checks) != 1 || report.Checks[0].Name != CheckReachability {
+		t.Errorf("FailConnectivity() = %+v, want a failed reachability check", report)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CordonAnnotation marks a cluster cordoned from new backup configurations and migrations, e.g. while it is
+// being decommissioned. Its value is the JSON encoded Cordon. Unlike maintenance, the existing backup
+// configurations of the cluster keep running.
+const CordonAnnotation = "ml-platform.io/cordoned"
+
+// Cordon describes why and since when a cluster is cordoned
+type Cordon struct {
+	Since  string `json:"since"`
+	By     string `json:"by,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CordonOf returns the cordon of a cluster, or nil when it is not cordoned
+func CordonOf(cluster *v1alpha1.Cluster) *Cordon {
+	value, ok := cluster.Annotations[CordonAnnotation]
+	if !ok {
+		return nil
+	}
+	cordon := &Cordon{}
+	// An annotation set by hand without details still cordons the cluster
+	_ = json.Unmarshal([]byte(value), cordon)
+	return cordon
+}
+
+// GetCordon returns the cordon of a cluster, or nil when it is not cordoned
+func GetCordon(ctx context.Context, client karmadaclientset.Interface, name string) (*Cordon, error) {
+	cluster, err := client.ClusterV1alpha1().Clusters().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return CordonOf(cluster), nil
+}
+
+// CordonCluster cordons a cluster. A cluster already cordoned keeps its original cordon.
+func CordonCluster(ctx context.Context, client karmadaclientset.Interface, name string, cordon Cordon) (*v1alpha1.Cluster, error) {
+	data, err := json.Marshal(cordon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cordon: %v", err)
+	}
+	return updateCluster(ctx, client, name, func(cluster *v1alpha1.Cluster) bool {
+		if CordonOf(cluster) != nil {
+			return false
+		}
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[CordonAnnotation] = string(data)
+		return true
+	})
+}
+
+// UncordonCluster removes the cordon of a cluster
+func UncordonCluster(ctx context.Context, client karmadaclientset.Interface, name string) (*v1alpha1.Cluster, error) {
+	return updateCluster(ctx, client, name, func(cluster *v1alpha1.Cluster) bool {
+		if _, ok := cluster.Annotations[CordonAnnotation]; !ok {
+			return false
+		}
+		delete(cluster.Annotations, CordonAnnotation)
+		return true
+	})
+}
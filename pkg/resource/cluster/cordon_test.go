@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadafake "github.com/karmada-io/karmada/pkg/generated/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCordon(t *testing.T) {
+	client := karmadafake.NewSimpleClientset(&v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}})
+	ctx := context.Background()
+
+	if _, err := CordonCluster(ctx, client, "member1", Cordon{Since: "2024-05-01T10:00:00Z", By: "alice", Reason: "decommission"}); err != nil {
+		t.Fatalf("CordonCluster() error = %v", err)
+	}
+	// Cordoning again keeps the original cordon
+	if _, err := CordonCluster(ctx, client, "member1", Cordon{Since: "2024-05-02T10:00:00Z", By: "bob"}); err != nil {
+		t.Fatalf("CordonCluster() error = %v", err)
+	}
+	cordon, err := GetCordon(ctx, client, "member1")
+	if err != nil {
+		t.Fatalf("GetCordon() error = %v", err)
+	}
+	if cordon == nil || cordon.By != "alice" || cordon.Reason != "decommission" {
+		t.Errorf("GetCordon() = %+v, want the cordon of alice", cordon)
+	}
+
+	cluster, err := UncordonCluster(ctx, client, "member1")
+	if err != nil {
+		t.Fatalf("UncordonCluster() error = %v", err)
+	}
+	if CordonOf(cluster) != nil {
+		t.Errorf("cluster after UncordonCluster() = %+v", cluster)
+	}
+
+	manual := &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{CordonAnnotation: "true"}}}
+	if CordonOf(manual) == nil {
+		t.Error("CordonOf() = nil for a cluster annotated by hand")
+	}
+}
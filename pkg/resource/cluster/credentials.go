@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// credentialRotationsConfigName stores the credential rotations of member clusters
+	credentialRotationsConfigName = "ml-platform-admin-credential-rotations"
+	credentialRotationsKey        = "rotations"
+	maxCredentialRotations        = 100
+)
+
+// tokenPollInterval and tokenTimeout bound the wait for the token controller to fill a new token secret
+var (
+	tokenPollInterval = time.Second
+	tokenTimeout      = 30 * time.Second
+)
+
+// CredentialRotation records a rotation of the credentials the control plane uses to access a member cluster
+type CredentialRotation struct {
+	Time    string `json:"time"`
+	User    string `json:"user"`
+	Cluster string `json:"cluster"`
+	// Secret is the token secret created in the member cluster
+	Secret  string `json:"secret,omitempty"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewServiceAccountToken creates a new token secret for a ServiceAccount of a member cluster and waits for
+// the token controller to fill it. The secret is named after the ServiceAccount and the time of the rotation.
+func NewServiceAccountToken(ctx context.Context, memberClient kubernetes.Interface, namespace, serviceAccount string, now time.Time) (*corev1.Secret, error) {
+	secrets := memberClient.CoreV1().Secrets(namespace)
+	secret, err := secrets.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        fmt.Sprintf("%s-%d", serviceAccount, now.Unix()),
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: serviceAccount},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token secret for service account %s/%s: %w", namespace, serviceAccount, err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, tokenPollInterval, tokenTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		secret = current
+		return len(secret.Data[corev1.ServiceAccountTokenKey]) > 0, nil
+	})
+	if err != nil {
+		return secret, fmt.Errorf("token secret %s/%s was not populated: %w", namespace, secret.Name, err)
+	}
+	return secret, nil
+}
+
+// DeleteServiceAccountTokens deletes the token secrets of a ServiceAccount except keep, which revokes their
+// tokens, and returns the names of the deleted secrets
+func DeleteServiceAccountTokens(ctx context.Context, memberClient kubernetes.Interface, namespace, serviceAccount, keep string) ([]string, error) {
+	secrets := memberClient.CoreV1().Secrets(namespace)
+	list, err := secrets.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	deleted := []string{}
+	for _, secret := range list.Items {
+		if secret.Type != corev1.SecretTypeServiceAccountToken || secret.Name == keep ||
+			secret.Annotations[corev1.ServiceAccountNameKey] != serviceAccount {
+			continue
+		}
+		if err := secrets.Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete token secret %s/%s: %w", namespace, secret.Name, err)
+		}
+		deleted = append(deleted, secret.Name)
+	}
+	return deleted, nil
+}
+
+// GetCredentialRotations returns the recorded credential rotations of a cluster, newest last. An empty
+// cluster name returns the rotations of all clusters.
+func GetCredentialRotations(ctx context.Context, k8sClient kubernetes.Interface, namespace, clusterName string) ([]CredentialRotation, error) {
+	rotations, err := loadCredentialRotations(ctx, k8sClient, namespace)
+	if err != nil || clusterName == "" {
+		return rotations, err
+	}
+	filtered := []CredentialRotation{}
+	for _, rotation := range rotations {
+		if rotation.Cluster == clusterName {
+			filtered = append(filtered, rotation)
+		}
+	}
+	return filtered, nil
+}
+
+// RecordCredentialRotation appends a rotation to the log, which keeps the last maxCredentialRotations rotations
+func RecordCredentialRotation(ctx context.Context, k8sClient kubernetes.Interface, namespace string, rotation CredentialRotation) error {
+	rotations, err := loadCredentialRotations(ctx, k8sClient, namespace)
+	if err != nil {
+		return err
+	}
+	rotations = append(rotations, rotation)
+	if len(rotations) > maxCredentialRotations {
+		rotations = rotations[len(rotations)-maxCredentialRotations:]
+	}
+	data, err := json.Marshal(rotations)
+	if err != nil {
+		return err
+	}
+
+	configMaps := k8sClient.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(ctx, credentialRotationsConfigName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      credentialRotationsConfigName,
+				Namespace: namespace,
+			},
+			Data: map[string]string{credentialRotationsKey: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[credentialRotationsKey] = string(data)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+func loadCredentialRotations(ctx context.Context, k8sClient kubernetes.Interface, namespace string) ([]CredentialRotation, error) {
+	cm, err := k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, credentialRotationsConfigName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []CredentialRotation{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rotations := []CredentialRotation{}
+	if data, ok := cm.Data[credentialRotationsKey]; ok {
+		if err := json.Unmarshal([]byte(data), &rotations); err != nil {
+			return nil, fmt.Errorf("failed to parse credential rotations: %v", err)
+		}
+	}
+	return rotations, nil
+}
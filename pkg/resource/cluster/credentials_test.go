@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func tokenSecret(name, serviceAccount string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "karmada-cluster",
+			Name:        name,
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: serviceAccount},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+}
+
+func TestRotateServiceAccountToken(t *testing.T) {
+	ctx := context.Background()
+	memberClient := kubefake.NewSimpleClientset(
+		tokenSecret("karmada-member1", "karmada-member1"),
+		tokenSecret("karmada-impersonator", "karmada-impersonator"),
+	)
+	// The fake clientset has no token controller, so new token secrets are filled when they are created
+	memberClient.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		secret := action.(k8stesting.CreateAction).GetObject().(*corev1.Secret)
+		secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("new-token")}
+		return false, nil, nil
+	})
+
+	secret, err := NewServiceAccountToken(ctx, memberClient, "karmada-cluster", "karmada-member1", time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewServiceAccountToken() error = %v", err)
+	}
+	if secret.Name != "karmada-member1-1700000000" || string(secret.Data[corev1.ServiceAccountTokenKey]) != "new-token" {
+		t.Errorf("NewServiceAccountToken() = %s with %v, want a filled secret named after the rotation", secret.Name, secret.Data)
+	}
+
+	deleted, err := DeleteServiceAccountTokens(ctx, memberClient, "karmada-cluster", "karmada-member1", secret.Name)
+	if err != nil {
+		t.Fatalf("DeleteServiceAccountTokens() error = %v", err)
+	}
+	if !reflect.DeepEqual(deleted, []string{"karmada-member1"}) {
+		t.Errorf("DeleteServiceAccountTokens() = %v, want only the old token of the service account", deleted)
+	}
+	list, _ := memberClient.CoreV1().Secrets("karmada-cluster").List(ctx, metav1.ListOptions{})
+	if len(list.Items) != 2 {
+		t.Errorf("%d secrets left, want the new token and the impersonator token", len(list.Items))
+	}
+}
+
+func TestNewServiceAccountTokenTimeout(t *testing.T) {
+	interval, timeout := tokenPollInterval, tokenTimeout
+	tokenPollInterval, tokenTimeout = time.Millisecond, 10*time.Millisecond
+	defer func() { tokenPollInterval, tokenTimeout = interval, timeout }()
+
+	secret, err := NewServiceAccountToken(context.Background(), kubefake.NewSimpleClientset(), "karmada-cluster", "karmada-member1", time.Now())
+	if err == nil {
+		t.Fatal("NewServiceAccountToken() error = nil for a token that is never filled")
+	}
+	if secret == nil {
+		t.Error("NewServiceAccountToken() did not return the created secret, so it cannot be cleaned up")
+	}
+}
+
+func TestCredentialRotations(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := kubefake.NewSimpleClientset()
+	rotations, err := GetCredentialRotations(ctx, k8sClient, "karmada-system", "")
+	if err != nil || len(rotations) != 0 {
+		t.Fatalf("GetCredentialRotations() without records = %v, %v", rotations, err)
+	}
+
+	for i := 0; i < maxCredentialRotations+1; i++ {
+		cluster := "member1"
+		if i == maxCredentialRotations {
+			cluster = "member2"
+		}
+		rotation := CredentialRotation{Cluster: cluster, User: "admin", Success: true}
+		if err := RecordCredentialRotation(ctx, k8sClient, "karmada-system", rotation); err != nil {
+			t.Fatalf("RecordCredentialRotation() error = %v", err)
+		}
+	}
+
+	all, err := GetCredentialRotations(ctx, k8sClient, "karmada-system", "")
+	if err != nil || len(all) != maxCredentialRotations {
+		t.Errorf("GetCredentialRotations() = %d rotations, %v, want the last %d", len(all), err, maxCredentialRotations)
+	}
+	member2, err := GetCredentialRotations(ctx, k8sClient, "karmada-system", "member2")
+	if err != nil || len(member2) != 1 || member2[0].Cluster != "member2" {
+		t.Errorf("GetCredentialRotations(member2) = %v, %v, want its one rotation", member2, err)
+	}
+}
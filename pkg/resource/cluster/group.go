@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterGroupLabel is the cluster label whose value assigns the cluster to a group.
+const ClusterGroupLabel = "cluster.ml-platform.io/group"
+
+// ClusterGroup is a set of clusters sharing the same group label value.
+type ClusterGroup struct {
+	Name          string   `json:"name"`
+	Clusters      []string `json:"clusters"`
+	ReadyClusters int      `json:"readyClusters"`
+}
+
+// GetClusterGroups returns all cluster groups, sorted by name.
+func GetClusterGroups(client karmadaclientset.Interface) ([]ClusterGroup, error) {
+	clusters, err := client.ClusterV1alpha1().Clusters().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: ClusterGroupLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*ClusterGroup)
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		name := cluster.Labels[ClusterGroupLabel]
+		if name == "" {
+			continue
+		}
+		group, ok := groups[name]
+		if !ok {
+			group = &ClusterGroup{Name: name, Clusters: []string{}}
+			groups[name] = group
+		}
+		group.Clusters = append(group.Clusters, cluster.Name)
+		if isClusterReady(cluster) {
+			group.ReadyClusters++
+		}
+	}
+
+	result := make([]ClusterGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.Clusters)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetClusterGroupMembers returns the names of the clusters in a group.
+func GetClusterGroupMembers(client karmadaclientset.Interface, group string) ([]string, error) {
+	clusters, err := client.ClusterV1alpha1().Clusters().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", ClusterGroupLabel, group),
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ResolveClusterNames returns the union of the explicit cluster names and the members of the groups.
+// It fails when a group has no clusters, so a typo does not silently target nothing.
+func ResolveClusterNames(client karmadaclientset.Interface, names []string, groups []string) ([]string, error) {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(names))
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	for _, name := range names {
+		add(name)
+	}
+	for _, group := range groups {
+		members, err := GetClusterGroupMembers(client, group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve cluster group %s: %w", group, err)
+		}
+		if len(members) == 0 {
+			return nil, fmt.Errorf("cluster group %s has no clusters", group)
+		}
+		for _, member := range members {
+			add(member)
+		}
+	}
+	return result, nil
+}
+
+func isClusterReady(cluster *v1alpha1.Cluster) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == v1alpha1.ClusterConditionReady {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+)
+
+// InventoryEntry is one cluster of the cluster inventory export
+type InventoryEntry struct {
+	Name                       string   `json:"name"`
+	KubernetesVersion          string   `json:"kubernetesVersion"`
+	NodeCount                  int32    `json:"nodeCount"`
+	ReadyNodeCount             int32    `json:"readyNodeCount"`
+	Ready                      bool     `json:"ready"`
+	SyncMode                   string   `json:"syncMode"`
+	MigrationControllerStatus  string   `json:"migrationControllerStatus"`
+	MigrationControllerVersion string   `json:"migrationControllerVersion"`
+	ArgoCD                     bool     `json:"argocd"`
+	Owners                     []string `json:"owners"`
+	// Errors lists the details that could not be read, e.g. because the cluster is unreachable
+	Errors []string `json:"errors,omitempty"`
+}
+
+// InventoryColumns are the header of the CSV inventory export
+var InventoryColumns = []string{
+	"name", "kubernetes_version", "node_count", "ready_node_count", "ready", "sync_mode",
+	"migration_controller_status", "migration_controller_version", "argocd", "owners", "errors",
+}
+
+// NewInventoryEntry returns the inventory entry of a cluster with the details reported in its status
+func NewInventoryEntry(cluster *v1alpha1.Cluster) InventoryEntry {
+	entry := InventoryEntry{
+		Name:              cluster.Name,
+		KubernetesVersion: cluster.Status.KubernetesVersion,
+		SyncMode:          string(cluster.Spec.SyncMode),
+		Owners:            []string{},
+	}
+	if summary := cluster.Status.NodeSummary; summary != nil {
+		entry.NodeCount = summary.TotalNum
+		entry.ReadyNodeCount = summary.ReadyNum
+	}
+	entry.Ready, _ = ReadyMessage(cluster)
+	return entry
+}
+
+// CSVRecord returns the entry as a row of the CSV inventory export, in the order of InventoryColumns.
+// Owners and errors are separated by semicolons.
+func (e InventoryEntry) CSVRecord() []string {
+	return []string{
+		e.Name,
+		e.KubernetesVersion,
+		strconv.FormatInt(int64(e.NodeCount), 10),
+		strconv.FormatInt(int64(e.ReadyNodeCount), 10),
+		strconv.FormatBool(e.Ready),
+		e.SyncMode,
+		e.MigrationControllerStatus,
+		e.MigrationControllerVersion,
+		strconv.FormatBool(e.ArgoCD),
+		strings.Join(e.Owners, ";"),
+		strings.Join(e.Errors, ";"),
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInventoryEntry(t *testing.T) {
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "member1"},
+		Spec:       v1alpha1.ClusterSpec{SyncMode: v1alpha1.Pull},
+		Status: v1alpha1.ClusterStatus{
+			KubernetesVersion: "v1.29.2",
+			NodeSummary:       &v1alpha1.NodeSummary{TotalNum: 3, ReadyNum: 2},
+			Conditions:        []metav1.Condition{{Type: v1alpha1.ClusterConditionReady, Status: metav1.ConditionTrue}},
+		},
+	}
+	entry := NewInventoryEntry(cluster)
+	entry.MigrationControllerStatus = "installed"
+	entry.MigrationControllerVersion = "v0.3.0"
+	entry.ArgoCD = true
+	entry.Owners = []string{"alice", "bob"}
+
+	want := []string{"member1", "v1.29.2", "3", "2", "true", "Pull", "installed", "v0.3.0", "true", "alice;bob", ""}
+	if got := entry.CSVRecord(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CSVRecord() = %v, want %v", got, want)
+	}
+	if len(want) != len(InventoryColumns) {
+		t.Errorf("CSVRecord() has %d fields, InventoryColumns %d", len(want), len(InventoryColumns))
+	}
+
+	entry = NewInventoryEntry(&v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member2"}})
+	if entry.Ready || entry.NodeCount != 0 || entry.Owners == nil {
+		t.Errorf("NewInventoryEntry() without status = %+v", entry)
+	}
+}
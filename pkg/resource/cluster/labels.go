@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// reservedDomains are label and taint key prefixes managed by Kubernetes and Karmada.
+// Keys under these domains, including their subdomains, cannot be changed through the dashboard.
+var reservedDomains = []string{"kubernetes.io", "k8s.io", "karmada.io"}
+
+// wellKnownKeys are keys under reserved domains that users may still set on clusters.
+var wellKnownKeys = map[string]bool{
+	"topology.kubernetes.io/region": true,
+	"topology.kubernetes.io/zone":   true,
+}
+
+// managedKeys are keys outside the reserved domains that the dashboard manages itself, like the maintenance taint.
+var managedKeys = map[string]bool{
+	MaintenanceTaintKey: true,
+}
+
+// IsReservedKey reports whether a label or taint key belongs to a reserved domain or is managed by the dashboard.
+func IsReservedKey(key string) bool {
+	if managedKeys[key] {
+		return true
+	}
+	if wellKnownKeys[key] {
+		return false
+	}
+	slash := strings.Index(key, "/")
+	if slash < 0 {
+		return false
+	}
+	domain := key[:slash]
+	for _, reserved := range reservedDomains {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateLabels checks the syntax of the labels and rejects changes to reserved keys.
+// A reserved key is accepted when it is unchanged from existing, so the full label set can be sent back.
+func ValidateLabels(labels, existing map[string]string) error {
+	var errs []string
+	for key, value := range labels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Sprintf("label key %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, fmt.Sprintf("label %q value %q: %s", key, value, msg))
+		}
+		if IsReservedKey(key) {
+			if current, ok := existing[key]; !ok || current != value {
+				errs = append(errs, fmt.Sprintf("label key %q uses a reserved prefix", key))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid labels: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateTaints checks the syntax and effect of the taints and rejects changes to reserved keys.
+// A reserved taint is accepted when an identical taint already exists.
+func ValidateTaints(taints, existing []corev1.Taint) error {
+	var errs []string
+	seen := make(map[string]bool)
+	for _, taint := range taints {
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			errs = append(errs, fmt.Sprintf("taint key %q: %s", taint.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			errs = append(errs, fmt.Sprintf("taint %q value %q: %s", taint.Key, taint.Value, msg))
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			errs = append(errs, fmt.Sprintf("taint %q has unsupported effect %q", taint.Key, taint.Effect))
+		}
+		id := taint.Key + ":" + string(taint.Effect)
+		if seen[id] {
+			errs = append(errs, fmt.Sprintf("taint %q with effect %q is duplicated", taint.Key, taint.Effect))
+		}
+		seen[id] = true
+		if IsReservedKey(taint.Key) && !containsTaint(existing, taint) {
+			errs = append(errs, fmt.Sprintf("taint key %q uses a reserved prefix", taint.Key))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid taints: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func containsTaint(taints []corev1.Taint, taint corev1.Taint) bool {
+	for _, t := range taints {
+		if t.Key == taint.Key && t.Value == taint.Value && t.Effect == taint.Effect {
+			return true
+		}
+	}
+	return false
+}
+
+// PreserveReservedLabels copies reserved labels of existing that are missing from labels,
+// so a full replacement does not drop labels managed by Karmada.
+func PreserveReservedLabels(labels, existing map[string]string) map[string]string {
+	for key, value := range existing {
+		if _, ok := labels[key]; !ok && IsReservedKey(key) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// PreserveReservedTaints appends reserved taints of existing that are missing from taints,
+// so a full replacement does not drop taints managed by Karmada.
+func PreserveReservedTaints(taints, existing []corev1.Taint) []corev1.Taint {
+	for _, taint := range existing {
+		if IsReservedKey(taint.Key) && !containsTaint(taints, taint) {
+			taints = append(taints, taint)
+		}
+	}
+	return taints
+}
+
+// MergeLabels applies set and remove to the current labels and returns the result.
+func MergeLabels(current, set map[string]string, remove []string) map[string]string {
+	merged := make(map[string]string, len(current)+len(set))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for _, key := range remove {
+		delete(merged, key)
+	}
+	for key, value := range set {
+		merged[key] = value
+	}
+	return merged
+}
+
+// MergeTaints applies set and remove to the current taints and returns the result.
+// Taints are identified by key and effect; a removal without effect matches every effect of the key.
+func MergeTaints(current, set, remove []corev1.Taint) []corev1.Taint {
+	merged := make([]corev1.Taint, 0, len(current)+len(set))
+	for _, taint := range current {
+		if matchesTaint(remove, taint) || matchesTaint(set, taint) {
+			continue
+		}
+		merged = append(merged, taint)
+	}
+	return append(merged, set...)
+}
+
+func matchesTaint(taints []corev1.Taint, taint corev1.Taint) bool {
+	for _, t := range taints {
+		if t.Key == taint.Key && (t.Effect == "" || t.Effect == taint.Effect) {
+			return true
+		}
+	}
+	return false
+}
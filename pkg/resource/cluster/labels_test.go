@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIsReservedKey(t *testing.T) {
+	cases := map[string]bool{
+		"team":                          false,
+		"cluster.ml-platform.io/group":  false,
+		"topology.kubernetes.io/region": false,
+		"kubernetes.io/hostname":        true,
+		"node.kubernetes.io/role":       true,
+		"cluster.karmada.io/not-ready":  true,
+		"karmada.io/managed":            true,
+		"notkarmada.io/x":               false,
+		"ml-platform.io/maintenance":    true,
+	}
+	for key, expected := range cases {
+		if actual := IsReservedKey(key); actual != expected {
+			t.Errorf("IsReservedKey(%q) == %v, expected %v", key, actual, expected)
+		}
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	existing := map[string]string{"karmada.io/managed": "true"}
+	cases := []struct {
+		labels  map[string]string
+		wantErr bool
+	}{
+		{map[string]string{"team": "ml"}, false},
+		{map[string]string{"karmada.io/managed": "true"}, false},
+		{map[string]string{"karmada.io/managed": "false"}, true},
+		{map[string]string{"kubernetes.io/new": "x"}, true},
+		{map[string]string{"bad key": "x"}, true},
+		{map[string]string{"team": "not a valid value"}, true},
+	}
+	for _, c := range cases {
+		err := ValidateLabels(c.labels, existing)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ValidateLabels(%v) error = %v, wantErr %v", c.labels, err, c.wantErr)
+		}
+	}
+}
+
+func TestValidateTaints(t *testing.T) {
+	cases := []struct {
+		taints  []corev1.Taint
+		wantErr bool
+	}{
+		{[]corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}, false},
+		{[]corev1.Taint{{Key: "gpu", Effect: "Sometimes"}}, true},
+		{[]corev1.Taint{{Key: "cluster.karmada.io/not-ready", Effect: corev1.TaintEffectNoSchedule}}, true},
+		{[]corev1.Taint{
+			{Key: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "gpu", Value: "x", Effect: corev1.TaintEffectNoSchedule},
+		}, true},
+	}
+	for _, c := range cases {
+		err := ValidateTaints(c.taints, nil)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ValidateTaints(%v) error = %v, wantErr %v", c.taints, err, c.wantErr)
+		}
+	}
+}
+
+func TestMergeTaints(t *testing.T) {
+	current := []corev1.Taint{
+		{Key: "gpu", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: corev1.TaintEffectNoExecute},
+	}
+	set := []corev1.Taint{{Key: "gpu", Value: "b", Effect: corev1.TaintEffectNoSchedule}}
+	remove := []corev1.Taint{{Key: "spot"}}
+	expected := []corev1.Taint{{Key: "gpu", Value: "b", Effect: corev1.TaintEffectNoSchedule}}
+
+	if actual := MergeTaints(current, set, remove); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("MergeTaints() == %v, expected %v", actual, expected)
+	}
+}
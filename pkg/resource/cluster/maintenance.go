@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// MaintenanceAnnotation marks a cluster in maintenance mode. Its value is the JSON encoded Maintenance,
+	// subsystems that start operations on clusters skip or reject the clusters that have it.
+	MaintenanceAnnotation = "ml-platform.io/maintenance"
+	// MaintenanceTaintKey is the NoSchedule taint of a cluster in maintenance, so Karmada stops scheduling
+	// new and rescheduled workloads to it while the ones already propagated keep running
+	MaintenanceTaintKey = "ml-platform.io/maintenance"
+)
+
+// Maintenance describes why and since when a cluster is in maintenance mode
+type Maintenance struct {
+	Since  string `json:"since"`
+	By     string `json:"by,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// MaintenanceOf returns the maintenance of a cluster, or nil when it is not in maintenance mode
+func MaintenanceOf(cluster *v1alpha1.Cluster) *Maintenance {
+	value, ok := cluster.Annotations[MaintenanceAnnotation]
+	if !ok {
+		return nil
+	}
+	maintenance := &Maintenance{}
+	// An annotation set by hand without details still puts the cluster in maintenance
+	_ = json.Unmarshal([]byte(value), maintenance)
+	return maintenance
+}
+
+// GetMaintenance returns the maintenance of a cluster, or nil when it is not in maintenance mode
+func GetMaintenance(ctx context.Context, client karmadaclientset.Interface, name string) (*Maintenance, error) {
+	cluster, err := client.ClusterV1alpha1().Clusters().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return MaintenanceOf(cluster), nil
+}
+
+// maintenanceTaint is the taint of a cluster in maintenance
+var maintenanceTaint = corev1.Taint{Key: MaintenanceTaintKey, Effect: corev1.TaintEffectNoSchedule}
+
+// EnterMaintenance puts a cluster in maintenance mode: it is annotated and tainted so nothing new is scheduled
+// to it. A cluster already in maintenance keeps its original maintenance.
+func EnterMaintenance(ctx context.Context, client karmadaclientset.Interface, name string, maintenance Maintenance) (*v1alpha1.Cluster, error) {
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode maintenance: %v", err)
+	}
+	return updateCluster(ctx, client, name, func(cluster *v1alpha1.Cluster) bool {
+		if MaintenanceOf(cluster) != nil {
+			return false
+		}
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[MaintenanceAnnotation] = string(data)
+		cluster.Spec.Taints = MergeTaints(cluster.Spec.Taints, []corev1.Taint{maintenanceTaint}, nil)
+		return true
+	})
+}
+
+// ExitMaintenance takes a cluster out of maintenance mode, removing its annotation and taint
+func ExitMaintenance(ctx context.Context, client karmadaclientset.Interface, name string) (*v1alpha1.Cluster, error) {
+	return updateCluster(ctx, client, name, func(cluster *v1alpha1.Cluster) bool {
+		_, annotated := cluster.Annotations[MaintenanceAnnotation]
+		if !annotated && !matchesTaint(cluster.Spec.Taints, maintenanceTaint) {
+			return false
+		}
+		delete(cluster.Annotations, MaintenanceAnnotation)
+		cluster.Spec.Taints = MergeTaints(cluster.Spec.Taints, nil, []corev1.Taint{{Key: MaintenanceTaintKey}})
+		return true
+	})
+}
+
+// updateCluster changes a cluster, retrying on conflicts. change reports whether the cluster has to be updated.
+func updateCluster(ctx context.Context, client karmadaclientset.Interface, name string, change func(cluster *v1alpha1.Cluster) bool) (*v1alpha1.Cluster, error) {
+	var updated *v1alpha1.Cluster
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster, err := client.ClusterV1alpha1().Clusters().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !change(cluster) {
+			updated = cluster
+			return nil
+		}
+		updated, err = client.ClusterV1alpha1().Clusters().Update(ctx, cluster, metav1.UpdateOptions{})
+		return err
+	})
+	return updated, err
+}
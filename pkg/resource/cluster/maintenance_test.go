@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadafake "github.com/karmada-io/karmada/pkg/generated/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMaintenance(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	client := karmadafake.NewSimpleClientset(&v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "member1"},
+		Spec:       v1alpha1.ClusterSpec{Taints: []corev1.Taint{gpuTaint}},
+	})
+	ctx := context.Background()
+
+	cluster, err := EnterMaintenance(ctx, client, "member1", Maintenance{Since: "2024-05-01T10:00:00Z", By: "alice", Reason: "upgrade"})
+	if err != nil {
+		t.Fatalf("EnterMaintenance() error = %v", err)
+	}
+	if len(cluster.Spec.Taints) != 2 || !matchesTaint(cluster.Spec.Taints, maintenanceTaint) {
+		t.Errorf("taints = %v, want the gpu and maintenance taints", cluster.Spec.Taints)
+	}
+
+	// Entering again keeps the original maintenance
+	if _, err := EnterMaintenance(ctx, client, "member1", Maintenance{Since: "2024-05-02T10:00:00Z", By: "bob"}); err != nil {
+		t.Fatalf("EnterMaintenance() error = %v", err)
+	}
+	maintenance, err := GetMaintenance(ctx, client, "member1")
+	if err != nil {
+		t.Fatalf("GetMaintenance() error = %v", err)
+	}
+	if maintenance == nil || maintenance.By != "alice" || maintenance.Reason != "upgrade" {
+		t.Errorf("GetMaintenance() = %+v, want the maintenance of alice", maintenance)
+	}
+
+	cluster, err = ExitMaintenance(ctx, client, "member1")
+	if err != nil {
+		t.Fatalf("ExitMaintenance() error = %v", err)
+	}
+	if MaintenanceOf(cluster) != nil || len(cluster.Spec.Taints) != 1 || cluster.Spec.Taints[0] != gpuTaint {
+		t.Errorf("cluster after ExitMaintenance() = %+v", cluster)
+	}
+}
+
+func TestMaintenanceOfManualAnnotation(t *testing.T) {
+	cluster := &v1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{MaintenanceAnnotation: "true"}}}
+	if MaintenanceOf(cluster) == nil {
+		t.Error("MaintenanceOf() = nil for a cluster annotated by hand")
+	}
+	if MaintenanceOf(&v1alpha1.Cluster{}) != nil {
+		t.Error("MaintenanceOf() != nil for a cluster without the annotation")
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+)
+
+// Steps of the onboarding checklist of a member cluster
+const (
+	OnboardingJoined              = "joined"
+	OnboardingReady               = "ready"
+	OnboardingMigrationController = "migrationController"
+	OnboardingMonitoring          = "monitoring"
+	OnboardingOwner               = "owner"
+	OnboardingNamespaces          = "namespaces"
+)
+
+// OnboardingStep is one item of the onboarding checklist of a member cluster
+type OnboardingStep struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	Done    bool   `json:"done"`
+	Message string `json:"message,omitempty"`
+}
+
+// OnboardingStatus is the onboarding checklist of a member cluster, in the order the steps are done
+type OnboardingStatus struct {
+	Cluster   string           `json:"cluster"`
+	Complete  bool             `json:"complete"`
+	Completed int              `json:"completed"`
+	Total     int              `json:"total"`
+	Steps     []OnboardingStep `json:"steps"`
+}
+
+// NewOnboardingStatus returns an empty checklist for a cluster
+func NewOnboardingStatus(clusterName string) *OnboardingStatus {
+	return &OnboardingStatus{Cluster: clusterName, Steps: []OnboardingStep{}}
+}
+
+// Record adds a step to the checklist. A step with an error is not done and explains the error.
+func (s *OnboardingStatus) Record(name, title string, done bool, message string, err error) {
+	step := OnboardingStep{Name: name, Title: title, Done: done && err == nil, Message: message}
+	if err != nil {
+		step.Message = err.Error()
+	}
+	s.Steps = append(s.Steps, step)
+	s.Total = len(s.Steps)
+	if step.Done {
+		s.Completed++
+	}
+	s.Complete = s.Completed == s.Total
+}
+
+// Skip adds a step that cannot be checked until an earlier step is done
+func (s *OnboardingStatus) Skip(name, title, reason string) {
+	s.Record(name, title, false, reason, nil)
+}
+
+// ReadyMessage tells whether the Ready condition of a cluster is true and explains it otherwise
+func ReadyMessage(cluster *v1alpha1.Cluster) (bool, string) {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type != v1alpha1.ClusterConditionReady {
+			continue
+		}
+		if condition.Status == metav1.ConditionTrue {
+			return true, ""
+		}
+		if condition.Message != "" {
+			return false, condition.Message
+		}
+		return false, fmt.Sprintf("cluster is not ready: %s", condition.Reason)
+	}
+	return false, "cluster has not reported its status yet"
+}
+
+// MissingNamespaces returns the required namespaces that are not among the namespaces of a cluster
+func MissingNamespaces(required []string, namespaces []corev1.Namespace) []string {
+	present := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		present[namespace.Name] = true
+	}
+	var missing []string
+	for _, name := range required {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// NamespacesMessage describes the missing namespaces of a cluster
+func NamespacesMessage(missing []string) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("missing namespaces: %s", strings.Join(missing, ", "))
+}
+
+// ClusterOwners returns the users with an owner tuple on the cluster. Dashboard admins own every
+// cluster through their role, so they are not counted as owners.
+func ClusterOwners(ctx context.Context, clusterName string) ([]string, error) {
+	owners, err := OwnersByCluster(ctx, []string{clusterName})
+	if err != nil {
+		return nil, err
+	}
+	return owners[clusterName], nil
+}
+
+// OwnersByCluster returns the owners of each of the clusters, as ClusterOwners does, listing the users once
+func OwnersByCluster(ctx context.Context, clusterNames []string) (map[string][]string, error) {
+	fgaService := fga.FGAService
+	if fgaService == nil {
+		return nil, fmt.Errorf("OpenFGA service is not initialized")
+	}
+	userManager, err := newUserManager()
+	if err != nil {
+		return nil, err
+	}
+	users, err := userManager.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	owners := make(map[string][]string, len(clusterNames))
+	for _, user := range users {
+		if user.Username == "" {
+			continue
+		}
+		isAdmin, err := fgaService.Check(ctx, user.Username, "admin", "dashboard", "dashboard")
+		if err != nil || isAdmin {
+			continue
+		}
+		for _, clusterName := range clusterNames {
+			isOwner, err := fgaService.Check(ctx, user.Username, "owner", "cluster", clusterName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check the owner of cluster %s: %w", clusterName, err)
+			}
+			if isOwner {
+				owners[clusterName] = append(owners[clusterName], user.Username)
+			}
+		}
+	}
+	return owners, nil
+}
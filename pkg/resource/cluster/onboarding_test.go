@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOnboardingStatus(t *testing.T) {
+	status := NewOnboardingStatus("member1")
+	status.Record(OnboardingJoined, "Joined to Karmada", true, "", nil)
+	if !status.Complete || status.Completed != 1 {
+		t.Errorf("status = %+v, want complete after one done step", status)
+	}
+
+	status.Record(OnboardingMonitoring, "Monitoring configured", true, "", errors.New("config unavailable"))
+	status.Skip(OnboardingNamespaces, "Required namespaces present", "cluster is not ready")
+	if status.Complete || status.Completed != 1 || status.Total != 3 {
+		t.Errorf("status = %+v, want 1 of 3 steps done", status)
+	}
+	if step := status.Steps[1]; step.Done || step.Message != "config unavailable" {
+		t.Errorf("failed step = %+v, want not done with the error", step)
+	}
+}
+
+func TestReadyMessage(t *testing.T) {
+	cluster := &v1alpha1.Cluster{}
+	if ready, message := ReadyMessage(cluster); ready || message == "" {
+		t.Errorf("ReadyMessage() without conditions = %v, %q", ready, message)
+	}
+	cluster.Status.Conditions = []metav1.Condition{{Type: v1alpha1.ClusterConditionReady, Status: metav1.ConditionFalse, Message: "cluster is not reachable"}}
+	if ready, message := ReadyMessage(cluster); ready || message != "cluster is not reachable" {
+		t.Errorf("ReadyMessage() = %v, %q, want the condition message", ready, message)
+	}
+	cluster.Status.Conditions[0].Status = metav1.ConditionTrue
+	if ready, _ := ReadyMessage(cluster); !ready {
+		t.Error("ReadyMessage() = false for a ready cluster")
+	}
+}
+
+func TestMissingNamespaces(t *testing.T) {
+	namespaces := []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, {ObjectMeta: metav1.ObjectMeta{Name: "stateful-migration"}}}
+	missing := MissingNamespaces([]string{"stateful-migration", "ml-platform-user-access"}, namespaces)
+	if !reflect.DeepEqual(missing, []string{"ml-platform-user-access"}) {
+		t.Errorf("MissingNamespaces() = %v, want [ml-platform-user-access]", missing)
+	}
+	if message := NamespacesMessage(missing); message != "missing namespaces: ml-platform-user-access" {
+		t.Errorf("NamespacesMessage() = %q", message)
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// GPUResourceName is the extended resource exposed by the NVIDIA device plugin
+const GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+// WorkloadSpec is what each replica of a workload requests and the nodes it can be scheduled on
+type WorkloadSpec struct {
+	Replicas     int32
+	Requests     corev1.ResourceList
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+}
+
+// PlacementResult tells whether a workload fits in a member cluster on its current free resources
+type PlacementResult struct {
+	Cluster string `json:"cluster"`
+	Fits    bool   `json:"fits"`
+	// MaxReplicas is the number of replicas the free resources of the eligible nodes can hold
+	MaxReplicas   int64 `json:"maxReplicas"`
+	EligibleNodes int   `json:"eligibleNodes"`
+	TotalNodes    int   `json:"totalNodes"`
+	// Free is the sum of the free resources of the eligible nodes
+	Free corev1.ResourceList `json:"free"`
+	// Score is the share of the most constrained resource left free after placing the workload,
+	// higher is better. It is 0 when the workload does not fit.
+	Score float64 `json:"score"`
+	// Reasons explain why nodes were not eligible or the workload does not fit
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// podRequests returns the resources requested by a pod, following the scheduler: the larger of the
+// sum of its containers and of any init container, plus its overhead
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResources(requests, pod.Spec.Overhead)
+	return requests
+}
+
+func addResources(total, list corev1.ResourceList) {
+	for name, quantity := range list {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+}
+
+// NodeFree returns the allocatable resources of a node minus the requests of the pods running on it.
+// The pods resource counts the pod slots left.
+func NodeFree(node *corev1.Node, pods []corev1.Pod) corev1.ResourceList {
+	free := node.Status.Allocatable.DeepCopy()
+	if free == nil {
+		free = corev1.ResourceList{}
+	}
+	var podCount int64
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != node.Name || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podCount++
+		for name, quantity := range podRequests(pod) {
+			current, ok := free[name]
+			if !ok {
+				continue
+			}
+			current.Sub(quantity)
+			free[name] = current
+		}
+	}
+	for name, quantity := range free {
+		// Nodes can be overcommitted when pods were bound before their allocatable shrank
+		if quantity.Sign() < 0 {
+			free[name] = *resource.NewQuantity(0, quantity.Format)
+		}
+	}
+	if allocatablePods, ok := free[corev1.ResourcePods]; ok {
+		free[corev1.ResourcePods] = *resource.NewQuantity(allocatablePods.Value()-podCount, resource.DecimalSI)
+	}
+	return free
+}
+
+// nodeIneligibleReason returns why a workload cannot be scheduled on a node, or an empty string
+func nodeIneligibleReason(node *corev1.Node, spec WorkloadSpec) string {
+	if node.Spec.Unschedulable {
+		return "node is unschedulable"
+	}
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			ready = condition.Status == corev1.ConditionTrue
+			break
+		}
+	}
+	if !ready {
+		return "node is not ready"
+	}
+	if len(spec.NodeSelector) > 0 && !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return "node does not match the node selector"
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		if !toleratesTaint(spec.Tolerations, taint) {
+			return fmt.Sprintf("node has untolerated taint %s", taint.ToString())
+		}
+	}
+	return ""
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// replicasFitting returns how many replicas the free resources hold. Every replica takes a pod slot,
+// so the result is bounded even when nothing else is requested.
+func replicasFitting(free, requests corev1.ResourceList) (int64, corev1.ResourceName) {
+	slots, ok := free[corev1.ResourcePods]
+	if !ok {
+		return 0, corev1.ResourcePods
+	}
+	fitting, limiting := slots.Value(), corev1.ResourcePods
+	for name, request := range requests {
+		if request.IsZero() {
+			continue
+		}
+		available := free[name]
+		var count int64
+		if available.Sign() > 0 {
+			count = available.MilliValue() / request.MilliValue()
+		}
+		if count < fitting {
+			fitting, limiting = count, name
+		}
+	}
+	if fitting < 0 {
+		fitting = 0
+	}
+	return fitting, limiting
+}
+
+// EvaluatePlacement simulates scheduling the replicas of a workload on the nodes of a cluster.
+// Each replica goes to an eligible node with enough free resources, as the scheduler would place it.
+func EvaluatePlacement(clusterName string, nodes []corev1.Node, pods []corev1.Pod, spec WorkloadSpec) PlacementResult {
+	result := PlacementResult{
+		Cluster:    clusterName,
+		TotalNodes: len(nodes),
+		Free:       corev1.ResourceList{},
+	}
+	reasons := map[string]int{}
+	for i := range nodes {
+		node := &nodes[i]
+		if reason := nodeIneligibleReason(node, spec); reason != "" {
+			reasons[reason]++
+			continue
+		}
+		result.EligibleNodes++
+		free := NodeFree(node, pods)
+		addResources(result.Free, free)
+		fitting, limiting := replicasFitting(free, spec.Requests)
+		if fitting == 0 {
+			reasons[fmt.Sprintf("insufficient %s", limiting)]++
+		}
+		result.MaxReplicas += fitting
+	}
+
+	for reason, count := range reasons {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d node(s): %s", count, reason))
+	}
+	sort.Strings(result.Reasons)
+
+	result.Fits = spec.Replicas > 0 && result.MaxReplicas >= int64(spec.Replicas)
+	if result.Fits {
+		result.Score = placementScore(result.Free, spec)
+	} else if result.MaxReplicas > 0 {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("only %d of %d replicas fit", result.MaxReplicas, spec.Replicas))
+	}
+	return result
+}
+
+// placementScore returns the smallest share of a requested resource left free once the workload is placed
+func placementScore(free corev1.ResourceList, spec WorkloadSpec) float64 {
+	score := 1.0
+	requests := spec.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	requests[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	for name, request := range requests {
+		available := free[name]
+		if request.IsZero() || available.Sign() <= 0 {
+			continue
+		}
+		used := float64(request.MilliValue()) * float64(spec.Replicas)
+		left := 1 - used/float64(available.MilliValue())
+		if left < score {
+			score = left
+		}
+	}
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// RankPlacements orders placement results with the clusters that fit first, best score first
+func RankPlacements(results []PlacementResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Fits != results[j].Fits {
+			return results[i].Fits
+		}
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Cluster < results[j].Cluster
+	})
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNode(name, cpu, memory, gpu string, labels map[string]string, taints ...corev1.Taint) corev1.Node {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	if gpu != "" {
+		allocatable[GPUResourceName] = resource.MustParse(gpu)
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Allocatable: allocatable,
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func testPod(node string, phase corev1.PodPhase, requests corev1.ResourceList) corev1.Pod {
+	return corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeName:   node,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: requests}}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestNodeFree(t *testing.T) {
+	node := testNode("gpu-1", "8", "32Gi", "4", nil)
+	pods := []corev1.Pod{
+		testPod("gpu-1", corev1.PodRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), GPUResourceName: resource.MustParse("1")}),
+		testPod("gpu-1", corev1.PodSucceeded, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+		testPod("gpu-2", corev1.PodRunning, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+	}
+	free := NodeFree(&node, pods)
+	if cpu := free[corev1.ResourceCPU]; cpu.MilliValue() != 6000 {
+		t.Errorf("free cpu = %s, expected 6", cpu.String())
+	}
+	if gpu := free[GPUResourceName]; gpu.Value() != 3 {
+		t.Errorf("free gpu = %s, expected 3", gpu.String())
+	}
+	if slots := free[corev1.ResourcePods]; slots.Value() != 109 {
+		t.Errorf("free pods = %s, expected 109", slots.String())
+	}
+}
+
+func TestEvaluatePlacement(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []corev1.Node{
+		testNode("cpu-1", "16", "64Gi", "", map[string]string{"pool": "cpu"}),
+		testNode("gpu-1", "32", "128Gi", "4", map[string]string{"pool": "gpu"}, gpuTaint),
+		testNode("gpu-2", "32", "128Gi", "4", map[string]string{"pool": "gpu"}, gpuTaint),
+	}
+	pods := []corev1.Pod{
+		testPod("gpu-2", corev1.PodRunning, corev1.ResourceList{GPUResourceName: resource.MustParse("3")}),
+	}
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+		GPUResourceName:       resource.MustParse("2"),
+	}
+	tolerations := []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+
+	tests := []struct {
+		name        string
+		spec        WorkloadSpec
+		fits        bool
+		maxReplicas int64
+		reasons     []string
+	}{
+		{
+			name:        "fits on tolerated gpu nodes",
+			spec:        WorkloadSpec{Replicas: 2, Requests: requests, Tolerations: tolerations},
+			fits:        true,
+			maxReplicas: 2,
+			reasons:     []string{"2 node(s): insufficient nvidia.com/gpu"},
+		},
+		{
+			name:        "too many replicas",
+			spec:        WorkloadSpec{Replicas: 3, Requests: requests, Tolerations: tolerations},
+			maxReplicas: 2,
+			reasons:     []string{"2 node(s): insufficient nvidia.com/gpu", "only 2 of 3 replicas fit"},
+		},
+		{
+			name:    "taints not tolerated",
+			spec:    WorkloadSpec{Replicas: 1, Requests: requests},
+			reasons: []string{"1 node(s): insufficient nvidia.com/gpu", "2 node(s): node has untolerated taint nvidia.com/gpu=present:NoSchedule"},
+		},
+		{
+			name:    "node selector",
+			spec:    WorkloadSpec{Replicas: 1, Requests: requests, Tolerations: tolerations, NodeSelector: map[string]string{"pool": "cpu"}},
+			reasons: []string{"1 node(s): insufficient nvidia.com/gpu", "2 node(s): node does not match the node selector"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluatePlacement("member1", nodes, pods, tt.spec)
+			if result.Fits != tt.fits || result.MaxReplicas != tt.maxReplicas {
+				t.Errorf("fits = %v with %d replicas, expected %v with %d", result.Fits, result.MaxReplicas, tt.fits, tt.maxReplicas)
+			}
+			if !reflect.DeepEqual(result.Reasons, tt.reasons) {
+				t.Errorf("reasons = %q, expected %q", result.Reasons, tt.reasons)
+			}
+			if tt.fits != (result.Score > 0) {
+				t.Errorf("score = %v", result.Score)
+			}
+		})
+	}
+}
+
+func TestRankPlacements(t *testing.T) {
+	results := []PlacementResult{
+		{Cluster: "member3"},
+		{Cluster: "member2", Fits: true, Score: 0.25},
+		{Cluster: "member1", Fits: true, Score: 0.75},
+		{Cluster: "member0"},
+	}
+	RankPlacements(results)
+	var order []string
+	for _, result := range results {
+		order = append(order, result.Cluster)
+	}
+	if expected := []string{"member1", "member2", "member0", "member3"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("order = %v, expected %v", order, expected)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SelectorLabels returns the cluster labels that select the clusters of a group and match the extra labels.
+// It is nil when neither is set.
+func SelectorLabels(group string, matchLabels map[string]string) map[string]string {
+	if group == "" && len(matchLabels) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(matchLabels)+1)
+	for key, value := range matchLabels {
+		result[key] = value
+	}
+	if group != "" {
+		result[ClusterGroupLabel] = group
+	}
+	return result
+}
+
+// SelectorName returns a short name that identifies a set of cluster labels. The same labels always get
+// the same name, so resources created for a selector can be found again and used as a name suffix.
+func SelectorName(matchLabels map[string]string) string {
+	keys := make([]string, 0, len(matchLabels))
+	for key := range matchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+matchLabels[key])
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(strings.Join(pairs, ",")))
+	return fmt.Sprintf("selector-%08x", hash.Sum32())
+}
+
+// GetSelectedClusters returns the names of the clusters that have all the labels.
+func GetSelectedClusters(client karmadaclientset.Interface, matchLabels map[string]string) ([]string, error) {
+	clusters, err := client.ClusterV1alpha1().Clusters().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(matchLabels).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
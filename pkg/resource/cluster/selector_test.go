@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	karmadafake "github.com/karmada-io/karmada/pkg/generated/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSelectorLabels(t *testing.T) {
+	if got := SelectorLabels("", nil); got != nil {
+		t.Errorf("SelectorLabels() without group and labels = %v, want nil", got)
+	}
+	got := SelectorLabels("gpu", map[string]string{"region": "eu"})
+	want := map[string]string{ClusterGroupLabel: "gpu", "region": "eu"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectorLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestSelectorName(t *testing.T) {
+	a := SelectorName(map[string]string{"region": "eu", "tier": "gpu"})
+	b := SelectorName(map[string]string{"tier": "gpu", "region": "eu"})
+	if a != b {
+		t.Errorf("SelectorName() depends on the map order: %q != %q", a, b)
+	}
+	if c := SelectorName(map[string]string{"region": "us", "tier": "gpu"}); c == a {
+		t.Errorf("SelectorName() of different labels = %q for both", c)
+	}
+	if len(a) != len("selector-")+8 {
+		t.Errorf("SelectorName() = %q, want selector- and 8 hex digits", a)
+	}
+}
+
+func TestGetSelectedClusters(t *testing.T) {
+	newCluster := func(name string, labels map[string]string) *clusterv1alpha1.Cluster {
+		return &clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	client := karmadafake.NewSimpleClientset(
+		newCluster("member2", map[string]string{"region": "eu", "tier": "gpu"}),
+		newCluster("member1", map[string]string{"region": "eu", "tier": "gpu"}),
+		newCluster("member3", map[string]string{"region": "eu"}),
+	)
+	got, err := GetSelectedClusters(client, map[string]string{"region": "eu", "tier": "gpu"})
+	if err != nil {
+		t.Fatalf("GetSelectedClusters() error = %v", err)
+	}
+	if want := []string{"member1", "member2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSelectedClusters() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Bounds of the time range of a usage timeline
+const (
+	DefaultUsageRange = 24 * time.Hour
+	MaxUsageRange     = 7 * 24 * time.Hour
+)
+
+// usagePointsPerRange is the number of points a timeline is downsampled to
+const usagePointsPerRange = 120
+
+// Metrics of a usage timeline
+const (
+	UsageCPU    = "cpu"
+	UsageMemory = "memory"
+	UsageGPU    = "gpu"
+	UsagePods   = "pods"
+)
+
+// UsageLabelKey marks the ConfigMaps storing the usage samples of a cluster and holds the cluster name
+const UsageLabelKey = "ml-platform.io/cluster-usage"
+
+const (
+	usageDataKey = "samples"
+	gpuResource  = corev1.ResourceName("nvidia.com/gpu")
+)
+
+var nodeMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+
+// UsagePoint is the resource usage of a cluster at a point in time
+type UsagePoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	CPUCores    float64   `json:"cpuCores"`
+	MemoryBytes float64   `json:"memoryBytes"`
+	// GPUs is the number of GPUs requested by the pods, since metrics-server does not measure GPU usage
+	GPUs float64 `json:"gpus"`
+	Pods float64 `json:"pods"`
+}
+
+// ParseUsageRange parses the time range of a usage timeline, a duration such as 90m or 24h or a number of days
+// such as 7d, defaulting to DefaultUsageRange
+func ParseUsageRange(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultUsageRange, nil
+	}
+	var d time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid range %q", value)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid range %q", value)
+		}
+		d = parsed
+	}
+	if d <= 0 || d > MaxUsageRange {
+		return 0, fmt.Errorf("range must be positive and at most %s", MaxUsageRange)
+	}
+	return d, nil
+}
+
+// UsageStep returns the resolution of a timeline over the range, a whole number of minutes
+func UsageStep(usageRange time.Duration) time.Duration {
+	step := (usageRange / usagePointsPerRange).Truncate(time.Minute)
+	if step < time.Minute {
+		return time.Minute
+	}
+	return step
+}
+
+// SampleUsage measures the current usage of a cluster: CPU and memory from the node metrics of metrics-server,
+// the GPUs requested by the pods that are not terminated and the number of running pods
+func SampleUsage(ctx context.Context, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface, now time.Time) (UsagePoint, error) {
+	point := UsagePoint{Timestamp: now}
+	nodes, err := dynamicClient.Resource(nodeMetricsResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return point, fmt.Errorf("failed to list node metrics: %w", err)
+	}
+	for _, node := range nodes.Items {
+		usage, _ := node.Object["usage"].(map[string]interface{})
+		if cpu, ok := usage["cpu"].(string); ok {
+			if q, err := resource.ParseQuantity(cpu); err == nil {
+				point.CPUCores += float64(q.MilliValue()) / 1000
+			}
+		}
+		if memory, ok := usage["memory"].(string); ok {
+			if q, err := resource.ParseQuantity(memory); err == nil {
+				point.MemoryBytes += float64(q.Value())
+			}
+		}
+	}
+
+	pods, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return point, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodRunning {
+			point.Pods++
+		}
+		for _, container := range pod.Spec.Containers {
+			if gpus, ok := container.Resources.Requests[gpuResource]; ok {
+				point.GPUs += float64(gpus.Value())
+			}
+		}
+	}
+	return point, nil
+}
+
+// AppendUsage appends a point to the samples of a cluster and drops the samples older than the retention
+func AppendUsage(samples []UsagePoint, point UsagePoint, retention time.Duration) []UsagePoint {
+	cutoff := point.Timestamp.Add(-retention)
+	kept := samples[:0]
+	for _, sample := range samples {
+		if sample.Timestamp.After(cutoff) {
+			kept = append(kept, sample)
+		}
+	}
+	return append(kept, point)
+}
+
+// DownsampleUsage averages the samples between start and end into buckets of one step, each stamped with the
+// start of its bucket. Buckets without samples are left out rather than reported as zero usage.
+func DownsampleUsage(samples []UsagePoint, start, end time.Time, step time.Duration) []UsagePoint {
+	type bucket struct {
+		sum   UsagePoint
+		count float64
+	}
+	buckets := map[int64]*bucket{}
+	for _, sample := range samples {
+		if sample.Timestamp.Before(start) || sample.Timestamp.After(end) {
+			continue
+		}
+		index := int64(sample.Timestamp.Sub(start) / step)
+		b, ok := buckets[index]
+		if !ok {
+			b = &bucket{}
+			buckets[index] = b
+		}
+		b.sum.CPUCores += sample.CPUCores
+		b.sum.MemoryBytes += sample.MemoryBytes
+		b.sum.GPUs += sample.GPUs
+		b.sum.Pods += sample.Pods
+		b.count++
+	}
+
+	points := make([]UsagePoint, 0, len(buckets))
+	for index, b := range buckets {
+		points = append(points, UsagePoint{
+			Timestamp:   start.Add(time.Duration(index) * step),
+			CPUCores:    b.sum.CPUCores / b.count,
+			MemoryBytes: b.sum.MemoryBytes / b.count,
+			GPUs:        b.sum.GPUs / b.count,
+			Pods:        b.sum.Pods / b.count,
+		})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points
+}
+
+// MergeUsage builds a timeline from the values of each metric by time, such as the results of Prometheus range
+// queries. A metric without a value at a time counts as zero.
+func MergeUsage(metrics map[string]map[time.Time]float64) []UsagePoint {
+	byTime := map[time.Time]*UsagePoint{}
+	for metric, values := range metrics {
+		for t, value := range values {
+			point, ok := byTime[t]
+			if !ok {
+				point = &UsagePoint{Timestamp: t}
+				byTime[t] = point
+			}
+			switch metric {
+			case UsageCPU:
+				point.CPUCores = value
+			case UsageMemory:
+				point.MemoryBytes = value
+			case UsageGPU:
+				point.GPUs = value
+			case UsagePods:
+				point.Pods = value
+			}
+		}
+	}
+	points := make([]UsagePoint, 0, len(byTime))
+	for _, point := range byTime {
+		points = append(points, *point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points
+}
+
+// usageConfigMapName returns the name of the ConfigMap storing the usage samples of a cluster
+func usageConfigMapName(clusterName string) string {
+	return fmt.Sprintf("cluster-usage-%s", clusterName)
+}
+
+// LoadUsage returns the usage samples stored for a cluster, none when it was never sampled
+func LoadUsage(ctx context.Context, kubeClient kubernetes.Interface, namespace, clusterName string) ([]UsagePoint, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, usageConfigMapName(clusterName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var samples []UsagePoint
+	if err := json.Unmarshal([]byte(cm.Data[usageDataKey]), &samples); err != nil {
+		return nil, fmt.Errorf("failed to decode usage samples of cluster %s: %w", clusterName, err)
+	}
+	return samples, nil
+}
+
+// SaveUsage stores the usage samples of a cluster, replacing the previous ones
+func SaveUsage(ctx context.Context, kubeClient kubernetes.Interface, namespace, clusterName string, samples []UsagePoint) error {
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return err
+	}
+	configMaps := kubeClient.CoreV1().ConfigMaps(namespace)
+	cm, err := configMaps.Get(ctx, usageConfigMapName(clusterName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      usageConfigMapName(clusterName),
+				Namespace: namespace,
+				Labels:    map[string]string{UsageLabelKey: clusterName},
+			},
+			Data: map[string]string{usageDataKey: string(data)},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{usageDataKey: string(data)}
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
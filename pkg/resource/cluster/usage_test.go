@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseUsageRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: DefaultUsageRange},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "24h", want: 24 * time.Hour},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "8d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "a week", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUsageRange(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseUsageRange(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseUsageRange(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+	if step := UsageStep(24 * time.Hour); step != 12*time.Minute {
+		t.Errorf("UsageStep(24h) = %s, want 12m", step)
+	}
+	if step := UsageStep(time.Hour); step != time.Minute {
+		t.Errorf("UsageStep(1h) = %s, want 1m", step)
+	}
+}
+
+func TestSampleUsage(t *testing.T) {
+	node := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "NodeMetrics",
+		"metadata":   map[string]interface{}{"name": "node1"},
+		"usage":      map[string]interface{}{"cpu": "1500m", "memory": "2Gi"},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{nodeMetricsResource: "NodeMetricsList"})
+	// Created through the resource, the fake client would guess nodemetricses from the kind
+	if _, err := dynamicClient.Resource(nodeMetricsResource).Create(context.TODO(), node, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	gpuPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "team-a"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "trainer",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{gpuResource: resource.MustParse("2")}},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	pending := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "team-a"}, Status: corev1.PodStatus{Phase: corev1.PodPending}}
+	done := gpuPod.DeepCopy()
+	done.Name = "done"
+	done.Status.Phase = corev1.PodSucceeded
+	kubeClient := kubefake.NewSimpleClientset(gpuPod, pending, done)
+
+	now := time.Now()
+	point, err := SampleUsage(context.TODO(), kubeClient, dynamicClient, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := UsagePoint{Timestamp: now, CPUCores: 1.5, MemoryBytes: 2 << 30, GPUs: 2, Pods: 1}
+	if point != want {
+		t.Errorf("SampleUsage() = %+v, want %+v", point, want)
+	}
+}
+
+func TestDownsampleUsage(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var samples []UsagePoint
+	for i := 0; i < 6; i++ {
+		samples = AppendUsage(samples, UsagePoint{Timestamp: start.Add(time.Duration(i) * 5 * time.Minute), CPUCores: float64(i)}, 20*time.Minute)
+	}
+	// The retention keeps the samples of the last 20 minutes, from minute 10 on
+	if len(samples) != 4 || !samples[0].Timestamp.Equal(start.Add(10*time.Minute)) {
+		t.Fatalf("AppendUsage() kept %d samples from %s, want 4 from minute 10", len(samples), samples[0].Timestamp)
+	}
+
+	points := DownsampleUsage(samples, start, start.Add(time.Hour), 10*time.Minute)
+	if len(points) != 2 {
+		t.Fatalf("DownsampleUsage() returned %d points, want 2", len(points))
+	}
+	if !points[0].Timestamp.Equal(start.Add(10*time.Minute)) || points[0].CPUCores != 2.5 {
+		t.Errorf("first point = %+v, want the average 2.5 at minute 10", points[0])
+	}
+	if !points[1].Timestamp.Equal(start.Add(20*time.Minute)) || points[1].CPUCores != 4.5 {
+		t.Errorf("second point = %+v, want the average 4.5 at minute 20", points[1])
+	}
+}
+
+func TestMergeUsage(t *testing.T) {
+	t1 := time.Unix(1714521600, 0)
+	t2 := t1.Add(time.Minute)
+	points := MergeUsage(map[string]map[time.Time]float64{
+		UsageCPU:    {t1: 1, t2: 2},
+		UsageMemory: {t2: 1024},
+		UsagePods:   {t1: 10, t2: 12},
+	})
+	if len(points) != 2 {
+		t.Fatalf("MergeUsage() returned %d points, want 2", len(points))
+	}
+	if points[0] != (UsagePoint{Timestamp: t1, CPUCores: 1, Pods: 10}) {
+		t.Errorf("first point = %+v", points[0])
+	}
+	if points[1] != (UsagePoint{Timestamp: t2, CPUCores: 2, MemoryBytes: 1024, Pods: 12}) {
+		t.Errorf("second point = %+v", points[1])
+	}
+}
+
+func TestSaveAndLoadUsage(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	ctx := context.TODO()
+	if samples, err := LoadUsage(ctx, kubeClient, "dashboard", "member1"); err != nil || samples != nil {
+		t.Fatalf("LoadUsage() of an unsampled cluster = %v, %v", samples, err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 1; i <= 2; i++ {
+		if err := SaveUsage(ctx, kubeClient, "dashboard", "member1", []UsagePoint{{Timestamp: now, Pods: float64(i)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	samples, err := LoadUsage(ctx, kubeClient, "dashboard", "member1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Pods != 2 || !samples[0].Timestamp.Equal(now) {
+		t.Errorf("LoadUsage() = %+v, want the last saved sample", samples)
+	}
+}
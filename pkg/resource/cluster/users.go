@@ -247,3 +247,48 @@ func getUsersWithRole(fgaService *fga.Service, relation, objectType, objectID st
 
 	return result, nil
 }
+
+// DeleteClusterUserTuples removes the owner and member relations of all users on the cluster
+// and returns the number of deleted tuples.
+func DeleteClusterUserTuples(ctx context.Context, clusterName string) (int, error) {
+	fgaService := fga.FGAService
+	if fgaService == nil {
+		return 0, nil
+	}
+	userManager, err := newUserManager()
+	if err != nil {
+		return 0, err
+	}
+	users, err := userManager.ListUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if user.Username == "" {
+			continue
+		}
+		for _, relation := range []string{"owner", "member"} {
+			hasRole, err := fgaService.Check(ctx, user.Username, relation, "cluster", clusterName)
+			if err != nil || !hasRole {
+				continue
+			}
+			// Relations inherited from the dashboard admin role have no tuple on the cluster
+			if err := fgaService.GetClient().DeleteTuple(ctx, user.Username, relation, "cluster", clusterName); err != nil {
+				klog.V(4).InfoS("Failed to delete cluster tuple", "user", user.Username, "relation", relation, "cluster", clusterName, "error", err)
+				continue
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newUserManager() (*etcd.UserManager, error) {
+	etcdClient, err := etcd.GetEtcdClient(etcd.NewDefaultOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get etcd client: %w", err)
+	}
+	return etcd.NewUserManager(etcdClient), nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crd summarizes the CustomResourceDefinitions installed in a member cluster, with the
+// versions they serve and how many instances exist, and extracts their OpenAPI v3 schemas, so operators
+// can check the migration, Kubeflow and Argo CRDs are installed and compatible before using them.
+package crd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// GVR is the CustomResourceDefinition resource
+var GVR = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// countWorkers bounds how many CRDs have their instances counted at the same time
+const countWorkers = 8
+
+// ErrVersionNotFound is returned by Schema when the CRD does not define the requested version
+var ErrVersionNotFound = errors.New("version not found")
+
+// Version is a version defined by a CRD
+type Version struct {
+	Name       string `json:"name"`
+	Served     bool   `json:"served"`
+	Storage    bool   `json:"storage"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+	// HasSchema is whether the version has an OpenAPI v3 validation schema
+	HasSchema bool `json:"hasSchema"`
+}
+
+// Definition summarizes a CRD installed in a member cluster
+type Definition struct {
+	Name     string    `json:"name"`
+	Group    string    `json:"group"`
+	Kind     string    `json:"kind"`
+	Plural   string    `json:"plural"`
+	Scope    string    `json:"scope"`
+	Versions []Version `json:"versions"`
+	// StorageVersion is the version objects are persisted in
+	StorageVersion string `json:"storageVersion"`
+	// Established is whether the API server serves the CRD
+	Established bool `json:"established"`
+	// Instances is how many objects of the CRD exist, or -1 when they could not be counted
+	Instances  int    `json:"instances"`
+	CountError string `json:"countError,omitempty"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+}
+
+// Summarize extracts the Definition of a CRD object. Instances is not set.
+func Summarize(obj *unstructured.Unstructured) Definition {
+	def := Definition{Name: obj.GetName()}
+	def.Group, _, _ = unstructured.NestedString(obj.Object, "spec", "group")
+	def.Kind, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "kind")
+	def.Plural, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "plural")
+	def.Scope, _, _ = unstructured.NestedString(obj.Object, "spec", "scope")
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		def.CreatedAt = created.UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	for _, raw := range versions {
+		v, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		version := Version{}
+		version.Name, _, _ = unstructured.NestedString(v, "name")
+		version.Served, _, _ = unstructured.NestedBool(v, "served")
+		version.Storage, _, _ = unstructured.NestedBool(v, "storage")
+		version.Deprecated, _, _ = unstructured.NestedBool(v, "deprecated")
+		_, version.HasSchema, _ = unstructured.NestedMap(v, "schema", "openAPIV3Schema")
+		if version.Storage {
+			def.StorageVersion = version.Name
+		}
+		def.Versions = append(def.Versions, version)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Established" && condition["status"] == "True" {
+			def.Established = true
+		}
+	}
+	return def
+}
+
+// servedVersion is the version instances are listed with: the storage version when it is served,
+// otherwise the first served version
+func (d Definition) servedVersion() string {
+	first := ""
+	for _, v := range d.Versions {
+		if !v.Served {
+			continue
+		}
+		if v.Storage {
+			return v.Name
+		}
+		if first == "" {
+			first = v.Name
+		}
+	}
+	return first
+}
+
+// List returns the CRDs installed in a member cluster sorted by name, optionally only those of group.
+// When countInstances is set the objects of each CRD are counted across all namespaces.
+func List(ctx context.Context, dynamicClient dynamic.Interface, group string, countInstances bool) ([]Definition, error) {
+	list, err := dynamicClient.Resource(GVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	definitions := make([]Definition, 0, len(list.Items))
+	for i := range list.Items {
+		def := Summarize(&list.Items[i])
+		if group != "" && def.Group != group {
+			continue
+		}
+		definitions = append(definitions, def)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+
+	if countInstances {
+		var wg sync.WaitGroup
+		workers := make(chan struct{}, countWorkers)
+		for i := range definitions {
+			wg.Add(1)
+			workers <- struct{}{}
+			go func(def *Definition) {
+				defer wg.Done()
+				defer func() { <-workers }()
+				count, countErr := countInstancesOf(ctx, dynamicClient, *def)
+				if countErr != nil {
+					def.Instances = -1
+					def.CountError = countErr.Error()
+					return
+				}
+				def.Instances = count
+			}(&definitions[i])
+		}
+		wg.Wait()
+	}
+	return definitions, nil
+}
+
+// countInstancesOf counts the objects of a CRD in all namespaces
+func countInstancesOf(ctx context.Context, dynamicClient dynamic.Interface, def Definition) (int, error) {
+	version := def.servedVersion()
+	if version == "" {
+		return 0, fmt.Errorf("no served version")
+	}
+	gvr := schema.GroupVersionResource{Group: def.Group, Version: version, Resource: def.Plural}
+	list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return len(list.Items), nil
+}
+
+// Schema returns the OpenAPI v3 schema of a version of a CRD, the storage version when version is empty.
+// The schema is nil when the version has none.
+func Schema(ctx context.Context, dynamicClient dynamic.Interface, name, version string) (Definition, map[string]interface{}, error) {
+	obj, err := dynamicClient.Resource(GVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return Definition{}, nil, err
+		}
+		return Definition{}, nil, fmt.Errorf("failed to get CRD %s: %w", name, err)
+	}
+	def := Summarize(obj)
+	if version == "" {
+		version = def.StorageVersion
+	}
+
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	for _, raw := range versions {
+		v, ok := raw.(map[string]interface{})
+		if !ok || v["name"] != version {
+			continue
+		}
+		openAPISchema, _, _ := unstructured.NestedMap(v, "schema", "openAPIV3Schema")
+		return def, openAPISchema, nil
+	}
+	return def, nil, fmt.Errorf("%w: CRD %s has no version %q", ErrVersionNotFound, name, version)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func definition(group, kind, plural, scope string, versions ...map[string]interface{}) *unstructured.Unstructured {
+	rawVersions := make([]interface{}, 0, len(versions))
+	for _, v := range versions {
+		rawVersions = append(rawVersions, v)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": plural + "." + group},
+		"spec": map[string]interface{}{
+			"group":    group,
+			"scope":    scope,
+			"names":    map[string]interface{}{"kind": kind, "plural": plural},
+			"versions": rawVersions,
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Established", "status": "True"}},
+		},
+	}}
+}
+
+func version(name string, served, storage bool, openAPISchema map[string]interface{}) map[string]interface{} {
+	v := map[string]interface{}{"name": name, "served": served, "storage": storage}
+	if openAPISchema != nil {
+		v["schema"] = map[string]interface{}{"openAPIV3Schema": openAPISchema}
+	}
+	return v
+}
+
+func instance(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func newClient() *dynamicfake.FakeDynamicClient {
+	workflowSchema := map[string]interface{}{"type": "object"}
+	listKinds := map[schema.GroupVersionResource]string{
+		GVR: "CustomResourceDefinitionList",
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"}:                  "WorkflowList",
+		{Group: "migration.dcnlab.com", Version: "v1", Resource: "statefulmigrations"}:      "StatefulMigrationList",
+		{Group: "migration.dcnlab.com", Version: "v1beta1", Resource: "statefulmigrations"}: "StatefulMigrationList",
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		definition("argoproj.io", "Workflow", "workflows", "Namespaced", version("v1alpha1", true, true, workflowSchema)),
+		definition("migration.dcnlab.com", "StatefulMigration", "statefulmigrations", "Cluster",
+			version("v1beta1", true, false, nil), version("v1", true, true, map[string]interface{}{"type": "object"})),
+		instance("argoproj.io/v1alpha1", "Workflow", "ml", "train"),
+		instance("argoproj.io/v1alpha1", "Workflow", "default", "eval"),
+		instance("migration.dcnlab.com/v1", "StatefulMigration", "", "move-db"),
+	)
+}
+
+func TestList(t *testing.T) {
+	definitions, err := List(context.Background(), newClient(), "", true)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(definitions) != 2 {
+		t.Fatalf("List() returned %d definitions, want 2", len(definitions))
+	}
+
+	migrations, workflows := definitions[0], definitions[1]
+	if migrations.Name != "statefulmigrations.migration.dcnlab.com" || workflows.Name != "workflows.argoproj.io" {
+		t.Fatalf("definitions are not sorted by name: %q, %q", migrations.Name, workflows.Name)
+	}
+	if workflows.Kind != "Workflow" || workflows.Scope != "Namespaced" || !workflows.Established {
+		t.Errorf("workflows = %+v", workflows)
+	}
+	if workflows.Instances != 2 || workflows.StorageVersion != "v1alpha1" {
+		t.Errorf("workflows instances = %d, storage version = %q", workflows.Instances, workflows.StorageVersion)
+	}
+
+	if migrations.Instances != 1 || migrations.StorageVersion != "v1" || len(migrations.Versions) != 2 {
+		t.Errorf("migrations = %+v", migrations)
+	}
+	if migrations.Versions[0].HasSchema || !migrations.Versions[1].HasSchema {
+		t.Errorf("migration versions = %+v", migrations.Versions)
+	}
+}
+
+func TestListGroupWithoutCounts(t *testing.T) {
+	definitions, err := List(context.Background(), newClient(), "argoproj.io", false)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(definitions) != 1 || definitions[0].Group != "argoproj.io" {
+		t.Fatalf("List() = %+v, want only the argoproj.io CRD", definitions)
+	}
+	if definitions[0].Instances != 0 {
+		t.Errorf("Instances = %d without counting", definitions[0].Instances)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	dynamicClient := newClient()
+
+	def, openAPISchema, err := Schema(context.Background(), dynamicClient, "statefulmigrations.migration.dcnlab.com", "")
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	if def.StorageVersion != "v1" || openAPISchema["type"] != "object" {
+		t.Errorf("Schema() = %+v, %v", def, openAPISchema)
+	}
+
+	if _, openAPISchema, err = Schema(context.Background(), dynamicClient, "statefulmigrations.migration.dcnlab.com", "v1beta1"); err != nil || openAPISchema != nil {
+		t.Errorf("Schema(v1beta1) = %v, %v; want no schema", openAPISchema, err)
+	}
+
+	_, _, err = Schema(context.Background(), dynamicClient, "statefulmigrations.migration.dcnlab.com", "v2")
+	if !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Schema(v2) error = %v, want ErrVersionNotFound", err)
+	}
+}
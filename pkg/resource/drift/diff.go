@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Diff returns the paths of the fields of declared that live does not match. Only declared fields are
+// compared, so fields defaulted by the API server or added by controllers are not drift. The status and
+// the metadata other than labels and annotations are ignored.
+func Diff(declared, live map[string]interface{}) []string {
+	var differences []string
+	keys := sortedKeys(declared)
+	for _, key := range keys {
+		switch key {
+		case "status", "apiVersion", "kind":
+			continue
+		case "metadata":
+			declaredMeta, _ := declared[key].(map[string]interface{})
+			liveMeta, _ := live[key].(map[string]interface{})
+			for _, field := range []string{"labels", "annotations"} {
+				if value, ok := declaredMeta[field]; ok {
+					diffValue("metadata."+field, value, liveMeta[field], &differences)
+				}
+			}
+		default:
+			diffValue(key, declared[key], live[key], &differences)
+		}
+	}
+	return differences
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func diffValue(path string, declared, live interface{}, differences *[]string) {
+	switch d := declared.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			if len(d) > 0 || live != nil {
+				*differences = append(*differences, path)
+			}
+			return
+		}
+		for _, key := range sortedKeys(d) {
+			diffValue(path+"."+key, d[key], l[key], differences)
+		}
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			if len(d) > 0 || len(l) > 0 {
+				*differences = append(*differences, path)
+			}
+			return
+		}
+		for i := range d {
+			diffValue(fmt.Sprintf("%s[%d]", path, i), d[i], l[i], differences)
+		}
+	case nil:
+		// A declared null leaves the field to the cluster
+	default:
+		if !scalarEqual(declared, live) {
+			*differences = append(*differences, path)
+		}
+	}
+}
+
+// scalarEqual compares numbers by value and quantities such as 1000m and 1 by amount
+func scalarEqual(declared, live interface{}) bool {
+	if reflect.DeepEqual(declared, live) {
+		return true
+	}
+	if d, ok := toFloat(declared); ok {
+		if l, ok := toFloat(live); ok {
+			return d == l
+		}
+	}
+	// Quantities may be declared as numbers, e.g. cpu: 1
+	ds, dok := scalarString(declared)
+	ls, lok := scalarString(live)
+	if dok && lok {
+		dq, derr := resource.ParseQuantity(ds)
+		lq, lerr := resource.ParseQuantity(ls)
+		return derr == nil && lerr == nil && dq.Cmp(lq) == 0
+	}
+	return false
+}
+
+func scalarString(value interface{}) (string, bool) {
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	if _, ok := toFloat(value); ok {
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	declared := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata": map[string]interface{}{
+			"name":   "checkpoint-backup",
+			"labels": map[string]interface{}{"app": "checkpoint-backup"},
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "agent",
+							"image": "agent:v1",
+							"resources": map[string]interface{}{
+								"limits": map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"numberReady": int64(3)},
+	}
+	live := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata": map[string]interface{}{
+			"name":            "checkpoint-backup",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{"app": "checkpoint-backup", "extra": "label"},
+		},
+		"spec": map[string]interface{}{
+			"revisionHistoryLimit": int64(10),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":            "agent",
+							"image":           "agent:v1",
+							"imagePullPolicy": "IfNotPresent",
+							"resources": map[string]interface{}{
+								"limits": map[string]interface{}{"cpu": "1000m", "memory": "1Gi"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"numberReady": int64(1)},
+	}
+	if differences := Diff(declared, live); len(differences) != 0 {
+		t.Errorf("Diff() == %v, expected no differences for defaulted fields and equal quantities", differences)
+	}
+
+	container := live["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})[0].(map[string]interface{})
+	container["image"] = "agent:v2"
+	live["metadata"].(map[string]interface{})["labels"] = map[string]interface{}{"app": "other"}
+	expected := []string{"metadata.labels.app", "spec.template.spec.containers[0].image"}
+	if differences := Diff(declared, live); !reflect.DeepEqual(differences, expected) {
+		t.Errorf("Diff() == %v, expected %v", differences, expected)
+	}
+}
+
+func TestScalarEqual(t *testing.T) {
+	cases := []struct {
+		declared, live interface{}
+		expected       bool
+	}{
+		{int64(1), float64(1), true},
+		{int64(1), "1", true},
+		{"500m", "0.5", true},
+		{"2Gi", "2048Mi", true},
+		{"nginx", "nginx:latest", false},
+		{true, false, false},
+	}
+	for _, c := range cases {
+		if actual := scalarEqual(c.declared, c.live); actual != c.expected {
+			t.Errorf("scalarEqual(%v, %v) == %v, expected %v", c.declared, c.live, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift compares the resources Karmada declares for each member cluster, which are the
+// manifests of the Works in the execution namespace of the cluster, with the objects in the cluster.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	workv1alpha2 "github.com/karmada-io/karmada/pkg/apis/work/v1alpha2"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	karmadautil "github.com/karmada-io/karmada/pkg/util"
+	"github.com/karmada-io/karmada/pkg/util/names"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// Drift states of an object
+const (
+	// StatusMissing means the object is declared but does not exist in the member cluster
+	StatusMissing = "missing"
+	// StatusModified means the object in the member cluster differs from the declared one
+	StatusModified = "modified"
+	// StatusExtra means the object is managed by Karmada in the member cluster but no longer declared
+	StatusExtra = "extra"
+)
+
+// Options restricts drift detection to a cluster, kind or namespace
+type Options struct {
+	Cluster   string
+	Kind      string
+	Namespace string
+	// IncludeExtra also looks for objects that are managed by Karmada but no longer declared.
+	// Only kinds that are declared for the cluster are checked.
+	IncludeExtra bool
+}
+
+// Item is an object that drifted from its declaration
+type Item struct {
+	Cluster    string `json:"cluster"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	// Work is the Karmada Work that declares the object
+	Work string `json:"work,omitempty"`
+	// Differences are the paths of the declared fields that differ, for modified objects
+	Differences []string `json:"differences,omitempty"`
+
+	declared *unstructured.Unstructured
+	resource schema.GroupVersionResource
+}
+
+// ClusterResult summarizes the drift of a member cluster
+type ClusterResult struct {
+	Name     string `json:"name"`
+	Checked  int    `json:"checked"`
+	Missing  int    `json:"missing"`
+	Modified int    `json:"modified"`
+	Extra    int    `json:"extra"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the drift of the member clusters
+type Report struct {
+	Clusters []ClusterResult `json:"clusters"`
+	Items    []Item          `json:"items"`
+}
+
+// declaredObject is a manifest of a Work
+type declaredObject struct {
+	object *unstructured.Unstructured
+	work   string
+}
+
+func objectKey(gvk schema.GroupVersionKind, namespace, name string) string {
+	return fmt.Sprintf("%s|%s|%s", gvk.String(), namespace, name)
+}
+
+// Detect compares the declared resources of the member clusters with the objects in the clusters
+func Detect(ctx context.Context, karmadaClient karmadaclientset.Interface, opts Options) (*Report, error) {
+	clusters, err := karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %v", err)
+	}
+	sort.Slice(clusters.Items, func(i, j int) bool {
+		return clusters.Items[i].Name < clusters.Items[j].Name
+	})
+
+	report := &Report{Clusters: []ClusterResult{}, Items: []Item{}}
+	found := false
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if opts.Cluster != "" && cluster.Name != opts.Cluster {
+			continue
+		}
+		found = true
+		result := ClusterResult{Name: cluster.Name}
+		items, checked, err := detectCluster(ctx, karmadaClient, cluster, opts)
+		result.Checked = checked
+		if err != nil {
+			klog.ErrorS(err, "Failed to detect drift", "cluster", cluster.Name)
+			result.Error = err.Error()
+		}
+		for _, item := range items {
+			switch item.Status {
+			case StatusMissing:
+				result.Missing++
+			case StatusModified:
+				result.Modified++
+			case StatusExtra:
+				result.Extra++
+			}
+		}
+		report.Items = append(report.Items, items...)
+		report.Clusters = append(report.Clusters, result)
+	}
+	if opts.Cluster != "" && !found {
+		return nil, fmt.Errorf("cluster %s not found", opts.Cluster)
+	}
+	return report, nil
+}
+
+// declaredObjects returns the manifests of the Works of a cluster by object key
+func declaredObjects(ctx context.Context, karmadaClient karmadaclientset.Interface, clusterName string, opts Options) (map[string]declaredObject, error) {
+	works, err := karmadaClient.WorkV1alpha1().Works(names.GenerateExecutionSpaceName(clusterName)).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list works: %v", err)
+	}
+	declared := map[string]declaredObject{}
+	for _, work := range works.Items {
+		for _, manifest := range work.Spec.Workload.Manifests {
+			object := &unstructured.Unstructured{}
+			if err := object.UnmarshalJSON(manifest.Raw); err != nil {
+				klog.V(4).InfoS("Skipping invalid manifest", "work", work.Name, "error", err)
+				continue
+			}
+			if opts.Kind != "" && object.GetKind() != opts.Kind {
+				continue
+			}
+			if opts.Namespace != "" && object.GetNamespace() != opts.Namespace {
+				continue
+			}
+			declared[objectKey(object.GroupVersionKind(), object.GetNamespace(), object.GetName())] = declaredObject{object: object, work: work.Name}
+		}
+	}
+	return declared, nil
+}
+
+// detectCluster returns the drifted objects of a cluster and the number of declared objects checked
+func detectCluster(ctx context.Context, karmadaClient karmadaclientset.Interface, cluster *clusterv1alpha1.Cluster, opts Options) ([]Item, int, error) {
+	declared, err := declaredObjects(ctx, karmadaClient, cluster.Name, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(declared) == 0 {
+		return nil, 0, nil
+	}
+
+	dynamicClient, err := client.DynamicClientForMemberCluster(cluster.Name)
+	if err != nil {
+		return nil, 0, err
+	}
+	kubeClient := client.InClusterClientForMemberCluster(cluster.Name)
+	if kubeClient == nil {
+		return nil, 0, fmt.Errorf("failed to get client for cluster %s", cluster.Name)
+	}
+	// Discovery returns the groups it could read along with an error when an aggregated API is down
+	groupResources, err := restmapper.GetAPIGroupResources(kubeClient.Discovery())
+	if err != nil && len(groupResources) == 0 {
+		return nil, 0, fmt.Errorf("failed to discover the APIs of cluster %s: %v", cluster.Name, err)
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	keys := make([]string, 0, len(declared))
+	for key := range declared {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var items []Item
+	mappings := map[schema.GroupVersionKind]*meta.RESTMapping{}
+	for _, key := range keys {
+		d := declared[key]
+		gvk := d.object.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// The API is not served by the cluster, so the object cannot exist
+			items = append(items, newItem(cluster.Name, d.object, d.work, StatusMissing, schema.GroupVersionResource{}))
+			continue
+		}
+		mappings[gvk] = mapping
+
+		live, err := resourceInterface(dynamicClient, mapping, d.object.GetNamespace()).Get(ctx, d.object.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			items = append(items, newItem(cluster.Name, d.object, d.work, StatusMissing, mapping.Resource))
+			continue
+		}
+		if err != nil {
+			return items, len(keys), fmt.Errorf("failed to get %s %s: %v", gvk.Kind, d.object.GetName(), err)
+		}
+		if differences := Diff(d.object.Object, live.Object); len(differences) > 0 {
+			item := newItem(cluster.Name, d.object, d.work, StatusModified, mapping.Resource)
+			item.Differences = differences
+			items = append(items, item)
+		}
+	}
+
+	if opts.IncludeExtra {
+		executionSpace := names.GenerateExecutionSpaceName(cluster.Name)
+		for gvk, mapping := range mappings {
+			list, err := resourceInterface(dynamicClient, mapping, opts.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s", karmadautil.ManagedByKarmadaLabel, karmadautil.ManagedByKarmadaLabelValue),
+			})
+			if err != nil {
+				return items, len(keys), fmt.Errorf("failed to list %s: %v", mapping.Resource.Resource, err)
+			}
+			for i := range list.Items {
+				live := &list.Items[i]
+				if _, ok := declared[objectKey(gvk, live.GetNamespace(), live.GetName())]; ok {
+					continue
+				}
+				if workNamespace := live.GetAnnotations()[workv1alpha2.WorkNamespaceAnnotation]; workNamespace != "" && workNamespace != executionSpace {
+					continue
+				}
+				live.SetGroupVersionKind(gvk)
+				items = append(items, newItem(cluster.Name, live, live.GetAnnotations()[workv1alpha2.WorkNameAnnotation], StatusExtra, mapping.Resource))
+			}
+		}
+	}
+	return items, len(keys), nil
+}
+
+// newItem describes a drifted object. object is the declared object, or the live one for extra objects.
+func newItem(clusterName string, object *unstructured.Unstructured, work, status string, resource schema.GroupVersionResource) Item {
+	item := Item{
+		Cluster:    clusterName,
+		APIVersion: object.GetAPIVersion(),
+		Kind:       object.GetKind(),
+		Namespace:  object.GetNamespace(),
+		Name:       object.GetName(),
+		Status:     status,
+		Work:       work,
+		resource:   resource,
+	}
+	if status != StatusExtra {
+		item.declared = object
+	}
+	return item
+}
+
+func resourceInterface(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return dynamicClient.Resource(mapping.Resource)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"context"
+	"fmt"
+
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/client"
+)
+
+// ObjectRef selects an object to repair
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// RepairResult is the outcome of repairing a drifted object
+type RepairResult struct {
+	Item   `json:",inline"`
+	Action string `json:"action"` // "created", "updated", "deleted" or "skipped"
+	Error  string `json:"error,omitempty"`
+}
+
+// Repair detects the drift of a cluster and repairs it: missing objects are created and modified
+// objects get their declared fields back. Extra objects are only deleted with deleteExtra.
+// When objects is not empty, only the listed objects are repaired.
+func Repair(ctx context.Context, karmadaClient karmadaclientset.Interface, opts Options, objects []ObjectRef, deleteExtra bool) ([]RepairResult, error) {
+	if opts.Cluster == "" {
+		return nil, fmt.Errorf("a cluster is required to repair drift")
+	}
+	opts.IncludeExtra = deleteExtra
+	report, err := Detect(ctx, karmadaClient, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Clusters) == 1 && report.Clusters[0].Error != "" {
+		return nil, fmt.Errorf("%s", report.Clusters[0].Error)
+	}
+
+	dynamicClient, err := client.DynamicClientForMemberCluster(opts.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	results := []RepairResult{}
+	for _, item := range report.Items {
+		if !selected(item, objects) {
+			continue
+		}
+		result := RepairResult{Item: item}
+		if err := repairItem(ctx, dynamicClient, item, &result); err != nil {
+			result.Error = err.Error()
+			klog.ErrorS(err, "Failed to repair drift", "cluster", item.Cluster, "kind", item.Kind, "namespace", item.Namespace, "name", item.Name)
+		} else {
+			klog.InfoS("Repaired drift", "cluster", item.Cluster, "kind", item.Kind, "namespace", item.Namespace, "name", item.Name, "action", result.Action)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func selected(item Item, objects []ObjectRef) bool {
+	if len(objects) == 0 {
+		return true
+	}
+	for _, ref := range objects {
+		if ref.Kind == item.Kind && ref.Namespace == item.Namespace && ref.Name == item.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func repairItem(ctx context.Context, dynamicClient dynamic.Interface, item Item, result *RepairResult) error {
+	result.Action = "skipped"
+	if item.resource.Resource == "" {
+		return fmt.Errorf("%s is not served by cluster %s", item.APIVersion, item.Cluster)
+	}
+	resources := dynamicClient.Resource(item.resource)
+	var target dynamic.ResourceInterface = resources
+	if item.Namespace != "" {
+		target = resources.Namespace(item.Namespace)
+	}
+
+	switch item.Status {
+	case StatusMissing:
+		object := item.declared.DeepCopy()
+		object.SetResourceVersion("")
+		if _, err := target.Create(ctx, object, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		result.Action = "created"
+	case StatusModified:
+		live, err := target.Get(ctx, item.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		merged := live.DeepCopy()
+		for key, value := range item.declared.Object {
+			switch key {
+			case "status", "apiVersion", "kind":
+				continue
+			case "metadata":
+				for _, field := range []string{"labels", "annotations"} {
+					if values, ok, _ := unstructured.NestedStringMap(item.declared.Object, "metadata", field); ok {
+						current, _, _ := unstructured.NestedStringMap(merged.Object, "metadata", field)
+						if current == nil {
+							current = map[string]string{}
+						}
+						for k, v := range values {
+							current[k] = v
+						}
+						if err := unstructured.SetNestedStringMap(merged.Object, current, "metadata", field); err != nil {
+							return err
+						}
+					}
+				}
+			default:
+				merged.Object[key] = mergeValue(merged.Object[key], value)
+			}
+		}
+		if _, err := target.Update(ctx, merged, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		result.Action = "updated"
+	case StatusExtra:
+		if err := target.Delete(ctx, item.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		result.Action = "deleted"
+	}
+	return nil
+}
+
+// mergeValue sets the declared fields on the live value, keeping the fields only the live value has.
+// Lists are replaced, since their items cannot be matched reliably.
+func mergeValue(live, declared interface{}) interface{} {
+	if declared == nil {
+		return live
+	}
+	d, ok := declared.(map[string]interface{})
+	if !ok {
+		return runtime.DeepCopyJSONValue(declared)
+	}
+	l, ok := live.(map[string]interface{})
+	if !ok {
+		return runtime.DeepCopyJSONValue(declared)
+	}
+	for key, value := range d {
+		l[key] = mergeValue(l[key], value)
+	}
+	return l
+}
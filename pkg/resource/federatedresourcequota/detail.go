@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedresourcequota
+
+import (
+	"context"
+	"sort"
+
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/karmada-io/dashboard/pkg/common/errors"
+)
+
+// FederatedResourceQuotaDetail is a presentation layer view of Karmada FederatedResourceQuota resource,
+// extended with the quota usage of each member cluster.
+type FederatedResourceQuotaDetail struct {
+	// Extends list item structure.
+	FederatedResourceQuota `json:",inline"`
+
+	// Clusters is the enforced quota and usage in each member cluster
+	Clusters []ClusterQuotaUsage `json:"clusters"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// ClusterQuotaUsage is the quota usage of a namespace in one member cluster
+type ClusterQuotaUsage struct {
+	ClusterName string          `json:"clusterName"`
+	Usage       []ResourceUsage `json:"usage"`
+}
+
+// GetFederatedResourceQuotaDetail gets federated resource quota details.
+func GetFederatedResourceQuotaDetail(client karmadaclientset.Interface, namespace, name string) (*FederatedResourceQuotaDetail, error) {
+	quota, err := client.PolicyV1alpha1().FederatedResourceQuotas(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	clusters := make([]ClusterQuotaUsage, 0, len(quota.Status.AggregatedStatus))
+	for _, status := range quota.Status.AggregatedStatus {
+		clusters = append(clusters, ClusterQuotaUsage{
+			ClusterName: status.ClusterName,
+			Usage:       toResourceUsage(status.Hard, status.Used),
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].ClusterName < clusters[j].ClusterName
+	})
+
+	return &FederatedResourceQuotaDetail{
+		FederatedResourceQuota: toFederatedResourceQuota(quota),
+		Clusters:               clusters,
+		Errors:                 nonCriticalErrors,
+	}, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedresourcequota
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/common/helpers"
+	"github.com/karmada-io/dashboard/pkg/common/types"
+	"github.com/karmada-io/dashboard/pkg/dataselect"
+	"github.com/karmada-io/dashboard/pkg/resource/common"
+)
+
+// FederatedResourceQuotaList contains a list of federated resource quotas in the karmada control-plane.
+type FederatedResourceQuotaList struct {
+	ListMeta types.ListMeta `json:"listMeta"`
+
+	// Unordered list of FederatedResourceQuotas.
+	FederatedResourceQuotas []FederatedResourceQuota `json:"federatedResourceQuotas"`
+
+	// List of non-critical errors, that occurred during resource retrieval.
+	Errors []error `json:"errors"`
+}
+
+// FederatedResourceQuota contains the limits of a namespace across all member clusters and their usage.
+type FederatedResourceQuota struct {
+	ObjectMeta        types.ObjectMeta                   `json:"objectMeta"`
+	TypeMeta          types.TypeMeta                     `json:"typeMeta"`
+	Overall           corev1.ResourceList                `json:"overall"`
+	StaticAssignments []v1alpha1.StaticClusterAssignment `json:"staticAssignments"`
+	// Usage compares the enforced limits with the usage aggregated over the member clusters
+	Usage []ResourceUsage `json:"usage"`
+}
+
+// ResourceUsage is the usage of one resource against its limit
+type ResourceUsage struct {
+	Resource corev1.ResourceName `json:"resource"`
+	Hard     string              `json:"hard"`
+	Used     string              `json:"used"`
+	// Percentage is the used share of the limit, 0 when the limit is zero
+	Percentage float64 `json:"percentage"`
+}
+
+// GetFederatedResourceQuotaList returns a list of all federated resource quotas in the Karmada control-plane.
+func GetFederatedResourceQuotaList(client karmadaclientset.Interface, nsQuery *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*FederatedResourceQuotaList, error) {
+	log.Println("Getting list of federatedresourcequota")
+	quotas, err := client.PolicyV1alpha1().FederatedResourceQuotas(nsQuery.ToRequestParam()).List(context.TODO(), helpers.ListEverything)
+	nonCriticalErrors, criticalError := errors.ExtractErrors(err)
+	if criticalError != nil {
+		return nil, criticalError
+	}
+
+	return toFederatedResourceQuotaList(quotas.Items, nonCriticalErrors, dsQuery), nil
+}
+
+func toFederatedResourceQuotaList(quotas []v1alpha1.FederatedResourceQuota, nonCriticalErrors []error, dsQuery *dataselect.DataSelectQuery) *FederatedResourceQuotaList {
+	quotaList := &FederatedResourceQuotaList{
+		FederatedResourceQuotas: make([]FederatedResourceQuota, 0),
+		ListMeta:                types.ListMeta{TotalItems: len(quotas)},
+	}
+	quotaCells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(quotas), dsQuery)
+	quotas = fromCells(quotaCells)
+	quotaList.ListMeta = types.ListMeta{TotalItems: filteredTotal}
+	quotaList.Errors = nonCriticalErrors
+
+	for i := range quotas {
+		quotaList.FederatedResourceQuotas = append(quotaList.FederatedResourceQuotas, toFederatedResourceQuota(&quotas[i]))
+	}
+	return quotaList
+}
+
+func toFederatedResourceQuota(quota *v1alpha1.FederatedResourceQuota) FederatedResourceQuota {
+	overall := quota.Status.Overall
+	if len(overall) == 0 {
+		// The status is empty until Karmada has synced the quota
+		overall = quota.Spec.Overall
+	}
+	staticAssignments := quota.Spec.StaticAssignments
+	if staticAssignments == nil {
+		staticAssignments = []v1alpha1.StaticClusterAssignment{}
+	}
+	return FederatedResourceQuota{
+		ObjectMeta:        types.NewObjectMeta(quota.ObjectMeta),
+		TypeMeta:          types.NewTypeMeta(types.ResourceKindFederatedResourceQuota),
+		Overall:           quota.Spec.Overall,
+		StaticAssignments: staticAssignments,
+		Usage:             toResourceUsage(overall, quota.Status.OverallUsed),
+	}
+}
+
+// toResourceUsage lists the usage of every limited resource, sorted by resource name
+func toResourceUsage(hard, used corev1.ResourceList) []ResourceUsage {
+	usage := make([]ResourceUsage, 0, len(hard))
+	for name, limit := range hard {
+		current := used[name]
+		entry := ResourceUsage{
+			Resource: name,
+			Hard:     limit.String(),
+			Used:     current.String(),
+		}
+		if limit.MilliValue() > 0 {
+			entry.Percentage = float64(current.MilliValue()) / float64(limit.MilliValue()) * 100
+		}
+		usage = append(usage, entry)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].Resource < usage[j].Resource
+	})
+	return usage
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+)
+
+// Attestation records the provenance of a checkpoint artifact the first time it is seen in its store.
+// Attestations are never updated, so a digest that changes afterwards shows the artifact was replaced.
+type Attestation struct {
+	BackupID   string `json:"backupId"`
+	Artifact   string `json:"artifact"`
+	Digest     string `json:"digest"`
+	Size       int64  `json:"size"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+	RecordedAt string `json:"recordedAt"`
+	// Signature is the base64 encoded signature of the SignaturePayload of the artifact, made with KeyID
+	Signature string `json:"signature,omitempty"`
+	KeyID     string `json:"keyId,omitempty"`
+}
+
+// simpleSigning is the payload format cosign signs for container images
+type simpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+// SignaturePayload returns the payload signed for an artifact, in the simple signing format of cosign
+// so the signatures can also be checked with cosign
+func SignaturePayload(reference, digest string) []byte {
+	payload := simpleSigning{}
+	payload.Critical.Identity.DockerReference = reference
+	payload.Critical.Image.DockerManifestDigest = digest
+	payload.Critical.Type = "cosign container image signature"
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+// GenerateSigningKey returns a new PEM encoded ECDSA P-256 private key
+func GenerateSigningKey() ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func parseSigningKey(privatePEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(privatePEM)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key is not an ECDSA key")
+	}
+	return ecdsaKey, nil
+}
+
+// SigningPublicKey returns the PEM encoded public key of a signing key and its key ID
+func SigningPublicKey(privatePEM []byte) ([]byte, string, error) {
+	key, err := parseSigningKey(privatePEM)
+	if err != nil {
+		return nil, "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), KeyFingerprint(der), nil
+}
+
+// Sign returns the base64 encoded ASN.1 ECDSA signature of the SHA-256 digest of payload
+func Sign(privatePEM, payload []byte) (string, error) {
+	key, err := parseSigningKey(privatePEM)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// VerifySignature checks a signature made by Sign against a PEM encoded public key
+func VerifySignature(publicPEM, payload []byte, signature string) error {
+	block, _ := pem.Decode(publicPEM)
+	if block == nil {
+		return fmt.Errorf("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("public key is not an ECDSA key")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("signature is not base64 encoded: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if !ecdsa.VerifyASN1(ecdsaKey, hash[:], decoded) {
+		return fmt.Errorf("signature does not match")
+	}
+	return nil
+}
+
+// Verify checks the signature of an attestation. Unsigned attestations return an error.
+func (a Attestation) Verify(publicPEM []byte) error {
+	if a.Signature == "" {
+		return fmt.Errorf("checkpoint %s is not signed", a.Artifact)
+	}
+	return VerifySignature(publicPEM, SignaturePayload(a.Artifact, a.Digest), a.Signature)
+}
+
+// VerifyCheckpoint checks a checkpoint against the attestations of its backup: its digest must be the one
+// recorded when it was first seen, and its signature must be valid when it is signed or signatures are
+// required
+func VerifyCheckpoint(artifact, digest string, attestations []Attestation, publicPEM []byte, requireSignature bool) error {
+	var recorded *Attestation
+	for i := range attestations {
+		if attestations[i].Artifact != artifact {
+			continue
+		}
+		if attestations[i].Digest == digest {
+			recorded = &attestations[i]
+			break
+		}
+		// A replaced artifact keeps its name, its first attestation still has the original digest
+		return fmt.Errorf("digest of checkpoint %s changed from %s to %s since it was recorded", artifact, attestations[i].Digest, digest)
+	}
+	if recorded == nil {
+		if requireSignature {
+			return fmt.Errorf("checkpoint %s has no recorded provenance", artifact)
+		}
+		return nil
+	}
+	if recorded.Signature == "" && !requireSignature {
+		return nil
+	}
+	return recorded.Verify(publicPEM)
+}
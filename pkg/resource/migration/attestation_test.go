@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	privateKey, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	publicKey, keyID, err := SigningPublicKey(privateKey)
+	if err != nil {
+		t.Fatalf("SigningPublicKey() error = %v", err)
+	}
+	if keyID == "" {
+		t.Errorf("SigningPublicKey() returned an empty key ID")
+	}
+
+	payload := SignaturePayload("registry.example.com/backups/db:latest", "sha256:abc")
+	signature, err := Sign(privateKey, payload)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if err := VerifySignature(publicKey, payload, signature); err != nil {
+		t.Errorf("VerifySignature() error = %v", err)
+	}
+	tampered := SignaturePayload("registry.example.com/backups/db:latest", "sha256:def")
+	if err := VerifySignature(publicKey, tampered, signature); err == nil {
+		t.Errorf("VerifySignature() accepted the signature of another digest")
+	}
+
+	otherKey, _ := GenerateSigningKey()
+	otherPublicKey, _, _ := SigningPublicKey(otherKey)
+	if err := VerifySignature(otherPublicKey, payload, signature); err == nil {
+		t.Errorf("VerifySignature() accepted a signature of another key")
+	}
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	privateKey, _ := GenerateSigningKey()
+	publicKey, keyID, _ := SigningPublicKey(privateKey)
+	signed := Attestation{Artifact: "backups/db:1", Digest: "sha256:aaa", KeyID: keyID}
+	signed.Signature, _ = Sign(privateKey, SignaturePayload(signed.Artifact, signed.Digest))
+	unsigned := Attestation{Artifact: "backups/db:2", Digest: "sha256:bbb"}
+	forged := Attestation{Artifact: "backups/db:3", Digest: "sha256:ccc", Signature: signed.Signature}
+	attestations := []Attestation{signed, unsigned, forged}
+
+	tests := []struct {
+		name             string
+		artifact         string
+		digest           string
+		requireSignature bool
+		wantErr          bool
+	}{
+		{name: "signed", artifact: "backups/db:1", digest: "sha256:aaa", requireSignature: true},
+		{name: "replaced", artifact: "backups/db:1", digest: "sha256:zzz", wantErr: true},
+		{name: "unsigned", artifact: "backups/db:2", digest: "sha256:bbb"},
+		{name: "unsigned when required", artifact: "backups/db:2", digest: "sha256:bbb", requireSignature: true, wantErr: true},
+		{name: "invalid signature", artifact: "backups/db:3", digest: "sha256:ccc", wantErr: true},
+		{name: "not recorded", artifact: "backups/db:4", digest: "sha256:ddd"},
+		{name: "not recorded when required", artifact: "backups/db:4", digest: "sha256:ddd", requireSignature: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyCheckpoint(tt.artifact, tt.digest, attestations, publicKey, tt.requireSignature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCheckpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// Environment variables the migration controllers read their runtime settings from
+const (
+	EnvCheckpointConcurrency = "CHECKPOINT_CONCURRENCY"
+	EnvResyncInterval        = "RESYNC_INTERVAL"
+	// EnvUploadBandwidthLimit is the upload rate limit of checkpoints in bytes per second
+	EnvUploadBandwidthLimit = "UPLOAD_BANDWIDTH_LIMIT"
+)
+
+// ControllerConfigHashAnnotation is set on the pod template to the hash of the controller settings, so the
+// pods restart when the settings in the ConfigMap change
+const ControllerConfigHashAnnotation = "ml-platform.io/controller-config-hash"
+
+// MaxCheckpointConcurrency bounds the checkpoints a controller takes in parallel
+const MaxCheckpointConcurrency = config.MaxCheckpointConcurrency
+
+// ControllerConfig holds the runtime settings of a migration controller. Unset fields use the defaults of
+// the controller and of its manifests.
+type ControllerConfig struct {
+	// CheckpointConcurrency is the number of checkpoints the controller takes in parallel on a node
+	CheckpointConcurrency int `json:"checkpointConcurrency,omitempty"`
+	// UploadBandwidthLimit caps the upload rate of checkpoints per node, as bytes per second like 50Mi
+	UploadBandwidthLimit string `json:"uploadBandwidthLimit,omitempty"`
+	// ResyncInterval is how often the controller resyncs its resources, as a duration like 10m
+	ResyncInterval  string                       `json:"resyncInterval,omitempty"`
+	ImagePullPolicy corev1.PullPolicy            `json:"imagePullPolicy,omitempty"`
+	Resources       *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// Validate checks the settings
+func (c ControllerConfig) Validate() error {
+	if c.CheckpointConcurrency < 0 || c.CheckpointConcurrency > MaxCheckpointConcurrency {
+		return fmt.Errorf("checkpointConcurrency must be at most %d, or 0 for the controller default", MaxCheckpointConcurrency)
+	}
+	if c.UploadBandwidthLimit != "" {
+		if _, err := ParseBandwidth(c.UploadBandwidthLimit); err != nil {
+			return err
+		}
+	}
+	if c.ResyncInterval != "" {
+		interval, err := time.ParseDuration(c.ResyncInterval)
+		if err != nil {
+			return fmt.Errorf("invalid resyncInterval %q: %v", c.ResyncInterval, err)
+		}
+		if interval < time.Second {
+			return fmt.Errorf("resyncInterval must be at least 1s")
+		}
+	}
+	switch c.ImagePullPolicy {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+	default:
+		return fmt.Errorf("invalid imagePullPolicy %q", c.ImagePullPolicy)
+	}
+	if c.Resources != nil {
+		for name, request := range c.Resources.Requests {
+			if limit, ok := c.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				return fmt.Errorf("%s request %s exceeds its limit %s", name, request.String(), limit.String())
+			}
+		}
+	}
+	return nil
+}
+
+// ConfigMapData returns the settings the controller reads from its ConfigMap, keyed by environment variable
+func (c ControllerConfig) ConfigMapData() map[string]string {
+	data := map[string]string{}
+	if c.CheckpointConcurrency > 0 {
+		data[EnvCheckpointConcurrency] = strconv.Itoa(c.CheckpointConcurrency)
+	}
+	if c.ResyncInterval != "" {
+		data[EnvResyncInterval] = c.ResyncInterval
+	}
+	if bytes, err := ParseBandwidth(c.UploadBandwidthLimit); err == nil {
+		data[EnvUploadBandwidthLimit] = strconv.FormatInt(bytes, 10)
+	}
+	return data
+}
+
+// hash returns a hash of the settings that changes whenever one of them does
+func (c ControllerConfig) hash() string {
+	encoded, _ := json.Marshal(c)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ReadControllerConfig returns the settings of a controller workload and of the data of its ConfigMap
+func ReadControllerConfig(workload *unstructured.Unstructured, containerName string, data map[string]string) (ControllerConfig, error) {
+	var cfg ControllerConfig
+	if value := data[EnvCheckpointConcurrency]; value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid %s %q: %v", EnvCheckpointConcurrency, value, err)
+		}
+		cfg.CheckpointConcurrency = concurrency
+	}
+	cfg.ResyncInterval = data[EnvResyncInterval]
+	if value := data[EnvUploadBandwidthLimit]; value != "" {
+		bytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid %s %q: %v", EnvUploadBandwidthLimit, value, err)
+		}
+		cfg.UploadBandwidthLimit = formatBandwidth(bytes)
+	}
+
+	containers, index, err := workloadContainer(workload, containerName)
+	if err != nil {
+		return cfg, err
+	}
+	var container corev1.Container
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(containers[index].(map[string]interface{}), &container); err != nil {
+		return cfg, fmt.Errorf("failed to decode container %s: %v", containerName, err)
+	}
+	cfg.ImagePullPolicy = container.ImagePullPolicy
+	if len(container.Resources.Requests) > 0 || len(container.Resources.Limits) > 0 {
+		cfg.Resources = &container.Resources
+	}
+	return cfg, nil
+}
+
+// ApplyControllerConfig sets the settings on a controller workload. The container reads its environment from
+// the ConfigMap, the pull policy and resources are set on it and the pods restart when the settings change.
+func ApplyControllerConfig(workload *unstructured.Unstructured, containerName, configMapName string, cfg ControllerConfig) error {
+	containers, index, err := workloadContainer(workload, containerName)
+	if err != nil {
+		return err
+	}
+	container := containers[index].(map[string]interface{})
+
+	configMapRef := map[string]interface{}{"configMapRef": map[string]interface{}{"name": configMapName, "optional": true}}
+	envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+	found := false
+	for _, source := range envFrom {
+		if name, _, _ := unstructured.NestedString(source.(map[string]interface{}), "configMapRef", "name"); name == configMapName {
+			found = true
+		}
+	}
+	if !found {
+		container["envFrom"] = append(envFrom, configMapRef)
+	}
+
+	if cfg.ImagePullPolicy != "" {
+		container["imagePullPolicy"] = string(cfg.ImagePullPolicy)
+	} else {
+		delete(container, "imagePullPolicy")
+	}
+	if cfg.Resources != nil {
+		resources, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cfg.Resources)
+		if err != nil {
+			return fmt.Errorf("failed to encode resources: %v", err)
+		}
+		container["resources"] = resources
+	} else {
+		delete(container, "resources")
+	}
+	containers[index] = container
+	if err := unstructured.SetNestedSlice(workload.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+		return fmt.Errorf("failed to set containers: %v", err)
+	}
+	return unstructured.SetNestedField(workload.Object, cfg.hash(), "spec", "template", "metadata", "annotations", ControllerConfigHashAnnotation)
+}
+
+// workloadContainer returns the containers of a Deployment or DaemonSet and the index of the named one
+func workloadContainer(workload *unstructured.Unstructured, containerName string) ([]interface{}, int, error) {
+	containers, _, err := unstructured.NestedSlice(workload.Object, "spec", "template", "spec", "containers")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get containers of %s: %v", workload.GetName(), err)
+	}
+	for i, container := range containers {
+		if containerMap, ok := container.(map[string]interface{}); ok && containerMap["name"] == containerName {
+			return containers, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%s %s has no container %s", workload.GetKind(), workload.GetName(), containerName)
+}
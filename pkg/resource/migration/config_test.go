@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestControllerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ControllerConfig
+		wantErr bool
+	}{
+		{name: "defaults", cfg: ControllerConfig{}},
+		{name: "valid", cfg: ControllerConfig{CheckpointConcurrency: 4, ResyncInterval: "10m", ImagePullPolicy: corev1.PullAlways}},
+		{name: "concurrency too high", cfg: ControllerConfig{CheckpointConcurrency: MaxCheckpointConcurrency + 1}, wantErr: true},
+		{name: "invalid interval", cfg: ControllerConfig{ResyncInterval: "often"}, wantErr: true},
+		{name: "interval too short", cfg: ControllerConfig{ResyncInterval: "100ms"}, wantErr: true},
+		{name: "invalid pull policy", cfg: ControllerConfig{ImagePullPolicy: "Sometimes"}, wantErr: true},
+		{name: "request above limit", cfg: ControllerConfig{Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyControllerConfig(t *testing.T) {
+	daemonSet := newDaemonSet("checkpoint-backup-controller-member1", 1)
+	cfg := ControllerConfig{
+		CheckpointConcurrency: 4,
+		ResyncInterval:        "10m",
+		ImagePullPolicy:       corev1.PullIfNotPresent,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+	}
+	if err := ApplyControllerConfig(daemonSet, "manager", "checkpoint-backup-config-member1", cfg); err != nil {
+		t.Fatalf("ApplyControllerConfig() error = %v", err)
+	}
+	// Applying again must not add the ConfigMap reference twice
+	if err := ApplyControllerConfig(daemonSet, "manager", "checkpoint-backup-config-member1", cfg); err != nil {
+		t.Fatalf("ApplyControllerConfig() error = %v", err)
+	}
+
+	got, err := ReadControllerConfig(daemonSet, "manager", cfg.ConfigMapData())
+	if err != nil {
+		t.Fatalf("ReadControllerConfig() error = %v", err)
+	}
+	if got.CheckpointConcurrency != 4 || got.ResyncInterval != "10m" || got.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("ReadControllerConfig() = %+v, want the applied settings", got)
+	}
+	if got.Resources == nil || !got.Resources.Limits.Memory().Equal(resource.MustParse("256Mi")) {
+		t.Errorf("ReadControllerConfig() resources = %+v, want a 256Mi memory limit", got.Resources)
+	}
+
+	containers, index, _ := workloadContainer(daemonSet, "manager")
+	envFrom := containers[index].(map[string]interface{})["envFrom"].([]interface{})
+	if len(envFrom) != 1 {
+		t.Errorf("envFrom = %v, want a single ConfigMap reference", envFrom)
+	}
+	annotations, _, _ := unstructured.NestedStringMap(daemonSet.Object, "spec", "template", "metadata", "annotations")
+	if annotations[ControllerConfigHashAnnotation] != cfg.hash() {
+		t.Errorf("config hash annotation = %q, want %q", annotations[ControllerConfigHashAnnotation], cfg.hash())
+	}
+
+	// Resetting the settings removes them from the container
+	if err := ApplyControllerConfig(daemonSet, "manager", "checkpoint-backup-config-member1", ControllerConfig{}); err != nil {
+		t.Fatalf("ApplyControllerConfig() error = %v", err)
+	}
+	if got, _ := ReadControllerConfig(daemonSet, "manager", nil); !reflect.DeepEqual(got, ControllerConfig{}) {
+		t.Errorf("ReadControllerConfig() after reset = %+v, want defaults", got)
+	}
+
+	if err := ApplyControllerConfig(daemonSet, "missing", "cm", cfg); err == nil {
+		t.Error("ApplyControllerConfig() of a missing container succeeded")
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// sidecarContainers are the names of the proxies injected by service meshes, which hold no state worth
+// checkpointing
+var sidecarContainers = map[string]bool{
+	"istio-proxy":      true,
+	"linkerd-proxy":    true,
+	"envoy":            true,
+	"envoy-sidecar":    true,
+	"cilium-envoy":     true,
+	"consul-dataplane": true,
+}
+
+// ContainerInfo is a container of a workload that can be selected for a backup
+type ContainerInfo struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// Sidecar is set for mesh proxies and native sidecars, which backups usually skip
+	Sidecar bool `json:"sidecar,omitempty"`
+}
+
+// IsSidecar reports whether a container is a native sidecar or a known mesh proxy
+func IsSidecar(container corev1.Container) bool {
+	if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+		return true
+	}
+	return sidecarContainers[container.Name]
+}
+
+// PodContainers returns the long running containers of a pod spec: its containers and native sidecars.
+// Init containers that run to completion have nothing to checkpoint and are left out.
+func PodContainers(spec *corev1.PodSpec) []ContainerInfo {
+	containers := make([]ContainerInfo, 0, len(spec.Containers))
+	for _, container := range spec.InitContainers {
+		if IsSidecar(container) {
+			containers = append(containers, ContainerInfo{Name: container.Name, Image: container.Image, Sidecar: true})
+		}
+	}
+	for _, container := range spec.Containers {
+		containers = append(containers, ContainerInfo{Name: container.Name, Image: container.Image, Sidecar: IsSidecar(container)})
+	}
+	return containers
+}
+
+// ValidateContainers checks a container selection of a backup. When the pod spec of the workload is
+// known, each selected container must be one of its long running containers.
+func ValidateContainers(selected []string, spec *corev1.PodSpec) error {
+	seen := make(map[string]bool, len(selected))
+	for _, name := range selected {
+		if name == "" {
+			return fmt.Errorf("container names cannot be empty")
+		}
+		if seen[name] {
+			return fmt.Errorf("container %s is selected more than once", name)
+		}
+		seen[name] = true
+	}
+	if spec == nil {
+		return nil
+	}
+	available := map[string]bool{}
+	for _, container := range PodContainers(spec) {
+		available[container.Name] = true
+	}
+	for _, name := range selected {
+		if !available[name] {
+			return fmt.Errorf("container %s is not a container of the workload", name)
+		}
+	}
+	return nil
+}
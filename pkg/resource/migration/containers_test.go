@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodContainers(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	spec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "migrate", Image: "migrate:1"},
+			{Name: "log-shipper", Image: "fluent-bit:2", RestartPolicy: &always},
+		},
+		Containers: []corev1.Container{
+			{Name: "app", Image: "app:1"},
+			{Name: "istio-proxy", Image: "proxyv2:1.22"},
+		},
+	}
+	want := []ContainerInfo{
+		{Name: "log-shipper", Image: "fluent-bit:2", Sidecar: true},
+		{Name: "app", Image: "app:1"},
+		{Name: "istio-proxy", Image: "proxyv2:1.22", Sidecar: true},
+	}
+	if got := PodContainers(spec); !reflect.DeepEqual(got, want) {
+		t.Errorf("PodContainers() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateContainers(t *testing.T) {
+	spec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}}}
+	tests := []struct {
+		name     string
+		selected []string
+		spec     *corev1.PodSpec
+		wantErr  bool
+	}{
+		{name: "all containers", selected: nil, spec: spec},
+		{name: "app only", selected: []string{"app"}, spec: spec},
+		{name: "unknown container", selected: []string{"db"}, spec: spec, wantErr: true},
+		{name: "unknown workload", selected: []string{"db"}},
+		{name: "duplicate", selected: []string{"app", "app"}, wantErr: true},
+		{name: "empty name", selected: []string{""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateContainers(tt.selected, tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateContainers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
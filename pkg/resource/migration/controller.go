@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Namespace is the namespace of the migration controllers and of the backup CRs
+const Namespace = "stateful-migration"
+
+// Controller components, also the prefix of the version in their image tag
+const (
+	ComponentMigrationBackup  = "migrationBackup"
+	ComponentMigrationRestore = "migrationRestore"
+	ComponentCheckpointBackup = "checkpointBackup"
+)
+
+// Installation states of the migration controller of a cluster
+const (
+	StatusInstalled    = "installed"
+	StatusPartial      = "partial"
+	StatusNotInstalled = "not-installed"
+	StatusUnknown      = "unknown"
+	StatusError        = "error"
+)
+
+// ControllerSelectorLabel marks the checkpoint backup controller resources installed on every cluster matching
+// a label selector rather than on a single cluster. Its value is the name of the selector.
+const ControllerSelectorLabel = "ml-platform.io/controller-selector"
+
+// UnknownVersion is returned when no version can be read from the controller images
+const UnknownVersion = "unknown"
+
+var daemonSetGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+
+// ImageVersion returns the version of a component from an image like
+// docker.io/lehuannhatrang/stateful-migration-operator:migrationBackup_v1.21
+func ImageVersion(image, component string) (string, bool) {
+	_, version, found := strings.Cut(image, ":"+component+"_")
+	return version, found
+}
+
+// DeploymentVersion returns the version of the component a deployment runs
+func DeploymentVersion(deployment *appsv1.Deployment, component string) string {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if version, ok := ImageVersion(container.Image, component); ok {
+			return version
+		}
+	}
+	return UnknownVersion
+}
+
+// DaemonSetVersion returns the version of the component an unstructured DaemonSet runs
+func DaemonSetVersion(daemonSet map[string]interface{}, component string) string {
+	containers, _, _ := unstructured.NestedSlice(daemonSet, "spec", "template", "spec", "containers")
+	for _, container := range containers {
+		containerMap, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		image, _, _ := unstructured.NestedString(containerMap, "image")
+		if version, ok := ImageVersion(image, component); ok {
+			return version
+		}
+	}
+	return UnknownVersion
+}
+
+// ManagementControllerStatus returns the state and version of the migration backup and restore controllers of
+// the management cluster. The StatefulMigration CRD must be served, it is listed in the namespace.
+func ManagementControllerStatus(ctx context.Context, k8sClient kubernetes.Interface, dynamicClient dynamic.Interface, namespace string) (status, version string, err error) {
+	controllers := []struct {
+		component string
+		name      string
+	}{
+		{ComponentMigrationBackup, "migration-backup-controller"},
+		{ComponentMigrationRestore, "migration-restore-controller"},
+	}
+	deployments := make([][]appsv1.Deployment, 0, len(controllers))
+	for _, controller := range controllers {
+		list, err := k8sClient.AppsV1().Deployments(Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "app.kubernetes.io/name=" + controller.name,
+		})
+		if err != nil {
+			return StatusError, "", fmt.Errorf("failed to check %s controller: %v", controller.component, err)
+		}
+		deployments = append(deployments, list.Items)
+	}
+
+	// Listing fails when the CRD does not exist
+	_, err = dynamicClient.Resource(Backup.Resource).Namespace(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return StatusError, "", fmt.Errorf("statefulMigration CRD not found or not accessible: %v", err)
+	}
+
+	ready := 0
+	for i, controller := range controllers {
+		if len(deployments[i]) == 0 || deployments[i][0].Status.ReadyReplicas == 0 {
+			continue
+		}
+		ready++
+		if version == "" {
+			version = DeploymentVersion(&deployments[i][0], controller.component)
+		}
+	}
+
+	switch ready {
+	case 0:
+		return StatusNotInstalled, "", nil
+	case len(controllers):
+		return StatusInstalled, version, nil
+	default:
+		return StatusPartial, version, fmt.Errorf("only %d of %d controllers are ready", ready, len(controllers))
+	}
+}
+
+// MemberControllerStatus returns the state and version of the checkpoint backup controller of a member cluster.
+// The CheckpointBackup and CheckpointRestore CRDs must be served by the cluster.
+func MemberControllerStatus(ctx context.Context, dynamicClient dynamic.Interface, clusterName string) (status, version string, err error) {
+	for _, resource := range []string{"checkpointbackups", "checkpointrestores"} {
+		gvr := schema.GroupVersionResource{Group: Group, Version: "v1", Resource: resource}
+		if _, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+			return StatusError, "", fmt.Errorf("%s CRD not found: %v", resource, err)
+		}
+	}
+
+	// The DaemonSet is named after the cluster when the dashboard installed it, manual deployments use the generic name
+	// and installs for a label selector are named after the selector
+	daemonSets := dynamicClient.Resource(daemonSetGVR).Namespace(Namespace)
+	daemonSet, err := daemonSets.Get(ctx, fmt.Sprintf("checkpoint-backup-controller-%s", clusterName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		daemonSet, err = daemonSets.Get(ctx, "checkpoint-backup-controller", metav1.GetOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		selected, listErr := daemonSets.List(ctx, metav1.ListOptions{LabelSelector: ControllerSelectorLabel})
+		if listErr != nil {
+			return StatusError, "", fmt.Errorf("failed to check checkpointBackup controller DaemonSet: %v", listErr)
+		}
+		if len(selected.Items) == 0 {
+			return StatusNotInstalled, "", nil
+		}
+		daemonSet, err = &selected.Items[0], nil
+	}
+	if err != nil {
+		return StatusError, "", fmt.Errorf("failed to check checkpointBackup controller DaemonSet: %v", err)
+	}
+
+	numberReady, found, err := unstructured.NestedFieldNoCopy(daemonSet.Object, "status", "numberReady")
+	if err != nil || !found {
+		return StatusError, "", fmt.Errorf("DaemonSet numberReady not available")
+	}
+	version = DaemonSetVersion(daemonSet.Object, ComponentCheckpointBackup)
+
+	var ready int64
+	switch v := numberReady.(type) {
+	case int64:
+		ready = v
+	case float64:
+		ready = int64(v)
+	default:
+		return StatusError, version, fmt.Errorf("unexpected numberReady type: %T", numberReady)
+	}
+	if ready == 0 {
+		return StatusPartial, version, fmt.Errorf("DaemonSet not ready")
+	}
+	return StatusInstalled, version, nil
+}
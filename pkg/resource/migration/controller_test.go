@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+const operatorImage = "docker.io/lehuannhatrang/stateful-migration-operator"
+
+func newDeployment(name, component string, readyReplicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: Namespace,
+			Labels:    map[string]string{"app.kubernetes.io/name": name},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "manager", Image: operatorImage + ":" + component + "_v1.21"},
+			}}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: readyReplicas},
+	}
+}
+
+func newDaemonSet(name string, numberReady int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "manager", "image": operatorImage + ":checkpointBackup_v2.0"},
+			},
+		}}},
+		"status": map[string]interface{}{"numberReady": numberReady},
+	}}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("DaemonSet")
+	obj.SetNamespace(Namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func newSelectorDaemonSet(name, selectorName string) *unstructured.Unstructured {
+	obj := newDaemonSet(name, 1)
+	obj.SetLabels(map[string]string{ControllerSelectorLabel: selectorName})
+	return obj
+}
+
+func newFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{
+		Backup.Resource: "StatefulMigrationList",
+		{Group: Group, Version: "v1", Resource: "checkpointbackups"}:  "CheckpointBackupList",
+		{Group: Group, Version: "v1", Resource: "checkpointrestores"}: "CheckpointRestoreList",
+		daemonSetGVR: "DaemonSetList",
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func TestImageVersion(t *testing.T) {
+	if version, ok := ImageVersion(operatorImage+":migrationBackup_v1.21", ComponentMigrationBackup); !ok || version != "v1.21" {
+		t.Errorf("ImageVersion() = %q, %v, want v1.21", version, ok)
+	}
+	if _, ok := ImageVersion(operatorImage+":checkpointBackup_v1.21", ComponentMigrationBackup); ok {
+		t.Error("ImageVersion() matched the image of another component")
+	}
+	if got := DeploymentVersion(newDeployment("c", ComponentMigrationRestore, 1), ComponentMigrationRestore); got != "v1.21" {
+		t.Errorf("DeploymentVersion() = %q, want v1.21", got)
+	}
+	if got := DaemonSetVersion(newDaemonSet("c", 1).Object, ComponentCheckpointBackup); got != "v2.0" {
+		t.Errorf("DaemonSetVersion() = %q, want v2.0", got)
+	}
+	if got := DaemonSetVersion(map[string]interface{}{}, ComponentCheckpointBackup); got != UnknownVersion {
+		t.Errorf("DaemonSetVersion() of an empty object = %q, want %q", got, UnknownVersion)
+	}
+}
+
+func TestManagementControllerStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		deployments []runtime.Object
+		wantStatus  string
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:       "not installed",
+			wantStatus: StatusNotInstalled,
+		},
+		{
+			name: "installed",
+			deployments: []runtime.Object{
+				newDeployment("migration-backup-controller", ComponentMigrationBackup, 1),
+				newDeployment("migration-restore-controller", ComponentMigrationRestore, 1),
+			},
+			wantStatus:  StatusInstalled,
+			wantVersion: "v1.21",
+		},
+		{
+			name: "restore controller not ready",
+			deployments: []runtime.Object{
+				newDeployment("migration-backup-controller", ComponentMigrationBackup, 1),
+				newDeployment("migration-restore-controller", ComponentMigrationRestore, 0),
+			},
+			wantStatus:  StatusPartial,
+			wantVersion: "v1.21",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, version, err := ManagementControllerStatus(context.TODO(), kubefake.NewSimpleClientset(tt.deployments...),
+				newFakeDynamicClient(), "karmada-system")
+			if status != tt.wantStatus || version != tt.wantVersion || (err != nil) != tt.wantErr {
+				t.Errorf("ManagementControllerStatus() = %q, %q, %v, want %q, %q, error %v",
+					status, version, err, tt.wantStatus, tt.wantVersion, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMemberControllerStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		daemonSets  []runtime.Object
+		wantStatus  string
+		wantVersion string
+		wantErr     bool
+	}{
+		{
+			name:       "not installed",
+			wantStatus: StatusNotInstalled,
+		},
+		{
+			name:        "installed by the dashboard",
+			daemonSets:  []runtime.Object{newDaemonSet("checkpoint-backup-controller-member1", 2)},
+			wantStatus:  StatusInstalled,
+			wantVersion: "v2.0",
+		},
+		{
+			name:        "installed for a label selector",
+			daemonSets:  []runtime.Object{newSelectorDaemonSet("checkpoint-backup-controller-selector-1a2b3c4d", "selector-1a2b3c4d")},
+			wantStatus:  StatusInstalled,
+			wantVersion: "v2.0",
+		},
+		{
+			name:        "installed manually and not ready",
+			daemonSets:  []runtime.Object{newDaemonSet("checkpoint-backup-controller", 0)},
+			wantStatus:  StatusPartial,
+			wantVersion: "v2.0",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, version, err := MemberControllerStatus(context.TODO(), newFakeDynamicClient(tt.daemonSets...), "member1")
+			if status != tt.wantStatus || version != tt.wantVersion || (err != nil) != tt.wantErr {
+				t.Errorf("MemberControllerStatus() = %q, %q, %v, want %q, %q, error %v",
+					status, version, err, tt.wantStatus, tt.wantVersion, tt.wantErr)
+			}
+		})
+	}
+}
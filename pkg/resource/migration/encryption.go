@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Providers of the keys that encrypt checkpoint artifacts
+const (
+	// EncryptionProviderSecret keeps the key material in a Secret propagated to the member clusters
+	EncryptionProviderSecret = "secret"
+	// EncryptionProviderKMS references a key of an external KMS, the member clusters unwrap it themselves
+	EncryptionProviderKMS = "kms"
+)
+
+// EncryptionAlgorithm is the cipher checkpoint artifacts are encrypted with
+const EncryptionAlgorithm = "aes-256-gcm"
+
+// encryptionKeySize is the size in bytes of an AES-256 key
+const encryptionKeySize = 32
+
+// kmsSchemes are the KMS key URI schemes the checkpoint controllers support
+var kmsSchemes = []string{"awskms://", "gcpkms://", "azurekms://", "hashivault://"}
+
+// GenerateEncryptionKey returns a random base64 encoded AES-256 key
+func GenerateEncryptionKey() (string, error) {
+	key := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseEncryptionKey decodes a base64 encoded AES-256 key
+func ParseEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+	}
+	if len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", encryptionKeySize, len(key))
+	}
+	return key, nil
+}
+
+// ValidateKMSKeyURI checks that a KMS key reference uses a supported scheme
+func ValidateKMSKeyURI(uri string) error {
+	for _, scheme := range kmsSchemes {
+		if strings.HasPrefix(uri, scheme) && len(uri) > len(scheme) {
+			return nil
+		}
+	}
+	return fmt.Errorf("kms key URI must start with one of %s", strings.Join(kmsSchemes, ", "))
+}
+
+// KeyFingerprint identifies a key without revealing it, so users can check which key a backup uses
+func KeyFingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestGenerateEncryptionKey(t *testing.T) {
+	encoded, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatalf("GenerateEncryptionKey() error = %v", err)
+	}
+	key, err := ParseEncryptionKey(encoded)
+	if err != nil {
+		t.Fatalf("ParseEncryptionKey() of a generated key error = %v", err)
+	}
+	other, _ := GenerateEncryptionKey()
+	if encoded == other {
+		t.Errorf("GenerateEncryptionKey() returned the same key twice")
+	}
+	if len(KeyFingerprint(key)) != 16 {
+		t.Errorf("KeyFingerprint() = %q, want 16 hex characters", KeyFingerprint(key))
+	}
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr bool
+	}{
+		{name: "aes-256 key", encoded: base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		{name: "short key", encoded: base64.StdEncoding.EncodeToString(make([]byte, 16)), wantErr: true},
+		{name: "not base64", encoded: "not a key!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseEncryptionKey(tt.encoded); (err != nil) != tt.wantErr {
+				t.Errorf("ParseEncryptionKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKMSKeyURI(t *testing.T) {
+	for uri, valid := range map[string]bool{
+		"awskms:///arn:aws:kms:eu-west-1:123456789012:key/abcd": true,
+		"hashivault://checkpoints":                              true,
+		"awskms://":                                             false,
+		"https://kms.example.com/key":                           false,
+	} {
+		if err := ValidateKMSKeyURI(uri); (err == nil) != valid {
+			t.Errorf("ValidateKMSKeyURI(%q) error = %v, want valid %v", uri, err, valid)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ManagedByAnnotation records which tool owns a StatefulMigration CR. The dashboard sets it on the CRs it
+// creates; any other value marks the CR as managed from Git.
+const ManagedByAnnotation = "ml-platform.io/managed-by"
+
+// Owners of a StatefulMigration CR
+const (
+	ManagedByDashboard = "dashboard"
+	ManagedByGitOps    = "gitops"
+)
+
+// ArgoCD tracking metadata, set on the resources an ArgoCD application deploys
+const (
+	argoTrackingAnnotation = "argocd.argoproj.io/tracking-id"
+	argoInstanceLabel      = "app.kubernetes.io/instance"
+)
+
+// ErrGitOpsManaged is returned when the dashboard is asked to change a CR that is managed from Git
+var ErrGitOpsManaged = errors.New("managed by gitops")
+
+// GitOpsManagedError rejects the change of a CR that is managed from Git, where the change must be made instead
+type GitOpsManagedError struct {
+	Name string
+	// Application is the ArgoCD application deploying the CR, if known
+	Application string
+}
+
+func (e *GitOpsManagedError) Error() string {
+	source := "its Git repository"
+	if e.Application != "" {
+		source = fmt.Sprintf("the Git repository of ArgoCD application %s", e.Application)
+	}
+	return fmt.Sprintf("%s is managed by GitOps and read-only in the dashboard, change it in %s instead", e.Name, source)
+}
+
+func (e *GitOpsManagedError) Is(target error) bool {
+	return target == ErrGitOpsManaged
+}
+
+// ManagedBy returns who owns a CR. CRs deployed by ArgoCD or annotated with another owner are managed by
+// GitOps, while CRs without any marker were created by the dashboard before it annotated them.
+func ManagedBy(obj *unstructured.Unstructured) string {
+	if GitOpsApplication(obj) != "" {
+		return ManagedByGitOps
+	}
+	if owner, ok := obj.GetAnnotations()[ManagedByAnnotation]; ok && owner != ManagedByDashboard {
+		return ManagedByGitOps
+	}
+	return ManagedByDashboard
+}
+
+// GitOpsApplication returns the ArgoCD application that deploys a CR, from its tracking annotation or label
+func GitOpsApplication(obj *unstructured.Unstructured) string {
+	if id := obj.GetAnnotations()[argoTrackingAnnotation]; id != "" {
+		// The tracking ID is <application>:<group>/<kind>:<namespace>/<name>
+		application, _, _ := strings.Cut(id, ":")
+		return application
+	}
+	return obj.GetLabels()[argoInstanceLabel]
+}
+
+// markManaged sets the dashboard as the owner of a CR it creates
+func markManaged(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if _, ok := annotations[ManagedByAnnotation]; !ok {
+		annotations[ManagedByAnnotation] = ManagedByDashboard
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// ensureMutable returns a GitOpsManagedError for a CR the dashboard must not change
+func ensureMutable(obj *unstructured.Unstructured) error {
+	if ManagedBy(obj) != ManagedByGitOps {
+		return nil
+	}
+	return &GitOpsManagedError{Name: obj.GetName(), Application: GitOpsApplication(obj)}
+}
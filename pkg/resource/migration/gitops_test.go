@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestManagedBy(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		labels          map[string]string
+		wantManagedBy   string
+		wantApplication string
+	}{
+		{name: "created before the annotation", wantManagedBy: ManagedByDashboard},
+		{name: "dashboard", annotations: map[string]string{ManagedByAnnotation: ManagedByDashboard}, wantManagedBy: ManagedByDashboard},
+		{name: "other owner", annotations: map[string]string{ManagedByAnnotation: "flux"}, wantManagedBy: ManagedByGitOps},
+		{
+			name:            "argocd tracking annotation",
+			annotations:     map[string]string{argoTrackingAnnotation: "backups:migration.dcnlab.com/StatefulMigration:stateful-migration/backup-db"},
+			wantManagedBy:   ManagedByGitOps,
+			wantApplication: "backups",
+		},
+		{
+			name:            "argocd tracking label wins over the dashboard annotation",
+			annotations:     map[string]string{ManagedByAnnotation: ManagedByDashboard},
+			labels:          map[string]string{argoInstanceLabel: "backups"},
+			wantManagedBy:   ManagedByGitOps,
+			wantApplication: "backups",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newStatefulMigration(Backup, Namespace, "db")
+			obj.SetAnnotations(tt.annotations)
+			if tt.labels != nil {
+				obj.SetLabels(tt.labels)
+			}
+			if got := ManagedBy(obj); got != tt.wantManagedBy {
+				t.Errorf("ManagedBy() = %q, want %q", got, tt.wantManagedBy)
+			}
+			if got := GitOpsApplication(obj); got != tt.wantApplication {
+				t.Errorf("GitOpsApplication() = %q, want %q", got, tt.wantApplication)
+			}
+		})
+	}
+}
+
+func TestServiceRejectsGitOpsChanges(t *testing.T) {
+	managed := newStatefulMigration(Backup, Namespace, "git")
+	managed.SetAnnotations(map[string]string{argoTrackingAnnotation: "backups:migration.dcnlab.com/StatefulMigration:stateful-migration/backup-git"})
+	s := newFakeService(Backup, managed)
+	ctx := context.TODO()
+
+	created, err := s.Create(ctx, newStatefulMigration(Backup, Namespace, "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := created.GetAnnotations()[ManagedByAnnotation]; owner != ManagedByDashboard {
+		t.Errorf("Create() %s annotation = %q, want %q", ManagedByAnnotation, owner, ManagedByDashboard)
+	}
+
+	obj, err := s.Get(ctx, "git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gitOpsErr *GitOpsManagedError
+	if _, err := s.Update(ctx, obj); !errors.As(err, &gitOpsErr) || gitOpsErr.Application != "backups" {
+		t.Errorf("Update() error = %v, want a GitOpsManagedError of application backups", err)
+	}
+	if _, err := s.Execute(ctx, "git", nil); !errors.Is(err, ErrGitOpsManaged) {
+		t.Errorf("Execute() error = %v, want ErrGitOpsManaged", err)
+	}
+	if err := s.Delete(ctx, "git"); !errors.Is(err, ErrGitOpsManaged) {
+		t.Errorf("Delete() error = %v, want ErrGitOpsManaged", err)
+	}
+	if _, err := s.Get(ctx, "git"); err != nil {
+		t.Errorf("Get() after a rejected Delete() returned %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// ImageRewriteRule replaces the prefix of the images restored on a target cluster, e.g. the registry of the
+// region of the source cluster by a mirror in the region of the target cluster
+type ImageRewriteRule struct {
+	SourcePrefix string `json:"sourcePrefix"`
+	TargetPrefix string `json:"targetPrefix"`
+}
+
+// ImageRewrites returns the image rewrite rules of the runtime config by target cluster
+func ImageRewrites() map[string][]ImageRewriteRule {
+	rewrites := map[string][]ImageRewriteRule{}
+	for cluster, rules := range config.GetDashboardConfig().Runtime.ImageRewrites {
+		rewrites[cluster] = fromConfigRules(rules)
+	}
+	return rewrites
+}
+
+// ClusterImageRewrites returns the image rewrite rules of the restores on a cluster
+func ClusterImageRewrites(cluster string) []ImageRewriteRule {
+	return fromConfigRules(config.ImageRewrites(cluster))
+}
+
+func fromConfigRules(rules []config.ImageRewriteRule) []ImageRewriteRule {
+	result := make([]ImageRewriteRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, ImageRewriteRule{SourcePrefix: rule.SourcePrefix, TargetPrefix: rule.TargetPrefix})
+	}
+	return result
+}
+
+// ImageRewritesConfig returns the rewrite rules by target cluster as they are stored in the runtime config,
+// leaving out the clusters without rules
+func ImageRewritesConfig(rewrites map[string][]ImageRewriteRule) map[string][]config.ImageRewriteRule {
+	result := map[string][]config.ImageRewriteRule{}
+	for cluster, rules := range rewrites {
+		for _, rule := range rules {
+			result[cluster] = append(result[cluster], config.ImageRewriteRule{SourcePrefix: rule.SourcePrefix, TargetPrefix: rule.TargetPrefix})
+		}
+	}
+	return result
+}
+
+// ValidateImageRewrites checks that the rules of every cluster are complete and rewrite a prefix once
+func ValidateImageRewrites(rewrites map[string][]ImageRewriteRule) error {
+	for cluster, rules := range rewrites {
+		if cluster == "" {
+			return fmt.Errorf("image rewrite rules need a target cluster")
+		}
+		sources := map[string]bool{}
+		for _, rule := range rules {
+			source := strings.TrimRight(rule.SourcePrefix, "/")
+			if source == "" || strings.TrimRight(rule.TargetPrefix, "/") == "" {
+				return fmt.Errorf("image rewrite rule of cluster %s needs a sourcePrefix and a targetPrefix", cluster)
+			}
+			if sources[source] {
+				return fmt.Errorf("image rewrite rules of cluster %s rewrite %s more than once", cluster, source)
+			}
+			sources[source] = true
+		}
+	}
+	return nil
+}
+
+// RewriteImage returns an image with the longest matching source prefix replaced by its target prefix.
+// A prefix matches whole path components, "registry.us/team" rewrites "registry.us/team/app:v1" but
+// not "registry.us/teams/app:v1". Images no rule matches are returned as is.
+func RewriteImage(image string, rules []ImageRewriteRule) string {
+	var match *ImageRewriteRule
+	for i := range rules {
+		source := strings.TrimRight(rules[i].SourcePrefix, "/")
+		if source == "" || !strings.HasPrefix(image, source) {
+			continue
+		}
+		if rest := image[len(source):]; rest != "" && !strings.ContainsAny(rest[:1], "/:@") {
+			continue
+		}
+		if match == nil || len(source) > len(strings.TrimRight(match.SourcePrefix, "/")) {
+			match = &rules[i]
+		}
+	}
+	if match == nil {
+		return image
+	}
+	return strings.TrimRight(match.TargetPrefix, "/") + image[len(strings.TrimRight(match.SourcePrefix, "/")):]
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewriteImage(t *testing.T) {
+	rules := []ImageRewriteRule{
+		{SourcePrefix: "harbor.us.example.com", TargetPrefix: "harbor.eu.example.com"},
+		{SourcePrefix: "harbor.us.example.com/checkpoints/", TargetPrefix: "mirror.eu.example.com/restore/"},
+	}
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "harbor.us.example.com/team/app:v1", want: "harbor.eu.example.com/team/app:v1"},
+		{image: "harbor.us.example.com/checkpoints/db:ckpt-1", want: "mirror.eu.example.com/restore/db:ckpt-1"},
+		{image: "harbor.us.example.com:5000/app", want: "harbor.eu.example.com:5000/app"},
+		{image: "harbor.us.example.com.evil/app", want: "harbor.us.example.com.evil/app"},
+		{image: "docker.io/library/redis:7", want: "docker.io/library/redis:7"},
+	}
+	for _, tt := range tests {
+		if got := RewriteImage(tt.image, rules); got != tt.want {
+			t.Errorf("RewriteImage(%s) = %s, want %s", tt.image, got, tt.want)
+		}
+	}
+	if got := RewriteImage("harbor.us.example.com/app", nil); got != "harbor.us.example.com/app" {
+		t.Errorf("RewriteImage() without rules = %s", got)
+	}
+}
+
+func TestValidateImageRewrites(t *testing.T) {
+	valid := map[string][]ImageRewriteRule{"eu": {{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"}}}
+	if err := ValidateImageRewrites(valid); err != nil {
+		t.Errorf("ValidateImageRewrites() error = %v", err)
+	}
+	invalid := []map[string][]ImageRewriteRule{
+		{"eu": {{SourcePrefix: "harbor.us"}}},
+		{"eu": {{SourcePrefix: "/", TargetPrefix: "harbor.eu"}}},
+		{"eu": {{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"}, {SourcePrefix: "harbor.us/", TargetPrefix: "mirror.eu"}}},
+		{"": {{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"}}},
+	}
+	for _, rewrites := range invalid {
+		if err := ValidateImageRewrites(rewrites); err == nil {
+			t.Errorf("ValidateImageRewrites(%v) did not fail", rewrites)
+		}
+	}
+}
+
+func TestImageRewritesConfig(t *testing.T) {
+	got := ImageRewritesConfig(map[string][]ImageRewriteRule{
+		"eu":   {{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"}},
+		"asia": {},
+	})
+	if len(got) != 1 || !reflect.DeepEqual(fromConfigRules(got["eu"]), []ImageRewriteRule{{SourcePrefix: "harbor.us", TargetPrefix: "harbor.eu"}}) {
+		t.Errorf("ImageRewritesConfig() = %v", got)
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Metadata of the Leases that lock workloads
+const (
+	LockLabel              = "ml-platform.io/workload-lock"
+	lockWorkloadAnnotation = "ml-platform.io/locked-workload"
+	lockUserAnnotation     = "ml-platform.io/locked-by"
+)
+
+// ErrLocked is returned when a workload is locked by another operation
+var ErrLocked = errors.New("workload is locked")
+
+// Workload identifies the workload an operation checkpoints or restores
+type Workload struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
+func (w Workload) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", w.Cluster, w.Namespace, strings.ToLower(w.Kind), w.Name)
+}
+
+// leaseName returns the name of the Lease that locks the workload. The workload is hashed since its parts
+// together may not fit in a name.
+func (w Workload) leaseName() string {
+	sum := sha256.Sum256([]byte(w.String()))
+	return "workload-lock-" + hex.EncodeToString(sum[:])[:20]
+}
+
+// LockHolder is the operation holding the lock of a workload
+type LockHolder struct {
+	// Operation is the kind of operation, like recovery or migration, and ID identifies it within its kind
+	Operation  string `json:"operation"`
+	ID         string `json:"id"`
+	User       string `json:"user,omitempty"`
+	AcquiredAt string `json:"acquiredAt"`
+}
+
+func (h LockHolder) identity() string {
+	return h.Operation + "/" + h.ID
+}
+
+// LockedError rejects an operation on a workload that another operation holds the lock of
+type LockedError struct {
+	Workload Workload
+	Holder   LockHolder
+}
+
+func (e *LockedError) Error() string {
+	msg := fmt.Sprintf("workload %s is locked by %s %s since %s", e.Workload, e.Holder.Operation, e.Holder.ID, e.Holder.AcquiredAt)
+	if e.Holder.User != "" {
+		msg += fmt.Sprintf(", started by %s", e.Holder.User)
+	}
+	return msg
+}
+
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Locks serializes the operations on a workload with Leases, so that the lock of a workload is shared by all
+// API replicas and survives restarts. A lock is released by its holder, or taken over once it expires or its
+// holder is found to be done.
+type Locks struct {
+	client    kubernetes.Interface
+	namespace string
+	// done reports whether the operation holding a lock has ended without releasing it
+	done func(ctx context.Context, holder LockHolder) bool
+	now  func() time.Time
+}
+
+// NewLocks returns the locks stored as Leases in the namespace. The done function reports whether the
+// operation holding a lock has ended without releasing it, so that its lock can be taken over.
+func NewLocks(client kubernetes.Interface, namespace string, done func(ctx context.Context, holder LockHolder) bool) *Locks {
+	return &Locks{client: client, namespace: namespace, done: done, now: time.Now}
+}
+
+// Acquire locks a workload for an operation for at most ttl. An operation acquiring a lock it already holds
+// renews it. When another operation holds the lock a LockedError is returned.
+func (l *Locks) Acquire(ctx context.Context, workload Workload, holder LockHolder, ttl time.Duration) error {
+	now := l.now()
+	holder.AcquiredAt = now.UTC().Format(time.RFC3339)
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	lease := newLockLease(workload, holder, now, ttl)
+	_, err := leases.Create(ctx, lease, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing, err := leases.Get(ctx, workload.leaseName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	current := lockHolder(existing)
+	if current.identity() != holder.identity() && !lockExpired(existing, now) && (l.done == nil || !l.done(ctx, current)) {
+		return &LockedError{Workload: workload, Holder: current}
+	}
+	if current.identity() == holder.identity() {
+		holder.AcquiredAt = current.AcquiredAt
+		lease = newLockLease(workload, holder, now, ttl)
+		lease.Spec.AcquireTime = existing.Spec.AcquireTime
+	}
+	lease.ResourceVersion = existing.ResourceVersion
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			// Another operation took the lock over first
+			if latest, getErr := leases.Get(ctx, workload.leaseName(), metav1.GetOptions{}); getErr == nil {
+				return &LockedError{Workload: workload, Holder: lockHolder(latest)}
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// Release unlocks a workload if the operation holds its lock
+func (l *Locks) Release(ctx context.Context, workload Workload, holder LockHolder) error {
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	existing, err := leases.Get(ctx, workload.leaseName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lockHolder(existing).identity() != holder.identity() {
+		return nil
+	}
+	err = leases.Delete(ctx, existing.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &existing.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// Holder returns the operation holding the lock of a workload, false when the workload is not locked
+func (l *Locks) Holder(ctx context.Context, workload Workload) (LockHolder, bool, error) {
+	lease, err := l.client.CoordinationV1().Leases(l.namespace).Get(ctx, workload.leaseName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return LockHolder{}, false, nil
+	}
+	if err != nil {
+		return LockHolder{}, false, err
+	}
+	if lockExpired(lease, l.now()) || (l.done != nil && l.done(ctx, lockHolder(lease))) {
+		return LockHolder{}, false, nil
+	}
+	return lockHolder(lease), true, nil
+}
+
+// Lock is a workload and the operation holding its lock
+type Lock struct {
+	Workload Workload
+	Holder   LockHolder
+}
+
+// List returns the locks that are held
+func (l *Locks) List(ctx context.Context) ([]Lock, error) {
+	leases, err := l.client.CoordinationV1().Leases(l.namespace).List(ctx, metav1.ListOptions{LabelSelector: LockLabel + "=true"})
+	if err != nil {
+		return nil, err
+	}
+	now := l.now()
+	locks := make([]Lock, 0, len(leases.Items))
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		workload, ok := parseWorkload(lease.Annotations[lockWorkloadAnnotation])
+		if !ok || lockExpired(lease, now) || (l.done != nil && l.done(ctx, lockHolder(lease))) {
+			continue
+		}
+		locks = append(locks, Lock{Workload: workload, Holder: lockHolder(lease)})
+	}
+	return locks, nil
+}
+
+// parseWorkload parses Workload.String. The cluster is split off last since it may be a label selector
+// containing slashes.
+func parseWorkload(value string) (Workload, bool) {
+	parts := make([]string, 3)
+	for i := 2; i >= 0; i-- {
+		index := strings.LastIndex(value, "/")
+		if index < 0 {
+			return Workload{}, false
+		}
+		parts[i], value = value[index+1:], value[:index]
+	}
+	return Workload{Cluster: value, Namespace: parts[0], Kind: parts[1], Name: parts[2]}, true
+}
+
+func newLockLease(workload Workload, holder LockHolder, now time.Time, ttl time.Duration) *coordinationv1.Lease {
+	identity := holder.identity()
+	seconds := int32(ttl / time.Second)
+	acquired := metav1.NewMicroTime(now)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   workload.leaseName(),
+			Labels: map[string]string{LockLabel: "true"},
+			Annotations: map[string]string{
+				lockWorkloadAnnotation: workload.String(),
+				lockUserAnnotation:     holder.User,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &identity,
+			LeaseDurationSeconds: &seconds,
+			AcquireTime:          &acquired,
+			RenewTime:            &acquired,
+		},
+	}
+}
+
+// lockHolder returns the holder recorded in the Lease of a lock
+func lockHolder(lease *coordinationv1.Lease) LockHolder {
+	var holder LockHolder
+	if lease.Spec.HolderIdentity != nil {
+		holder.Operation, holder.ID, _ = strings.Cut(*lease.Spec.HolderIdentity, "/")
+	}
+	holder.User = lease.Annotations[lockUserAnnotation]
+	if lease.Spec.AcquireTime != nil {
+		holder.AcquiredAt = lease.Spec.AcquireTime.UTC().Format(time.RFC3339)
+	}
+	return holder
+}
+
+// lockExpired reports whether the holder of a lock did not renew it in time
+func lockExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return now.After(lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second))
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLocks(t *testing.T) {
+	ctx := context.TODO()
+	done := map[string]bool{}
+	locks := NewLocks(kubefake.NewSimpleClientset(), Namespace, func(_ context.Context, holder LockHolder) bool {
+		return done[holder.ID]
+	})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	locks.now = func() time.Time { return now }
+
+	db := Workload{Cluster: "member1", Namespace: "default", Kind: "StatefulSet", Name: "db"}
+	first := LockHolder{Operation: "recovery", ID: "r1", User: "alice"}
+	second := LockHolder{Operation: "migration", ID: "m1", User: "bob"}
+
+	if err := locks.Acquire(ctx, db, first, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := locks.Acquire(ctx, db, first, time.Hour); err != nil {
+		t.Errorf("renewing the lock failed: %v", err)
+	}
+	if err := locks.Acquire(ctx, Workload{Cluster: "member2", Namespace: "default", Kind: "StatefulSet", Name: "db"}, second, time.Hour); err != nil {
+		t.Errorf("locking another workload failed: %v", err)
+	}
+
+	err := locks.Acquire(ctx, db, second, time.Hour)
+	var locked *LockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrLocked) {
+		t.Fatalf("Acquire() of a locked workload returned %v, expected a LockedError", err)
+	}
+	if locked.Holder.Operation != "recovery" || locked.Holder.ID != "r1" || locked.Holder.User != "alice" {
+		t.Errorf("LockedError holder = %+v", locked.Holder)
+	}
+	if holder, found, err := locks.Holder(ctx, db); err != nil || !found || holder.ID != "r1" {
+		t.Errorf("Holder() = %+v, %v, %v", holder, found, err)
+	}
+
+	// Only the holder releases the lock
+	if err := locks.Release(ctx, db, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := locks.Holder(ctx, db); !found {
+		t.Error("the lock was released by another operation")
+	}
+	if err := locks.Release(ctx, db, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := locks.Acquire(ctx, db, second, time.Hour); err != nil {
+		t.Errorf("Acquire() after release failed: %v", err)
+	}
+
+	// A lock whose holder is done is taken over
+	done["m1"] = true
+	if err := locks.Acquire(ctx, db, first, time.Hour); err != nil {
+		t.Errorf("taking over the lock of a finished operation failed: %v", err)
+	}
+
+	// An expired lock is taken over
+	now = now.Add(2 * time.Hour)
+	if _, found, _ := locks.Holder(ctx, db); found {
+		t.Error("an expired lock is still held")
+	}
+	if err := locks.Acquire(ctx, db, LockHolder{Operation: "migration", ID: "m2"}, time.Hour); err != nil {
+		t.Errorf("taking over an expired lock failed: %v", err)
+	}
+}
+
+func TestLocksList(t *testing.T) {
+	ctx := context.TODO()
+	locks := NewLocks(kubefake.NewSimpleClientset(), Namespace, nil)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	locks.now = func() time.Time { return now }
+
+	db := Workload{Cluster: "member1", Namespace: "default", Kind: "StatefulSet", Name: "db"}
+	selector := Workload{Cluster: "topology.kubernetes.io/zone=a", Kind: "controller"}
+	if err := locks.Acquire(ctx, db, LockHolder{Operation: "recovery", ID: "r1"}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := locks.Acquire(ctx, selector, LockHolder{Operation: "install", ID: "i1", User: "alice"}, 3*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := locks.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("List() returned %d locks, expected 2", len(list))
+	}
+	for _, lock := range list {
+		switch lock.Holder.ID {
+		case "r1":
+			if lock.Workload != (Workload{Cluster: "member1", Namespace: "default", Kind: "statefulset", Name: "db"}) {
+				t.Errorf("workload of r1 = %+v", lock.Workload)
+			}
+		case "i1":
+			if lock.Workload != selector || lock.Holder.User != "alice" {
+				t.Errorf("lock of i1 = %+v", lock)
+			}
+		default:
+			t.Errorf("unexpected lock %+v", lock)
+		}
+	}
+
+	// Expired locks are not listed
+	now = now.Add(2 * time.Hour)
+	if list, err := locks.List(ctx); err != nil || len(list) != 1 || list[0].Holder.ID != "i1" {
+		t.Errorf("List() after expiry = %+v, %v", list, err)
+	}
+}
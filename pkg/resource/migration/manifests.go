@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// lastAppliedAnnotation is left out of exported objects, it duplicates the object
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// WorkloadManifests is the state of a backed up workload exported with its checkpoints: the workload and the
+// objects it depends on. A recovery recreates them from the manifests instead of reading the source cluster.
+type WorkloadManifests struct {
+	Cluster     string              `json:"cluster"`
+	CapturedAt  string              `json:"capturedAt"`
+	StatefulSet *appsv1.StatefulSet `json:"statefulSet,omitempty"`
+	Pod         *corev1.Pod         `json:"pod,omitempty"`
+	// Deployment owns the backed up pod through a ReplicaSet
+	Deployment *appsv1.Deployment             `json:"deployment,omitempty"`
+	Services   []corev1.Service               `json:"services,omitempty"`
+	ConfigMaps []corev1.ConfigMap             `json:"configMaps,omitempty"`
+	Secrets    []corev1.Secret                `json:"secrets,omitempty"`
+	Claims     []corev1.PersistentVolumeClaim `json:"persistentVolumeClaims,omitempty"`
+}
+
+// exportMeta keeps the name, namespace, labels and annotations of an object, leaving out what the source
+// cluster set, such as its UID, resource version and owners
+func exportMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	exported := metav1.ObjectMeta{Name: meta.Name, Namespace: meta.Namespace, Labels: meta.Labels}
+	for key, value := range meta.Annotations {
+		if key == lastAppliedAnnotation {
+			continue
+		}
+		if exported.Annotations == nil {
+			exported.Annotations = map[string]string{}
+		}
+		exported.Annotations[key] = value
+	}
+	return exported
+}
+
+// Add exports an object of the workload into the manifests, without its status and server-set metadata
+func (m *WorkloadManifests) Add(obj metav1.Object) error {
+	switch obj := obj.(type) {
+	case *appsv1.StatefulSet:
+		m.StatefulSet = &appsv1.StatefulSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Spec:       *obj.Spec.DeepCopy(),
+		}
+	case *corev1.Pod:
+		m.Pod = &corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Spec:       *obj.Spec.DeepCopy(),
+		}
+	case *appsv1.Deployment:
+		m.Deployment = &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Spec:       *obj.Spec.DeepCopy(),
+		}
+	case *corev1.Service:
+		m.Services = append(m.Services, corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: KindService},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Spec:       *obj.Spec.DeepCopy(),
+		})
+	case *corev1.ConfigMap:
+		m.ConfigMaps = append(m.ConfigMaps, corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: KindConfigMap},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Data:       obj.Data,
+			BinaryData: obj.BinaryData,
+		})
+	case *corev1.Secret:
+		m.Secrets = append(m.Secrets, corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: KindSecret},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Data:       obj.Data,
+			Type:       obj.Type,
+		})
+	case *corev1.PersistentVolumeClaim:
+		m.Claims = append(m.Claims, corev1.PersistentVolumeClaim{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: KindPersistentVolumeClaim},
+			ObjectMeta: exportMeta(obj.ObjectMeta),
+			Spec:       *obj.Spec.DeepCopy(),
+		})
+	default:
+		return fmt.Errorf("unsupported workload object %T", obj)
+	}
+	return nil
+}
+
+// PodSpec returns the pod spec of the backed up pod or the pod template of the backed up statefulset, with the
+// labels of the pods and the subdomain or governing service, nil when the manifests have no workload
+func (m *WorkloadManifests) PodSpec() (*corev1.PodSpec, map[string]string, string) {
+	switch {
+	case m.StatefulSet != nil:
+		return &m.StatefulSet.Spec.Template.Spec, m.StatefulSet.Spec.Template.Labels, m.StatefulSet.Spec.ServiceName
+	case m.Pod != nil:
+		return &m.Pod.Spec, m.Pod.Labels, m.Pod.Spec.Subdomain
+	}
+	return nil, nil, ""
+}
+
+// Names returns the sorted names of the exported objects of a kind
+func (m *WorkloadManifests) Names(kind string) []string {
+	var names []string
+	for _, obj := range m.objects(kind) {
+		names = append(names, obj.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Object returns the exported ConfigMap, Secret, Service or claim of a name, nil when it was not exported
+func (m *WorkloadManifests) Object(kind, name string) metav1.Object {
+	for _, obj := range m.objects(kind) {
+		if obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+func (m *WorkloadManifests) objects(kind string) []metav1.Object {
+	var objects []metav1.Object
+	switch kind {
+	case KindService:
+		for i := range m.Services {
+			objects = append(objects, &m.Services[i])
+		}
+	case KindConfigMap:
+		for i := range m.ConfigMaps {
+			objects = append(objects, &m.ConfigMaps[i])
+		}
+	case KindSecret:
+		for i := range m.Secrets {
+			objects = append(objects, &m.Secrets[i])
+		}
+	case KindPersistentVolumeClaim:
+		for i := range m.Claims {
+			objects = append(objects, &m.Claims[i])
+		}
+	}
+	return objects
+}
+
+// Count returns the number of exported objects, the workload included
+func (m *WorkloadManifests) Count() int {
+	count := len(m.Services) + len(m.ConfigMaps) + len(m.Secrets) + len(m.Claims)
+	for _, exported := range []bool{m.StatefulSet != nil, m.Pod != nil, m.Deployment != nil} {
+		if exported {
+			count++
+		}
+	}
+	return count
+}
+
+// YAML returns the manifests as a multi-document YAML stream that can be applied with kubectl. The values of
+// the Secrets are left out when redactSecrets is set, keeping their keys.
+func (m *WorkloadManifests) YAML(redactSecrets bool) ([]byte, error) {
+	var objects []interface{}
+	if m.Deployment != nil {
+		objects = append(objects, m.Deployment)
+	}
+	if m.StatefulSet != nil {
+		objects = append(objects, m.StatefulSet)
+	}
+	if m.Pod != nil {
+		objects = append(objects, m.Pod)
+	}
+	for i := range m.Services {
+		objects = append(objects, &m.Services[i])
+	}
+	for i := range m.ConfigMaps {
+		objects = append(objects, &m.ConfigMaps[i])
+	}
+	for i := range m.Secrets {
+		secret := m.Secrets[i].DeepCopy()
+		if redactSecrets {
+			for key := range secret.Data {
+				secret.Data[key] = []byte{}
+			}
+		}
+		objects = append(objects, secret)
+	}
+	for i := range m.Claims {
+		objects = append(objects, &m.Claims[i])
+	}
+
+	var buf bytes.Buffer
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestManifests(t *testing.T) *WorkloadManifests {
+	t.Helper()
+	m := &WorkloadManifests{Cluster: "member1"}
+	objects := []metav1.Object{
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "db",
+				Namespace:       "default",
+				UID:             "1234",
+				ResourceVersion: "42",
+				Annotations:     map[string]string{lastAppliedAnnotation: "{}", "team": "data"},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				ServiceName: "db-headless",
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "db"}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "db", Image: "postgres:16"}}},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{Replicas: 1},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "db-headless", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "db-config", Namespace: "default"}, Data: map[string]string{"mode": "replica"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db-password", Namespace: "default"}, Data: map[string][]byte{"password": []byte("s3cret")}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "default"}},
+	}
+	for _, obj := range objects {
+		if err := m.Add(obj); err != nil {
+			t.Fatalf("Add(%s) error = %v", obj.GetName(), err)
+		}
+	}
+	return m
+}
+
+func TestWorkloadManifestsAdd(t *testing.T) {
+	m := newTestManifests(t)
+	sts := m.StatefulSet
+	if sts.UID != "" || sts.ResourceVersion != "" || sts.Status.Replicas != 0 {
+		t.Errorf("server fields and status were exported: %+v", sts.ObjectMeta)
+	}
+	if !reflect.DeepEqual(sts.Annotations, map[string]string{"team": "data"}) {
+		t.Errorf("annotations = %v, want the last applied configuration left out", sts.Annotations)
+	}
+	if sts.Kind != "StatefulSet" || sts.APIVersion != "apps/v1" {
+		t.Errorf("type = %s/%s", sts.APIVersion, sts.Kind)
+	}
+	if m.Count() != 5 {
+		t.Errorf("Count() = %d, want 5", m.Count())
+	}
+	if err := m.Add(&corev1.Node{}); err == nil {
+		t.Error("Add() of a node did not fail")
+	}
+}
+
+func TestWorkloadManifestsLookup(t *testing.T) {
+	m := newTestManifests(t)
+	spec, labels, governing := m.PodSpec()
+	if spec == nil || spec.Containers[0].Image != "postgres:16" || labels["app"] != "db" || governing != "db-headless" {
+		t.Errorf("PodSpec() = %v, %v, %s", spec, labels, governing)
+	}
+	if got := m.Names(KindPersistentVolumeClaim); !reflect.DeepEqual(got, []string{"data-db-0"}) {
+		t.Errorf("Names(claims) = %v", got)
+	}
+	configMap, ok := m.Object(KindConfigMap, "db-config").(*corev1.ConfigMap)
+	if !ok || configMap.Data["mode"] != "replica" {
+		t.Errorf("Object(db-config) = %v", m.Object(KindConfigMap, "db-config"))
+	}
+	if m.Object(KindSecret, "missing") != nil {
+		t.Error("Object() of a missing secret is not nil")
+	}
+	if spec, _, _ := (&WorkloadManifests{}).PodSpec(); spec != nil {
+		t.Error("PodSpec() without workload is not nil")
+	}
+}
+
+func TestWorkloadManifestsYAML(t *testing.T) {
+	m := newTestManifests(t)
+	data, err := m.YAML(true)
+	if err != nil {
+		t.Fatalf("YAML() error = %v", err)
+	}
+	out := string(data)
+	if strings.Count(out, "---\n") != 5 || !strings.Contains(out, "kind: StatefulSet") {
+		t.Errorf("YAML() = %s", out)
+	}
+	if strings.Contains(out, "czNjcmV0") {
+		t.Error("YAML() with redaction contains the secret value")
+	}
+	if string(m.Secrets[0].Data["password"]) != "s3cret" {
+		t.Error("YAML() redacted the stored secret")
+	}
+	if data, _ := m.YAML(false); !strings.Contains(string(data), "czNjcmV0") {
+		t.Error("YAML() without redaction left out the secret value")
+	}
+}
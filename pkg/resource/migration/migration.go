@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration manages the StatefulMigration custom resources backing the backup configurations and
+// recovery operations, and reads the state of the migration controllers that act on them.
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// Group is the API group of the migration custom resources
+const Group = "migration.dcnlab.com"
+
+// ErrConflict is returned when a CR keeps changing concurrently while the dashboard updates it
+var ErrConflict = errors.New("conflict")
+
+// ConflictError rejects the change of a CR that kept conflicting with concurrent changes, the change can be
+// made again on the latest version
+type ConflictError struct {
+	Name string
+	// ResourceVersion is the latest version of the CR, if it could be read
+	ResourceVersion string
+	Err             error
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s was changed concurrently, retry on resource version %s: %v", e.Name, e.ResourceVersion, e.Err)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Kind is a kind of operation stored as StatefulMigration CRs
+type Kind struct {
+	// Resource is the StatefulMigration resource version the operation is stored with
+	Resource schema.GroupVersionResource
+	// Prefix is prepended to the ID of an operation to name its CR
+	Prefix string
+	// App is the value of the app label of the CRs
+	App string
+}
+
+// Kinds of operations
+var (
+	Backup = Kind{
+		Resource: schema.GroupVersionResource{Group: Group, Version: "v1", Resource: "statefulmigrations"},
+		Prefix:   "backup",
+		App:      "backup-migration",
+	}
+	Recovery = Kind{
+		Resource: schema.GroupVersionResource{Group: Group, Version: "v1alpha1", Resource: "statefulmigrations"},
+		Prefix:   "recovery",
+		App:      "recovery-migration",
+	}
+)
+
+// Name returns the name of the CR of an operation
+func (k Kind) Name(id string) string {
+	return fmt.Sprintf("%s-%s", k.Prefix, id)
+}
+
+// ID returns the ID of an operation from the name of its CR
+func (k Kind) ID(name string) string {
+	return strings.TrimPrefix(name, k.Prefix+"-")
+}
+
+// LabelSelector selects the CRs of the kind, including the ones in the trash
+func (k Kind) LabelSelector() string {
+	return "app=" + k.App
+}
+
+// ActiveLabelSelector selects the CRs of the kind that are not in the trash
+func (k Kind) ActiveLabelSelector() string {
+	return k.LabelSelector() + ",!" + DeletedLabel
+}
+
+// Service manages the StatefulMigration CRs of a kind of operation
+type Service struct {
+	client    dynamic.Interface
+	kind      Kind
+	namespace string
+}
+
+// NewService returns a service for the CRs of the kind in the namespace
+func NewService(client dynamic.Interface, kind Kind, namespace string) *Service {
+	return &Service{client: client, kind: kind, namespace: namespace}
+}
+
+// List returns the CRs of the kind in all namespaces, leaving out the ones in the trash
+func (s *Service) List(ctx context.Context) ([]unstructured.Unstructured, error) {
+	list, err := s.client.Resource(s.kind.Resource).List(ctx, metav1.ListOptions{
+		LabelSelector: s.kind.ActiveLabelSelector(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// Get returns the CR of an operation. CRs in the trash are not found.
+func (s *Service) Get(ctx context.Context, id string) (*unstructured.Unstructured, error) {
+	obj, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if IsDeleted(obj) {
+		return nil, apierrors.NewNotFound(s.kind.Resource.GroupResource(), obj.GetName())
+	}
+	return obj, nil
+}
+
+// get returns the CR of an operation, whether it is in the trash or not
+func (s *Service) get(ctx context.Context, id string) (*unstructured.Unstructured, error) {
+	return s.client.Resource(s.kind.Resource).Namespace(s.namespace).Get(ctx, s.kind.Name(id), metav1.GetOptions{})
+}
+
+// Create creates the CR of an operation, owned by the dashboard unless the CR names another owner
+func (s *Service) Create(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	markManaged(obj)
+	return s.client.Resource(s.kind.Resource).Namespace(s.namespace).Create(ctx, obj, metav1.CreateOptions{})
+}
+
+// Update replaces the CR of an operation. CRs managed by GitOps are rejected with a GitOpsManagedError.
+func (s *Service) Update(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if err := ensureMutable(obj); err != nil {
+		return nil, err
+	}
+	return s.update(ctx, obj)
+}
+
+// update writes a CR without any check
+func (s *Service) update(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return s.client.Resource(s.kind.Resource).Namespace(s.namespace).Update(ctx, obj, metav1.UpdateOptions{})
+}
+
+// Delete deletes the CR of an operation for good, also when it is in the trash. CRs managed by GitOps are
+// rejected with a GitOpsManagedError.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	obj, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := ensureMutable(obj); err != nil {
+		return err
+	}
+	return s.client.Resource(s.kind.Resource).Namespace(s.namespace).Delete(ctx, s.kind.Name(id), metav1.DeleteOptions{})
+}
+
+// Execute asks the controller to run an operation now by setting the execution trigger of its CR.
+// The prepare function may change the CR further before it is updated, or reject the execution with an error.
+func (s *Service) Execute(ctx context.Context, id string, prepare func(obj *unstructured.Unstructured) error) (*unstructured.Unstructured, error) {
+	return s.Mutate(ctx, id, func(obj *unstructured.Unstructured) error {
+		spec, found, err := unstructured.NestedMap(obj.Object, "spec")
+		if err != nil || !found {
+			return fmt.Errorf("failed to get spec from StatefulMigration CR %s", obj.GetName())
+		}
+		spec["executeNow"] = time.Now().Unix()
+		if err := unstructured.SetNestedMap(obj.Object, spec, "spec"); err != nil {
+			return err
+		}
+		if prepare != nil {
+			return prepare(obj)
+		}
+		return nil
+	})
+}
+
+// Mutate changes the CR of an operation with mutate and updates it. When the update conflicts with a concurrent
+// change the CR is read again and mutate applied to the latest version, so mutate must only depend on the CR it
+// is given. A conflict left after the retries is returned as a ConflictError. CRs in the trash are not found.
+func (s *Service) Mutate(ctx context.Context, id string, mutate func(obj *unstructured.Unstructured) error) (*unstructured.Unstructured, error) {
+	return s.mutate(ctx, id, s.Get, func(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		if err := mutate(obj); err != nil {
+			return nil, err
+		}
+		return s.Update(ctx, obj)
+	})
+}
+
+// mutate reads a CR with get and writes it with update, retrying both on conflicts
+func (s *Service) mutate(ctx context.Context, id string, get func(context.Context, string) (*unstructured.Unstructured, error),
+	update func(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)) (*unstructured.Unstructured, error) {
+	var updated *unstructured.Unstructured
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := get(ctx, id)
+		if err != nil {
+			return err
+		}
+		updated, err = update(obj)
+		return err
+	})
+	if apierrors.IsConflict(err) {
+		conflict := &ConflictError{Name: s.kind.Name(id), Err: err}
+		if latest, getErr := s.get(ctx, id); getErr == nil {
+			conflict.ResourceVersion = latest.GetResourceVersion()
+		}
+		return nil, conflict
+	}
+	return updated, err
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newStatefulMigration(kind Kind, namespace, id string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"schedule": "0 0 * * *"},
+	}}
+	obj.SetAPIVersion(kind.Resource.GroupVersion().String())
+	obj.SetKind("StatefulMigration")
+	obj.SetNamespace(namespace)
+	obj.SetName(kind.Name(id))
+	obj.SetLabels(map[string]string{"app": kind.App})
+	return obj
+}
+
+func newFakeService(kind Kind, objects ...runtime.Object) *Service {
+	listKinds := map[schema.GroupVersionResource]string{
+		Backup.Resource:   "StatefulMigrationList",
+		Recovery.Resource: "StatefulMigrationList",
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+	return NewService(client, kind, Namespace)
+}
+
+func TestServiceCRUD(t *testing.T) {
+	other := newStatefulMigration(Backup, "other", "nightly")
+	unlabelled := newStatefulMigration(Backup, Namespace, "manual")
+	unlabelled.SetLabels(nil)
+	s := newFakeService(Backup, other, unlabelled)
+	ctx := context.TODO()
+
+	if _, err := s.Create(ctx, newStatefulMigration(Backup, Namespace, "db")); err != nil {
+		t.Fatal(err)
+	}
+	items, err := s.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("List() returned %d CRs, want the 2 labelled ones", len(items))
+	}
+
+	obj, err := s.Get(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetName() != "backup-db" {
+		t.Errorf("Get() returned %s, want backup-db", obj.GetName())
+	}
+	obj.SetAnnotations(map[string]string{"updated": "true"})
+	if _, err := s.Update(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj, _ = s.Get(ctx, "db"); obj.GetAnnotations()["updated"] != "true" {
+		t.Errorf("Update() was not stored, annotations %v", obj.GetAnnotations())
+	}
+
+	if err := s.Delete(ctx, "db"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "db"); err == nil {
+		t.Error("Get() after Delete() returned no error")
+	}
+}
+
+func TestServiceExecute(t *testing.T) {
+	s := newFakeService(Recovery, newStatefulMigration(Recovery, Namespace, "r1"))
+	ctx := context.TODO()
+
+	updated, err := s.Execute(ctx, "r1", func(obj *unstructured.Unstructured) error {
+		return unstructured.SetNestedField(obj.Object, "running", "spec", "phase")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := unstructured.NestedInt64(updated.Object, "spec", "executeNow"); !found {
+		t.Error("Execute() did not set spec.executeNow")
+	}
+	if phase, _, _ := unstructured.NestedString(updated.Object, "spec", "phase"); phase != "running" {
+		t.Errorf("Execute() spec.phase = %q, want the one set by prepare", phase)
+	}
+
+	rejected := errors.New("blackout")
+	if _, err := s.Execute(ctx, "r1", func(*unstructured.Unstructured) error { return rejected }); !errors.Is(err, rejected) {
+		t.Errorf("Execute() error = %v, want the one of prepare", err)
+	}
+	if _, err := s.Execute(ctx, "missing", nil); err == nil {
+		t.Error("Execute() of a missing operation returned no error")
+	}
+}
+
+func TestServiceMutateRetriesConflicts(t *testing.T) {
+	obj := newStatefulMigration(Backup, Namespace, "db")
+	obj.SetResourceVersion("7")
+	s := newFakeService(Backup, obj)
+	fakeClient := s.client.(*dynamicfake.FakeDynamicClient)
+	conflicts := 2
+	fakeClient.PrependReactor("update", "statefulmigrations", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			return false, nil, nil
+		}
+		conflicts--
+		return true, nil, apierrors.NewConflict(Backup.Resource.GroupResource(), "backup-db", errors.New("modified"))
+	})
+	ctx := context.TODO()
+
+	calls := 0
+	updated, err := s.Mutate(ctx, "db", func(obj *unstructured.Unstructured) error {
+		calls++
+		return unstructured.SetNestedField(obj.Object, "0 1 * * *", "spec", "schedule")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("mutate was called %d times, want once per attempt", calls)
+	}
+	if schedule, _, _ := unstructured.NestedString(updated.Object, "spec", "schedule"); schedule != "0 1 * * *" {
+		t.Errorf("Mutate() schedule = %q, want the mutated one", schedule)
+	}
+
+	conflicts = 100
+	_, err = s.Mutate(ctx, "db", func(*unstructured.Unstructured) error { return nil })
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("Mutate() error = %v, want a ConflictError", err)
+	}
+	if conflict.ResourceVersion == "" {
+		t.Error("ConflictError has no resource version")
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		scheduleType, value, want string
+	}{
+		{"selection", "5m", "*/5 * * * *"},
+		{"selection", "1h", "0 * * * *"},
+		{"selection", "weekly", "0 0 * * *"},
+		{"cron", "0 3 * * 1", "0 3 * * 1"},
+	}
+	for _, tt := range tests {
+		if got := CronExpression(tt.scheduleType, tt.value); got != tt.want {
+			t.Errorf("CronExpression(%q, %q) = %q, want %q", tt.scheduleType, tt.value, got, tt.want)
+		}
+	}
+	if err := ValidateCronExpression("0 3 * * 1"); err != nil {
+		t.Errorf("ValidateCronExpression() of a valid expression returned %v", err)
+	}
+	if err := ValidateCronExpression("0 3 * *"); err == nil {
+		t.Error("ValidateCronExpression() of 4 fields returned no error")
+	}
+}
+
+func TestResourceAPIVersion(t *testing.T) {
+	for kind, want := range map[string]string{"pod": "v1", "StatefulSet": "apps/v1", "deployment": "v1"} {
+		if got := ResourceAPIVersion(kind); got != want {
+			t.Errorf("ResourceAPIVersion(%q) = %q, want %q", kind, got, want)
+		}
+	}
+}
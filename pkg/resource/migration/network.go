@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Names of the checks of a network connectivity test between the clusters of a migration
+const (
+	NetworkCheckDNS       = "dns"
+	NetworkCheckRegistry  = "registry"
+	NetworkCheckImagePull = "image-pull"
+	NetworkCheckSourceAPI = "source-api"
+	NetworkCheckBandwidth = "bandwidth"
+)
+
+// BandwidthPayloadBytes is the size of the payload the bandwidth check downloads from the source cluster
+const BandwidthPayloadBytes = 8 << 20
+
+// probeTimeoutSeconds bounds each request of the probe script
+const probeTimeoutSeconds = 30
+
+// curlErrors describe the curl exit codes a probe commonly fails with
+var curlErrors = map[int]string{
+	6:  "could not resolve host",
+	7:  "failed to connect",
+	28: "timed out",
+	35: "TLS handshake failed",
+	52: "empty reply from server",
+	56: "connection reset",
+}
+
+// imagePullFailures are the waiting reasons of a container whose image cannot be pulled
+var imagePullFailures = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// ProbeTarget is a URL the probe pod of a connectivity test requests
+type ProbeTarget struct {
+	Check string
+	URL   string
+	// Codes are the HTTP status codes that pass the check, any response passes when empty
+	Codes []int
+	// Optional checks do not fail the test, like reaching the source cluster, which not every migration needs
+	Optional bool
+	// ResolveCheck also reports the name resolution of the URL host as a DNS check
+	ResolveCheck bool
+}
+
+// NetworkCheck is the outcome of one check of a network connectivity test
+type NetworkCheck struct {
+	Name     string `json:"name"`
+	Target   string `json:"target,omitempty"`
+	Success  bool   `json:"success"`
+	Optional bool   `json:"optional,omitempty"`
+	Message  string `json:"message,omitempty"`
+	// LatencyMs is the time to connect for network checks and the time to start for the image pull
+	LatencyMs int64 `json:"latencyMs"`
+	// ThroughputMbps is the download rate measured by the bandwidth check
+	ThroughputMbps float64 `json:"throughputMbps,omitempty"`
+}
+
+// NetworkReport is the result of a network connectivity test between the source and target cluster of a migration
+type NetworkReport struct {
+	SourceCluster string         `json:"sourceCluster"`
+	TargetCluster string         `json:"targetCluster"`
+	Registry      string         `json:"registry,omitempty"`
+	Success       bool           `json:"success"`
+	Checks        []NetworkCheck `json:"checks"`
+}
+
+// Record adds a check to the report. A failed check that is not optional fails the test.
+func (r *NetworkReport) Record(check NetworkCheck) {
+	r.Checks = append(r.Checks, check)
+	if !check.Success && !check.Optional {
+		r.Success = false
+	}
+}
+
+// RegistryProbeURL returns the URL of the registry API of a registry address, which answers without credentials
+func RegistryProbeURL(registry string) (string, error) {
+	address := strings.TrimSpace(registry)
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid registry address %q", registry)
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/v2/", nil
+}
+
+// ProbeScript returns the shell script of the probe pod. It requests each target with curl and prints one
+// result line per target, which ParseProbeOutput reads.
+func ProbeScript(targets []ProbeTarget) string {
+	var script strings.Builder
+	for i, target := range targets {
+		fmt.Fprintf(&script, "out=$(curl -sk -o /dev/null --max-time %d -w '%%{http_code} %%{time_namelookup} %%{time_connect} %%{time_total} %%{speed_download}' '%s'); echo \"probe %d $? $out\"\n",
+			probeTimeoutSeconds, strings.ReplaceAll(target.URL, "'", ""), i)
+	}
+	return script.String()
+}
+
+// probeResult is a result line of the probe script
+type probeResult struct {
+	exitCode int
+	httpCode int
+	// lookup, connect and total are seconds since the start of the request
+	lookup, connect, total float64
+	bytesPerSecond         float64
+}
+
+func parseProbeLine(fields []string) (int, probeResult, bool) {
+	if len(fields) < 3 || fields[0] != "probe" {
+		return 0, probeResult{}, false
+	}
+	index, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, probeResult{}, false
+	}
+	result := probeResult{}
+	if result.exitCode, err = strconv.Atoi(fields[2]); err != nil {
+		return 0, probeResult{}, false
+	}
+	values := []*float64{&result.lookup, &result.connect, &result.total, &result.bytesPerSecond}
+	if len(fields) > 3 {
+		result.httpCode, _ = strconv.Atoi(fields[3])
+	}
+	for i, value := range values {
+		if len(fields) > 4+i {
+			*value, _ = strconv.ParseFloat(fields[4+i], 64)
+		}
+	}
+	return index, result, true
+}
+
+// ParseProbeOutput turns the output of the probe script into the checks of the targets, in the order of
+// the targets. A target without a result line failed to run.
+func ParseProbeOutput(output string, targets []ProbeTarget) []NetworkCheck {
+	results := map[int]probeResult{}
+	for _, line := range strings.Split(output, "\n") {
+		if index, result, ok := parseProbeLine(strings.Fields(line)); ok {
+			results[index] = result
+		}
+	}
+
+	checks := make([]NetworkCheck, 0, len(targets))
+	for i, target := range targets {
+		result, ok := results[i]
+		if target.ResolveCheck {
+			checks = append(checks, resolveCheck(target, result, ok))
+		}
+		checks = append(checks, targetCheck(target, result, ok))
+	}
+	return checks
+}
+
+func probeHost(target ProbeTarget) string {
+	if parsed, err := url.Parse(target.URL); err == nil && parsed.Host != "" {
+		return parsed.Hostname()
+	}
+	return target.URL
+}
+
+func resolveCheck(target ProbeTarget, result probeResult, ok bool) NetworkCheck {
+	check := NetworkCheck{Name: NetworkCheckDNS, Target: probeHost(target), Optional: target.Optional}
+	switch {
+	case !ok:
+		check.Message = "probe did not report a result"
+	case result.exitCode == 6:
+		check.Message = curlErrors[6]
+	default:
+		check.Success = true
+		check.LatencyMs = int64(result.lookup * 1000)
+	}
+	return check
+}
+
+func targetCheck(target ProbeTarget, result probeResult, ok bool) NetworkCheck {
+	check := NetworkCheck{Name: target.Check, Target: target.URL, Optional: target.Optional}
+	if !ok {
+		check.Message = "probe did not report a result"
+		return check
+	}
+	if result.exitCode != 0 {
+		check.Message = curlErrors[result.exitCode]
+		if check.Message == "" {
+			check.Message = fmt.Sprintf("request failed with curl exit code %d", result.exitCode)
+		}
+		return check
+	}
+
+	check.LatencyMs = int64((result.connect - result.lookup) * 1000)
+	check.Message = fmt.Sprintf("HTTP %d", result.httpCode)
+	check.Success = len(target.Codes) == 0
+	for _, code := range target.Codes {
+		if result.httpCode == code {
+			check.Success = true
+		}
+	}
+	if target.Check == NetworkCheckBandwidth && check.Success {
+		check.ThroughputMbps = result.bytesPerSecond * 8 / 1e6
+		check.Message = fmt.Sprintf("%.1f Mbit/s over %d bytes", check.ThroughputMbps, BandwidthPayloadBytes)
+	}
+	return check
+}
+
+// ImagePullCheck reports whether the image of the first container of a pull test pod was pulled. done is
+// false while the kubelet is still pulling.
+func ImagePullCheck(pod *corev1.Pod) (check NetworkCheck, done bool) {
+	check = NetworkCheck{Name: NetworkCheckImagePull}
+	if len(pod.Spec.Containers) > 0 {
+		check.Target = pod.Spec.Containers[0].Image
+	}
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return check, false
+	}
+	state := pod.Status.ContainerStatuses[0].State
+	switch {
+	case state.Running != nil, state.Terminated != nil:
+		check.Success = true
+		check.Message = "image pulled"
+		return check, true
+	case state.Waiting != nil && imagePullFailures[state.Waiting.Reason]:
+		check.Message = state.Waiting.Reason
+		if state.Waiting.Message != "" {
+			check.Message += ": " + state.Waiting.Message
+		}
+		return check, true
+	}
+	return check, false
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRegistryProbeURL(t *testing.T) {
+	for registry, want := range map[string]string{
+		"harbor.example.com/library":     "https://harbor.example.com/v2/",
+		"http://registry.local:5000":     "http://registry.local:5000/v2/",
+		" https://ghcr.io/org/project/ ": "https://ghcr.io/v2/",
+	} {
+		if got, err := RegistryProbeURL(registry); err != nil || got != want {
+			t.Errorf("RegistryProbeURL(%q) = %q, %v, want %q", registry, got, err, want)
+		}
+	}
+	if _, err := RegistryProbeURL("https://"); err == nil {
+		t.Error("RegistryProbeURL() error = nil for an address without host")
+	}
+}
+
+func TestProbeScript(t *testing.T) {
+	script := ProbeScript([]ProbeTarget{{URL: "https://harbor.example.com/v2/"}, {URL: "http://10.0.0.5:8080/payload'"}})
+	lines := strings.Split(strings.TrimSpace(script), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ProbeScript() = %d lines, want one per target", len(lines))
+	}
+	if !strings.Contains(lines[0], "'https://harbor.example.com/v2/'") || !strings.Contains(lines[0], `echo "probe 0 $? $out"`) {
+		t.Errorf("ProbeScript() line = %q", lines[0])
+	}
+	if strings.Contains(lines[1], "payload''") {
+		t.Errorf("ProbeScript() did not strip quotes from the URL: %q", lines[1])
+	}
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	targets := []ProbeTarget{
+		{Check: NetworkCheckRegistry, URL: "https://harbor.example.com/v2/", Codes: []int{200, 401}, ResolveCheck: true},
+		{Check: NetworkCheckSourceAPI, URL: "https://10.0.0.1:6443/healthz", Optional: true},
+		{Check: NetworkCheckBandwidth, URL: "http://10.0.0.5:8080/payload", Codes: []int{200}, Optional: true},
+		{Check: NetworkCheckRegistry, URL: "https://missing.example.com/v2/"},
+	}
+	output := strings.Join([]string{
+		"probe 0 0 401 0.004 0.015 0.030 0",
+		"probe 1 7 000 0.000 0.000 0.001 0",
+		"probe 2 0 200 0.000 0.001 0.800 10485760",
+		"unrelated output",
+	}, "\n")
+
+	checks := ParseProbeOutput(output, targets)
+	if len(checks) != 5 {
+		t.Fatalf("ParseProbeOutput() = %d checks, want a DNS check and one per target", len(checks))
+	}
+	if dns := checks[0]; dns.Name != NetworkCheckDNS || !dns.Success || dns.Target != "harbor.example.com" || dns.LatencyMs != 4 {
+		t.Errorf("DNS check = %+v", dns)
+	}
+	if registry := checks[1]; !registry.Success || registry.LatencyMs != 11 {
+		t.Errorf("registry check = %+v, want passed on 401 with the connect latency", registry)
+	}
+	if source := checks[2]; source.Success || !source.Optional || source.Message != "failed to connect" {
+		t.Errorf("source check = %+v, want an optional failure", source)
+	}
+	if bandwidth := checks[3]; !bandwidth.Success || bandwidth.ThroughputMbps < 83.8 || bandwidth.ThroughputMbps > 83.9 {
+		t.Errorf("bandwidth check = %+v, want 83.9 Mbit/s", bandwidth)
+	}
+	if missing := checks[4]; missing.Success || missing.Message == "" {
+		t.Errorf("check without result = %+v, want failed", missing)
+	}
+
+	report := &NetworkReport{Success: true}
+	for _, check := range checks[:4] {
+		report.Record(check)
+	}
+	if !report.Success {
+		t.Error("NetworkReport failed on an optional check")
+	}
+	report.Record(checks[4])
+	if report.Success {
+		t.Error("NetworkReport passed with a failed required check")
+	}
+}
+
+func TestImagePullCheck(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "harbor.example.com/ml/model:1"}}}}
+	if _, done := ImagePullCheck(pod); done {
+		t.Error("ImagePullCheck() done without container status")
+	}
+
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{State: corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+	}}}
+	if _, done := ImagePullCheck(pod); done {
+		t.Error("ImagePullCheck() done while the container is being created")
+	}
+
+	pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "unauthorized"}
+	if check, done := ImagePullCheck(pod); !done || check.Success || check.Message != "ImagePullBackOff: unauthorized" {
+		t.Errorf("ImagePullCheck() = %+v, %v, want a failed pull", check, done)
+	}
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+	if check, done := ImagePullCheck(pod); !done || !check.Success || check.Target != "harbor.example.com/ml/model:1" {
+		t.Errorf("ImagePullCheck() = %+v, %v, want a pulled image even if the container exited", check, done)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultRetryBackoff is the delay before the first retry of a failed backup when the policy sets none
+	DefaultRetryBackoff = time.Minute
+	// MaxRetryAttempts bounds the retries of a failed backup
+	MaxRetryAttempts = 10
+)
+
+// RetryPolicy re-triggers the failed executions of a backup. The delay before a retry doubles with each
+// attempt, from Backoff up to MaxBackoff.
+type RetryPolicy struct {
+	// MaxAttempts is the number of retries after a failed execution, 0 turns retries off
+	MaxAttempts int `json:"maxAttempts"`
+	// Backoff is the delay before the first retry, e.g. 30s, it defaults to DefaultRetryBackoff
+	Backoff string `json:"backoff,omitempty"`
+	// MaxBackoff caps the delay between retries, unset for no cap
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+}
+
+// Validate checks the attempts and delays of the policy
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 || p.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("retry maxAttempts must be between 0 and %d", MaxRetryAttempts)
+	}
+	for field, value := range map[string]string{"backoff": p.Backoff, "maxBackoff": p.MaxBackoff} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("retry %s %q is not a positive duration", field, value)
+		}
+	}
+	return nil
+}
+
+// Delay returns how long to wait before an attempt, counted from 1
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := DefaultRetryBackoff
+	if d, err := time.ParseDuration(p.Backoff); err == nil && d > 0 {
+		delay = d
+	}
+	maxDelay, err := time.ParseDuration(p.MaxBackoff)
+	if err != nil {
+		maxDelay = 0
+	}
+	for i := 1; i < attempt && (maxDelay <= 0 || delay < maxDelay); i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// Outcomes of the latest execution of a backup
+const (
+	ExecutionRunning   = "running"
+	ExecutionSucceeded = "succeeded"
+	ExecutionFailed    = "failed"
+)
+
+// ExecutionOutcome returns the outcome of a CheckpointBackup from its phase
+func ExecutionOutcome(phase string) string {
+	switch phase {
+	case "Failed", "failed", "Error", "error":
+		return ExecutionFailed
+	case "Completed", "completed", "Succeeded", "succeeded":
+		return ExecutionSucceeded
+	}
+	return ExecutionRunning
+}
+
+// RetryState is the progress of the retries of the failed executions of a backup
+type RetryState struct {
+	// Attempts is the number of retries since the last successful execution
+	Attempts int `json:"attempts"`
+	// Failure is the latest failed execution that was handled
+	Failure string `json:"failure,omitempty"`
+	// NextRetry is when the failed execution is retried, unset when no retry is pending
+	NextRetry *time.Time `json:"nextRetry,omitempty"`
+	// Exhausted is set when the last failure came after all the attempts of the policy
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
+// RetryAction is what the retry worker does after looking at the latest execution of a backup
+type RetryAction string
+
+// Retry actions
+const (
+	// RetryNone leaves the backup and its state as they are
+	RetryNone RetryAction = ""
+	// RetryReset clears the state after a successful execution
+	RetryReset RetryAction = "reset"
+	// RetrySchedule records a new failure and when it is retried
+	RetrySchedule RetryAction = "schedule"
+	// RetryNow triggers the execution of the backup
+	RetryNow RetryAction = "retry"
+	// RetryExhausted records a failure that comes after all the attempts
+	RetryExhausted RetryAction = "exhausted"
+)
+
+// Next returns the state and the action after the latest execution of a backup, identified by execution and
+// with an outcome of ExecutionOutcome. Failures stay in the same series until an execution succeeds; once the
+// attempts are exhausted, the next failure, e.g. of a scheduled execution, starts a new series.
+func (p RetryPolicy) Next(state RetryState, execution, outcome string, now time.Time) (RetryState, RetryAction) {
+	switch outcome {
+	case ExecutionSucceeded:
+		if state == (RetryState{}) {
+			return state, RetryNone
+		}
+		return RetryState{}, RetryReset
+	case ExecutionFailed:
+	default:
+		return state, RetryNone
+	}
+
+	if execution != state.Failure {
+		if state.Exhausted {
+			state = RetryState{}
+		}
+		state.Failure = execution
+		state.NextRetry = nil
+		if state.Attempts >= p.MaxAttempts {
+			state.Exhausted = true
+			return state, RetryExhausted
+		}
+		next := now.Add(p.Delay(state.Attempts + 1))
+		state.NextRetry = &next
+		return state, RetrySchedule
+	}
+	if state.NextRetry == nil || now.Before(*state.NextRetry) {
+		return state, RetryNone
+	}
+	state.Attempts++
+	state.NextRetry = nil
+	return state, RetryNow
+}
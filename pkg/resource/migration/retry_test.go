@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	valid := []RetryPolicy{{}, {MaxAttempts: 3, Backoff: "30s", MaxBackoff: "10m"}}
+	for _, policy := range valid {
+		if err := policy.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", policy, err)
+		}
+	}
+	invalid := []RetryPolicy{{MaxAttempts: -1}, {MaxAttempts: MaxRetryAttempts + 1}, {MaxAttempts: 1, Backoff: "soon"}, {MaxAttempts: 1, MaxBackoff: "-1m"}}
+	for _, policy := range invalid {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("Validate(%+v) did not fail", policy)
+		}
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, Backoff: "30s", MaxBackoff: "100s"}
+	want := []time.Duration{30 * time.Second, time.Minute, 100 * time.Second, 100 * time.Second}
+	for i, delay := range want {
+		if got := policy.Delay(i + 1); got != delay {
+			t.Errorf("Delay(%d) = %s, want %s", i+1, got, delay)
+		}
+	}
+	if got := (RetryPolicy{MaxAttempts: 1}).Delay(3); got != 4*DefaultRetryBackoff {
+		t.Errorf("Delay(3) without backoff = %s, want %s", got, 4*DefaultRetryBackoff)
+	}
+}
+
+func TestRetryPolicyNext(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, Backoff: "1m"}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	state, action := policy.Next(RetryState{}, "cb-1", ExecutionFailed, now)
+	if action != RetrySchedule || state.NextRetry == nil || !state.NextRetry.Equal(now.Add(time.Minute)) {
+		t.Fatalf("first failure = %+v, %s", state, action)
+	}
+	if _, action := policy.Next(state, "cb-1", ExecutionFailed, now.Add(30*time.Second)); action != RetryNone {
+		t.Errorf("failure before the backoff = %s, want none", action)
+	}
+	state, action = policy.Next(state, "cb-1", ExecutionFailed, now.Add(time.Minute))
+	if action != RetryNow || state.Attempts != 1 || state.NextRetry != nil {
+		t.Fatalf("failure after the backoff = %+v, %s", state, action)
+	}
+	if _, action := policy.Next(state, "cb-1", ExecutionFailed, now.Add(time.Hour)); action != RetryNone {
+		t.Errorf("retried failure = %s, want none", action)
+	}
+	if _, action := policy.Next(state, "cb-2", ExecutionRunning, now.Add(time.Hour)); action != RetryNone {
+		t.Errorf("running retry = %s, want none", action)
+	}
+
+	state, action = policy.Next(state, "cb-2", ExecutionFailed, now.Add(2*time.Minute))
+	if action != RetrySchedule || !state.NextRetry.Equal(now.Add(4*time.Minute)) {
+		t.Fatalf("second failure = %+v, %s", state, action)
+	}
+	state, _ = policy.Next(state, "cb-2", ExecutionFailed, now.Add(4*time.Minute))
+	state, action = policy.Next(state, "cb-3", ExecutionFailed, now.Add(5*time.Minute))
+	if action != RetryExhausted || !state.Exhausted || state.Attempts != 2 {
+		t.Fatalf("failure after the attempts = %+v, %s", state, action)
+	}
+
+	// A later failure starts a new series
+	next, action := policy.Next(state, "cb-4", ExecutionFailed, now.Add(time.Hour))
+	if action != RetrySchedule || next.Attempts != 0 || next.Exhausted {
+		t.Errorf("failure after exhaustion = %+v, %s", next, action)
+	}
+	if next, action := policy.Next(state, "cb-4", ExecutionSucceeded, now.Add(time.Hour)); action != RetryReset || next != (RetryState{}) {
+		t.Errorf("success = %+v, %s", next, action)
+	}
+	if _, action := policy.Next(RetryState{}, "cb-5", ExecutionSucceeded, now); action != RetryNone {
+		t.Errorf("success without retries = %s, want none", action)
+	}
+}
+
+func TestExecutionOutcome(t *testing.T) {
+	tests := map[string]string{"Failed": ExecutionFailed, "error": ExecutionFailed, "Completed": ExecutionSucceeded, "Running": ExecutionRunning, "": ExecutionRunning}
+	for phase, want := range tests {
+		if got := ExecutionOutcome(phase); got != want {
+			t.Errorf("ExecutionOutcome(%q) = %s, want %s", phase, got, want)
+		}
+	}
+}
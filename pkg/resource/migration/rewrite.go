@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Kinds of the objects a recovered workload depends on
+const (
+	KindConfigMap             = "ConfigMap"
+	KindSecret                = "Secret"
+	KindService               = "Service"
+	KindPersistentVolumeClaim = "PersistentVolumeClaim"
+)
+
+// Dependency is an object referenced by a workload
+type Dependency struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Conflict is an object of a recovery that cannot be created on the target cluster
+type Conflict struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// Rename relocates a workload and the objects named after it to another name and namespace
+type Rename struct {
+	SourceName      string
+	SourceNamespace string
+	TargetName      string
+	TargetNamespace string
+}
+
+// Renamed reports whether the workload gets another name or namespace
+func (r Rename) Renamed() bool {
+	return r.SourceName != r.TargetName || r.SourceNamespace != r.TargetNamespace
+}
+
+// Name returns the target name of an object. Objects named after the workload, where the workload name
+// is a dash separated part of their name such as "<name>-config" or "data-<name>-0", follow the
+// renamed workload; other objects keep their name.
+func (r Rename) Name(name string) string {
+	if r.SourceName == "" || r.SourceName == r.TargetName {
+		return name
+	}
+	parts := strings.Split(name, "-")
+	source := strings.Split(r.SourceName, "-")
+	for i := 0; i+len(source) <= len(parts); i++ {
+		if strings.Join(parts[i:i+len(source)], "-") != r.SourceName {
+			continue
+		}
+		renamed := append(append(append([]string{}, parts[:i]...), r.TargetName), parts[i+len(source):]...)
+		return strings.Join(renamed, "-")
+	}
+	return name
+}
+
+// PodDependencies returns the ConfigMaps, Secrets and claims referenced by a pod spec through volumes,
+// environment variables and image pull secrets, sorted by kind and name
+func PodDependencies(spec *corev1.PodSpec) []Dependency {
+	seen := map[Dependency]bool{}
+	add := func(kind, name string) {
+		if name != "" {
+			seen[Dependency{Kind: kind, Name: name}] = true
+		}
+	}
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			add(KindConfigMap, volume.ConfigMap.Name)
+		case volume.Secret != nil:
+			add(KindSecret, volume.Secret.SecretName)
+		case volume.PersistentVolumeClaim != nil:
+			add(KindPersistentVolumeClaim, volume.PersistentVolumeClaim.ClaimName)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(KindConfigMap, source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					add(KindSecret, source.Secret.Name)
+				}
+			}
+		}
+	}
+	for _, container := range podContainers(spec) {
+		for _, from := range container.EnvFrom {
+			if from.ConfigMapRef != nil {
+				add(KindConfigMap, from.ConfigMapRef.Name)
+			}
+			if from.SecretRef != nil {
+				add(KindSecret, from.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				add(KindConfigMap, env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				add(KindSecret, env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+	for _, secret := range spec.ImagePullSecrets {
+		add(KindSecret, secret.Name)
+	}
+
+	dependencies := make([]Dependency, 0, len(seen))
+	for dependency := range seen {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Kind != dependencies[j].Kind {
+			return dependencies[i].Kind < dependencies[j].Kind
+		}
+		return dependencies[i].Name < dependencies[j].Name
+	})
+	return dependencies
+}
+
+// RewritePodSpec renames the ConfigMaps, Secrets and claims referenced by a pod spec, and the subdomain
+// of the pods, the same way as the objects they reference
+func RewritePodSpec(spec *corev1.PodSpec, rename Rename) {
+	for i := range spec.Volumes {
+		volume := &spec.Volumes[i]
+		switch {
+		case volume.ConfigMap != nil:
+			volume.ConfigMap.Name = rename.Name(volume.ConfigMap.Name)
+		case volume.Secret != nil:
+			volume.Secret.SecretName = rename.Name(volume.Secret.SecretName)
+		case volume.PersistentVolumeClaim != nil:
+			volume.PersistentVolumeClaim.ClaimName = rename.Name(volume.PersistentVolumeClaim.ClaimName)
+		case volume.Projected != nil:
+			for j := range volume.Projected.Sources {
+				source := &volume.Projected.Sources[j]
+				if source.ConfigMap != nil {
+					source.ConfigMap.Name = rename.Name(source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					source.Secret.Name = rename.Name(source.Secret.Name)
+				}
+			}
+		}
+	}
+	for _, container := range podContainers(spec) {
+		for i := range container.EnvFrom {
+			from := &container.EnvFrom[i]
+			if from.ConfigMapRef != nil {
+				from.ConfigMapRef.Name = rename.Name(from.ConfigMapRef.Name)
+			}
+			if from.SecretRef != nil {
+				from.SecretRef.Name = rename.Name(from.SecretRef.Name)
+			}
+		}
+		for i := range container.Env {
+			valueFrom := container.Env[i].ValueFrom
+			if valueFrom == nil {
+				continue
+			}
+			if valueFrom.ConfigMapKeyRef != nil {
+				valueFrom.ConfigMapKeyRef.Name = rename.Name(valueFrom.ConfigMapKeyRef.Name)
+			}
+			if valueFrom.SecretKeyRef != nil {
+				valueFrom.SecretKeyRef.Name = rename.Name(valueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+	for i := range spec.ImagePullSecrets {
+		spec.ImagePullSecrets[i].Name = rename.Name(spec.ImagePullSecrets[i].Name)
+	}
+	if spec.Subdomain != "" {
+		spec.Subdomain = rename.Name(spec.Subdomain)
+	}
+}
+
+// podContainers returns pointers to the init and regular containers of a pod spec
+func podContainers(spec *corev1.PodSpec) []*corev1.Container {
+	containers := make([]*corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	for i := range spec.InitContainers {
+		containers = append(containers, &spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		containers = append(containers, &spec.Containers[i])
+	}
+	return containers
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRenameName(t *testing.T) {
+	rename := Rename{SourceName: "db", SourceNamespace: "default", TargetName: "db-restored", TargetNamespace: "prod"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "db", want: "db-restored"},
+		{name: "db-config", want: "db-restored-config"},
+		{name: "data-db-0", want: "data-db-restored-0"},
+		{name: "redis-config", want: "redis-config"},
+		{name: "dbx-config", want: "dbx-config"},
+		{name: "shared", want: "shared"},
+	}
+	for _, tt := range tests {
+		if got := rename.Name(tt.name); got != tt.want {
+			t.Errorf("Name(%s) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	multi := Rename{SourceName: "my-db", TargetName: "new-db"}
+	if got := multi.Name("data-my-db-1"); got != "data-new-db-1" {
+		t.Errorf("Name(data-my-db-1) = %s, want data-new-db-1", got)
+	}
+	if got := (Rename{SourceName: "db", TargetName: "db"}).Name("db-config"); got != "db-config" {
+		t.Errorf("Name() without rename = %s, want db-config", got)
+	}
+	if !rename.Renamed() || (Rename{SourceName: "db", TargetName: "db", SourceNamespace: "a", TargetNamespace: "a"}).Renamed() {
+		t.Error("Renamed() did not detect the target")
+	}
+}
+
+func newDependentPodSpec() *corev1.PodSpec {
+	return &corev1.PodSpec{
+		Subdomain:        "db",
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db-config"}}}},
+			{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db-tls"}}},
+			{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db"}}},
+			{Name: "projected", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "shared"}}},
+			}}}},
+		},
+		InitContainers: []corev1.Container{{
+			Name:    "init",
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db-credentials"}}}},
+		}},
+		Containers: []corev1.Container{{
+			Name: "db",
+			Env: []corev1.EnvVar{{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db-credentials"}, Key: "password"},
+			}}},
+		}},
+	}
+}
+
+func TestPodDependencies(t *testing.T) {
+	got := PodDependencies(newDependentPodSpec())
+	want := []Dependency{
+		{Kind: KindConfigMap, Name: "db-config"},
+		{Kind: KindConfigMap, Name: "shared"},
+		{Kind: KindPersistentVolumeClaim, Name: "data-db"},
+		{Kind: KindSecret, Name: "db-credentials"},
+		{Kind: KindSecret, Name: "db-tls"},
+		{Kind: KindSecret, Name: "registry"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PodDependencies() = %v, want %v", got, want)
+	}
+}
+
+func TestRewritePodSpec(t *testing.T) {
+	spec := newDependentPodSpec()
+	RewritePodSpec(spec, Rename{SourceName: "db", TargetName: "db2"})
+
+	got := PodDependencies(spec)
+	want := []Dependency{
+		{Kind: KindConfigMap, Name: "db2-config"},
+		{Kind: KindConfigMap, Name: "shared"},
+		{Kind: KindPersistentVolumeClaim, Name: "data-db2"},
+		{Kind: KindSecret, Name: "db2-credentials"},
+		{Kind: KindSecret, Name: "db2-tls"},
+		{Kind: KindSecret, Name: "registry"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dependencies after rewrite = %v, want %v", got, want)
+	}
+	if spec.Subdomain != "db2" {
+		t.Errorf("subdomain = %s, want db2", spec.Subdomain)
+	}
+	if name := spec.Containers[0].Env[0].ValueFrom.SecretKeyRef.Name; name != "db2-credentials" {
+		t.Errorf("env secret = %s, want db2-credentials", name)
+	}
+}
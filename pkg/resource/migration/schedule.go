@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GenerateID returns a unique ID for an operation named name
+func GenerateID(name string) string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(strings.ReplaceAll(name, " ", "-")), time.Now().Unix())
+}
+
+// ResourceAPIVersion returns the API version of a kind of workload a migration refers to
+func ResourceAPIVersion(kind string) string {
+	switch strings.ToLower(kind) {
+	case "statefulset":
+		return "apps/v1"
+	default:
+		return "v1"
+	}
+}
+
+// SelectionToCron converts a schedule selected in the UI to a cron expression, daily by default
+func SelectionToCron(selection string) string {
+	switch selection {
+	case "5m":
+		return "*/5 * * * *"
+	case "15m":
+		return "*/15 * * * *"
+	case "30m":
+		return "*/30 * * * *"
+	case "1h":
+		return "0 * * * *"
+	default:
+		return "0 0 * * *"
+	}
+}
+
+// CronExpression returns the cron expression of a schedule, which is either a selection or a cron expression
+func CronExpression(scheduleType, value string) string {
+	if scheduleType == "selection" {
+		return SelectionToCron(value)
+	}
+	return value
+}
+
+// ValidateCronExpression checks that a cron expression has five fields
+func ValidateCronExpression(cron string) error {
+	parts := strings.Fields(cron)
+	if len(parts) != 5 {
+		return fmt.Errorf("cron expression must have 5 fields")
+	}
+	return nil
+}
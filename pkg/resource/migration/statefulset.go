@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrdinalPodName returns the name of the pod of a StatefulSet ordinal
+func OrdinalPodName(statefulSetName string, ordinal int) string {
+	return fmt.Sprintf("%s-%d", statefulSetName, ordinal)
+}
+
+// PodOrdinal returns the ordinal of a StatefulSet pod, whose name is <statefulset>-<ordinal>
+func PodOrdinal(statefulSetName, podName string) (int, bool) {
+	suffix, ok := strings.CutPrefix(podName, statefulSetName+"-")
+	if !ok {
+		return 0, false
+	}
+	ordinal, err := strconv.Atoi(suffix)
+	if err != nil || ordinal < 0 {
+		return 0, false
+	}
+	return ordinal, true
+}
+
+// StatefulSetReplicas returns the desired replicas of a StatefulSet, 1 when unset
+func StatefulSetReplicas(sts *appsv1.StatefulSet) int {
+	if sts.Spec.Replicas == nil {
+		return 1
+	}
+	return int(*sts.Spec.Replicas)
+}
+
+// cleanObjectMeta returns the metadata of a copy of an object in another cluster, without the fields
+// set by the API server of the source cluster
+func cleanObjectMeta(meta metav1.ObjectMeta, name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
+
+// TargetStatefulSet returns the StatefulSet to create on the target cluster for a source StatefulSet.
+// It is scaled to zero so the pods are restored one ordinal at a time, and its pods are started in
+// ordinal order once it is scaled up.
+func TargetStatefulSet(source *appsv1.StatefulSet, name, namespace string) *appsv1.StatefulSet {
+	target := &appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+		ObjectMeta: cleanObjectMeta(source.ObjectMeta, name, namespace),
+		Spec:       *source.Spec.DeepCopy(),
+	}
+	replicas := int32(0)
+	target.Spec.Replicas = &replicas
+	target.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	for i := range target.Spec.VolumeClaimTemplates {
+		template := &target.Spec.VolumeClaimTemplates[i]
+		template.ObjectMeta = cleanObjectMeta(template.ObjectMeta, template.Name, "")
+		template.Status = corev1.PersistentVolumeClaimStatus{}
+	}
+	return target
+}
+
+// TargetService returns a copy of a Service for the target cluster. Cluster IPs are allocated by the
+// target cluster, except for headless services which keep "None".
+func TargetService(source *corev1.Service, namespace string) *corev1.Service {
+	target := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: cleanObjectMeta(source.ObjectMeta, source.Name, namespace),
+		Spec:       *source.Spec.DeepCopy(),
+	}
+	if target.Spec.ClusterIP != corev1.ClusterIPNone {
+		target.Spec.ClusterIP = ""
+	}
+	target.Spec.ClusterIPs = nil
+	for i := range target.Spec.Ports {
+		target.Spec.Ports[i].NodePort = 0
+	}
+	target.Spec.HealthCheckNodePort = 0
+	return target
+}
+
+// OrdinalClaims returns the claims the StatefulSet controller creates for an ordinal from the volume
+// claim templates, named <template>-<statefulset>-<ordinal>
+func OrdinalClaims(sts *appsv1.StatefulSet, ordinal int) []*corev1.PersistentVolumeClaim {
+	claims := make([]*corev1.PersistentVolumeClaim, 0, len(sts.Spec.VolumeClaimTemplates))
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		claim := &corev1.PersistentVolumeClaim{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+			ObjectMeta: cleanObjectMeta(template.ObjectMeta, fmt.Sprintf("%s-%s", template.Name, OrdinalPodName(sts.Name, ordinal)), sts.Namespace),
+			Spec:       *template.Spec.DeepCopy(),
+		}
+		// The StatefulSet controller labels the claims with the pod selector
+		if sts.Spec.Selector != nil && len(sts.Spec.Selector.MatchLabels) > 0 {
+			labels := make(map[string]string, len(claim.Labels)+len(sts.Spec.Selector.MatchLabels))
+			for key, value := range claim.Labels {
+				labels[key] = value
+			}
+			for key, value := range sts.Spec.Selector.MatchLabels {
+				labels[key] = value
+			}
+			claim.Labels = labels
+		}
+		claims = append(claims, claim)
+	}
+	return claims
+}
+
+// PodReady reports whether the Ready condition of a pod is true
+func PodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodOrdinal(t *testing.T) {
+	tests := []struct {
+		podName string
+		ordinal int
+		ok      bool
+	}{
+		{podName: "db-0", ordinal: 0, ok: true},
+		{podName: "db-12", ordinal: 12, ok: true},
+		{podName: "db-replica-1", ok: false},
+		{podName: "db-", ok: false},
+		{podName: "web-0", ok: false},
+	}
+	for _, tt := range tests {
+		ordinal, ok := PodOrdinal("db", tt.podName)
+		if ok != tt.ok || ordinal != tt.ordinal {
+			t.Errorf("PodOrdinal(db, %s) = %d, %v, want %d, %v", tt.podName, ordinal, ok, tt.ordinal, tt.ok)
+		}
+	}
+	if name := OrdinalPodName("db", 3); name != "db-3" {
+		t.Errorf("OrdinalPodName() = %s, want db-3", name)
+	}
+}
+
+func newSourceStatefulSet() *appsv1.StatefulSet {
+	replicas := int32(3)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "db",
+			Namespace:       "default",
+			Labels:          map[string]string{"app": "db"},
+			ResourceVersion: "42",
+			UID:             "uid",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            &replicas,
+			ServiceName:         "db",
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector:            &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Labels: map[string]string{"tier": "storage"}, ResourceVersion: "7"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			}},
+		},
+	}
+}
+
+func TestTargetStatefulSet(t *testing.T) {
+	source := newSourceStatefulSet()
+	target := TargetStatefulSet(source, "db-restored", "prod")
+
+	if target.Name != "db-restored" || target.Namespace != "prod" {
+		t.Errorf("target = %s/%s, want prod/db-restored", target.Namespace, target.Name)
+	}
+	if target.ResourceVersion != "" || target.UID != "" {
+		t.Errorf("target kept server fields: resourceVersion %q, uid %q", target.ResourceVersion, target.UID)
+	}
+	if target.Spec.Replicas == nil || *target.Spec.Replicas != 0 {
+		t.Errorf("target replicas = %v, want 0", target.Spec.Replicas)
+	}
+	if target.Spec.PodManagementPolicy != appsv1.OrderedReadyPodManagement {
+		t.Errorf("target pod management policy = %s, want OrderedReady", target.Spec.PodManagementPolicy)
+	}
+	template := target.Spec.VolumeClaimTemplates[0]
+	if template.ResourceVersion != "" || template.Status.Phase != "" {
+		t.Errorf("claim template kept server fields: %+v", template)
+	}
+	if *source.Spec.Replicas != 3 || source.Spec.VolumeClaimTemplates[0].ResourceVersion != "7" {
+		t.Error("TargetStatefulSet modified the source")
+	}
+	if StatefulSetReplicas(source) != 3 {
+		t.Errorf("StatefulSetReplicas() = %d, want 3", StatefulSetReplicas(source))
+	}
+}
+
+func TestTargetService(t *testing.T) {
+	headless := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, ClusterIPs: []string{corev1.ClusterIPNone}},
+	}
+	if target := TargetService(headless, "prod"); target.Spec.ClusterIP != corev1.ClusterIPNone || target.Namespace != "prod" {
+		t.Errorf("headless target = %s/%s, want None in prod", target.Spec.ClusterIP, target.Namespace)
+	}
+
+	nodePort := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-external", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Type:       corev1.ServiceTypeNodePort,
+			ClusterIP:  "10.0.0.1",
+			ClusterIPs: []string{"10.0.0.1"},
+			Ports:      []corev1.ServicePort{{Port: 5432, NodePort: 30432}},
+		},
+	}
+	target := TargetService(nodePort, "default")
+	if target.Spec.ClusterIP != "" || target.Spec.ClusterIPs != nil || target.Spec.Ports[0].NodePort != 0 {
+		t.Errorf("target kept allocated fields: %+v", target.Spec)
+	}
+	if nodePort.Spec.Ports[0].NodePort != 30432 {
+		t.Error("TargetService modified the source")
+	}
+}
+
+func TestOrdinalClaims(t *testing.T) {
+	sts := TargetStatefulSet(newSourceStatefulSet(), "db", "prod")
+	claims := OrdinalClaims(sts, 2)
+	if len(claims) != 1 {
+		t.Fatalf("OrdinalClaims() returned %d claims, want 1", len(claims))
+	}
+	claim := claims[0]
+	if claim.Name != "data-db-2" || claim.Namespace != "prod" {
+		t.Errorf("claim = %s/%s, want prod/data-db-2", claim.Namespace, claim.Name)
+	}
+	if claim.Labels["app"] != "db" || claim.Labels["tier"] != "storage" {
+		t.Errorf("claim labels = %v, want template and selector labels", claim.Labels)
+	}
+}
+
+func TestPodReady(t *testing.T) {
+	pod := &corev1.Pod{}
+	if PodReady(pod) {
+		t.Error("PodReady() = true for a pod without conditions")
+	}
+	pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	if !PodReady(pod) {
+		t.Error("PodReady() = false for a ready pod")
+	}
+}
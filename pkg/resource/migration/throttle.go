@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+// InheritedSettingsAnnotation lists the environment variables of a controller ConfigMap that come from the
+// global throttle rather than from the settings of the cluster
+const InheritedSettingsAnnotation = "ml-platform.io/inherited-settings"
+
+// Throttle limits what the checkpoints of a backup or of a controller take from the nodes and the network.
+// Unset fields leave the limit to the next level: a backup falls back to its controller, a controller to the
+// global throttle of the runtime config.
+type Throttle struct {
+	// UploadBandwidthLimit caps the upload rate of checkpoints per node, as bytes per second like 50Mi
+	UploadBandwidthLimit string `json:"uploadBandwidthLimit,omitempty"`
+	// CheckpointConcurrency is the number of containers checkpointed in parallel per node
+	CheckpointConcurrency int `json:"checkpointConcurrency,omitempty"`
+}
+
+// GlobalThrottle returns the throttle of the runtime config, which applies to the controllers of every cluster
+func GlobalThrottle() Throttle {
+	throttle := config.CheckpointThrottle()
+	return Throttle{UploadBandwidthLimit: throttle.UploadBandwidthLimit, CheckpointConcurrency: throttle.CheckpointConcurrency}
+}
+
+// IsZero reports whether the throttle sets no limit
+func (t Throttle) IsZero() bool {
+	return t.UploadBandwidthLimit == "" && t.CheckpointConcurrency == 0
+}
+
+// Validate checks the limits
+func (t Throttle) Validate() error {
+	if t.CheckpointConcurrency < 0 || t.CheckpointConcurrency > MaxCheckpointConcurrency {
+		return fmt.Errorf("checkpointConcurrency must be at most %d, or 0 for the default", MaxCheckpointConcurrency)
+	}
+	if t.UploadBandwidthLimit != "" {
+		if _, err := ParseBandwidth(t.UploadBandwidthLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseBandwidth returns the bytes per second of a bandwidth limit like 50Mi or 100M
+func ParseBandwidth(limit string) (int64, error) {
+	q, err := resource.ParseQuantity(limit)
+	if err != nil || q.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid uploadBandwidthLimit %q: must be a positive quantity of bytes per second like 50Mi", limit)
+	}
+	return q.Value(), nil
+}
+
+// formatBandwidth returns a bandwidth in bytes per second as a quantity, with binary suffixes when they are exact
+func formatBandwidth(bytes int64) string {
+	if bytes%1024 == 0 {
+		return resource.NewQuantity(bytes, resource.BinarySI).String()
+	}
+	return resource.NewQuantity(bytes, resource.DecimalSI).String()
+}
+
+// Spec returns the throttle as the field of a StatefulMigration spec, nil when it sets no limit
+func (t Throttle) Spec() map[string]interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	spec := map[string]interface{}{}
+	if t.UploadBandwidthLimit != "" {
+		spec["uploadBandwidthLimit"] = t.UploadBandwidthLimit
+	}
+	if t.CheckpointConcurrency > 0 {
+		spec["checkpointConcurrency"] = int64(t.CheckpointConcurrency)
+	}
+	return spec
+}
+
+// WithDefaults returns the settings the controller runs with: the settings of its cluster, with the limits
+// they leave unset taken from the global throttle. The environment variables taken from it are returned too.
+func (c ControllerConfig) WithDefaults(defaults Throttle) (ControllerConfig, []string) {
+	var inherited []string
+	if c.CheckpointConcurrency == 0 && defaults.CheckpointConcurrency > 0 {
+		c.CheckpointConcurrency = defaults.CheckpointConcurrency
+		inherited = append(inherited, EnvCheckpointConcurrency)
+	}
+	if c.UploadBandwidthLimit == "" && defaults.UploadBandwidthLimit != "" {
+		c.UploadBandwidthLimit = defaults.UploadBandwidthLimit
+		inherited = append(inherited, EnvUploadBandwidthLimit)
+	}
+	return c, inherited
+}
+
+// ClusterSettings returns the data of a controller ConfigMap without the settings inherited from the global
+// throttle, as listed in its InheritedSettingsAnnotation
+func ClusterSettings(data map[string]string, annotations map[string]string) map[string]string {
+	inherited := annotations[InheritedSettingsAnnotation]
+	if inherited == "" {
+		return data
+	}
+	settings := make(map[string]string, len(data))
+	for key, value := range data {
+		settings[key] = value
+	}
+	for _, key := range strings.Split(inherited, ",") {
+		delete(settings, key)
+	}
+	return settings
+}
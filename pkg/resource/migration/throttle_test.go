@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestThrottleValidate(t *testing.T) {
+	for _, throttle := range []Throttle{{}, {UploadBandwidthLimit: "50Mi", CheckpointConcurrency: 2}, {UploadBandwidthLimit: "100M"}} {
+		if err := throttle.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", throttle, err)
+		}
+	}
+	for _, throttle := range []Throttle{{UploadBandwidthLimit: "fast"}, {UploadBandwidthLimit: "0"}, {CheckpointConcurrency: -1}} {
+		if err := throttle.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil", throttle)
+		}
+	}
+}
+
+func TestThrottleSpec(t *testing.T) {
+	if spec := (Throttle{}).Spec(); spec != nil {
+		t.Errorf("Spec() of an empty throttle = %v, want nil", spec)
+	}
+	want := map[string]interface{}{"uploadBandwidthLimit": "50Mi", "checkpointConcurrency": int64(2)}
+	if spec := (Throttle{UploadBandwidthLimit: "50Mi", CheckpointConcurrency: 2}).Spec(); !reflect.DeepEqual(spec, want) {
+		t.Errorf("Spec() = %v, want %v", spec, want)
+	}
+}
+
+func TestControllerConfigWithDefaults(t *testing.T) {
+	cluster := ControllerConfig{CheckpointConcurrency: 4, ResyncInterval: "10m"}
+	effective, inherited := cluster.WithDefaults(Throttle{UploadBandwidthLimit: "50Mi", CheckpointConcurrency: 1})
+	if effective.CheckpointConcurrency != 4 || effective.UploadBandwidthLimit != "50Mi" {
+		t.Errorf("WithDefaults() = %+v, want the cluster concurrency and the global bandwidth limit", effective)
+	}
+	if !reflect.DeepEqual(inherited, []string{EnvUploadBandwidthLimit}) {
+		t.Errorf("WithDefaults() inherited = %v, want the bandwidth limit", inherited)
+	}
+
+	data := effective.ConfigMapData()
+	if data[EnvUploadBandwidthLimit] != "52428800" {
+		t.Errorf("ConfigMapData() bandwidth = %q, want bytes per second", data[EnvUploadBandwidthLimit])
+	}
+	settings := ClusterSettings(data, map[string]string{InheritedSettingsAnnotation: EnvUploadBandwidthLimit})
+	if _, ok := settings[EnvUploadBandwidthLimit]; ok || settings[EnvCheckpointConcurrency] != "4" {
+		t.Errorf("ClusterSettings() = %v, want the settings of the cluster only", settings)
+	}
+	if _, ok := data[EnvUploadBandwidthLimit]; !ok {
+		t.Error("ClusterSettings() modified the ConfigMap data")
+	}
+
+	daemonSet := newDaemonSet("checkpoint-backup-controller-member1", 1)
+	got, err := ReadControllerConfig(daemonSet, "manager", map[string]string{EnvUploadBandwidthLimit: "100000000"})
+	if err != nil || got.UploadBandwidthLimit != "100M" {
+		t.Errorf("ReadControllerConfig() bandwidth = %q, %v, want 100M", got.UploadBandwidthLimit, err)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Sources of timeline entries
+const (
+	TimelineSourceEvent      = "event"
+	TimelineSourceCheckpoint = "checkpoint"
+	TimelineSourceLog        = "log"
+)
+
+// TimelineEntry is one thing that happened to a backup, from a Kubernetes Event, a CheckpointBackup status
+// change or a controller log line
+type TimelineEntry struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Cluster string    `json:"cluster,omitempty"`
+	// Object is the kind/namespace/name of the resource the entry is about
+	Object string `json:"object,omitempty"`
+	// Type is the event type, the checkpoint phase or condition status, empty for log lines
+	Type    string `json:"type,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+// EventTimeline returns the entries of the events about the objects that involved accepts
+func EventTimeline(events []corev1.Event, cluster string, involved func(kind, name string) bool) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, event := range events {
+		ref := event.InvolvedObject
+		if !involved(ref.Kind, ref.Name) {
+			continue
+		}
+		message := event.Message
+		if event.Count > 1 {
+			message = fmt.Sprintf("%s (x%d)", message, event.Count)
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    eventTime(event),
+			Source:  TimelineSourceEvent,
+			Cluster: cluster,
+			Object:  objectKey(ref.Kind, ref.Namespace, ref.Name),
+			Type:    event.Type,
+			Reason:  event.Reason,
+			Message: message,
+		})
+	}
+	return entries
+}
+
+// eventTime returns when an event last happened
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// CheckpointTimeline returns the status transitions of a CheckpointBackup: its creation, the transitions of its
+// conditions and its current phase when the status records when it was reached
+func CheckpointTimeline(checkpoint *unstructured.Unstructured, cluster string) []TimelineEntry {
+	object := objectKey("CheckpointBackup", checkpoint.GetNamespace(), checkpoint.GetName())
+	entries := []TimelineEntry{{
+		Time:    checkpoint.GetCreationTimestamp().Time,
+		Source:  TimelineSourceCheckpoint,
+		Cluster: cluster,
+		Object:  object,
+		Type:    "Created",
+		Message: fmt.Sprintf("CheckpointBackup %s created", checkpoint.GetName()),
+	}}
+
+	conditions, _, _ := unstructured.NestedSlice(checkpoint.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transitioned, ok := nestedTime(conditionMap, "lastTransitionTime")
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(conditionMap, "type")
+		status, _, _ := unstructured.NestedString(conditionMap, "status")
+		reason, _, _ := unstructured.NestedString(conditionMap, "reason")
+		message, _, _ := unstructured.NestedString(conditionMap, "message")
+		if message == "" {
+			message = fmt.Sprintf("%s is %s", conditionType, status)
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    transitioned,
+			Source:  TimelineSourceCheckpoint,
+			Cluster: cluster,
+			Object:  object,
+			Type:    conditionType + "=" + status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+
+	phase, _, _ := unstructured.NestedString(checkpoint.Object, "status", "phase")
+	if phase == "" {
+		return entries
+	}
+	status, _, _ := unstructured.NestedMap(checkpoint.Object, "status")
+	for _, field := range []string{"completionTime", "completedAt", "lastTransitionTime", "lastUpdateTime"} {
+		if reached, ok := nestedTime(status, field); ok {
+			message, _, _ := unstructured.NestedString(status, "message")
+			if message == "" {
+				message = fmt.Sprintf("CheckpointBackup %s is %s", checkpoint.GetName(), phase)
+			}
+			entries = append(entries, TimelineEntry{
+				Time:    reached,
+				Source:  TimelineSourceCheckpoint,
+				Cluster: cluster,
+				Object:  object,
+				Type:    phase,
+				Message: message,
+			})
+			break
+		}
+	}
+	return entries
+}
+
+// LogTimeline returns the controller log lines that mention one of the keywords. The lines must start with the
+// RFC3339 timestamp the API server adds when logs are requested with timestamps.
+func LogTimeline(lines []string, cluster, object string, keywords []string) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, line := range lines {
+		timestamp, message, found := strings.Cut(line, " ")
+		if !found || !containsAny(message, keywords) {
+			continue
+		}
+		logged, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    logged,
+			Source:  TimelineSourceLog,
+			Cluster: cluster,
+			Object:  object,
+			Message: strings.TrimSpace(message),
+		})
+	}
+	return entries
+}
+
+// SortTimeline keeps the entries between from and to, either of which may be zero, in chronological order
+func SortTimeline(entries []TimelineEntry, from, to time.Time) []TimelineEntry {
+	result := make([]TimelineEntry, 0, len(entries))
+	for _, entry := range entries {
+		if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && entry.Time.After(to)) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
+
+func nestedTime(obj map[string]interface{}, field string) (time.Time, bool) {
+	value, _, _ := unstructured.NestedString(obj, field)
+	if value == "" {
+		return time.Time{}, false
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	return parsed, err == nil
+}
+
+func objectKey(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+func containsAny(s string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var timelineStart = time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+
+func TestEventTimeline(t *testing.T) {
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "db", Name: "postgres-0"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			Message:        "0/3 nodes are available",
+			Count:          3,
+			LastTimestamp:  metav1.NewTime(timelineStart),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "db", Name: "redis-0"},
+			Reason:         "Started",
+			LastTimestamp:  metav1.NewTime(timelineStart),
+		},
+	}
+	entries := EventTimeline(events, "member1", func(kind, name string) bool { return name == "postgres-0" })
+	if len(entries) != 1 {
+		t.Fatalf("EventTimeline() = %v, want the event of postgres-0", entries)
+	}
+	entry := entries[0]
+	if entry.Object != "Pod/db/postgres-0" || entry.Message != "0/3 nodes are available (x3)" || !entry.Time.Equal(timelineStart) {
+		t.Errorf("EventTimeline() = %+v", entry)
+	}
+}
+
+func TestCheckpointTimeline(t *testing.T) {
+	checkpoint := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":          "Failed",
+			"message":        "criu dump failed",
+			"completionTime": timelineStart.Add(2 * time.Minute).Format(time.RFC3339),
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":               "Checkpointed",
+					"status":             "False",
+					"reason":             "DumpFailed",
+					"lastTransitionTime": timelineStart.Add(time.Minute).Format(time.RFC3339),
+				},
+			},
+		},
+	}}
+	checkpoint.SetNamespace("db")
+	checkpoint.SetName("postgres-backup")
+	checkpoint.SetCreationTimestamp(metav1.NewTime(timelineStart))
+
+	entries := CheckpointTimeline(checkpoint, "member1")
+	if len(entries) != 3 {
+		t.Fatalf("CheckpointTimeline() = %v, want creation, condition and phase", entries)
+	}
+	if entries[1].Type != "Checkpointed=False" || entries[1].Reason != "DumpFailed" {
+		t.Errorf("condition entry = %+v", entries[1])
+	}
+	if entries[2].Type != "Failed" || entries[2].Message != "criu dump failed" {
+		t.Errorf("phase entry = %+v", entries[2])
+	}
+}
+
+func TestLogTimelineAndSort(t *testing.T) {
+	lines := []string{
+		timelineStart.Add(time.Minute).Format(time.RFC3339Nano) + " reconciling backup-nightly",
+		timelineStart.Format(time.RFC3339Nano) + " schedule for backup-nightly not due",
+		timelineStart.Format(time.RFC3339Nano) + " reconciling backup-other",
+		"not a timestamped line backup-nightly",
+	}
+	logs := LogTimeline(lines, "mgmt-cluster", "Deployment/stateful-migration/migration-backup-controller", []string{"backup-nightly"})
+	if len(logs) != 2 {
+		t.Fatalf("LogTimeline() = %v, want the 2 timestamped lines of backup-nightly", logs)
+	}
+
+	earlier := TimelineEntry{Time: timelineStart.Add(-time.Hour), Source: TimelineSourceEvent, Message: "too early"}
+	sorted := SortTimeline(append(logs, earlier), timelineStart, time.Time{})
+	if len(sorted) != 2 || sorted[0].Message != "schedule for backup-nightly not due" {
+		t.Errorf("SortTimeline() = %v, want the log lines in order without the earlier entry", sorted)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Metadata of the CRs in the trash. A deleted CR is labelled so that lists leave it out, and stays until it is
+// restored or purged.
+const (
+	DeletedLabel        = "ml-platform.io/deleted"
+	DeletedAtAnnotation = "ml-platform.io/deleted-at"
+	DeletedByAnnotation = "ml-platform.io/deleted-by"
+	// trashedScheduleAnnotation keeps the schedule of a backup in the trash, which is removed from the spec so
+	// that the controller stops taking checkpoints
+	trashedScheduleAnnotation = "ml-platform.io/trashed-schedule"
+)
+
+// ErrNotInTrash is returned when a CR that is not in the trash is restored
+var ErrNotInTrash = errors.New("not in the trash")
+
+// IsDeleted reports whether a CR is in the trash
+func IsDeleted(obj *unstructured.Unstructured) bool {
+	return obj.GetLabels()[DeletedLabel] == "true"
+}
+
+// DeletedAt returns when a CR was moved to the trash, the zero time when it is not in the trash
+func DeletedAt(obj *unstructured.Unstructured) time.Time {
+	deletedAt, _ := time.Parse(time.RFC3339, obj.GetAnnotations()[DeletedAtAnnotation])
+	return deletedAt
+}
+
+// DeletedBy returns the user who moved a CR to the trash
+func DeletedBy(obj *unstructured.Unstructured) string {
+	return obj.GetAnnotations()[DeletedByAnnotation]
+}
+
+// Trash moves the CR of an operation to the trash instead of deleting it. The schedule of a backup is put
+// aside until it is restored. CRs managed by GitOps are rejected with a GitOpsManagedError.
+func (s *Service) Trash(ctx context.Context, id, user string, now time.Time) (*unstructured.Unstructured, error) {
+	return s.mutate(ctx, id, s.get, func(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		if err := ensureMutable(obj); err != nil {
+			return nil, err
+		}
+		if IsDeleted(obj) {
+			return obj, nil
+		}
+		trash(obj, user, now)
+		return s.update(ctx, obj)
+	})
+}
+
+// trash marks a CR as deleted and puts its schedule aside
+func trash(obj *unstructured.Unstructured, user string, now time.Time) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[DeletedLabel] = "true"
+	obj.SetLabels(labels)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[DeletedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	if user != "" {
+		annotations[DeletedByAnnotation] = user
+	}
+	if schedule, found, _ := unstructured.NestedString(obj.Object, "spec", "schedule"); found {
+		annotations[trashedScheduleAnnotation] = schedule
+		unstructured.RemoveNestedField(obj.Object, "spec", "schedule")
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// Restore takes the CR of an operation out of the trash, with the schedule it had when it was deleted
+func (s *Service) Restore(ctx context.Context, id string) (*unstructured.Unstructured, error) {
+	return s.mutate(ctx, id, s.get, func(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+		if !IsDeleted(obj) {
+			return nil, ErrNotInTrash
+		}
+		if err := restore(obj); err != nil {
+			return nil, err
+		}
+		return s.update(ctx, obj)
+	})
+}
+
+// restore clears the deletion marks of a CR and puts its schedule back
+func restore(obj *unstructured.Unstructured) error {
+	labels := obj.GetLabels()
+	delete(labels, DeletedLabel)
+	obj.SetLabels(labels)
+	annotations := obj.GetAnnotations()
+	if schedule, ok := annotations[trashedScheduleAnnotation]; ok {
+		if err := unstructured.SetNestedField(obj.Object, schedule, "spec", "schedule"); err != nil {
+			return err
+		}
+	}
+	delete(annotations, DeletedAtAnnotation)
+	delete(annotations, DeletedByAnnotation)
+	delete(annotations, trashedScheduleAnnotation)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// Purge deletes the CR of an operation in the trash for good, CRs that are not in the trash are rejected with
+// ErrNotInTrash
+func (s *Service) Purge(ctx context.Context, id string) error {
+	obj, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !IsDeleted(obj) {
+		return ErrNotInTrash
+	}
+	return s.Delete(ctx, id)
+}
+
+// ListTrash returns the CRs of the kind in the trash
+func (s *Service) ListTrash(ctx context.Context) ([]unstructured.Unstructured, error) {
+	list, err := s.client.Resource(s.kind.Resource).List(ctx, metav1.ListOptions{
+		LabelSelector: s.kind.LabelSelector() + "," + DeletedLabel + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// PurgeTrash deletes the CRs that have been in the trash for longer than retention and returns their IDs.
+// CRs labelled as deleted by hand, without a deletion time, are kept.
+func (s *Service) PurgeTrash(ctx context.Context, retention time.Duration, now time.Time) ([]string, error) {
+	items, err := s.ListTrash(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var purged []string
+	var errs []error
+	for i := range items {
+		obj := &items[i]
+		deletedAt := DeletedAt(obj)
+		if obj.GetNamespace() != s.namespace || deletedAt.IsZero() || now.Sub(deletedAt) < retention {
+			continue
+		}
+		id := s.kind.ID(obj.GetName())
+		if err := s.Delete(ctx, id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		purged = append(purged, id)
+	}
+	return purged, errors.Join(errs...)
+}
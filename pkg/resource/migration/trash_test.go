@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTrashAndRestore(t *testing.T) {
+	s := newFakeService(Backup, newStatefulMigration(Backup, Namespace, "db"))
+	ctx := context.TODO()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	trashed, err := s.Trash(ctx, "db", "alice", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsDeleted(trashed) || !DeletedAt(trashed).Equal(now) || DeletedBy(trashed) != "alice" {
+		t.Errorf("trashed CR has labels %v and annotations %v", trashed.GetLabels(), trashed.GetAnnotations())
+	}
+	if _, found, _ := unstructured.NestedString(trashed.Object, "spec", "schedule"); found {
+		t.Error("trashed backup kept its schedule")
+	}
+
+	if _, err := s.Get(ctx, "db"); !apierrors.IsNotFound(err) {
+		t.Errorf("Get() of a trashed CR returned %v, expected not found", err)
+	}
+	if items, err := s.List(ctx); err != nil || len(items) != 0 {
+		t.Errorf("List() = %d items, %v, expected the trashed CR to be left out", len(items), err)
+	}
+	if items, err := s.ListTrash(ctx); err != nil || len(items) != 1 {
+		t.Errorf("ListTrash() = %d items, %v, expected 1", len(items), err)
+	}
+
+	restored, err := s.Restore(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsDeleted(restored) || restored.GetAnnotations()[DeletedAtAnnotation] != "" {
+		t.Errorf("restored CR has labels %v and annotations %v", restored.GetLabels(), restored.GetAnnotations())
+	}
+	if schedule, _, _ := unstructured.NestedString(restored.Object, "spec", "schedule"); schedule != "0 0 * * *" {
+		t.Errorf("restored schedule = %q, expected the one before the deletion", schedule)
+	}
+	if _, err := s.Restore(ctx, "db"); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("Restore() of an active CR returned %v, expected ErrNotInTrash", err)
+	}
+	if err := s.Purge(ctx, "db"); !errors.Is(err, ErrNotInTrash) {
+		t.Errorf("Purge() of an active CR returned %v, expected ErrNotInTrash", err)
+	}
+}
+
+func TestTrashGitOpsManaged(t *testing.T) {
+	obj := newStatefulMigration(Backup, Namespace, "db")
+	obj.SetAnnotations(map[string]string{ManagedByAnnotation: ManagedByGitOps})
+	s := newFakeService(Backup, obj)
+	if _, err := s.Trash(context.TODO(), "db", "", time.Now()); !errors.Is(err, ErrGitOpsManaged) {
+		t.Errorf("Trash() returned %v, expected ErrGitOpsManaged", err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	s := newFakeService(Backup,
+		newStatefulMigration(Backup, Namespace, "old"),
+		newStatefulMigration(Backup, Namespace, "recent"),
+		newStatefulMigration(Backup, Namespace, "active"),
+	)
+	ctx := context.TODO()
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := s.Trash(ctx, "old", "", now.Add(-8*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Trash(ctx, "recent", "", now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := s.PurgeTrash(ctx, 7*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 1 || purged[0] != "old" {
+		t.Errorf("PurgeTrash() = %v, expected [old]", purged)
+	}
+	if _, err := s.get(ctx, "old"); !apierrors.IsNotFound(err) {
+		t.Errorf("purged CR still exists: %v", err)
+	}
+	for _, id := range []string{"recent", "active"} {
+		if _, err := s.get(ctx, id); err != nil {
+			t.Errorf("CR %s was purged: %v", id, err)
+		}
+	}
+}
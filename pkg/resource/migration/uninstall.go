@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Kinds of resources that keep the migration controller of a cluster from being uninstalled
+const (
+	BlockerBackup           = "BackupConfiguration"
+	BlockerRecovery         = "Recovery"
+	BlockerCheckpointBackup = "CheckpointBackup"
+)
+
+// UninstallBlocker is a resource that depends on the migration controller of a cluster
+type UninstallBlocker struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase,omitempty"`
+	// Cascade is set on the backup configurations that a forced uninstall deletes. The other blockers
+	// are operations in flight, which fail once the controller is gone.
+	Cascade bool `json:"cascade"`
+}
+
+// UninstallBlockedError refuses the uninstall of a migration controller that is still in use
+type UninstallBlockedError struct {
+	Cluster  string
+	Blockers []UninstallBlocker
+}
+
+func (e *UninstallBlockedError) Error() string {
+	names := make([]string, 0, len(e.Blockers))
+	for _, blocker := range e.Blockers {
+		names = append(names, fmt.Sprintf("%s %s", blocker.Kind, blocker.Name))
+	}
+	return fmt.Sprintf("migration controller of cluster %s is in use by %s, uninstall with force to override",
+		e.Cluster, strings.Join(names, ", "))
+}
+
+// terminalPhases are the phases of operations that no longer need their controller
+var terminalPhases = map[string]bool{
+	"completed": true,
+	"succeeded": true,
+	"failed":    true,
+	"error":     true,
+	"cancelled": true,
+}
+
+// IsActivePhase reports whether an operation in the phase is still in flight. Operations without a phase
+// have not been picked up by their controller yet, so they are in flight too.
+func IsActivePhase(phase string) bool {
+	return !terminalPhases[strings.ToLower(phase)]
+}
+
+// SourceClusters returns the clusters a backup configuration CR backs up from
+func SourceClusters(obj *unstructured.Unstructured) []string {
+	clusters, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "sourceClusters")
+	return clusters
+}
+
+// UninstallBlockers returns the resources that use the migration controller of a cluster: the backup
+// configurations of the cluster, the recoveries in flight from or to it and its checkpoint backups in flight.
+// The controller of the management cluster serves every backup configuration and recovery.
+func UninstallBlockers(clusterName string, management bool, backups, recoveries, checkpoints []unstructured.Unstructured) []UninstallBlocker {
+	blockers := []UninstallBlocker{}
+	for i := range backups {
+		backup := &backups[i]
+		if !management && !containsCluster(SourceClusters(backup), clusterName) {
+			continue
+		}
+		blockers = append(blockers, UninstallBlocker{
+			Kind:      BlockerBackup,
+			Namespace: backup.GetNamespace(),
+			Name:      backup.GetName(),
+			Cascade:   true,
+		})
+	}
+	for i := range recoveries {
+		recovery := &recoveries[i]
+		phase, _, _ := unstructured.NestedString(recovery.Object, "status", "phase")
+		if phase == "" {
+			phase, _, _ = unstructured.NestedString(recovery.Object, "spec", "phase")
+		}
+		if !IsActivePhase(phase) {
+			continue
+		}
+		source, _, _ := unstructured.NestedString(recovery.Object, "spec", "sourceCluster")
+		target, _, _ := unstructured.NestedString(recovery.Object, "spec", "targetCluster")
+		if !management && source != clusterName && target != clusterName {
+			continue
+		}
+		blockers = append(blockers, UninstallBlocker{
+			Kind:      BlockerRecovery,
+			Namespace: recovery.GetNamespace(),
+			Name:      recovery.GetName(),
+			Phase:     phase,
+		})
+	}
+	for i := range checkpoints {
+		checkpoint := &checkpoints[i]
+		phase, _, _ := unstructured.NestedString(checkpoint.Object, "status", "phase")
+		if !IsActivePhase(phase) {
+			continue
+		}
+		blockers = append(blockers, UninstallBlocker{
+			Kind:      BlockerCheckpointBackup,
+			Namespace: checkpoint.GetNamespace(),
+			Name:      checkpoint.GetName(),
+			Phase:     phase,
+		})
+	}
+	return blockers
+}
+
+func containsCluster(clusters []string, clusterName string) bool {
+	for _, cluster := range clusters {
+		if cluster == clusterName {
+			return true
+		}
+	}
+	return false
+}
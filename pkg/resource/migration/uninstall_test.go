@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newRecovery(id, sourceCluster, targetCluster, phase string) unstructured.Unstructured {
+	obj := newStatefulMigration(Recovery, Namespace, id)
+	obj.Object["spec"] = map[string]interface{}{"sourceCluster": sourceCluster, "targetCluster": targetCluster, "phase": phase}
+	return *obj
+}
+
+func newCheckpointBackup(name, phase string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	if phase != "" {
+		obj.Object["status"] = map[string]interface{}{"phase": phase}
+	}
+	return obj
+}
+
+func TestUninstallBlockers(t *testing.T) {
+	db := newStatefulMigration(Backup, Namespace, "db")
+	db.Object["spec"] = map[string]interface{}{"sourceClusters": []interface{}{"member1"}}
+	cache := newStatefulMigration(Backup, Namespace, "cache")
+	cache.Object["spec"] = map[string]interface{}{"sourceClusters": []interface{}{"member2"}}
+	backups := []unstructured.Unstructured{*db, *cache}
+	recoveries := []unstructured.Unstructured{
+		newRecovery("r1", "member2", "member1", "running"),
+		newRecovery("r2", "member1", "member2", "completed"),
+		newRecovery("r3", "member2", "member3", "pending"),
+	}
+	checkpoints := []unstructured.Unstructured{
+		newCheckpointBackup("cb-new", ""),
+		newCheckpointBackup("cb-done", "Completed"),
+	}
+
+	names := func(blockers []UninstallBlocker) []string {
+		result := []string{}
+		for _, blocker := range blockers {
+			result = append(result, blocker.Kind+"/"+blocker.Name)
+		}
+		return result
+	}
+
+	got := names(UninstallBlockers("member1", false, backups, recoveries, checkpoints))
+	want := []string{"BackupConfiguration/backup-db", "Recovery/recovery-r1", "CheckpointBackup/cb-new"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UninstallBlockers() of a member = %v, want %v", got, want)
+	}
+
+	got = names(UninstallBlockers("mgmt-cluster", true, backups, recoveries, nil))
+	want = []string{"BackupConfiguration/backup-db", "BackupConfiguration/backup-cache", "Recovery/recovery-r1", "Recovery/recovery-r3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UninstallBlockers() of the management cluster = %v, want %v", got, want)
+	}
+
+	if blockers := UninstallBlockers("member4", false, backups, recoveries, nil); len(blockers) != 0 {
+		t.Errorf("UninstallBlockers() of an unused cluster = %v, want none", blockers)
+	}
+}
+
+func TestUninstallBlockedError(t *testing.T) {
+	err := &UninstallBlockedError{Cluster: "member1", Blockers: []UninstallBlocker{{Kind: BlockerBackup, Name: "backup-db"}}}
+	if msg := err.Error(); !strings.Contains(msg, "BackupConfiguration backup-db") || !strings.Contains(msg, "member1") {
+		t.Errorf("Error() = %q, want the cluster and blockers", msg)
+	}
+	if id := Backup.ID("backup-db"); id != "db" {
+		t.Errorf("Backup.ID() = %q, want db", id)
+	}
+}
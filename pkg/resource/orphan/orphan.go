@@ -0,0 +1,310 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package orphan finds the Karmada resources created by the dashboard whose target member clusters no
+// longer exist, e.g. after a cluster was removed or renamed without being detached, and deletes them.
+package orphan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadaclientset "github.com/karmada-io/karmada/pkg/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ManagedLabel marks the Karmada resources created by the dashboard
+	ManagedLabel = "ml-platform.io/managed"
+	// ManagedLabelValue is the value of ManagedLabel
+	ManagedLabelValue = "true"
+	// TargetClusterLabel is the member cluster a dashboard resource is created for. Resources propagated
+	// to several clusters, like propagation policies, do not have it.
+	TargetClusterLabel = "ml-platform.io/target-cluster"
+)
+
+// Actions taken on an orphaned resource
+const (
+	// ActionDelete deletes a resource none of whose target clusters exist
+	ActionDelete = "delete"
+	// ActionPrune removes the missing clusters from a policy that still targets existing ones
+	ActionPrune = "prune"
+)
+
+// ProfileGVR is the Kubeflow Profile created in Karmada for each user
+var ProfileGVR = schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "profiles"}
+
+// clusterResource is a kind of dashboard resource created in Karmada for a single member cluster
+type clusterResource struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
+// clusterResources are the kinds created for a single cluster, found by their TargetClusterLabel
+var clusterResources = []clusterResource{
+	{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, kind: "DaemonSet", namespaced: true},
+	{gvr: schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, kind: "ServiceAccount", namespaced: true},
+	{gvr: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, kind: "ConfigMap", namespaced: true},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, kind: "Role", namespaced: true},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, kind: "RoleBinding", namespaced: true},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, kind: "ClusterRole"},
+	{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, kind: "ClusterRoleBinding"},
+}
+
+// Labels returns the labels of a dashboard resource created for a member cluster,
+// or for several clusters when clusterName is empty
+func Labels(clusterName string) map[string]string {
+	labels := map[string]string{ManagedLabel: ManagedLabelValue}
+	if clusterName != "" {
+		labels[TargetClusterLabel] = clusterName
+	}
+	return labels
+}
+
+// SetLabels adds the labels of a dashboard resource to obj, keeping its other labels
+func SetLabels(obj metav1.Object, clusterName string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range Labels(clusterName) {
+		labels[key] = value
+	}
+	obj.SetLabels(labels)
+}
+
+// Item is an orphaned dashboard resource
+type Item struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// MissingClusters are the target clusters that no longer exist
+	MissingClusters []string `json:"missingClusters"`
+	Action          string   `json:"action"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// Report lists the orphaned resources and, unless DryRun, the outcome of their cleanup
+type Report struct {
+	DryRun  bool   `json:"dryRun"`
+	Items   []Item `json:"items"`
+	Deleted int    `json:"deleted"`
+	Pruned  int    `json:"pruned"`
+	Failed  int    `json:"failed"`
+}
+
+func (r *Report) add(item Item, err error) {
+	if err != nil {
+		item.Error = err.Error()
+		r.Failed++
+		klog.ErrorS(err, "Failed to clean up orphaned resource", "kind", item.Kind, "namespace", item.Namespace, "name", item.Name)
+	} else if !r.DryRun {
+		switch item.Action {
+		case ActionDelete:
+			r.Deleted++
+		case ActionPrune:
+			r.Pruned++
+		}
+		klog.InfoS("Cleaned up orphaned resource", "kind", item.Kind, "namespace", item.Namespace, "name", item.Name,
+			"action", item.Action, "missingClusters", item.MissingClusters)
+	}
+	r.Items = append(r.Items, item)
+}
+
+// missingClusters returns the names that are not existing clusters
+func missingClusters(names []string, existing map[string]bool) []string {
+	var missing []string
+	for _, name := range names {
+		if !existing[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// prunePlacement returns the action for a policy placement and drops the missing clusters from it.
+// Policies that select clusters by labels or fields rather than names are left alone.
+func prunePlacement(placement *policyv1alpha1.Placement, existing map[string]bool) (string, []string) {
+	if placement.ClusterAffinity == nil || len(placement.ClusterAffinity.ClusterNames) == 0 {
+		return "", nil
+	}
+	names := placement.ClusterAffinity.ClusterNames
+	missing := missingClusters(names, existing)
+	if len(missing) == 0 {
+		return "", nil
+	}
+	if len(missing) == len(names) {
+		return ActionDelete, missing
+	}
+	remaining := make([]string, 0, len(names)-len(missing))
+	for _, name := range names {
+		if existing[name] {
+			remaining = append(remaining, name)
+		}
+	}
+	placement.ClusterAffinity.ClusterNames = remaining
+	return ActionPrune, missing
+}
+
+// Collect finds the orphaned dashboard resources in Karmada and, unless dryRun, deletes them or
+// removes the missing clusters from the policies that still target existing clusters.
+// dynamicClient is a client of the Karmada API server.
+func Collect(ctx context.Context, karmadaClient karmadaclientset.Interface, dynamicClient dynamic.Interface, dryRun bool) (*Report, error) {
+	clusters, err := karmadaClient.ClusterV1alpha1().Clusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	existing := make(map[string]bool, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		existing[cluster.Name] = true
+	}
+
+	report := &Report{DryRun: dryRun, Items: []Item{}}
+	selector := metav1.ListOptions{LabelSelector: ManagedLabel + "=" + ManagedLabelValue}
+
+	policies, err := karmadaClient.PolicyV1alpha1().PropagationPolicies(metav1.NamespaceAll).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list propagation policies: %w", err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		action, missing := prunePlacement(&policy.Spec.Placement, existing)
+		if action == "" {
+			continue
+		}
+		item := Item{APIVersion: policyv1alpha1.SchemeGroupVersion.String(), Kind: "PropagationPolicy",
+			Namespace: policy.Namespace, Name: policy.Name, MissingClusters: missing, Action: action}
+		var err error
+		if !dryRun {
+			policyClient := karmadaClient.PolicyV1alpha1().PropagationPolicies(policy.Namespace)
+			if action == ActionDelete {
+				err = ignoreNotFound(policyClient.Delete(ctx, policy.Name, metav1.DeleteOptions{}))
+			} else {
+				_, err = policyClient.Update(ctx, policy, metav1.UpdateOptions{})
+			}
+		}
+		report.add(item, err)
+	}
+
+	// Profiles are only propagated by their cluster policy, so they are orphaned along with it
+	orphanedProfiles := map[string][]string{}
+	clusterPolicies, err := karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies().List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster propagation policies: %w", err)
+	}
+	for i := range clusterPolicies.Items {
+		policy := &clusterPolicies.Items[i]
+		action, missing := prunePlacement(&policy.Spec.Placement, existing)
+		if action == "" {
+			continue
+		}
+		item := Item{APIVersion: policyv1alpha1.SchemeGroupVersion.String(), Kind: "ClusterPropagationPolicy",
+			Name: policy.Name, MissingClusters: missing, Action: action}
+		var err error
+		if !dryRun {
+			policyClient := karmadaClient.PolicyV1alpha1().ClusterPropagationPolicies()
+			if action == ActionDelete {
+				err = ignoreNotFound(policyClient.Delete(ctx, policy.Name, metav1.DeleteOptions{}))
+			} else {
+				_, err = policyClient.Update(ctx, policy, metav1.UpdateOptions{})
+			}
+		}
+		report.add(item, err)
+		if action == ActionDelete && err == nil {
+			for _, resourceSelector := range policy.Spec.ResourceSelectors {
+				if resourceSelector.Kind == "Profile" && resourceSelector.Name != "" {
+					orphanedProfiles[resourceSelector.Name] = missing
+				}
+			}
+		}
+	}
+
+	for _, resource := range clusterResources {
+		objects, err := dynamicClient.Resource(resource.gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s,%s", ManagedLabel, ManagedLabelValue, TargetClusterLabel),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", resource.gvr.Resource, err)
+		}
+		for _, obj := range objects.Items {
+			clusterName := obj.GetLabels()[TargetClusterLabel]
+			if existing[clusterName] {
+				continue
+			}
+			item := Item{APIVersion: resource.gvr.GroupVersion().String(), Kind: resource.kind,
+				Namespace: obj.GetNamespace(), Name: obj.GetName(), MissingClusters: []string{clusterName}, Action: ActionDelete}
+			var err error
+			if !dryRun {
+				resourceClient := dynamicClient.Resource(resource.gvr)
+				if resource.namespaced {
+					err = ignoreNotFound(resourceClient.Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{}))
+				} else {
+					err = ignoreNotFound(resourceClient.Delete(ctx, obj.GetName(), metav1.DeleteOptions{}))
+				}
+			}
+			report.add(item, err)
+		}
+	}
+
+	if len(orphanedProfiles) > 0 {
+		profiles, err := dynamicClient.Resource(ProfileGVR).List(ctx, selector)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to list profiles: %w", err)
+		}
+		if profiles != nil {
+			for _, profile := range profiles.Items {
+				missing, ok := orphanedProfiles[profile.GetName()]
+				if !ok {
+					continue
+				}
+				item := Item{APIVersion: ProfileGVR.GroupVersion().String(), Kind: "Profile",
+					Name: profile.GetName(), MissingClusters: missing, Action: ActionDelete}
+				var err error
+				if !dryRun {
+					err = ignoreNotFound(dynamicClient.Resource(ProfileGVR).Delete(ctx, profile.GetName(), metav1.DeleteOptions{}))
+				}
+				report.add(item, err)
+			}
+		}
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
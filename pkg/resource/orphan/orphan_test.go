@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"testing"
+
+	clusterv1alpha1 "github.com/karmada-io/karmada/pkg/apis/cluster/v1alpha1"
+	policyv1alpha1 "github.com/karmada-io/karmada/pkg/apis/policy/v1alpha1"
+	karmadafake "github.com/karmada-io/karmada/pkg/generated/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func policy(name string, labels map[string]string, clusters ...string) *policyv1alpha1.PropagationPolicy {
+	return &policyv1alpha1.PropagationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "stateful-migration", Labels: labels},
+		Spec: policyv1alpha1.PropagationSpec{
+			Placement: policyv1alpha1.Placement{ClusterAffinity: &policyv1alpha1.ClusterAffinity{ClusterNames: clusters}},
+		},
+	}
+}
+
+func object(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func newClients(objects ...runtime.Object) (*karmadafake.Clientset, *dynamicfake.FakeDynamicClient) {
+	karmadaObjects := []runtime.Object{
+		&clusterv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "member1"}},
+		policy("checkpoint-backup-member1", Labels("member1"), "member1"),
+		policy("checkpoint-backup-removed", Labels("removed"), "removed"),
+		policy("storage", Labels(""), "member1", "removed"),
+		policy("unmanaged", nil, "removed"),
+		&policyv1alpha1.ClusterPropagationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile-alice", Labels: Labels("")},
+			Spec: policyv1alpha1.PropagationSpec{
+				ResourceSelectors: []policyv1alpha1.ResourceSelector{{APIVersion: "kubeflow.org/v1", Kind: "Profile", Name: "alice"}},
+				Placement:         policyv1alpha1.Placement{ClusterAffinity: &policyv1alpha1.ClusterAffinity{ClusterNames: []string{"removed"}}},
+			},
+		},
+	}
+	listKinds := map[schema.GroupVersionResource]string{ProfileGVR: "ProfileList"}
+	for _, resource := range clusterResources {
+		listKinds[resource.gvr] = resource.kind + "List"
+	}
+	return karmadafake.NewSimpleClientset(karmadaObjects...),
+		dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+func TestCollect(t *testing.T) {
+	dynamicObjects := []runtime.Object{
+		object("apps/v1", "DaemonSet", "stateful-migration", "checkpoint-backup-controller-removed", Labels("removed")),
+		object("apps/v1", "DaemonSet", "stateful-migration", "checkpoint-backup-controller-member1", Labels("member1")),
+		object("rbac.authorization.k8s.io/v1", "ClusterRole", "", "checkpoint-backup-role-removed", Labels("removed")),
+		object("kubeflow.org/v1", "Profile", "", "alice", Labels("")),
+		object("kubeflow.org/v1", "Profile", "", "bob", Labels("")),
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		karmadaClient, dynamicClient := newClients(dynamicObjects...)
+		report, err := Collect(context.Background(), karmadaClient, dynamicClient, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"ClusterPropagationPolicy/profile-alice delete",
+			"ClusterRole/checkpoint-backup-role-removed delete",
+			"DaemonSet/checkpoint-backup-controller-removed delete",
+			"Profile/alice delete",
+			"PropagationPolicy/checkpoint-backup-removed delete",
+			"PropagationPolicy/storage prune",
+		}
+		if len(report.Items) != len(want) {
+			t.Fatalf("got %d items %+v, want %v", len(report.Items), report.Items, want)
+		}
+		for i, item := range report.Items {
+			if got := item.Kind + "/" + item.Name + " " + item.Action; got != want[i] {
+				t.Errorf("item %d = %s, want %s", i, got, want[i])
+			}
+		}
+		if report.Deleted != 0 || report.Pruned != 0 {
+			t.Errorf("dry run changed resources: %+v", report)
+		}
+		if _, err := karmadaClient.PolicyV1alpha1().PropagationPolicies("stateful-migration").Get(context.Background(), "checkpoint-backup-removed", metav1.GetOptions{}); err != nil {
+			t.Errorf("dry run deleted the policy: %v", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		karmadaClient, dynamicClient := newClients(dynamicObjects...)
+		report, err := Collect(context.Background(), karmadaClient, dynamicClient, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Deleted != 5 || report.Pruned != 1 || report.Failed != 0 {
+			t.Errorf("deleted %d pruned %d failed %d, want 5 1 0", report.Deleted, report.Pruned, report.Failed)
+		}
+		policies := karmadaClient.PolicyV1alpha1().PropagationPolicies("stateful-migration")
+		if _, err := policies.Get(context.Background(), "checkpoint-backup-removed", metav1.GetOptions{}); err == nil {
+			t.Error("expected the orphaned policy to be deleted")
+		}
+		if _, err := policies.Get(context.Background(), "unmanaged", metav1.GetOptions{}); err != nil {
+			t.Errorf("unmanaged policy was deleted: %v", err)
+		}
+		storage, err := policies.Get(context.Background(), "storage", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if names := storage.Spec.Placement.ClusterAffinity.ClusterNames; len(names) != 1 || names[0] != "member1" {
+			t.Errorf("pruned clusters = %v, want [member1]", names)
+		}
+		profiles, err := dynamicClient.Resource(ProfileGVR).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(profiles.Items) != 1 || profiles.Items[0].GetName() != "bob" {
+			t.Errorf("remaining profiles = %v, want [bob]", profiles.Items)
+		}
+	})
+}
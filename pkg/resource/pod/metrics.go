@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ErrMetricsUnavailable is returned when the metrics API of a cluster has no metrics for a pod, because
+// metrics-server is not installed or has not scraped the pod yet
+var ErrMetricsUnavailable = errors.New("pod metrics are not available")
+
+var podMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// Usage is the CPU and memory usage of a pod or container
+type Usage struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+}
+
+// ContainerMetrics is the usage of a container of a pod
+type ContainerMetrics struct {
+	Name string `json:"name"`
+	Usage
+}
+
+// PodMetrics is the usage of a pod as last measured by metrics-server, summed over its containers
+type PodMetrics struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Timestamp is when the usage was measured, averaged over the window
+	Timestamp  string             `json:"timestamp"`
+	Window     string             `json:"window"`
+	Containers []ContainerMetrics `json:"containers"`
+	Usage
+}
+
+// GetPodMetrics returns the usage of a pod from the metrics API of its cluster
+func GetPodMetrics(ctx context.Context, dynamicClient dynamic.Interface, namespace, name string) (*PodMetrics, error) {
+	obj, err := dynamicClient.Resource(podMetricsResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w for pod %s/%s", ErrMetricsUnavailable, namespace, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	metrics := podMetricsFromObject(obj)
+	return &metrics, nil
+}
+
+// ListPodMetrics returns the usage of the pods of a namespace, all namespaces when empty, by namespace/name
+func ListPodMetrics(ctx context.Context, dynamicClient dynamic.Interface, namespace string) (map[string]PodMetrics, error) {
+	list, err := dynamicClient.Resource(podMetricsResource).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrMetricsUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	metrics := make(map[string]PodMetrics, len(list.Items))
+	for i := range list.Items {
+		m := podMetricsFromObject(&list.Items[i])
+		metrics[m.Namespace+"/"+m.Name] = m
+	}
+	return metrics, nil
+}
+
+func podMetricsFromObject(obj *unstructured.Unstructured) PodMetrics {
+	metrics := PodMetrics{
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Containers: []ContainerMetrics{},
+	}
+	metrics.Timestamp, _, _ = unstructured.NestedString(obj.Object, "timestamp")
+	metrics.Window, _, _ = unstructured.NestedString(obj.Object, "window")
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "containers")
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := container["name"].(string)
+		usage, _ := container["usage"].(map[string]interface{})
+		containerMetrics := ContainerMetrics{Name: name, Usage: parseUsage(usage)}
+		metrics.CPUMillicores += containerMetrics.CPUMillicores
+		metrics.MemoryBytes += containerMetrics.MemoryBytes
+		metrics.Containers = append(metrics.Containers, containerMetrics)
+	}
+	return metrics
+}
+
+// parseUsage reads the cpu and memory quantities of a usage, ignoring the ones that do not parse
+func parseUsage(usage map[string]interface{}) Usage {
+	var u Usage
+	if cpu, ok := usage["cpu"].(string); ok {
+		if q, err := resource.ParseQuantity(cpu); err == nil {
+			u.CPUMillicores = q.MilliValue()
+		}
+	}
+	if memory, ok := usage["memory"].(string); ok {
+		if q, err := resource.ParseQuantity(memory); err == nil {
+			u.MemoryBytes = q.Value()
+		}
+	}
+	return u
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestGetPodMetrics(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podMetricsResource: "PodMetricsList"})
+	metricsObject := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata":   map[string]interface{}{"name": "db-0", "namespace": "team-a"},
+		"timestamp":  "2024-05-01T10:00:00Z",
+		"window":     "15s",
+		"containers": []interface{}{
+			map[string]interface{}{"name": "db", "usage": map[string]interface{}{"cpu": "250m", "memory": "512Mi"}},
+			map[string]interface{}{"name": "istio-proxy", "usage": map[string]interface{}{"cpu": "12345678n", "memory": "64Mi"}},
+		},
+	}}
+	ctx := context.TODO()
+	if _, err := dynamicClient.Resource(podMetricsResource).Namespace("team-a").Create(ctx, metricsObject, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics, err := GetPodMetrics(ctx, dynamicClient, "team-a", "db-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics.CPUMillicores != 263 || metrics.MemoryBytes != 576<<20 {
+		t.Errorf("usage = %+v, want 263m and 576Mi", metrics.Usage)
+	}
+	if len(metrics.Containers) != 2 || metrics.Containers[0].Name != "db" || metrics.Containers[0].CPUMillicores != 250 {
+		t.Errorf("containers = %+v", metrics.Containers)
+	}
+	if metrics.Window != "15s" {
+		t.Errorf("window = %q, want 15s", metrics.Window)
+	}
+
+	if _, err := GetPodMetrics(ctx, dynamicClient, "team-a", "missing"); !errors.Is(err, ErrMetricsUnavailable) {
+		t.Errorf("GetPodMetrics() of a pod without metrics error = %v, want ErrMetricsUnavailable", err)
+	}
+	listed, err := ListPodMetrics(ctx, dynamicClient, "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := listed["team-a/db-0"]; !ok || len(listed) != 1 {
+		t.Errorf("ListPodMetrics() = %v, want the metrics of team-a/db-0", listed)
+	}
+}
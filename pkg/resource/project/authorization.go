@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/auth/fga"
+)
+
+// Tuple is a relation of the authorization model written for a project
+type Tuple struct {
+	User       string
+	Relation   string
+	ObjectType string
+	ObjectID   string
+}
+
+// Tuples returns the relations that grant the project roles to its members and relate its
+// clusters to it. Namespaces are not part of the authorization model, so only clusters owned
+// as a whole are related and inherit the project roles.
+func Tuples(p *Project) []Tuple {
+	if p == nil {
+		return nil
+	}
+	tuples := make([]Tuple, 0, len(p.Members)+len(p.Clusters))
+	for _, member := range p.Members {
+		tuples = append(tuples, Tuple{User: member.Username, Relation: member.Role, ObjectType: "project", ObjectID: p.Name})
+	}
+	for _, cluster := range p.Clusters {
+		tuples = append(tuples, Tuple{User: fga.ProjectSubject(p.Name), Relation: "project", ObjectType: "cluster", ObjectID: cluster})
+	}
+	return tuples
+}
+
+// DiffTuples returns the tuples to write and to delete to go from the old to the new project.
+// Either project may be nil for a create or a delete.
+func DiffTuples(old, updated *Project) (writes, deletes []Tuple) {
+	oldTuples := map[Tuple]bool{}
+	for _, t := range Tuples(old) {
+		oldTuples[t] = true
+	}
+	newTuples := map[Tuple]bool{}
+	for _, t := range Tuples(updated) {
+		newTuples[t] = true
+		if !oldTuples[t] {
+			writes = append(writes, t)
+		}
+	}
+	for _, t := range Tuples(old) {
+		if !newTuples[t] {
+			deletes = append(deletes, t)
+		}
+	}
+	return writes, deletes
+}
+
+// SyncTuples writes and deletes the tuples of a project change in OpenFGA.
+// It does nothing when OpenFGA is not initialized.
+func SyncTuples(ctx context.Context, old, updated *Project) error {
+	if fga.FGAService == nil || fga.FGAService.GetClient() == nil {
+		klog.V(4).InfoS("OpenFGA service not initialized, skipping project tuples")
+		return nil
+	}
+	fgaClient := fga.FGAService.GetClient()
+	writes, deletes := DiffTuples(old, updated)
+	var failed int
+	for _, t := range deletes {
+		if err := fgaClient.DeleteTuple(ctx, t.User, t.Relation, t.ObjectType, t.ObjectID); err != nil {
+			klog.ErrorS(err, "Failed to delete project tuple", "user", t.User, "relation", t.Relation, "object", t.ObjectType+":"+t.ObjectID)
+			failed++
+		}
+	}
+	for _, t := range writes {
+		if err := fgaClient.WriteTuple(ctx, t.User, t.Relation, t.ObjectType, t.ObjectID); err != nil {
+			klog.ErrorS(err, "Failed to write project tuple", "user", t.User, "relation", t.Relation, "object", t.ObjectType+":"+t.ObjectID)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to update %d of %d authorization tuples", failed, len(writes)+len(deletes))
+	}
+	return nil
+}
+
+// CanView reports whether the user is an admin or a member of the project.
+// Everyone can view projects when OpenFGA is not initialized, as everyone can view all clusters then.
+func CanView(ctx context.Context, username, name string) (bool, error) {
+	return hasRole(ctx, username, RoleMember, name)
+}
+
+// CanManage reports whether the user is an admin or an owner of the project
+func CanManage(ctx context.Context, username, name string) (bool, error) {
+	return hasRole(ctx, username, RoleOwner, name)
+}
+
+// IsAdmin reports whether the user is a dashboard admin, who manages all projects
+func IsAdmin(ctx context.Context, username string) (bool, error) {
+	if fga.FGAService == nil || fga.FGAService.GetClient() == nil {
+		return true, nil
+	}
+	return fga.FGAService.GetClient().Check(ctx, username, "admin", "dashboard", "dashboard")
+}
+
+func hasRole(ctx context.Context, username, relation, name string) (bool, error) {
+	isAdmin, err := IsAdmin(ctx, username)
+	if err != nil || isAdmin {
+		return isAdmin, err
+	}
+	return fga.FGAService.GetClient().Check(ctx, username, relation, "project", name)
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/common/errors"
+	"github.com/karmada-io/dashboard/pkg/config"
+)
+
+const (
+	// LabelKey marks the ConfigMaps that store projects
+	LabelKey = "ml-platform.io/project"
+	dataKey  = "project"
+)
+
+// Project roles, which are also the relations of the project in the authorization model
+const (
+	RoleOwner  = "owner"
+	RoleMember = "member"
+)
+
+// Namespace is a namespace of a member cluster owned by a project
+type Namespace struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+}
+
+// Member is a user of a project and the role they have in it
+type Member struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// Application is an ArgoCD application of a member cluster owned by a project
+type Application struct {
+	Cluster string `json:"cluster"`
+	Name    string `json:"name"`
+}
+
+// Project groups member clusters or namespaces, the users working on them, and the backup
+// configurations and ArgoCD applications that belong to them.
+type Project struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Clusters are owned as a whole, their users inherit the project roles on them
+	Clusters []string `json:"clusters"`
+	// Namespaces are owned without the rest of their cluster
+	Namespaces []Namespace `json:"namespaces"`
+	Members    []Member    `json:"members"`
+	// Backups are the IDs of backup configurations of workloads outside the project namespaces
+	Backups      []string      `json:"backups"`
+	Applications []Application `json:"applications"`
+	CreatedAt    string        `json:"createdAt,omitempty"`
+}
+
+// Validate checks the name, roles and references of a project and fills empty lists
+func Validate(p *Project) error {
+	var errs []string
+	for _, msg := range validation.IsDNS1123Label(p.Name) {
+		errs = append(errs, fmt.Sprintf("name %q: %s", p.Name, msg))
+	}
+	seen := map[string]bool{}
+	for _, cluster := range p.Clusters {
+		if cluster == "" || seen["cluster/"+cluster] {
+			errs = append(errs, fmt.Sprintf("cluster %q is empty or duplicated", cluster))
+		}
+		seen["cluster/"+cluster] = true
+	}
+	for _, ns := range p.Namespaces {
+		key := "namespace/" + ns.Cluster + "/" + ns.Namespace
+		if ns.Cluster == "" || ns.Namespace == "" || seen[key] {
+			errs = append(errs, fmt.Sprintf("namespace %s/%s is incomplete or duplicated", ns.Cluster, ns.Namespace))
+		}
+		seen[key] = true
+	}
+	for _, member := range p.Members {
+		if member.Username == "" || seen["member/"+member.Username] {
+			errs = append(errs, fmt.Sprintf("member %q is empty or duplicated", member.Username))
+		}
+		if member.Role != RoleOwner && member.Role != RoleMember {
+			errs = append(errs, fmt.Sprintf("member %q has role %q, expected %s or %s", member.Username, member.Role, RoleOwner, RoleMember))
+		}
+		seen["member/"+member.Username] = true
+	}
+	for _, app := range p.Applications {
+		if app.Cluster == "" || app.Name == "" {
+			errs = append(errs, fmt.Sprintf("application %s/%s is incomplete", app.Cluster, app.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid project: %s", strings.Join(errs, "; "))
+	}
+
+	if p.Clusters == nil {
+		p.Clusters = []string{}
+	}
+	if p.Namespaces == nil {
+		p.Namespaces = []Namespace{}
+	}
+	if p.Members == nil {
+		p.Members = []Member{}
+	}
+	if p.Backups == nil {
+		p.Backups = []string{}
+	}
+	if p.Applications == nil {
+		p.Applications = []Application{}
+	}
+	return nil
+}
+
+// ClusterNames returns the clusters the project owns entirely or has namespaces in
+func (p *Project) ClusterNames() []string {
+	set := map[string]bool{}
+	for _, cluster := range p.Clusters {
+		set[cluster] = true
+	}
+	for _, ns := range p.Namespaces {
+		set[ns.Cluster] = true
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasCluster reports whether the project owns the cluster or one of its namespaces
+func (p *Project) HasCluster(cluster string) bool {
+	for _, name := range p.ClusterNames() {
+		if name == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsCluster reports whether the project owns the whole cluster
+func (p *Project) ownsCluster(cluster string) bool {
+	for _, name := range p.Clusters {
+		if name == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNamespace reports whether the namespace belongs to the project, directly or through its cluster
+func (p *Project) HasNamespace(cluster, namespace string) bool {
+	if p.ownsCluster(cluster) {
+		return true
+	}
+	for _, ns := range p.Namespaces {
+		if ns.Cluster == cluster && ns.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBackup reports whether a backup configuration belongs to the project, because it was added to it
+// or because it backs up a workload in a project namespace
+func (p *Project) HasBackup(id, cluster, namespace string) bool {
+	for _, backupID := range p.Backups {
+		if backupID == id {
+			return true
+		}
+	}
+	return p.HasNamespace(cluster, namespace)
+}
+
+// HasApplication reports whether an ArgoCD application belongs to the project, because it was added
+// to it or because the project owns its cluster
+func (p *Project) HasApplication(cluster, name string) bool {
+	for _, app := range p.Applications {
+		if app.Cluster == cluster && app.Name == name {
+			return true
+		}
+	}
+	return p.ownsCluster(cluster)
+}
+
+// RoleOf returns the role of the user in the project, or an empty string
+func (p *Project) RoleOf(username string) string {
+	for _, member := range p.Members {
+		if member.Username == username {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// configMapName returns the name of the ConfigMap that stores a project
+func configMapName(name string) string {
+	return fmt.Sprintf("project-%s", name)
+}
+
+func configMapToProject(cm *corev1.ConfigMap) (*Project, error) {
+	p := &Project{}
+	if err := json.Unmarshal([]byte(cm.Data[dataKey]), p); err != nil {
+		return nil, fmt.Errorf("failed to decode project %s: %w", cm.Name, err)
+	}
+	p.CreatedAt = cm.CreationTimestamp.Format("2006-01-02 15:04:05")
+	return p, nil
+}
+
+// List returns all projects sorted by name
+func List(ctx context.Context, kubeClient kubernetes.Interface) ([]*Project, error) {
+	list, err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: LabelKey + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]*Project, 0, len(list.Items))
+	for i := range list.Items {
+		p, err := configMapToProject(&list.Items[i])
+		if err != nil {
+			klog.ErrorS(err, "Skipping invalid project", "configMap", list.Items[i].Name)
+			continue
+		}
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects, nil
+}
+
+// Get returns the project with the given name
+func Get(ctx context.Context, kubeClient kubernetes.Interface, name string) (*Project, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Get(ctx, configMapName(name), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, errors.NewNotFound(fmt.Sprintf("Project %s not found", name))
+		}
+		return nil, err
+	}
+	return configMapToProject(cm)
+}
+
+// Create stores a new project
+func Create(ctx context.Context, kubeClient kubernetes.Interface, p *Project) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName(p.Name),
+			Namespace: config.GetNamespace(),
+			Labels:    map[string]string{LabelKey: "true"},
+		},
+		Data: map[string]string{dataKey: string(data)},
+	}
+	created, err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Create(ctx, cm, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	p.CreatedAt = created.CreationTimestamp.Format("2006-01-02 15:04:05")
+	return nil
+}
+
+// Update replaces a stored project
+func Update(ctx context.Context, kubeClient kubernetes.Interface, p *Project) error {
+	configMaps := kubeClient.CoreV1().ConfigMaps(config.GetNamespace())
+	cm, err := configMaps.Get(ctx, configMapName(p.Name), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[dataKey] = string(data)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// Delete removes a stored project
+func Delete(ctx context.Context, kubeClient kubernetes.Interface, name string) error {
+	err := kubeClient.CoreV1().ConfigMaps(config.GetNamespace()).Delete(ctx, configMapName(name), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return errors.NewNotFound(fmt.Sprintf("Project %s not found", name))
+	}
+	return err
+}
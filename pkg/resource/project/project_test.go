@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		project Project
+		wantErr bool
+	}{
+		{
+			name:    "minimal",
+			project: Project{Name: "team-a"},
+		},
+		{
+			name: "complete",
+			project: Project{
+				Name:         "team-a",
+				Clusters:     []string{"member1"},
+				Namespaces:   []Namespace{{Cluster: "member2", Namespace: "training"}},
+				Members:      []Member{{Username: "alice", Role: RoleOwner}, {Username: "bob", Role: RoleMember}},
+				Applications: []Application{{Cluster: "member2", Name: "app"}},
+			},
+		},
+		{
+			name:    "invalid name",
+			project: Project{Name: "Team A"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated cluster",
+			project: Project{Name: "team-a", Clusters: []string{"member1", "member1"}},
+			wantErr: true,
+		},
+		{
+			name:    "incomplete namespace",
+			project: Project{Name: "team-a", Namespaces: []Namespace{{Cluster: "member1"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			project: Project{Name: "team-a", Members: []Member{{Username: "alice", Role: "admin"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.project)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (tt.project.Clusters == nil || tt.project.Members == nil || tt.project.Backups == nil) {
+				t.Errorf("Validate() left empty lists nil")
+			}
+		})
+	}
+}
+
+func TestMembership(t *testing.T) {
+	p := &Project{
+		Name:         "team-a",
+		Clusters:     []string{"member1"},
+		Namespaces:   []Namespace{{Cluster: "member2", Namespace: "training"}},
+		Backups:      []string{"redis-1700000000"},
+		Applications: []Application{{Cluster: "member2", Name: "app"}},
+	}
+
+	if got := p.ClusterNames(); !reflect.DeepEqual(got, []string{"member1", "member2"}) {
+		t.Errorf("ClusterNames() = %v", got)
+	}
+	if !p.HasCluster("member2") || p.HasCluster("member3") {
+		t.Errorf("HasCluster() does not match the clusters and namespaces")
+	}
+	if !p.HasNamespace("member1", "any") || !p.HasNamespace("member2", "training") || p.HasNamespace("member2", "default") {
+		t.Errorf("HasNamespace() does not match the owned clusters and namespaces")
+	}
+	if !p.HasBackup("redis-1700000000", "member3", "default") || !p.HasBackup("other", "member2", "training") || p.HasBackup("other", "member2", "default") {
+		t.Errorf("HasBackup() does not match the backups and namespaces")
+	}
+	if !p.HasApplication("member2", "app") || !p.HasApplication("member1", "any") || p.HasApplication("member2", "other") {
+		t.Errorf("HasApplication() does not match the applications and owned clusters")
+	}
+}
+
+func TestDiffTuples(t *testing.T) {
+	old := &Project{
+		Name:     "team-a",
+		Clusters: []string{"member1"},
+		Members:  []Member{{Username: "alice", Role: RoleOwner}, {Username: "bob", Role: RoleMember}},
+	}
+	updated := &Project{
+		Name:       "team-a",
+		Clusters:   []string{"member2"},
+		Namespaces: []Namespace{{Cluster: "member3", Namespace: "training"}},
+		Members:    []Member{{Username: "alice", Role: RoleOwner}, {Username: "bob", Role: RoleOwner}},
+	}
+
+	writes, deletes := DiffTuples(old, updated)
+	wantWrites := []Tuple{
+		{User: "bob", Relation: RoleOwner, ObjectType: "project", ObjectID: "team-a"},
+		{User: "project:team-a", Relation: "project", ObjectType: "cluster", ObjectID: "member2"},
+	}
+	wantDeletes := []Tuple{
+		{User: "bob", Relation: RoleMember, ObjectType: "project", ObjectID: "team-a"},
+		{User: "project:team-a", Relation: "project", ObjectType: "cluster", ObjectID: "member1"},
+	}
+	if !reflect.DeepEqual(writes, wantWrites) {
+		t.Errorf("writes = %v, want %v", writes, wantWrites)
+	}
+	if !reflect.DeepEqual(deletes, wantDeletes) {
+		t.Errorf("deletes = %v, want %v", deletes, wantDeletes)
+	}
+
+	if writes, deletes := DiffTuples(nil, old); len(writes) != 3 || len(deletes) != 0 {
+		t.Errorf("DiffTuples(nil, project) = %v, %v", writes, deletes)
+	}
+	if writes, deletes := DiffTuples(old, nil); len(writes) != 0 || len(deletes) != 3 {
+		t.Errorf("DiffTuples(project, nil) = %v, %v", writes, deletes)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package search keeps an in-memory index of the resources the dashboard manages across clusters and modules,
+// such as clusters, backups and ArgoCD applications, for the global search. The index is filled per source
+// by a background indexer, so a search never reaches the clusters.
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Type is the type of an indexed resource
+type Type string
+
+// Types of indexed resources
+const (
+	TypeCluster     Type = "cluster"
+	TypeNamespace   Type = "namespace"
+	TypeBackup      Type = "backup"
+	TypeRecovery    Type = "recovery"
+	TypeApplication Type = "application"
+	TypeUser        Type = "user"
+)
+
+// DefaultLimit is the number of results returned when a query sets no limit
+const DefaultLimit = 50
+
+// Link locates a resource in the dashboard
+type Link struct {
+	// Module is the part of the dashboard the resource is shown in
+	Module string `json:"module"`
+	// API is the path of the API returning the resource
+	API string `json:"api"`
+	// Params identify the resource within its module
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Document is an indexed resource
+type Document struct {
+	Type        Type   `json:"type"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Cluster     string `json:"cluster,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Keywords are matched besides the name, like the labels of a cluster or the workload of a backup
+	Keywords []string `json:"-"`
+	Link     Link     `json:"link"`
+}
+
+// Result is a document matching a query, a higher score is a better match
+type Result struct {
+	Document
+	Score int `json:"score"`
+}
+
+// Query selects documents
+type Query struct {
+	// Text is matched word by word against the documents, every word must match
+	Text string
+	// Types restricts the results to these types, all types when empty
+	Types []Type
+	// Limit is the maximum number of results, DefaultLimit when not positive
+	Limit int
+	// Allow filters the documents the user may see, all documents when nil
+	Allow func(Document) bool
+}
+
+// SourceStatus is the state of a source of the index
+type SourceStatus struct {
+	Source    string `json:"source"`
+	Documents int    `json:"documents"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type source struct {
+	docs      []Document
+	updatedAt time.Time
+}
+
+// Index holds the documents of each source
+type Index struct {
+	mu      sync.RWMutex
+	sources map[string]*source
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{sources: map[string]*source{}}
+}
+
+// Replace sets the documents of a source, replacing the ones indexed before
+func (i *Index) Replace(name string, docs []Document, now time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.sources[name] = &source{docs: docs, updatedAt: now}
+}
+
+// Ready reports whether any source was indexed
+func (i *Index) Ready() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.sources) > 0
+}
+
+// Status returns the sources of the index, sorted by name
+func (i *Index) Status() []SourceStatus {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	status := make([]SourceStatus, 0, len(i.sources))
+	for name, s := range i.sources {
+		status = append(status, SourceStatus{Source: name, Documents: len(s.docs), UpdatedAt: s.updatedAt.Format(time.RFC3339)})
+	}
+	sort.Slice(status, func(a, b int) bool { return status[a].Source < status[b].Source })
+	return status
+}
+
+// Search returns the documents matching a query, best matches first
+func (i *Index) Search(q Query) []Result {
+	words := strings.Fields(strings.ToLower(q.Text))
+	if len(words) == 0 {
+		return []Result{}
+	}
+	types := map[Type]bool{}
+	for _, t := range q.Types {
+		types[t] = true
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	i.mu.RLock()
+	var results []Result
+	for _, s := range i.sources {
+		for _, doc := range s.docs {
+			if len(types) > 0 && !types[doc.Type] {
+				continue
+			}
+			if score := match(doc, words); score > 0 {
+				results = append(results, Result{Document: doc, Score: score})
+			}
+		}
+	}
+	i.mu.RUnlock()
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		if results[a].Type != results[b].Type {
+			return results[a].Type < results[b].Type
+		}
+		if results[a].Name != results[b].Name {
+			return results[a].Name < results[b].Name
+		}
+		return results[a].Cluster < results[b].Cluster
+	})
+
+	// The access of the user is checked last, on the results that can still be returned
+	filtered := make([]Result, 0, limit)
+	for _, result := range results {
+		if len(filtered) == limit {
+			break
+		}
+		if q.Allow == nil || q.Allow(result.Document) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// match scores how well a document matches the words of a query, 0 when a word does not match.
+// A word matches the name best, then the cluster and namespace, then the keywords and description.
+func match(doc Document, words []string) int {
+	name := strings.ToLower(doc.Name)
+	score := 0
+	for _, word := range words {
+		switch {
+		case name == word:
+			score += 100
+		case strings.HasPrefix(name, word):
+			score += 60
+		case strings.Contains(name, word):
+			score += 40
+		case strings.Contains(strings.ToLower(doc.Cluster), word) || strings.Contains(strings.ToLower(doc.Namespace), word):
+			score += 20
+		case containsAny(doc.Keywords, word) || strings.Contains(strings.ToLower(doc.Description), word):
+			score += 10
+		default:
+			return 0
+		}
+	}
+	return score
+}
+
+func containsAny(values []string, word string) bool {
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestIndex() *Index {
+	index := NewIndex()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	index.Replace("clusters", []Document{
+		{Type: TypeCluster, ID: "gpu-east", Name: "gpu-east", Keywords: []string{"region=us-east"}},
+		{Type: TypeCluster, ID: "cpu-west", Name: "cpu-west", Keywords: []string{"region=us-west"}},
+	}, now)
+	index.Replace("backups", []Document{
+		{Type: TypeBackup, ID: "db", Name: "db-nightly", Cluster: "gpu-east", Namespace: "prod", Keywords: []string{"postgres"}},
+		{Type: TypeBackup, ID: "gpu", Name: "gpu", Cluster: "cpu-west", Namespace: "ml"},
+	}, now)
+	return index
+}
+
+func names(results []Result) []string {
+	var names []string
+	for _, result := range results {
+		names = append(names, string(result.Type)+"/"+result.Name)
+	}
+	return names
+}
+
+func TestSearch(t *testing.T) {
+	index := newTestIndex()
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{
+			name:     "exact name first, then prefix, then cluster",
+			query:    Query{Text: "gpu"},
+			expected: []string{"backup/gpu", "cluster/gpu-east", "backup/db-nightly"},
+		},
+		{
+			name:     "every word must match",
+			query:    Query{Text: "DB prod"},
+			expected: []string{"backup/db-nightly"},
+		},
+		{
+			name:     "keywords",
+			query:    Query{Text: "us-west"},
+			expected: []string{"cluster/cpu-west"},
+		},
+		{
+			name:     "types",
+			query:    Query{Text: "gpu", Types: []Type{TypeCluster}},
+			expected: []string{"cluster/gpu-east"},
+		},
+		{
+			name:     "allow",
+			query:    Query{Text: "gpu", Allow: func(doc Document) bool { return doc.Cluster != "gpu-east" }},
+			expected: []string{"backup/gpu", "cluster/gpu-east"},
+		},
+		{
+			name:     "limit",
+			query:    Query{Text: "gpu", Limit: 1},
+			expected: []string{"backup/gpu"},
+		},
+		{
+			name:  "empty query",
+			query: Query{Text: "  "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(index.Search(tt.query))
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Search() = %v, expected %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("Search() = %v, expected %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	index := newTestIndex()
+	index.Replace("backups", nil, time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC))
+	if got := names(index.Search(Query{Text: "db"})); len(got) != 0 {
+		t.Errorf("Search() after replacing the backups = %v", got)
+	}
+	status := index.Status()
+	if len(status) != 2 || status[0].Source != "backups" || status[0].Documents != 0 || status[1].Documents != 2 {
+		t.Errorf("Status() = %+v", status)
+	}
+}
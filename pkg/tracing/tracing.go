@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing exports OpenTelemetry spans of the API requests and of the Kubernetes API calls made for
+// them, so the latency of a request can be broken down per cluster.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ServiceName is the service of the exported spans
+	ServiceName = "ml-platform-admin-api"
+	// ClusterKey is the attribute of the cluster a span is about
+	ClusterKey = attribute.Key("k8s.cluster.name")
+	// ManagementCluster and KarmadaControlPlane are the clusters of the calls to the cluster the dashboard
+	// runs in and to the Karmada API server
+	ManagementCluster   = "mgmt-cluster"
+	KarmadaControlPlane = "karmada"
+
+	instrumentationName = "github.com/karmada-io/dashboard"
+)
+
+var enabled bool
+
+// Config configures the export of spans
+type Config struct {
+	// Endpoint is the URL of an OTLP/HTTP collector, e.g. http://tempo.monitoring:4318. Tracing is disabled
+	// when it is empty.
+	Endpoint string
+	// SampleRatio is the share of the requests that are traced, requests of a traced caller always are
+	SampleRatio float64
+	Version     string
+}
+
+// Init installs the tracer provider exporting to the endpoint of the config. The returned function flushes
+// the pending spans and must be called on shutdown.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		klog.InfoS("Tracing is disabled, no OTLP endpoint is configured")
+		return func(context.Context) error { return nil }, nil
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing sample ratio %v is not between 0 and 1", cfg.SampleRatio)
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(cfg.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled = true
+	klog.InfoS("Tracing enabled", "endpoint", cfg.Endpoint, "sampleRatio", cfg.SampleRatio)
+	return provider.Shutdown, nil
+}
+
+// Enabled reports whether spans are exported
+func Enabled() bool {
+	return enabled
+}
+
+// Tracer returns the tracer of the dashboard spans
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Transport returns a transport.WrapperFunc for rest.Config.Wrap that records a client span for each call
+// to the API server of a cluster. The transport is unchanged when tracing is disabled.
+func Transport(cluster string) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		if !enabled {
+			return rt
+		}
+		return otelhttp.NewTransport(rt,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + cluster
+			}),
+			otelhttp.WithSpanOptions(trace.WithAttributes(ClusterKey.String(cluster))),
+		)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestInitDisabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if Enabled() {
+		t.Errorf("Enabled() = true without an endpoint")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+	if rt := Transport("member1")(http.DefaultTransport); rt != http.DefaultTransport {
+		t.Errorf("Transport() wrapped the transport with tracing disabled")
+	}
+}
+
+func TestInitInvalidSampleRatio(t *testing.T) {
+	for _, ratio := range []float64{-0.1, 1.5} {
+		if _, err := Init(context.Background(), Config{Endpoint: "http://localhost:4318", SampleRatio: ratio}); err == nil {
+			t.Errorf("Init() with sample ratio %v succeeded", ratio)
+		}
+	}
+	if Enabled() {
+		t.Errorf("Enabled() = true after failed Init()")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	}
+	for _, spec := range cases {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, expected an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC) // a Friday
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2024, time.March, 16, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 8 * * mon", time.Date(2024, time.March, 18, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * 7", time.Date(2024, time.March, 17, 8, 0, 0, 0, time.UTC)},
+		{"30 9 1-5 jan,jul *", time.Date(2024, time.July, 1, 9, 30, 0, 0, time.UTC)},
+		// Restricted day of month and day of week match either
+		{"0 0 1 * sat", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.spec)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", c.spec, err)
+			continue
+		}
+		if next := s.Next(from); !next.Equal(c.expected) {
+			t.Errorf("Next(%q) == %v, expected %v", c.spec, next, c.expected)
+		}
+	}
+}
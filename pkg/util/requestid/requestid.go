@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requestid correlates the log entries of a request with the error its user reports. The ID of a
+// request is returned in the X-Request-ID header, added to the log entries written with Logger and sent
+// with the Kubernetes API requests made for it.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Header carries the ID of a request in requests and responses
+	Header = "X-Request-ID"
+	// Key is the key of the ID in log entries and in the keys of a gin context
+	Key = "requestID"
+	// maxLength bounds the IDs accepted from clients
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New returns a random request ID
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether an ID set by a client can be used, it is kept in logs and headers as is
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a context carrying the request ID, with a logger that adds it to the log entries
+func NewContext(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, contextKey{}, id)
+	return klog.NewContext(ctx, klog.FromContext(ctx).WithValues(Key, id))
+}
+
+// FromContext returns the request ID of a context. A gin context resolves the ID from its keys, so
+// handlers may pass the gin context itself.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	if id, ok := ctx.Value(Key).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// Logger returns a logger adding the request ID of a context to its entries
+func Logger(ctx context.Context) klog.Logger {
+	logger := klog.Background()
+	if id := FromContext(ctx); id != "" {
+		logger = logger.WithValues(Key, id)
+	}
+	return logger
+}
+
+// Transport sets the request ID of the context of outgoing requests in their X-Request-ID header.
+// It is a transport.WrapperFunc for rest.Config.Wrap.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return &roundTripper{next: rt}
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return t.next.RoundTrip(req)
+	}
+	// A round tripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return t.next.RoundTrip(req)
+}
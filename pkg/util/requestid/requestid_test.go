@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	a, b := New(), New()
+	if !Valid(a) || !Valid(b) {
+		t.Fatalf("New() returned invalid IDs %q, %q", a, b)
+	}
+	if a == b {
+		t.Errorf("New() returned %q twice", a)
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"3f2a9c1e-7b4d-4e8f-9a0b-1c2d3e4f5a6b", true},
+		{"trace.id_1:2", true},
+		{"", false},
+		{"with space", false},
+		{"line\nbreak", false},
+		{"quote\"", false},
+		{strings.Repeat("a", maxLength), true},
+		{strings.Repeat("a", maxLength+1), false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.id); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("FromContext() without ID = %q", got)
+	}
+	ctx := NewContext(context.Background(), "abc")
+	if got := FromContext(ctx); got != "abc" {
+		t.Errorf("FromContext() = %q, want abc", got)
+	}
+	derived, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if got := FromContext(derived); got != "abc" {
+		t.Errorf("FromContext() of derived context = %q, want abc", got)
+	}
+}
+
+type recordingTransport struct {
+	header string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.header = req.Header.Get(Header)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		header string
+		want   string
+	}{
+		{name: "request ID of context", ctx: NewContext(context.Background(), "abc"), want: "abc"},
+		{name: "no request ID", ctx: context.Background(), want: ""},
+		{name: "header already set", ctx: NewContext(context.Background(), "abc"), header: "xyz", want: "xyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &recordingTransport{}
+			req, _ := http.NewRequestWithContext(tt.ctx, http.MethodGet, "https://example.com", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			resp, err := Transport(next).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			resp.Body.Close()
+			if next.header != tt.want {
+				t.Errorf("header = %q, want %q", next.header, tt.want)
+			}
+			if tt.header == "" && req.Header.Get(Header) != "" {
+				t.Errorf("RoundTrip() modified the original request")
+			}
+		})
+	}
+}
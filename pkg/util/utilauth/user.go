@@ -17,6 +17,7 @@ limitations under the License.
 package auth
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -62,26 +63,37 @@ func GetAuthenticatedUser(c *gin.Context) string {
 
 	// Extract the token
 	tokenString := authHeader[len(prefix):]
-	
+	username, roles := AuthenticateToken(c.Request.Context(), tokenString)
+	if username == "" {
+		return ""
+	}
+	if roles != nil {
+		// Store roles in context for authorization
+		c.Set("user_roles", roles)
+	}
+	return username
+}
+
+// AuthenticateToken returns the username of a bearer token, empty if the token is invalid, and the realm roles
+// of the user when the token is issued by Keycloak
+func AuthenticateToken(ctx context.Context, tokenString string) (string, []string) {
 	// Try Keycloak authentication first if available
 	if kc := keycloak.GetClient(); kc != nil {
-		claims, err := kc.ValidateToken(c.Request.Context(), tokenString)
+		claims, err := kc.ValidateToken(ctx, tokenString)
 		if err == nil {
 			username := claims.GetUsername()
 			client.SetCurrentUser(username)
-			// Store roles in context for authorization
-			c.Set("user_roles", claims.Roles)
-			return username
+			return username, claims.Roles
 		}
 	}
-	
+
 	// Fallback to JWT validation
 	claims, err := auth.ValidateToken(tokenString)
 	if err != nil {
-		return ""
+		return "", nil
 	}
 
 	// Store the username for use in non-context functions
 	client.SetCurrentUser(claims.Username)
-	return claims.Username
+	return claims.Username, nil
 }
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation registers the custom validation rules of the request DTOs and turns validation failures
+// into per-field errors the UI can show next to the form fields.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	"github.com/karmada-io/dashboard/pkg/util/cron"
+)
+
+// The custom validation tags, used in the binding tags of the request DTOs
+const (
+	// TagDNS1123Label is a name that is a lowercase RFC 1123 label, like a namespace
+	TagDNS1123Label = "dns1123label"
+	// TagDNS1123Subdomain is a name that is a lowercase RFC 1123 subdomain, like most resource names
+	TagDNS1123Subdomain = "dns1123subdomain"
+	// TagCron is a five field cron expression or a descriptor like @daily
+	TagCron = "cron"
+	// TagRegistryURL is an image registry, a host with an optional port and path and an optional http(s) scheme
+	TagRegistryURL = "registryurl"
+	// TagCluster is the name of a cluster joined to Karmada
+	TagCluster = "cluster"
+)
+
+// ClusterLister returns the names of the clusters joined to Karmada
+type ClusterLister func() ([]string, error)
+
+// FieldError is a validation failure of one field of a request
+type FieldError struct {
+	// Field is the JSON path of the field, like "schedule.value"
+	Field string `json:"field"`
+	// Rule is the validation tag that failed, like "required" or "dns1123label"
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Register registers the custom validation rules and reports fields by their JSON names. Clusters are
+// checked with listClusters when the request is validated.
+func Register(v *validator.Validate, listClusters ClusterLister) error {
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	validations := map[string]validator.Func{
+		TagDNS1123Label: func(fl validator.FieldLevel) bool {
+			return len(k8svalidation.IsDNS1123Label(fl.Field().String())) == 0
+		},
+		TagDNS1123Subdomain: func(fl validator.FieldLevel) bool {
+			return len(k8svalidation.IsDNS1123Subdomain(fl.Field().String())) == 0
+		},
+		TagCron: func(fl validator.FieldLevel) bool {
+			return IsCron(fl.Field().String())
+		},
+		TagRegistryURL: func(fl validator.FieldLevel) bool {
+			return ValidateRegistryURL(fl.Field().String()) == nil
+		},
+		TagCluster: func(fl validator.FieldLevel) bool {
+			return clusterExists(listClusters, fl.Field().String())
+		},
+	}
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("failed to register validation %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// IsCron reports whether an expression is a valid cron expression
+func IsCron(expr string) bool {
+	_, err := cron.Parse(expr)
+	return err == nil
+}
+
+// ValidateRegistryURL checks an image registry like "harbor.example.com:5000/library" or "https://ghcr.io"
+func ValidateRegistryURL(registry string) error {
+	if registry == "" {
+		return errors.New("registry is empty")
+	}
+	raw := registry
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid registry %q: %w", registry, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid registry %q: scheme must be http or https", registry)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid registry %q: credentials, queries and fragments are not allowed", registry)
+	}
+	host := u.Hostname()
+	if net.ParseIP(host) == nil && len(k8svalidation.IsDNS1123Subdomain(strings.ToLower(host))) > 0 {
+		return fmt.Errorf("invalid registry %q: %q is not a valid host name", registry, host)
+	}
+	return nil
+}
+
+// FieldErrors returns the per-field errors of a failed validation, or nil when err is not a validation failure
+func FieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return fields
+}
+
+// Summary describes a list of field errors in a single message
+func Summary(fields []FieldError) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, fmt.Sprintf("%s %s", f.Field, f.Message))
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+// clusterExists reports whether a cluster is joined to Karmada. A failure to list the clusters lets the
+// request through, the handler fails when it accesses the cluster anyway.
+func clusterExists(listClusters ClusterLister, name string) bool {
+	if listClusters == nil {
+		return true
+	}
+	clusters, err := listClusters()
+	if err != nil {
+		klog.ErrorS(err, "Failed to list clusters, skipping cluster validation", "cluster", name)
+		return true
+	}
+	for _, cluster := range clusters {
+		if cluster == name {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFieldName names fields by their JSON name, fields not serialized keep their Go name
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// fieldPath drops the name of the request struct from the namespace of a field error
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// message describes why a field failed a validation rule
+func message(fe validator.FieldError) string {
+	value := fmt.Sprint(fe.Value())
+	switch fe.Tag() {
+	case "required", "required_without", "required_without_all", "required_if":
+		return "is required"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.Join(strings.Fields(fe.Param()), ", "))
+	case "url":
+		return "must be a valid URL"
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case TagDNS1123Label:
+		return strings.Join(k8svalidation.IsDNS1123Label(value), ", ")
+	case TagDNS1123Subdomain:
+		return strings.Join(k8svalidation.IsDNS1123Subdomain(value), ", ")
+	case TagCron:
+		if _, err := cron.Parse(value); err != nil {
+			return err.Error()
+		}
+		return "must be a valid cron expression"
+	case TagRegistryURL:
+		if err := ValidateRegistryURL(value); err != nil {
+			return err.Error()
+		}
+		return "must be a valid registry"
+	case TagCluster:
+		return fmt.Sprintf("cluster %q is not joined to Karmada", value)
+	}
+	return fmt.Sprintf("failed the %s validation", fe.Tag())
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Karmada Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type scheduleRequest struct {
+	Value string `json:"value" validate:"required,cron"`
+}
+
+type request struct {
+	Name      string          `json:"name" validate:"required,dns1123subdomain"`
+	Namespace string          `json:"namespace" validate:"omitempty,dns1123label"`
+	Cluster   string          `json:"cluster" validate:"required,cluster"`
+	Registry  string          `json:"registry" validate:"omitempty,registryurl"`
+	Schedule  scheduleRequest `json:"schedule"`
+}
+
+func newValidator(t *testing.T, listClusters ClusterLister) *validator.Validate {
+	t.Helper()
+	v := validator.New()
+	if err := Register(v, listClusters); err != nil {
+		t.Fatalf("Register() failed: %v", err)
+	}
+	return v
+}
+
+func TestFieldErrors(t *testing.T) {
+	v := newValidator(t, func() ([]string, error) { return []string{"member1"}, nil })
+
+	valid := request{
+		Name:      "web.backup",
+		Namespace: "default",
+		Cluster:   "member1",
+		Registry:  "harbor.example.com:5000/library",
+		Schedule:  scheduleRequest{Value: "*/15 * * * *"},
+	}
+	if err := v.Struct(valid); err != nil {
+		t.Fatalf("valid request failed validation: %v", err)
+	}
+
+	invalid := request{
+		Name:      "Web_Backup",
+		Namespace: "a.b",
+		Cluster:   "member2",
+		Registry:  "ftp://harbor",
+		Schedule:  scheduleRequest{Value: "61 * * * *"},
+	}
+	fields := FieldErrors(v.Struct(invalid))
+	want := map[string]string{
+		"name":           TagDNS1123Subdomain,
+		"namespace":      TagDNS1123Label,
+		"cluster":        TagCluster,
+		"registry":       TagRegistryURL,
+		"schedule.value": TagCron,
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("FieldErrors() = %+v, expected %d errors", fields, len(want))
+	}
+	for _, f := range fields {
+		if want[f.Field] != f.Rule {
+			t.Errorf("field %s failed %s, expected %s", f.Field, f.Rule, want[f.Field])
+		}
+		if f.Message == "" {
+			t.Errorf("field %s has no message", f.Field)
+		}
+	}
+}
+
+func TestClusterListFailure(t *testing.T) {
+	v := newValidator(t, func() ([]string, error) { return nil, errors.New("unavailable") })
+	if err := v.Var("member1", TagCluster); err != nil {
+		t.Errorf("cluster validation failed when clusters cannot be listed: %v", err)
+	}
+}
+
+func TestFieldErrorsOtherError(t *testing.T) {
+	if fields := FieldErrors(errors.New("unexpected EOF")); fields != nil {
+		t.Errorf("FieldErrors() = %+v, expected nil for errors other than validation failures", fields)
+	}
+}
+
+func TestValidateRegistryURL(t *testing.T) {
+	for _, registry := range []string{"docker.io", "https://ghcr.io", "10.0.0.5:5000", "http://registry.local:5000/team"} {
+		if err := ValidateRegistryURL(registry); err != nil {
+			t.Errorf("ValidateRegistryURL(%q) failed: %v", registry, err)
+		}
+	}
+	for _, registry := range []string{"", "ftp://registry", "user:pass@registry.io", "https://registry.io?x=1", "bad_host"} {
+		if err := ValidateRegistryURL(registry); err == nil {
+			t.Errorf("ValidateRegistryURL(%q) succeeded, expected an error", registry)
+		}
+	}
+}
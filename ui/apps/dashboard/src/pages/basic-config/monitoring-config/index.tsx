@@ -75,7 +75,8 @@ const MonitoringConfig: FC = () => {
                                         open: true,
                                         name: item.name,
                                         endpoint: item.endpoint,
-                                        token: item.token,
+                                        // The stored token is not returned, it is kept unless a new one is entered
+                                        token: '',
                                         type: item.type,
                                     })} />,
                                     <ExportOutlined key="new-tab" onClick={() => window.open(item.endpoint, '_blank')} />,
@@ -8,7 +8,7 @@ interface MonitoringConfig {
   name: string;
   type: MonitoringType;
   endpoint: string;
-  token: string;
+  hasToken: boolean;
 }
 
 interface NewDashboardModalProps {
@@ -4,7 +4,7 @@ export interface MonitoringConfig {
     name: string;
     type: MonitoringType;
     endpoint: string;
-    token: string;
+    hasToken: boolean;
 }
 
 export enum MonitoringType {